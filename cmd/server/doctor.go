@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func parseDoctorFlags(args []string, output io.Writer) (bool, error) {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fix := fs.Bool("fix", false, "apply the safe repairs: disable reminders on unusable cards and delete orphaned reactions")
+	if err := fs.Parse(args); err != nil {
+		return false, err
+	}
+	if fs.NArg() > 0 {
+		return false, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	return *fix, nil
+}
+
+// runDoctor checks the data invariants the code relies on, prints a JSON
+// report to stdout and returns an error if any check failed. Like selftest it
+// doesn't run migrations, so run it against a database the server has
+// already migrated.
+func runDoctor(args []string) error {
+	// Keep stdout for the report.
+	logging.Default.SetOutput(os.Stderr)
+
+	fix, err := parseDoctorFlags(args, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	db, _, closeDB, err := openDatabase(cfg, logging.Default, false)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	report := services.NewDoctorService(db).Run(context.Background(), fix)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	if !report.OK {
+		return fmt.Errorf("doctor found problems")
+	}
+	return nil
+}
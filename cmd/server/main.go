@@ -7,20 +7,63 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/activitypub"
 	"github.com/HammerMeetNail/yearofbingo/internal/config"
-	"github.com/HammerMeetNail/yearofbingo/internal/database"
 	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/httpserver"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 	"github.com/HammerMeetNail/yearofbingo/internal/middleware"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 	"github.com/HammerMeetNail/yearofbingo/internal/services/ai"
 )
 
+// Background job names reported by /api/admin/jobs.
+const (
+	jobNotificationCleanup   = "notification_cleanup"
+	jobNotificationReconcile = "notification_reconcile"
+	jobNotificationOutbox    = "notification_email_outbox"
+	jobReminderCleanup       = "reminder_cleanup"
+	jobReminderRunner        = "reminder_runner"
+	jobFriendsDigest         = "friends_digest"
+	jobDraftNudges           = "draft_nudges"
+	jobShareUpdates          = "share_updates"
+	jobReactionCleanup       = "reaction_cleanup"
+	jobWebhookRunner         = "webhook_runner"
+	jobWebhookCleanup        = "webhook_cleanup"
+	jobShareAccessLog        = "share_access_log"
+	jobAIQuotaReset          = "ai_quota_reset"
+)
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "selftest" {
+		if err := runSelfTest(); err != nil {
+			logging.Error("Self-test failed", map[string]interface{}{"error": err.Error()})
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		if err := runDoctor(os.Args[2:]); err != nil {
+			logging.Error("Doctor failed", map[string]interface{}{"error": err.Error()})
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := runSeed(os.Args[2:]); err != nil {
+			logging.Error("Seeding failed", map[string]interface{}{"error": err.Error()})
+			os.Exit(1)
+		}
+		return
+	}
 	if err := run(); err != nil {
 		logging.Error("Application error", map[string]interface{}{"error": err.Error()})
 		os.Exit(1)
@@ -48,61 +91,80 @@ func run() error {
 
 	logger.Info("Starting Year of Bingo server...")
 
-	// Connect to PostgreSQL
-	logger.Info("Connecting to PostgreSQL", map[string]interface{}{
-		"host": cfg.Database.Host,
-		"port": cfg.Database.Port,
-	})
-	db, err := database.NewPostgresDB(cfg.Database.DSN())
+	dbAdapter, dbHealth, closeDB, err := openDatabase(cfg, logger, true)
 	if err != nil {
-		return fmt.Errorf("connecting to postgres: %w", err)
+		return err
 	}
-	defer db.Close()
-	logger.Info("Connected to PostgreSQL")
+	defer closeDB()
 
-	// Run migrations
-	logger.Info("Running database migrations...")
-	migrator, err := database.NewMigrator(cfg.Database.DSN(), "migrations")
+	redisDB, err := openRedis(cfg, logger)
 	if err != nil {
-		return fmt.Errorf("creating migrator: %w", err)
-	}
-	if err := migrator.Up(); err != nil {
-		_ = migrator.Close()
-		return fmt.Errorf("running migrations: %w", err)
+		return err
 	}
-	_ = migrator.Close()
-	logger.Info("Migrations completed")
+	defer func() { _ = redisDB.Close() }()
 
-	// Connect to Redis
-	logger.Info("Connecting to Redis", map[string]interface{}{
-		"addr": cfg.Redis.Addr(),
-	})
-	redisDB, err := database.NewRedisDB(cfg.Redis.Addr(), cfg.Redis.Password, cfg.Redis.DB)
-	if err != nil {
-		return fmt.Errorf("connecting to redis: %w", err)
+	if cfg.Render.FontDir != "" {
+		loaded, err := services.LoadFallbackFonts(cfg.Render.FontDir)
+		if err != nil {
+			logger.Warn("Some fallback fonts failed to load", map[string]interface{}{
+				"dir":   cfg.Render.FontDir,
+				"error": err.Error(),
+			})
+		}
+		logger.Info("Loaded fallback fonts for card images", map[string]interface{}{
+			"dir":   cfg.Render.FontDir,
+			"count": loaded,
+		})
 	}
-	defer func() { _ = redisDB.Close() }()
-	logger.Info("Connected to Redis")
 
 	// Initialize services
-	dbAdapter := services.NewPoolAdapter(db.Pool)
 	redisAdapter := services.NewRedisAdapter(redisDB.Client)
 
 	userService := services.NewUserService(dbAdapter)
 	authService := services.NewAuthService(dbAdapter, redisAdapter)
 	providerAuthService := services.NewProviderAuthService(dbAdapter)
 	emailService := services.NewEmailService(&cfg.Email, dbAdapter)
+	emailService.SetBranding(cfg.Branding)
 	cardService := services.NewCardService(dbAdapter)
+	shareCache := services.NewSharedCardCache(redisAdapter, services.DefaultSharedCardCacheTTL)
+	cardService.SetShareCache(shareCache)
+	shareAccessLog := services.NewShareAccessLog(dbAdapter)
+	cardService.SetShareAccessLog(shareAccessLog)
+	proofStorage := services.NewDiskStorage(cfg.Cards.ProofStorageDir)
+	cardService.SetProofStorage(proofStorage)
+	cardService.SetDifficultyWeights(models.DifficultyWeights{
+		Easy:   cfg.Cards.DifficultyWeightEasy,
+		Medium: cfg.Cards.DifficultyWeightMedium,
+		Hard:   cfg.Cards.DifficultyWeightHard,
+	})
 	suggestionService := services.NewSuggestionService(dbAdapter)
+	suggestionCache := services.NewSuggestionCache(redisAdapter)
+	suggestionService.SetCache(suggestionCache)
+	// Migrations are the only writer of the suggestion pool, so entries cached
+	// by the previous deploy are dropped at startup.
+	suggestionService.InvalidateCache(context.Background())
 	friendService := services.NewFriendService(dbAdapter)
 	reactionService := services.NewReactionService(dbAdapter, friendService)
+	commentService := services.NewCommentService(dbAdapter, friendService)
 	apiTokenService := services.NewApiTokenService(dbAdapter)
+	webhookService := services.NewWebhookService(dbAdapter)
 	blockService := services.NewBlockService(dbAdapter)
+	profileService := services.NewProfileService(dbAdapter, cfg.Email.BaseURL)
 	inviteService := services.NewFriendInviteService(dbAdapter)
 	notificationService := services.NewNotificationService(dbAdapter, emailService, cfg.Email.BaseURL)
+	notificationService.SetBranding(cfg.Branding)
 	reminderService := services.NewReminderService(dbAdapter, emailService, cfg.Email.BaseURL)
+	reminderService.SetImageTokenPolicy(cfg.Reminder.ImageTokenTTL, cfg.Reminder.ImageTokenMaxAccess)
+	reminderService.SetMemoryFinder(cardService)
+	reminderService.SetDifficultyPacing(cfg.Reminder.DifficultyPacing)
+	reminderService.SetBranding(cfg.Branding)
 	accountService := services.NewAccountService(dbAdapter)
+	accountService.SetBranding(cfg.Branding)
+	accountService.SetProofStorage(proofStorage)
+	adminAuditService := services.NewAdminAuditService(dbAdapter)
 	aiService := ai.NewService(cfg, dbAdapter)
+	usageService := services.NewUsageService(redisAdapter)
+	usageService.SetAIFreeGenerations(ai.FreeGenerationsBeforeVerification)
 
 	oauthProviders := map[services.Provider]services.OAuthProvider{}
 	if cfg.OAuth.Google.Enabled {
@@ -121,73 +183,223 @@ func run() error {
 	}
 
 	cardService.SetNotificationService(notificationService)
+	cardService.SetWebhookEnqueuer(webhookService)
 	friendService.SetNotificationService(notificationService)
 	inviteService.SetNotificationService(notificationService)
+	commentService.SetNotificationService(notificationService)
+	reminderService.SetNotificationService(notificationService)
 
 	// Initialize handlers
-	healthHandler := handlers.NewHealthHandler(db, redisDB)
+	if cfg.Server.CursorSecret == "" && cfg.Server.Environment == "production" {
+		logger.Warn("PAGINATION_CURSOR_SECRET is not set; list cursors will not survive a restart or work across replicas")
+	}
+	cursors := pagination.NewCodec(cfg.Server.CursorSecret)
+	healthHandler := handlers.NewHealthHandler(dbHealth, redisDB)
+	if cfg.Database.IsSQLite() {
+		healthHandler.SetDatabaseName(config.DatabaseDriverSQLite)
+	}
+	healthHandler.SetCacheStats(shareCache, suggestionCache)
+	healthHandler.SetEmailLimiter(emailService)
+	versionHandler := handlers.NewVersionHandler(cfg.Server.Version, cfg.Server.MinClientVersion)
 	authHandler := handlers.NewAuthHandler(userService, authService, emailService, cfg.Server.Secure)
 	providerAuthHandler := handlers.NewProviderAuthHandler(providerAuthService, authService, redisAdapter, oauthProviders, cfg.Server.Secure)
+	providerAuthHandler.SetUserService(userService)
+	shareSubscriptionService := services.NewShareSubscriptionService(dbAdapter, emailService, cfg.Email.BaseURL)
+	shareSubscriptionService.SetBranding(cfg.Branding)
 	cardHandler := handlers.NewCardHandler(cardService)
+	cardHandler.SetCursorCodec(cursors)
+	cardHandler.SetShareSubscriptionService(shareSubscriptionService)
+	cardHandler.SetDraftStateService(services.NewCardDraftStateService(redisAdapter))
+	shareSubscriptionHandler := handlers.NewShareSubscriptionHandler(shareSubscriptionService)
+	shareSubscriptionHandler.SetBranding(cfg.Branding)
 	suggestionHandler := handlers.NewSuggestionHandler(suggestionService)
 	friendHandler := handlers.NewFriendHandler(friendService, cardService)
 	reactionHandler := handlers.NewReactionHandler(reactionService)
+	commentHandler := handlers.NewCommentHandler(commentService)
 	supportHandler := handlers.NewSupportHandler(emailService, redisDB.Client)
 	apiTokenHandler := handlers.NewApiTokenHandler(apiTokenService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
 	blockHandler := handlers.NewBlockHandler(blockService)
+	profileHandler := handlers.NewProfileHandler(profileService)
 	inviteHandler := handlers.NewFriendInviteHandler(inviteService)
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	notificationHandler.SetCursorCodec(cursors)
 	reminderHandler := handlers.NewReminderHandler(reminderService)
+	reminderHandler.SetCursorCodec(cursors)
 	reminderPublicHandler := handlers.NewReminderPublicHandler(reminderService)
+	reminderPublicHandler.SetBranding(cfg.Branding)
 	aiHandler := handlers.NewAIHandler(aiService)
+	proofHandler := handlers.NewProofHandler(proofStorage)
 	accountHandler := handlers.NewAccountHandler(accountService, authService, cfg.Server.Secure)
+	accountHandler.SetExportLimiter(redisDB.Client)
+	accountHandler.SetEmailService(emailService)
+	usageHandler := handlers.NewUsageHandler(usageService)
+	adminHandler := handlers.NewAdminHandler(reminderService, adminAuditService)
+	adminHandler.SetCursorCodec(cursors)
+	adminHandler.SetAIQuotaService(aiService)
+	adminHandler.SetReminderRunEnabled(cfg.Server.Environment != "production" || cfg.Admin.ToolsEnabled)
+	if cfg.Admin.SearchEnabled {
+		adminHandler.SetSearchService(services.NewAdminSearchService(dbAdapter))
+	}
+	// Suggestion analytics match goals by a Postgres-only content hash.
+	if cfg.Admin.SuggestionAnalyticsEnabled && cfg.Database.IsSQLite() {
+		logger.Warn("Suggestion analytics are not available with SQLite; leaving them off")
+	} else if cfg.Admin.SuggestionAnalyticsEnabled {
+		cardService.SetSuggestionUsageRecorder(suggestionService)
+		adminHandler.SetSuggestionAnalytics(suggestionService)
+	}
 	pageHandler, err := handlers.NewPageHandler("web/templates", handlers.PageOAuthConfig{
 		GoogleEnabled: cfg.OAuth.Google.Enabled,
 	})
 	if err != nil {
 		return fmt.Errorf("loading templates: %w", err)
 	}
+	pageHandler.SetBranding(cfg.Branding)
 	sharePublicHandler, err := handlers.NewSharePublicHandler("web/templates", cardService)
 	if err != nil {
 		return fmt.Errorf("loading share templates: %w", err)
 	}
+	sharePublicHandler.SetBranding(cfg.Branding)
+	profilePublicHandler, err := handlers.NewProfilePublicHandler("web/templates", profileService)
+	if err != nil {
+		return fmt.Errorf("loading profile templates: %w", err)
+	}
+	profilePublicHandler.SetBranding(cfg.Branding)
+	var activityPubHandler *activitypub.Handler
+	if cfg.ActivityPub.Enabled {
+		if !strings.HasPrefix(cfg.Email.BaseURL, "https://") {
+			logger.Warn("ACTIVITYPUB_ENABLED is set but APP_BASE_URL is not https; other servers will not federate with it")
+		}
+		activityPubService := activitypub.NewService(dbAdapter, profileService, cfg.Email.BaseURL)
+		cardService.SetActivityRecorder(activityPubService)
+		activityPubHandler = activitypub.NewHandler(activityPubService)
+		activityPubHandler.SetCursorCodec(cursors)
+	}
 	shareOGImageHandler := handlers.NewShareOGImageHandler(cardService)
+	shareOGImageHandler.SetImageCache(shareCache)
 	ogImageHandler := handlers.NewOGImageHandler()
-
-	if err := notificationService.CleanupOld(context.Background()); err != nil {
-		logger.Warn("Notification cleanup failed", map[string]interface{}{"error": err.Error()})
+	ogImageHandler.SetBranding(cfg.Branding)
+
+	// Background jobs record their runs in jobRegistry for /api/admin/jobs and /ready?verbose=1.
+	jobRegistry := services.NewJobRegistry()
+	healthHandler.SetJobRegistry(jobRegistry)
+	jobsHandler := handlers.NewJobsHandler(jobRegistry)
+	cleanupNotifications := notificationService.CleanupOld
+	reconcileNotifications := func(ctx context.Context) (int, error) {
+		return notificationService.ReconcilePending(ctx)
+	}
+	sendDeferredNotificationEmails := notificationService.SendDeferredEmails
+	cleanupReactions := func(ctx context.Context) (int, error) {
+		return reactionService.CleanupOrphaned(ctx)
+	}
+	cleanupReminders := reminderService.CleanupOld
+	runReminders := func(ctx context.Context) (int, error) {
+		return reminderService.RunDue(ctx, time.Now(), 50)
+	}
+	friendDigestService := services.NewFriendDigestService(dbAdapter, emailService, cfg.Email.BaseURL)
+	friendDigestService.SetBranding(cfg.Branding)
+	runFriendsDigest := func(ctx context.Context) (int, error) {
+		return friendDigestService.RunDue(ctx, time.Now(), 50)
 	}
+	runDraftNudges := func(ctx context.Context) (int, error) {
+		return notificationService.NudgeForgottenDrafts(ctx, time.Now(), 50)
+	}
+	runShareUpdates := func(ctx context.Context) (int, error) {
+		return shareSubscriptionService.RunDue(ctx, time.Now(), 50)
+	}
+	runWebhooks := func(ctx context.Context) (int, error) {
+		return webhookService.DeliverDue(ctx, 50)
+	}
+	cleanupWebhooks := webhookService.CleanupOld
+
+	// Retention cleanups start in the background, so a large backlog of old
+	// rows delays neither startup nor readiness; shutdown cancels them.
+	jobRegistry.Register(jobNotificationCleanup, 24*time.Hour)
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
 	notificationService.SetAsyncContext(cleanupCtx)
 	go func() {
 		ticker := time.NewTicker(24 * time.Hour)
 		defer ticker.Stop()
 		for {
+			if err := jobRegistry.Run(cleanupCtx, jobNotificationCleanup, cleanupNotifications); err != nil {
+				logger.Warn("Notification cleanup failed", map[string]interface{}{"error": err.Error()})
+			}
 			select {
 			case <-cleanupCtx.Done():
 				return
 			case <-ticker.C:
-				if err := notificationService.CleanupOld(context.Background()); err != nil {
-					logger.Warn("Notification cleanup failed", map[string]interface{}{"error": err.Error()})
+			}
+		}
+	}()
+
+	// Friend notifications that failed inside their card write are queued and
+	// retried here.
+	jobRegistry.Register(jobNotificationReconcile, 5*time.Minute)
+	go func() {
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-cleanupCtx.Done():
+				return
+			case <-ticker.C:
+				if err := jobRegistry.Run(context.Background(), jobNotificationReconcile, reconcileNotifications); err != nil {
+					logger.Warn("Notification reconcile failed", map[string]interface{}{"error": err.Error()})
 				}
 			}
 		}
 	}()
 
-	if err := reminderService.CleanupOld(context.Background()); err != nil {
-		logger.Warn("Reminder cleanup failed", map[string]interface{}{"error": err.Error()})
+	// Notification emails the email send limiter refused are sent from the
+	// outbox once it has room.
+	jobRegistry.Register(jobNotificationOutbox, time.Minute)
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-cleanupCtx.Done():
+				return
+			case <-ticker.C:
+				if err := jobRegistry.Run(cleanupCtx, jobNotificationOutbox, sendDeferredNotificationEmails); err != nil {
+					logger.Warn("Notification email outbox failed", map[string]interface{}{"error": err.Error()})
+				}
+			}
+		}
+	}()
+
+	jobRegistry.Register(jobReactionCleanup, 24*time.Hour)
+	if err := jobRegistry.Run(context.Background(), jobReactionCleanup, cleanupReactions); err != nil {
+		logger.Warn("Reaction cleanup failed", map[string]interface{}{"error": err.Error()})
 	}
+	go func() {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-cleanupCtx.Done():
+				return
+			case <-ticker.C:
+				if err := jobRegistry.Run(context.Background(), jobReactionCleanup, cleanupReactions); err != nil {
+					logger.Warn("Reaction cleanup failed", map[string]interface{}{"error": err.Error()})
+				}
+			}
+		}
+	}()
+
+	jobRegistry.Register(jobReminderCleanup, 24*time.Hour)
 	reminderCtx, reminderCancel := context.WithCancel(context.Background())
+	reminderInterval := resolveRemindersPollInterval(logger, os.LookupEnv)
+	jobRegistry.Register(jobReminderRunner, reminderInterval)
 	go func() {
-		interval := resolveRemindersPollInterval(logger, os.LookupEnv)
-		ticker := time.NewTicker(interval)
+		ticker := time.NewTicker(reminderInterval)
 		defer ticker.Stop()
 		for {
 			select {
 			case <-reminderCtx.Done():
 				return
 			case <-ticker.C:
-				if _, err := reminderService.RunDue(context.Background(), time.Now(), 50); err != nil {
+				if err := jobRegistry.Run(context.Background(), jobReminderRunner, runReminders); err != nil {
 					logger.Warn("Reminder runner failed", map[string]interface{}{"error": err.Error()})
 				}
 			}
@@ -197,12 +409,134 @@ func run() error {
 		ticker := time.NewTicker(24 * time.Hour)
 		defer ticker.Stop()
 		for {
+			if err := jobRegistry.Run(reminderCtx, jobReminderCleanup, cleanupReminders); err != nil {
+				logger.Warn("Reminder cleanup failed", map[string]interface{}{"error": err.Error()})
+			}
 			select {
 			case <-reminderCtx.Done():
 				return
 			case <-ticker.C:
-				if err := reminderService.CleanupOld(context.Background()); err != nil {
-					logger.Warn("Reminder cleanup failed", map[string]interface{}{"error": err.Error()})
+			}
+		}
+	}()
+
+	// Digests go out at most weekly per user; an hourly pass spreads them out
+	// and retries users who were at their daily email cap.
+	jobRegistry.Register(jobFriendsDigest, time.Hour)
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-reminderCtx.Done():
+				return
+			case <-ticker.C:
+				if err := jobRegistry.Run(context.Background(), jobFriendsDigest, runFriendsDigest); err != nil {
+					logger.Warn("Friends digest failed", map[string]interface{}{"error": err.Error()})
+				}
+			}
+		}
+	}()
+
+	// Each forgotten draft is nudged once; an hourly pass is plenty.
+	jobRegistry.Register(jobDraftNudges, time.Hour)
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-reminderCtx.Done():
+				return
+			case <-ticker.C:
+				if err := jobRegistry.Run(context.Background(), jobDraftNudges, runDraftNudges); err != nil {
+					logger.Warn("Draft nudges failed", map[string]interface{}{"error": err.Error()})
+				}
+			}
+		}
+	}()
+
+	// Monthly AI allowances roll over at the start of each UTC month. A
+	// generation starts the new month itself, so the hourly pass only keeps
+	// idle users' counters honest.
+	jobRegistry.Register(jobAIQuotaReset, time.Hour)
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if err := jobRegistry.Run(reminderCtx, jobAIQuotaReset, aiService.ResetMonthlyQuotas); err != nil {
+				logger.Warn("AI quota reset failed", map[string]interface{}{"error": err.Error()})
+			}
+			select {
+			case <-reminderCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	// Share watchers get at most one email a week, on the same hourly pass.
+	jobRegistry.Register(jobShareUpdates, time.Hour)
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-reminderCtx.Done():
+				return
+			case <-ticker.C:
+				if err := jobRegistry.Run(context.Background(), jobShareUpdates, runShareUpdates); err != nil {
+					logger.Warn("Share updates failed", map[string]interface{}{"error": err.Error()})
+				}
+			}
+		}
+	}()
+
+	// Webhook deliveries are queued by card writes; the runner sends them and
+	// retries failures with backoff.
+	jobRegistry.Register(jobWebhookRunner, time.Minute)
+	jobRegistry.Register(jobWebhookCleanup, 24*time.Hour)
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-reminderCtx.Done():
+				return
+			case <-ticker.C:
+				if err := jobRegistry.Run(reminderCtx, jobWebhookRunner, runWebhooks); err != nil {
+					logger.Warn("Webhook runner failed", map[string]interface{}{"error": err.Error()})
+				}
+			}
+		}
+	}()
+	go func() {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for {
+			if err := jobRegistry.Run(reminderCtx, jobWebhookCleanup, cleanupWebhooks); err != nil {
+				logger.Warn("Webhook cleanup failed", map[string]interface{}{"error": err.Error()})
+			}
+			select {
+			case <-reminderCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	// Share link visits are buffered in memory and written in batches, so
+	// the public page never waits on the insert. Shutdown flushes the rest.
+	jobRegistry.Register(jobShareAccessLog, services.ShareAccessFlushInterval)
+	go func() {
+		ticker := time.NewTicker(services.ShareAccessFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-reminderCtx.Done():
+				return
+			case <-ticker.C:
+				if err := jobRegistry.Run(context.Background(), jobShareAccessLog, shareAccessLog.Flush); err != nil {
+					logger.Warn("Share access log flush failed", map[string]interface{}{"error": err.Error()})
 				}
 			}
 		}
@@ -212,190 +546,100 @@ func run() error {
 	authMiddleware := middleware.NewAuthMiddleware(authService, userService, apiTokenService)
 	csrfMiddleware := middleware.NewCSRFMiddleware(cfg.Server.Secure)
 	securityHeaders := middleware.NewSecurityHeaders(cfg.Server.Secure)
+	securityHeaders.AllowImageSource(cfg.Branding.LogoOrigin())
 	cacheControl := middleware.NewCacheControl()
 	compress := middleware.NewCompress()
+	compress.SetRequestDecodingLimit(cfg.Server.MaxDecodedRequestBytes)
 	requestLogger := middleware.NewRequestLogger(logger)
+	disconnectWatchdog := middleware.NewDisconnectWatchdog(logger, cfg.Server.DisconnectWatchdog)
 
 	// AI Rate Limit configuration
 	aiRateLimit := resolveAIRateLimit(cfg, logger, os.LookupEnv)
 
-	aiRateLimiter := middleware.NewRateLimiter(redisDB.Client, aiRateLimit, 1*time.Hour, "ratelimit:ai:", func(r *http.Request) string {
-		user := handlers.GetUserFromContext(r.Context())
-		if user != nil {
-			return user.ID.String()
-		}
-		return ""
-	}, false)
-
-	// Helper middlewares for API token scope enforcement
-	requireRead := authMiddleware.RequireScope(models.ScopeRead)
-	requireWrite := authMiddleware.RequireScope(models.ScopeWrite)
-	requireSession := authMiddleware.RequireSession
-
-	// Set up router
-	mux := http.NewServeMux()
-
-	// Health endpoints (no auth, no rate limit)
-	mux.HandleFunc("GET /health", healthHandler.Health)
-	mux.HandleFunc("GET /ready", healthHandler.Ready)
-	mux.HandleFunc("GET /live", healthHandler.Live)
-
-	// CSRF token endpoint
-	mux.Handle("GET /api/csrf", requireSession(http.HandlerFunc(csrfMiddleware.GetToken)))
-
-	// Auth endpoints
-	mux.Handle("POST /api/auth/register", requireSession(http.HandlerFunc(authHandler.Register)))
-	mux.Handle("POST /api/auth/login", requireSession(http.HandlerFunc(authHandler.Login)))
-	mux.Handle("POST /api/auth/logout", requireSession(http.HandlerFunc(authHandler.Logout)))
-	mux.Handle("GET /api/auth/me", requireRead(http.HandlerFunc(authHandler.Me)))
-	mux.Handle("POST /api/auth/password", requireSession(http.HandlerFunc(authHandler.ChangePassword)))
-	mux.Handle("POST /api/auth/verify-email", requireSession(http.HandlerFunc(authHandler.VerifyEmail)))
-	mux.Handle("POST /api/auth/resend-verification", requireSession(http.HandlerFunc(authHandler.ResendVerification)))
-	mux.Handle("POST /api/auth/magic-link", requireSession(http.HandlerFunc(authHandler.MagicLink)))
-	mux.Handle("GET /api/auth/magic-link/verify", requireSession(http.HandlerFunc(authHandler.MagicLinkVerify)))
-	mux.Handle("POST /api/auth/forgot-password", requireSession(http.HandlerFunc(authHandler.ForgotPassword)))
-	mux.Handle("POST /api/auth/reset-password", requireSession(http.HandlerFunc(authHandler.ResetPassword)))
-	mux.Handle("PUT /api/auth/searchable", requireSession(http.HandlerFunc(authHandler.UpdateSearchable)))
-	mux.Handle("GET /api/auth/{provider}/start", requireSession(http.HandlerFunc(providerAuthHandler.ProviderStart)))
-	mux.Handle("GET /api/auth/{provider}/callback", requireSession(http.HandlerFunc(providerAuthHandler.ProviderCallback)))
-	mux.Handle("POST /api/auth/{provider}/complete", requireSession(http.HandlerFunc(providerAuthHandler.ProviderComplete)))
-
-	// Account endpoints
-	mux.Handle("GET /api/account/export", requireSession(http.HandlerFunc(accountHandler.Export)))
-	mux.Handle("DELETE /api/account", requireSession(http.HandlerFunc(accountHandler.Delete)))
-
-	// API Token endpoints
-	mux.Handle("GET /api/tokens", requireSession(http.HandlerFunc(apiTokenHandler.List)))
-	mux.Handle("POST /api/tokens", requireSession(http.HandlerFunc(apiTokenHandler.Create)))
-	mux.Handle("DELETE /api/tokens/{id}", requireSession(http.HandlerFunc(apiTokenHandler.Delete)))
-	mux.Handle("DELETE /api/tokens", requireSession(http.HandlerFunc(apiTokenHandler.DeleteAll)))
-
-	// Card endpoints
-	mux.Handle("POST /api/cards", requireWrite(http.HandlerFunc(cardHandler.Create)))
-	mux.Handle("GET /api/cards", requireRead(http.HandlerFunc(cardHandler.List)))
-	mux.Handle("GET /api/cards/archive", requireSession(http.HandlerFunc(cardHandler.Archive)))
-	mux.Handle("GET /api/cards/categories", requireRead(http.HandlerFunc(cardHandler.GetCategories)))
-	mux.Handle("GET /api/cards/export", requireSession(http.HandlerFunc(cardHandler.ListExportable)))
-	mux.Handle("POST /api/cards/import", requireSession(http.HandlerFunc(cardHandler.Import)))
-	mux.Handle("PUT /api/cards/visibility/bulk", requireSession(http.HandlerFunc(cardHandler.BulkUpdateVisibility)))
-	mux.Handle("DELETE /api/cards/bulk", requireSession(http.HandlerFunc(cardHandler.BulkDelete)))
-	mux.Handle("PUT /api/cards/archive/bulk", requireSession(http.HandlerFunc(cardHandler.BulkUpdateArchive)))
-	mux.Handle("GET /api/cards/{id}", requireRead(http.HandlerFunc(cardHandler.Get)))
-	mux.Handle("DELETE /api/cards/{id}", requireSession(http.HandlerFunc(cardHandler.Delete)))
-	mux.Handle("GET /api/cards/{id}/stats", requireRead(http.HandlerFunc(cardHandler.Stats)))
-	mux.Handle("PUT /api/cards/{id}/meta", requireSession(http.HandlerFunc(cardHandler.UpdateMeta)))
-	mux.Handle("PUT /api/cards/{id}/visibility", requireSession(http.HandlerFunc(cardHandler.UpdateVisibility)))
-	mux.Handle("PUT /api/cards/{id}/config", requireWrite(http.HandlerFunc(cardHandler.UpdateConfig)))
-	mux.Handle("POST /api/cards/{id}/clone", requireWrite(http.HandlerFunc(cardHandler.Clone)))
-	mux.Handle("POST /api/cards/{id}/items", requireWrite(http.HandlerFunc(cardHandler.AddItem)))
-	mux.Handle("PUT /api/cards/{id}/items/{pos}", requireWrite(http.HandlerFunc(cardHandler.UpdateItem)))
-	mux.Handle("DELETE /api/cards/{id}/items/{pos}", requireWrite(http.HandlerFunc(cardHandler.RemoveItem)))
-	mux.Handle("POST /api/cards/{id}/shuffle", requireWrite(http.HandlerFunc(cardHandler.Shuffle)))
-	mux.Handle("POST /api/cards/{id}/swap", requireWrite(http.HandlerFunc(cardHandler.SwapItems)))
-	mux.Handle("POST /api/cards/{id}/finalize", requireWrite(http.HandlerFunc(cardHandler.Finalize)))
-	mux.Handle("POST /api/cards/{id}/share", requireSession(http.HandlerFunc(cardHandler.CreateShare)))
-	mux.Handle("GET /api/cards/{id}/share", requireSession(http.HandlerFunc(cardHandler.GetShareStatus)))
-	mux.Handle("DELETE /api/cards/{id}/share", requireSession(http.HandlerFunc(cardHandler.RevokeShare)))
-	mux.Handle("PUT /api/cards/{id}/items/{pos}/complete", requireWrite(http.HandlerFunc(cardHandler.CompleteItem)))
-	mux.Handle("PUT /api/cards/{id}/items/{pos}/uncomplete", requireWrite(http.HandlerFunc(cardHandler.UncompleteItem)))
-	mux.Handle("PUT /api/cards/{id}/items/{pos}/notes", requireWrite(http.HandlerFunc(cardHandler.UpdateNotes)))
-	mux.Handle("GET /api/share/{token}", http.HandlerFunc(cardHandler.GetSharedCard))
-
-	// Suggestion endpoints
-	mux.Handle("GET /api/suggestions", http.HandlerFunc(suggestionHandler.GetAll))
-	mux.Handle("GET /api/suggestions/categories", http.HandlerFunc(suggestionHandler.GetCategories))
-
-	// Friend endpoints
-	mux.Handle("GET /api/friends", requireSession(http.HandlerFunc(friendHandler.List)))
-	mux.Handle("GET /api/friends/search", requireSession(http.HandlerFunc(friendHandler.Search)))
-	mux.Handle("POST /api/friends/requests", requireSession(http.HandlerFunc(friendHandler.SendRequest)))
-	mux.Handle("PUT /api/friends/requests/{id}/accept", requireSession(http.HandlerFunc(friendHandler.AcceptRequest)))
-	mux.Handle("PUT /api/friends/requests/{id}/reject", requireSession(http.HandlerFunc(friendHandler.RejectRequest)))
-	mux.Handle("DELETE /api/friends/{id}", requireSession(http.HandlerFunc(friendHandler.Remove)))
-	mux.Handle("DELETE /api/friends/requests/{id}/cancel", requireSession(http.HandlerFunc(friendHandler.CancelRequest)))
-	mux.Handle("GET /api/friends/{id}/card", requireSession(http.HandlerFunc(friendHandler.GetFriendCard)))
-	mux.Handle("GET /api/friends/{id}/cards", requireSession(http.HandlerFunc(friendHandler.GetFriendCards)))
-	mux.Handle("POST /api/blocks", requireSession(http.HandlerFunc(blockHandler.Block)))
-	mux.Handle("DELETE /api/blocks/{id}", requireSession(http.HandlerFunc(blockHandler.Unblock)))
-	mux.Handle("GET /api/blocks", requireSession(http.HandlerFunc(blockHandler.List)))
-	mux.Handle("POST /api/friends/invites", requireSession(http.HandlerFunc(inviteHandler.Create)))
-	mux.Handle("GET /api/friends/invites", requireSession(http.HandlerFunc(inviteHandler.List)))
-	mux.Handle("DELETE /api/friends/invites/{id}/revoke", requireSession(http.HandlerFunc(inviteHandler.Revoke)))
-	mux.Handle("POST /api/friends/invites/accept", requireSession(http.HandlerFunc(inviteHandler.Accept)))
-	mux.Handle("GET /api/notifications", requireSession(http.HandlerFunc(notificationHandler.List)))
-	mux.Handle("POST /api/notifications/{id}/read", requireSession(http.HandlerFunc(notificationHandler.MarkRead)))
-	mux.Handle("POST /api/notifications/read-all", requireSession(http.HandlerFunc(notificationHandler.MarkAllRead)))
-	mux.Handle("DELETE /api/notifications/{id}", requireSession(http.HandlerFunc(notificationHandler.Delete)))
-	mux.Handle("DELETE /api/notifications", requireSession(http.HandlerFunc(notificationHandler.DeleteAll)))
-	mux.Handle("GET /api/notifications/unread-count", requireSession(http.HandlerFunc(notificationHandler.UnreadCount)))
-	mux.Handle("GET /api/notifications/settings", requireSession(http.HandlerFunc(notificationHandler.GetSettings)))
-	mux.Handle("PUT /api/notifications/settings", requireSession(http.HandlerFunc(notificationHandler.UpdateSettings)))
-
-	// Reminder endpoints
-	mux.Handle("GET /api/reminders/settings", requireSession(http.HandlerFunc(reminderHandler.GetSettings)))
-	mux.Handle("PUT /api/reminders/settings", requireSession(http.HandlerFunc(reminderHandler.UpdateSettings)))
-	mux.Handle("GET /api/reminders/cards", requireSession(http.HandlerFunc(reminderHandler.ListCards)))
-	mux.Handle("PUT /api/reminders/cards/{cardId}", requireSession(http.HandlerFunc(reminderHandler.UpsertCardCheckin)))
-	mux.Handle("DELETE /api/reminders/cards/{cardId}", requireSession(http.HandlerFunc(reminderHandler.DeleteCardCheckin)))
-	mux.Handle("GET /api/reminders/goals", requireSession(http.HandlerFunc(reminderHandler.ListGoals)))
-	mux.Handle("POST /api/reminders/goals", requireSession(http.HandlerFunc(reminderHandler.UpsertGoalReminder)))
-	mux.Handle("DELETE /api/reminders/goals/{id}", requireSession(http.HandlerFunc(reminderHandler.DeleteGoalReminder)))
-	mux.Handle("POST /api/reminders/test", requireSession(http.HandlerFunc(reminderHandler.SendTest)))
-
-	// Reaction endpoints
-	mux.Handle("POST /api/items/{id}/react", requireSession(http.HandlerFunc(reactionHandler.AddReaction)))
-	mux.Handle("DELETE /api/items/{id}/react", requireSession(http.HandlerFunc(reactionHandler.RemoveReaction)))
-	mux.Handle("GET /api/items/{id}/reactions", requireSession(http.HandlerFunc(reactionHandler.GetReactions)))
-	mux.Handle("GET /api/reactions/emojis", requireSession(http.HandlerFunc(reactionHandler.GetAllowedEmojis)))
-
-	// Support endpoint
-	mux.Handle("POST /api/support", requireSession(http.HandlerFunc(supportHandler.Submit)))
-
-	// AI endpoint
-	mux.Handle("POST /api/ai/generate", requireSession(aiRateLimiter.Middleware(http.HandlerFunc(aiHandler.Generate))))
-	mux.Handle("POST /api/ai/guide", requireSession(aiRateLimiter.Middleware(http.HandlerFunc(aiHandler.Guide))))
-
-	// Static files
-	fs := http.FileServer(http.Dir("web/static"))
-	mux.Handle("GET /static/", http.StripPrefix("/static/", fs))
-
-	// Reminder public endpoints
-	mux.Handle("GET /r/img/{token}", http.HandlerFunc(reminderPublicHandler.ServeImage))
-	mux.Handle("GET /r/unsubscribe", http.HandlerFunc(reminderPublicHandler.UnsubscribeConfirm))
-	mux.Handle("POST /r/unsubscribe", http.HandlerFunc(reminderPublicHandler.UnsubscribeSubmit))
-
-	// OpenGraph images (public)
-	mux.Handle("GET /og/default.png", http.HandlerFunc(ogImageHandler.Default))
-	mux.Handle("GET /og/share/{token}", http.HandlerFunc(shareOGImageHandler.Serve))
-
-	// Public share landing page (for link unfurls)
-	mux.Handle("GET /s/{token}", http.HandlerFunc(sharePublicHandler.Serve))
-
-	// API Docs redirect
-	mux.Handle("GET /api/docs", http.RedirectHandler("/static/swagger/index.html", http.StatusFound))
-
-	// SPA route - serve index.html for all client-side routes
-	mux.Handle("GET /{path...}", requireSession(http.HandlerFunc(pageHandler.Index)))
-
-	// Build middleware chain (order matters: outermost first)
-	var handler http.Handler = mux
-	handler = authMiddleware.Authenticate(handler)
-	handler = csrfMiddleware.Protect(handler)
-	handler = cacheControl.Apply(handler)
-	handler = compress.Apply(handler)
-	handler = securityHeaders.Apply(handler)
-	handler = requestLogger.Apply(handler)
+	rateLimitByCaller := middleware.RateLimitKeyByCaller
+	aiLimit := services.UsageLimitSpec{Name: "ai", Limit: aiRateLimit, Window: time.Hour, KeyPrefix: "ratelimit:ai:"}
+	aiRateLimiter := middleware.NewRateLimiter(redisDB.Client, aiLimit.Limit, aiLimit.Window, aiLimit.KeyPrefix, rateLimitByCaller, false)
+	// Reactions are cheap, so the limiter fails open; it only stops scripted add/remove loops.
+	reactionLimit := services.UsageLimitSpec{Name: "reactions", Limit: 60, Window: time.Minute, KeyPrefix: "ratelimit:reactions:"}
+	reactionRateLimiter := middleware.NewRateLimiter(redisDB.Client, reactionLimit.Limit, reactionLimit.Window, reactionLimit.KeyPrefix, rateLimitByCaller, true)
+	// Comments notify the card owner, so they are limited harder than
+	// reactions; the limiter still fails open.
+	commentLimit := services.UsageLimitSpec{Name: "comments", Limit: 10, Window: time.Minute, KeyPrefix: "ratelimit:comments:"}
+	commentRateLimiter := middleware.NewRateLimiter(redisDB.Client, commentLimit.Limit, commentLimit.Window, commentLimit.KeyPrefix, rateLimitByCaller, true)
+	usageService.SetLimits(aiLimit, reactionLimit, commentLimit, handlers.AccountExportUsageLimit())
+	usageTracker := middleware.NewUsageTracker(usageService)
+	// Widget images are public and polled, so they are limited per token to
+	// stop a leaked link being hotlinked; the limiter fails open.
+	widgetRateLimiter := middleware.NewRateLimiter(redisDB.Client, 120, time.Hour, "ratelimit:widget:", func(r *http.Request) string {
+		return strings.TrimSuffix(r.PathValue("token"), ".png")
+	}, true)
+	// Share subscriptions send mail to an address the caller chooses, so
+	// sign-ups are limited per IP and the limiter fails closed.
+	shareSubscribeRateLimiter := middleware.NewRateLimiter(redisDB.Client, 10, time.Hour, "ratelimit:share-subscribe:", middleware.GetClientIP, false)
+	// Each inbox delivery can make the server fetch the sender's actor, so
+	// deliveries are limited per IP; the limiter fails open.
+	activityPubInboxLimiter := middleware.NewRateLimiter(redisDB.Client, 120, time.Minute, "ratelimit:activitypub-inbox:", middleware.GetClientIP, true)
+
+	handler := httpserver.New(httpserver.Handlers{
+		Health:            healthHandler,
+		Version:           versionHandler,
+		Auth:              authHandler,
+		ProviderAuth:      providerAuthHandler,
+		Account:           accountHandler,
+		Profile:           profileHandler,
+		Usage:             usageHandler,
+		APIToken:          apiTokenHandler,
+		Webhook:           webhookHandler,
+		Card:              cardHandler,
+		ShareSubscription: shareSubscriptionHandler,
+		Suggestion:        suggestionHandler,
+		Friend:            friendHandler,
+		Block:             blockHandler,
+		Invite:            inviteHandler,
+		Notification:      notificationHandler,
+		Reminder:          reminderHandler,
+		ReminderPublic:    reminderPublicHandler,
+		Admin:             adminHandler,
+		Jobs:              jobsHandler,
+		Reaction:          reactionHandler,
+		Comment:           commentHandler,
+		Support:           supportHandler,
+		AI:                aiHandler,
+		Proof:             proofHandler,
+		OGImage:           ogImageHandler,
+		ShareOGImage:      shareOGImageHandler,
+		SharePublic:       sharePublicHandler,
+		ProfilePublic:     profilePublicHandler,
+		Page:              pageHandler,
+		ActivityPub:       activityPubHandler,
+		StaticDir:         "web/static",
+	}, httpserver.Middleware{
+		Auth:                      authMiddleware,
+		CSRF:                      csrfMiddleware,
+		SecurityHeaders:           securityHeaders,
+		CacheControl:              cacheControl,
+		Compress:                  compress,
+		RequestLogger:             requestLogger,
+		DisconnectWatchdog:        disconnectWatchdog,
+		UsageTracker:              usageTracker,
+		AdminGate:                 middleware.NewAdminGate(resolveAdminUserIDs(cfg.Admin.UserIDs, logger)),
+		InternalTokenGate:         middleware.NewInternalTokenGate(cfg.Admin.InternalToken),
+		AIRateLimiter:             aiRateLimiter,
+		ReactionRateLimiter:       reactionRateLimiter,
+		CommentRateLimiter:        commentRateLimiter,
+		WidgetRateLimiter:         widgetRateLimiter,
+		ShareSubscribeRateLimiter: shareSubscribeRateLimiter,
+		ActivityPubInboxLimiter:   activityPubInboxLimiter,
+	})
 
 	// Create server
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	server := &http.Server{
-		Addr:        addr,
-		Handler:     handler,
-		ReadTimeout: 15 * time.Second,
-		// AI generation calls can legitimately take >15s; keep a higher write timeout
-		// so the frontend gets a JSON error/response instead of a dropped connection.
-		WriteTimeout: 95 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	server := newHTTPServer(addr, handler, readHeaderTimeout)
+	listener, err := listen(addr, cfg.Server.MaxConnections)
+	if err != nil {
+		cleanupCancel()
+		reminderCancel()
+		return fmt.Errorf("listening on %s: %w", addr, err)
 	}
 
 	// Graceful shutdown
@@ -418,13 +662,17 @@ func run() error {
 				"error": err.Error(),
 			})
 		}
+		if _, err := shareAccessLog.Flush(ctx); err != nil {
+			logger.Warn("Share access log flush failed", map[string]interface{}{"error": err.Error()})
+		}
 		close(done)
 	}()
 
 	logger.Info("Server listening", map[string]interface{}{
-		"addr": addr,
+		"addr":            addr,
+		"max_connections": cfg.Server.MaxConnections,
 	})
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("server error: %w", err)
 	}
 
@@ -469,3 +717,16 @@ func resolveRemindersPollInterval(logger *logging.Logger, lookupEnv func(string)
 	}
 	return interval
 }
+
+func resolveAdminUserIDs(values []string, logger *logging.Logger) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			logger.Warn("Ignoring invalid ADMIN_USER_IDS entry", map[string]interface{}{"value": value})
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
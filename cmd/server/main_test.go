@@ -2,11 +2,17 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
 func TestResolveAIRateLimit_Defaults(t *testing.T) {
@@ -86,3 +92,61 @@ func TestResolveRemindersPollInterval_InvalidEnv(t *testing.T) {
 		t.Fatalf("expected fallback interval 1m, got %v", interval)
 	}
 }
+
+func TestResolveAdminUserIDs(t *testing.T) {
+	logger := logging.New().SetOutput(&bytes.Buffer{})
+	id := uuid.New()
+	ids := resolveAdminUserIDs([]string{id.String(), "not-a-uuid"}, logger)
+	if len(ids) != 1 || ids[0] != id {
+		t.Fatalf("expected only %v, got %v", id, ids)
+	}
+}
+
+func TestApplyConnectErrors(t *testing.T) {
+	report := models.SelfTestReport{OK: true, Checks: []models.SelfTestCheck{
+		{Name: services.SelfTestPostgres, Status: models.SelfTestFailed, Error: "no database connection"},
+		{Name: services.SelfTestRedis, Status: models.SelfTestOK},
+	}}
+	applyConnectErrors(&report, map[string]error{services.SelfTestPostgres: errors.New("dial tcp: refused")})
+	if report.OK || report.Checks[0].Error != "connect: dial tcp: refused" || report.Checks[1].Status != models.SelfTestOK {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	var out bytes.Buffer
+	if err := writeSelfTestReport(&out, report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"ok": false`) || !strings.Contains(out.String(), `"latency_ms": 0`) {
+		t.Fatalf("unexpected report JSON %s", out.String())
+	}
+}
+
+func TestParseSeedFlags(t *testing.T) {
+	f, err := parseSeedFlags([]string{"--users", "3", "--with-friends", "--seed", "9"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.opts.Users != 3 || f.opts.CardsPerUser != 2 || !f.opts.WithFriends || f.opts.WithReminders || f.opts.Seed != 9 || f.clean {
+		t.Fatalf("unexpected flags %+v", f)
+	}
+
+	for _, args := range [][]string{{"--users", "many"}, {"extra"}, {"--nope"}} {
+		if _, err := parseSeedFlags(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestParseDoctorFlags(t *testing.T) {
+	if fix, err := parseDoctorFlags(nil, &bytes.Buffer{}); err != nil || fix {
+		t.Fatalf("expected a read-only run, got %v %v", fix, err)
+	}
+	if fix, err := parseDoctorFlags([]string{"--fix"}, &bytes.Buffer{}); err != nil || !fix {
+		t.Fatalf("expected --fix, got %v %v", fix, err)
+	}
+	for _, args := range [][]string{{"extra"}, {"--nope"}} {
+		if _, err := parseDoctorFlags(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
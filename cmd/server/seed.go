@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// seedFlags are the options of `server seed`.
+type seedFlags struct {
+	opts  services.SeedOptions
+	clean bool
+}
+
+func parseSeedFlags(args []string, output io.Writer) (seedFlags, error) {
+	var f seedFlags
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.IntVar(&f.opts.Users, "users", 5, "number of users to create")
+	fs.IntVar(&f.opts.CardsPerUser, "cards-per-user", 2, "finalized cards per user, one per year counting back from this one")
+	fs.BoolVar(&f.opts.WithFriends, "with-friends", false, "make each user friends with the next and leave one request pending")
+	fs.BoolVar(&f.opts.WithReminders, "with-reminders", false, "add a monthly check-in and a weekly goal reminder per user")
+	fs.BoolVar(&f.opts.Force, "force", false, "seed even though the database already has users")
+	fs.Int64Var(&f.opts.Seed, "seed", 1, "random seed; the same seed gives the same goals and progress")
+	fs.BoolVar(&f.clean, "clean", false, "delete every seeded account ("+services.SeedEmailDomain+") instead of seeding")
+	if err := fs.Parse(args); err != nil {
+		return seedFlags{}, err
+	}
+	if fs.NArg() > 0 {
+		return seedFlags{}, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	return f, nil
+}
+
+// runSeed creates demo data, or removes it with --clean, and prints a JSON
+// summary to stdout. Migrations run first so it works on a fresh database.
+func runSeed(args []string) error {
+	// Keep stdout for the summary.
+	logging.Default.SetOutput(os.Stderr)
+
+	f, err := parseSeedFlags(args, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	db, _, closeDB, err := openDatabase(cfg, logging.Default, true)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	seed := services.NewSeedService(db, cfg.Email.BaseURL)
+	ctx := context.Background()
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if f.clean {
+		deleted, err := seed.Clean(ctx)
+		if err != nil {
+			return err
+		}
+		return enc.Encode(map[string]int64{"deleted_users": deleted})
+	}
+	report, err := seed.Seed(ctx, f.opts)
+	if err != nil {
+		return err
+	}
+	return enc.Encode(report)
+}
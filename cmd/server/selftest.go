@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+	"github.com/HammerMeetNail/yearofbingo/internal/services/ai"
+)
+
+// runSelfTest checks connectivity to Postgres, Redis, the email provider and
+// the AI provider, prints a JSON report to stdout and returns an error if any
+// check failed. It doesn't run migrations or start background jobs, so run it
+// against a database the server has already migrated.
+func runSelfTest() error {
+	// Keep stdout for the report.
+	logging.Default.SetOutput(os.Stderr)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	connectErrs := map[string]error{}
+	var dbConn services.DBConn
+	db, _, closeDB, err := openDatabase(cfg, logging.Default, false)
+	if err != nil {
+		connectErrs[services.SelfTestPostgres] = err
+	} else {
+		defer closeDB()
+		dbConn = db
+	}
+	var redisClient services.RedisClient
+	redisDB, err := openRedis(cfg, logging.Default)
+	if err != nil {
+		connectErrs[services.SelfTestRedis] = err
+	} else {
+		defer func() { _ = redisDB.Close() }()
+		redisClient = services.NewRedisAdapter(redisDB.Client)
+	}
+
+	selfTest := services.NewSelfTestService(dbConn, redisClient)
+	if cfg.Admin.SelfTestEmail != "" {
+		// Without a database the plaintext preference lookup is skipped.
+		selfTest.SetEmail(services.NewEmailService(&cfg.Email, dbConn), cfg.Admin.SelfTestEmail)
+	}
+	if cfg.AI.GeminiAPIKey != "" && !cfg.AI.Stub {
+		selfTest.SetAIPinger(ai.NewService(cfg, nil))
+	}
+
+	report := selfTest.Run(context.Background())
+	applyConnectErrors(&report, connectErrs)
+	if err := writeSelfTestReport(os.Stdout, report); err != nil {
+		return err
+	}
+	if !report.OK {
+		return fmt.Errorf("self-test failed")
+	}
+	return nil
+}
+
+// applyConnectErrors replaces the generic "no connection" error of checks
+// whose client couldn't be created with the error from connecting.
+func applyConnectErrors(report *models.SelfTestReport, errs map[string]error) {
+	for i, check := range report.Checks {
+		if err, ok := errs[check.Name]; ok {
+			report.Checks[i].Status = models.SelfTestFailed
+			report.Checks[i].Error = "connect: " + err.Error()
+			report.OK = false
+		}
+	}
+}
+
+func writeSelfTestReport(w io.Writer, report models.SelfTestReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// readHeaderTimeout bounds how long a client may take to send request
+	// headers, which is what slowloris-style clients stall on.
+	readHeaderTimeout = 5 * time.Second
+	// maxHeaderBytes caps request line plus headers.
+	maxHeaderBytes = 64 << 10
+)
+
+func newHTTPServer(addr string, handler http.Handler, headerTimeout time.Duration) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: headerTimeout,
+		ReadTimeout:       15 * time.Second,
+		// AI generation calls can legitimately take >15s; keep a higher write timeout
+		// so the frontend gets a JSON error/response instead of a dropped connection.
+		WriteTimeout:   95 * time.Second,
+		IdleTimeout:    60 * time.Second,
+		MaxHeaderBytes: maxHeaderBytes,
+	}
+}
+
+// listen opens a TCP listener on addr, limited to maxConns concurrently
+// accepted connections when maxConns > 0.
+func listen(addr string, maxConns int) (net.Listener, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if maxConns <= 0 {
+		return ln, nil
+	}
+	return newLimitListener(ln, maxConns), nil
+}
+
+// limitListener blocks Accept once n connections are open, releasing a slot
+// when a connection is closed. It mirrors golang.org/x/net/netutil.LimitListener,
+// which isn't a dependency of this module.
+type limitListener struct {
+	net.Listener
+	sem       chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newLimitListener(l net.Listener, n int) *limitListener {
+	return &limitListener{
+		Listener: l,
+		sem:      make(chan struct{}, n),
+		done:     make(chan struct{}),
+	}
+}
+
+func (l *limitListener) acquire() bool {
+	select {
+	case <-l.done:
+		return false
+	case l.sem <- struct{}{}:
+		return true
+	}
+}
+
+func (l *limitListener) release() { <-l.sem }
+
+func (l *limitListener) Accept() (net.Conn, error) {
+	if !l.acquire() {
+		return nil, net.ErrClosed
+	}
+	c, err := l.Listener.Accept()
+	if err != nil {
+		l.release()
+		return nil, err
+	}
+	return &limitListenerConn{Conn: c, release: l.release}, nil
+}
+
+func (l *limitListener) Close() error {
+	err := l.Listener.Close()
+	l.closeOnce.Do(func() { close(l.done) })
+	return err
+}
+
+type limitListenerConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (c *limitListenerConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
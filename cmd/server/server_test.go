@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPServer_Limits(t *testing.T) {
+	server := newHTTPServer(":0", http.NotFoundHandler(), readHeaderTimeout)
+	if server.ReadHeaderTimeout != readHeaderTimeout {
+		t.Fatalf("expected ReadHeaderTimeout %s, got %s", readHeaderTimeout, server.ReadHeaderTimeout)
+	}
+	if server.MaxHeaderBytes != maxHeaderBytes {
+		t.Fatalf("expected MaxHeaderBytes %d, got %d", maxHeaderBytes, server.MaxHeaderBytes)
+	}
+}
+
+func TestHTTPServer_SlowHeadersAreDropped(t *testing.T) {
+	ln, err := listen("127.0.0.1:0", 0)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := newHTTPServer(ln.Addr().String(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be reached by a client that never finishes its headers")
+	}), 200*time.Millisecond)
+	go func() { _ = server.Serve(ln) }()
+	defer func() { _ = server.Close() }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Send a partial request and then stall, as a slowloris client would.
+	if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\nX-Slow: ")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	start := time.Now()
+	_, err = io.ReadAll(conn)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatal("expected server to close the connection before the client deadline")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected connection to close shortly after header timeout, took %s", elapsed)
+	}
+}
+
+func TestHTTPServer_OversizedHeadersRejected(t *testing.T) {
+	ln, err := listen("127.0.0.1:0", 0)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := newHTTPServer(ln.Addr().String(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), readHeaderTimeout)
+	go func() { _ = server.Serve(ln) }()
+	defer func() { _ = server.Close() }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	big := strings.Repeat("a", maxHeaderBytes*2)
+	_, _ = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\nX-Big: " + big + "\r\n\r\n"))
+
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	buf := make([]byte, 64)
+	n, _ := conn.Read(buf)
+	if !strings.Contains(string(buf[:n]), "431") {
+		t.Fatalf("expected 431 response, got %q", string(buf[:n]))
+	}
+}
+
+func TestLimitListener_BlocksBeyondMax(t *testing.T) {
+	ln, err := listen("127.0.0.1:0", 1)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- c
+		}
+	}()
+
+	first, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial first: %v", err)
+	}
+	defer func() { _ = first.Close() }()
+
+	var firstServer net.Conn
+	select {
+	case firstServer = <-accepted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected first connection to be accepted")
+	}
+
+	second, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial second: %v", err)
+	}
+	defer func() { _ = second.Close() }()
+
+	select {
+	case <-accepted:
+		t.Fatal("expected second connection to wait for a free slot")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	_ = firstServer.Close()
+	select {
+	case c := <-accepted:
+		_ = c.Close()
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected second connection to be accepted after the first closed")
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/database"
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// openDatabase connects to the configured database and runs its
+// migrations. The returned close func releases the connection.
+func openDatabase(cfg *config.Config, logger *logging.Logger, migrate bool) (services.DB, handlers.HealthChecker, func(), error) {
+	if cfg.Database.IsSQLite() {
+		logger.Info("Opening SQLite database", map[string]interface{}{
+			"path": cfg.Database.SQLitePath,
+		})
+		db, err := database.NewSQLiteDB(cfg.Database.SQLitePath)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("opening sqlite: %w", err)
+		}
+		if migrate {
+			if err := runMigrations(logger, database.SQLiteMigrationURL(cfg.Database.SQLitePath), "migrations/sqlite"); err != nil {
+				db.Close()
+				return nil, nil, nil, err
+			}
+		}
+		return services.NewSQLiteAdapter(db.DB), db, db.Close, nil
+	}
+
+	logger.Info("Connecting to PostgreSQL", map[string]interface{}{
+		"host": cfg.Database.Host,
+		"port": cfg.Database.Port,
+	})
+	db, err := database.NewPostgresDB(cfg.Database.DSN())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("connecting to postgres: %w", err)
+	}
+	logger.Info("Connected to PostgreSQL")
+	if migrate {
+		if err := runMigrations(logger, cfg.Database.DSN(), "migrations"); err != nil {
+			db.Close()
+			return nil, nil, nil, err
+		}
+	}
+	return services.NewPoolAdapter(db.Pool), db, db.Close, nil
+}
+
+func runMigrations(logger *logging.Logger, dsn, path string) error {
+	logger.Info("Running database migrations...")
+	migrator, err := database.NewMigrator(dsn, path)
+	if err != nil {
+		return fmt.Errorf("creating migrator: %w", err)
+	}
+	if err := migrator.Up(); err != nil {
+		_ = migrator.Close()
+		return fmt.Errorf("running migrations: %w", err)
+	}
+	_ = migrator.Close()
+	logger.Info("Migrations completed")
+	return nil
+}
+
+// openRedis connects to Redis, or starts an in-process one for SQLite
+// installs, which run without a Redis server.
+func openRedis(cfg *config.Config, logger *logging.Logger) (*database.RedisDB, error) {
+	if cfg.Database.IsSQLite() {
+		logger.Info("Using in-memory Redis for single-user mode")
+		redisDB, err := database.NewMemoryRedisDB()
+		if err != nil {
+			return nil, fmt.Errorf("starting in-memory redis: %w", err)
+		}
+		return redisDB, nil
+	}
+
+	logger.Info("Connecting to Redis", map[string]interface{}{
+		"addr": cfg.Redis.Addr(),
+	})
+	redisDB, err := database.NewRedisDB(cfg.Redis.Addr(), cfg.Redis.Password, cfg.Redis.DB)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to redis: %w", err)
+	}
+	logger.Info("Connected to Redis")
+	return redisDB, nil
+}
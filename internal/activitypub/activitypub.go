@@ -0,0 +1,682 @@
+// Package activitypub is the experimental ActivityPub actor for public
+// profiles, enabled with ACTIVITYPUB_ENABLED. Each user with a public
+// profile and a fediverse-safe username can be followed from Mastodon and
+// similar servers; their outbox posts goal completions and bingos on the
+// cards their profile shows, and never goal text. Inboxes only act on Follow
+// and Undo. Every actor signs with one key for the whole instance.
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+const (
+	// ContentType is the media type of ActivityPub documents.
+	ContentType = "application/activity+json"
+
+	// MaxFollowersPerUser caps the followers of one profile, which bounds the
+	// deliveries a single completion fans out to.
+	MaxFollowersPerUser = 1000
+
+	keyBits            = 2048
+	maxRemoteDocument  = 1 << 20
+	remoteAcceptHeader = `application/activity+json, application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+)
+
+var (
+	ErrNotFound          = errors.New("actor not found")
+	ErrTooManyFollowers  = errors.New("too many followers")
+	errInvalidActivity   = errors.New("invalid activity")
+	errRemoteActor       = errors.New("remote actor could not be loaded")
+	errRemoteDestination = errors.New("remote URL must be an https URL")
+)
+
+// federatedUsername matches the usernames other servers accept in a handle.
+// Profiles with other usernames stay off the fediverse.
+var federatedUsername = regexp.MustCompile(`^[A-Za-z0-9_]([A-Za-z0-9_.-]{0,62}[A-Za-z0-9_])?$`)
+
+// activityOrder pages the outbox newest first.
+var activityOrder = pagination.NewOrder("a.created_at", "a.id")
+
+// Service serves actors for public profiles, records their outbox and
+// handles follows. Deliveries to other servers run in the background and are
+// tried once; a server that misses one still sees it in the outbox.
+type Service struct {
+	db       services.DB
+	profiles services.ProfileServiceInterface
+	baseURL  string
+	client   *http.Client
+	async    func(fn func())
+	now      func() time.Time
+
+	keyMu sync.Mutex
+	key   *rsa.PrivateKey
+}
+
+func NewService(db services.DB, profiles services.ProfileServiceInterface, baseURL string) *Service {
+	return &Service{
+		db:       db,
+		profiles: profiles,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		client:   services.NewPublicHTTPClient(),
+		async:    func(fn func()) { go fn() },
+		now:      time.Now,
+	}
+}
+
+// SetAsync replaces how deliveries are started; tests run them inline.
+func (s *Service) SetAsync(fn func(fn func())) {
+	s.async = fn
+}
+
+func (s *Service) actorID(username string) string {
+	return s.baseURL + "/ap/users/" + username
+}
+
+func (s *Service) keyID(username string) string {
+	return s.actorID(username) + "#main-key"
+}
+
+func (s *Service) profileURL(username string) string {
+	return s.baseURL + "/u/" + username
+}
+
+// host is the domain in this instance's handles.
+func (s *Service) host() string {
+	u, err := url.Parse(s.baseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// lookup finds the public profile behind an actor, under the same rules as
+// /u/{username}.
+func (s *Service) lookup(ctx context.Context, username string) (*models.PublicProfile, error) {
+	if !federatedUsername.MatchString(username) {
+		return nil, ErrNotFound
+	}
+	profile, err := s.profiles.GetPublicProfile(ctx, username)
+	if errors.Is(err, services.ErrProfileNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// privateKey loads the instance key, creating it on first use. Replicas
+// racing to create it all end up using the one that was stored.
+func (s *Service) privateKey(ctx context.Context) (*rsa.PrivateKey, error) {
+	s.keyMu.Lock()
+	defer s.keyMu.Unlock()
+	if s.key != nil {
+		return s.key, nil
+	}
+
+	var encoded string
+	err := s.db.QueryRow(ctx, "SELECT private_key_pem FROM activitypub_keys WHERE id = 1").Scan(&encoded)
+	if errors.Is(err, pgx.ErrNoRows) {
+		generated, genErr := rsa.GenerateKey(rand.Reader, keyBits)
+		if genErr != nil {
+			return nil, fmt.Errorf("generating activitypub key: %w", genErr)
+		}
+		der, genErr := x509.MarshalPKCS8PrivateKey(generated)
+		if genErr != nil {
+			return nil, fmt.Errorf("encoding activitypub key: %w", genErr)
+		}
+		if _, err := s.db.Exec(ctx,
+			"INSERT INTO activitypub_keys (id, private_key_pem) VALUES (1, $1) ON CONFLICT (id) DO NOTHING",
+			string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		); err != nil {
+			return nil, fmt.Errorf("storing activitypub key: %w", err)
+		}
+		err = s.db.QueryRow(ctx, "SELECT private_key_pem FROM activitypub_keys WHERE id = 1").Scan(&encoded)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading activitypub key: %w", err)
+	}
+
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil {
+		return nil, errors.New("activitypub key is not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing activitypub key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("activitypub key is not RSA")
+	}
+	s.key = key
+	return key, nil
+}
+
+// Actor returns the Person document for username.
+func (s *Service) Actor(ctx context.Context, username string) (*Actor, error) {
+	profile, err := s.lookup(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.privateKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("encoding activitypub public key: %w", err)
+	}
+
+	id := s.actorID(profile.Username)
+	return &Actor{
+		Context:           []string{activityStreamsContext, securityContext},
+		ID:                id,
+		Type:              "Person",
+		PreferredUsername: profile.Username,
+		Name:              profile.Username,
+		Summary:           "<p>Goals completed and bingos from " + profile.Username + "'s public bingo cards.</p>",
+		URL:               s.profileURL(profile.Username),
+		Inbox:             id + "/inbox",
+		Outbox:            id + "/outbox",
+		Followers:         id + "/followers",
+		Discoverable:      profile.Indexable,
+		Indexable:         profile.Indexable,
+		PublicKey: PublicKey{
+			ID:           s.keyID(profile.Username),
+			Owner:        id,
+			PublicKeyPem: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		},
+	}, nil
+}
+
+// WebFinger resolves an acct: handle or actor URL on this instance.
+func (s *Service) WebFinger(ctx context.Context, resource string) (*WebFinger, error) {
+	username, ok := s.parseResource(resource)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	profile, err := s.lookup(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	id := s.actorID(profile.Username)
+	return &WebFinger{
+		Subject: "acct:" + profile.Username + "@" + s.host(),
+		Aliases: []string{id, s.profileURL(profile.Username)},
+		Links: []WebFingerLink{
+			{Rel: "self", Type: ContentType, Href: id},
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: s.profileURL(profile.Username)},
+		},
+	}, nil
+}
+
+func (s *Service) parseResource(resource string) (string, bool) {
+	if handle, ok := strings.CutPrefix(resource, "acct:"); ok {
+		handle = strings.TrimPrefix(handle, "@")
+		at := strings.LastIndexByte(handle, '@')
+		if at <= 0 || !strings.EqualFold(handle[at+1:], s.host()) {
+			return "", false
+		}
+		return handle[:at], true
+	}
+	if username, ok := strings.CutPrefix(resource, s.actorID("")); ok && username != "" {
+		return username, true
+	}
+	return "", false
+}
+
+// OutboxPage is one page of an outbox, newest first.
+type OutboxPage struct {
+	Username   string
+	Activities []Activity
+	// Total counts the whole outbox.
+	Total int
+	// Next is the position after the page's last activity, or nil on the
+	// last page.
+	Next *pagination.Key
+}
+
+// Outbox returns the page of username's outbox after the position after, or
+// the first page when it is nil.
+func (s *Service) Outbox(ctx context.Context, username string, after *pagination.Key, limit int) (*OutboxPage, error) {
+	profile, err := s.lookup(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int
+	if err := s.db.QueryRow(ctx,
+		`SELECT COUNT(*)
+		 FROM activitypub_activities a
+		 JOIN bingo_cards c ON c.id = a.card_id
+		 WHERE a.user_id = $1 AND c.is_finalized = true AND c.visible_to_friends = true`,
+		profile.UserID,
+	).Scan(&total); err != nil {
+		return nil, fmt.Errorf("counting activitypub activities: %w", err)
+	}
+
+	// Cards hidden from friends since are left out, as on the profile.
+	query := `SELECT a.id, a.kind, a.completed_items, a.total_items, a.bingo_count, a.created_at, c.year, c.title
+	          FROM activitypub_activities a
+	          JOIN bingo_cards c ON c.id = a.card_id
+	          WHERE a.user_id = $1 AND c.is_finalized = true AND c.visible_to_friends = true`
+	args := []any{profile.UserID}
+	if after != nil {
+		condition, keyArgs := activityOrder.After(*after, len(args)+1)
+		query += " AND " + condition
+		args = append(args, keyArgs...)
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", activityOrder.OrderBy(), len(args))
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing activitypub activities: %w", err)
+	}
+	defer rows.Close()
+	var records []activityRecord
+	for rows.Next() {
+		record, err := scanActivity(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing activitypub activities: %w", err)
+	}
+
+	records, hasMore := pagination.Trim(records, limit)
+	page := &OutboxPage{Username: profile.Username, Activities: make([]Activity, 0, len(records)), Total: total}
+	for _, record := range records {
+		page.Activities = append(page.Activities, s.createActivity(profile, record))
+	}
+	if hasMore {
+		last := records[len(records)-1]
+		page.Next = &pagination.Key{At: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// Activity returns one Create from username's outbox.
+func (s *Service) Activity(ctx context.Context, username string, id uuid.UUID) (*Activity, error) {
+	profile, err := s.lookup(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	record, err := s.loadActivity(ctx, profile.UserID, id)
+	if err != nil {
+		return nil, err
+	}
+	activity := s.createActivity(profile, record)
+	activity.Context = activityStreamsContext
+	return &activity, nil
+}
+
+// FollowerCount is how many remote actors follow username.
+func (s *Service) FollowerCount(ctx context.Context, username string) (int, error) {
+	profile, err := s.lookup(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	if err := s.db.QueryRow(ctx,
+		"SELECT COUNT(*) FROM activitypub_followers WHERE user_id = $1", profile.UserID,
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting activitypub followers: %w", err)
+	}
+	return count, nil
+}
+
+func (s *Service) loadActivity(ctx context.Context, userID, id uuid.UUID) (activityRecord, error) {
+	record, err := scanActivity(s.db.QueryRow(ctx,
+		`SELECT a.id, a.kind, a.completed_items, a.total_items, a.bingo_count, a.created_at, c.year, c.title
+		 FROM activitypub_activities a
+		 JOIN bingo_cards c ON c.id = a.card_id
+		 WHERE a.id = $1 AND a.user_id = $2 AND c.is_finalized = true AND c.visible_to_friends = true`,
+		id, userID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return activityRecord{}, ErrNotFound
+	}
+	return record, err
+}
+
+func scanActivity(row services.Row) (activityRecord, error) {
+	var record activityRecord
+	err := row.Scan(&record.ID, &record.Kind, &record.CompletedItems, &record.TotalItems, &record.BingoCount,
+		&record.CreatedAt, &record.CardYear, &record.CardTitle)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return activityRecord{}, fmt.Errorf("scanning activitypub activity: %w", err)
+	}
+	return record, err
+}
+
+// createActivity wraps record's Note in a Create. Profiles that aren't
+// indexable post unlisted: to followers, with the public only copied.
+func (s *Service) createActivity(profile *models.PublicProfile, record activityRecord) Activity {
+	actor := s.actorID(profile.Username)
+	to, cc := []string{publicAddress}, []string{actor + "/followers"}
+	if !profile.Indexable {
+		to, cc = cc, to
+	}
+	published := record.CreatedAt.UTC().Format(time.RFC3339)
+	return Activity{
+		ID:        actor + "/activities/" + record.ID.String(),
+		Type:      "Create",
+		Actor:     actor,
+		Published: published,
+		To:        to,
+		Cc:        cc,
+		Object: Note{
+			ID:           actor + "/notes/" + record.ID.String(),
+			Type:         "Note",
+			AttributedTo: actor,
+			Content:      noteContent(record),
+			URL:          s.profileURL(profile.Username),
+			Published:    published,
+			To:           to,
+			Cc:           cc,
+		},
+	}
+}
+
+// Note returns the Note of one Create in username's outbox.
+func (s *Service) Note(ctx context.Context, username string, id uuid.UUID) (*Note, error) {
+	activity, err := s.Activity(ctx, username, id)
+	if err != nil {
+		return nil, err
+	}
+	note := activity.Object.(Note)
+	note.Context = activityStreamsContext
+	return &note, nil
+}
+
+// RecordActivity adds a completion or bingo to the owner's outbox and sends
+// it to their followers. Nothing is recorded unless the owner's profile is
+// public and the card is one it shows.
+func (s *Service) RecordActivity(ctx context.Context, userID, cardID uuid.UUID, kind string, bingoCount int) error {
+	var id uuid.UUID
+	err := s.db.QueryRow(ctx,
+		`INSERT INTO activitypub_activities (user_id, card_id, kind, completed_items, total_items, bingo_count)
+		 SELECT c.user_id, c.id, $3,
+		        (SELECT COUNT(*) FROM bingo_items bi WHERE bi.card_id = c.id AND bi.is_completed = true),
+		        c.grid_size * c.grid_size - CASE WHEN c.has_free_space THEN 1 ELSE 0 END,
+		        $4
+		 FROM bingo_cards c
+		 JOIN users u ON u.id = c.user_id
+		 WHERE c.id = $2 AND c.user_id = $1
+		   AND c.is_finalized = true AND c.visible_to_friends = true
+		   AND u.deleted_at IS NULL AND u.profile_visibility = 'public'
+		 RETURNING id`,
+		userID, cardID, kind, bingoCount,
+	).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("recording activitypub activity: %w", err)
+	}
+
+	s.async(func() {
+		ctx := context.WithoutCancel(ctx)
+		if err := s.deliverToFollowers(ctx, userID, id); err != nil {
+			logging.Warn("Failed to deliver ActivityPub activity", map[string]interface{}{
+				"error":       err.Error(),
+				"user_id":     userID.String(),
+				"activity_id": id.String(),
+			})
+		}
+	})
+	return nil
+}
+
+// deliverToFollowers sends a recorded activity to each follower inbox once.
+func (s *Service) deliverToFollowers(ctx context.Context, userID, activityID uuid.UUID) error {
+	var username string
+	if err := s.db.QueryRow(ctx, "SELECT username FROM users WHERE id = $1", userID).Scan(&username); err != nil {
+		return fmt.Errorf("loading activitypub actor: %w", err)
+	}
+	activity, err := s.Activity(ctx, username, activityID)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.db.Query(ctx,
+		"SELECT DISTINCT inbox_url FROM activitypub_followers WHERE user_id = $1", userID,
+	)
+	if err != nil {
+		return fmt.Errorf("listing activitypub followers: %w", err)
+	}
+	var inboxes []string
+	for rows.Next() {
+		var inbox string
+		if err := rows.Scan(&inbox); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning activitypub follower: %w", err)
+		}
+		inboxes = append(inboxes, inbox)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("listing activitypub followers: %w", err)
+	}
+
+	for _, inbox := range inboxes {
+		if err := s.deliver(ctx, username, inbox, activity); err != nil {
+			logging.Warn("ActivityPub delivery failed", map[string]interface{}{
+				"error": err.Error(),
+				"inbox": inbox,
+			})
+		}
+	}
+	return nil
+}
+
+// HandleInbox verifies an activity delivered to username's inbox and applies
+// it. Follow and Undo of a Follow are the only activities acted on; anything
+// else is accepted and ignored.
+func (s *Service) HandleInbox(ctx context.Context, username string, r *http.Request, body []byte) error {
+	profile, err := s.lookup(ctx, username)
+	if err != nil {
+		return err
+	}
+	var activity incomingActivity
+	if err := json.Unmarshal(body, &activity); err != nil || activity.Type == "" || activity.Actor == "" {
+		return errInvalidActivity
+	}
+
+	var signer *remoteActor
+	_, err = verifyRequest(r, body, s.now(), func(keyID string) (*rsa.PublicKey, error) {
+		actor, err := s.fetchActor(ctx, profile.Username, keyID)
+		if err != nil {
+			return nil, err
+		}
+		if actor.PublicKey.ID != keyID || actor.PublicKey.Owner != actor.ID {
+			return nil, errInvalidSignature
+		}
+		signer = actor
+		return parsePublicKey(actor.PublicKey.PublicKeyPem)
+	})
+	if err != nil {
+		return err
+	}
+	// The signature proves who sent the request; it must be the actor the
+	// activity claims to be from.
+	if signer.ID != activity.Actor {
+		return errInvalidSignature
+	}
+
+	switch activity.Type {
+	case "Follow":
+		if target, _ := objectRef(activity.Object); target != s.actorID(profile.Username) {
+			return errInvalidActivity
+		}
+		return s.follow(ctx, profile, signer, body)
+	case "Undo":
+		if _, objectType := objectRef(activity.Object); objectType != "" && objectType != "Follow" {
+			return nil
+		}
+		if _, err := s.db.Exec(ctx,
+			"DELETE FROM activitypub_followers WHERE user_id = $1 AND actor_uri = $2",
+			profile.UserID, signer.ID,
+		); err != nil {
+			return fmt.Errorf("removing activitypub follower: %w", err)
+		}
+	}
+	return nil
+}
+
+// follow records follower and sends the Accept of their Follow.
+func (s *Service) follow(ctx context.Context, profile *models.PublicProfile, follower *remoteActor, followActivity []byte) error {
+	if err := checkRemoteURL(follower.Inbox); err != nil {
+		return errInvalidActivity
+	}
+	var count int
+	if err := s.db.QueryRow(ctx,
+		"SELECT COUNT(*) FROM activitypub_followers WHERE user_id = $1 AND actor_uri <> $2",
+		profile.UserID, follower.ID,
+	).Scan(&count); err != nil {
+		return fmt.Errorf("counting activitypub followers: %w", err)
+	}
+	if count >= MaxFollowersPerUser {
+		return ErrTooManyFollowers
+	}
+	if _, err := s.db.Exec(ctx,
+		`INSERT INTO activitypub_followers (user_id, actor_uri, inbox_url)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id, actor_uri) DO UPDATE SET inbox_url = EXCLUDED.inbox_url`,
+		profile.UserID, follower.ID, follower.Inbox,
+	); err != nil {
+		return fmt.Errorf("adding activitypub follower: %w", err)
+	}
+
+	actor := s.actorID(profile.Username)
+	accept := Activity{
+		Context: activityStreamsContext,
+		ID:      actor + "#accepts/" + uuid.NewString(),
+		Type:    "Accept",
+		Actor:   actor,
+		Object:  json.RawMessage(followActivity),
+	}
+	s.async(func() {
+		if err := s.deliver(context.WithoutCancel(ctx), profile.Username, follower.Inbox, accept); err != nil {
+			logging.Warn("Failed to deliver ActivityPub Accept", map[string]interface{}{
+				"error":    err.Error(),
+				"follower": follower.ID,
+			})
+		}
+	})
+	return nil
+}
+
+// deliver POSTs document to inbox, signed as username.
+func (s *Service) deliver(ctx context.Context, username, inbox string, document any) error {
+	if err := checkRemoteURL(inbox); err != nil {
+		return err
+	}
+	body, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("encoding activity: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, inbox, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentType)
+	if err := s.sign(ctx, req, username, body); err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxRemoteDocument))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("inbox responded %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// fetchActor loads the actor document a keyId belongs to, signing the GET
+// as username for servers that require signed fetches.
+func (s *Service) fetchActor(ctx context.Context, username, keyID string) (*remoteActor, error) {
+	actorURL, _, _ := strings.Cut(keyID, "#")
+	if err := checkRemoteURL(actorURL); err != nil {
+		return nil, errInvalidSignature
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, actorURL, nil)
+	if err != nil {
+		return nil, errInvalidSignature
+	}
+	req.Header.Set("Accept", remoteAcceptHeader)
+	if err := s.sign(ctx, req, username, nil); err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errRemoteActor, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", errRemoteActor, resp.StatusCode)
+	}
+	var actor remoteActor
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRemoteDocument)).Decode(&actor); err != nil {
+		return nil, fmt.Errorf("%w: %v", errRemoteActor, err)
+	}
+	// An actor can only speak for its own server.
+	fetched, _ := url.Parse(actorURL)
+	id, err := url.Parse(actor.ID)
+	if err != nil || id.Host != fetched.Host {
+		return nil, errInvalidSignature
+	}
+	return &actor, nil
+}
+
+func (s *Service) sign(ctx context.Context, req *http.Request, username string, body []byte) error {
+	key, err := s.privateKey(ctx)
+	if err != nil {
+		return err
+	}
+	return signRequest(req, body, s.keyID(username), key, s.now())
+}
+
+// checkRemoteURL accepts absolute https URLs without credentials. The HTTP
+// client separately refuses to connect to non-public addresses.
+func checkRemoteURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return errRemoteDestination
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/database"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+var testGoals = []string{"Run a 10k", "Read 12 books", "Learn to knit", "Visit Lisbon", "Plant a garden", "Bake bread", "Call grandma", "Swim in a lake", "Write a song"}
+
+func newTestDB(t *testing.T) *services.SQLiteAdapter {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bingo.db")
+	db, err := database.NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(db.Close)
+	migrator, err := database.NewMigrator(database.SQLiteMigrationURL(path), "../../migrations/sqlite")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = migrator.Close() }()
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("unexpected migration error: %v", err)
+	}
+	return services.NewSQLiteAdapter(db.DB)
+}
+
+type fixture struct {
+	db       *services.SQLiteAdapter
+	ap       *Service
+	cards    *services.CardService
+	profiles *services.ProfileService
+	user     *models.User
+	card     *models.BingoCard
+}
+
+// newFixture sets up a user "solo" with a finalized 3x3 card without a FREE
+// space, recording activities inline. The profile starts off.
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	ctx := context.Background()
+	db := newTestDB(t)
+	user, err := services.NewUserService(db).Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "solo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	profiles := services.NewProfileService(db, "https://bingo.example")
+	ap := NewService(db, profiles, "https://bingo.example/")
+	ap.SetAsync(func(fn func()) { fn() })
+
+	cards := services.NewCardService(db)
+	cards.SetActivityRecorder(ap)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: 2026, GridSize: 3, Header: "BIN"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	for _, content := range testGoals {
+		if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+	return &fixture{db: db, ap: ap, cards: cards, profiles: profiles, user: user, card: card}
+}
+
+func (f *fixture) setPublic(t *testing.T, public bool) {
+	t.Helper()
+	visibility := models.ProfileVisibilityOff
+	if public {
+		visibility = models.ProfileVisibilityPublic
+	}
+	if _, err := f.profiles.UpdateSettings(context.Background(), f.user.ID, &visibility, nil); err != nil {
+		t.Fatalf("unexpected error updating profile: %v", err)
+	}
+}
+
+func (f *fixture) complete(t *testing.T, position int) {
+	t.Helper()
+	if _, err := f.cards.CompleteItem(context.Background(), f.user.ID, f.card.ID, position, models.CompleteItemParams{}); err != nil {
+		t.Fatalf("unexpected error completing %d: %v", position, err)
+	}
+}
+
+func TestService_OutboxFollowsPublicProfileRules(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	// Nothing is published, or even served, before the profile is public.
+	f.complete(t, 0)
+	if _, err := f.ap.Outbox(ctx, "solo", nil, 20); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a private profile, got %v", err)
+	}
+
+	f.setPublic(t, true)
+	f.complete(t, 4)
+	f.complete(t, 8) // the diagonal 0-4-8
+
+	page, err := f.ap.Outbox(ctx, "SOLO", nil, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Username != "solo" || page.Total != 2 || len(page.Activities) != 2 || page.Next != nil {
+		t.Fatalf("expected two activities for solo, got %+v", page)
+	}
+	bingo, completed := page.Activities[0].Object.(Note), page.Activities[1].Object.(Note)
+	if bingo.Content != "<p>Got a bingo on 2026 Bingo Card! 1 bingo, 3/9 done.</p>" {
+		t.Fatalf("unexpected bingo note %q", bingo.Content)
+	}
+	if completed.Content != "<p>Completed a goal on 2026 Bingo Card: 2/9 done.</p>" {
+		t.Fatalf("unexpected completion note %q", completed.Content)
+	}
+	encoded, _ := json.Marshal(page.Activities)
+	for _, goal := range testGoals {
+		if strings.Contains(string(encoded), goal) {
+			t.Fatalf("outbox leaks goal text %q: %s", goal, encoded)
+		}
+	}
+	// Profiles that aren't indexable post unlisted.
+	if activity := page.Activities[0]; activity.To[0] != "https://bingo.example/ap/users/solo/followers" || activity.Cc[0] != publicAddress {
+		t.Fatalf("expected an unlisted post, got to=%v cc=%v", activity.To, activity.Cc)
+	}
+
+	// Hiding the card from friends takes it off the profile and the outbox.
+	id := uuid.MustParse(page.Activities[0].ID[strings.LastIndexByte(page.Activities[0].ID, '/')+1:])
+	if _, err := f.cards.UpdateVisibility(ctx, f.user.ID, f.card.ID, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page, err := f.ap.Outbox(ctx, "solo", nil, 20); err != nil || page.Total != 0 || len(page.Activities) != 0 {
+		t.Fatalf("expected an empty outbox, got %+v %v", page, err)
+	}
+	if _, err := f.ap.Activity(ctx, "solo", id); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a hidden card's activity, got %v", err)
+	}
+	f.complete(t, 1)
+	var count int
+	if err := f.db.QueryRow(ctx, "SELECT COUNT(*) FROM activitypub_activities").Scan(&count); err != nil || count != 2 {
+		t.Fatalf("expected completions on hidden cards not to be recorded, got %d %v", count, err)
+	}
+}
+
+func TestService_OutboxPagesThroughTies(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	f.setPublic(t, true)
+	at := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		if _, err := f.db.Exec(ctx,
+			`INSERT INTO activitypub_activities (id, user_id, card_id, kind, completed_items, total_items, created_at)
+			 VALUES ($1, $2, $3, 'item_completed', $4, 9, $5)`,
+			uuid.New(), f.user.ID, f.card.ID, i+1, at.Add(time.Duration(i/3)*time.Second),
+		); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	seen := map[string]bool{}
+	var after *pagination.Key
+	for pages := 0; ; pages++ {
+		page, err := f.ap.Outbox(ctx, "solo", after, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, activity := range page.Activities {
+			if seen[activity.ID] {
+				t.Fatalf("activity %s repeated", activity.ID)
+			}
+			seen[activity.ID] = true
+		}
+		if page.Next == nil {
+			break
+		}
+		if pages > 5 {
+			t.Fatal("outbox never ended")
+		}
+		after = page.Next
+	}
+	if len(seen) != 7 {
+		t.Fatalf("expected all 7 activities, got %d", len(seen))
+	}
+}
+
+func TestService_ActorAndWebFinger(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	if _, err := f.ap.Actor(ctx, "solo"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before opting in, got %v", err)
+	}
+	f.setPublic(t, true)
+
+	actor, err := f.ap.Actor(ctx, "Solo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actor.ID != "https://bingo.example/ap/users/solo" || actor.Inbox != actor.ID+"/inbox" ||
+		actor.PublicKey.ID != actor.ID+"#main-key" || actor.PublicKey.Owner != actor.ID || actor.URL != "https://bingo.example/u/solo" {
+		t.Fatalf("unexpected actor %+v", actor)
+	}
+	key, err := parsePublicKey(actor.PublicKey.PublicKeyPem)
+	if err != nil {
+		t.Fatalf("unexpected error parsing the actor key: %v", err)
+	}
+
+	// The key is stored once for the instance, so a new process signs with it
+	// too.
+	restarted := NewService(f.db, f.profiles, "https://bingo.example")
+	again, err := restarted.Actor(ctx, "solo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if againKey, _ := parsePublicKey(again.PublicKey.PublicKeyPem); !key.Equal(againKey) {
+		t.Fatal("expected the instance key to be reused")
+	}
+
+	for _, resource := range []string{"acct:solo@bingo.example", "acct:@SOLO@bingo.example", "https://bingo.example/ap/users/solo"} {
+		finger, err := f.ap.WebFinger(ctx, resource)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", resource, err)
+		}
+		if finger.Subject != "acct:solo@bingo.example" || finger.Links[0].Href != actor.ID {
+			t.Fatalf("%s: unexpected result %+v", resource, finger)
+		}
+	}
+	for _, resource := range []string{"acct:solo@elsewhere.example", "acct:solo", "mailto:solo@example.com", "acct:nobody@bingo.example"} {
+		if _, err := f.ap.WebFinger(ctx, resource); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: expected ErrNotFound, got %v", resource, err)
+		}
+	}
+}
+
+func TestService_UsernamesOutsideHandleRulesAreNotFederated(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	f.setPublic(t, true)
+	for i, username := range []string{"two words", "émile", ".dot", "under_score"} {
+		user, err := services.NewUserService(f.db).Create(ctx, models.CreateUserParams{Email: fmt.Sprintf("u%d@example.com", i), Username: username})
+		if err != nil {
+			t.Fatalf("unexpected error creating %q: %v", username, err)
+		}
+		public := models.ProfileVisibilityPublic
+		if _, err := f.profiles.UpdateSettings(ctx, user.ID, &public, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = f.ap.Actor(ctx, username)
+		if federatedUsername.MatchString(username) != (err == nil) {
+			t.Errorf("%q: unexpected result %v", username, err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+const (
+	activityStreamsContext = "https://www.w3.org/ns/activitystreams"
+	securityContext        = "https://w3id.org/security/v1"
+	// publicAddress addresses an activity to everyone.
+	publicAddress = "https://www.w3.org/ns/activitystreams#Public"
+)
+
+// Actor is the Person document for a public profile.
+type Actor struct {
+	Context           []string  `json:"@context"`
+	ID                string    `json:"id"`
+	Type              string    `json:"type"`
+	PreferredUsername string    `json:"preferredUsername"`
+	Name              string    `json:"name"`
+	Summary           string    `json:"summary"`
+	URL               string    `json:"url"`
+	Inbox             string    `json:"inbox"`
+	Outbox            string    `json:"outbox"`
+	Followers         string    `json:"followers"`
+	Discoverable      bool      `json:"discoverable"`
+	Indexable         bool      `json:"indexable"`
+	PublicKey         PublicKey `json:"publicKey"`
+}
+
+// PublicKey is the key other servers check this actor's signatures with.
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// Activity is an outgoing activity: a Create wrapping a Note, or the Accept
+// of a Follow.
+type Activity struct {
+	Context   string   `json:"@context,omitempty"`
+	ID        string   `json:"id"`
+	Type      string   `json:"type"`
+	Actor     string   `json:"actor"`
+	Published string   `json:"published,omitempty"`
+	To        []string `json:"to,omitempty"`
+	Cc        []string `json:"cc,omitempty"`
+	Object    any      `json:"object"`
+}
+
+// Note is the post a completion or bingo is published as.
+type Note struct {
+	Context      string   `json:"@context,omitempty"`
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	AttributedTo string   `json:"attributedTo"`
+	Content      string   `json:"content"`
+	URL          string   `json:"url"`
+	Published    string   `json:"published"`
+	To           []string `json:"to"`
+	Cc           []string `json:"cc"`
+}
+
+// Collection is an OrderedCollection, or one OrderedCollectionPage of it.
+type Collection struct {
+	Context      string     `json:"@context"`
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	TotalItems   *int       `json:"totalItems,omitempty"`
+	First        string     `json:"first,omitempty"`
+	PartOf       string     `json:"partOf,omitempty"`
+	Next         string     `json:"next,omitempty"`
+	OrderedItems []Activity `json:"orderedItems,omitempty"`
+}
+
+// WebFinger is the JRD answer to a WebFinger lookup.
+type WebFinger struct {
+	Subject string          `json:"subject"`
+	Aliases []string        `json:"aliases"`
+	Links   []WebFingerLink `json:"links"`
+}
+
+type WebFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type"`
+	Href string `json:"href"`
+}
+
+// incomingActivity is the part of a delivered activity the inbox reads.
+type incomingActivity struct {
+	ID     string          `json:"id"`
+	Type   string          `json:"type"`
+	Actor  string          `json:"actor"`
+	Object json.RawMessage `json:"object"`
+}
+
+// objectRef reads an activity's object, which is either an ID or an
+// embedded object; an ID has no type.
+func objectRef(raw json.RawMessage) (id, objectType string) {
+	var asString string
+	if json.Unmarshal(raw, &asString) == nil {
+		return asString, ""
+	}
+	var embedded struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(raw, &embedded) == nil {
+		return embedded.ID, embedded.Type
+	}
+	return "", ""
+}
+
+// remoteActor is the part of another server's actor document the inbox
+// uses: where to deliver to and the key its requests are signed with.
+type remoteActor struct {
+	ID        string    `json:"id"`
+	Inbox     string    `json:"inbox"`
+	PublicKey PublicKey `json:"publicKey"`
+}
+
+// activityRecord is a row of the outbox.
+type activityRecord struct {
+	ID             uuid.UUID
+	Kind           string
+	CompletedItems int
+	TotalItems     int
+	BingoCount     int
+	CreatedAt      time.Time
+	CardYear       int
+	CardTitle      *string
+}
+
+// noteContent is the post text. Like the public profile, it names the card
+// and its progress but never a goal.
+func noteContent(record activityRecord) string {
+	card := models.BingoCard{Year: record.CardYear, Title: record.CardTitle}
+	name := html.EscapeString(card.DisplayName())
+	progress := fmt.Sprintf("%d/%d done", record.CompletedItems, record.TotalItems)
+	if record.Kind == models.ActivityKindBingo {
+		bingos := "1 bingo"
+		if record.BingoCount != 1 {
+			bingos = fmt.Sprintf("%d bingos", record.BingoCount)
+		}
+		return fmt.Sprintf("<p>Got a bingo on %s! %s, %s.</p>", name, bingos, progress)
+	}
+	return fmt.Sprintf("<p>Completed a goal on %s: %s.</p>", name, progress)
+}
@@ -0,0 +1,208 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
+)
+
+const (
+	outboxPageSize = 20
+	maxInboxBody   = 256 << 10
+
+	// outboxCursorScope keeps outbox cursors from working on API lists.
+	outboxCursorScope = "activitypub_outbox"
+)
+
+// Handler serves the ActivityPub routes: WebFinger, actors, outboxes,
+// follower counts and inboxes. Documents are public and carry no session,
+// so nothing here depends on who is asking.
+type Handler struct {
+	service *Service
+	cursors *pagination.Codec
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service, cursors: pagination.NewCodec("")}
+}
+
+// SetCursorCodec signs outbox page cursors with the server's shared codec.
+func (h *Handler) SetCursorCodec(codec *pagination.Codec) {
+	h.cursors = codec
+}
+
+func (h *Handler) WebFinger(w http.ResponseWriter, r *http.Request) {
+	resource := r.URL.Query().Get("resource")
+	if resource == "" {
+		writeJSON(w, http.StatusBadRequest, "application/json", map[string]string{"error": "resource is required"})
+		return
+	}
+	finger, err := h.service.WebFinger(r.Context(), resource)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, "application/jrd+json", finger)
+}
+
+// Actor serves the Person document, or sends browsers to the profile page.
+func (h *Handler) Actor(w http.ResponseWriter, r *http.Request) {
+	actor, err := h.service.Actor(r.Context(), r.PathValue("username"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	if wantsHTML(r) {
+		http.Redirect(w, r, actor.URL, http.StatusFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, ContentType, actor)
+}
+
+// Outbox serves the outbox collection, or with ?page=true one page of it.
+func (h *Handler) Outbox(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	var after *pagination.Key
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		cursor, err := h.cursors.Decode(outboxCursorScope, raw)
+		if err != nil || cursor.Key == nil {
+			writeJSON(w, http.StatusBadRequest, "application/json", map[string]string{"error": "Invalid cursor"})
+			return
+		}
+		after = cursor.Key
+	}
+
+	result, err := h.service.Outbox(r.Context(), username, after, outboxPageSize)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	outbox := h.service.actorID(result.Username) + "/outbox"
+	if r.URL.Query().Get("page") == "" {
+		writeJSON(w, http.StatusOK, ContentType, Collection{
+			Context:    activityStreamsContext,
+			ID:         outbox,
+			Type:       "OrderedCollection",
+			TotalItems: &result.Total,
+			First:      outbox + "?page=true",
+		})
+		return
+	}
+
+	page := Collection{
+		Context:      activityStreamsContext,
+		ID:           outbox + "?page=true",
+		Type:         "OrderedCollectionPage",
+		PartOf:       outbox,
+		OrderedItems: result.Activities,
+	}
+	if after != nil {
+		page.ID += "&cursor=" + url.QueryEscape(r.URL.Query().Get("cursor"))
+	}
+	if result.Next != nil {
+		page.Next = outbox + "?page=true&cursor=" + url.QueryEscape(h.cursors.EncodeKey(outboxCursorScope, *result.Next))
+	}
+	writeJSON(w, http.StatusOK, ContentType, page)
+}
+
+// Followers serves the follower count. Who follows a profile isn't listed.
+func (h *Handler) Followers(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	count, err := h.service.FollowerCount(r.Context(), username)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ContentType, Collection{
+		Context:    activityStreamsContext,
+		ID:         h.service.actorID(username) + "/followers",
+		Type:       "OrderedCollection",
+		TotalItems: &count,
+	})
+}
+
+func (h *Handler) Activity(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, ErrNotFound)
+		return
+	}
+	activity, err := h.service.Activity(r.Context(), r.PathValue("username"), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ContentType, activity)
+}
+
+func (h *Handler) Note(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, ErrNotFound)
+		return
+	}
+	note, err := h.service.Note(r.Context(), r.PathValue("username"), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	if wantsHTML(r) {
+		http.Redirect(w, r, note.URL, http.StatusFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, ContentType, note)
+}
+
+// Inbox takes signed deliveries from other servers.
+func (h *Handler) Inbox(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInboxBody))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, "application/json", map[string]string{"error": "Activity too large"})
+		return
+	}
+	if err := h.service.HandleInbox(r.Context(), r.PathValue("username"), r, body); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	status, message := http.StatusInternalServerError, "Internal server error"
+	switch {
+	case errors.Is(err, ErrNotFound):
+		status, message = http.StatusNotFound, "Not found"
+	case errors.Is(err, errInvalidSignature), errors.Is(err, errRemoteActor):
+		status, message = http.StatusUnauthorized, "Invalid signature"
+	case errors.Is(err, errInvalidActivity):
+		status, message = http.StatusBadRequest, "Invalid activity"
+	case errors.Is(err, ErrTooManyFollowers):
+		status, message = http.StatusForbidden, "This profile has too many followers"
+	default:
+		logging.Error("ActivityPub request failed", map[string]interface{}{"error": err.Error()})
+	}
+	writeJSON(w, status, "application/json", map[string]string{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, contentType string, body any) {
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// wantsHTML reports a browser, which asks for HTML and not ActivityStreams.
+func wantsHTML(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "text/html") &&
+		!strings.Contains(accept, "application/activity+json") &&
+		!strings.Contains(accept, "application/ld+json")
+}
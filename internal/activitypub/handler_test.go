@@ -0,0 +1,279 @@
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type delivery struct {
+	request *http.Request
+	body    []byte
+}
+
+// remoteServer is another fediverse server with one actor, alice, whose
+// inbox records what it is sent.
+type remoteServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu         sync.Mutex
+	deliveries []delivery
+	fetches    []*http.Request
+}
+
+func newRemoteServer(t *testing.T) *remoteServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	remote := &remoteServer{key: key}
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	remote.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remote.mu.Lock()
+		defer remote.mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/users/alice":
+			remote.fetches = append(remote.fetches, r)
+			w.Header().Set("Content-Type", ContentType)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":    remote.actor(),
+				"type":  "Person",
+				"inbox": remote.URL + "/users/alice/inbox",
+				"publicKey": PublicKey{
+					ID:           remote.actor() + "#main-key",
+					Owner:        remote.actor(),
+					PublicKeyPem: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+				},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/users/alice/inbox":
+			body, _ := io.ReadAll(r.Body)
+			remote.deliveries = append(remote.deliveries, delivery{request: r, body: body})
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(remote.Close)
+	return remote
+}
+
+func (r *remoteServer) actor() string {
+	return r.URL + "/users/alice"
+}
+
+// post delivers activity to solo's inbox, signed by alice.
+func (r *remoteServer) post(t *testing.T, h *Handler, activity map[string]any) *httptest.ResponseRecorder {
+	t.Helper()
+	body, _ := json.Marshal(activity)
+	req := httptest.NewRequest(http.MethodPost, "https://bingo.example/ap/users/solo/inbox", bytes.NewReader(body))
+	req.SetPathValue("username", "solo")
+	if err := signRequest(req, body, r.actor()+"#main-key", r.key, time.Now()); err != nil {
+		t.Fatalf("unexpected error signing: %v", err)
+	}
+	rr := httptest.NewRecorder()
+	h.Inbox(rr, req)
+	return rr
+}
+
+func (r *remoteServer) received() []delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]delivery(nil), r.deliveries...)
+}
+
+func TestHandler_FollowAcceptDeliverUndo(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	f.setPublic(t, true)
+	remote := newRemoteServer(t)
+	f.ap.client = remote.Client()
+	h := NewHandler(f.ap)
+	soloActor := "https://bingo.example/ap/users/solo"
+
+	follow := map[string]any{"id": remote.URL + "/follows/1", "type": "Follow", "actor": remote.actor(), "object": soloActor}
+	if rr := remote.post(t, h, follow); rr.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if count, err := f.ap.FollowerCount(ctx, "solo"); err != nil || count != 1 {
+		t.Fatalf("expected one follower, got %d %v", count, err)
+	}
+	if fetch := remote.fetches[0]; fetch.Header.Get("Signature") == "" {
+		t.Fatal("expected the actor fetch to be signed")
+	}
+
+	// The follow is accepted with a request signed by solo's key.
+	actor, err := f.ap.Actor(ctx, "solo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	soloKey, _ := parsePublicKey(actor.PublicKey.PublicKeyPem)
+	verify := func(d delivery) map[string]any {
+		t.Helper()
+		keyID, err := verifyRequest(d.request, d.body, time.Now(), func(string) (*rsa.PublicKey, error) { return soloKey, nil })
+		if err != nil || keyID != soloActor+"#main-key" {
+			t.Fatalf("expected a delivery signed by solo, got %q %v", keyID, err)
+		}
+		var activity map[string]any
+		if err := json.Unmarshal(d.body, &activity); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return activity
+	}
+	accept := verify(remote.received()[0])
+	if accept["type"] != "Accept" || accept["actor"] != soloActor || accept["object"].(map[string]any)["id"] != follow["id"] {
+		t.Fatalf("unexpected accept %v", accept)
+	}
+
+	// Following again doesn't add a second follower.
+	if rr := remote.post(t, h, follow); rr.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rr.Code)
+	}
+	if count, _ := f.ap.FollowerCount(ctx, "solo"); count != 1 {
+		t.Fatalf("expected one follower, got %d", count)
+	}
+
+	// New completions are delivered to the follower.
+	f.complete(t, 0)
+	deliveries := remote.received()
+	create := verify(deliveries[len(deliveries)-1])
+	if create["type"] != "Create" || create["object"].(map[string]any)["content"] != "<p>Completed a goal on 2026 Bingo Card: 1/9 done.</p>" {
+		t.Fatalf("unexpected create %v", create)
+	}
+
+	undo := map[string]any{"id": remote.URL + "/undo/1", "type": "Undo", "actor": remote.actor(), "object": follow}
+	if rr := remote.post(t, h, undo); rr.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rr.Code)
+	}
+	if count, _ := f.ap.FollowerCount(ctx, "solo"); count != 0 {
+		t.Fatalf("expected no followers after undo, got %d", count)
+	}
+}
+
+func TestHandler_InboxRejects(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	f.setPublic(t, true)
+	remote := newRemoteServer(t)
+	f.ap.client = remote.Client()
+	h := NewHandler(f.ap)
+
+	// Signed by alice but claiming to be from someone else.
+	spoofed := map[string]any{"type": "Follow", "actor": "https://elsewhere.example/users/bob", "object": "https://bingo.example/ap/users/solo"}
+	if rr := remote.post(t, h, spoofed); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a spoofed actor, got %d", rr.Code)
+	}
+	wrongTarget := map[string]any{"type": "Follow", "actor": remote.actor(), "object": "https://bingo.example/ap/users/other"}
+	if rr := remote.post(t, h, wrongTarget); rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a follow of another actor, got %d", rr.Code)
+	}
+
+	unsigned := httptest.NewRequest(http.MethodPost, "https://bingo.example/ap/users/solo/inbox",
+		strings.NewReader(`{"type":"Follow","actor":"`+remote.actor()+`","object":"https://bingo.example/ap/users/solo"}`))
+	unsigned.SetPathValue("username", "solo")
+	rr := httptest.NewRecorder()
+	h.Inbox(rr, unsigned)
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a signature, got %d", rr.Code)
+	}
+
+	if count, _ := f.ap.FollowerCount(ctx, "solo"); count != 0 {
+		t.Fatalf("expected no followers, got %d", count)
+	}
+	if len(remote.received()) != 0 {
+		t.Fatal("expected nothing to be delivered")
+	}
+
+	// Other activities are acknowledged and ignored.
+	like := map[string]any{"type": "Like", "actor": remote.actor(), "object": "https://bingo.example/ap/users/solo/notes/" + uuid.NewString()}
+	if rr := remote.post(t, h, like); rr.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 for an ignored activity, got %d", rr.Code)
+	}
+}
+
+func TestHandler_OutboxPages(t *testing.T) {
+	f := newFixture(t)
+	f.setPublic(t, true)
+	for i := 0; i < outboxPageSize+1; i++ {
+		if _, err := f.db.Exec(context.Background(),
+			`INSERT INTO activitypub_activities (user_id, card_id, kind, completed_items, total_items)
+			 VALUES ($1, $2, 'item_completed', 1, 9)`,
+			f.user.ID, f.card.ID,
+		); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	h := NewHandler(f.ap)
+	get := func(target string) (*httptest.ResponseRecorder, Collection) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.SetPathValue("username", "solo")
+		rr := httptest.NewRecorder()
+		h.Outbox(rr, req)
+		var collection Collection
+		_ = json.Unmarshal(rr.Body.Bytes(), &collection)
+		return rr, collection
+	}
+
+	rr, outbox := get("/ap/users/solo/outbox")
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), ContentType) {
+		t.Fatalf("expected an activity+json collection, got %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	if outbox.Type != "OrderedCollection" || *outbox.TotalItems != outboxPageSize+1 || outbox.First == "" {
+		t.Fatalf("unexpected outbox %+v", outbox)
+	}
+
+	_, first := get("/ap/users/solo/outbox?page=true")
+	if len(first.OrderedItems) != outboxPageSize || first.Next == "" {
+		t.Fatalf("expected a full first page with a next link, got %d %q", len(first.OrderedItems), first.Next)
+	}
+	next, _ := url.Parse(first.Next)
+	_, second := get(next.RequestURI())
+	if len(second.OrderedItems) != 1 || second.Next != "" || second.OrderedItems[0].ID == first.OrderedItems[0].ID {
+		t.Fatalf("expected the one remaining activity, got %+v", second)
+	}
+
+	if rr, _ := get("/ap/users/solo/outbox?page=true&cursor=forged"); rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a forged cursor, got %d", rr.Code)
+	}
+}
+
+func TestHandler_ActorRedirectsBrowsers(t *testing.T) {
+	f := newFixture(t)
+	h := NewHandler(f.ap)
+	get := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ap/users/solo", nil)
+		req.SetPathValue("username", "solo")
+		req.Header.Set("Accept", accept)
+		rr := httptest.NewRecorder()
+		h.Actor(rr, req)
+		return rr
+	}
+
+	if rr := get(ContentType); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before opting in, got %d", rr.Code)
+	}
+	f.setPublic(t, true)
+	if rr := get(ContentType); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	rr := get("text/html,application/xhtml+xml")
+	if rr.Code != http.StatusFound || rr.Header().Get("Location") != "https://bingo.example/u/solo" {
+		t.Fatalf("expected a redirect to the profile, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+}
@@ -0,0 +1,96 @@
+package activitypub
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func signedTestRequest(t *testing.T, key *rsa.PrivateKey, body []byte, now time.Time) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "https://bingo.example/ap/users/solo/inbox", bytes.NewReader(body))
+	if err := signRequest(req, body, "https://remote.example/users/alice#main-key", key, now); err != nil {
+		t.Fatalf("unexpected error signing: %v", err)
+	}
+	return req
+}
+
+func TestVerifyRequest(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	body := []byte(`{"type":"Follow"}`)
+	lookup := func(keyID string) (*rsa.PublicKey, error) {
+		if keyID != "https://remote.example/users/alice#main-key" {
+			t.Fatalf("unexpected keyId %q", keyID)
+		}
+		return &key.PublicKey, nil
+	}
+
+	req := signedTestRequest(t, key, body, now)
+	keyID, err := verifyRequest(req, body, now, lookup)
+	if err != nil || keyID != "https://remote.example/users/alice#main-key" {
+		t.Fatalf("expected a valid signature, got %q %v", keyID, err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(req *http.Request) ([]byte, time.Time)
+		lookup func(string) (*rsa.PublicKey, error)
+	}{
+		{"edited body", func(*http.Request) ([]byte, time.Time) { return []byte(`{"type":"Undo"}`), now }, lookup},
+		{"other inbox", func(req *http.Request) ([]byte, time.Time) {
+			req.URL.Path = "/ap/users/other/inbox"
+			return body, now
+		}, lookup},
+		{"replayed later", func(*http.Request) ([]byte, time.Time) { return body, now.Add(2 * maxClockSkew) }, lookup},
+		{"no signature", func(req *http.Request) ([]byte, time.Time) {
+			req.Header.Del("Signature")
+			return body, now
+		}, lookup},
+		{"digest not signed", func(req *http.Request) ([]byte, time.Time) {
+			req.Header.Set("Signature", `keyId="https://remote.example/users/alice#main-key",headers="(request-target) host date",signature="AAAA"`)
+			return body, now
+		}, lookup},
+		{"wrong key", func(*http.Request) ([]byte, time.Time) { return body, now }, func(string) (*rsa.PublicKey, error) {
+			return &other.PublicKey, nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := signedTestRequest(t, key, body, now)
+			body, at := tt.mutate(req)
+			if _, err := verifyRequest(req, body, at, tt.lookup); !errors.Is(err, errInvalidSignature) {
+				t.Fatalf("expected errInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	params, err := parseSignature(`keyId="https://remote.example/actor?a=1,b=2#key", algorithm="hs2019",headers="(request-target) Host date",signature="AQID"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.keyID != "https://remote.example/actor?a=1,b=2#key" || params.algorithm != "hs2019" ||
+		len(params.headers) != 3 || params.headers[1] != "host" || !bytes.Equal(params.signature, []byte{1, 2, 3}) {
+		t.Fatalf("unexpected params %+v", params)
+	}
+
+	for _, header := range []string{"", `keyId=unquoted`, `keyId="x",signature="AQID"`, `keyId="x",headers="date",signature="not base64!"`, `keyId="x`} {
+		if _, err := parseSignature(header); !errors.Is(err, errInvalidSignature) {
+			t.Errorf("%q: expected errInvalidSignature, got %v", header, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Package authz holds the rules for who may see or change a card and its
+// goals. The checks are pure: callers load the relationship between the viewer
+// and the card's owner and pass it in, so the friend card endpoints, reactions
+// and the owner-only card writes all answer the same question the same way.
+package authz
+
+import "github.com/google/uuid"
+
+// Relation is how a viewer stands toward the owner of a resource. Friends
+// means an accepted friendship exists; Blocked means either side has blocked
+// the other. Blocked wins over Friends, so a friendship row that outlives a
+// block grants nothing.
+type Relation struct {
+	ViewerID uuid.UUID
+	OwnerID  uuid.UUID
+	Friends  bool
+	Blocked  bool
+}
+
+// For returns the relation between a viewer and an owner with no friendship
+// or block loaded, which is all the owner-only checks need.
+func For(viewerID, ownerID uuid.UUID) Relation {
+	return Relation{ViewerID: viewerID, OwnerID: ownerID}
+}
+
+// IsOwner reports whether the viewer owns the resource. An anonymous viewer
+// never does.
+func (r Relation) IsOwner() bool {
+	return r.ViewerID != uuid.Nil && r.ViewerID == r.OwnerID
+}
+
+// IsFriend reports whether the viewer can see the owner's friend-visible
+// content at all: an accepted friendship and no block in either direction.
+func (r Relation) IsFriend() bool {
+	return r.Friends && !r.Blocked && !r.IsOwner()
+}
+
+// CardVisibility is the part of a card that decides who besides the owner
+// may see it.
+type CardVisibility struct {
+	Finalized        bool
+	VisibleToFriends bool
+}
+
+// CanViewCard reports whether the viewer may load the card. Owners see every
+// card; friends see finalized cards the owner hasn't hidden from them.
+func CanViewCard(rel Relation, vis CardVisibility) bool {
+	if rel.IsOwner() {
+		return true
+	}
+	return rel.IsFriend() && vis.Finalized && vis.VisibleToFriends
+}
+
+// CanEditCard reports whether the viewer may change the card or its goals.
+// Only the owner can; friendship never grants writes.
+func CanEditCard(rel Relation) bool {
+	return rel.IsOwner()
+}
+
+// CanViewItem reports whether the viewer may see a goal's content. Friends
+// who can see the card see its private goals only as placeholders, so they
+// can't see, or react to, what a private goal says.
+func CanViewItem(rel Relation, vis CardVisibility, private bool) bool {
+	if rel.IsOwner() {
+		return true
+	}
+	return CanViewCard(rel, vis) && !private
+}
@@ -0,0 +1,64 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRelation_Matrix(t *testing.T) {
+	ownerID := uuid.New()
+	viewers := map[string]Relation{
+		"owner":                 {ViewerID: ownerID, OwnerID: ownerID},
+		"friend":                {ViewerID: uuid.New(), OwnerID: ownerID, Friends: true},
+		"blocked former friend": {ViewerID: uuid.New(), OwnerID: ownerID, Friends: true, Blocked: true},
+		"blocked stranger":      {ViewerID: uuid.New(), OwnerID: ownerID, Blocked: true},
+		"stranger":              {ViewerID: uuid.New(), OwnerID: ownerID},
+		"anonymous":             {OwnerID: ownerID},
+	}
+
+	visible := CardVisibility{Finalized: true, VisibleToFriends: true}
+	hidden := CardVisibility{Finalized: true}
+	draft := CardVisibility{VisibleToFriends: true}
+
+	endpoints := map[string]func(Relation) bool{
+		"view visible card":   func(r Relation) bool { return CanViewCard(r, visible) },
+		"view hidden card":    func(r Relation) bool { return CanViewCard(r, hidden) },
+		"view draft card":     func(r Relation) bool { return CanViewCard(r, draft) },
+		"edit card":           CanEditCard,
+		"view goal":           func(r Relation) bool { return CanViewItem(r, visible, false) },
+		"view private goal":   func(r Relation) bool { return CanViewItem(r, visible, true) },
+		"view goal on hidden": func(r Relation) bool { return CanViewItem(r, hidden, false) },
+	}
+
+	// Everything not listed here must be denied.
+	allowed := map[string]map[string]bool{
+		"owner": {
+			"view visible card": true, "view hidden card": true, "view draft card": true, "edit card": true,
+			"view goal": true, "view private goal": true, "view goal on hidden": true,
+		},
+		"friend": {"view visible card": true, "view goal": true},
+	}
+
+	for viewer, rel := range viewers {
+		for endpoint, check := range endpoints {
+			want := allowed[viewer][endpoint]
+			if got := check(rel); got != want {
+				t.Errorf("%s / %s: got %v, want %v", viewer, endpoint, got, want)
+			}
+		}
+	}
+}
+
+func TestFor_OwnerOnly(t *testing.T) {
+	ownerID := uuid.New()
+	if !CanEditCard(For(ownerID, ownerID)) {
+		t.Fatal("expected the owner to edit")
+	}
+	if CanEditCard(For(uuid.New(), ownerID)) {
+		t.Fatal("expected another user to be denied")
+	}
+	if CanEditCard(For(uuid.Nil, uuid.Nil)) {
+		t.Fatal("expected a nil viewer to be denied even against a nil owner")
+	}
+}
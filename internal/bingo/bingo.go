@@ -0,0 +1,398 @@
+// Package bingo holds the grid rules shared by reminder emails, card stats and
+// the recommendations API: which lines exist, how many are complete, and which
+// open goals get a card closest to its next bingo.
+package bingo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type LineKind string
+
+const (
+	LineRow          LineKind = "row"
+	LineColumn       LineKind = "column"
+	LineDiagonal     LineKind = "diagonal"
+	LineAntiDiagonal LineKind = "anti-diagonal"
+)
+
+// Line is one row, column or diagonal of the grid. Index is zero-based and
+// only meaningful for rows and columns.
+type Line struct {
+	Kind      LineKind
+	Index     int
+	Positions []int
+}
+
+// Label names the line the way it is shown to users, e.g. "row 3".
+func (l Line) Label() string {
+	switch l.Kind {
+	case LineRow, LineColumn:
+		return fmt.Sprintf("%s %d", l.Kind, l.Index+1)
+	default:
+		return string(l.Kind)
+	}
+}
+
+// Recommendation is an open goal plus the lines it moves closest to a bingo.
+// Lines is empty for fallback picks that are not on any near-complete line.
+type Recommendation struct {
+	Item      models.BingoItem
+	Lines     []Line
+	Remaining int
+}
+
+// Reason explains the pick, e.g. "completes row 3 and the diagonal".
+func (r Recommendation) Reason() string {
+	if len(r.Lines) == 0 {
+		return ""
+	}
+	labels := make([]string, 0, len(r.Lines))
+	for _, line := range r.Lines {
+		label := line.Label()
+		if line.Kind == LineDiagonal || line.Kind == LineAntiDiagonal {
+			label = "the " + label
+		}
+		labels = append(labels, label)
+	}
+	joined := joinLabels(labels)
+	if r.Remaining <= 1 {
+		return "completes " + joined
+	}
+	return fmt.Sprintf("one of %d left in %s", r.Remaining, joined)
+}
+
+func joinLabels(labels []string) string {
+	if len(labels) <= 2 {
+		return strings.Join(labels, " and ")
+	}
+	return strings.Join(labels[:len(labels)-1], ", ") + " and " + labels[len(labels)-1]
+}
+
+func normalizeGridSize(gridSize int) int {
+	if !models.IsValidGridSize(gridSize) {
+		return models.MaxGridSize
+	}
+	return gridSize
+}
+
+// Lines returns every row, then every column, then both diagonals.
+func Lines(gridSize int) []Line {
+	lines := make([]Line, 0, gridSize*2+2)
+	for row := 0; row < gridSize; row++ {
+		positions := make([]int, 0, gridSize)
+		for col := 0; col < gridSize; col++ {
+			positions = append(positions, row*gridSize+col)
+		}
+		lines = append(lines, Line{Kind: LineRow, Index: row, Positions: positions})
+	}
+	for col := 0; col < gridSize; col++ {
+		positions := make([]int, 0, gridSize)
+		for row := 0; row < gridSize; row++ {
+			positions = append(positions, row*gridSize+col)
+		}
+		lines = append(lines, Line{Kind: LineColumn, Index: col, Positions: positions})
+	}
+
+	positions := make([]int, 0, gridSize)
+	for i := 0; i < gridSize; i++ {
+		positions = append(positions, i*gridSize+i)
+	}
+	lines = append(lines, Line{Kind: LineDiagonal, Positions: positions})
+
+	positions = make([]int, 0, gridSize)
+	for i := 0; i < gridSize; i++ {
+		positions = append(positions, i*gridSize+(gridSize-1-i))
+	}
+	lines = append(lines, Line{Kind: LineAntiDiagonal, Positions: positions})
+
+	return lines
+}
+
+// CountBingos counts complete lines. The free space counts as completed.
+func CountBingos(items []models.BingoItem, gridSize int, freePos *int) int {
+	gridSize = normalizeGridSize(gridSize)
+	total := gridSize * gridSize
+	grid := make([]bool, total)
+
+	if freePos != nil && *freePos >= 0 && *freePos < total {
+		grid[*freePos] = true
+	}
+
+	for _, item := range items {
+		if item.IsCompleted && item.Position >= 0 && item.Position < total {
+			grid[item.Position] = true
+		}
+	}
+
+	bingos := 0
+	for _, line := range Lines(gridSize) {
+		complete := true
+		for _, pos := range line.Positions {
+			if !grid[pos] {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			bingos++
+		}
+	}
+	return bingos
+}
+
+// Recommend returns up to limit open goals on the lines with the fewest
+// missing squares, ranked by how many of those lines each goal sits on. When
+// no such goal exists it falls back to the oldest open goals.
+func Recommend(items []models.BingoItem, gridSize int, freePos *int, limit int) []Recommendation {
+	return recommend(items, gridSize, freePos, limit, nil)
+}
+
+func recommend(items []models.BingoItem, gridSize int, freePos *int, limit int, rank func(models.BingoItem) int) []Recommendation {
+	if rank == nil {
+		rank = func(models.BingoItem) int { return 0 }
+	}
+	if limit <= 0 {
+		return []Recommendation{}
+	}
+	gridSize = normalizeGridSize(gridSize)
+	itemByPos := make(map[int]models.BingoItem, len(items))
+	for _, item := range items {
+		itemByPos[item.Position] = item
+	}
+
+	free := -1
+	if freePos != nil {
+		free = *freePos
+	}
+
+	lines := Lines(gridSize)
+	minMissing := gridSize + 1
+	lineMissing := make([][]int, 0, len(lines))
+
+	for _, line := range lines {
+		missing := make([]int, 0, len(line.Positions))
+		for _, pos := range line.Positions {
+			if pos == free {
+				continue
+			}
+			item, ok := itemByPos[pos]
+			if !ok || !item.IsCompleted {
+				missing = append(missing, pos)
+			}
+		}
+		if len(missing) == 0 {
+			lineMissing = append(lineMissing, nil)
+			continue
+		}
+		if len(missing) < minMissing {
+			minMissing = len(missing)
+		}
+		lineMissing = append(lineMissing, missing)
+	}
+
+	linesByPos := map[int][]Line{}
+	if minMissing <= gridSize {
+		for i, missing := range lineMissing {
+			if len(missing) == 0 || len(missing) != minMissing {
+				continue
+			}
+			for _, pos := range missing {
+				linesByPos[pos] = append(linesByPos[pos], lines[i])
+			}
+		}
+	}
+
+	var scored []Recommendation
+	for pos, posLines := range linesByPos {
+		item, ok := itemByPos[pos]
+		if !ok {
+			continue
+		}
+		if item.IsCompleted || pos == free {
+			continue
+		}
+		scored = append(scored, Recommendation{Item: item, Lines: posLines, Remaining: minMissing})
+	}
+
+	if len(scored) == 0 {
+		return fallback(items, free, limit, rank)
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if len(scored[i].Lines) != len(scored[j].Lines) {
+			return len(scored[i].Lines) > len(scored[j].Lines)
+		}
+		if ri, rj := rank(scored[i].Item), rank(scored[j].Item); ri != rj {
+			return ri < rj
+		}
+		return scored[i].Item.Position < scored[j].Item.Position
+	})
+
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
+	return scored
+}
+
+// PickRecommendations is Recommend without the line reasoning.
+func PickRecommendations(items []models.BingoItem, gridSize int, freePos *int, limit int) []models.BingoItem {
+	return pick(items, gridSize, freePos, limit, nil, nil)
+}
+
+// PickRotatedRecommendations is PickRecommendations that pushes the goals in
+// recent behind every other open goal, so ignored suggestions rotate out.
+// Recent goals keep their place when they are the only goals on the lines
+// closest to a bingo.
+func PickRotatedRecommendations(items []models.BingoItem, gridSize int, freePos *int, limit int, recent map[uuid.UUID]bool) []models.BingoItem {
+	return pick(items, gridSize, freePos, limit, nil, recent)
+}
+
+// PickPacedRecommendations is PickRecommendations with ties broken by
+// difficulty for the time of year: easier goals first from January to April,
+// harder goals first from September to December. Untagged goals count as
+// medium.
+func PickPacedRecommendations(items []models.BingoItem, gridSize int, freePos *int, limit int, month time.Month) []models.BingoItem {
+	return pick(items, gridSize, freePos, limit, pacedRank(month), nil)
+}
+
+// PickPacedRotatedRecommendations combines PickPacedRecommendations and
+// PickRotatedRecommendations.
+func PickPacedRotatedRecommendations(items []models.BingoItem, gridSize int, freePos *int, limit int, month time.Month, recent map[uuid.UUID]bool) []models.BingoItem {
+	return pick(items, gridSize, freePos, limit, pacedRank(month), recent)
+}
+
+func pacedRank(month time.Month) func(models.BingoItem) int {
+	return func(item models.BingoItem) int {
+		return difficultyRank(item.Difficulty, month)
+	}
+}
+
+// pick orders goals as fresh line goals, other fresh goals, then recent
+// goals. When every line goal is recent they lead instead, since skipping
+// them would hide the only route to the next bingo.
+func pick(items []models.BingoItem, gridSize int, freePos *int, limit int, rank func(models.BingoItem) int, recent map[uuid.UUID]bool) []models.BingoItem {
+	if len(recent) == 0 || limit <= 0 {
+		return recommendationItems(recommend(items, gridSize, freePos, limit, rank))
+	}
+	if rank == nil {
+		rank = func(models.BingoItem) int { return 0 }
+	}
+
+	var freshLine, staleLine []models.BingoItem
+	for _, rec := range recommend(items, gridSize, freePos, len(items), rank) {
+		if len(rec.Lines) == 0 {
+			continue
+		}
+		if recent[rec.Item.ID] {
+			staleLine = append(staleLine, rec.Item)
+		} else {
+			freshLine = append(freshLine, rec.Item)
+		}
+	}
+
+	free := -1
+	if freePos != nil {
+		free = *freePos
+	}
+	var freshOther, staleOther []models.BingoItem
+	for _, rec := range fallback(items, free, len(items), rank) {
+		if recent[rec.Item.ID] {
+			staleOther = append(staleOther, rec.Item)
+		} else {
+			freshOther = append(freshOther, rec.Item)
+		}
+	}
+
+	var ordered []models.BingoItem
+	if len(freshLine) > 0 {
+		ordered = append(ordered, freshLine...)
+		ordered = append(ordered, freshOther...)
+		ordered = append(ordered, staleLine...)
+	} else {
+		ordered = append(ordered, staleLine...)
+		ordered = append(ordered, freshOther...)
+	}
+	ordered = append(ordered, staleOther...)
+
+	result := make([]models.BingoItem, 0, limit)
+	seen := make(map[int]bool, len(ordered))
+	for _, item := range ordered {
+		if len(result) == limit {
+			break
+		}
+		if seen[item.Position] {
+			continue
+		}
+		seen[item.Position] = true
+		result = append(result, item)
+	}
+	return result
+}
+
+func recommendationItems(recs []Recommendation) []models.BingoItem {
+	result := make([]models.BingoItem, 0, len(recs))
+	for _, rec := range recs {
+		result = append(result, rec.Item)
+	}
+	return result
+}
+
+// difficultyRank orders goals for the month; lower ranks come first.
+func difficultyRank(difficulty *string, month time.Month) int {
+	level := 1
+	if difficulty != nil {
+		switch *difficulty {
+		case models.DifficultyEasy:
+			level = 0
+		case models.DifficultyHard:
+			level = 2
+		}
+	}
+	switch {
+	case month <= time.April:
+		return level
+	case month >= time.September:
+		return 2 - level
+	default:
+		return 0
+	}
+}
+
+func fallback(items []models.BingoItem, freePos int, limit int, rank func(models.BingoItem) int) []Recommendation {
+	candidates := make([]models.BingoItem, 0, len(items))
+	for _, item := range items {
+		if item.IsCompleted || item.Position == freePos {
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if ri, rj := rank(candidates[i]), rank(candidates[j]); ri != rj {
+			return ri < rj
+		}
+		if !candidates[i].CreatedAt.IsZero() && !candidates[j].CreatedAt.IsZero() {
+			if !candidates[i].CreatedAt.Equal(candidates[j].CreatedAt) {
+				return candidates[i].CreatedAt.Before(candidates[j].CreatedAt)
+			}
+		}
+		return candidates[i].Position < candidates[j].Position
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	result := make([]Recommendation, 0, len(candidates))
+	for _, item := range candidates {
+		result = append(result, Recommendation{Item: item})
+	}
+	return result
+}
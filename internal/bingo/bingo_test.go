@@ -0,0 +1,240 @@
+package bingo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestCountBingos_FreeSpaceAndBounds(t *testing.T) {
+	freePos := 4
+	items := []models.BingoItem{
+		{Position: 0, IsCompleted: true},
+		{Position: 8, IsCompleted: true},
+		{Position: 3, IsCompleted: true},
+		{Position: 5, IsCompleted: true},
+		{Position: 99, IsCompleted: true}, // out of range, ignored
+	}
+	// Diagonal 0-4-8 and middle row 3-4-5.
+	if got := CountBingos(items, 3, &freePos); got != 2 {
+		t.Fatalf("expected 2 bingos, got %d", got)
+	}
+	if got := CountBingos(items, 3, nil); got != 0 {
+		t.Fatalf("expected 0 bingos without free space, got %d", got)
+	}
+}
+
+func TestLines_LabelsAndCount(t *testing.T) {
+	lines := Lines(4)
+	if len(lines) != 10 {
+		t.Fatalf("expected 10 lines, got %d", len(lines))
+	}
+	if got := lines[2].Label(); got != "row 3" {
+		t.Fatalf("expected row 3, got %q", got)
+	}
+	if got := lines[5].Label(); got != "column 2" {
+		t.Fatalf("expected column 2, got %q", got)
+	}
+	if got := lines[9].Positions; got[0] != 3 || got[3] != 12 {
+		t.Fatalf("unexpected anti-diagonal positions %v", got)
+	}
+}
+
+func TestPickRecommendations_ExcludesCompletedAndFree(t *testing.T) {
+	freePos := 4
+	items := []models.BingoItem{
+		{Position: 0, Content: "A", IsCompleted: true},
+		{Position: 1, Content: "B", IsCompleted: true},
+		{Position: 2, Content: "C"},
+		{Position: 3, Content: "D", IsCompleted: true},
+		{Position: 5, Content: "E", IsCompleted: true},
+		{Position: 6, Content: "F"},
+		{Position: 7, Content: "G", IsCompleted: true},
+		{Position: 8, Content: "H"},
+	}
+
+	recs := PickRecommendations(items, 3, &freePos, 3)
+	if len(recs) < 3 {
+		t.Fatalf("expected at least 3 recommendations, got %d", len(recs))
+	}
+	for _, rec := range recs {
+		if rec.IsCompleted {
+			t.Fatalf("expected incomplete recommendation, got %+v", rec)
+		}
+		if rec.Position == freePos {
+			t.Fatalf("expected free position excluded, got %d", rec.Position)
+		}
+	}
+
+	expected := []int{2, 6, 8}
+	for i, pos := range expected {
+		if recs[i].Position != pos {
+			t.Fatalf("expected recommendation %d to be position %d, got %d", i, pos, recs[i].Position)
+		}
+	}
+}
+
+func TestRecommend_ExplainsLineCompletion(t *testing.T) {
+	freePos := 4
+	items := []models.BingoItem{
+		{Position: 0, Content: "A", IsCompleted: true},
+		{Position: 1, Content: "B", IsCompleted: true},
+		{Position: 2, Content: "C"},
+		{Position: 3, Content: "D"},
+		{Position: 5, Content: "E"},
+		{Position: 6, Content: "F"},
+		{Position: 7, Content: "G"},
+		{Position: 8, Content: "H", IsCompleted: true},
+	}
+
+	recs := Recommend(items, 3, &freePos, 2)
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 recommendations, got %d", len(recs))
+	}
+	if recs[0].Item.Position != 2 || recs[0].Reason() != "completes row 1" {
+		t.Fatalf("unexpected first recommendation: position %d, reason %q", recs[0].Item.Position, recs[0].Reason())
+	}
+	if recs[1].Item.Position != 7 || recs[1].Reason() != "completes column 2" {
+		t.Fatalf("unexpected second recommendation: position %d, reason %q", recs[1].Item.Position, recs[1].Reason())
+	}
+}
+
+func TestRecommend_MultipleLinesAndPartialProgress(t *testing.T) {
+	items := []models.BingoItem{
+		{Position: 0, Content: "A", IsCompleted: true},
+		{Position: 1, Content: "B"},
+		{Position: 2, Content: "C"},
+		{Position: 3, Content: "D"},
+		{Position: 4, Content: "E"},
+		{Position: 5, Content: "F"},
+		{Position: 6, Content: "G"},
+		{Position: 7, Content: "H"},
+		{Position: 8, Content: "I"},
+	}
+
+	recs := Recommend(items, 3, nil, 1)
+	if len(recs) != 1 || recs[0].Item.Position != 1 {
+		t.Fatalf("expected lowest position on a two-left line, got %+v", recs)
+	}
+	if got := recs[0].Reason(); got != "one of 2 left in row 1" {
+		t.Fatalf("unexpected reason %q", got)
+	}
+
+	for _, pos := range []int{1, 3, 5, 7} {
+		items[pos].IsCompleted = true
+	}
+	recs = Recommend(items, 3, nil, 1)
+	if recs[0].Item.Position != 4 {
+		t.Fatalf("expected center square first, got %+v", recs)
+	}
+	if got := recs[0].Reason(); got != "completes row 2 and column 2" {
+		t.Fatalf("unexpected reason %q", got)
+	}
+}
+
+func TestRecommend_FallsBackToOldestOpenGoals(t *testing.T) {
+	older := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	// Every near-complete line is missing only empty squares.
+	items := []models.BingoItem{
+		{Position: 0, Content: "A", IsCompleted: true},
+		{Position: 1, Content: "B", IsCompleted: true},
+		{Position: 4, Content: "C", CreatedAt: newer},
+		{Position: 5, Content: "D", CreatedAt: older},
+	}
+
+	recs := Recommend(items, 3, nil, 3)
+	if len(recs) != 2 || recs[0].Item.Position != 5 || recs[1].Item.Position != 4 {
+		t.Fatalf("expected oldest open goals, got %+v", recs)
+	}
+	if recs[0].Reason() != "" || len(recs[0].Lines) != 0 {
+		t.Fatalf("expected no line reasoning for fallback, got %q", recs[0].Reason())
+	}
+	if got := Recommend(items, 3, nil, 0); len(got) != 0 {
+		t.Fatalf("expected no recommendations for zero limit, got %d", len(got))
+	}
+}
+
+func TestPickPacedRecommendations_OrdersTiesByDifficultyForMonth(t *testing.T) {
+	easy, hard := models.DifficultyEasy, models.DifficultyHard
+	// 3x3 with the middle row one square from bingo at either end, so
+	// positions 3 and 5 tie on lines.
+	items := []models.BingoItem{
+		{Position: 3, Difficulty: &hard},
+		{Position: 4, IsCompleted: true},
+		{Position: 5, Difficulty: &easy},
+	}
+
+	tests := []struct {
+		month time.Month
+		want  int
+	}{
+		{time.February, 5},
+		{time.June, 3},
+		{time.November, 3},
+	}
+	for _, tt := range tests {
+		got := PickPacedRecommendations(items, 3, nil, 1, tt.month)
+		if len(got) != 1 || got[0].Position != tt.want {
+			t.Errorf("%s: expected position %d first, got %+v", tt.month, tt.want, got)
+		}
+	}
+
+	if got := PickRecommendations(items, 3, nil, 1); got[0].Position != 3 {
+		t.Fatalf("expected unpaced ranking to keep position order, got %+v", got)
+	}
+}
+
+func TestPickPacedRecommendations_FallbackPrefersHardLateInYear(t *testing.T) {
+	older := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	hard := models.DifficultyHard
+	// Same layout as the fallback test: only empty squares finish a line.
+	items := []models.BingoItem{
+		{Position: 0, IsCompleted: true},
+		{Position: 1, IsCompleted: true},
+		{Position: 4, Difficulty: &hard, CreatedAt: older.Add(time.Hour)},
+		{Position: 5, CreatedAt: older},
+	}
+	got := PickPacedRecommendations(items, 3, nil, 2, time.October)
+	if len(got) != 2 || got[0].Position != 4 {
+		t.Fatalf("expected hard goal first, got %+v", got)
+	}
+}
+
+func TestPickRotatedRecommendations_DemotesRecentGoals(t *testing.T) {
+	// Middle row needs 3 or 5; position 6 is any other open goal.
+	items := []models.BingoItem{
+		{ID: uuid.New(), Position: 3},
+		{ID: uuid.New(), Position: 4, IsCompleted: true},
+		{ID: uuid.New(), Position: 5},
+		{ID: uuid.New(), Position: 6},
+	}
+	recent := map[uuid.UUID]bool{items[0].ID: true}
+
+	got := PickRotatedRecommendations(items, 3, nil, 3, recent)
+	if len(got) != 3 || got[0].Position != 5 || got[1].Position != 6 || got[2].Position != 3 {
+		t.Fatalf("expected fresh line goal, fresh goal, then recent goal, got %+v", got)
+	}
+	if got := PickRotatedRecommendations(items, 3, nil, 2, nil); got[0].Position != 3 || got[1].Position != 5 {
+		t.Fatalf("expected no rotation without history, got %+v", got)
+	}
+}
+
+func TestPickRotatedRecommendations_KeepsRecentWhenOnlyPathToBingo(t *testing.T) {
+	// Top row needs only position 2; everything else is far from a bingo.
+	items := []models.BingoItem{
+		{ID: uuid.New(), Position: 0, IsCompleted: true},
+		{ID: uuid.New(), Position: 1, IsCompleted: true},
+		{ID: uuid.New(), Position: 2},
+		{ID: uuid.New(), Position: 7},
+	}
+	recent := map[uuid.UUID]bool{items[2].ID: true}
+
+	got := PickPacedRotatedRecommendations(items, 3, nil, 2, time.June, recent)
+	if len(got) != 2 || got[0].Position != 2 || got[1].Position != 7 {
+		t.Fatalf("expected recent bingo goal to stay first, got %+v", got)
+	}
+}
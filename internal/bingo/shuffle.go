@@ -0,0 +1,49 @@
+package bingo
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// MaxShuffleSeed keeps shuffle seeds within the integers a browser can hold
+// exactly in a JSON number, so a seed shown to the user can be sent back.
+const MaxShuffleSeed = 1<<53 - 1
+
+// ShuffleLayout deals items onto the grid's squares other than the free space
+// with a seeded Fisher-Yates shuffle and returns each item's new position.
+// Items are dealt in ID order, so the layout depends only on which goals are
+// on the card and the seed, not on where they sit now.
+func ShuffleLayout(items []models.BingoItem, gridSize int, freePos *int, seed int64) map[uuid.UUID]int {
+	positions := make([]int, 0, gridSize*gridSize)
+	for p := 0; p < gridSize*gridSize; p++ {
+		if freePos != nil && p == *freePos {
+			continue
+		}
+		positions = append(positions, p)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	for i := len(positions) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		positions[i], positions[j] = positions[j], positions[i]
+	}
+
+	ordered := make([]models.BingoItem, len(items))
+	copy(ordered, items)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].ID.String() < ordered[j].ID.String()
+	})
+
+	layout := make(map[uuid.UUID]int, len(ordered))
+	for i, item := range ordered {
+		if i >= len(positions) {
+			break
+		}
+		layout[item.ID] = positions[i]
+	}
+	return layout
+}
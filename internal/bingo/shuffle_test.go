@@ -0,0 +1,78 @@
+package bingo
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func shuffleItems(n int) []models.BingoItem {
+	items := make([]models.BingoItem, n)
+	for i := range items {
+		items[i] = models.BingoItem{ID: uuid.New(), Position: i}
+	}
+	return items
+}
+
+func TestShuffleLayout_SameSeedSameLayout(t *testing.T) {
+	items := shuffleItems(24)
+	freePos := 12
+
+	first := ShuffleLayout(items, 5, &freePos, 42)
+	second := ShuffleLayout(items, 5, &freePos, 42)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("expected identical layouts for the same seed, got %v and %v", first, second)
+	}
+
+	// Where the goals sit now must not matter.
+	reordered := make([]models.BingoItem, len(items))
+	for i, item := range items {
+		item.Position = first[item.ID]
+		reordered[len(items)-1-i] = item
+	}
+	if again := ShuffleLayout(reordered, 5, &freePos, 42); !reflect.DeepEqual(first, again) {
+		t.Fatalf("expected layout to ignore current positions, got %v and %v", first, again)
+	}
+
+	if other := ShuffleLayout(items, 5, &freePos, 43); reflect.DeepEqual(first, other) {
+		t.Fatal("expected a different seed to give a different layout")
+	}
+}
+
+func TestShuffleLayout_KeepsFreeSpaceFixed(t *testing.T) {
+	items := shuffleItems(8)
+	freePos := 4
+
+	for seed := int64(0); seed < 200; seed++ {
+		layout := ShuffleLayout(items, 3, &freePos, seed)
+		if len(layout) != len(items) {
+			t.Fatalf("seed %d: expected %d positions, got %d", seed, len(items), len(layout))
+		}
+		seen := map[int]bool{}
+		for _, pos := range layout {
+			if pos == freePos {
+				t.Fatalf("seed %d: an item was placed on the free space", seed)
+			}
+			if pos < 0 || pos >= 9 || seen[pos] {
+				t.Fatalf("seed %d: invalid or duplicate position %d", seed, pos)
+			}
+			seen[pos] = true
+		}
+	}
+}
+
+func TestShuffleLayout_PartialCardWithoutFreeSpace(t *testing.T) {
+	items := shuffleItems(3)
+	layout := ShuffleLayout(items, 2, nil, 7)
+	if len(layout) != 3 {
+		t.Fatalf("expected 3 positions, got %d", len(layout))
+	}
+	for _, pos := range layout {
+		if pos < 0 || pos >= 4 {
+			t.Fatalf("expected positions on a 2x2 grid, got %d", pos)
+		}
+	}
+}
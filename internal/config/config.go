@@ -2,9 +2,13 @@ package config
 
 import (
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -14,6 +18,13 @@ type Config struct {
 	Email    EmailConfig
 	AI       AIConfig
 	OAuth    OAuthConfig
+	Admin    AdminConfig
+	Render   RenderConfig
+	Reminder ReminderConfig
+	Cards    CardsConfig
+	Branding BrandingConfig
+
+	ActivityPub ActivityPubConfig
 }
 
 type ServerConfig struct {
@@ -23,15 +34,47 @@ type ServerConfig struct {
 	Environment   string // "development", "production", "test"
 	Debug         bool
 	DebugMaxChars int
+	// Version is the deployed release (e.g. "1.8.1"), reported by /api/version.
+	Version string
+	// MinClientVersion is the oldest API client release the server still supports.
+	MinClientVersion string
+	// MaxConnections caps concurrently accepted connections (0 = unlimited).
+	MaxConnections int
+	// MaxDecodedRequestBytes enables gzip request bodies up to this decompressed
+	// size; 0 rejects Content-Encoding'd request bodies.
+	MaxDecodedRequestBytes int64
+	// DisconnectWatchdog is how long a handler may keep running after its
+	// client disconnects before it is logged as slow (0 = off).
+	DisconnectWatchdog time.Duration
+	// CursorSecret signs pagination cursors. Replicas must share it; when
+	// empty each process picks a random key and cursors break on restart.
+	CursorSecret string
 }
 
+// Database drivers accepted in DB_DRIVER.
+const (
+	DatabaseDriverPostgres = "postgres"
+	DatabaseDriverSQLite   = "sqlite"
+)
+
 type DatabaseConfig struct {
+	// Driver is "postgres" (default) or "sqlite". SQLite mode is for
+	// single-user installs: it swaps Redis for an in-process store and
+	// turns off suggestion analytics.
+	Driver   string
 	Host     string
 	Port     int
 	User     string
 	Password string
 	DBName   string
 	SSLMode  string
+	// SQLitePath is the database file used when Driver is "sqlite".
+	SQLitePath string
+}
+
+// IsSQLite reports whether the app runs in single-user SQLite mode.
+func (d DatabaseConfig) IsSQLite() bool {
+	return d.Driver == DatabaseDriverSQLite
 }
 
 type RedisConfig struct {
@@ -49,6 +92,9 @@ type AIConfig struct {
 	GeminiThinkingBudget  int
 	GeminiTemperature     float64
 	GeminiMaxOutputTokens int
+	// FreeGenerationsPerMonth is each verified user's monthly AI allowance.
+	// 0 turns the cap off.
+	FreeGenerationsPerMonth int
 }
 
 type EmailConfig struct {
@@ -60,6 +106,129 @@ type EmailConfig struct {
 	// SMTP settings (for Mailpit in local dev)
 	SMTPHost string
 	SMTPPort int
+	// SendRate caps provider requests per second across every email the
+	// server sends. 0 turns the limit off.
+	SendRate float64
+	// SendMaxWait is how long a send may wait for the rate limit before it
+	// fails and is deferred.
+	SendMaxWait time.Duration
+}
+
+type AdminConfig struct {
+	// UserIDs lists the user IDs allowed to call /api/admin endpoints.
+	UserIDs []string
+	// InternalToken lets operator tooling read selected admin endpoints
+	// (job status) via the X-Internal-Token header. Empty disables it.
+	InternalToken string
+	// SearchEnabled exposes /api/admin/search. Self-hosters can turn it off.
+	SearchEnabled bool
+	// SuggestionAnalyticsEnabled counts goals that match curated suggestions
+	// (daily totals, no user IDs) and exposes /api/admin/suggestions/analytics.
+	SuggestionAnalyticsEnabled bool
+	// SelfTestEmail receives the test message sent by `server selftest`.
+	// Empty skips the email check.
+	SelfTestEmail string
+	// ToolsEnabled allows operator tools that act on live data (the manual
+	// reminder run) when APP_ENV is production. They are always on elsewhere.
+	ToolsEnabled bool
+}
+
+type ReminderConfig struct {
+	// ImageTokenTTL is how long per-email image tokens stay valid. Tokens in
+	// the default reuse mode keep their 14-day rolling expiry.
+	ImageTokenTTL time.Duration
+	// ImageTokenMaxAccess caps views of a per-email image token before the
+	// placeholder image is served. 0 means unlimited.
+	ImageTokenMaxAccess int
+	// DifficultyPacing makes check-in recommendations prefer easier goals
+	// early in the year and harder ones later.
+	DifficultyPacing bool
+}
+
+type CardsConfig struct {
+	// Difficulty weights used for weighted completion in card stats.
+	// Non-positive values fall back to the defaults (1/2/3).
+	DifficultyWeightEasy   int
+	DifficultyWeightMedium int
+	DifficultyWeightHard   int
+	// ProofStorageDir holds uploaded proof photos.
+	ProofStorageDir string
+}
+
+// ActivityPubConfig controls the experimental fediverse actors for public
+// profiles. Actor IDs are built from APP_BASE_URL, which should be the
+// instance's permanent https address before this is turned on.
+type ActivityPubConfig struct {
+	Enabled bool
+}
+
+// DefaultBrandName is the instance name used when BRANDING_NAME is unset.
+const DefaultBrandName = "Year of Bingo"
+
+// BrandingConfig lets self-hosted deployments replace the Year of Bingo
+// name, logo and accent color in pages and emails. Empty fields keep the
+// defaults, so the zero value renders the stock branding.
+type BrandingConfig struct {
+	Name string
+	// LogoURL is an absolute image URL shown in the header and emails.
+	LogoURL string
+	// PrimaryColor is a hex color (#rgb or #rrggbb) for buttons and accents.
+	PrimaryColor string
+	// SupportEmail receives support form messages.
+	SupportEmail string
+}
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// DisplayName returns the configured instance name or the default.
+func (b BrandingConfig) DisplayName() string {
+	if name := strings.TrimSpace(b.Name); name != "" {
+		return name
+	}
+	return DefaultBrandName
+}
+
+// LogoOrigin returns the scheme and host of LogoURL for the CSP, or "".
+func (b BrandingConfig) LogoOrigin() string {
+	u, err := url.Parse(b.LogoURL)
+	if b.LogoURL == "" || err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// IsDefaultName reports whether the instance uses the stock name.
+func (b BrandingConfig) IsDefaultName() bool {
+	return b.DisplayName() == DefaultBrandName
+}
+
+// Validate checks the branding values. Logo URLs must be absolute, and
+// https when the server runs with secure cookies.
+func (b BrandingConfig) Validate(secure bool) error {
+	if b.LogoURL != "" {
+		u, err := url.Parse(b.LogoURL)
+		if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+			return fmt.Errorf("BRANDING_LOGO_URL must be an absolute http(s) URL")
+		}
+		if secure && u.Scheme != "https" {
+			return fmt.Errorf("BRANDING_LOGO_URL must use https when SERVER_SECURE is true")
+		}
+	}
+	if b.PrimaryColor != "" && !hexColorPattern.MatchString(b.PrimaryColor) {
+		return fmt.Errorf("BRANDING_PRIMARY_COLOR must be a hex color like #0f6f62")
+	}
+	if b.SupportEmail != "" {
+		if addr, err := mail.ParseAddress(b.SupportEmail); err != nil || addr.Address != b.SupportEmail {
+			return fmt.Errorf("BRANDING_SUPPORT_EMAIL must be a plain email address")
+		}
+	}
+	return nil
+}
+
+type RenderConfig struct {
+	// FontDir holds extra TrueType/OpenType fonts used for glyphs the bundled
+	// font lacks (Arabic, Hebrew, CJK, symbols) in rendered card images.
+	FontDir string
 }
 
 type OAuthConfig struct {
@@ -90,20 +259,28 @@ func (r RedisConfig) Addr() string {
 func Load() (*Config, error) {
 	cfg := &Config{
 		Server: ServerConfig{
-			Host:          getEnv("SERVER_HOST", "0.0.0.0"),
-			Port:          getEnvInt("SERVER_PORT", 8080),
-			Secure:        getEnvBool("SERVER_SECURE", false),
-			Environment:   getEnv("APP_ENV", "development"),
-			Debug:         getEnvBool("DEBUG", false),
-			DebugMaxChars: getEnvInt("DEBUG_LOG_MAX_CHARS", 8000),
+			Host:                   getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:                   getEnvInt("SERVER_PORT", 8080),
+			Secure:                 getEnvBool("SERVER_SECURE", false),
+			Environment:            getEnv("APP_ENV", "development"),
+			Debug:                  getEnvBool("DEBUG", false),
+			DebugMaxChars:          getEnvInt("DEBUG_LOG_MAX_CHARS", 8000),
+			Version:                getEnvNonEmpty("APP_VERSION", "dev"),
+			MinClientVersion:       getEnvNonEmpty("API_MIN_CLIENT_VERSION", "1.0.0"),
+			MaxConnections:         getEnvInt("SERVER_MAX_CONNECTIONS", 1000),
+			MaxDecodedRequestBytes: int64(getEnvInt("SERVER_MAX_DECOMPRESSED_BODY_BYTES", 0)),
+			DisconnectWatchdog:     time.Duration(getEnvInt("SERVER_DISCONNECT_WATCHDOG_SECONDS", 10)) * time.Second,
+			CursorSecret:           getEnv("PAGINATION_CURSOR_SECRET", ""),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvInt("DB_PORT", 5432),
-			User:     getEnv("DB_USER", "bingo"),
-			Password: getEnv("DB_PASSWORD", "bingo"),
-			DBName:   getEnv("DB_NAME", "nye_bingo"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Driver:     strings.ToLower(strings.TrimSpace(getEnvNonEmpty("DB_DRIVER", DatabaseDriverPostgres))),
+			Host:       getEnv("DB_HOST", "localhost"),
+			Port:       getEnvInt("DB_PORT", 5432),
+			User:       getEnv("DB_USER", "bingo"),
+			Password:   getEnv("DB_PASSWORD", "bingo"),
+			DBName:     getEnv("DB_NAME", "nye_bingo"),
+			SSLMode:    getEnv("DB_SSLMODE", "disable"),
+			SQLitePath: getEnvNonEmpty("SQLITE_PATH", "data/yearofbingo.db"),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -119,15 +296,18 @@ func Load() (*Config, error) {
 			ResendAPIKey: getEnv("RESEND_API_KEY", ""),
 			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
 			SMTPPort:     getEnvInt("SMTP_PORT", 1025),
+			SendRate:     getEnvFloat64("EMAIL_SEND_RATE", 10),
+			SendMaxWait:  time.Duration(getEnvInt("EMAIL_SEND_MAX_WAIT_MS", 2000)) * time.Millisecond,
 		},
 		AI: AIConfig{
-			GeminiAPIKey:          getEnv("GEMINI_API_KEY", ""),
-			GeminiModel:           getEnvNonEmpty("GEMINI_MODEL", "gemini-3-flash-preview"),
-			GeminiThinkingLevel:   getEnvNonEmpty("GEMINI_THINKING_LEVEL", "minimal"),
-			GeminiThinkingBudget:  getEnvInt("GEMINI_THINKING_BUDGET", 0),
-			GeminiTemperature:     getEnvFloat64("GEMINI_TEMPERATURE", 0.8),
-			GeminiMaxOutputTokens: getEnvInt("GEMINI_MAX_OUTPUT_TOKENS", 4096),
-			Stub:                  getEnvBool("AI_STUB", false),
+			GeminiAPIKey:            getEnv("GEMINI_API_KEY", ""),
+			GeminiModel:             getEnvNonEmpty("GEMINI_MODEL", "gemini-3-flash-preview"),
+			GeminiThinkingLevel:     getEnvNonEmpty("GEMINI_THINKING_LEVEL", "minimal"),
+			GeminiThinkingBudget:    getEnvInt("GEMINI_THINKING_BUDGET", 0),
+			GeminiTemperature:       getEnvFloat64("GEMINI_TEMPERATURE", 0.8),
+			GeminiMaxOutputTokens:   getEnvInt("GEMINI_MAX_OUTPUT_TOKENS", 4096),
+			FreeGenerationsPerMonth: getEnvInt("AI_FREE_GENERATIONS_PER_MONTH", 50),
+			Stub:                    getEnvBool("AI_STUB", false),
 		},
 		OAuth: OAuthConfig{
 			AllowedProviders: getEnvList("OAUTH_ALLOWED_PROVIDERS", nil),
@@ -140,6 +320,44 @@ func Load() (*Config, error) {
 				Scopes:       getEnvList("GOOGLE_OIDC_SCOPES", []string{"openid", "email", "profile"}),
 			},
 		},
+		Admin: AdminConfig{
+			UserIDs:                    getEnvList("ADMIN_USER_IDS", nil),
+			InternalToken:              getEnv("INTERNAL_API_TOKEN", ""),
+			SearchEnabled:              getEnvBool("ADMIN_SEARCH_ENABLED", true),
+			SuggestionAnalyticsEnabled: getEnvBool("SUGGESTION_ANALYTICS_ENABLED", true),
+			SelfTestEmail:              strings.TrimSpace(getEnv("SELFTEST_EMAIL", "")),
+			ToolsEnabled:               getEnvBool("ADMIN_TOOLS_ENABLED", false),
+		},
+		Render: RenderConfig{
+			FontDir: getEnv("RENDER_FONT_DIR", ""),
+		},
+		Reminder: ReminderConfig{
+			ImageTokenTTL:       time.Duration(getEnvInt("REMINDER_IMAGE_TOKEN_TTL_DAYS", 7)) * 24 * time.Hour,
+			ImageTokenMaxAccess: getEnvInt("REMINDER_IMAGE_TOKEN_MAX_ACCESS", 50),
+			DifficultyPacing:    getEnvBool("REMINDER_DIFFICULTY_PACING", true),
+		},
+		Cards: CardsConfig{
+			DifficultyWeightEasy:   getEnvInt("DIFFICULTY_WEIGHT_EASY", 1),
+			DifficultyWeightMedium: getEnvInt("DIFFICULTY_WEIGHT_MEDIUM", 2),
+			DifficultyWeightHard:   getEnvInt("DIFFICULTY_WEIGHT_HARD", 3),
+			ProofStorageDir:        getEnvNonEmpty("PROOF_STORAGE_DIR", "data/proofs"),
+		},
+		Branding: BrandingConfig{
+			Name:         strings.TrimSpace(getEnvNonEmpty("BRANDING_NAME", DefaultBrandName)),
+			LogoURL:      strings.TrimSpace(getEnv("BRANDING_LOGO_URL", "")),
+			PrimaryColor: strings.TrimSpace(getEnv("BRANDING_PRIMARY_COLOR", "")),
+			SupportEmail: strings.TrimSpace(getEnv("BRANDING_SUPPORT_EMAIL", "")),
+		},
+		ActivityPub: ActivityPubConfig{
+			Enabled: getEnvBool("ACTIVITYPUB_ENABLED", false),
+		},
+	}
+
+	if cfg.Database.Driver != DatabaseDriverPostgres && cfg.Database.Driver != DatabaseDriverSQLite {
+		return nil, fmt.Errorf("DB_DRIVER must be %q or %q", DatabaseDriverPostgres, DatabaseDriverSQLite)
+	}
+	if err := cfg.Branding.Validate(cfg.Server.Secure); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
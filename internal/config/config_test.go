@@ -2,17 +2,25 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoad_Defaults(t *testing.T) {
 	// Clear any existing env vars that might interfere
 	envVars := []string{
-		"SERVER_HOST", "SERVER_PORT", "SERVER_SECURE", "DEBUG", "DEBUG_LOG_MAX_CHARS",
-		"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE",
+		"SERVER_HOST", "SERVER_PORT", "SERVER_SECURE", "DEBUG", "DEBUG_LOG_MAX_CHARS", "APP_VERSION", "API_MIN_CLIENT_VERSION", "SERVER_MAX_CONNECTIONS", "SERVER_MAX_DECOMPRESSED_BODY_BYTES",
+		"DB_DRIVER", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE", "SQLITE_PATH",
 		"REDIS_HOST", "REDIS_PORT", "REDIS_PASSWORD", "REDIS_DB",
 		"AI_STUB", "GEMINI_API_KEY", "GEMINI_MODEL", "GEMINI_THINKING_LEVEL", "GEMINI_THINKING_BUDGET", "GEMINI_TEMPERATURE", "GEMINI_MAX_OUTPUT_TOKENS",
 		"OAUTH_ALLOWED_PROVIDERS", "GOOGLE_OAUTH_ENABLED", "GOOGLE_OAUTH_CLIENT_ID", "GOOGLE_OAUTH_CLIENT_SECRET", "GOOGLE_OAUTH_REDIRECT_URL", "GOOGLE_OIDC_ISSUER_URL", "GOOGLE_OIDC_SCOPES",
+		"ADMIN_USER_IDS",
+		"INTERNAL_API_TOKEN", "ADMIN_SEARCH_ENABLED", "SUGGESTION_ANALYTICS_ENABLED", "SELFTEST_EMAIL",
+		"RENDER_FONT_DIR",
+		"REMINDER_IMAGE_TOKEN_TTL_DAYS", "REMINDER_IMAGE_TOKEN_MAX_ACCESS", "REMINDER_DIFFICULTY_PACING",
+		"DIFFICULTY_WEIGHT_EASY", "DIFFICULTY_WEIGHT_MEDIUM", "DIFFICULTY_WEIGHT_HARD",
+		"BRANDING_NAME", "BRANDING_LOGO_URL", "BRANDING_PRIMARY_COLOR", "BRANDING_SUPPORT_EMAIL",
 	}
 	for _, v := range envVars {
 		os.Unsetenv(v)
@@ -39,6 +47,21 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.Server.DebugMaxChars != 8000 {
 		t.Errorf("expected Server.DebugMaxChars to be 8000, got %d", cfg.Server.DebugMaxChars)
 	}
+	if cfg.Server.Version != "dev" {
+		t.Errorf("expected Server.Version to be dev, got %s", cfg.Server.Version)
+	}
+	if cfg.Server.MinClientVersion != "1.0.0" {
+		t.Errorf("expected Server.MinClientVersion to be 1.0.0, got %s", cfg.Server.MinClientVersion)
+	}
+	if cfg.Server.MaxConnections != 1000 {
+		t.Errorf("expected Server.MaxConnections to be 1000, got %d", cfg.Server.MaxConnections)
+	}
+	if cfg.Server.MaxDecodedRequestBytes != 0 {
+		t.Errorf("expected Server.MaxDecodedRequestBytes to be 0, got %d", cfg.Server.MaxDecodedRequestBytes)
+	}
+	if cfg.Server.DisconnectWatchdog != 10*time.Second {
+		t.Errorf("expected Server.DisconnectWatchdog to be 10s, got %v", cfg.Server.DisconnectWatchdog)
+	}
 
 	// Database defaults
 	if cfg.Database.Host != "localhost" {
@@ -59,6 +82,9 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.Database.SSLMode != "disable" {
 		t.Errorf("expected Database.SSLMode to be disable, got %s", cfg.Database.SSLMode)
 	}
+	if cfg.Database.SQLitePath != "data/yearofbingo.db" {
+		t.Errorf("expected Database.SQLitePath to be data/yearofbingo.db, got %s", cfg.Database.SQLitePath)
+	}
 
 	// Redis defaults
 	if cfg.Redis.Host != "localhost" {
@@ -109,6 +135,48 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.OAuth.Google.Scopes[0] != "openid" {
 		t.Errorf("expected OAuth.Google.Scopes[0] to be openid, got %q", cfg.OAuth.Google.Scopes[0])
 	}
+	if len(cfg.Admin.UserIDs) != 0 {
+		t.Errorf("expected no Admin.UserIDs by default, got %v", cfg.Admin.UserIDs)
+	}
+	if cfg.Admin.InternalToken != "" {
+		t.Errorf("expected empty Admin.InternalToken by default, got %q", cfg.Admin.InternalToken)
+	}
+	if !cfg.Admin.SearchEnabled {
+		t.Error("expected Admin.SearchEnabled by default")
+	}
+	if !cfg.Admin.SuggestionAnalyticsEnabled {
+		t.Error("expected Admin.SuggestionAnalyticsEnabled by default")
+	}
+	if cfg.Admin.ToolsEnabled {
+		t.Error("expected Admin.ToolsEnabled off by default")
+	}
+	if cfg.AI.FreeGenerationsPerMonth != 50 {
+		t.Errorf("expected AI.FreeGenerationsPerMonth 50 by default, got %d", cfg.AI.FreeGenerationsPerMonth)
+	}
+	if cfg.Email.SendRate != 10 {
+		t.Errorf("expected Email.SendRate 10 by default, got %v", cfg.Email.SendRate)
+	}
+	if cfg.Email.SendMaxWait != 2*time.Second {
+		t.Errorf("expected Email.SendMaxWait 2s by default, got %v", cfg.Email.SendMaxWait)
+	}
+	if cfg.Render.FontDir != "" {
+		t.Errorf("expected empty Render.FontDir by default, got %q", cfg.Render.FontDir)
+	}
+	if cfg.Reminder.ImageTokenTTL != 7*24*time.Hour {
+		t.Errorf("expected Reminder.ImageTokenTTL 7 days by default, got %v", cfg.Reminder.ImageTokenTTL)
+	}
+	if cfg.Reminder.ImageTokenMaxAccess != 50 {
+		t.Errorf("expected Reminder.ImageTokenMaxAccess 50 by default, got %d", cfg.Reminder.ImageTokenMaxAccess)
+	}
+	if !cfg.Reminder.DifficultyPacing {
+		t.Error("expected Reminder.DifficultyPacing true by default")
+	}
+	if cfg.Cards.DifficultyWeightEasy != 1 || cfg.Cards.DifficultyWeightMedium != 2 || cfg.Cards.DifficultyWeightHard != 3 {
+		t.Errorf("expected default difficulty weights 1/2/3, got %+v", cfg.Cards)
+	}
+	if cfg.Branding != (BrandingConfig{Name: DefaultBrandName}) {
+		t.Errorf("expected default branding, got %+v", cfg.Branding)
+	}
 }
 
 func TestLoad_CustomValues(t *testing.T) {
@@ -135,6 +203,16 @@ func TestLoad_CustomValues(t *testing.T) {
 	os.Setenv("GOOGLE_OAUTH_REDIRECT_URL", "https://example.com/callback")
 	os.Setenv("GOOGLE_OIDC_ISSUER_URL", "https://issuer.example.com")
 	os.Setenv("GOOGLE_OIDC_SCOPES", "openid,email")
+	os.Setenv("ADMIN_USER_IDS", "a, b")
+	os.Setenv("INTERNAL_API_TOKEN", "ops-token")
+	os.Setenv("ADMIN_SEARCH_ENABLED", "false")
+	os.Setenv("SUGGESTION_ANALYTICS_ENABLED", "false")
+	os.Setenv("SELFTEST_EMAIL", " ops@example.com ")
+	os.Setenv("RENDER_FONT_DIR", "/usr/share/fonts/noto")
+	os.Setenv("REMINDER_IMAGE_TOKEN_TTL_DAYS", "3")
+	os.Setenv("REMINDER_IMAGE_TOKEN_MAX_ACCESS", "0")
+	os.Setenv("REMINDER_DIFFICULTY_PACING", "false")
+	os.Setenv("DIFFICULTY_WEIGHT_HARD", "5")
 
 	defer func() {
 		// Clean up
@@ -160,6 +238,16 @@ func TestLoad_CustomValues(t *testing.T) {
 		os.Unsetenv("GOOGLE_OAUTH_REDIRECT_URL")
 		os.Unsetenv("GOOGLE_OIDC_ISSUER_URL")
 		os.Unsetenv("GOOGLE_OIDC_SCOPES")
+		os.Unsetenv("ADMIN_USER_IDS")
+		os.Unsetenv("INTERNAL_API_TOKEN")
+		os.Unsetenv("ADMIN_SEARCH_ENABLED")
+		os.Unsetenv("SUGGESTION_ANALYTICS_ENABLED")
+		os.Unsetenv("SELFTEST_EMAIL")
+		os.Unsetenv("RENDER_FONT_DIR")
+		os.Unsetenv("REMINDER_IMAGE_TOKEN_TTL_DAYS")
+		os.Unsetenv("REMINDER_IMAGE_TOKEN_MAX_ACCESS")
+		os.Unsetenv("REMINDER_DIFFICULTY_PACING")
+		os.Unsetenv("DIFFICULTY_WEIGHT_HARD")
 	}()
 
 	cfg, err := Load()
@@ -242,6 +330,30 @@ func TestLoad_CustomValues(t *testing.T) {
 	if len(cfg.OAuth.Google.Scopes) != 2 || cfg.OAuth.Google.Scopes[1] != "email" {
 		t.Errorf("unexpected OAuth.Google.Scopes: %#v", cfg.OAuth.Google.Scopes)
 	}
+	if len(cfg.Admin.UserIDs) != 2 || cfg.Admin.UserIDs[1] != "b" {
+		t.Errorf("unexpected Admin.UserIDs: %#v", cfg.Admin.UserIDs)
+	}
+	if cfg.Admin.InternalToken != "ops-token" {
+		t.Errorf("expected Admin.InternalToken 'ops-token', got %q", cfg.Admin.InternalToken)
+	}
+	if cfg.Admin.SearchEnabled {
+		t.Error("expected Admin.SearchEnabled false when ADMIN_SEARCH_ENABLED=false")
+	}
+	if cfg.Admin.SuggestionAnalyticsEnabled {
+		t.Error("expected Admin.SuggestionAnalyticsEnabled false when SUGGESTION_ANALYTICS_ENABLED=false")
+	}
+	if cfg.Admin.SelfTestEmail != "ops@example.com" {
+		t.Errorf("expected trimmed Admin.SelfTestEmail, got %q", cfg.Admin.SelfTestEmail)
+	}
+	if cfg.Render.FontDir != "/usr/share/fonts/noto" {
+		t.Errorf("expected Render.FontDir '/usr/share/fonts/noto', got %q", cfg.Render.FontDir)
+	}
+	if cfg.Reminder.ImageTokenTTL != 3*24*time.Hour || cfg.Reminder.ImageTokenMaxAccess != 0 || cfg.Reminder.DifficultyPacing {
+		t.Errorf("unexpected Reminder config: %+v", cfg.Reminder)
+	}
+	if cfg.Cards.DifficultyWeightHard != 5 || cfg.Cards.DifficultyWeightEasy != 1 {
+		t.Errorf("unexpected Cards config: %+v", cfg.Cards)
+	}
 }
 
 func TestLoad_InvalidIntFallsBackToDefault(t *testing.T) {
@@ -450,3 +562,86 @@ func TestGetEnvBool(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_Branding(t *testing.T) {
+	t.Setenv("BRANDING_NAME", " Team Goals ")
+	t.Setenv("BRANDING_LOGO_URL", "https://cdn.example.com/logo.png")
+	t.Setenv("BRANDING_PRIMARY_COLOR", "#123abc")
+	t.Setenv("BRANDING_SUPPORT_EMAIL", "help@example.com")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := BrandingConfig{Name: "Team Goals", LogoURL: "https://cdn.example.com/logo.png", PrimaryColor: "#123abc", SupportEmail: "help@example.com"}
+	if cfg.Branding != want {
+		t.Errorf("expected %+v, got %+v", want, cfg.Branding)
+	}
+	if cfg.Branding.IsDefaultName() {
+		t.Error("expected custom name not to be the default")
+	}
+}
+
+func TestLoad_DatabaseDriver(t *testing.T) {
+	t.Setenv("DB_DRIVER", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Database.Driver != DatabaseDriverPostgres || cfg.Database.IsSQLite() {
+		t.Fatalf("expected postgres by default, got %q", cfg.Database.Driver)
+	}
+
+	t.Setenv("DB_DRIVER", " SQLite ")
+	t.Setenv("SQLITE_PATH", "/var/lib/bingo/app.db")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Database.IsSQLite() || cfg.Database.SQLitePath != "/var/lib/bingo/app.db" {
+		t.Fatalf("expected sqlite at the configured path, got %+v", cfg.Database)
+	}
+
+	t.Setenv("DB_DRIVER", "mysql")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "DB_DRIVER") {
+		t.Fatalf("expected DB_DRIVER error, got %v", err)
+	}
+}
+
+func TestLoad_BrandingRequiresHTTPSLogoWhenSecure(t *testing.T) {
+	t.Setenv("SERVER_SECURE", "true")
+	t.Setenv("BRANDING_LOGO_URL", "http://cdn.example.com/logo.png")
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "https") {
+		t.Fatalf("expected https error, got %v", err)
+	}
+
+	t.Setenv("SERVER_SECURE", "false")
+	if _, err := Load(); err != nil {
+		t.Fatalf("expected http logo to be allowed without SERVER_SECURE, got %v", err)
+	}
+}
+
+func TestBrandingConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       BrandingConfig
+		wantErr bool
+	}{
+		{name: "zero value", b: BrandingConfig{}},
+		{name: "relative logo", b: BrandingConfig{LogoURL: "/logo.png"}, wantErr: true},
+		{name: "javascript logo", b: BrandingConfig{LogoURL: "javascript:alert(1)"}, wantErr: true},
+		{name: "short color", b: BrandingConfig{PrimaryColor: "#abc"}},
+		{name: "named color", b: BrandingConfig{PrimaryColor: "red"}, wantErr: true},
+		{name: "css injection", b: BrandingConfig{PrimaryColor: "#fff;}body{"}, wantErr: true},
+		{name: "display name email", b: BrandingConfig{SupportEmail: "Help <help@example.com>"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := tt.b.Validate(false); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error=%v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+	if got := (BrandingConfig{}).DisplayName(); got != DefaultBrandName {
+		t.Errorf("expected default display name, got %q", got)
+	}
+}
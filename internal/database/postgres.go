@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -34,6 +36,10 @@ func NewPostgresDB(dsn string) (*PostgresDB, error) {
 	config.MaxConnLifetime = time.Hour
 	config.MaxConnIdleTime = 30 * time.Minute
 	config.HealthCheckPeriod = time.Minute
+	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		useUTCTimestamps(conn.TypeMap())
+		return nil
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -51,6 +57,16 @@ func NewPostgresDB(dsn string) (*PostgresDB, error) {
 	return &PostgresDB{Pool: pool}, nil
 }
 
+// useUTCTimestamps scans timestamptz columns as UTC instead of the server's
+// local zone, so API responses don't depend on where the app runs.
+func useUTCTimestamps(m *pgtype.Map) {
+	m.RegisterType(&pgtype.Type{
+		Name:  "timestamptz",
+		OID:   pgtype.TimestamptzOID,
+		Codec: &pgtype.TimestamptzCodec{ScanLocation: time.UTC},
+	})
+}
+
 func (db *PostgresDB) Close() {
 	if db.Pool != nil {
 		closePGPool(db.Pool)
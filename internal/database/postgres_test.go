@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -143,6 +144,30 @@ func TestNewPostgresDB_SuccessConfigValues(t *testing.T) {
 	if cfg.HealthCheckPeriod != time.Minute {
 		t.Fatalf("expected HealthCheckPeriod 1m, got %v", cfg.HealthCheckPeriod)
 	}
+	if cfg.AfterConnect == nil {
+		t.Fatal("expected AfterConnect to set up UTC timestamps")
+	}
+}
+
+func TestUseUTCTimestamps(t *testing.T) {
+	m := pgtype.NewMap()
+	useUTCTimestamps(m)
+
+	want := time.Date(2026, time.March, 9, 9, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	buf, err := m.Encode(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, want, nil)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var got time.Time
+	if err := m.Scan(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, buf, &got); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if !got.Equal(want) || got.Location() != time.UTC {
+		t.Fatalf("expected %v in UTC, got %v", want, got)
+	}
+	if s := got.Format(time.RFC3339); s != "2026-03-09T14:30:00Z" {
+		t.Fatalf("expected an RFC3339 UTC timestamp, got %s", s)
+	}
 }
 
 func TestPostgresDB_Close_CallsPoolClose(t *testing.T) {
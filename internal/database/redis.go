@@ -2,14 +2,22 @@ package database
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
 )
 
 type RedisDB struct {
 	Client *redis.Client
+
+	// memory is set when Client talks to an in-process server; stopClock
+	// ends the goroutine that advances its TTLs.
+	memory    *miniredis.Miniredis
+	stopClock chan struct{}
 }
 
 var (
@@ -41,7 +49,57 @@ func NewRedisDB(addr, password string, db int) (*RedisDB, error) {
 	return &RedisDB{Client: client}, nil
 }
 
+// memoryClockInterval is how often the in-process Redis's TTLs count down.
+// miniredis only expires keys when it is told time has passed, so without
+// this rate limit windows, caches and sessions would never expire.
+var memoryClockInterval = time.Second
+
+// NewMemoryRedisDB starts an in-process Redis for single-user installs that
+// don't run a Redis server. Sessions and caches live only as long as the
+// process. It listens on a random loopback port, so it requires a random
+// password that only this process knows.
+func NewMemoryRedisDB() (*RedisDB, error) {
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generating in-memory redis password: %w", err)
+	}
+	password := hex.EncodeToString(secret)
+
+	server := miniredis.NewMiniRedis()
+	server.RequireAuth(password)
+	if err := server.Start(); err != nil {
+		return nil, fmt.Errorf("starting in-memory redis: %w", err)
+	}
+	client := newRedisClient(&redis.Options{Addr: server.Addr(), Password: password})
+	stop := make(chan struct{})
+	go advanceMemoryClock(server, memoryClockInterval, stop)
+	return &RedisDB{Client: client, memory: server, stopClock: stop}, nil
+}
+
+// advanceMemoryClock moves the in-process Redis's clock and TTLs forward by
+// the wall time elapsed, every interval, until stop is closed.
+func advanceMemoryClock(server *miniredis.Miniredis, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			server.SetTime(now)
+			server.FastForward(now.Sub(last))
+			last = now
+		}
+	}
+}
+
 func (r *RedisDB) Close() error {
+	if r.memory != nil {
+		close(r.stopClock)
+		defer r.memory.Close()
+	}
 	if r.Client != nil {
 		return r.Client.Close()
 	}
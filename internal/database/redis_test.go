@@ -120,3 +120,71 @@ func TestRedisDB_Close_Client(t *testing.T) {
 		t.Fatalf("unexpected close error: %v", err)
 	}
 }
+
+func TestNewMemoryRedisDB(t *testing.T) {
+	db, err := NewMemoryRedisDB()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	if err := db.Health(ctx); err != nil {
+		t.Fatalf("expected healthy in-memory redis, got %v", err)
+	}
+	if err := db.Client.Set(ctx, "k", "v", time.Minute).Err(); err != nil {
+		t.Fatalf("unexpected set error: %v", err)
+	}
+	if got, err := db.Client.Get(ctx, "k").Result(); err != nil || got != "v" {
+		t.Fatalf("expected stored value, got %q %v", got, err)
+	}
+}
+
+func TestNewMemoryRedisDB_ExpiresKeys(t *testing.T) {
+	orig := memoryClockInterval
+	memoryClockInterval = 10 * time.Millisecond
+	t.Cleanup(func() { memoryClockInterval = orig })
+
+	db, err := NewMemoryRedisDB()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	// The rate limiter's INCR-then-EXPIRE window has to reset.
+	ctx := context.Background()
+	if err := db.Client.Incr(ctx, "ratelimit:test").Err(); err != nil {
+		t.Fatalf("unexpected incr error: %v", err)
+	}
+	if err := db.Client.PExpire(ctx, "ratelimit:test", 100*time.Millisecond).Err(); err != nil {
+		t.Fatalf("unexpected expire error: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		n, err := db.Client.Exists(ctx, "ratelimit:test").Result()
+		if err != nil {
+			t.Fatalf("unexpected exists error: %v", err)
+		}
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the key to expire")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestNewMemoryRedisDB_RequiresPassword(t *testing.T) {
+	db, err := NewMemoryRedisDB()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	other := redis.NewClient(&redis.Options{Addr: db.Client.Options().Addr})
+	t.Cleanup(func() { _ = other.Close() })
+	if err := other.Ping(context.Background()).Err(); err == nil || !strings.Contains(err.Error(), "NOAUTH") {
+		t.Fatalf("expected NOAUTH without the password, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteDB is the database for single-user installs, a local file in place
+// of PostgreSQL.
+type SQLiteDB struct {
+	DB *sql.DB
+}
+
+// SQLiteDSN opens the file with foreign keys enforced, WAL so readers don't
+// block the writer, and write transactions that take the lock up front
+// instead of failing when a reader upgrades.
+func SQLiteDSN(path string) string {
+	return fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate", path)
+}
+
+// SQLiteMigrationURL is the golang-migrate URL for the database at path.
+func SQLiteMigrationURL(path string) string {
+	return fmt.Sprintf("sqlite://%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", path)
+}
+
+func NewSQLiteDB(path string) (*SQLiteDB, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("creating database directory: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", SQLiteDSN(path))
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite database: %w", err)
+	}
+	// SQLite has a single writer; a few connections let reads proceed
+	// alongside it without piling up lock waits.
+	db.SetMaxOpenConns(4)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("pinging database: %w", err)
+	}
+
+	return &SQLiteDB{DB: db}, nil
+}
+
+func (db *SQLiteDB) Close() {
+	if db.DB != nil {
+		_ = db.DB.Close()
+	}
+}
+
+func (db *SQLiteDB) Health(ctx context.Context) error {
+	return db.DB.PingContext(ctx)
+}
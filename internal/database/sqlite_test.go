@@ -0,0 +1,460 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	postgresMigrationsDir = "../../migrations"
+	sqliteMigrationsDir   = "../../migrations/sqlite"
+)
+
+// migrateSQLite opens a fresh SQLite database and applies the SQLite
+// migrations to it.
+func migrateSQLite(t *testing.T) *SQLiteDB {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "nested", "bingo.db")
+	db, err := NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	m, err := NewMigrator(SQLiteMigrationURL(path), sqliteMigrationsDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = m.Close() }()
+	if err := m.Up(); err != nil {
+		t.Fatalf("unexpected migration error: %v", err)
+	}
+	return db
+}
+
+func TestNewSQLiteDB_MigratesToPostgresVersion(t *testing.T) {
+	db := migrateSQLite(t)
+	if err := db.Health(context.Background()); err != nil {
+		t.Fatalf("expected healthy database, got %v", err)
+	}
+
+	var version int
+	if err := db.DB.QueryRow("SELECT version FROM schema_migrations").Scan(&version); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := latestMigrationVersion(t, postgresMigrationsDir); version != want {
+		t.Fatalf("SQLite schema is at version %d but PostgreSQL migrations reach %d; add a matching migration under migrations/sqlite", version, want)
+	}
+
+	var suggestions int
+	if err := db.DB.QueryRow("SELECT COUNT(*) FROM suggestions WHERE is_active = true").Scan(&suggestions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if suggestions == 0 {
+		t.Fatal("expected curated suggestions to be seeded")
+	}
+
+	var id string
+	if err := db.DB.QueryRow("INSERT INTO users (email, username) VALUES ('a@example.com', 'a') RETURNING id").Scan(&id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Fatalf("expected a v4 UUID default, got %q", id)
+	}
+}
+
+func TestSQLiteSchemaMatchesPostgres(t *testing.T) {
+	db := migrateSQLite(t)
+	want := postgresSchema(t)
+	got := sqliteSchema(t, db)
+
+	for table, columns := range want.columns {
+		if _, ok := got.columns[table]; !ok {
+			t.Errorf("table %s is missing from the SQLite schema", table)
+			continue
+		}
+		if strings.Join(columns, ",") != strings.Join(got.columns[table], ",") {
+			t.Errorf("table %s columns differ:\n postgres: %v\n   sqlite: %v", table, columns, got.columns[table])
+		}
+		if w, g := want.notNullList(table), got.notNullList(table); w != g {
+			t.Errorf("table %s NOT NULL columns differ:\n postgres: %s\n   sqlite: %s", table, w, g)
+		}
+		if w, g := strings.Join(want.uniques[table], " "), strings.Join(got.uniques[table], " "); w != g {
+			t.Errorf("table %s unique keys differ:\n postgres: %s\n   sqlite: %s", table, w, g)
+		}
+	}
+	for table := range got.columns {
+		if _, ok := want.columns[table]; !ok {
+			t.Errorf("table %s is only in the SQLite schema", table)
+		}
+	}
+}
+
+// TestSQLiteMigrationsMatchPostgresVersions checks that every PostgreSQL
+// migration after the consolidated SQLite schema has a SQLite counterpart
+// with the same version and name, and that SQLite has no extra ones.
+func TestSQLiteMigrationsMatchPostgresVersions(t *testing.T) {
+	postgres := migrationNames(t, postgresMigrationsDir)
+	sqlite := migrationNames(t, sqliteMigrationsDir)
+	first := sqlite[0]
+	version := func(name string) string { return strings.SplitN(name, "_", 2)[0] }
+
+	var wantNames []string
+	for _, name := range postgres {
+		if version(name) > version(first) {
+			wantNames = append(wantNames, name)
+		}
+	}
+	if got := strings.Join(sqlite[1:], "\n"); got != strings.Join(wantNames, "\n") {
+		t.Fatalf("SQLite migrations after %s don't mirror PostgreSQL's:\n postgres: %v\n   sqlite: %v", first, wantNames, sqlite[1:])
+	}
+	for _, name := range sqlite {
+		if _, err := os.Stat(filepath.Join(sqliteMigrationsDir, name+".down.sql")); err != nil {
+			t.Errorf("missing down migration for %s: %v", name, err)
+		}
+	}
+}
+
+// migrationNames lists the up migrations in dir without their suffix, in
+// order.
+func migrationNames(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("listing migrations: %v", err)
+	}
+	sort.Strings(files)
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = strings.TrimSuffix(filepath.Base(file), ".up.sql")
+	}
+	return names
+}
+
+func latestMigrationVersion(t *testing.T, dir string) int {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("listing migrations: %v", err)
+	}
+	sort.Strings(files)
+	version, err := strconv.Atoi(strings.SplitN(filepath.Base(files[len(files)-1]), "_", 2)[0])
+	if err != nil {
+		t.Fatalf("parsing migration version: %v", err)
+	}
+	return version
+}
+
+var (
+	sqlCommentPattern     = regexp.MustCompile(`--[^\n]*`)
+	sqlDollarQuotePattern = regexp.MustCompile(`(?s)\$\$.*?\$\$`)
+	createTablePattern    = regexp.MustCompile(`(?is)^CREATE TABLE (\w+) \((.*)\)$`)
+	alterTablePattern     = regexp.MustCompile(`(?is)^ALTER TABLE (\w+) (.*)$`)
+	addColumnPattern      = regexp.MustCompile(`(?i)^ADD COLUMN (\w+)(.*)$`)
+	renameColumnPattern   = regexp.MustCompile(`(?i)^RENAME COLUMN (\w+) TO (\w+)`)
+	dropColumnPattern     = regexp.MustCompile(`(?i)^DROP COLUMN (\w+)`)
+	dropTablePattern      = regexp.MustCompile(`(?i)^DROP TABLE (IF EXISTS )?(\w+)`)
+	setNotNullPattern     = regexp.MustCompile(`(?i)^ALTER COLUMN (\w+) (SET|DROP) NOT NULL`)
+	addUniquePattern      = regexp.MustCompile(`(?i)^ADD CONSTRAINT (\w+) UNIQUE ?\((.*)\)$`)
+	dropConstraintPattern = regexp.MustCompile(`(?i)^DROP CONSTRAINT (IF EXISTS )?(\w+)`)
+	tableUniquePattern    = regexp.MustCompile(`(?i)^(CONSTRAINT (\w+) )?UNIQUE ?\((.*)\)$`)
+	tablePrimaryPattern   = regexp.MustCompile(`(?i)^(CONSTRAINT \w+ )?PRIMARY KEY ?\((.*)\)$`)
+	createIndexPattern    = regexp.MustCompile(`(?is)^CREATE UNIQUE INDEX (IF NOT EXISTS )?(\w+) ON (\w+)(?: USING \w+)? ?(\(.*)$`)
+	dropIndexPattern      = regexp.MustCompile(`(?i)^DROP INDEX (IF EXISTS )?(\w+)`)
+	inlineUniquePattern   = regexp.MustCompile(`(?i)\bUNIQUE\b`)
+	notNullPattern        = regexp.MustCompile(`(?i)\bNOT NULL\b|\bPRIMARY KEY\b`)
+)
+
+// schemaShape is what the drift test compares: each table's columns, which
+// of them are NOT NULL, and its unique keys as "cols" or "cols where" for
+// partial indexes. Primary keys count as NOT NULL but not as unique keys.
+type schemaShape struct {
+	columns map[string][]string
+	notNull map[string]map[string]bool
+	uniques map[string][]string
+}
+
+func (s schemaShape) notNullList(table string) string {
+	var cols []string
+	for _, col := range s.columns[table] {
+		if s.notNull[table][col] {
+			cols = append(cols, col)
+		}
+	}
+	return strings.Join(cols, ",")
+}
+
+// uniqueKey normalizes a unique key's column list (possibly expressions such
+// as LOWER(username)) so both dialects compare equal.
+func uniqueKey(columns string, partial bool) string {
+	key := strings.ToLower(strings.Join(strings.Fields(columns), ""))
+	if partial {
+		key += "+where"
+	}
+	return key
+}
+
+// parenthesized returns the text inside the parentheses s starts with and
+// what follows them.
+func parenthesized(s string) (string, string) {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[1:i], strings.TrimSpace(s[i+1:])
+			}
+		}
+	}
+	return s, ""
+}
+
+// postgresSchema replays the PostgreSQL migrations' table, column, NOT NULL
+// and unique key changes to get the schema they end up with.
+func postgresSchema(t *testing.T) schemaShape {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(postgresMigrationsDir, "*.up.sql"))
+	if err != nil {
+		t.Fatalf("listing migrations: %v", err)
+	}
+	sort.Strings(files)
+
+	type uniqueDef struct{ table, columns string }
+	tables := map[string][]string{}
+	notNull := map[string]map[string]bool{}
+	uniques := map[string]uniqueDef{} // by constraint or index name
+	addUnique := func(name, table, columns string) {
+		if name == "" {
+			name = table + "_" + strings.Join(strings.Split(strings.ToLower(strings.Join(strings.Fields(columns), "")), ","), "_") + "_key"
+		}
+		uniques[name] = uniqueDef{table, columns}
+	}
+	addColumn := func(table, name, def string) {
+		tables[table] = append(tables[table], name)
+		if notNull[table] == nil {
+			notNull[table] = map[string]bool{}
+		}
+		notNull[table][name] = notNullPattern.MatchString(def)
+		if inlineUniquePattern.MatchString(def) {
+			addUnique("", table, name)
+		}
+	}
+
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("reading %s: %v", file, err)
+		}
+		script := sqlDollarQuotePattern.ReplaceAllString(sqlCommentPattern.ReplaceAllString(string(raw), ""), "''")
+		for _, stmt := range strings.Split(script, ";") {
+			stmt = strings.Join(strings.Fields(stmt), " ")
+			if m := createTablePattern.FindStringSubmatch(stmt); m != nil {
+				table := m[1]
+				tables[table] = nil
+				notNull[table] = map[string]bool{}
+				for _, def := range splitTopLevel(m[2]) {
+					if u := tableUniquePattern.FindStringSubmatch(def); u != nil {
+						addUnique(u[2], table, u[3])
+						continue
+					}
+					if p := tablePrimaryPattern.FindStringSubmatch(def); p != nil {
+						for _, col := range strings.Split(p[2], ",") {
+							notNull[table][strings.TrimSpace(col)] = true
+						}
+						continue
+					}
+					name := strings.FieldsFunc(def, func(r rune) bool { return r == ' ' || r == '(' })[0]
+					switch strings.ToUpper(name) {
+					case "CONSTRAINT", "CHECK", "FOREIGN":
+						continue
+					}
+					addColumn(table, name, strings.TrimPrefix(def, name))
+				}
+				continue
+			}
+			if m := dropTablePattern.FindStringSubmatch(stmt); m != nil {
+				delete(tables, m[2])
+				delete(notNull, m[2])
+				for name, u := range uniques {
+					if u.table == m[2] {
+						delete(uniques, name)
+					}
+				}
+				continue
+			}
+			if m := createIndexPattern.FindStringSubmatch(stmt); m != nil {
+				columns, rest := parenthesized(m[4])
+				if strings.HasPrefix(strings.ToUpper(rest), "WHERE") {
+					columns += " where"
+				}
+				uniques[m[2]] = uniqueDef{m[3], columns}
+				continue
+			}
+			if m := dropIndexPattern.FindStringSubmatch(stmt); m != nil {
+				delete(uniques, m[2])
+				continue
+			}
+			m := alterTablePattern.FindStringSubmatch(stmt)
+			if m == nil {
+				continue
+			}
+			table := m[1]
+			for _, action := range splitTopLevel(m[2]) {
+				if a := addColumnPattern.FindStringSubmatch(action); a != nil {
+					addColumn(table, a[1], a[2])
+				} else if n := setNotNullPattern.FindStringSubmatch(action); n != nil {
+					notNull[table][n[1]] = strings.EqualFold(n[2], "SET")
+				} else if u := addUniquePattern.FindStringSubmatch(action); u != nil {
+					addUnique(u[1], table, u[2])
+				} else if d := dropConstraintPattern.FindStringSubmatch(action); d != nil {
+					delete(uniques, d[2])
+				} else if r := renameColumnPattern.FindStringSubmatch(action); r != nil {
+					for i, col := range tables[table] {
+						if col == r[1] {
+							tables[table][i] = r[2]
+						}
+					}
+					notNull[table][r[2]] = notNull[table][r[1]]
+					delete(notNull[table], r[1])
+					for name, u := range uniques {
+						if u.table == table {
+							u.columns = regexp.MustCompile(`\b`+r[1]+`\b`).ReplaceAllString(u.columns, r[2])
+							uniques[name] = u
+						}
+					}
+				} else if d := dropColumnPattern.FindStringSubmatch(action); d != nil {
+					kept := tables[table][:0]
+					for _, col := range tables[table] {
+						if col != d[1] {
+							kept = append(kept, col)
+						}
+					}
+					tables[table] = kept
+					delete(notNull[table], d[1])
+				}
+			}
+		}
+	}
+
+	shape := schemaShape{columns: tables, notNull: notNull, uniques: map[string][]string{}}
+	for _, columns := range tables {
+		sort.Strings(columns)
+	}
+	for _, u := range uniques {
+		columns := strings.TrimSuffix(u.columns, " where")
+		shape.uniques[u.table] = append(shape.uniques[u.table], uniqueKey(columns, columns != u.columns))
+	}
+	for _, keys := range shape.uniques {
+		sort.Strings(keys)
+	}
+	return shape
+}
+
+// sqliteSchema reads the same shape back from a migrated SQLite database.
+func sqliteSchema(t *testing.T, db *SQLiteDB) schemaShape {
+	t.Helper()
+	query := func(sql string, args []any, scan func(rows *sql.Rows) error) {
+		rows, err := db.DB.Query(sql, args...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			if err := scan(rows); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	shape := schemaShape{columns: map[string][]string{}, notNull: map[string]map[string]bool{}, uniques: map[string][]string{}}
+	var tables []string
+	query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')", nil, func(rows *sql.Rows) error {
+		var table string
+		err := rows.Scan(&table)
+		tables = append(tables, table)
+		return err
+	})
+
+	for _, table := range tables {
+		shape.notNull[table] = map[string]bool{}
+		query(`SELECT name, "notnull", pk FROM pragma_table_info(?)`, []any{table}, func(rows *sql.Rows) error {
+			var name string
+			var notNull, pk int
+			if err := rows.Scan(&name, &notNull, &pk); err != nil {
+				return err
+			}
+			shape.columns[table] = append(shape.columns[table], name)
+			shape.notNull[table][name] = notNull == 1 || pk > 0
+			return nil
+		})
+		sort.Strings(shape.columns[table])
+
+		type index struct {
+			name, origin string
+			partial      bool
+		}
+		var indexes []index
+		query(`SELECT name, origin, partial FROM pragma_index_list(?) WHERE "unique" = 1 AND origin <> 'pk'`, []any{table}, func(rows *sql.Rows) error {
+			var idx index
+			err := rows.Scan(&idx.name, &idx.origin, &idx.partial)
+			indexes = append(indexes, idx)
+			return err
+		})
+		for _, idx := range indexes {
+			var columns string
+			if idx.origin == "c" {
+				var def string
+				if err := db.DB.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'index' AND name = ?", idx.name).Scan(&def); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				on := strings.Index(strings.ToUpper(def), " ON ")
+				columns, _ = parenthesized(def[on+strings.Index(def[on:], "("):])
+			} else {
+				var names []string
+				query("SELECT name FROM pragma_index_info(?) ORDER BY seqno", []any{idx.name}, func(rows *sql.Rows) error {
+					var name string
+					err := rows.Scan(&name)
+					names = append(names, name)
+					return err
+				})
+				columns = strings.Join(names, ",")
+			}
+			shape.uniques[table] = append(shape.uniques[table], uniqueKey(columns, idx.partial))
+		}
+		sort.Strings(shape.uniques[table])
+	}
+	return shape
+}
+
+// splitTopLevel splits s on commas outside parentheses and quotes.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inQuote := false
+	for i, c := range s {
+		switch {
+		case inQuote:
+			inQuote = c != '\''
+		case c == '\'':
+			inQuote = true
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
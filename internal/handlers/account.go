@@ -1,23 +1,49 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
+const (
+	accountExportLimitMax    = 3              // max exports per window
+	accountExportLimitWindow = 24 * time.Hour // export limit window
+	accountExportBufferSize  = 64 << 10       // ZIP bytes held back before the response is committed
+	accountExportLimitPrefix = "ratelimit:account_export:"
+)
+
 type AccountHandler struct {
 	accountService services.AccountServiceInterface
 	authService    services.AuthServiceInterface
+	emailService   services.EmailServiceInterface
+	exportLimiter  exportLimitStore
 	secure         bool
 }
 
+// exportLimitStore is a rateLimitStore that can also report how long until a
+// key expires, so a limited export can say when the next one is allowed.
+type exportLimitStore interface {
+	rateLimitStore
+	TTL(ctx context.Context, key string) (time.Duration, error)
+}
+
 func NewAccountHandler(accountService services.AccountServiceInterface, authService services.AuthServiceInterface, secure bool) *AccountHandler {
 	return &AccountHandler{
 		accountService: accountService,
@@ -26,6 +52,28 @@ func NewAccountHandler(accountService services.AccountServiceInterface, authServ
 	}
 }
 
+// AccountExportUsageLimit describes the export limit for usage reports.
+func AccountExportUsageLimit() services.UsageLimitSpec {
+	return services.UsageLimitSpec{
+		Name:      "account_export",
+		Limit:     accountExportLimitMax,
+		Window:    accountExportLimitWindow,
+		KeyPrefix: accountExportLimitPrefix,
+	}
+}
+
+// SetExportLimiter enables the per-user export limit. Without Redis, exports are unlimited.
+func (h *AccountHandler) SetExportLimiter(redisClient *redis.Client) {
+	if redisClient != nil {
+		h.exportLimiter = redisRateLimitStore{client: redisClient}
+	}
+}
+
+// SetEmailService enables the courtesy email sent after each export.
+func (h *AccountHandler) SetEmailService(emailService services.EmailServiceInterface) {
+	h.emailService = emailService
+}
+
 type AccountDeleteRequest struct {
 	ConfirmUsername string `json:"confirm_username"`
 	Password        string `json:"password"`
@@ -36,31 +84,168 @@ type AccountMessageResponse struct {
 	Message string `json:"message"`
 }
 
+type PreferencesResponse struct {
+	Preferences *models.UserPreferences `json:"preferences"`
+}
+
+type UpdatePreferencesRequest struct {
+	DataMinimization *bool `json:"data_minimization"`
+}
+
+type AccountExportLimitResponse struct {
+	Error   string    `json:"error"`
+	RetryAt time.Time `json:"retry_at"`
+}
+
 func (h *AccountHandler) Export(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
 		writeError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
+	if rejectTokenAuth(w, r) {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "zip"
+	}
+	if format != "zip" && format != "json" {
+		writeError(w, http.StatusBadRequest, "format must be zip or json")
+		return
+	}
+
+	now := time.Now().UTC()
+	if retryAt, limited := h.checkExportLimit(r.Context(), user.ID, now); limited {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAt.Sub(now).Seconds())))
+		writeJSON(w, http.StatusTooManyRequests, AccountExportLimitResponse{
+			Error:   fmt.Sprintf("Export limit reached. You can export again after %s.", retryAt.UTC().Format(time.RFC3339)),
+			RetryAt: retryAt,
+		})
+		return
+	}
+
+	filename := "yearofbingo_account_export_" + now.UTC().Format("2006-01-02") + "." + format
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	var size int64
+	if format == "json" {
+		data, err := h.accountService.BuildExportJSON(r.Context(), user.ID)
+		if err != nil {
+			writeExportError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", disposition)
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(data); err != nil {
+			log.Printf("Error writing account export: %v", err)
+		}
+		size = int64(len(data))
+	} else {
+		var ok bool
+		if size, ok = h.streamExportZip(w, r, user.ID, disposition); !ok {
+			return
+		}
+	}
+
+	details, _ := json.Marshal(map[string]any{"size_bytes": size, "format": format})
+	if err := h.accountService.RecordEvent(r.Context(), models.AccountEvent{
+		UserID:    user.ID,
+		EventType: models.AccountEventDataExport,
+		Details:   details,
+	}); err != nil {
+		log.Printf("Error recording account export: %v", err)
+	}
+	if h.emailService != nil {
+		if err := h.emailService.SendDataExportEmail(r.Context(), user.Email, now); err != nil {
+			log.Printf("Error sending account export email: %v", err)
+		}
+	}
+}
 
-	data, err := h.accountService.BuildExportZip(r.Context(), user.ID)
+// streamExportZip writes the user's ZIP export to the response as it is
+// built and returns its size. The first accountExportBufferSize bytes are
+// held back, so an error before then still gets a normal error response. An
+// error after that is only logged: the 200 is already sent, and stopping
+// leaves the archive without its central directory, so clients reject it.
+func (h *AccountHandler) streamExportZip(w http.ResponseWriter, r *http.Request, userID uuid.UUID, disposition string) (int64, bool) {
+	sent := &countingWriter{w: w}
+	buf := bufio.NewWriterSize(sent, accountExportBufferSize)
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", disposition)
+
+	err := h.accountService.StreamExport(r.Context(), userID, buf)
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err == nil {
+		return sent.n, true
+	}
+	if sent.n == 0 {
+		w.Header().Del("Content-Disposition")
+		writeExportError(w, err)
+		return 0, false
+	}
+	if !clientGone(r) {
+		log.Printf("Error streaming account export after %d bytes: %v", sent.n, err)
+	}
+	return sent.n, false
+}
+
+func writeExportError(w http.ResponseWriter, err error) {
+	if errors.Is(err, context.Canceled) {
+		// The client disconnected; there's no one to answer.
+		return
+	}
 	if errors.Is(err, services.ErrUserNotFound) {
 		writeError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
+	log.Printf("Error building account export: %v", err)
+	writeError(w, http.StatusInternalServerError, "Internal server error")
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// checkExportLimit counts an export attempt for the user and reports whether
+// the limit is exceeded, along with when the next export is allowed. Redis
+// errors allow the export.
+func (h *AccountHandler) checkExportLimit(ctx context.Context, userID uuid.UUID, now time.Time) (time.Time, bool) {
+	if h.exportLimiter == nil {
+		return time.Time{}, false
+	}
+
+	key := accountExportLimitPrefix + userID.String()
+	count, err := h.exportLimiter.Incr(ctx, key)
 	if err != nil {
-		log.Printf("Error building account export: %v", err)
-		writeError(w, http.StatusInternalServerError, "Internal server error")
-		return
+		logging.Error("Account export limit Redis error", map[string]interface{}{"error": err.Error()})
+		return time.Time{}, false
+	}
+	if count == 1 {
+		_ = h.exportLimiter.Expire(ctx, key, accountExportLimitWindow)
+	}
+	if count <= accountExportLimitMax {
+		return time.Time{}, false
 	}
 
-	filename := "yearofbingo_account_export_" + time.Now().UTC().Format("2006-01-02") + ".zip"
-	w.Header().Set("Content-Type", "application/zip")
-	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write(data); err != nil {
-		log.Printf("Error writing account export: %v", err)
+	ttl, err := h.exportLimiter.TTL(ctx, key)
+	if err != nil || ttl <= 0 {
+		// The window's expiry was lost; start a fresh one so the key can't stick forever.
+		_ = h.exportLimiter.Expire(ctx, key, accountExportLimitWindow)
+		ttl = accountExportLimitWindow
 	}
+	return now.Add(ttl).Truncate(time.Second), true
 }
 
 func (h *AccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -104,6 +289,54 @@ func (h *AccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, AccountMessageResponse{Message: "Account deleted"})
 }
 
+func (h *AccountHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	prefs, err := h.accountService.GetPreferences(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error loading preferences: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PreferencesResponse{Preferences: prefs})
+}
+
+// UpdatePreferences saves account preferences. Enabling data minimization also
+// purges the records it covers.
+func (h *AccountHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req UpdatePreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.DataMinimization == nil {
+		writeError(w, http.StatusBadRequest, "data_minimization is required")
+		return
+	}
+
+	prefs, err := h.accountService.UpdatePreferences(r.Context(), user.ID, models.UserPreferences{
+		DataMinimization: *req.DataMinimization,
+	})
+	if err != nil {
+		log.Printf("Error updating preferences: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PreferencesResponse{Preferences: prefs})
+}
+
 func (h *AccountHandler) clearSessionCookie(w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     sessionCookieName,
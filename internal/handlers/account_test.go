@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -16,12 +20,36 @@ import (
 
 type mockAccountService struct {
 	services.AccountServiceInterface
-	BuildExportZipFunc func(ctx context.Context, userID uuid.UUID) ([]byte, error)
-	DeleteFunc         func(ctx context.Context, userID uuid.UUID) error
+	StreamExportFunc    func(ctx context.Context, userID uuid.UUID, w io.Writer) error
+	BuildExportJSONFunc func(ctx context.Context, userID uuid.UUID) ([]byte, error)
+	RecordEventFunc     func(ctx context.Context, event models.AccountEvent) error
+	DeleteFunc          func(ctx context.Context, userID uuid.UUID) error
+
+	GetPreferencesFunc    func(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error)
+	UpdatePreferencesFunc func(ctx context.Context, userID uuid.UUID, prefs models.UserPreferences) (*models.UserPreferences, error)
+}
+
+func (m *mockAccountService) GetPreferences(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error) {
+	return m.GetPreferencesFunc(ctx, userID)
+}
+
+func (m *mockAccountService) UpdatePreferences(ctx context.Context, userID uuid.UUID, prefs models.UserPreferences) (*models.UserPreferences, error) {
+	return m.UpdatePreferencesFunc(ctx, userID, prefs)
+}
+
+func (m *mockAccountService) StreamExport(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+	return m.StreamExportFunc(ctx, userID, w)
 }
 
-func (m *mockAccountService) BuildExportZip(ctx context.Context, userID uuid.UUID) ([]byte, error) {
-	return m.BuildExportZipFunc(ctx, userID)
+func (m *mockAccountService) BuildExportJSON(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	return m.BuildExportJSONFunc(ctx, userID)
+}
+
+func (m *mockAccountService) RecordEvent(ctx context.Context, event models.AccountEvent) error {
+	if m.RecordEventFunc != nil {
+		return m.RecordEventFunc(ctx, event)
+	}
+	return nil
 }
 
 func (m *mockAccountService) Delete(ctx context.Context, userID uuid.UUID) error {
@@ -57,8 +85,9 @@ func TestAccountHandler_Export_Unauthorized(t *testing.T) {
 func TestAccountHandler_Export_Success(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	handler := NewAccountHandler(&mockAccountService{
-		BuildExportZipFunc: func(ctx context.Context, userID uuid.UUID) ([]byte, error) {
-			return []byte("PK\x03\x04test"), nil
+		StreamExportFunc: func(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+			_, err := w.Write([]byte("PK\x03\x04test"))
+			return err
 		},
 	}, &mockAccountAuthService{}, false)
 
@@ -79,6 +108,367 @@ func TestAccountHandler_Export_Success(t *testing.T) {
 	}
 }
 
+func TestAccountHandler_Export_RejectsTokenAuth(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewAccountHandler(&mockAccountService{
+		StreamExportFunc: func(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+			t.Fatal("export should not run for token callers")
+			return nil
+		},
+	}, &mockAccountAuthService{}, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/account/export", nil)
+	req = req.WithContext(SetAuthInfoInContext(req.Context(), &AuthInfo{User: user, Method: AuthMethodToken, TokenID: uuid.New(), Scope: models.ScopeReadWrite}))
+	rr := httptest.NewRecorder()
+
+	handler.Export(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusForbidden, "Token authentication not allowed for this endpoint")
+}
+
+func TestAccountHandler_Export_JSON(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	var recorded models.AccountEvent
+	handler := NewAccountHandler(&mockAccountService{
+		BuildExportJSONFunc: func(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+			return []byte(`{"export_version":1}`), nil
+		},
+		RecordEventFunc: func(ctx context.Context, event models.AccountEvent) error {
+			recorded = event
+			return nil
+		},
+	}, &mockAccountAuthService{}, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/account/export?format=json", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Export(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected content-type application/json, got %q", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, ".json") {
+		t.Fatalf("expected a .json attachment, got %q", cd)
+	}
+	if !strings.Contains(string(recorded.Details), `"format":"json"`) {
+		t.Fatalf("expected format in event details, got %s", recorded.Details)
+	}
+}
+
+func TestAccountHandler_Export_InvalidFormat(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewAccountHandler(&mockAccountService{}, &mockAccountAuthService{}, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/account/export?format=xml", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Export(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+}
+
+func TestAccountHandler_Export_RecordsEventAndSendsEmail(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "user@example.com"}
+	var recorded *models.AccountEvent
+	handler := NewAccountHandler(&mockAccountService{
+		StreamExportFunc: func(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+			_, err := w.Write([]byte("PK\x03\x04test"))
+			return err
+		},
+		RecordEventFunc: func(ctx context.Context, event models.AccountEvent) error {
+			recorded = &event
+			return nil
+		},
+	}, &mockAccountAuthService{}, false)
+	var emailedTo string
+	handler.SetEmailService(&mockEmailService{
+		SendDataExportEmailFunc: func(ctx context.Context, email string, exportedAt time.Time) error {
+			emailedTo = email
+			return nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/account/export", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Export(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if recorded == nil || recorded.UserID != user.ID || recorded.EventType != models.AccountEventDataExport {
+		t.Fatalf("expected export event, got %+v", recorded)
+	}
+	if string(recorded.Details) != `{"format":"zip","size_bytes":8}` {
+		t.Fatalf("expected size in event details, got %s", recorded.Details)
+	}
+	if emailedTo != user.Email {
+		t.Fatalf("expected courtesy email to %q, got %q", user.Email, emailedTo)
+	}
+}
+
+func TestAccountHandler_Export_EventAndEmailFailuresDontBlockDownload(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "user@example.com"}
+	handler := NewAccountHandler(&mockAccountService{
+		StreamExportFunc: func(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+			_, err := w.Write([]byte("PK\x03\x04test"))
+			return err
+		},
+		RecordEventFunc: func(ctx context.Context, event models.AccountEvent) error {
+			return errors.New("db down")
+		},
+	}, &mockAccountAuthService{}, false)
+	handler.SetEmailService(&mockEmailService{
+		SendDataExportEmailFunc: func(ctx context.Context, email string, exportedAt time.Time) error {
+			return errors.New("smtp down")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/account/export", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Export(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+}
+
+func TestAccountHandler_Export_ErrorBeforeFirstFlush(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	recorded := false
+	handler := NewAccountHandler(&mockAccountService{
+		StreamExportFunc: func(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+			_, _ = w.Write([]byte("PK\x03\x04partial"))
+			return errors.New("query failed")
+		},
+		RecordEventFunc: func(ctx context.Context, event models.AccountEvent) error {
+			recorded = true
+			return nil
+		},
+	}, &mockAccountAuthService{}, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/account/export", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Export(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected a JSON error, got content-type %q", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); cd != "" {
+		t.Fatalf("expected no attachment header, got %q", cd)
+	}
+	if bytes.Contains(rr.Body.Bytes(), []byte("partial")) {
+		t.Fatalf("expected buffered export bytes to be dropped, got %q", rr.Body.Bytes())
+	}
+	if recorded {
+		t.Fatal("expected no export event for a failed export")
+	}
+}
+
+func TestAccountHandler_Export_UserNotFound(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewAccountHandler(&mockAccountService{
+		StreamExportFunc: func(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+			return services.ErrUserNotFound
+		},
+	}, &mockAccountAuthService{}, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/account/export", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Export(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", rr.Code)
+	}
+}
+
+func TestAccountHandler_Export_ErrorMidStream(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "user@example.com"}
+	recorded, emailed := false, false
+	handler := NewAccountHandler(&mockAccountService{
+		StreamExportFunc: func(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+			if _, err := w.Write(bytes.Repeat([]byte("x"), 2*accountExportBufferSize)); err != nil {
+				return err
+			}
+			_, _ = w.Write([]byte("unflushed"))
+			return errors.New("query failed")
+		},
+		RecordEventFunc: func(ctx context.Context, event models.AccountEvent) error {
+			recorded = true
+			return nil
+		},
+	}, &mockAccountAuthService{}, false)
+	handler.SetEmailService(&mockEmailService{
+		SendDataExportEmailFunc: func(ctx context.Context, email string, exportedAt time.Time) error {
+			emailed = true
+			return nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/account/export", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Export(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected the committed 200 to stand, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Fatalf("expected content-type application/zip, got %q", ct)
+	}
+	if rr.Body.Len() != 2*accountExportBufferSize || bytes.Contains(rr.Body.Bytes(), []byte("unflushed")) {
+		t.Fatalf("expected only the committed bytes, got %d bytes", rr.Body.Len())
+	}
+	if recorded || emailed {
+		t.Fatalf("expected no event or email for a failed export, got event=%v email=%v", recorded, emailed)
+	}
+}
+
+func TestAccountHandler_Export_ClientDisconnect(t *testing.T) {
+	for _, format := range []string{"zip", "json"} {
+		t.Run(format, func(t *testing.T) {
+			user := &models.User{ID: uuid.New()}
+			ctx, cancel := context.WithCancel(SetUserInContext(context.Background(), user))
+			recorded := false
+			exportCanceled := func(ctx context.Context) error {
+				cancel()
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			handler := NewAccountHandler(&mockAccountService{
+				StreamExportFunc: func(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+					return exportCanceled(ctx)
+				},
+				BuildExportJSONFunc: func(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+					return nil, exportCanceled(ctx)
+				},
+				RecordEventFunc: func(ctx context.Context, event models.AccountEvent) error {
+					recorded = true
+					return nil
+				},
+			}, &mockAccountAuthService{}, false)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/account/export?format="+format, nil).WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			handler.Export(rr, req)
+
+			if rr.Body.Len() != 0 {
+				t.Fatalf("expected nothing written to a gone client, got %q", rr.Body.String())
+			}
+			if recorded {
+				t.Fatal("expected no export event for a canceled export")
+			}
+		})
+	}
+}
+
+func TestAccountHandler_Export_RateLimited(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	built := 0
+	handler := NewAccountHandler(&mockAccountService{
+		StreamExportFunc: func(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+			built++
+			_, err := w.Write([]byte("PK\x03\x04test"))
+			return err
+		},
+	}, &mockAccountAuthService{}, false)
+	store := &fakeRateLimitStore{ttl: 2 * time.Hour}
+	handler.exportLimiter = store
+
+	for i := 0; i < accountExportLimitMax; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/account/export", nil)
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.Export(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("export %d: expected status 200, got %d", i+1, rr.Code)
+		}
+	}
+	if store.expireCalls != 1 {
+		t.Fatalf("expected window expiry set once, got %d", store.expireCalls)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/account/export", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	before := time.Now()
+	handler.Export(rr, req)
+
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", rr.Code)
+	}
+	if built != accountExportLimitMax {
+		t.Fatalf("expected no export build once limited, got %d builds", built)
+	}
+	if ra := rr.Header().Get("Retry-After"); ra == "" {
+		t.Fatal("expected Retry-After header")
+	}
+	var resp AccountExportLimitResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	wantRetry := before.Add(2 * time.Hour)
+	if resp.RetryAt.Before(wantRetry.Add(-time.Second)) || resp.RetryAt.After(wantRetry.Add(time.Minute)) {
+		t.Fatalf("expected retry_at about 2h out, got %v", resp.RetryAt)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if s, _ := raw["retry_at"].(string); !strings.HasSuffix(s, "Z") {
+		t.Fatalf("expected retry_at as an RFC3339 UTC timestamp, got %q", s)
+	}
+	if !strings.Contains(resp.Error, "Export limit reached") {
+		t.Fatalf("unexpected error message: %q", resp.Error)
+	}
+}
+
+func TestAccountHandler_checkExportLimit(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("redis error allows export", func(t *testing.T) {
+		h := &AccountHandler{exportLimiter: &fakeRateLimitStore{incrErr: errors.New("boom")}}
+		if _, limited := h.checkExportLimit(context.Background(), userID, now); limited {
+			t.Fatal("expected export allowed on Redis error")
+		}
+	})
+
+	t.Run("missing expiry is reset to a full window", func(t *testing.T) {
+		store := &fakeRateLimitStore{incrCount: accountExportLimitMax, ttl: -1}
+		h := &AccountHandler{exportLimiter: store}
+		retryAt, limited := h.checkExportLimit(context.Background(), userID, now)
+		if !limited {
+			t.Fatal("expected export limited")
+		}
+		if !retryAt.Equal(now.Add(accountExportLimitWindow)) {
+			t.Fatalf("expected retry after a full window, got %v", retryAt)
+		}
+		if store.expireCalls != 1 {
+			t.Fatalf("expected expiry reset, got %d calls", store.expireCalls)
+		}
+	})
+}
+
 func TestAccountHandler_Delete_Unauthorized(t *testing.T) {
 	handler := NewAccountHandler(&mockAccountService{}, &mockAccountAuthService{}, false)
 	req := httptest.NewRequest(http.MethodDelete, "/api/account", nil)
@@ -183,3 +573,63 @@ func TestAccountHandler_Delete_Success(t *testing.T) {
 		t.Fatalf("expected cleared session cookie, got %+v", sessionCookie)
 	}
 }
+
+func TestAccountHandler_GetPreferences(t *testing.T) {
+	handler := NewAccountHandler(&mockAccountService{}, &mockAccountAuthService{}, false)
+	rr := httptest.NewRecorder()
+	handler.GetPreferences(rr, httptest.NewRequest(http.MethodGet, "/api/auth/preferences", nil))
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+
+	handler = NewAccountHandler(&mockAccountService{
+		GetPreferencesFunc: func(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error) {
+			return &models.UserPreferences{DataMinimization: true}, nil
+		},
+	}, &mockAccountAuthService{}, false)
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/preferences", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr = httptest.NewRecorder()
+	handler.GetPreferences(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp PreferencesResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Preferences == nil || !resp.Preferences.DataMinimization {
+		t.Fatalf("expected data minimization in response, got %+v", resp.Preferences)
+	}
+}
+
+func TestAccountHandler_UpdatePreferences(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	put := func(svc *mockAccountService, body string) *httptest.ResponseRecorder {
+		handler := NewAccountHandler(svc, &mockAccountAuthService{}, false)
+		req := httptest.NewRequest(http.MethodPut, "/api/auth/preferences", bytes.NewBufferString(body))
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.UpdatePreferences(rr, req)
+		return rr
+	}
+
+	var got models.UserPreferences
+	rr := put(&mockAccountService{
+		UpdatePreferencesFunc: func(ctx context.Context, userID uuid.UUID, prefs models.UserPreferences) (*models.UserPreferences, error) {
+			got = prefs
+			return &prefs, nil
+		},
+	}, `{"data_minimization":true}`)
+	if rr.Code != http.StatusOK || !got.DataMinimization {
+		t.Fatalf("expected enabled preference saved, got %d %+v", rr.Code, got)
+	}
+
+	assertErrorResponse(t, put(&mockAccountService{}, `{`), http.StatusBadRequest, "Invalid request body")
+	assertErrorResponse(t, put(&mockAccountService{}, `{}`), http.StatusBadRequest, "data_minimization is required")
+
+	rr = put(&mockAccountService{
+		UpdatePreferencesFunc: func(ctx context.Context, userID uuid.UUID, prefs models.UserPreferences) (*models.UserPreferences, error) {
+			return nil, errors.New("boom")
+		},
+	}, `{"data_minimization":false}`)
+	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+}
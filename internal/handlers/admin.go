@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// Admin audit actions.
+const (
+	AdminActionReminderResend   = "reminder.resend"
+	AdminActionViewUserReminder = "reminder.view_user"
+	AdminActionContentSearch    = "content.search"
+	AdminActionReminderRun      = "reminder.run"
+	AdminActionAIQuotaOverride  = "ai_quota.override"
+)
+
+const (
+	adminSearchMinQuery     = 2
+	adminSearchMaxQuery     = 100
+	adminSearchDefaultLimit = 20
+	adminSearchMaxLimit     = 100
+
+	suggestionAnalyticsDefaultDays  = 90
+	suggestionAnalyticsMaxDays      = 365
+	suggestionAnalyticsDefaultLimit = 10
+	suggestionAnalyticsMaxLimit     = 50
+
+	reminderRunMaxLimit = 500
+)
+
+// AdminHandler serves support-only endpoints. Routes must be wrapped with the
+// admin gate middleware; the handler itself only checks for an authenticated user.
+type AdminHandler struct {
+	cursorPager
+	reminderService services.ReminderServiceInterface
+	auditService    services.AdminAuditServiceInterface
+	searchService   services.AdminSearchServiceInterface
+	suggestionStats services.SuggestionAnalyticsServiceInterface
+	aiQuota         services.AIQuotaAdminServiceInterface
+	// reminderRunEnabled allows POST /api/admin/reminders/run. It is off in
+	// production unless ADMIN_TOOLS_ENABLED is set.
+	reminderRunEnabled bool
+}
+
+func NewAdminHandler(reminderService services.ReminderServiceInterface, auditService services.AdminAuditServiceInterface) *AdminHandler {
+	return &AdminHandler{
+		reminderService: reminderService,
+		auditService:    auditService,
+	}
+}
+
+// SetSearchService enables /api/admin/search. Without it, search responds 404.
+func (h *AdminHandler) SetSearchService(searchService services.AdminSearchServiceInterface) {
+	h.searchService = searchService
+}
+
+// SetSuggestionAnalytics enables /api/admin/suggestions/analytics. Without it,
+// the endpoint responds 404.
+func (h *AdminHandler) SetSuggestionAnalytics(suggestionStats services.SuggestionAnalyticsServiceInterface) {
+	h.suggestionStats = suggestionStats
+}
+
+// SetAIQuotaService enables /api/admin/users/{id}/ai-quota. Without it, the
+// endpoint responds 404.
+func (h *AdminHandler) SetAIQuotaService(aiQuota services.AIQuotaAdminServiceInterface) {
+	h.aiQuota = aiQuota
+}
+
+// SetReminderRunEnabled allows /api/admin/reminders/run. Without it, the
+// endpoint responds 403.
+func (h *AdminHandler) SetReminderRunEnabled(enabled bool) {
+	h.reminderRunEnabled = enabled
+}
+
+type AdminReminderResendRequest struct {
+	BypassCap bool `json:"bypass_cap"`
+}
+
+type AdminReminderResendResponse struct {
+	Result *models.ReminderResendResult `json:"result"`
+}
+
+type AdminReminderRunRequest struct {
+	Limit int        `json:"limit"`
+	AsOf  *time.Time `json:"as_of"`
+}
+
+type AdminReminderRunResponse struct {
+	Result *models.ReminderRunResult `json:"result"`
+}
+
+type AdminAIQuotaRequest struct {
+	Unlimited *bool `json:"unlimited"`
+}
+
+type AdminAIQuotaResponse struct {
+	Quota *models.AIQuota `json:"quota"`
+}
+
+type AdminUserRemindersResponse struct {
+	Report *models.UserReminderReport `json:"report"`
+}
+
+func (h *AdminHandler) ResendReminder(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	reminderID, err := uuid.Parse(r.PathValue("reminderId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid reminder ID")
+		return
+	}
+
+	// The body is optional; an empty body resends with the cap enforced.
+	var req AdminReminderResendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.reminderService.ResendReminder(r.Context(), reminderID, req.BypassCap)
+	switch {
+	case errors.Is(err, services.ErrReminderNotFound):
+		writeError(w, http.StatusNotFound, "Reminder not found")
+		return
+	case errors.Is(err, services.ErrReminderCapReached):
+		writeError(w, http.StatusConflict, "Daily reminder cap reached")
+		return
+	case errors.Is(err, services.ErrRemindersDisabled):
+		writeError(w, http.StatusConflict, "User has reminder emails disabled")
+		return
+	case errors.Is(err, services.ErrEmailNotVerified):
+		writeError(w, http.StatusConflict, "User email is not verified")
+		return
+	case errors.Is(err, services.ErrCardNotEligible):
+		writeError(w, http.StatusConflict, "Card must be finalized and not archived")
+		return
+	case errors.Is(err, services.ErrGoalCompleted):
+		writeError(w, http.StatusConflict, "Goal already completed")
+		return
+	case errors.Is(err, services.ErrReminderSendFailed):
+		log.Printf("Error resending reminder %s: %v", reminderID, err)
+		writeError(w, http.StatusBadGateway, "Failed to send reminder email")
+		return
+	case err != nil:
+		log.Printf("Error resending reminder %s: %v", reminderID, err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	targetID := result.ReminderID
+	targetUserID := result.UserID
+	h.recordAudit(r, models.AdminAuditEntry{
+		AdminUserID:  user.ID,
+		Action:       AdminActionReminderResend,
+		TargetUserID: &targetUserID,
+		TargetID:     &targetID,
+		Details:      auditDetails(map[string]any{"source_type": result.SourceType, "bypass_cap": req.BypassCap}),
+	})
+
+	writeJSON(w, http.StatusOK, AdminReminderResendResponse{Result: result})
+}
+
+// RunReminders sends due reminders now instead of waiting for the poll
+// interval, optionally as if the clock read as_of.
+func (h *AdminHandler) RunReminders(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if !h.reminderRunEnabled {
+		writeError(w, http.StatusForbidden, "Admin tools are disabled in production")
+		return
+	}
+
+	// The body is optional; an empty body runs with the default limit now.
+	var req AdminReminderRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Limit < 0 || req.Limit > reminderRunMaxLimit {
+		writeError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	result, err := h.reminderService.RunNow(r.Context(), req.AsOf, req.Limit)
+	if errors.Is(err, services.ErrRunAsOfInPast) {
+		writeError(w, http.StatusBadRequest, "as_of cannot be in the past")
+		return
+	}
+	if err != nil {
+		log.Printf("Error running reminders: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	h.recordAudit(r, models.AdminAuditEntry{
+		AdminUserID: user.ID,
+		Action:      AdminActionReminderRun,
+		Details: auditDetails(map[string]any{
+			"limit":    req.Limit,
+			"as_of":    result.AsOf,
+			"sent":     result.Sent,
+			"deferred": result.Deferred,
+			"skipped":  result.Skipped,
+		}),
+	})
+
+	writeJSON(w, http.StatusOK, AdminReminderRunResponse{Result: result})
+}
+
+func (h *AdminHandler) UserReminders(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	report, err := h.reminderService.GetUserReminderReport(r.Context(), userID)
+	if errors.Is(err, services.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading reminder report for user %s: %v", userID, err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	h.recordAudit(r, models.AdminAuditEntry{
+		AdminUserID:  user.ID,
+		Action:       AdminActionViewUserReminder,
+		TargetUserID: &userID,
+	})
+
+	writeJSON(w, http.StatusOK, AdminUserRemindersResponse{Report: report})
+}
+
+// SetAIQuota sets or clears the override that puts a user on the unlimited
+// AI tier.
+func (h *AdminHandler) SetAIQuota(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if h.aiQuota == nil {
+		writeError(w, http.StatusNotFound, "AI quota overrides are disabled")
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	var req AdminAIQuotaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Unlimited == nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	quota, err := h.aiQuota.SetUnlimited(r.Context(), userID, *req.Unlimited)
+	if errors.Is(err, services.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error setting AI quota override for user %s: %v", userID, err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	h.recordAudit(r, models.AdminAuditEntry{
+		AdminUserID:  user.ID,
+		Action:       AdminActionAIQuotaOverride,
+		TargetUserID: &userID,
+		Details:      auditDetails(map[string]any{"unlimited": *req.Unlimited}),
+	})
+
+	writeJSON(w, http.StatusOK, AdminAIQuotaResponse{Quota: quota})
+}
+
+// Search finds cards, items or users for moderation and support. Every query
+// is written to the audit log before it runs; if that fails the search is refused.
+func (h *AdminHandler) Search(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if h.searchService == nil {
+		writeError(w, http.StatusNotFound, "Admin search is disabled")
+		return
+	}
+
+	query := r.URL.Query()
+	params := models.AdminSearchParams{
+		Type:           query.Get("type"),
+		Query:          strings.TrimSpace(query.Get("q")),
+		IncludePrivate: query.Get("include_private") == "1" || query.Get("include_private") == "true",
+	}
+	switch params.Type {
+	case models.AdminSearchCards, models.AdminSearchItems, models.AdminSearchUsers:
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid search type")
+		return
+	}
+	if n := len([]rune(params.Query)); n < adminSearchMinQuery || n > adminSearchMaxQuery {
+		writeError(w, http.StatusBadRequest, "Query must be between 2 and 100 characters")
+		return
+	}
+	limit, ok := readLimit(w, r, adminSearchDefaultLimit, adminSearchMaxLimit)
+	if !ok {
+		return
+	}
+	params.Limit = limit
+	cursor, ok := h.readCursor(w, r, cursorScopeAdminSearch)
+	if !ok {
+		return
+	}
+	params.Offset = cursor.Offset
+
+	entry := models.AdminAuditEntry{
+		AdminUserID: user.ID,
+		Action:      AdminActionContentSearch,
+		Details: auditDetails(map[string]any{
+			"type":            params.Type,
+			"q":               params.Query,
+			"include_private": params.IncludePrivate,
+			"limit":           params.Limit,
+			"offset":          params.Offset,
+		}),
+	}
+	if h.auditService == nil {
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if err := h.auditService.Record(r.Context(), entry); err != nil {
+		log.Printf("Error recording admin search by %s: %v", user.ID, err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	result, err := h.searchService.Search(r.Context(), params)
+	if err != nil {
+		log.Printf("Error running admin search: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	result.Meta = h.offsetMeta(cursorScopeAdminSearch, result.HasMore, params.Offset+params.Limit)
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// SuggestionAnalytics ranks curated suggestions by adoption and completion
+// rate over the last `days` days, to inform pruning the pool.
+func (h *AdminHandler) SuggestionAnalytics(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if h.suggestionStats == nil {
+		writeError(w, http.StatusNotFound, "Suggestion analytics is disabled")
+		return
+	}
+
+	query := r.URL.Query()
+	days := suggestionAnalyticsDefaultDays
+	if daysParam := query.Get("days"); daysParam != "" {
+		parsed, err := strconv.Atoi(daysParam)
+		if err != nil || parsed <= 0 || parsed > suggestionAnalyticsMaxDays {
+			writeError(w, http.StatusBadRequest, "Invalid days")
+			return
+		}
+		days = parsed
+	}
+	limit := suggestionAnalyticsDefaultLimit
+	if limitParam := query.Get("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 || parsed > suggestionAnalyticsMaxLimit {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	result, err := h.suggestionStats.Analytics(r.Context(), since, days, limit)
+	if err != nil {
+		log.Printf("Error getting suggestion analytics: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// recordAudit writes an audit entry. Failures are logged rather than surfaced
+// so a completed admin action is still reported to the caller.
+func (h *AdminHandler) recordAudit(r *http.Request, entry models.AdminAuditEntry) {
+	if h.auditService == nil {
+		return
+	}
+	if err := h.auditService.Record(r.Context(), entry); err != nil {
+		log.Printf("Error recording admin audit %s by %s: %v", entry.Action, entry.AdminUserID, err)
+	}
+}
+
+func auditDetails(details map[string]any) json.RawMessage {
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
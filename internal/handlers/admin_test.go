@@ -0,0 +1,578 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func newAdminResendRequest(t *testing.T, reminderID string, body string, user *models.User) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/reminders/"+reminderID+"/resend", bytes.NewBufferString(body))
+	req.SetPathValue("reminderId", reminderID)
+	if user != nil {
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, user))
+	}
+	return req
+}
+
+func TestAdminHandler_ResendReminder_RequiresAuth(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{})
+	rr := httptest.NewRecorder()
+
+	handler.ResendReminder(rr, newAdminResendRequest(t, uuid.NewString(), "", nil))
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestAdminHandler_ResendReminder_InvalidID(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{})
+	rr := httptest.NewRecorder()
+
+	handler.ResendReminder(rr, newAdminResendRequest(t, "nope", "", &models.User{ID: uuid.New()}))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid reminder ID")
+}
+
+func TestAdminHandler_ResendReminder_Success(t *testing.T) {
+	adminID := uuid.New()
+	reminderID := uuid.New()
+	targetUserID := uuid.New()
+	var gotBypass bool
+	var audited *models.AdminAuditEntry
+
+	handler := NewAdminHandler(&mockReminderService{
+		ResendReminderFunc: func(ctx context.Context, gotID uuid.UUID, bypassCap bool) (*models.ReminderResendResult, error) {
+			if gotID != reminderID {
+				t.Fatalf("expected reminder %v, got %v", reminderID, gotID)
+			}
+			gotBypass = bypassCap
+			return &models.ReminderResendResult{
+				ReminderID: reminderID,
+				UserID:     targetUserID,
+				SourceType: "goal_reminder",
+				Status:     "manual",
+				SentAt:     time.Now(),
+			}, nil
+		},
+	}, &mockAdminAuditService{
+		RecordFunc: func(ctx context.Context, entry models.AdminAuditEntry) error {
+			audited = &entry
+			return nil
+		},
+	})
+	rr := httptest.NewRecorder()
+
+	handler.ResendReminder(rr, newAdminResendRequest(t, reminderID.String(), `{"bypass_cap":true}`, &models.User{ID: adminID}))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !gotBypass {
+		t.Fatal("expected bypass_cap to be passed through")
+	}
+	var resp AdminReminderResendResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Result == nil || resp.Result.Status != "manual" {
+		t.Fatalf("unexpected result: %+v", resp.Result)
+	}
+	if audited == nil {
+		t.Fatal("expected audit entry")
+	}
+	if audited.AdminUserID != adminID || audited.Action != AdminActionReminderResend {
+		t.Fatalf("unexpected audit entry: %+v", audited)
+	}
+	if audited.TargetUserID == nil || *audited.TargetUserID != targetUserID {
+		t.Fatalf("expected target user %v, got %v", targetUserID, audited.TargetUserID)
+	}
+}
+
+func TestAdminHandler_ResendReminder_EmptyBodyEnforcesCap(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{
+		ResendReminderFunc: func(ctx context.Context, reminderID uuid.UUID, bypassCap bool) (*models.ReminderResendResult, error) {
+			if bypassCap {
+				t.Fatal("expected cap to be enforced by default")
+			}
+			return nil, services.ErrReminderCapReached
+		},
+	}, &mockAdminAuditService{})
+	rr := httptest.NewRecorder()
+
+	handler.ResendReminder(rr, newAdminResendRequest(t, uuid.NewString(), "", &models.User{ID: uuid.New()}))
+	assertErrorResponse(t, rr, http.StatusConflict, "Daily reminder cap reached")
+}
+
+func TestAdminHandler_ResendReminder_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		{"not found", services.ErrReminderNotFound, http.StatusNotFound, "Reminder not found"},
+		{"disabled", services.ErrRemindersDisabled, http.StatusConflict, "User has reminder emails disabled"},
+		{"unverified", services.ErrEmailNotVerified, http.StatusConflict, "User email is not verified"},
+		{"card ineligible", services.ErrCardNotEligible, http.StatusConflict, "Card must be finalized and not archived"},
+		{"goal completed", services.ErrGoalCompleted, http.StatusConflict, "Goal already completed"},
+		{"send failed", fmt.Errorf("%w: smtp down", services.ErrReminderSendFailed), http.StatusBadGateway, "Failed to send reminder email"},
+		{"internal", errors.New("db down"), http.StatusInternalServerError, "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audited := false
+			handler := NewAdminHandler(&mockReminderService{
+				ResendReminderFunc: func(ctx context.Context, reminderID uuid.UUID, bypassCap bool) (*models.ReminderResendResult, error) {
+					return nil, tt.err
+				},
+			}, &mockAdminAuditService{
+				RecordFunc: func(ctx context.Context, entry models.AdminAuditEntry) error {
+					audited = true
+					return nil
+				},
+			})
+			rr := httptest.NewRecorder()
+
+			handler.ResendReminder(rr, newAdminResendRequest(t, uuid.NewString(), "{}", &models.User{ID: uuid.New()}))
+			assertErrorResponse(t, rr, tt.wantStatus, tt.wantMsg)
+			if audited {
+				t.Fatal("expected no audit entry for a failed resend")
+			}
+		})
+	}
+}
+
+func TestAdminHandler_UserReminders(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	var audited *models.AdminAuditEntry
+
+	handler := NewAdminHandler(&mockReminderService{
+		GetUserReminderReportFunc: func(ctx context.Context, gotUserID uuid.UUID) (*models.UserReminderReport, error) {
+			if gotUserID != userID {
+				t.Fatalf("expected user %v, got %v", userID, gotUserID)
+			}
+			return &models.UserReminderReport{UserID: userID}, nil
+		},
+	}, &mockAdminAuditService{
+		RecordFunc: func(ctx context.Context, entry models.AdminAuditEntry) error {
+			audited = &entry
+			return errors.New("audit unavailable")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users/"+userID.String()+"/reminders", nil)
+	req.SetPathValue("id", userID.String())
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: adminID}))
+	rr := httptest.NewRecorder()
+
+	handler.UserReminders(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 even when audit fails, got %d", rr.Code)
+	}
+	if audited == nil || audited.Action != AdminActionViewUserReminder {
+		t.Fatalf("unexpected audit entry: %+v", audited)
+	}
+}
+
+func TestAdminHandler_UserReminders_NotFound(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{
+		GetUserReminderReportFunc: func(ctx context.Context, userID uuid.UUID) (*models.UserReminderReport, error) {
+			return nil, services.ErrUserNotFound
+		},
+	}, &mockAdminAuditService{})
+
+	userID := uuid.NewString()
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users/"+userID+"/reminders", nil)
+	req.SetPathValue("id", userID)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.UserReminders(rr, req)
+	assertErrorResponse(t, rr, http.StatusNotFound, "User not found")
+}
+
+func newAdminSearchRequest(rawQuery string, user *models.User) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/search?"+rawQuery, nil)
+	if user != nil {
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, user))
+	}
+	return req
+}
+
+func newAdminRunRequest(body string, user *models.User) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/reminders/run", bytes.NewBufferString(body))
+	if user != nil {
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, user))
+	}
+	return req
+}
+
+func TestAdminHandler_RunReminders_RefusedWhenDisabled(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{
+		RunNowFunc: func(ctx context.Context, asOf *time.Time, limit int) (*models.ReminderRunResult, error) {
+			t.Fatal("expected no run while admin tools are disabled")
+			return nil, nil
+		},
+	}, &mockAdminAuditService{})
+	rr := httptest.NewRecorder()
+
+	handler.RunReminders(rr, newAdminRunRequest("", &models.User{ID: uuid.New()}))
+	assertErrorResponse(t, rr, http.StatusForbidden, "Admin tools are disabled in production")
+}
+
+func TestAdminHandler_RunReminders_PassesOverridesAndAudits(t *testing.T) {
+	adminID := uuid.New()
+	asOf := time.Date(2030, time.March, 1, 9, 0, 0, 0, time.UTC)
+	var gotAsOf *time.Time
+	var gotLimit int
+	var audited *models.AdminAuditEntry
+	handler := NewAdminHandler(&mockReminderService{
+		RunNowFunc: func(ctx context.Context, asOf *time.Time, limit int) (*models.ReminderRunResult, error) {
+			gotAsOf, gotLimit = asOf, limit
+			return &models.ReminderRunResult{AsOf: *asOf, Sent: 3, Deferred: 1, Skipped: 2}, nil
+		},
+	}, &mockAdminAuditService{
+		RecordFunc: func(ctx context.Context, entry models.AdminAuditEntry) error {
+			audited = &entry
+			return nil
+		},
+	})
+	handler.SetReminderRunEnabled(true)
+	rr := httptest.NewRecorder()
+
+	handler.RunReminders(rr, newAdminRunRequest(`{"limit":100,"as_of":"2030-03-01T09:00:00Z"}`, &models.User{ID: adminID}))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if gotLimit != 100 || gotAsOf == nil || !gotAsOf.Equal(asOf) {
+		t.Fatalf("expected limit 100 as of %v, got %d %v", asOf, gotLimit, gotAsOf)
+	}
+	var resp AdminReminderRunResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Result == nil || resp.Result.Sent != 3 || resp.Result.Deferred != 1 || resp.Result.Skipped != 2 {
+		t.Fatalf("unexpected result: %+v", resp.Result)
+	}
+	if audited == nil || audited.AdminUserID != adminID || audited.Action != AdminActionReminderRun {
+		t.Fatalf("unexpected audit entry: %+v", audited)
+	}
+}
+
+func TestAdminHandler_RunReminders_Validation(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{
+		RunNowFunc: func(ctx context.Context, asOf *time.Time, limit int) (*models.ReminderRunResult, error) {
+			return nil, services.ErrRunAsOfInPast
+		},
+	}, &mockAdminAuditService{})
+	handler.SetReminderRunEnabled(true)
+	user := &models.User{ID: uuid.New()}
+
+	tests := []struct {
+		body    string
+		message string
+	}{
+		{`{"limit":`, "Invalid request body"},
+		{`{"limit":-1}`, "Invalid limit"},
+		{`{"limit":501}`, "Invalid limit"},
+		{`{"as_of":"2020-01-01T00:00:00Z"}`, "as_of cannot be in the past"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		handler.RunReminders(rr, newAdminRunRequest(tt.body, user))
+		assertErrorResponse(t, rr, http.StatusBadRequest, tt.message)
+	}
+}
+
+func TestAdminHandler_Search_DisabledWithoutService(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{})
+	rr := httptest.NewRecorder()
+
+	handler.Search(rr, newAdminSearchRequest("type=users&q=bob", &models.User{ID: uuid.New()}))
+	assertErrorResponse(t, rr, http.StatusNotFound, "Admin search is disabled")
+}
+
+func TestAdminHandler_Search_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"missing type", "q=bob", "Invalid search type"},
+		{"unknown type", "type=emails&q=bob", "Invalid search type"},
+		{"short query", "type=users&q=b", "Query must be between 2 and 100 characters"},
+		{"bad limit", "type=users&q=bob&limit=0", "Invalid limit"},
+		{"bad cursor", "type=users&q=bob&cursor=forged", "Invalid cursor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{
+				RecordFunc: func(ctx context.Context, entry models.AdminAuditEntry) error {
+					t.Fatal("unexpected audit for invalid search")
+					return nil
+				},
+			})
+			handler.SetSearchService(&mockAdminSearchService{})
+			rr := httptest.NewRecorder()
+
+			handler.Search(rr, newAdminSearchRequest(tt.query, &models.User{ID: uuid.New()}))
+			assertErrorResponse(t, rr, http.StatusBadRequest, tt.want)
+		})
+	}
+}
+
+func TestAdminHandler_Search_AuditsQueryAndOverride(t *testing.T) {
+	adminID := uuid.New()
+	var audited *models.AdminAuditEntry
+	var gotParams models.AdminSearchParams
+
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{
+		RecordFunc: func(ctx context.Context, entry models.AdminAuditEntry) error {
+			audited = &entry
+			return nil
+		},
+	})
+	handler.SetSearchService(&mockAdminSearchService{
+		SearchFunc: func(ctx context.Context, params models.AdminSearchParams) (*models.AdminSearchResult, error) {
+			if audited == nil {
+				t.Fatal("expected audit before search runs")
+			}
+			gotParams = params
+			return &models.AdminSearchResult{Items: []models.AdminSearchHit{{Type: models.AdminSearchItems, Text: "bad words"}}}, nil
+		},
+	})
+	rr := httptest.NewRecorder()
+
+	// Only the signed cursor sets the offset; a raw offset parameter is ignored.
+	cursor := defaultCursorCodec.EncodeOffset(cursorScopeAdminSearch, 10)
+	handler.Search(rr, newAdminSearchRequest("type=items&q=+bad+&include_private=1&limit=5&offset=9000&cursor="+cursor, &models.User{ID: adminID}))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if gotParams.Query != "bad" || !gotParams.IncludePrivate || gotParams.Limit != 5 || gotParams.Offset != 10 {
+		t.Fatalf("unexpected params: %+v", gotParams)
+	}
+	if audited.AdminUserID != adminID || audited.Action != AdminActionContentSearch {
+		t.Fatalf("unexpected audit entry: %+v", audited)
+	}
+	var details map[string]any
+	if err := json.Unmarshal(audited.Details, &details); err != nil {
+		t.Fatalf("invalid audit details: %v", err)
+	}
+	if details["q"] != "bad" || details["type"] != "items" || details["include_private"] != true {
+		t.Fatalf("unexpected audit details: %v", details)
+	}
+
+	var resp map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if items, ok := resp["items"].([]any); !ok || len(items) != 1 || resp["has_more"] != false {
+		t.Fatalf("expected the page envelope, got %v", resp)
+	}
+}
+
+func TestAdminHandler_Search_RefusesWhenAuditFails(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{
+		RecordFunc: func(ctx context.Context, entry models.AdminAuditEntry) error {
+			return errors.New("db down")
+		},
+	})
+	handler.SetSearchService(&mockAdminSearchService{
+		SearchFunc: func(ctx context.Context, params models.AdminSearchParams) (*models.AdminSearchResult, error) {
+			t.Fatal("search must not run without an audit entry")
+			return nil, nil
+		},
+	})
+	rr := httptest.NewRecorder()
+
+	handler.Search(rr, newAdminSearchRequest("type=users&q=bob", &models.User{ID: uuid.New()}))
+	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+}
+
+func TestAdminHandler_Search_ServiceError(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{})
+	handler.SetSearchService(&mockAdminSearchService{
+		SearchFunc: func(ctx context.Context, params models.AdminSearchParams) (*models.AdminSearchResult, error) {
+			return nil, errors.New("boom")
+		},
+	})
+	rr := httptest.NewRecorder()
+
+	handler.Search(rr, newAdminSearchRequest("type=cards&q=year", &models.User{ID: uuid.New()}))
+	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+}
+
+func newSuggestionAnalyticsRequest(rawQuery string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/suggestions/analytics?"+rawQuery, nil)
+	return req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
+}
+
+func TestAdminHandler_SuggestionAnalytics_DisabledWithoutService(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{})
+	rr := httptest.NewRecorder()
+
+	handler.SuggestionAnalytics(rr, newSuggestionAnalyticsRequest(""))
+	assertErrorResponse(t, rr, http.StatusNotFound, "Suggestion analytics is disabled")
+}
+
+func TestAdminHandler_SuggestionAnalytics_Validation(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"days=0", "Invalid days"},
+		{"days=1000", "Invalid days"},
+		{"limit=abc", "Invalid limit"},
+		{"limit=51", "Invalid limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{})
+			handler.SetSuggestionAnalytics(&mockSuggestionAnalyticsService{})
+			rr := httptest.NewRecorder()
+
+			handler.SuggestionAnalytics(rr, newSuggestionAnalyticsRequest(tt.query))
+			assertErrorResponse(t, rr, http.StatusBadRequest, tt.want)
+		})
+	}
+}
+
+func TestAdminHandler_SuggestionAnalytics_Success(t *testing.T) {
+	var gotDays, gotLimit int
+	var gotSince time.Time
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{})
+	handler.SetSuggestionAnalytics(&mockSuggestionAnalyticsService{
+		AnalyticsFunc: func(ctx context.Context, since time.Time, windowDays, limit int) (*models.SuggestionAnalytics, error) {
+			gotSince, gotDays, gotLimit = since, windowDays, limit
+			return &models.SuggestionAnalytics{WindowDays: windowDays, MostAdopted: []models.SuggestionStats{{Content: "Run a 5K", AddedCount: 4}}}, nil
+		},
+	})
+	rr := httptest.NewRecorder()
+
+	handler.SuggestionAnalytics(rr, newSuggestionAnalyticsRequest("days=30&limit=5"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if gotDays != 30 || gotLimit != 5 {
+		t.Fatalf("expected days=30 limit=5, got %d %d", gotDays, gotLimit)
+	}
+	if since := time.Since(gotSince); since < 29*24*time.Hour || since > 31*24*time.Hour {
+		t.Fatalf("expected window start 30 days ago, got %v", gotSince)
+	}
+	var body models.SuggestionAnalytics
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.MostAdopted) != 1 || body.MostAdopted[0].AddedCount != 4 {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestAdminHandler_SuggestionAnalytics_ServiceError(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{})
+	handler.SetSuggestionAnalytics(&mockSuggestionAnalyticsService{
+		AnalyticsFunc: func(ctx context.Context, since time.Time, windowDays, limit int) (*models.SuggestionAnalytics, error) {
+			return nil, errors.New("boom")
+		},
+	})
+	rr := httptest.NewRecorder()
+
+	handler.SuggestionAnalytics(rr, newSuggestionAnalyticsRequest(""))
+	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+}
+
+func newAdminAIQuotaRequest(userID, body string, user *models.User) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/users/"+userID+"/ai-quota", bytes.NewBufferString(body))
+	req.SetPathValue("id", userID)
+	if user != nil {
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, user))
+	}
+	return req
+}
+
+func TestAdminHandler_SetAIQuota_Validation(t *testing.T) {
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{})
+	admin := &models.User{ID: uuid.New()}
+
+	rr := httptest.NewRecorder()
+	handler.SetAIQuota(rr, newAdminAIQuotaRequest(uuid.NewString(), `{"unlimited":true}`, admin))
+	assertErrorResponse(t, rr, http.StatusNotFound, "AI quota overrides are disabled")
+
+	handler.SetAIQuotaService(&mockAIQuotaAdminService{
+		SetUnlimitedFunc: func(ctx context.Context, userID uuid.UUID, unlimited bool) (*models.AIQuota, error) {
+			return nil, services.ErrUserNotFound
+		},
+	})
+	cases := []struct {
+		userID  string
+		body    string
+		status  int
+		message string
+	}{
+		{userID: "nope", body: `{"unlimited":true}`, status: http.StatusBadRequest, message: "Invalid user ID"},
+		{userID: uuid.NewString(), body: `{}`, status: http.StatusBadRequest, message: "Invalid request body"},
+		{userID: uuid.NewString(), body: `{"unlimited":"yes"}`, status: http.StatusBadRequest, message: "Invalid request body"},
+		{userID: uuid.NewString(), body: `{"unlimited":true}`, status: http.StatusNotFound, message: "User not found"},
+	}
+	for _, tc := range cases {
+		rr := httptest.NewRecorder()
+		handler.SetAIQuota(rr, newAdminAIQuotaRequest(tc.userID, tc.body, admin))
+		assertErrorResponse(t, rr, tc.status, tc.message)
+	}
+}
+
+func TestAdminHandler_SetAIQuota_OverridesAndAudits(t *testing.T) {
+	adminID := uuid.New()
+	targetID := uuid.New()
+	var gotUnlimited bool
+	var audited *models.AdminAuditEntry
+	handler := NewAdminHandler(&mockReminderService{}, &mockAdminAuditService{
+		RecordFunc: func(ctx context.Context, entry models.AdminAuditEntry) error {
+			audited = &entry
+			return nil
+		},
+	})
+	handler.SetAIQuotaService(&mockAIQuotaAdminService{
+		SetUnlimitedFunc: func(ctx context.Context, userID uuid.UUID, unlimited bool) (*models.AIQuota, error) {
+			if userID != targetID {
+				t.Fatalf("expected user %v, got %v", targetID, userID)
+			}
+			gotUnlimited = unlimited
+			return &models.AIQuota{Tier: models.AIQuotaTierUnlimited, Used: 61}, nil
+		},
+	})
+	rr := httptest.NewRecorder()
+
+	handler.SetAIQuota(rr, newAdminAIQuotaRequest(targetID.String(), `{"unlimited":true}`, &models.User{ID: adminID}))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var response AdminAIQuotaResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !gotUnlimited || response.Quota == nil || response.Quota.Tier != models.AIQuotaTierUnlimited {
+		t.Fatalf("expected the user moved to the unlimited tier, got %+v", response.Quota)
+	}
+	if audited == nil || audited.Action != AdminActionAIQuotaOverride || audited.AdminUserID != adminID ||
+		audited.TargetUserID == nil || *audited.TargetUserID != targetID || string(audited.Details) != `{"unlimited":true}` {
+		t.Fatalf("unexpected audit entry: %+v", audited)
+	}
+}
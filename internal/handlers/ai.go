@@ -4,21 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services/ai"
 )
 
 type AIService interface {
 	GenerateGoals(ctx context.Context, userID uuid.UUID, prompt ai.GoalPrompt) ([]string, ai.UsageStats, error)
 	GenerateGuideGoals(ctx context.Context, userID uuid.UUID, prompt ai.GuidePrompt) ([]string, ai.UsageStats, error)
-	ConsumeUnverifiedFreeGeneration(ctx context.Context, userID uuid.UUID) (int, error)
-	RefundUnverifiedFreeGeneration(ctx context.Context, userID uuid.UUID) (bool, error)
+	ConsumeGeneration(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error)
+	RefundGeneration(ctx context.Context, userID uuid.UUID) (bool, error)
+	Quota(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error)
 }
 
+// ErrorCodeQuotaExhausted marks an AI request refused because the user's
+// monthly allowance is spent, so clients can say when it resets.
+const ErrorCodeQuotaExhausted = "quota_exhausted"
+
 type AIHandler struct {
 	service AIService
 }
@@ -36,17 +44,113 @@ type GenerateRequest struct {
 	Count      int    `json:"count"`
 }
 
+// GenerateResponse carries free_remaining while the user is on the
+// unverified trial, and the quota the request was counted against.
 type GenerateResponse struct {
-	Goals         []string `json:"goals"`
-	FreeRemaining *int     `json:"free_remaining,omitempty"`
+	Goals         []string        `json:"goals"`
+	FreeRemaining *int            `json:"free_remaining,omitempty"`
+	Quota         *models.AIQuota `json:"quota,omitempty"`
 }
 
 type GenerateErrorResponse struct {
-	Error         string `json:"error"`
-	FreeRemaining *int   `json:"free_remaining,omitempty"`
+	Error         string          `json:"error"`
+	Code          string          `json:"code,omitempty"`
+	FreeRemaining *int            `json:"free_remaining,omitempty"`
+	Quota         *models.AIQuota `json:"quota,omitempty"`
+}
+
+type AIQuotaResponse struct {
+	Quota *models.AIQuota `json:"quota"`
+}
+
+// Quota reports the caller's AI tier, how many generations they have used
+// and have left, and when a monthly allowance resets.
+func (h *AIHandler) Quota(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	quota, err := h.service.Quota(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error loading AI quota: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "AI usage tracking is temporarily unavailable. Please try again later.")
+		return
+	}
+	writeJSON(w, http.StatusOK, AIQuotaResponse{Quota: quota})
+}
+
+// consumeGeneration counts the request against the user's AI quota. When the
+// quota refuses it, the error response is written and nil is returned.
+func (h *AIHandler) consumeGeneration(w http.ResponseWriter, r *http.Request, userID uuid.UUID) *models.AIQuota {
+	quota, err := h.service.ConsumeGeneration(r.Context(), userID)
+	switch {
+	case err == nil:
+		return quota
+	case errors.Is(err, ai.ErrEmailVerificationRequired):
+		zero := 0
+		writeJSON(w, http.StatusForbidden, GenerateErrorResponse{
+			Error:         "You've used your 5 free AI generations. Verify your email to keep using AI.",
+			FreeRemaining: &zero,
+			Quota:         quota,
+		})
+	case errors.Is(err, ai.ErrAIQuotaExhausted):
+		msg := "You've used all of this month's free AI generations."
+		if quota != nil && quota.Limit != nil && quota.ResetsAt != nil {
+			msg = fmt.Sprintf("You've used all %d of this month's free AI generations. They reset on %s.", *quota.Limit, quota.ResetsAt.Format("January 2"))
+		}
+		writeJSON(w, http.StatusForbidden, GenerateErrorResponse{
+			Error: msg,
+			Code:  ErrorCodeQuotaExhausted,
+			Quota: quota,
+		})
+	default:
+		writeError(w, http.StatusServiceUnavailable, "AI usage tracking is temporarily unavailable. Please try again later.")
+	}
+	return nil
+}
+
+// refundGeneration gives back the generation of a request that delivered no
+// goals and updates quota to match. It uses its own context, since the
+// request's may already be canceled.
+func (h *AIHandler) refundGeneration(userID uuid.UUID, quota *models.AIQuota) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	refunded, err := h.service.RefundGeneration(ctx, userID)
+	if err != nil || !refunded || quota == nil {
+		return
+	}
+	quota.Used = max(quota.Used-1, 0)
+	if quota.Remaining != nil {
+		remaining := *quota.Remaining + 1
+		quota.Remaining = &remaining
+	}
+}
+
+// trialRemaining is the free_remaining value for responses: the generations
+// left before verification, only while the user is on the trial.
+func trialRemaining(quota *models.AIQuota) *int {
+	if quota == nil || quota.Tier != models.AIQuotaTierTrial || quota.Remaining == nil {
+		return nil
+	}
+	remaining := *quota.Remaining
+	return &remaining
+}
+
+// refundableAIError reports whether a failed generation should not cost the
+// user a generation: the provider, not the request, was at fault.
+func refundableAIError(err error) bool {
+	return errors.Is(err, ai.ErrAIProviderUnavailable) || errors.Is(err, ai.ErrAINotConfigured) || errors.Is(err, ai.ErrRateLimitExceeded)
 }
 
 func (h *AIHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
 	var req GenerateRequest
 	r.Body = http.MaxBytesReader(w, r.Body, 8*1024)
 	dec := json.NewDecoder(r.Body)
@@ -98,37 +202,14 @@ func (h *AIHandler) Generate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user := GetUserFromContext(r.Context())
-	if user == nil {
-		writeError(w, http.StatusUnauthorized, "Authentication required")
+	quota := h.consumeGeneration(w, r, user.ID)
+	if quota == nil {
 		return
 	}
-
-	var freeRemaining *int
-	freeRemainingValue := 0
-	consumedFree := false
-	if !user.EmailVerified {
-		remaining, err := h.service.ConsumeUnverifiedFreeGeneration(r.Context(), user.ID)
-		if err != nil {
-			switch {
-			case errors.Is(err, ai.ErrEmailVerificationRequired):
-				zero := 0
-				writeJSON(w, http.StatusForbidden, GenerateErrorResponse{
-					Error:         "You've used your 5 free AI generations. Verify your email to keep using AI.",
-					FreeRemaining: &zero,
-				})
-				return
-			case errors.Is(err, ai.ErrAIUsageTrackingUnavailable):
-				writeError(w, http.StatusServiceUnavailable, "AI usage tracking is temporarily unavailable. Please try again later.")
-				return
-			default:
-				writeError(w, http.StatusServiceUnavailable, "AI usage tracking is temporarily unavailable. Please try again later.")
-				return
-			}
-		}
-		freeRemainingValue = remaining
-		freeRemaining = &freeRemainingValue
-		consumedFree = true
+	if clientGone(r) {
+		// The client left before the provider call; don't make it.
+		h.refundGeneration(user.ID, quota)
+		return
 	}
 
 	prompt := ai.GoalPrompt{
@@ -140,8 +221,15 @@ func (h *AIHandler) Generate(w http.ResponseWriter, r *http.Request) {
 		Count:      req.Count,
 	}
 
-	goals, _, err := h.service.GenerateGoals(r.Context(), user.ID, prompt)
+	ctx := ai.WithQuotaTier(r.Context(), quota.Tier)
+	goals, _, err := h.service.GenerateGoals(ctx, user.ID, prompt)
 	if err != nil {
+		if clientGone(r) {
+			// The client disconnected mid-call and won't see the goals.
+			h.refundGeneration(user.ID, quota)
+			return
+		}
+
 		status := http.StatusInternalServerError
 		msg := "An unexpected error occurred."
 
@@ -160,23 +248,21 @@ func (h *AIHandler) Generate(w http.ResponseWriter, r *http.Request) {
 			msg = "The AI service is currently down. Please try again later."
 		}
 
-		if consumedFree && (errors.Is(err, ai.ErrAIProviderUnavailable) || errors.Is(err, ai.ErrAINotConfigured) || errors.Is(err, ai.ErrRateLimitExceeded)) {
-			refundCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			defer cancel()
-			if refunded, refundErr := h.service.RefundUnverifiedFreeGeneration(refundCtx, user.ID); refundErr == nil && refunded && freeRemaining != nil {
-				freeRemainingValue++
-			}
+		if refundableAIError(err) {
+			h.refundGeneration(user.ID, quota)
 		}
 
 		writeJSON(w, status, GenerateErrorResponse{
 			Error:         msg,
-			FreeRemaining: freeRemaining,
+			FreeRemaining: trialRemaining(quota),
+			Quota:         quota,
 		})
 		return
 	}
 
 	writeJSON(w, http.StatusOK, GenerateResponse{
 		Goals:         goals,
-		FreeRemaining: freeRemaining,
+		FreeRemaining: trialRemaining(quota),
+		Quota:         quota,
 	})
 }
@@ -1,13 +1,12 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"strings"
-	"time"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services/ai"
 )
 
@@ -20,16 +19,20 @@ type GuideRequest struct {
 }
 
 type GuideResponse struct {
-	Goals         []string `json:"goals"`
-	FreeRemaining *int     `json:"free_remaining,omitempty"`
+	Goals         []string        `json:"goals"`
+	FreeRemaining *int            `json:"free_remaining,omitempty"`
+	Quota         *models.AIQuota `json:"quota,omitempty"`
 }
 
-type GuideErrorResponse struct {
-	Error         string `json:"error"`
-	FreeRemaining *int   `json:"free_remaining,omitempty"`
-}
+type GuideErrorResponse = GenerateErrorResponse
 
 func (h *AIHandler) Guide(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
 	var req GuideRequest
 	r.Body = http.MaxBytesReader(w, r.Body, 8*1024)
 	dec := json.NewDecoder(r.Body)
@@ -79,37 +82,14 @@ func (h *AIHandler) Guide(w http.ResponseWriter, r *http.Request) {
 
 	avoid := normalizeGuideAvoidList(req.Avoid)
 
-	user := GetUserFromContext(r.Context())
-	if user == nil {
-		writeError(w, http.StatusUnauthorized, "Authentication required")
+	quota := h.consumeGeneration(w, r, user.ID)
+	if quota == nil {
 		return
 	}
-
-	var freeRemaining *int
-	freeRemainingValue := 0
-	consumedFree := false
-	if !user.EmailVerified {
-		remaining, err := h.service.ConsumeUnverifiedFreeGeneration(r.Context(), user.ID)
-		if err != nil {
-			switch {
-			case errors.Is(err, ai.ErrEmailVerificationRequired):
-				zero := 0
-				writeJSON(w, http.StatusForbidden, GuideErrorResponse{
-					Error:         "You've used your 5 free AI generations. Verify your email to keep using AI.",
-					FreeRemaining: &zero,
-				})
-				return
-			case errors.Is(err, ai.ErrAIUsageTrackingUnavailable):
-				writeError(w, http.StatusServiceUnavailable, "AI usage tracking is temporarily unavailable. Please try again later.")
-				return
-			default:
-				writeError(w, http.StatusServiceUnavailable, "AI usage tracking is temporarily unavailable. Please try again later.")
-				return
-			}
-		}
-		freeRemainingValue = remaining
-		freeRemaining = &freeRemainingValue
-		consumedFree = true
+	if clientGone(r) {
+		// The client left before the provider call; don't make it.
+		h.refundGeneration(user.ID, quota)
+		return
 	}
 
 	prompt := ai.GuidePrompt{
@@ -120,8 +100,15 @@ func (h *AIHandler) Guide(w http.ResponseWriter, r *http.Request) {
 		Avoid:       avoid,
 	}
 
-	goals, _, err := h.service.GenerateGuideGoals(r.Context(), user.ID, prompt)
+	ctx := ai.WithQuotaTier(r.Context(), quota.Tier)
+	goals, _, err := h.service.GenerateGuideGoals(ctx, user.ID, prompt)
 	if err != nil {
+		if clientGone(r) {
+			// The client disconnected mid-call and won't see the goals.
+			h.refundGeneration(user.ID, quota)
+			return
+		}
+
 		status := http.StatusInternalServerError
 		msg := "An unexpected error occurred."
 
@@ -143,24 +130,22 @@ func (h *AIHandler) Guide(w http.ResponseWriter, r *http.Request) {
 			msg = "The AI service is currently down. Please try again later."
 		}
 
-		if consumedFree && (errors.Is(err, ai.ErrAIProviderUnavailable) || errors.Is(err, ai.ErrAINotConfigured) || errors.Is(err, ai.ErrRateLimitExceeded)) {
-			refundCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			defer cancel()
-			if refunded, refundErr := h.service.RefundUnverifiedFreeGeneration(refundCtx, user.ID); refundErr == nil && refunded && freeRemaining != nil {
-				freeRemainingValue++
-			}
+		if refundableAIError(err) {
+			h.refundGeneration(user.ID, quota)
 		}
 
 		writeJSON(w, status, GuideErrorResponse{
 			Error:         msg,
-			FreeRemaining: freeRemaining,
+			FreeRemaining: trialRemaining(quota),
+			Quota:         quota,
 		})
 		return
 	}
 
 	writeJSON(w, http.StatusOK, GuideResponse{
 		Goals:         goals,
-		FreeRemaining: freeRemaining,
+		FreeRemaining: trialRemaining(quota),
+		Quota:         quota,
 	})
 }
 
@@ -45,8 +45,8 @@ func TestGuide(t *testing.T) {
 			user:        &models.User{ID: uuid.New(), EmailVerified: false},
 			mockSetup: func(t *testing.T) *MockAIService {
 				return &MockAIService{
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						return 4, nil
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						return trialQuota(4), nil
 					},
 					GenerateGuideFunc: func(ctx context.Context, userID uuid.UUID, prompt ai.GuidePrompt) ([]string, ai.UsageStats, error) {
 						if prompt.Count != 3 {
@@ -71,8 +71,8 @@ func TestGuide(t *testing.T) {
 			user:        &models.User{ID: uuid.New(), EmailVerified: false},
 			mockSetup: func(t *testing.T) *MockAIService {
 				return &MockAIService{
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						return 4, nil
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						return trialQuota(4), nil
 					},
 					GenerateGuideFunc: func(ctx context.Context, userID uuid.UUID, prompt ai.GuidePrompt) ([]string, ai.UsageStats, error) {
 						if prompt.Count != 5 {
@@ -169,9 +169,9 @@ func TestGuide(t *testing.T) {
 						t.Fatal("GenerateGuideGoals should not be called when unauthorized")
 						return nil, ai.UsageStats{}, nil
 					},
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						t.Fatal("ConsumeUnverifiedFreeGeneration should not be called when unauthorized")
-						return 0, nil
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						t.Fatal("ConsumeGeneration should not be called when unauthorized")
+						return nil, nil
 					},
 				}
 			},
@@ -184,8 +184,8 @@ func TestGuide(t *testing.T) {
 			user:        &models.User{ID: uuid.New(), EmailVerified: false},
 			mockSetup: func(t *testing.T) *MockAIService {
 				return &MockAIService{
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						return 0, ai.ErrEmailVerificationRequired
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						return trialQuota(0), ai.ErrEmailVerificationRequired
 					},
 					GenerateGuideFunc: func(ctx context.Context, userID uuid.UUID, prompt ai.GuidePrompt) ([]string, ai.UsageStats, error) {
 						t.Fatal("GenerateGuideGoals should not be called when quota is exhausted")
@@ -204,8 +204,8 @@ func TestGuide(t *testing.T) {
 			user:        &models.User{ID: uuid.New(), EmailVerified: false},
 			mockSetup: func(t *testing.T) *MockAIService {
 				return &MockAIService{
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						return 4, nil
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						return trialQuota(4), nil
 					},
 					RefundFunc: func(ctx context.Context, userID uuid.UUID) (bool, error) {
 						return true, nil
@@ -305,19 +305,19 @@ func TestGuide(t *testing.T) {
 				t.Fatalf("expected GenerateGuideGoals calls %d, got %d", tt.guideCalls, mockService.GenerateGuideCalls)
 			}
 			if tt.consumeCalls > 0 && mockService.ConsumeCalls != tt.consumeCalls {
-				t.Fatalf("expected ConsumeUnverifiedFreeGeneration calls %d, got %d", tt.consumeCalls, mockService.ConsumeCalls)
+				t.Fatalf("expected ConsumeGeneration calls %d, got %d", tt.consumeCalls, mockService.ConsumeCalls)
 			}
 			if tt.refundCalls > 0 && mockService.RefundCalls != tt.refundCalls {
-				t.Fatalf("expected RefundUnverifiedFreeGeneration calls %d, got %d", tt.refundCalls, mockService.RefundCalls)
+				t.Fatalf("expected RefundGeneration calls %d, got %d", tt.refundCalls, mockService.RefundCalls)
 			}
 			if tt.guideCalls == 0 && mockService.GenerateGuideCalls != 0 {
 				t.Fatalf("expected no GenerateGuideGoals calls, got %d", mockService.GenerateGuideCalls)
 			}
 			if tt.consumeCalls == 0 && mockService.ConsumeCalls != 0 {
-				t.Fatalf("expected no ConsumeUnverifiedFreeGeneration calls, got %d", mockService.ConsumeCalls)
+				t.Fatalf("expected no ConsumeGeneration calls, got %d", mockService.ConsumeCalls)
 			}
 			if tt.refundCalls == 0 && mockService.RefundCalls != 0 {
-				t.Fatalf("expected no RefundUnverifiedFreeGeneration calls, got %d", mockService.RefundCalls)
+				t.Fatalf("expected no RefundGeneration calls, got %d", mockService.RefundCalls)
 			}
 		})
 	}
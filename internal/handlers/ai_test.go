@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -20,8 +21,9 @@ import (
 type MockAIService struct {
 	GenerateGoalsFunc  func(ctx context.Context, userID uuid.UUID, prompt ai.GoalPrompt) ([]string, ai.UsageStats, error)
 	GenerateGuideFunc  func(ctx context.Context, userID uuid.UUID, prompt ai.GuidePrompt) ([]string, ai.UsageStats, error)
-	ConsumeFunc        func(ctx context.Context, userID uuid.UUID) (int, error)
+	ConsumeFunc        func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error)
 	RefundFunc         func(ctx context.Context, userID uuid.UUID) (bool, error)
+	QuotaFunc          func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error)
 	GenerateCalls      int
 	GenerateGuideCalls int
 	ConsumeCalls       int
@@ -44,15 +46,17 @@ func (m *MockAIService) GenerateGuideGoals(ctx context.Context, userID uuid.UUID
 	return m.GenerateGuideFunc(ctx, userID, prompt)
 }
 
-func (m *MockAIService) ConsumeUnverifiedFreeGeneration(ctx context.Context, userID uuid.UUID) (int, error) {
+// ConsumeGeneration admits every request on the unlimited tier unless
+// ConsumeFunc is set.
+func (m *MockAIService) ConsumeGeneration(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
 	m.ConsumeCalls++
 	if m.ConsumeFunc == nil {
-		return 0, nil
+		return &models.AIQuota{Tier: models.AIQuotaTierUnlimited}, nil
 	}
 	return m.ConsumeFunc(ctx, userID)
 }
 
-func (m *MockAIService) RefundUnverifiedFreeGeneration(ctx context.Context, userID uuid.UUID) (bool, error) {
+func (m *MockAIService) RefundGeneration(ctx context.Context, userID uuid.UUID) (bool, error) {
 	m.RefundCalls++
 	if m.RefundFunc == nil {
 		return false, nil
@@ -60,6 +64,20 @@ func (m *MockAIService) RefundUnverifiedFreeGeneration(ctx context.Context, user
 	return m.RefundFunc(ctx, userID)
 }
 
+func (m *MockAIService) Quota(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+	if m.QuotaFunc == nil {
+		return nil, errors.New("QuotaFunc not set")
+	}
+	return m.QuotaFunc(ctx, userID)
+}
+
+// trialQuota is the quota of an unverified user with remaining free
+// generations left.
+func trialQuota(remaining int) *models.AIQuota {
+	limit := 5
+	return &models.AIQuota{Tier: models.AIQuotaTierTrial, Used: limit - remaining, Limit: &limit, Remaining: &remaining}
+}
+
 func TestGenerate(t *testing.T) {
 	// Setup common variables
 	validBody := map[string]any{
@@ -97,6 +115,7 @@ func TestGenerate(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedGoals:  []string{"Goal 1", "Goal 2"},
 			generateCalls:  1,
+			consumeCalls:   1,
 		},
 		{
 			name:        "Success (Count defaults to 24)",
@@ -115,6 +134,7 @@ func TestGenerate(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedGoals:  []string{"Goal 1", "Goal 2"},
 			generateCalls:  1,
+			consumeCalls:   1,
 		},
 		{
 			name: "Success (Count passed through)",
@@ -140,6 +160,7 @@ func TestGenerate(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedGoals:  []string{"Goal 1", "Goal 2"},
 			generateCalls:  1,
+			consumeCalls:   1,
 		},
 		{
 			name: "Invalid Input - Count",
@@ -167,8 +188,8 @@ func TestGenerate(t *testing.T) {
 			user:        &models.User{ID: uuid.New(), EmailVerified: false},
 			mockSetup: func() *MockAIService {
 				return &MockAIService{
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						return 4, nil
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						return trialQuota(4), nil
 					},
 					GenerateGoalsFunc: func(ctx context.Context, userID uuid.UUID, prompt ai.GoalPrompt) ([]string, ai.UsageStats, error) {
 						return []string{"Goal 1", "Goal 2"}, ai.UsageStats{}, nil
@@ -187,8 +208,8 @@ func TestGenerate(t *testing.T) {
 			user:        &models.User{ID: uuid.New(), EmailVerified: false},
 			mockSetup: func() *MockAIService {
 				return &MockAIService{
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						return 4, nil
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						return trialQuota(4), nil
 					},
 					RefundFunc: func(ctx context.Context, userID uuid.UUID) (bool, error) {
 						return true, nil
@@ -211,8 +232,8 @@ func TestGenerate(t *testing.T) {
 			user:        &models.User{ID: uuid.New(), EmailVerified: false},
 			mockSetup: func() *MockAIService {
 				return &MockAIService{
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						return 0, ai.ErrEmailVerificationRequired
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						return trialQuota(0), ai.ErrEmailVerificationRequired
 					},
 					GenerateGoalsFunc: func(ctx context.Context, userID uuid.UUID, prompt ai.GoalPrompt) ([]string, ai.UsageStats, error) {
 						t.Fatal("GenerateGoals should not be called when quota is exhausted")
@@ -235,9 +256,9 @@ func TestGenerate(t *testing.T) {
 						t.Fatal("GenerateGoals should not be called when unauthorized")
 						return nil, ai.UsageStats{}, nil
 					},
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						t.Fatal("ConsumeUnverifiedFreeGeneration should not be called when unauthorized")
-						return 0, nil
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						t.Fatal("ConsumeGeneration should not be called when unauthorized")
+						return nil, nil
 					},
 				}
 			},
@@ -388,6 +409,7 @@ func TestGenerate(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "We couldn't generate safe goals for that topic. Please try rephrasing.",
 			generateCalls:  1,
+			consumeCalls:   1,
 		},
 		{
 			name:        "Service Error - Rate Limit",
@@ -403,6 +425,8 @@ func TestGenerate(t *testing.T) {
 			expectedStatus: http.StatusTooManyRequests,
 			expectedError:  "AI provider rate limit exceeded.",
 			generateCalls:  1,
+			consumeCalls:   1,
+			refundCalls:    1,
 		},
 		{
 			name:        "Service Error - Unavailable",
@@ -418,6 +442,8 @@ func TestGenerate(t *testing.T) {
 			expectedStatus: http.StatusServiceUnavailable,
 			expectedError:  "The AI service is currently down. Please try again later.",
 			generateCalls:  1,
+			consumeCalls:   1,
+			refundCalls:    1,
 		},
 		{
 			name:        "Service Error - Not Configured",
@@ -433,6 +459,8 @@ func TestGenerate(t *testing.T) {
 			expectedStatus: http.StatusServiceUnavailable,
 			expectedError:  "AI is not configured on this server. Please try again later.",
 			generateCalls:  1,
+			consumeCalls:   1,
+			refundCalls:    1,
 		},
 		{
 			name:        "Service Error - Generic",
@@ -448,6 +476,7 @@ func TestGenerate(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  "An unexpected error occurred.",
 			generateCalls:  1,
+			consumeCalls:   1,
 		},
 		{
 			name:        "Unverified Usage Tracking Unavailable",
@@ -455,8 +484,8 @@ func TestGenerate(t *testing.T) {
 			user:        &models.User{ID: uuid.New(), EmailVerified: false},
 			mockSetup: func() *MockAIService {
 				return &MockAIService{
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						return 0, ai.ErrAIUsageTrackingUnavailable
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						return nil, ai.ErrAIUsageTrackingUnavailable
 					},
 					GenerateGoalsFunc: func(ctx context.Context, userID uuid.UUID, prompt ai.GoalPrompt) ([]string, ai.UsageStats, error) {
 						t.Fatal("GenerateGoals should not be called when tracking is unavailable")
@@ -474,8 +503,8 @@ func TestGenerate(t *testing.T) {
 			user:        &models.User{ID: uuid.New(), EmailVerified: false},
 			mockSetup: func() *MockAIService {
 				return &MockAIService{
-					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
-						return 0, errors.New("boom")
+					ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+						return nil, errors.New("boom")
 					},
 					GenerateGoalsFunc: func(ctx context.Context, userID uuid.UUID, prompt ai.GoalPrompt) ([]string, ai.UsageStats, error) {
 						t.Fatal("GenerateGoals should not be called when tracking fails")
@@ -572,19 +601,19 @@ func TestGenerate(t *testing.T) {
 				t.Fatalf("expected GenerateGoals calls %d, got %d", tt.generateCalls, mockService.GenerateCalls)
 			}
 			if tt.consumeCalls > 0 && mockService.ConsumeCalls != tt.consumeCalls {
-				t.Fatalf("expected ConsumeUnverifiedFreeGeneration calls %d, got %d", tt.consumeCalls, mockService.ConsumeCalls)
+				t.Fatalf("expected ConsumeGeneration calls %d, got %d", tt.consumeCalls, mockService.ConsumeCalls)
 			}
 			if tt.refundCalls > 0 && mockService.RefundCalls != tt.refundCalls {
-				t.Fatalf("expected RefundUnverifiedFreeGeneration calls %d, got %d", tt.refundCalls, mockService.RefundCalls)
+				t.Fatalf("expected RefundGeneration calls %d, got %d", tt.refundCalls, mockService.RefundCalls)
 			}
 			if tt.generateCalls == 0 && mockService.GenerateCalls != 0 {
 				t.Fatalf("expected no GenerateGoals calls, got %d", mockService.GenerateCalls)
 			}
 			if tt.consumeCalls == 0 && mockService.ConsumeCalls != 0 {
-				t.Fatalf("expected no ConsumeUnverifiedFreeGeneration calls, got %d", mockService.ConsumeCalls)
+				t.Fatalf("expected no ConsumeGeneration calls, got %d", mockService.ConsumeCalls)
 			}
 			if tt.refundCalls == 0 && mockService.RefundCalls != 0 {
-				t.Fatalf("expected no RefundUnverifiedFreeGeneration calls, got %d", mockService.RefundCalls)
+				t.Fatalf("expected no RefundGeneration calls, got %d", mockService.RefundCalls)
 			}
 		})
 	}
@@ -593,3 +622,158 @@ func TestGenerate(t *testing.T) {
 func ptrToIntValue(value int) *int {
 	return &value
 }
+
+func TestGenerate_ClientDisconnect(t *testing.T) {
+	body := `{"category":"hobbies","difficulty":"easy","budget":"free"}`
+	cases := []struct {
+		name          string
+		cancelOn      string
+		generateCalls int
+	}{
+		{name: "during the provider call", cancelOn: "generate", generateCalls: 1},
+		{name: "before the provider call", cancelOn: "consume"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &models.User{ID: uuid.New()}
+			ctx, cancel := context.WithCancel(context.WithValue(context.Background(), userContextKey, user))
+			defer cancel()
+			mockService := &MockAIService{
+				ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+					if tc.cancelOn == "consume" {
+						cancel()
+					}
+					return trialQuota(4), nil
+				},
+				GenerateGoalsFunc: func(ctx context.Context, userID uuid.UUID, prompt ai.GoalPrompt) ([]string, ai.UsageStats, error) {
+					cancel()
+					<-ctx.Done()
+					return nil, ai.UsageStats{}, ctx.Err()
+				},
+				RefundFunc: func(ctx context.Context, userID uuid.UUID) (bool, error) {
+					if ctx.Err() != nil {
+						t.Fatal("expected the refund to run on a live context")
+					}
+					return true, nil
+				},
+			}
+			handler := NewAIHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/ai/generate", strings.NewReader(body)).WithContext(ctx)
+			rr := httptest.NewRecorder()
+			handler.Generate(rr, req)
+
+			if mockService.GenerateCalls != tc.generateCalls {
+				t.Fatalf("expected %d GenerateGoals calls, got %d", tc.generateCalls, mockService.GenerateCalls)
+			}
+			if mockService.RefundCalls != 1 {
+				t.Fatalf("expected the free generation to be refunded, got %d refunds", mockService.RefundCalls)
+			}
+			if rr.Body.Len() != 0 {
+				t.Fatalf("expected nothing written to a gone client, got %q", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestAIHandler_MonthlyQuotaExhausted(t *testing.T) {
+	limit, remaining := 50, 0
+	resetsAt := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	requests := map[string]string{
+		"generate": `{"category":"hobbies","difficulty":"easy","budget":"free"}`,
+		"guide":    `{"mode":"new"}`,
+	}
+	for name, body := range requests {
+		t.Run(name, func(t *testing.T) {
+			mockService := &MockAIService{
+				ConsumeFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+					return &models.AIQuota{Tier: models.AIQuotaTierFree, Used: limit, Limit: &limit, Remaining: &remaining, ResetsAt: &resetsAt}, ai.ErrAIQuotaExhausted
+				},
+			}
+			handler := NewAIHandler(mockService)
+			req := httptest.NewRequest(http.MethodPost, "/api/ai/"+name, strings.NewReader(body))
+			req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New(), EmailVerified: true}))
+			rr := httptest.NewRecorder()
+
+			if name == "guide" {
+				handler.Guide(rr, req)
+			} else {
+				handler.Generate(rr, req)
+			}
+
+			if rr.Code != http.StatusForbidden {
+				t.Fatalf("expected 403, got %d", rr.Code)
+			}
+			var response GenerateErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if response.Code != ErrorCodeQuotaExhausted || response.FreeRemaining != nil {
+				t.Fatalf("expected a quota_exhausted error without free_remaining, got %+v", response)
+			}
+			if response.Error != "You've used all 50 of this month's free AI generations. They reset on April 1." {
+				t.Fatalf("unexpected message: %q", response.Error)
+			}
+			if response.Quota == nil || response.Quota.ResetsAt == nil || !response.Quota.ResetsAt.Equal(resetsAt) {
+				t.Fatalf("expected the quota with its reset date, got %+v", response.Quota)
+			}
+			if mockService.GenerateCalls != 0 || mockService.GenerateGuideCalls != 0 || mockService.RefundCalls != 0 {
+				t.Fatalf("expected no provider call or refund, got %+v", mockService)
+			}
+		})
+	}
+}
+
+func TestAIHandler_Quota(t *testing.T) {
+	t.Run("unauthorized", func(t *testing.T) {
+		handler := NewAIHandler(&MockAIService{})
+		rr := httptest.NewRecorder()
+		handler.Quota(rr, httptest.NewRequest(http.MethodGet, "/api/ai/quota", nil))
+		assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+	})
+
+	t.Run("reports the caller's quota", func(t *testing.T) {
+		user := &models.User{ID: uuid.New(), EmailVerified: true}
+		limit, remaining := 50, 38
+		resetsAt := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+		handler := NewAIHandler(&MockAIService{
+			QuotaFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+				if userID != user.ID {
+					t.Fatalf("expected the caller's quota, got %s", userID)
+				}
+				return &models.AIQuota{Tier: models.AIQuotaTierFree, Used: 12, Limit: &limit, Remaining: &remaining, ResetsAt: &resetsAt}, nil
+			},
+		})
+		req := httptest.NewRequest(http.MethodGet, "/api/ai/quota", nil)
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+
+		handler.Quota(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		var response AIQuotaResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if q := response.Quota; q == nil || q.Tier != models.AIQuotaTierFree || q.Used != 12 || *q.Remaining != 38 {
+			t.Fatalf("unexpected quota: %+v", response.Quota)
+		}
+	})
+
+	t.Run("tracking unavailable", func(t *testing.T) {
+		handler := NewAIHandler(&MockAIService{
+			QuotaFunc: func(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+				return nil, ai.ErrAIUsageTrackingUnavailable
+			},
+		})
+		req := httptest.NewRequest(http.MethodGet, "/api/ai/quota", nil)
+		req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+		rr := httptest.NewRecorder()
+
+		handler.Quota(rr, req)
+
+		assertErrorResponse(t, rr, http.StatusServiceUnavailable, "AI usage tracking is temporarily unavailable. Please try again later.")
+	})
+}
@@ -43,6 +43,9 @@ func (h *ApiTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
+	if rejectTokenAuth(w, r) {
+		return
+	}
 
 	var req CreateApiTokenRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -81,6 +84,9 @@ func (h *ApiTokenHandler) List(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
+	if rejectTokenAuth(w, r) {
+		return
+	}
 
 	tokens, err := h.apiTokenService.List(r.Context(), user.ID)
 	if err != nil {
@@ -102,6 +108,9 @@ func (h *ApiTokenHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
+	if rejectTokenAuth(w, r) {
+		return
+	}
 
 	// Extract token ID from path
 	path := r.URL.Path
@@ -137,6 +146,9 @@ func (h *ApiTokenHandler) DeleteAll(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
+	if rejectTokenAuth(w, r) {
+		return
+	}
 
 	err := h.apiTokenService.DeleteAll(r.Context(), user.ID)
 	if err != nil {
@@ -173,6 +173,29 @@ func TestApiTokenHandler_List_Success(t *testing.T) {
 	}
 }
 
+func TestApiTokenHandler_RejectsTokenAuth(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewApiTokenHandler(&mockApiTokenService{})
+	tokenID := uuid.New()
+
+	for name, serve := range map[string]http.HandlerFunc{
+		"create":     handler.Create,
+		"list":       handler.List,
+		"delete":     handler.Delete,
+		"delete all": handler.DeleteAll,
+	} {
+		req := httptest.NewRequest(http.MethodDelete, "/api/tokens/"+tokenID.String(), nil)
+		req = req.WithContext(SetAuthInfoInContext(req.Context(), &AuthInfo{User: user, Method: AuthMethodToken, TokenID: tokenID, Scope: models.ScopeReadWrite}))
+		rr := httptest.NewRecorder()
+
+		serve(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("%s: expected status 403 for token auth, got %d", name, rr.Code)
+		}
+	}
+}
+
 func TestApiTokenHandler_List_Unauthenticated(t *testing.T) {
 	handler := NewApiTokenHandler(&mockApiTokenService{})
 
@@ -118,7 +118,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create session
-	token, err := h.authService.CreateSession(r.Context(), user.ID)
+	token, err := h.authService.CreateSession(sessionContext(r), user.ID)
 	if err != nil {
 		log.Printf("Error creating session: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -172,7 +172,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create session
-	token, err := h.authService.CreateSession(r.Context(), user.ID)
+	token, err := h.authService.CreateSession(sessionContext(r), user.ID)
 	if err != nil {
 		log.Printf("Error creating session: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -259,7 +259,7 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	_ = h.authService.DeleteAllUserSessions(r.Context(), user.ID)
 
 	// Create new session
-	token, err := h.authService.CreateSession(r.Context(), user.ID)
+	token, err := h.authService.CreateSession(sessionContext(r), user.ID)
 	if err != nil {
 		log.Printf("Error creating session: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -344,14 +344,43 @@ func (h *AuthHandler) MagicLink(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "If an account exists, a login link has been sent"})
 }
 
-// MagicLinkVerify verifies a magic link token and creates a session
+// MagicLinkVerify consumes a magic link token posted by the confirmation page
+// and creates a session. Opening the emailed link only renders that page, so
+// mail scanners that prefetch links cannot use up the token.
 func (h *AuthHandler) MagicLinkVerify(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Token == "" {
+		writeError(w, http.StatusBadRequest, "Token is required")
+		return
+	}
+
+	h.completeMagicLink(w, r, req.Token)
+}
+
+// MagicLinkVerifyLegacy is the deprecated single-GET magic link flow. It only
+// consumes the token when legacy=1 is passed, so a plain prefetch of the URL
+// has no side effects.
+func (h *AuthHandler) MagicLinkVerifyLegacy(w http.ResponseWriter, r *http.Request) {
 	token := r.URL.Query().Get("token")
 	if token == "" {
 		writeError(w, http.StatusBadRequest, "Token is required")
 		return
 	}
+	if r.URL.Query().Get("legacy") != "1" {
+		writeError(w, http.StatusBadRequest, "Confirm the sign-in with POST /api/auth/magic-link/verify")
+		return
+	}
+
+	h.completeMagicLink(w, r, token)
+}
 
+func (h *AuthHandler) completeMagicLink(w http.ResponseWriter, r *http.Request, token string) {
 	email, err := h.emailService.VerifyMagicLink(r.Context(), token)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
@@ -376,7 +405,7 @@ func (h *AuthHandler) MagicLinkVerify(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create session
-	sessionToken, err := h.authService.CreateSession(r.Context(), user.ID)
+	sessionToken, err := h.authService.CreateSession(sessionContext(r), user.ID)
 	if err != nil {
 		log.Printf("Error creating session: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -486,7 +515,7 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create new session
-	sessionToken, err := h.authService.CreateSession(r.Context(), userID)
+	sessionToken, err := h.authService.CreateSession(sessionContext(r), userID)
 	if err != nil {
 		log.Printf("Error creating session: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -592,3 +621,10 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, ErrorResponse{Error: message})
 }
+
+// clientGone reports whether the request's client has disconnected. Handlers
+// check it between expensive phases and stop instead of writing a response
+// nobody will read.
+func clientGone(r *http.Request) bool {
+	return r.Context().Err() != nil
+}
@@ -9,8 +9,10 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
@@ -22,11 +24,18 @@ const (
 	oauthNextCookieName  = "oauth_next"
 	oauthCookieMaxAge    = 10 * 60 // 10 minutes
 	oauthPendingTTL      = 10 * time.Minute
+
+	// oauthLinkCookieName holds the ID of the user who started linking a
+	// provider, so the callback only links for that same signed-in user.
+	oauthLinkCookieName = "oauth_link"
 )
 
+const providerPendingExpiredMessage = "Signup session expired. Please restart OAuth login."
+
 type ProviderAuthHandler struct {
 	providerAuth services.ProviderAuthServiceInterface
 	authService  services.AuthServiceInterface
+	userService  services.UserServiceInterface
 	redis        services.RedisClient
 	providers    map[string]services.OAuthProvider
 	secure       bool
@@ -47,6 +56,11 @@ func NewProviderAuthHandler(providerAuth services.ProviderAuthServiceInterface,
 	}
 }
 
+// SetUserService enables username suggestions for provider signups.
+func (h *ProviderAuthHandler) SetUserService(userService services.UserServiceInterface) {
+	h.userService = userService
+}
+
 func (h *ProviderAuthHandler) ProviderStart(w http.ResponseWriter, r *http.Request) {
 	provider, _ := h.getProvider(r)
 	if provider == nil {
@@ -67,6 +81,7 @@ func (h *ProviderAuthHandler) ProviderStart(w http.ResponseWriter, r *http.Reque
 
 	h.setOAuthCookie(w, oauthStateCookieName, state)
 	h.setOAuthCookie(w, oauthNonceCookieName, nonce)
+	h.clearOAuthCookie(w, oauthLinkCookieName)
 
 	if next := sanitizeNext(r.URL.Query().Get("next")); next != "" {
 		h.setOAuthCookie(w, oauthNextCookieName, next)
@@ -109,6 +124,13 @@ func (h *ProviderAuthHandler) ProviderCallback(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Only a flow started through ProviderLink links; a signed-in user
+	// signing in with a provider gets the usual login.
+	if user := GetUserFromContext(r.Context()); user != nil && linkRequested(r) {
+		h.finishLink(w, r, provider, user, code, nonceCookie.Value)
+		return
+	}
+
 	claims, err := provider.ExchangeAndVerify(r.Context(), code, nonceCookie.Value)
 	if err != nil {
 		log.Printf("Provider exchange failed: %v", err)
@@ -131,7 +153,7 @@ func (h *ProviderAuthHandler) ProviderCallback(w http.ResponseWriter, r *http.Re
 	h.clearOAuthCookie(w, oauthNonceCookieName)
 
 	if linkResult.User != nil {
-		token, err := h.authService.CreateSession(r.Context(), linkResult.User.ID)
+		token, err := h.authService.CreateSession(sessionContext(r), linkResult.User.ID)
 		if err != nil {
 			log.Printf("Provider session failed: %v", err)
 			writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -159,6 +181,7 @@ func (h *ProviderAuthHandler) ProviderCallback(w http.ResponseWriter, r *http.Re
 		Provider: string(linkResult.Pending.Provider),
 		Subject:  linkResult.Pending.Subject,
 		Email:    linkResult.Pending.Email,
+		Next:     h.readOAuthNext(r),
 	}
 	payload, err := json.Marshal(pendingRecord)
 	if err != nil {
@@ -177,38 +200,145 @@ func (h *ProviderAuthHandler) ProviderCallback(w http.ResponseWriter, r *http.Re
 	http.Redirect(w, r, h.redirectTarget("", fmt.Sprintf("/%s-complete", providerKey)), http.StatusFound)
 }
 
-func (h *ProviderAuthHandler) ProviderComplete(w http.ResponseWriter, r *http.Request) {
-	provider, providerKey := h.getProvider(r)
+// finishLink completes a callback for a signed-in user by attaching the
+// provider identity to their account instead of signing in with it. The link
+// must have been started by the same user through ProviderLink.
+func (h *ProviderAuthHandler) finishLink(w http.ResponseWriter, r *http.Request, provider services.OAuthProvider, user *models.User, code, nonce string) {
+	linkCookie, err := r.Cookie(oauthLinkCookieName)
+	if err != nil || !secureCompare(linkCookie.Value, user.ID.String()) {
+		h.redirectToLoginError(w, r, "oauth_invalid")
+		return
+	}
+
+	claims, err := provider.ExchangeAndVerify(r.Context(), code, nonce)
+	if err != nil {
+		log.Printf("Provider exchange failed: %v", err)
+		h.redirectToLoginError(w, r, "oauth_exchange")
+		return
+	}
+
+	h.clearOAuthCookie(w, oauthStateCookieName)
+	h.clearOAuthCookie(w, oauthNonceCookieName)
+	h.clearOAuthCookie(w, oauthLinkCookieName)
+
+	if err := h.providerAuth.LinkProviderToUser(r.Context(), user.ID, claims); err != nil {
+		switch {
+		case errors.Is(err, services.ErrProviderIdentityExists):
+			h.redirectToLoginError(w, r, "oauth_linked_elsewhere")
+		case errors.Is(err, services.ErrProviderAlreadyLinked):
+			h.redirectToLoginError(w, r, "oauth_already_linked")
+		default:
+			log.Printf("Provider link failed: %v", err)
+			h.redirectToLoginError(w, r, "oauth_link")
+		}
+		return
+	}
+	http.Redirect(w, r, "/profile?linked="+string(provider.Provider()), http.StatusFound)
+}
+
+// ListProviders returns the providers linked to the current user, the ones
+// this server offers, and whether the account has a password to fall back on.
+func (h *ProviderAuthHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Not authenticated")
+		return
+	}
+
+	identities, err := h.providerAuth.ListIdentities(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Provider list failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	available := make([]string, 0, len(h.providers))
+	for key := range h.providers {
+		available = append(available, key)
+	}
+	sort.Strings(available)
+
+	writeJSON(w, http.StatusOK, providerListResponse{
+		Identities:  identities,
+		Available:   available,
+		HasPassword: user.PasswordHash != nil && *user.PasswordHash != "",
+	})
+}
+
+// ProviderLink starts linking a provider to the current user. It sets the
+// same state and nonce cookies as ProviderStart plus the link cookie, and
+// returns the provider URL for the browser to visit; the callback then links
+// instead of signing in.
+func (h *ProviderAuthHandler) ProviderLink(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Not authenticated")
+		return
+	}
+	provider, _ := h.getProvider(r)
 	if provider == nil {
-		http.NotFound(w, r)
+		writeError(w, http.StatusNotFound, "Provider not found")
 		return
 	}
 
-	if GetUserFromContext(r.Context()) != nil {
-		writeError(w, http.StatusBadRequest, "Already authenticated")
+	state, err := generateSecureToken(32)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to start provider auth")
+		return
+	}
+	nonce, err := generateSecureToken(32)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to start provider auth")
 		return
 	}
 
-	pendingCookie, err := r.Cookie(providerPendingCookieName(providerKey))
-	if err != nil || pendingCookie.Value == "" {
-		writeError(w, http.StatusBadRequest, "Signup session expired. Please restart OAuth login.")
+	h.setOAuthCookie(w, oauthStateCookieName, state)
+	h.setOAuthCookie(w, oauthNonceCookieName, nonce)
+	h.setOAuthCookie(w, oauthLinkCookieName, user.ID.String())
+	h.clearOAuthCookie(w, oauthNextCookieName)
+
+	writeJSON(w, http.StatusOK, providerLinkResponse{RedirectURL: provider.AuthCodeURL(state, nonce)})
+}
+
+// ProviderUnlink removes a linked provider from the current user. Providers
+// this server no longer offers can still be removed.
+func (h *ProviderAuthHandler) ProviderUnlink(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Not authenticated")
 		return
 	}
+	providerKey := strings.ToLower(r.PathValue("provider"))
 
-	pendingKey := providerPendingRedisKey(pendingCookie.Value)
-	pendingJSON, err := h.redis.Get(r.Context(), pendingKey)
-	if err != nil || pendingJSON == "" {
-		writeError(w, http.StatusBadRequest, "Signup session expired. Please restart OAuth login.")
+	if err := h.providerAuth.UnlinkProvider(r.Context(), user.ID, services.Provider(providerKey)); err != nil {
+		switch {
+		case errors.Is(err, services.ErrProviderNotLinked):
+			writeError(w, http.StatusNotFound, "Provider not linked")
+		case errors.Is(err, services.ErrLastLoginMethod):
+			writeError(w, http.StatusConflict, "Set a password before disconnecting your only sign-in method")
+		default:
+			log.Printf("Provider unlink failed: %v", err)
+			writeError(w, http.StatusInternalServerError, "Internal server error")
+		}
 		return
 	}
+	writeJSON(w, http.StatusOK, map[string]bool{"unlinked": true})
+}
 
-	var pending providerPendingRecord
-	if err := json.Unmarshal([]byte(pendingJSON), &pending); err != nil {
-		writeError(w, http.StatusBadRequest, "Signup session expired. Please restart OAuth login.")
+func (h *ProviderAuthHandler) ProviderComplete(w http.ResponseWriter, r *http.Request) {
+	provider, providerKey := h.getProvider(r)
+	if provider == nil {
+		http.NotFound(w, r)
 		return
 	}
-	if pending.Provider != string(provider.Provider()) {
-		writeError(w, http.StatusBadRequest, "Invalid signup session. Please restart OAuth login.")
+
+	if GetUserFromContext(r.Context()) != nil {
+		writeError(w, http.StatusBadRequest, "Already authenticated")
+		return
+	}
+
+	pending, pendingKey, errMessage := h.loadPending(r, provider, providerKey)
+	if pending == nil {
+		writeError(w, http.StatusBadRequest, errMessage)
 		return
 	}
 
@@ -221,6 +351,16 @@ func (h *ProviderAuthHandler) ProviderComplete(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// An omitted username takes the same suggestion the pending endpoint shows.
+	if strings.TrimSpace(req.Username) == "" && h.userService != nil {
+		suggested, err := h.userService.SuggestUsername(r.Context(), pending.Email)
+		if err != nil {
+			log.Printf("Provider username suggestion failed: %v", err)
+		} else {
+			req.Username = suggested
+		}
+	}
+
 	user, err := h.providerAuth.CreateUserFromProviderPending(r.Context(), services.PendingProviderUser{
 		Provider: provider.Provider(),
 		Subject:  pending.Subject,
@@ -235,7 +375,7 @@ func (h *ProviderAuthHandler) ProviderComplete(w http.ResponseWriter, r *http.Re
 		case errors.Is(err, services.ErrInvalidUsername):
 			writeError(w, http.StatusBadRequest, "Username must be between 2 and 100 characters")
 		case errors.Is(err, services.ErrInvalidProviderPending):
-			writeError(w, http.StatusBadRequest, "Signup session expired. Please restart OAuth login.")
+			writeError(w, http.StatusBadRequest, providerPendingExpiredMessage)
 		default:
 			log.Printf("Provider complete failed: %v", err)
 			writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -243,7 +383,7 @@ func (h *ProviderAuthHandler) ProviderComplete(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	token, err := h.authService.CreateSession(r.Context(), user.ID)
+	token, err := h.authService.CreateSession(sessionContext(r), user.ID)
 	if err != nil {
 		log.Printf("Provider session failed: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -254,7 +394,13 @@ func (h *ProviderAuthHandler) ProviderComplete(w http.ResponseWriter, r *http.Re
 	h.clearOAuthCookie(w, providerPendingCookieName(providerKey))
 	h.clearOAuthCookie(w, oauthNextCookieName)
 
-	next := h.readOAuthNext(r)
+	// The pending record carries next through the signup step; the cookie is
+	// a fallback for records stored before it did. Sanitize again on the way
+	// out in case the stored value was tampered with.
+	next := sanitizeNext(pending.Next)
+	if next == "" {
+		next = h.readOAuthNext(r)
+	}
 	response := providerCompleteResponse{
 		User: user,
 		Next: next,
@@ -278,6 +424,90 @@ func (h *ProviderAuthHandler) ProviderComplete(w http.ResponseWriter, r *http.Re
 	_ = h.redis.Del(r.Context(), pendingKey)
 }
 
+// ProviderPending returns the masked email and a suggested username for an
+// in-progress provider signup, so the complete page can show which account
+// the user authenticated with.
+func (h *ProviderAuthHandler) ProviderPending(w http.ResponseWriter, r *http.Request) {
+	provider, providerKey := h.getProvider(r)
+	if provider == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if GetUserFromContext(r.Context()) != nil {
+		writeError(w, http.StatusBadRequest, "Already authenticated")
+		return
+	}
+
+	pending, _, errMessage := h.loadPending(r, provider, providerKey)
+	if pending == nil {
+		writeError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	response := providerPendingResponse{Email: maskEmail(pending.Email)}
+	if h.userService != nil {
+		suggested, err := h.userService.SuggestUsername(r.Context(), pending.Email)
+		if err != nil {
+			log.Printf("Provider username suggestion failed: %v", err)
+		} else {
+			response.SuggestedUsername = suggested
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// loadPending reads the pending signup record referenced by the provider's
+// pending cookie. On failure it returns nil and the client-facing message.
+func (h *ProviderAuthHandler) loadPending(r *http.Request, provider services.OAuthProvider, providerKey string) (*providerPendingRecord, string, string) {
+	pendingCookie, err := r.Cookie(providerPendingCookieName(providerKey))
+	if err != nil || pendingCookie.Value == "" {
+		return nil, "", providerPendingExpiredMessage
+	}
+
+	pendingKey := providerPendingRedisKey(pendingCookie.Value)
+	pendingJSON, err := h.redis.Get(r.Context(), pendingKey)
+	if err != nil || pendingJSON == "" {
+		return nil, "", providerPendingExpiredMessage
+	}
+
+	var pending providerPendingRecord
+	if err := json.Unmarshal([]byte(pendingJSON), &pending); err != nil {
+		return nil, "", providerPendingExpiredMessage
+	}
+	if pending.Provider != string(provider.Provider()) {
+		return nil, "", "Invalid signup session. Please restart OAuth login."
+	}
+	return &pending, pendingKey, ""
+}
+
+// maskEmail keeps the first character of the local part and the domain,
+// e.g. "jane@gmail.com" -> "j***@gmail.com".
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return "***"
+	}
+	first, _ := utf8.DecodeRuneInString(email)
+	return string(first) + "***" + email[at:]
+}
+
+type providerPendingResponse struct {
+	Email             string `json:"email"`
+	SuggestedUsername string `json:"suggested_username,omitempty"`
+}
+
+type providerListResponse struct {
+	Identities  []models.LinkedIdentity `json:"identities"`
+	Available   []string                `json:"available"`
+	HasPassword bool                    `json:"has_password"`
+}
+
+type providerLinkResponse struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
 type providerCompleteResponse struct {
 	User *models.User `json:"user"`
 	Next string       `json:"next,omitempty"`
@@ -287,6 +517,7 @@ type providerPendingRecord struct {
 	Provider string `json:"provider"`
 	Subject  string `json:"subject"`
 	Email    string `json:"email"`
+	Next     string `json:"next,omitempty"`
 }
 
 func providerPendingCookieName(provider string) string {
@@ -346,11 +577,25 @@ func (h *ProviderAuthHandler) setSessionCookie(w http.ResponseWriter, token stri
 	})
 }
 
+// redirectToLoginError ends a failed callback on the login page, or for a
+// signed-in user linking a provider, on the account page.
 func (h *ProviderAuthHandler) redirectToLoginError(w http.ResponseWriter, r *http.Request, code string) {
 	message := sanitizeErrorParam(code)
+	if GetUserFromContext(r.Context()) != nil && linkRequested(r) {
+		h.clearOAuthCookie(w, oauthLinkCookieName)
+		http.Redirect(w, r, "/profile?link_error="+message, http.StatusFound)
+		return
+	}
 	http.Redirect(w, r, "/login?error="+message, http.StatusFound)
 }
 
+// linkRequested reports whether the callback belongs to a flow started by
+// ProviderLink, which sets the link cookie.
+func linkRequested(r *http.Request) bool {
+	cookie, err := r.Cookie(oauthLinkCookieName)
+	return err == nil && cookie.Value != ""
+}
+
 func (h *ProviderAuthHandler) readOAuthNext(r *http.Request) string {
 	nextCookie, err := r.Cookie(oauthNextCookieName)
 	if err != nil {
@@ -44,8 +44,11 @@ func (m *mockOAuthProvider) ExchangeAndVerify(ctx context.Context, code, nonce s
 }
 
 type mockProviderAuthService struct {
-	LinkFunc   func(ctx context.Context, claims services.IdentityClaims) (*services.ProviderLinkResult, error)
-	CreateFunc func(ctx context.Context, pending services.PendingProviderUser, username string, searchable bool) (*models.User, error)
+	LinkFunc       func(ctx context.Context, claims services.IdentityClaims) (*services.ProviderLinkResult, error)
+	CreateFunc     func(ctx context.Context, pending services.PendingProviderUser, username string, searchable bool) (*models.User, error)
+	ListFunc       func(ctx context.Context, userID uuid.UUID) ([]models.LinkedIdentity, error)
+	LinkToUserFunc func(ctx context.Context, userID uuid.UUID, claims services.IdentityClaims) error
+	UnlinkFunc     func(ctx context.Context, userID uuid.UUID, provider services.Provider) error
 }
 
 func (m *mockProviderAuthService) LinkOrFindUserFromProvider(ctx context.Context, claims services.IdentityClaims) (*services.ProviderLinkResult, error) {
@@ -55,6 +58,27 @@ func (m *mockProviderAuthService) LinkOrFindUserFromProvider(ctx context.Context
 	return nil, nil
 }
 
+func (m *mockProviderAuthService) ListIdentities(ctx context.Context, userID uuid.UUID) ([]models.LinkedIdentity, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockProviderAuthService) LinkProviderToUser(ctx context.Context, userID uuid.UUID, claims services.IdentityClaims) error {
+	if m.LinkToUserFunc != nil {
+		return m.LinkToUserFunc(ctx, userID, claims)
+	}
+	return nil
+}
+
+func (m *mockProviderAuthService) UnlinkProvider(ctx context.Context, userID uuid.UUID, provider services.Provider) error {
+	if m.UnlinkFunc != nil {
+		return m.UnlinkFunc(ctx, userID, provider)
+	}
+	return nil
+}
+
 func (m *mockProviderAuthService) CreateUserFromProviderPending(ctx context.Context, pending services.PendingProviderUser, username string, searchable bool) (*models.User, error) {
 	if m.CreateFunc != nil {
 		return m.CreateFunc(ctx, pending, username, searchable)
@@ -420,6 +444,250 @@ func TestProviderAuthHandler_Complete_UsernameConflict(t *testing.T) {
 	assertErrorResponse(t, rr, http.StatusConflict, "Username already taken")
 }
 
+func TestProviderAuthHandler_Callback_NewUserStoresSanitizedNext(t *testing.T) {
+	tests := []struct {
+		name string
+		next string
+		want string
+	}{
+		{"relative path", "/friend-invite/abc123", "/friend-invite/abc123"},
+		{"absolute url", "https://evil.example/steal", ""},
+		{"protocol relative", "//evil.example", ""},
+		{"javascript scheme", "javascript:alert(1)", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := &mockOAuthProvider{
+				provider: services.ProviderGoogle,
+				claims: services.IdentityClaims{
+					Provider:      services.ProviderGoogle,
+					Subject:       "sub",
+					Email:         "user@example.com",
+					EmailVerified: true,
+				},
+			}
+			mockProviderAuth := &mockProviderAuthService{
+				LinkFunc: func(ctx context.Context, claims services.IdentityClaims) (*services.ProviderLinkResult, error) {
+					return &services.ProviderLinkResult{
+						Pending: &services.PendingProviderUser{
+							Provider: services.ProviderGoogle,
+							Subject:  "sub",
+							Email:    "user@example.com",
+						},
+					}, nil
+				},
+			}
+			redis := &fakeRedisClient{values: map[string]string{}}
+			handler := NewProviderAuthHandler(mockProviderAuth, &mockAuthService{}, redis, map[services.Provider]services.OAuthProvider{
+				services.ProviderGoogle: mockProvider,
+			}, false)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/google/callback?code=abc&state=state123", nil)
+			req.AddCookie(&http.Cookie{Name: oauthStateCookieName, Value: "state123"})
+			req.AddCookie(&http.Cookie{Name: oauthNonceCookieName, Value: "nonce123"})
+			req.AddCookie(&http.Cookie{Name: oauthNextCookieName, Value: tt.next})
+			req.SetPathValue("provider", "google")
+			rr := httptest.NewRecorder()
+
+			handler.ProviderCallback(rr, req)
+
+			if rr.Code != http.StatusFound {
+				t.Fatalf("expected status 302, got %d", rr.Code)
+			}
+			if len(redis.values) != 1 {
+				t.Fatalf("expected one pending record, got %d", len(redis.values))
+			}
+			for _, raw := range redis.values {
+				var record providerPendingRecord
+				if err := json.Unmarshal([]byte(raw), &record); err != nil {
+					t.Fatalf("failed to decode pending record: %v", err)
+				}
+				if record.Next != tt.want {
+					t.Fatalf("expected stored next %q, got %q", tt.want, record.Next)
+				}
+			}
+		})
+	}
+}
+
+func TestProviderAuthHandler_Complete_ReturnsPendingNext(t *testing.T) {
+	tests := []struct {
+		name string
+		next string
+		want string
+	}{
+		{"relative path", "/friend-invite/abc123", "/friend-invite/abc123"},
+		{"absolute url", "https://evil.example/steal", ""},
+		{"protocol relative", "//evil.example", ""},
+		{"backslash prefix", "/\\evil.example", ""},
+		{"javascript scheme", "javascript:alert(1)", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{ID: uuid.New(), Email: "user@example.com", Username: "tester"}
+			pendingBytes, _ := json.Marshal(providerPendingRecord{
+				Provider: "google",
+				Subject:  "sub",
+				Email:    "user@example.com",
+				Next:     tt.next,
+			})
+			redis := &fakeRedisClient{values: map[string]string{
+				providerPendingRedisKey("token123"): string(pendingBytes),
+			}}
+			mockProviderAuth := &mockProviderAuthService{
+				CreateFunc: func(ctx context.Context, pending services.PendingProviderUser, username string, searchable bool) (*models.User, error) {
+					return user, nil
+				},
+			}
+			mockAuth := &mockAuthService{
+				CreateSessionFunc: func(ctx context.Context, userID uuid.UUID) (string, error) {
+					return "session-token", nil
+				},
+			}
+			handler := NewProviderAuthHandler(mockProviderAuth, mockAuth, redis, map[services.Provider]services.OAuthProvider{
+				services.ProviderGoogle: &mockOAuthProvider{provider: services.ProviderGoogle},
+			}, false)
+
+			body := bytes.NewBufferString(`{"username":"tester","searchable":true}`)
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/google/complete", body)
+			req.AddCookie(&http.Cookie{Name: providerPendingCookieName("google"), Value: "token123"})
+			req.SetPathValue("provider", "google")
+			rr := httptest.NewRecorder()
+
+			handler.ProviderComplete(rr, req)
+
+			if rr.Code != http.StatusCreated {
+				t.Fatalf("expected status 201, got %d", rr.Code)
+			}
+			var response providerCompleteResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if response.Next != tt.want {
+				t.Fatalf("expected next %q, got %q", tt.want, response.Next)
+			}
+		})
+	}
+}
+
+func TestProviderAuthHandler_Pending_ReturnsMaskedEmailAndSuggestion(t *testing.T) {
+	pendingBytes, _ := json.Marshal(providerPendingRecord{
+		Provider: "google",
+		Subject:  "sub",
+		Email:    "jane.doe@gmail.com",
+	})
+	redis := &fakeRedisClient{values: map[string]string{
+		providerPendingRedisKey("token123"): string(pendingBytes),
+	}}
+	handler := NewProviderAuthHandler(&mockProviderAuthService{}, &mockAuthService{}, redis, map[services.Provider]services.OAuthProvider{
+		services.ProviderGoogle: &mockOAuthProvider{provider: services.ProviderGoogle},
+	}, false)
+	handler.SetUserService(&mockUserService{
+		SuggestUsernameFunc: func(ctx context.Context, email string) (string, error) {
+			if email != "jane.doe@gmail.com" {
+				t.Fatalf("unexpected email %q", email)
+			}
+			return "jane.doe2", nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/google/pending", nil)
+	req.AddCookie(&http.Cookie{Name: providerPendingCookieName("google"), Value: "token123"})
+	req.SetPathValue("provider", "google")
+	rr := httptest.NewRecorder()
+
+	handler.ProviderPending(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var response providerPendingResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Email != "j***@gmail.com" {
+		t.Fatalf("expected masked email, got %q", response.Email)
+	}
+	if response.SuggestedUsername != "jane.doe2" {
+		t.Fatalf("expected suggested username, got %q", response.SuggestedUsername)
+	}
+}
+
+func TestProviderAuthHandler_Pending_Expired(t *testing.T) {
+	handler := NewProviderAuthHandler(&mockProviderAuthService{}, &mockAuthService{}, &fakeRedisClient{}, map[services.Provider]services.OAuthProvider{
+		services.ProviderGoogle: &mockOAuthProvider{provider: services.ProviderGoogle},
+	}, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/google/pending", nil)
+	req.AddCookie(&http.Cookie{Name: providerPendingCookieName("google"), Value: "gone"})
+	req.SetPathValue("provider", "google")
+	rr := httptest.NewRecorder()
+
+	handler.ProviderPending(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Signup session expired. Please restart OAuth login.")
+}
+
+func TestProviderAuthHandler_Complete_OmittedUsernameUsesSuggestion(t *testing.T) {
+	pendingBytes, _ := json.Marshal(providerPendingRecord{
+		Provider: "google",
+		Subject:  "sub",
+		Email:    "jane@example.com",
+	})
+	redis := &fakeRedisClient{values: map[string]string{
+		providerPendingRedisKey("token123"): string(pendingBytes),
+	}}
+	var gotUsername string
+	mockProviderAuth := &mockProviderAuthService{
+		CreateFunc: func(ctx context.Context, pending services.PendingProviderUser, username string, searchable bool) (*models.User, error) {
+			gotUsername = username
+			return &models.User{ID: uuid.New(), Email: pending.Email, Username: username}, nil
+		},
+	}
+	mockAuth := &mockAuthService{
+		CreateSessionFunc: func(ctx context.Context, userID uuid.UUID) (string, error) {
+			return "session-token", nil
+		},
+	}
+	handler := NewProviderAuthHandler(mockProviderAuth, mockAuth, redis, map[services.Provider]services.OAuthProvider{
+		services.ProviderGoogle: &mockOAuthProvider{provider: services.ProviderGoogle},
+	}, false)
+	handler.SetUserService(&mockUserService{
+		SuggestUsernameFunc: func(ctx context.Context, email string) (string, error) {
+			return "jane", nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/google/complete", bytes.NewBufferString(`{"searchable":false}`))
+	req.AddCookie(&http.Cookie{Name: providerPendingCookieName("google"), Value: "token123"})
+	req.SetPathValue("provider", "google")
+	rr := httptest.NewRecorder()
+
+	handler.ProviderComplete(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", rr.Code)
+	}
+	if gotUsername != "jane" {
+		t.Fatalf("expected suggested username, got %q", gotUsername)
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	tests := map[string]string{
+		"jane@gmail.com": "j***@gmail.com",
+		"j@example.com":  "j***@example.com",
+		"élodie@mail.fr": "é***@mail.fr",
+		"not-an-email":   "***",
+		"@missing.local": "***",
+	}
+	for input, want := range tests {
+		if got := maskEmail(input); got != want {
+			t.Fatalf("maskEmail(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
 func TestSanitizeNext_RejectsUnsafePrefix(t *testing.T) {
 	for _, input := range []string{"//evil.com", "/\\evil.com"} {
 		if got := sanitizeNext(input); got != "" {
@@ -465,3 +733,239 @@ func TestRedirectTarget_NormalizesFallback(t *testing.T) {
 		t.Fatalf("expected fallback to normalize, got %q", target)
 	}
 }
+
+func newLinkTestHandler(providerAuth *mockProviderAuthService, provider *mockOAuthProvider) *ProviderAuthHandler {
+	return NewProviderAuthHandler(providerAuth, &mockAuthService{
+		CreateSessionFunc: func(ctx context.Context, userID uuid.UUID) (string, error) {
+			return "", errors.New("linking must not sign in")
+		},
+	}, &fakeRedisClient{}, map[services.Provider]services.OAuthProvider{
+		services.ProviderGoogle: provider,
+	}, false)
+}
+
+func linkCallbackRequest(user *models.User, linkCookie string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/google/callback?code=abc&state=state123", nil)
+	req.AddCookie(&http.Cookie{Name: oauthStateCookieName, Value: "state123"})
+	req.AddCookie(&http.Cookie{Name: oauthNonceCookieName, Value: "nonce123"})
+	if linkCookie != "" {
+		req.AddCookie(&http.Cookie{Name: oauthLinkCookieName, Value: linkCookie})
+	}
+	req.SetPathValue("provider", "google")
+	return req.WithContext(SetUserInContext(req.Context(), user))
+}
+
+func TestProviderAuthHandler_Link_StartsFlow(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	provider := &mockOAuthProvider{provider: services.ProviderGoogle, authURL: "https://example.com/auth"}
+	handler := newLinkTestHandler(&mockProviderAuthService{}, provider)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/google/link", nil)
+	req.SetPathValue("provider", "google")
+	rr := httptest.NewRecorder()
+	handler.ProviderLink(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 when signed out, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/auth/google/link", nil)
+	req.SetPathValue("provider", "google")
+	rr = httptest.NewRecorder()
+	handler.ProviderLink(rr, req.WithContext(SetUserInContext(req.Context(), user)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp providerLinkResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil || resp.RedirectURL != provider.authURL {
+		t.Fatalf("expected the provider URL, got %+v %v", resp, err)
+	}
+	cookies := map[string]string{}
+	for _, c := range rr.Result().Cookies() {
+		cookies[c.Name] = c.Value
+	}
+	if cookies[oauthStateCookieName] != provider.state || cookies[oauthNonceCookieName] != provider.nonce || cookies[oauthLinkCookieName] != user.ID.String() {
+		t.Fatalf("unexpected cookies %v", cookies)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/auth/github/link", nil)
+	req.SetPathValue("provider", "github")
+	rr = httptest.NewRecorder()
+	handler.ProviderLink(rr, req.WithContext(SetUserInContext(req.Context(), user)))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown provider, got %d", rr.Code)
+	}
+}
+
+func TestProviderAuthHandler_Callback_LinksForSignedInUser(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	provider := &mockOAuthProvider{
+		provider: services.ProviderGoogle,
+		claims:   services.IdentityClaims{Provider: services.ProviderGoogle, Subject: "sub", Email: "other@gmail.com"},
+	}
+	var linkedUser uuid.UUID
+	var linkedClaims services.IdentityClaims
+	handler := newLinkTestHandler(&mockProviderAuthService{
+		LinkFunc: func(ctx context.Context, claims services.IdentityClaims) (*services.ProviderLinkResult, error) {
+			t.Fatal("expected no login lookup while linking")
+			return nil, nil
+		},
+		LinkToUserFunc: func(ctx context.Context, userID uuid.UUID, claims services.IdentityClaims) error {
+			linkedUser, linkedClaims = userID, claims
+			return nil
+		},
+	}, provider)
+
+	rr := httptest.NewRecorder()
+	handler.ProviderCallback(rr, linkCallbackRequest(user, user.ID.String()))
+
+	if rr.Code != http.StatusFound || rr.Header().Get("Location") != "/profile?linked=google" {
+		t.Fatalf("expected a redirect to the profile, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+	if linkedUser != user.ID || linkedClaims.Subject != "sub" {
+		t.Fatalf("expected sub linked to the signed-in user, got %v %+v", linkedUser, linkedClaims)
+	}
+	for _, c := range rr.Result().Cookies() {
+		if c.Name == sessionCookieName {
+			t.Fatal("expected no new session")
+		}
+	}
+}
+
+func TestProviderAuthHandler_Callback_LinkRejected(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	tests := []struct {
+		name       string
+		linkCookie string
+		linkErr    error
+		want       string
+	}{
+		{"not started by this user", uuid.NewString(), nil, "/profile?link_error=oauth_invalid"},
+		{"used by another account", user.ID.String(), services.ErrProviderIdentityExists, "/profile?link_error=oauth_linked_elsewhere"},
+		{"other identity linked", user.ID.String(), services.ErrProviderAlreadyLinked, "/profile?link_error=oauth_already_linked"},
+		{"failure", user.ID.String(), errors.New("boom"), "/profile?link_error=oauth_link"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := newLinkTestHandler(&mockProviderAuthService{
+				LinkToUserFunc: func(ctx context.Context, userID uuid.UUID, claims services.IdentityClaims) error {
+					called = true
+					return tt.linkErr
+				},
+			}, &mockOAuthProvider{provider: services.ProviderGoogle})
+
+			rr := httptest.NewRecorder()
+			handler.ProviderCallback(rr, linkCallbackRequest(user, tt.linkCookie))
+			if location := rr.Header().Get("Location"); location != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, location)
+			}
+			if called != (tt.linkErr != nil) {
+				t.Fatalf("expected link called=%v", tt.linkErr != nil)
+			}
+		})
+	}
+}
+
+func TestProviderAuthHandler_Callback_SignedInLoginWithoutLinkCookie(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	provider := &mockOAuthProvider{
+		provider: services.ProviderGoogle,
+		claims:   services.IdentityClaims{Provider: services.ProviderGoogle, Subject: "sub", Email: "user@example.com", EmailVerified: true},
+	}
+	handler := NewProviderAuthHandler(&mockProviderAuthService{
+		LinkFunc: func(ctx context.Context, claims services.IdentityClaims) (*services.ProviderLinkResult, error) {
+			return &services.ProviderLinkResult{User: user}, nil
+		},
+		LinkToUserFunc: func(ctx context.Context, userID uuid.UUID, claims services.IdentityClaims) error {
+			t.Fatal("expected a login, not a link")
+			return nil
+		},
+	}, &mockAuthService{
+		CreateSessionFunc: func(ctx context.Context, userID uuid.UUID) (string, error) {
+			return "session-token", nil
+		},
+	}, &fakeRedisClient{}, map[services.Provider]services.OAuthProvider{
+		services.ProviderGoogle: provider,
+	}, false)
+
+	// Starting a login drops the cookie of an abandoned link attempt.
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/google/start", nil)
+	req.SetPathValue("provider", "google")
+	rr := httptest.NewRecorder()
+	handler.ProviderStart(rr, req)
+	cleared := false
+	for _, c := range rr.Result().Cookies() {
+		cleared = cleared || (c.Name == oauthLinkCookieName && c.MaxAge < 0)
+	}
+	if !cleared {
+		t.Fatal("expected the link cookie cleared")
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ProviderCallback(rr, linkCallbackRequest(user, ""))
+	if rr.Code != http.StatusFound || rr.Header().Get("Location") != "/dashboard" {
+		t.Fatalf("expected the usual login redirect, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+	signedIn := false
+	for _, c := range rr.Result().Cookies() {
+		signedIn = signedIn || (c.Name == sessionCookieName && c.Value == "session-token")
+	}
+	if !signedIn {
+		t.Fatal("expected a new session")
+	}
+}
+
+func TestProviderAuthHandler_ListProviders(t *testing.T) {
+	hash := "hash"
+	user := &models.User{ID: uuid.New(), PasswordHash: &hash}
+	email := "me@gmail.com"
+	handler := newLinkTestHandler(&mockProviderAuthService{
+		ListFunc: func(ctx context.Context, userID uuid.UUID) ([]models.LinkedIdentity, error) {
+			return []models.LinkedIdentity{{Provider: "google", Email: &email}}, nil
+		},
+	}, &mockOAuthProvider{provider: services.ProviderGoogle})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/providers", nil)
+	rr := httptest.NewRecorder()
+	handler.ListProviders(rr, req.WithContext(SetUserInContext(req.Context(), user)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp providerListResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Identities) != 1 || resp.Identities[0].Provider != "google" || len(resp.Available) != 1 || !resp.HasPassword {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
+
+func TestProviderAuthHandler_Unlink(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, http.StatusOK},
+		{services.ErrProviderNotLinked, http.StatusNotFound},
+		{services.ErrLastLoginMethod, http.StatusConflict},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		var unlinked services.Provider
+		handler := newLinkTestHandler(&mockProviderAuthService{
+			UnlinkFunc: func(ctx context.Context, userID uuid.UUID, provider services.Provider) error {
+				unlinked = provider
+				return tt.err
+			},
+		}, &mockOAuthProvider{provider: services.ProviderGoogle})
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/auth/providers/Google", nil)
+		req.SetPathValue("provider", "Google")
+		rr := httptest.NewRecorder()
+		handler.ProviderUnlink(rr, req.WithContext(SetUserInContext(req.Context(), user)))
+		if rr.Code != tt.want || unlinked != services.ProviderGoogle {
+			t.Fatalf("%v: expected %d for google, got %d for %q", tt.err, tt.want, rr.Code, unlinked)
+		}
+	}
+}
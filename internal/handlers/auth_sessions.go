@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type SessionsResponse struct {
+	Sessions []models.Session `json:"sessions"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+// sessionContext passes the client details of a login request on to
+// CreateSession, which stores them with the session.
+func sessionContext(r *http.Request) context.Context {
+	return services.WithSessionClient(r.Context(), r.UserAgent(), getClientIP(r))
+}
+
+// currentSessionToken is the session cookie the request was made with.
+func currentSessionToken(r *http.Request) string {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// ListSessions returns the caller's active sessions with the current one
+// flagged.
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if rejectTokenAuth(w, r) {
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(r.Context(), user.ID, currentSessionToken(r))
+	if err != nil {
+		log.Printf("Error listing sessions: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	writeJSON(w, http.StatusOK, SessionsResponse{Sessions: sessions})
+}
+
+// RevokeSession signs one of the caller's sessions out. Revoking the current
+// session also clears its cookie, like logging out.
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if rejectTokenAuth(w, r) {
+		return
+	}
+
+	sessionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	current, err := h.authService.RevokeSession(r.Context(), user.ID, sessionID, currentSessionToken(r))
+	if errors.Is(err, services.ErrSessionNotFound) {
+		writeError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error revoking session: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	if current {
+		h.clearSessionCookie(w)
+	}
+	writeJSON(w, http.StatusOK, RevokeSessionsResponse{Revoked: 1})
+}
+
+// RevokeOtherSessions signs out every session of the caller except the one
+// making the request.
+func (h *AuthHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if rejectTokenAuth(w, r) {
+		return
+	}
+
+	revoked, err := h.authService.RevokeOtherSessions(r.Context(), user.ID, currentSessionToken(r))
+	if err != nil {
+		log.Printf("Error revoking sessions: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	writeJSON(w, http.StatusOK, RevokeSessionsResponse{Revoked: revoked})
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func newSessionRequest(method, target string, user *models.User) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "current-token"})
+	return req.WithContext(SetUserInContext(req.Context(), user))
+}
+
+func TestAuthHandler_ListSessions_FlagsCurrent(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	currentID := uuid.New()
+	handler := NewAuthHandler(nil, &mockAuthService{
+		ListSessionsFunc: func(ctx context.Context, userID uuid.UUID, currentToken string) ([]models.Session, error) {
+			if userID != user.ID || currentToken != "current-token" {
+				t.Fatalf("unexpected arguments: %v %q", userID, currentToken)
+			}
+			return []models.Session{{ID: currentID, UserAgent: "Firefox", Current: true}, {ID: uuid.New()}}, nil
+		},
+	}, nil, false)
+
+	rr := httptest.NewRecorder()
+	handler.ListSessions(rr, newSessionRequest(http.MethodGet, "/api/auth/sessions", user))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var response struct {
+		Sessions []map[string]any `json:"sessions"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(response.Sessions) != 2 || response.Sessions[0]["current"] != true || response.Sessions[0]["user_agent"] != "Firefox" {
+		t.Fatalf("unexpected sessions: %v", response.Sessions)
+	}
+	if _, ok := response.Sessions[0]["token_hash"]; ok {
+		t.Fatal("expected token hashes to stay out of the response")
+	}
+}
+
+func TestAuthHandler_ListSessions_RejectsTokenAuth(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewAuthHandler(nil, &mockAuthService{}, nil, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/sessions", nil)
+	req = req.WithContext(SetAuthInfoInContext(req.Context(), &AuthInfo{User: user, Method: AuthMethodToken, TokenID: uuid.New(), Scope: models.ScopeReadWrite}))
+	rr := httptest.NewRecorder()
+	handler.ListSessions(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusForbidden, "Token authentication not allowed for this endpoint")
+}
+
+func TestAuthHandler_RevokeSession(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name        string
+		id          string
+		current     bool
+		err         error
+		wantStatus  int
+		wantMessage string
+		wantCleared bool
+	}{
+		{name: "other session", id: sessionID.String(), wantStatus: http.StatusOK},
+		{name: "current session clears cookie", id: sessionID.String(), current: true, wantStatus: http.StatusOK, wantCleared: true},
+		{name: "invalid id", id: "nope", wantStatus: http.StatusBadRequest, wantMessage: "Invalid session ID"},
+		{name: "not found", id: sessionID.String(), err: services.ErrSessionNotFound, wantStatus: http.StatusNotFound, wantMessage: "Session not found"},
+		{name: "service error", id: sessionID.String(), err: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantMessage: "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAuthHandler(nil, &mockAuthService{
+				RevokeSessionFunc: func(ctx context.Context, userID, id uuid.UUID, currentToken string) (bool, error) {
+					if userID != user.ID || id != sessionID || currentToken != "current-token" {
+						t.Fatalf("unexpected arguments: %v %v %q", userID, id, currentToken)
+					}
+					return tt.current, tt.err
+				},
+			}, nil, false)
+
+			req := newSessionRequest(http.MethodDelete, "/api/auth/sessions/"+tt.id, user)
+			req.SetPathValue("id", tt.id)
+			rr := httptest.NewRecorder()
+			handler.RevokeSession(rr, req)
+
+			if tt.wantMessage != "" {
+				assertErrorResponse(t, rr, tt.wantStatus, tt.wantMessage)
+				return
+			}
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			cleared := false
+			for _, c := range rr.Result().Cookies() {
+				if c.Name == sessionCookieName && c.MaxAge == -1 {
+					cleared = true
+				}
+			}
+			if cleared != tt.wantCleared {
+				t.Fatalf("expected cookie cleared=%v, got %v", tt.wantCleared, cleared)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_RevokeOtherSessions(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewAuthHandler(nil, &mockAuthService{
+		RevokeOtherSessionsFunc: func(ctx context.Context, userID uuid.UUID, currentToken string) (int, error) {
+			if userID != user.ID || currentToken != "current-token" {
+				t.Fatalf("unexpected arguments: %v %q", userID, currentToken)
+			}
+			return 3, nil
+		},
+	}, nil, false)
+
+	rr := httptest.NewRecorder()
+	handler.RevokeOtherSessions(rr, newSessionRequest(http.MethodDelete, "/api/auth/sessions", user))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var response RevokeSessionsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Revoked != 3 {
+		t.Fatalf("expected 3 revoked, got %d", response.Revoked)
+	}
+	for _, c := range rr.Result().Cookies() {
+		if c.Name == sessionCookieName {
+			t.Fatal("expected the current session cookie to be kept")
+		}
+	}
+}
+
+func TestAuthHandler_RevokeOtherSessions_Unauthenticated(t *testing.T) {
+	handler := NewAuthHandler(nil, &mockAuthService{}, nil, false)
+
+	rr := httptest.NewRecorder()
+	handler.RevokeOtherSessions(rr, httptest.NewRequest(http.MethodDelete, "/api/auth/sessions", nil))
+
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
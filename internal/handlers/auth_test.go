@@ -842,7 +842,7 @@ func TestAuthHandler_MagicLink_Success(t *testing.T) {
 func TestAuthHandler_MagicLinkVerify_MissingToken(t *testing.T) {
 	handler := NewAuthHandler(nil, nil, nil, false)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/auth/magic-link/verify", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/magic-link/verify", strings.NewReader(`{"token":""}`))
 	rr := httptest.NewRecorder()
 
 	handler.MagicLinkVerify(rr, req)
@@ -860,7 +860,7 @@ func TestAuthHandler_MagicLinkVerify_VerifyError(t *testing.T) {
 	}
 	handler := NewAuthHandler(&mockUserService{}, &mockAuthService{}, mockEmail, false)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/auth/magic-link/verify?token=bad", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/magic-link/verify", strings.NewReader(`{"token":"bad"}`))
 	rr := httptest.NewRecorder()
 
 	handler.MagicLinkVerify(rr, req)
@@ -889,7 +889,7 @@ func TestAuthHandler_MagicLinkVerify_CreateSessionError(t *testing.T) {
 	}
 	handler := NewAuthHandler(mockUser, mockAuth, mockEmail, false)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/auth/magic-link/verify?token=token", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/magic-link/verify", strings.NewReader(`{"token":"token"}`))
 	rr := httptest.NewRecorder()
 
 	handler.MagicLinkVerify(rr, req)
@@ -899,6 +899,88 @@ func TestAuthHandler_MagicLinkVerify_CreateSessionError(t *testing.T) {
 	}
 }
 
+// singleUseMagicLink returns handler dependencies whose magic link token can
+// be consumed exactly once, like the real email service.
+func singleUseMagicLink(user *models.User) (*mockUserService, *mockAuthService, *mockEmailService, *int) {
+	consumed := 0
+	mockEmail := &mockEmailService{
+		VerifyMagicLinkFunc: func(ctx context.Context, token string) (string, error) {
+			consumed++
+			if consumed > 1 {
+				return "", errors.New("invalid or expired token")
+			}
+			return user.Email, nil
+		},
+	}
+	mockUser := &mockUserService{
+		GetByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+			return user, nil
+		},
+	}
+	mockAuth := &mockAuthService{
+		CreateSessionFunc: func(ctx context.Context, userID uuid.UUID) (string, error) {
+			return "session-token", nil
+		},
+	}
+	return mockUser, mockAuth, mockEmail, &consumed
+}
+
+func TestAuthHandler_MagicLinkVerify_SurvivesScannerPrefetch(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", EmailVerified: true}
+	mockUser, mockAuth, mockEmail, consumed := singleUseMagicLink(user)
+	handler := NewAuthHandler(mockUser, mockAuth, mockEmail, false)
+
+	// A mail scanner fetches the link the way it appears in the email.
+	prefetch := httptest.NewRecorder()
+	handler.MagicLinkVerifyLegacy(prefetch, httptest.NewRequest(http.MethodGet, "/api/auth/magic-link/verify?token=tok", nil))
+	if prefetch.Code != http.StatusBadRequest {
+		t.Fatalf("expected prefetch to be rejected without side effects, got %d", prefetch.Code)
+	}
+	if *consumed != 0 {
+		t.Fatal("expected prefetch not to consume the token")
+	}
+
+	// The user then confirms on the page.
+	rr := httptest.NewRecorder()
+	handler.MagicLinkVerify(rr, httptest.NewRequest(http.MethodPost, "/api/auth/magic-link/verify", strings.NewReader(`{"token":"tok"}`)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected user confirmation to succeed, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Header().Get("Set-Cookie"), "session-token") {
+		t.Fatal("expected session cookie after confirmation")
+	}
+
+	// Replaying the token fails.
+	rr = httptest.NewRecorder()
+	handler.MagicLinkVerify(rr, httptest.NewRequest(http.MethodPost, "/api/auth/magic-link/verify", strings.NewReader(`{"token":"tok"}`)))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected reused token to fail, got %d", rr.Code)
+	}
+}
+
+func TestAuthHandler_MagicLinkVerifyLegacy_WithFlag(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", EmailVerified: true}
+	mockUser, mockAuth, mockEmail, consumed := singleUseMagicLink(user)
+	handler := NewAuthHandler(mockUser, mockAuth, mockEmail, false)
+
+	rr := httptest.NewRecorder()
+	handler.MagicLinkVerifyLegacy(rr, httptest.NewRequest(http.MethodGet, "/api/auth/magic-link/verify?token=tok&legacy=1", nil))
+	if rr.Code != http.StatusOK || *consumed != 1 {
+		t.Fatalf("expected legacy GET to sign in, got %d (consumed %d)", rr.Code, *consumed)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.MagicLinkVerifyLegacy(rr, httptest.NewRequest(http.MethodGet, "/api/auth/magic-link/verify?legacy=1", nil))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Token is required")
+}
+
+func TestAuthHandler_MagicLinkVerify_InvalidBody(t *testing.T) {
+	handler := NewAuthHandler(nil, nil, nil, false)
+	rr := httptest.NewRecorder()
+	handler.MagicLinkVerify(rr, httptest.NewRequest(http.MethodPost, "/api/auth/magic-link/verify", strings.NewReader("invalid")))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid request body")
+}
+
 func TestAuthHandler_ForgotPassword_InvalidBody(t *testing.T) {
 	handler := NewAuthHandler(nil, nil, nil, false)
 
@@ -1378,7 +1460,7 @@ func TestAuthHandler_MagicLinkVerify_Success(t *testing.T) {
 	}
 	handler := NewAuthHandler(mockUser, mockAuth, mockEmail, false)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/auth/magic-link/verify?token=abc", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/magic-link/verify", strings.NewReader(`{"token":"abc"}`))
 	rr := httptest.NewRecorder()
 
 	handler.MagicLinkVerify(rr, req)
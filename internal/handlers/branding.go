@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"html/template"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+)
+
+// BrandData is the deployment branding available to page templates.
+type BrandData struct {
+	Name    string
+	LogoURL string
+	// PrimaryColor is validated by config.BrandingConfig.Validate, so it is
+	// safe to emit into the inline theme override.
+	PrimaryColor template.CSS
+}
+
+func newBrandData(branding config.BrandingConfig) BrandData {
+	return BrandData{
+		Name:         branding.DisplayName(),
+		LogoURL:      branding.LogoURL,
+		PrimaryColor: template.CSS(branding.PrimaryColor),
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
@@ -17,13 +19,22 @@ import (
 )
 
 type CardHandler struct {
-	cardService services.CardServiceInterface
+	cursorPager
+	cardService        services.CardServiceInterface
+	shareSubscriptions services.ShareSubscriptionServiceInterface
+	draftStates        services.CardDraftStateServiceInterface
 }
 
 func NewCardHandler(cardService services.CardServiceInterface) *CardHandler {
 	return &CardHandler{cardService: cardService}
 }
 
+// SetShareSubscriptionService lists and removes a shared card's email
+// watchers in the share status endpoints.
+func (h *CardHandler) SetShareSubscriptionService(shareSubscriptions services.ShareSubscriptionServiceInterface) {
+	h.shareSubscriptions = shareSubscriptions
+}
+
 type CreateCardRequest struct {
 	Year         int     `json:"year"`
 	Category     *string `json:"category,omitempty"`
@@ -31,6 +42,8 @@ type CreateCardRequest struct {
 	GridSize     *int    `json:"grid_size,omitempty"`
 	HeaderText   *string `json:"header_text,omitempty"`
 	HasFreeSpace *bool   `json:"has_free_space,omitempty"`
+	StartDate    *string `json:"start_date,omitempty"` // YYYY-MM-DD; defaults to Jan 1 of year
+	EndDate      *string `json:"end_date,omitempty"`   // YYYY-MM-DD; defaults to Dec 31 of year
 }
 
 type UpdateCardMetaRequest struct {
@@ -48,13 +61,18 @@ type CategoriesResponse struct {
 }
 
 type AddItemRequest struct {
-	Content  string `json:"content"`
-	Position *int   `json:"position,omitempty"`
+	Content    string  `json:"content"`
+	Position   *int    `json:"position,omitempty"`
+	IsPrivate  bool    `json:"is_private,omitempty"`
+	Difficulty *string `json:"difficulty,omitempty"`
 }
 
 type UpdateItemRequest struct {
-	Content  *string `json:"content,omitempty"`
-	Position *int    `json:"position,omitempty"`
+	Content   *string `json:"content,omitempty"`
+	Position  *int    `json:"position,omitempty"`
+	IsPrivate *bool   `json:"is_private,omitempty"`
+	// Difficulty sets the item's tag; an empty string clears it.
+	Difficulty *string `json:"difficulty,omitempty"`
 }
 
 type CompleteItemRequest struct {
@@ -62,6 +80,35 @@ type CompleteItemRequest struct {
 	ProofURL *string `json:"proof_url,omitempty"`
 }
 
+// CompleteByContentRequest identifies the goal to complete by its text. With
+// exact false, a goal containing the text also matches.
+type CompleteByContentRequest struct {
+	Content  string  `json:"content"`
+	Exact    bool    `json:"exact"`
+	Notes    *string `json:"notes,omitempty"`
+	ProofURL *string `json:"proof_url,omitempty"`
+}
+
+// ItemCandidate is one goal that matched an ambiguous content lookup.
+type ItemCandidate struct {
+	Position int    `json:"position"`
+	Content  string `json:"content"`
+}
+
+type AmbiguousItemResponse struct {
+	Error      string          `json:"error"`
+	Candidates []ItemCandidate `json:"candidates"`
+}
+
+// ErrorCodeProofRequired marks a completion rejected by a require_proof card,
+// so clients can prompt for a note or proof URL.
+const ErrorCodeProofRequired = "proof_required"
+
+type CompleteItemErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
 type UpdateNotesRequest struct {
 	Notes    *string `json:"notes,omitempty"`
 	ProofURL *string `json:"proof_url,omitempty"`
@@ -75,30 +122,68 @@ type UpdateVisibilityRequest struct {
 	VisibleToFriends bool `json:"visible_to_friends"`
 }
 
+// BulkUpdateVisibilityRequest accepts either per-card Changes or the legacy
+// CardIDs list with a single VisibleToFriends value applied to all of them.
 type BulkUpdateVisibilityRequest struct {
-	CardIDs          []string `json:"card_ids"`
-	VisibleToFriends bool     `json:"visible_to_friends"`
+	Changes          []BulkVisibilityChange `json:"changes,omitempty"`
+	CardIDs          []string               `json:"card_ids,omitempty"`
+	VisibleToFriends bool                   `json:"visible_to_friends"`
 }
 
-type BulkUpdateVisibilityResponse struct {
-	UpdatedCount int `json:"updated_count"`
+type BulkVisibilityChange struct {
+	CardID           string `json:"card_id"`
+	VisibleToFriends bool   `json:"visible_to_friends"`
 }
 
 type BulkDeleteRequest struct {
 	CardIDs []string `json:"card_ids"`
 }
 
-type BulkDeleteResponse struct {
-	DeletedCount int `json:"deleted_count"`
-}
-
 type BulkUpdateArchiveRequest struct {
 	CardIDs    []string `json:"card_ids"`
 	IsArchived bool     `json:"is_archived"`
 }
 
-type BulkUpdateArchiveResponse struct {
-	UpdatedCount int `json:"updated_count"`
+// BulkResponse is the body of every bulk endpoint: the IDs the operation was
+// applied to, the ones it wasn't with a reason, and how many were requested.
+// Error carries the usual message when the request as a whole failed.
+type BulkResponse struct {
+	Succeeded []string      `json:"succeeded"`
+	Failed    []BulkFailure `json:"failed"`
+	Total     int           `json:"total"`
+	Error     string        `json:"error,omitempty"`
+}
+
+type BulkFailure struct {
+	ID      string `json:"id"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Failure codes in BulkResponse. ErrorCodeNotFound marks a card that doesn't
+// exist or isn't the caller's; ErrorCodeAborted marks a card left unchanged
+// because another card failed an all-or-nothing request.
+const (
+	ErrorCodeNotFound = "not_found"
+	ErrorCodeAborted  = "aborted"
+)
+
+// newBulkResponse reports every requested ID in succeeded as applied and the
+// rest as not found.
+func newBulkResponse(requested, succeeded []uuid.UUID) BulkResponse {
+	done := make(map[uuid.UUID]bool, len(succeeded))
+	for _, id := range succeeded {
+		done[id] = true
+	}
+	resp := BulkResponse{Succeeded: []string{}, Failed: []BulkFailure{}, Total: len(requested)}
+	for _, id := range requested {
+		if done[id] {
+			resp.Succeeded = append(resp.Succeeded, id.String())
+		} else {
+			resp.Failed = append(resp.Failed, BulkFailure{ID: id.String(), Code: ErrorCodeNotFound, Message: "Card not found"})
+		}
+	}
+	return resp
 }
 
 // ImportCardRequest represents a request to import an anonymous card
@@ -112,6 +197,12 @@ type ImportCardRequest struct {
 	FreeSpacePosition *int             `json:"free_space_position,omitempty"`
 	Items             []ImportCardItem `json:"items"`
 	Finalize          bool             `json:"finalize"`
+	StartDate         *string          `json:"start_date,omitempty"`
+	EndDate           *string          `json:"end_date,omitempty"`
+	// TargetCardID merges the items into an existing draft instead of
+	// creating a card; card settings in the payload are then ignored.
+	TargetCardID *string `json:"target_card_id,omitempty"`
+	DryRun       bool    `json:"dry_run,omitempty"`
 }
 
 type ImportCardItem struct {
@@ -143,6 +234,10 @@ type CardResponse struct {
 	Message string              `json:"message,omitempty"`
 }
 
+type CardRecommendationsResponse struct {
+	Recommendations []models.CardRecommendation `json:"recommendations"`
+}
+
 func (h *CardHandler) Create(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
@@ -156,12 +251,21 @@ func (h *CardHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Default to current year if not specified
+	startDate, endDate, ok := parseCardPeriod(w, req.StartDate, req.EndDate)
+	if !ok {
+		return
+	}
+
+	// Default to the start date's year, then the current year, if not specified
+	if req.Year == 0 && startDate != nil {
+		req.Year = startDate.Year()
+	}
 	if req.Year == 0 {
 		req.Year = time.Now().Year()
 	}
 
-	// Validate year (reasonable range: 2020 to next year)
+	// Sanity-check the display year (2020 to next year); the card's active
+	// window comes from its start/end dates.
 	currentYear := time.Now().Year()
 	if req.Year < 2020 || req.Year > currentYear+1 {
 		writeError(w, http.StatusBadRequest, "Year must be between 2020 and next year")
@@ -222,13 +326,15 @@ func (h *CardHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	card, err := h.cardService.Create(r.Context(), models.CreateCardParams{
-		UserID:   user.ID,
-		Year:     req.Year,
-		Category: req.Category,
-		Title:    req.Title,
-		GridSize: gridSize,
-		Header:   headerText,
-		HasFree:  hasFreeSpace,
+		UserID:    user.ID,
+		Year:      req.Year,
+		Category:  req.Category,
+		Title:     req.Title,
+		GridSize:  gridSize,
+		Header:    headerText,
+		HasFree:   hasFreeSpace,
+		StartDate: startDate,
+		EndDate:   endDate,
 	})
 	// These errors shouldn't happen since we checked above, but handle gracefully
 	if errors.Is(err, services.ErrCardAlreadyExists) {
@@ -251,6 +357,10 @@ func (h *CardHandler) Create(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "Grid size must be 2, 3, 4, or 5")
 		return
 	}
+	if errors.Is(err, services.ErrInvalidCardPeriod) {
+		writeError(w, http.StatusBadRequest, cardPeriodErrorMessage)
+		return
+	}
 	if errors.Is(err, services.ErrInvalidHeaderText) {
 		writeError(w, http.StatusBadRequest, "Invalid header text")
 		return
@@ -261,6 +371,7 @@ func (h *CardHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.clearDraftState(r, user.ID)
 	writeJSON(w, http.StatusCreated, CardResponse{Card: card})
 }
 
@@ -373,15 +484,24 @@ func (h *CardHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "Content is required")
 		return
 	}
-	if len(req.Content) > 500 {
-		writeError(w, http.StatusBadRequest, "Content must be 500 characters or less")
+	if utf8.RuneCountInString(req.Content) > models.MaxItemContentLength {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Content must be %d characters or less", models.MaxItemContentLength))
+		return
+	}
+	if req.Difficulty != nil && *req.Difficulty == "" {
+		req.Difficulty = nil
+	}
+	if req.Difficulty != nil && !models.IsValidDifficulty(*req.Difficulty) {
+		writeError(w, http.StatusBadRequest, "Difficulty must be easy, medium or hard")
 		return
 	}
 
 	item, err := h.cardService.AddItem(r.Context(), user.ID, models.AddItemParams{
-		CardID:   cardID,
-		Content:  req.Content,
-		Position: req.Position,
+		CardID:     cardID,
+		Content:    req.Content,
+		Position:   req.Position,
+		IsPrivate:  req.IsPrivate,
+		Difficulty: req.Difficulty,
 	})
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
@@ -417,8 +537,10 @@ func (h *CardHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 }
 
 type UpdateCardConfigRequest struct {
-	HeaderText   *string `json:"header_text,omitempty"`
-	HasFreeSpace *bool   `json:"has_free_space,omitempty"`
+	HeaderText    *string `json:"header_text,omitempty"`
+	HasFreeSpace  *bool   `json:"has_free_space,omitempty"`
+	FreeSpaceText *string `json:"free_space_text,omitempty"`
+	RequireProof  *bool   `json:"require_proof,omitempty"`
 }
 
 func (h *CardHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
@@ -446,8 +568,10 @@ func (h *CardHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 	}
 
 	card, err := h.cardService.UpdateConfig(r.Context(), user.ID, cardID, models.UpdateCardConfigParams{
-		HeaderText:   req.HeaderText,
-		HasFreeSpace: req.HasFreeSpace,
+		HeaderText:    req.HeaderText,
+		HasFreeSpace:  req.HasFreeSpace,
+		FreeSpaceText: req.FreeSpaceText,
+		RequireProof:  req.RequireProof,
 	})
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
@@ -465,10 +589,18 @@ func (h *CardHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "Invalid header text")
 		return
 	}
+	if errors.Is(err, services.ErrInvalidFreeSpaceText) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Free space text must be %d characters or less", models.MaxFreeSpaceTextLength))
+		return
+	}
 	if errors.Is(err, services.ErrNoSpaceForFree) {
 		writeError(w, http.StatusBadRequest, "Your card is full. Remove an item to add or move the FREE space.")
 		return
 	}
+	if errors.Is(err, services.ErrInvalidPosition) {
+		writeError(w, http.StatusConflict, "Goal positions changed; reload the card and try again")
+		return
+	}
 	if err != nil {
 		log.Printf("Error updating card config: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -598,15 +730,22 @@ func (h *CardHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusBadRequest, "Content cannot be empty")
 			return
 		}
-		if len(*req.Content) > 500 {
-			writeError(w, http.StatusBadRequest, "Content must be 500 characters or less")
+		if utf8.RuneCountInString(*req.Content) > models.MaxItemContentLength {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Content must be %d characters or less", models.MaxItemContentLength))
 			return
 		}
 	}
 
+	if req.Difficulty != nil && *req.Difficulty != "" && !models.IsValidDifficulty(*req.Difficulty) {
+		writeError(w, http.StatusBadRequest, "Difficulty must be easy, medium or hard")
+		return
+	}
+
 	item, err := h.cardService.UpdateItem(r.Context(), user.ID, cardID, position, models.UpdateItemParams{
-		Content:  req.Content,
-		Position: req.Position,
+		Content:    req.Content,
+		Position:   req.Position,
+		IsPrivate:  req.IsPrivate,
+		Difficulty: req.Difficulty,
 	})
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
@@ -686,6 +825,16 @@ func (h *CardHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, CardResponse{Message: "Item removed"})
 }
 
+// ShuffleRequest optionally fixes the shuffle seed to reproduce a layout.
+type ShuffleRequest struct {
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+type ShuffleResponse struct {
+	Card *models.BingoCard `json:"card"`
+	Seed int64             `json:"seed"`
+}
+
 func (h *CardHandler) Shuffle(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
@@ -699,7 +848,19 @@ func (h *CardHandler) Shuffle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	card, err := h.cardService.Shuffle(r.Context(), user.ID, cardID)
+	var req ShuffleRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	result, err := h.cardService.Shuffle(r.Context(), user.ID, cardID, req.Seed)
+	if errors.Is(err, services.ErrInvalidShuffleSeed) {
+		writeError(w, http.StatusBadRequest, "Seed must be a whole number between 0 and 9007199254740991")
+		return
+	}
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -712,12 +873,64 @@ func (h *CardHandler) Shuffle(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "Card is finalized and cannot be modified")
 		return
 	}
+	if errors.Is(err, services.ErrInvalidPosition) {
+		writeError(w, http.StatusConflict, "Goal positions changed; reload the card and try again")
+		return
+	}
 	if err != nil {
 		log.Printf("Error shuffling card: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
+	writeJSON(w, http.StatusOK, ShuffleResponse{Card: result.Card, Seed: result.Seed})
+}
+
+// UndoShuffle restores the layout from before the latest shuffle.
+func (h *CardHandler) UndoShuffle(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	card, err := h.cardService.UndoShuffle(r.Context(), user.ID, cardID)
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if errors.Is(err, services.ErrCardFinalized) {
+		writeError(w, http.StatusBadRequest, "Card is finalized and cannot be modified")
+		return
+	}
+	if errors.Is(err, services.ErrNoShuffleToUndo) {
+		writeError(w, http.StatusConflict, "There is no shuffle to undo")
+		return
+	}
+	if errors.Is(err, services.ErrShuffleUndoStale) {
+		writeError(w, http.StatusConflict, "Goals changed since the last shuffle, so it can no longer be undone")
+		return
+	}
+	if errors.Is(err, services.ErrInvalidPosition) {
+		writeError(w, http.StatusConflict, "Goal positions changed; reload the card and try again")
+		return
+	}
+	if err != nil {
+		log.Printf("Error undoing shuffle: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
 	writeJSON(w, http.StatusOK, CardResponse{Card: card})
 }
 
@@ -842,8 +1055,19 @@ func (h *CardHandler) CompleteItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A multipart body can carry a proof photo as "proof".
 	var req CompleteItemRequest
-	if r.ContentLength > 0 {
+	var proofImage []byte
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		req, proofImage, err = readCompleteItemForm(w, r)
+		if writeProofImageError(w, err) {
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	} else if r.ContentLength > 0 {
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			writeError(w, http.StatusBadRequest, "Invalid request body")
 			return
@@ -851,8 +1075,9 @@ func (h *CardHandler) CompleteItem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	item, err := h.cardService.CompleteItem(r.Context(), user.ID, cardID, position, models.CompleteItemParams{
-		Notes:    req.Notes,
-		ProofURL: req.ProofURL,
+		Notes:      req.Notes,
+		ProofURL:   req.ProofURL,
+		ProofImage: proofImage,
 	})
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
@@ -870,6 +1095,20 @@ func (h *CardHandler) CompleteItem(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "Card must be finalized first")
 		return
 	}
+	if errors.Is(err, services.ErrProofRequired) {
+		writeJSON(w, http.StatusBadRequest, CompleteItemErrorResponse{
+			Error: "This card requires a note or proof URL to complete a goal",
+			Code:  ErrorCodeProofRequired,
+		})
+		return
+	}
+	if errors.Is(err, services.ErrItemCompleted) {
+		writeError(w, http.StatusConflict, "Goal is already completed; edit its notes or proof instead")
+		return
+	}
+	if writeProofImageError(w, err) {
+		return
+	}
 	if err != nil {
 		log.Printf("Error completing item: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -879,6 +1118,83 @@ func (h *CardHandler) CompleteItem(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, CardResponse{Item: item})
 }
 
+// CompleteItemByContent completes the goal matching the request's content,
+// so scripts don't need to know its position.
+func (h *CardHandler) CompleteItemByContent(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	var req CompleteByContentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		writeError(w, http.StatusBadRequest, "Content is required")
+		return
+	}
+
+	item, err := h.cardService.CompleteItemByContent(r.Context(), user.ID, cardID, req.Content, req.Exact, models.CompleteItemParams{
+		Notes:    req.Notes,
+		ProofURL: req.ProofURL,
+	})
+	var ambiguous *services.ItemAmbiguousError
+	if errors.As(err, &ambiguous) {
+		candidates := make([]ItemCandidate, len(ambiguous.Candidates))
+		for i, c := range ambiguous.Candidates {
+			candidates[i] = ItemCandidate{Position: c.Position, Content: c.Content}
+		}
+		writeJSON(w, http.StatusConflict, AmbiguousItemResponse{
+			Error:      "More than one goal matches; use a more specific content or the position",
+			Candidates: candidates,
+		})
+		return
+	}
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrItemNotFound) {
+		writeError(w, http.StatusNotFound, "No goal matches that content")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if errors.Is(err, services.ErrCardNotFinalized) {
+		writeError(w, http.StatusBadRequest, "Card must be finalized first")
+		return
+	}
+	if errors.Is(err, services.ErrProofRequired) {
+		writeJSON(w, http.StatusBadRequest, CompleteItemErrorResponse{
+			Error: "This card requires a note or proof URL to complete a goal",
+			Code:  ErrorCodeProofRequired,
+		})
+		return
+	}
+	if errors.Is(err, services.ErrItemCompleted) {
+		writeError(w, http.StatusConflict, "Goal is already completed; edit its notes or proof instead")
+		return
+	}
+	if err != nil {
+		log.Printf("Error completing item by content: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CardResponse{Item: item})
+}
+
 func (h *CardHandler) UncompleteItem(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
@@ -971,6 +1287,35 @@ func (h *CardHandler) UpdateNotes(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, CardResponse{Item: item})
 }
 
+const cardPeriodErrorMessage = "End date must be after start date and at most 18 months later"
+
+// parseCardPeriod parses optional YYYY-MM-DD start/end dates, writing a 400
+// and returning ok=false when either is malformed.
+func parseCardPeriod(w http.ResponseWriter, start, end *string) (*time.Time, *time.Time, bool) {
+	startDate, err := parseCardDate(start)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Dates must use YYYY-MM-DD format")
+		return nil, nil, false
+	}
+	endDate, err := parseCardDate(end)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Dates must use YYYY-MM-DD format")
+		return nil, nil, false
+	}
+	return startDate, endDate, true
+}
+
+func parseCardDate(raw *string) (*time.Time, error) {
+	if raw == nil || strings.TrimSpace(*raw) == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(models.CardDateLayout, strings.TrimSpace(*raw))
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
 func parseCardID(r *http.Request) (uuid.UUID, error) {
 	// Extract card ID from path: /api/cards/{id}
 	path := r.URL.Path
@@ -1016,6 +1361,42 @@ func (h *CardHandler) Archive(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, CardResponse{Cards: cards})
 }
 
+type MemoriesResponse struct {
+	Memories []models.Memory `json:"memories"`
+}
+
+// Memories returns goals the user completed around today's date in earlier
+// years. An optional IANA `tz` sets which day counts as today (default UTC).
+func (h *CardHandler) Memories(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	loc := time.UTC
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		parsed, err := time.LoadLocation(tz)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid timezone")
+			return
+		}
+		loc = parsed
+	}
+
+	memories, err := h.cardService.GetMemories(r.Context(), user.ID, time.Now(), loc)
+	if err != nil {
+		log.Printf("Error getting memories: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if memories == nil {
+		memories = []models.Memory{}
+	}
+
+	writeJSON(w, http.StatusOK, MemoriesResponse{Memories: memories})
+}
+
 func (h *CardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
@@ -1047,6 +1428,74 @@ func (h *CardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, CardResponse{Stats: stats})
 }
 
+type CardProgressResponse struct {
+	Cards []models.CardProgress `json:"cards"`
+}
+
+// AllStats returns the dashboard summary of every card the user owns.
+// Archived cards are included only with include_archived=true.
+func (h *CardHandler) AllStats(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	includeArchived, _ := strconv.ParseBool(r.URL.Query().Get("include_archived"))
+	cards, err := h.cardService.ListProgress(r.Context(), user.ID, includeArchived)
+	if err != nil {
+		log.Printf("Error getting card progress: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if cards == nil {
+		cards = []models.CardProgress{}
+	}
+
+	writeJSON(w, http.StatusOK, CardProgressResponse{Cards: cards})
+}
+
+func (h *CardHandler) Recommendations(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	limit := 3
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 || parsed > services.MaxCardRecommendations {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	recs, err := h.cardService.GetRecommendations(r.Context(), user.ID, cardID, limit)
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting recommendations: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CardRecommendationsResponse{Recommendations: recs})
+}
+
 func (h *CardHandler) UpdateMeta(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
@@ -1155,30 +1604,67 @@ func (h *CardHandler) BulkUpdateVisibility(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if len(req.CardIDs) == 0 {
+	changes := req.Changes
+	if len(changes) == 0 {
+		for _, idStr := range req.CardIDs {
+			changes = append(changes, BulkVisibilityChange{CardID: idStr, VisibleToFriends: req.VisibleToFriends})
+		}
+	}
+	if len(changes) == 0 {
 		writeError(w, http.StatusBadRequest, "At least one card ID is required")
 		return
 	}
 
-	// Parse card IDs
-	cardIDs := make([]uuid.UUID, 0, len(req.CardIDs))
-	for _, idStr := range req.CardIDs {
-		id, err := uuid.Parse(idStr)
+	parsed := make([]models.CardVisibilityChange, 0, len(changes))
+	seen := make(map[uuid.UUID]bool, len(changes))
+	for _, change := range changes {
+		id, err := uuid.Parse(change.CardID)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "Invalid card ID: "+idStr)
+			writeError(w, http.StatusBadRequest, "Invalid card ID: "+change.CardID)
 			return
 		}
-		cardIDs = append(cardIDs, id)
+		if seen[id] {
+			writeError(w, http.StatusBadRequest, "Duplicate card ID: "+change.CardID)
+			return
+		}
+		seen[id] = true
+		parsed = append(parsed, models.CardVisibilityChange{CardID: id, VisibleToFriends: change.VisibleToFriends})
 	}
 
-	count, err := h.cardService.BulkUpdateVisibility(r.Context(), user.ID, cardIDs, req.VisibleToFriends)
+	cardIDs := make([]uuid.UUID, len(parsed))
+	for i, change := range parsed {
+		cardIDs[i] = change.CardID
+	}
+
+	// Visibility changes are all-or-nothing: one card that isn't the caller's
+	// fails the whole request and the rest are reported as aborted.
+	_, err := h.cardService.BulkUpdateVisibility(r.Context(), user.ID, parsed)
 	if err != nil {
+		var notOwned *services.CardsNotOwnedError
+		if errors.As(err, &notOwned) {
+			resp := newBulkResponse(cardIDs, nil)
+			missing := make(map[string]bool, len(notOwned.CardIDs))
+			ids := make([]string, len(notOwned.CardIDs))
+			for i, id := range notOwned.CardIDs {
+				missing[id.String()] = true
+				ids[i] = id.String()
+			}
+			for i, failure := range resp.Failed {
+				if !missing[failure.ID] {
+					resp.Failed[i].Code = ErrorCodeAborted
+					resp.Failed[i].Message = "Not changed because another card was not found"
+				}
+			}
+			resp.Error = "Cards not found: " + strings.Join(ids, ", ")
+			writeJSON(w, http.StatusNotFound, resp)
+			return
+		}
 		log.Printf("Error bulk updating visibility: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, BulkUpdateVisibilityResponse{UpdatedCount: count})
+	writeJSON(w, http.StatusOK, newBulkResponse(cardIDs, cardIDs))
 }
 
 func (h *CardHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
@@ -1210,14 +1696,14 @@ func (h *CardHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
 		cardIDs = append(cardIDs, id)
 	}
 
-	count, err := h.cardService.BulkDelete(r.Context(), user.ID, cardIDs)
+	deleted, err := h.cardService.BulkDelete(r.Context(), user.ID, cardIDs)
 	if err != nil {
 		log.Printf("Error bulk deleting cards: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, BulkDeleteResponse{DeletedCount: count})
+	writeJSON(w, http.StatusOK, newBulkResponse(cardIDs, deleted))
 }
 
 func (h *CardHandler) BulkUpdateArchive(w http.ResponseWriter, r *http.Request) {
@@ -1249,14 +1735,14 @@ func (h *CardHandler) BulkUpdateArchive(w http.ResponseWriter, r *http.Request)
 		cardIDs = append(cardIDs, id)
 	}
 
-	count, err := h.cardService.BulkUpdateArchive(r.Context(), user.ID, cardIDs, req.IsArchived)
+	updated, err := h.cardService.BulkUpdateArchive(r.Context(), user.ID, cardIDs, req.IsArchived)
 	if err != nil {
 		log.Printf("Error bulk updating archive status: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, BulkUpdateArchiveResponse{UpdatedCount: count})
+	writeJSON(w, http.StatusOK, newBulkResponse(cardIDs, updated))
 }
 
 func (h *CardHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
@@ -1305,7 +1791,9 @@ func (h *CardHandler) ListExportable(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, CardResponse{Cards: allCards})
 }
 
-// Import imports an anonymous card, creating the card and all items in one transaction
+// Import imports an anonymous card, creating the card and all items in one
+// transaction. A ZIP or multipart body is an account export instead (see
+// importAccountExport).
 func (h *CardHandler) Import(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
@@ -1313,12 +1801,30 @@ func (h *CardHandler) Import(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); isAccountExportUpload(mediaType) {
+		h.importAccountExport(w, r, user, mediaType)
+		return
+	}
+
 	var req ImportCardRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
+	if req.TargetCardID != nil {
+		h.mergeImport(w, r, user, req)
+		return
+	}
+
+	startDate, endDate, ok := parseCardPeriod(w, req.StartDate, req.EndDate)
+	if !ok {
+		return
+	}
+	if req.Year == 0 && startDate != nil {
+		req.Year = startDate.Year()
+	}
+
 	// Validate year
 	currentYear := time.Now().Year()
 	if req.Year < 2020 || req.Year > currentYear+1 {
@@ -1401,6 +1907,10 @@ func (h *CardHandler) Import(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if clientGone(r) {
+		return
+	}
+
 	// Convert request items to models
 	items := make([]models.ImportItem, len(req.Items))
 	for i, item := range req.Items {
@@ -1422,6 +1932,8 @@ func (h *CardHandler) Import(w http.ResponseWriter, r *http.Request) {
 		HeaderText:   headerText,
 		HasFreeSpace: hasFreeSpace,
 		FreeSpacePos: req.FreeSpacePosition,
+		StartDate:    startDate,
+		EndDate:      endDate,
 	})
 	if errors.Is(err, services.ErrInvalidCategory) {
 		writeError(w, http.StatusBadRequest, "Invalid category")
@@ -1439,6 +1951,10 @@ func (h *CardHandler) Import(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "Grid size must be 2, 3, 4, or 5")
 		return
 	}
+	if errors.Is(err, services.ErrInvalidCardPeriod) {
+		writeError(w, http.StatusBadRequest, cardPeriodErrorMessage)
+		return
+	}
 	if errors.Is(err, services.ErrInvalidHeaderText) {
 		writeError(w, http.StatusBadRequest, "Invalid header text")
 		return
@@ -1451,3 +1967,61 @@ func (h *CardHandler) Import(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusCreated, CardResponse{Card: card})
 }
+
+// mergeImport appends import items into an existing draft card.
+func (h *CardHandler) mergeImport(w http.ResponseWriter, r *http.Request, user *models.User, req ImportCardRequest) {
+	cardID, err := uuid.Parse(*req.TargetCardID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid target card ID")
+		return
+	}
+	if len(req.Items) == 0 {
+		writeError(w, http.StatusBadRequest, "At least one item is required")
+		return
+	}
+
+	items := make([]models.ImportItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		content := strings.TrimSpace(item.Content)
+		if content == "" {
+			writeError(w, http.StatusBadRequest, "Content is required")
+			return
+		}
+		if utf8.RuneCountInString(content) > models.MaxItemContentLength {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Content must be %d characters or less", models.MaxItemContentLength))
+			return
+		}
+		items = append(items, models.ImportItem{Content: content})
+	}
+
+	result, err := h.cardService.MergeImport(r.Context(), models.MergeImportParams{
+		UserID: user.ID,
+		CardID: cardID,
+		Items:  items,
+		DryRun: req.DryRun,
+	})
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if errors.Is(err, services.ErrCardFinalized) {
+		writeError(w, http.StatusBadRequest, "Cannot import into a finalized card")
+		return
+	}
+	if errors.Is(err, services.ErrNotEnoughOpenSquares) {
+		// The wrapped message carries the shortfall, e.g. "... (2 short)".
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Error merging import: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// maxAccountExportImportBytes bounds an uploaded account export. Only its
+// cards and goals are read, but the whole archive has to be uploaded.
+const maxAccountExportImportBytes = 10 << 20
+
+// isAccountExportUpload reports whether an import request carries an account
+// export rather than a JSON card.
+func isAccountExportUpload(mediaType string) bool {
+	return mediaType == "application/zip" || mediaType == "multipart/form-data"
+}
+
+// importAccountExport recreates the cards in an account export as drafts.
+// The body is the export ZIP itself (application/zip) or a multipart form
+// with the ZIP as "file" or its CSVs as "cards" and "items". Completed goals
+// stay completed only with `include_completions=true`.
+func (h *CardHandler) importAccountExport(w http.ResponseWriter, r *http.Request, user *models.User, mediaType string) {
+	includeCompletions, _ := strconv.ParseBool(r.URL.Query().Get("include_completions"))
+	r.Body = http.MaxBytesReader(w, r.Body, maxAccountExportImportBytes)
+
+	var cards []models.ImportCardParams
+	var err error
+	if mediaType == "application/zip" {
+		data, readErr := io.ReadAll(r.Body)
+		if readErr != nil {
+			writeError(w, http.StatusBadRequest, "Invalid account export")
+			return
+		}
+		cards, err = services.ReadAccountExportZip(bytes.NewReader(data), int64(len(data)), includeCompletions)
+	} else {
+		cards, err = readAccountExportForm(r, includeCompletions)
+	}
+	if errors.Is(err, services.ErrInvalidAccountExport) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid account export")
+		return
+	}
+	if len(cards) == 0 {
+		writeError(w, http.StatusBadRequest, "The account export has no cards")
+		return
+	}
+
+	if clientGone(r) {
+		return
+	}
+
+	result, err := h.cardService.ImportAccountExport(r.Context(), user.ID, cards)
+	if errors.Is(err, services.ErrInvalidAccountExport) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil && clientGone(r) {
+		return
+	}
+	if err != nil {
+		log.Printf("Error importing account export: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	status := http.StatusOK
+	if len(result.Cards) > 0 {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, result)
+}
+
+// readAccountExportForm reads the cards from a multipart account export
+// upload: the ZIP as "file", or "cards" and "items" CSV files.
+func readAccountExportForm(r *http.Request, includeCompletions bool) ([]models.ImportCardParams, error) {
+	if err := r.ParseMultipartForm(maxAccountExportImportBytes); err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	if file, header, err := r.FormFile("file"); err == nil {
+		defer func() { _ = file.Close() }()
+		return services.ReadAccountExportZip(file, header.Size, includeCompletions)
+	}
+
+	cards, _, err := r.FormFile("cards")
+	if err != nil {
+		return nil, formFileError("cards", err)
+	}
+	defer func() { _ = cards.Close() }()
+	items, _, err := r.FormFile("items")
+	if err != nil {
+		return nil, formFileError("items", err)
+	}
+	defer func() { _ = items.Close() }()
+	return services.ReadAccountExportCSV(cards, items, includeCompletions)
+}
+
+func formFileError(field string, err error) error {
+	if errors.Is(err, http.ErrMissingFile) {
+		return fmt.Errorf("%w: upload the export ZIP as file, or %s.csv as %s", services.ErrInvalidAccountExport, field, field)
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	testExportCardID = uuid.New()
+	testExportCards  = "id,user_id,year,category,title,grid_size,header_text,has_free_space,free_space_position,is_active,is_finalized,visible_to_friends,is_archived,created_at,updated_at,free_space_text,start_date,end_date,require_proof\n" +
+		testExportCardID.String() + "," + uuid.NewString() + ",2025,,Travel,2,BI,false,,true,true,true,false,2025-01-01T00:00:00Z,2025-01-01T00:00:00Z,,2025-01-01,2025-12-31,false\n"
+	testExportItems = "id,card_id,position,content,is_completed,completed_at,notes,proof_url,created_at,is_private,difficulty\n" +
+		uuid.NewString() + "," + testExportCardID.String() + ",0,Visit Lisbon,true,2025-03-01T00:00:00Z,Loved it,,2025-01-01T00:00:00Z,false,\n"
+)
+
+func testExportZip(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"cards.csv": testExportCards, "items.csv": testExportItems} {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		_, _ = f.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func importAccountExport(t *testing.T, handler *CardHandler, target, contentType string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.Import(rr, req)
+	return rr
+}
+
+func TestCardHandler_Import_AccountExportZip(t *testing.T) {
+	var got []models.ImportCardParams
+	handler := NewCardHandler(&mockCardService{
+		ImportAccountExportFunc: func(ctx context.Context, userID uuid.UUID, cards []models.ImportCardParams) (*models.AccountExportImportResult, error) {
+			got = cards
+			return &models.AccountExportImportResult{
+				Cards:      []*models.BingoCard{{ID: uuid.New()}},
+				Duplicates: []models.CardImportDuplicate{},
+			}, nil
+		},
+	})
+
+	rr := importAccountExport(t, handler, "/api/cards/import?include_completions=true", "application/zip", testExportZip(t))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(got) != 1 || got[0].Title == nil || *got[0].Title != "Travel" || len(got[0].Items) != 1 {
+		t.Fatalf("expected the exported card passed to the service, got %+v", got)
+	}
+	if item := got[0].Items[0]; !item.IsCompleted || item.Notes == nil || *item.Notes != "Loved it" {
+		t.Fatalf("expected completion and notes kept, got %+v", item)
+	}
+	var resp models.AccountExportImportResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil || len(resp.Cards) != 1 {
+		t.Fatalf("expected created cards in response, got %s", rr.Body.String())
+	}
+}
+
+func TestCardHandler_Import_AccountExportCSVForm(t *testing.T) {
+	var got []models.ImportCardParams
+	handler := NewCardHandler(&mockCardService{
+		ImportAccountExportFunc: func(ctx context.Context, userID uuid.UUID, cards []models.ImportCardParams) (*models.AccountExportImportResult, error) {
+			got = cards
+			title := "Travel"
+			return &models.AccountExportImportResult{
+				Cards:      []*models.BingoCard{},
+				Duplicates: []models.CardImportDuplicate{{Title: &title, Year: 2025}},
+			}, nil
+		},
+	})
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for field, content := range map[string]string{"cards": testExportCards, "items": testExportItems} {
+		part, err := form.CreateFormFile(field, field+".csv")
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		_, _ = part.Write([]byte(content))
+	}
+	_ = form.Close()
+
+	rr := importAccountExport(t, handler, "/api/cards/import", form.FormDataContentType(), body.Bytes())
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200 when nothing was created, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(got) != 1 || got[0].Items[0].IsCompleted {
+		t.Fatalf("expected completion dropped without include_completions, got %+v", got)
+	}
+	if !strings.Contains(rr.Body.String(), `"duplicates":[{"title":"Travel","year":2025}]`) {
+		t.Fatalf("expected duplicate reported, got %s", rr.Body.String())
+	}
+}
+
+func TestCardHandler_Import_AccountExportInvalid(t *testing.T) {
+	called := false
+	handler := NewCardHandler(&mockCardService{
+		ImportAccountExportFunc: func(ctx context.Context, userID uuid.UUID, cards []models.ImportCardParams) (*models.AccountExportImportResult, error) {
+			called = true
+			return nil, nil
+		},
+	})
+
+	rr := importAccountExport(t, handler, "/api/cards/import", "application/zip", []byte("not a zip"))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "invalid account export") {
+		t.Fatalf("expected reason in error, got %s", rr.Body.String())
+	}
+	if called {
+		t.Fatal("expected service not called")
+	}
+}
+
+func TestCardHandler_Import_AccountExportServiceError(t *testing.T) {
+	handler := NewCardHandler(&mockCardService{
+		ImportAccountExportFunc: func(ctx context.Context, userID uuid.UUID, cards []models.ImportCardParams) (*models.AccountExportImportResult, error) {
+			return nil, errors.New("db down")
+		},
+	})
+
+	rr := importAccountExport(t, handler, "/api/cards/import", "application/zip", testExportZip(t))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", rr.Code)
+	}
+}
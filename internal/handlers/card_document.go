@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// maxCardDocumentBytes bounds an uploaded card file. A full 5x5 card with
+// maximum-length goals is well under this.
+const maxCardDocumentBytes = 64 * 1024
+
+// ExportDocument downloads one of the user's cards as a portable JSON card
+// file (see models.CardDocument).
+func (h *CardHandler) ExportDocument(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	card, err := h.cardService.GetByID(r.Context(), cardID)
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting card for export: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if card.UserID != user.ID {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	filename := "yearofbingo_card_" + time.Now().UTC().Format("2006-01-02") + ".json"
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	writeJSON(w, http.StatusOK, models.NewCardDocument(card))
+}
+
+// ImportDocument creates a draft card from a card file. The draft goes in the
+// `year` query parameter's year, or the current year.
+func (h *CardHandler) ImportDocument(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	year := time.Now().Year()
+	if raw := r.URL.Query().Get("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid year")
+			return
+		}
+		year = parsed
+	}
+	if year < 2020 || year > time.Now().Year()+1 {
+		writeError(w, http.StatusBadRequest, "Year must be between 2020 and next year")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCardDocumentBytes)
+	var doc models.CardDocument
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card file")
+		return
+	}
+
+	params, dropped, err := doc.ImportParams(user.ID, year)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card file: "+err.Error())
+		return
+	}
+
+	existingCard, err := h.cardService.CheckForConflict(r.Context(), user.ID, year, params.Title)
+	if err != nil && !errors.Is(err, services.ErrCardNotFound) {
+		log.Printf("Error checking for conflict: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if existingCard != nil {
+		writeError(w, http.StatusConflict, "You already have a card with this title for this year")
+		return
+	}
+
+	card, err := h.cardService.Import(r.Context(), params)
+	if errors.Is(err, services.ErrInvalidCategory) {
+		writeError(w, http.StatusBadRequest, "Invalid category")
+		return
+	}
+	if errors.Is(err, services.ErrTitleTooLong) {
+		writeError(w, http.StatusBadRequest, "Title must be 100 characters or less")
+		return
+	}
+	if err != nil {
+		log.Printf("Error importing card file: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	message := "Card imported"
+	if dropped > 0 {
+		message = fmt.Sprintf("Card imported (%d goals did not fit the grid and were left out)", dropped)
+	}
+	writeJSON(w, http.StatusCreated, CardResponse{Card: card, Message: message})
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func exportDocument(t *testing.T, handler *CardHandler, user *models.User, cardID uuid.UUID) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/export.json", nil)
+	req.SetPathValue("id", cardID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.ExportDocument(rr, req)
+	return rr
+}
+
+func importDocument(t *testing.T, handler *CardHandler, user *models.User, target string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.ImportDocument(rr, req)
+	return rr
+}
+
+func TestCardHandler_ExportDocument_NotOwner(t *testing.T) {
+	cardID := uuid.New()
+	handler := NewCardHandler(&mockCardService{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.BingoCard, error) {
+			return &models.BingoCard{ID: id, UserID: uuid.New()}, nil
+		},
+	})
+
+	rr := exportDocument(t, handler, &models.User{ID: uuid.New()}, cardID)
+	assertErrorResponse(t, rr, http.StatusForbidden, "Access denied")
+}
+
+func TestCardHandler_ExportDocument_OmitsIDsAndCompletion(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	now := time.Now()
+	notes := "ran it in 2:01"
+	handler := NewCardHandler(&mockCardService{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.BingoCard, error) {
+			return &models.BingoCard{
+				ID: id, UserID: user.ID, Year: 2026, GridSize: 3, HeaderText: "BIN",
+				Items: []models.BingoItem{{ID: uuid.New(), CardID: id, Position: 0, Content: "Run a marathon", IsCompleted: true, CompletedAt: &now, Notes: &notes}},
+			}, nil
+		},
+	})
+
+	rr := exportDocument(t, handler, user, cardID)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Header().Get("Content-Disposition"), "attachment") {
+		t.Fatalf("expected attachment download, got %q", rr.Header().Get("Content-Disposition"))
+	}
+	body := rr.Body.String()
+	for _, leaked := range []string{cardID.String(), user.ID.String(), "is_completed", "completed_at", notes, "2026"} {
+		if strings.Contains(body, leaked) {
+			t.Fatalf("expected export not to contain %q, got %s", leaked, body)
+		}
+	}
+	if !strings.Contains(body, `"format":"`+models.CardDocumentFormat+`"`) {
+		t.Fatalf("expected versioned document, got %s", body)
+	}
+}
+
+func TestCardHandler_CardDocument_RoundTrip(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	title := "Reading year"
+	category := "hobbies"
+	freeText := "READ"
+	hard := models.DifficultyHard
+	center := 4
+	source := &models.BingoCard{
+		ID: uuid.New(), UserID: user.ID, Year: 2025, Title: &title, Category: &category,
+		GridSize: 3, HeaderText: "BIN", HasFreeSpace: true, FreeSpacePos: &center, FreeSpaceText: &freeText,
+		RequireProof: true, IsFinalized: true,
+		Items: []models.BingoItem{
+			{Position: 0, Content: "Read 12 books", IsCompleted: true},
+			{Position: 5, Content: "Finish a classic", Difficulty: &hard},
+			{Position: 8, Content: "Join a book club", IsPrivate: true},
+		},
+	}
+
+	var imported models.ImportCardParams
+	handler := NewCardHandler(&mockCardService{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.BingoCard, error) {
+			return source, nil
+		},
+		ImportFunc: func(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error) {
+			imported = params
+			return &models.BingoCard{ID: uuid.New(), UserID: params.UserID, Year: params.Year}, nil
+		},
+	})
+
+	exported := exportDocument(t, handler, user, source.ID)
+	if exported.Code != http.StatusOK {
+		t.Fatalf("expected export status 200, got %d", exported.Code)
+	}
+	rr := importDocument(t, handler, user, "/api/cards/import-json?year=2026", exported.Body.Bytes())
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected import status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if imported.UserID != user.ID || imported.Year != 2026 || imported.Finalize {
+		t.Fatalf("expected a draft for the importer in 2026, got %+v", imported)
+	}
+	if *imported.Title != title || *imported.Category != category || imported.GridSize != 3 || imported.HeaderText != "BIN" ||
+		!imported.HasFreeSpace || *imported.FreeSpacePos != center || *imported.FreeSpaceText != freeText || !imported.RequireProof {
+		t.Fatalf("expected card config to survive the round trip, got %+v", imported)
+	}
+	want := []models.ImportItem{
+		{Position: 0, Content: "Read 12 books"},
+		{Position: 5, Content: "Finish a classic", Difficulty: &hard},
+		{Position: 8, Content: "Join a book club", IsPrivate: true},
+	}
+	if !reflect.DeepEqual(imported.Items, want) {
+		t.Fatalf("expected items %+v, got %+v", want, imported.Items)
+	}
+}
+
+func TestCardHandler_ImportDocument_ClampsToCapacity(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	var imported models.ImportCardParams
+	handler := NewCardHandler(&mockCardService{
+		ImportFunc: func(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error) {
+			imported = params
+			return &models.BingoCard{ID: uuid.New()}, nil
+		},
+	})
+
+	doc := models.CardDocument{Format: models.CardDocumentFormat, Version: models.CardDocumentVersion, GridSize: 2, HasFreeSpace: true}
+	for i := 0; i < 5; i++ {
+		doc.Items = append(doc.Items, models.CardDocumentItem{Position: i, Content: "Goal " + string(rune('A'+i))})
+	}
+	body, _ := json.Marshal(doc)
+
+	rr := importDocument(t, handler, user, "/api/cards/import-json", body)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(imported.Items) != 3 {
+		t.Fatalf("expected items clamped to 3, got %d", len(imported.Items))
+	}
+	var response CardResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !strings.Contains(response.Message, "2 goals did not fit") {
+		t.Fatalf("expected dropped goals in message, got %q", response.Message)
+	}
+}
+
+func TestCardHandler_ImportDocument_Validation(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewCardHandler(&mockCardService{
+		ImportFunc: func(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error) {
+			t.Fatal("import should not be called")
+			return nil, nil
+		},
+	})
+
+	cases := map[string]struct {
+		target string
+		body   string
+	}{
+		"not json":      {"/api/cards/import-json", "nope"},
+		"wrong format":  {"/api/cards/import-json", `{"format":"other","version":1,"grid_size":3,"items":[]}`},
+		"newer version": {"/api/cards/import-json", `{"format":"yearofbingo.card","version":99,"grid_size":3,"items":[]}`},
+		"bad grid":      {"/api/cards/import-json", `{"format":"yearofbingo.card","version":1,"grid_size":7,"items":[]}`},
+		"empty goal":    {"/api/cards/import-json", `{"format":"yearofbingo.card","version":1,"grid_size":3,"items":[{"position":0,"content":" "}]}`},
+		"bad year":      {"/api/cards/import-json?year=1999", `{"format":"yearofbingo.card","version":1,"grid_size":3,"items":[]}`},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rr := importDocument(t, handler, user, tc.target, []byte(tc.body))
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d", rr.Code)
+			}
+		})
+	}
+}
+
+func TestCardHandler_ImportDocument_Conflict(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewCardHandler(&mockCardService{
+		CheckForConflictFunc: func(ctx context.Context, userID uuid.UUID, year int, title *string) (*models.BingoCard, error) {
+			return &models.BingoCard{ID: uuid.New()}, nil
+		},
+		ImportFunc: func(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error) {
+			t.Fatal("import should not be called")
+			return nil, nil
+		},
+	})
+
+	rr := importDocument(t, handler, user, "/api/cards/import-json", []byte(`{"format":"yearofbingo.card","version":1,"grid_size":3,"items":[]}`))
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// SetDraftStateService enables the card creation wizard's autosave endpoints
+// and clears the autosave once a card is created.
+func (h *CardHandler) SetDraftStateService(draftStates services.CardDraftStateServiceInterface) {
+	h.draftStates = draftStates
+}
+
+// SaveDraftState stores the request body, any JSON value up to 32KB, as the
+// user's in-progress card wizard state.
+func (h *CardHandler) SaveDraftState(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if h.draftStates == nil {
+		writeError(w, http.StatusServiceUnavailable, "Draft autosave is not available")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, services.CardDraftStateMaxBytes)
+	state, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, "Draft state must be 32KB or less")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err = h.draftStates.Save(r.Context(), user.ID, state)
+	if errors.Is(err, services.ErrCardDraftStateTooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, "Draft state must be 32KB or less")
+		return
+	}
+	if errors.Is(err, services.ErrCardDraftStateInvalid) {
+		writeError(w, http.StatusBadRequest, "Draft state must be valid JSON")
+		return
+	}
+	if err != nil {
+		log.Printf("Error saving card draft state: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CardResponse{Message: "Draft saved"})
+}
+
+// GetDraftState returns the user's saved wizard state exactly as it was
+// saved.
+func (h *CardHandler) GetDraftState(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if h.draftStates == nil {
+		writeError(w, http.StatusNotFound, "No saved draft")
+		return
+	}
+
+	state, err := h.draftStates.Get(r.Context(), user.ID)
+	if errors.Is(err, services.ErrCardDraftStateNotFound) {
+		writeError(w, http.StatusNotFound, "No saved draft")
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading card draft state: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, json.RawMessage(state))
+}
+
+// DeleteDraftState discards the user's saved wizard state.
+func (h *CardHandler) DeleteDraftState(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	if h.draftStates != nil {
+		if err := h.draftStates.Clear(r.Context(), user.ID); err != nil {
+			log.Printf("Error clearing card draft state: %v", err)
+			writeError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, CardResponse{Message: "Draft cleared"})
+}
+
+// clearDraftState drops the wizard autosave after a card is created. A
+// failure only leaves the autosave to expire.
+func (h *CardHandler) clearDraftState(r *http.Request, userID uuid.UUID) {
+	if h.draftStates == nil {
+		return
+	}
+	if err := h.draftStates.Clear(r.Context(), userID); err != nil {
+		log.Printf("Error clearing card draft state: %v", err)
+	}
+}
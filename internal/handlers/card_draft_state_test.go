@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// memoryDraftStates keeps draft states in a map and validates like the real
+// service.
+type memoryDraftStates struct {
+	states  map[uuid.UUID][]byte
+	cleared int
+}
+
+func (m *memoryDraftStates) Save(ctx context.Context, userID uuid.UUID, state []byte) error {
+	if len(state) > services.CardDraftStateMaxBytes {
+		return services.ErrCardDraftStateTooLarge
+	}
+	if !json.Valid(state) {
+		return services.ErrCardDraftStateInvalid
+	}
+	m.states[userID] = state
+	return nil
+}
+
+func (m *memoryDraftStates) Get(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	state, ok := m.states[userID]
+	if !ok {
+		return nil, services.ErrCardDraftStateNotFound
+	}
+	return state, nil
+}
+
+func (m *memoryDraftStates) Clear(ctx context.Context, userID uuid.UUID) error {
+	m.cleared++
+	delete(m.states, userID)
+	return nil
+}
+
+func draftStateRequest(handler http.HandlerFunc, method string, user *models.User, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/cards/draft-state", bytes.NewReader(body))
+	if user != nil {
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+	}
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	return rr
+}
+
+func TestCardHandler_DraftState_RoundTrip(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	states := &memoryDraftStates{states: map[uuid.UUID][]byte{}}
+	handler := NewCardHandler(&mockCardService{})
+	handler.SetDraftStateService(states)
+
+	rr := draftStateRequest(handler.GetDraftState, http.MethodGet, user, nil)
+	assertErrorResponse(t, rr, http.StatusNotFound, "No saved draft")
+
+	state := []byte(`{"step":2,"grid_size":4,"goals":["Run a 5k"]}`)
+	rr = draftStateRequest(handler.SaveDraftState, http.MethodPost, user, state)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = draftStateRequest(handler.GetDraftState, http.MethodGet, user, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if got := strings.TrimSpace(rr.Body.String()); got != string(state) {
+		t.Fatalf("expected the saved state back unchanged, got %s", got)
+	}
+
+	rr = draftStateRequest(handler.DeleteDraftState, http.MethodDelete, user, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	rr = draftStateRequest(handler.GetDraftState, http.MethodGet, user, nil)
+	assertErrorResponse(t, rr, http.StatusNotFound, "No saved draft")
+}
+
+func TestCardHandler_SaveDraftState_Rejects(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	states := &memoryDraftStates{states: map[uuid.UUID][]byte{}}
+	handler := NewCardHandler(&mockCardService{})
+	handler.SetDraftStateService(states)
+
+	rr := draftStateRequest(handler.SaveDraftState, http.MethodPost, nil, []byte(`{}`))
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+
+	rr = draftStateRequest(handler.SaveDraftState, http.MethodPost, user, []byte(`{"step":`))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Draft state must be valid JSON")
+
+	large := []byte(`"` + strings.Repeat("a", services.CardDraftStateMaxBytes) + `"`)
+	rr = draftStateRequest(handler.SaveDraftState, http.MethodPost, user, large)
+	assertErrorResponse(t, rr, http.StatusRequestEntityTooLarge, "Draft state must be 32KB or less")
+
+	if len(states.states) != 0 {
+		t.Fatalf("expected nothing saved, got %d states", len(states.states))
+	}
+}
+
+func TestCardHandler_Create_ClearsDraftState(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	states := &memoryDraftStates{states: map[uuid.UUID][]byte{user.ID: []byte(`{"step":3}`)}}
+	handler := NewCardHandler(&mockCardService{
+		CreateFunc: func(ctx context.Context, params models.CreateCardParams) (*models.BingoCard, error) {
+			return &models.BingoCard{ID: uuid.New(), UserID: user.ID, Year: params.Year}, nil
+		},
+	})
+	handler.SetDraftStateService(states)
+
+	body, _ := json.Marshal(CreateCardRequest{Year: time.Now().Year()})
+	req := httptest.NewRequest(http.MethodPost, "/api/cards", bytes.NewReader(body))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.Create(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if _, ok := states.states[user.ID]; ok || states.cleared != 1 {
+		t.Fatalf("expected the draft state cleared once, cleared %d times", states.cleared)
+	}
+}
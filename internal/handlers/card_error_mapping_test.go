@@ -64,6 +64,7 @@ func TestCardHandler_Create_ConflictAndServiceErrors(t *testing.T) {
 		{"title too long", services.ErrTitleTooLong, http.StatusBadRequest, "Title must be 100 characters or less"},
 		{"invalid grid size", services.ErrInvalidGridSize, http.StatusBadRequest, "Grid size must be 2, 3, 4, or 5"},
 		{"invalid header", services.ErrInvalidHeaderText, http.StatusBadRequest, "Invalid header text"},
+		{"invalid period", services.ErrInvalidCardPeriod, http.StatusBadRequest, cardPeriodErrorMessage},
 		{"internal error", errors.New("boom"), http.StatusInternalServerError, "Internal server error"},
 	}
 
@@ -158,6 +159,29 @@ func TestCardHandler_UpdateItem_ServiceErrors(t *testing.T) {
 			t.Fatalf("expected status 200, got %d", rr.Code)
 		}
 	})
+
+	t.Run("privacy only", func(t *testing.T) {
+		var gotParams models.UpdateItemParams
+		mockCard := &mockCardService{
+			UpdateItemFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, position int, params models.UpdateItemParams) (*models.BingoItem, error) {
+				gotParams = params
+				return &models.BingoItem{CardID: gotCardID, Position: position, IsPrivate: true}, nil
+			},
+		}
+		handler := NewCardHandler(mockCard)
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/cards/"+cardID.String()+"/items/1", bytes.NewBufferString(`{"is_private":true}`))
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+
+		handler.UpdateItem(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+		if gotParams.IsPrivate == nil || !*gotParams.IsPrivate || gotParams.Content != nil {
+			t.Fatalf("unexpected params: %+v", gotParams)
+		}
+	})
 }
 
 func TestCardHandler_RemoveItem_ServiceErrors(t *testing.T) {
@@ -210,13 +234,15 @@ func TestCardHandler_ShuffleAndSwapItems_ServiceErrors(t *testing.T) {
 			{"card not found", services.ErrCardNotFound, http.StatusNotFound},
 			{"not owner", services.ErrNotCardOwner, http.StatusForbidden},
 			{"finalized", services.ErrCardFinalized, http.StatusBadRequest},
+			{"invalid seed", services.ErrInvalidShuffleSeed, http.StatusBadRequest},
+			{"positions out of sync", services.ErrInvalidPosition, http.StatusConflict},
 			{"internal error", errors.New("boom"), http.StatusInternalServerError},
 		}
 
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
 				mockCard := &mockCardService{
-					ShuffleFunc: func(ctx context.Context, userID, gotCardID uuid.UUID) (*models.BingoCard, error) {
+					ShuffleFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, seed *int64) (*services.ShuffleResult, error) {
 						return nil, tt.serviceErr
 					},
 				}
@@ -234,6 +260,42 @@ func TestCardHandler_ShuffleAndSwapItems_ServiceErrors(t *testing.T) {
 		}
 	})
 
+	t.Run("undo shuffle errors", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			serviceErr error
+			wantStatus int
+		}{
+			{"card not found", services.ErrCardNotFound, http.StatusNotFound},
+			{"not owner", services.ErrNotCardOwner, http.StatusForbidden},
+			{"finalized", services.ErrCardFinalized, http.StatusBadRequest},
+			{"nothing to undo", services.ErrNoShuffleToUndo, http.StatusConflict},
+			{"stale", services.ErrShuffleUndoStale, http.StatusConflict},
+			{"positions out of sync", services.ErrInvalidPosition, http.StatusConflict},
+			{"internal error", errors.New("boom"), http.StatusInternalServerError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockCard := &mockCardService{
+					UndoShuffleFunc: func(ctx context.Context, userID, gotCardID uuid.UUID) (*models.BingoCard, error) {
+						return nil, tt.serviceErr
+					},
+				}
+				handler := NewCardHandler(mockCard)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/cards/"+cardID.String()+"/shuffle/undo", nil)
+				req = req.WithContext(SetUserInContext(req.Context(), user))
+				rr := httptest.NewRecorder()
+
+				handler.UndoShuffle(rr, req)
+				if rr.Code != tt.wantStatus {
+					t.Fatalf("expected status %d, got %d", tt.wantStatus, rr.Code)
+				}
+			})
+		}
+	})
+
 	t.Run("swap errors", func(t *testing.T) {
 		tests := []struct {
 			name       string
@@ -502,6 +564,7 @@ func TestCardHandler_UpdateConfigAndMeta_ServiceErrors(t *testing.T) {
 			{"not owner", services.ErrNotCardOwner, http.StatusForbidden},
 			{"finalized", services.ErrCardFinalized, http.StatusBadRequest},
 			{"invalid header", services.ErrInvalidHeaderText, http.StatusBadRequest},
+			{"invalid free space text", services.ErrInvalidFreeSpaceText, http.StatusBadRequest},
 			{"no space for free", services.ErrNoSpaceForFree, http.StatusBadRequest},
 			{"internal", errors.New("boom"), http.StatusInternalServerError},
 		}
@@ -828,6 +891,7 @@ func TestCardHandler_Import_MapsServiceErrors(t *testing.T) {
 		{"invalid position", services.ErrInvalidPosition, http.StatusBadRequest},
 		{"invalid grid size", services.ErrInvalidGridSize, http.StatusBadRequest},
 		{"invalid header", services.ErrInvalidHeaderText, http.StatusBadRequest},
+		{"invalid period", services.ErrInvalidCardPeriod, http.StatusBadRequest},
 		{"internal", errors.New("boom"), http.StatusInternalServerError},
 	}
 
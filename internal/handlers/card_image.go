@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// Image downloads the user's card as a PNG, with ?format=pdf as a single-page
+// PDF for printing, or with ?format=svg (or an Accept header preferring SVG)
+// as a vector image. ?show_completions=false leaves completed goals unmarked,
+// and ?size picks the raster width in pixels (1080 or 2048).
+func (h *CardHandler) Image(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	query := r.URL.Query()
+	showCompletions := true
+	if raw := query.Get("show_completions"); raw != "" {
+		showCompletions, err = strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "show_completions must be true or false")
+			return
+		}
+	}
+	width := services.CardExportWidthDefault
+	if raw := query.Get("size"); raw != "" {
+		width, err = strconv.Atoi(raw)
+		if err != nil || !services.IsValidCardExportWidth(width) {
+			writeError(w, http.StatusBadRequest, "size must be 1080 or 2048")
+			return
+		}
+	}
+	format := query.Get("format")
+	if format == "" {
+		format = "png"
+		if acceptsSVGOverPNG(r) {
+			format = "svg"
+		}
+	}
+	if format != "png" && format != "pdf" && format != "svg" {
+		writeError(w, http.StatusBadRequest, "format must be png, pdf or svg")
+		return
+	}
+
+	card, err := h.cardService.GetByID(r.Context(), cardID)
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting card for image: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if card.UserID != user.ID {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	opts := services.RenderOptions{ShowCompletions: showCompletions}
+	var data []byte
+	switch format {
+	case "svg":
+		data, err = services.RenderCardSVG(*card, card.Items, opts)
+	case "pdf":
+		data, err = services.RenderCardExportPDF(*card, card.Items, opts, width)
+	default:
+		data, err = services.RenderCardExportPNG(*card, card.Items, opts, width)
+	}
+	if err != nil {
+		log.Printf("Error rendering card image: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	filename := "yearofbingo_card_" + strconv.Itoa(card.Year) + "." + format
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	w.Header().Set("Cache-Control", "private, max-age=60")
+	w.Header().Set("Vary", "Accept")
+	switch format {
+	case "svg":
+		writeSVG(w, data)
+		return
+	case "pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+	default:
+		w.Header().Set("Content-Type", "image/png")
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func cardImage(t *testing.T, handler *CardHandler, user *models.User, cardID uuid.UUID, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/image.png"+query, nil)
+	req.SetPathValue("id", cardID.String())
+	if user != nil {
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+	}
+	rr := httptest.NewRecorder()
+	handler.Image(rr, req)
+	return rr
+}
+
+func imageTestHandler(owner uuid.UUID) *CardHandler {
+	return NewCardHandler(&mockCardService{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*models.BingoCard, error) {
+			return &models.BingoCard{
+				ID: id, UserID: owner, Year: 2026, GridSize: 2, HeaderText: "BI",
+				Items: []models.BingoItem{{ID: uuid.New(), CardID: id, Position: 0, Content: "Run a marathon", IsCompleted: true}},
+			}, nil
+		},
+	})
+}
+
+func TestCardHandler_Image_PNG(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	rr := cardImage(t, imageTestHandler(user.ID), user, uuid.New(), "?size=2048&show_completions=false")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Content-Type"); got != "image/png" {
+		t.Fatalf("expected image/png, got %q", got)
+	}
+	if got := rr.Header().Get("Cache-Control"); !strings.HasPrefix(got, "private, max-age=") {
+		t.Fatalf("expected a private cache header, got %q", got)
+	}
+	img, err := png.Decode(bytes.NewReader(rr.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("expected valid png: %v", err)
+	}
+	if img.Bounds().Dx() != 2048 {
+		t.Fatalf("expected a 2048px wide image, got %d", img.Bounds().Dx())
+	}
+}
+
+func TestCardHandler_Image_PDF(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	rr := cardImage(t, imageTestHandler(user.ID), user, uuid.New(), "?format=pdf")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Fatalf("expected application/pdf, got %q", got)
+	}
+	if got := rr.Header().Get("Content-Disposition"); !strings.Contains(got, "yearofbingo_card_2026.pdf") {
+		t.Fatalf("expected a pdf filename, got %q", got)
+	}
+	if !bytes.HasPrefix(rr.Body.Bytes(), []byte("%PDF-")) {
+		t.Fatal("expected a PDF body")
+	}
+}
+
+func TestCardHandler_Image_Errors(t *testing.T) {
+	owner := &models.User{ID: uuid.New()}
+	handler := imageTestHandler(owner.ID)
+
+	rr := cardImage(t, handler, nil, uuid.New(), "")
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+
+	rr = cardImage(t, handler, &models.User{ID: uuid.New()}, uuid.New(), "")
+	assertErrorResponse(t, rr, http.StatusForbidden, "Access denied")
+
+	for query, message := range map[string]string{
+		"?size=4096":              "size must be 1080 or 2048",
+		"?size=big":               "size must be 1080 or 2048",
+		"?format=gif":             "format must be png, pdf or svg",
+		"?show_completions=maybe": "show_completions must be true or false",
+	} {
+		rr = cardImage(t, handler, owner, uuid.New(), query)
+		assertErrorResponse(t, rr, http.StatusBadRequest, message)
+	}
+}
+
+func TestCardHandler_Image_SVG(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := imageTestHandler(user.ID)
+
+	for name, query := range map[string]string{"query": "?format=svg", "accept": ""} {
+		cardID := uuid.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/image.png"+query, nil)
+		req.SetPathValue("id", cardID.String())
+		if name == "accept" {
+			req.Header.Set("Accept", "image/svg+xml")
+		}
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.Image(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", name, rr.Code)
+		}
+		if got := rr.Header().Get("Content-Type"); got != "image/svg+xml" {
+			t.Fatalf("%s: expected image/svg+xml, got %q", name, got)
+		}
+		if rr.Header().Get("Content-Security-Policy") == "" || rr.Header().Get("Vary") != "Accept" {
+			t.Fatalf("%s: expected CSP and Vary headers, got %v", name, rr.Header())
+		}
+		if !strings.HasPrefix(rr.Body.String(), "<svg ") {
+			t.Fatalf("%s: expected an SVG body", name)
+		}
+	}
+}
+
+func TestAcceptsSVGOverPNG(t *testing.T) {
+	cases := map[string]bool{
+		"":                               false,
+		"image/svg+xml":                  true,
+		"image/svg+xml, image/png;q=0.5": true,
+		"image/png, image/svg+xml":       false,
+		"image/svg+xml;q=0.4, */*;q=0.8": false,
+		// Chrome's image request Accept header.
+		"image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8": false,
+	}
+	for accept, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+		if got := acceptsSVGOverPNG(req); got != want {
+			t.Fatalf("%q: expected %t, got %t", accept, want, got)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// maxProofUploadBytes bounds a multipart completion: the photo plus room for
+// the notes and proof_url fields.
+const maxProofUploadBytes = models.MaxProofImageBytes + 64<<10
+
+// readCompleteItemForm reads a multipart completion: optional "notes" and
+// "proof_url" fields and an optional "proof" photo.
+func readCompleteItemForm(w http.ResponseWriter, r *http.Request) (CompleteItemRequest, []byte, error) {
+	var req CompleteItemRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxProofUploadBytes)
+	if err := r.ParseMultipartForm(maxProofUploadBytes); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return req, nil, services.ErrProofImageTooLarge
+		}
+		return req, nil, err
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	if notes := r.FormValue("notes"); notes != "" {
+		req.Notes = &notes
+	}
+	if proofURL := strings.TrimSpace(r.FormValue("proof_url")); proofURL != "" {
+		req.ProofURL = &proofURL
+	}
+
+	file, _, err := r.FormFile("proof")
+	if errors.Is(err, http.ErrMissingFile) {
+		return req, nil, nil
+	}
+	if err != nil {
+		return req, nil, err
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(io.LimitReader(file, models.MaxProofImageBytes+1))
+	if err != nil {
+		return req, nil, err
+	}
+	if len(data) > models.MaxProofImageBytes {
+		return req, nil, services.ErrProofImageTooLarge
+	}
+	return req, data, nil
+}
+
+// writeProofImageError answers a rejected proof photo.
+func writeProofImageError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrProofImageTooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, "Proof image must be 5MB or less")
+	case errors.Is(err, services.ErrProofImageType):
+		writeError(w, http.StatusBadRequest, "Proof image must be a JPEG, PNG or WebP")
+	default:
+		return false
+	}
+	return true
+}
+
+// ProofHandler serves uploaded proof photos. Their URLs carry a random part,
+// and they are shown wherever the goal's proof URL is, including share links,
+// so they are served without a session.
+type ProofHandler struct {
+	storage services.StorageService
+}
+
+func NewProofHandler(storage services.StorageService) *ProofHandler {
+	return &ProofHandler{storage: storage}
+}
+
+func (h *ProofHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	data, err := h.storage.Get(r.Context(), key)
+	if errors.Is(err, services.ErrStorageObjectNotFound) || errors.Is(err, services.ErrInvalidStorageKey) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("Error reading proof image: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", services.ProofImageContentType(key))
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+var testPNG = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+func newProofUploadRequest(t *testing.T, cardID uuid.UUID, notes string, photo []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if notes != "" {
+		if err := mw.WriteField("notes", notes); err != nil {
+			t.Fatalf("write field: %v", err)
+		}
+	}
+	if photo != nil {
+		part, err := mw.CreateFormFile("proof", "finish.png")
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		if _, err := part.Write(photo); err != nil {
+			t.Fatalf("write photo: %v", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatalf("close multipart: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPut, "/api/cards/"+cardID.String()+"/items/3/complete", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+}
+
+func TestCardHandler_CompleteItem_ProofUpload(t *testing.T) {
+	cardID := uuid.New()
+	var got models.CompleteItemParams
+	handler := NewCardHandler(&mockCardService{
+		CompleteItemFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error) {
+			got = params
+			return &models.BingoItem{CardID: gotCardID, Position: position, IsCompleted: true}, nil
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	handler.CompleteItem(rr, newProofUploadRequest(t, cardID, "crossed the line", testPNG))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got.Notes == nil || *got.Notes != "crossed the line" || !bytes.Equal(got.ProofImage, testPNG) {
+		t.Fatalf("expected notes and photo to reach the service, got %+v", got)
+	}
+}
+
+func TestCardHandler_CompleteItem_ProofUploadRejected(t *testing.T) {
+	cardID := uuid.New()
+	called := false
+	handler := NewCardHandler(&mockCardService{
+		CompleteItemFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error) {
+			called = true
+			return nil, services.ErrProofImageType
+		},
+	})
+
+	oversized := append(append([]byte{}, testPNG...), make([]byte, models.MaxProofImageBytes)...)
+	rr := httptest.NewRecorder()
+	handler.CompleteItem(rr, newProofUploadRequest(t, cardID, "", oversized))
+	assertErrorResponse(t, rr, http.StatusRequestEntityTooLarge, "Proof image must be 5MB or less")
+	if called {
+		t.Fatal("expected an oversized upload to be rejected before the service")
+	}
+
+	rr = httptest.NewRecorder()
+	handler.CompleteItem(rr, newProofUploadRequest(t, cardID, "", []byte("not an image")))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Proof image must be a JPEG, PNG or WebP")
+}
+
+func TestProofHandler_Serve(t *testing.T) {
+	storage := services.NewDiskStorage(t.TempDir())
+	key := uuid.NewString() + "/" + uuid.NewString() + "-abc.png"
+	if err := storage.Put(context.Background(), key, testPNG); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	handler := NewProofHandler(storage)
+
+	for _, tc := range []struct {
+		key    string
+		status int
+	}{
+		{key, http.StatusOK},
+		{uuid.NewString() + "/missing.png", http.StatusNotFound},
+		{"../" + key, http.StatusNotFound},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/proofs/"+tc.key, nil)
+		req.SetPathValue("key", tc.key)
+		rr := httptest.NewRecorder()
+		handler.Serve(rr, req)
+		if rr.Code != tc.status {
+			t.Fatalf("%s: expected status %d, got %d", tc.key, tc.status, rr.Code)
+		}
+		if tc.status == http.StatusOK && (rr.Header().Get("Content-Type") != "image/png" || !bytes.Equal(rr.Body.Bytes(), testPNG)) {
+			t.Fatalf("expected the stored PNG, got %q", rr.Header().Get("Content-Type"))
+		}
+	}
+}
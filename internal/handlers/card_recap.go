@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type CardRecapResponse struct {
+	Recap *models.CardRecap `json:"recap"`
+}
+
+// recapFormat reads ?format, which is json (the default) or png.
+func recapFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "png" {
+		writeError(w, http.StatusBadRequest, "format must be json or png")
+		return "", false
+	}
+	return format, true
+}
+
+func writeRecap(w http.ResponseWriter, format string, recap *models.CardRecap) {
+	if format == "json" {
+		writeJSON(w, http.StatusOK, CardRecapResponse{Recap: recap})
+		return
+	}
+	pngBytes, err := services.RenderRecapPNG(*recap)
+	if err != nil {
+		log.Printf("Error rendering recap image: %v", err)
+		writeError(w, http.StatusInternalServerError, "Failed to render image")
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pngBytes)
+}
+
+// Recap returns the year-end summary of one of the caller's cards, as JSON
+// or, with format=png, as a shareable image.
+func (h *CardHandler) Recap(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+	format, ok := recapFormat(w, r)
+	if !ok {
+		return
+	}
+
+	recap, err := h.cardService.BuildRecap(r.Context(), user.ID, cardID)
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if err != nil {
+		log.Printf("Error building recap: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeRecap(w, format, recap)
+}
+
+// Recap serves the year-end summary of a shared card to anyone holding the
+// share link. Private goals show as placeholders.
+func (h *SharePublicHandler) Recap(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSpace(r.PathValue("token"))
+	if !isValidShareToken(token) {
+		writeError(w, http.StatusNotFound, "Share link not found")
+		return
+	}
+	format, ok := recapFormat(w, r)
+	if !ok {
+		return
+	}
+
+	recap, err := h.cardService.BuildSharedRecap(r.Context(), token)
+	if errors.Is(err, services.ErrShareNotFound) || errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Share link not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error building shared recap: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	writeRecap(w, format, recap)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestCardHandler_Recap(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	handler := NewCardHandler(&mockCardService{
+		BuildRecapFunc: func(ctx context.Context, userID, gotCardID uuid.UUID) (*models.CardRecap, error) {
+			if gotCardID != cardID {
+				return nil, services.ErrCardNotFound
+			}
+			if userID != user.ID {
+				return nil, services.ErrNotCardOwner
+			}
+			return &models.CardRecap{CardID: cardID, Year: 2026, DisplayName: "2026 Bingo Card", CompletedItems: 3, TotalItems: 24, OldestOpenGoals: []models.RecapGoal{}}, nil
+		},
+	})
+
+	recap := func(caller *models.User, id uuid.UUID, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/cards/"+id.String()+"/recap"+query, nil)
+		req.SetPathValue("id", id.String())
+		if caller != nil {
+			req = req.WithContext(SetUserInContext(req.Context(), caller))
+		}
+		rr := httptest.NewRecorder()
+		handler.Recap(rr, req)
+		return rr
+	}
+
+	rr := recap(user, cardID, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp CardRecapResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Recap == nil || resp.Recap.CompletedItems != 3 {
+		t.Fatalf("unexpected response: %s", rr.Body.String())
+	}
+
+	rr = recap(user, cardID, "?format=png")
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("expected a png, got %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	if _, err := png.Decode(bytes.NewReader(rr.Body.Bytes())); err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+
+	assertErrorResponse(t, recap(user, cardID, "?format=pdf"), http.StatusBadRequest, "format must be json or png")
+	assertErrorResponse(t, recap(&models.User{ID: uuid.New()}, cardID, ""), http.StatusForbidden, "Access denied")
+	assertErrorResponse(t, recap(user, uuid.New(), ""), http.StatusNotFound, "Card not found")
+	assertErrorResponse(t, recap(nil, cardID, ""), http.StatusUnauthorized, "Authentication required")
+}
+
+func TestSharePublicHandler_Recap(t *testing.T) {
+	token := strings.Repeat("d", 64)
+	handler, err := NewSharePublicHandler("../../web/templates", &mockCardService{
+		BuildSharedRecapFunc: func(ctx context.Context, got string) (*models.CardRecap, error) {
+			if got != token {
+				return nil, services.ErrShareNotFound
+			}
+			return &models.CardRecap{Year: 2026, DisplayName: "2026 Bingo Card", TotalItems: 24, OldestOpenGoals: []models.RecapGoal{}}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	recap := func(tok, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/s/"+tok+"/recap"+query, nil)
+		req.SetPathValue("token", tok)
+		rr := httptest.NewRecorder()
+		handler.Recap(rr, req)
+		return rr
+	}
+
+	rr := recap(token, "?format=png")
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("expected a png, got %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	if rr.Header().Get("X-Robots-Tag") != "noindex" {
+		t.Fatal("expected shared recaps to be kept out of search indexes")
+	}
+	if rr := recap(token, ""); rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"recap"`) {
+		t.Fatalf("expected a json recap, got %d %s", rr.Code, rr.Body.String())
+	}
+	assertErrorResponse(t, recap(strings.Repeat("e", 64), ""), http.StatusNotFound, "Share link not found")
+	assertErrorResponse(t, recap("short", ""), http.StatusNotFound, "Share link not found")
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+const (
+	cardSearchMinQuery     = 2
+	cardSearchMaxQuery     = 100
+	cardSearchDefaultLimit = 20
+	cardSearchMaxLimit     = 50
+)
+
+// Search finds goals and card titles across the user's own cards, archived
+// ones included.
+func (h *CardHandler) Search(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	query := r.URL.Query()
+	params := models.CardSearchParams{
+		UserID: user.ID,
+		Query:  strings.TrimSpace(query.Get("q")),
+	}
+	if n := len([]rune(params.Query)); n < cardSearchMinQuery || n > cardSearchMaxQuery {
+		writeError(w, http.StatusBadRequest, "Query must be between 2 and 100 characters")
+		return
+	}
+	limit, ok := readLimit(w, r, cardSearchDefaultLimit, cardSearchMaxLimit)
+	if !ok {
+		return
+	}
+	params.Limit = limit
+	cursor, ok := h.readCursor(w, r, cursorScopeCardSearch)
+	if !ok {
+		return
+	}
+	params.Offset = cursor.Offset
+
+	result, err := h.cardService.Search(r.Context(), params)
+	if err != nil {
+		log.Printf("Error searching cards: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	result.Meta = h.offsetMeta(cursorScopeCardSearch, result.HasMore, params.Offset+params.Limit)
+
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func searchCards(handler *CardHandler, user *models.User, query url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/search?"+query.Encode(), nil)
+	if user != nil {
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+	}
+	rr := httptest.NewRecorder()
+	handler.Search(rr, req)
+	return rr
+}
+
+func TestCardHandler_Search(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	var got models.CardSearchParams
+	handler := NewCardHandler(&mockCardService{
+		SearchFunc: func(ctx context.Context, params models.CardSearchParams) (*models.CardSearchResult, error) {
+			got = params
+			return &models.CardSearchResult{
+				Items: []models.CardSearchMatch{{CardID: uuid.New(), Year: 2024, Items: []models.CardSearchItem{{
+					Position: 4,
+					Snippet:  []models.SnippetPart{{Text: "Learn to "}, {Text: "juggle", Match: true}},
+				}}}},
+			}, nil
+		},
+	})
+
+	rr := searchCards(handler, user, url.Values{"q": {"  juggle "}, "limit": {"50"}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got.UserID != user.ID || got.Query != "juggle" || got.Limit != 50 || got.Offset != 0 {
+		t.Fatalf("unexpected search params %+v", got)
+	}
+	var result models.CardSearchResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(result.Items) != 1 || result.Items[0].Items[0].Position != 4 || !result.Items[0].Items[0].Snippet[1].Match {
+		t.Fatalf("expected grouped hit with highlighted snippet, got %+v", result)
+	}
+	if strings.Contains(rr.Body.String(), `"offset"`) {
+		t.Fatalf("expected no raw offset in the response, got %s", rr.Body.String())
+	}
+}
+
+func TestCardHandler_Search_Validation(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewCardHandler(&mockCardService{
+		SearchFunc: func(ctx context.Context, params models.CardSearchParams) (*models.CardSearchResult, error) {
+			t.Fatal("expected no search")
+			return nil, nil
+		},
+	})
+
+	cases := map[string]struct {
+		query   url.Values
+		message string
+	}{
+		"short query": {url.Values{"q": {" a "}}, "Query must be between 2 and 100 characters"},
+		"bad limit":   {url.Values{"q": {"juggle"}, "limit": {"0"}}, "Invalid limit"},
+		"bad cursor":  {url.Values{"q": {"juggle"}, "cursor": {"forged"}}, "Invalid cursor"},
+		"other scope": {url.Values{"q": {"juggle"}, "cursor": {defaultCursorCodec.EncodeOffset(cursorScopeAdminSearch, 20)}}, "Invalid cursor"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assertErrorResponse(t, searchCards(handler, user, tc.query), http.StatusBadRequest, tc.message)
+		})
+	}
+
+	assertErrorResponse(t, searchCards(handler, nil, url.Values{"q": {"juggle"}}), http.StatusUnauthorized, "Authentication required")
+}
+
+func TestCardHandler_Search_PagesWithCursor(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	var got models.CardSearchParams
+	handler := NewCardHandler(&mockCardService{
+		SearchFunc: func(ctx context.Context, params models.CardSearchParams) (*models.CardSearchResult, error) {
+			got = params
+			result := &models.CardSearchResult{Items: []models.CardSearchMatch{}}
+			result.HasMore = params.Offset == 0
+			return result, nil
+		},
+	})
+
+	// Limits past the maximum are clamped rather than refused, and a raw
+	// offset parameter is ignored.
+	rr := searchCards(handler, user, url.Values{"q": {"juggle"}, "limit": {"500"}, "offset": {"9000"}})
+	if rr.Code != http.StatusOK || got.Limit != cardSearchMaxLimit || got.Offset != 0 {
+		t.Fatalf("expected the limit clamped to %d, got %d (status %d)", cardSearchMaxLimit, got.Limit, rr.Code)
+	}
+	var first models.CardSearchResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &first); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !first.HasMore || first.NextCursor == "" {
+		t.Fatalf("expected a next cursor, got %+v", first.Meta)
+	}
+
+	// The cursor carries the offset.
+	rr = searchCards(handler, user, url.Values{"q": {"juggle"}, "limit": {"500"}, "cursor": {first.NextCursor}, "offset": {"3"}})
+	if rr.Code != http.StatusOK || got.Offset != cardSearchMaxLimit {
+		t.Fatalf("expected the second page at offset %d, got %d (status %d)", cardSearchMaxLimit, got.Offset, rr.Code)
+	}
+	var second models.CardSearchResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &second); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if second.HasMore || second.NextCursor != "" {
+		t.Fatalf("expected the last page to have no cursor, got %+v", second.Meta)
+	}
+}
@@ -1,18 +1,36 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
+type ShareAnalyticsResponse struct {
+	Analytics *models.ShareAnalytics `json:"analytics"`
+}
+
 type ShareCardRequest struct {
 	ExpiresInDays *int `json:"expires_in_days,omitempty"`
+	// GraceDays keeps the replaced token working for that many days when an
+	// existing share is rotated. Zero or unset ends it immediately.
+	GraceDays *int `json:"grace_days,omitempty"`
+	// ShowCompletions and ShowNotes choose what the link reveals. Unset
+	// keeps the current setting; a new share shows completions but not
+	// notes.
+	ShowCompletions *bool `json:"show_completions,omitempty"`
+	ShowNotes       *bool `json:"show_notes,omitempty"`
 }
 
 type ShareStatusResponse struct {
@@ -23,7 +41,14 @@ type ShareStatusResponse struct {
 	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
 	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
 	AccessCount    int        `json:"access_count"`
-	Message        string     `json:"message,omitempty"`
+	// SupersededUntil is when the previous link, replaced by the last
+	// rotation, stops working.
+	SupersededUntil *time.Time `json:"superseded_until,omitempty"`
+	ShowCompletions bool       `json:"show_completions"`
+	ShowNotes       bool       `json:"show_notes"`
+	// Subscribers are the people following the card by email.
+	Subscribers []models.ShareSubscriber `json:"subscribers,omitempty"`
+	Message     string                   `json:"message,omitempty"`
 }
 
 func (h *CardHandler) CreateShare(w http.ResponseWriter, r *http.Request) {
@@ -62,7 +87,21 @@ func (h *CardHandler) CreateShare(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	share, err := h.cardService.CreateOrRotateShare(r.Context(), user.ID, cardID, expiresAt)
+	var graceUntil *time.Time
+	if req.GraceDays != nil {
+		days := *req.GraceDays
+		if days < 0 || days > services.ShareGraceMaxDays {
+			writeError(w, http.StatusBadRequest, "grace_days is out of range")
+			return
+		}
+		if days != 0 {
+			t := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+			graceUntil = &t
+		}
+	}
+
+	options := models.ShareOptions{ShowCompletions: req.ShowCompletions, ShowNotes: req.ShowNotes}
+	share, err := h.cardService.CreateOrRotateShare(r.Context(), user.ID, cardID, expiresAt, graceUntil, options)
 	if errors.Is(err, services.ErrCardNotFound) {
 		writeError(w, http.StatusNotFound, "Card not found")
 		return
@@ -83,12 +122,15 @@ func (h *CardHandler) CreateShare(w http.ResponseWriter, r *http.Request) {
 
 	url := share.Token
 	writeJSON(w, http.StatusCreated, ShareStatusResponse{
-		Enabled:        true,
-		URL:            url,
-		CreatedAt:      &share.CreatedAt,
-		ExpiresAt:      share.ExpiresAt,
-		LastAccessedAt: share.LastAccessedAt,
-		AccessCount:    share.AccessCount,
+		Enabled:         true,
+		URL:             url,
+		CreatedAt:       &share.CreatedAt,
+		ExpiresAt:       share.ExpiresAt,
+		LastAccessedAt:  share.LastAccessedAt,
+		AccessCount:     share.AccessCount,
+		SupersededUntil: share.SupersededUntil,
+		ShowCompletions: share.ShowCompletions,
+		ShowNotes:       share.ShowNotes,
 	})
 }
 
@@ -130,14 +172,28 @@ func (h *CardHandler) GetShareStatus(w http.ResponseWriter, r *http.Request) {
 		url = ""
 	}
 
+	var subscribers []models.ShareSubscriber
+	if h.shareSubscriptions != nil {
+		subscribers, err = h.shareSubscriptions.ListSubscribers(r.Context(), user.ID, cardID)
+		if err != nil {
+			log.Printf("Error listing share subscribers: %v", err)
+			writeError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+	}
+
 	writeJSON(w, http.StatusOK, ShareStatusResponse{
-		Enabled:        true,
-		Expired:        expired,
-		URL:            url,
-		CreatedAt:      &share.CreatedAt,
-		ExpiresAt:      share.ExpiresAt,
-		LastAccessedAt: share.LastAccessedAt,
-		AccessCount:    share.AccessCount,
+		Enabled:         true,
+		Expired:         expired,
+		URL:             url,
+		CreatedAt:       &share.CreatedAt,
+		ExpiresAt:       share.ExpiresAt,
+		LastAccessedAt:  share.LastAccessedAt,
+		AccessCount:     share.AccessCount,
+		SupersededUntil: share.SupersededUntil,
+		ShowCompletions: share.ShowCompletions,
+		ShowNotes:       share.ShowNotes,
+		Subscribers:     subscribers,
 	})
 }
 
@@ -175,6 +231,52 @@ func (h *CardHandler) RevokeShare(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// RemoveShareSubscriber stops one person's progress emails for the owner's
+// shared card.
+func (h *CardHandler) RemoveShareSubscriber(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if h.shareSubscriptions == nil {
+		writeError(w, http.StatusNotFound, "Subscriber not found")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+	subscriberID, err := uuid.Parse(r.PathValue("subscriberId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid subscriber ID")
+		return
+	}
+
+	err = h.shareSubscriptions.RemoveSubscriber(r.Context(), user.ID, cardID, subscriberID)
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if errors.Is(err, services.ErrShareSubscriptionNotFound) {
+		writeError(w, http.StatusNotFound, "Subscriber not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error removing share subscriber: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Subscriber removed"})
+}
+
 func (h *CardHandler) GetSharedCard(w http.ResponseWriter, r *http.Request) {
 	token := r.PathValue("token")
 	if token == "" {
@@ -182,7 +284,7 @@ func (h *CardHandler) GetSharedCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	shared, err := h.cardService.GetSharedCardByToken(r.Context(), token)
+	shared, err := h.cardService.GetSharedCardByToken(shareVisitContext(r), token)
 	if errors.Is(err, services.ErrShareNotFound) {
 		writeError(w, http.StatusNotFound, "Share link not found")
 		return
@@ -197,3 +299,61 @@ func (h *CardHandler) GetSharedCard(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("X-Robots-Tag", "noindex")
 	writeJSON(w, http.StatusOK, shared)
 }
+
+// shareVisitContext marks an API fetch of a shared card as a visit for the
+// owner's analytics, unless it comes from this site's own share page: the
+// /s/ landing that led there has already logged the visit.
+func shareVisitContext(r *http.Request) context.Context {
+	referrer := r.Referer()
+	if ref, err := url.Parse(referrer); err == nil && ref.Host != "" {
+		if base, err := url.Parse(resolveBaseURL(r)); err == nil && strings.EqualFold(ref.Host, base.Host) {
+			return r.Context()
+		}
+	}
+	return services.WithShareVisit(r.Context(), referrer, r.UserAgent(), shareSourceParam(r))
+}
+
+// shareSourceParam reads the owner's source tag from a share link, ?src= or
+// the UTM-style ?utm_source=. The service cleans it before storing.
+func shareSourceParam(r *http.Request) string {
+	query := r.URL.Query()
+	if source := query.Get("src"); source != "" {
+		return source
+	}
+	return query.Get("utm_source")
+}
+
+func (h *CardHandler) GetShareAnalytics(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	analytics, err := h.cardService.GetShareAnalytics(r.Context(), user.ID, cardID)
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if errors.Is(err, services.ErrShareNotFound) {
+		writeError(w, http.StatusNotFound, "Sharing is not enabled for this card")
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading share analytics: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ShareAnalyticsResponse{Analytics: analytics})
+}
@@ -19,14 +19,15 @@ import (
 
 type mockCardShareService struct {
 	services.CardServiceInterface
-	CreateOrRotateShareFunc func(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error)
+	CreateOrRotateShareFunc func(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error)
 	GetShareStatusFunc      func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardShare, error)
 	RevokeShareFunc         func(ctx context.Context, userID, cardID uuid.UUID) error
 	GetSharedCardFunc       func(ctx context.Context, token string) (*models.SharedCard, error)
+	GetShareAnalyticsFunc   func(ctx context.Context, userID, cardID uuid.UUID) (*models.ShareAnalytics, error)
 }
 
-func (m *mockCardShareService) CreateOrRotateShare(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error) {
-	return m.CreateOrRotateShareFunc(ctx, userID, cardID, expiresAt)
+func (m *mockCardShareService) CreateOrRotateShare(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
+	return m.CreateOrRotateShareFunc(ctx, userID, cardID, expiresAt, graceUntil, options)
 }
 
 func (m *mockCardShareService) GetShareStatus(ctx context.Context, userID, cardID uuid.UUID) (*models.CardShare, error) {
@@ -41,6 +42,10 @@ func (m *mockCardShareService) GetSharedCardByToken(ctx context.Context, token s
 	return m.GetSharedCardFunc(ctx, token)
 }
 
+func (m *mockCardShareService) GetShareAnalytics(ctx context.Context, userID, cardID uuid.UUID) (*models.ShareAnalytics, error) {
+	return m.GetShareAnalyticsFunc(ctx, userID, cardID)
+}
+
 func TestCardShare_Create_Unauthorized(t *testing.T) {
 	handler := NewCardHandler(&mockCardShareService{})
 	req := httptest.NewRequest(http.MethodPost, "/api/cards/123/share", nil)
@@ -58,7 +63,7 @@ func TestCardShare_Create_NotFinalized(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	cardID := uuid.New()
 	handler := NewCardHandler(&mockCardShareService{
-		CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error) {
+		CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
 			return nil, services.ErrCardNotFinalized
 		},
 	})
@@ -86,7 +91,7 @@ func TestCardShare_Create_Success(t *testing.T) {
 	}
 
 	handler := NewCardHandler(&mockCardShareService{
-		CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error) {
+		CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
 			return share, nil
 		},
 	})
@@ -294,7 +299,7 @@ func TestCardShare_Create_ExpiresInDaysValidation(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	cardID := uuid.New()
 	handler := NewCardHandler(&mockCardShareService{
-		CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error) {
+		CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
 			return &models.CardShare{CardID: cardID, Token: "t", CreatedAt: time.Now()}, nil
 		},
 	})
@@ -323,7 +328,7 @@ func TestCardShare_Create_ExpiresInDaysValidation(t *testing.T) {
 func TestCardShare_Create_InvalidCardIDAndInvalidBody(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	handler := NewCardHandler(&mockCardShareService{
-		CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error) {
+		CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
 			return nil, errors.New("should not be called")
 		},
 	})
@@ -356,7 +361,7 @@ func TestCardShare_Create_WithExpiration_PassesThrough(t *testing.T) {
 	expiresAt := now.Add(time.Duration(expiresDays) * 24 * time.Hour)
 
 	handler := NewCardHandler(&mockCardShareService{
-		CreateOrRotateShareFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, gotExpiresAt *time.Time) (*models.CardShare, error) {
+		CreateOrRotateShareFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, gotExpiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
 			if gotCardID != cardID {
 				t.Fatalf("expected cardID %v, got %v", cardID, gotCardID)
 			}
@@ -381,6 +386,78 @@ func TestCardShare_Create_WithExpiration_PassesThrough(t *testing.T) {
 	}
 }
 
+func TestCardShare_Create_GraceDays(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	var gotGrace *time.Time
+	handler := NewCardHandler(&mockCardShareService{
+		CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
+			gotGrace = graceUntil
+			return &models.CardShare{CardID: cardID, Token: "t", CreatedAt: time.Now(), SupersededUntil: graceUntil}, nil
+		},
+	})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/cards/"+cardID.String()+"/share", strings.NewReader(body))
+		req.SetPathValue("id", cardID.String())
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.CreateShare(rr, req)
+		return rr
+	}
+
+	for _, body := range []string{`{"grace_days":-1}`, fmt.Sprintf(`{"grace_days":%d}`, services.ShareGraceMaxDays+1)} {
+		if rr := post(body); rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d", body, rr.Code)
+		}
+	}
+
+	rr := post(`{"grace_days":7}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", rr.Code)
+	}
+	want := time.Now().Add(7 * 24 * time.Hour)
+	if gotGrace == nil || gotGrace.Before(want.Add(-time.Minute)) || gotGrace.After(want.Add(time.Minute)) {
+		t.Fatalf("expected grace deadline near %v, got %v", want, gotGrace)
+	}
+	var response ShareStatusResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.SupersededUntil == nil {
+		t.Fatalf("expected superseded_until in response, got %+v", response)
+	}
+
+	if rr := post(`{"grace_days":0}`); rr.Code != http.StatusCreated || gotGrace != nil {
+		t.Fatalf("expected zero grace days to end the old link immediately, status %d grace %v", rr.Code, gotGrace)
+	}
+}
+
+func TestCardShare_Status_ReportsSupersededGrace(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	graceUntil := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	handler := NewCardHandler(&mockCardShareService{
+		GetShareStatusFunc: func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardShare, error) {
+			return &models.CardShare{CardID: cardID, Token: "new", CreatedAt: time.Now(), SupersededUntil: &graceUntil}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/share", nil)
+	req.SetPathValue("id", cardID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.GetShareStatus(rr, req)
+
+	var response ShareStatusResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.SupersededUntil == nil || !response.SupersededUntil.Equal(graceUntil) {
+		t.Fatalf("expected superseded_until %v, got %+v", graceUntil, response)
+	}
+}
+
 func TestCardShare_Create_ErrorMappings(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	cardID := uuid.New()
@@ -396,7 +473,7 @@ func TestCardShare_Create_ErrorMappings(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			handler := NewCardHandler(&mockCardShareService{
-				CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error) {
+				CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
 					return nil, tc.err
 				},
 			})
@@ -499,3 +576,90 @@ func TestCardShare_Public_InvalidTokenAndInternalError(t *testing.T) {
 		t.Fatalf("expected status 500, got %d", rr.Code)
 	}
 }
+
+func TestCardShare_Create_ShareOptions(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	var got models.ShareOptions
+	handler := NewCardHandler(&mockCardShareService{
+		CreateOrRotateShareFunc: func(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
+			got = options
+			return &models.CardShare{CardID: cardID, Token: "t", CreatedAt: time.Now(), ShowNotes: true}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cards/"+cardID.String()+"/share", strings.NewReader(`{"show_completions":false,"show_notes":true}`))
+	req.SetPathValue("id", cardID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.CreateShare(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", rr.Code)
+	}
+	if got.ShowCompletions == nil || *got.ShowCompletions || got.ShowNotes == nil || !*got.ShowNotes {
+		t.Fatalf("expected options passed through, got %+v", got)
+	}
+	if !strings.Contains(rr.Body.String(), `"show_completions":false`) || !strings.Contains(rr.Body.String(), `"show_notes":true`) {
+		t.Fatalf("expected options in response, got %s", rr.Body.String())
+	}
+}
+
+func TestCardShare_Analytics(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	analytics := &models.ShareAnalytics{
+		Days:       []models.ShareAnalyticsDay{{Date: "2026-10-16", Count: 2}},
+		Total:      2,
+		Referrers:  []models.ShareReferrerCount{{Host: "example.com", Count: 2}},
+		UserAgents: map[string]int{"desktop": 2},
+	}
+	tests := []struct {
+		name     string
+		user     *models.User
+		err      error
+		wantCode int
+	}{
+		{"unauthenticated", nil, nil, http.StatusUnauthorized},
+		{"not shared", user, services.ErrShareNotFound, http.StatusNotFound},
+		{"not owner", user, services.ErrNotCardOwner, http.StatusForbidden},
+		{"success", user, nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewCardHandler(&mockCardShareService{
+				GetShareAnalyticsFunc: func(ctx context.Context, userID, gotCardID uuid.UUID) (*models.ShareAnalytics, error) {
+					if userID != user.ID || gotCardID != cardID {
+						t.Fatalf("unexpected user or card: %s %s", userID, gotCardID)
+					}
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return analytics, nil
+				},
+			})
+			req := httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/share/analytics", nil)
+			req.SetPathValue("id", cardID.String())
+			if tt.user != nil {
+				req = req.WithContext(SetUserInContext(req.Context(), tt.user))
+			}
+			rr := httptest.NewRecorder()
+
+			handler.GetShareAnalytics(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, rr.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp ShareAnalyticsResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if resp.Analytics == nil || resp.Analytics.Total != 2 || resp.Analytics.Referrers[0].Host != "example.com" {
+				t.Fatalf("unexpected analytics: %+v", resp.Analytics)
+			}
+		})
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -124,6 +127,49 @@ func TestCardHandler_Create_Success(t *testing.T) {
 	}
 }
 
+func TestCardHandler_Create_DerivesYearFromStartDate(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	year := time.Now().Year()
+	var got models.CreateCardParams
+	mockCard := &mockCardService{
+		CreateFunc: func(ctx context.Context, params models.CreateCardParams) (*models.BingoCard, error) {
+			got = params
+			return &models.BingoCard{ID: uuid.New(), UserID: user.ID, Year: params.Year}, nil
+		},
+	}
+	handler := NewCardHandler(mockCard)
+
+	body := fmt.Sprintf(`{"start_date":"%d-09-01","end_date":"%d-08-31"}`, year, year+1)
+	req := httptest.NewRequest(http.MethodPost, "/api/cards", bytes.NewBufferString(body))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Create(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got.Year != year {
+		t.Fatalf("expected year %d, got %d", year, got.Year)
+	}
+	if got.StartDate == nil || got.StartDate.Month() != time.September || got.EndDate == nil || got.EndDate.Year() != year+1 {
+		t.Fatalf("expected parsed period, got %v - %v", got.StartDate, got.EndDate)
+	}
+}
+
+func TestCardHandler_Create_InvalidDateFormat(t *testing.T) {
+	handler := NewCardHandler(nil)
+	user := &models.User{ID: uuid.New()}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cards", bytes.NewBufferString(`{"start_date":"09/01/2025"}`))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Create(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Dates must use YYYY-MM-DD format")
+}
+
 func ptrToInt(i int) *int {
 	return &i
 }
@@ -311,6 +357,78 @@ func TestCardHandler_AddItem_ContentTooLong(t *testing.T) {
 	}
 }
 
+func TestCardHandler_AddItem_Difficulty(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	var got *string
+	handler := NewCardHandler(&mockCardService{
+		AddItemFunc: func(ctx context.Context, userID uuid.UUID, params models.AddItemParams) (*models.BingoItem, error) {
+			got = params.Difficulty
+			return &models.BingoItem{ID: uuid.New(), Difficulty: params.Difficulty}, nil
+		},
+	})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/cards/"+uuid.New().String()+"/items", bytes.NewBufferString(body))
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.AddItem(rr, req)
+		return rr
+	}
+
+	rr := post(`{"content":"Run a marathon","difficulty":"extreme"}`)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Difficulty must be easy, medium or hard")
+
+	rr = post(`{"content":"Run a marathon","difficulty":"hard"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+	if got == nil || *got != models.DifficultyHard {
+		t.Fatalf("expected hard difficulty passed to service, got %v", got)
+	}
+
+	rr = post(`{"content":"Run a marathon","difficulty":""}`)
+	if rr.Code != http.StatusCreated || got != nil {
+		t.Fatalf("expected empty difficulty to be untagged, got code=%d difficulty=%v", rr.Code, got)
+	}
+}
+
+func TestCardHandler_UpdateItem_InvalidDifficulty(t *testing.T) {
+	handler := NewCardHandler(nil)
+
+	user := &models.User{ID: uuid.New()}
+	req := httptest.NewRequest(http.MethodPut, "/api/cards/"+uuid.New().String()+"/items/0", bytes.NewBufferString(`{"difficulty":"Hard"}`))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.UpdateItem(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Difficulty must be easy, medium or hard")
+}
+
+func TestCardHandler_AddItem_LimitCountsCharactersAndPassesTruncationWarning(t *testing.T) {
+	handler := NewCardHandler(&mockCardService{
+		AddItemFunc: func(ctx context.Context, userID uuid.UUID, params models.AddItemParams) (*models.BingoItem, error) {
+			return &models.BingoItem{Content: params.Content, ContentTruncated: true}, nil
+		},
+	})
+
+	// 500 multi-byte characters is well over 500 bytes but within the limit.
+	content := strings.Repeat("é", models.MaxItemContentLength)
+	bodyBytes, _ := json.Marshal(AddItemRequest{Content: content})
+	req := httptest.NewRequest(http.MethodPost, "/api/cards/"+uuid.New().String()+"/items", bytes.NewBuffer(bodyBytes))
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.AddItem(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), `"content_truncated":true`) {
+		t.Fatalf("expected content_truncated warning in response, got %s", rr.Body.String())
+	}
+}
+
 func TestCardHandler_UpdateItem_Unauthenticated(t *testing.T) {
 	handler := NewCardHandler(nil)
 
@@ -409,6 +527,45 @@ func TestCardHandler_Shuffle_Unauthenticated(t *testing.T) {
 	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
 }
 
+func TestCardHandler_Shuffle_Seed(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	var gotSeed *int64
+	handler := NewCardHandler(&mockCardService{
+		ShuffleFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, seed *int64) (*services.ShuffleResult, error) {
+			gotSeed = seed
+			used := int64(99)
+			if seed != nil {
+				used = *seed
+			}
+			return &services.ShuffleResult{Card: &models.BingoCard{ID: gotCardID}, Seed: used}, nil
+		},
+	})
+
+	shuffle := func(body string) ShuffleResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/cards/"+cardID.String()+"/shuffle", strings.NewReader(body))
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.Shuffle(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var response ShuffleResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		return response
+	}
+
+	if response := shuffle(""); gotSeed != nil || response.Seed != 99 {
+		t.Fatalf("expected a generated seed to be returned, got seed=%v response=%d", gotSeed, response.Seed)
+	}
+	if response := shuffle(`{"seed":12345}`); gotSeed == nil || *gotSeed != 12345 || response.Seed != 12345 {
+		t.Fatalf("expected seed 12345 to be passed through, got %v", response.Seed)
+	}
+}
+
 func TestCardHandler_SwapItems_Unauthenticated(t *testing.T) {
 	handler := NewCardHandler(nil)
 
@@ -597,8 +754,8 @@ func TestCardHandler_BulkUpdateVisibility_InvalidCardID(t *testing.T) {
 func TestCardHandler_BulkUpdateVisibility_ServiceError(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	mockCard := &mockCardService{
-		BulkUpdateVisibilityFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error) {
-			return 0, errors.New("bulk visibility error")
+		BulkUpdateVisibilityFunc: func(ctx context.Context, userID uuid.UUID, changes []models.CardVisibilityChange) ([]models.CardVisibilityResult, error) {
+			return nil, errors.New("bulk visibility error")
 		},
 	}
 	handler := NewCardHandler(mockCard)
@@ -616,6 +773,99 @@ func TestCardHandler_BulkUpdateVisibility_ServiceError(t *testing.T) {
 	}
 }
 
+func TestCardHandler_BulkUpdateVisibility_PerCardChanges(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	hideID := uuid.New()
+	showID := uuid.New()
+	mockCard := &mockCardService{
+		BulkUpdateVisibilityFunc: func(ctx context.Context, userID uuid.UUID, changes []models.CardVisibilityChange) ([]models.CardVisibilityResult, error) {
+			if len(changes) != 2 || changes[0].CardID != hideID || changes[0].VisibleToFriends || changes[1].CardID != showID || !changes[1].VisibleToFriends {
+				t.Fatalf("unexpected changes: %+v", changes)
+			}
+			return []models.CardVisibilityResult{
+				{CardID: hideID, VisibleToFriends: false, Changed: true},
+				{CardID: showID, VisibleToFriends: true, Changed: false},
+			}, nil
+		},
+	}
+	handler := NewCardHandler(mockCard)
+
+	body := BulkUpdateVisibilityRequest{Changes: []BulkVisibilityChange{
+		{CardID: hideID.String(), VisibleToFriends: false},
+		{CardID: showID.String(), VisibleToFriends: true},
+	}}
+	bodyBytes, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPut, "/api/cards/visibility/bulk", bytes.NewBuffer(bodyBytes))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.BulkUpdateVisibility(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp BulkResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Total != 2 || len(resp.Failed) != 0 || len(resp.Succeeded) != 2 || resp.Succeeded[0] != hideID.String() {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestCardHandler_BulkUpdateVisibility_DuplicateCardID(t *testing.T) {
+	handler := NewCardHandler(&mockCardService{})
+
+	id := uuid.New().String()
+	body := BulkUpdateVisibilityRequest{Changes: []BulkVisibilityChange{
+		{CardID: id, VisibleToFriends: true},
+		{CardID: id, VisibleToFriends: false},
+	}}
+	bodyBytes, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPut, "/api/cards/visibility/bulk", bytes.NewBuffer(bodyBytes))
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.BulkUpdateVisibility(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Duplicate card ID: "+id)
+}
+
+func TestCardHandler_BulkUpdateVisibility_CardsNotOwned(t *testing.T) {
+	foreignID := uuid.New()
+	mockCard := &mockCardService{
+		BulkUpdateVisibilityFunc: func(ctx context.Context, userID uuid.UUID, changes []models.CardVisibilityChange) ([]models.CardVisibilityResult, error) {
+			return nil, &services.CardsNotOwnedError{CardIDs: []uuid.UUID{foreignID}}
+		},
+	}
+	handler := NewCardHandler(mockCard)
+
+	ownedID := uuid.New()
+	body := BulkUpdateVisibilityRequest{Changes: []BulkVisibilityChange{
+		{CardID: ownedID.String(), VisibleToFriends: true},
+		{CardID: foreignID.String(), VisibleToFriends: true},
+	}}
+	bodyBytes, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPut, "/api/cards/visibility/bulk", bytes.NewBuffer(bodyBytes))
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.BulkUpdateVisibility(rr, req)
+
+	assertErrorResponse(t, rr, http.StatusNotFound, "Cards not found: "+foreignID.String())
+	var resp BulkResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	want := []BulkFailure{
+		{ID: ownedID.String(), Code: ErrorCodeAborted, Message: "Not changed because another card was not found"},
+		{ID: foreignID.String(), Code: ErrorCodeNotFound, Message: "Card not found"},
+	}
+	if resp.Total != 2 || len(resp.Succeeded) != 0 || !reflect.DeepEqual(resp.Failed, want) {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
 func TestCardHandler_BulkDelete_Unauthenticated(t *testing.T) {
 	handler := NewCardHandler(nil)
 
@@ -649,8 +899,8 @@ func TestCardHandler_BulkDelete_EmptyCardIDs(t *testing.T) {
 func TestCardHandler_BulkDelete_ServiceError(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	mockCard := &mockCardService{
-		BulkDeleteFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error) {
-			return 0, errors.New("bulk delete error")
+		BulkDeleteFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) ([]uuid.UUID, error) {
+			return nil, errors.New("bulk delete error")
 		},
 	}
 	handler := NewCardHandler(mockCard)
@@ -701,8 +951,8 @@ func TestCardHandler_BulkUpdateArchive_EmptyCardIDs(t *testing.T) {
 func TestCardHandler_BulkUpdateArchive_ServiceError(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	mockCard := &mockCardService{
-		BulkUpdateArchiveFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) (int, error) {
-			return 0, errors.New("bulk archive error")
+		BulkUpdateArchiveFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) ([]uuid.UUID, error) {
+			return nil, errors.New("bulk archive error")
 		},
 	}
 	handler := NewCardHandler(mockCard)
@@ -923,6 +1173,156 @@ func TestCardHandler_UpdateConfig_Success(t *testing.T) {
 	}
 }
 
+func TestCardHandler_UpdateConfig_ForwardsRequireProof(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+
+	var gotParams models.UpdateCardConfigParams
+	handler := NewCardHandler(&mockCardService{
+		UpdateConfigFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, params models.UpdateCardConfigParams) (*models.BingoCard, error) {
+			gotParams = params
+			return &models.BingoCard{ID: cardID, UserID: user.ID, RequireProof: true}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/cards/"+cardID.String()+"/config", strings.NewReader(`{"require_proof":true}`))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.UpdateConfig(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if gotParams.RequireProof == nil || !*gotParams.RequireProof || !gotParams.OnlyRequireProof() {
+		t.Fatalf("expected require_proof-only update, got %+v", gotParams)
+	}
+	if !strings.Contains(rr.Body.String(), `"require_proof":true`) {
+		t.Fatalf("expected require_proof in response, got %s", rr.Body.String())
+	}
+}
+
+func TestCardHandler_CompleteItem_ProofRequired(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	handler := NewCardHandler(&mockCardService{
+		CompleteItemFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error) {
+			return nil, services.ErrProofRequired
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/cards/"+cardID.String()+"/items/3/complete", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.CompleteItem(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+	var resp CompleteItemErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Code != ErrorCodeProofRequired || resp.Error == "" {
+		t.Fatalf("unexpected error response: %+v", resp)
+	}
+}
+
+func TestCardHandler_CompleteItem_AlreadyCompleted(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	handler := NewCardHandler(&mockCardService{
+		CompleteItemFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error) {
+			return nil, services.ErrItemCompleted
+		},
+		CompleteItemByContentFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, content string, exact bool, params models.CompleteItemParams) (*models.BingoItem, error) {
+			return nil, services.ErrItemCompleted
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/cards/"+cardID.String()+"/items/3/complete", strings.NewReader(`{"proof_url":"https://example.com/new.jpg"}`))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.CompleteItem(rr, req)
+	assertErrorResponse(t, rr, http.StatusConflict, "Goal is already completed; edit its notes or proof instead")
+
+	req = httptest.NewRequest(http.MethodPut, "/api/cards/"+cardID.String()+"/items/complete-by-content", strings.NewReader(`{"content":"run","notes":"again"}`))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr = httptest.NewRecorder()
+	handler.CompleteItemByContent(rr, req)
+	assertErrorResponse(t, rr, http.StatusConflict, "Goal is already completed; edit its notes or proof instead")
+}
+
+func TestCardHandler_CompleteItemByContent(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	target := "/api/cards/" + cardID.String() + "/items/complete-by-content"
+
+	send := func(handler *CardHandler, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, target, strings.NewReader(body))
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.CompleteItemByContent(rr, req)
+		return rr
+	}
+
+	t.Run("completes the resolved item", func(t *testing.T) {
+		handler := NewCardHandler(&mockCardService{
+			CompleteItemByContentFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, content string, exact bool, params models.CompleteItemParams) (*models.BingoItem, error) {
+				if userID != user.ID || gotCardID != cardID || content != "Go for a run" || !exact {
+					t.Fatalf("unexpected args: %v %v %q %v", userID, gotCardID, content, exact)
+				}
+				return &models.BingoItem{Position: 7, Content: "Go for a run", IsCompleted: true}, nil
+			},
+		})
+		rr := send(handler, `{"content":"Go for a run","exact":true}`)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+	})
+
+	t.Run("ambiguous lists candidates", func(t *testing.T) {
+		handler := NewCardHandler(&mockCardService{
+			CompleteItemByContentFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, content string, exact bool, params models.CompleteItemParams) (*models.BingoItem, error) {
+				return nil, &services.ItemAmbiguousError{Candidates: []models.BingoItem{
+					{Position: 1, Content: "Read a book"},
+					{Position: 4, Content: "Read a poem"},
+				}}
+			},
+		})
+		rr := send(handler, `{"content":"read a"}`)
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected status 409, got %d", rr.Code)
+		}
+		var resp AmbiguousItemResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		want := []ItemCandidate{{Position: 1, Content: "Read a book"}, {Position: 4, Content: "Read a poem"}}
+		if resp.Error == "" || !reflect.DeepEqual(resp.Candidates, want) {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		handler := NewCardHandler(&mockCardService{
+			CompleteItemByContentFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, content string, exact bool, params models.CompleteItemParams) (*models.BingoItem, error) {
+				return nil, services.ErrItemNotFound
+			},
+		})
+		assertErrorResponse(t, send(handler, `{"content":"swim"}`), http.StatusNotFound, "No goal matches that content")
+	})
+
+	t.Run("content required", func(t *testing.T) {
+		handler := NewCardHandler(&mockCardService{
+			CompleteItemByContentFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, content string, exact bool, params models.CompleteItemParams) (*models.BingoItem, error) {
+				t.Fatal("service should not be called")
+				return nil, nil
+			},
+		})
+		assertErrorResponse(t, send(handler, `{"content":"  "}`), http.StatusBadRequest, "Content is required")
+	})
+}
+
 func TestCardHandler_Clone_SuccessAndTruncationMessage(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	cardID := uuid.New()
@@ -1150,6 +1550,109 @@ func TestCardHandler_StatsAndUpdateVisibility_Success(t *testing.T) {
 	}
 }
 
+func TestCardHandler_AllStats(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+
+	var gotArchived []bool
+	handler := NewCardHandler(&mockCardService{
+		ListProgressFunc: func(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]models.CardProgress, error) {
+			if userID != user.ID {
+				t.Fatalf("unexpected user %v", userID)
+			}
+			gotArchived = append(gotArchived, includeArchived)
+			if includeArchived {
+				return []models.CardProgress{{CardID: uuid.New(), Year: 2025, IsArchived: true, TotalItems: 24}}, nil
+			}
+			return nil, nil
+		},
+	})
+
+	for _, target := range []string{"/api/cards/stats", "/api/cards/stats?include_archived=true"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.AllStats(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %s, got %d", target, rr.Code)
+		}
+		var body map[string][]map[string]any
+		if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		cards, ok := body["cards"]
+		if !ok || cards == nil {
+			t.Fatalf("expected a cards array for %s, got %s", target, rr.Body.String())
+		}
+		for _, card := range cards {
+			if _, ok := card["last_completion"]; !ok {
+				t.Fatalf("expected last_completion to always be present, got %v", card)
+			}
+		}
+	}
+	if len(gotArchived) != 2 || gotArchived[0] || !gotArchived[1] {
+		t.Fatalf("unexpected include_archived values %v", gotArchived)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/stats", nil)
+	rr := httptest.NewRecorder()
+	handler.AllStats(rr, req)
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestCardHandler_Recommendations(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+
+	var gotLimit int
+	mockCard := &mockCardService{
+		GetRecommendationsFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, limit int) ([]models.CardRecommendation, error) {
+			if userID != user.ID || gotCardID != cardID {
+				t.Fatalf("unexpected ids: %v %v", userID, gotCardID)
+			}
+			gotLimit = limit
+			return []models.CardRecommendation{
+				{Item: models.BingoItem{Position: 2, Content: "Run"}, Lines: []string{"row 1"}, Reason: "completes row 1"},
+			}, nil
+		},
+	}
+	handler := NewCardHandler(mockCard)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/recommendations?limit=5", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.Recommendations(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if gotLimit != 5 {
+		t.Fatalf("expected limit 5, got %d", gotLimit)
+	}
+	var resp CardRecommendationsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Recommendations) != 1 || resp.Recommendations[0].Reason != "completes row 1" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	for _, limit := range []string{"0", "11", "abc"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/recommendations?limit="+limit, nil)
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr = httptest.NewRecorder()
+		handler.Recommendations(rr, req)
+		assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid limit")
+	}
+
+	mockCard.GetRecommendationsFunc = func(ctx context.Context, userID, gotCardID uuid.UUID, limit int) ([]models.CardRecommendation, error) {
+		return nil, services.ErrNotCardOwner
+	}
+	req = httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/recommendations", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr = httptest.NewRecorder()
+	handler.Recommendations(rr, req)
+	assertErrorResponse(t, rr, http.StatusForbidden, "Access denied")
+}
+
 func TestCardHandler_ListGetDeleteAndOtherEndpoints_Success(t *testing.T) {
 	user := &models.User{ID: uuid.New()}
 	cardID := uuid.New()
@@ -1167,8 +1670,8 @@ func TestCardHandler_ListGetDeleteAndOtherEndpoints_Success(t *testing.T) {
 		RemoveItemFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, position int) error {
 			return nil
 		},
-		ShuffleFunc: func(ctx context.Context, userID, gotCardID uuid.UUID) (*models.BingoCard, error) {
-			return &models.BingoCard{ID: gotCardID, UserID: userID}, nil
+		ShuffleFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, seed *int64) (*services.ShuffleResult, error) {
+			return &services.ShuffleResult{Card: &models.BingoCard{ID: gotCardID, UserID: userID}, Seed: 7}, nil
 		},
 		SwapItemsFunc: func(ctx context.Context, userID, gotCardID uuid.UUID, pos1, pos2 int) error {
 			if pos1 != 1 || pos2 != 2 {
@@ -1185,17 +1688,24 @@ func TestCardHandler_ListGetDeleteAndOtherEndpoints_Success(t *testing.T) {
 		GetArchiveFunc: func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
 			return nil, nil
 		},
-		BulkUpdateVisibilityFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error) {
-			return len(cardIDs), nil
+		BulkUpdateVisibilityFunc: func(ctx context.Context, userID uuid.UUID, changes []models.CardVisibilityChange) ([]models.CardVisibilityResult, error) {
+			results := make([]models.CardVisibilityResult, len(changes))
+			for i, change := range changes {
+				if !change.VisibleToFriends {
+					t.Fatalf("expected legacy visible_to_friends applied to every card")
+				}
+				results[i] = models.CardVisibilityResult{CardID: change.CardID, VisibleToFriends: true, Changed: true}
+			}
+			return results, nil
 		},
-		BulkDeleteFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error) {
-			return len(cardIDs), nil
+		BulkDeleteFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) ([]uuid.UUID, error) {
+			return cardIDs, nil
 		},
-		BulkUpdateArchiveFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) (int, error) {
+		BulkUpdateArchiveFunc: func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) ([]uuid.UUID, error) {
 			if !isArchived {
 				t.Fatalf("expected isArchived true")
 			}
-			return len(cardIDs), nil
+			return cardIDs[:1], nil
 		},
 	}
 	handler := NewCardHandler(mockCard)
@@ -1295,6 +1805,14 @@ func TestCardHandler_ListGetDeleteAndOtherEndpoints_Success(t *testing.T) {
 	if rr.Code != http.StatusOK {
 		t.Fatalf("expected bulk archive 200, got %d", rr.Code)
 	}
+	var resp BulkResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Total != 2 || len(resp.Succeeded) != 1 || resp.Succeeded[0] != id1.String() ||
+		len(resp.Failed) != 1 || resp.Failed[0].ID != id2.String() || resp.Failed[0].Code != ErrorCodeNotFound {
+		t.Fatalf("expected the unowned card reported as not found, got %+v", resp)
+	}
 }
 
 func TestCardHandler_Import_Success(t *testing.T) {
@@ -1377,6 +1895,89 @@ func TestCardHandler_Import_ServiceError(t *testing.T) {
 	}
 }
 
+func TestCardHandler_Import_MergeIntoTarget(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	targetID := uuid.New()
+	mockCard := &mockCardService{
+		MergeImportFunc: func(ctx context.Context, params models.MergeImportParams) (*models.MergeImportResult, error) {
+			if params.CardID != targetID || params.UserID != user.ID {
+				t.Fatalf("unexpected merge params: %+v", params)
+			}
+			if !params.DryRun || len(params.Items) != 2 {
+				t.Fatalf("expected dry run with 2 items, got %+v", params)
+			}
+			return &models.MergeImportResult{DryRun: true, Duplicates: []string{"b"}}, nil
+		},
+	}
+	handler := NewCardHandler(mockCard)
+
+	target := targetID.String()
+	bodyBytes, _ := json.Marshal(ImportCardRequest{
+		TargetCardID: &target,
+		DryRun:       true,
+		Items: []ImportCardItem{
+			{Position: 0, Content: "a"},
+			{Position: 1, Content: "b"},
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/cards/import", bytes.NewBuffer(bodyBytes))
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+
+	handler.Import(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp models.MergeImportResult
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !resp.DryRun || len(resp.Duplicates) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestCardHandler_Import_MergeErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		{"invalid target", "not-a-uuid", nil, http.StatusBadRequest, "Invalid target card ID"},
+		{"not found", uuid.NewString(), services.ErrCardNotFound, http.StatusNotFound, "Card not found"},
+		{"not owner", uuid.NewString(), services.ErrNotCardOwner, http.StatusForbidden, "Access denied"},
+		{"finalized", uuid.NewString(), services.ErrCardFinalized, http.StatusBadRequest, "Cannot import into a finalized card"},
+		{"short", uuid.NewString(), fmt.Errorf("%w: 3 new items but only 1 open (2 short)", services.ErrNotEnoughOpenSquares), http.StatusBadRequest, "not enough open squares: 3 new items but only 1 open (2 short)"},
+		{"internal", uuid.NewString(), errors.New("db down"), http.StatusInternalServerError, "Internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCard := &mockCardService{
+				MergeImportFunc: func(ctx context.Context, params models.MergeImportParams) (*models.MergeImportResult, error) {
+					return nil, tt.err
+				},
+			}
+			handler := NewCardHandler(mockCard)
+
+			target := tt.target
+			bodyBytes, _ := json.Marshal(ImportCardRequest{
+				TargetCardID: &target,
+				Items:        []ImportCardItem{{Position: 0, Content: "a"}},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/cards/import", bytes.NewBuffer(bodyBytes))
+			req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+			rr := httptest.NewRecorder()
+
+			handler.Import(rr, req)
+
+			assertErrorResponse(t, rr, tt.wantStatus, tt.wantMsg)
+		})
+	}
+}
+
 func TestParseCardID(t *testing.T) {
 	validID := uuid.New()
 
@@ -1488,3 +2089,59 @@ func TestParsePosition(t *testing.T) {
 		})
 	}
 }
+
+func TestCardHandler_Memories(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewCardHandler(nil)
+		rr := httptest.NewRecorder()
+		handler.Memories(rr, httptest.NewRequest(http.MethodGet, "/api/memories", nil))
+		assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		handler := NewCardHandler(&mockCardService{})
+		req := httptest.NewRequest(http.MethodGet, "/api/memories?tz=Mars/Olympus", nil)
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.Memories(rr, req)
+		assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid timezone")
+	})
+
+	t.Run("uses timezone and returns empty array", func(t *testing.T) {
+		var gotLoc *time.Location
+		handler := NewCardHandler(&mockCardService{
+			GetMemoriesFunc: func(ctx context.Context, userID uuid.UUID, now time.Time, loc *time.Location) ([]models.Memory, error) {
+				gotLoc = loc
+				return nil, nil
+			},
+		})
+		req := httptest.NewRequest(http.MethodGet, "/api/memories?tz=America/New_York", nil)
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.Memories(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if gotLoc == nil || gotLoc.String() != "America/New_York" {
+			t.Fatalf("expected New York location, got %v", gotLoc)
+		}
+		if !strings.Contains(rr.Body.String(), `"memories":[]`) {
+			t.Fatalf("expected empty memories array, got %s", rr.Body.String())
+		}
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		handler := NewCardHandler(&mockCardService{
+			GetMemoriesFunc: func(ctx context.Context, userID uuid.UUID, now time.Time, loc *time.Location) ([]models.Memory, error) {
+				return nil, errors.New("boom")
+			},
+		})
+		req := httptest.NewRequest(http.MethodGet, "/api/memories", nil)
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.Memories(rr, req)
+		assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+	})
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// WidgetToken is a widget token as shown in the card's settings. URL is the
+// path of the progress image to paste into a widget app.
+type WidgetToken struct {
+	ID             uuid.UUID  `json:"id"`
+	URL            string     `json:"url"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	AccessCount    int        `json:"access_count"`
+}
+
+type WidgetTokensResponse struct {
+	Tokens []WidgetToken `json:"tokens"`
+}
+
+func newWidgetToken(widget models.CardWidgetToken) WidgetToken {
+	return WidgetToken{
+		ID:             widget.ID,
+		URL:            "/w/" + widget.Token + ".png",
+		CreatedAt:      widget.CreatedAt,
+		LastAccessedAt: widget.LastAccessedAt,
+		AccessCount:    widget.AccessCount,
+	}
+}
+
+func (h *CardHandler) CreateWidgetToken(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	widget, err := h.cardService.CreateWidgetToken(r.Context(), user.ID, cardID)
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if errors.Is(err, services.ErrWidgetTokenLimit) {
+		writeError(w, http.StatusConflict, "This card already has the maximum number of widget links; revoke one first")
+		return
+	}
+	if err != nil {
+		log.Printf("Error creating widget token: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newWidgetToken(*widget))
+}
+
+func (h *CardHandler) ListWidgetTokens(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	widgets, err := h.cardService.ListWidgetTokens(r.Context(), user.ID, cardID)
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if err != nil {
+		log.Printf("Error listing widget tokens: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	tokens := make([]WidgetToken, len(widgets))
+	for i, widget := range widgets {
+		tokens[i] = newWidgetToken(widget)
+	}
+	writeJSON(w, http.StatusOK, WidgetTokensResponse{Tokens: tokens})
+}
+
+func (h *CardHandler) RevokeWidgetToken(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := parseCardID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+	tokenID, err := uuid.Parse(r.PathValue("tokenId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid widget token ID")
+		return
+	}
+
+	err = h.cardService.RevokeWidgetToken(r.Context(), user.ID, cardID, tokenID)
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrNotCardOwner) {
+		writeError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if errors.Is(err, services.ErrWidgetTokenNotFound) {
+		writeError(w, http.StatusNotFound, "Widget token not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error revoking widget token: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Widget link revoked"})
+}
+
+// ServeWidgetImage serves the progress strip for a widget token. Widgets poll
+// it, so it is cacheable for 15 minutes.
+func (h *CardHandler) ServeWidgetImage(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(strings.TrimSpace(r.PathValue("token")), ".png")
+	if !isValidShareToken(token) {
+		http.NotFound(w, r)
+		return
+	}
+
+	pngBytes, err := h.cardService.RenderWidgetByToken(r.Context(), token)
+	if errors.Is(err, services.ErrWidgetTokenNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("Error rendering widget image: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=900")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pngBytes)
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestCardHandler_CreateWidgetToken(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	token := strings.Repeat("ab", 32)
+
+	handler := NewCardHandler(&mockCardService{
+		CreateWidgetTokenFunc: func(ctx context.Context, userID, gotCardID uuid.UUID) (*models.CardWidgetToken, error) {
+			if userID != user.ID || gotCardID != cardID {
+				t.Fatalf("unexpected args: %v %v", userID, gotCardID)
+			}
+			return &models.CardWidgetToken{ID: uuid.New(), CardID: cardID, Token: token, CreatedAt: time.Now()}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cards/"+cardID.String()+"/widget-token", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.CreateWidgetToken(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", rr.Code)
+	}
+	var resp WidgetToken
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.URL != "/w/"+token+".png" {
+		t.Fatalf("unexpected widget url %q", resp.URL)
+	}
+}
+
+func TestCardHandler_CreateWidgetToken_Limit(t *testing.T) {
+	handler := NewCardHandler(&mockCardService{
+		CreateWidgetTokenFunc: func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardWidgetToken, error) {
+			return nil, services.ErrWidgetTokenLimit
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cards/"+uuid.New().String()+"/widget-token", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.CreateWidgetToken(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", rr.Code)
+	}
+}
+
+func TestCardHandler_RevokeWidgetToken(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	tokenID := uuid.New()
+
+	revoke := func(handler *CardHandler, rawID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/cards/"+cardID.String()+"/widget-tokens/"+rawID, nil)
+		req.SetPathValue("tokenId", rawID)
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.RevokeWidgetToken(rr, req)
+		return rr
+	}
+
+	handler := NewCardHandler(&mockCardService{
+		RevokeWidgetTokenFunc: func(ctx context.Context, userID, gotCardID, gotTokenID uuid.UUID) error {
+			if gotTokenID != tokenID {
+				return services.ErrWidgetTokenNotFound
+			}
+			return nil
+		},
+	})
+
+	if rr := revoke(handler, tokenID.String()); rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	assertErrorResponse(t, revoke(handler, uuid.New().String()), http.StatusNotFound, "Widget token not found")
+	assertErrorResponse(t, revoke(handler, "nope"), http.StatusBadRequest, "Invalid widget token ID")
+}
+
+func TestCardHandler_ServeWidgetImage(t *testing.T) {
+	token := strings.Repeat("cd", 32)
+	handler := NewCardHandler(&mockCardService{
+		RenderWidgetByTokenFunc: func(ctx context.Context, gotToken string) ([]byte, error) {
+			if gotToken != token {
+				return nil, services.ErrWidgetTokenNotFound
+			}
+			return []byte("png"), nil
+		},
+	})
+
+	serve := func(raw string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/w/"+raw, nil)
+		req.SetPathValue("token", raw)
+		rr := httptest.NewRecorder()
+		handler.ServeWidgetImage(rr, req)
+		return rr
+	}
+
+	rr := serve(token + ".png")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Cache-Control"); got != "public, max-age=900" {
+		t.Fatalf("expected 15 minute cache, got %q", got)
+	}
+	if got := rr.Header().Get("Content-Type"); got != "image/png" {
+		t.Fatalf("expected png content type, got %q", got)
+	}
+
+	if rr := serve(strings.Repeat("ef", 32) + ".png"); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for unknown token, got %d", rr.Code)
+	}
+	if rr := serve("short.png"); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for malformed token, got %d", rr.Code)
+	}
+}
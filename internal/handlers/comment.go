@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type CommentHandler struct {
+	commentService services.CommentServiceInterface
+}
+
+func NewCommentHandler(commentService services.CommentServiceInterface) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+type AddCommentRequest struct {
+	Content string `json:"content"`
+}
+
+type CommentResponse struct {
+	Comment *models.ItemComment `json:"comment"`
+}
+
+type ListCommentsResponse struct {
+	Comments []models.ItemComment `json:"comments"`
+}
+
+func (h *CommentHandler) Add(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	itemID, err := parseItemID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	var req AddCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	comment, err := h.commentService.AddComment(r.Context(), user.ID, itemID, req.Content)
+	if errors.Is(err, services.ErrCommentEmpty) {
+		writeError(w, http.StatusBadRequest, "Comment cannot be empty")
+		return
+	}
+	if errors.Is(err, services.ErrCommentTooLong) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Comment must be %d characters or less", models.MaxItemCommentLength))
+		return
+	}
+	if writeCommentAccessError(w, err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Error adding comment: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, CommentResponse{Comment: comment})
+}
+
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	itemID, err := parseItemID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	comments, err := h.commentService.ListComments(r.Context(), user.ID, itemID)
+	if writeCommentAccessError(w, err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Error listing comments: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ListCommentsResponse{Comments: comments})
+}
+
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	commentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	err = h.commentService.DeleteComment(r.Context(), user.ID, commentID)
+	if errors.Is(err, services.ErrCommentNotFound) {
+		writeError(w, http.StatusNotFound, "Comment not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error deleting comment: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeCommentAccessError answers the errors for a goal the user may not
+// comment on, with the same statuses as reactions.
+func writeCommentAccessError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrItemNotFound):
+		writeError(w, http.StatusNotFound, "Item not found")
+	case errors.Is(err, services.ErrItemPrivate):
+		writeError(w, http.StatusForbidden, "Cannot comment on private items")
+	case errors.Is(err, services.ErrNotFriend):
+		writeError(w, http.StatusForbidden, "You must be friends to comment")
+	default:
+		return false
+	}
+	return true
+}
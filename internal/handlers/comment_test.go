@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestCommentHandler_Add(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	itemID := uuid.New()
+	cases := map[string]struct {
+		body       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		"bad body":   {`nope`, nil, http.StatusBadRequest, "Invalid request body"},
+		"empty":      {`{"content":" "}`, services.ErrCommentEmpty, http.StatusBadRequest, "Comment cannot be empty"},
+		"too long":   {`{"content":"x"}`, services.ErrCommentTooLong, http.StatusBadRequest, "Comment must be 500 characters or less"},
+		"not friend": {`{"content":"hi"}`, services.ErrNotFriend, http.StatusForbidden, "You must be friends to comment"},
+		"private":    {`{"content":"hi"}`, services.ErrItemPrivate, http.StatusForbidden, "Cannot comment on private items"},
+		"missing":    {`{"content":"hi"}`, services.ErrItemNotFound, http.StatusNotFound, "Item not found"},
+		"created":    {`{"content":"so proud of you!"}`, nil, http.StatusCreated, ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gotContent string
+			handler := NewCommentHandler(&mockCommentService{
+				AddCommentFunc: func(ctx context.Context, userID, gotItemID uuid.UUID, content string) (*models.ItemComment, error) {
+					if gotItemID != itemID {
+						t.Fatalf("expected item %v, got %v", itemID, gotItemID)
+					}
+					gotContent = content
+					if tc.err != nil {
+						return nil, tc.err
+					}
+					return &models.ItemComment{ID: uuid.New(), ItemID: gotItemID, UserID: userID, Content: content}, nil
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/items/"+itemID.String()+"/comments", strings.NewReader(tc.body))
+			req.SetPathValue("id", itemID.String())
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+			handler.Add(rr, req)
+
+			if tc.wantMsg != "" {
+				assertErrorResponse(t, rr, tc.wantStatus, tc.wantMsg)
+				return
+			}
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("expected %d, got %d", tc.wantStatus, rr.Code)
+			}
+			var resp CommentResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotContent != "so proud of you!" || resp.Comment == nil || resp.Comment.Content != gotContent {
+				t.Fatalf("unexpected response %+v", resp.Comment)
+			}
+		})
+	}
+}
+
+func TestCommentHandler_List(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	itemID := uuid.New()
+	handler := NewCommentHandler(&mockCommentService{
+		ListCommentsFunc: func(ctx context.Context, userID, gotItemID uuid.UUID) ([]models.ItemComment, error) {
+			if gotItemID != itemID {
+				return nil, services.ErrItemNotFound
+			}
+			return []models.ItemComment{{ID: uuid.New(), ItemID: itemID, Content: "nice"}}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/items/"+itemID.String()+"/comments", nil)
+	req.SetPathValue("id", itemID.String())
+	rr := httptest.NewRecorder()
+	handler.List(rr, req)
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/items/bad/comments", nil)
+	req.SetPathValue("id", "bad")
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr = httptest.NewRecorder()
+	handler.List(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid item ID")
+
+	other := uuid.New().String()
+	req = httptest.NewRequest(http.MethodGet, "/api/items/"+other+"/comments", nil)
+	req.SetPathValue("id", other)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr = httptest.NewRecorder()
+	handler.List(rr, req)
+	assertErrorResponse(t, rr, http.StatusNotFound, "Item not found")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/items/"+itemID.String()+"/comments", nil)
+	req.SetPathValue("id", itemID.String())
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr = httptest.NewRecorder()
+	handler.List(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp ListCommentsResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil || len(resp.Comments) != 1 {
+		t.Fatalf("expected one comment, got %s (%v)", rr.Body.String(), err)
+	}
+}
+
+func TestCommentHandler_Delete(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	commentID := uuid.New()
+	handler := NewCommentHandler(&mockCommentService{
+		DeleteCommentFunc: func(ctx context.Context, userID, gotCommentID uuid.UUID) error {
+			if gotCommentID != commentID {
+				return services.ErrCommentNotFound
+			}
+			return nil
+		},
+	})
+
+	cases := map[string]struct {
+		id         string
+		wantStatus int
+		wantMsg    string
+	}{
+		"bad id":  {"nope", http.StatusBadRequest, "Invalid comment ID"},
+		"missing": {uuid.New().String(), http.StatusNotFound, "Comment not found"},
+		"deleted": {commentID.String(), http.StatusOK, ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/api/comments/"+tc.id, nil)
+			req.SetPathValue("id", tc.id)
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+			handler.Delete(rr, req)
+
+			if tc.wantMsg != "" {
+				assertErrorResponse(t, rr, tc.wantStatus, tc.wantMsg)
+				return
+			}
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("expected %d, got %d", tc.wantStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package handlers
 
 import (
 	"context"
+	"net/http"
+
+	"github.com/google/uuid"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
@@ -11,8 +14,29 @@ type contextKey string
 const (
 	userContextKey       contextKey = "user"
 	tokenScopeContextKey contextKey = "token_scope"
+	authInfoContextKey   contextKey = "auth_info"
+)
+
+type AuthMethod string
+
+const (
+	AuthMethodSession AuthMethod = "session"
+	AuthMethodToken   AuthMethod = "token"
 )
 
+// AuthInfo describes how a request was authenticated. TokenID and Scope are
+// set only for API token callers.
+type AuthInfo struct {
+	User    *models.User
+	Method  AuthMethod
+	TokenID uuid.UUID
+	Scope   models.ApiTokenScope
+}
+
+func (a *AuthInfo) IsToken() bool {
+	return a != nil && a.Method == AuthMethodToken
+}
+
 func SetUserInContext(ctx context.Context, user *models.User) context.Context {
 	return context.WithValue(ctx, userContextKey, user)
 }
@@ -30,3 +54,41 @@ func GetTokenScopeFromContext(ctx context.Context) models.ApiTokenScope {
 	scope, _ := ctx.Value(tokenScopeContextKey).(models.ApiTokenScope)
 	return scope
 }
+
+// SetAuthInfoInContext stores info along with the user and token scope, so
+// GetUserFromContext and GetTokenScopeFromContext keep working.
+func SetAuthInfoInContext(ctx context.Context, info *AuthInfo) context.Context {
+	ctx = context.WithValue(ctx, authInfoContextKey, info)
+	ctx = SetUserInContext(ctx, info.User)
+	if info.Method == AuthMethodToken {
+		ctx = SetTokenScopeInContext(ctx, info.Scope)
+	}
+	return ctx
+}
+
+// GetAuthInfoFromContext returns how the request was authenticated, or nil
+// for anonymous requests. A context holding only a user is treated as session
+// auth, or token auth when a token scope is set.
+func GetAuthInfoFromContext(ctx context.Context) *AuthInfo {
+	if info, ok := ctx.Value(authInfoContextKey).(*AuthInfo); ok {
+		return info
+	}
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		return nil
+	}
+	if scope := GetTokenScopeFromContext(ctx); scope != "" {
+		return &AuthInfo{User: user, Method: AuthMethodToken, Scope: scope}
+	}
+	return &AuthInfo{User: user, Method: AuthMethodSession}
+}
+
+// rejectTokenAuth answers 403 for API token callers of session-only
+// endpoints, with the same message as the RequireSession middleware.
+func rejectTokenAuth(w http.ResponseWriter, r *http.Request) bool {
+	if !GetAuthInfoFromContext(r.Context()).IsToken() {
+		return false
+	}
+	writeError(w, http.StatusForbidden, "Token authentication not allowed for this endpoint")
+	return true
+}
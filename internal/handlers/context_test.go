@@ -107,3 +107,29 @@ func TestTokenScopeContext(t *testing.T) {
 		t.Fatalf("expected scope %q, got %q", models.ScopeReadWrite, got)
 	}
 }
+
+func TestGetAuthInfoFromContext(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	tokenID := uuid.New()
+
+	if info := GetAuthInfoFromContext(context.Background()); info != nil {
+		t.Fatalf("expected no auth info for anonymous requests, got %+v", info)
+	}
+
+	ctx := SetAuthInfoInContext(context.Background(), &AuthInfo{User: user, Method: AuthMethodToken, TokenID: tokenID, Scope: models.ScopeWrite})
+	info := GetAuthInfoFromContext(ctx)
+	if !info.IsToken() || info.TokenID != tokenID || info.User != user {
+		t.Fatalf("unexpected auth info %+v", info)
+	}
+	if GetUserFromContext(ctx) != user || GetTokenScopeFromContext(ctx) != models.ScopeWrite {
+		t.Fatal("expected user and scope to stay readable on their own")
+	}
+
+	if info := GetAuthInfoFromContext(SetUserInContext(context.Background(), user)); info.IsToken() || info.Method != AuthMethodSession {
+		t.Fatalf("expected a bare user to read as session auth, got %+v", info)
+	}
+	ctx = SetTokenScopeInContext(SetUserInContext(context.Background(), user), models.ScopeRead)
+	if info := GetAuthInfoFromContext(ctx); !info.IsToken() || info.Scope != models.ScopeRead {
+		t.Fatalf("expected a token scope to read as token auth, got %+v", info)
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"errors"
+	"html"
+	"log"
+	"net/http"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// emailPreferencesTokenLength is the hex length of a preferences token (24
+// random bytes).
+const emailPreferencesTokenLength = 48
+
+// emailPreferenceFields maps the page's checkbox names to the preferences
+// they toggle, in display order.
+var emailPreferenceFields = []struct {
+	name  string
+	label string
+	field func(*models.EmailPreferences) *bool
+}{
+	{"reminder_emails", "Card check-ins and goal reminders", func(p *models.EmailPreferences) *bool { return &p.ReminderEmails }},
+	{"notification_emails", "Friend activity emails", func(p *models.EmailPreferences) *bool { return &p.NotificationEmails }},
+	{"friend_request_received", "New friend requests", func(p *models.EmailPreferences) *bool { return &p.FriendRequestReceived }},
+	{"friend_request_accepted", "Accepted friend requests", func(p *models.EmailPreferences) *bool { return &p.FriendRequestAccepted }},
+	{"friend_bingo", "Friends getting a bingo", func(p *models.EmailPreferences) *bool { return &p.FriendBingo }},
+	{"friend_new_card", "Friends starting a new card", func(p *models.EmailPreferences) *bool { return &p.FriendNewCard }},
+	{"friends_digest", "Weekly friends activity digest", func(p *models.EmailPreferences) *bool { return &p.FriendsDigest }},
+}
+
+func isValidPreferencesToken(token string) bool {
+	if len(token) != emailPreferencesTokenLength {
+		return false
+	}
+	_, err := hex.DecodeString(token)
+	return err == nil
+}
+
+// PreferencesPage shows the email toggles for the user an emailed preferences
+// link was issued to. The link works without signing in and can be reused
+// until it expires.
+func (h *ReminderPublicHandler) PreferencesPage(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "Missing token")
+		return
+	}
+	if !isValidPreferencesToken(token) {
+		writeError(w, http.StatusNotFound, "Preferences link not found")
+		return
+	}
+
+	prefs, err := h.reminderService.EmailPreferencesByToken(r.Context(), token)
+	if !h.handlePreferencesError(w, err) {
+		return
+	}
+	h.writePreferencesPage(w, token, prefs, "")
+}
+
+// PreferencesSubmit saves the page's toggles. Unchecked boxes are not sent
+// with the form, so a missing field turns that email off.
+func (h *ReminderPublicHandler) PreferencesSubmit(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid form")
+		return
+	}
+	token := r.Form.Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "Missing token")
+		return
+	}
+	if !isValidPreferencesToken(token) {
+		writeError(w, http.StatusNotFound, "Preferences link not found")
+		return
+	}
+
+	var prefs models.EmailPreferences
+	for _, f := range emailPreferenceFields {
+		*f.field(&prefs) = r.Form.Get(f.name) == "on"
+	}
+
+	saved, err := h.reminderService.UpdateEmailPreferencesByToken(r.Context(), token, prefs)
+	if errors.Is(err, services.ErrEmailNotVerified) {
+		writeError(w, http.StatusBadRequest, "Verify your email address before turning emails on")
+		return
+	}
+	if !h.handlePreferencesError(w, err) {
+		return
+	}
+	h.writePreferencesPage(w, token, saved, "Preferences saved")
+}
+
+// handlePreferencesError writes the response for a failed token lookup and
+// reports whether the request can continue.
+func (h *ReminderPublicHandler) handlePreferencesError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrReminderNotFound):
+		writeError(w, http.StatusNotFound, "Preferences link not found")
+	case errors.Is(err, services.ErrEmailPreferencesExpired):
+		writeError(w, http.StatusGone, "Preferences link expired")
+	default:
+		log.Printf("Error loading email preferences: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+	}
+	return false
+}
+
+func (h *ReminderPublicHandler) writePreferencesPage(w http.ResponseWriter, token string, prefs *models.EmailPreferences, status string) {
+	checkboxes := ""
+	for _, f := range emailPreferenceFields {
+		checked := ""
+		if *f.field(prefs) {
+			checked = " checked"
+		}
+		checkboxes += `
+        <label class="checkbox-label"><input type="checkbox" name="` + f.name + `"` + checked + `> ` + html.EscapeString(f.label) + `</label>`
+	}
+	statusLine := ""
+	if status != "" {
+		statusLine = `
+      <p role="status">` + html.EscapeString(status) + `</p>`
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>Email preferences - ` + html.EscapeString(h.brand.DisplayName()) + `</title>
+  <link rel="stylesheet" href="/static/css/styles.css">
+</head>
+<body>
+  <main class="container main-content">
+    <div class="card">
+      <h2>Email preferences</h2>` + statusLine + `
+      <p>Choose which emails you get. The friend emails below only send while friend activity emails are on.</p>
+      <form method="POST" action="/r/preferences">
+        <input type="hidden" name="token" value="` + html.EscapeString(token) + `">` + checkboxes + `
+        <div class="profile-actions">
+          <button type="submit" class="btn btn-primary">Save preferences</button>
+          <a class="btn btn-ghost" href="/profile">All settings</a>
+        </div>
+      </form>
+    </div>
+  </main>
+</body>
+</html>`))
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+const testPreferencesToken = "0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func submitPreferences(handler *ReminderPublicHandler, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/r/preferences", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	handler.PreferencesSubmit(rr, req)
+	return rr
+}
+
+func TestReminderPublicHandler_PreferencesPage_RendersCurrentToggles(t *testing.T) {
+	handler := NewReminderPublicHandler(&mockReminderService{
+		EmailPreferencesFunc: func(ctx context.Context, token string) (*models.EmailPreferences, error) {
+			return &models.EmailPreferences{ReminderEmails: true, FriendsDigest: true}, nil
+		},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/r/preferences?token="+testPreferencesToken, nil)
+	rr := httptest.NewRecorder()
+
+	handler.PreferencesPage(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if cache := rr.Result().Header.Get("Cache-Control"); cache != "no-store" {
+		t.Fatalf("expected Cache-Control no-store, got %q", cache)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{`name="reminder_emails" checked`, `name="friends_digest" checked`, `name="friend_bingo">`, `value="` + testPreferencesToken + `"`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected page to contain %q, got %s", want, body)
+		}
+	}
+}
+
+func TestReminderPublicHandler_PreferencesPage_MissingToken(t *testing.T) {
+	handler := NewReminderPublicHandler(&mockReminderService{})
+	req := httptest.NewRequest(http.MethodGet, "/r/preferences", nil)
+	rr := httptest.NewRecorder()
+
+	handler.PreferencesPage(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Missing token")
+}
+
+func TestReminderPublicHandler_Preferences_ExpiredToken(t *testing.T) {
+	handler := NewReminderPublicHandler(&mockReminderService{
+		EmailPreferencesFunc: func(ctx context.Context, token string) (*models.EmailPreferences, error) {
+			return nil, services.ErrEmailPreferencesExpired
+		},
+		UpdateEmailPrefsFunc: func(ctx context.Context, token string, prefs models.EmailPreferences) (*models.EmailPreferences, error) {
+			return nil, services.ErrEmailPreferencesExpired
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/r/preferences?token="+testPreferencesToken, nil)
+	rr := httptest.NewRecorder()
+	handler.PreferencesPage(rr, req)
+	assertErrorResponse(t, rr, http.StatusGone, "Preferences link expired")
+
+	rr = submitPreferences(handler, url.Values{"token": {testPreferencesToken}})
+	assertErrorResponse(t, rr, http.StatusGone, "Preferences link expired")
+}
+
+func TestReminderPublicHandler_PreferencesSubmit_TokenIsReusable(t *testing.T) {
+	var saved []models.EmailPreferences
+	handler := NewReminderPublicHandler(&mockReminderService{
+		UpdateEmailPrefsFunc: func(ctx context.Context, token string, prefs models.EmailPreferences) (*models.EmailPreferences, error) {
+			if token != testPreferencesToken {
+				t.Fatalf("expected token %q, got %q", testPreferencesToken, token)
+			}
+			saved = append(saved, prefs)
+			return &prefs, nil
+		},
+	})
+
+	rr := submitPreferences(handler, url.Values{"token": {testPreferencesToken}, "reminder_emails": {"on"}, "friend_bingo": {"on"}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on first use, got %d", rr.Code)
+	}
+	rr = submitPreferences(handler, url.Values{"token": {testPreferencesToken}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on reuse, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "Preferences saved") {
+		t.Fatalf("expected saved confirmation, got %s", rr.Body.String())
+	}
+
+	if len(saved) != 2 {
+		t.Fatalf("expected 2 saves, got %d", len(saved))
+	}
+	if want := (models.EmailPreferences{ReminderEmails: true, FriendBingo: true}); saved[0] != want {
+		t.Fatalf("expected first save %+v, got %+v", want, saved[0])
+	}
+	if saved[1] != (models.EmailPreferences{}) {
+		t.Fatalf("expected unchecked boxes to turn everything off, got %+v", saved[1])
+	}
+}
+
+func TestReminderPublicHandler_Preferences_TamperedToken(t *testing.T) {
+	t.Run("malformed", func(t *testing.T) {
+		handler := NewReminderPublicHandler(&mockReminderService{
+			EmailPreferencesFunc: func(ctx context.Context, token string) (*models.EmailPreferences, error) {
+				t.Fatal("service should not be called for a malformed token")
+				return nil, nil
+			},
+			UpdateEmailPrefsFunc: func(ctx context.Context, token string, prefs models.EmailPreferences) (*models.EmailPreferences, error) {
+				t.Fatal("service should not be called for a malformed token")
+				return nil, nil
+			},
+		})
+		tampered := `"><script>` + testPreferencesToken[10:]
+		req := httptest.NewRequest(http.MethodGet, "/r/preferences?token="+url.QueryEscape(tampered), nil)
+		rr := httptest.NewRecorder()
+		handler.PreferencesPage(rr, req)
+		assertErrorResponse(t, rr, http.StatusNotFound, "Preferences link not found")
+
+		rr = submitPreferences(handler, url.Values{"token": {tampered}, "reminder_emails": {"on"}})
+		assertErrorResponse(t, rr, http.StatusNotFound, "Preferences link not found")
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		handler := NewReminderPublicHandler(&mockReminderService{
+			EmailPreferencesFunc: func(ctx context.Context, token string) (*models.EmailPreferences, error) {
+				return nil, services.ErrReminderNotFound
+			},
+			UpdateEmailPrefsFunc: func(ctx context.Context, token string, prefs models.EmailPreferences) (*models.EmailPreferences, error) {
+				return nil, services.ErrReminderNotFound
+			},
+		})
+		flipped := "f" + testPreferencesToken[1:]
+		req := httptest.NewRequest(http.MethodGet, "/r/preferences?token="+flipped, nil)
+		rr := httptest.NewRecorder()
+		handler.PreferencesPage(rr, req)
+		assertErrorResponse(t, rr, http.StatusNotFound, "Preferences link not found")
+
+		rr = submitPreferences(handler, url.Values{"token": {flipped}})
+		assertErrorResponse(t, rr, http.StatusNotFound, "Preferences link not found")
+	})
+}
+
+func TestReminderPublicHandler_PreferencesSubmit_RequiresVerifiedEmail(t *testing.T) {
+	handler := NewReminderPublicHandler(&mockReminderService{
+		UpdateEmailPrefsFunc: func(ctx context.Context, token string, prefs models.EmailPreferences) (*models.EmailPreferences, error) {
+			return nil, services.ErrEmailNotVerified
+		},
+	})
+
+	rr := submitPreferences(handler, url.Values{"token": {testPreferencesToken}, "reminder_emails": {"on"}})
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Verify your email address before turning emails on")
+}
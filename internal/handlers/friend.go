@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
@@ -30,10 +31,11 @@ type SendRequestRequest struct {
 }
 
 type FriendListResponse struct {
-	Friends  []models.FriendWithUser `json:"friends,omitempty"`
-	Requests []models.FriendRequest  `json:"requests,omitempty"`
-	Sent     []models.FriendWithUser `json:"sent,omitempty"`
-	Message  string                  `json:"message,omitempty"`
+	Friends    []models.FriendWithUser `json:"friends,omitempty"`
+	Requests   []models.FriendRequest  `json:"requests,omitempty"`
+	Sent       []models.FriendWithUser `json:"sent,omitempty"`
+	Friendship *models.Friendship      `json:"friendship,omitempty"`
+	Message    string                  `json:"message,omitempty"`
 }
 
 type UserSearchResponse struct {
@@ -97,7 +99,7 @@ func (h *FriendHandler) SendRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = h.friendService.SendRequest(r.Context(), user.ID, friendID)
+	friendship, created, err := h.friendService.SendRequest(r.Context(), user.ID, friendID)
 	if errors.Is(err, services.ErrCannotFriendSelf) {
 		writeError(w, http.StatusBadRequest, "Cannot send friend request to yourself")
 		return
@@ -106,17 +108,31 @@ func (h *FriendHandler) SendRequest(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusForbidden, "Cannot send friend request")
 		return
 	}
-	if errors.Is(err, services.ErrFriendshipExists) {
-		writeError(w, http.StatusConflict, "Friend request already exists")
-		return
-	}
 	if err != nil {
 		log.Printf("Error sending friend request: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, FriendListResponse{Message: "Friend request sent"})
+	if !created {
+		writeJSON(w, http.StatusOK, FriendListResponse{Friendship: friendship, Message: existingFriendshipMessage(friendship, user.ID)})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, FriendListResponse{Friendship: friendship, Message: "Friend request sent"})
+}
+
+// existingFriendshipMessage describes a friendship that SendRequest found
+// instead of creating a new request.
+func existingFriendshipMessage(friendship *models.Friendship, userID uuid.UUID) string {
+	switch {
+	case friendship.Status == models.FriendshipStatusAccepted:
+		return "Already friends"
+	case friendship.UserID == userID:
+		return "Friend request already sent"
+	default:
+		return "This user has already sent you a friend request"
+	}
 }
 
 func (h *FriendHandler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
@@ -289,27 +305,11 @@ func (h *FriendHandler) GetFriendCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	friendshipID, err := parseFriendshipID(r)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid friendship ID")
-		return
-	}
-
-	// Get the friend's user ID
-	friendUserID, err := h.friendService.GetFriendUserID(r.Context(), user.ID, friendshipID)
-	if errors.Is(err, services.ErrFriendshipNotFound) {
-		writeError(w, http.StatusNotFound, "Friendship not found")
-		return
-	}
-	if errors.Is(err, services.ErrNotFriend) {
-		writeError(w, http.StatusForbidden, "You are not friends with this user")
-		return
-	}
-	if err != nil {
-		log.Printf("Error getting friend user ID: %v", err)
-		writeError(w, http.StatusInternalServerError, "Internal server error")
+	rel, ok := h.friendRelation(w, r, user.ID)
+	if !ok {
 		return
 	}
+	friendUserID := rel.OwnerID
 
 	// Get the friend's cards
 	cards, err := h.cardService.ListByUser(r.Context(), friendUserID)
@@ -322,7 +322,7 @@ func (h *FriendHandler) GetFriendCard(w http.ResponseWriter, r *http.Request) {
 	// Find the active/current year finalized card that is visible to friends
 	var activeCard *models.BingoCard
 	for _, card := range cards {
-		if card.IsFinalized && card.VisibleToFriends {
+		if authz.CanViewCard(rel, cardVisibility(card)) {
 			if activeCard == nil || card.Year > activeCard.Year {
 				activeCard = card
 			}
@@ -354,6 +354,8 @@ func (h *FriendHandler) GetFriendCard(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	activeCard.RedactPrivateItems()
+
 	writeJSON(w, http.StatusOK, FriendCardResponse{
 		Card:  activeCard,
 		Owner: &FriendOwner{Username: ownerName},
@@ -367,27 +369,11 @@ func (h *FriendHandler) GetFriendCards(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	friendshipID, err := parseFriendshipID(r)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid friendship ID")
-		return
-	}
-
-	// Get the friend's user ID
-	friendUserID, err := h.friendService.GetFriendUserID(r.Context(), user.ID, friendshipID)
-	if errors.Is(err, services.ErrFriendshipNotFound) {
-		writeError(w, http.StatusNotFound, "Friendship not found")
-		return
-	}
-	if errors.Is(err, services.ErrNotFriend) {
-		writeError(w, http.StatusForbidden, "You are not friends with this user")
-		return
-	}
-	if err != nil {
-		log.Printf("Error getting friend user ID: %v", err)
-		writeError(w, http.StatusInternalServerError, "Internal server error")
+	rel, ok := h.friendRelation(w, r, user.ID)
+	if !ok {
 		return
 	}
+	friendUserID := rel.OwnerID
 
 	// Get all friend's cards
 	cards, err := h.cardService.ListByUser(r.Context(), friendUserID)
@@ -400,7 +386,7 @@ func (h *FriendHandler) GetFriendCards(w http.ResponseWriter, r *http.Request) {
 	// Filter to only finalized cards that are visible to friends
 	var finalizedCards []*models.BingoCard
 	for _, card := range cards {
-		if card.IsFinalized && card.VisibleToFriends {
+		if authz.CanViewCard(rel, cardVisibility(card)) {
 			finalizedCards = append(finalizedCards, card)
 		}
 	}
@@ -425,12 +411,60 @@ func (h *FriendHandler) GetFriendCards(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	for _, card := range finalizedCards {
+		card.RedactPrivateItems()
+	}
+
 	writeJSON(w, http.StatusOK, FriendCardsResponse{
 		Cards: finalizedCards,
 		Owner: &FriendOwner{Username: ownerName},
 	})
 }
 
+// friendRelation resolves the friendship in the path to the friend's
+// relation to user, writing the error response when the friendship is
+// missing, not accepted, or a block now sits between the two users.
+func (h *FriendHandler) friendRelation(w http.ResponseWriter, r *http.Request, userID uuid.UUID) (authz.Relation, bool) {
+	friendshipID, err := parseFriendshipID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid friendship ID")
+		return authz.Relation{}, false
+	}
+
+	friendUserID, err := h.friendService.GetFriendUserID(r.Context(), userID, friendshipID)
+	if errors.Is(err, services.ErrFriendshipNotFound) {
+		writeError(w, http.StatusNotFound, "Friendship not found")
+		return authz.Relation{}, false
+	}
+	if errors.Is(err, services.ErrNotFriend) {
+		writeError(w, http.StatusForbidden, "You are not friends with this user")
+		return authz.Relation{}, false
+	}
+	if err != nil {
+		log.Printf("Error getting friend user ID: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return authz.Relation{}, false
+	}
+
+	// The friendship row alone isn't enough: a block ends access even if the
+	// row survived it.
+	rel, err := h.friendService.Relation(r.Context(), userID, friendUserID)
+	if err != nil {
+		log.Printf("Error loading friend relation: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return authz.Relation{}, false
+	}
+	if !rel.IsFriend() {
+		writeError(w, http.StatusForbidden, "You are not friends with this user")
+		return authz.Relation{}, false
+	}
+	return rel, true
+}
+
+func cardVisibility(card *models.BingoCard) authz.CardVisibility {
+	return authz.CardVisibility{Finalized: card.IsFinalized, VisibleToFriends: card.VisibleToFriends}
+}
+
 func parseFriendshipID(r *http.Request) (uuid.UUID, error) {
 	if id := r.PathValue("id"); id != "" {
 		return uuid.Parse(id)
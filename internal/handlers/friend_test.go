@@ -3,6 +3,7 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
@@ -43,9 +45,9 @@ func TestFriendHandler_Search_ServiceError(t *testing.T) {
 }
 
 func TestFriendHandler_SendRequest_InvalidBody(t *testing.T) {
-	handler := NewFriendHandler(&mockFriendService{SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
+	handler := NewFriendHandler(&mockFriendService{SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, bool, error) {
 		t.Fatal("SendRequest should not be called for invalid body")
-		return nil, nil
+		return nil, false, nil
 	}}, &mockCardService{})
 
 	req := httptest.NewRequest(http.MethodPost, "/api/friends/requests", bytes.NewBufferString("{"))
@@ -57,11 +59,11 @@ func TestFriendHandler_SendRequest_InvalidBody(t *testing.T) {
 
 func TestFriendHandler_SendRequest_Self(t *testing.T) {
 	friendID := uuid.New()
-	handler := NewFriendHandler(&mockFriendService{SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
+	handler := NewFriendHandler(&mockFriendService{SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, bool, error) {
 		if friendID == userID {
-			return nil, services.ErrCannotFriendSelf
+			return nil, false, services.ErrCannotFriendSelf
 		}
-		return &models.Friendship{}, nil
+		return &models.Friendship{}, true, nil
 	}}, &mockCardService{})
 
 	payload := []byte(`{"friend_id":"` + friendID.String() + `"}`)
@@ -75,8 +77,8 @@ func TestFriendHandler_SendRequest_Self(t *testing.T) {
 
 func TestFriendHandler_SendRequest_Success(t *testing.T) {
 	friendID := uuid.New()
-	handler := NewFriendHandler(&mockFriendService{SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
-		return &models.Friendship{}, nil
+	handler := NewFriendHandler(&mockFriendService{SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, bool, error) {
+		return &models.Friendship{}, true, nil
 	}}, &mockCardService{})
 
 	payload := []byte(`{"friend_id":"` + friendID.String() + `"}`)
@@ -91,9 +93,9 @@ func TestFriendHandler_SendRequest_Success(t *testing.T) {
 }
 
 func TestFriendHandler_SendRequest_InvalidFriendID(t *testing.T) {
-	handler := NewFriendHandler(&mockFriendService{SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
+	handler := NewFriendHandler(&mockFriendService{SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, bool, error) {
 		t.Fatal("SendRequest should not be called for invalid friend ID")
-		return nil, nil
+		return nil, false, nil
 	}}, &mockCardService{})
 	req := httptest.NewRequest(http.MethodPost, "/api/friends/requests", bytes.NewBufferString(`{"friend_id":"not-a-uuid"}`))
 	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
@@ -102,24 +104,67 @@ func TestFriendHandler_SendRequest_InvalidFriendID(t *testing.T) {
 	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid friend ID")
 }
 
-func TestFriendHandler_SendRequest_Conflict(t *testing.T) {
-	handler := NewFriendHandler(&mockFriendService{
-		SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
-			return nil, services.ErrFriendshipExists
+func TestFriendHandler_SendRequest_ExistingFriendship(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+	tests := []struct {
+		name        string
+		friendship  models.Friendship
+		wantMessage string
+	}{
+		{
+			name:        "already friends",
+			friendship:  models.Friendship{UserID: otherID, FriendID: userID, Status: models.FriendshipStatusAccepted},
+			wantMessage: "Already friends",
 		},
-	}, &mockCardService{})
+		{
+			name:        "request already sent",
+			friendship:  models.Friendship{UserID: userID, FriendID: otherID, Status: models.FriendshipStatusPending},
+			wantMessage: "Friend request already sent",
+		},
+		{
+			name:        "request already received",
+			friendship:  models.Friendship{UserID: otherID, FriendID: userID, Status: models.FriendshipStatusPending},
+			wantMessage: "This user has already sent you a friend request",
+		},
+	}
 
-	req := httptest.NewRequest(http.MethodPost, "/api/friends/requests", bytes.NewBufferString(`{"friend_id":"`+uuid.New().String()+`"}`))
-	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
-	rr := httptest.NewRecorder()
-	handler.SendRequest(rr, req)
-	assertErrorResponse(t, rr, http.StatusConflict, "Friend request already exists")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := tt.friendship
+			existing.ID = uuid.New()
+			handler := NewFriendHandler(&mockFriendService{
+				SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, bool, error) {
+					return &existing, false, nil
+				},
+			}, &mockCardService{})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/friends/requests", bytes.NewBufferString(`{"friend_id":"`+otherID.String()+`"}`))
+			req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+			rr := httptest.NewRecorder()
+			handler.SendRequest(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rr.Code)
+			}
+			var resp FriendListResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.Message != tt.wantMessage {
+				t.Fatalf("expected message %q, got %q", tt.wantMessage, resp.Message)
+			}
+			if resp.Friendship == nil || resp.Friendship.ID != existing.ID || resp.Friendship.Status != existing.Status {
+				t.Fatalf("expected existing friendship in response, got %+v", resp.Friendship)
+			}
+		})
+	}
 }
 
 func TestFriendHandler_SendRequest_Blocked(t *testing.T) {
 	handler := NewFriendHandler(&mockFriendService{
-		SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
-			return nil, services.ErrUserBlocked
+		SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, bool, error) {
+			return nil, false, services.ErrUserBlocked
 		},
 	}, &mockCardService{})
 
@@ -132,8 +177,8 @@ func TestFriendHandler_SendRequest_Blocked(t *testing.T) {
 
 func TestFriendHandler_SendRequest_Error(t *testing.T) {
 	handler := NewFriendHandler(&mockFriendService{
-		SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
-			return nil, errors.New("boom")
+		SendRequestFunc: func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, bool, error) {
+			return nil, false, errors.New("boom")
 		},
 	}, &mockCardService{})
 
@@ -407,6 +452,51 @@ func TestFriendHandler_GetFriendCardAndCards(t *testing.T) {
 	}
 }
 
+func TestFriendHandler_GetFriendCard_RedactsPrivateItems(t *testing.T) {
+	currentUser := &models.User{ID: uuid.New()}
+	friendUserID := uuid.New()
+
+	mockFriend := &mockFriendService{
+		GetFriendUserIDFunc: func(ctx context.Context, currentUserID, friendshipID uuid.UUID) (uuid.UUID, error) {
+			return friendUserID, nil
+		},
+	}
+	mockCard := &mockCardService{
+		ListByUserFunc: func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
+			return []*models.BingoCard{{
+				ID: uuid.New(), UserID: friendUserID, Year: 2024, IsFinalized: true, VisibleToFriends: true,
+				Items: []models.BingoItem{
+					{Position: 0, Content: "Run a 5k"},
+					{Position: 1, Content: "See a therapist", IsCompleted: true, IsPrivate: true},
+				},
+			}}, nil
+		},
+	}
+
+	handler := NewFriendHandler(mockFriend, mockCard)
+
+	for _, path := range []string{"/card", "/cards"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/friends/"+uuid.New().String()+path, nil)
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, currentUser))
+		rr := httptest.NewRecorder()
+		if path == "/card" {
+			handler.GetFriendCard(rr, req)
+		} else {
+			handler.GetFriendCards(rr, req)
+		}
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, rr.Code)
+		}
+		body := rr.Body.String()
+		if strings.Contains(body, "See a therapist") {
+			t.Fatalf("%s: private content leaked: %s", path, body)
+		}
+		if !strings.Contains(body, models.PrivateItemPlaceholder) || !strings.Contains(body, "Run a 5k") {
+			t.Fatalf("%s: expected placeholder and public content, got %s", path, body)
+		}
+	}
+}
+
 func TestFriendHandler_GetFriendCards_FriendshipNotFound(t *testing.T) {
 	friendshipID := uuid.New()
 	handler := NewFriendHandler(&mockFriendService{
@@ -443,6 +533,35 @@ func TestFriendHandler_GetFriendCards_NotFriend(t *testing.T) {
 	}
 }
 
+func TestFriendHandler_GetFriendCard_BlockedFormerFriend(t *testing.T) {
+	friendUserID := uuid.New()
+	handler := NewFriendHandler(&mockFriendService{
+		GetFriendUserIDFunc: func(ctx context.Context, userID, friendshipID uuid.UUID) (uuid.UUID, error) {
+			return friendUserID, nil
+		},
+		RelationFunc: func(ctx context.Context, viewerID, ownerID uuid.UUID) (authz.Relation, error) {
+			return authz.Relation{ViewerID: viewerID, OwnerID: ownerID, Friends: true, Blocked: true}, nil
+		},
+	}, &mockCardService{
+		ListByUserFunc: func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
+			t.Fatal("expected cards not to be loaded")
+			return nil, nil
+		},
+	})
+
+	for _, path := range []string{"/card", "/cards"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/friends/"+uuid.New().String()+path, nil)
+		req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+		rr := httptest.NewRecorder()
+		if path == "/card" {
+			handler.GetFriendCard(rr, req)
+		} else {
+			handler.GetFriendCards(rr, req)
+		}
+		assertErrorResponse(t, rr, http.StatusForbidden, "You are not friends with this user")
+	}
+}
+
 func TestFriendHandler_GetFriendCards_ListCardsError(t *testing.T) {
 	friendshipID := uuid.New()
 	friendID := uuid.New()
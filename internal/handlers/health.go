@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
 type HealthChecker interface {
@@ -12,17 +16,54 @@ type HealthChecker interface {
 }
 
 type HealthHandler struct {
-	db    HealthChecker
-	redis HealthChecker
+	db     HealthChecker
+	dbName string
+	redis  HealthChecker
+	jobs   services.JobRegistryInterface
+	caches []services.CacheStatsInterface
+	email  services.EmailSendLimiterStatsInterface
 }
 
 func NewHealthHandler(db, redis HealthChecker) *HealthHandler {
 	return &HealthHandler{
-		db:    db,
-		redis: redis,
+		db:     db,
+		dbName: "postgres",
+		redis:  redis,
 	}
 }
 
+// SetDatabaseName sets the key the database check is reported under.
+func (h *HealthHandler) SetDatabaseName(name string) {
+	h.dbName = name
+}
+
+// SetJobRegistry includes background job status in verbose /ready output.
+func (h *HealthHandler) SetJobRegistry(jobs services.JobRegistryInterface) {
+	h.jobs = jobs
+}
+
+// SetCacheStats includes cache hit and miss counters in verbose /ready output.
+func (h *HealthHandler) SetCacheStats(caches ...services.CacheStatsInterface) {
+	h.caches = caches
+}
+
+// SetEmailLimiter includes the email send limiter's saturation in verbose
+// /ready output.
+func (h *HealthHandler) SetEmailLimiter(email services.EmailSendLimiterStatsInterface) {
+	h.email = email
+}
+
+// ReadyResponse is returned by /ready?verbose=1. Job errors are omitted since
+// the endpoint is public; operators can read them from /api/admin/jobs.
+type ReadyResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]string            `json:"checks"`
+	Jobs   []models.JobStatus           `json:"jobs,omitempty"`
+	Caches map[string]models.CacheStats `json:"caches,omitempty"`
+	// EmailSendLimiter is omitted when sends are not rate limited.
+	EmailSendLimiter *models.EmailSendLimiterStats `json:"email_send_limiter,omitempty"`
+}
+
 type HealthResponse struct {
 	Status    string            `json:"status"`
 	Checks    map[string]string `json:"checks"`
@@ -39,12 +80,12 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 	}
 
-	// Check PostgreSQL
+	// Check the database
 	if err := h.db.Health(ctx); err != nil {
 		response.Status = "unhealthy"
-		response.Checks["postgres"] = "unhealthy: " + err.Error()
+		response.Checks[h.dbName] = "unhealthy: " + err.Error()
 	} else {
-		response.Checks["postgres"] = "healthy"
+		response.Checks[h.dbName] = "healthy"
 	}
 
 	// Check Redis
@@ -74,6 +115,11 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	dbErr := h.db.Health(ctx)
 	redisErr := h.redis.Health(ctx)
 
+	if verbose, _ := strconv.ParseBool(r.URL.Query().Get("verbose")); verbose {
+		h.writeVerboseReady(w, dbErr, redisErr)
+		return
+	}
+
 	if dbErr != nil || redisErr != nil {
 		w.WriteHeader(http.StatusServiceUnavailable)
 		_, _ = w.Write([]byte("not ready"))
@@ -84,6 +130,46 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte("ready"))
 }
 
+func (h *HealthHandler) writeVerboseReady(w http.ResponseWriter, dbErr, redisErr error) {
+	response := ReadyResponse{
+		Status: "ready",
+		Checks: map[string]string{h.dbName: "ready", "redis": "ready"},
+	}
+	if dbErr != nil {
+		response.Status = "not ready"
+		response.Checks[h.dbName] = "not ready"
+	}
+	if redisErr != nil {
+		response.Status = "not ready"
+		response.Checks["redis"] = "not ready"
+	}
+	if h.jobs != nil {
+		response.Jobs = h.jobs.Statuses()
+		for i := range response.Jobs {
+			response.Jobs[i].LastError = nil
+		}
+	}
+	for _, cache := range h.caches {
+		if response.Caches == nil {
+			response.Caches = make(map[string]models.CacheStats)
+		}
+		for name, stats := range cache.Stats() {
+			response.Caches[name] = stats
+		}
+	}
+	if h.email != nil {
+		if stats, ok := h.email.SendLimiterStats(); ok {
+			response.EmailSendLimiter = &stats
+		}
+	}
+
+	status := http.StatusOK
+	if response.Status != "ready" {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, response)
+}
+
 func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("alive"))
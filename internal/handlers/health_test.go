@@ -6,7 +6,11 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
 // Mock health checker for testing
@@ -52,6 +56,25 @@ func TestHealthHandler_Health_AllHealthy(t *testing.T) {
 	}
 }
 
+func TestHealthHandler_Health_DatabaseName(t *testing.T) {
+	handler := NewHealthHandler(&mockHealthChecker{healthy: true}, &mockHealthChecker{healthy: true})
+	handler.SetDatabaseName("sqlite")
+
+	rr := httptest.NewRecorder()
+	handler.Health(rr, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var response HealthResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Checks["sqlite"] != "healthy" {
+		t.Errorf("expected sqlite 'healthy', got %+v", response.Checks)
+	}
+	if _, ok := response.Checks["postgres"]; ok {
+		t.Errorf("expected no postgres check, got %+v", response.Checks)
+	}
+}
+
 func TestHealthHandler_Health_DBUnhealthy(t *testing.T) {
 	db := &mockHealthChecker{healthy: false, err: errors.New("connection refused")}
 	redis := &mockHealthChecker{healthy: true}
@@ -202,6 +225,108 @@ func TestHealthHandler_Ready_RedisUnhealthy(t *testing.T) {
 	}
 }
 
+func TestHealthHandler_Ready_VerboseIncludesJobsWithoutErrors(t *testing.T) {
+	lastRun := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	errMsg := "connection refused to 10.0.0.5"
+	handler := NewHealthHandler(&mockHealthChecker{healthy: true}, &mockHealthChecker{healthy: true})
+	handler.SetJobRegistry(&mockJobRegistry{
+		StatusesFunc: func() []models.JobStatus {
+			return []models.JobStatus{{Name: "reminder_runner", LastRunAt: &lastRun, LastError: &errMsg, LastRunFailed: true}}
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ready?verbose=1", nil)
+	rr := httptest.NewRecorder()
+
+	handler.Ready(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), errMsg) {
+		t.Fatalf("expected job error to be omitted, got %s", rr.Body.String())
+	}
+	var resp ReadyResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Status != "ready" || resp.Checks["postgres"] != "ready" || resp.Checks["redis"] != "ready" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if len(resp.Jobs) != 1 || resp.Jobs[0].Name != "reminder_runner" || !resp.Jobs[0].LastRunFailed {
+		t.Fatalf("unexpected jobs: %+v", resp.Jobs)
+	}
+}
+
+func TestHealthHandler_Ready_VerboseIncludesCacheStats(t *testing.T) {
+	handler := NewHealthHandler(&mockHealthChecker{healthy: true}, &mockHealthChecker{healthy: true})
+	handler.SetCacheStats(&mockCacheStats{
+		StatsFunc: func() map[string]models.CacheStats {
+			return map[string]models.CacheStats{"shared_card": {Hits: 7, Misses: 2}}
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ready?verbose=1", nil)
+	rr := httptest.NewRecorder()
+
+	handler.Ready(rr, req)
+
+	var resp ReadyResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got := resp.Caches["shared_card"]; got.Hits != 7 || got.Misses != 2 {
+		t.Fatalf("unexpected caches: %+v", resp.Caches)
+	}
+}
+
+func TestHealthHandler_Ready_VerboseIncludesEmailLimiter(t *testing.T) {
+	stats := models.EmailSendLimiterStats{RatePerSecond: 5, Burst: 5, Available: 1.5, Saturation: 0.7, Waiting: 2, RateLimited: 3}
+	for _, enabled := range []bool{true, false} {
+		handler := NewHealthHandler(&mockHealthChecker{healthy: true}, &mockHealthChecker{healthy: true})
+		handler.SetEmailLimiter(&mockEmailLimiterStats{stats: stats, enabled: enabled})
+
+		req := httptest.NewRequest(http.MethodGet, "/ready?verbose=1", nil)
+		rr := httptest.NewRecorder()
+
+		handler.Ready(rr, req)
+
+		var resp ReadyResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if !enabled {
+			if resp.EmailSendLimiter != nil {
+				t.Fatalf("expected no limiter stats when disabled, got %+v", resp.EmailSendLimiter)
+			}
+			continue
+		}
+		if resp.EmailSendLimiter == nil || *resp.EmailSendLimiter != stats {
+			t.Fatalf("unexpected limiter stats: %+v", resp.EmailSendLimiter)
+		}
+	}
+}
+
+func TestHealthHandler_Ready_VerboseNotReady(t *testing.T) {
+	handler := NewHealthHandler(&mockHealthChecker{healthy: false, err: errors.New("down")}, &mockHealthChecker{healthy: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/ready?verbose=true", nil)
+	rr := httptest.NewRecorder()
+
+	handler.Ready(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", rr.Code)
+	}
+	var resp ReadyResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Status != "not ready" || resp.Checks["postgres"] != "not ready" || resp.Jobs != nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
 func TestHealthHandler_Live(t *testing.T) {
 	// Live check doesn't depend on services
 	handler := NewHealthHandler(nil, nil)
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// svgContentSecurityPolicy keeps a card SVG opened directly in the browser
+// from running anything; it only needs its inline presentation attributes.
+const svgContentSecurityPolicy = "default-src 'none'; style-src 'unsafe-inline'"
+
+// acceptsSVGOverPNG reports whether the request's Accept header asks for SVG
+// more strongly than PNG. Browsers list image/svg+xml next to image/* in
+// their image requests, so only a client that ranks SVG above PNG, such as
+// "Accept: image/svg+xml", gets SVG without asking for it by name.
+func acceptsSVGOverPNG(r *http.Request) bool {
+	svgQ, pngQ := 0.0, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, q := parseAcceptRange(part)
+		switch mediaType {
+		case "image/svg+xml":
+			svgQ = max(svgQ, q)
+		case "image/png", "image/*", "*/*":
+			pngQ = max(pngQ, q)
+		}
+	}
+	return svgQ > pngQ
+}
+
+// parseAcceptRange splits one Accept entry into its media range and quality.
+func parseAcceptRange(part string) (string, float64) {
+	params := strings.Split(part, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+	q := 1.0
+	for _, param := range params[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.TrimSpace(key) != "q" {
+			continue
+		}
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && parsed >= 0 && parsed <= 1 {
+			q = parsed
+		}
+	}
+	return mediaType, q
+}
+
+// imageThemeParam returns the card image theme from ?theme=, light when
+// absent. ok is false for an unknown theme.
+func imageThemeParam(r *http.Request) (theme string, ok bool) {
+	theme = r.URL.Query().Get("theme")
+	if theme == "" {
+		return models.ImageThemeLight, true
+	}
+	return theme, models.IsValidImageTheme(theme)
+}
+
+// writeSVG sends a rendered card SVG.
+func writeSVG(w http.ResponseWriter, svg []byte) {
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Content-Security-Policy", svgContentSecurityPolicy)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(svg)
+}
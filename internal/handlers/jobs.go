@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// JobsHandler reports background job status to operators. Routes must be
+// wrapped with the admin gate or the internal token gate.
+type JobsHandler struct {
+	registry services.JobRegistryInterface
+}
+
+func NewJobsHandler(registry services.JobRegistryInterface) *JobsHandler {
+	return &JobsHandler{registry: registry}
+}
+
+type JobsResponse struct {
+	Jobs []models.JobStatus `json:"jobs"`
+}
+
+func (h *JobsHandler) List(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, JobsResponse{Jobs: h.registry.Statuses()})
+}
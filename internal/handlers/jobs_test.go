@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestJobsHandler_List(t *testing.T) {
+	lastRun := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	next := lastRun.Add(time.Minute)
+	errMsg := "boom"
+	handler := NewJobsHandler(&mockJobRegistry{
+		StatusesFunc: func() []models.JobStatus {
+			return []models.JobStatus{{
+				Name:            "reminder_runner",
+				IntervalSeconds: 60,
+				Runs:            3,
+				LastRunAt:       &lastRun,
+				LastDurationMS:  42,
+				LastError:       &errMsg,
+				LastRunFailed:   true,
+				ItemsProcessed:  5,
+				NextRunAt:       &next,
+			}}
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/jobs", nil)
+	rr := httptest.NewRecorder()
+	handler.List(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp JobsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(resp.Jobs))
+	}
+	job := resp.Jobs[0]
+	if job.Name != "reminder_runner" || job.ItemsProcessed != 5 || job.LastError == nil || *job.LastError != "boom" {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	if job.NextRunAt == nil || !job.NextRunAt.Equal(next) {
+		t.Fatalf("expected next_run_at %v, got %v", next, job.NextRunAt)
+	}
+}
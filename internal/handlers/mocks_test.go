@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
@@ -17,6 +18,7 @@ type mockUserService struct {
 	UpdatePasswordFunc    func(ctx context.Context, userID uuid.UUID, newPasswordHash string) error
 	MarkEmailVerifiedFunc func(ctx context.Context, userID uuid.UUID) error
 	UpdateSearchableFunc  func(ctx context.Context, userID uuid.UUID, searchable bool) error
+	SuggestUsernameFunc   func(ctx context.Context, email string) (string, error)
 }
 
 func (m *mockUserService) Create(ctx context.Context, params models.CreateUserParams) (*models.User, error) {
@@ -61,6 +63,13 @@ func (m *mockUserService) UpdateSearchable(ctx context.Context, userID uuid.UUID
 	return nil
 }
 
+func (m *mockUserService) SuggestUsername(ctx context.Context, email string) (string, error) {
+	if m.SuggestUsernameFunc != nil {
+		return m.SuggestUsernameFunc(ctx, email)
+	}
+	return "", nil
+}
+
 type mockAuthService struct {
 	HashPasswordFunc          func(password string) (string, error)
 	VerifyPasswordFunc        func(hash *string, password string) bool
@@ -69,6 +78,9 @@ type mockAuthService struct {
 	ValidateSessionFunc       func(ctx context.Context, token string) (*models.User, error)
 	DeleteSessionFunc         func(ctx context.Context, token string) error
 	DeleteAllUserSessionsFunc func(ctx context.Context, userID uuid.UUID) error
+	ListSessionsFunc          func(ctx context.Context, userID uuid.UUID, currentToken string) ([]models.Session, error)
+	RevokeSessionFunc         func(ctx context.Context, userID, sessionID uuid.UUID, currentToken string) (bool, error)
+	RevokeOtherSessionsFunc   func(ctx context.Context, userID uuid.UUID, currentToken string) (int, error)
 }
 
 func (m *mockAuthService) HashPassword(password string) (string, error) {
@@ -120,6 +132,27 @@ func (m *mockAuthService) DeleteAllUserSessions(ctx context.Context, userID uuid
 	return nil
 }
 
+func (m *mockAuthService) ListSessions(ctx context.Context, userID uuid.UUID, currentToken string) ([]models.Session, error) {
+	if m.ListSessionsFunc != nil {
+		return m.ListSessionsFunc(ctx, userID, currentToken)
+	}
+	return []models.Session{}, nil
+}
+
+func (m *mockAuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID, currentToken string) (bool, error) {
+	if m.RevokeSessionFunc != nil {
+		return m.RevokeSessionFunc(ctx, userID, sessionID, currentToken)
+	}
+	return false, nil
+}
+
+func (m *mockAuthService) RevokeOtherSessions(ctx context.Context, userID uuid.UUID, currentToken string) (int, error) {
+	if m.RevokeOtherSessionsFunc != nil {
+		return m.RevokeOtherSessionsFunc(ctx, userID, currentToken)
+	}
+	return 0, nil
+}
+
 type mockEmailService struct {
 	SendVerificationEmailFunc    func(ctx context.Context, userID uuid.UUID, email string) error
 	VerifyEmailFunc              func(ctx context.Context, token string) error
@@ -130,6 +163,7 @@ type mockEmailService struct {
 	MarkPasswordResetUsedFunc    func(ctx context.Context, token string) error
 	SendNotificationEmailFunc    func(ctx context.Context, toEmail, subject, html, text string) error
 	SendSupportEmailFunc         func(ctx context.Context, fromEmail, category, message string, userID string) error
+	SendDataExportEmailFunc      func(ctx context.Context, email string, exportedAt time.Time) error
 }
 
 func (m *mockEmailService) SendVerificationEmail(ctx context.Context, userID uuid.UUID, email string) error {
@@ -195,35 +229,57 @@ func (m *mockEmailService) SendSupportEmail(ctx context.Context, fromEmail, cate
 	return nil
 }
 
+func (m *mockEmailService) SendDataExportEmail(ctx context.Context, email string, exportedAt time.Time) error {
+	if m.SendDataExportEmailFunc != nil {
+		return m.SendDataExportEmailFunc(ctx, email, exportedAt)
+	}
+	return nil
+}
+
 type mockCardService struct {
-	CheckForConflictFunc     func(ctx context.Context, userID uuid.UUID, year int, title *string) (*models.BingoCard, error)
-	CreateFunc               func(ctx context.Context, params models.CreateCardParams) (*models.BingoCard, error)
-	ListByUserFunc           func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error)
-	GetByIDFunc              func(ctx context.Context, cardID uuid.UUID) (*models.BingoCard, error)
-	DeleteFunc               func(ctx context.Context, userID, cardID uuid.UUID) error
-	AddItemFunc              func(ctx context.Context, userID uuid.UUID, params models.AddItemParams) (*models.BingoItem, error)
-	UpdateConfigFunc         func(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardConfigParams) (*models.BingoCard, error)
-	CloneFunc                func(ctx context.Context, userID, cardID uuid.UUID, params services.CloneParams) (*services.CloneResult, error)
-	UpdateItemFunc           func(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemParams) (*models.BingoItem, error)
-	RemoveItemFunc           func(ctx context.Context, userID, cardID uuid.UUID, position int) error
-	ShuffleFunc              func(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error)
-	SwapItemsFunc            func(ctx context.Context, userID, cardID uuid.UUID, pos1, pos2 int) error
-	FinalizeFunc             func(ctx context.Context, userID, cardID uuid.UUID, params *services.FinalizeParams) (*models.BingoCard, error)
-	CompleteItemFunc         func(ctx context.Context, userID, cardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error)
-	UncompleteItemFunc       func(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error)
-	UpdateItemNotesFunc      func(ctx context.Context, userID, cardID uuid.UUID, position int, notes, proofURL *string) (*models.BingoItem, error)
-	GetArchiveFunc           func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error)
-	GetStatsFunc             func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardStats, error)
-	UpdateMetaFunc           func(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardMetaParams) (*models.BingoCard, error)
-	UpdateVisibilityFunc     func(ctx context.Context, userID, cardID uuid.UUID, visibleToFriends bool) (*models.BingoCard, error)
-	BulkUpdateVisibilityFunc func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error)
-	BulkDeleteFunc           func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error)
-	BulkUpdateArchiveFunc    func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) (int, error)
-	ImportFunc               func(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error)
-	CreateOrRotateShareFunc  func(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error)
-	GetShareStatusFunc       func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardShare, error)
-	RevokeShareFunc          func(ctx context.Context, userID, cardID uuid.UUID) error
-	GetSharedCardByTokenFunc func(ctx context.Context, token string) (*models.SharedCard, error)
+	CheckForConflictFunc      func(ctx context.Context, userID uuid.UUID, year int, title *string) (*models.BingoCard, error)
+	CreateFunc                func(ctx context.Context, params models.CreateCardParams) (*models.BingoCard, error)
+	ListByUserFunc            func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error)
+	GetByIDFunc               func(ctx context.Context, cardID uuid.UUID) (*models.BingoCard, error)
+	DeleteFunc                func(ctx context.Context, userID, cardID uuid.UUID) error
+	AddItemFunc               func(ctx context.Context, userID uuid.UUID, params models.AddItemParams) (*models.BingoItem, error)
+	UpdateConfigFunc          func(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardConfigParams) (*models.BingoCard, error)
+	CloneFunc                 func(ctx context.Context, userID, cardID uuid.UUID, params services.CloneParams) (*services.CloneResult, error)
+	UpdateItemFunc            func(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemParams) (*models.BingoItem, error)
+	RemoveItemFunc            func(ctx context.Context, userID, cardID uuid.UUID, position int) error
+	ShuffleFunc               func(ctx context.Context, userID, cardID uuid.UUID, seed *int64) (*services.ShuffleResult, error)
+	UndoShuffleFunc           func(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error)
+	SwapItemsFunc             func(ctx context.Context, userID, cardID uuid.UUID, pos1, pos2 int) error
+	FinalizeFunc              func(ctx context.Context, userID, cardID uuid.UUID, params *services.FinalizeParams) (*models.BingoCard, error)
+	CompleteItemFunc          func(ctx context.Context, userID, cardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error)
+	CompleteItemByContentFunc func(ctx context.Context, userID, cardID uuid.UUID, content string, exact bool, params models.CompleteItemParams) (*models.BingoItem, error)
+	UncompleteItemFunc        func(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error)
+	UpdateItemNotesFunc       func(ctx context.Context, userID, cardID uuid.UUID, position int, notes, proofURL *string) (*models.BingoItem, error)
+	GetArchiveFunc            func(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error)
+	SearchFunc                func(ctx context.Context, params models.CardSearchParams) (*models.CardSearchResult, error)
+	GetMemoriesFunc           func(ctx context.Context, userID uuid.UUID, now time.Time, loc *time.Location) ([]models.Memory, error)
+	GetStatsFunc              func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardStats, error)
+	ListProgressFunc          func(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]models.CardProgress, error)
+	BuildRecapFunc            func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardRecap, error)
+	BuildSharedRecapFunc      func(ctx context.Context, token string) (*models.CardRecap, error)
+	GetRecommendationsFunc    func(ctx context.Context, userID, cardID uuid.UUID, limit int) ([]models.CardRecommendation, error)
+	UpdateMetaFunc            func(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardMetaParams) (*models.BingoCard, error)
+	UpdateVisibilityFunc      func(ctx context.Context, userID, cardID uuid.UUID, visibleToFriends bool) (*models.BingoCard, error)
+	BulkUpdateVisibilityFunc  func(ctx context.Context, userID uuid.UUID, changes []models.CardVisibilityChange) ([]models.CardVisibilityResult, error)
+	BulkDeleteFunc            func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) ([]uuid.UUID, error)
+	BulkUpdateArchiveFunc     func(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) ([]uuid.UUID, error)
+	ImportFunc                func(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error)
+	MergeImportFunc           func(ctx context.Context, params models.MergeImportParams) (*models.MergeImportResult, error)
+	ImportAccountExportFunc   func(ctx context.Context, userID uuid.UUID, cards []models.ImportCardParams) (*models.AccountExportImportResult, error)
+	CreateOrRotateShareFunc   func(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error)
+	GetShareStatusFunc        func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardShare, error)
+	GetShareAnalyticsFunc     func(ctx context.Context, userID, cardID uuid.UUID) (*models.ShareAnalytics, error)
+	RevokeShareFunc           func(ctx context.Context, userID, cardID uuid.UUID) error
+	GetSharedCardByTokenFunc  func(ctx context.Context, token string) (*models.SharedCard, error)
+	CreateWidgetTokenFunc     func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardWidgetToken, error)
+	ListWidgetTokensFunc      func(ctx context.Context, userID, cardID uuid.UUID) ([]models.CardWidgetToken, error)
+	RevokeWidgetTokenFunc     func(ctx context.Context, userID, cardID, tokenID uuid.UUID) error
+	RenderWidgetByTokenFunc   func(ctx context.Context, token string) ([]byte, error)
 }
 
 func (m *mockCardService) CheckForConflict(ctx context.Context, userID uuid.UUID, year int, title *string) (*models.BingoCard, error) {
@@ -296,9 +352,16 @@ func (m *mockCardService) RemoveItem(ctx context.Context, userID, cardID uuid.UU
 	return nil
 }
 
-func (m *mockCardService) Shuffle(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+func (m *mockCardService) Shuffle(ctx context.Context, userID, cardID uuid.UUID, seed *int64) (*services.ShuffleResult, error) {
 	if m.ShuffleFunc != nil {
-		return m.ShuffleFunc(ctx, userID, cardID)
+		return m.ShuffleFunc(ctx, userID, cardID, seed)
+	}
+	return &services.ShuffleResult{}, nil
+}
+
+func (m *mockCardService) UndoShuffle(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+	if m.UndoShuffleFunc != nil {
+		return m.UndoShuffleFunc(ctx, userID, cardID)
 	}
 	return nil, nil
 }
@@ -324,6 +387,13 @@ func (m *mockCardService) CompleteItem(ctx context.Context, userID, cardID uuid.
 	return nil, nil
 }
 
+func (m *mockCardService) CompleteItemByContent(ctx context.Context, userID, cardID uuid.UUID, content string, exact bool, params models.CompleteItemParams) (*models.BingoItem, error) {
+	if m.CompleteItemByContentFunc != nil {
+		return m.CompleteItemByContentFunc(ctx, userID, cardID, content, exact, params)
+	}
+	return nil, nil
+}
+
 func (m *mockCardService) UncompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error) {
 	if m.UncompleteItemFunc != nil {
 		return m.UncompleteItemFunc(ctx, userID, cardID, position)
@@ -345,6 +415,20 @@ func (m *mockCardService) GetArchive(ctx context.Context, userID uuid.UUID) ([]*
 	return nil, nil
 }
 
+func (m *mockCardService) Search(ctx context.Context, params models.CardSearchParams) (*models.CardSearchResult, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, params)
+	}
+	return &models.CardSearchResult{Items: []models.CardSearchMatch{}}, nil
+}
+
+func (m *mockCardService) GetMemories(ctx context.Context, userID uuid.UUID, now time.Time, loc *time.Location) ([]models.Memory, error) {
+	if m.GetMemoriesFunc != nil {
+		return m.GetMemoriesFunc(ctx, userID, now, loc)
+	}
+	return nil, nil
+}
+
 func (m *mockCardService) GetStats(ctx context.Context, userID, cardID uuid.UUID) (*models.CardStats, error) {
 	if m.GetStatsFunc != nil {
 		return m.GetStatsFunc(ctx, userID, cardID)
@@ -352,6 +436,34 @@ func (m *mockCardService) GetStats(ctx context.Context, userID, cardID uuid.UUID
 	return nil, nil
 }
 
+func (m *mockCardService) ListProgress(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]models.CardProgress, error) {
+	if m.ListProgressFunc != nil {
+		return m.ListProgressFunc(ctx, userID, includeArchived)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) BuildRecap(ctx context.Context, userID, cardID uuid.UUID) (*models.CardRecap, error) {
+	if m.BuildRecapFunc != nil {
+		return m.BuildRecapFunc(ctx, userID, cardID)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) BuildSharedRecap(ctx context.Context, token string) (*models.CardRecap, error) {
+	if m.BuildSharedRecapFunc != nil {
+		return m.BuildSharedRecapFunc(ctx, token)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) GetRecommendations(ctx context.Context, userID, cardID uuid.UUID, limit int) ([]models.CardRecommendation, error) {
+	if m.GetRecommendationsFunc != nil {
+		return m.GetRecommendationsFunc(ctx, userID, cardID, limit)
+	}
+	return nil, nil
+}
+
 func (m *mockCardService) UpdateMeta(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardMetaParams) (*models.BingoCard, error) {
 	if m.UpdateMetaFunc != nil {
 		return m.UpdateMetaFunc(ctx, userID, cardID, params)
@@ -366,25 +478,25 @@ func (m *mockCardService) UpdateVisibility(ctx context.Context, userID, cardID u
 	return nil, nil
 }
 
-func (m *mockCardService) BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error) {
+func (m *mockCardService) BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, changes []models.CardVisibilityChange) ([]models.CardVisibilityResult, error) {
 	if m.BulkUpdateVisibilityFunc != nil {
-		return m.BulkUpdateVisibilityFunc(ctx, userID, cardIDs, visibleToFriends)
+		return m.BulkUpdateVisibilityFunc(ctx, userID, changes)
 	}
-	return 0, nil
+	return nil, nil
 }
 
-func (m *mockCardService) BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error) {
+func (m *mockCardService) BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) ([]uuid.UUID, error) {
 	if m.BulkDeleteFunc != nil {
 		return m.BulkDeleteFunc(ctx, userID, cardIDs)
 	}
-	return 0, nil
+	return nil, nil
 }
 
-func (m *mockCardService) BulkUpdateArchive(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) (int, error) {
+func (m *mockCardService) BulkUpdateArchive(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) ([]uuid.UUID, error) {
 	if m.BulkUpdateArchiveFunc != nil {
 		return m.BulkUpdateArchiveFunc(ctx, userID, cardIDs, isArchived)
 	}
-	return 0, nil
+	return nil, nil
 }
 
 func (m *mockCardService) Import(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error) {
@@ -394,9 +506,23 @@ func (m *mockCardService) Import(ctx context.Context, params models.ImportCardPa
 	return nil, nil
 }
 
-func (m *mockCardService) CreateOrRotateShare(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error) {
+func (m *mockCardService) MergeImport(ctx context.Context, params models.MergeImportParams) (*models.MergeImportResult, error) {
+	if m.MergeImportFunc != nil {
+		return m.MergeImportFunc(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) ImportAccountExport(ctx context.Context, userID uuid.UUID, cards []models.ImportCardParams) (*models.AccountExportImportResult, error) {
+	if m.ImportAccountExportFunc != nil {
+		return m.ImportAccountExportFunc(ctx, userID, cards)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) CreateOrRotateShare(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
 	if m.CreateOrRotateShareFunc != nil {
-		return m.CreateOrRotateShareFunc(ctx, userID, cardID, expiresAt)
+		return m.CreateOrRotateShareFunc(ctx, userID, cardID, expiresAt, graceUntil, options)
 	}
 	return nil, nil
 }
@@ -408,6 +534,13 @@ func (m *mockCardService) GetShareStatus(ctx context.Context, userID, cardID uui
 	return nil, services.ErrShareNotFound
 }
 
+func (m *mockCardService) GetShareAnalytics(ctx context.Context, userID, cardID uuid.UUID) (*models.ShareAnalytics, error) {
+	if m.GetShareAnalyticsFunc != nil {
+		return m.GetShareAnalyticsFunc(ctx, userID, cardID)
+	}
+	return nil, services.ErrShareNotFound
+}
+
 func (m *mockCardService) RevokeShare(ctx context.Context, userID, cardID uuid.UUID) error {
 	if m.RevokeShareFunc != nil {
 		return m.RevokeShareFunc(ctx, userID, cardID)
@@ -422,6 +555,34 @@ func (m *mockCardService) GetSharedCardByToken(ctx context.Context, token string
 	return nil, services.ErrShareNotFound
 }
 
+func (m *mockCardService) CreateWidgetToken(ctx context.Context, userID, cardID uuid.UUID) (*models.CardWidgetToken, error) {
+	if m.CreateWidgetTokenFunc != nil {
+		return m.CreateWidgetTokenFunc(ctx, userID, cardID)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) ListWidgetTokens(ctx context.Context, userID, cardID uuid.UUID) ([]models.CardWidgetToken, error) {
+	if m.ListWidgetTokensFunc != nil {
+		return m.ListWidgetTokensFunc(ctx, userID, cardID)
+	}
+	return nil, nil
+}
+
+func (m *mockCardService) RevokeWidgetToken(ctx context.Context, userID, cardID, tokenID uuid.UUID) error {
+	if m.RevokeWidgetTokenFunc != nil {
+		return m.RevokeWidgetTokenFunc(ctx, userID, cardID, tokenID)
+	}
+	return nil
+}
+
+func (m *mockCardService) RenderWidgetByToken(ctx context.Context, token string) ([]byte, error) {
+	if m.RenderWidgetByTokenFunc != nil {
+		return m.RenderWidgetByTokenFunc(ctx, token)
+	}
+	return nil, nil
+}
+
 type mockSuggestionService struct {
 	GetAllFunc               func(ctx context.Context) ([]*models.Suggestion, error)
 	GetByCategoryFunc        func(ctx context.Context, category string) ([]*models.Suggestion, error)
@@ -459,7 +620,7 @@ func (m *mockSuggestionService) GetGroupedByCategory(ctx context.Context) ([]ser
 
 type mockFriendService struct {
 	SearchUsersFunc         func(ctx context.Context, currentUserID uuid.UUID, query string) ([]models.UserSearchResult, error)
-	SendRequestFunc         func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error)
+	SendRequestFunc         func(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, bool, error)
 	AcceptRequestFunc       func(ctx context.Context, userID, friendshipID uuid.UUID) (*models.Friendship, error)
 	RejectRequestFunc       func(ctx context.Context, userID, friendshipID uuid.UUID) error
 	RemoveFriendFunc        func(ctx context.Context, userID, friendshipID uuid.UUID) error
@@ -469,6 +630,7 @@ type mockFriendService struct {
 	ListSentRequestsFunc    func(ctx context.Context, userID uuid.UUID) ([]models.FriendWithUser, error)
 	IsFriendFunc            func(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error)
 	GetFriendUserIDFunc     func(ctx context.Context, currentUserID, friendshipID uuid.UUID) (uuid.UUID, error)
+	RelationFunc            func(ctx context.Context, viewerID, ownerID uuid.UUID) (authz.Relation, error)
 }
 
 func (m *mockFriendService) SearchUsers(ctx context.Context, currentUserID uuid.UUID, query string) ([]models.UserSearchResult, error) {
@@ -478,11 +640,11 @@ func (m *mockFriendService) SearchUsers(ctx context.Context, currentUserID uuid.
 	return nil, nil
 }
 
-func (m *mockFriendService) SendRequest(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
+func (m *mockFriendService) SendRequest(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, bool, error) {
 	if m.SendRequestFunc != nil {
 		return m.SendRequestFunc(ctx, userID, friendID)
 	}
-	return nil, nil
+	return nil, true, nil
 }
 
 func (m *mockFriendService) AcceptRequest(ctx context.Context, userID, friendshipID uuid.UUID) (*models.Friendship, error) {
@@ -548,9 +710,19 @@ func (m *mockFriendService) GetFriendUserID(ctx context.Context, currentUserID,
 	return uuid.Nil, nil
 }
 
+// Relation defaults to an accepted friendship with no block, matching what a
+// successful GetFriendUserID implies.
+func (m *mockFriendService) Relation(ctx context.Context, viewerID, ownerID uuid.UUID) (authz.Relation, error) {
+	if m.RelationFunc != nil {
+		return m.RelationFunc(ctx, viewerID, ownerID)
+	}
+	return authz.Relation{ViewerID: viewerID, OwnerID: ownerID, Friends: true}, nil
+}
+
 type mockReactionService struct {
 	AddReactionFunc               func(ctx context.Context, userID, itemID uuid.UUID, emoji string) (*models.Reaction, error)
 	RemoveReactionFunc            func(ctx context.Context, userID, itemID uuid.UUID) error
+	CanViewReactionsFunc          func(ctx context.Context, userID, itemID uuid.UUID) error
 	GetReactionsForItemFunc       func(ctx context.Context, itemID uuid.UUID) ([]models.ReactionWithUser, error)
 	GetReactionSummaryForItemFunc func(ctx context.Context, itemID uuid.UUID) ([]models.ReactionSummary, error)
 	GetReactionsForCardFunc       func(ctx context.Context, cardID uuid.UUID) (map[uuid.UUID][]models.ReactionWithUser, error)
@@ -571,6 +743,13 @@ func (m *mockReactionService) RemoveReaction(ctx context.Context, userID, itemID
 	return nil
 }
 
+func (m *mockReactionService) CanViewReactions(ctx context.Context, userID, itemID uuid.UUID) error {
+	if m.CanViewReactionsFunc != nil {
+		return m.CanViewReactionsFunc(ctx, userID, itemID)
+	}
+	return nil
+}
+
 func (m *mockReactionService) GetReactionsForItem(ctx context.Context, itemID uuid.UUID) ([]models.ReactionWithUser, error) {
 	if m.GetReactionsForItemFunc != nil {
 		return m.GetReactionsForItemFunc(ctx, itemID)
@@ -599,6 +778,60 @@ func (m *mockReactionService) GetUserReactionForItem(ctx context.Context, userID
 	return nil, nil
 }
 
+type mockWebhookService struct {
+	ListFunc   func(ctx context.Context, userID uuid.UUID) ([]models.Webhook, error)
+	CreateFunc func(ctx context.Context, userID uuid.UUID, rawURL string) (*models.Webhook, error)
+	DeleteFunc func(ctx context.Context, userID, webhookID uuid.UUID) error
+}
+
+func (m *mockWebhookService) List(ctx context.Context, userID uuid.UUID) ([]models.Webhook, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockWebhookService) Create(ctx context.Context, userID uuid.UUID, rawURL string) (*models.Webhook, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, userID, rawURL)
+	}
+	return nil, nil
+}
+
+func (m *mockWebhookService) Delete(ctx context.Context, userID, webhookID uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, webhookID)
+	}
+	return nil
+}
+
+type mockCommentService struct {
+	AddCommentFunc    func(ctx context.Context, userID, itemID uuid.UUID, content string) (*models.ItemComment, error)
+	ListCommentsFunc  func(ctx context.Context, userID, itemID uuid.UUID) ([]models.ItemComment, error)
+	DeleteCommentFunc func(ctx context.Context, userID, commentID uuid.UUID) error
+}
+
+func (m *mockCommentService) AddComment(ctx context.Context, userID, itemID uuid.UUID, content string) (*models.ItemComment, error) {
+	if m.AddCommentFunc != nil {
+		return m.AddCommentFunc(ctx, userID, itemID, content)
+	}
+	return nil, nil
+}
+
+func (m *mockCommentService) ListComments(ctx context.Context, userID, itemID uuid.UUID) ([]models.ItemComment, error) {
+	if m.ListCommentsFunc != nil {
+		return m.ListCommentsFunc(ctx, userID, itemID)
+	}
+	return nil, nil
+}
+
+func (m *mockCommentService) DeleteComment(ctx context.Context, userID, commentID uuid.UUID) error {
+	if m.DeleteCommentFunc != nil {
+		return m.DeleteCommentFunc(ctx, userID, commentID)
+	}
+	return nil
+}
+
 type mockApiTokenService struct {
 	CreateFunc    func(ctx context.Context, userID uuid.UUID, name string, scope models.ApiTokenScope, expiresInDays int) (*models.ApiToken, string, error)
 	ListFunc      func(ctx context.Context, userID uuid.UUID) ([]models.ApiToken, error)
@@ -637,7 +870,9 @@ func (m *mockApiTokenService) DeleteAll(ctx context.Context, userID uuid.UUID) e
 type mockNotificationService struct {
 	GetSettingsFunc    func(ctx context.Context, userID uuid.UUID) (*models.NotificationSettings, error)
 	UpdateSettingsFunc func(ctx context.Context, userID uuid.UUID, patch models.NotificationSettingsPatch) (*models.NotificationSettings, error)
-	ListFunc           func(ctx context.Context, userID uuid.UUID, params services.NotificationListParams) ([]models.Notification, error)
+	CopySettingsFunc   func(ctx context.Context, userID uuid.UUID, from, to models.NotificationChannel) (*models.NotificationSettings, error)
+	SetEmailPauseFunc  func(ctx context.Context, userID uuid.UUID, until *time.Time) (*models.NotificationSettings, error)
+	ListFunc           func(ctx context.Context, userID uuid.UUID, params services.NotificationListParams) ([]models.Notification, bool, error)
 	MarkReadFunc       func(ctx context.Context, userID, notificationID uuid.UUID) error
 	MarkAllReadFunc    func(ctx context.Context, userID uuid.UUID) error
 	DeleteFunc         func(ctx context.Context, userID, notificationID uuid.UUID) error
@@ -645,8 +880,8 @@ type mockNotificationService struct {
 	UnreadCountFunc    func(ctx context.Context, userID uuid.UUID) (int, error)
 	NotifyRequestFunc  func(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error
 	NotifyAcceptedFunc func(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error
-	NotifyNewCardFunc  func(ctx context.Context, actorID, cardID uuid.UUID) error
-	NotifyBingoFunc    func(ctx context.Context, actorID, cardID uuid.UUID, bingoCount int) error
+	NotifyNewCardFunc  func(ctx context.Context, tx services.Tx, actorID, cardID uuid.UUID) ([]uuid.UUID, error)
+	NotifyBingoFunc    func(ctx context.Context, tx services.Tx, actorID, cardID uuid.UUID, bingoCount int) ([]uuid.UUID, error)
 }
 
 func (m *mockNotificationService) GetSettings(ctx context.Context, userID uuid.UUID) (*models.NotificationSettings, error) {
@@ -663,11 +898,25 @@ func (m *mockNotificationService) UpdateSettings(ctx context.Context, userID uui
 	return &models.NotificationSettings{}, nil
 }
 
-func (m *mockNotificationService) List(ctx context.Context, userID uuid.UUID, params services.NotificationListParams) ([]models.Notification, error) {
+func (m *mockNotificationService) CopySettings(ctx context.Context, userID uuid.UUID, from, to models.NotificationChannel) (*models.NotificationSettings, error) {
+	if m.CopySettingsFunc != nil {
+		return m.CopySettingsFunc(ctx, userID, from, to)
+	}
+	return &models.NotificationSettings{}, nil
+}
+
+func (m *mockNotificationService) SetEmailPause(ctx context.Context, userID uuid.UUID, until *time.Time) (*models.NotificationSettings, error) {
+	if m.SetEmailPauseFunc != nil {
+		return m.SetEmailPauseFunc(ctx, userID, until)
+	}
+	return &models.NotificationSettings{EmailPausedUntil: until}, nil
+}
+
+func (m *mockNotificationService) List(ctx context.Context, userID uuid.UUID, params services.NotificationListParams) ([]models.Notification, bool, error) {
 	if m.ListFunc != nil {
 		return m.ListFunc(ctx, userID, params)
 	}
-	return []models.Notification{}, nil
+	return []models.Notification{}, false, nil
 }
 
 func (m *mockNotificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
@@ -719,32 +968,63 @@ func (m *mockNotificationService) NotifyFriendRequestAccepted(ctx context.Contex
 	return nil
 }
 
-func (m *mockNotificationService) NotifyFriendsNewCard(ctx context.Context, actorID, cardID uuid.UUID) error {
+func (m *mockNotificationService) NotifyItemComment(ctx context.Context, recipientID, actorID, cardID uuid.UUID) error {
+	return nil
+}
+
+func (m *mockNotificationService) NotifyReminderCheckin(ctx context.Context, tx services.Tx, userID, cardID uuid.UUID, sentAt time.Time) error {
+	return nil
+}
+
+func (m *mockNotificationService) NotifyReminderGoal(ctx context.Context, tx services.Tx, userID, cardID, itemID uuid.UUID, sentAt time.Time) error {
+	return nil
+}
+
+func (m *mockNotificationService) NotifyFriendsNewCard(ctx context.Context, tx services.Tx, actorID, cardID uuid.UUID) ([]uuid.UUID, error) {
 	if m.NotifyNewCardFunc != nil {
-		return m.NotifyNewCardFunc(ctx, actorID, cardID)
+		return m.NotifyNewCardFunc(ctx, tx, actorID, cardID)
 	}
-	return nil
+	return nil, nil
 }
 
-func (m *mockNotificationService) NotifyFriendsBingo(ctx context.Context, actorID, cardID uuid.UUID, bingoCount int) error {
+func (m *mockNotificationService) NotifyFriendsBingo(ctx context.Context, tx services.Tx, actorID, cardID uuid.UUID, bingoCount int) ([]uuid.UUID, error) {
 	if m.NotifyBingoFunc != nil {
-		return m.NotifyBingoFunc(ctx, actorID, cardID, bingoCount)
+		return m.NotifyBingoFunc(ctx, tx, actorID, cardID, bingoCount)
 	}
-	return nil
+	return nil, nil
 }
 
+func (m *mockNotificationService) DispatchEmails(notificationIDs []uuid.UUID) {}
+
 type mockReminderService struct {
-	GetSettingsFunc        func(ctx context.Context, userID uuid.UUID) (*models.ReminderSettings, error)
-	UpdateSettingsFunc     func(ctx context.Context, userID uuid.UUID, patch models.ReminderSettingsPatch) (*models.ReminderSettings, error)
-	ListCardCheckinsFunc   func(ctx context.Context, userID uuid.UUID) ([]models.CardCheckinSummary, error)
-	UpsertCardCheckinFunc  func(ctx context.Context, userID, cardID uuid.UUID, schedule models.CardCheckinScheduleInput) (*models.CardCheckinReminder, error)
-	DeleteCardCheckinFunc  func(ctx context.Context, userID, cardID uuid.UUID) error
-	ListGoalRemindersFunc  func(ctx context.Context, userID uuid.UUID, cardID *uuid.UUID) ([]models.GoalReminderSummary, error)
-	UpsertGoalReminderFunc func(ctx context.Context, userID uuid.UUID, input models.GoalReminderInput) (*models.GoalReminder, error)
-	DeleteGoalReminderFunc func(ctx context.Context, userID, reminderID uuid.UUID) error
-	SendTestEmailFunc      func(ctx context.Context, userID, cardID uuid.UUID) error
-	RenderImageByTokenFunc func(ctx context.Context, token string) ([]byte, error)
-	UnsubscribeByTokenFunc func(ctx context.Context, token string) (bool, error)
+	GetSettingsFunc             func(ctx context.Context, userID uuid.UUID) (*models.ReminderSettings, error)
+	UpdateSettingsFunc          func(ctx context.Context, userID uuid.UUID, patch models.ReminderSettingsPatch) (*models.ReminderSettings, error)
+	ListCardCheckinsFunc        func(ctx context.Context, userID uuid.UUID) ([]models.CardCheckinSummary, error)
+	UpsertCardCheckinFunc       func(ctx context.Context, userID, cardID uuid.UUID, schedule models.CardCheckinScheduleInput) (*models.CardCheckinReminder, error)
+	DeleteCardCheckinFunc       func(ctx context.Context, userID, cardID uuid.UUID) error
+	ListGoalRemindersFunc       func(ctx context.Context, userID uuid.UUID, cardID *uuid.UUID) ([]models.GoalReminderSummary, error)
+	UpsertGoalReminderFunc      func(ctx context.Context, userID uuid.UUID, input models.GoalReminderInput) (*models.GoalReminder, error)
+	BulkUpsertGoalRemindersFunc func(ctx context.Context, userID uuid.UUID, input models.GoalReminderBulkInput) (*models.GoalReminderBulkResult, error)
+	DeleteGoalReminderFunc      func(ctx context.Context, userID, reminderID uuid.UUID) error
+	ValidateScheduleFunc        func(ctx context.Context, userID uuid.UUID, input models.ReminderScheduleValidationInput) (*models.ReminderScheduleValidation, error)
+	SendTestEmailFunc           func(ctx context.Context, userID, cardID uuid.UUID) error
+	RenderImageByTokenFunc      func(ctx context.Context, token, theme string) ([]byte, error)
+	FollowReminderLinkFunc      func(ctx context.Context, token string) (string, error)
+	GetEmailHistoryFunc         func(ctx context.Context, userID uuid.UUID, params services.ReminderHistoryParams) (*models.ReminderEmailHistory, error)
+	UnsubscribeByTokenFunc      func(ctx context.Context, token string) (bool, error)
+	SnoozeByTokenFunc           func(ctx context.Context, token string, days int) (bool, error)
+	EmailPreferencesFunc        func(ctx context.Context, token string) (*models.EmailPreferences, error)
+	UpdateEmailPrefsFunc        func(ctx context.Context, token string, prefs models.EmailPreferences) (*models.EmailPreferences, error)
+	ResendReminderFunc          func(ctx context.Context, reminderID uuid.UUID, bypassCap bool) (*models.ReminderResendResult, error)
+	RunNowFunc                  func(ctx context.Context, asOf *time.Time, limit int) (*models.ReminderRunResult, error)
+	GetUserReminderReportFunc   func(ctx context.Context, userID uuid.UUID) (*models.UserReminderReport, error)
+	RevokeImageTokensFunc       func(ctx context.Context, userID uuid.UUID) (int64, error)
+	RunDeliverabilityFunc       func(ctx context.Context, userID uuid.UUID) (*models.DeliverabilityCheck, error)
+	GetDeliverabilityFunc       func(ctx context.Context, userID uuid.UUID) (*models.DeliverabilityCheck, error)
+	GetCalendarFeedFunc         func(ctx context.Context, userID uuid.UUID) (*models.ReminderCalendarFeed, error)
+	CreateCalendarFeedFunc      func(ctx context.Context, userID uuid.UUID) (*models.ReminderCalendarFeed, error)
+	RevokeCalendarFeedFunc      func(ctx context.Context, userID uuid.UUID) error
+	RenderCalendarFeedFunc      func(ctx context.Context, token string) ([]byte, error)
 }
 
 func (m *mockReminderService) GetSettings(ctx context.Context, userID uuid.UUID) (*models.ReminderSettings, error) {
@@ -796,6 +1076,13 @@ func (m *mockReminderService) UpsertGoalReminder(ctx context.Context, userID uui
 	return &models.GoalReminder{}, nil
 }
 
+func (m *mockReminderService) BulkUpsertGoalReminders(ctx context.Context, userID uuid.UUID, input models.GoalReminderBulkInput) (*models.GoalReminderBulkResult, error) {
+	if m.BulkUpsertGoalRemindersFunc != nil {
+		return m.BulkUpsertGoalRemindersFunc(ctx, userID, input)
+	}
+	return &models.GoalReminderBulkResult{}, nil
+}
+
 func (m *mockReminderService) DeleteGoalReminder(ctx context.Context, userID, reminderID uuid.UUID) error {
 	if m.DeleteGoalReminderFunc != nil {
 		return m.DeleteGoalReminderFunc(ctx, userID, reminderID)
@@ -810,9 +1097,23 @@ func (m *mockReminderService) SendTestEmail(ctx context.Context, userID, cardID
 	return nil
 }
 
-func (m *mockReminderService) RenderImageByToken(ctx context.Context, token string) ([]byte, error) {
+func (m *mockReminderService) RenderImageByToken(ctx context.Context, token, theme string) ([]byte, error) {
 	if m.RenderImageByTokenFunc != nil {
-		return m.RenderImageByTokenFunc(ctx, token)
+		return m.RenderImageByTokenFunc(ctx, token, theme)
+	}
+	return nil, nil
+}
+
+func (m *mockReminderService) FollowReminderLink(ctx context.Context, token string) (string, error) {
+	if m.FollowReminderLinkFunc != nil {
+		return m.FollowReminderLinkFunc(ctx, token)
+	}
+	return "", nil
+}
+
+func (m *mockReminderService) GetEmailHistory(ctx context.Context, userID uuid.UUID, params services.ReminderHistoryParams) (*models.ReminderEmailHistory, error) {
+	if m.GetEmailHistoryFunc != nil {
+		return m.GetEmailHistoryFunc(ctx, userID, params)
 	}
 	return nil, nil
 }
@@ -823,3 +1124,254 @@ func (m *mockReminderService) UnsubscribeByToken(ctx context.Context, token stri
 	}
 	return false, nil
 }
+
+func (m *mockReminderService) ValidateSchedule(ctx context.Context, userID uuid.UUID, input models.ReminderScheduleValidationInput) (*models.ReminderScheduleValidation, error) {
+	if m.ValidateScheduleFunc != nil {
+		return m.ValidateScheduleFunc(ctx, userID, input)
+	}
+	return &models.ReminderScheduleValidation{}, nil
+}
+
+func (m *mockReminderService) SnoozeGoalReminderByToken(ctx context.Context, token string, days int) (bool, error) {
+	if m.SnoozeByTokenFunc != nil {
+		return m.SnoozeByTokenFunc(ctx, token, days)
+	}
+	return false, nil
+}
+
+func (m *mockReminderService) EmailPreferencesByToken(ctx context.Context, token string) (*models.EmailPreferences, error) {
+	if m.EmailPreferencesFunc != nil {
+		return m.EmailPreferencesFunc(ctx, token)
+	}
+	return &models.EmailPreferences{}, nil
+}
+
+func (m *mockReminderService) UpdateEmailPreferencesByToken(ctx context.Context, token string, prefs models.EmailPreferences) (*models.EmailPreferences, error) {
+	if m.UpdateEmailPrefsFunc != nil {
+		return m.UpdateEmailPrefsFunc(ctx, token, prefs)
+	}
+	return &prefs, nil
+}
+
+func (m *mockReminderService) ResendReminder(ctx context.Context, reminderID uuid.UUID, bypassCap bool) (*models.ReminderResendResult, error) {
+	if m.ResendReminderFunc != nil {
+		return m.ResendReminderFunc(ctx, reminderID, bypassCap)
+	}
+	return &models.ReminderResendResult{ReminderID: reminderID}, nil
+}
+
+func (m *mockReminderService) RunNow(ctx context.Context, asOf *time.Time, limit int) (*models.ReminderRunResult, error) {
+	if m.RunNowFunc != nil {
+		return m.RunNowFunc(ctx, asOf, limit)
+	}
+	return &models.ReminderRunResult{}, nil
+}
+
+func (m *mockReminderService) GetUserReminderReport(ctx context.Context, userID uuid.UUID) (*models.UserReminderReport, error) {
+	if m.GetUserReminderReportFunc != nil {
+		return m.GetUserReminderReportFunc(ctx, userID)
+	}
+	return &models.UserReminderReport{UserID: userID}, nil
+}
+
+func (m *mockReminderService) RevokeImageTokens(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if m.RevokeImageTokensFunc != nil {
+		return m.RevokeImageTokensFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *mockReminderService) RunDeliverabilityCheck(ctx context.Context, userID uuid.UUID) (*models.DeliverabilityCheck, error) {
+	if m.RunDeliverabilityFunc != nil {
+		return m.RunDeliverabilityFunc(ctx, userID)
+	}
+	return &models.DeliverabilityCheck{}, nil
+}
+
+func (m *mockReminderService) GetDeliverabilityCheck(ctx context.Context, userID uuid.UUID) (*models.DeliverabilityCheck, error) {
+	if m.GetDeliverabilityFunc != nil {
+		return m.GetDeliverabilityFunc(ctx, userID)
+	}
+	return &models.DeliverabilityCheck{}, nil
+}
+
+func (m *mockReminderService) GetCalendarFeed(ctx context.Context, userID uuid.UUID) (*models.ReminderCalendarFeed, error) {
+	if m.GetCalendarFeedFunc != nil {
+		return m.GetCalendarFeedFunc(ctx, userID)
+	}
+	return &models.ReminderCalendarFeed{}, nil
+}
+
+func (m *mockReminderService) CreateCalendarFeed(ctx context.Context, userID uuid.UUID) (*models.ReminderCalendarFeed, error) {
+	if m.CreateCalendarFeedFunc != nil {
+		return m.CreateCalendarFeedFunc(ctx, userID)
+	}
+	return &models.ReminderCalendarFeed{Active: true}, nil
+}
+
+func (m *mockReminderService) RevokeCalendarFeed(ctx context.Context, userID uuid.UUID) error {
+	if m.RevokeCalendarFeedFunc != nil {
+		return m.RevokeCalendarFeedFunc(ctx, userID)
+	}
+	return nil
+}
+
+func (m *mockReminderService) RenderCalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	if m.RenderCalendarFeedFunc != nil {
+		return m.RenderCalendarFeedFunc(ctx, token)
+	}
+	return nil, services.ErrCalendarFeedNotFound
+}
+
+type mockAdminAuditService struct {
+	RecordFunc func(ctx context.Context, entry models.AdminAuditEntry) error
+}
+
+func (m *mockAdminAuditService) Record(ctx context.Context, entry models.AdminAuditEntry) error {
+	if m.RecordFunc != nil {
+		return m.RecordFunc(ctx, entry)
+	}
+	return nil
+}
+
+type mockAdminSearchService struct {
+	SearchFunc func(ctx context.Context, params models.AdminSearchParams) (*models.AdminSearchResult, error)
+}
+
+func (m *mockAdminSearchService) Search(ctx context.Context, params models.AdminSearchParams) (*models.AdminSearchResult, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, params)
+	}
+	return &models.AdminSearchResult{Items: []models.AdminSearchHit{}}, nil
+}
+
+type mockJobRegistry struct {
+	StatusesFunc func() []models.JobStatus
+}
+
+func (m *mockJobRegistry) Statuses() []models.JobStatus {
+	if m.StatusesFunc != nil {
+		return m.StatusesFunc()
+	}
+	return nil
+}
+
+type mockCacheStats struct {
+	StatsFunc func() map[string]models.CacheStats
+}
+
+func (m *mockCacheStats) Stats() map[string]models.CacheStats {
+	if m.StatsFunc != nil {
+		return m.StatsFunc()
+	}
+	return nil
+}
+
+type mockEmailLimiterStats struct {
+	stats   models.EmailSendLimiterStats
+	enabled bool
+}
+
+func (m *mockEmailLimiterStats) SendLimiterStats() (models.EmailSendLimiterStats, bool) {
+	return m.stats, m.enabled
+}
+
+type mockAIQuotaAdminService struct {
+	SetUnlimitedFunc func(ctx context.Context, userID uuid.UUID, unlimited bool) (*models.AIQuota, error)
+}
+
+func (m *mockAIQuotaAdminService) SetUnlimited(ctx context.Context, userID uuid.UUID, unlimited bool) (*models.AIQuota, error) {
+	if m.SetUnlimitedFunc != nil {
+		return m.SetUnlimitedFunc(ctx, userID, unlimited)
+	}
+	return nil, nil
+}
+
+type mockSuggestionAnalyticsService struct {
+	AnalyticsFunc func(ctx context.Context, since time.Time, windowDays, limit int) (*models.SuggestionAnalytics, error)
+}
+
+func (m *mockSuggestionAnalyticsService) Analytics(ctx context.Context, since time.Time, windowDays, limit int) (*models.SuggestionAnalytics, error) {
+	if m.AnalyticsFunc != nil {
+		return m.AnalyticsFunc(ctx, since, windowDays, limit)
+	}
+	return &models.SuggestionAnalytics{}, nil
+}
+
+type mockProfileService struct {
+	GetSettingsFunc      func(ctx context.Context, userID uuid.UUID) (*models.ProfileSettings, error)
+	UpdateSettingsFunc   func(ctx context.Context, userID uuid.UUID, visibility *string, indexable *bool) (*models.ProfileSettings, error)
+	GetPublicProfileFunc func(ctx context.Context, username string) (*models.PublicProfile, error)
+}
+
+func (m *mockProfileService) GetSettings(ctx context.Context, userID uuid.UUID) (*models.ProfileSettings, error) {
+	if m.GetSettingsFunc != nil {
+		return m.GetSettingsFunc(ctx, userID)
+	}
+	return &models.ProfileSettings{Visibility: models.ProfileVisibilityOff}, nil
+}
+
+func (m *mockProfileService) UpdateSettings(ctx context.Context, userID uuid.UUID, visibility *string, indexable *bool) (*models.ProfileSettings, error) {
+	if m.UpdateSettingsFunc != nil {
+		return m.UpdateSettingsFunc(ctx, userID, visibility, indexable)
+	}
+	return &models.ProfileSettings{Visibility: models.ProfileVisibilityOff}, nil
+}
+
+func (m *mockProfileService) GetPublicProfile(ctx context.Context, username string) (*models.PublicProfile, error) {
+	if m.GetPublicProfileFunc != nil {
+		return m.GetPublicProfileFunc(ctx, username)
+	}
+	return nil, services.ErrProfileNotFound
+}
+
+type mockShareSubscriptionService struct {
+	SubscribeFunc        func(ctx context.Context, shareToken, email string) error
+	ConfirmFunc          func(ctx context.Context, confirmToken string) error
+	UnsubscribeFunc      func(ctx context.Context, unsubscribeToken string) error
+	ListSubscribersFunc  func(ctx context.Context, userID, cardID uuid.UUID) ([]models.ShareSubscriber, error)
+	RemoveSubscriberFunc func(ctx context.Context, userID, cardID, subscriberID uuid.UUID) error
+}
+
+func (m *mockShareSubscriptionService) Subscribe(ctx context.Context, shareToken, email string) error {
+	if m.SubscribeFunc != nil {
+		return m.SubscribeFunc(ctx, shareToken, email)
+	}
+	return nil
+}
+
+func (m *mockShareSubscriptionService) Confirm(ctx context.Context, confirmToken string) error {
+	if m.ConfirmFunc != nil {
+		return m.ConfirmFunc(ctx, confirmToken)
+	}
+	return nil
+}
+
+func (m *mockShareSubscriptionService) Unsubscribe(ctx context.Context, unsubscribeToken string) error {
+	if m.UnsubscribeFunc != nil {
+		return m.UnsubscribeFunc(ctx, unsubscribeToken)
+	}
+	return nil
+}
+
+func (m *mockShareSubscriptionService) ListSubscribers(ctx context.Context, userID, cardID uuid.UUID) ([]models.ShareSubscriber, error) {
+	if m.ListSubscribersFunc != nil {
+		return m.ListSubscribersFunc(ctx, userID, cardID)
+	}
+	return []models.ShareSubscriber{}, nil
+}
+
+func (m *mockShareSubscriptionService) RemoveSubscriber(ctx context.Context, userID, cardID, subscriberID uuid.UUID) error {
+	if m.RemoveSubscriberFunc != nil {
+		return m.RemoveSubscriberFunc(ctx, userID, cardID, subscriberID)
+	}
+	return nil
+}
+
+type mockUsageService struct {
+	GetFunc func(ctx context.Context, user *models.User) (*models.UsageReport, error)
+}
+
+func (m *mockUsageService) Get(ctx context.Context, user *models.User) (*models.UsageReport, error) {
+	return m.GetFunc(ctx, user)
+}
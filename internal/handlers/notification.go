@@ -5,16 +5,16 @@ import (
 	"errors"
 	"log"
 	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
 type NotificationHandler struct {
+	cursorPager
 	notificationService services.NotificationServiceInterface
 }
 
@@ -23,7 +23,8 @@ func NewNotificationHandler(notificationService services.NotificationServiceInte
 }
 
 type NotificationListResponse struct {
-	Notifications []models.Notification `json:"notifications"`
+	Items []models.Notification `json:"items"`
+	pagination.Meta
 }
 
 type NotificationSettingsResponse struct {
@@ -45,40 +46,34 @@ func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit := 50
-	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
-		parsed, err := strconv.Atoi(limitParam)
-		if err != nil || parsed <= 0 {
-			writeError(w, http.StatusBadRequest, "Invalid limit")
-			return
-		}
-		limit = parsed
+	limit, ok := readLimit(w, r, pagination.DefaultLimit, pagination.MaxLimit)
+	if !ok {
+		return
 	}
-
-	var before *time.Time
-	if beforeParam := r.URL.Query().Get("before"); beforeParam != "" {
-		parsed, err := time.Parse(time.RFC3339, beforeParam)
-		if err != nil {
-			writeError(w, http.StatusBadRequest, "Invalid before timestamp")
-			return
-		}
-		before = &parsed
+	cursor, ok := h.readCursor(w, r, cursorScopeNotifications)
+	if !ok {
+		return
 	}
 
-	unreadOnly := r.URL.Query().Get("unread") == "1"
-
-	notifications, err := h.notificationService.List(r.Context(), user.ID, services.NotificationListParams{
+	params := services.NotificationListParams{
 		Limit:      limit,
-		Before:     before,
-		UnreadOnly: unreadOnly,
-	})
+		After:      cursor.Key,
+		UnreadOnly: r.URL.Query().Get("unread") == "1",
+	}
+
+	notifications, hasMore, err := h.notificationService.List(r.Context(), user.ID, params)
 	if err != nil {
 		log.Printf("Error listing notifications: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, NotificationListResponse{Notifications: notifications})
+	response := NotificationListResponse{Items: notifications}
+	if n := len(notifications); n > 0 {
+		last := notifications[n-1]
+		response.Meta = h.keysetMeta(cursorScopeNotifications, hasMore, pagination.Key{At: last.CreatedAt, ID: last.ID})
+	}
+	writeJSON(w, http.StatusOK, response)
 }
 
 func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
@@ -221,6 +216,10 @@ func (h *NotificationHandler) UpdateSettings(w http.ResponseWriter, r *http.Requ
 		writeError(w, http.StatusForbidden, "Verify your email to enable email notifications")
 		return
 	}
+	if errors.Is(err, services.ErrInvalidEmailFormat) {
+		writeError(w, http.StatusBadRequest, "Email format must be html or text")
+		return
+	}
 	if err != nil {
 		log.Printf("Error updating notification settings: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -229,3 +228,61 @@ func (h *NotificationHandler) UpdateSettings(w http.ResponseWriter, r *http.Requ
 
 	writeJSON(w, http.StatusOK, NotificationSettingsResponse{Settings: settings})
 }
+
+func (h *NotificationHandler) CopySettings(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var input models.NotificationSettingsCopyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	settings, err := h.notificationService.CopySettings(r.Context(), user.ID, input.From, input.To)
+	if errors.Is(err, services.ErrInvalidNotificationChannel) {
+		writeError(w, http.StatusBadRequest, "Channels must be in_app or email")
+		return
+	}
+	if errors.Is(err, services.ErrNotificationChannelDisabled) {
+		writeError(w, http.StatusConflict, "Turn on the channel before copying settings to it")
+		return
+	}
+	if err != nil {
+		log.Printf("Error copying notification settings: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NotificationSettingsResponse{Settings: settings})
+}
+
+func (h *NotificationHandler) SetEmailPause(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var input models.NotificationEmailPauseInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	settings, err := h.notificationService.SetEmailPause(r.Context(), user.ID, input.Until)
+	if errors.Is(err, services.ErrInvalidEmailPause) {
+		writeError(w, http.StatusBadRequest, "Pause must end in the future and within one year")
+		return
+	}
+	if err != nil {
+		log.Printf("Error updating email pause: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NotificationSettingsResponse{Settings: settings})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
@@ -268,17 +269,6 @@ func TestNotificationHandler_List_InvalidLimit(t *testing.T) {
 	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid limit")
 }
 
-func TestNotificationHandler_List_InvalidBefore(t *testing.T) {
-	userID := uuid.New()
-	handler := NewNotificationHandler(&mockNotificationService{})
-	req := httptest.NewRequest(http.MethodGet, "/api/notifications?before=not-a-time", nil)
-	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
-	rr := httptest.NewRecorder()
-
-	handler.List(rr, req)
-	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid before timestamp")
-}
-
 func TestNotificationHandler_UpdateSettings_EmailNotVerified(t *testing.T) {
 	userID := uuid.New()
 	handler := NewNotificationHandler(&mockNotificationService{
@@ -296,11 +286,31 @@ func TestNotificationHandler_UpdateSettings_EmailNotVerified(t *testing.T) {
 	assertErrorResponse(t, rr, http.StatusForbidden, "Verify your email to enable email notifications")
 }
 
+func TestNotificationHandler_UpdateSettings_InvalidEmailFormat(t *testing.T) {
+	var gotFormat string
+	handler := NewNotificationHandler(&mockNotificationService{
+		UpdateSettingsFunc: func(ctx context.Context, gotUserID uuid.UUID, patch models.NotificationSettingsPatch) (*models.NotificationSettings, error) {
+			gotFormat = *patch.EmailFormat
+			return nil, services.ErrInvalidEmailFormat
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/notifications/settings", bytes.NewBufferString(`{"email_format":"rtf"}`))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+
+	handler.UpdateSettings(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Email format must be html or text")
+	if gotFormat != "rtf" {
+		t.Fatalf("expected email_format passed through, got %q", gotFormat)
+	}
+}
+
 func TestNotificationHandler_List_SuccessAndInternalError(t *testing.T) {
 	userID := uuid.New()
 	handler := NewNotificationHandler(&mockNotificationService{
-		ListFunc: func(ctx context.Context, gotUserID uuid.UUID, params services.NotificationListParams) ([]models.Notification, error) {
-			return []models.Notification{{ID: uuid.New(), UserID: gotUserID, Type: models.NotificationTypeFriendNewCard, CreatedAt: time.Now()}}, nil
+		ListFunc: func(ctx context.Context, gotUserID uuid.UUID, params services.NotificationListParams) ([]models.Notification, bool, error) {
+			return []models.Notification{{ID: uuid.New(), UserID: gotUserID, Type: models.NotificationTypeFriendNewCard, CreatedAt: time.Now()}}, false, nil
 		},
 	})
 
@@ -313,8 +323,8 @@ func TestNotificationHandler_List_SuccessAndInternalError(t *testing.T) {
 	}
 
 	handler = NewNotificationHandler(&mockNotificationService{
-		ListFunc: func(ctx context.Context, gotUserID uuid.UUID, params services.NotificationListParams) ([]models.Notification, error) {
-			return nil, errors.New("boom")
+		ListFunc: func(ctx context.Context, gotUserID uuid.UUID, params services.NotificationListParams) ([]models.Notification, bool, error) {
+			return nil, false, errors.New("boom")
 		},
 	})
 	rr = httptest.NewRecorder()
@@ -322,6 +332,72 @@ func TestNotificationHandler_List_SuccessAndInternalError(t *testing.T) {
 	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
 }
 
+func TestNotificationHandler_List_PagesWithCursor(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	last := models.Notification{ID: uuid.New(), UserID: user.ID, CreatedAt: time.Date(2026, 3, 9, 14, 30, 0, 0, time.UTC)}
+	var got services.NotificationListParams
+	handler := NewNotificationHandler(&mockNotificationService{
+		ListFunc: func(ctx context.Context, userID uuid.UUID, params services.NotificationListParams) ([]models.Notification, bool, error) {
+			got = params
+			if params.After == nil {
+				return []models.Notification{{ID: uuid.New(), CreatedAt: last.CreatedAt.Add(time.Minute)}, last}, true, nil
+			}
+			return []models.Notification{{ID: uuid.New(), CreatedAt: last.CreatedAt}}, false, nil
+		},
+	})
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/notifications?"+query, nil)
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.List(rr, req)
+		return rr
+	}
+
+	// Only the signed cursor moves the page; a raw before parameter is ignored.
+	rr := list("limit=2&before=2020-01-01T00:00:00Z")
+	var first NotificationListResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &first); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if rr.Code != http.StatusOK || got.After != nil || len(first.Items) != 2 || !first.HasMore || first.NextCursor == "" {
+		t.Fatalf("expected a first page with a next cursor, got %d %+v", rr.Code, first)
+	}
+
+	rr = list("limit=2&cursor=" + first.NextCursor)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if got.After == nil || !got.After.At.Equal(last.CreatedAt) || got.After.ID != last.ID {
+		t.Fatalf("expected the page to start after the last notification, got %+v", got)
+	}
+	var second NotificationListResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &second); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if second.HasMore || second.NextCursor != "" {
+		t.Fatalf("expected no cursor on the last page, got %+v", second.Meta)
+	}
+
+	// Cursors are signed with the server's codec and only work on their list.
+	other := NewNotificationHandler(&mockNotificationService{})
+	other.SetCursorCodec(pagination.NewCodec("another server"))
+	req := httptest.NewRequest(http.MethodGet, "/api/notifications?cursor="+first.NextCursor, nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr = httptest.NewRecorder()
+	other.List(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid cursor")
+
+	historyCursor := defaultCursorCodec.EncodeKey(cursorScopeReminderHistory, pagination.Key{At: last.CreatedAt, ID: last.ID})
+	assertErrorResponse(t, list("cursor="+historyCursor), http.StatusBadRequest, "Invalid cursor")
+	assertErrorResponse(t, list("limit=0"), http.StatusBadRequest, "Invalid limit")
+
+	// Limits past the maximum are clamped.
+	list("limit=1000")
+	if got.Limit != pagination.MaxLimit {
+		t.Fatalf("expected the limit clamped to %d, got %d", pagination.MaxLimit, got.Limit)
+	}
+}
+
 func TestNotificationHandler_MarkRead_SuccessAndInternalError(t *testing.T) {
 	userID := uuid.New()
 	notificationID := uuid.New()
@@ -419,3 +495,156 @@ func TestNotificationHandler_OtherInternalErrors(t *testing.T) {
 	handler.UpdateSettings(rr, req)
 	assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
 }
+
+func TestNotificationHandler_SetEmailPause(t *testing.T) {
+	userID := uuid.New()
+	until := time.Date(2030, time.July, 1, 0, 0, 0, 0, time.UTC)
+	var gotUntil *time.Time
+	handler := NewNotificationHandler(&mockNotificationService{
+		SetEmailPauseFunc: func(ctx context.Context, gotUserID uuid.UUID, value *time.Time) (*models.NotificationSettings, error) {
+			if gotUserID != userID {
+				t.Fatalf("expected userID %v, got %v", userID, gotUserID)
+			}
+			gotUntil = value
+			return &models.NotificationSettings{UserID: userID, EmailPausedUntil: value}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/notifications/pause", bytes.NewBufferString(`{"until":"2030-07-01T00:00:00Z"}`))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	rr := httptest.NewRecorder()
+
+	handler.SetEmailPause(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if gotUntil == nil || !gotUntil.Equal(until) {
+		t.Fatalf("expected pause until %v, got %v", until, gotUntil)
+	}
+	var resp NotificationSettingsResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Settings.EmailPausedUntil == nil || !resp.Settings.EmailPausedUntil.Equal(until) {
+		t.Fatalf("expected settings to include pause, got %v", resp.Settings.EmailPausedUntil)
+	}
+}
+
+func TestNotificationHandler_SetEmailPause_Errors(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("requires auth", func(t *testing.T) {
+		handler := NewNotificationHandler(&mockNotificationService{})
+		req := httptest.NewRequest(http.MethodPut, "/api/notifications/pause", bytes.NewBufferString(`{"until":null}`))
+		rr := httptest.NewRecorder()
+		handler.SetEmailPause(rr, req)
+		assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		handler := NewNotificationHandler(&mockNotificationService{})
+		req := httptest.NewRequest(http.MethodPut, "/api/notifications/pause", bytes.NewBufferString(`{"until":"tomorrow"}`))
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+		rr := httptest.NewRecorder()
+		handler.SetEmailPause(rr, req)
+		assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid request body")
+	})
+
+	t.Run("invalid pause", func(t *testing.T) {
+		handler := NewNotificationHandler(&mockNotificationService{
+			SetEmailPauseFunc: func(ctx context.Context, userID uuid.UUID, until *time.Time) (*models.NotificationSettings, error) {
+				return nil, services.ErrInvalidEmailPause
+			},
+		})
+		req := httptest.NewRequest(http.MethodPut, "/api/notifications/pause", bytes.NewBufferString(`{"until":"2001-01-01T00:00:00Z"}`))
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+		rr := httptest.NewRecorder()
+		handler.SetEmailPause(rr, req)
+		assertErrorResponse(t, rr, http.StatusBadRequest, "Pause must end in the future and within one year")
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		handler := NewNotificationHandler(&mockNotificationService{
+			SetEmailPauseFunc: func(ctx context.Context, userID uuid.UUID, until *time.Time) (*models.NotificationSettings, error) {
+				return nil, errors.New("db down")
+			},
+		})
+		req := httptest.NewRequest(http.MethodPut, "/api/notifications/pause", bytes.NewBufferString(`{"until":null}`))
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+		rr := httptest.NewRecorder()
+		handler.SetEmailPause(rr, req)
+		assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+	})
+}
+
+func TestNotificationHandler_CopySettings(t *testing.T) {
+	userID := uuid.New()
+	var gotFrom, gotTo models.NotificationChannel
+	handler := NewNotificationHandler(&mockNotificationService{
+		CopySettingsFunc: func(ctx context.Context, gotUserID uuid.UUID, from, to models.NotificationChannel) (*models.NotificationSettings, error) {
+			if gotUserID != userID {
+				t.Fatalf("expected userID %v, got %v", userID, gotUserID)
+			}
+			gotFrom, gotTo = from, to
+			return &models.NotificationSettings{UserID: userID, EmailEnabled: true, EmailFriendBingo: true}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/notifications/settings/copy", bytes.NewBufferString(`{"from":"in_app","to":"email"}`))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	rr := httptest.NewRecorder()
+
+	handler.CopySettings(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if gotFrom != models.NotificationChannelInApp || gotTo != models.NotificationChannelEmail {
+		t.Fatalf("expected in_app to email, got %q to %q", gotFrom, gotTo)
+	}
+	var resp NotificationSettingsResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Settings == nil || !resp.Settings.EmailFriendBingo {
+		t.Fatalf("expected the copied settings, got %+v", resp.Settings)
+	}
+}
+
+func TestNotificationHandler_CopySettings_Errors(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		err     error
+		status  int
+		message string
+	}{
+		{"invalid body", `{`, nil, http.StatusBadRequest, "Invalid request body"},
+		{"unknown channel", `{"from":"in_app","to":"push"}`, services.ErrInvalidNotificationChannel, http.StatusBadRequest, "Channels must be in_app or email"},
+		{"channel off", `{"from":"in_app","to":"email"}`, services.ErrNotificationChannelDisabled, http.StatusConflict, "Turn on the channel before copying settings to it"},
+		{"service error", `{"from":"in_app","to":"email"}`, errors.New("boom"), http.StatusInternalServerError, "Internal server error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewNotificationHandler(&mockNotificationService{
+				CopySettingsFunc: func(ctx context.Context, userID uuid.UUID, from, to models.NotificationChannel) (*models.NotificationSettings, error) {
+					return nil, tc.err
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/notifications/settings/copy", bytes.NewBufferString(tc.body))
+			req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
+			rr := httptest.NewRecorder()
+
+			handler.CopySettings(rr, req)
+			assertErrorResponse(t, rr, tc.status, tc.message)
+		})
+	}
+
+	t.Run("requires auth", func(t *testing.T) {
+		handler := NewNotificationHandler(&mockNotificationService{})
+		req := httptest.NewRequest(http.MethodPost, "/api/notifications/settings/copy", bytes.NewBufferString(`{}`))
+		rr := httptest.NewRecorder()
+
+		handler.CopySettings(rr, req)
+		assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+	})
+}
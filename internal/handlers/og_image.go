@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
@@ -12,15 +13,22 @@ type OGImageHandler struct {
 	once     sync.Once
 	pngBytes []byte
 	err      error
+	brand    config.BrandingConfig
 }
 
 func NewOGImageHandler() *OGImageHandler {
 	return &OGImageHandler{}
 }
 
+// SetBranding titles the default preview card with the deployment's name.
+// It must be called before the first request.
+func (h *OGImageHandler) SetBranding(branding config.BrandingConfig) {
+	h.brand = branding
+}
+
 func (h *OGImageHandler) Default(w http.ResponseWriter, r *http.Request) {
 	h.once.Do(func() {
-		title := "Year of Bingo"
+		title := h.brand.DisplayName()
 		freePos := 12
 		card := models.BingoCard{
 			Title:        &title,
@@ -9,12 +9,14 @@ import (
 	"strings"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/assets"
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
 )
 
 type PageHandler struct {
 	templates *template.Template
 	manifest  *assets.Manifest
 	oauth     PageOAuthConfig
+	brand     config.BrandingConfig
 }
 
 type PageOAuthConfig struct {
@@ -41,6 +43,11 @@ func NewPageHandler(templatesDir string, oauth PageOAuthConfig) (*PageHandler, e
 	}, nil
 }
 
+// SetBranding applies the deployment's name, logo and color to pages.
+func (h *PageHandler) SetBranding(branding config.BrandingConfig) {
+	h.brand = branding
+}
+
 type PageData struct {
 	Title               string
 	HideHeader          bool
@@ -53,13 +60,14 @@ type PageData struct {
 	AppJSPath           string
 	AIWizardJSPath      string
 	GoogleOAuthEnabled  bool
+	Brand               BrandData
 }
 
 func (h *PageHandler) Index(w http.ResponseWriter, r *http.Request) {
 	// For a SPA, we serve the same template for all routes
 	// The JavaScript router handles the actual routing
 	data := PageData{
-		Title:               "Year of Bingo",
+		Title:               h.brand.DisplayName(),
 		BaseURL:             resolveBaseURL(r),
 		CSSPath:             h.manifest.GetCSS(),
 		APIJSPath:           h.manifest.GetAPIJS(),
@@ -67,6 +75,7 @@ func (h *PageHandler) Index(w http.ResponseWriter, r *http.Request) {
 		AppJSPath:           h.manifest.GetAppJS(),
 		AIWizardJSPath:      h.manifest.GetAIWizardJS(),
 		GoogleOAuthEnabled:  h.oauth.GoogleEnabled,
+		Brand:               newBrandData(h.brand),
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -228,7 +237,7 @@ func isAlphaNum(b byte) bool {
 func (h *PageHandler) NotFound(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(http.StatusNotFound)
-	if err := h.templates.ExecuteTemplate(w, "404.html", nil); err != nil {
+	if err := h.templates.ExecuteTemplate(w, "404.html", PageData{Brand: newBrandData(h.brand)}); err != nil {
 		http.Error(w, "Page not found", http.StatusNotFound)
 	}
 }
@@ -237,7 +246,7 @@ func (h *PageHandler) NotFound(w http.ResponseWriter, r *http.Request) {
 func (h *PageHandler) InternalError(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(http.StatusInternalServerError)
-	if err := h.templates.ExecuteTemplate(w, "500.html", nil); err != nil {
+	if err := h.templates.ExecuteTemplate(w, "500.html", PageData{Brand: newBrandData(h.brand)}); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
 )
 
 func TestPageHandler_IndexAndErrors(t *testing.T) {
@@ -60,6 +62,46 @@ func TestPageHandler_IndexAndErrors(t *testing.T) {
 	})
 }
 
+func TestPageHandler_Branding(t *testing.T) {
+	handler, err := NewPageHandler("../../web/templates", PageOAuthConfig{})
+	if err != nil {
+		t.Fatalf("failed to create page handler: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.Index(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := rr.Body.String(); !containsAll(body, []string{`<title>Year of Bingo - Goal Tracker</title>`, `class="logo-icon"`}) || strings.Contains(body, "--color-gold:") {
+		t.Fatalf("expected stock branding, got %s", body)
+	}
+
+	handler.SetBranding(config.BrandingConfig{
+		Name:         "Team <Goals>",
+		LogoURL:      "https://cdn.example.com/logo.png",
+		PrimaryColor: "#123abc",
+	})
+
+	rr = httptest.NewRecorder()
+	handler.Index(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rr.Body.String()
+	if !containsAll(body, []string{
+		`<title>Team &lt;Goals&gt; - Goal Tracker</title>`,
+		`data-brand-name="Team &lt;Goals&gt;"`,
+		`<img class="logo-image" src="https://cdn.example.com/logo.png"`,
+		`--color-gold: #123abc;`,
+	}) {
+		t.Fatalf("expected custom branding in index, got %s", body)
+	}
+	if strings.Contains(body, "Year of Bingo") {
+		t.Fatal("expected stock name to be replaced")
+	}
+
+	rr = httptest.NewRecorder()
+	handler.NotFound(rr, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	if !strings.Contains(rr.Body.String(), `<title>Page Not Found - Team &lt;Goals&gt;</title>`) {
+		t.Fatalf("expected branded 404 title, got %s", rr.Body.String())
+	}
+}
+
 func TestPageHandler_NewPageHandler_InvalidDir(t *testing.T) {
 	_, err := NewPageHandler(filepath.Join(os.TempDir(), "nope"), PageOAuthConfig{})
 	if err == nil {
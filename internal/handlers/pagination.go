@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
+)
+
+// Cursor scopes, one per paged list, so a cursor only works on the list
+// that issued it.
+const (
+	cursorScopeNotifications   = "notifications"
+	cursorScopeReminderHistory = "reminder_history"
+	cursorScopeCardSearch      = "card_search"
+	cursorScopeAdminSearch     = "admin_search"
+)
+
+// defaultCursorCodec serves handlers that were never given the server's
+// codec, such as in tests.
+var defaultCursorCodec = pagination.NewCodec("")
+
+// cursorPager is embedded by handlers with paged lists to hold the codec
+// their cursors are signed with.
+type cursorPager struct {
+	cursors *pagination.Codec
+}
+
+// SetCursorCodec signs the handler's list cursors with codec, which every
+// instance of the server shares.
+func (p *cursorPager) SetCursorCodec(codec *pagination.Codec) {
+	p.cursors = codec
+}
+
+func (p *cursorPager) codec() *pagination.Codec {
+	if p.cursors == nil {
+		return defaultCursorCodec
+	}
+	return p.cursors
+}
+
+// readCursor decodes the cursor query parameter for scope. It answers 400
+// and returns false when the cursor is invalid; without one it returns the
+// zero Cursor.
+func (p *cursorPager) readCursor(w http.ResponseWriter, r *http.Request, scope string) (pagination.Cursor, bool) {
+	raw := r.URL.Query().Get("cursor")
+	if raw == "" {
+		return pagination.Cursor{}, true
+	}
+	cursor, err := p.codec().Decode(scope, raw)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid cursor")
+		return pagination.Cursor{}, false
+	}
+	return cursor, true
+}
+
+// keysetMeta is the paging state for a keyset page whose last row is at
+// last.
+func (p *cursorPager) keysetMeta(scope string, hasMore bool, last pagination.Key) pagination.Meta {
+	if !hasMore {
+		return pagination.Meta{}
+	}
+	return pagination.Meta{NextCursor: p.codec().EncodeKey(scope, last), HasMore: true}
+}
+
+// offsetMeta is the paging state for a ranked page whose next page starts
+// at next.
+func (p *cursorPager) offsetMeta(scope string, hasMore bool, next int) pagination.Meta {
+	if !hasMore || next > pagination.MaxOffset {
+		return pagination.Meta{HasMore: hasMore}
+	}
+	return pagination.Meta{NextCursor: p.codec().EncodeOffset(scope, next), HasMore: true}
+}
+
+// readLimit parses the limit query parameter, clamped to max. It answers 400
+// and returns false for a limit that isn't a positive integer.
+func readLimit(w http.ResponseWriter, r *http.Request, def, max int) (int, bool) {
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"), def, max)
+	if errors.Is(err, pagination.ErrInvalidLimit) {
+		writeError(w, http.StatusBadRequest, "Invalid limit")
+		return 0, false
+	}
+	return limit, true
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type ProfileHandler struct {
+	profileService services.ProfileServiceInterface
+}
+
+func NewProfileHandler(profileService services.ProfileServiceInterface) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+type ProfileSettingsResponse struct {
+	Settings *models.ProfileSettings `json:"settings"`
+}
+
+type UpdateProfileSettingsRequest struct {
+	Visibility *string `json:"profile_visibility,omitempty"`
+	Indexable  *bool   `json:"profile_indexable,omitempty"`
+}
+
+func (h *ProfileHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	settings, err := h.profileService.GetSettings(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error getting profile settings: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ProfileSettingsResponse{Settings: settings})
+}
+
+func (h *ProfileHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req UpdateProfileSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	settings, err := h.profileService.UpdateSettings(r.Context(), user.ID, req.Visibility, req.Indexable)
+	if errors.Is(err, services.ErrInvalidProfileVisibility) {
+		writeError(w, http.StatusBadRequest, "profile_visibility must be \"off\" or \"public\"")
+		return
+	}
+	if err != nil {
+		log.Printf("Error updating profile settings: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ProfileSettingsResponse{Settings: settings})
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// ProfilePublicHandler serves opt-in public profiles at /u/{username} and
+// their OpenGraph images. Profiles are visible to anyone, so there is no
+// viewer-specific filtering here.
+type ProfilePublicHandler struct {
+	templates      *template.Template
+	profileService services.ProfileServiceInterface
+	brand          config.BrandingConfig
+}
+
+type ProfilePageData struct {
+	Found     bool
+	Indexable bool
+	PageTitle string
+	Username  string
+	Cards     []models.PublicProfileCard
+
+	OGTitle       string
+	OGDescription string
+	OGURL         string
+	OGImage       string
+	OGImageAlt    string
+
+	Brand BrandData
+}
+
+func NewProfilePublicHandler(templatesDir string, profileService services.ProfileServiceInterface) (*ProfilePublicHandler, error) {
+	templates, err := template.ParseFiles(filepath.Join(templatesDir, "profile.html"))
+	if err != nil {
+		return nil, err
+	}
+	return &ProfilePublicHandler{
+		templates:      templates,
+		profileService: profileService,
+	}, nil
+}
+
+// SetBranding applies the deployment's name to profile pages and images.
+func (h *ProfilePublicHandler) SetBranding(branding config.BrandingConfig) {
+	h.brand = branding
+}
+
+func (h *ProfilePublicHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.profileService.GetPublicProfile(r.Context(), r.PathValue("username"))
+	if errors.Is(err, services.ErrProfileNotFound) {
+		h.render(w, http.StatusNotFound, ProfilePageData{PageTitle: "Profile Not Found - " + h.brand.DisplayName()})
+		return
+	}
+	if err != nil {
+		w.Header().Set("Cache-Control", "no-store")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	baseURL := resolveBaseURL(r)
+	escaped := url.PathEscape(profile.Username)
+	h.render(w, http.StatusOK, ProfilePageData{
+		Found:         true,
+		Indexable:     profile.Indexable,
+		PageTitle:     profile.Username + " - " + h.brand.DisplayName(),
+		Username:      profile.Username,
+		Cards:         profile.Cards,
+		OGTitle:       profile.Username + " on " + h.brand.DisplayName(),
+		OGDescription: profileDescription(profile),
+		OGURL:         baseURL + "/u/" + escaped,
+		OGImage:       baseURL + "/og/profile/" + escaped + ".png?v=" + profileVersion(profile),
+		OGImageAlt:    "Bingo progress for " + profile.Username,
+	})
+}
+
+func (h *ProfilePublicHandler) ServeOGImage(w http.ResponseWriter, r *http.Request) {
+	username := strings.TrimSuffix(r.PathValue("username"), ".png")
+	profile, err := h.profileService.GetPublicProfile(r.Context(), username)
+	if errors.Is(err, services.ErrProfileNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	etag := `W/"` + profileVersion(profile) + `"`
+	if inm := r.Header.Get("If-None-Match"); inm != "" && strings.Contains(inm, etag) {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	pngBytes, err := services.RenderProfilePNG(*profile, h.brand.DisplayName())
+	if err != nil {
+		http.Error(w, "Failed to render image", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=300, must-revalidate")
+	w.Header().Set("ETag", etag)
+	if !profile.Indexable {
+		w.Header().Set("X-Robots-Tag", "noindex")
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pngBytes)
+}
+
+func (h *ProfilePublicHandler) render(w http.ResponseWriter, status int, data ProfilePageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if !data.Indexable {
+		w.Header().Set("X-Robots-Tag", "noindex")
+	}
+	w.WriteHeader(status)
+	data.Brand = newBrandData(h.brand)
+	_ = h.templates.ExecuteTemplate(w, "profile.html", data)
+}
+
+func profileDescription(profile *models.PublicProfile) string {
+	switch len(profile.Cards) {
+	case 0:
+		return "No cards to show yet"
+	case 1:
+		card := profile.Cards[0]
+		return fmt.Sprintf("%s: %d/%d complete", card.DisplayName(), card.CompletedItems, card.TotalItems)
+	default:
+		return fmt.Sprintf("%d bingo cards", len(profile.Cards))
+	}
+}
+
+// profileVersion changes whenever anything drawn on the profile image changes.
+func profileVersion(profile *models.PublicProfile) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", profile.Username)
+	for _, card := range profile.Cards {
+		fmt.Fprintf(h, "%s|%d|%d|%d\n", card.DisplayName(), card.CompletedItems, card.TotalItems, card.Bingos)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func newTestProfilePublicHandler(t *testing.T, svc *mockProfileService) *ProfilePublicHandler {
+	t.Helper()
+	handler, err := NewProfilePublicHandler("../../web/templates", svc)
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	return handler
+}
+
+func testPublicProfile(indexable bool) *models.PublicProfile {
+	title := "Run <everything>"
+	return &models.PublicProfile{
+		Username:  "alice",
+		Indexable: indexable,
+		Cards: []models.PublicProfileCard{
+			{Year: 2026, Title: &title, GridSize: 5, CompletedItems: 6, TotalItems: 24, Bingos: 1},
+		},
+	}
+}
+
+func TestProfilePublicHandler_Serve_NoindexByDefault(t *testing.T) {
+	var gotUsername string
+	handler := newTestProfilePublicHandler(t, &mockProfileService{
+		GetPublicProfileFunc: func(ctx context.Context, username string) (*models.PublicProfile, error) {
+			gotUsername = username
+			return testPublicProfile(false), nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/u/Alice", nil)
+	req.Host = "example.com"
+	req.SetPathValue("username", "Alice")
+	rr := httptest.NewRecorder()
+	handler.Serve(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if gotUsername != "Alice" {
+		t.Fatalf("expected username lookup, got %q", gotUsername)
+	}
+	if rr.Header().Get("X-Robots-Tag") != "noindex" {
+		t.Fatalf("expected noindex header, got %q", rr.Header().Get("X-Robots-Tag"))
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		`content="noindex,nofollow"`,
+		"Run &lt;everything&gt;",
+		"6/24 complete",
+		`/og/profile/alice.png?v=`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected body to contain %q, got %s", want, body)
+		}
+	}
+}
+
+func TestProfilePublicHandler_Serve_Indexable(t *testing.T) {
+	handler := newTestProfilePublicHandler(t, &mockProfileService{
+		GetPublicProfileFunc: func(ctx context.Context, username string) (*models.PublicProfile, error) {
+			return testPublicProfile(true), nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/u/alice", nil)
+	req.SetPathValue("username", "alice")
+	rr := httptest.NewRecorder()
+	handler.Serve(rr, req)
+
+	if rr.Header().Get("X-Robots-Tag") != "" {
+		t.Fatalf("expected no robots header, got %q", rr.Header().Get("X-Robots-Tag"))
+	}
+	if !strings.Contains(rr.Body.String(), `content="index,follow"`) {
+		t.Fatalf("expected indexable robots meta, got %s", rr.Body.String())
+	}
+}
+
+func TestProfilePublicHandler_Serve_NotFound(t *testing.T) {
+	handler := newTestProfilePublicHandler(t, &mockProfileService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/u/ghost", nil)
+	req.SetPathValue("username", "ghost")
+	rr := httptest.NewRecorder()
+	handler.Serve(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+	if rr.Header().Get("X-Robots-Tag") != "noindex" || !strings.Contains(rr.Body.String(), "Profile Not Found") {
+		t.Fatalf("expected noindex not-found page, got %s", rr.Body.String())
+	}
+}
+
+func TestProfilePublicHandler_ServeOGImage(t *testing.T) {
+	handler := newTestProfilePublicHandler(t, &mockProfileService{
+		GetPublicProfileFunc: func(ctx context.Context, username string) (*models.PublicProfile, error) {
+			if username != "alice" {
+				return nil, services.ErrProfileNotFound
+			}
+			return testPublicProfile(false), nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/og/profile/alice.png", nil)
+	req.SetPathValue("username", "alice.png")
+	rr := httptest.NewRecorder()
+	handler.ServeOGImage(rr, req)
+
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("expected png, got %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	etag := rr.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/og/profile/alice.png", nil)
+	req.SetPathValue("username", "alice.png")
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	handler.ServeOGImage(rr, req)
+	if rr.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/og/profile/bob.png", nil)
+	req.SetPathValue("username", "bob.png")
+	rr = httptest.NewRecorder()
+	handler.ServeOGImage(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestProfilePublicHandler_ServiceError(t *testing.T) {
+	handler := newTestProfilePublicHandler(t, &mockProfileService{
+		GetPublicProfileFunc: func(ctx context.Context, username string) (*models.PublicProfile, error) {
+			return nil, errors.New("boom")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/u/alice", nil)
+	req.SetPathValue("username", "alice")
+	rr := httptest.NewRecorder()
+	handler.Serve(rr, req)
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rr.Code)
+	}
+}
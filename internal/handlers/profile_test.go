@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestProfileHandler_GetSettings(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+
+	handler := NewProfileHandler(&mockProfileService{})
+	rr := httptest.NewRecorder()
+	handler.GetSettings(rr, httptest.NewRequest(http.MethodGet, "/api/profile/settings", nil))
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+
+	handler = NewProfileHandler(&mockProfileService{
+		GetSettingsFunc: func(ctx context.Context, userID uuid.UUID) (*models.ProfileSettings, error) {
+			return &models.ProfileSettings{Visibility: models.ProfileVisibilityOff, Notice: models.ProfilePublicNotice}, nil
+		},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/profile/settings", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr = httptest.NewRecorder()
+	handler.GetSettings(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp ProfileSettingsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Settings == nil || resp.Settings.Notice == "" {
+		t.Fatalf("expected settings with public notice, got %+v", resp.Settings)
+	}
+}
+
+func TestProfileHandler_UpdateSettings(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	put := func(handler *ProfileHandler, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/profile/settings", bytes.NewBufferString(body))
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.UpdateSettings(rr, req)
+		return rr
+	}
+
+	t.Run("passes fields through", func(t *testing.T) {
+		var gotVisibility *string
+		var gotIndexable *bool
+		handler := NewProfileHandler(&mockProfileService{
+			UpdateSettingsFunc: func(ctx context.Context, userID uuid.UUID, visibility *string, indexable *bool) (*models.ProfileSettings, error) {
+				gotVisibility, gotIndexable = visibility, indexable
+				return &models.ProfileSettings{Visibility: *visibility}, nil
+			},
+		})
+		rr := put(handler, `{"profile_visibility":"public"}`)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if gotVisibility == nil || *gotVisibility != "public" || gotIndexable != nil {
+			t.Fatalf("unexpected args: %v %v", gotVisibility, gotIndexable)
+		}
+	})
+
+	t.Run("invalid visibility", func(t *testing.T) {
+		handler := NewProfileHandler(&mockProfileService{
+			UpdateSettingsFunc: func(ctx context.Context, userID uuid.UUID, visibility *string, indexable *bool) (*models.ProfileSettings, error) {
+				return nil, services.ErrInvalidProfileVisibility
+			},
+		})
+		rr := put(handler, `{"profile_visibility":"friends"}`)
+		assertErrorResponse(t, rr, http.StatusBadRequest, `profile_visibility must be "off" or "public"`)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rr := put(NewProfileHandler(&mockProfileService{}), `{`)
+		assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid request body")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		handler := NewProfileHandler(&mockProfileService{
+			UpdateSettingsFunc: func(ctx context.Context, userID uuid.UUID, visibility *string, indexable *bool) (*models.ProfileSettings, error) {
+				return nil, errors.New("boom")
+			},
+		})
+		rr := put(handler, `{"profile_indexable":true}`)
+		assertErrorResponse(t, rr, http.StatusInternalServerError, "Internal server error")
+	})
+}
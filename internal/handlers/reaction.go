@@ -72,6 +72,10 @@ func (h *ReactionHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "Can only react to completed items")
 		return
 	}
+	if errors.Is(err, services.ErrItemPrivate) {
+		writeError(w, http.StatusForbidden, "Cannot react to private items")
+		return
+	}
 	if errors.Is(err, services.ErrNotFriend) {
 		writeError(w, http.StatusForbidden, "You must be friends to react")
 		return
@@ -125,6 +129,25 @@ func (h *ReactionHandler) GetReactions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = h.reactionService.CanViewReactions(r.Context(), user.ID, itemID)
+	if errors.Is(err, services.ErrItemNotFound) {
+		writeError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if errors.Is(err, services.ErrItemPrivate) {
+		writeError(w, http.StatusForbidden, "Cannot view reactions on private items")
+		return
+	}
+	if errors.Is(err, services.ErrNotFriend) {
+		writeError(w, http.StatusForbidden, "You must be friends to view reactions")
+		return
+	}
+	if err != nil {
+		log.Printf("Error checking reaction access: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
 	reactions, err := h.reactionService.GetReactionsForItem(r.Context(), itemID)
 	if err != nil {
 		log.Printf("Error getting reactions: %v", err)
@@ -267,6 +267,25 @@ func TestReactionHandler_AddReaction_SuccessAndErrors(t *testing.T) {
 		}
 	})
 
+	t.Run("item private", func(t *testing.T) {
+		mockSvc := &mockReactionService{
+			AddReactionFunc: func(ctx context.Context, userID, gotItemID uuid.UUID, emoji string) (*models.Reaction, error) {
+				return nil, services.ErrItemPrivate
+			},
+		}
+		handler := NewReactionHandler(mockSvc)
+
+		bodyBytes, _ := json.Marshal(AddReactionRequest{Emoji: "🎉"})
+		req := httptest.NewRequest(http.MethodPost, "/api/items/"+itemID.String()+"/react", bytes.NewBuffer(bodyBytes))
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+
+		handler.AddReaction(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected status 403, got %d", rr.Code)
+		}
+	})
+
 	t.Run("not friend", func(t *testing.T) {
 		mockSvc := &mockReactionService{
 			AddReactionFunc: func(ctx context.Context, userID, gotItemID uuid.UUID, emoji string) (*models.Reaction, error) {
@@ -369,6 +388,28 @@ func TestReactionHandler_GetReactions_SuccessAndError(t *testing.T) {
 			t.Fatalf("expected status 500, got %d", rr.Code)
 		}
 	})
+
+	t.Run("not friend", func(t *testing.T) {
+		mockSvc := &mockReactionService{
+			CanViewReactionsFunc: func(ctx context.Context, userID, gotItemID uuid.UUID) error {
+				return services.ErrNotFriend
+			},
+			GetReactionsForItemFunc: func(ctx context.Context, gotItemID uuid.UUID) ([]models.ReactionWithUser, error) {
+				t.Fatal("reactions should not be loaded for a non-friend")
+				return nil, nil
+			},
+		}
+		handler := NewReactionHandler(mockSvc)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/items/"+itemID.String()+"/reactions", nil)
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+
+		handler.GetReactions(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected status 403, got %d", rr.Code)
+		}
+	})
 }
 
 func TestReactionHandler_RemoveReaction_SuccessAndErrors(t *testing.T) {
@@ -5,14 +5,18 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
 type ReminderHandler struct {
+	cursorPager
 	reminderService services.ReminderServiceInterface
 }
 
@@ -44,6 +48,19 @@ type ReminderMessageResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+type ReminderImageTokensRevokedResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+type ReminderDeliverabilityResponse struct {
+	Check *models.DeliverabilityCheck `json:"check"`
+}
+
+type ReminderDeliverabilityLimitResponse struct {
+	Error   string     `json:"error"`
+	RetryAt *time.Time `json:"retry_at,omitempty"`
+}
+
 type ReminderTestRequest struct {
 	CardID uuid.UUID `json:"card_id"`
 }
@@ -83,6 +100,22 @@ func (h *ReminderHandler) UpdateSettings(w http.ResponseWriter, r *http.Request)
 		writeError(w, http.StatusForbidden, "Verify your email to enable reminder emails")
 		return
 	}
+	if errors.Is(err, services.ErrInvalidImageTokenMode) {
+		writeError(w, http.StatusBadRequest, "image_token_mode must be reuse or per_email")
+		return
+	}
+	if errors.Is(err, services.ErrInvalidImageTheme) {
+		writeError(w, http.StatusBadRequest, "image_theme must be light or dark")
+		return
+	}
+	if errors.Is(err, services.ErrInvalidTimezone) {
+		writeError(w, http.StatusBadRequest, "timezone must be an IANA zone name such as America/New_York")
+		return
+	}
+	if errors.Is(err, services.ErrInvalidDigestTime) {
+		writeError(w, http.StatusBadRequest, "digest_time must be HH:MM")
+		return
+	}
 	if err != nil {
 		log.Printf("Error updating reminder settings: %v", err)
 		writeError(w, http.StatusInternalServerError, "Internal server error")
@@ -177,6 +210,25 @@ func (h *ReminderHandler) DeleteCardCheckin(w http.ResponseWriter, r *http.Reque
 	writeJSON(w, http.StatusOK, ReminderMessageResponse{Message: "Reminder deleted"})
 }
 
+// RevokeImageTokens invalidates every card image link in the user's past
+// reminder emails.
+func (h *ReminderHandler) RevokeImageTokens(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	revoked, err := h.reminderService.RevokeImageTokens(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error revoking reminder image tokens: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ReminderImageTokensRevokedResponse{Revoked: revoked})
+}
+
 func (h *ReminderHandler) ListGoals(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
@@ -247,6 +299,46 @@ func (h *ReminderHandler) UpsertGoalReminder(w http.ResponseWriter, r *http.Requ
 	writeJSON(w, http.StatusOK, ReminderGoalResponse{Reminder: reminder})
 }
 
+// BulkUpsertGoalReminders sets one reminder on every open goal of a card.
+func (h *ReminderHandler) BulkUpsertGoalReminders(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var input models.GoalReminderBulkInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if input.CardID == uuid.Nil {
+		writeError(w, http.StatusBadRequest, "Invalid card ID")
+		return
+	}
+
+	result, err := h.reminderService.BulkUpsertGoalReminders(r.Context(), user.ID, input)
+	if errors.Is(err, services.ErrInvalidSchedule) {
+		writeError(w, http.StatusBadRequest, "Invalid reminder schedule")
+		return
+	}
+	if errors.Is(err, services.ErrCardNotFound) {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+	if errors.Is(err, services.ErrCardNotEligible) {
+		writeError(w, http.StatusBadRequest, "Card must be finalized and not archived")
+		return
+	}
+	if err != nil {
+		log.Printf("Error bulk updating goal reminders: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
 func (h *ReminderHandler) DeleteGoalReminder(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
@@ -273,6 +365,32 @@ func (h *ReminderHandler) DeleteGoalReminder(w http.ResponseWriter, r *http.Requ
 	writeJSON(w, http.StatusOK, ReminderMessageResponse{Message: "Reminder deleted"})
 }
 
+// ValidateSchedule previews a check-in or goal reminder schedule for the
+// settings UI without saving it. Invalid schedules are a 200 with valid false
+// and an error_code.
+func (h *ReminderHandler) ValidateSchedule(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var input models.ReminderScheduleValidationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.reminderService.ValidateSchedule(r.Context(), user.ID, input)
+	if err != nil {
+		log.Printf("Error validating reminder schedule: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
 func (h *ReminderHandler) SendTest(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
@@ -310,3 +428,87 @@ func (h *ReminderHandler) SendTest(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, ReminderMessageResponse{Message: "Test email sent"})
 }
+
+// GetDeliverability returns the latest probe result and the reminder
+// deliverability checklist.
+func (h *ReminderHandler) GetDeliverability(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	check, err := h.reminderService.GetDeliverabilityCheck(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error loading deliverability check: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ReminderDeliverabilityResponse{Check: check})
+}
+
+// History returns a page of the user's reminder sends from the last 90 days
+// and their link click-through.
+func (h *ReminderHandler) History(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	limit, ok := readLimit(w, r, pagination.DefaultLimit, pagination.MaxLimit)
+	if !ok {
+		return
+	}
+	cursor, ok := h.readCursor(w, r, cursorScopeReminderHistory)
+	if !ok {
+		return
+	}
+
+	params := services.ReminderHistoryParams{Limit: limit, After: cursor.Key}
+
+	history, err := h.reminderService.GetEmailHistory(r.Context(), user.ID, params)
+	if err != nil {
+		log.Printf("Error loading reminder history: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if n := len(history.Items); n > 0 {
+		last := history.Items[n-1]
+		history.Meta = h.keysetMeta(cursorScopeReminderHistory, history.HasMore, pagination.Key{At: last.SentAt, ID: last.ID})
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+// RunDeliverability sends a probe email, limited to one per hour.
+func (h *ReminderHandler) RunDeliverability(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	check, err := h.reminderService.RunDeliverabilityCheck(r.Context(), user.ID)
+	if errors.Is(err, services.ErrEmailNotVerified) {
+		writeError(w, http.StatusForbidden, "Verify your email before running a deliverability check")
+		return
+	} else if errors.Is(err, services.ErrDeliverabilityCheckLimited) {
+		resp := ReminderDeliverabilityLimitResponse{Error: "You can run a deliverability check once per hour"}
+		if current, err := h.reminderService.GetDeliverabilityCheck(r.Context(), user.ID); err == nil && current.NextCheckAt != nil {
+			resp.RetryAt = current.NextCheckAt
+			if wait := int(time.Until(*current.NextCheckAt).Seconds()); wait > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(wait))
+			}
+		}
+		writeJSON(w, http.StatusTooManyRequests, resp)
+		return
+	} else if err != nil {
+		log.Printf("Error running deliverability check: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ReminderDeliverabilityResponse{Check: check})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type ReminderCalendarFeedResponse struct {
+	Feed *models.ReminderCalendarFeed `json:"feed"`
+}
+
+func (h *ReminderHandler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	feed, err := h.reminderService.GetCalendarFeed(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error loading reminder calendar feed: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ReminderCalendarFeedResponse{Feed: feed})
+}
+
+// CreateCalendarFeed issues a new feed URL, replacing the previous one.
+func (h *ReminderHandler) CreateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	feed, err := h.reminderService.CreateCalendarFeed(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error creating reminder calendar feed: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, ReminderCalendarFeedResponse{Feed: feed})
+}
+
+func (h *ReminderHandler) RevokeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	if err := h.reminderService.RevokeCalendarFeed(r.Context(), user.ID); err != nil {
+		log.Printf("Error revoking reminder calendar feed: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ReminderMessageResponse{Message: "Calendar feed revoked"})
+}
+
+// CalendarFeed serves the iCalendar feed for the ?token= of a calendar feed
+// URL. Calendar apps fetch it without a session, so the token is the only
+// credential.
+func (h *ReminderHandler) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ics, err := h.reminderService.RenderCalendarFeed(r.Context(), r.URL.Query().Get("token"))
+	if errors.Is(err, services.ErrCalendarFeedNotFound) {
+		writeError(w, http.StatusNotFound, "Calendar feed not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error rendering reminder calendar feed: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="reminders.ics"`)
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(ics)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestReminderHandler_CreateCalendarFeed(t *testing.T) {
+	userID := uuid.New()
+	handler := NewReminderHandler(&mockReminderService{
+		CreateCalendarFeedFunc: func(ctx context.Context, gotUserID uuid.UUID) (*models.ReminderCalendarFeed, error) {
+			if gotUserID != userID {
+				t.Fatalf("expected userID %v, got %v", userID, gotUserID)
+			}
+			return &models.ReminderCalendarFeed{Active: true, URL: "https://example.com/api/reminders/calendar.ics?token=abc"}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reminders/calendar-feed", nil)
+	rr := httptest.NewRecorder()
+	handler.CreateCalendarFeed(rr, req)
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+
+	req = httptest.NewRequest(http.MethodPost, "/api/reminders/calendar-feed", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	rr = httptest.NewRecorder()
+	handler.CreateCalendarFeed(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", rr.Code)
+	}
+	var resp ReminderCalendarFeedResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil || resp.Feed == nil || resp.Feed.URL == "" {
+		t.Fatalf("expected the feed URL, got %s (err %v)", rr.Body.String(), err)
+	}
+}
+
+func TestReminderHandler_CalendarFeed(t *testing.T) {
+	handler := NewReminderHandler(&mockReminderService{
+		RenderCalendarFeedFunc: func(ctx context.Context, token string) ([]byte, error) {
+			if token != "abc" {
+				return nil, services.ErrCalendarFeedNotFound
+			}
+			return []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reminders/calendar.ics?token=abc", nil)
+	rr := httptest.NewRecorder()
+	handler.CalendarFeed(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+		t.Fatalf("expected text/calendar, got %q", ct)
+	}
+	if rr.Body.String() != "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n" {
+		t.Fatalf("unexpected body %q", rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/reminders/calendar.ics?token=revoked", nil)
+	rr = httptest.NewRecorder()
+	handler.CalendarFeed(rr, req)
+	assertErrorResponse(t, rr, http.StatusNotFound, "Calendar feed not found")
+}
@@ -3,20 +3,31 @@ package handlers
 import (
 	"errors"
 	"html"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
 type ReminderPublicHandler struct {
 	reminderService services.ReminderServiceInterface
+	brand           config.BrandingConfig
 }
 
 func NewReminderPublicHandler(reminderService services.ReminderServiceInterface) *ReminderPublicHandler {
 	return &ReminderPublicHandler{reminderService: reminderService}
 }
 
+// SetBranding applies the deployment's name to the unsubscribe and snooze
+// pages.
+func (h *ReminderPublicHandler) SetBranding(branding config.BrandingConfig) {
+	h.brand = branding
+}
+
 func (h *ReminderPublicHandler) ServeImage(w http.ResponseWriter, r *http.Request) {
 	token := r.PathValue("token")
 	if token == "" {
@@ -24,8 +35,13 @@ func (h *ReminderPublicHandler) ServeImage(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	token = strings.TrimSuffix(token, ".png")
+	theme, ok := imageThemeParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "theme must be light or dark")
+		return
+	}
 
-	pngBytes, err := h.reminderService.RenderImageByToken(r.Context(), token)
+	pngBytes, err := h.reminderService.RenderImageByToken(r.Context(), token, theme)
 	if errors.Is(err, services.ErrReminderNotFound) {
 		writeError(w, http.StatusNotFound, "Image not found")
 		return
@@ -41,6 +57,31 @@ func (h *ReminderPublicHandler) ServeImage(w http.ResponseWriter, r *http.Reques
 	_, _ = w.Write(pngBytes)
 }
 
+// FollowLink records a click on a reminder email's card link and redirects to
+// the card. Unknown or cleaned-up tokens land on the home page instead of an
+// error, since the email itself was genuine.
+func (h *ReminderPublicHandler) FollowLink(w http.ResponseWriter, r *http.Request) {
+	target := "/"
+	if token := r.PathValue("token"); token != "" {
+		url, err := h.reminderService.FollowReminderLink(r.Context(), token)
+		switch {
+		case err == nil:
+			target = url
+		case !errors.Is(err, services.ErrReminderNotFound):
+			log.Printf("Error following reminder link: %v", err)
+		}
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// unsubscribeListFriendsDigest marks unsubscribe links sent with the weekly
+// friends digest. It only changes the page copy; the token scope decides what
+// is disabled.
+const unsubscribeListFriendsDigest = "friends_digest"
+
 func (h *ReminderPublicHandler) UnsubscribeConfirm(w http.ResponseWriter, r *http.Request) {
 	token := r.URL.Query().Get("token")
 	if token == "" {
@@ -52,24 +93,34 @@ func (h *ReminderPublicHandler) UnsubscribeConfirm(w http.ResponseWriter, r *htt
 	w.Header().Set("Cache-Control", "no-store")
 	w.WriteHeader(http.StatusOK)
 
+	question := "Disable reminder emails for your account?"
+	button := "Disable reminders"
+	listField := ""
+	if r.URL.Query().Get("list") == unsubscribeListFriendsDigest {
+		question = "Stop the weekly friends activity email?"
+		button = "Stop digest"
+		listField = `
+        <input type="hidden" name="list" value="` + unsubscribeListFriendsDigest + `">`
+	}
+
 	escaped := html.EscapeString(token)
 	_, _ = w.Write([]byte(`<!DOCTYPE html>
 <html>
 <head>
   <meta charset="utf-8">
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
-  <title>Unsubscribe</title>
+  <title>Unsubscribe - ` + html.EscapeString(h.brand.DisplayName()) + `</title>
   <link rel="stylesheet" href="/static/css/styles.css">
 </head>
 <body>
   <main class="container main-content">
     <div class="card">
       <h2>Unsubscribe</h2>
-      <p>Disable reminder emails for your account?</p>
+      <p>` + question + `</p>
       <form method="POST" action="/r/unsubscribe">
-        <input type="hidden" name="token" value="` + escaped + `">
+        <input type="hidden" name="token" value="` + escaped + `">` + listField + `
         <div class="profile-actions">
-          <button type="submit" class="btn btn-danger-outline">Disable reminders</button>
+          <button type="submit" class="btn btn-danger-outline">` + button + `</button>
           <a class="btn btn-ghost" href="/">Cancel</a>
         </div>
       </form>
@@ -104,6 +155,12 @@ func (h *ReminderPublicHandler) UnsubscribeSubmit(w http.ResponseWriter, r *http
 	if alreadyDisabled {
 		status = "Reminders were already disabled"
 	}
+	if r.Form.Get("list") == unsubscribeListFriendsDigest {
+		status = "Friends activity digest disabled"
+		if alreadyDisabled {
+			status = "Friends activity digest was already disabled"
+		}
+	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Header().Set("Cache-Control", "no-store")
@@ -113,7 +170,7 @@ func (h *ReminderPublicHandler) UnsubscribeSubmit(w http.ResponseWriter, r *http
 <head>
   <meta charset="utf-8">
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
-  <title>Unsubscribed</title>
+  <title>Unsubscribed - ` + html.EscapeString(h.brand.DisplayName()) + `</title>
   <link rel="stylesheet" href="/static/css/styles.css">
 </head>
 <body>
@@ -129,3 +186,114 @@ func (h *ReminderPublicHandler) UnsubscribeSubmit(w http.ResponseWriter, r *http
 </body>
 </html>`))
 }
+
+// snoozeChoices labels the snooze lengths offered on the snooze page.
+var snoozeChoices = map[int]string{
+	1:  "Tomorrow",
+	7:  "In a week",
+	30: "In 30 days",
+}
+
+func (h *ReminderPublicHandler) SnoozeConfirm(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "Missing token")
+		return
+	}
+
+	var buttons strings.Builder
+	for _, days := range models.GoalReminderSnoozeDays {
+		buttons.WriteString(`
+          <button type="submit" name="days" value="` + strconv.Itoa(days) + `" class="btn btn-secondary">` + snoozeChoices[days] + `</button>`)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>Snooze reminder - ` + html.EscapeString(h.brand.DisplayName()) + `</title>
+  <link rel="stylesheet" href="/static/css/styles.css">
+</head>
+<body>
+  <main class="container main-content">
+    <div class="card">
+      <h2>Snooze reminder</h2>
+      <p>When should we remind you about this goal again?</p>
+      <form method="POST" action="/r/snooze">
+        <input type="hidden" name="token" value="` + html.EscapeString(token) + `">
+        <div class="profile-actions">` + buttons.String() + `
+          <a class="btn btn-ghost" href="/">Cancel</a>
+        </div>
+      </form>
+    </div>
+  </main>
+</body>
+</html>`))
+}
+
+func (h *ReminderPublicHandler) SnoozeSubmit(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid form")
+		return
+	}
+	token := r.Form.Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "Missing token")
+		return
+	}
+	days, err := strconv.Atoi(r.Form.Get("days"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid snooze length")
+		return
+	}
+
+	alreadySnoozed, err := h.reminderService.SnoozeGoalReminderByToken(r.Context(), token, days)
+	switch {
+	case errors.Is(err, services.ErrInvalidSnoozeDays):
+		writeError(w, http.StatusBadRequest, "Invalid snooze length")
+		return
+	case errors.Is(err, services.ErrReminderNotFound):
+		writeError(w, http.StatusNotFound, "Snooze link expired")
+		return
+	case errors.Is(err, services.ErrGoalCompleted):
+		writeError(w, http.StatusConflict, "Goal already completed")
+		return
+	case err != nil:
+		log.Printf("Error snoozing goal reminder: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	status := "We'll remind you about this goal again " + strings.ToLower(snoozeChoices[days]) + "."
+	if alreadySnoozed {
+		status = "This reminder was already snoozed."
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>Reminder snoozed - ` + html.EscapeString(h.brand.DisplayName()) + `</title>
+  <link rel="stylesheet" href="/static/css/styles.css">
+</head>
+<body>
+  <main class="container main-content">
+    <div class="card">
+      <h2>Reminder snoozed</h2>
+      <p>` + html.EscapeString(status) + `</p>
+      <div class="profile-actions">
+        <a class="btn btn-secondary" href="/profile">Manage reminders</a>
+      </div>
+    </div>
+  </main>
+</body>
+</html>`))
+}
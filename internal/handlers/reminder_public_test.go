@@ -3,12 +3,14 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
@@ -129,10 +131,13 @@ func TestReminderPublicHandler_UnsubscribeSubmit_AlreadyDisabled(t *testing.T) {
 func TestReminderPublicHandler_ServeImage_TrimsPngSuffixAndWritesHeaders(t *testing.T) {
 	pngBytes := []byte{0x89, 0x50, 0x4E, 0x47}
 	handler := NewReminderPublicHandler(&mockReminderService{
-		RenderImageByTokenFunc: func(ctx context.Context, token string) ([]byte, error) {
+		RenderImageByTokenFunc: func(ctx context.Context, token, theme string) ([]byte, error) {
 			if token != "abc123" {
 				t.Fatalf("expected token abc123, got %q", token)
 			}
+			if theme != models.ImageThemeLight {
+				t.Fatalf("expected the light theme by default, got %q", theme)
+			}
 			return pngBytes, nil
 		},
 	})
@@ -156,9 +161,33 @@ func TestReminderPublicHandler_ServeImage_TrimsPngSuffixAndWritesHeaders(t *test
 	}
 }
 
+func TestReminderPublicHandler_ServeImage_Theme(t *testing.T) {
+	var gotTheme string
+	handler := NewReminderPublicHandler(&mockReminderService{
+		RenderImageByTokenFunc: func(ctx context.Context, token, theme string) ([]byte, error) {
+			gotTheme = theme
+			return []byte{0x89}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/r/img/abc.png?theme=dark", nil)
+	req.SetPathValue("token", "abc.png")
+	rr := httptest.NewRecorder()
+	handler.ServeImage(rr, req)
+	if rr.Code != http.StatusOK || gotTheme != models.ImageThemeDark {
+		t.Fatalf("expected a dark render, got status %d and theme %q", rr.Code, gotTheme)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/r/img/abc.png?theme=sepia", nil)
+	req.SetPathValue("token", "abc.png")
+	rr = httptest.NewRecorder()
+	handler.ServeImage(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "theme must be light or dark")
+}
+
 func TestReminderPublicHandler_ServeImage_NotFound(t *testing.T) {
 	handler := NewReminderPublicHandler(&mockReminderService{
-		RenderImageByTokenFunc: func(ctx context.Context, token string) ([]byte, error) {
+		RenderImageByTokenFunc: func(ctx context.Context, token, theme string) ([]byte, error) {
 			return nil, services.ErrReminderNotFound
 		},
 	})
@@ -169,3 +198,138 @@ func TestReminderPublicHandler_ServeImage_NotFound(t *testing.T) {
 	handler.ServeImage(rr, req)
 	assertErrorResponse(t, rr, http.StatusNotFound, "Image not found")
 }
+
+func TestReminderPublicHandler_Unsubscribe_FriendsDigestCopy(t *testing.T) {
+	handler := NewReminderPublicHandler(&mockReminderService{
+		UnsubscribeByTokenFunc: func(ctx context.Context, token string) (bool, error) {
+			return false, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/r/unsubscribe?token=abc&list=friends_digest", nil)
+	rr := httptest.NewRecorder()
+	handler.UnsubscribeConfirm(rr, req)
+	body := rr.Body.String()
+	if !strings.Contains(body, "Stop the weekly friends activity email?") {
+		t.Fatalf("expected digest question, got %q", body)
+	}
+	if !strings.Contains(body, `name="list" value="friends_digest"`) {
+		t.Fatalf("expected list field, got %q", body)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/r/unsubscribe", strings.NewReader("token=abc&list=friends_digest"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr = httptest.NewRecorder()
+	handler.UnsubscribeSubmit(rr, req)
+	if !strings.Contains(rr.Body.String(), "Friends activity digest disabled") {
+		t.Fatalf("expected digest status, got %q", rr.Body.String())
+	}
+}
+
+func TestReminderPublicHandler_FollowLink(t *testing.T) {
+	cases := []struct {
+		name     string
+		url      string
+		err      error
+		wantDest string
+	}{
+		{name: "known token", url: "https://example.com/card/abc", wantDest: "https://example.com/card/abc"},
+		{name: "unknown token", err: services.ErrReminderNotFound, wantDest: "/"},
+		{name: "lookup failure", err: errors.New("db down"), wantDest: "/"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewReminderPublicHandler(&mockReminderService{
+				FollowReminderLinkFunc: func(ctx context.Context, token string) (string, error) {
+					if token != "tok" {
+						t.Fatalf("expected token tok, got %q", token)
+					}
+					return tc.url, tc.err
+				},
+			})
+			req := httptest.NewRequest(http.MethodGet, "/r/go/tok", nil)
+			req.SetPathValue("token", "tok")
+			rr := httptest.NewRecorder()
+
+			handler.FollowLink(rr, req)
+			if rr.Code != http.StatusFound {
+				t.Fatalf("expected status 302, got %d", rr.Code)
+			}
+			if got := rr.Header().Get("Location"); got != tc.wantDest {
+				t.Fatalf("expected redirect to %q, got %q", tc.wantDest, got)
+			}
+			if rr.Header().Get("Cache-Control") != "no-store" {
+				t.Fatalf("expected no-store, got %q", rr.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+func TestReminderPublicHandler_SnoozeConfirm_OffersLengths(t *testing.T) {
+	handler := NewReminderPublicHandler(&mockReminderService{})
+	req := httptest.NewRequest(http.MethodGet, "/r/snooze?token=%22%3Cx%3E", nil)
+	rr := httptest.NewRecorder()
+
+	handler.SnoozeConfirm(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{`value="&#34;&lt;x&gt;"`, `name="days" value="1"`, `name="days" value="7"`, `name="days" value="30"`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in page, got %q", want, body)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	handler.SnoozeConfirm(rr, httptest.NewRequest(http.MethodGet, "/r/snooze", nil))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Missing token")
+}
+
+func TestReminderPublicHandler_SnoozeSubmit(t *testing.T) {
+	cases := []struct {
+		name       string
+		form       string
+		already    bool
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "snoozed", form: "token=abc&days=7", wantStatus: http.StatusOK, wantBody: "again in a week"},
+		{name: "double click", form: "token=abc&days=7", already: true, wantStatus: http.StatusOK, wantBody: "already snoozed"},
+		{name: "missing token", form: "days=7", wantStatus: http.StatusBadRequest, wantBody: "Missing token"},
+		{name: "bad length", form: "token=abc&days=soon", wantStatus: http.StatusBadRequest, wantBody: "Invalid snooze length"},
+		{name: "unsupported length", form: "token=abc&days=3", err: services.ErrInvalidSnoozeDays, wantStatus: http.StatusBadRequest, wantBody: "Invalid snooze length"},
+		{name: "expired", form: "token=abc&days=1", err: services.ErrReminderNotFound, wantStatus: http.StatusNotFound, wantBody: "Snooze link expired"},
+		{name: "completed goal", form: "token=abc&days=1", err: services.ErrGoalCompleted, wantStatus: http.StatusConflict, wantBody: "Goal already completed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewReminderPublicHandler(&mockReminderService{
+				SnoozeByTokenFunc: func(ctx context.Context, token string, days int) (bool, error) {
+					if token != "abc" {
+						t.Fatalf("unexpected token %q", token)
+					}
+					return tc.already, tc.err
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/r/snooze", strings.NewReader(tc.form))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rr := httptest.NewRecorder()
+
+			handler.SnoozeSubmit(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, rr.Code)
+			}
+			if tc.wantStatus != http.StatusOK {
+				assertErrorResponse(t, rr, tc.wantStatus, tc.wantBody)
+				return
+			}
+			if !strings.Contains(rr.Body.String(), tc.wantBody) {
+				t.Fatalf("expected %q, got %q", tc.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
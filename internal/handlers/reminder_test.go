@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -234,6 +235,73 @@ func TestReminderHandler_UpsertGoalReminder_ErrorsAndSuccess(t *testing.T) {
 	})
 }
 
+func TestReminderHandler_BulkUpsertGoalReminders(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	body := `{"card_id":"` + cardID.String() + `","kind":"recurring","schedule":{"every_days":7,"time":"09:00"},"overwrite":true}`
+
+	t.Run("missing-card-id", func(t *testing.T) {
+		handler := NewReminderHandler(&mockReminderService{})
+		req := httptest.NewRequest(http.MethodPost, "/api/reminders/goals/bulk", bytes.NewBufferString(`{}`))
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+		rr := httptest.NewRecorder()
+
+		handler.BulkUpsertGoalReminders(rr, req)
+		assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid card ID")
+	})
+
+	errorCases := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+	}{
+		{"not-found", services.ErrCardNotFound, http.StatusNotFound, "Card not found"},
+		{"not-eligible", services.ErrCardNotEligible, http.StatusBadRequest, "Card must be finalized and not archived"},
+		{"invalid-schedule", services.ErrInvalidSchedule, http.StatusBadRequest, "Invalid reminder schedule"},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewReminderHandler(&mockReminderService{
+				BulkUpsertGoalRemindersFunc: func(ctx context.Context, gotUserID uuid.UUID, input models.GoalReminderBulkInput) (*models.GoalReminderBulkResult, error) {
+					return nil, tc.err
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/reminders/goals/bulk", bytes.NewBufferString(body))
+			req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+			rr := httptest.NewRecorder()
+
+			handler.BulkUpsertGoalReminders(rr, req)
+			assertErrorResponse(t, rr, tc.status, tc.message)
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var got models.GoalReminderBulkInput
+		created := uuid.New()
+		handler := NewReminderHandler(&mockReminderService{
+			BulkUpsertGoalRemindersFunc: func(ctx context.Context, gotUserID uuid.UUID, input models.GoalReminderBulkInput) (*models.GoalReminderBulkResult, error) {
+				got = input
+				return &models.GoalReminderBulkResult{Created: []uuid.UUID{created}, Updated: []uuid.UUID{}, SkippedItemIDs: []uuid.UUID{}}, nil
+			},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/reminders/goals/bulk", bytes.NewBufferString(body))
+		req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+		rr := httptest.NewRecorder()
+
+		handler.BulkUpsertGoalReminders(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+		if got.CardID != cardID || got.Kind != models.GoalReminderKindRecurring || !got.Overwrite || got.Schedule.EveryDays != 7 {
+			t.Fatalf("expected request passed through, got %+v", got)
+		}
+		if !strings.Contains(rr.Body.String(), created.String()) {
+			t.Fatalf("expected created reminder ID in response, got %s", rr.Body.String())
+		}
+	})
+}
+
 func TestReminderHandler_DeleteGoalReminder_InvalidID(t *testing.T) {
 	handler := NewReminderHandler(&mockReminderService{})
 	req := httptest.NewRequest(http.MethodDelete, "/api/reminders/goals/bad", nil)
@@ -614,3 +682,224 @@ func TestReminderHandler_SendTest_MappingsAndSuccess(t *testing.T) {
 		t.Fatalf("expected status 200, got %d", rr.Code)
 	}
 }
+
+func TestReminderHandler_UpdateSettings_InvalidImageTokenMode(t *testing.T) {
+	handler := NewReminderHandler(&mockReminderService{
+		UpdateSettingsFunc: func(ctx context.Context, userID uuid.UUID, patch models.ReminderSettingsPatch) (*models.ReminderSettings, error) {
+			if patch.ImageTokenMode == nil || *patch.ImageTokenMode != "forever" {
+				t.Fatalf("expected image_token_mode to be decoded, got %+v", patch)
+			}
+			return nil, services.ErrInvalidImageTokenMode
+		},
+	})
+	req := httptest.NewRequest(http.MethodPut, "/api/reminders/settings", bytes.NewBufferString(`{"image_token_mode":"forever"}`))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.UpdateSettings(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "image_token_mode must be reuse or per_email")
+}
+
+func TestReminderHandler_UpdateSettings_InvalidTimezone(t *testing.T) {
+	handler := NewReminderHandler(&mockReminderService{
+		UpdateSettingsFunc: func(ctx context.Context, userID uuid.UUID, patch models.ReminderSettingsPatch) (*models.ReminderSettings, error) {
+			if patch.Timezone == nil || *patch.Timezone != "Mars/Olympus" {
+				t.Fatalf("expected timezone to be decoded, got %+v", patch)
+			}
+			return nil, services.ErrInvalidTimezone
+		},
+	})
+	req := httptest.NewRequest(http.MethodPut, "/api/reminders/settings", bytes.NewBufferString(`{"timezone":"Mars/Olympus"}`))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.UpdateSettings(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "timezone must be an IANA zone name such as America/New_York")
+}
+
+func TestReminderHandler_RevokeImageTokens(t *testing.T) {
+	userID := uuid.New()
+	handler := NewReminderHandler(&mockReminderService{
+		RevokeImageTokensFunc: func(ctx context.Context, gotUserID uuid.UUID) (int64, error) {
+			if gotUserID != userID {
+				t.Fatalf("expected userID %v, got %v", userID, gotUserID)
+			}
+			return 3, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/reminders/image-tokens", nil)
+	rr := httptest.NewRecorder()
+	handler.RevokeImageTokens(rr, req)
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/reminders/image-tokens", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	rr = httptest.NewRecorder()
+	handler.RevokeImageTokens(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp ReminderImageTokensRevokedResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil || resp.Revoked != 3 {
+		t.Fatalf("expected 3 revoked, got %+v (err %v)", resp, err)
+	}
+}
+
+func TestReminderHandler_History(t *testing.T) {
+	userID := uuid.New()
+	var gotParams services.ReminderHistoryParams
+	handler := NewReminderHandler(&mockReminderService{
+		GetEmailHistoryFunc: func(ctx context.Context, gotUserID uuid.UUID, params services.ReminderHistoryParams) (*models.ReminderEmailHistory, error) {
+			if gotUserID != userID {
+				t.Fatalf("expected userID %v, got %v", userID, gotUserID)
+			}
+			gotParams = params
+			return &models.ReminderEmailHistory{
+				Items:        []models.ReminderHistoryEntry{{SourceType: "card_checkin", Status: "sent"}},
+				ClickThrough: &models.ReminderClickThrough{Tracked: 2, Clicked: 1, Rate: 0.5},
+			}, nil
+		},
+	})
+	withUser := func(req *http.Request) *http.Request {
+		return req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reminders/history", nil)
+	rr := httptest.NewRecorder()
+	handler.History(rr, req)
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+
+	rr = httptest.NewRecorder()
+	handler.History(rr, withUser(httptest.NewRequest(http.MethodGet, "/api/reminders/history?limit=0", nil)))
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid limit")
+
+	rr = httptest.NewRecorder()
+	handler.History(rr, withUser(httptest.NewRequest(http.MethodGet, "/api/reminders/history?limit=10&before=2026-05-01T00:00:00Z", nil)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	// A raw before parameter no longer pages; only the signed cursor does.
+	if gotParams.Limit != 10 || gotParams.After != nil {
+		t.Fatalf("unexpected params: %+v", gotParams)
+	}
+	var resp models.ReminderEmailHistory
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.ClickThrough.Rate != 0.5 || resp.HasMore {
+		t.Fatalf("unexpected history: %+v", resp)
+	}
+}
+
+func TestReminderHandler_RunDeliverability(t *testing.T) {
+	userID := uuid.New()
+	sentAt := time.Now().Add(-10 * time.Minute)
+	retryAt := sentAt.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		runErr     error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "sent", wantStatus: http.StatusOK, wantBody: `"status":"sent"`},
+		{name: "unverified", runErr: services.ErrEmailNotVerified, wantStatus: http.StatusForbidden, wantBody: "Verify your email"},
+		{name: "limited", runErr: services.ErrDeliverabilityCheckLimited, wantStatus: http.StatusTooManyRequests, wantBody: "once per hour"},
+		{name: "error", runErr: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantBody: "Internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewReminderHandler(&mockReminderService{
+				RunDeliverabilityFunc: func(ctx context.Context, gotUserID uuid.UUID) (*models.DeliverabilityCheck, error) {
+					if gotUserID != userID {
+						t.Fatalf("unexpected user %v", gotUserID)
+					}
+					if tt.runErr != nil {
+						return nil, tt.runErr
+					}
+					return &models.DeliverabilityCheck{Probe: &models.DeliverabilityProbe{Status: "sent", SentAt: sentAt}, NextCheckAt: &retryAt}, nil
+				},
+				GetDeliverabilityFunc: func(ctx context.Context, gotUserID uuid.UUID) (*models.DeliverabilityCheck, error) {
+					return &models.DeliverabilityCheck{NextCheckAt: &retryAt}, nil
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/reminders/deliverability-check", nil)
+			req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+			rr := httptest.NewRecorder()
+
+			handler.RunDeliverability(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %s", tt.wantBody, rr.Body.String())
+			}
+			if tt.wantStatus == http.StatusTooManyRequests {
+				if rr.Header().Get("Retry-After") == "" || !strings.Contains(rr.Body.String(), "retry_at") {
+					t.Fatalf("expected retry hints, headers=%v body=%s", rr.Header(), rr.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestReminderHandler_GetDeliverability(t *testing.T) {
+	userID := uuid.New()
+	handler := NewReminderHandler(&mockReminderService{
+		GetDeliverabilityFunc: func(ctx context.Context, gotUserID uuid.UUID) (*models.DeliverabilityCheck, error) {
+			return &models.DeliverabilityCheck{Checklist: []models.DeliverabilityCheckItem{{Key: "email_verified", Status: models.DeliverabilityOK}}}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reminders/deliverability-check", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	rr := httptest.NewRecorder()
+
+	handler.GetDeliverability(rr, req)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"key":"email_verified"`) {
+		t.Fatalf("unexpected response %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	handler.GetDeliverability(rr, httptest.NewRequest(http.MethodGet, "/api/reminders/deliverability-check", nil))
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+}
+
+func TestReminderHandler_ValidateSchedule(t *testing.T) {
+	userID := uuid.New()
+	next := time.Date(2026, time.March, 28, 9, 0, 0, 0, time.UTC)
+	handler := NewReminderHandler(&mockReminderService{
+		ValidateScheduleFunc: func(ctx context.Context, gotUserID uuid.UUID, input models.ReminderScheduleValidationInput) (*models.ReminderScheduleValidation, error) {
+			if gotUserID != userID || input.Type != "checkin" || string(input.Schedule) != `{"day_of_month":28,"time":"09:00"}` {
+				t.Fatalf("unexpected args user=%v input=%+v", gotUserID, input)
+			}
+			return &models.ReminderScheduleValidation{
+				Valid:              true,
+				NormalizedSchedule: input.Schedule,
+				NextSendAt:         &next,
+				Timezone:           "UTC",
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/reminders/validate-schedule", bytes.NewBufferString(`not json`))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	rr := httptest.NewRecorder()
+	handler.ValidateSchedule(rr, req)
+	assertErrorResponse(t, rr, http.StatusBadRequest, "Invalid request body")
+
+	req = httptest.NewRequest(http.MethodPost, "/api/reminders/validate-schedule", bytes.NewBufferString(`{"type":"checkin","schedule":{"day_of_month":28,"time":"09:00"}}`))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, &models.User{ID: userID}))
+	rr = httptest.NewRecorder()
+	handler.ValidateSchedule(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp["valid"] != true || resp["next_send_at"] != "2026-03-28T09:00:00Z" || resp["normalized_schedule"] == nil {
+		t.Fatalf("unexpected response %v", resp)
+	}
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -9,21 +12,40 @@ import (
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
+// shareOGImageCacheControl lets browsers and link-preview crawlers keep an
+// image as long as the server-side image cache does.
+var shareOGImageCacheControl = fmt.Sprintf("public, max-age=%d", int(services.SharedImageCacheTTL.Seconds()))
+
 type ShareOGImageHandler struct {
 	cardService services.CardServiceInterface
+	imageCache  services.SharedCardImageCacheInterface
 }
 
 func NewShareOGImageHandler(cardService services.CardServiceInterface) *ShareOGImageHandler {
 	return &ShareOGImageHandler{cardService: cardService}
 }
 
+// SetImageCache reuses rendered images for hot share links.
+func (h *ShareOGImageHandler) SetImageCache(cache services.SharedCardImageCacheInterface) {
+	h.imageCache = cache
+}
+
 func (h *ShareOGImageHandler) Serve(w http.ResponseWriter, r *http.Request) {
 	token := strings.TrimSpace(r.PathValue("token"))
+	format := r.URL.Query().Get("format")
+	if trimmed, ok := strings.CutSuffix(token, ".svg"); ok {
+		token, format = trimmed, "svg"
+	}
 	token = strings.TrimSuffix(token, ".png")
 	if token == "" || !isValidShareToken(token) {
 		http.NotFound(w, r)
 		return
 	}
+	theme, ok := imageThemeParam(r)
+	if !ok {
+		http.Error(w, "theme must be light or dark", http.StatusBadRequest)
+		return
+	}
 
 	shared, err := h.cardService.GetSharedCardByToken(r.Context(), token)
 	if err != nil {
@@ -35,38 +57,100 @@ func (h *ShareOGImageHandler) Serve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	state := shareCompletionState(shared)
-	etag := `W/"` + shareVersion(state) + `"`
+	svg := format == "svg" || (format == "" && acceptsSVGOverPNG(r))
+	etag := shareImageETag(shared)
+	if theme == models.ImageThemeDark {
+		etag = strings.TrimSuffix(etag, `"`) + `-dark"`
+	}
+	if svg {
+		etag = strings.TrimSuffix(etag, `"`) + `-svg"`
+	}
+	w.Header().Set("Vary", "Accept")
 	if inm := r.Header.Get("If-None-Match"); inm != "" && strings.Contains(inm, etag) {
 		w.Header().Set("ETag", etag)
 		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
-	pngBytes, err := renderSharedCardPNG(shared)
+	if svg {
+		card, items, opts := sharedCardImageData(shared)
+		opts.Theme = theme
+		svgBytes, err := services.RenderCardSVG(card, items, opts)
+		if err != nil {
+			http.Error(w, "Failed to render image", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Cache-Control", shareOGImageCacheControl)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("X-Robots-Tag", "noindex")
+		writeSVG(w, svgBytes)
+		return
+	}
+
+	pngBytes, err := h.sharedCardPNG(r, token, theme, shared)
 	if err != nil {
 		http.Error(w, "Failed to render image", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "image/png")
-	w.Header().Set("Cache-Control", "public, max-age=300, must-revalidate")
+	w.Header().Set("Cache-Control", shareOGImageCacheControl)
 	w.Header().Set("ETag", etag)
 	w.Header().Set("X-Robots-Tag", "noindex")
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(pngBytes)
 }
 
-func renderSharedCardPNG(shared *models.SharedCard) ([]byte, error) {
+// shareImageETag identifies everything the image is drawn from: when the
+// card's settings last changed, which cells are complete, the goal text, and
+// the share options. Goal edits don't touch the card's updated_at, so the
+// text is hashed too.
+func shareImageETag(shared *models.SharedCard) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\x00", shared.Card.UpdatedAt.UnixNano())
+	h.Write(shareCompletionState(shared))
+	for _, item := range shared.Items {
+		fmt.Fprintf(h, "\x00%d:%s", item.Position, item.Content)
+	}
+	fmt.Fprintf(h, "\x00superseded=%t", shared.Superseded)
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:8]) + `"`
+}
+
+func (h *ShareOGImageHandler) sharedCardPNG(r *http.Request, token, theme string, shared *models.SharedCard) ([]byte, error) {
+	if h.imageCache != nil {
+		if pngBytes, ok := h.imageCache.GetImage(r.Context(), token, theme, shared); ok {
+			return pngBytes, nil
+		}
+	}
+	pngBytes, err := renderSharedCardPNG(shared, theme)
+	if err != nil {
+		return nil, err
+	}
+	if h.imageCache != nil {
+		h.imageCache.PutImage(r.Context(), token, theme, shared, pngBytes)
+	}
+	return pngBytes, nil
+}
+
+func renderSharedCardPNG(shared *models.SharedCard, theme string) ([]byte, error) {
+	card, items, opts := sharedCardImageData(shared)
+	opts.Theme = theme
+	return services.RenderReminderPNG(card, items, opts)
+}
+
+// sharedCardImageData builds renderer input from a share's public view,
+// honoring its show_completions option.
+func sharedCardImageData(shared *models.SharedCard) (models.BingoCard, []models.BingoItem, services.RenderOptions) {
 	card := models.BingoCard{
-		Year:         shared.Card.Year,
-		Category:     shared.Card.Category,
-		Title:        shared.Card.Title,
-		GridSize:     shared.Card.GridSize,
-		HeaderText:   shared.Card.HeaderText,
-		HasFreeSpace: shared.Card.HasFreeSpace,
-		FreeSpacePos: shared.Card.FreeSpacePos,
-		IsFinalized:  true,
+		Year:          shared.Card.Year,
+		Category:      shared.Card.Category,
+		Title:         shared.Card.Title,
+		GridSize:      shared.Card.GridSize,
+		HeaderText:    shared.Card.HeaderText,
+		HasFreeSpace:  shared.Card.HasFreeSpace,
+		FreeSpacePos:  shared.Card.FreeSpacePos,
+		FreeSpaceText: shared.Card.FreeSpaceText,
+		IsFinalized:   true,
 	}
 	items := make([]models.BingoItem, 0, len(shared.Items))
 	for _, item := range shared.Items {
@@ -76,5 +160,8 @@ func renderSharedCardPNG(shared *models.SharedCard) ([]byte, error) {
 			IsCompleted: item.IsCompleted,
 		})
 	}
-	return services.RenderReminderPNG(card, items, services.RenderOptions{ShowCompletions: true})
+	return card, items, services.RenderOptions{
+		ShowCompletions: !shared.CompletionsHidden,
+		HideProgress:    shared.CompletionsHidden,
+	}
 }
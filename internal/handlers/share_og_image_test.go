@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
@@ -63,6 +64,9 @@ func TestShareOGImageHandler_Serve_PNGAndETag(t *testing.T) {
 	if etag := rr.Header().Get("ETag"); etag == "" {
 		t.Fatalf("expected etag to be set")
 	}
+	if cc := rr.Header().Get("Cache-Control"); cc != "public, max-age=900" {
+		t.Fatalf("expected 15 minute public caching, got %q", cc)
+	}
 	if _, err := png.Decode(bytes.NewReader(rr.Body.Bytes())); err != nil {
 		t.Fatalf("expected response body to be a valid PNG: %v", err)
 	}
@@ -77,3 +81,166 @@ func TestShareOGImageHandler_Serve_PNGAndETag(t *testing.T) {
 		t.Fatalf("expected status 304, got %d", rr2.Code)
 	}
 }
+
+func TestShareOGImageHandler_Serve_SVG(t *testing.T) {
+	token := strings.Repeat("d", 64)
+	shared := &models.SharedCard{
+		Card:  models.PublicBingoCard{Year: 2026, GridSize: 3, IsFinalized: true},
+		Items: []models.PublicBingoItem{{Position: 0, Content: "Swim", IsCompleted: true}},
+	}
+	h := NewShareOGImageHandler(&mockShareOGService{
+		GetSharedCardFunc: func(ctx context.Context, got string) (*models.SharedCard, error) {
+			return shared, nil
+		},
+	})
+	serve := func(pathToken, query, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/og/share/"+pathToken+query, nil)
+		req.SetPathValue("token", pathToken)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rr := httptest.NewRecorder()
+		h.Serve(rr, req)
+		return rr
+	}
+
+	pngETag := serve(token+".png", "", "").Header().Get("ETag")
+	for name, rr := range map[string]*httptest.ResponseRecorder{
+		"suffix": serve(token+".svg", "", ""),
+		"query":  serve(token, "?format=svg", ""),
+		"accept": serve(token, "", "image/svg+xml"),
+	} {
+		if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "image/svg+xml" {
+			t.Fatalf("%s: expected an SVG, got %d %q", name, rr.Code, rr.Header().Get("Content-Type"))
+		}
+		if etag := rr.Header().Get("ETag"); etag == "" || etag == pngETag {
+			t.Fatalf("%s: expected an ETag distinct from the PNG's, got %q", name, etag)
+		}
+		if !strings.Contains(rr.Body.String(), ">Swim</text>") {
+			t.Fatalf("%s: expected the goal text in the SVG", name)
+		}
+	}
+
+	// Browsers list SVG alongside image/* and keep getting the PNG.
+	rr := serve(token+".png", "", "image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8")
+	if rr.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("expected a PNG for a browser Accept header, got %q", rr.Header().Get("Content-Type"))
+	}
+}
+
+type mockSharedCardImageCache struct {
+	images map[string][]byte
+	puts   int
+}
+
+func (m *mockSharedCardImageCache) GetImage(ctx context.Context, token, theme string, shared *models.SharedCard) ([]byte, bool) {
+	png, ok := m.images[token+":"+theme]
+	return png, ok
+}
+
+func (m *mockSharedCardImageCache) PutImage(ctx context.Context, token, theme string, shared *models.SharedCard, png []byte) {
+	m.puts++
+	m.images[token+":"+theme] = png
+}
+
+func TestShareOGImageHandler_Serve_UsesImageCache(t *testing.T) {
+	token := strings.Repeat("d", 64)
+	shared := &models.SharedCard{
+		Card:  models.PublicBingoCard{Year: 2026, GridSize: 3, IsFinalized: true},
+		Items: []models.PublicBingoItem{{Position: 0, Content: "A"}},
+	}
+	cache := &mockSharedCardImageCache{images: map[string][]byte{}}
+	h := NewShareOGImageHandler(&mockShareOGService{
+		GetSharedCardFunc: func(ctx context.Context, got string) (*models.SharedCard, error) {
+			return shared, nil
+		},
+	})
+	h.SetImageCache(cache)
+
+	serve := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/og/share/"+token+".png"+query, nil)
+		req.SetPathValue("token", token+".png")
+		rr := httptest.NewRecorder()
+		h.Serve(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+		return rr
+	}
+
+	lightKey := token + ":" + models.ImageThemeLight
+	first := serve("")
+	if cache.puts != 1 || !bytes.Equal(cache.images[lightKey], first.Body.Bytes()) {
+		t.Fatalf("expected rendered image to be cached, puts=%d", cache.puts)
+	}
+
+	cache.images[lightKey] = []byte("cached")
+	if second := serve(""); second.Body.String() != "cached" || cache.puts != 1 {
+		t.Fatalf("expected cached image without re-rendering, got %q puts=%d", second.Body.String(), cache.puts)
+	}
+
+	dark := serve("?theme=dark")
+	if dark.Body.String() == "cached" || cache.puts != 2 || !bytes.Equal(cache.images[token+":"+models.ImageThemeDark], dark.Body.Bytes()) {
+		t.Fatalf("expected the dark theme rendered and cached separately, puts=%d", cache.puts)
+	}
+	if dark.Header().Get("ETag") == first.Header().Get("ETag") {
+		t.Fatal("expected the dark image to have its own ETag")
+	}
+}
+
+func TestShareImageETag(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	base := func() *models.SharedCard {
+		return &models.SharedCard{
+			Card: models.PublicBingoCard{Year: 2026, GridSize: 3, IsFinalized: true, UpdatedAt: updatedAt},
+			Items: []models.PublicBingoItem{
+				{Position: 0, Content: "A", IsCompleted: true},
+				{Position: 1, Content: "B"},
+			},
+		}
+	}
+
+	etag := shareImageETag(base())
+	if !strings.HasPrefix(etag, `W/"`) || etag != shareImageETag(base()) {
+		t.Fatalf("expected a stable weak etag, got %q", etag)
+	}
+
+	changes := map[string]func(*models.SharedCard){
+		"updated_at": func(s *models.SharedCard) { s.Card.UpdatedAt = updatedAt.Add(time.Second) },
+		"completion": func(s *models.SharedCard) { s.Items[1].IsCompleted = true },
+		"goal text":  func(s *models.SharedCard) { s.Items[1].Content = "C" },
+		"superseded": func(s *models.SharedCard) { s.Superseded = true },
+	}
+	for name, change := range changes {
+		shared := base()
+		change(shared)
+		if got := shareImageETag(shared); got == etag {
+			t.Errorf("expected %s change to change the etag", name)
+		}
+	}
+}
+
+func TestRenderSharedCardPNG_HiddenCompletionsNotDrawn(t *testing.T) {
+	render := func(completed, hidden bool) []byte {
+		t.Helper()
+		out, err := renderSharedCardPNG(&models.SharedCard{
+			Card:              models.PublicBingoCard{Year: 2026, GridSize: 3, IsFinalized: true},
+			Items:             []models.PublicBingoItem{{Position: 0, Content: "A", IsCompleted: completed}},
+			CompletionsHidden: hidden,
+		}, models.ImageThemeLight)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out
+	}
+
+	if !bytes.Equal(render(true, true), render(false, true)) {
+		t.Fatal("expected a share hiding completions to render the same either way")
+	}
+	if bytes.Equal(render(false, true), render(false, false)) {
+		t.Fatal("expected the progress line left out when completions are hidden")
+	}
+	if bytes.Equal(render(true, false), render(false, false)) {
+		t.Fatal("expected completions drawn when shown")
+	}
+}
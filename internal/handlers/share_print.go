@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// Printable area of a US Letter page with half-inch margins, less the title
+// block. The print template uses the same numbers.
+const (
+	sharePrintWidthIn      = 7.5
+	sharePrintGridHeightIn = 8.2
+)
+
+// sharePrintFontSizes are the cell text sizes tried, largest first.
+var sharePrintFontSizes = []int{14, 12, 11, 10, 9, 8, 7}
+
+// Completion marks that print on any printer, including black and white.
+const (
+	sharePrintMarkDone = "✔"
+	sharePrintMarkOpen = "☐"
+	sharePrintMarkFree = "★"
+)
+
+type SharePrintCell struct {
+	Content   string
+	Mark      string
+	Completed bool
+	Free      bool
+}
+
+// SharePrintPage is the part of the grid printed on one sheet.
+type SharePrintPage struct {
+	Rows [][]SharePrintCell
+}
+
+type SharePrintData struct {
+	PageTitle string
+	Title     string
+	Summary   string
+	Header    []string
+	Pages     []SharePrintPage
+	GridSize  int
+	// CellWidthIn, CellHeightIn and FontSizePt size every cell alike, so a
+	// card always prints the same way.
+	CellWidthIn  string
+	CellHeightIn string
+	FontSizePt   int
+	SharePath    string
+
+	Brand BrandData
+}
+
+// sharePrintLayout picks the cell height, font size and rows per page for a
+// grid whose longest goal has longest runes. Everything fits on one page
+// unless a 5x5 card's longest goal doesn't fit at the smallest font; that
+// card gets two pages with taller cells. Text that still doesn't fit is
+// clipped, never reflowed onto another page.
+func sharePrintLayout(gridSize, longest int) (cellHeightIn float64, fontPt int, rowsPerPage int) {
+	cellWidthIn := sharePrintWidthIn / float64(gridSize)
+	rowsPerPage = gridSize
+	cellHeightIn = min(cellWidthIn, sharePrintGridHeightIn/float64(gridSize))
+	if gridSize == models.MaxGridSize && !sharePrintFits(cellWidthIn, cellHeightIn, sharePrintFontSizes[len(sharePrintFontSizes)-1], longest) {
+		rowsPerPage = (gridSize + 1) / 2
+		cellHeightIn = sharePrintGridHeightIn / float64(rowsPerPage)
+	}
+	for _, size := range sharePrintFontSizes {
+		if sharePrintFits(cellWidthIn, cellHeightIn, size, longest) {
+			return cellHeightIn, size, rowsPerPage
+		}
+	}
+	return cellHeightIn, sharePrintFontSizes[len(sharePrintFontSizes)-1], rowsPerPage
+}
+
+// sharePrintFits estimates whether runes characters fit in a cell at fontPt,
+// allowing an average glyph of half the font size, a 1.25 line height, room
+// for the completion mark and some slack for word wrapping.
+func sharePrintFits(widthIn, heightIn float64, fontPt, runes int) bool {
+	const padIn = 0.12
+	perLine := int((widthIn - 2*padIn) * 72 / (0.5 * float64(fontPt)))
+	lines := int((heightIn-2*padIn)*72/(1.25*float64(fontPt))) - 1
+	if perLine <= 0 || lines <= 0 {
+		return false
+	}
+	return float64(runes) <= float64(perLine*lines)*0.8
+}
+
+func newSharePrintData(shared *models.SharedCard, displayName, brandName string) SharePrintData {
+	gridSize := shared.Card.GridSize
+	if !models.IsValidGridSize(gridSize) {
+		gridSize = models.MaxGridSize
+	}
+
+	byPos := make(map[int]models.PublicBingoItem, len(shared.Items))
+	longest := 0
+	for _, item := range shared.Items {
+		byPos[item.Position] = item
+		longest = max(longest, utf8.RuneCountInString(item.Content))
+	}
+
+	cells := make([]SharePrintCell, gridSize*gridSize)
+	for pos := range cells {
+		if shared.FreeSpace != nil && shared.FreeSpace.Position == pos {
+			cells[pos] = SharePrintCell{Content: shared.FreeSpace.Content, Mark: sharePrintMarkFree, Completed: true, Free: true}
+			continue
+		}
+		item, ok := byPos[pos]
+		if !ok {
+			continue
+		}
+		mark := sharePrintMarkOpen
+		switch {
+		case shared.CompletionsHidden:
+			mark = ""
+		case item.IsCompleted:
+			mark = sharePrintMarkDone
+		}
+		cells[pos] = SharePrintCell{Content: item.Content, Mark: mark, Completed: item.IsCompleted}
+	}
+
+	cellHeightIn, fontPt, rowsPerPage := sharePrintLayout(gridSize, longest)
+	var pages []SharePrintPage
+	for row := 0; row < gridSize; row += rowsPerPage {
+		var page SharePrintPage
+		for r := row; r < min(row+rowsPerPage, gridSize); r++ {
+			page.Rows = append(page.Rows, cells[r*gridSize:(r+1)*gridSize])
+		}
+		pages = append(pages, page)
+	}
+
+	letters := []rune(models.NormalizeHeaderText(shared.Card.HeaderText))
+	header := make([]string, gridSize)
+	for i := range header {
+		if i < len(letters) {
+			header[i] = string(letters[i])
+		}
+	}
+
+	summary := ""
+	if !shared.CompletionsHidden {
+		completed, total := shareCompletionStats(shared)
+		summary = fmt.Sprintf("%d of %d goals complete", completed, total)
+	}
+	return SharePrintData{
+		PageTitle:    displayName + " - " + brandName,
+		Title:        displayName,
+		Summary:      summary,
+		Header:       header,
+		Pages:        pages,
+		GridSize:     gridSize,
+		CellWidthIn:  fmt.Sprintf("%.3f", sharePrintWidthIn/float64(gridSize)),
+		CellHeightIn: fmt.Sprintf("%.3f", cellHeightIn),
+		FontSizePt:   fontPt,
+	}
+}
+
+// Print serves a print-ready version of a shared card: no navigation, a
+// high-contrast grid and completion marks that survive black-and-white
+// printing.
+func (h *SharePublicHandler) Print(w http.ResponseWriter, r *http.Request) {
+	token, shared, ok := h.loadShared(w, r)
+	if !ok {
+		return
+	}
+
+	displayName := shareCardDisplayName(shared.Card, h.brand.DisplayName())
+	data := newSharePrintData(shared, displayName, h.brand.DisplayName())
+	data.SharePath = "/share/" + token
+	data.Brand = newBrandData(h.brand)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(http.StatusOK)
+	_ = h.templates.ExecuteTemplate(w, "share_print.html", data)
+}
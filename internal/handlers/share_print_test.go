@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestSharePrintLayout(t *testing.T) {
+	for _, tc := range []struct {
+		gridSize, longest int
+		wantPages         int
+	}{
+		{3, 20, 1},
+		{3, models.MaxItemContentLength, 1},
+		{4, models.MaxItemContentLength, 1},
+		{5, 40, 1},
+		{5, models.MaxItemContentLength, 2},
+	} {
+		height, font, rowsPerPage := sharePrintLayout(tc.gridSize, tc.longest)
+		pages := (tc.gridSize + rowsPerPage - 1) / rowsPerPage
+		if pages != tc.wantPages {
+			t.Fatalf("%dx%d with %d runes: expected %d pages, got %d", tc.gridSize, tc.gridSize, tc.longest, tc.wantPages, pages)
+		}
+		if float64(rowsPerPage)*height > sharePrintGridHeightIn+0.001 || font < 7 {
+			t.Fatalf("%dx%d: layout doesn't fit the page: %d rows of %.2fin at %dpt", tc.gridSize, tc.gridSize, rowsPerPage, height, font)
+		}
+		if h2, f2, r2 := sharePrintLayout(tc.gridSize, tc.longest); h2 != height || f2 != font || r2 != rowsPerPage {
+			t.Fatal("expected the same layout for the same card")
+		}
+	}
+	_, short, _ := sharePrintLayout(3, 10)
+	_, long, _ := sharePrintLayout(3, 300)
+	if long >= short {
+		t.Fatalf("expected longer goals to print smaller, got %dpt and %dpt", short, long)
+	}
+}
+
+func TestSharePublicHandler_Print(t *testing.T) {
+	token := strings.Repeat("c", 64)
+	title := "Print <Me>"
+	items := make([]models.PublicBingoItem, 0, 25)
+	for pos := 0; pos < 25; pos++ {
+		if pos == 12 {
+			continue
+		}
+		items = append(items, models.PublicBingoItem{Position: pos, Content: "Goal", IsCompleted: pos == 0})
+	}
+	items[1].Content = models.PrivateItemPlaceholder
+	items[2].Content = strings.Repeat("long goal ", 50)
+	handler, err := NewSharePublicHandler("../../web/templates", &mockSharePublicService{
+		GetSharedCardFunc: func(ctx context.Context, got string) (*models.SharedCard, error) {
+			if got != token {
+				return nil, services.ErrShareNotFound
+			}
+			return &models.SharedCard{
+				Card:      models.PublicBingoCard{Year: 2026, Title: &title, GridSize: 5, HeaderText: "BINGO", HasFreeSpace: true, FreeSpacePos: ptrInt(12), IsFinalized: true},
+				Items:     items,
+				FreeSpace: models.NewFreeSpaceItem(true, ptrInt(12), nil),
+			}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/s/"+token+"/print", nil)
+	req.SetPathValue("token", token)
+	rr := httptest.NewRecorder()
+	handler.Print(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !containsAll(body, []string{
+		"Print &lt;Me&gt;",
+		"1 of 24 goals complete",
+		`<th scope="col">B</th>`,
+		sharePrintMarkDone,
+		sharePrintMarkOpen,
+		sharePrintMarkFree,
+		models.PrivateItemPlaceholder,
+		"/share/" + token,
+	}) {
+		t.Fatalf("expected the printable card, got %s", body)
+	}
+	if strings.Count(body, `<section class="print-page">`) != 2 {
+		t.Fatal("expected a 5x5 card with a long goal to print on two pages")
+	}
+	if strings.Contains(body, "http-equiv") || strings.Contains(body, "<nav") {
+		t.Fatal("expected no redirect or navigation on the print page")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/s/"+strings.Repeat("d", 64)+"/print", nil)
+	req.SetPathValue("token", strings.Repeat("d", 64))
+	rr = httptest.NewRecorder()
+	handler.Print(rr, req)
+	if rr.Code != http.StatusNotFound || !strings.Contains(rr.Body.String(), "Share Link Not Found") {
+		t.Fatalf("expected the share not found page, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/s/"+token, nil)
+	req.SetPathValue("token", token)
+	rr = httptest.NewRecorder()
+	handler.Serve(rr, req)
+	if !strings.Contains(rr.Body.String(), "/s/"+token+"/print") {
+		t.Fatal("expected the share page to link to the print page")
+	}
+}
+
+func TestNewSharePrintData_CompletionsHidden(t *testing.T) {
+	shared := &models.SharedCard{
+		Card:              models.PublicBingoCard{Year: 2026, GridSize: 2, HeaderText: "BI", IsFinalized: true},
+		Items:             []models.PublicBingoItem{{Position: 0, Content: "Run"}, {Position: 1, Content: "Read"}},
+		CompletionsHidden: true,
+	}
+	data := newSharePrintData(shared, "Card", "Year of Bingo")
+	if data.Summary != "" {
+		t.Fatalf("expected no progress summary, got %q", data.Summary)
+	}
+	if mark := data.Pages[0].Rows[0][0].Mark; mark != "" {
+		t.Fatalf("expected no completion marks, got %q", mark)
+	}
+}
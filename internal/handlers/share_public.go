@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
@@ -17,12 +18,19 @@ import (
 type SharePublicHandler struct {
 	templates   *template.Template
 	cardService services.CardServiceInterface
+	brand       config.BrandingConfig
 }
 
 type SharePageData struct {
-	Found        bool
+	Found bool
+	// Superseded pages come from a rotated-out token in its grace period.
+	// They don't auto-redirect, so a leaked old link can't be chained into
+	// visits that look like they came from the new one.
+	Superseded   bool
 	PageTitle    string
 	RedirectPath string
+	// PrintPath is the card's print-ready page.
+	PrintPath    string
 	ErrorMessage string
 
 	OGTitle       string
@@ -30,10 +38,15 @@ type SharePageData struct {
 	OGURL         string
 	OGImage       string
 	OGImageAlt    string
+
+	Brand BrandData
 }
 
 func NewSharePublicHandler(templatesDir string, cardService services.CardServiceInterface) (*SharePublicHandler, error) {
-	templates, err := template.ParseFiles(filepath.Join(templatesDir, "share.html"))
+	templates, err := template.ParseFiles(
+		filepath.Join(templatesDir, "share.html"),
+		filepath.Join(templatesDir, "share_print.html"),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -43,16 +56,60 @@ func NewSharePublicHandler(templatesDir string, cardService services.CardService
 	}, nil
 }
 
+// SetBranding applies the deployment's name to share page titles.
+func (h *SharePublicHandler) SetBranding(branding config.BrandingConfig) {
+	h.brand = branding
+}
+
 func (h *SharePublicHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	// Share links point here, so this is where visits are logged. The
+	// source tag is only recorded: the redirect and preview URLs below never
+	// carry it on to viewers.
+	r = r.WithContext(services.WithShareVisit(r.Context(), r.Referer(), r.UserAgent(), shareSourceParam(r)))
+	token, shared, ok := h.loadShared(w, r)
+	if !ok {
+		return
+	}
+
+	redirectPath := "/share/" + token
+	baseURL := resolveBaseURL(r)
+	displayName := shareCardDisplayName(shared.Card, h.brand.DisplayName())
+
+	description := "View shared card"
+	if !shared.CompletionsHidden {
+		completed, total := shareCompletionStats(shared)
+		description = fmt.Sprintf("%d/%d complete — View shared card", completed, total)
+	}
+
+	state := shareCompletionState(shared)
+	version := shareVersion(state)
+
+	h.render(w, r, http.StatusOK, SharePageData{
+		Found:         true,
+		Superseded:    shared.Superseded,
+		PageTitle:     displayName + " - " + h.brand.DisplayName(),
+		RedirectPath:  redirectPath,
+		PrintPath:     "/s/" + token + "/print",
+		OGTitle:       displayName,
+		OGDescription: description,
+		OGURL:         baseURL + "/s/" + token,
+		OGImage:       baseURL + "/og/share/" + token + ".png?v=" + version,
+		OGImageAlt:    "Bingo card preview for " + displayName,
+	})
+}
+
+// loadShared resolves the request's share token, rendering the share error
+// page and returning false when it doesn't lead to a card.
+func (h *SharePublicHandler) loadShared(w http.ResponseWriter, r *http.Request) (string, *models.SharedCard, bool) {
 	token := strings.TrimSpace(r.PathValue("token"))
 	if token == "" || !isValidShareToken(token) {
 		h.render(w, r, http.StatusNotFound, SharePageData{
 			Found:        false,
-			PageTitle:    "Invalid Share Link - Year of Bingo",
+			PageTitle:    "Invalid Share Link - " + h.brand.DisplayName(),
 			RedirectPath: "/",
 			ErrorMessage: "This share link is missing or malformed.",
 		})
-		return
+		return "", nil, false
 	}
 
 	redirectPath := "/share/" + token
@@ -62,40 +119,21 @@ func (h *SharePublicHandler) Serve(w http.ResponseWriter, r *http.Request) {
 		if !errors.Is(err, services.ErrShareNotFound) {
 			h.render(w, r, http.StatusInternalServerError, SharePageData{
 				Found:        false,
-				PageTitle:    "Error - Year of Bingo",
+				PageTitle:    "Error - " + h.brand.DisplayName(),
 				RedirectPath: redirectPath,
 				ErrorMessage: "An unexpected error occurred.",
 			})
-			return
+			return "", nil, false
 		}
 		h.render(w, r, http.StatusNotFound, SharePageData{
 			Found:        false,
-			PageTitle:    "Share Link Not Found - Year of Bingo",
+			PageTitle:    "Share Link Not Found - " + h.brand.DisplayName(),
 			RedirectPath: redirectPath,
 			ErrorMessage: "This share link may have expired or been revoked.",
 		})
-		return
+		return "", nil, false
 	}
-
-	baseURL := resolveBaseURL(r)
-	displayName := shareCardDisplayName(shared.Card)
-
-	completed, total := shareCompletionStats(shared)
-	description := fmt.Sprintf("%d/%d complete — View shared card", completed, total)
-
-	state := shareCompletionState(shared)
-	version := shareVersion(state)
-
-	h.render(w, r, http.StatusOK, SharePageData{
-		Found:         true,
-		PageTitle:     displayName + " - Year of Bingo",
-		RedirectPath:  redirectPath,
-		OGTitle:       displayName,
-		OGDescription: description,
-		OGURL:         baseURL + "/s/" + token,
-		OGImage:       baseURL + "/og/share/" + token + ".png?v=" + version,
-		OGImageAlt:    "Bingo card preview for " + displayName,
-	})
+	return token, shared, true
 }
 
 func (h *SharePublicHandler) render(w http.ResponseWriter, r *http.Request, status int, data SharePageData) {
@@ -103,6 +141,7 @@ func (h *SharePublicHandler) render(w http.ResponseWriter, r *http.Request, stat
 	w.Header().Set("Cache-Control", "no-store")
 	w.Header().Set("X-Robots-Tag", "noindex")
 	w.WriteHeader(status)
+	data.Brand = newBrandData(h.brand)
 	_ = h.templates.ExecuteTemplate(w, "share.html", data)
 }
 
@@ -114,14 +153,14 @@ func isValidShareToken(token string) bool {
 	return err == nil
 }
 
-func shareCardDisplayName(card models.PublicBingoCard) string {
+func shareCardDisplayName(card models.PublicBingoCard, brandName string) string {
 	if card.Title != nil && strings.TrimSpace(*card.Title) != "" {
 		return strings.TrimSpace(*card.Title)
 	}
 	if card.Year > 0 {
 		return fmt.Sprintf("%d Bingo Card", card.Year)
 	}
-	return "Year of Bingo"
+	return brandName
 }
 
 func shareCompletionStats(shared *models.SharedCard) (completed int, total int) {
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
@@ -127,3 +128,95 @@ func TestSharePublicHandler_Serve_InvalidToken(t *testing.T) {
 		t.Fatalf("expected invalid token messaging and redirect meta")
 	}
 }
+
+func TestSharePublicHandler_Serve_Branding(t *testing.T) {
+	handler, err := NewSharePublicHandler("../../web/templates", &mockSharePublicService{
+		GetSharedCardFunc: func(ctx context.Context, token string) (*models.SharedCard, error) {
+			return &models.SharedCard{Card: models.PublicBingoCard{GridSize: 5}}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	handler.SetBranding(config.BrandingConfig{Name: "Team Goals"})
+
+	token := strings.Repeat("b", 64)
+	req := httptest.NewRequest(http.MethodGet, "/s/"+token, nil)
+	req.SetPathValue("token", token)
+	rr := httptest.NewRecorder()
+	handler.Serve(rr, req)
+
+	if !containsAll(rr.Body.String(), []string{
+		`<title>Team Goals - Team Goals</title>`,
+		`property="og:site_name" content="Team Goals"`,
+	}) {
+		t.Fatalf("expected branded share page, got %s", rr.Body.String())
+	}
+}
+
+func TestSharePublicHandler_Serve_SupersededDoesNotRedirect(t *testing.T) {
+	handler, err := NewSharePublicHandler("../../web/templates", &mockSharePublicService{
+		GetSharedCardFunc: func(ctx context.Context, token string) (*models.SharedCard, error) {
+			return &models.SharedCard{Card: models.PublicBingoCard{Year: 2026, GridSize: 5}, Superseded: true}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	token := strings.Repeat("c", 64)
+	req := httptest.NewRequest(http.MethodGet, "/s/"+token, nil)
+	req.SetPathValue("token", token)
+	rr := httptest.NewRecorder()
+	handler.Serve(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if strings.Contains(body, `http-equiv="refresh"`) {
+		t.Fatal("expected no automatic redirect for a superseded link")
+	}
+	if !containsAll(body, []string{"This link has been replaced", `href="/share/` + token + `"`}) {
+		t.Fatalf("expected superseded banner with a manual link, got %s", body)
+	}
+}
+
+func TestSharePublicHandler_Serve_DoesNotExposeSourceTag(t *testing.T) {
+	token := strings.Repeat("e", 64)
+	handler, err := NewSharePublicHandler("../../web/templates", &mockSharePublicService{
+		GetSharedCardFunc: func(ctx context.Context, got string) (*models.SharedCard, error) {
+			return &models.SharedCard{Card: models.PublicBingoCard{Year: 2026, GridSize: 3, IsFinalized: true}}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/s/"+token+"?src=climbing-crew", nil)
+	req.Host = "example.com"
+	req.SetPathValue("token", token)
+	rr := httptest.NewRecorder()
+	handler.Serve(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if body := rr.Body.String(); strings.Contains(body, "climbing-crew") || strings.Contains(body, "src=") {
+		t.Fatalf("expected the source tag to stay out of the page, got %s", body)
+	}
+}
+
+func TestShareSourceParam(t *testing.T) {
+	cases := map[string]string{
+		"/s/x":                                  "",
+		"/s/x?src=family":                       "family",
+		"/s/x?utm_source=newsletter":            "newsletter",
+		"/s/x?src=family&utm_source=newsletter": "family",
+	}
+	for target, want := range cases {
+		if got := shareSourceParam(httptest.NewRequest(http.MethodGet, target, nil)); got != want {
+			t.Fatalf("%s: expected %q, got %q", target, want, got)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// ShareSubscriptionHandler serves the public side of share subscriptions:
+// the sign-up on the shared card page and the emailed confirm and
+// unsubscribe links.
+type ShareSubscriptionHandler struct {
+	subscriptions services.ShareSubscriptionServiceInterface
+	brand         config.BrandingConfig
+}
+
+func NewShareSubscriptionHandler(subscriptions services.ShareSubscriptionServiceInterface) *ShareSubscriptionHandler {
+	return &ShareSubscriptionHandler{subscriptions: subscriptions}
+}
+
+// SetBranding applies the deployment's name to the confirm and unsubscribe
+// pages.
+func (h *ShareSubscriptionHandler) SetBranding(branding config.BrandingConfig) {
+	h.brand = branding
+}
+
+type ShareSubscribeRequest struct {
+	Email string `json:"email"`
+}
+
+// Subscribe signs an email address up for weekly progress emails about a
+// shared card. The response is the same whether or not the address was
+// already subscribed.
+func (h *ShareSubscriptionHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+	if !isValidShareToken(token) {
+		writeError(w, http.StatusNotFound, "Share link not found")
+		return
+	}
+
+	var req ShareSubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
+	if len(req.Email) > 254 {
+		writeError(w, http.StatusBadRequest, "Invalid email address")
+		return
+	}
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid email address")
+		return
+	}
+
+	err := h.subscriptions.Subscribe(r.Context(), token, req.Email)
+	if errors.Is(err, services.ErrShareNotFound) {
+		writeError(w, http.StatusNotFound, "Share link not found")
+		return
+	}
+	if errors.Is(err, services.ErrShareSubscriberLimit) {
+		writeError(w, http.StatusConflict, "This card can't take more subscribers")
+		return
+	}
+	if err != nil {
+		log.Printf("Error subscribing to share: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"message": "Check your email to confirm weekly updates"})
+}
+
+// ConfirmPage asks the visitor to confirm their subscription. Confirming
+// takes a POST so link scanners that follow URLs in emails don't confirm on
+// the recipient's behalf.
+func (h *ShareSubscriptionHandler) ConfirmPage(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if !isValidShareToken(token) {
+		writeError(w, http.StatusNotFound, "Confirmation link not found")
+		return
+	}
+	h.writeTokenForm(w, "Confirm updates", "Start getting a weekly progress email about this card?", "/r/watch/confirm", token, "Confirm", "btn-primary")
+}
+
+func (h *ShareSubscriptionHandler) ConfirmSubmit(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid form")
+		return
+	}
+	token := r.Form.Get("token")
+	if !isValidShareToken(token) {
+		writeError(w, http.StatusNotFound, "Confirmation link not found")
+		return
+	}
+
+	err := h.subscriptions.Confirm(r.Context(), token)
+	if errors.Is(err, services.ErrShareSubscriptionNotFound) {
+		writeError(w, http.StatusNotFound, "Confirmation link expired or already used")
+		return
+	}
+	if err != nil {
+		log.Printf("Error confirming share subscription: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	h.writeStatus(w, "Subscribed", "You'll get a weekly email when this card makes progress. Every email has an unsubscribe link.")
+}
+
+func (h *ShareSubscriptionHandler) UnsubscribePage(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if !isValidShareToken(token) {
+		writeError(w, http.StatusNotFound, "Unsubscribe link not found")
+		return
+	}
+	h.writeTokenForm(w, "Unsubscribe", "Stop the weekly progress email about this card?", "/r/watch/unsubscribe", token, "Unsubscribe", "btn-danger-outline")
+}
+
+func (h *ShareSubscriptionHandler) UnsubscribeSubmit(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid form")
+		return
+	}
+	token := r.Form.Get("token")
+	if !isValidShareToken(token) {
+		writeError(w, http.StatusNotFound, "Unsubscribe link not found")
+		return
+	}
+
+	err := h.subscriptions.Unsubscribe(r.Context(), token)
+	if err != nil && !errors.Is(err, services.ErrShareSubscriptionNotFound) {
+		log.Printf("Error unsubscribing from share: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	// Already removed (by the owner, a revoked share, or an earlier click)
+	// reads the same as unsubscribing now.
+	h.writeStatus(w, "Unsubscribed", "You won't get any more emails about this card.")
+}
+
+func (h *ShareSubscriptionHandler) writeTokenForm(w http.ResponseWriter, heading, question, action, token, button, buttonClass string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>` + html.EscapeString(heading) + ` - ` + html.EscapeString(h.brand.DisplayName()) + `</title>
+  <link rel="stylesheet" href="/static/css/styles.css">
+</head>
+<body>
+  <main class="container main-content">
+    <div class="card">
+      <h2>` + html.EscapeString(heading) + `</h2>
+      <p>` + html.EscapeString(question) + `</p>
+      <form method="POST" action="` + action + `">
+        <input type="hidden" name="token" value="` + html.EscapeString(token) + `">
+        <div class="profile-actions">
+          <button type="submit" class="btn ` + buttonClass + `">` + html.EscapeString(button) + `</button>
+          <a class="btn btn-ghost" href="/">Cancel</a>
+        </div>
+      </form>
+    </div>
+  </main>
+</body>
+</html>`))
+}
+
+func (h *ShareSubscriptionHandler) writeStatus(w http.ResponseWriter, heading, status string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>` + html.EscapeString(heading) + ` - ` + html.EscapeString(h.brand.DisplayName()) + `</title>
+  <link rel="stylesheet" href="/static/css/styles.css">
+</head>
+<body>
+  <main class="container main-content">
+    <div class="card">
+      <h2>` + html.EscapeString(heading) + `</h2>
+      <p>` + html.EscapeString(status) + `</p>
+    </div>
+  </main>
+</body>
+</html>`))
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+var testShareToken = strings.Repeat("ab", 32)
+
+func subscribeRequest(handler *ShareSubscriptionHandler, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/share/"+token+"/subscribe", strings.NewReader(body))
+	req.SetPathValue("token", token)
+	rr := httptest.NewRecorder()
+	handler.Subscribe(rr, req)
+	return rr
+}
+
+func TestShareSubscription_Subscribe(t *testing.T) {
+	var gotToken, gotEmail string
+	handler := NewShareSubscriptionHandler(&mockShareSubscriptionService{
+		SubscribeFunc: func(ctx context.Context, shareToken, email string) error {
+			gotToken, gotEmail = shareToken, email
+			return nil
+		},
+	})
+
+	rr := subscribeRequest(handler, testShareToken, `{"email":"  Fan@Example.com "}`)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if gotToken != testShareToken || gotEmail != "fan@example.com" {
+		t.Fatalf("expected normalized email for the share token, got %q %q", gotToken, gotEmail)
+	}
+}
+
+func TestShareSubscription_Subscribe_Errors(t *testing.T) {
+	cases := map[string]struct {
+		token   string
+		body    string
+		err     error
+		code    int
+		message string
+	}{
+		"bad token":     {"nope", `{"email":"a@example.com"}`, nil, http.StatusNotFound, "Share link not found"},
+		"bad email":     {testShareToken, `{"email":"not-an-email"}`, nil, http.StatusBadRequest, "Invalid email address"},
+		"bad body":      {testShareToken, `nope`, nil, http.StatusBadRequest, "Invalid request body"},
+		"share gone":    {testShareToken, `{"email":"a@example.com"}`, services.ErrShareNotFound, http.StatusNotFound, "Share link not found"},
+		"too many subs": {testShareToken, `{"email":"a@example.com"}`, services.ErrShareSubscriberLimit, http.StatusConflict, "This card can't take more subscribers"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewShareSubscriptionHandler(&mockShareSubscriptionService{
+				SubscribeFunc: func(ctx context.Context, shareToken, email string) error {
+					return tc.err
+				},
+			})
+			rr := subscribeRequest(handler, tc.token, tc.body)
+			assertErrorResponse(t, rr, tc.code, tc.message)
+		})
+	}
+}
+
+func TestShareSubscription_ConfirmPageDoesNotConfirm(t *testing.T) {
+	handler := NewShareSubscriptionHandler(&mockShareSubscriptionService{
+		ConfirmFunc: func(ctx context.Context, confirmToken string) error {
+			t.Fatal("GET must not confirm")
+			return nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/r/watch/confirm?token="+testShareToken, nil)
+	rr := httptest.NewRecorder()
+	handler.ConfirmPage(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `action="/r/watch/confirm"`) || !strings.Contains(rr.Body.String(), testShareToken) {
+		t.Fatalf("expected confirm form with token, got %s", rr.Body.String())
+	}
+}
+
+func TestShareSubscription_ConfirmSubmit(t *testing.T) {
+	var confirmed string
+	handler := NewShareSubscriptionHandler(&mockShareSubscriptionService{
+		ConfirmFunc: func(ctx context.Context, confirmToken string) error {
+			if confirmed != "" {
+				return services.ErrShareSubscriptionNotFound
+			}
+			confirmed = confirmToken
+			return nil
+		},
+	})
+
+	submit := func() *httptest.ResponseRecorder {
+		form := url.Values{"token": {testShareToken}}
+		req := httptest.NewRequest(http.MethodPost, "/r/watch/confirm", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.ConfirmSubmit(rr, req)
+		return rr
+	}
+
+	if rr := submit(); rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "Subscribed") {
+		t.Fatalf("expected confirmation page, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if confirmed != testShareToken {
+		t.Fatalf("expected token %q to be confirmed, got %q", testShareToken, confirmed)
+	}
+	assertErrorResponse(t, submit(), http.StatusNotFound, "Confirmation link expired or already used")
+}
+
+func TestShareSubscription_UnsubscribeSubmit_AlreadyGone(t *testing.T) {
+	handler := NewShareSubscriptionHandler(&mockShareSubscriptionService{
+		UnsubscribeFunc: func(ctx context.Context, unsubscribeToken string) error {
+			return services.ErrShareSubscriptionNotFound
+		},
+	})
+
+	form := url.Values{"token": {testShareToken}}
+	req := httptest.NewRequest(http.MethodPost, "/r/watch/unsubscribe", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	handler.UnsubscribeSubmit(rr, req)
+
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "Unsubscribed") {
+		t.Fatalf("expected unsubscribed page, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCardShare_Status_IncludesSubscribers(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	handler := NewCardHandler(&mockCardShareService{
+		GetShareStatusFunc: func(ctx context.Context, userID, cardID uuid.UUID) (*models.CardShare, error) {
+			return &models.CardShare{CardID: cardID, Token: "deadbeef", CreatedAt: time.Now()}, nil
+		},
+	})
+	handler.SetShareSubscriptionService(&mockShareSubscriptionService{
+		ListSubscribersFunc: func(ctx context.Context, userID, cardID uuid.UUID) ([]models.ShareSubscriber, error) {
+			return []models.ShareSubscriber{{ID: uuid.New(), Email: "fan@example.com", Confirmed: true}}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cards/"+cardID.String()+"/share", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.GetShareStatus(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var response ShareStatusResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(response.Subscribers) != 1 || response.Subscribers[0].Email != "fan@example.com" {
+		t.Fatalf("expected subscriber in status, got %+v", response.Subscribers)
+	}
+}
+
+func TestCardShare_RemoveSubscriber(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cardID := uuid.New()
+	subscriberID := uuid.New()
+
+	var removed uuid.UUID
+	handler := NewCardHandler(&mockCardShareService{})
+	handler.SetShareSubscriptionService(&mockShareSubscriptionService{
+		RemoveSubscriberFunc: func(ctx context.Context, userID, gotCardID, gotSubscriberID uuid.UUID) error {
+			if userID != user.ID || gotCardID != cardID {
+				return services.ErrNotCardOwner
+			}
+			if removed == gotSubscriberID {
+				return services.ErrShareSubscriptionNotFound
+			}
+			removed = gotSubscriberID
+			return nil
+		},
+	})
+
+	remove := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/cards/"+cardID.String()+"/share/subscribers/"+subscriberID.String(), nil)
+		req.SetPathValue("subscriberId", subscriberID.String())
+		req = req.WithContext(SetUserInContext(req.Context(), user))
+		rr := httptest.NewRecorder()
+		handler.RemoveShareSubscriber(rr, req)
+		return rr
+	}
+
+	if rr := remove(); rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if removed != subscriberID {
+		t.Fatalf("expected subscriber %s removed, got %s", subscriberID, removed)
+	}
+	assertErrorResponse(t, remove(), http.StatusNotFound, "Subscriber not found")
+}
@@ -8,6 +8,10 @@ import (
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
+// suggestionsCacheControl lets browsers and CDNs reuse suggestion responses
+// for as long as the server caches them.
+const suggestionsCacheControl = "public, max-age=600"
+
 type SuggestionHandler struct {
 	suggestionService services.SuggestionServiceInterface
 }
@@ -35,6 +39,7 @@ func (h *SuggestionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		setSuggestionsCacheHeaders(w)
 		writeJSON(w, http.StatusOK, SuggestionsResponse{Grouped: groupedSuggestions})
 		return
 	}
@@ -47,6 +52,7 @@ func (h *SuggestionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		setSuggestionsCacheHeaders(w)
 		writeJSON(w, http.StatusOK, SuggestionsResponse{Suggestions: suggestions})
 		return
 	}
@@ -58,6 +64,7 @@ func (h *SuggestionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setSuggestionsCacheHeaders(w)
 	writeJSON(w, http.StatusOK, SuggestionsResponse{Suggestions: suggestions})
 }
 
@@ -69,5 +76,13 @@ func (h *SuggestionHandler) GetCategories(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	setSuggestionsCacheHeaders(w)
 	writeJSON(w, http.StatusOK, SuggestionsResponse{Categories: categories})
 }
+
+// setSuggestionsCacheHeaders replaces the API's default no-store headers:
+// suggestions are the same for every visitor.
+func setSuggestionsCacheHeaders(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", suggestionsCacheControl)
+	w.Header().Del("Pragma")
+}
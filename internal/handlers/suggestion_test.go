@@ -99,12 +99,19 @@ func TestSuggestionHandler_GetAll_All(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodGet, "/api/suggestions", nil)
 	rr := httptest.NewRecorder()
+	rr.Header().Set("Pragma", "no-cache")
 
 	handler.GetAll(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Fatalf("expected status 200, got %d", rr.Code)
 	}
+	if got := rr.Header().Get("Cache-Control"); got != suggestionsCacheControl {
+		t.Fatalf("expected public cache header, got %q", got)
+	}
+	if rr.Header().Get("Pragma") != "" {
+		t.Fatal("expected the API's Pragma: no-cache to be dropped")
+	}
 }
 
 func TestSuggestionHandler_GetAll_GroupedError(t *testing.T) {
@@ -141,6 +148,9 @@ func TestSuggestionHandler_GetAll_Error(t *testing.T) {
 	if rr.Code != http.StatusInternalServerError {
 		t.Fatalf("expected status 500, got %d", rr.Code)
 	}
+	if rr.Header().Get("Cache-Control") == suggestionsCacheControl {
+		t.Fatal("expected errors not to be cached publicly")
+	}
 }
 
 func TestSuggestionHandler_GetCategories_Success(t *testing.T) {
@@ -159,6 +169,9 @@ func TestSuggestionHandler_GetCategories_Success(t *testing.T) {
 	if rr.Code != http.StatusOK {
 		t.Fatalf("expected status 200, got %d", rr.Code)
 	}
+	if got := rr.Header().Get("Cache-Control"); got != suggestionsCacheControl {
+		t.Fatalf("expected public cache header, got %q", got)
+	}
 
 	var resp SuggestionsResponse
 	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
@@ -138,6 +138,10 @@ func (s redisRateLimitStore) Expire(ctx context.Context, key string, expiration
 	return s.client.Expire(ctx, key, expiration).Err()
 }
 
+func (s redisRateLimitStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return s.client.TTL(ctx, key).Result()
+}
+
 // checkRateLimit checks if the client has exceeded the rate limit
 func (h *SupportHandler) checkRateLimit(r *http.Request, clientIP string) bool {
 	if h.rateLimiter == nil {
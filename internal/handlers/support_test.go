@@ -19,6 +19,7 @@ type fakeRateLimitStore struct {
 	incrCount   int64
 	incrErr     error
 	expireCalls int
+	ttl         time.Duration
 }
 
 func (s *fakeRateLimitStore) Incr(ctx context.Context, key string) (int64, error) {
@@ -34,6 +35,10 @@ func (s *fakeRateLimitStore) Expire(ctx context.Context, key string, expiration
 	return nil
 }
 
+func (s *fakeRateLimitStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return s.ttl, nil
+}
+
 func TestSupportHandler_checkRateLimit(t *testing.T) {
 	t.Run("no store", func(t *testing.T) {
 		h := &SupportHandler{emailService: &mockEmailService{}, rateLimiter: nil}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// UsageHandler reports a user's own API usage so token clients can see how
+// close they are to their limits.
+type UsageHandler struct {
+	usageService services.UsageServiceInterface
+}
+
+func NewUsageHandler(usageService services.UsageServiceInterface) *UsageHandler {
+	return &UsageHandler{usageService: usageService}
+}
+
+type UsageResponse struct {
+	Usage *models.UsageReport `json:"usage"`
+}
+
+func (h *UsageHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	report, err := h.usageService.Get(r.Context(), user)
+	if err != nil {
+		log.Printf("Error loading usage: %v", err)
+		writeError(w, http.StatusServiceUnavailable, "Usage reporting temporarily unavailable")
+		return
+	}
+	writeJSON(w, http.StatusOK, UsageResponse{Usage: report})
+}
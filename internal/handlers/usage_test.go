@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestUsageHandler_Get(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewUsageHandler(&mockUsageService{
+		GetFunc: func(ctx context.Context, got *models.User) (*models.UsageReport, error) {
+			if got.ID != user.ID {
+				t.Fatalf("expected report for %s, got %s", user.ID, got.ID)
+			}
+			return &models.UsageReport{
+				Date:          "2026-03-04",
+				Requests:      map[string]int64{"cards": 4},
+				TotalRequests: 4,
+				Limits:        []models.UsageLimit{{Name: "ai", Limit: 10, WindowSeconds: 3600, Used: 1, Remaining: 9}},
+			}, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/usage", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr := httptest.NewRecorder()
+	handler.Get(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp UsageResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Usage == nil || resp.Usage.Requests["cards"] != 4 || resp.Usage.Limits[0].Remaining != 9 {
+		t.Fatalf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestUsageHandler_Get_Errors(t *testing.T) {
+	handler := NewUsageHandler(&mockUsageService{
+		GetFunc: func(ctx context.Context, user *models.User) (*models.UsageReport, error) {
+			return nil, errors.New("redis down")
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	handler.Get(rr, httptest.NewRequest(http.MethodGet, "/api/usage", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a user, got %d", rr.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/usage", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr = httptest.NewRecorder()
+	handler.Get(rr, req)
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when redis fails, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import "net/http"
+
+// APIVersion is the current major version of the JSON API. Routes are served
+// under both /api and /api/v{APIVersion}.
+const APIVersion = "1"
+
+type VersionHandler struct {
+	serverVersion    string
+	minClientVersion string
+}
+
+func NewVersionHandler(serverVersion, minClientVersion string) *VersionHandler {
+	return &VersionHandler{
+		serverVersion:    serverVersion,
+		minClientVersion: minClientVersion,
+	}
+}
+
+type VersionResponse struct {
+	ServerVersion    string `json:"server_version"`
+	APIVersion       string `json:"api_version"`
+	MinClientVersion string `json:"min_client_version"`
+}
+
+func (h *VersionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, VersionResponse{
+		ServerVersion:    h.serverVersion,
+		APIVersion:       APIVersion,
+		MinClientVersion: h.minClientVersion,
+	})
+}
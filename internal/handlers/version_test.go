@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVersionHandler_Get(t *testing.T) {
+	handler := NewVersionHandler("1.8.1", "1.0.0")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/version", nil)
+	rr := httptest.NewRecorder()
+
+	handler.Get(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var response VersionResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.ServerVersion != "1.8.1" {
+		t.Errorf("expected server_version 1.8.1, got %q", response.ServerVersion)
+	}
+	if response.APIVersion != APIVersion {
+		t.Errorf("expected api_version %q, got %q", APIVersion, response.APIVersion)
+	}
+	if response.MinClientVersion != "1.0.0" {
+		t.Errorf("expected min_client_version 1.0.0, got %q", response.MinClientVersion)
+	}
+}
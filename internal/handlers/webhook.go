@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookServiceInterface
+}
+
+func NewWebhookHandler(webhookService services.WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+type CreateWebhookRequest struct {
+	URL string `json:"url"`
+}
+
+type CreateWebhookResponse struct {
+	Webhook *models.Webhook `json:"webhook"`
+	Warning string          `json:"warning"`
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []models.Webhook `json:"webhooks"`
+}
+
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	webhooks, err := h.webhookService.List(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error listing webhooks: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if webhooks == nil {
+		webhooks = []models.Webhook{}
+	}
+
+	writeJSON(w, http.StatusOK, ListWebhooksResponse{Webhooks: webhooks})
+}
+
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	webhook, err := h.webhookService.Create(r.Context(), user.ID, req.URL)
+	if errors.Is(err, services.ErrInvalidWebhookURL) {
+		writeError(w, http.StatusBadRequest, "Webhook URL must be a public https URL")
+		return
+	}
+	if errors.Is(err, services.ErrWebhookLimit) {
+		writeError(w, http.StatusConflict, "You already have the maximum number of webhooks")
+		return
+	}
+	if err != nil {
+		log.Printf("Error creating webhook: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, CreateWebhookResponse{
+		Webhook: webhook,
+		Warning: "Save this secret now. You won't be able to see it again.",
+	})
+}
+
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	webhookID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	err = h.webhookService.Delete(r.Context(), user.ID, webhookID)
+	if errors.Is(err, services.ErrWebhookNotFound) {
+		writeError(w, http.StatusNotFound, "Webhook not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error deleting webhook: %v", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+func TestWebhookHandler_List(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	handler := NewWebhookHandler(&mockWebhookService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/webhooks", nil)
+	rr := httptest.NewRecorder()
+	handler.List(rr, req)
+	assertErrorResponse(t, rr, http.StatusUnauthorized, "Authentication required")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/webhooks", nil)
+	req = req.WithContext(SetUserInContext(req.Context(), user))
+	rr = httptest.NewRecorder()
+	handler.List(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp ListWebhooksResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil || resp.Webhooks == nil {
+		t.Fatalf("expected an empty webhook list, got %s (%v)", rr.Body.String(), err)
+	}
+}
+
+func TestWebhookHandler_Create(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	cases := map[string]struct {
+		body       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		"bad body":   {`nope`, nil, http.StatusBadRequest, "Invalid request body"},
+		"bad url":    {`{"url":"http://example.com"}`, services.ErrInvalidWebhookURL, http.StatusBadRequest, "Webhook URL must be a public https URL"},
+		"over limit": {`{"url":"https://example.com"}`, services.ErrWebhookLimit, http.StatusConflict, "You already have the maximum number of webhooks"},
+		"created":    {`{"url":"https://example.com"}`, nil, http.StatusCreated, ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gotURL string
+			handler := NewWebhookHandler(&mockWebhookService{
+				CreateFunc: func(ctx context.Context, userID uuid.UUID, rawURL string) (*models.Webhook, error) {
+					gotURL = rawURL
+					if tc.err != nil {
+						return nil, tc.err
+					}
+					return &models.Webhook{ID: uuid.New(), UserID: userID, URL: rawURL, Secret: "s3cret"}, nil
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(tc.body))
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+			handler.Create(rr, req)
+
+			if tc.wantMsg != "" {
+				assertErrorResponse(t, rr, tc.wantStatus, tc.wantMsg)
+				return
+			}
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("expected %d, got %d", tc.wantStatus, rr.Code)
+			}
+			var resp CreateWebhookResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotURL != "https://example.com" || resp.Webhook.Secret != "s3cret" {
+				t.Fatalf("unexpected response %+v", resp.Webhook)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_Delete(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	hookID := uuid.New()
+	handler := NewWebhookHandler(&mockWebhookService{
+		DeleteFunc: func(ctx context.Context, userID, webhookID uuid.UUID) error {
+			if webhookID != hookID {
+				return services.ErrWebhookNotFound
+			}
+			return nil
+		},
+	})
+
+	cases := map[string]struct {
+		id         string
+		wantStatus int
+		wantMsg    string
+	}{
+		"bad id":  {"nope", http.StatusBadRequest, "Invalid webhook ID"},
+		"missing": {uuid.New().String(), http.StatusNotFound, "Webhook not found"},
+		"deleted": {hookID.String(), http.StatusOK, ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/api/webhooks/"+tc.id, nil)
+			req.SetPathValue("id", tc.id)
+			req = req.WithContext(SetUserInContext(req.Context(), user))
+			rr := httptest.NewRecorder()
+			handler.Delete(rr, req)
+
+			if tc.wantMsg != "" {
+				assertErrorResponse(t, rr, tc.wantStatus, tc.wantMsg)
+				return
+			}
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("expected %d, got %d", tc.wantStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// TestServer_CardAccessMatrix runs every viewer relationship against each
+// endpoint class that reads or writes someone's card, through the real
+// middleware chain and services, so a handler that skips the authz helpers
+// shows up as a wrong status.
+func TestServer_CardAccessMatrix(t *testing.T) {
+	ctx := context.Background()
+	var db services.DB
+	var cards *services.CardService
+	var friends *services.FriendService
+	s := newTestServer(t, func(h *Handlers, conn services.DB) {
+		db = conn
+		cards = services.NewCardService(conn)
+		friends = services.NewFriendService(conn)
+		h.Card = handlers.NewCardHandler(cards)
+		h.Friend = handlers.NewFriendHandler(friends, cards)
+		h.Reaction = handlers.NewReactionHandler(services.NewReactionService(conn, friends))
+		h.Comment = handlers.NewCommentHandler(services.NewCommentService(conn, friends))
+	})
+
+	newUser := func(name string) *models.User {
+		user, err := s.users.Create(ctx, models.CreateUserParams{Email: name + "@example.com", Username: name})
+		if err != nil {
+			t.Fatalf("unexpected error creating %s: %v", name, err)
+		}
+		return user
+	}
+	owner := newUser("owner")
+	friend := newUser("friend")
+	blocked := newUser("blocked")
+	stranger := newUser("stranger")
+
+	befriend := func(user *models.User) *models.Friendship {
+		friendship, _, err := friends.SendRequest(ctx, user.ID, owner.ID)
+		if err != nil {
+			t.Fatalf("unexpected error sending request: %v", err)
+		}
+		if _, err := friends.AcceptRequest(ctx, owner.ID, friendship.ID); err != nil {
+			t.Fatalf("unexpected error accepting request: %v", err)
+		}
+		return friendship
+	}
+	friendship := befriend(friend)
+	blockedFriendship := befriend(blocked)
+	// Blocking normally deletes the friendship; keep the row, as a block
+	// racing an accept would, so access has to come from the block itself.
+	if _, err := db.Exec(ctx, "INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2)", owner.ID, blocked.ID); err != nil {
+		t.Fatalf("unexpected error blocking: %v", err)
+	}
+
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: owner.ID, Year: 2026, GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		if _, err := cards.AddItem(ctx, owner.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, owner.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+	item, err := cards.CompleteItem(ctx, owner.ID, card.ID, 0, models.CompleteItemParams{})
+	if err != nil {
+		t.Fatalf("unexpected error completing: %v", err)
+	}
+
+	session := func(user *models.User) *http.Cookie {
+		token, err := s.auth.CreateSession(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("unexpected error creating session: %v", err)
+		}
+		return &http.Cookie{Name: "session_token", Value: token}
+	}
+	type viewer struct {
+		name       string
+		cookie     *http.Cookie
+		friendship string // the friendship the viewer asks for the owner's card through
+	}
+	viewers := []viewer{
+		{"owner", session(owner), friendship.ID.String()},
+		{"friend", session(friend), friendship.ID.String()},
+		{"blocked former friend", session(blocked), blockedFriendship.ID.String()},
+		{"stranger", session(stranger), friendship.ID.String()},
+		{"anonymous", nil, friendship.ID.String()},
+	}
+
+	itemPath := "/api/items/" + item.ID.String()
+	cardPath := "/api/cards/" + card.ID.String()
+	for _, tc := range []struct {
+		method string
+		path   func(v viewer) string
+		body   string
+		want   map[string]int
+	}{
+		{
+			// The owner reads through the friend's side of the friendship,
+			// where there is no card to show.
+			http.MethodGet, func(v viewer) string { return "/api/friends/" + v.friendship + "/card" }, "",
+			map[string]int{"owner": 200, "friend": 200, "blocked former friend": 403, "stranger": 404, "anonymous": 401},
+		},
+		{
+			http.MethodGet, func(v viewer) string { return "/api/friends/" + v.friendship + "/cards" }, "",
+			map[string]int{"owner": 200, "friend": 200, "blocked former friend": 403, "stranger": 404, "anonymous": 401},
+		},
+		{
+			http.MethodPost, func(viewer) string { return itemPath + "/react" }, `{"emoji":"🎉"}`,
+			map[string]int{"owner": 400, "friend": 200, "blocked former friend": 403, "stranger": 403, "anonymous": 401},
+		},
+		{
+			http.MethodGet, func(viewer) string { return itemPath + "/reactions" }, "",
+			map[string]int{"owner": 200, "friend": 200, "blocked former friend": 403, "stranger": 403, "anonymous": 401},
+		},
+		{
+			http.MethodPost, func(viewer) string { return itemPath + "/comments" }, `{"content":"Nice"}`,
+			map[string]int{"owner": 201, "friend": 201, "blocked former friend": 403, "stranger": 403, "anonymous": 401},
+		},
+		{
+			http.MethodGet, func(viewer) string { return itemPath + "/comments" }, "",
+			map[string]int{"owner": 200, "friend": 200, "blocked former friend": 403, "stranger": 403, "anonymous": 401},
+		},
+		{
+			http.MethodPut, func(viewer) string { return cardPath + "/items/0/notes" }, `{"notes":"Done"}`,
+			map[string]int{"owner": 200, "friend": 403, "blocked former friend": 403, "stranger": 403, "anonymous": 401},
+		},
+		{
+			http.MethodPut, func(viewer) string { return cardPath + "/items/1/complete" }, "",
+			map[string]int{"owner": 200, "friend": 403, "blocked former friend": 403, "stranger": 403, "anonymous": 401},
+		},
+	} {
+		for _, v := range viewers {
+			path := tc.path(v)
+			req := withCSRF(tc.method, path)
+			if tc.body != "" {
+				req.Body = io.NopCloser(strings.NewReader(tc.body))
+				req.ContentLength = int64(len(tc.body))
+			}
+			if v.cookie != nil {
+				req.AddCookie(v.cookie)
+			}
+			rr := httptest.NewRecorder()
+			s.handler.ServeHTTP(rr, req)
+			if want := tc.want[v.name]; rr.Code != want {
+				t.Errorf("%s %s as %s: expected %d, got %d %s", tc.method, path, v.name, want, rr.Code, rr.Body.String())
+			}
+			if v.name == "friend" && strings.HasSuffix(path, "/card") && !strings.Contains(rr.Body.String(), card.ID.String()) {
+				t.Errorf("expected the friend to see the owner's card, got %s", rr.Body.String())
+			}
+		}
+	}
+}
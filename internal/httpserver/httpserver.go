@@ -0,0 +1,355 @@
+// Package httpserver builds the server's route table and the middleware
+// chain around it, so the binary and the integration tests serve exactly the
+// same routes.
+package httpserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/activitypub"
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/middleware"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// Handlers are the request handlers the route table dispatches to.
+type Handlers struct {
+	Health            *handlers.HealthHandler
+	Version           *handlers.VersionHandler
+	Auth              *handlers.AuthHandler
+	ProviderAuth      *handlers.ProviderAuthHandler
+	Account           *handlers.AccountHandler
+	Profile           *handlers.ProfileHandler
+	Usage             *handlers.UsageHandler
+	APIToken          *handlers.ApiTokenHandler
+	Webhook           *handlers.WebhookHandler
+	Card              *handlers.CardHandler
+	ShareSubscription *handlers.ShareSubscriptionHandler
+	Suggestion        *handlers.SuggestionHandler
+	Friend            *handlers.FriendHandler
+	Block             *handlers.BlockHandler
+	Invite            *handlers.FriendInviteHandler
+	Notification      *handlers.NotificationHandler
+	Reminder          *handlers.ReminderHandler
+	ReminderPublic    *handlers.ReminderPublicHandler
+	Admin             *handlers.AdminHandler
+	Jobs              *handlers.JobsHandler
+	Reaction          *handlers.ReactionHandler
+	Comment           *handlers.CommentHandler
+	Support           *handlers.SupportHandler
+	AI                *handlers.AIHandler
+	Proof             *handlers.ProofHandler
+	OGImage           *handlers.OGImageHandler
+	ShareOGImage      *handlers.ShareOGImageHandler
+	SharePublic       *handlers.SharePublicHandler
+	ProfilePublic     *handlers.ProfilePublicHandler
+	Page              *handlers.PageHandler
+	// ActivityPub is nil unless ACTIVITYPUB_ENABLED is set.
+	ActivityPub *activitypub.Handler
+	// StaticDir is the directory served under /static/.
+	StaticDir string
+}
+
+// Middleware is the middleware the chain and individual routes apply.
+type Middleware struct {
+	Auth               *middleware.AuthMiddleware
+	CSRF               *middleware.CSRFMiddleware
+	SecurityHeaders    *middleware.SecurityHeaders
+	CacheControl       *middleware.CacheControl
+	Compress           *middleware.Compress
+	RequestLogger      *middleware.RequestLogger
+	DisconnectWatchdog *middleware.DisconnectWatchdog
+	UsageTracker       *middleware.UsageTracker
+	AdminGate          *middleware.AdminGate
+	InternalTokenGate  *middleware.InternalTokenGate
+
+	AIRateLimiter             *middleware.RateLimiter
+	ReactionRateLimiter       *middleware.RateLimiter
+	CommentRateLimiter        *middleware.RateLimiter
+	WidgetRateLimiter         *middleware.RateLimiter
+	ShareSubscribeRateLimiter *middleware.RateLimiter
+	ActivityPubInboxLimiter   *middleware.RateLimiter
+}
+
+// New returns the server's root handler: every route wrapped in the full
+// middleware chain.
+func New(h Handlers, mw Middleware) http.Handler {
+	return wrap(newRoutes(h, mw), mw)
+}
+
+// newRoutes registers every route on a new router.
+func newRoutes(h Handlers, mw Middleware) *router {
+	// Helper middlewares for API token scope enforcement
+	requireRead := mw.Auth.RequireScope(models.ScopeRead)
+	requireWrite := mw.Auth.RequireScope(models.ScopeWrite)
+	requireSession := mw.Auth.RequireSession
+	requireAdmin := func(next http.Handler) http.Handler {
+		return requireSession(mw.AdminGate.Require(next))
+	}
+	requireAdminOrInternal := func(next http.Handler) http.Handler {
+		return mw.InternalTokenGate.Or(next, requireAdmin(next))
+	}
+
+	routes := newRouter()
+
+	// Health endpoints (no auth, no rate limit)
+	routes.Handle("GET /health", http.HandlerFunc(h.Health.Health))
+	routes.Handle("GET /ready", http.HandlerFunc(h.Health.Ready))
+	routes.Handle("GET /live", http.HandlerFunc(h.Health.Live))
+
+	// Version endpoint
+	routes.API("GET /api/version", http.HandlerFunc(h.Version.Get))
+
+	// CSRF token endpoint
+	routes.API("GET /api/csrf", requireSession(http.HandlerFunc(mw.CSRF.GetToken)))
+
+	// Auth endpoints
+	routes.API("POST /api/auth/register", requireSession(http.HandlerFunc(h.Auth.Register)))
+	routes.API("POST /api/auth/login", requireSession(http.HandlerFunc(h.Auth.Login)))
+	routes.API("POST /api/auth/logout", requireSession(http.HandlerFunc(h.Auth.Logout)))
+	routes.API("GET /api/auth/me", requireRead(http.HandlerFunc(h.Auth.Me)))
+	routes.API("POST /api/auth/password", requireSession(http.HandlerFunc(h.Auth.ChangePassword)))
+	routes.API("POST /api/auth/verify-email", requireSession(http.HandlerFunc(h.Auth.VerifyEmail)))
+	routes.API("POST /api/auth/resend-verification", requireSession(http.HandlerFunc(h.Auth.ResendVerification)))
+	routes.API("POST /api/auth/magic-link", requireSession(http.HandlerFunc(h.Auth.MagicLink)))
+	routes.API("POST /api/auth/magic-link/verify", requireSession(http.HandlerFunc(h.Auth.MagicLinkVerify)))
+	routes.API("GET /api/auth/magic-link/verify", requireSession(http.HandlerFunc(h.Auth.MagicLinkVerifyLegacy)),
+		deprecated(time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC), time.Date(2027, time.January, 15, 0, 0, 0, 0, time.UTC)))
+	routes.API("POST /api/auth/forgot-password", requireSession(http.HandlerFunc(h.Auth.ForgotPassword)))
+	routes.API("POST /api/auth/reset-password", requireSession(http.HandlerFunc(h.Auth.ResetPassword)))
+	routes.API("PUT /api/auth/searchable", requireSession(http.HandlerFunc(h.Auth.UpdateSearchable)))
+	routes.API("GET /api/auth/sessions", requireSession(http.HandlerFunc(h.Auth.ListSessions)))
+	routes.API("DELETE /api/auth/sessions", requireSession(http.HandlerFunc(h.Auth.RevokeOtherSessions)))
+	routes.API("DELETE /api/auth/sessions/{id}", requireSession(http.HandlerFunc(h.Auth.RevokeSession)))
+	routes.API("GET /api/auth/preferences", requireSession(http.HandlerFunc(h.Account.GetPreferences)))
+	routes.API("PUT /api/auth/preferences", requireSession(http.HandlerFunc(h.Account.UpdatePreferences)))
+	routes.API("GET /api/profile/settings", requireSession(http.HandlerFunc(h.Profile.GetSettings)))
+	routes.API("PUT /api/profile/settings", requireSession(http.HandlerFunc(h.Profile.UpdateSettings)))
+	routes.API("GET /api/auth/{provider}/start", requireSession(http.HandlerFunc(h.ProviderAuth.ProviderStart)))
+	routes.API("GET /api/auth/{provider}/callback", requireSession(http.HandlerFunc(h.ProviderAuth.ProviderCallback)))
+	routes.API("GET /api/auth/{provider}/pending", requireSession(http.HandlerFunc(h.ProviderAuth.ProviderPending)))
+	routes.API("POST /api/auth/{provider}/complete", requireSession(http.HandlerFunc(h.ProviderAuth.ProviderComplete)))
+	routes.API("GET /api/auth/providers", requireSession(http.HandlerFunc(h.ProviderAuth.ListProviders)))
+	routes.API("POST /api/auth/{provider}/link", requireSession(http.HandlerFunc(h.ProviderAuth.ProviderLink)))
+	routes.API("DELETE /api/auth/providers/{provider}", requireSession(http.HandlerFunc(h.ProviderAuth.ProviderUnlink)))
+
+	// Account endpoints
+	routes.API("GET /api/account/export", requireSession(http.HandlerFunc(h.Account.Export)))
+	routes.API("DELETE /api/account", requireSession(http.HandlerFunc(h.Account.Delete)))
+	routes.API("GET /api/usage", requireRead(http.HandlerFunc(h.Usage.Get)))
+
+	// API Token endpoints
+	routes.API("GET /api/tokens", requireSession(http.HandlerFunc(h.APIToken.List)))
+	routes.API("POST /api/tokens", requireSession(http.HandlerFunc(h.APIToken.Create)))
+	routes.API("DELETE /api/tokens/{id}", requireSession(http.HandlerFunc(h.APIToken.Delete)))
+	routes.API("DELETE /api/tokens", requireSession(http.HandlerFunc(h.APIToken.DeleteAll)))
+
+	// Webhook endpoints (session only, like API tokens)
+	routes.API("GET /api/webhooks", requireSession(http.HandlerFunc(h.Webhook.List)))
+	routes.API("POST /api/webhooks", requireSession(http.HandlerFunc(h.Webhook.Create)))
+	routes.API("DELETE /api/webhooks/{id}", requireSession(http.HandlerFunc(h.Webhook.Delete)))
+
+	// Card endpoints
+	routes.API("POST /api/cards", requireWrite(http.HandlerFunc(h.Card.Create)))
+	routes.API("GET /api/cards", requireRead(http.HandlerFunc(h.Card.List)))
+	routes.API("GET /api/cards/archive", requireSession(http.HandlerFunc(h.Card.Archive)))
+	routes.API("GET /api/cards/search", requireRead(http.HandlerFunc(h.Card.Search)))
+	routes.API("GET /api/cards/stats", requireRead(http.HandlerFunc(h.Card.AllStats)))
+	routes.API("GET /api/memories", requireRead(http.HandlerFunc(h.Card.Memories)))
+	routes.API("GET /api/cards/categories", requireRead(http.HandlerFunc(h.Card.GetCategories)))
+	routes.API("GET /api/cards/export", requireSession(http.HandlerFunc(h.Card.ListExportable)))
+	routes.API("POST /api/cards/import", requireSession(http.HandlerFunc(h.Card.Import)))
+	routes.API("POST /api/cards/import-json", requireSession(http.HandlerFunc(h.Card.ImportDocument)))
+	routes.API("PUT /api/cards/visibility/bulk", requireSession(http.HandlerFunc(h.Card.BulkUpdateVisibility)))
+	routes.API("DELETE /api/cards/bulk", requireSession(http.HandlerFunc(h.Card.BulkDelete)))
+	routes.API("PUT /api/cards/archive/bulk", requireSession(http.HandlerFunc(h.Card.BulkUpdateArchive)))
+	routes.API("GET /api/cards/draft-state", requireRead(http.HandlerFunc(h.Card.GetDraftState)))
+	routes.API("POST /api/cards/draft-state", requireWrite(http.HandlerFunc(h.Card.SaveDraftState)))
+	routes.API("DELETE /api/cards/draft-state", requireWrite(http.HandlerFunc(h.Card.DeleteDraftState)))
+	routes.API("GET /api/cards/{id}", requireRead(http.HandlerFunc(h.Card.Get)))
+	routes.API("DELETE /api/cards/{id}", requireSession(http.HandlerFunc(h.Card.Delete)))
+	routes.API("GET /api/cards/{id}/export.json", requireRead(http.HandlerFunc(h.Card.ExportDocument)))
+	routes.API("GET /api/cards/{id}/image.png", requireRead(http.HandlerFunc(h.Card.Image)))
+	routes.API("GET /api/cards/{id}/stats", requireRead(http.HandlerFunc(h.Card.Stats)))
+	routes.API("GET /api/cards/{id}/recommendations", requireRead(http.HandlerFunc(h.Card.Recommendations)))
+	routes.API("GET /api/cards/{id}/recap", requireRead(http.HandlerFunc(h.Card.Recap)))
+	routes.API("PUT /api/cards/{id}/meta", requireSession(http.HandlerFunc(h.Card.UpdateMeta)))
+	routes.API("PUT /api/cards/{id}/visibility", requireSession(http.HandlerFunc(h.Card.UpdateVisibility)))
+	routes.API("PUT /api/cards/{id}/config", requireWrite(http.HandlerFunc(h.Card.UpdateConfig)))
+	routes.API("POST /api/cards/{id}/clone", requireWrite(http.HandlerFunc(h.Card.Clone)))
+	routes.API("POST /api/cards/{id}/items", requireWrite(http.HandlerFunc(h.Card.AddItem)))
+	routes.API("PUT /api/cards/{id}/items/{pos}", requireWrite(http.HandlerFunc(h.Card.UpdateItem)))
+	routes.API("DELETE /api/cards/{id}/items/{pos}", requireWrite(http.HandlerFunc(h.Card.RemoveItem)))
+	routes.API("POST /api/cards/{id}/shuffle", requireWrite(http.HandlerFunc(h.Card.Shuffle)))
+	routes.API("POST /api/cards/{id}/shuffle/undo", requireWrite(http.HandlerFunc(h.Card.UndoShuffle)))
+	routes.API("POST /api/cards/{id}/swap", requireWrite(http.HandlerFunc(h.Card.SwapItems)))
+	routes.API("POST /api/cards/{id}/finalize", requireWrite(http.HandlerFunc(h.Card.Finalize)))
+	routes.API("POST /api/cards/{id}/share", requireSession(http.HandlerFunc(h.Card.CreateShare)))
+	routes.API("GET /api/cards/{id}/share", requireSession(http.HandlerFunc(h.Card.GetShareStatus)))
+	routes.API("GET /api/cards/{id}/share/analytics", requireSession(http.HandlerFunc(h.Card.GetShareAnalytics)))
+	routes.API("DELETE /api/cards/{id}/share", requireSession(http.HandlerFunc(h.Card.RevokeShare)))
+	routes.API("DELETE /api/cards/{id}/share/subscribers/{subscriberId}", requireSession(http.HandlerFunc(h.Card.RemoveShareSubscriber)))
+	routes.API("POST /api/cards/{id}/widget-token", requireSession(http.HandlerFunc(h.Card.CreateWidgetToken)))
+	routes.API("GET /api/cards/{id}/widget-tokens", requireSession(http.HandlerFunc(h.Card.ListWidgetTokens)))
+	routes.API("DELETE /api/cards/{id}/widget-tokens/{tokenId}", requireSession(http.HandlerFunc(h.Card.RevokeWidgetToken)))
+	routes.API("PUT /api/cards/{id}/items/{pos}/complete", requireWrite(http.HandlerFunc(h.Card.CompleteItem)))
+	routes.API("PUT /api/cards/{id}/items/complete-by-content", requireWrite(http.HandlerFunc(h.Card.CompleteItemByContent)))
+	routes.API("PUT /api/cards/{id}/items/{pos}/uncomplete", requireWrite(http.HandlerFunc(h.Card.UncompleteItem)))
+	routes.API("PUT /api/cards/{id}/items/{pos}/notes", requireWrite(http.HandlerFunc(h.Card.UpdateNotes)))
+	routes.API("GET /api/share/{token}", http.HandlerFunc(h.Card.GetSharedCard))
+	routes.API("POST /api/share/{token}/subscribe", mw.ShareSubscribeRateLimiter.Middleware(http.HandlerFunc(h.ShareSubscription.Subscribe)))
+
+	// Suggestion endpoints
+	routes.API("GET /api/suggestions", http.HandlerFunc(h.Suggestion.GetAll))
+	routes.API("GET /api/suggestions/categories", http.HandlerFunc(h.Suggestion.GetCategories))
+
+	// Friend endpoints
+	routes.API("GET /api/friends", requireSession(http.HandlerFunc(h.Friend.List)))
+	routes.API("GET /api/friends/search", requireSession(http.HandlerFunc(h.Friend.Search)))
+	routes.API("POST /api/friends/requests", requireSession(http.HandlerFunc(h.Friend.SendRequest)))
+	routes.API("PUT /api/friends/requests/{id}/accept", requireSession(http.HandlerFunc(h.Friend.AcceptRequest)))
+	routes.API("PUT /api/friends/requests/{id}/reject", requireSession(http.HandlerFunc(h.Friend.RejectRequest)))
+	routes.API("DELETE /api/friends/{id}", requireSession(http.HandlerFunc(h.Friend.Remove)))
+	routes.API("DELETE /api/friends/requests/{id}/cancel", requireSession(http.HandlerFunc(h.Friend.CancelRequest)))
+	routes.API("GET /api/friends/{id}/card", requireSession(http.HandlerFunc(h.Friend.GetFriendCard)))
+	routes.API("GET /api/friends/{id}/cards", requireSession(http.HandlerFunc(h.Friend.GetFriendCards)))
+	routes.API("POST /api/blocks", requireSession(http.HandlerFunc(h.Block.Block)))
+	routes.API("DELETE /api/blocks/{id}", requireSession(http.HandlerFunc(h.Block.Unblock)))
+	routes.API("GET /api/blocks", requireSession(http.HandlerFunc(h.Block.List)))
+	routes.API("POST /api/friends/invites", requireSession(http.HandlerFunc(h.Invite.Create)))
+	routes.API("GET /api/friends/invites", requireSession(http.HandlerFunc(h.Invite.List)))
+	routes.API("DELETE /api/friends/invites/{id}/revoke", requireSession(http.HandlerFunc(h.Invite.Revoke)))
+	routes.API("POST /api/friends/invites/accept", requireSession(http.HandlerFunc(h.Invite.Accept)))
+	routes.API("GET /api/notifications", requireSession(http.HandlerFunc(h.Notification.List)))
+	routes.API("POST /api/notifications/{id}/read", requireSession(http.HandlerFunc(h.Notification.MarkRead)))
+	routes.API("POST /api/notifications/read-all", requireSession(http.HandlerFunc(h.Notification.MarkAllRead)))
+	routes.API("DELETE /api/notifications/{id}", requireSession(http.HandlerFunc(h.Notification.Delete)))
+	routes.API("DELETE /api/notifications", requireSession(http.HandlerFunc(h.Notification.DeleteAll)))
+	routes.API("GET /api/notifications/unread-count", requireSession(http.HandlerFunc(h.Notification.UnreadCount)))
+	routes.API("GET /api/notifications/settings", requireSession(http.HandlerFunc(h.Notification.GetSettings)))
+	routes.API("PUT /api/notifications/settings", requireSession(http.HandlerFunc(h.Notification.UpdateSettings)))
+	routes.API("POST /api/notifications/settings/copy", requireSession(http.HandlerFunc(h.Notification.CopySettings)))
+	routes.API("PUT /api/notifications/pause", requireSession(http.HandlerFunc(h.Notification.SetEmailPause)))
+
+	// Reminder endpoints
+	routes.API("GET /api/reminders/settings", requireSession(http.HandlerFunc(h.Reminder.GetSettings)))
+	routes.API("PUT /api/reminders/settings", requireSession(http.HandlerFunc(h.Reminder.UpdateSettings)))
+	routes.API("GET /api/reminders/cards", requireSession(http.HandlerFunc(h.Reminder.ListCards)))
+	routes.API("PUT /api/reminders/cards/{cardId}", requireSession(http.HandlerFunc(h.Reminder.UpsertCardCheckin)))
+	routes.API("DELETE /api/reminders/cards/{cardId}", requireSession(http.HandlerFunc(h.Reminder.DeleteCardCheckin)))
+	routes.API("GET /api/reminders/goals", requireSession(http.HandlerFunc(h.Reminder.ListGoals)))
+	routes.API("POST /api/reminders/goals", requireSession(http.HandlerFunc(h.Reminder.UpsertGoalReminder)))
+	routes.API("POST /api/reminders/goals/bulk", requireSession(http.HandlerFunc(h.Reminder.BulkUpsertGoalReminders)))
+	routes.API("DELETE /api/reminders/goals/{id}", requireSession(http.HandlerFunc(h.Reminder.DeleteGoalReminder)))
+	routes.API("POST /api/reminders/test", requireSession(http.HandlerFunc(h.Reminder.SendTest)))
+	routes.API("POST /api/reminders/validate-schedule", requireSession(http.HandlerFunc(h.Reminder.ValidateSchedule)))
+	routes.API("GET /api/reminders/deliverability-check", requireSession(http.HandlerFunc(h.Reminder.GetDeliverability)))
+	routes.API("POST /api/reminders/deliverability-check", requireSession(http.HandlerFunc(h.Reminder.RunDeliverability)))
+	routes.API("DELETE /api/reminders/image-tokens", requireSession(http.HandlerFunc(h.Reminder.RevokeImageTokens)))
+	routes.API("GET /api/reminders/history", requireSession(http.HandlerFunc(h.Reminder.History)))
+	routes.API("GET /api/reminders/calendar-feed", requireSession(http.HandlerFunc(h.Reminder.GetCalendarFeed)))
+	routes.API("POST /api/reminders/calendar-feed", requireSession(http.HandlerFunc(h.Reminder.CreateCalendarFeed)))
+	routes.API("DELETE /api/reminders/calendar-feed", requireSession(http.HandlerFunc(h.Reminder.RevokeCalendarFeed)))
+	routes.API("GET /api/reminders/calendar.ics", http.HandlerFunc(h.Reminder.CalendarFeed))
+
+	// Admin endpoints
+	routes.API("POST /api/admin/reminders/run", requireAdmin(http.HandlerFunc(h.Admin.RunReminders)))
+	routes.API("POST /api/admin/reminders/{reminderId}/resend", requireAdmin(http.HandlerFunc(h.Admin.ResendReminder)))
+	routes.API("GET /api/admin/users/{id}/reminders", requireAdmin(http.HandlerFunc(h.Admin.UserReminders)))
+	routes.API("PUT /api/admin/users/{id}/ai-quota", requireAdmin(http.HandlerFunc(h.Admin.SetAIQuota)))
+	routes.API("GET /api/admin/search", requireAdmin(http.HandlerFunc(h.Admin.Search)))
+	routes.API("GET /api/admin/suggestions/analytics", requireAdmin(http.HandlerFunc(h.Admin.SuggestionAnalytics)))
+	routes.API("GET /api/admin/jobs", requireAdminOrInternal(http.HandlerFunc(h.Jobs.List)))
+
+	// Reaction endpoints
+	routes.API("POST /api/items/{id}/react", requireSession(mw.ReactionRateLimiter.Middleware(http.HandlerFunc(h.Reaction.AddReaction))))
+	routes.API("DELETE /api/items/{id}/react", requireSession(mw.ReactionRateLimiter.Middleware(http.HandlerFunc(h.Reaction.RemoveReaction))))
+	routes.API("GET /api/items/{id}/reactions", requireSession(http.HandlerFunc(h.Reaction.GetReactions)))
+	routes.API("GET /api/reactions/emojis", requireSession(http.HandlerFunc(h.Reaction.GetAllowedEmojis)))
+
+	// Comment endpoints
+	routes.API("POST /api/items/{id}/comments", requireSession(mw.CommentRateLimiter.Middleware(http.HandlerFunc(h.Comment.Add))))
+	routes.API("GET /api/items/{id}/comments", requireSession(http.HandlerFunc(h.Comment.List)))
+	routes.API("DELETE /api/comments/{id}", requireSession(http.HandlerFunc(h.Comment.Delete)))
+
+	// Support endpoint
+	routes.API("POST /api/support", requireSession(http.HandlerFunc(h.Support.Submit)))
+
+	// AI endpoint
+	routes.API("POST /api/ai/generate", requireSession(mw.AIRateLimiter.Middleware(http.HandlerFunc(h.AI.Generate))))
+	routes.API("POST /api/ai/guide", requireSession(mw.AIRateLimiter.Middleware(http.HandlerFunc(h.AI.Guide))))
+	routes.API("GET /api/ai/quota", requireSession(http.HandlerFunc(h.AI.Quota)))
+
+	// Static files
+	fs := http.FileServer(http.Dir(h.StaticDir))
+	routes.Handle("GET /static/", http.StripPrefix("/static/", fs))
+
+	// Reminder public endpoints
+	routes.Handle("GET /r/img/{token}", http.HandlerFunc(h.ReminderPublic.ServeImage))
+	routes.Handle("GET /r/go/{token}", http.HandlerFunc(h.ReminderPublic.FollowLink))
+	routes.Handle("GET /r/unsubscribe", http.HandlerFunc(h.ReminderPublic.UnsubscribeConfirm))
+	routes.Handle("POST /r/unsubscribe", http.HandlerFunc(h.ReminderPublic.UnsubscribeSubmit))
+	routes.Handle("GET /r/snooze", http.HandlerFunc(h.ReminderPublic.SnoozeConfirm))
+	routes.Handle("POST /r/snooze", http.HandlerFunc(h.ReminderPublic.SnoozeSubmit))
+	routes.Handle("GET /r/preferences", http.HandlerFunc(h.ReminderPublic.PreferencesPage))
+	routes.Handle("POST /r/preferences", http.HandlerFunc(h.ReminderPublic.PreferencesSubmit))
+	routes.Handle("GET /r/watch/confirm", http.HandlerFunc(h.ShareSubscription.ConfirmPage))
+	routes.Handle("POST /r/watch/confirm", http.HandlerFunc(h.ShareSubscription.ConfirmSubmit))
+	routes.Handle("GET /r/watch/unsubscribe", http.HandlerFunc(h.ShareSubscription.UnsubscribePage))
+	routes.Handle("POST /r/watch/unsubscribe", http.HandlerFunc(h.ShareSubscription.UnsubscribeSubmit))
+
+	// OpenGraph images (public)
+	routes.Handle("GET /og/default.png", http.HandlerFunc(h.OGImage.Default))
+	routes.Handle("GET /og/share/{token}", http.HandlerFunc(h.ShareOGImage.Serve))
+	routes.Handle("GET /og/profile/{username}", http.HandlerFunc(h.ProfilePublic.ServeOGImage))
+
+	// Progress images for home- and lock-screen widgets (public, by token)
+	routes.Handle("GET /w/{token}", mw.WidgetRateLimiter.Middleware(http.HandlerFunc(h.Card.ServeWidgetImage)))
+
+	// Uploaded proof photos (public, by unguessable URL)
+	routes.Handle("GET /proofs/{key...}", http.HandlerFunc(h.Proof.Serve))
+
+	// Public share landing page (for link unfurls)
+	routes.Handle("GET /s/{token}", http.HandlerFunc(h.SharePublic.Serve))
+	routes.Handle("GET /s/{token}/print", http.HandlerFunc(h.SharePublic.Print))
+	routes.Handle("GET /s/{token}/recap", http.HandlerFunc(h.SharePublic.Recap))
+
+	// Opt-in public profiles
+	routes.Handle("GET /u/{username}", http.HandlerFunc(h.ProfilePublic.Serve))
+
+	// Experimental ActivityPub actors for public profiles
+	if h.ActivityPub != nil {
+		routes.Handle("GET /.well-known/webfinger", http.HandlerFunc(h.ActivityPub.WebFinger))
+		routes.Handle("GET /ap/users/{username}", http.HandlerFunc(h.ActivityPub.Actor))
+		routes.Handle("GET /ap/users/{username}/outbox", http.HandlerFunc(h.ActivityPub.Outbox))
+		routes.Handle("GET /ap/users/{username}/followers", http.HandlerFunc(h.ActivityPub.Followers))
+		routes.Handle("GET /ap/users/{username}/activities/{id}", http.HandlerFunc(h.ActivityPub.Activity))
+		routes.Handle("GET /ap/users/{username}/notes/{id}", http.HandlerFunc(h.ActivityPub.Note))
+		routes.Handle("POST /ap/users/{username}/inbox", mw.ActivityPubInboxLimiter.Middleware(http.HandlerFunc(h.ActivityPub.Inbox)))
+	}
+
+	// API Docs redirect
+	routes.API("GET /api/docs", http.RedirectHandler("/static/swagger/index.html", http.StatusFound))
+
+	// SPA route - serve index.html for all client-side routes
+	routes.Handle("GET /{path...}", requireSession(http.HandlerFunc(h.Page.Index)))
+
+	return routes
+}
+
+// wrap applies the middleware chain around the router. Each layer wraps the
+// ones before it, so the request logger runs first and the disconnect
+// watchdog last.
+func wrap(next http.Handler, mw Middleware) http.Handler {
+	var handler http.Handler = next
+	handler = mw.DisconnectWatchdog.Apply(handler)
+	handler = mw.UsageTracker.Apply(handler)
+	handler = mw.Auth.Authenticate(handler)
+	handler = mw.CSRF.Protect(handler)
+	handler = mw.CacheControl.Apply(handler)
+	handler = mw.Compress.Apply(handler)
+	handler = mw.SecurityHeaders.Apply(handler)
+	handler = mw.RequestLogger.Apply(handler)
+	return handler
+}
@@ -0,0 +1,380 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/database"
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/middleware"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+// publicAPIRoutes are the /api routes documented as usable without signing
+// in. Every other /api route must answer an anonymous caller with 401.
+var publicAPIRoutes = map[string]bool{
+	"GET /api/version":                   true,
+	"GET /api/csrf":                      true,
+	"GET /api/docs":                      true,
+	"POST /api/auth/register":            true,
+	"POST /api/auth/login":               true,
+	"POST /api/auth/logout":              true,
+	"POST /api/auth/verify-email":        true,
+	"POST /api/auth/magic-link":          true,
+	"POST /api/auth/magic-link/verify":   true,
+	"GET /api/auth/magic-link/verify":    true,
+	"POST /api/auth/forgot-password":     true,
+	"POST /api/auth/reset-password":      true,
+	"GET /api/auth/{provider}/start":     true,
+	"GET /api/auth/{provider}/callback":  true,
+	"GET /api/auth/{provider}/pending":   true,
+	"POST /api/auth/{provider}/complete": true,
+	"GET /api/share/{token}":             true,
+	"POST /api/share/{token}/subscribe":  true,
+	"GET /api/suggestions":               true,
+	"GET /api/suggestions/categories":    true,
+	"GET /api/reminders/calendar.ics":    true,
+	"POST /api/support":                  true,
+	"GET /api/reactions/emojis":          true,
+}
+
+var pathParamPattern = regexp.MustCompile(`\{[^}]+\}`)
+
+type testServer struct {
+	handler http.Handler
+	routes  []route
+	users   *services.UserService
+	auth    *services.AuthService
+}
+
+// newTestServer boots the real route table and middleware chain. Sessions
+// and users are backed by SQLite and an in-memory Redis; every other
+// service is left nil, so a route that reaches its service without
+// authenticating first panics the test. Tests that need more real services
+// pass wire funcs, which may swap handlers before the routes are built.
+func newTestServer(t *testing.T, wire ...func(h *Handlers, db services.DB)) *testServer {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bingo.db")
+	db, err := database.NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(db.Close)
+	migrator, err := database.NewMigrator(database.SQLiteMigrationURL(path), "../../migrations/sqlite")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = migrator.Close() }()
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("unexpected migration error: %v", err)
+	}
+	redisDB, err := database.NewMemoryRedisDB()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = redisDB.Close() })
+
+	dbAdapter := services.NewSQLiteAdapter(db.DB)
+	redisAdapter := services.NewRedisAdapter(redisDB.Client)
+	userService := services.NewUserService(dbAdapter)
+	authService := services.NewAuthService(dbAdapter, redisAdapter)
+	apiTokenService := services.NewApiTokenService(dbAdapter)
+
+	pageHandler, err := handlers.NewPageHandler("../../web/templates", handlers.PageOAuthConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sharePublicHandler, err := handlers.NewSharePublicHandler("../../web/templates", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	profilePublicHandler, err := handlers.NewProfilePublicHandler("../../web/templates", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	h := Handlers{
+		Health:            handlers.NewHealthHandler(db, redisDB),
+		Version:           handlers.NewVersionHandler("test", ""),
+		Auth:              handlers.NewAuthHandler(userService, authService, nil, false),
+		ProviderAuth:      handlers.NewProviderAuthHandler(nil, authService, redisAdapter, nil, false),
+		Account:           handlers.NewAccountHandler(nil, authService, false),
+		Profile:           handlers.NewProfileHandler(nil),
+		Usage:             handlers.NewUsageHandler(nil),
+		APIToken:          handlers.NewApiTokenHandler(apiTokenService),
+		Webhook:           handlers.NewWebhookHandler(nil),
+		Card:              handlers.NewCardHandler(nil),
+		ShareSubscription: handlers.NewShareSubscriptionHandler(nil),
+		Suggestion:        handlers.NewSuggestionHandler(nil),
+		Friend:            handlers.NewFriendHandler(nil, nil),
+		Block:             handlers.NewBlockHandler(nil),
+		Invite:            handlers.NewFriendInviteHandler(nil),
+		Notification:      handlers.NewNotificationHandler(nil),
+		Reminder:          handlers.NewReminderHandler(nil),
+		ReminderPublic:    handlers.NewReminderPublicHandler(nil),
+		Admin:             handlers.NewAdminHandler(nil, nil),
+		Jobs:              handlers.NewJobsHandler(services.NewJobRegistry()),
+		Reaction:          handlers.NewReactionHandler(nil),
+		Comment:           handlers.NewCommentHandler(nil),
+		Support:           handlers.NewSupportHandler(nil, redisDB.Client),
+		AI:                handlers.NewAIHandler(nil),
+		Proof:             handlers.NewProofHandler(nil),
+		OGImage:           handlers.NewOGImageHandler(),
+		ShareOGImage:      handlers.NewShareOGImageHandler(nil),
+		SharePublic:       sharePublicHandler,
+		ProfilePublic:     profilePublicHandler,
+		Page:              pageHandler,
+		StaticDir:         "../../web/static",
+	}
+	logger := logging.New()
+	mw := Middleware{
+		Auth:                      middleware.NewAuthMiddleware(authService, userService, apiTokenService),
+		CSRF:                      middleware.NewCSRFMiddleware(false),
+		SecurityHeaders:           middleware.NewSecurityHeaders(false),
+		CacheControl:              middleware.NewCacheControl(),
+		Compress:                  middleware.NewCompress(),
+		RequestLogger:             middleware.NewRequestLogger(logger),
+		DisconnectWatchdog:        middleware.NewDisconnectWatchdog(logger, 0),
+		UsageTracker:              middleware.NewUsageTracker(nil),
+		AdminGate:                 middleware.NewAdminGate(nil),
+		InternalTokenGate:         middleware.NewInternalTokenGate(""),
+		AIRateLimiter:             middleware.NewRateLimiter(redisDB.Client, 10, 0, "test:ai:", middleware.RateLimitKeyByCaller, true),
+		ReactionRateLimiter:       middleware.NewRateLimiter(redisDB.Client, 10, 0, "test:reactions:", middleware.RateLimitKeyByCaller, true),
+		CommentRateLimiter:        middleware.NewRateLimiter(redisDB.Client, 10, 0, "test:comments:", middleware.RateLimitKeyByCaller, true),
+		WidgetRateLimiter:         middleware.NewRateLimiter(redisDB.Client, 10, 0, "test:widget:", middleware.GetClientIP, true),
+		ShareSubscribeRateLimiter: middleware.NewRateLimiter(redisDB.Client, 10, 0, "test:share-subscribe:", middleware.GetClientIP, false),
+	}
+
+	for _, fn := range wire {
+		fn(&h, dbAdapter)
+	}
+
+	return &testServer{
+		handler: New(h, mw),
+		routes:  newRoutes(h, mw).Routes(),
+		users:   userService,
+		auth:    authService,
+	}
+}
+
+// do sends a request through the full chain without a CSRF token.
+func (s *testServer) do(method, path string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rr := httptest.NewRecorder()
+	s.handler.ServeHTTP(rr, req)
+	return rr
+}
+
+// withCSRF builds a request carrying a matching CSRF cookie and header.
+func withCSRF(method, path string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "tok"})
+	req.Header.Set("X-CSRF-Token", "tok")
+	return req
+}
+
+func assertSecurityHeaders(t *testing.T, label string, header http.Header) {
+	t.Helper()
+	for _, name := range []string{"X-Frame-Options", "X-Content-Type-Options", "Referrer-Policy", "Content-Security-Policy"} {
+		if header.Get(name) == "" {
+			t.Fatalf("%s: expected %s on the response", label, name)
+		}
+	}
+}
+
+func TestServer_APIRoutesRequireAuthUnlessPublic(t *testing.T) {
+	s := newTestServer(t)
+	registered := map[string]bool{}
+	for _, rt := range s.routes {
+		if rt.APIVersion == "" {
+			continue
+		}
+		key := rt.Method + " " + rt.Path
+		registered[key] = true
+		if publicAPIRoutes[key] {
+			continue
+		}
+
+		concrete := pathParamPattern.ReplaceAllString(rt.Path, uuid.NewString())
+		for _, path := range []string{concrete, versionedAPIPrefix + strings.TrimPrefix(concrete, apiPrefix)} {
+			rr := httptest.NewRecorder()
+			s.handler.ServeHTTP(rr, withCSRF(rt.Method, path))
+			if rr.Code != http.StatusUnauthorized {
+				t.Errorf("%s %s: expected 401 for an anonymous caller, got %d %s", rt.Method, path, rr.Code, rr.Body.String())
+			}
+			assertSecurityHeaders(t, rt.Method+" "+path, rr.Header())
+		}
+	}
+
+	// Keep the allowlist honest: every public route must still exist.
+	for key, public := range publicAPIRoutes {
+		if public && !registered[key] {
+			t.Errorf("public route %s is not registered", key)
+		}
+	}
+}
+
+// openAPIOperations reads the operations under paths in the OpenAPI spec,
+// keyed "METHOD /api/path", and whether each is marked deprecated. The spec's
+// layout is regular enough that indentation alone finds them.
+func openAPIOperations(t *testing.T, path string) map[string]bool {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading spec: %v", err)
+	}
+	ops := map[string]bool{}
+	inPaths := false
+	specPath, op := "", ""
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		case indent == 0:
+			inPaths = trimmed == "paths:"
+			specPath, op = "", ""
+		case !inPaths:
+		case indent == 2:
+			specPath, op = strings.TrimSuffix(trimmed, ":"), ""
+		case indent == 4:
+			op = ""
+			if method := strings.TrimSuffix(trimmed, ":"); openAPIMethods[method] {
+				op = strings.ToUpper(method) + " /api" + specPath
+				ops[op] = false
+			}
+		case indent == 6 && op != "" && trimmed == "deprecated: true":
+			ops[op] = true
+		}
+	}
+	return ops
+}
+
+var openAPIMethods = map[string]bool{"get": true, "post": true, "put": true, "patch": true, "delete": true}
+
+func TestServer_APIRoutesMatchOpenAPISpec(t *testing.T) {
+	s := newTestServer(t)
+	ops := openAPIOperations(t, "../../web/static/openapi.yaml")
+	if len(ops) == 0 {
+		t.Fatal("expected operations in the spec")
+	}
+	for _, rt := range s.routes {
+		if rt.APIVersion == "" {
+			continue
+		}
+		key := rt.Method + " " + rt.Path
+		deprecated, ok := ops[key]
+		if !ok {
+			t.Errorf("%s is not documented in openapi.yaml", key)
+			continue
+		}
+		if deprecated != rt.Deprecated {
+			t.Errorf("%s: spec deprecated=%v, route deprecated=%v", key, deprecated, rt.Deprecated)
+		}
+	}
+}
+
+func TestServer_CSRFGuardsSessionWrites(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	user, err := s.users.Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "solo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	token, err := s.auth.CreateSession(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("unexpected error creating session: %v", err)
+	}
+	session := &http.Cookie{Name: "session_token", Value: token}
+
+	if rr := s.do(http.MethodGet, "/api/auth/me", session); rr.Code != http.StatusOK {
+		t.Fatalf("expected the session to authenticate reads without CSRF, got %d", rr.Code)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		cookie string
+		header string
+	}{
+		{"no token", "", ""},
+		{"header only", "", "tok"},
+		{"cookie only", "tok", ""},
+		{"mismatch", "tok", "other"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil)
+		req.AddCookie(session)
+		if tc.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "csrf_token", Value: tc.cookie})
+		}
+		if tc.header != "" {
+			req.Header.Set("X-CSRF-Token", tc.header)
+		}
+		rr := httptest.NewRecorder()
+		s.handler.ServeHTTP(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("%s: expected 403, got %d", tc.name, rr.Code)
+		}
+		assertSecurityHeaders(t, tc.name, rr.Header())
+	}
+	if rr := s.do(http.MethodGet, "/api/auth/me", session); rr.Code != http.StatusOK {
+		t.Fatalf("expected rejected logouts to leave the session alone, got %d", rr.Code)
+	}
+
+	req := withCSRF(http.MethodPost, "/api/auth/logout")
+	req.AddCookie(session)
+	rr := httptest.NewRecorder()
+	s.handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected logout with a CSRF token to succeed, got %d %s", rr.Code, rr.Body.String())
+	}
+	if rr := s.do(http.MethodGet, "/api/auth/me", session); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the session to be gone after logout, got %d", rr.Code)
+	}
+}
+
+func TestServer_SecurityHeadersOnEveryResponse(t *testing.T) {
+	s := newTestServer(t)
+	for _, tc := range []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/live", http.StatusOK},
+		{http.MethodGet, "/health", http.StatusOK},
+		{http.MethodGet, "/api/version", http.StatusOK},
+		{http.MethodGet, "/api/v1/version", http.StatusOK},
+		{http.MethodGet, "/api/docs", http.StatusFound},
+		{http.MethodGet, "/static/openapi.yaml", http.StatusOK},
+		{http.MethodGet, "/", http.StatusOK},
+		{http.MethodGet, "/static/missing.js", http.StatusNotFound},
+		{http.MethodPut, "/api/version", http.StatusForbidden},
+		{http.MethodHead, "/api/version", http.StatusOK},
+	} {
+		rr := s.do(tc.method, tc.path)
+		if rr.Code != tc.status {
+			t.Fatalf("%s %s: expected %d, got %d", tc.method, tc.path, tc.status, rr.Code)
+		}
+		assertSecurityHeaders(t, tc.method+" "+tc.path, rr.Header())
+	}
+	rr := httptest.NewRecorder()
+	s.handler.ServeHTTP(rr, withCSRF(http.MethodPut, "/api/version"))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for a wrong method past CSRF, got %d", rr.Code)
+	}
+	assertSecurityHeaders(t, "405", rr.Header())
+}
@@ -0,0 +1,156 @@
+package httpserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/middleware"
+)
+
+const (
+	apiPrefix          = "/api/"
+	versionedAPIPrefix = "/api/v" + handlers.APIVersion + "/"
+)
+
+// route is one entry in the route registry. API routes carry their version
+// metadata so the mux and the OpenAPI spec can be checked against one list.
+type route struct {
+	Method       string
+	Path         string
+	APIVersion   string
+	Deprecated   bool
+	DeprecatedAt time.Time
+	Sunset       time.Time
+}
+
+type routeOption func(*route)
+
+// deprecated marks an API route with Deprecation/Sunset headers.
+func deprecated(deprecatedAt, sunset time.Time) routeOption {
+	return func(r *route) {
+		r.Deprecated = true
+		r.DeprecatedAt = deprecatedAt
+		r.Sunset = sunset
+	}
+}
+
+// router registers handlers on a ServeMux and records them in a registry.
+type router struct {
+	mux    *http.ServeMux
+	routes []route
+}
+
+func newRouter() *router {
+	return &router{mux: http.NewServeMux()}
+}
+
+// Handle registers a non-API route as-is.
+func (rt *router) Handle(pattern string, h http.Handler) {
+	method, path := splitPattern(pattern)
+	rt.mux.Handle(pattern, headAware(method, h))
+	rt.routes = append(rt.routes, route{Method: method, Path: path})
+}
+
+// API registers a route under /api and its /api/v1 alias. Both paths share the
+// same handler and deprecation metadata.
+func (rt *router) API(pattern string, h http.Handler, opts ...routeOption) {
+	method, path := splitPattern(pattern)
+	if !strings.HasPrefix(path, apiPrefix) {
+		panic("router: API route must start with " + apiPrefix + ": " + pattern)
+	}
+
+	entry := route{Method: method, Path: path, APIVersion: handlers.APIVersion}
+	for _, opt := range opts {
+		opt(&entry)
+	}
+	if entry.Deprecated {
+		h = middleware.NewDeprecation(entry.DeprecatedAt, entry.Sunset, "/api/docs").Apply(h)
+	}
+
+	h = headAware(method, h)
+	versionedPath := versionedAPIPrefix + strings.TrimPrefix(path, apiPrefix)
+	rt.mux.Handle(joinPattern(method, path), h)
+	rt.mux.Handle(joinPattern(method, versionedPath), h)
+	rt.routes = append(rt.routes, entry)
+}
+
+// Routes returns the registered routes in registration order.
+func (rt *router) Routes() []route {
+	return rt.routes
+}
+
+func (rt *router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.mux.ServeHTTP(w, r)
+}
+
+// headAware makes a GET handler answer HEAD properly. The mux already routes
+// HEAD to GET patterns (and answers other unregistered methods with 405 and an
+// Allow header), but handlers write their bodies regardless, and net/http only
+// fills in Content-Length for small ones. Writes are counted and dropped so
+// the response reports the length a GET would have sent.
+func headAware(method string, h http.Handler) http.Handler {
+	if method != http.MethodGet {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			h.ServeHTTP(w, r)
+			return
+		}
+		hw := &headResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(hw, r)
+		hw.finish()
+	})
+}
+
+// headResponseWriter holds back the status line until the handler returns so
+// the counted body length can still be added as a header.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	length      int64
+}
+
+func (w *headResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	w.length += int64(len(b))
+	return len(b), nil
+}
+
+func (w *headResponseWriter) finish() {
+	header := w.Header()
+	if header.Get("Content-Length") == "" && w.length > 0 && bodyAllowed(w.status) {
+		header.Set("Content-Length", strconv.FormatInt(w.length, 10))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+func splitPattern(pattern string) (string, string) {
+	if method, path, ok := strings.Cut(pattern, " "); ok {
+		return method, path
+	}
+	return "", pattern
+}
+
+func joinPattern(method, path string) string {
+	if method == "" {
+		return path
+	}
+	return method + " " + path
+}
@@ -0,0 +1,157 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouterAPI_RegistersVersionedAlias(t *testing.T) {
+	rt := newRouter()
+	rt.API("GET /api/cards/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.PathValue("id")))
+	}))
+
+	for _, path := range []string{"/api/cards/abc", "/api/v1/cards/abc"} {
+		rr := httptest.NewRecorder()
+		rt.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", path, rr.Code)
+		}
+		if rr.Body.String() != "abc" {
+			t.Fatalf("%s: expected path value abc, got %q", path, rr.Body.String())
+		}
+		if rr.Header().Get("Deprecation") != "" {
+			t.Fatalf("%s: expected no Deprecation header", path)
+		}
+	}
+
+	routes := rt.Routes()
+	if len(routes) != 1 {
+		t.Fatalf("expected 1 registered route, got %d", len(routes))
+	}
+	if routes[0].Method != http.MethodGet || routes[0].Path != "/api/cards/{id}" || routes[0].APIVersion != "1" {
+		t.Fatalf("unexpected route entry: %+v", routes[0])
+	}
+}
+
+func TestRouterAPI_DeprecatedRouteSetsHeaders(t *testing.T) {
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	rt := newRouter()
+	rt.API("POST /api/old", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}), deprecated(time.Time{}, sunset))
+
+	for _, path := range []string{"/api/old", "/api/v1/old"} {
+		rr := httptest.NewRecorder()
+		rt.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, path, nil))
+		if rr.Code != http.StatusNoContent {
+			t.Fatalf("%s: expected status 204, got %d", path, rr.Code)
+		}
+		if rr.Header().Get("Deprecation") != "true" {
+			t.Fatalf("%s: expected Deprecation header, got %q", path, rr.Header().Get("Deprecation"))
+		}
+		if rr.Header().Get("Sunset") != "Fri, 01 Jan 2027 00:00:00 GMT" {
+			t.Fatalf("%s: unexpected Sunset header %q", path, rr.Header().Get("Sunset"))
+		}
+	}
+
+	if !rt.Routes()[0].Deprecated {
+		t.Fatal("expected registry entry to be marked deprecated")
+	}
+}
+
+func TestRouterHandle_NonAPIRouteHasNoAlias(t *testing.T) {
+	rt := newRouter()
+	rt.Handle("GET /health", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	rt.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rr.Code)
+	}
+	if rt.Routes()[0].APIVersion != "" {
+		t.Fatalf("expected non-API route to have no version, got %q", rt.Routes()[0].APIVersion)
+	}
+}
+
+func TestRouterAPI_PanicsOnNonAPIPath(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for non-API path")
+		}
+	}()
+	newRouter().API("GET /health", http.NotFoundHandler())
+}
+
+func TestRouter_HeadAndMethodNotAllowed(t *testing.T) {
+	body := func(payload string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(payload))
+		})
+	}
+	rt := newRouter()
+	rt.Handle("GET /health", body(`{"status":"ok"}`))
+	rt.Handle("GET /r/img/{token}", body(strings.Repeat("i", 5000)))
+	rt.Handle("GET /og/share/{token}", body(strings.Repeat("o", 70000)))
+	rt.Handle("GET /s/{token}", body("<html>share</html>"))
+	rt.Handle("POST /r/unsubscribe", body("done"))
+	rt.API("GET /api/version", body(`{"version":"test"}`))
+	rt.Handle("GET /{path...}", body("<html>app</html>"))
+
+	for _, path := range []string{"/health", "/r/img/abc", "/og/share/abc", "/s/abc", "/api/v1/version"} {
+		get := httptest.NewRecorder()
+		rt.ServeHTTP(get, httptest.NewRequest(http.MethodGet, path, nil))
+
+		head := httptest.NewRecorder()
+		rt.ServeHTTP(head, httptest.NewRequest(http.MethodHead, path, nil))
+		if head.Code != http.StatusOK {
+			t.Fatalf("HEAD %s: expected status 200, got %d", path, head.Code)
+		}
+		if head.Body.Len() != 0 {
+			t.Fatalf("HEAD %s: expected no body, got %d bytes", path, head.Body.Len())
+		}
+		if got, want := head.Header().Get("Content-Length"), strconv.Itoa(get.Body.Len()); got != want {
+			t.Fatalf("HEAD %s: expected Content-Length %s, got %q", path, want, got)
+		}
+		if head.Header().Get("Content-Type") != "text/plain" {
+			t.Fatalf("HEAD %s: expected GET headers, got %v", path, head.Header())
+		}
+
+		post := httptest.NewRecorder()
+		rt.ServeHTTP(post, httptest.NewRequest(http.MethodPost, path, nil))
+		if post.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("POST %s: expected status 405, got %d", path, post.Code)
+		}
+		if allow := post.Header().Get("Allow"); !strings.Contains(allow, "GET") || !strings.Contains(allow, "HEAD") {
+			t.Fatalf("POST %s: expected Allow to list GET and HEAD, got %q", path, allow)
+		}
+	}
+
+	// Unsupported methods on a POST-only route are refused, not routed to the SPA.
+	rr := httptest.NewRecorder()
+	rt.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/r/unsubscribe", nil))
+	if rr.Code != http.StatusMethodNotAllowed || !strings.Contains(rr.Header().Get("Allow"), "POST") {
+		t.Fatalf("DELETE /r/unsubscribe: expected 405 allowing POST, got %d %q", rr.Code, rr.Header().Get("Allow"))
+	}
+}
+
+func TestHeadAware_KeepsHandlerStatusAndLength(t *testing.T) {
+	h := headAware(http.MethodGet, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "42")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodHead, "/s/missing", nil))
+	if rr.Code != http.StatusNotFound || rr.Header().Get("Content-Length") != "42" || rr.Body.Len() != 0 {
+		t.Fatalf("expected 404 with handler's length and no body, got %d %q %q", rr.Code, rr.Header().Get("Content-Length"), rr.Body.String())
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+)
+
+// AdminGate restricts routes to a configured set of admin user IDs.
+type AdminGate struct {
+	admins map[uuid.UUID]struct{}
+}
+
+func NewAdminGate(adminIDs []uuid.UUID) *AdminGate {
+	admins := make(map[uuid.UUID]struct{}, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = struct{}{}
+	}
+	return &AdminGate{admins: admins}
+}
+
+// IsAdmin reports whether userID is a configured admin.
+func (g *AdminGate) IsAdmin(userID uuid.UUID) bool {
+	_, ok := g.admins[userID]
+	return ok
+}
+
+// Require rejects unauthenticated requests with 401 and non-admins with 403.
+func (g *AdminGate) Require(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := handlers.GetUserFromContext(r.Context())
+		if user == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"Authentication required"}`))
+			return
+		}
+		if !g.IsAdmin(user.ID) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"Admin access required"}`))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestAdminGate_Require(t *testing.T) {
+	adminID := uuid.New()
+	gate := NewAdminGate([]uuid.UUID{adminID})
+
+	tests := []struct {
+		name       string
+		user       *models.User
+		wantStatus int
+		wantCalled bool
+	}{
+		{name: "unauthenticated", wantStatus: http.StatusUnauthorized},
+		{name: "non-admin", user: &models.User{ID: uuid.New()}, wantStatus: http.StatusForbidden},
+		{name: "admin", user: &models.User{ID: adminID}, wantStatus: http.StatusOK, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := gate.Require(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/users/x/reminders", nil)
+			if tt.user != nil {
+				req = req.WithContext(handlers.SetUserInContext(req.Context(), tt.user))
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestAdminGate_EmptyAllowsNobody(t *testing.T) {
+	gate := NewAdminGate(nil)
+	if gate.IsAdmin(uuid.New()) {
+		t.Fatal("expected empty gate to reject every user")
+	}
+}
@@ -39,9 +39,12 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 				// Valid token, get user
 				user, err := m.userService.GetByID(r.Context(), token.UserID)
 				if err == nil {
-					// Add user and scope to context
-					ctx := handlers.SetUserInContext(r.Context(), user)
-					ctx = handlers.SetTokenScopeInContext(ctx, token.Scope)
+					ctx := handlers.SetAuthInfoInContext(r.Context(), &handlers.AuthInfo{
+						User:    user,
+						Method:  handlers.AuthMethodToken,
+						TokenID: token.ID,
+						Scope:   token.Scope,
+					})
 
 					// Update last used
 					_ = m.apiTokenService.UpdateLastUsed(r.Context(), token.ID)
@@ -66,8 +69,7 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
-		// Add user to context
-		ctx := handlers.SetUserInContext(r.Context(), user)
+		ctx := handlers.SetAuthInfoInContext(r.Context(), &handlers.AuthInfo{User: user, Method: handlers.AuthMethodSession})
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -350,6 +350,10 @@ func TestAuthMiddleware_Authenticate_BearerToken(t *testing.T) {
 		if scope != models.ScopeRead {
 			t.Fatalf("expected scope read, got %q", scope)
 		}
+		info := handlers.GetAuthInfoFromContext(r.Context())
+		if !info.IsToken() || info.TokenID != tokenID || info.Scope != models.ScopeRead {
+			t.Fatalf("expected token auth info, got %+v", info)
+		}
 	})
 
 	req := httptest.NewRequest(http.MethodGet, "/api/protected", nil)
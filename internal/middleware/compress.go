@@ -27,18 +27,33 @@ var gzipPool = sync.Pool{
 }
 
 // Compress provides gzip compression for responses.
-type Compress struct{}
+type Compress struct {
+	// maxDecodedRequestBytes caps the decompressed size of gzip request bodies.
+	// Zero (the default) rejects all Content-Encoding'd request bodies.
+	maxDecodedRequestBytes int64
+}
 
 // NewCompress creates a new compression middleware.
 func NewCompress() *Compress {
 	return &Compress{}
 }
 
+// SetRequestDecodingLimit enables gzip request bodies, capped at limit bytes
+// once decompressed. A limit <= 0 disables request decoding.
+func (c *Compress) SetRequestDecodingLimit(limit int64) {
+	c.maxDecodedRequestBytes = limit
+}
+
 // Apply adds gzip compression to responses when the client accepts it.
 func (c *Compress) Apply(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Check if client accepts gzip
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		if !c.decodeRequestBody(w, r) {
+			return
+		}
+
+		// Check if client accepts gzip. HEAD responses carry no body, so
+		// their Content-Length must describe the identity encoding.
+		if r.Method == http.MethodHead || !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -74,6 +89,32 @@ func (c *Compress) Apply(next http.Handler) http.Handler {
 	})
 }
 
+// decodeRequestBody rejects encoded request bodies unless gzip decoding is
+// enabled, in which case the body is swapped for a size-capped decoder so a
+// small compressed payload can't expand without bound. Returns false if an
+// error response was written.
+func (c *Compress) decodeRequestBody(w http.ResponseWriter, r *http.Request) bool {
+	encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		return true
+	}
+	if encoding != "gzip" || c.maxDecodedRequestBytes <= 0 {
+		writeError(w, http.StatusUnsupportedMediaType, "Compressed request bodies are not supported")
+		return false
+	}
+
+	gz, err := gzip.NewReader(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid gzip request body")
+		return false
+	}
+	r.Body = http.MaxBytesReader(w, gz, c.maxDecodedRequestBytes)
+	r.Header.Del("Content-Encoding")
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+	return true
+}
+
 // isPreCompressedPath returns true for file types that are already compressed.
 func isPreCompressedPath(path string) bool {
 	compressedExtensions := []string{
@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"bytes"
 	"compress/gzip"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -74,6 +77,25 @@ func TestCompress_NoGzipWhenNotAccepted(t *testing.T) {
 	}
 }
 
+func TestCompress_SkipsHead(t *testing.T) {
+	compress := NewCompress()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "23")
+	})
+
+	req := httptest.NewRequest(http.MethodHead, "/s/abc", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	compress.Apply(handler).ServeHTTP(rr, req)
+
+	if got := rr.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("expected no Content-Encoding on HEAD, got %q", got)
+	}
+	if got := rr.Header().Get("Content-Length"); got != "23" {
+		t.Errorf("expected Content-Length to be kept, got %q", got)
+	}
+}
+
 func TestCompress_SkipPreCompressedFiles(t *testing.T) {
 	compress := NewCompress()
 
@@ -240,3 +262,129 @@ func TestCompress_VaryHeader(t *testing.T) {
 		t.Errorf("expected Vary: Accept-Encoding, got %q", got)
 	}
 }
+
+func TestCompress_RejectsEncodedRequestBodyByDefault(t *testing.T) {
+	compress := NewCompress()
+
+	called := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	for _, encoding := range []string{"gzip", "deflate", "br"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/cards", strings.NewReader("payload"))
+		req.Header.Set("Content-Encoding", encoding)
+
+		rr := httptest.NewRecorder()
+		compress.Apply(handler).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("%s: expected status 415, got %d", encoding, rr.Code)
+		}
+	}
+	if called {
+		t.Error("expected handler not to be called for encoded bodies")
+	}
+}
+
+func TestCompress_AllowsIdentityRequestBody(t *testing.T) {
+	compress := NewCompress()
+
+	var body string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cards", strings.NewReader("payload"))
+	req.Header.Set("Content-Encoding", "identity")
+
+	rr := httptest.NewRecorder()
+	compress.Apply(handler).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if body != "payload" {
+		t.Errorf("expected body %q, got %q", "payload", body)
+	}
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCompress_DecodesGzipRequestBodyWithinLimit(t *testing.T) {
+	compress := NewCompress()
+	compress.SetRequestDecodingLimit(1024)
+
+	var body string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "" {
+			t.Error("expected Content-Encoding to be removed after decoding")
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("unexpected read error: %v", err)
+		}
+		body = string(b)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cards", bytes.NewReader(gzipBytes(t, []byte(`{"year":2026}`))))
+	req.Header.Set("Content-Encoding", "gzip")
+
+	rr := httptest.NewRecorder()
+	compress.Apply(handler).ServeHTTP(rr, req)
+
+	if body != `{"year":2026}` {
+		t.Errorf("expected decoded body, got %q", body)
+	}
+}
+
+func TestCompress_GzipRequestBodyOverLimitFails(t *testing.T) {
+	compress := NewCompress()
+	compress.SetRequestDecodingLimit(1024)
+
+	var readErr error
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	})
+
+	bomb := gzipBytes(t, bytes.Repeat([]byte("a"), 1<<20))
+	req := httptest.NewRequest(http.MethodPost, "/api/cards", bytes.NewReader(bomb))
+	req.Header.Set("Content-Encoding", "gzip")
+
+	rr := httptest.NewRecorder()
+	compress.Apply(handler).ServeHTTP(rr, req)
+
+	var maxErr *http.MaxBytesError
+	if !errors.As(readErr, &maxErr) {
+		t.Fatalf("expected MaxBytesError, got %v", readErr)
+	}
+}
+
+func TestCompress_InvalidGzipRequestBody(t *testing.T) {
+	compress := NewCompress()
+	compress.SetRequestDecodingLimit(1024)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cards", strings.NewReader("not gzip"))
+	req.Header.Set("Content-Encoding", "gzip")
+
+	rr := httptest.NewRecorder()
+	compress.Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	})).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -26,7 +27,14 @@ func NewCSRFMiddleware(secure bool) *CSRFMiddleware {
 func (m *CSRFMiddleware) Protect(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Public tokenized endpoints (no session) should not require CSRF headers/cookies.
-		if r.URL.Path == "/r/unsubscribe" {
+		switch r.URL.Path {
+		case "/r/unsubscribe", "/r/snooze", "/r/preferences", "/r/watch/confirm", "/r/watch/unsubscribe":
+			next.ServeHTTP(w, r)
+			return
+		}
+		// ActivityPub inboxes take signed deliveries from other servers, which
+		// have no session or CSRF cookie.
+		if strings.HasPrefix(r.URL.Path, "/ap/") {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -112,22 +112,24 @@ func TestCSRFMiddleware_ValidTokenAllowsRequest(t *testing.T) {
 func TestCSRFMiddleware_UnsubscribeBypass(t *testing.T) {
 	csrf := NewCSRFMiddleware(false)
 
-	handlerCalled := false
-	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		handlerCalled = true
-		w.WriteHeader(http.StatusOK)
-	})
+	for _, path := range []string{"/r/unsubscribe", "/r/preferences", "/r/watch/confirm", "/r/watch/unsubscribe", "/ap/users/solo/inbox"} {
+		handlerCalled := false
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlerCalled = true
+			w.WriteHeader(http.StatusOK)
+		})
 
-	req := httptest.NewRequest(http.MethodPost, "/r/unsubscribe", nil)
-	rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		rr := httptest.NewRecorder()
 
-	csrf.Protect(handler).ServeHTTP(rr, req)
+		csrf.Protect(handler).ServeHTTP(rr, req)
 
-	if !handlerCalled {
-		t.Error("handler should be called for unsubscribe without CSRF token")
-	}
-	if rr.Code != http.StatusOK {
-		t.Errorf("expected status 200, got %d", rr.Code)
+		if !handlerCalled {
+			t.Errorf("handler should be called for %s without CSRF token", path)
+		}
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected status 200 for %s, got %d", path, rr.Code)
+		}
 	}
 }
 
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deprecation marks responses from a route as deprecated using the
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers.
+type Deprecation struct {
+	deprecatedAt time.Time
+	sunset       time.Time
+	link         string
+}
+
+// NewDeprecation creates a deprecation middleware. A zero deprecatedAt emits
+// "Deprecation: true", a zero sunset omits the Sunset header, and a non-empty
+// link is advertised as the migration guide for the route.
+func NewDeprecation(deprecatedAt, sunset time.Time, link string) *Deprecation {
+	return &Deprecation{
+		deprecatedAt: deprecatedAt,
+		sunset:       sunset,
+		link:         link,
+	}
+}
+
+// Apply adds the deprecation headers before calling the next handler.
+func (d *Deprecation) Apply(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d.deprecatedAt.IsZero() {
+			w.Header().Set("Deprecation", "true")
+		} else {
+			w.Header().Set("Deprecation", "@"+strconv.FormatInt(d.deprecatedAt.Unix(), 10))
+		}
+		if !d.sunset.IsZero() {
+			w.Header().Set("Sunset", d.sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.link != "" {
+			w.Header().Add("Link", "<"+d.link+">; rel=\"deprecation\"")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInternalTokenGate_Or(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		header     string
+		wantNext   bool
+	}{
+		{name: "valid token", configured: "secret", header: "secret", wantNext: true},
+		{name: "wrong token", configured: "secret", header: "nope"},
+		{name: "missing token", configured: "secret"},
+		{name: "gate disabled", configured: "", header: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := NewInternalTokenGate(tt.configured)
+			var nextCalled, otherwiseCalled bool
+			handler := gate.Or(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { nextCalled = true }),
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { otherwiseCalled = true }),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/jobs", nil)
+			if tt.header != "" {
+				req.Header.Set(InternalTokenHeader, tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if nextCalled != tt.wantNext || otherwiseCalled == tt.wantNext {
+				t.Fatalf("next called=%v, otherwise called=%v, want next=%v", nextCalled, otherwiseCalled, tt.wantNext)
+			}
+		})
+	}
+}
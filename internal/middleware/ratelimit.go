@@ -8,8 +8,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 )
 
@@ -104,6 +106,21 @@ func writeError(w http.ResponseWriter, status int, message string) {
 	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
 }
 
+// RateLimitKeyByCaller keys a limiter by API token when the request used one,
+// so separate automations don't share a bucket, and by user otherwise.
+// Anonymous requests get "", which falls back to the client IP.
+func RateLimitKeyByCaller(r *http.Request) string {
+	info := handlers.GetAuthInfoFromContext(r.Context())
+	switch {
+	case info == nil:
+		return ""
+	case info.IsToken() && info.TokenID != uuid.Nil:
+		return "token:" + info.TokenID.String()
+	default:
+		return info.User.ID.String()
+	}
+}
+
 // GetClientIP extracts the client IP from the request, respecting X-Forwarded-For
 func GetClientIP(r *http.Request) string {
 	// Check X-Forwarded-For header (set by Cloudflare/proxies)
@@ -6,7 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
 func TestRateLimiter_Middleware_NilRedis(t *testing.T) {
@@ -150,3 +154,27 @@ func TestRateLimiter_Middleware_RedisError_FailOpenAndFailClosed(t *testing.T) {
 		}
 	})
 }
+
+func TestRateLimitKeyByCaller(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	tokenA, tokenB := uuid.New(), uuid.New()
+	keyFor := func(info *handlers.AuthInfo) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if info != nil {
+			req = req.WithContext(handlers.SetAuthInfoInContext(req.Context(), info))
+		}
+		return RateLimitKeyByCaller(req)
+	}
+
+	if got := keyFor(nil); got != "" {
+		t.Fatalf("expected anonymous requests to fall back to IP, got %q", got)
+	}
+	if got := keyFor(&handlers.AuthInfo{User: user, Method: handlers.AuthMethodSession}); got != user.ID.String() {
+		t.Fatalf("expected session requests keyed by user, got %q", got)
+	}
+	a := keyFor(&handlers.AuthInfo{User: user, Method: handlers.AuthMethodToken, TokenID: tokenA, Scope: models.ScopeRead})
+	b := keyFor(&handlers.AuthInfo{User: user, Method: handlers.AuthMethodToken, TokenID: tokenB, Scope: models.ScopeRead})
+	if a != "token:"+tokenA.String() || b == a {
+		t.Fatalf("expected each token to get its own bucket, got %q and %q", a, b)
+	}
+}
@@ -7,6 +7,8 @@ import (
 // SecurityHeaders adds security-related HTTP headers to responses.
 type SecurityHeaders struct {
 	secure bool
+	// imgSources are extra img-src origins, e.g. a branding logo host.
+	imgSources []string
 }
 
 // NewSecurityHeaders creates a new security headers middleware.
@@ -14,8 +16,19 @@ func NewSecurityHeaders(secure bool) *SecurityHeaders {
 	return &SecurityHeaders{secure: secure}
 }
 
+// AllowImageSource adds an origin to the img-src directive.
+func (s *SecurityHeaders) AllowImageSource(origin string) {
+	if origin != "" {
+		s.imgSources = append(s.imgSources, origin)
+	}
+}
+
 // Apply adds security headers to all responses.
 func (s *SecurityHeaders) Apply(next http.Handler) http.Handler {
+	imgSrc := "img-src 'self' data:"
+	for _, origin := range s.imgSources {
+		imgSrc += " " + origin
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Prevent clickjacking
 		w.Header().Set("X-Frame-Options", "DENY")
@@ -37,7 +50,7 @@ func (s *SecurityHeaders) Apply(next http.Handler) http.Handler {
 			"script-src 'self' https://static.cloudflareinsights.com https://cdnjs.cloudflare.com; " +
 			"style-src 'self' 'unsafe-inline' https://fonts.googleapis.com https://fonts.cdnfonts.com https://cdnjs.cloudflare.com; " +
 			"font-src 'self' https://fonts.gstatic.com https://fonts.cdnfonts.com https://cdnjs.cloudflare.com data:; " +
-			"img-src 'self' data:; " +
+			imgSrc + "; " +
 			"connect-src 'self'; " +
 			"frame-ancestors 'none'; " +
 			"base-uri 'self'; " +
@@ -122,6 +122,21 @@ func TestSecurityHeaders_CSP(t *testing.T) {
 	}
 }
 
+func TestSecurityHeaders_AllowImageSource(t *testing.T) {
+	sec := NewSecurityHeaders(true)
+	sec.AllowImageSource("https://cdn.example.com")
+	sec.AllowImageSource("")
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	sec.Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rr, req)
+
+	csp := rr.Header().Get("Content-Security-Policy")
+	if !strings.Contains(csp, "img-src 'self' data: https://cdn.example.com;") {
+		t.Errorf("expected logo origin in img-src, got %q", csp)
+	}
+}
+
 func TestSecurityHeaders_HandlerCalled(t *testing.T) {
 	sec := NewSecurityHeaders(false)
 
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+)
+
+// usageRecordTimeout bounds how long a request waits on the usage counter.
+const usageRecordTimeout = 100 * time.Millisecond
+
+// UsageRecorder counts one API request from a user to a route group.
+type UsageRecorder interface {
+	Record(ctx context.Context, userID uuid.UUID, group string) error
+}
+
+// UsageTracker counts authenticated API requests per user by route group. It
+// must sit between Authenticate and the router so it sees both the user and
+// the matched route pattern. Counting is best-effort: errors are logged and
+// never affect the response.
+type UsageTracker struct {
+	recorder UsageRecorder
+}
+
+func NewUsageTracker(recorder UsageRecorder) *UsageTracker {
+	return &UsageTracker{recorder: recorder}
+}
+
+func (u *UsageTracker) Apply(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		if u.recorder == nil {
+			return
+		}
+		user := handlers.GetUserFromContext(r.Context())
+		if user == nil {
+			return
+		}
+		// The router sets Pattern on the request it matched, so unknown
+		// paths are never counted.
+		group := usageGroup(r.Pattern)
+		if group == "" {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), usageRecordTimeout)
+		defer cancel()
+		if err := u.recorder.Record(ctx, user.ID, group); err != nil {
+			logging.Warn("Usage counter error", map[string]interface{}{"error": err.Error()})
+		}
+	})
+}
+
+// usageGroup returns the first path segment after /api (or /api/v<n>) of a
+// route pattern such as "GET /api/v1/cards/{id}", or "" for non-API routes.
+func usageGroup(pattern string) string {
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = path
+	}
+	rest, ok := strings.CutPrefix(pattern, "/api/")
+	if !ok {
+		return ""
+	}
+	rest = strings.TrimPrefix(rest, "v"+handlers.APIVersion+"/")
+	group, _, _ := strings.Cut(rest, "/")
+	if group == "" || strings.HasPrefix(group, "{") {
+		return ""
+	}
+	return group
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/handlers"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type recordedUsage struct {
+	userID uuid.UUID
+	group  string
+}
+
+type fakeUsageRecorder struct {
+	calls []recordedUsage
+	err   error
+}
+
+func (f *fakeUsageRecorder) Record(ctx context.Context, userID uuid.UUID, group string) error {
+	f.calls = append(f.calls, recordedUsage{userID: userID, group: group})
+	return f.err
+}
+
+func TestUsageGroup(t *testing.T) {
+	tests := map[string]string{
+		"GET /api/cards/{id}":        "cards",
+		"POST /api/v1/ai/generate":   "ai",
+		"GET /api/usage":             "usage",
+		"/api/friends":               "friends",
+		"GET /{path...}":             "",
+		"GET /r/img/{token}":         "",
+		"":                           "",
+		"GET /api/{path...}":         "",
+		"DELETE /api/v1/tokens/{id}": "tokens",
+	}
+	for pattern, want := range tests {
+		if got := usageGroup(pattern); got != want {
+			t.Errorf("usageGroup(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestUsageTracker_CountsMatchedAPIRoutes(t *testing.T) {
+	recorder := &fakeUsageRecorder{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/cards/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := NewUsageTracker(recorder).Apply(mux)
+	user := &models.User{ID: uuid.New()}
+
+	for _, path := range []string{"/api/v1/cards/123", "/api/v1/unknown"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req = req.WithContext(handlers.SetUserInContext(req.Context(), user))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	// Anonymous requests are not counted.
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/cards/123", nil))
+
+	if len(recorder.calls) != 1 || recorder.calls[0].group != "cards" || recorder.calls[0].userID != user.ID {
+		t.Fatalf("expected one cards request counted, got %+v", recorder.calls)
+	}
+}
+
+func TestUsageTracker_RecorderErrorDoesNotFailRequest(t *testing.T) {
+	recorder := &fakeUsageRecorder{err: errors.New("redis down")}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/usage", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := NewUsageTracker(recorder).Apply(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/usage", nil)
+	req = req.WithContext(handlers.SetUserInContext(req.Context(), &models.User{ID: uuid.New()}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if len(recorder.calls) != 1 {
+		t.Fatalf("expected the count attempted, got %d", len(recorder.calls))
+	}
+}
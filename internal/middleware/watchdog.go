@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+)
+
+// DisconnectWatchdog logs handlers that keep running after their client has
+// gone away, which usually means some work isn't passing r.Context() down.
+type DisconnectWatchdog struct {
+	logger *logging.Logger
+	grace  time.Duration
+}
+
+// NewDisconnectWatchdog creates a watchdog that logs a handler still running
+// grace after its request context was canceled. A zero grace turns it off.
+func NewDisconnectWatchdog(logger *logging.Logger, grace time.Duration) *DisconnectWatchdog {
+	if logger == nil {
+		logger = logging.Default
+	}
+	return &DisconnectWatchdog{logger: logger, grace: grace}
+}
+
+// Apply watches each request until its handler returns.
+func (d *DisconnectWatchdog) Apply(next http.Handler) http.Handler {
+	if d.grace <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		finished := make(chan struct{})
+		go d.watch(r, time.Now(), finished)
+		defer close(finished)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// watch waits for the client to disconnect, then for the grace period, and
+// logs once if the handler is still running by then and again when it
+// finally returns.
+func (d *DisconnectWatchdog) watch(r *http.Request, start time.Time, finished <-chan struct{}) {
+	select {
+	case <-finished:
+		return
+	case <-r.Context().Done():
+	}
+	disconnected := time.Now()
+
+	timer := time.NewTimer(d.grace)
+	defer timer.Stop()
+	select {
+	case <-finished:
+		return
+	case <-timer.C:
+	}
+
+	d.logger.Warn("Handler still running after client disconnect", map[string]interface{}{
+		"method":          r.Method,
+		"path":            r.URL.Path,
+		"running_ms":      time.Since(start).Milliseconds(),
+		"since_cancel_ms": time.Since(disconnected).Milliseconds(),
+	})
+
+	<-finished
+	d.logger.Warn("Handler finished after client disconnect", map[string]interface{}{
+		"method":          r.Method,
+		"path":            r.URL.Path,
+		"duration_ms":     time.Since(start).Milliseconds(),
+		"since_cancel_ms": time.Since(disconnected).Milliseconds(),
+	})
+}
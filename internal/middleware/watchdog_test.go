@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+)
+
+// lockedBuffer lets the test read log output the watchdog writes from its
+// own goroutine.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDisconnectWatchdog_LogsHandlerStillRunning(t *testing.T) {
+	var out lockedBuffer
+	logger := logging.New().SetOutput(&out).SetLevel(logging.LevelDebug)
+
+	release := make(chan struct{})
+	handler := NewDisconnectWatchdog(logger, 10*time.Millisecond).Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/api/ai/generate", nil).WithContext(ctx)
+	served := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		close(served)
+	}()
+	cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), "Handler still running after client disconnect") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a watchdog warning, got %q", out.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(out.String(), `"path":"/api/ai/generate"`) {
+		t.Fatalf("expected the request path in the warning, got %q", out.String())
+	}
+
+	close(release)
+	<-served
+	for !strings.Contains(out.String(), "Handler finished after client disconnect") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a finish warning, got %q", out.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDisconnectWatchdog_QuietWhenHandlerStops(t *testing.T) {
+	var out lockedBuffer
+	logger := logging.New().SetOutput(&out).SetLevel(logging.LevelDebug)
+
+	watchdog := NewDisconnectWatchdog(logger, 10*time.Millisecond)
+	stopsOnCancel := watchdog.Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	fast := watchdog.Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stopsOnCancel.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	fast.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	time.Sleep(30 * time.Millisecond)
+	if out.String() != "" {
+		t.Fatalf("expected no warnings, got %q", out.String())
+	}
+}
+
+func TestDisconnectWatchdog_DisabledPassesThrough(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := NewDisconnectWatchdog(nil, 0).Apply(next)
+	if reflect.ValueOf(handler).Pointer() != reflect.ValueOf(next).Pointer() {
+		t.Fatal("expected the handler to be returned unwrapped")
+	}
+}
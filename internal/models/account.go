@@ -0,0 +1,20 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountEventDataExport is recorded each time a user downloads their data.
+const AccountEventDataExport = "data_export"
+
+// AccountEvent records a security-relevant action a user took on their own account.
+type AccountEvent struct {
+	ID        uuid.UUID       `json:"id"`
+	UserID    uuid.UUID       `json:"user_id"`
+	EventType string          `json:"event_type"`
+	Details   json.RawMessage `json:"details,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
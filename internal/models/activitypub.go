@@ -0,0 +1,7 @@
+package models
+
+// Card events published to the ActivityPub outbox of a public profile.
+const (
+	ActivityKindItemCompleted = "item_completed"
+	ActivityKindBingo         = "bingo"
+)
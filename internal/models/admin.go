@@ -0,0 +1,143 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
+)
+
+// AdminAuditEntry records an action taken through an admin endpoint.
+type AdminAuditEntry struct {
+	ID           uuid.UUID       `json:"id"`
+	AdminUserID  uuid.UUID       `json:"admin_user_id"`
+	Action       string          `json:"action"`
+	TargetUserID *uuid.UUID      `json:"target_user_id,omitempty"`
+	TargetID     *uuid.UUID      `json:"target_id,omitempty"`
+	Details      json.RawMessage `json:"details,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// JobStatus reports the most recent run of a background loop.
+type JobStatus struct {
+	Name            string     `json:"name"`
+	IntervalSeconds int64      `json:"interval_seconds"`
+	Running         bool       `json:"running"`
+	Runs            int64      `json:"runs"`
+	LastRunAt       *time.Time `json:"last_run_at"`
+	LastDurationMS  int64      `json:"last_duration_ms"`
+	LastError       *string    `json:"last_error,omitempty"`
+	LastRunFailed   bool       `json:"last_run_failed"`
+	ItemsProcessed  int        `json:"items_processed"`
+	NextRunAt       *time.Time `json:"next_run_at"`
+}
+
+// CacheStats counts lookups against an in-process or Redis cache since
+// startup.
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// EmailSendLimiterStats is the email send limiter's state, reported by
+// /ready?verbose=1. Saturation runs from 0 (full bucket) to 1 (no tokens
+// left); RateLimited counts sends refused since startup.
+type EmailSendLimiterStats struct {
+	RatePerSecond float64 `json:"rate_per_second"`
+	Burst         int     `json:"burst"`
+	Available     float64 `json:"available"`
+	Saturation    float64 `json:"saturation"`
+	Waiting       int     `json:"waiting"`
+	RateLimited   int64   `json:"rate_limited"`
+}
+
+// Admin search types.
+const (
+	AdminSearchCards = "cards"
+	AdminSearchItems = "items"
+	AdminSearchUsers = "users"
+)
+
+// AdminSearchParams filters an admin content search. IncludePrivate lifts the
+// items restriction to publicly shared cards and non-private goals.
+type AdminSearchParams struct {
+	Type           string
+	Query          string
+	IncludePrivate bool
+	Limit          int
+	Offset         int
+}
+
+// AdminSearchHit is one card, item or user matched by an admin search. Text is
+// the card title, item content or username; AdminUserURL points at the owning
+// user's admin view.
+type AdminSearchHit struct {
+	Type         string     `json:"type"`
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	Username     string     `json:"username"`
+	Email        string     `json:"email,omitempty"`
+	CardID       *uuid.UUID `json:"card_id,omitempty"`
+	Year         int        `json:"year,omitempty"`
+	Text         string     `json:"text"`
+	IsShared     bool       `json:"is_shared"`
+	IsPrivate    bool       `json:"is_private,omitempty"`
+	AdminUserURL string     `json:"admin_user_url"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// AdminSearchResult is one page of admin search hits.
+type AdminSearchResult struct {
+	Items []AdminSearchHit `json:"items"`
+	pagination.Meta
+}
+
+// Self-test check outcomes.
+const (
+	SelfTestOK      = "ok"
+	SelfTestFailed  = "failed"
+	SelfTestSkipped = "skipped"
+)
+
+// SelfTestCheck is the outcome of one dependency check run by `server
+// selftest`. Skipped checks cover optional dependencies that aren't configured.
+type SelfTestCheck struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// SelfTestReport collects every check. OK is false when any check failed.
+type SelfTestReport struct {
+	OK     bool            `json:"ok"`
+	Checks []SelfTestCheck `json:"checks"`
+}
+
+// Doctor check outcomes. Fixed means --fix repaired every row the check
+// found.
+const (
+	DoctorOK     = "ok"
+	DoctorFailed = "failed"
+	DoctorFixed  = "fixed"
+)
+
+// DoctorCheck is the outcome of one data invariant checked by `server
+// doctor`. Rows counts the rows that break it before any fix; Fixable is
+// true when --fix knows a safe repair.
+type DoctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Rows    int64  `json:"rows"`
+	Fixable bool   `json:"fixable"`
+	Fixed   int64  `json:"fixed,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// DoctorReport collects every check. OK is false when any check failed.
+type DoctorReport struct {
+	OK     bool          `json:"ok"`
+	Checks []DoctorCheck `json:"checks"`
+}
@@ -13,12 +13,69 @@ import (
 const (
 	MinGridSize = 2
 	MaxGridSize = 5
+
+	// MaxItemContentLength is the maximum goal length in characters (runes).
+	MaxItemContentLength = 500
+
+	// MaxFreeSpaceTextLength is the maximum custom FREE space label length in
+	// characters (runes).
+	MaxFreeSpaceTextLength = 40
+
+	// DefaultFreeSpaceText is shown when a card has no custom label.
+	DefaultFreeSpaceText = "FREE"
+
+	// MaxCardPeriodMonths caps how long a card's start_date..end_date span
+	// may be, so a "year" can be a fiscal or school year but not open-ended.
+	MaxCardPeriodMonths = 18
+
+	// CardDateLayout is the wire format for card start/end dates.
+	CardDateLayout = "2006-01-02"
 )
 
+// DefaultCardPeriod returns Jan 1 - Dec 31 of year, the period used when a
+// card does not set its own dates.
+func DefaultCardPeriod(year int) (time.Time, time.Time) {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+// ValidateCardPeriod checks that end is after start and the span is at most
+// MaxCardPeriodMonths.
+func ValidateCardPeriod(start, end time.Time) error {
+	if !end.After(start) {
+		return fmt.Errorf("end date must be after start date")
+	}
+	if end.After(start.AddDate(0, MaxCardPeriodMonths, 0)) {
+		return fmt.Errorf("card period must be %d months or less", MaxCardPeriodMonths)
+	}
+	return nil
+}
+
 func IsValidGridSize(n int) bool {
 	return n >= MinGridSize && n <= MaxGridSize
 }
 
+// NormalizeFreeSpaceText trims the label; an empty result means "use the
+// default".
+func NormalizeFreeSpaceText(s string) string {
+	return strings.TrimSpace(s)
+}
+
+func ValidateFreeSpaceText(text string) error {
+	if utf8.RuneCountInString(NormalizeFreeSpaceText(text)) > MaxFreeSpaceTextLength {
+		return fmt.Errorf("free space text must be %d characters or less", MaxFreeSpaceTextLength)
+	}
+	return nil
+}
+
+// FreeSpaceLabel returns the text to render on the FREE square.
+func FreeSpaceLabel(text *string) string {
+	if text == nil || NormalizeFreeSpaceText(*text) == "" {
+		return DefaultFreeSpaceText
+	}
+	return NormalizeFreeSpaceText(*text)
+}
+
 func DefaultHeaderText(gridSize int) string {
 	if !IsValidGridSize(gridSize) {
 		gridSize = MaxGridSize
@@ -62,9 +119,33 @@ type BingoCard struct {
 	IsFinalized      bool        `json:"is_finalized"`
 	VisibleToFriends bool        `json:"visible_to_friends"`
 	IsArchived       bool        `json:"is_archived"`
+	FreeSpaceText    *string     `json:"free_space_text,omitempty"`
+	RequireProof     bool        `json:"require_proof"`
+	StartDate        time.Time   `json:"start_date"`
+	EndDate          time.Time   `json:"end_date"`
 	CreatedAt        time.Time   `json:"created_at"`
 	UpdatedAt        time.Time   `json:"updated_at"`
 	Items            []BingoItem `json:"items,omitempty"`
+	// FreeSpace describes the FREE square as a read-only pseudo-item. It is
+	// not part of Items, so capacity and bingo math are unaffected.
+	FreeSpace *FreeSpaceItem `json:"free_space,omitempty"`
+}
+
+// FreeSpaceItem is the FREE square rendered like an item: always completed,
+// never stored in bingo_items.
+type FreeSpaceItem struct {
+	Position    int    `json:"position"`
+	Content     string `json:"content"`
+	IsCompleted bool   `json:"is_completed"`
+}
+
+// NewFreeSpaceItem returns the pseudo-item for a card's FREE square, or nil
+// when the card has none.
+func NewFreeSpaceItem(hasFree bool, freePos *int, text *string) *FreeSpaceItem {
+	if !hasFree || freePos == nil {
+		return nil
+	}
+	return &FreeSpaceItem{Position: *freePos, Content: FreeSpaceLabel(text), IsCompleted: true}
 }
 
 func (c *BingoCard) TotalSquares() int {
@@ -94,6 +175,9 @@ func (c *BingoCard) IsFreeSpacePosition(pos int) bool {
 	return c.HasFreePositionSet() && pos == *c.FreeSpacePos
 }
 
+// IsValidItemPosition reports whether a goal may sit at pos: on the grid and
+// not the FREE square. Positions are grid squares, so they keep gaps when
+// goals are removed; the database enforces the same rule at commit.
 func (c *BingoCard) IsValidItemPosition(pos int) bool {
 	return c.IsPositionInRange(pos) && !c.IsFreeSpacePosition(pos)
 }
@@ -109,7 +193,59 @@ func (c BingoCard) DefaultFreeSpacePosition() int {
 	return rand.Intn(total)
 }
 
+// RedactPrivateItems replaces private items with placeholders in place. Only
+// call it on cards loaded for a viewer other than the owner.
+func (c *BingoCard) RedactPrivateItems() {
+	for i := range c.Items {
+		c.Items[i] = c.Items[i].Redacted()
+	}
+}
+
+// SyncFreeSpace refreshes the FreeSpace pseudo-item from the card's free
+// space settings. Call it after loading or changing those settings.
+func (c *BingoCard) SyncFreeSpace() {
+	c.FreeSpace = NewFreeSpaceItem(c.HasFreeSpace, c.FreeSpacePos, c.FreeSpaceText)
+}
+
 // DisplayName returns a human-readable name for the card
+// Period returns the card's start and end dates, falling back to the calendar
+// year when they are unset.
+func (c *BingoCard) Period() (time.Time, time.Time) {
+	if c.StartDate.IsZero() || c.EndDate.IsZero() {
+		return DefaultCardPeriod(c.Year)
+	}
+	return c.StartDate, c.EndDate
+}
+
+// DaysRemaining returns the whole days left until the card's end date
+// (inclusive), or 0 once the period is over.
+func (c *BingoCard) DaysRemaining(now time.Time) int {
+	_, end := c.Period()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(end.Sub(today).Hours()/24) + 1
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// MonthsRemaining returns the whole calendar months left before the card's
+// end date, or 0 when less than a month remains.
+func (c *BingoCard) MonthsRemaining(now time.Time) int {
+	_, end := c.Period()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	months := 0
+	for !today.AddDate(0, months+1, 0).After(end.AddDate(0, 0, 1)) {
+		months++
+	}
+	return months
+}
+
+// IsOverdue reports whether the card's period has ended.
+func (c *BingoCard) IsOverdue(now time.Time) bool {
+	return c.DaysRemaining(now) == 0
+}
+
 func (c *BingoCard) DisplayName() string {
 	if c.Title != nil && *c.Title != "" {
 		return *c.Title
@@ -160,17 +296,109 @@ type BingoItem struct {
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 	Notes       *string    `json:"notes,omitempty"`
 	ProofURL    *string    `json:"proof_url,omitempty"`
+	IsPrivate   bool       `json:"is_private"`
+	Difficulty  *string    `json:"difficulty,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
+	// ContentTruncated is set on write responses when the content will be
+	// ellipsized in rendered card images at the card's grid size.
+	ContentTruncated bool `json:"content_truncated,omitempty"`
+}
+
+// PrivateItemPlaceholder replaces the content of private items anywhere the
+// card is shown to someone other than its owner.
+const PrivateItemPlaceholder = "Private goal"
+
+// Redacted returns the item as others may see it. Completion state is kept so
+// progress and bingos still add up; content, notes and proof are hidden.
+func (i BingoItem) Redacted() BingoItem {
+	if !i.IsPrivate {
+		return i
+	}
+	i.Content = PrivateItemPlaceholder
+	i.Notes = nil
+	i.ProofURL = nil
+	i.Difficulty = nil
+	i.ContentTruncated = false
+	return i
+}
+
+// Item difficulty tags. Difficulty is optional; untagged items count as
+// medium when weighting progress.
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+// IsValidDifficulty reports whether d is one of the difficulty tags.
+func IsValidDifficulty(d string) bool {
+	switch d {
+	case DifficultyEasy, DifficultyMedium, DifficultyHard:
+		return true
+	}
+	return false
+}
+
+// DifficultyWeights sets how much each difficulty counts toward weighted
+// progress.
+type DifficultyWeights struct {
+	Easy   int
+	Medium int
+	Hard   int
+}
+
+// DefaultDifficultyWeights counts a hard goal three times an easy one.
+var DefaultDifficultyWeights = DifficultyWeights{Easy: 1, Medium: 2, Hard: 3}
+
+// Weight returns the weight for a difficulty tag; nil or unknown tags weigh
+// the same as medium.
+func (w DifficultyWeights) Weight(difficulty *string) int {
+	if difficulty != nil {
+		switch *difficulty {
+		case DifficultyEasy:
+			return w.Easy
+		case DifficultyHard:
+			return w.Hard
+		}
+	}
+	return w.Medium
+}
+
+// WeightedCompletionRate returns the percentage of the card's effort that is
+// complete. Squares without an item yet are weighted like untagged items.
+func (c *BingoCard) WeightedCompletionRate(weights DifficultyWeights) float64 {
+	capacity := c.Capacity()
+	total, done, counted := 0, 0, 0
+	for _, item := range c.Items {
+		if c.HasFreePositionSet() && item.Position == *c.FreeSpacePos {
+			continue
+		}
+		weight := weights.Weight(item.Difficulty)
+		total += weight
+		counted++
+		if item.IsCompleted {
+			done += weight
+		}
+	}
+	if counted < capacity {
+		total += (capacity - counted) * weights.Medium
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(done) / float64(total) * 100
 }
 
 type CreateCardParams struct {
-	UserID   uuid.UUID
-	Year     int
-	Category *string
-	Title    *string
-	GridSize int
-	Header   string
-	HasFree  bool
+	UserID    uuid.UUID
+	Year      int
+	Category  *string
+	Title     *string
+	GridSize  int
+	Header    string
+	HasFree   bool
+	StartDate *time.Time // Optional; defaults to Jan 1 of Year
+	EndDate   *time.Time // Optional; defaults to Dec 31 of Year
 }
 
 type UpdateCardMetaParams struct {
@@ -179,36 +407,128 @@ type UpdateCardMetaParams struct {
 }
 
 type UpdateCardConfigParams struct {
-	HeaderText   *string
-	HasFreeSpace *bool
+	HeaderText    *string
+	HasFreeSpace  *bool
+	FreeSpaceText *string // Empty string clears back to the default label
+	RequireProof  *bool
+}
+
+// OnlyRequireProof reports whether the update touches nothing but the
+// require_proof setting, which may change after the card is finalized.
+func (p UpdateCardConfigParams) OnlyRequireProof() bool {
+	return p.RequireProof != nil && p.HeaderText == nil && p.HasFreeSpace == nil && p.FreeSpaceText == nil
 }
 
 type AddItemParams struct {
-	CardID   uuid.UUID
-	Content  string
-	Position *int // Optional; if nil, assign randomly
+	CardID     uuid.UUID
+	Content    string
+	Position   *int // Optional; if nil, assign randomly
+	IsPrivate  bool
+	Difficulty *string // Optional; one of the Difficulty* tags
 }
 
 type UpdateItemParams struct {
-	Content  *string
-	Position *int
+	Content    *string
+	Position   *int
+	IsPrivate  *bool
+	Difficulty *string // Empty string clears the tag
 }
 
+// MaxProofImageBytes caps an uploaded proof photo.
+const MaxProofImageBytes = 5 << 20
+
 type CompleteItemParams struct {
 	Notes    *string
 	ProofURL *string
+	// ProofImage is an uploaded proof photo. When set it is stored and its
+	// URL replaces ProofURL.
+	ProofImage []byte
+}
+
+// HasProof reports whether the completion carries a non-empty note, proof URL
+// or proof image.
+func (p CompleteItemParams) HasProof() bool {
+	return (p.Notes != nil && strings.TrimSpace(*p.Notes) != "") ||
+		(p.ProofURL != nil && strings.TrimSpace(*p.ProofURL) != "") ||
+		len(p.ProofImage) > 0
 }
 
 // CardStats contains statistics for a bingo card
 type CardStats struct {
+	CardID         uuid.UUID `json:"card_id"`
+	Year           int       `json:"year"`
+	TotalItems     int       `json:"total_items"`
+	CompletedItems int       `json:"completed_items"`
+	CompletionRate float64   `json:"completion_rate"`
+	// WeightedCompletionRate weights each goal by its difficulty, so hard
+	// goals move it further than easy ones.
+	WeightedCompletionRate float64    `json:"weighted_completion_rate"`
+	BingosAchieved         int        `json:"bingos_achieved"`
+	FirstCompletion        *time.Time `json:"first_completion,omitempty"`
+	LastCompletion         *time.Time `json:"last_completion,omitempty"`
+	StartDate              time.Time  `json:"start_date"`
+	EndDate                time.Time  `json:"end_date"`
+	DaysRemaining          int        `json:"days_remaining"`
+	IsOverdue              bool       `json:"is_overdue"`
+}
+
+// CardProgress is one card's row in GET /api/cards/stats, the dashboard
+// summary of all of a user's cards. Every field is always present.
+type CardProgress struct {
+	CardID         uuid.UUID  `json:"card_id"`
+	Year           int        `json:"year"`
+	Title          *string    `json:"title"`
+	GridSize       int        `json:"grid_size"`
+	IsFinalized    bool       `json:"is_finalized"`
+	IsArchived     bool       `json:"is_archived"`
+	CompletedItems int        `json:"completed_items"`
+	TotalItems     int        `json:"total_items"`
+	BingosAchieved int        `json:"bingos_achieved"`
+	LastCompletion *time.Time `json:"last_completion"`
+}
+
+// CardRecap is the year-end summary of a card, from GET /api/cards/{id}/recap
+// or a share link's /s/{token}/recap.
+type CardRecap struct {
 	CardID          uuid.UUID  `json:"card_id"`
 	Year            int        `json:"year"`
-	TotalItems      int        `json:"total_items"`
+	DisplayName     string     `json:"display_name"`
 	CompletedItems  int        `json:"completed_items"`
-	CompletionRate  float64    `json:"completion_rate"`
+	TotalItems      int        `json:"total_items"`
 	BingosAchieved  int        `json:"bingos_achieved"`
-	FirstCompletion *time.Time `json:"first_completion,omitempty"`
-	LastCompletion  *time.Time `json:"last_completion,omitempty"`
+	FirstCompletion *time.Time `json:"first_completion"`
+	LastCompletion  *time.Time `json:"last_completion"`
+	// LongestWeekStreak counts consecutive weeks (Monday to Sunday, UTC) with
+	// at least one completion.
+	LongestWeekStreak int `json:"longest_week_streak"`
+	// OldestOpenGoals are up to three goals never completed, oldest first.
+	OldestOpenGoals []RecapGoal `json:"oldest_open_goals"`
+}
+
+type RecapGoal struct {
+	Position  int       `json:"position"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Memory is a goal the user completed around today's date in an earlier year.
+type Memory struct {
+	ItemID      uuid.UUID `json:"item_id"`
+	CardID      uuid.UUID `json:"card_id"`
+	CardTitle   *string   `json:"card_title,omitempty"`
+	CardYear    int       `json:"card_year"`
+	IsArchived  bool      `json:"is_archived"`
+	Content     string    `json:"content"`
+	CompletedAt time.Time `json:"completed_at"`
+	YearsAgo    int       `json:"years_ago"`
+}
+
+// CardRecommendation is an open goal suggested as the next one to complete,
+// with the lines it helps finish (e.g. "row 3") and a readable reason.
+type CardRecommendation struct {
+	Item   BingoItem `json:"item"`
+	Lines  []string  `json:"lines,omitempty"`
+	Reason string    `json:"reason,omitempty"`
 }
 
 // ImportCardParams contains parameters for importing an anonymous card
@@ -224,10 +544,71 @@ type ImportCardParams struct {
 	HeaderText       string
 	HasFreeSpace     bool
 	FreeSpacePos     *int
+	StartDate        *time.Time // Optional; defaults to Jan 1 of Year
+	EndDate          *time.Time // Optional; defaults to Dec 31 of Year
+	FreeSpaceText    *string
+	RequireProof     bool
 }
 
 // ImportItem represents a single item to import
 type ImportItem struct {
-	Position int
-	Content  string
+	Position   int
+	Content    string
+	Difficulty *string
+	IsPrivate  bool
+	Notes      *string
+	// ProofURL, IsCompleted and CompletedAt are only set when restoring
+	// completions from an account export.
+	ProofURL    *string
+	IsCompleted bool
+	CompletedAt *time.Time
+}
+
+// MergeImportParams appends imported items into the open squares of an
+// existing draft card. Item positions are ignored.
+type MergeImportParams struct {
+	UserID uuid.UUID
+	CardID uuid.UUID
+	Items  []ImportItem
+	DryRun bool
+}
+
+// MergeImportResult reports what a merge import added (or, for a dry run,
+// would add) and which items were skipped as duplicates of existing goals.
+type MergeImportResult struct {
+	DryRun     bool        `json:"dry_run"`
+	Added      []BingoItem `json:"added"`
+	Duplicates []string    `json:"duplicates"`
+	Card       *BingoCard  `json:"card,omitempty"`
+}
+
+// AccountExportImportResult reports the draft cards an account export
+// import created and the cards it left out because the user already has a
+// card with the same title and year.
+type AccountExportImportResult struct {
+	Cards      []*BingoCard          `json:"cards"`
+	Duplicates []CardImportDuplicate `json:"duplicates"`
+}
+
+// CardImportDuplicate is a card left out of an import because its title and
+// year clash with an existing card, or with an earlier card in the same
+// import (ExistingCardID is then nil).
+type CardImportDuplicate struct {
+	Title          *string    `json:"title"`
+	Year           int        `json:"year"`
+	ExistingCardID *uuid.UUID `json:"existing_card_id,omitempty"`
+}
+
+// CardVisibilityChange is one entry in a bulk visibility update.
+type CardVisibilityChange struct {
+	CardID           uuid.UUID
+	VisibleToFriends bool
+}
+
+// CardVisibilityResult reports the visibility applied to one card in a bulk
+// update and whether it differed from the previous value.
+type CardVisibilityResult struct {
+	CardID           uuid.UUID `json:"card_id"`
+	VisibleToFriends bool      `json:"visible_to_friends"`
+	Changed          bool      `json:"changed"`
 }
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// CardDocumentFormat identifies a portable single-card file.
+	CardDocumentFormat = "yearofbingo.card"
+
+	// CardDocumentVersion is the document version written by export. Import
+	// accepts this version and older ones.
+	CardDocumentVersion = 1
+)
+
+// CardDocument is a self-contained card template that can be passed around
+// as a file. It carries the card's configuration and goal contents only:
+// no IDs, owner, year, or completion state.
+type CardDocument struct {
+	Format            string             `json:"format"`
+	Version           int                `json:"version"`
+	Title             *string            `json:"title,omitempty"`
+	Category          *string            `json:"category,omitempty"`
+	GridSize          int                `json:"grid_size"`
+	HeaderText        string             `json:"header_text"`
+	HasFreeSpace      bool               `json:"has_free_space"`
+	FreeSpacePosition *int               `json:"free_space_position,omitempty"`
+	FreeSpaceText     *string            `json:"free_space_text,omitempty"`
+	RequireProof      bool               `json:"require_proof,omitempty"`
+	Items             []CardDocumentItem `json:"items"`
+}
+
+// CardDocumentItem is one goal in a CardDocument.
+type CardDocumentItem struct {
+	Position   int     `json:"position"`
+	Content    string  `json:"content"`
+	Difficulty *string `json:"difficulty,omitempty"`
+	IsPrivate  bool    `json:"is_private,omitempty"`
+}
+
+// NewCardDocument builds the portable document for card.
+func NewCardDocument(card *BingoCard) CardDocument {
+	doc := CardDocument{
+		Format:            CardDocumentFormat,
+		Version:           CardDocumentVersion,
+		Title:             card.Title,
+		Category:          card.Category,
+		GridSize:          card.GridSize,
+		HeaderText:        card.HeaderText,
+		HasFreeSpace:      card.HasFreeSpace,
+		FreeSpacePosition: card.FreeSpacePos,
+		FreeSpaceText:     card.FreeSpaceText,
+		RequireProof:      card.RequireProof,
+		Items:             make([]CardDocumentItem, 0, len(card.Items)),
+	}
+	if !card.HasFreeSpace {
+		doc.FreeSpacePosition = nil
+		doc.FreeSpaceText = nil
+	}
+	for _, item := range card.Items {
+		doc.Items = append(doc.Items, CardDocumentItem{
+			Position:   item.Position,
+			Content:    item.Content,
+			Difficulty: item.Difficulty,
+			IsPrivate:  item.IsPrivate,
+		})
+	}
+	return doc
+}
+
+// ImportParams validates the document and turns it into draft import
+// parameters for userID's card in year. Items keep their positions where
+// possible; out-of-range, duplicate or FREE-square positions move to the
+// first open square. Items that don't fit the grid are dropped and counted
+// in the returned total.
+func (d CardDocument) ImportParams(userID uuid.UUID, year int) (ImportCardParams, int, error) {
+	if d.Format != CardDocumentFormat {
+		return ImportCardParams{}, 0, fmt.Errorf("not a card file")
+	}
+	if d.Version < 1 || d.Version > CardDocumentVersion {
+		return ImportCardParams{}, 0, fmt.Errorf("unsupported card file version %d", d.Version)
+	}
+	if !IsValidGridSize(d.GridSize) {
+		return ImportCardParams{}, 0, fmt.Errorf("grid size must be 2, 3, 4, or 5")
+	}
+
+	headerText := NormalizeHeaderText(d.HeaderText)
+	if headerText == "" {
+		headerText = DefaultHeaderText(d.GridSize)
+	}
+	if err := ValidateHeaderText(headerText, d.GridSize); err != nil {
+		return ImportCardParams{}, 0, err
+	}
+
+	var title *string
+	if d.Title != nil && strings.TrimSpace(*d.Title) != "" {
+		trimmed := strings.TrimSpace(*d.Title)
+		title = &trimmed
+	}
+	var category *string
+	if d.Category != nil && *d.Category != "" {
+		if !IsValidCategory(*d.Category) {
+			return ImportCardParams{}, 0, fmt.Errorf("invalid category")
+		}
+		category = d.Category
+	}
+
+	total := d.GridSize * d.GridSize
+	var freePos *int
+	var freeText *string
+	if d.HasFreeSpace {
+		pos := BingoCard{GridSize: d.GridSize}.DefaultFreeSpacePosition()
+		if d.FreeSpacePosition != nil && *d.FreeSpacePosition >= 0 && *d.FreeSpacePosition < total {
+			pos = *d.FreeSpacePosition
+		}
+		freePos = &pos
+		if d.FreeSpaceText != nil {
+			if err := ValidateFreeSpaceText(*d.FreeSpaceText); err != nil {
+				return ImportCardParams{}, 0, err
+			}
+			if text := NormalizeFreeSpaceText(*d.FreeSpaceText); text != "" {
+				freeText = &text
+			}
+		}
+	}
+
+	for _, item := range d.Items {
+		content := strings.TrimSpace(item.Content)
+		if content == "" {
+			return ImportCardParams{}, 0, fmt.Errorf("goal content is required")
+		}
+		if utf8.RuneCountInString(content) > MaxItemContentLength {
+			return ImportCardParams{}, 0, fmt.Errorf("goals must be %d characters or less", MaxItemContentLength)
+		}
+		if item.Difficulty != nil && *item.Difficulty != "" && !IsValidDifficulty(*item.Difficulty) {
+			return ImportCardParams{}, 0, fmt.Errorf("invalid difficulty %q", *item.Difficulty)
+		}
+	}
+
+	taken := make(map[int]bool, total)
+	if freePos != nil {
+		taken[*freePos] = true
+	}
+	placed := make([]int, len(d.Items))
+	for i, item := range d.Items {
+		placed[i] = -1
+		if item.Position >= 0 && item.Position < total && !taken[item.Position] {
+			placed[i] = item.Position
+			taken[item.Position] = true
+		}
+	}
+	next := 0
+	for i := range placed {
+		if placed[i] >= 0 {
+			continue
+		}
+		for next < total && taken[next] {
+			next++
+		}
+		if next < total {
+			placed[i] = next
+			taken[next] = true
+		}
+	}
+
+	items := make([]ImportItem, 0, len(d.Items))
+	dropped := 0
+	for i, item := range d.Items {
+		if placed[i] < 0 {
+			dropped++
+			continue
+		}
+		var difficulty *string
+		if item.Difficulty != nil && *item.Difficulty != "" {
+			difficulty = item.Difficulty
+		}
+		items = append(items, ImportItem{
+			Position:   placed[i],
+			Content:    strings.TrimSpace(item.Content),
+			Difficulty: difficulty,
+			IsPrivate:  item.IsPrivate,
+		})
+	}
+
+	return ImportCardParams{
+		UserID:        userID,
+		Year:          year,
+		Title:         title,
+		Category:      category,
+		Items:         items,
+		GridSize:      d.GridSize,
+		HeaderText:    headerText,
+		HasFreeSpace:  d.HasFreeSpace,
+		FreeSpacePos:  freePos,
+		FreeSpaceText: freeText,
+		RequireProof:  d.RequireProof,
+	}, dropped, nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
+)
+
+// CardSearchParams is one page of a user's search over their own cards.
+type CardSearchParams struct {
+	UserID uuid.UUID
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// SnippetPart is a run of snippet text; Match marks the runs that matched
+// the query so clients can highlight them without parsing markup.
+type SnippetPart struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match,omitempty"`
+}
+
+// CardSearchItem is a goal whose text matched the query.
+type CardSearchItem struct {
+	ItemID   uuid.UUID     `json:"item_id"`
+	Position int           `json:"position"`
+	Snippet  []SnippetPart `json:"snippet"`
+}
+
+// CardSearchMatch groups the hits on one card. TitleSnippet is set when the
+// card title itself matched.
+type CardSearchMatch struct {
+	CardID       uuid.UUID        `json:"card_id"`
+	Title        *string          `json:"title,omitempty"`
+	Year         int              `json:"year"`
+	IsArchived   bool             `json:"is_archived"`
+	TitleSnippet []SnippetPart    `json:"title_snippet,omitempty"`
+	Items        []CardSearchItem `json:"items"`
+}
+
+// CardSearchResult is one page of search hits grouped by card. Pages count
+// cards, and each card carries all of its hits.
+type CardSearchResult struct {
+	Items []CardSearchMatch `json:"items"`
+	pagination.Meta
+}
@@ -13,6 +13,47 @@ type CardShare struct {
 	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
 	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
 	AccessCount    int        `json:"access_count"`
+	// SupersededUntil is when the token replaced by the last rotation stops
+	// resolving. Nil when there is no superseded token.
+	SupersededUntil *time.Time `json:"superseded_until,omitempty"`
+	// ShowCompletions and ShowNotes control what the public link reveals.
+	ShowCompletions bool `json:"show_completions"`
+	ShowNotes       bool `json:"show_notes"`
+}
+
+// ShareOptions controls what a public share link reveals. A nil field keeps
+// the share's current setting, or the default for a new share: completions
+// shown, notes hidden.
+type ShareOptions struct {
+	ShowCompletions *bool
+	ShowNotes       *bool
+}
+
+// ShareAnalytics summarizes visits to a card's share link over the last 30
+// days. Referrers are hosts only; "" is direct or unknown. Sources group
+// visits by the link's ?src= tag; "" is untagged.
+type ShareAnalytics struct {
+	Days       []ShareAnalyticsDay  `json:"days"`
+	Total      int                  `json:"total"`
+	Referrers  []ShareReferrerCount `json:"referrers"`
+	Sources    []ShareSourceCount   `json:"sources"`
+	UserAgents map[string]int       `json:"user_agents"`
+}
+
+// ShareAnalyticsDay is the visit count for one UTC date (YYYY-MM-DD).
+type ShareAnalyticsDay struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+type ShareReferrerCount struct {
+	Host  string `json:"host"`
+	Count int    `json:"count"`
+}
+
+type ShareSourceCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
 }
 
 type PublicBingoCard struct {
@@ -25,15 +66,54 @@ type PublicBingoCard struct {
 	HasFreeSpace bool      `json:"has_free_space"`
 	FreeSpacePos *int      `json:"free_space_position,omitempty"`
 	IsFinalized  bool      `json:"is_finalized"`
+	// FreeSpaceText is the custom FREE label, if any.
+	FreeSpaceText *string `json:"free_space_text,omitempty"`
+	// UpdatedAt is when the card's settings last changed.
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type PublicBingoItem struct {
 	Position    int    `json:"position"`
 	Content     string `json:"content"`
 	IsCompleted bool   `json:"is_completed"`
+	IsPrivate   bool   `json:"is_private,omitempty"`
+	// ProofURL is the goal's proof link or photo; never set for private goals.
+	ProofURL *string `json:"proof_url,omitempty"`
+	// Notes are only included when the share shows notes.
+	Notes *string `json:"notes,omitempty"`
 }
 
 type SharedCard struct {
-	Card  PublicBingoCard   `json:"card"`
-	Items []PublicBingoItem `json:"items"`
+	Card      PublicBingoCard   `json:"card"`
+	Items     []PublicBingoItem `json:"items"`
+	FreeSpace *FreeSpaceItem    `json:"free_space,omitempty"`
+	// Superseded is set when the card was reached through a rotated-out
+	// token that is still inside its grace period.
+	Superseded bool `json:"superseded,omitempty"`
+	// CompletionsHidden is set when the owner shared the card without
+	// progress; every goal is then reported as not completed.
+	CompletionsHidden bool `json:"completions_hidden,omitempty"`
+}
+
+// CardWidgetToken grants read-only access to a card's progress image at
+// /w/{token}.png for home- and lock-screen widgets. It never opens the share
+// page.
+type CardWidgetToken struct {
+	ID             uuid.UUID  `json:"id"`
+	CardID         uuid.UUID  `json:"card_id"`
+	Token          string     `json:"token"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	AccessCount    int        `json:"access_count"`
+}
+
+// ShareSubscriber is someone following a shared card by email, as shown to
+// the card's owner. Unconfirmed subscribers haven't used their confirmation
+// link yet and get no emails.
+type ShareSubscriber struct {
+	ID         uuid.UUID  `json:"id"`
+	Email      string     `json:"email"`
+	Confirmed  bool       `json:"confirmed"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
 }
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 func TestIsValidGridSize(t *testing.T) {
@@ -123,3 +127,232 @@ func TestIsValidCategory(t *testing.T) {
 		t.Fatal("expected invalid category")
 	}
 }
+
+func TestBingoCard_RedactPrivateItems(t *testing.T) {
+	notes := "weekly sessions"
+	difficulty := DifficultyHard
+	card := &BingoCard{Items: []BingoItem{
+		{Position: 0, Content: "Run a 5k", IsCompleted: true},
+		{Position: 1, Content: "See a therapist", IsCompleted: true, IsPrivate: true, Notes: &notes, Difficulty: &difficulty},
+	}}
+
+	card.RedactPrivateItems()
+
+	if card.Items[0].Content != "Run a 5k" {
+		t.Errorf("public item changed: %q", card.Items[0].Content)
+	}
+	private := card.Items[1]
+	if private.Content != PrivateItemPlaceholder {
+		t.Errorf("expected placeholder, got %q", private.Content)
+	}
+	if private.Notes != nil || private.Difficulty != nil {
+		t.Error("expected notes and difficulty to be cleared")
+	}
+	if !private.IsCompleted {
+		t.Error("expected completion state to be kept")
+	}
+}
+
+func TestBingoCard_WeightedCompletionRate(t *testing.T) {
+	hard, easy := DifficultyHard, DifficultyEasy
+	freePos := 4
+	card := &BingoCard{GridSize: 3, HasFreeSpace: true, FreeSpacePos: &freePos, Items: []BingoItem{
+		{Position: 0, IsCompleted: true, Difficulty: &hard},
+		{Position: 1, Difficulty: &easy},
+		{Position: 2},
+	}}
+
+	// 3 of 3+1+2 tagged weight plus 5 empty squares at medium weight.
+	if got := card.WeightedCompletionRate(DefaultDifficultyWeights); got != 18.75 {
+		t.Fatalf("expected 18.75, got %v", got)
+	}
+	flat := DifficultyWeights{Easy: 1, Medium: 1, Hard: 1}
+	if got := card.WeightedCompletionRate(flat); got != 12.5 {
+		t.Fatalf("expected equal weights to match plain completion, got %v", got)
+	}
+	if got := (&BingoCard{GridSize: 3}).WeightedCompletionRate(DifficultyWeights{}); got != 0 {
+		t.Fatalf("expected 0 for zero weights, got %v", got)
+	}
+}
+
+func TestIsValidDifficulty(t *testing.T) {
+	for _, d := range []string{"easy", "medium", "hard"} {
+		if !IsValidDifficulty(d) {
+			t.Errorf("expected %q to be valid", d)
+		}
+	}
+	for _, d := range []string{"", "Hard", "extreme"} {
+		if IsValidDifficulty(d) {
+			t.Errorf("expected %q to be invalid", d)
+		}
+	}
+}
+
+func TestFreeSpaceLabelAndItem(t *testing.T) {
+	custom := "  FREE - you survived 2024 "
+	blank := "   "
+	if got := FreeSpaceLabel(nil); got != DefaultFreeSpaceText {
+		t.Errorf("FreeSpaceLabel(nil) = %q", got)
+	}
+	if got := FreeSpaceLabel(&blank); got != DefaultFreeSpaceText {
+		t.Errorf("FreeSpaceLabel(blank) = %q", got)
+	}
+	if got := FreeSpaceLabel(&custom); got != "FREE - you survived 2024" {
+		t.Errorf("FreeSpaceLabel(custom) = %q", got)
+	}
+
+	if ValidateFreeSpaceText(strings.Repeat("é", MaxFreeSpaceTextLength)) != nil {
+		t.Error("expected max-length text to be valid")
+	}
+	if ValidateFreeSpaceText(strings.Repeat("é", MaxFreeSpaceTextLength+1)) == nil {
+		t.Error("expected over-long text to be rejected")
+	}
+
+	pos := 12
+	if NewFreeSpaceItem(false, &pos, &custom) != nil {
+		t.Error("expected no pseudo-item without a free space")
+	}
+	item := NewFreeSpaceItem(true, &pos, &custom)
+	if item == nil || item.Position != 12 || !item.IsCompleted || item.Content != "FREE - you survived 2024" {
+		t.Errorf("unexpected free space item: %+v", item)
+	}
+}
+
+func TestValidateCardPeriod(t *testing.T) {
+	start := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		end     time.Time
+		wantErr bool
+	}{
+		{"school year", time.Date(2026, time.August, 31, 0, 0, 0, 0, time.UTC), false},
+		{"exactly 18 months", start.AddDate(0, 18, 0), false},
+		{"over 18 months", start.AddDate(0, 18, 1), true},
+		{"same day", start, true},
+		{"end before start", start.AddDate(0, 0, -1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCardPeriod(start, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateCardPeriod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBingoCard_PeriodRemaining(t *testing.T) {
+	card := &BingoCard{Year: 2025}
+	start, end := card.Period()
+	if start != time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC) || end != time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC) {
+		t.Fatalf("expected calendar year default, got %v - %v", start, end)
+	}
+
+	card.StartDate = time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	card.EndDate = time.Date(2026, time.April, 30, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, time.January, 1, 15, 0, 0, 0, time.UTC)
+	if got := card.MonthsRemaining(now); got != 4 {
+		t.Fatalf("expected 4 months remaining, got %d", got)
+	}
+	if got := card.DaysRemaining(now); got != 120 {
+		t.Fatalf("expected 120 days remaining, got %d", got)
+	}
+	if card.IsOverdue(now) {
+		t.Fatal("expected card not to be overdue")
+	}
+
+	after := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	if !card.IsOverdue(after) || card.DaysRemaining(after) != 0 {
+		t.Fatal("expected card to be overdue after its end date")
+	}
+}
+
+func TestCompleteItemParams_HasProof(t *testing.T) {
+	note := "ran it"
+	url := "https://example.com/proof"
+	blank := "  "
+	tests := []struct {
+		params CompleteItemParams
+		want   bool
+	}{
+		{CompleteItemParams{}, false},
+		{CompleteItemParams{Notes: &blank, ProofURL: &blank}, false},
+		{CompleteItemParams{Notes: &note}, true},
+		{CompleteItemParams{ProofURL: &url}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.params.HasProof(); got != tt.want {
+			t.Errorf("HasProof(%+v) = %v, want %v", tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateCardConfigParams_OnlyRequireProof(t *testing.T) {
+	on := true
+	header := "BINGO"
+	if (UpdateCardConfigParams{}).OnlyRequireProof() {
+		t.Error("expected empty params not to be require_proof-only")
+	}
+	if !(UpdateCardConfigParams{RequireProof: &on}).OnlyRequireProof() {
+		t.Error("expected require_proof-only params")
+	}
+	if (UpdateCardConfigParams{RequireProof: &on, HeaderText: &header}).OnlyRequireProof() {
+		t.Error("expected header change not to be require_proof-only")
+	}
+}
+
+func TestCardDocument_ImportParams_MovesCollidingPositions(t *testing.T) {
+	center := 4
+	doc := CardDocument{
+		Format:            CardDocumentFormat,
+		Version:           CardDocumentVersion,
+		GridSize:          3,
+		HasFreeSpace:      true,
+		FreeSpacePosition: &center,
+		Items: []CardDocumentItem{
+			{Position: 4, Content: "On the free square"},
+			{Position: 1, Content: "Kept"},
+			{Position: 1, Content: "Duplicate"},
+			{Position: 40, Content: "Out of range"},
+		},
+	}
+
+	params, dropped, err := doc.ImportParams(uuid.New(), 2026)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dropped != 0 {
+		t.Fatalf("expected nothing dropped, got %d", dropped)
+	}
+	got := map[string]int{}
+	for _, item := range params.Items {
+		got[item.Content] = item.Position
+	}
+	want := map[string]int{"Kept": 1, "On the free square": 0, "Duplicate": 2, "Out of range": 3}
+	for content, pos := range want {
+		if got[content] != pos {
+			t.Errorf("%s: expected position %d, got %d", content, pos, got[content])
+		}
+	}
+	if params.HeaderText != "BIN" {
+		t.Errorf("expected default header BIN, got %q", params.HeaderText)
+	}
+}
+
+func TestCardDocument_ImportParams_RejectsInvalid(t *testing.T) {
+	long := strings.Repeat("a", MaxItemContentLength+1)
+	bad := "impossible"
+	cases := map[string]CardDocument{
+		"format":     {Format: "x", Version: 1, GridSize: 3},
+		"version":    {Format: CardDocumentFormat, Version: 0, GridSize: 3},
+		"header":     {Format: CardDocumentFormat, Version: 1, GridSize: 2, HeaderText: "BINGO"},
+		"category":   {Format: CardDocumentFormat, Version: 1, GridSize: 3, Category: &bad},
+		"long goal":  {Format: CardDocumentFormat, Version: 1, GridSize: 3, Items: []CardDocumentItem{{Content: long}}},
+		"difficulty": {Format: CardDocumentFormat, Version: 1, GridSize: 3, Items: []CardDocumentItem{{Content: "a", Difficulty: &bad}}},
+	}
+	for name, doc := range cases {
+		if _, _, err := doc.ImportParams(uuid.New(), 2026); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -13,8 +13,62 @@ const (
 	NotificationTypeFriendRequestAccepted NotificationType = "friend_request_accepted"
 	NotificationTypeFriendBingo           NotificationType = "friend_bingo"
 	NotificationTypeFriendNewCard         NotificationType = "friend_new_card"
+
+	// NotificationTypeDraftNudge reminds a user to finalize a forgotten
+	// draft. It has no per-type setting; only the channel switches apply.
+	NotificationTypeDraftNudge NotificationType = "draft_nudge"
+	// NotificationTypeItemComment tells a card owner a friend commented on one
+	// of their goals. It is in-app only and has no per-type setting.
+	NotificationTypeItemComment NotificationType = "item_comment"
+	// NotificationTypeReminderCheckin and NotificationTypeReminderGoal mirror
+	// a reminder email in the bell, so a user whose mail is failing still sees
+	// it. They are in-app only, with one setting each.
+	NotificationTypeReminderCheckin NotificationType = "reminder_checkin"
+	NotificationTypeReminderGoal    NotificationType = "reminder_goal"
+)
+
+// NotificationTypes lists the types that have a per-channel setting.
+var NotificationTypes = []NotificationType{
+	NotificationTypeFriendRequestReceived,
+	NotificationTypeFriendRequestAccepted,
+	NotificationTypeFriendBingo,
+	NotificationTypeFriendNewCard,
+}
+
+// NotificationChannel is a way notifications reach a user. Each channel has a
+// global switch and one flag per notification type.
+type NotificationChannel string
+
+const (
+	NotificationChannelInApp NotificationChannel = "in_app"
+	NotificationChannelEmail NotificationChannel = "email"
+)
+
+var NotificationChannels = []NotificationChannel{
+	NotificationChannelInApp,
+	NotificationChannelEmail,
+}
+
+func IsValidNotificationChannel(channel NotificationChannel) bool {
+	for _, c := range NotificationChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// Email formats a user can choose for reminder and notification emails.
+// Text sends a single plaintext part for mail setups that strip HTML.
+const (
+	EmailFormatHTML = "html"
+	EmailFormatText = "text"
 )
 
+func IsValidEmailFormat(format string) bool {
+	return format == EmailFormatHTML || format == EmailFormatText
+}
+
 type Notification struct {
 	ID             uuid.UUID        `json:"id"`
 	UserID         uuid.UUID        `json:"user_id"`
@@ -26,6 +80,8 @@ type Notification struct {
 	CardTitle      *string          `json:"card_title,omitempty"`
 	CardYear       *int             `json:"card_year,omitempty"`
 	BingoCount     *int             `json:"bingo_count,omitempty"`
+	ItemID         *uuid.UUID       `json:"item_id,omitempty"`
+	ItemContent    *string          `json:"item_content,omitempty"`
 	InAppDelivered bool             `json:"in_app_delivered"`
 	EmailDelivered bool             `json:"email_delivered"`
 	EmailSentAt    *time.Time       `json:"email_sent_at,omitempty"`
@@ -34,30 +90,101 @@ type Notification struct {
 }
 
 type NotificationSettings struct {
-	UserID                     uuid.UUID `json:"user_id"`
-	InAppEnabled               bool      `json:"in_app_enabled"`
-	InAppFriendRequestReceived bool      `json:"in_app_friend_request_received"`
-	InAppFriendRequestAccepted bool      `json:"in_app_friend_request_accepted"`
-	InAppFriendBingo           bool      `json:"in_app_friend_bingo"`
-	InAppFriendNewCard         bool      `json:"in_app_friend_new_card"`
-	EmailEnabled               bool      `json:"email_enabled"`
-	EmailFriendRequestReceived bool      `json:"email_friend_request_received"`
-	EmailFriendRequestAccepted bool      `json:"email_friend_request_accepted"`
-	EmailFriendBingo           bool      `json:"email_friend_bingo"`
-	EmailFriendNewCard         bool      `json:"email_friend_new_card"`
-	CreatedAt                  time.Time `json:"created_at"`
-	UpdatedAt                  time.Time `json:"updated_at"`
+	UserID                     uuid.UUID  `json:"user_id"`
+	InAppEnabled               bool       `json:"in_app_enabled"`
+	InAppFriendRequestReceived bool       `json:"in_app_friend_request_received"`
+	InAppFriendRequestAccepted bool       `json:"in_app_friend_request_accepted"`
+	InAppFriendBingo           bool       `json:"in_app_friend_bingo"`
+	InAppFriendNewCard         bool       `json:"in_app_friend_new_card"`
+	InAppReminderCheckin       bool       `json:"in_app_reminder_checkin"`
+	InAppReminderGoal          bool       `json:"in_app_reminder_goal"`
+	EmailEnabled               bool       `json:"email_enabled"`
+	EmailFriendRequestReceived bool       `json:"email_friend_request_received"`
+	EmailFriendRequestAccepted bool       `json:"email_friend_request_accepted"`
+	EmailFriendBingo           bool       `json:"email_friend_bingo"`
+	EmailFriendNewCard         bool       `json:"email_friend_new_card"`
+	EmailFriendsDigest         bool       `json:"email_friends_digest"`
+	EmailFormat                string     `json:"email_format"`
+	EmailPausedUntil           *time.Time `json:"email_paused_until"`
+	CreatedAt                  time.Time  `json:"created_at"`
+	UpdatedAt                  time.Time  `json:"updated_at"`
+}
+
+// ChannelEnabled reports the global switch of a channel.
+func (s *NotificationSettings) ChannelEnabled(channel NotificationChannel) bool {
+	switch channel {
+	case NotificationChannelInApp:
+		return s.InAppEnabled
+	case NotificationChannelEmail:
+		return s.EmailEnabled
+	default:
+		return false
+	}
+}
+
+// TypeEnabled reports the flag for one notification type on a channel,
+// regardless of the channel's global switch.
+func (s *NotificationSettings) TypeEnabled(channel NotificationChannel, nType NotificationType) bool {
+	return s.typeFlags()[channel][nType]
+}
+
+func (s *NotificationSettings) typeFlags() map[NotificationChannel]map[NotificationType]bool {
+	return map[NotificationChannel]map[NotificationType]bool{
+		NotificationChannelInApp: {
+			NotificationTypeFriendRequestReceived: s.InAppFriendRequestReceived,
+			NotificationTypeFriendRequestAccepted: s.InAppFriendRequestAccepted,
+			NotificationTypeFriendBingo:           s.InAppFriendBingo,
+			NotificationTypeFriendNewCard:         s.InAppFriendNewCard,
+			NotificationTypeReminderCheckin:       s.InAppReminderCheckin,
+			NotificationTypeReminderGoal:          s.InAppReminderGoal,
+		},
+		NotificationChannelEmail: {
+			NotificationTypeFriendRequestReceived: s.EmailFriendRequestReceived,
+			NotificationTypeFriendRequestAccepted: s.EmailFriendRequestAccepted,
+			NotificationTypeFriendBingo:           s.EmailFriendBingo,
+			NotificationTypeFriendNewCard:         s.EmailFriendNewCard,
+		},
+	}
 }
 
 type NotificationSettingsPatch struct {
-	InAppEnabled               *bool `json:"in_app_enabled,omitempty"`
-	InAppFriendRequestReceived *bool `json:"in_app_friend_request_received,omitempty"`
-	InAppFriendRequestAccepted *bool `json:"in_app_friend_request_accepted,omitempty"`
-	InAppFriendBingo           *bool `json:"in_app_friend_bingo,omitempty"`
-	InAppFriendNewCard         *bool `json:"in_app_friend_new_card,omitempty"`
-	EmailEnabled               *bool `json:"email_enabled,omitempty"`
-	EmailFriendRequestReceived *bool `json:"email_friend_request_received,omitempty"`
-	EmailFriendRequestAccepted *bool `json:"email_friend_request_accepted,omitempty"`
-	EmailFriendBingo           *bool `json:"email_friend_bingo,omitempty"`
-	EmailFriendNewCard         *bool `json:"email_friend_new_card,omitempty"`
+	InAppEnabled               *bool   `json:"in_app_enabled,omitempty"`
+	InAppFriendRequestReceived *bool   `json:"in_app_friend_request_received,omitempty"`
+	InAppFriendRequestAccepted *bool   `json:"in_app_friend_request_accepted,omitempty"`
+	InAppFriendBingo           *bool   `json:"in_app_friend_bingo,omitempty"`
+	InAppFriendNewCard         *bool   `json:"in_app_friend_new_card,omitempty"`
+	InAppReminderCheckin       *bool   `json:"in_app_reminder_checkin,omitempty"`
+	InAppReminderGoal          *bool   `json:"in_app_reminder_goal,omitempty"`
+	EmailEnabled               *bool   `json:"email_enabled,omitempty"`
+	EmailFriendRequestReceived *bool   `json:"email_friend_request_received,omitempty"`
+	EmailFriendRequestAccepted *bool   `json:"email_friend_request_accepted,omitempty"`
+	EmailFriendBingo           *bool   `json:"email_friend_bingo,omitempty"`
+	EmailFriendNewCard         *bool   `json:"email_friend_new_card,omitempty"`
+	EmailFriendsDigest         *bool   `json:"email_friends_digest,omitempty"`
+	EmailFormat                *string `json:"email_format,omitempty"`
+}
+
+// NotificationSettingsCopyInput copies the per-type flags of one channel onto
+// another.
+type NotificationSettingsCopyInput struct {
+	From NotificationChannel `json:"from"`
+	To   NotificationChannel `json:"to"`
+}
+
+// NotificationEmailPauseInput sets or clears the global email pause. A nil
+// Until resumes email immediately.
+type NotificationEmailPauseInput struct {
+	Until *time.Time `json:"until"`
+}
+
+// EmailPreferences are the email toggles on the tokenized preferences page.
+// ReminderEmails lives in reminder_settings; the rest in notification_settings.
+type EmailPreferences struct {
+	ReminderEmails        bool `json:"reminder_emails"`
+	NotificationEmails    bool `json:"notification_emails"`
+	FriendRequestReceived bool `json:"friend_request_received"`
+	FriendRequestAccepted bool `json:"friend_request_accepted"`
+	FriendBingo           bool `json:"friend_bingo"`
+	FriendNewCard         bool `json:"friend_new_card"`
+	FriendsDigest         bool `json:"friends_digest"`
 }
@@ -0,0 +1,55 @@
+package models
+
+import "github.com/google/uuid"
+
+const (
+	ProfileVisibilityOff    = "off"
+	ProfileVisibilityPublic = "public"
+)
+
+// ProfilePublicNotice is shown wherever a public profile is turned on. Blocks
+// and friendships cannot be enforced on a page anyone can open.
+const ProfilePublicNotice = "Your public profile can be seen by anyone with the link, including people you have blocked. It shows your finalized cards that are visible to friends, with progress only."
+
+// ProfileSettings controls the public profile page at /u/{username}.
+type ProfileSettings struct {
+	Visibility string `json:"profile_visibility"`
+	Indexable  bool   `json:"profile_indexable"`
+	URL        string `json:"profile_url"`
+	Notice     string `json:"notice"`
+}
+
+// PublicProfileCard is one card on a public profile. Goal text is never
+// included, only progress.
+type PublicProfileCard struct {
+	Year           int     `json:"year"`
+	Title          *string `json:"title,omitempty"`
+	Category       *string `json:"category,omitempty"`
+	GridSize       int     `json:"grid_size"`
+	IsArchived     bool    `json:"is_archived"`
+	CompletedItems int     `json:"completed_items"`
+	TotalItems     int     `json:"total_items"`
+	Bingos         int     `json:"bingos"`
+}
+
+// DisplayName returns the card title, falling back to "<year> Bingo Card".
+func (c PublicProfileCard) DisplayName() string {
+	card := BingoCard{Year: c.Year, Title: c.Title}
+	return card.DisplayName()
+}
+
+// Percent returns the card's completion as a whole percentage.
+func (c PublicProfileCard) Percent() int {
+	if c.TotalItems <= 0 {
+		return 0
+	}
+	return c.CompletedItems * 100 / c.TotalItems
+}
+
+// PublicProfile is what /u/{username} shows.
+type PublicProfile struct {
+	UserID    uuid.UUID           `json:"-"`
+	Username  string              `json:"username"`
+	Indexable bool                `json:"-"`
+	Cards     []PublicProfileCard `json:"cards"`
+}
@@ -21,7 +21,12 @@ type ReactionWithUser struct {
 	UserUsername string `json:"user_username"`
 }
 
+// ReactionDisplayMax caps reaction counts shown in summaries; larger counts
+// display as "99+".
+const ReactionDisplayMax = 99
+
 type ReactionSummary struct {
-	Emoji string `json:"emoji"`
-	Count int    `json:"count"`
+	Emoji        string `json:"emoji"`
+	Count        int    `json:"count"`
+	DisplayCount string `json:"display_count"`
 }
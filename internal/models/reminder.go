@@ -5,20 +5,66 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
+)
+
+// Reminder image token modes. Reuse keeps one long-lived token per card and
+// extends it on every email; per-email mints a short-lived, access-capped
+// token for each email so old forwarded emails stop showing the live card.
+const (
+	ReminderImageTokenReuse    = "reuse"
+	ReminderImageTokenPerEmail = "per_email"
 )
 
-// ReminderSettings stores user-level reminder preferences.
+// Card image themes. Dark draws the card on a dark background for dark-mode
+// mail clients and link previews; light is the default.
+const (
+	ImageThemeLight = "light"
+	ImageThemeDark  = "dark"
+)
+
+// IsValidImageTheme reports whether theme is a known card image theme.
+func IsValidImageTheme(theme string) bool {
+	return theme == ImageThemeLight || theme == ImageThemeDark
+}
+
+// DefaultReminderTimezone is the zone reminder times are read in until the
+// user picks one.
+const DefaultReminderTimezone = "UTC"
+
+// DefaultReminderDigestTime is when the daily digest goes out, in the
+// user's zone, until they pick another time.
+const DefaultReminderDigestTime = "08:00"
+
+// ReminderSettings stores user-level reminder preferences. Timezone is the
+// IANA zone that check-in and goal reminder times are interpreted in. With
+// DigestEnabled, due reminders are held and sent together once a day at
+// DigestTime ("15:04" in Timezone); DigestNextAt is the next such send.
 type ReminderSettings struct {
-	UserID        uuid.UUID `json:"user_id"`
-	EmailEnabled  bool      `json:"email_enabled"`
-	DailyEmailCap int       `json:"daily_email_cap"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	UserID           uuid.UUID  `json:"user_id"`
+	EmailEnabled     bool       `json:"email_enabled"`
+	DailyEmailCap    int        `json:"daily_email_cap"`
+	ImageTokenMode   string     `json:"image_token_mode"`
+	ImageTheme       string     `json:"image_theme"`
+	Timezone         string     `json:"timezone"`
+	DigestEnabled    bool       `json:"digest_enabled"`
+	DigestTime       string     `json:"digest_time"`
+	DigestNextAt     *time.Time `json:"digest_next_at"`
+	EmailPausedUntil *time.Time `json:"email_paused_until"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 // ReminderSettingsPatch allows partial updates to reminder settings.
+// Timezone is an IANA zone name such as "America/New_York".
 type ReminderSettingsPatch struct {
-	EmailEnabled *bool `json:"email_enabled,omitempty"`
+	EmailEnabled   *bool   `json:"email_enabled,omitempty"`
+	ImageTokenMode *string `json:"image_token_mode,omitempty"`
+	ImageTheme     *string `json:"image_theme,omitempty"`
+	Timezone       *string `json:"timezone,omitempty"`
+	DigestEnabled  *bool   `json:"digest_enabled,omitempty"`
+	DigestTime     *string `json:"digest_time,omitempty"`
 }
 
 // CardCheckinReminder stores a per-card reminder schedule.
@@ -31,6 +77,7 @@ type CardCheckinReminder struct {
 	Schedule               json.RawMessage `json:"schedule"`
 	IncludeImage           bool            `json:"include_image"`
 	IncludeRecommendations bool            `json:"include_recommendations"`
+	IncludeMemories        bool            `json:"include_memories"`
 	NextSendAt             *time.Time      `json:"next_send_at,omitempty"`
 	LastSentAt             *time.Time      `json:"last_sent_at,omitempty"`
 	CreatedAt              time.Time       `json:"created_at"`
@@ -43,12 +90,16 @@ type CardCheckinScheduleInput struct {
 	Schedule               CardCheckinSchedulePayload `json:"schedule"`
 	IncludeImage           *bool                      `json:"include_image,omitempty"`
 	IncludeRecommendations *bool                      `json:"include_recommendations,omitempty"`
+	IncludeMemories        *bool                      `json:"include_memories,omitempty"`
 }
 
 // CardCheckinSchedulePayload describes the monthly schedule payload.
+// JitterWindowMinutes (0-60) opts in to a random send-time offset of up to
+// that many minutes either side of Time, chosen once when the schedule is saved.
 type CardCheckinSchedulePayload struct {
-	DayOfMonth int    `json:"day_of_month"`
-	Time       string `json:"time"`
+	DayOfMonth          int    `json:"day_of_month"`
+	Time                string `json:"time"`
+	JitterWindowMinutes int    `json:"jitter_window_minutes,omitempty"`
 }
 
 // CardCheckinSummary joins card metadata with an optional reminder.
@@ -85,9 +136,75 @@ type GoalReminderInput struct {
 	Schedule GoalReminderScheduleInput `json:"schedule"`
 }
 
-// GoalReminderScheduleInput defines goal reminder scheduling fields.
+// GoalReminderBulkInput is the payload for setting one reminder on every
+// open goal of a card.
+type GoalReminderBulkInput struct {
+	CardID    uuid.UUID                 `json:"card_id"`
+	Kind      string                    `json:"kind"`
+	Schedule  GoalReminderScheduleInput `json:"schedule"`
+	Overwrite bool                      `json:"overwrite"`
+}
+
+// GoalReminderBulkResult lists the reminders a bulk upsert created and
+// updated, and the goals it left alone because they already had one.
+type GoalReminderBulkResult struct {
+	Created        []uuid.UUID `json:"created"`
+	Updated        []uuid.UUID `json:"updated"`
+	SkippedItemIDs []uuid.UUID `json:"skipped_item_ids"`
+}
+
+// Goal reminder kinds. A one_time reminder sends once at SendAt; a recurring
+// reminder sends every EveryDays days at Time until the goal is completed.
+const (
+	GoalReminderKindOneTime   = "one_time"
+	GoalReminderKindRecurring = "recurring"
+)
+
+// GoalReminderSnoozeDays are the lengths, in days, a goal reminder email's
+// snooze link offers.
+var GoalReminderSnoozeDays = []int{1, 7, 30}
+
+// GoalReminderScheduleInput defines goal reminder scheduling fields. SendAt
+// is used by one_time reminders; EveryDays and Time ("15:04", in the user's
+// reminder time zone) by recurring ones.
 type GoalReminderScheduleInput struct {
-	SendAt string `json:"send_at"`
+	SendAt    string `json:"send_at,omitempty"`
+	EveryDays int    `json:"every_days,omitempty"`
+	Time      string `json:"time,omitempty"`
+}
+
+// Reminder schedule types accepted by the schedule preview.
+const (
+	ReminderScheduleTypeCheckin = "checkin"
+	ReminderScheduleTypeGoal    = "goal"
+)
+
+// Error codes of an invalid schedule preview.
+const (
+	ReminderScheduleErrorInvalidType     = "invalid_type"
+	ReminderScheduleErrorInvalidSchedule = "invalid_schedule"
+	ReminderScheduleErrorSendAtInPast    = "send_at_in_past"
+)
+
+// ReminderScheduleValidationInput is a check-in (Frequency and a
+// CardCheckinSchedulePayload) or goal reminder (Kind and a
+// GoalReminderScheduleInput) schedule to preview.
+type ReminderScheduleValidationInput struct {
+	Type      string          `json:"type"`
+	Frequency string          `json:"frequency,omitempty"`
+	Kind      string          `json:"kind,omitempty"`
+	Schedule  json.RawMessage `json:"schedule"`
+}
+
+// ReminderScheduleValidation is the outcome of a schedule preview.
+// NormalizedSchedule is the schedule as it would be stored and NextSendAt the
+// first send, in the user's reminder time zone.
+type ReminderScheduleValidation struct {
+	Valid              bool            `json:"valid"`
+	NormalizedSchedule json.RawMessage `json:"normalized_schedule,omitempty"`
+	NextSendAt         *time.Time      `json:"next_send_at,omitempty"`
+	Timezone           string          `json:"timezone"`
+	ErrorCode          string          `json:"error_code,omitempty"`
 }
 
 // GoalReminderSummary joins reminder data with card/item context for the UI.
@@ -114,6 +231,7 @@ type ReminderImageToken struct {
 	CreatedAt       time.Time  `json:"created_at"`
 	LastAccessedAt  *time.Time `json:"last_accessed_at,omitempty"`
 	AccessCount     int        `json:"access_count"`
+	MaxAccessCount  *int       `json:"max_access_count,omitempty"`
 }
 
 // ReminderUnsubscribeToken is a stored token for one-click unsubscribe.
@@ -124,3 +242,109 @@ type ReminderUnsubscribeToken struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UsedAt    *time.Time `json:"used_at,omitempty"`
 }
+
+// ReminderEmailLogEntry is a single reminder email send attempt.
+type ReminderEmailLogEntry struct {
+	ID         uuid.UUID `json:"id"`
+	SourceType string    `json:"source_type"`
+	SourceID   uuid.UUID `json:"source_id"`
+	Status     string    `json:"status"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+// UserReminderReport is the support view of a user's reminders and recent sends.
+type UserReminderReport struct {
+	UserID   uuid.UUID               `json:"user_id"`
+	Settings *ReminderSettings       `json:"settings"`
+	Checkins []CardCheckinReminder   `json:"checkins"`
+	Goals    []GoalReminder          `json:"goals"`
+	EmailLog []ReminderEmailLogEntry `json:"email_log"`
+	// ClickThrough covers the user's reminder links that haven't been
+	// cleaned up yet.
+	ClickThrough *ReminderClickThrough `json:"click_through"`
+}
+
+// ReminderClickThrough counts reminder emails with a tracked card link and how
+// many of those links were opened at least once.
+type ReminderClickThrough struct {
+	Tracked int     `json:"tracked"`
+	Clicked int     `json:"clicked"`
+	Rate    float64 `json:"rate"`
+}
+
+// ReminderHistoryEntry is one reminder email as shown to its recipient. The
+// card and goal fields are nil when the reminder or card no longer exists or
+// the email isn't tied to one.
+type ReminderHistoryEntry struct {
+	ID         uuid.UUID  `json:"id"`
+	SourceType string     `json:"source_type"`
+	CardID     *uuid.UUID `json:"card_id,omitempty"`
+	CardTitle  *string    `json:"card_title,omitempty"`
+	CardYear   *int       `json:"card_year,omitempty"`
+	GoalText   *string    `json:"goal_text,omitempty"`
+	Status     string     `json:"status"`
+	SentAt     time.Time  `json:"sent_at"`
+}
+
+// ReminderEmailHistory is a user's view of their recent reminder sends.
+type ReminderEmailHistory struct {
+	Items        []ReminderHistoryEntry `json:"items"`
+	ClickThrough *ReminderClickThrough  `json:"click_through"`
+	pagination.Meta
+}
+
+// ReminderResendResult describes the outcome of a manual reminder resend.
+type ReminderResendResult struct {
+	ReminderID uuid.UUID `json:"reminder_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	SourceType string    `json:"source_type"`
+	Status     string    `json:"status"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+// Deliverability checklist item statuses.
+const (
+	DeliverabilityOK      = "ok"
+	DeliverabilityWarning = "warning"
+	DeliverabilityFailed  = "failed"
+	DeliverabilityUnknown = "unknown"
+)
+
+// DeliverabilityCheckItem is one line of the reminder deliverability checklist.
+type DeliverabilityCheckItem struct {
+	Key    string `json:"key"`
+	Label  string `json:"label"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// DeliverabilityProbe is the latest self-serve probe email and whether the
+// email provider accepted it.
+type DeliverabilityProbe struct {
+	Status string    `json:"status"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// DeliverabilityCheck explains why reminder emails may not be arriving.
+type DeliverabilityCheck struct {
+	Probe       *DeliverabilityProbe      `json:"probe"`
+	NextCheckAt *time.Time                `json:"next_check_at"`
+	Checklist   []DeliverabilityCheckItem `json:"checklist"`
+}
+
+// ReminderCalendarFeed is the state of a user's reminder calendar feed. URL
+// carries the feed token, so it is only filled in when the feed is created.
+type ReminderCalendarFeed struct {
+	Active    bool       `json:"active"`
+	URL       string     `json:"url,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// ReminderRunResult counts what one run of the reminder sender did with the
+// due reminders and digests it picked up.
+type ReminderRunResult struct {
+	AsOf     time.Time `json:"as_of"`
+	Sent     int       `json:"sent"`
+	Deferred int       `json:"deferred"`
+	Skipped  int       `json:"skipped"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// SeedReport summarizes what `server seed` created.
+type SeedReport struct {
+	// Password is the password of every seeded account.
+	Password      string     `json:"password"`
+	Users         []SeedUser `json:"users"`
+	Cards         int        `json:"cards"`
+	Completed     int        `json:"completed_items"`
+	Friendships   int        `json:"friendships"`
+	Notifications int        `json:"notifications"`
+	Reminders     int        `json:"reminders"`
+}
+
+type SeedUser struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
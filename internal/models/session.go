@@ -7,9 +7,15 @@ import (
 )
 
 type Session struct {
-	ID        uuid.UUID `json:"id"`
-	UserID    uuid.UUID `json:"user_id"`
-	TokenHash string    `json:"-"`
-	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	// UserAgent and IPAddress are captured when the session is created.
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+	// Current marks the session the request listing sessions was made with.
+	Current bool `json:"current"`
 }
@@ -19,3 +19,26 @@ var SuggestionCategories = []string{
 	"Personal Growth",
 	"Home & Organization",
 }
+
+// SuggestionStats is one curated suggestion's adoption and completion over an
+// analytics window. Goals match a suggestion by trimmed, case-insensitive content.
+type SuggestionStats struct {
+	SuggestionID   uuid.UUID `json:"suggestion_id"`
+	Category       string    `json:"category"`
+	Content        string    `json:"content"`
+	AddedCount     int       `json:"added_count"`
+	MatchedItems   int       `json:"matched_items"`
+	CompletedItems int       `json:"completed_items"`
+	CompletionRate float64   `json:"completion_rate"`
+}
+
+// SuggestionAnalytics ranks active suggestions by adoption and completion.
+// Completion rankings only include suggestions with at least MinSample goals.
+type SuggestionAnalytics struct {
+	WindowDays        int               `json:"window_days"`
+	MinSample         int               `json:"min_sample"`
+	MostAdopted       []SuggestionStats `json:"most_adopted"`
+	LeastAdopted      []SuggestionStats `json:"least_adopted"`
+	HighestCompletion []SuggestionStats `json:"highest_completion"`
+	LowestCompletion  []SuggestionStats `json:"lowest_completion"`
+}
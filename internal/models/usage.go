@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// UsageLimit is one per-user rate limit and how much of its current window
+// the user has spent.
+type UsageLimit struct {
+	Name          string `json:"name"`
+	Limit         int64  `json:"limit"`
+	WindowSeconds int64  `json:"window_seconds"`
+	Used          int64  `json:"used"`
+	Remaining     int64  `json:"remaining"`
+}
+
+// UsageReport is a user's API request counts for the current UTC day by route
+// group, alongside the limits that apply to them.
+type UsageReport struct {
+	Date          string           `json:"date"`
+	Requests      map[string]int64 `json:"requests"`
+	TotalRequests int64            `json:"total_requests"`
+	Limits        []UsageLimit     `json:"limits"`
+	// AIFreeGenerationsRemaining is null once the user has verified their
+	// email, since the free trial no longer applies.
+	AIFreeGenerationsRemaining *int `json:"ai_free_generations_remaining"`
+}
+
+// AI quota tiers. Unverified users share a one-time trial, verified users
+// get a monthly allowance, and users an admin marked unlimited have no cap.
+const (
+	AIQuotaTierTrial     = "trial"
+	AIQuotaTierFree      = "free"
+	AIQuotaTierUnlimited = "unlimited"
+)
+
+// AIQuota is how many AI generations a user has used and has left. Limit and
+// Remaining are null for the unlimited tier; ResetsAt is only set for the
+// monthly free tier.
+type AIQuota struct {
+	Tier      string     `json:"tier"`
+	Used      int        `json:"used"`
+	Limit     *int       `json:"limit"`
+	Remaining *int       `json:"remaining"`
+	ResetsAt  *time.Time `json:"resets_at"`
+}
@@ -19,6 +19,21 @@ type User struct {
 	UpdatedAt             time.Time  `json:"updated_at"`
 }
 
+// UserPreferences holds account-level privacy preferences.
+type UserPreferences struct {
+	// DataMinimization stops AI generation logs and share access analytics
+	// from being recorded for the user.
+	DataMinimization bool `json:"data_minimization"`
+}
+
+// LinkedIdentity is a login provider account attached to a user. Email is
+// the provider's address when it was linked and isn't kept up to date.
+type LinkedIdentity struct {
+	Provider string    `json:"provider"`
+	Email    *string   `json:"email,omitempty"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
 type CreateUserParams struct {
 	Email        string
 	PasswordHash *string
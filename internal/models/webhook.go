@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook events sent to a user's webhooks.
+const (
+	WebhookEventCardFinalized = "card.finalized"
+	WebhookEventItemCompleted = "item.completed"
+	WebhookEventBingo         = "card.bingo"
+)
+
+// Webhook delivery statuses. A pending delivery is retried until it is
+// delivered or runs out of attempts and becomes failed.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // Only returned when the webhook is created
+	CreatedAt time.Time `json:"created_at"`
+
+	RecentDeliveries []WebhookDelivery `json:"recent_deliveries"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID  `json:"id"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus *int       `json:"response_status,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"` // Only set while pending
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookCardFinalized is the data of a card.finalized event.
+type WebhookCardFinalized struct {
+	CardID   uuid.UUID `json:"card_id"`
+	Year     int       `json:"year"`
+	Title    *string   `json:"title,omitempty"`
+	GridSize int       `json:"grid_size"`
+}
+
+// WebhookItemCompleted is the data of an item.completed event.
+type WebhookItemCompleted struct {
+	CardID      uuid.UUID `json:"card_id"`
+	ItemID      uuid.UUID `json:"item_id"`
+	Position    int       `json:"position"`
+	Content     string    `json:"content"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// WebhookBingo is the data of a card.bingo event, sent when a completion
+// finishes one or more new lines.
+type WebhookBingo struct {
+	CardID     uuid.UUID `json:"card_id"`
+	ItemID     uuid.UUID `json:"item_id"`
+	BingoCount int       `json:"bingo_count"`
+	NewLines   int       `json:"new_lines"`
+}
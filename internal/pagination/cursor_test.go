@@ -0,0 +1,93 @@
+package pagination
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codec := NewCodec("secret")
+	key := Key{At: time.Date(2026, 3, 9, 14, 30, 0, 123456789, time.UTC), ID: uuid.New()}
+
+	cursor, err := codec.Decode("notifications", codec.EncodeKey("notifications", key))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Nanoseconds past the database's microseconds are dropped.
+	if cursor.Key == nil || !cursor.Key.At.Equal(key.At.Truncate(time.Microsecond)) || cursor.Key.ID != key.ID || cursor.Offset != 0 {
+		t.Fatalf("expected key %+v back, got %+v", key, cursor)
+	}
+
+	cursor, err = codec.Decode("card_search", codec.EncodeOffset("card_search", 40))
+	if err != nil || cursor.Key != nil || cursor.Offset != 40 {
+		t.Fatalf("expected offset 40, got %+v, %v", cursor, err)
+	}
+}
+
+func TestCodec_RejectsForgedCursors(t *testing.T) {
+	codec := NewCodec("secret")
+	token := codec.EncodeOffset("card_search", 40)
+	body, mac, _ := strings.Cut(token, ".")
+
+	forgedBody := cursorEncoding.EncodeToString([]byte(`{"s":"card_search","o":9000}`))
+	tests := map[string]string{
+		"edited offset":    forgedBody + "." + mac,
+		"other key":        NewCodec("other").EncodeOffset("card_search", 40),
+		"other list":       codec.EncodeOffset("admin_search", 40),
+		"missing mac":      body,
+		"truncated mac":    body + "." + mac[:len(mac)-2],
+		"not base64":       "!!!." + mac,
+		"empty":            "",
+		"too long":         strings.Repeat("a", maxCursorLength+1),
+		"offset too large": codec.EncodeOffset("card_search", MaxOffset+1),
+		"zero offset":      codec.EncodeOffset("card_search", 0),
+	}
+	for name, token := range tests {
+		if _, err := codec.Decode("card_search", token); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: expected ErrInvalidCursor, got %v", name, err)
+		}
+	}
+}
+
+func TestNewCodec_RandomKeyWithoutSecret(t *testing.T) {
+	first, second := NewCodec(""), NewCodec("")
+	if _, err := second.Decode("notifications", first.EncodeOffset("notifications", 1)); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected codecs without a secret not to share a key, got %v", err)
+	}
+}
+
+// FuzzCodec_Decode checks that decoding never panics and that every cursor
+// it accepts is one the codec itself would have issued.
+func FuzzCodec_Decode(f *testing.F) {
+	codec := NewCodec("fuzz")
+	f.Add(codec.EncodeKey("list", Key{At: time.Unix(1700000000, 0), ID: uuid.New()}))
+	f.Add(codec.EncodeOffset("list", 20))
+	f.Add("")
+	f.Add(".")
+	f.Add("e30.AAAA")
+
+	f.Fuzz(func(t *testing.T, token string) {
+		cursor, err := codec.Decode("list", token)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("expected ErrInvalidCursor, got %v", err)
+			}
+			return
+		}
+		var reencoded string
+		if cursor.Key != nil {
+			reencoded = codec.EncodeKey("list", *cursor.Key)
+		} else {
+			reencoded = codec.EncodeOffset("list", cursor.Offset)
+		}
+		again, err := codec.Decode("list", reencoded)
+		if err != nil || (cursor.Key == nil) != (again.Key == nil) || cursor.Offset != again.Offset ||
+			(cursor.Key != nil && (!cursor.Key.At.Equal(again.Key.At) || cursor.Key.ID != again.Key.ID)) {
+			t.Fatalf("accepted cursor %q decoded to %+v, which does not round-trip", token, cursor)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package pagination
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// columnPattern is what Order accepts as a column: a plain, optionally
+// table-qualified identifier.
+var columnPattern = regexp.MustCompile(`^([a-z_][a-z0-9_]*\.)?[a-z_][a-z0-9_]*$`)
+
+// Order is a newest-first keyset ordering on a time column, with an ID
+// column breaking ties so rows sharing a timestamp are neither skipped nor
+// repeated across pages.
+type Order struct {
+	timeColumn string
+	idColumn   string
+}
+
+// NewOrder returns the ordering on the given columns. Column names are
+// spliced into SQL, so anything but a plain identifier panics; callers pass
+// constants.
+func NewOrder(timeColumn, idColumn string) Order {
+	for _, column := range []string{timeColumn, idColumn} {
+		if !columnPattern.MatchString(column) {
+			panic(fmt.Sprintf("pagination: invalid column %q", column))
+		}
+	}
+	return Order{timeColumn: timeColumn, idColumn: idColumn}
+}
+
+// OrderBy is the ORDER BY clause for the ordering, without the keywords.
+func (o Order) OrderBy() string {
+	return o.timeColumn + " DESC, " + o.idColumn + " DESC"
+}
+
+// After returns a WHERE condition selecting the rows that come after key,
+// numbering its placeholders from argIndex, and the args it takes. The key
+// only ever reaches the query as arguments.
+func (o Order) After(key Key, argIndex int) (string, []any) {
+	condition := fmt.Sprintf("(%[1]s < $%[3]d OR (%[1]s = $%[3]d AND %[2]s < $%[4]d))",
+		o.timeColumn, o.idColumn, argIndex, argIndex+1)
+	return condition, []any{key.At, key.ID}
+}
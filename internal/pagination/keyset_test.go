@@ -0,0 +1,37 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestOrder_After(t *testing.T) {
+	order := NewOrder("n.created_at", "n.id")
+	key := Key{At: time.Now(), ID: uuid.New()}
+
+	condition, args := order.After(key, 3)
+	if want := "(n.created_at < $3 OR (n.created_at = $3 AND n.id < $4))"; condition != want {
+		t.Fatalf("expected %q, got %q", want, condition)
+	}
+	if len(args) != 2 || args[0] != key.At || args[1] != key.ID {
+		t.Fatalf("expected the key as args, got %v", args)
+	}
+	if got := order.OrderBy(); got != "n.created_at DESC, n.id DESC" {
+		t.Fatalf("unexpected ORDER BY %q", got)
+	}
+}
+
+func TestNewOrder_RejectsNonIdentifiers(t *testing.T) {
+	for _, column := range []string{"", "created_at; DROP TABLE users", "a.b.c", "created_at DESC", "Created"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected NewOrder to panic for %q", column)
+				}
+			}()
+			NewOrder(column, "id")
+		}()
+	}
+}
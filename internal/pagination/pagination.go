@@ -0,0 +1,70 @@
+// Package pagination is the one way list endpoints page: a clamped limit,
+// an opaque signed cursor naming where the next page starts, and the same
+// next_cursor/has_more fields on every paged response. Handlers own the
+// cursor codec; services see only the decoded Key or offset and report
+// whether more rows follow.
+package pagination
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultLimit and MaxLimit apply to endpoints without limits of their own.
+const (
+	DefaultLimit = 50
+	MaxLimit     = 100
+)
+
+// ErrInvalidLimit is returned by ParseLimit for a limit that isn't a
+// positive integer.
+var ErrInvalidLimit = errors.New("invalid limit")
+
+// ParseLimit reads a limit query parameter: empty means def, and values over
+// max are clamped to max.
+func ParseLimit(raw string, def, max int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return 0, ErrInvalidLimit
+	}
+	if limit > max {
+		return max, nil
+	}
+	return limit, nil
+}
+
+// Key is a keyset position: the sort time and ID of the last row of a page,
+// for lists ordered newest first with the ID breaking ties.
+type Key struct {
+	At time.Time
+	ID uuid.UUID
+}
+
+// Meta is the paging state added to every paged response. NextCursor is
+// empty on the last page.
+type Meta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// Page is the response envelope for paged lists without a named field of
+// their own.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Meta
+}
+
+// Trim cuts rows fetched with a limit of limit+1 back to limit, reporting
+// whether the extra row was there.
+func Trim[T any](rows []T, limit int) ([]T, bool) {
+	if len(rows) > limit {
+		return rows[:limit], true
+	}
+	return rows, false
+}
@@ -0,0 +1,46 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{raw: "", want: 20},
+		{raw: "5", want: 5},
+		{raw: "50", want: 50},
+		{raw: "51", want: 50},
+		{raw: "100000000000000000000", wantErr: true},
+		{raw: "0", wantErr: true},
+		{raw: "-3", wantErr: true},
+		{raw: "ten", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.raw, 20, 50)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidLimit) {
+				t.Errorf("ParseLimit(%q): expected ErrInvalidLimit, got %d, %v", tt.raw, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %d, %v; want %d", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestTrim(t *testing.T) {
+	rows, more := Trim([]int{1, 2, 3}, 2)
+	if len(rows) != 2 || !more {
+		t.Fatalf("expected the extra row cut and more reported, got %v %v", rows, more)
+	}
+	rows, more = Trim([]int{1, 2}, 2)
+	if len(rows) != 2 || more {
+		t.Fatalf("expected a full last page to report no more, got %v %v", rows, more)
+	}
+}
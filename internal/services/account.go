@@ -2,9 +2,9 @@ package services
 
 import (
 	"archive/zip"
-	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -13,33 +13,257 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+const (
+	accountExportVersion = 1
+	accountExportNotes   = "token hashes and secret token values are excluded from this export."
 )
 
 type AccountService struct {
-	db DB
+	db           DB
+	branding     config.BrandingConfig
+	proofStorage StorageService
 }
 
 func NewAccountService(db DB) *AccountService {
 	return &AccountService{db: db}
 }
 
-func (s *AccountService) BuildExportZip(ctx context.Context, userID uuid.UUID) ([]byte, error) {
-	var user struct {
-		ID                    uuid.UUID
-		Email                 string
-		Username              string
-		EmailVerified         bool
-		EmailVerifiedAt       *time.Time
-		AIFreeGenerationsUsed int
-		Searchable            bool
-		CreatedAt             time.Time
-		UpdatedAt             time.Time
-		DeletedAt             *time.Time
+// SetBranding names the deployment in the export README.
+func (s *AccountService) SetBranding(branding config.BrandingConfig) {
+	s.branding = branding
+}
+
+// SetProofStorage lets account deletion remove uploaded proof photos.
+func (s *AccountService) SetProofStorage(storage StorageService) {
+	s.proofStorage = storage
+}
+
+// RecordEvent stores an account event. Details defaults to an empty JSON object.
+func (s *AccountService) RecordEvent(ctx context.Context, event models.AccountEvent) error {
+	details := event.Details
+	if len(details) == 0 || !json.Valid(details) {
+		details = json.RawMessage(`{}`)
 	}
 
+	_, err := s.db.Exec(ctx,
+		`INSERT INTO account_events (user_id, event_type, details) VALUES ($1, $2, $3)`,
+		event.UserID, event.EventType, []byte(details),
+	)
+	if err != nil {
+		return fmt.Errorf("record account event: %w", err)
+	}
+	return nil
+}
+
+// IsDataMinimized reports whether the user opted out of analytics-style
+// records. Unknown users are reported as minimized so nothing is written for
+// them.
+func IsDataMinimized(ctx context.Context, db DBConn, userID uuid.UUID) (bool, error) {
+	var minimized bool
+	err := db.QueryRow(ctx, "SELECT data_minimization FROM users WHERE id = $1", userID).Scan(&minimized)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("load data minimization: %w", err)
+	}
+	return minimized, nil
+}
+
+func (s *AccountService) GetPreferences(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error) {
+	prefs := &models.UserPreferences{}
+	err := s.db.QueryRow(ctx,
+		"SELECT data_minimization FROM users WHERE id = $1 AND deleted_at IS NULL",
+		userID,
+	).Scan(&prefs.DataMinimization)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences saves the user's preferences. Turning data minimization on
+// also purges the AI generation logs, share access counters and visit log,
+// and reminder link clicks already stored for the user.
+func (s *AccountService) UpdatePreferences(ctx context.Context, userID uuid.UUID, prefs models.UserPreferences) (*models.UserPreferences, error) {
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("begin preferences update: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	result, err := tx.Exec(ctx,
+		`UPDATE users SET data_minimization = $2, updated_at = NOW()
+		 WHERE id = $1 AND deleted_at IS NULL`,
+		userID, prefs.DataMinimization,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update preferences: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	if prefs.DataMinimization {
+		if _, err := tx.Exec(ctx, "DELETE FROM ai_generation_logs WHERE user_id = $1", userID); err != nil {
+			return nil, fmt.Errorf("purge ai generation logs: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE bingo_card_shares
+			SET access_count = 0,
+			    last_accessed_at = NULL
+			WHERE card_id IN (SELECT id FROM bingo_cards WHERE user_id = $1)
+		`, userID); err != nil {
+			return nil, fmt.Errorf("purge share access analytics: %w", err)
+		}
+		if _, err := tx.Exec(ctx,
+			"DELETE FROM card_share_accesses WHERE card_id IN (SELECT id FROM bingo_cards WHERE user_id = $1)",
+			userID,
+		); err != nil {
+			return nil, fmt.Errorf("purge share access log: %w", err)
+		}
+		if _, err := tx.Exec(ctx,
+			"UPDATE reminder_link_tokens SET clicked_at = NULL WHERE user_id = $1",
+			userID,
+		); err != nil {
+			return nil, fmt.Errorf("purge reminder link clicks: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit preferences update: %w", err)
+	}
+	committed = true
+
+	return &models.UserPreferences{DataMinimization: prefs.DataMinimization}, nil
+}
+
+// exportRecord is one row of an account export table. The same value is
+// written as a CSV record in the ZIP export and marshaled as-is in the JSON
+// export, so both formats always carry the same columns.
+type exportRecord interface {
+	csvRecord() []string
+}
+
+// exportWriter receives the account export one table at a time. writeRows
+// calls add for each row in order.
+type exportWriter interface {
+	writeTable(name string, header []string, writeRows func(add func(exportRecord) error) error) error
+}
+
+// zipExportWriter writes each table to <name>.csv in a ZIP archive.
+type zipExportWriter struct {
+	zipWriter *zip.Writer
+}
+
+func (z zipExportWriter) writeTable(name string, header []string, writeRows func(add func(exportRecord) error) error) error {
+	return writeCSVFile(z.zipWriter, name+".csv", header, func(w *csv.Writer) error {
+		return writeRows(func(record exportRecord) error {
+			return w.Write(record.csvRecord())
+		})
+	})
+}
+
+// jsonExportWriter collects each table as a JSON array of row objects.
+type jsonExportWriter struct {
+	tables map[string][]exportRecord
+}
+
+func (j *jsonExportWriter) writeTable(name string, header []string, writeRows func(add func(exportRecord) error) error) error {
+	// Empty tables marshal as [] rather than null.
+	records := []exportRecord{}
+	if err := writeRows(func(record exportRecord) error {
+		records = append(records, record)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("write %s rows: %w", name, err)
+	}
+	j.tables[name] = records
+	return nil
+}
+
+// AccountExportJSON is the document returned by BuildExportJSON. Tables are
+// keyed by the same names as the CSV files in the ZIP export, without the
+// extension.
+type AccountExportJSON struct {
+	ExportVersion int                       `json:"export_version"`
+	GeneratedAt   time.Time                 `json:"generated_at"`
+	Notes         string                    `json:"notes"`
+	Tables        map[string][]exportRecord `json:"tables"`
+}
+
+// StreamExport writes a ZIP with a README and one CSV file per table of the
+// user's data to w as each table is read, so the archive is never held in
+// memory. The user is loaded before anything is written, so ErrUserNotFound
+// leaves w untouched. Any other error may come after part of the archive has
+// been written; the archive is left without its central directory, which ZIP
+// readers reject.
+func (s *AccountService) StreamExport(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+	user, err := s.loadExportUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	zipWriter := zip.NewWriter(w)
+
+	if err := writeReadme(zipWriter, time.Now().UTC(), s.branding.DisplayName()); err != nil {
+		return err
+	}
+	if err := s.writeExportTables(ctx, zipExportWriter{zipWriter: zipWriter}, user); err != nil {
+		return err
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("close export zip: %w", err)
+	}
+	return nil
+}
+
+// BuildExportJSON returns the same tables as StreamExport as a single JSON
+// document of typed rows, for programmatic re-import.
+func (s *AccountService) BuildExportJSON(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	user, err := s.loadExportUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &jsonExportWriter{tables: map[string][]exportRecord{}}
+	if err := s.writeExportTables(ctx, out, user); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(AccountExportJSON{
+		ExportVersion: accountExportVersion,
+		GeneratedAt:   time.Now().UTC().Truncate(time.Second),
+		Notes:         accountExportNotes,
+		Tables:        out.tables,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal export json: %w", err)
+	}
+	return data, nil
+}
+
+func (s *AccountService) loadExportUser(ctx context.Context, userID uuid.UUID) (*exportUser, error) {
+	var user exportUser
 	err := s.db.QueryRow(ctx,
 		`SELECT id, email, username, email_verified, email_verified_at, ai_free_generations_used,
-		        searchable, created_at, updated_at, deleted_at
+		        searchable, profile_visibility, profile_indexable, data_minimization,
+		        created_at, updated_at, deleted_at
 		 FROM users
 		 WHERE id = $1 AND deleted_at IS NULL`,
 		userID,
@@ -51,6 +275,9 @@ func (s *AccountService) BuildExportZip(ctx context.Context, userID uuid.UUID) (
 		&user.EmailVerifiedAt,
 		&user.AIFreeGenerationsUsed,
 		&user.Searchable,
+		&user.ProfileVisibility,
+		&user.ProfileIndexable,
+		&user.DataMinimization,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.DeletedAt,
@@ -61,15 +288,11 @@ func (s *AccountService) BuildExportZip(ctx context.Context, userID uuid.UUID) (
 	if err != nil {
 		return nil, fmt.Errorf("load user for export: %w", err)
 	}
+	return &user, nil
+}
 
-	var buf bytes.Buffer
-	zipWriter := zip.NewWriter(&buf)
-
-	if err := writeReadme(zipWriter, time.Now().UTC()); err != nil {
-		return nil, err
-	}
-
-	if err := writeCSVFile(zipWriter, "user.csv", []string{
+func (s *AccountService) writeExportTables(ctx context.Context, out exportWriter, user *exportUser) error {
+	if err := out.writeTable("user", []string{
 		"id",
 		"email",
 		"username",
@@ -77,93 +300,53 @@ func (s *AccountService) BuildExportZip(ctx context.Context, userID uuid.UUID) (
 		"email_verified_at",
 		"ai_free_generations_used",
 		"searchable",
+		"profile_visibility",
+		"profile_indexable",
+		"data_minimization",
 		"created_at",
 		"updated_at",
 		"deleted_at",
-	}, func(w *csv.Writer) error {
-		return w.Write([]string{
-			user.ID.String(),
-			sanitizeCSVValue(user.Email),
-			sanitizeCSVValue(user.Username),
-			boolString(user.EmailVerified),
-			formatTime(user.EmailVerifiedAt),
-			fmt.Sprintf("%d", user.AIFreeGenerationsUsed),
-			boolString(user.Searchable),
-			formatTimeValue(user.CreatedAt),
-			formatTimeValue(user.UpdatedAt),
-			formatTime(user.DeletedAt),
-		})
+	}, func(add func(exportRecord) error) error {
+		return add(*user)
 	}); err != nil {
-		return nil, err
-	}
-
-	if err := s.writeCardsCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeItemsCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeFriendshipsCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeBlocksCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeAPITokensCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeNotificationSettingsCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeNotificationsCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeReminderSettingsCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeCardCheckinRemindersCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeGoalRemindersCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeReminderEmailLogCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeReminderImageTokensCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeReminderUnsubscribeTokensCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeEmailVerificationTokensCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writePasswordResetTokensCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeAIGenerationLogsCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeCardSharesCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeFriendInvitesCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-	if err := s.writeSessionsCSV(ctx, zipWriter, userID); err != nil {
-		return nil, err
-	}
-
-	if err := zipWriter.Close(); err != nil {
-		return nil, fmt.Errorf("close export zip: %w", err)
+		return err
+	}
+
+	writers := []func(context.Context, exportWriter, uuid.UUID) error{
+		s.writeCards,
+		s.writeItems,
+		s.writeFriendships,
+		s.writeBlocks,
+		s.writeAPITokens,
+		s.writeNotificationSettings,
+		s.writeNotifications,
+		s.writeReminderSettings,
+		s.writeCardCheckinReminders,
+		s.writeGoalReminders,
+		s.writeReminderEmailLog,
+		s.writeReminderImageTokens,
+		s.writeReminderUnsubscribeTokens,
+		s.writeEmailVerificationTokens,
+		s.writePasswordResetTokens,
+		s.writeAIGenerationLogs,
+		s.writeCardShares,
+		s.writeFriendInvites,
+		s.writeSessions,
+	}
+	for _, write := range writers {
+		// Stop between tables once the client is gone.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := write(ctx, out, user.ID); err != nil {
+			return err
+		}
 	}
-
-	return buf.Bytes(), nil
+	return nil
 }
 
 func (s *AccountService) Delete(ctx context.Context, userID uuid.UUID) error {
-	tx, err := s.db.Begin(ctx)
+	tx, ctx, err := beginTx(ctx, s.db)
 	if err != nil {
 		return fmt.Errorf("begin account delete: %w", err)
 	}
@@ -193,7 +376,9 @@ func (s *AccountService) Delete(ctx context.Context, userID uuid.UUID) error {
 		    password_hash = $4,
 		    email_verified = false,
 		    email_verified_at = NULL,
-		    searchable = false
+		    searchable = false,
+		    profile_visibility = 'off',
+		    profile_indexable = false
 		WHERE id = $1 AND deleted_at IS NULL
 	`, userID, scrubEmail, scrubUsername, scrubPassword)
 	if err != nil {
@@ -240,6 +425,31 @@ func (s *AccountService) Delete(ctx context.Context, userID uuid.UUID) error {
 	if _, err := tx.Exec(ctx, "DELETE FROM reminder_unsubscribe_tokens WHERE user_id = $1", userID); err != nil {
 		return fmt.Errorf("revoke reminder unsubscribe tokens: %w", err)
 	}
+	if _, err := tx.Exec(ctx, "DELETE FROM reminder_snooze_tokens WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("revoke reminder snooze tokens: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM reminder_calendar_feeds WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("revoke reminder calendar feed: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE bingo_items SET proof_url = NULL
+		WHERE card_id IN (SELECT id FROM bingo_cards WHERE user_id = $1)
+		  AND proof_url LIKE $2
+	`, userID, ProofPathPrefix+userID.String()+"/%"); err != nil {
+		return fmt.Errorf("clear proof images: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM bingo_item_comments WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("delete comments: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM webhooks WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("delete webhooks: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM activitypub_followers WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("delete activitypub followers: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM activitypub_activities WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("delete activitypub activities: %w", err)
+	}
 	if _, err := tx.Exec(ctx, "DELETE FROM sessions WHERE user_id = $1", userID); err != nil {
 		return fmt.Errorf("delete sessions: %w", err)
 	}
@@ -248,17 +458,31 @@ func (s *AccountService) Delete(ctx context.Context, userID uuid.UUID) error {
 		return fmt.Errorf("commit account delete: %w", err)
 	}
 	committed = true
+
+	// The photos are unlinked above; removing the files can't be rolled back,
+	// so it happens after the commit and a failure only leaves stray files.
+	if s.proofStorage != nil {
+		if err := s.proofStorage.DeletePrefix(context.WithoutCancel(ctx), userID.String()+"/"); err != nil {
+			logging.Warn("Failed to delete proof images", map[string]interface{}{
+				"error":   err.Error(),
+				"user_id": userID.String(),
+			})
+		}
+	}
 	return nil
 }
 
-func writeReadme(zipWriter *zip.Writer, generatedAt time.Time) error {
+func writeReadme(zipWriter *zip.Writer, generatedAt time.Time, brandName string) error {
 	file, err := zipWriter.Create("README.txt")
 	if err != nil {
 		return fmt.Errorf("create README.txt: %w", err)
 	}
 	content := fmt.Sprintf(
-		"Year of Bingo account export\nexport_version: 1\ngenerated_at: %s\nnotes: token hashes and secret token values are excluded from this export.\n",
+		"%s account export\nexport_version: %d\ngenerated_at: %s\nnotes: %s\n",
+		brandName,
+		accountExportVersion,
 		generatedAt.Format(time.RFC3339),
+		accountExportNotes,
 	)
 	if _, err := io.WriteString(file, content); err != nil {
 		return fmt.Errorf("write README.txt: %w", err)
@@ -327,11 +551,91 @@ func firstNonSpace(value string) rune {
 	return 0
 }
 
-func (s *AccountService) writeCardsCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportUser struct {
+	ID                    uuid.UUID  `json:"id"`
+	Email                 string     `json:"email"`
+	Username              string     `json:"username"`
+	EmailVerified         bool       `json:"email_verified"`
+	EmailVerifiedAt       *time.Time `json:"email_verified_at"`
+	AIFreeGenerationsUsed int        `json:"ai_free_generations_used"`
+	Searchable            bool       `json:"searchable"`
+	ProfileVisibility     string     `json:"profile_visibility"`
+	ProfileIndexable      bool       `json:"profile_indexable"`
+	DataMinimization      bool       `json:"data_minimization"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+	DeletedAt             *time.Time `json:"deleted_at"`
+}
+
+func (u exportUser) csvRecord() []string {
+	return []string{
+		u.ID.String(),
+		sanitizeCSVValue(u.Email),
+		sanitizeCSVValue(u.Username),
+		boolString(u.EmailVerified),
+		formatTime(u.EmailVerifiedAt),
+		fmt.Sprintf("%d", u.AIFreeGenerationsUsed),
+		boolString(u.Searchable),
+		u.ProfileVisibility,
+		boolString(u.ProfileIndexable),
+		boolString(u.DataMinimization),
+		formatTimeValue(u.CreatedAt),
+		formatTimeValue(u.UpdatedAt),
+		formatTime(u.DeletedAt),
+	}
+}
+
+type exportCard struct {
+	ID                uuid.UUID `json:"id"`
+	UserID            uuid.UUID `json:"user_id"`
+	Year              int       `json:"year"`
+	Category          *string   `json:"category"`
+	Title             *string   `json:"title"`
+	GridSize          int       `json:"grid_size"`
+	HeaderText        string    `json:"header_text"`
+	HasFreeSpace      bool      `json:"has_free_space"`
+	FreeSpacePosition *int      `json:"free_space_position"`
+	IsActive          bool      `json:"is_active"`
+	IsFinalized       bool      `json:"is_finalized"`
+	VisibleToFriends  bool      `json:"visible_to_friends"`
+	IsArchived        bool      `json:"is_archived"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	FreeSpaceText     *string   `json:"free_space_text"`
+	StartDate         time.Time `json:"start_date"`
+	EndDate           time.Time `json:"end_date"`
+	RequireProof      bool      `json:"require_proof"`
+}
+
+func (c exportCard) csvRecord() []string {
+	return []string{
+		c.ID.String(),
+		c.UserID.String(),
+		fmt.Sprintf("%d", c.Year),
+		nullableString(c.Category),
+		nullableString(c.Title),
+		fmt.Sprintf("%d", c.GridSize),
+		sanitizeCSVValue(c.HeaderText),
+		boolString(c.HasFreeSpace),
+		nullableInt(c.FreeSpacePosition),
+		boolString(c.IsActive),
+		boolString(c.IsFinalized),
+		boolString(c.VisibleToFriends),
+		boolString(c.IsArchived),
+		formatTimeValue(c.CreatedAt),
+		formatTimeValue(c.UpdatedAt),
+		nullableString(c.FreeSpaceText),
+		c.StartDate.Format("2006-01-02"),
+		c.EndDate.Format("2006-01-02"),
+		boolString(c.RequireProof),
+	}
+}
+
+func (s *AccountService) writeCards(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space,
 		        free_space_position, is_active, is_finalized, visible_to_friends, is_archived,
-		        created_at, updated_at
+		        created_at, updated_at, free_space_text, start_date, end_date, require_proof
 		 FROM bingo_cards
 		 WHERE user_id = $1
 		 ORDER BY created_at`,
@@ -358,63 +662,39 @@ func (s *AccountService) writeCardsCSV(ctx context.Context, zipWriter *zip.Write
 		"is_archived",
 		"created_at",
 		"updated_at",
+		"free_space_text",
+		"start_date",
+		"end_date",
+		"require_proof",
 	}
 
-	return writeCSVFile(zipWriter, "cards.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("cards", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				cardID           uuid.UUID
-				ownerID          uuid.UUID
-				year             int
-				category         *string
-				title            *string
-				gridSize         int
-				headerText       string
-				hasFreeSpace     bool
-				freeSpacePos     *int
-				isActive         bool
-				isFinalized      bool
-				visibleToFriends bool
-				isArchived       bool
-				createdAt        time.Time
-				updatedAt        time.Time
-			)
+			var card exportCard
 			if err := rows.Scan(
-				&cardID,
-				&ownerID,
-				&year,
-				&category,
-				&title,
-				&gridSize,
-				&headerText,
-				&hasFreeSpace,
-				&freeSpacePos,
-				&isActive,
-				&isFinalized,
-				&visibleToFriends,
-				&isArchived,
-				&createdAt,
-				&updatedAt,
+				&card.ID,
+				&card.UserID,
+				&card.Year,
+				&card.Category,
+				&card.Title,
+				&card.GridSize,
+				&card.HeaderText,
+				&card.HasFreeSpace,
+				&card.FreeSpacePosition,
+				&card.IsActive,
+				&card.IsFinalized,
+				&card.VisibleToFriends,
+				&card.IsArchived,
+				&card.CreatedAt,
+				&card.UpdatedAt,
+				&card.FreeSpaceText,
+				&card.StartDate,
+				&card.EndDate,
+				&card.RequireProof,
 			); err != nil {
 				return fmt.Errorf("scan cards: %w", err)
 			}
-			if err := w.Write([]string{
-				cardID.String(),
-				ownerID.String(),
-				fmt.Sprintf("%d", year),
-				nullableString(category),
-				nullableString(title),
-				fmt.Sprintf("%d", gridSize),
-				sanitizeCSVValue(headerText),
-				boolString(hasFreeSpace),
-				nullableInt(freeSpacePos),
-				boolString(isActive),
-				boolString(isFinalized),
-				boolString(visibleToFriends),
-				boolString(isArchived),
-				formatTimeValue(createdAt),
-				formatTimeValue(updatedAt),
-			}); err != nil {
+			if err := add(card); err != nil {
 				return fmt.Errorf("write cards row: %w", err)
 			}
 		}
@@ -425,10 +705,40 @@ func (s *AccountService) writeCardsCSV(ctx context.Context, zipWriter *zip.Write
 	})
 }
 
-func (s *AccountService) writeItemsCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportItem struct {
+	ID          uuid.UUID  `json:"id"`
+	CardID      uuid.UUID  `json:"card_id"`
+	Position    int        `json:"position"`
+	Content     string     `json:"content"`
+	IsCompleted bool       `json:"is_completed"`
+	CompletedAt *time.Time `json:"completed_at"`
+	Notes       *string    `json:"notes"`
+	ProofURL    *string    `json:"proof_url"`
+	CreatedAt   time.Time  `json:"created_at"`
+	IsPrivate   bool       `json:"is_private"`
+	Difficulty  *string    `json:"difficulty"`
+}
+
+func (i exportItem) csvRecord() []string {
+	return []string{
+		i.ID.String(),
+		i.CardID.String(),
+		fmt.Sprintf("%d", i.Position),
+		sanitizeCSVValue(i.Content),
+		boolString(i.IsCompleted),
+		formatTime(i.CompletedAt),
+		nullableString(i.Notes),
+		nullableString(i.ProofURL),
+		formatTimeValue(i.CreatedAt),
+		boolString(i.IsPrivate),
+		nullableString(i.Difficulty),
+	}
+}
+
+func (s *AccountService) writeItems(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT bi.id, bi.card_id, bi.position, bi.content, bi.is_completed, bi.completed_at,
-		        bi.notes, bi.proof_url, bi.created_at
+		        bi.notes, bi.proof_url, bi.created_at, bi.is_private, bi.difficulty
 		 FROM bingo_items bi
 		 JOIN bingo_cards bc ON bi.card_id = bc.id
 		 WHERE bc.user_id = $1
@@ -450,45 +760,29 @@ func (s *AccountService) writeItemsCSV(ctx context.Context, zipWriter *zip.Write
 		"notes",
 		"proof_url",
 		"created_at",
+		"is_private",
+		"difficulty",
 	}
 
-	return writeCSVFile(zipWriter, "items.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("items", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				itemID      uuid.UUID
-				cardID      uuid.UUID
-				position    int
-				content     string
-				isCompleted bool
-				completedAt *time.Time
-				notes       *string
-				proofURL    *string
-				createdAt   time.Time
-			)
+			var item exportItem
 			if err := rows.Scan(
-				&itemID,
-				&cardID,
-				&position,
-				&content,
-				&isCompleted,
-				&completedAt,
-				&notes,
-				&proofURL,
-				&createdAt,
+				&item.ID,
+				&item.CardID,
+				&item.Position,
+				&item.Content,
+				&item.IsCompleted,
+				&item.CompletedAt,
+				&item.Notes,
+				&item.ProofURL,
+				&item.CreatedAt,
+				&item.IsPrivate,
+				&item.Difficulty,
 			); err != nil {
 				return fmt.Errorf("scan items: %w", err)
 			}
-			if err := w.Write([]string{
-				itemID.String(),
-				cardID.String(),
-				fmt.Sprintf("%d", position),
-				sanitizeCSVValue(content),
-				boolString(isCompleted),
-				formatTime(completedAt),
-				nullableString(notes),
-				nullableString(proofURL),
-				formatTimeValue(createdAt),
-			}); err != nil {
+			if err := add(item); err != nil {
 				return fmt.Errorf("write items row: %w", err)
 			}
 		}
@@ -499,7 +793,25 @@ func (s *AccountService) writeItemsCSV(ctx context.Context, zipWriter *zip.Write
 	})
 }
 
-func (s *AccountService) writeFriendshipsCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportFriendship struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	FriendID  uuid.UUID `json:"friend_id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (f exportFriendship) csvRecord() []string {
+	return []string{
+		f.ID.String(),
+		f.UserID.String(),
+		f.FriendID.String(),
+		f.Status,
+		formatTimeValue(f.CreatedAt),
+	}
+}
+
+func (s *AccountService) writeFriendships(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, friend_id, status, created_at
 		 FROM friendships
@@ -520,25 +832,13 @@ func (s *AccountService) writeFriendshipsCSV(ctx context.Context, zipWriter *zip
 		"created_at",
 	}
 
-	return writeCSVFile(zipWriter, "friendships.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("friendships", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				friendshipID uuid.UUID
-				userA        uuid.UUID
-				userB        uuid.UUID
-				status       string
-				createdAt    time.Time
-			)
-			if err := rows.Scan(&friendshipID, &userA, &userB, &status, &createdAt); err != nil {
+			var friendship exportFriendship
+			if err := rows.Scan(&friendship.ID, &friendship.UserID, &friendship.FriendID, &friendship.Status, &friendship.CreatedAt); err != nil {
 				return fmt.Errorf("scan friendships: %w", err)
 			}
-			if err := w.Write([]string{
-				friendshipID.String(),
-				userA.String(),
-				userB.String(),
-				status,
-				formatTimeValue(createdAt),
-			}); err != nil {
+			if err := add(friendship); err != nil {
 				return fmt.Errorf("write friendships row: %w", err)
 			}
 		}
@@ -549,7 +849,21 @@ func (s *AccountService) writeFriendshipsCSV(ctx context.Context, zipWriter *zip
 	})
 }
 
-func (s *AccountService) writeBlocksCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportBlock struct {
+	BlockerID uuid.UUID `json:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (b exportBlock) csvRecord() []string {
+	return []string{
+		b.BlockerID.String(),
+		b.BlockedID.String(),
+		formatTimeValue(b.CreatedAt),
+	}
+}
+
+func (s *AccountService) writeBlocks(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT blocker_id, blocked_id, created_at
 		 FROM user_blocks
@@ -568,21 +882,13 @@ func (s *AccountService) writeBlocksCSV(ctx context.Context, zipWriter *zip.Writ
 		"created_at",
 	}
 
-	return writeCSVFile(zipWriter, "blocks.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("blocks", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				blockerID uuid.UUID
-				blockedID uuid.UUID
-				createdAt time.Time
-			)
-			if err := rows.Scan(&blockerID, &blockedID, &createdAt); err != nil {
+			var block exportBlock
+			if err := rows.Scan(&block.BlockerID, &block.BlockedID, &block.CreatedAt); err != nil {
 				return fmt.Errorf("scan blocks: %w", err)
 			}
-			if err := w.Write([]string{
-				blockerID.String(),
-				blockedID.String(),
-				formatTimeValue(createdAt),
-			}); err != nil {
+			if err := add(block); err != nil {
 				return fmt.Errorf("write blocks row: %w", err)
 			}
 		}
@@ -593,7 +899,31 @@ func (s *AccountService) writeBlocksCSV(ctx context.Context, zipWriter *zip.Writ
 	})
 }
 
-func (s *AccountService) writeAPITokensCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportAPIToken struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scope       string     `json:"scope"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (t exportAPIToken) csvRecord() []string {
+	return []string{
+		t.ID.String(),
+		t.UserID.String(),
+		sanitizeCSVValue(t.Name),
+		t.TokenPrefix,
+		t.Scope,
+		formatTime(t.ExpiresAt),
+		formatTime(t.LastUsedAt),
+		formatTimeValue(t.CreatedAt),
+	}
+}
+
+func (s *AccountService) writeAPITokens(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, name, token_prefix, scope, expires_at, last_used_at, created_at
 		 FROM api_tokens
@@ -617,31 +947,13 @@ func (s *AccountService) writeAPITokensCSV(ctx context.Context, zipWriter *zip.W
 		"created_at",
 	}
 
-	return writeCSVFile(zipWriter, "api_tokens.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("api_tokens", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				tokenID    uuid.UUID
-				ownerID    uuid.UUID
-				name       string
-				prefix     string
-				scope      string
-				expiresAt  *time.Time
-				lastUsedAt *time.Time
-				createdAt  time.Time
-			)
-			if err := rows.Scan(&tokenID, &ownerID, &name, &prefix, &scope, &expiresAt, &lastUsedAt, &createdAt); err != nil {
+			var token exportAPIToken
+			if err := rows.Scan(&token.ID, &token.UserID, &token.Name, &token.TokenPrefix, &token.Scope, &token.ExpiresAt, &token.LastUsedAt, &token.CreatedAt); err != nil {
 				return fmt.Errorf("scan api tokens: %w", err)
 			}
-			if err := w.Write([]string{
-				tokenID.String(),
-				ownerID.String(),
-				sanitizeCSVValue(name),
-				prefix,
-				scope,
-				formatTime(expiresAt),
-				formatTime(lastUsedAt),
-				formatTimeValue(createdAt),
-			}); err != nil {
+			if err := add(token); err != nil {
 				return fmt.Errorf("write api tokens row: %w", err)
 			}
 		}
@@ -652,7 +964,41 @@ func (s *AccountService) writeAPITokensCSV(ctx context.Context, zipWriter *zip.W
 	})
 }
 
-func (s *AccountService) writeNotificationSettingsCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportNotificationSettings struct {
+	UserID                     uuid.UUID `json:"user_id"`
+	InAppEnabled               bool      `json:"in_app_enabled"`
+	InAppFriendRequestReceived bool      `json:"in_app_friend_request_received"`
+	InAppFriendRequestAccepted bool      `json:"in_app_friend_request_accepted"`
+	InAppFriendBingo           bool      `json:"in_app_friend_bingo"`
+	InAppFriendNewCard         bool      `json:"in_app_friend_new_card"`
+	EmailEnabled               bool      `json:"email_enabled"`
+	EmailFriendRequestReceived bool      `json:"email_friend_request_received"`
+	EmailFriendRequestAccepted bool      `json:"email_friend_request_accepted"`
+	EmailFriendBingo           bool      `json:"email_friend_bingo"`
+	EmailFriendNewCard         bool      `json:"email_friend_new_card"`
+	CreatedAt                  time.Time `json:"created_at"`
+	UpdatedAt                  time.Time `json:"updated_at"`
+}
+
+func (n exportNotificationSettings) csvRecord() []string {
+	return []string{
+		n.UserID.String(),
+		boolString(n.InAppEnabled),
+		boolString(n.InAppFriendRequestReceived),
+		boolString(n.InAppFriendRequestAccepted),
+		boolString(n.InAppFriendBingo),
+		boolString(n.InAppFriendNewCard),
+		boolString(n.EmailEnabled),
+		boolString(n.EmailFriendRequestReceived),
+		boolString(n.EmailFriendRequestAccepted),
+		boolString(n.EmailFriendBingo),
+		boolString(n.EmailFriendNewCard),
+		formatTimeValue(n.CreatedAt),
+		formatTimeValue(n.UpdatedAt),
+	}
+}
+
+func (s *AccountService) writeNotificationSettings(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT user_id, in_app_enabled, in_app_friend_request_received, in_app_friend_request_accepted,
 		        in_app_friend_bingo, in_app_friend_new_card, email_enabled, email_friend_request_received,
@@ -682,55 +1028,27 @@ func (s *AccountService) writeNotificationSettingsCSV(ctx context.Context, zipWr
 		"updated_at",
 	}
 
-	return writeCSVFile(zipWriter, "notification_settings.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("notification_settings", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				rowUserID                uuid.UUID
-				inAppEnabled             bool
-				inAppFriendRequestRec    bool
-				inAppFriendRequestAccept bool
-				inAppFriendBingo         bool
-				inAppFriendNewCard       bool
-				emailEnabled             bool
-				emailFriendRequestRec    bool
-				emailFriendRequestAccept bool
-				emailFriendBingo         bool
-				emailFriendNewCard       bool
-				createdAt                time.Time
-				updatedAt                time.Time
-			)
+			var settings exportNotificationSettings
 			if err := rows.Scan(
-				&rowUserID,
-				&inAppEnabled,
-				&inAppFriendRequestRec,
-				&inAppFriendRequestAccept,
-				&inAppFriendBingo,
-				&inAppFriendNewCard,
-				&emailEnabled,
-				&emailFriendRequestRec,
-				&emailFriendRequestAccept,
-				&emailFriendBingo,
-				&emailFriendNewCard,
-				&createdAt,
-				&updatedAt,
+				&settings.UserID,
+				&settings.InAppEnabled,
+				&settings.InAppFriendRequestReceived,
+				&settings.InAppFriendRequestAccepted,
+				&settings.InAppFriendBingo,
+				&settings.InAppFriendNewCard,
+				&settings.EmailEnabled,
+				&settings.EmailFriendRequestReceived,
+				&settings.EmailFriendRequestAccepted,
+				&settings.EmailFriendBingo,
+				&settings.EmailFriendNewCard,
+				&settings.CreatedAt,
+				&settings.UpdatedAt,
 			); err != nil {
 				return fmt.Errorf("scan notification settings: %w", err)
 			}
-			if err := w.Write([]string{
-				rowUserID.String(),
-				boolString(inAppEnabled),
-				boolString(inAppFriendRequestRec),
-				boolString(inAppFriendRequestAccept),
-				boolString(inAppFriendBingo),
-				boolString(inAppFriendNewCard),
-				boolString(emailEnabled),
-				boolString(emailFriendRequestRec),
-				boolString(emailFriendRequestAccept),
-				boolString(emailFriendBingo),
-				boolString(emailFriendNewCard),
-				formatTimeValue(createdAt),
-				formatTimeValue(updatedAt),
-			}); err != nil {
+			if err := add(settings); err != nil {
 				return fmt.Errorf("write notification settings row: %w", err)
 			}
 		}
@@ -741,7 +1059,39 @@ func (s *AccountService) writeNotificationSettingsCSV(ctx context.Context, zipWr
 	})
 }
 
-func (s *AccountService) writeNotificationsCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportNotification struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Type           string     `json:"type"`
+	ActorUserID    *uuid.UUID `json:"actor_user_id"`
+	FriendshipID   *uuid.UUID `json:"friendship_id"`
+	CardID         *uuid.UUID `json:"card_id"`
+	BingoCount     *int       `json:"bingo_count"`
+	InAppDelivered bool       `json:"in_app_delivered"`
+	EmailDelivered bool       `json:"email_delivered"`
+	EmailSentAt    *time.Time `json:"email_sent_at"`
+	ReadAt         *time.Time `json:"read_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func (n exportNotification) csvRecord() []string {
+	return []string{
+		n.ID.String(),
+		n.UserID.String(),
+		n.Type,
+		nullableUUID(n.ActorUserID),
+		nullableUUID(n.FriendshipID),
+		nullableUUID(n.CardID),
+		nullableInt(n.BingoCount),
+		boolString(n.InAppDelivered),
+		boolString(n.EmailDelivered),
+		formatTime(n.EmailSentAt),
+		formatTime(n.ReadAt),
+		formatTimeValue(n.CreatedAt),
+	}
+}
+
+func (s *AccountService) writeNotifications(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, type, actor_user_id, friendship_id, card_id, bingo_count,
 		        in_app_delivered, email_delivered, email_sent_at, read_at, created_at
@@ -770,52 +1120,26 @@ func (s *AccountService) writeNotificationsCSV(ctx context.Context, zipWriter *z
 		"created_at",
 	}
 
-	return writeCSVFile(zipWriter, "notifications.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("notifications", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				notificationID uuid.UUID
-				rowUserID      uuid.UUID
-				nType          string
-				actorUserID    *uuid.UUID
-				friendshipID   *uuid.UUID
-				cardID         *uuid.UUID
-				bingoCount     *int
-				inAppDelivered bool
-				emailDelivered bool
-				emailSentAt    *time.Time
-				readAt         *time.Time
-				createdAt      time.Time
-			)
+			var notification exportNotification
 			if err := rows.Scan(
-				&notificationID,
-				&rowUserID,
-				&nType,
-				&actorUserID,
-				&friendshipID,
-				&cardID,
-				&bingoCount,
-				&inAppDelivered,
-				&emailDelivered,
-				&emailSentAt,
-				&readAt,
-				&createdAt,
+				&notification.ID,
+				&notification.UserID,
+				&notification.Type,
+				&notification.ActorUserID,
+				&notification.FriendshipID,
+				&notification.CardID,
+				&notification.BingoCount,
+				&notification.InAppDelivered,
+				&notification.EmailDelivered,
+				&notification.EmailSentAt,
+				&notification.ReadAt,
+				&notification.CreatedAt,
 			); err != nil {
 				return fmt.Errorf("scan notifications: %w", err)
 			}
-			if err := w.Write([]string{
-				notificationID.String(),
-				rowUserID.String(),
-				nType,
-				nullableUUID(actorUserID),
-				nullableUUID(friendshipID),
-				nullableUUID(cardID),
-				nullableInt(bingoCount),
-				boolString(inAppDelivered),
-				boolString(emailDelivered),
-				formatTime(emailSentAt),
-				formatTime(readAt),
-				formatTimeValue(createdAt),
-			}); err != nil {
+			if err := add(notification); err != nil {
 				return fmt.Errorf("write notifications row: %w", err)
 			}
 		}
@@ -826,7 +1150,25 @@ func (s *AccountService) writeNotificationsCSV(ctx context.Context, zipWriter *z
 	})
 }
 
-func (s *AccountService) writeReminderSettingsCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportReminderSettings struct {
+	UserID        uuid.UUID `json:"user_id"`
+	EmailEnabled  bool      `json:"email_enabled"`
+	DailyEmailCap int       `json:"daily_email_cap"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (r exportReminderSettings) csvRecord() []string {
+	return []string{
+		r.UserID.String(),
+		boolString(r.EmailEnabled),
+		fmt.Sprintf("%d", r.DailyEmailCap),
+		formatTimeValue(r.CreatedAt),
+		formatTimeValue(r.UpdatedAt),
+	}
+}
+
+func (s *AccountService) writeReminderSettings(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT user_id, email_enabled, daily_email_cap, created_at, updated_at
 		 FROM reminder_settings
@@ -846,25 +1188,13 @@ func (s *AccountService) writeReminderSettingsCSV(ctx context.Context, zipWriter
 		"updated_at",
 	}
 
-	return writeCSVFile(zipWriter, "reminder_settings.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("reminder_settings", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				rowUserID     uuid.UUID
-				emailEnabled  bool
-				dailyEmailCap int
-				createdAt     time.Time
-				updatedAt     time.Time
-			)
-			if err := rows.Scan(&rowUserID, &emailEnabled, &dailyEmailCap, &createdAt, &updatedAt); err != nil {
+			var settings exportReminderSettings
+			if err := rows.Scan(&settings.UserID, &settings.EmailEnabled, &settings.DailyEmailCap, &settings.CreatedAt, &settings.UpdatedAt); err != nil {
 				return fmt.Errorf("scan reminder settings: %w", err)
 			}
-			if err := w.Write([]string{
-				rowUserID.String(),
-				boolString(emailEnabled),
-				fmt.Sprintf("%d", dailyEmailCap),
-				formatTimeValue(createdAt),
-				formatTimeValue(updatedAt),
-			}); err != nil {
+			if err := add(settings); err != nil {
 				return fmt.Errorf("write reminder settings row: %w", err)
 			}
 		}
@@ -875,10 +1205,44 @@ func (s *AccountService) writeReminderSettingsCSV(ctx context.Context, zipWriter
 	})
 }
 
-func (s *AccountService) writeCardCheckinRemindersCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportCardCheckinReminder struct {
+	ID                     uuid.UUID       `json:"id"`
+	UserID                 uuid.UUID       `json:"user_id"`
+	CardID                 uuid.UUID       `json:"card_id"`
+	Enabled                bool            `json:"enabled"`
+	Frequency              string          `json:"frequency"`
+	Schedule               json.RawMessage `json:"schedule"`
+	IncludeImage           bool            `json:"include_image"`
+	IncludeRecommendations bool            `json:"include_recommendations"`
+	IncludeMemories        bool            `json:"include_memories"`
+	NextSendAt             *time.Time      `json:"next_send_at"`
+	LastSentAt             *time.Time      `json:"last_sent_at"`
+	CreatedAt              time.Time       `json:"created_at"`
+	UpdatedAt              time.Time       `json:"updated_at"`
+}
+
+func (r exportCardCheckinReminder) csvRecord() []string {
+	return []string{
+		r.ID.String(),
+		r.UserID.String(),
+		r.CardID.String(),
+		boolString(r.Enabled),
+		r.Frequency,
+		string(r.Schedule),
+		boolString(r.IncludeImage),
+		boolString(r.IncludeRecommendations),
+		boolString(r.IncludeMemories),
+		formatTime(r.NextSendAt),
+		formatTime(r.LastSentAt),
+		formatTimeValue(r.CreatedAt),
+		formatTimeValue(r.UpdatedAt),
+	}
+}
+
+func (s *AccountService) writeCardCheckinReminders(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, card_id, enabled, frequency, schedule, include_image, include_recommendations,
-		        next_send_at, last_sent_at, created_at, updated_at
+		        include_memories, next_send_at, last_sent_at, created_at, updated_at
 		 FROM card_checkin_reminders
 		 WHERE user_id = $1
 		 ORDER BY created_at`,
@@ -898,58 +1262,38 @@ func (s *AccountService) writeCardCheckinRemindersCSV(ctx context.Context, zipWr
 		"schedule",
 		"include_image",
 		"include_recommendations",
+		"include_memories",
 		"next_send_at",
 		"last_sent_at",
 		"created_at",
 		"updated_at",
 	}
 
-	return writeCSVFile(zipWriter, "card_checkin_reminders.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("card_checkin_reminders", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
 			var (
-				reminderID             uuid.UUID
-				rowUserID              uuid.UUID
-				cardID                 uuid.UUID
-				enabled                bool
-				frequency              string
-				schedule               []byte
-				includeImage           bool
-				includeRecommendations bool
-				nextSendAt             *time.Time
-				lastSentAt             *time.Time
-				createdAt              time.Time
-				updatedAt              time.Time
+				reminder exportCardCheckinReminder
+				schedule []byte
 			)
 			if err := rows.Scan(
-				&reminderID,
-				&rowUserID,
-				&cardID,
-				&enabled,
-				&frequency,
+				&reminder.ID,
+				&reminder.UserID,
+				&reminder.CardID,
+				&reminder.Enabled,
+				&reminder.Frequency,
 				&schedule,
-				&includeImage,
-				&includeRecommendations,
-				&nextSendAt,
-				&lastSentAt,
-				&createdAt,
-				&updatedAt,
+				&reminder.IncludeImage,
+				&reminder.IncludeRecommendations,
+				&reminder.IncludeMemories,
+				&reminder.NextSendAt,
+				&reminder.LastSentAt,
+				&reminder.CreatedAt,
+				&reminder.UpdatedAt,
 			); err != nil {
 				return fmt.Errorf("scan card checkin reminders: %w", err)
 			}
-			if err := w.Write([]string{
-				reminderID.String(),
-				rowUserID.String(),
-				cardID.String(),
-				boolString(enabled),
-				frequency,
-				string(schedule),
-				boolString(includeImage),
-				boolString(includeRecommendations),
-				formatTime(nextSendAt),
-				formatTime(lastSentAt),
-				formatTimeValue(createdAt),
-				formatTimeValue(updatedAt),
-			}); err != nil {
+			reminder.Schedule = schedule
+			if err := add(reminder); err != nil {
 				return fmt.Errorf("write card checkin reminders row: %w", err)
 			}
 		}
@@ -960,7 +1304,37 @@ func (s *AccountService) writeCardCheckinRemindersCSV(ctx context.Context, zipWr
 	})
 }
 
-func (s *AccountService) writeGoalRemindersCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportGoalReminder struct {
+	ID         uuid.UUID       `json:"id"`
+	UserID     uuid.UUID       `json:"user_id"`
+	CardID     uuid.UUID       `json:"card_id"`
+	ItemID     uuid.UUID       `json:"item_id"`
+	Enabled    bool            `json:"enabled"`
+	Kind       string          `json:"kind"`
+	Schedule   json.RawMessage `json:"schedule"`
+	NextSendAt *time.Time      `json:"next_send_at"`
+	LastSentAt *time.Time      `json:"last_sent_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+func (r exportGoalReminder) csvRecord() []string {
+	return []string{
+		r.ID.String(),
+		r.UserID.String(),
+		r.CardID.String(),
+		r.ItemID.String(),
+		boolString(r.Enabled),
+		r.Kind,
+		string(r.Schedule),
+		formatTime(r.NextSendAt),
+		formatTime(r.LastSentAt),
+		formatTimeValue(r.CreatedAt),
+		formatTimeValue(r.UpdatedAt),
+	}
+}
+
+func (s *AccountService) writeGoalReminders(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, card_id, item_id, enabled, kind, schedule, next_send_at,
 		        last_sent_at, created_at, updated_at
@@ -988,49 +1362,29 @@ func (s *AccountService) writeGoalRemindersCSV(ctx context.Context, zipWriter *z
 		"updated_at",
 	}
 
-	return writeCSVFile(zipWriter, "goal_reminders.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("goal_reminders", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
 			var (
-				reminderID uuid.UUID
-				rowUserID  uuid.UUID
-				cardID     uuid.UUID
-				itemID     uuid.UUID
-				enabled    bool
-				kind       string
-				schedule   []byte
-				nextSendAt *time.Time
-				lastSentAt *time.Time
-				createdAt  time.Time
-				updatedAt  time.Time
+				reminder exportGoalReminder
+				schedule []byte
 			)
 			if err := rows.Scan(
-				&reminderID,
-				&rowUserID,
-				&cardID,
-				&itemID,
-				&enabled,
-				&kind,
+				&reminder.ID,
+				&reminder.UserID,
+				&reminder.CardID,
+				&reminder.ItemID,
+				&reminder.Enabled,
+				&reminder.Kind,
 				&schedule,
-				&nextSendAt,
-				&lastSentAt,
-				&createdAt,
-				&updatedAt,
+				&reminder.NextSendAt,
+				&reminder.LastSentAt,
+				&reminder.CreatedAt,
+				&reminder.UpdatedAt,
 			); err != nil {
 				return fmt.Errorf("scan goal reminders: %w", err)
 			}
-			if err := w.Write([]string{
-				reminderID.String(),
-				rowUserID.String(),
-				cardID.String(),
-				itemID.String(),
-				boolString(enabled),
-				kind,
-				string(schedule),
-				formatTime(nextSendAt),
-				formatTime(lastSentAt),
-				formatTimeValue(createdAt),
-				formatTimeValue(updatedAt),
-			}); err != nil {
+			reminder.Schedule = schedule
+			if err := add(reminder); err != nil {
 				return fmt.Errorf("write goal reminders row: %w", err)
 			}
 		}
@@ -1041,7 +1395,31 @@ func (s *AccountService) writeGoalRemindersCSV(ctx context.Context, zipWriter *z
 	})
 }
 
-func (s *AccountService) writeReminderEmailLogCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportReminderEmailLog struct {
+	ID                uuid.UUID `json:"id"`
+	UserID            uuid.UUID `json:"user_id"`
+	SourceType        string    `json:"source_type"`
+	SourceID          uuid.UUID `json:"source_id"`
+	SentAt            time.Time `json:"sent_at"`
+	SentOn            time.Time `json:"sent_on"`
+	ProviderMessageID *string   `json:"provider_message_id"`
+	Status            string    `json:"status"`
+}
+
+func (l exportReminderEmailLog) csvRecord() []string {
+	return []string{
+		l.ID.String(),
+		l.UserID.String(),
+		l.SourceType,
+		l.SourceID.String(),
+		formatTimeValue(l.SentAt),
+		l.SentOn.UTC().Format("2006-01-02"),
+		nullableString(l.ProviderMessageID),
+		l.Status,
+	}
+}
+
+func (s *AccountService) writeReminderEmailLog(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, source_type, source_id, sent_at, sent_on, provider_message_id, status
 		 FROM reminder_email_log
@@ -1065,40 +1443,22 @@ func (s *AccountService) writeReminderEmailLogCSV(ctx context.Context, zipWriter
 		"status",
 	}
 
-	return writeCSVFile(zipWriter, "reminder_email_log.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("reminder_email_log", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				logID             uuid.UUID
-				rowUserID         uuid.UUID
-				sourceType        string
-				sourceID          uuid.UUID
-				sentAt            time.Time
-				sentOn            time.Time
-				providerMessageID *string
-				status            string
-			)
+			var entry exportReminderEmailLog
 			if err := rows.Scan(
-				&logID,
-				&rowUserID,
-				&sourceType,
-				&sourceID,
-				&sentAt,
-				&sentOn,
-				&providerMessageID,
-				&status,
+				&entry.ID,
+				&entry.UserID,
+				&entry.SourceType,
+				&entry.SourceID,
+				&entry.SentAt,
+				&entry.SentOn,
+				&entry.ProviderMessageID,
+				&entry.Status,
 			); err != nil {
 				return fmt.Errorf("scan reminder email log: %w", err)
 			}
-			if err := w.Write([]string{
-				logID.String(),
-				rowUserID.String(),
-				sourceType,
-				sourceID.String(),
-				formatTimeValue(sentAt),
-				sentOn.UTC().Format("2006-01-02"),
-				nullableString(providerMessageID),
-				status,
-			}); err != nil {
+			if err := add(entry); err != nil {
 				return fmt.Errorf("write reminder email log row: %w", err)
 			}
 		}
@@ -1109,7 +1469,29 @@ func (s *AccountService) writeReminderEmailLogCSV(ctx context.Context, zipWriter
 	})
 }
 
-func (s *AccountService) writeReminderImageTokensCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportReminderImageToken struct {
+	UserID          uuid.UUID  `json:"user_id"`
+	CardID          uuid.UUID  `json:"card_id"`
+	ShowCompletions bool       `json:"show_completions"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastAccessedAt  *time.Time `json:"last_accessed_at"`
+	AccessCount     int        `json:"access_count"`
+}
+
+func (t exportReminderImageToken) csvRecord() []string {
+	return []string{
+		t.UserID.String(),
+		t.CardID.String(),
+		boolString(t.ShowCompletions),
+		formatTimeValue(t.ExpiresAt),
+		formatTimeValue(t.CreatedAt),
+		formatTime(t.LastAccessedAt),
+		fmt.Sprintf("%d", t.AccessCount),
+	}
+}
+
+func (s *AccountService) writeReminderImageTokens(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT user_id, card_id, show_completions, expires_at, created_at, last_accessed_at, access_count
 		 FROM reminder_image_tokens
@@ -1132,37 +1514,21 @@ func (s *AccountService) writeReminderImageTokensCSV(ctx context.Context, zipWri
 		"access_count",
 	}
 
-	return writeCSVFile(zipWriter, "reminder_image_tokens.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("reminder_image_tokens", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				rowUserID      uuid.UUID
-				cardID         uuid.UUID
-				showCompletion bool
-				expiresAt      time.Time
-				createdAt      time.Time
-				lastAccessedAt *time.Time
-				accessCount    int
-			)
+			var token exportReminderImageToken
 			if err := rows.Scan(
-				&rowUserID,
-				&cardID,
-				&showCompletion,
-				&expiresAt,
-				&createdAt,
-				&lastAccessedAt,
-				&accessCount,
+				&token.UserID,
+				&token.CardID,
+				&token.ShowCompletions,
+				&token.ExpiresAt,
+				&token.CreatedAt,
+				&token.LastAccessedAt,
+				&token.AccessCount,
 			); err != nil {
 				return fmt.Errorf("scan reminder image tokens: %w", err)
 			}
-			if err := w.Write([]string{
-				rowUserID.String(),
-				cardID.String(),
-				boolString(showCompletion),
-				formatTimeValue(expiresAt),
-				formatTimeValue(createdAt),
-				formatTime(lastAccessedAt),
-				fmt.Sprintf("%d", accessCount),
-			}); err != nil {
+			if err := add(token); err != nil {
 				return fmt.Errorf("write reminder image tokens row: %w", err)
 			}
 		}
@@ -1173,7 +1539,23 @@ func (s *AccountService) writeReminderImageTokensCSV(ctx context.Context, zipWri
 	})
 }
 
-func (s *AccountService) writeReminderUnsubscribeTokensCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportReminderUnsubscribeToken struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+func (t exportReminderUnsubscribeToken) csvRecord() []string {
+	return []string{
+		t.UserID.String(),
+		formatTimeValue(t.ExpiresAt),
+		formatTimeValue(t.CreatedAt),
+		formatTime(t.UsedAt),
+	}
+}
+
+func (s *AccountService) writeReminderUnsubscribeTokens(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT user_id, expires_at, created_at, used_at
 		 FROM reminder_unsubscribe_tokens
@@ -1193,23 +1575,13 @@ func (s *AccountService) writeReminderUnsubscribeTokensCSV(ctx context.Context,
 		"used_at",
 	}
 
-	return writeCSVFile(zipWriter, "reminder_unsubscribe_tokens.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("reminder_unsubscribe_tokens", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				rowUserID uuid.UUID
-				expiresAt time.Time
-				createdAt time.Time
-				usedAt    *time.Time
-			)
-			if err := rows.Scan(&rowUserID, &expiresAt, &createdAt, &usedAt); err != nil {
+			var token exportReminderUnsubscribeToken
+			if err := rows.Scan(&token.UserID, &token.ExpiresAt, &token.CreatedAt, &token.UsedAt); err != nil {
 				return fmt.Errorf("scan reminder unsubscribe tokens: %w", err)
 			}
-			if err := w.Write([]string{
-				rowUserID.String(),
-				formatTimeValue(expiresAt),
-				formatTimeValue(createdAt),
-				formatTime(usedAt),
-			}); err != nil {
+			if err := add(token); err != nil {
 				return fmt.Errorf("write reminder unsubscribe tokens row: %w", err)
 			}
 		}
@@ -1220,7 +1592,23 @@ func (s *AccountService) writeReminderUnsubscribeTokensCSV(ctx context.Context,
 	})
 }
 
-func (s *AccountService) writeEmailVerificationTokensCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportEmailVerificationToken struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (t exportEmailVerificationToken) csvRecord() []string {
+	return []string{
+		t.ID.String(),
+		t.UserID.String(),
+		formatTimeValue(t.ExpiresAt),
+		formatTimeValue(t.CreatedAt),
+	}
+}
+
+func (s *AccountService) writeEmailVerificationTokens(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, expires_at, created_at
 		 FROM email_verification_tokens
@@ -1240,23 +1628,13 @@ func (s *AccountService) writeEmailVerificationTokensCSV(ctx context.Context, zi
 		"created_at",
 	}
 
-	return writeCSVFile(zipWriter, "email_verification_tokens.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("email_verification_tokens", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				tokenID   uuid.UUID
-				rowUserID uuid.UUID
-				expiresAt time.Time
-				createdAt time.Time
-			)
-			if err := rows.Scan(&tokenID, &rowUserID, &expiresAt, &createdAt); err != nil {
+			var token exportEmailVerificationToken
+			if err := rows.Scan(&token.ID, &token.UserID, &token.ExpiresAt, &token.CreatedAt); err != nil {
 				return fmt.Errorf("scan email verification tokens: %w", err)
 			}
-			if err := w.Write([]string{
-				tokenID.String(),
-				rowUserID.String(),
-				formatTimeValue(expiresAt),
-				formatTimeValue(createdAt),
-			}); err != nil {
+			if err := add(token); err != nil {
 				return fmt.Errorf("write email verification tokens row: %w", err)
 			}
 		}
@@ -1267,7 +1645,25 @@ func (s *AccountService) writeEmailVerificationTokensCSV(ctx context.Context, zi
 	})
 }
 
-func (s *AccountService) writePasswordResetTokensCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportPasswordResetToken struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (t exportPasswordResetToken) csvRecord() []string {
+	return []string{
+		t.ID.String(),
+		t.UserID.String(),
+		formatTimeValue(t.ExpiresAt),
+		formatTime(t.UsedAt),
+		formatTimeValue(t.CreatedAt),
+	}
+}
+
+func (s *AccountService) writePasswordResetTokens(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, expires_at, used_at, created_at
 		 FROM password_reset_tokens
@@ -1288,25 +1684,13 @@ func (s *AccountService) writePasswordResetTokensCSV(ctx context.Context, zipWri
 		"created_at",
 	}
 
-	return writeCSVFile(zipWriter, "password_reset_tokens.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("password_reset_tokens", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				tokenID   uuid.UUID
-				rowUserID uuid.UUID
-				expiresAt time.Time
-				usedAt    *time.Time
-				createdAt time.Time
-			)
-			if err := rows.Scan(&tokenID, &rowUserID, &expiresAt, &usedAt, &createdAt); err != nil {
+			var token exportPasswordResetToken
+			if err := rows.Scan(&token.ID, &token.UserID, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt); err != nil {
 				return fmt.Errorf("scan password reset tokens: %w", err)
 			}
-			if err := w.Write([]string{
-				tokenID.String(),
-				rowUserID.String(),
-				formatTimeValue(expiresAt),
-				formatTime(usedAt),
-				formatTimeValue(createdAt),
-			}); err != nil {
+			if err := add(token); err != nil {
 				return fmt.Errorf("write password reset tokens row: %w", err)
 			}
 		}
@@ -1317,7 +1701,31 @@ func (s *AccountService) writePasswordResetTokensCSV(ctx context.Context, zipWri
 	})
 }
 
-func (s *AccountService) writeAIGenerationLogsCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportAIGenerationLog struct {
+	ID           uuid.UUID `json:"id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Model        string    `json:"model"`
+	TokensInput  int       `json:"tokens_input"`
+	TokensOutput int       `json:"tokens_output"`
+	DurationMS   int       `json:"duration_ms"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (l exportAIGenerationLog) csvRecord() []string {
+	return []string{
+		l.ID.String(),
+		l.UserID.String(),
+		l.Model,
+		fmt.Sprintf("%d", l.TokensInput),
+		fmt.Sprintf("%d", l.TokensOutput),
+		fmt.Sprintf("%d", l.DurationMS),
+		l.Status,
+		formatTimeValue(l.CreatedAt),
+	}
+}
+
+func (s *AccountService) writeAIGenerationLogs(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, model, tokens_input, tokens_output, duration_ms, status, created_at
 		 FROM ai_generation_logs
@@ -1341,31 +1749,13 @@ func (s *AccountService) writeAIGenerationLogsCSV(ctx context.Context, zipWriter
 		"created_at",
 	}
 
-	return writeCSVFile(zipWriter, "ai_generation_logs.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("ai_generation_logs", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				logID       uuid.UUID
-				rowUserID   uuid.UUID
-				model       string
-				tokensInput int
-				tokensOut   int
-				durationMs  int
-				status      string
-				createdAt   time.Time
-			)
-			if err := rows.Scan(&logID, &rowUserID, &model, &tokensInput, &tokensOut, &durationMs, &status, &createdAt); err != nil {
+			var entry exportAIGenerationLog
+			if err := rows.Scan(&entry.ID, &entry.UserID, &entry.Model, &entry.TokensInput, &entry.TokensOutput, &entry.DurationMS, &entry.Status, &entry.CreatedAt); err != nil {
 				return fmt.Errorf("scan ai generation logs: %w", err)
 			}
-			if err := w.Write([]string{
-				logID.String(),
-				rowUserID.String(),
-				model,
-				fmt.Sprintf("%d", tokensInput),
-				fmt.Sprintf("%d", tokensOut),
-				fmt.Sprintf("%d", durationMs),
-				status,
-				formatTimeValue(createdAt),
-			}); err != nil {
+			if err := add(entry); err != nil {
 				return fmt.Errorf("write ai generation logs row: %w", err)
 			}
 		}
@@ -1376,7 +1766,25 @@ func (s *AccountService) writeAIGenerationLogsCSV(ctx context.Context, zipWriter
 	})
 }
 
-func (s *AccountService) writeCardSharesCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportCardShare struct {
+	CardID         uuid.UUID  `json:"card_id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+	AccessCount    int        `json:"access_count"`
+}
+
+func (c exportCardShare) csvRecord() []string {
+	return []string{
+		c.CardID.String(),
+		formatTimeValue(c.CreatedAt),
+		formatTime(c.ExpiresAt),
+		formatTime(c.LastAccessedAt),
+		fmt.Sprintf("%d", c.AccessCount),
+	}
+}
+
+func (s *AccountService) writeCardShares(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT s.card_id, s.created_at, s.expires_at, s.last_accessed_at, s.access_count
 		 FROM bingo_card_shares s
@@ -1398,25 +1806,13 @@ func (s *AccountService) writeCardSharesCSV(ctx context.Context, zipWriter *zip.
 		"access_count",
 	}
 
-	return writeCSVFile(zipWriter, "card_shares.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("card_shares", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				cardID         uuid.UUID
-				createdAt      time.Time
-				expiresAt      *time.Time
-				lastAccessedAt *time.Time
-				accessCount    int
-			)
-			if err := rows.Scan(&cardID, &createdAt, &expiresAt, &lastAccessedAt, &accessCount); err != nil {
+			var share exportCardShare
+			if err := rows.Scan(&share.CardID, &share.CreatedAt, &share.ExpiresAt, &share.LastAccessedAt, &share.AccessCount); err != nil {
 				return fmt.Errorf("scan card shares: %w", err)
 			}
-			if err := w.Write([]string{
-				cardID.String(),
-				formatTimeValue(createdAt),
-				formatTime(expiresAt),
-				formatTime(lastAccessedAt),
-				fmt.Sprintf("%d", accessCount),
-			}); err != nil {
+			if err := add(share); err != nil {
 				return fmt.Errorf("write card shares row: %w", err)
 			}
 		}
@@ -1427,7 +1823,29 @@ func (s *AccountService) writeCardSharesCSV(ctx context.Context, zipWriter *zip.
 	})
 }
 
-func (s *AccountService) writeFriendInvitesCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportFriendInvite struct {
+	ID               uuid.UUID  `json:"id"`
+	InviterUserID    uuid.UUID  `json:"inviter_user_id"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	AcceptedByUserID *uuid.UUID `json:"accepted_by_user_id"`
+	AcceptedAt       *time.Time `json:"accepted_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+func (i exportFriendInvite) csvRecord() []string {
+	return []string{
+		i.ID.String(),
+		i.InviterUserID.String(),
+		formatTime(i.ExpiresAt),
+		formatTime(i.RevokedAt),
+		nullableUUID(i.AcceptedByUserID),
+		formatTime(i.AcceptedAt),
+		formatTimeValue(i.CreatedAt),
+	}
+}
+
+func (s *AccountService) writeFriendInvites(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, inviter_user_id, expires_at, revoked_at, accepted_by_user_id, accepted_at, created_at
 		 FROM friend_invites
@@ -1450,29 +1868,13 @@ func (s *AccountService) writeFriendInvitesCSV(ctx context.Context, zipWriter *z
 		"created_at",
 	}
 
-	return writeCSVFile(zipWriter, "friend_invites.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("friend_invites", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				inviteID         uuid.UUID
-				inviterID        uuid.UUID
-				expiresAt        *time.Time
-				revokedAt        *time.Time
-				acceptedByUserID *uuid.UUID
-				acceptedAt       *time.Time
-				createdAt        time.Time
-			)
-			if err := rows.Scan(&inviteID, &inviterID, &expiresAt, &revokedAt, &acceptedByUserID, &acceptedAt, &createdAt); err != nil {
+			var invite exportFriendInvite
+			if err := rows.Scan(&invite.ID, &invite.InviterUserID, &invite.ExpiresAt, &invite.RevokedAt, &invite.AcceptedByUserID, &invite.AcceptedAt, &invite.CreatedAt); err != nil {
 				return fmt.Errorf("scan friend invites: %w", err)
 			}
-			if err := w.Write([]string{
-				inviteID.String(),
-				inviterID.String(),
-				formatTime(expiresAt),
-				formatTime(revokedAt),
-				nullableUUID(acceptedByUserID),
-				formatTime(acceptedAt),
-				formatTimeValue(createdAt),
-			}); err != nil {
+			if err := add(invite); err != nil {
 				return fmt.Errorf("write friend invites row: %w", err)
 			}
 		}
@@ -1483,9 +1885,31 @@ func (s *AccountService) writeFriendInvitesCSV(ctx context.Context, zipWriter *z
 	})
 }
 
-func (s *AccountService) writeSessionsCSV(ctx context.Context, zipWriter *zip.Writer, userID uuid.UUID) error {
+type exportSession struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	UserAgent  *string    `json:"user_agent"`
+	IPAddress  *string    `json:"ip_address"`
+}
+
+func (s exportSession) csvRecord() []string {
+	return []string{
+		s.ID.String(),
+		s.UserID.String(),
+		formatTimeValue(s.ExpiresAt),
+		formatTimeValue(s.CreatedAt),
+		formatTime(s.LastSeenAt),
+		sanitizeCSVValue(nullableString(s.UserAgent)),
+		nullableString(s.IPAddress),
+	}
+}
+
+func (s *AccountService) writeSessions(ctx context.Context, out exportWriter, userID uuid.UUID) error {
 	rows, err := s.db.Query(ctx,
-		`SELECT id, user_id, expires_at, created_at
+		`SELECT id, user_id, expires_at, created_at, last_seen_at, user_agent, ip_address
 		 FROM sessions
 		 WHERE user_id = $1
 		 ORDER BY created_at DESC`,
@@ -1501,25 +1925,18 @@ func (s *AccountService) writeSessionsCSV(ctx context.Context, zipWriter *zip.Wr
 		"user_id",
 		"expires_at",
 		"created_at",
+		"last_seen_at",
+		"user_agent",
+		"ip_address",
 	}
 
-	return writeCSVFile(zipWriter, "sessions.csv", header, func(w *csv.Writer) error {
+	return out.writeTable("sessions", header, func(add func(exportRecord) error) error {
 		for rows.Next() {
-			var (
-				sessionID uuid.UUID
-				rowUserID uuid.UUID
-				expiresAt time.Time
-				createdAt time.Time
-			)
-			if err := rows.Scan(&sessionID, &rowUserID, &expiresAt, &createdAt); err != nil {
+			var session exportSession
+			if err := rows.Scan(&session.ID, &session.UserID, &session.ExpiresAt, &session.CreatedAt, &session.LastSeenAt, &session.UserAgent, &session.IPAddress); err != nil {
 				return fmt.Errorf("scan sessions: %w", err)
 			}
-			if err := w.Write([]string{
-				sessionID.String(),
-				rowUserID.String(),
-				formatTimeValue(expiresAt),
-				formatTimeValue(createdAt),
-			}); err != nil {
+			if err := add(session); err != nil {
 				return fmt.Errorf("write sessions row: %w", err)
 			}
 		}
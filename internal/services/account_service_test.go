@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"strings"
@@ -12,30 +13,21 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
 )
 
-func TestAccountService_BuildExportZip_CreatesFiles(t *testing.T) {
-	userID := uuid.New()
-	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
-	verifiedAt := now
+func TestAccountService_StreamExport_CreatesFiles(t *testing.T) {
+	user := testutil.NewTestUser()
+	userID := user.ID
 
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			if !strings.Contains(sql, "FROM users") {
 				return fakeRow{scanFunc: func(dest ...any) error { return errors.New("unexpected query") }}
 			}
-			return rowFromValues(
-				userID,
-				"test@example.com",
-				"testuser",
-				true,
-				&verifiedAt,
-				2,
-				true,
-				now,
-				now,
-				nil,
-			)
+			return rowFromValues(user.ExportRow()...)
 		},
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
 			return &fakeRows{}, nil
@@ -43,12 +35,12 @@ func TestAccountService_BuildExportZip_CreatesFiles(t *testing.T) {
 	}
 
 	service := NewAccountService(db)
-	data, err := service.BuildExportZip(context.Background(), userID)
-	if err != nil {
+	var buf bytes.Buffer
+	if err := service.StreamExport(context.Background(), userID, &buf); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
 	if err != nil {
 		t.Fatalf("failed to read zip: %v", err)
 	}
@@ -115,6 +107,9 @@ func TestAccountService_BuildExportZip_CreatesFiles(t *testing.T) {
 	if !strings.Contains(userCSV, "test@example.com") {
 		t.Fatalf("expected user email in user.csv, got %q", userCSV)
 	}
+	if !strings.Contains(userCSV, "data_minimization") {
+		t.Fatalf("expected data_minimization column in user.csv, got %q", userCSV)
+	}
 	if strings.Contains(apiTokensHeader, "token_hash") {
 		t.Fatalf("expected api_tokens.csv to omit token_hash, got %q", apiTokensHeader)
 	}
@@ -126,7 +121,7 @@ func TestAccountService_BuildExportZip_CreatesFiles(t *testing.T) {
 	}
 }
 
-func TestAccountService_BuildExportZip_UserNotFound(t *testing.T) {
+func TestAccountService_StreamExport_UserNotFound(t *testing.T) {
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
@@ -134,18 +129,174 @@ func TestAccountService_BuildExportZip_UserNotFound(t *testing.T) {
 	}
 
 	service := NewAccountService(db)
-	_, err := service.BuildExportZip(context.Background(), uuid.New())
+	var buf bytes.Buffer
+	err := service.StreamExport(context.Background(), uuid.New(), &buf)
 	if !errors.Is(err, ErrUserNotFound) {
 		t.Fatalf("expected ErrUserNotFound, got %v", err)
 	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing written for a missing user, got %d bytes", buf.Len())
+	}
+}
+
+func TestAccountService_StreamExport_ErrorMidStream(t *testing.T) {
+	user := testutil.NewTestUser()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(user.ExportRow()...)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if strings.Contains(sql, "FROM notifications") {
+				return nil, errors.New("query failed")
+			}
+			return &fakeRows{}, nil
+		},
+	}
+
+	service := NewAccountService(db)
+	var buf bytes.Buffer
+	if err := service.StreamExport(context.Background(), user.ID, &buf); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err == nil {
+		t.Fatal("expected a truncated export to be an invalid zip")
+	}
+}
+
+func TestAccountService_StreamExport_StopsWhenCanceled(t *testing.T) {
+	user := testutil.NewTestUser()
+	ctx, cancel := context.WithCancel(context.Background())
+	var tables []string
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(user.ExportRow()...)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			tables = append(tables, sql)
+			cancel()
+			return &fakeRows{}, nil
+		},
+	}
+
+	service := NewAccountService(db)
+	var buf bytes.Buffer
+	if err := service.StreamExport(ctx, user.ID, &buf); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("expected the export to stop after the first table, got %d queries", len(tables))
+	}
+}
+
+func TestAccountService_BuildExportJSON(t *testing.T) {
+	user := testutil.NewTestUser()
+	card := testutil.NewTestCard(testutil.WithCardOwner(user.ID), testutil.WithItems(2))
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(user.ExportRow()...)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			switch {
+			case strings.Contains(sql, "FROM bingo_cards"):
+				return &fakeRows{rows: [][]any{card.Row()}}, nil
+			case strings.Contains(sql, "FROM bingo_items"):
+				return &fakeRows{rows: card.ItemRows()}, nil
+			default:
+				return &fakeRows{}, nil
+			}
+		},
+	}
+
+	data, err := NewAccountService(db).BuildExportJSON(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "token_hash") {
+		t.Fatal("expected token hashes to be excluded")
+	}
+
+	var export struct {
+		ExportVersion int                         `json:"export_version"`
+		GeneratedAt   time.Time                   `json:"generated_at"`
+		Tables        map[string][]map[string]any `json:"tables"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	if export.ExportVersion != 1 || export.GeneratedAt.IsZero() {
+		t.Fatalf("expected export metadata, got version %d at %v", export.ExportVersion, export.GeneratedAt)
+	}
+	if len(export.Tables) != 20 {
+		t.Fatalf("expected 20 tables, got %d", len(export.Tables))
+	}
+	if got := export.Tables["user"][0]["email"]; got != "test@example.com" {
+		t.Fatalf("expected user email, got %v", got)
+	}
+	if len(export.Tables["items"]) != 2 || export.Tables["items"][0]["position"] != float64(0) {
+		t.Fatalf("expected typed item rows, got %v", export.Tables["items"])
+	}
+	if export.Tables["cards"][0]["free_space_position"] != float64(12) || export.Tables["cards"][0]["title"] != nil {
+		t.Fatalf("expected typed card row, got %v", export.Tables["cards"][0])
+	}
+	if sessions, ok := export.Tables["sessions"]; !ok || sessions == nil {
+		t.Fatal("expected empty tables as empty arrays")
+	}
+}
+
+func TestAccountService_RecordEvent(t *testing.T) {
+	userID := uuid.New()
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "INSERT INTO account_events") {
+				t.Fatalf("unexpected sql: %s", sql)
+			}
+			gotArgs = args
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	svc := NewAccountService(db)
+	err := svc.RecordEvent(context.Background(), models.AccountEvent{
+		UserID:    userID,
+		EventType: models.AccountEventDataExport,
+		Details:   json.RawMessage(`{"size_bytes":42}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotArgs[0] != userID || gotArgs[1] != models.AccountEventDataExport || string(gotArgs[2].([]byte)) != `{"size_bytes":42}` {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+
+	// Missing details are stored as an empty object.
+	if err := svc.RecordEvent(context.Background(), models.AccountEvent{UserID: userID, EventType: "x"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(gotArgs[2].([]byte)) != "{}" {
+		t.Fatalf("expected empty details object, got %s", gotArgs[2])
+	}
+}
+
+func TestAccountService_RecordEvent_Error(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{}, errors.New("boom")
+		},
+	}
+
+	svc := NewAccountService(db)
+	if err := svc.RecordEvent(context.Background(), models.AccountEvent{UserID: uuid.New(), EventType: "x"}); err == nil {
+		t.Fatal("expected error")
+	}
 }
 
 func TestAccountService_Delete_Success(t *testing.T) {
-	var execSQL []string
+	var sqlLog testutil.SQLLog
 	var committed bool
 	tx := &fakeTx{
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			execSQL = append(execSQL, sql)
+			sqlLog.Record(sql, args...)
 			if strings.Contains(sql, "UPDATE users") {
 				return fakeCommandTag{rowsAffected: 1}, nil
 			}
@@ -178,15 +329,10 @@ func TestAccountService_Delete_Success(t *testing.T) {
 	if !committed {
 		t.Fatal("expected transaction commit")
 	}
-	if !containsSQL(execSQL, "DELETE FROM email_verification_tokens") {
-		t.Fatal("expected email verification tokens to be revoked")
-	}
-	if !containsSQL(execSQL, "DELETE FROM password_reset_tokens") {
-		t.Fatal("expected password reset tokens to be revoked")
-	}
-	if !containsSQL(execSQL, "DELETE FROM magic_link_tokens") {
-		t.Fatal("expected magic link tokens to be revoked")
-	}
+	// The account is soft-deleted before its outstanding login tokens are revoked.
+	sqlLog.AssertOrder(t, "UPDATE users", "DELETE FROM email_verification_tokens")
+	sqlLog.AssertCalled(t, "DELETE FROM password_reset_tokens")
+	sqlLog.AssertCalled(t, "DELETE FROM magic_link_tokens")
 }
 
 func TestAccountService_Delete_Idempotent(t *testing.T) {
@@ -252,22 +398,134 @@ func TestAccountService_Delete_NotFound(t *testing.T) {
 	}
 }
 
+func TestAccountService_UpdatePreferences_PurgesWhenEnabled(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		var sqlLog testutil.SQLLog
+		committed := false
+		tx := &fakeTx{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+				sqlLog.Record(sql, args...)
+				return fakeCommandTag{rowsAffected: 1}, nil
+			},
+			CommitFunc: func(ctx context.Context) error {
+				committed = true
+				return nil
+			},
+		}
+		db := &fakeDB{
+			BeginFunc: func(ctx context.Context) (Tx, error) {
+				return tx, nil
+			},
+		}
+
+		prefs, err := NewAccountService(db).UpdatePreferences(context.Background(), uuid.New(), models.UserPreferences{DataMinimization: enabled})
+		if err != nil {
+			t.Fatalf("enabled=%v: unexpected error: %v", enabled, err)
+		}
+		if prefs.DataMinimization != enabled || !committed {
+			t.Fatalf("enabled=%v: unexpected result %+v, committed=%v", enabled, prefs, committed)
+		}
+		purgedLogs := len(sqlLog.Matching("DELETE FROM ai_generation_logs")) > 0
+		resetShares := len(sqlLog.Matching("UPDATE bingo_card_shares")) > 0
+		purgedVisits := len(sqlLog.Matching("DELETE FROM card_share_accesses")) > 0
+		resetClicks := len(sqlLog.Matching("UPDATE reminder_link_tokens SET clicked_at = NULL")) > 0
+		if purgedLogs != enabled || resetShares != enabled || purgedVisits != enabled || resetClicks != enabled {
+			t.Fatalf("enabled=%v: purge logs=%v shares=%v visits=%v clicks=%v", enabled, purgedLogs, resetShares, purgedVisits, resetClicks)
+		}
+	}
+}
+
+func TestAccountService_UpdatePreferences_NotFound(t *testing.T) {
+	tx := &fakeTx{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "DELETE FROM ai_generation_logs") {
+				t.Fatal("expected no purge for unknown user")
+			}
+			return fakeCommandTag{rowsAffected: 0}, nil
+		},
+	}
+	db := &fakeDB{
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			return tx, nil
+		},
+	}
+
+	_, err := NewAccountService(db).UpdatePreferences(context.Background(), uuid.New(), models.UserPreferences{DataMinimization: true})
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestAccountService_GetPreferences(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(true)
+		},
+	}
+	prefs, err := NewAccountService(db).GetPreferences(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !prefs.DataMinimization {
+		t.Fatal("expected data minimization to be enabled")
+	}
+
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+	}
+	if _, err := NewAccountService(db).GetPreferences(context.Background(), uuid.New()); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestIsDataMinimized(t *testing.T) {
+	tests := []struct {
+		name string
+		row  Row
+		want bool
+	}{
+		{name: "enabled", row: rowFromValues(true), want: true},
+		{name: "disabled", row: rowFromValues(false), want: false},
+		{name: "unknown user", row: fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					return tt.row
+				},
+			}
+			got, err := IsDataMinimized(context.Background(), db, uuid.New())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestAccountService_Writers_WriteRowsCoverNullables(t *testing.T) {
 	userID := uuid.New()
 	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
 	title := "Title,\nWith Newline"
 	category := "Category"
-	freePos := 12
 	notes := "note"
 	proofURL := "https://example.com"
+	difficulty := models.DifficultyHard
+	card := testutil.NewTestCard(testutil.WithCardOwner(userID), testutil.WithItems(1), testutil.WithCompletedItems(1))
+	card.Title, card.Category = &title, &category
+	card.Items[0].Notes, card.Items[0].ProofURL = &notes, &proofURL
+	card.Items[0].IsPrivate, card.Items[0].Difficulty = true, &difficulty
+	checkin := testutil.NewTestReminder(card, testutil.WithNextSendAt(now.Add(48*time.Hour)))
+	goalReminder := testutil.NewTestGoalReminder(card, card.Items[0], now.Add(72*time.Hour))
 	actorID := uuid.New()
 	friendshipID := uuid.New()
-	cardID := uuid.New()
+	cardID := card.ID
 	bingoCount := 2
 	expiresAt := now.Add(24 * time.Hour)
-	checkinID := uuid.New()
-	goalReminderID := uuid.New()
-	itemID := uuid.New()
+	checkinID := checkin.ID
 	cardShareCreated := now.Add(-2 * time.Hour)
 	emailLogID := uuid.New()
 	providerMessageID := "provider-msg"
@@ -279,15 +537,9 @@ func TestAccountService_Writers_WriteRowsCoverNullables(t *testing.T) {
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
 			switch {
 			case strings.Contains(sql, "FROM bingo_cards"):
-				return &fakeRows{rows: [][]any{{
-					cardID, userID, 2025, &category, &title, 5, "BINGO", true, &freePos, true, true, true, false, now, now,
-				}}}, nil
+				return &fakeRows{rows: [][]any{card.Row()}}, nil
 			case strings.Contains(sql, "FROM bingo_items"):
-				itemID := uuid.New()
-				completedAt := now.Add(-time.Hour)
-				return &fakeRows{rows: [][]any{{
-					itemID, cardID, 3, "Do something", true, &completedAt, &notes, &proofURL, now,
-				}}}, nil
+				return &fakeRows{rows: card.ItemRows()}, nil
 			case strings.Contains(sql, "FROM friendships"):
 				friendID := uuid.New()
 				return &fakeRows{rows: [][]any{{
@@ -328,43 +580,16 @@ func TestAccountService_Writers_WriteRowsCoverNullables(t *testing.T) {
 			case strings.Contains(sql, "FROM sessions"):
 				sessionID := uuid.New()
 				return &fakeRows{rows: [][]any{{
-					sessionID, userID, expiresAt, now,
+					sessionID, userID, expiresAt, now, &now, stringPtr("Firefox"), stringPtr("203.0.113.7"),
 				}}}, nil
 			case strings.Contains(sql, "FROM reminder_settings"):
 				return &fakeRows{rows: [][]any{{
 					userID, true, 3, now, now,
 				}}}, nil
 			case strings.Contains(sql, "FROM card_checkin_reminders"):
-				nextSendAt := now.Add(48 * time.Hour)
-				return &fakeRows{rows: [][]any{{
-					checkinID,
-					userID,
-					cardID,
-					true,
-					"monthly",
-					[]byte(`{"day_of_month":28,"time":"09:00"}`),
-					true,
-					true,
-					&nextSendAt,
-					nil,
-					now,
-					now,
-				}}}, nil
+				return &fakeRows{rows: [][]any{checkin.Row()}}, nil
 			case strings.Contains(sql, "FROM goal_reminders"):
-				nextSendAt := now.Add(72 * time.Hour)
-				return &fakeRows{rows: [][]any{{
-					goalReminderID,
-					userID,
-					cardID,
-					itemID,
-					true,
-					"one_time",
-					[]byte(`{"send_at":"2030-01-02T15:04:05Z"}`),
-					&nextSendAt,
-					nil,
-					now,
-					now,
-				}}}, nil
+				return &fakeRows{rows: [][]any{goalReminder.Row()}}, nil
 			case strings.Contains(sql, "FROM reminder_email_log"):
 				sentAt := now.Add(-30 * time.Minute)
 				sentOn := time.Date(sentAt.Year(), sentAt.Month(), sentAt.Day(), 0, 0, 0, 0, sentAt.Location())
@@ -443,62 +668,38 @@ func TestAccountService_Writers_WriteRowsCoverNullables(t *testing.T) {
 	var buf bytes.Buffer
 	zipWriter := zip.NewWriter(&buf)
 
-	if err := service.writeCardsCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeCardsCSV: %v", err)
-	}
-	if err := service.writeItemsCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeItemsCSV: %v", err)
-	}
-	if err := service.writeFriendshipsCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeFriendshipsCSV: %v", err)
-	}
-	if err := service.writeBlocksCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeBlocksCSV: %v", err)
-	}
-	if err := service.writeNotificationsCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeNotificationsCSV: %v", err)
-	}
-	if err := service.writeAPITokensCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeAPITokensCSV: %v", err)
-	}
-	if err := service.writeNotificationSettingsCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeNotificationSettingsCSV: %v", err)
-	}
-	if err := service.writeFriendInvitesCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeFriendInvitesCSV: %v", err)
-	}
-	if err := service.writeSessionsCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeSessionsCSV: %v", err)
-	}
-	if err := service.writeReminderSettingsCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeReminderSettingsCSV: %v", err)
-	}
-	if err := service.writeCardCheckinRemindersCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeCardCheckinRemindersCSV: %v", err)
-	}
-	if err := service.writeGoalRemindersCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeGoalRemindersCSV: %v", err)
-	}
-	if err := service.writeCardSharesCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeCardSharesCSV: %v", err)
-	}
-	if err := service.writeReminderEmailLogCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeReminderEmailLogCSV: %v", err)
-	}
-	if err := service.writeReminderImageTokensCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeReminderImageTokensCSV: %v", err)
-	}
-	if err := service.writeReminderUnsubscribeTokensCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeReminderUnsubscribeTokensCSV: %v", err)
-	}
-	if err := service.writeEmailVerificationTokensCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeEmailVerificationTokensCSV: %v", err)
-	}
-	if err := service.writePasswordResetTokensCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writePasswordResetTokensCSV: %v", err)
-	}
-	if err := service.writeAIGenerationLogsCSV(context.Background(), zipWriter, userID); err != nil {
-		t.Fatalf("writeAIGenerationLogsCSV: %v", err)
+	writers := map[string]func(context.Context, exportWriter, uuid.UUID) error{
+		"cards":                       service.writeCards,
+		"items":                       service.writeItems,
+		"friendships":                 service.writeFriendships,
+		"blocks":                      service.writeBlocks,
+		"notifications":               service.writeNotifications,
+		"api_tokens":                  service.writeAPITokens,
+		"notification_settings":       service.writeNotificationSettings,
+		"friend_invites":              service.writeFriendInvites,
+		"sessions":                    service.writeSessions,
+		"reminder_settings":           service.writeReminderSettings,
+		"card_checkin_reminders":      service.writeCardCheckinReminders,
+		"goal_reminders":              service.writeGoalReminders,
+		"card_shares":                 service.writeCardShares,
+		"reminder_email_log":          service.writeReminderEmailLog,
+		"reminder_image_tokens":       service.writeReminderImageTokens,
+		"reminder_unsubscribe_tokens": service.writeReminderUnsubscribeTokens,
+		"email_verification_tokens":   service.writeEmailVerificationTokens,
+		"password_reset_tokens":       service.writePasswordResetTokens,
+		"ai_generation_logs":          service.writeAIGenerationLogs,
+	}
+	jsonOut := &jsonExportWriter{tables: map[string][]exportRecord{}}
+	for name, write := range writers {
+		if err := write(context.Background(), zipExportWriter{zipWriter: zipWriter}, userID); err != nil {
+			t.Fatalf("%s csv: %v", name, err)
+		}
+		if err := write(context.Background(), jsonOut, userID); err != nil {
+			t.Fatalf("%s json: %v", name, err)
+		}
+		if len(jsonOut.tables[name]) != 1 {
+			t.Fatalf("%s: expected one json row, got %d", name, len(jsonOut.tables[name]))
+		}
 	}
 	if err := zipWriter.Close(); err != nil {
 		t.Fatalf("zip close: %v", err)
@@ -561,12 +762,3 @@ func firstLine(data []byte) string {
 	parts := strings.SplitN(string(data), "\n", 2)
 	return strings.TrimSpace(parts[0])
 }
-
-func containsSQL(values []string, needle string) bool {
-	for _, value := range values {
-		if strings.Contains(value, needle) {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type AdminAuditService struct {
+	db DB
+}
+
+func NewAdminAuditService(db DB) *AdminAuditService {
+	return &AdminAuditService{db: db}
+}
+
+// Record stores an admin action. Details defaults to an empty JSON object.
+func (s *AdminAuditService) Record(ctx context.Context, entry models.AdminAuditEntry) error {
+	details := entry.Details
+	if len(details) == 0 || !json.Valid(details) {
+		details = json.RawMessage(`{}`)
+	}
+
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO admin_audit_log (admin_user_id, action, target_user_id, target_id, details)
+		VALUES ($1, $2, $3, $4, $5)`,
+		entry.AdminUserID,
+		entry.Action,
+		entry.TargetUserID,
+		entry.TargetID,
+		[]byte(details),
+	)
+	if err != nil {
+		return fmt.Errorf("record admin audit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestAdminAuditService_Record(t *testing.T) {
+	adminID := uuid.New()
+	targetUserID := uuid.New()
+
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "INSERT INTO admin_audit_log") {
+				t.Fatalf("unexpected sql: %s", sql)
+			}
+			gotArgs = args
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	svc := NewAdminAuditService(db)
+	err := svc.Record(context.Background(), models.AdminAuditEntry{
+		AdminUserID:  adminID,
+		Action:       "reminder.resend",
+		TargetUserID: &targetUserID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotArgs[0] != adminID || gotArgs[1] != "reminder.resend" {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+	if string(gotArgs[4].([]byte)) != "{}" {
+		t.Fatalf("expected empty details object, got %s", gotArgs[4])
+	}
+}
+
+func TestAdminAuditService_RecordError(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return nil, errors.New("db down")
+		},
+	}
+
+	svc := NewAdminAuditService(db)
+	if err := svc.Record(context.Background(), models.AdminAuditEntry{Action: "x"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
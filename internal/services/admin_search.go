@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var ErrInvalidSearchType = errors.New("invalid search type")
+
+// activeShareCondition matches cards (aliased c) with an unexpired public share link.
+const activeShareCondition = `EXISTS (
+	SELECT 1 FROM bingo_card_shares s
+	WHERE s.card_id = c.id AND (s.expires_at IS NULL OR s.expires_at > NOW())
+)`
+
+// AdminSearchService finds user content for moderation and support.
+type AdminSearchService struct {
+	db DB
+}
+
+func NewAdminSearchService(db DB) *AdminSearchService {
+	return &AdminSearchService{db: db}
+}
+
+// Search returns one page of matches for params.Query. Item content is only
+// searched on publicly shared cards, skipping private goals, unless
+// IncludePrivate is set.
+func (s *AdminSearchService) Search(ctx context.Context, params models.AdminSearchParams) (*models.AdminSearchResult, error) {
+	pattern := likeContains(params.Query)
+
+	var (
+		sql  string
+		args []any
+	)
+	switch params.Type {
+	case models.AdminSearchCards:
+		sql = `SELECT c.id, c.user_id, u.username, c.id, c.year, COALESCE(c.title, ''), ` + activeShareCondition + `, false, c.created_at
+			FROM bingo_cards c
+			JOIN users u ON u.id = c.user_id
+			WHERE u.deleted_at IS NULL AND c.title ILIKE $1
+			ORDER BY c.created_at DESC, c.id
+			LIMIT $2 OFFSET $3`
+		args = []any{pattern, params.Limit + 1, params.Offset}
+	case models.AdminSearchItems:
+		sql = `SELECT i.id, c.user_id, u.username, c.id, c.year, i.content, ` + activeShareCondition + `, i.is_private, i.created_at
+			FROM bingo_items i
+			JOIN bingo_cards c ON c.id = i.card_id
+			JOIN users u ON u.id = c.user_id
+			WHERE u.deleted_at IS NULL AND i.content ILIKE $1
+			  AND ($4 OR (` + activeShareCondition + ` AND NOT i.is_private))
+			ORDER BY i.created_at DESC, i.id
+			LIMIT $2 OFFSET $3`
+		args = []any{pattern, params.Limit + 1, params.Offset, params.IncludePrivate}
+	case models.AdminSearchUsers:
+		sql = `SELECT u.id, u.id, u.username, u.email, u.created_at
+			FROM users u
+			WHERE u.deleted_at IS NULL AND (u.username ILIKE $1 OR u.email ILIKE $1)
+			ORDER BY u.username, u.id
+			LIMIT $2 OFFSET $3`
+		args = []any{pattern, params.Limit + 1, params.Offset}
+	default:
+		return nil, ErrInvalidSearchType
+	}
+
+	rows, err := s.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("admin search %s: %w", params.Type, err)
+	}
+	defer rows.Close()
+
+	result := &models.AdminSearchResult{Items: []models.AdminSearchHit{}}
+	for rows.Next() {
+		hit := models.AdminSearchHit{Type: params.Type}
+		if params.Type == models.AdminSearchUsers {
+			err = rows.Scan(&hit.ID, &hit.UserID, &hit.Username, &hit.Email, &hit.CreatedAt)
+			hit.Text = hit.Username
+		} else {
+			var cardID uuid.UUID
+			err = rows.Scan(&hit.ID, &hit.UserID, &hit.Username, &cardID, &hit.Year, &hit.Text, &hit.IsShared, &hit.IsPrivate, &hit.CreatedAt)
+			hit.CardID = &cardID
+		}
+		if err != nil {
+			return nil, fmt.Errorf("scanning admin search hit: %w", err)
+		}
+		hit.AdminUserURL = "/api/admin/users/" + hit.UserID.String() + "/reminders"
+		result.Items = append(result.Items, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating admin search hits: %w", err)
+	}
+
+	if len(result.Items) > params.Limit {
+		result.Items = result.Items[:params.Limit]
+		result.HasMore = true
+	}
+	return result, nil
+}
+
+// likeContains builds an ILIKE pattern matching query anywhere, treating the
+// LIKE wildcards in query as literals.
+func likeContains(query string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query)
+	return "%" + escaped + "%"
+}
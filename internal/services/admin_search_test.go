@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestAdminSearchService_Items_RestrictedToSharedByDefault(t *testing.T) {
+	itemID := uuid.New()
+	userID := uuid.New()
+	cardID := uuid.New()
+	var gotSQL string
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			gotSQL = sql
+			gotArgs = args
+			return &fakeRows{rows: [][]any{
+				{itemID, userID, "alice", cardID, 2026, "Offensive goal", true, false, time.Now()},
+			}}, nil
+		},
+	}
+
+	svc := NewAdminSearchService(db)
+	result, err := svc.Search(context.Background(), models.AdminSearchParams{
+		Type:   models.AdminSearchItems,
+		Query:  "offens",
+		Limit:  20,
+		Offset: 0,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(gotSQL, "bingo_card_shares") || !strings.Contains(gotSQL, "NOT i.is_private") {
+		t.Fatalf("expected shared/private restriction in query: %s", gotSQL)
+	}
+	if gotArgs[0] != "%offens%" || gotArgs[1] != 21 || gotArgs[3] != false {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+	if len(result.Items) != 1 || result.HasMore {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	hit := result.Items[0]
+	if hit.ID != itemID || hit.CardID == nil || *hit.CardID != cardID || !hit.IsShared || hit.Text != "Offensive goal" {
+		t.Fatalf("unexpected hit: %+v", hit)
+	}
+	if hit.AdminUserURL != "/api/admin/users/"+userID.String()+"/reminders" {
+		t.Fatalf("unexpected admin user url: %q", hit.AdminUserURL)
+	}
+}
+
+func TestAdminSearchService_Items_IncludePrivateOverride(t *testing.T) {
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			gotArgs = args
+			return &fakeRows{}, nil
+		},
+	}
+
+	svc := NewAdminSearchService(db)
+	result, err := svc.Search(context.Background(), models.AdminSearchParams{
+		Type:           models.AdminSearchItems,
+		Query:          "goal",
+		IncludePrivate: true,
+		Limit:          20,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotArgs[3] != true {
+		t.Fatalf("expected include_private arg, got %v", gotArgs)
+	}
+	if result.Items == nil {
+		t.Fatal("expected empty results slice, not nil")
+	}
+}
+
+func TestAdminSearchService_Users_Paginates(t *testing.T) {
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			gotArgs = args
+			if !strings.Contains(sql, "u.email ILIKE $1") {
+				t.Fatalf("expected email match in query: %s", sql)
+			}
+			return &fakeRows{rows: [][]any{
+				{uuid.New(), uuid.New(), "bob1", "bob1@example.com", time.Now()},
+				{uuid.New(), uuid.New(), "bob2", "bob2@example.com", time.Now()},
+				{uuid.New(), uuid.New(), "bob3", "bob3@example.com", time.Now()},
+			}}, nil
+		},
+	}
+
+	svc := NewAdminSearchService(db)
+	result, err := svc.Search(context.Background(), models.AdminSearchParams{
+		Type:   models.AdminSearchUsers,
+		Query:  "bob",
+		Limit:  2,
+		Offset: 4,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotArgs[1] != 3 || gotArgs[2] != 4 {
+		t.Fatalf("expected limit+1 and offset args, got %v", gotArgs)
+	}
+	if len(result.Items) != 2 || !result.HasMore {
+		t.Fatalf("unexpected page: %+v", result)
+	}
+	if result.Items[0].Email != "bob1@example.com" || result.Items[0].Text != "bob1" {
+		t.Fatalf("unexpected user hit: %+v", result.Items[0])
+	}
+}
+
+func TestAdminSearchService_Cards(t *testing.T) {
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "c.title ILIKE $1") {
+				t.Fatalf("expected title match in query: %s", sql)
+			}
+			return &fakeRows{rows: [][]any{
+				{uuid.New(), uuid.New(), "carol", uuid.New(), 2025, "My Year", false, false, time.Now()},
+			}}, nil
+		},
+	}
+
+	svc := NewAdminSearchService(db)
+	result, err := svc.Search(context.Background(), models.AdminSearchParams{Type: models.AdminSearchCards, Query: "year", Limit: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Items) != 1 || result.Items[0].Year != 2025 || result.Items[0].Type != models.AdminSearchCards {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestAdminSearchService_InvalidTypeAndQueryError(t *testing.T) {
+	svc := NewAdminSearchService(&fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return nil, errors.New("boom")
+		},
+	})
+
+	if _, err := svc.Search(context.Background(), models.AdminSearchParams{Type: "emails", Query: "x"}); !errors.Is(err, ErrInvalidSearchType) {
+		t.Fatalf("expected ErrInvalidSearchType, got %v", err)
+	}
+	if _, err := svc.Search(context.Background(), models.AdminSearchParams{Type: models.AdminSearchUsers, Query: "xx", Limit: 20}); err == nil {
+		t.Fatal("expected query error")
+	}
+}
+
+func TestLikeContains_EscapesWildcards(t *testing.T) {
+	if got := likeContains(`50%_off\`); got != `%50\%\_off\\%` {
+		t.Fatalf("unexpected pattern: %q", got)
+	}
+}
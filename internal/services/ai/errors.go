@@ -10,4 +10,5 @@ var (
 	ErrInvalidInput               = errors.New("invalid input parameters")                   // 400
 	ErrEmailVerificationRequired  = errors.New("email verification required for AI")         // 403
 	ErrAIUsageTrackingUnavailable = errors.New("AI usage tracking unavailable")              // 503
+	ErrAIQuotaExhausted           = errors.New("monthly AI generation quota exhausted")      // 403
 )
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,17 +12,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
 )
 
-const (
-	freeGenerationsBeforeVerification = 5
-)
-
 var geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta/models"
 
 type Service struct {
@@ -39,6 +33,9 @@ type Service struct {
 	debug           bool
 	debugMaxChars   int
 	environment     string
+	// freePerMonth is each verified user's monthly allowance; 0 means no cap.
+	freePerMonth int
+	now          func() time.Time
 }
 
 func NewService(cfg *config.Config, db services.DBConn) *Service {
@@ -92,68 +89,11 @@ func NewService(cfg *config.Config, db services.DBConn) *Service {
 		debug:           cfg.Server.Debug,
 		debugMaxChars:   debugMaxChars,
 		environment:     cfg.Server.Environment,
+		freePerMonth:    cfg.AI.FreeGenerationsPerMonth,
+		now:             time.Now,
 	}
 }
 
-// ConsumeUnverifiedFreeGeneration increments the caller's free-generation counter (max 5) and returns remaining free generations.
-// This is used to allow a small trial for unverified users while keeping costs bounded.
-func (s *Service) ConsumeUnverifiedFreeGeneration(ctx context.Context, userID uuid.UUID) (int, error) {
-	if s.db == nil {
-		return 0, ErrAIUsageTrackingUnavailable
-	}
-
-	var used int
-	err := s.db.QueryRow(ctx, `
-		UPDATE users
-		SET ai_free_generations_used = ai_free_generations_used + 1
-		WHERE id = $1
-		  AND email_verified = false
-		  AND ai_free_generations_used < $2
-		RETURNING ai_free_generations_used
-	`, userID, freeGenerationsBeforeVerification).Scan(&used)
-	if errors.Is(err, pgx.ErrNoRows) {
-		return 0, ErrEmailVerificationRequired
-	}
-	if err != nil {
-		logging.Error("Failed to increment AI free generation counter", map[string]interface{}{
-			"error":   err.Error(),
-			"user_id": userID.String(),
-		})
-		return 0, ErrAIUsageTrackingUnavailable
-	}
-
-	remaining := freeGenerationsBeforeVerification - used
-	if remaining < 0 {
-		remaining = 0
-	}
-	return remaining, nil
-}
-
-func (s *Service) RefundUnverifiedFreeGeneration(ctx context.Context, userID uuid.UUID) (bool, error) {
-	if s.db == nil {
-		return false, ErrAIUsageTrackingUnavailable
-	}
-
-	tag, err := s.db.Exec(ctx, `
-		UPDATE users
-		SET ai_free_generations_used = GREATEST(ai_free_generations_used - 1, 0)
-		WHERE id = $1
-		  AND email_verified = false
-		  AND ai_free_generations_used > 0
-	`, userID)
-	if err != nil {
-		logging.Error("Failed to refund AI free generation counter", map[string]interface{}{
-			"error":   err.Error(),
-			"user_id": userID.String(),
-		})
-		return false, ErrAIUsageTrackingUnavailable
-	}
-	if tag.RowsAffected() == 0 {
-		return false, nil
-	}
-	return true, nil
-}
-
 type GoalPrompt struct {
 	Category   string
 	Focus      string
@@ -260,7 +200,7 @@ func (s *Service) GenerateGoals(ctx context.Context, userID uuid.UUID, prompt Go
 			Model:    "stub",
 			Duration: time.Since(start),
 		}
-		s.logUsageWithTimeout(userID, stats, "success")
+		s.logUsageWithTimeout(ctx, userID, stats, "success")
 		return goals, stats, nil
 	}
 
@@ -396,7 +336,10 @@ Output exactly %d items as a JSON array of strings.`,
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		s.logUsageWithTimeout(userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
+		s.logUsageWithTimeout(ctx, userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
+		if ctx.Err() != nil {
+			return nil, UsageStats{}, fmt.Errorf("gemini request canceled: %w", ctx.Err())
+		}
 		return nil, UsageStats{}, fmt.Errorf("%w: %v", ErrAIProviderUnavailable, err)
 	}
 	defer func() {
@@ -406,7 +349,7 @@ Output exactly %d items as a JSON array of strings.`,
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		s.logUsageWithTimeout(userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
+		s.logUsageWithTimeout(ctx, userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
 
 		if resp.StatusCode == http.StatusTooManyRequests {
 			return nil, UsageStats{}, fmt.Errorf("%w: status %d", ErrRateLimitExceeded, resp.StatusCode)
@@ -435,7 +378,10 @@ Output exactly %d items as a JSON array of strings.`,
 
 	var geminiResp geminiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
-		s.logUsageWithTimeout(userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
+		s.logUsageWithTimeout(ctx, userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
+		if ctx.Err() != nil {
+			return nil, UsageStats{}, fmt.Errorf("gemini response canceled: %w", ctx.Err())
+		}
 		return nil, UsageStats{}, fmt.Errorf("%w: failed to decode response", ErrAIProviderUnavailable)
 	}
 
@@ -449,19 +395,19 @@ Output exactly %d items as a JSON array of strings.`,
 	}
 
 	if len(geminiResp.Candidates) == 0 {
-		s.logUsageWithTimeout(userID, stats, "safety_block")
+		s.logUsageWithTimeout(ctx, userID, stats, "safety_block")
 		return nil, stats, ErrSafetyViolation // Or generic empty error
 	}
 
 	candidate := geminiResp.Candidates[0]
 	if candidate.FinishReason == "SAFETY" {
-		s.logUsageWithTimeout(userID, stats, "safety_block")
+		s.logUsageWithTimeout(ctx, userID, stats, "safety_block")
 		return nil, stats, ErrSafetyViolation
 	}
 
 	// Parse the JSON array from the text
 	if len(candidate.Content.Parts) == 0 {
-		s.logUsageWithTimeout(userID, stats, "error")
+		s.logUsageWithTimeout(ctx, userID, stats, "error")
 		return nil, stats, fmt.Errorf("%w: empty content parts", ErrAIProviderUnavailable)
 	}
 
@@ -494,7 +440,7 @@ Output exactly %d items as a JSON array of strings.`,
 
 	var goals []string
 	if err := json.Unmarshal([]byte(responseText), &goals); err != nil {
-		s.logUsageWithTimeout(userID, stats, "error")
+		s.logUsageWithTimeout(ctx, userID, stats, "error")
 		logging.Error("Gemini returned invalid JSON for goals array", map[string]interface{}{
 			"user_id":          userID.String(),
 			"finish_reason":    candidate.FinishReason,
@@ -511,7 +457,7 @@ Output exactly %d items as a JSON array of strings.`,
 		goals = goals[:count]
 	}
 	if len(goals) != count {
-		s.logUsageWithTimeout(userID, stats, "error")
+		s.logUsageWithTimeout(ctx, userID, stats, "error")
 		logging.Error("Gemini returned wrong goal count", map[string]interface{}{
 			"user_id":          userID.String(),
 			"finish_reason":    candidate.FinishReason,
@@ -522,10 +468,41 @@ Output exactly %d items as a JSON array of strings.`,
 		return nil, stats, fmt.Errorf("%w: expected %d goals, got %d", ErrAIProviderUnavailable, count, len(goals))
 	}
 
-	s.logUsageWithTimeout(userID, stats, "success")
+	s.logUsageWithTimeout(ctx, userID, stats, "success")
 	return goals, stats, nil
 }
 
+// Ping checks that the API key can reach the configured model by fetching the
+// model's metadata, which generates nothing and uses no tokens. The stub
+// provider always succeeds.
+func (s *Service) Ping(ctx context.Context) error {
+	if s.stub {
+		return nil
+	}
+	if strings.TrimSpace(s.apiKey) == "" {
+		return ErrAINotConfigured
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s", geminiBaseURL, s.model), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("x-goog-api-key", s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAIProviderUnavailable, err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status %d", ErrAIProviderUnavailable, resp.StatusCode)
+	}
+	return nil
+}
+
 // stripMarkdownCodeBlock removes leading and trailing markdown code block fences (```json or ```).
 func stripMarkdownCodeBlock(s string) string {
 	s = strings.TrimSpace(s)
@@ -549,10 +526,25 @@ func (s *Service) logUsage(ctx context.Context, userID uuid.UUID, stats UsageSta
 	if s.db == nil {
 		return
 	}
-	_, err := s.db.Exec(ctx, `
-        INSERT INTO ai_generation_logs (user_id, model, tokens_input, tokens_output, duration_ms, status)
-        VALUES ($1, $2, $3, $4, $5, $6)
-    `, userID, stats.Model, stats.TokensInput, stats.TokensOutput, stats.Duration.Milliseconds(), status)
+	minimized, err := services.IsDataMinimized(ctx, s.db, userID)
+	if err != nil {
+		logging.Error("Failed to check data minimization", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID.String(),
+		})
+		return
+	}
+	if minimized {
+		return
+	}
+	var tier *string
+	if t := quotaTierFrom(ctx); t != "" {
+		tier = &t
+	}
+	_, err = s.db.Exec(ctx, `
+        INSERT INTO ai_generation_logs (user_id, model, tokens_input, tokens_output, duration_ms, status, tier)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `, userID, stats.Model, stats.TokensInput, stats.TokensOutput, stats.Duration.Milliseconds(), status, tier)
 
 	if err != nil {
 		logging.Error("Failed to log AI usage", map[string]interface{}{
@@ -562,11 +554,13 @@ func (s *Service) logUsage(ctx context.Context, userID uuid.UUID, stats UsageSta
 	}
 }
 
-func (s *Service) logUsageWithTimeout(userID uuid.UUID, stats UsageStats, status string) {
+// logUsageWithTimeout logs on a context of its own, since the request's may
+// already be canceled, keeping the quota tier ctx carries.
+func (s *Service) logUsageWithTimeout(ctx context.Context, userID uuid.UUID, stats UsageStats, status string) {
 	if s.db == nil {
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
 	defer cancel()
 	s.logUsage(ctx, userID, stats, status)
 }
@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/services"
@@ -412,86 +411,63 @@ func TestNewService(t *testing.T) {
 	}
 }
 
-func TestConsumeUnverifiedFreeGeneration_NoDB(t *testing.T) {
+func TestLogUsage_NoDB(t *testing.T) {
 	svc := &Service{}
-	_, err := svc.ConsumeUnverifiedFreeGeneration(context.Background(), uuid.New())
-	if !errors.Is(err, ErrAIUsageTrackingUnavailable) {
-		t.Fatalf("expected tracking unavailable, got %v", err)
-	}
+	svc.logUsage(context.Background(), uuid.New(), UsageStats{}, "success")
 }
 
-func TestConsumeUnverifiedFreeGeneration_RequiresVerification(t *testing.T) {
+func TestLogUsage_WritesToDB(t *testing.T) {
+	called := false
 	db := &fakeDB{
-		queryRowFunc: func(ctx context.Context, sql string, args ...any) services.Row {
-			return fakeRow{scanFunc: func(dest ...any) error {
-				return pgx.ErrNoRows
-			}}
+		execFunc: func(ctx context.Context, sql string, args ...any) (services.CommandTag, error) {
+			called = true
+			return nil, nil
 		},
 	}
 	svc := &Service{db: db}
-	_, err := svc.ConsumeUnverifiedFreeGeneration(context.Background(), uuid.New())
-	if !errors.Is(err, ErrEmailVerificationRequired) {
-		t.Fatalf("expected verification required, got %v", err)
+	svc.logUsage(context.Background(), uuid.New(), UsageStats{Model: "m", TokensInput: 1, TokensOutput: 2, Duration: time.Second}, "success")
+	if !called {
+		t.Fatal("expected log usage insert")
 	}
 }
 
-func TestConsumeUnverifiedFreeGeneration_DBError(t *testing.T) {
+func TestLogUsage_SkipsDataMinimizedUser(t *testing.T) {
 	db := &fakeDB{
 		queryRowFunc: func(ctx context.Context, sql string, args ...any) services.Row {
+			if !strings.Contains(sql, "data_minimization") {
+				t.Fatalf("unexpected query: %s", sql)
+			}
 			return fakeRow{scanFunc: func(dest ...any) error {
-				return errors.New("boom")
+				*(dest[0].(*bool)) = true
+				return nil
 			}}
 		},
+		execFunc: func(ctx context.Context, sql string, args ...any) (services.CommandTag, error) {
+			t.Fatal("expected no ai_generation_logs insert for a data-minimized user")
+			return nil, nil
+		},
 	}
 	svc := &Service{db: db}
-	_, err := svc.ConsumeUnverifiedFreeGeneration(context.Background(), uuid.New())
-	if !errors.Is(err, ErrAIUsageTrackingUnavailable) {
-		t.Fatalf("expected ErrAIUsageTrackingUnavailable, got %v", err)
-	}
+	svc.logUsage(context.Background(), uuid.New(), UsageStats{Model: "m"}, "success")
 }
 
-func TestConsumeUnverifiedFreeGeneration_Success(t *testing.T) {
+func TestLogUsage_SkipsWhenPreferenceLookupFails(t *testing.T) {
 	db := &fakeDB{
 		queryRowFunc: func(ctx context.Context, sql string, args ...any) services.Row {
-			return fakeRow{scanFunc: func(dest ...any) error {
-				*(dest[0].(*int)) = 3
-				return nil
-			}}
+			return fakeRow{scanFunc: func(dest ...any) error { return errors.New("db down") }}
 		},
-	}
-	svc := &Service{db: db}
-	remaining, err := svc.ConsumeUnverifiedFreeGeneration(context.Background(), uuid.New())
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if remaining != 2 {
-		t.Fatalf("expected 2 remaining, got %d", remaining)
-	}
-}
-
-func TestLogUsage_NoDB(t *testing.T) {
-	svc := &Service{}
-	svc.logUsage(context.Background(), uuid.New(), UsageStats{}, "success")
-}
-
-func TestLogUsage_WritesToDB(t *testing.T) {
-	called := false
-	db := &fakeDB{
 		execFunc: func(ctx context.Context, sql string, args ...any) (services.CommandTag, error) {
-			called = true
+			t.Fatal("expected no insert when the preference lookup fails")
 			return nil, nil
 		},
 	}
 	svc := &Service{db: db}
-	svc.logUsage(context.Background(), uuid.New(), UsageStats{Model: "m", TokensInput: 1, TokensOutput: 2, Duration: time.Second}, "success")
-	if !called {
-		t.Fatal("expected log usage insert")
-	}
+	svc.logUsage(context.Background(), uuid.New(), UsageStats{Model: "m"}, "success")
 }
 
 func TestLogUsageWithTimeout_NoDB(t *testing.T) {
 	svc := &Service{}
-	svc.logUsageWithTimeout(uuid.New(), UsageStats{}, "success")
+	svc.logUsageWithTimeout(context.Background(), uuid.New(), UsageStats{}, "success")
 }
 
 func TestLogUsageWithTimeout_WritesToDB(t *testing.T) {
@@ -503,7 +479,7 @@ func TestLogUsageWithTimeout_WritesToDB(t *testing.T) {
 		},
 	}
 	svc := &Service{db: db}
-	svc.logUsageWithTimeout(uuid.New(), UsageStats{Model: "m", TokensInput: 1, TokensOutput: 2, Duration: time.Second}, "success")
+	svc.logUsageWithTimeout(context.Background(), uuid.New(), UsageStats{Model: "m", TokensInput: 1, TokensOutput: 2, Duration: time.Second}, "success")
 	if !called {
 		t.Fatal("expected log usage insert")
 	}
@@ -594,6 +570,57 @@ func TestGenerateGoals_NotConfigured(t *testing.T) {
 	}
 }
 
+func TestGenerateGoals_ClientDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	service := &Service{
+		apiKey: "test-key",
+		client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			cancel()
+			<-r.Context().Done()
+			return nil, r.Context().Err()
+		})},
+	}
+
+	_, _, err := service.GenerateGoals(ctx, uuid.New(), GoalPrompt{Category: "hobbies", Difficulty: "easy", Budget: "free"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if errors.Is(err, ErrAIProviderUnavailable) {
+		t.Fatalf("expected a canceled call not to count as a provider outage, got %v", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	var gotMethod, gotPath string
+	service := &Service{
+		apiKey: "test-key",
+		model:  "test-model",
+		client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			gotMethod, gotPath = r.Method, r.URL.Path
+			if r.Header.Get("x-goog-api-key") != "test-key" {
+				return jsonHTTPResponse(t, http.StatusForbidden, map[string]string{}), nil
+			}
+			return jsonHTTPResponse(t, http.StatusOK, map[string]string{"name": "models/test-model"}), nil
+		})},
+	}
+	if err := service.Ping(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotMethod != http.MethodGet || !strings.HasSuffix(gotPath, "/test-model") {
+		t.Fatalf("expected a GET of the model, got %s %s", gotMethod, gotPath)
+	}
+
+	service.apiKey = "wrong-key"
+	if err := service.Ping(context.Background()); !errors.Is(err, ErrAIProviderUnavailable) {
+		t.Fatalf("expected ErrAIProviderUnavailable, got %v", err)
+	}
+
+	service.apiKey = ""
+	if err := service.Ping(context.Background()); !errors.Is(err, ErrAINotConfigured) {
+		t.Fatalf("expected ErrAINotConfigured, got %v", err)
+	}
+}
+
 type roundTripperFunc func(r *http.Request) (*http.Response, error)
 
 func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
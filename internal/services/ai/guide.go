@@ -60,7 +60,7 @@ func (s *Service) GenerateGuideGoals(ctx context.Context, userID uuid.UUID, prom
 			Model:    "stub",
 			Duration: time.Since(start),
 		}
-		s.logUsageWithTimeout(userID, stats, "success")
+		s.logUsageWithTimeout(ctx, userID, stats, "success")
 		return goals, stats, nil
 	}
 
@@ -211,7 +211,10 @@ Output exactly %d items as a JSON array of strings.`,
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		s.logUsageWithTimeout(userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
+		s.logUsageWithTimeout(ctx, userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
+		if ctx.Err() != nil {
+			return nil, UsageStats{}, fmt.Errorf("gemini request canceled: %w", ctx.Err())
+		}
 		return nil, UsageStats{}, fmt.Errorf("%w: %v", ErrAIProviderUnavailable, err)
 	}
 	defer func() {
@@ -220,7 +223,7 @@ Output exactly %d items as a JSON array of strings.`,
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		s.logUsageWithTimeout(userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
+		s.logUsageWithTimeout(ctx, userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
 
 		if resp.StatusCode == http.StatusTooManyRequests {
 			return nil, UsageStats{}, fmt.Errorf("%w: status %d", ErrRateLimitExceeded, resp.StatusCode)
@@ -248,7 +251,10 @@ Output exactly %d items as a JSON array of strings.`,
 
 	var geminiResp geminiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
-		s.logUsageWithTimeout(userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
+		s.logUsageWithTimeout(ctx, userID, UsageStats{Model: s.model, Duration: time.Since(start)}, "error")
+		if ctx.Err() != nil {
+			return nil, UsageStats{}, fmt.Errorf("gemini response canceled: %w", ctx.Err())
+		}
 		return nil, UsageStats{}, fmt.Errorf("%w: failed to decode response", ErrAIProviderUnavailable)
 	}
 
@@ -261,18 +267,18 @@ Output exactly %d items as a JSON array of strings.`,
 	}
 
 	if len(geminiResp.Candidates) == 0 {
-		s.logUsageWithTimeout(userID, stats, "safety_block")
+		s.logUsageWithTimeout(ctx, userID, stats, "safety_block")
 		return nil, stats, ErrSafetyViolation
 	}
 
 	candidate := geminiResp.Candidates[0]
 	if candidate.FinishReason == "SAFETY" {
-		s.logUsageWithTimeout(userID, stats, "safety_block")
+		s.logUsageWithTimeout(ctx, userID, stats, "safety_block")
 		return nil, stats, ErrSafetyViolation
 	}
 
 	if len(candidate.Content.Parts) == 0 {
-		s.logUsageWithTimeout(userID, stats, "error")
+		s.logUsageWithTimeout(ctx, userID, stats, "error")
 		return nil, stats, fmt.Errorf("%w: empty content parts", ErrAIProviderUnavailable)
 	}
 
@@ -304,7 +310,7 @@ Output exactly %d items as a JSON array of strings.`,
 
 	var goals []string
 	if err := json.Unmarshal([]byte(responseText), &goals); err != nil {
-		s.logUsageWithTimeout(userID, stats, "error")
+		s.logUsageWithTimeout(ctx, userID, stats, "error")
 		logging.Error("Gemini returned invalid JSON for guide goals array", map[string]interface{}{
 			"user_id":          userID.String(),
 			"finish_reason":    candidate.FinishReason,
@@ -324,7 +330,7 @@ Output exactly %d items as a JSON array of strings.`,
 		goals = goals[:count]
 	}
 	if len(goals) != count {
-		s.logUsageWithTimeout(userID, stats, "error")
+		s.logUsageWithTimeout(ctx, userID, stats, "error")
 		logging.Error("Gemini returned wrong guide goal count", map[string]interface{}{
 			"user_id":          userID.String(),
 			"finish_reason":    candidate.FinishReason,
@@ -335,7 +341,7 @@ Output exactly %d items as a JSON array of strings.`,
 		return nil, stats, fmt.Errorf("%w: expected %d goals, got %d", ErrAIProviderUnavailable, count, len(goals))
 	}
 
-	s.logUsageWithTimeout(userID, stats, "success")
+	s.logUsageWithTimeout(ctx, userID, stats, "success")
 	return goals, stats, nil
 }
 
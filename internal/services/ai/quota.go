@@ -0,0 +1,267 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+const (
+	// FreeGenerationsBeforeVerification is how many AI generations an
+	// unverified user may run.
+	FreeGenerationsBeforeVerification = 5
+)
+
+type quotaTierKey struct{}
+
+// WithQuotaTier marks ctx with the quota tier a generation was admitted
+// under, so its ai_generation_logs row records which allowance paid for it.
+func WithQuotaTier(ctx context.Context, tier string) context.Context {
+	return context.WithValue(ctx, quotaTierKey{}, tier)
+}
+
+func quotaTierFrom(ctx context.Context) string {
+	tier, _ := ctx.Value(quotaTierKey{}).(string)
+	return tier
+}
+
+// quotaMonthStart is the start of the UTC month holding t. Monthly allowances
+// run from one month start to the next.
+func quotaMonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// quotaAccount is the users row the AI quota is computed from.
+// ai_free_generations_used counts the lifetime trial while the user is
+// unverified, and the month starting at ai_quota_period_start after that.
+type quotaAccount struct {
+	verified    bool
+	unlimited   bool
+	used        int
+	periodStart *time.Time
+}
+
+func (s *Service) loadQuotaAccount(ctx context.Context, userID uuid.UUID) (quotaAccount, error) {
+	var acct quotaAccount
+	err := s.db.QueryRow(ctx, `
+		SELECT email_verified, ai_unlimited, ai_free_generations_used, ai_quota_period_start
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL
+	`, userID).Scan(&acct.verified, &acct.unlimited, &acct.used, &acct.periodStart)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return quotaAccount{}, services.ErrUserNotFound
+	}
+	if err != nil {
+		logging.Error("Failed to load AI quota", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID.String(),
+		})
+		return quotaAccount{}, ErrAIUsageTrackingUnavailable
+	}
+	return acct, nil
+}
+
+func (s *Service) currentTime() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
+
+// quotaFor works out the account's tier and allowance as of now. A monthly
+// count from an earlier month reads as zero: the next generation or the reset
+// job, whichever comes first, starts the new month.
+func (s *Service) quotaFor(acct quotaAccount, now time.Time) *models.AIQuota {
+	used := acct.used
+	monthStart := quotaMonthStart(now)
+	if acct.verified && (acct.periodStart == nil || acct.periodStart.Before(monthStart)) {
+		used = 0
+	}
+
+	quota := &models.AIQuota{Used: used}
+	limit := 0
+	switch {
+	case acct.unlimited || (acct.verified && s.freePerMonth <= 0):
+		quota.Tier = models.AIQuotaTierUnlimited
+	case !acct.verified:
+		quota.Tier = models.AIQuotaTierTrial
+		limit = FreeGenerationsBeforeVerification
+	default:
+		quota.Tier = models.AIQuotaTierFree
+		limit = s.freePerMonth
+		resetsAt := monthStart.AddDate(0, 1, 0)
+		quota.ResetsAt = &resetsAt
+	}
+	if quota.Tier != models.AIQuotaTierUnlimited {
+		remaining := max(limit-used, 0)
+		quota.Limit = &limit
+		quota.Remaining = &remaining
+	}
+	return quota
+}
+
+// Quota reports the user's AI tier, how many generations they have used and
+// how many are left.
+func (s *Service) Quota(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+	if s.db == nil {
+		return nil, ErrAIUsageTrackingUnavailable
+	}
+	acct, err := s.loadQuotaAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.quotaFor(acct, s.currentTime()), nil
+}
+
+// ConsumeGeneration counts one AI generation against the user's quota and
+// returns the quota after it. When nothing is left it returns the quota with
+// ErrEmailVerificationRequired for the trial tier or ErrAIQuotaExhausted for
+// the monthly tier, and logs the refusal to ai_generation_logs. Unlimited
+// users are counted but never refused.
+func (s *Service) ConsumeGeneration(ctx context.Context, userID uuid.UUID) (*models.AIQuota, error) {
+	if s.db == nil {
+		return nil, ErrAIUsageTrackingUnavailable
+	}
+	acct, err := s.loadQuotaAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.currentTime()
+	quota := s.quotaFor(acct, now)
+	limit := 0
+	if quota.Limit != nil {
+		limit = *quota.Limit
+		if quota.Used >= limit {
+			return quota, s.refuseGeneration(ctx, userID, quota)
+		}
+	}
+
+	// The limit is checked again in the update, so concurrent requests
+	// can't overspend the allowance.
+	var used int
+	if acct.verified {
+		err = s.db.QueryRow(ctx, `
+			UPDATE users
+			SET ai_free_generations_used = CASE
+			        WHEN ai_quota_period_start IS NULL OR ai_quota_period_start < $2 THEN 1
+			        ELSE ai_free_generations_used + 1
+			    END,
+			    ai_quota_period_start = $2
+			WHERE id = $1
+			  AND email_verified = true
+			  AND ($3 = 0 OR ai_quota_period_start IS NULL OR ai_quota_period_start < $2 OR ai_free_generations_used < $3)
+			RETURNING ai_free_generations_used
+		`, userID, quotaMonthStart(now), limit).Scan(&used)
+	} else {
+		err = s.db.QueryRow(ctx, `
+			UPDATE users
+			SET ai_free_generations_used = ai_free_generations_used + 1
+			WHERE id = $1
+			  AND email_verified = false
+			  AND ($2 = 0 OR ai_free_generations_used < $2)
+			RETURNING ai_free_generations_used
+		`, userID, limit).Scan(&used)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Another request spent the last generation first.
+		quota.Used = limit
+		zero := 0
+		quota.Remaining = &zero
+		return quota, s.refuseGeneration(ctx, userID, quota)
+	}
+	if err != nil {
+		logging.Error("Failed to increment AI generation counter", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID.String(),
+		})
+		return nil, ErrAIUsageTrackingUnavailable
+	}
+
+	quota.Used = used
+	if quota.Limit != nil {
+		remaining := max(limit-used, 0)
+		quota.Remaining = &remaining
+	}
+	return quota, nil
+}
+
+// refuseGeneration logs a generation refused for lack of quota, so support
+// can see which tier turned the user away, and returns the error for it.
+func (s *Service) refuseGeneration(ctx context.Context, userID uuid.UUID, quota *models.AIQuota) error {
+	s.logUsage(WithQuotaTier(ctx, quota.Tier), userID, UsageStats{Model: s.model}, "quota_exhausted")
+	if quota.Tier == models.AIQuotaTierTrial {
+		return ErrEmailVerificationRequired
+	}
+	return ErrAIQuotaExhausted
+}
+
+// RefundGeneration gives back a generation counted by ConsumeGeneration for
+// a request that delivered no goals.
+func (s *Service) RefundGeneration(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if s.db == nil {
+		return false, ErrAIUsageTrackingUnavailable
+	}
+
+	tag, err := s.db.Exec(ctx, `
+		UPDATE users
+		SET ai_free_generations_used = GREATEST(ai_free_generations_used - 1, 0)
+		WHERE id = $1
+		  AND ai_free_generations_used > 0
+	`, userID)
+	if err != nil {
+		logging.Error("Failed to refund AI generation counter", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID.String(),
+		})
+		return false, ErrAIUsageTrackingUnavailable
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	return true, nil
+}
+
+// ResetMonthlyQuotas starts the current month for verified users still
+// counting an earlier one. Returns how many users were reset.
+func (s *Service) ResetMonthlyQuotas(ctx context.Context) (int, error) {
+	if s.db == nil {
+		return 0, nil
+	}
+	tag, err := s.db.Exec(ctx, `
+		UPDATE users
+		SET ai_free_generations_used = 0, ai_quota_period_start = $1
+		WHERE email_verified = true
+		  AND ai_quota_period_start < $1
+	`, quotaMonthStart(s.currentTime()))
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// SetUnlimited sets or clears the admin override that lifts the user's AI
+// quota, and returns the quota that now applies.
+func (s *Service) SetUnlimited(ctx context.Context, userID uuid.UUID, unlimited bool) (*models.AIQuota, error) {
+	if s.db == nil {
+		return nil, ErrAIUsageTrackingUnavailable
+	}
+	tag, err := s.db.Exec(ctx, `
+		UPDATE users SET ai_unlimited = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`, userID, unlimited)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, services.ErrUserNotFound
+	}
+	return s.Quota(ctx, userID)
+}
@@ -0,0 +1,370 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/database"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/services"
+)
+
+type fakeCommandTag int64
+
+func (t fakeCommandTag) RowsAffected() int64 { return int64(t) }
+
+// quotaDB serves a users row to the quota queries and records the counter
+// update and any ai_generation_logs insert.
+type quotaDB struct {
+	acct      quotaAccount
+	updateErr error
+	updated   []any
+	logged    []any
+}
+
+func (q *quotaDB) db(t *testing.T) *fakeDB {
+	return &fakeDB{
+		queryRowFunc: func(ctx context.Context, sql string, args ...any) services.Row {
+			switch {
+			case strings.Contains(sql, "SELECT email_verified, ai_unlimited"):
+				return fakeRow{scanFunc: func(dest ...any) error {
+					*(dest[0].(*bool)) = q.acct.verified
+					*(dest[1].(*bool)) = q.acct.unlimited
+					*(dest[2].(*int)) = q.acct.used
+					*(dest[3].(**time.Time)) = q.acct.periodStart
+					return nil
+				}}
+			case strings.Contains(sql, "UPDATE users"):
+				q.updated = args
+				return fakeRow{scanFunc: func(dest ...any) error {
+					if q.updateErr != nil {
+						return q.updateErr
+					}
+					*(dest[0].(*int)) = q.acct.used + 1
+					return nil
+				}}
+			case strings.Contains(sql, "data_minimization"):
+				return fakeRow{scanFunc: func(dest ...any) error { return nil }}
+			}
+			t.Fatalf("unexpected query: %s", sql)
+			return nil
+		},
+		execFunc: func(ctx context.Context, sql string, args ...any) (services.CommandTag, error) {
+			if !strings.Contains(sql, "INSERT INTO ai_generation_logs") {
+				t.Fatalf("unexpected exec: %s", sql)
+			}
+			q.logged = args
+			return fakeCommandTag(1), nil
+		},
+	}
+}
+
+func TestConsumeGeneration(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	thisMonth := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	lastMonth := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	nextMonth := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		acct          quotaAccount
+		updateErr     error
+		wantErr       error
+		wantTier      string
+		wantUsed      int
+		wantRemaining *int
+		wantResetsAt  *time.Time
+		wantUpdate    bool
+	}{
+		{
+			name:          "trial counts toward verification",
+			acct:          quotaAccount{used: 2},
+			wantTier:      models.AIQuotaTierTrial,
+			wantUsed:      3,
+			wantRemaining: intPtr(2),
+			wantUpdate:    true,
+		},
+		{
+			name:          "trial exhausted requires verification",
+			acct:          quotaAccount{used: FreeGenerationsBeforeVerification},
+			wantErr:       ErrEmailVerificationRequired,
+			wantTier:      models.AIQuotaTierTrial,
+			wantUsed:      FreeGenerationsBeforeVerification,
+			wantRemaining: intPtr(0),
+		},
+		{
+			name:          "monthly allowance spends down",
+			acct:          quotaAccount{verified: true, used: 3, periodStart: &thisMonth},
+			wantTier:      models.AIQuotaTierFree,
+			wantUsed:      4,
+			wantRemaining: intPtr(0),
+			wantResetsAt:  &nextMonth,
+			wantUpdate:    true,
+		},
+		{
+			name:          "monthly allowance exhausted",
+			acct:          quotaAccount{verified: true, used: 4, periodStart: &thisMonth},
+			wantErr:       ErrAIQuotaExhausted,
+			wantTier:      models.AIQuotaTierFree,
+			wantUsed:      4,
+			wantRemaining: intPtr(0),
+			wantResetsAt:  &nextMonth,
+		},
+		{
+			name:          "last month's count is not held against this month",
+			acct:          quotaAccount{verified: true, used: 4, periodStart: &lastMonth},
+			wantTier:      models.AIQuotaTierFree,
+			wantUsed:      5,
+			wantRemaining: intPtr(0),
+			wantResetsAt:  &nextMonth,
+			wantUpdate:    true,
+		},
+		{
+			name:       "unlimited override is counted but never refused",
+			acct:       quotaAccount{verified: true, unlimited: true, used: 40, periodStart: &thisMonth},
+			wantTier:   models.AIQuotaTierUnlimited,
+			wantUsed:   41,
+			wantUpdate: true,
+		},
+		{
+			name:          "concurrent request spent the last generation",
+			acct:          quotaAccount{verified: true, used: 3, periodStart: &thisMonth},
+			updateErr:     pgx.ErrNoRows,
+			wantErr:       ErrAIQuotaExhausted,
+			wantTier:      models.AIQuotaTierFree,
+			wantUsed:      4,
+			wantRemaining: intPtr(0),
+			wantResetsAt:  &nextMonth,
+			wantUpdate:    true,
+		},
+		{
+			name:       "counter update failure",
+			acct:       quotaAccount{verified: true, used: 1, periodStart: &thisMonth},
+			updateErr:  errors.New("boom"),
+			wantErr:    ErrAIUsageTrackingUnavailable,
+			wantUpdate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &quotaDB{acct: tt.acct, updateErr: tt.updateErr}
+			svc := &Service{db: q.db(t), model: "test-model", freePerMonth: 4, now: func() time.Time { return now }}
+
+			quota, err := svc.ConsumeGeneration(context.Background(), uuid.New())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if (q.updated != nil) != tt.wantUpdate {
+				t.Fatalf("expected counter update %v, got args %v", tt.wantUpdate, q.updated)
+			}
+			if tt.wantUpdate && tt.acct.verified && q.updated[1] != thisMonth {
+				t.Fatalf("expected the count to be for %v, got %v", thisMonth, q.updated[1])
+			}
+			refused := errors.Is(err, ErrAIQuotaExhausted) || errors.Is(err, ErrEmailVerificationRequired)
+			if refused != (q.logged != nil) {
+				t.Fatalf("expected a quota_exhausted log only for refusals, got %v", q.logged)
+			}
+			if refused && (q.logged[5] != "quota_exhausted" || *(q.logged[6].(*string)) != tt.wantTier) {
+				t.Fatalf("expected a quota_exhausted log for tier %s, got %v", tt.wantTier, q.logged)
+			}
+			if tt.wantTier == "" {
+				if quota != nil {
+					t.Fatalf("expected no quota, got %+v", quota)
+				}
+				return
+			}
+			if quota.Tier != tt.wantTier || quota.Used != tt.wantUsed {
+				t.Fatalf("expected %s with %d used, got %+v", tt.wantTier, tt.wantUsed, quota)
+			}
+			if !equalIntPtr(quota.Remaining, tt.wantRemaining) {
+				t.Fatalf("expected remaining %v, got %v", tt.wantRemaining, quota.Remaining)
+			}
+			if (quota.ResetsAt == nil) != (tt.wantResetsAt == nil) || (quota.ResetsAt != nil && !quota.ResetsAt.Equal(*tt.wantResetsAt)) {
+				t.Fatalf("expected resets_at %v, got %v", tt.wantResetsAt, quota.ResetsAt)
+			}
+		})
+	}
+}
+
+func TestConsumeGeneration_NoDB(t *testing.T) {
+	svc := &Service{}
+	if _, err := svc.ConsumeGeneration(context.Background(), uuid.New()); !errors.Is(err, ErrAIUsageTrackingUnavailable) {
+		t.Fatalf("expected tracking unavailable, got %v", err)
+	}
+}
+
+func TestQuota_MonthlyCapOff(t *testing.T) {
+	q := &quotaDB{acct: quotaAccount{verified: true, used: 7}}
+	svc := &Service{db: q.db(t)}
+	quota, err := svc.Quota(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quota.Tier != models.AIQuotaTierUnlimited || quota.Limit != nil || quota.Remaining != nil || quota.ResetsAt != nil {
+		t.Fatalf("expected an unlimited quota without a cap, got %+v", quota)
+	}
+}
+
+func TestQuota_UserNotFound(t *testing.T) {
+	db := &fakeDB{
+		queryRowFunc: func(ctx context.Context, sql string, args ...any) services.Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	svc := &Service{db: db}
+	if _, err := svc.Quota(context.Background(), uuid.New()); !errors.Is(err, services.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestResetMonthlyQuotas(t *testing.T) {
+	var args []any
+	db := &fakeDB{
+		execFunc: func(ctx context.Context, sql string, a ...any) (services.CommandTag, error) {
+			if !strings.Contains(sql, "SET ai_free_generations_used = 0") {
+				t.Fatalf("unexpected exec: %s", sql)
+			}
+			args = a
+			return fakeCommandTag(3), nil
+		},
+	}
+	svc := &Service{db: db, now: func() time.Time { return time.Date(2026, 5, 1, 0, 30, 0, 0, time.UTC) }}
+	n, err := svc.ResetMonthlyQuotas(context.Background())
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 users reset, got %d %v", n, err)
+	}
+	if want := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC); args[0] != want {
+		t.Fatalf("expected the new period to start %v, got %v", want, args[0])
+	}
+}
+
+func TestSetUnlimited_UserNotFound(t *testing.T) {
+	db := &fakeDB{
+		execFunc: func(ctx context.Context, sql string, args ...any) (services.CommandTag, error) {
+			return fakeCommandTag(0), nil
+		},
+	}
+	svc := &Service{db: db}
+	if _, err := svc.SetUnlimited(context.Background(), uuid.New(), true); !errors.Is(err, services.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestLogUsage_RecordsQuotaTier(t *testing.T) {
+	var args []any
+	db := &fakeDB{
+		execFunc: func(ctx context.Context, sql string, a ...any) (services.CommandTag, error) {
+			args = a
+			return nil, nil
+		},
+	}
+	svc := &Service{db: db}
+	ctx, cancel := context.WithCancel(WithQuotaTier(context.Background(), models.AIQuotaTierFree))
+	cancel()
+	svc.logUsageWithTimeout(ctx, uuid.New(), UsageStats{Model: "m"}, "success")
+	if tier, ok := args[6].(*string); !ok || tier == nil || *tier != models.AIQuotaTierFree {
+		t.Fatalf("expected the free tier logged after the request ended, got %v", args)
+	}
+}
+
+func TestQuota_SQLiteMonthlyRollover(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "bingo.db")
+	sqlite, err := database.NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(sqlite.Close)
+	migrator, err := database.NewMigrator(database.SQLiteMigrationURL(path), "../../../migrations/sqlite")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("unexpected migration error: %v", err)
+	}
+	_ = migrator.Close()
+	db := services.NewSQLiteAdapter(sqlite.DB)
+
+	user, err := services.NewUserService(db).Create(ctx, models.CreateUserParams{Email: "ai@example.com", Username: "ai"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	now := time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC)
+	svc := &Service{db: db, model: "test-model", freePerMonth: 2, now: func() time.Time { return now }}
+
+	// The trial is spent, then verification starts a fresh monthly count.
+	for i := 0; i < FreeGenerationsBeforeVerification; i++ {
+		if _, err := svc.ConsumeGeneration(ctx, user.ID); err != nil {
+			t.Fatalf("trial generation %d: unexpected error: %v", i+1, err)
+		}
+	}
+	if _, err := svc.ConsumeGeneration(ctx, user.ID); !errors.Is(err, ErrEmailVerificationRequired) {
+		t.Fatalf("expected ErrEmailVerificationRequired, got %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE users SET email_verified = true WHERE id = $1", user.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.ConsumeGeneration(ctx, user.ID); err != nil {
+			t.Fatalf("monthly generation %d: unexpected error: %v", i+1, err)
+		}
+	}
+	quota, err := svc.ConsumeGeneration(ctx, user.ID)
+	if !errors.Is(err, ErrAIQuotaExhausted) || *quota.Remaining != 0 {
+		t.Fatalf("expected the monthly allowance spent, got %+v %v", quota, err)
+	}
+	if refunded, err := svc.RefundGeneration(ctx, user.ID); err != nil || !refunded {
+		t.Fatalf("expected a refund, got %v %v", refunded, err)
+	}
+	if quota, err = svc.Quota(ctx, user.ID); err != nil || quota.Used != 1 {
+		t.Fatalf("expected one generation used after the refund, got %+v %v", quota, err)
+	}
+
+	// In April the reset job starts the new month.
+	now = time.Date(2026, 4, 1, 0, 10, 0, 0, time.UTC)
+	if n, err := svc.ResetMonthlyQuotas(ctx); err != nil || n != 1 {
+		t.Fatalf("expected one user reset, got %d %v", n, err)
+	}
+	quota, err = svc.Quota(ctx, user.ID)
+	if err != nil || quota.Used != 0 || *quota.Remaining != 2 || !quota.ResetsAt.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected a fresh allowance until May, got %+v %v", quota, err)
+	}
+
+	// The admin override lifts the cap without losing count.
+	if quota, err = svc.SetUnlimited(ctx, user.ID, true); err != nil || quota.Tier != models.AIQuotaTierUnlimited {
+		t.Fatalf("expected the unlimited tier, got %+v %v", quota, err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := svc.ConsumeGeneration(ctx, user.ID); err != nil {
+			t.Fatalf("unlimited generation %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	var refusals int
+	var tiers string
+	if err := db.QueryRow(ctx, "SELECT COUNT(*), GROUP_CONCAT(tier) FROM ai_generation_logs WHERE status = 'quota_exhausted'").Scan(&refusals, &tiers); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refusals != 2 || tiers != "trial,free" {
+		t.Fatalf("expected the trial and free refusals logged, got %d (%s)", refusals, tiers)
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
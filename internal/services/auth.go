@@ -20,6 +20,15 @@ const (
 	bcryptCost       = 12
 	sessionDuration  = 30 * 24 * time.Hour // 30 days
 	sessionKeyPrefix = "session:"
+
+	// sessionSeenKeyPrefix marks a session whose last_seen_at was written
+	// recently, so validating a Redis-cached session writes to the database
+	// at most once per sessionSeenInterval.
+	sessionSeenKeyPrefix = "session_seen:"
+	sessionSeenInterval  = 5 * time.Minute
+
+	maxSessionUserAgentLength = 512
+	maxSessionIPAddressLength = 45
 )
 
 var (
@@ -78,28 +87,56 @@ func (s *AuthService) hashToken(token string) string {
 	return hex.EncodeToString(hashBytes[:])
 }
 
+type sessionClientContextKey struct{}
+
+type sessionClient struct {
+	userAgent string
+	ipAddress string
+}
+
+// WithSessionClient records the user agent and IP address of a login request
+// so CreateSession can store them with the session.
+func WithSessionClient(ctx context.Context, userAgent, ipAddress string) context.Context {
+	return context.WithValue(ctx, sessionClientContextKey{}, sessionClient{userAgent: userAgent, ipAddress: ipAddress})
+}
+
+// optionalClientDetail cuts a captured client detail to its column's length
+// and stores a missing one as NULL.
+func optionalClientDetail(value string, maxLength int) *string {
+	if value == "" {
+		return nil
+	}
+	if runes := []rune(value); len(runes) > maxLength {
+		value = string(runes[:maxLength])
+	}
+	return &value
+}
+
+// CreateSession stores a new session for userID and returns its token. The
+// database row is the record of the session, listed and revoked from the
+// account; Redis caches it for fast lookups.
 func (s *AuthService) CreateSession(ctx context.Context, userID uuid.UUID) (token string, err error) {
 	token, tokenHash, err := s.GenerateSessionToken()
 	if err != nil {
 		return "", err
 	}
 
-	expiresAt := time.Now().Add(sessionDuration)
-
-	// Store in Redis for fast lookups
-	redisKey := sessionKeyPrefix + tokenHash
-	err = s.redis.Set(ctx, redisKey, userID.String(), sessionDuration)
+	now := time.Now()
+	client, _ := ctx.Value(sessionClientContextKey{}).(sessionClient)
+	_, err = s.db.Exec(ctx,
+		`INSERT INTO sessions (user_id, token_hash, expires_at, last_seen_at, user_agent, ip_address)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		userID, tokenHash, now.Add(sessionDuration), now,
+		optionalClientDetail(client.userAgent, maxSessionUserAgentLength), optionalClientDetail(client.ipAddress, maxSessionIPAddressLength),
+	)
 	if err != nil {
-		// Fall back to PostgreSQL if Redis fails
-		_, err = s.db.Exec(ctx,
-			`INSERT INTO sessions (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`,
-			userID, tokenHash, expiresAt,
-		)
-		if err != nil {
-			return "", fmt.Errorf("creating session in database: %w", err)
-		}
+		return "", fmt.Errorf("creating session in database: %w", err)
 	}
 
+	// A Redis failure only costs speed: validation falls back to the row.
+	_ = s.redis.Set(ctx, sessionKeyPrefix+tokenHash, userID.String(), sessionDuration)
+	_ = s.redis.Set(ctx, sessionSeenKeyPrefix+tokenHash, "1", sessionSeenInterval)
+
 	return token, nil
 }
 
@@ -118,16 +155,21 @@ func (s *AuthService) ValidateSession(ctx context.Context, token string) (*model
 			return nil, fmt.Errorf("parsing user id: %w", err)
 		}
 
-		return s.getUserByID(ctx, userID)
+		user, err := s.getUserByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		s.touchCachedSession(ctx, userID, tokenHash)
+		return user, nil
 	}
 
 	// Fall back to PostgreSQL
 	var session models.Session
 	err = s.db.QueryRow(ctx,
-		`SELECT id, user_id, token_hash, expires_at, created_at
+		`SELECT id, user_id, token_hash, expires_at, created_at, last_seen_at
 		 FROM sessions WHERE token_hash = $1`,
 		tokenHash,
-	).Scan(&session.ID, &session.UserID, &session.TokenHash, &session.ExpiresAt, &session.CreatedAt)
+	).Scan(&session.ID, &session.UserID, &session.TokenHash, &session.ExpiresAt, &session.CreatedAt, &session.LastSeenAt)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrSessionNotFound
@@ -142,15 +184,50 @@ func (s *AuthService) ValidateSession(ctx context.Context, token string) (*model
 		return nil, ErrSessionExpired
 	}
 
-	return s.getUserByID(ctx, session.UserID)
+	user, err := s.getUserByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if now := time.Now(); session.LastSeenAt == nil || now.Sub(*session.LastSeenAt) >= sessionSeenInterval {
+		_, _ = s.db.Exec(ctx,
+			"UPDATE sessions SET last_seen_at = $2, expires_at = $3 WHERE id = $1",
+			session.ID, now, now.Add(sessionDuration),
+		)
+	}
+	return user, nil
+}
+
+// touchCachedSession keeps the row of a Redis-cached session in step with
+// the cache: last_seen_at, and the expiry Redis slides on every request. It
+// writes at most once per sessionSeenInterval. A session cached before every
+// session had a row gets its row here, without client details.
+func (s *AuthService) touchCachedSession(ctx context.Context, userID uuid.UUID, tokenHash string) {
+	seenKey := sessionSeenKeyPrefix + tokenHash
+	if _, err := s.redis.Get(ctx, seenKey); err == nil {
+		return
+	}
+	now := time.Now()
+	tag, err := s.db.Exec(ctx,
+		"UPDATE sessions SET last_seen_at = $2, expires_at = $3 WHERE token_hash = $1",
+		tokenHash, now, now.Add(sessionDuration),
+	)
+	if err == nil && tag.RowsAffected() == 0 {
+		_, err = s.db.Exec(ctx,
+			"INSERT INTO sessions (user_id, token_hash, expires_at, last_seen_at) VALUES ($1, $2, $3, $4)",
+			userID, tokenHash, now.Add(sessionDuration), now,
+		)
+	}
+	if err != nil {
+		return
+	}
+	_ = s.redis.Set(ctx, seenKey, "1", sessionSeenInterval)
 }
 
 func (s *AuthService) DeleteSession(ctx context.Context, token string) error {
 	tokenHash := s.hashToken(token)
 
 	// Delete from Redis
-	redisKey := sessionKeyPrefix + tokenHash
-	_ = s.redis.Del(ctx, redisKey)
+	_ = s.redis.Del(ctx, sessionKeyPrefix+tokenHash, sessionSeenKeyPrefix+tokenHash)
 
 	// Delete from PostgreSQL
 	_, err := s.db.Exec(ctx, "DELETE FROM sessions WHERE token_hash = $1", tokenHash)
@@ -180,7 +257,7 @@ func (s *AuthService) DeleteAllUserSessions(ctx context.Context, userID uuid.UUI
 
 	// Delete from Redis
 	for _, hash := range tokenHashes {
-		_ = s.redis.Del(ctx, sessionKeyPrefix+hash)
+		_ = s.redis.Del(ctx, sessionKeyPrefix+hash, sessionSeenKeyPrefix+hash)
 	}
 
 	// Delete from PostgreSQL
@@ -192,6 +269,86 @@ func (s *AuthService) DeleteAllUserSessions(ctx context.Context, userID uuid.UUI
 	return nil
 }
 
+// ListSessions returns the user's unexpired sessions, most recently used
+// first, with the one for currentToken marked.
+func (s *AuthService) ListSessions(ctx context.Context, userID uuid.UUID, currentToken string) ([]models.Session, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT id, user_id, token_hash, expires_at, created_at, last_seen_at, COALESCE(user_agent, ''), COALESCE(ip_address, '')
+		 FROM sessions
+		 WHERE user_id = $1 AND expires_at > $2
+		 ORDER BY COALESCE(last_seen_at, created_at) DESC, created_at DESC`,
+		userID, time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying sessions: %w", err)
+	}
+	defer rows.Close()
+
+	currentHash := s.hashToken(currentToken)
+	sessions := []models.Session{}
+	for rows.Next() {
+		var session models.Session
+		if err := rows.Scan(&session.ID, &session.UserID, &session.TokenHash, &session.ExpiresAt, &session.CreatedAt,
+			&session.LastSeenAt, &session.UserAgent, &session.IPAddress); err != nil {
+			return nil, fmt.Errorf("scanning session: %w", err)
+		}
+		session.Current = currentToken != "" && session.TokenHash == currentHash
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends one of the user's sessions, in the database and in the
+// Redis cache, so its cookie stops working on the next request. It reports
+// whether that was the session for currentToken.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID, currentToken string) (bool, error) {
+	var tokenHash string
+	err := s.db.QueryRow(ctx,
+		"DELETE FROM sessions WHERE id = $1 AND user_id = $2 RETURNING token_hash",
+		sessionID, userID,
+	).Scan(&tokenHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrSessionNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("deleting session: %w", err)
+	}
+	_ = s.redis.Del(ctx, sessionKeyPrefix+tokenHash, sessionSeenKeyPrefix+tokenHash)
+	return currentToken != "" && tokenHash == s.hashToken(currentToken), nil
+}
+
+// RevokeOtherSessions ends every session of the user except the one for
+// currentToken and returns how many it ended.
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userID uuid.UUID, currentToken string) (int, error) {
+	rows, err := s.db.Query(ctx,
+		"DELETE FROM sessions WHERE user_id = $1 AND token_hash <> $2 RETURNING token_hash",
+		userID, s.hashToken(currentToken),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("deleting sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return 0, fmt.Errorf("scanning token hash: %w", err)
+		}
+		keys = append(keys, sessionKeyPrefix+hash, sessionSeenKeyPrefix+hash)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("deleting sessions: %w", err)
+	}
+	if len(keys) > 0 {
+		_ = s.redis.Del(ctx, keys...)
+	}
+	return len(keys) / 2, nil
+}
+
 func (s *AuthService) getUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	user := &models.User{}
 	err := s.db.QueryRow(ctx,
@@ -9,6 +9,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
 func TestAuthService_HashPassword(t *testing.T) {
@@ -240,7 +242,7 @@ func (f *fakeRedis) Del(ctx context.Context, keys ...string) error {
 	return f.delErr
 }
 
-func TestAuthService_CreateSession_RedisFailure_FallsBackToDB(t *testing.T) {
+func TestAuthService_CreateSession_RedisFailure_StillCreatesSession(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New()
 	execCalled := false
@@ -262,7 +264,7 @@ func TestAuthService_CreateSession_RedisFailure_FallsBackToDB(t *testing.T) {
 		t.Fatal("expected token to be returned")
 	}
 	if !execCalled {
-		t.Fatal("expected database fallback when redis set fails")
+		t.Fatal("expected the session row to be written when redis set fails")
 	}
 }
 
@@ -345,7 +347,7 @@ func TestAuthService_ValidateSession_DBExpired(t *testing.T) {
 
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			return rowFromValues(sessionID, userID, "hash", expired, expired)
+			return rowFromValues(sessionID, userID, "hash", expired, expired, (*time.Time)(nil))
 		},
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
 			execCalled = true
@@ -402,8 +404,8 @@ func TestAuthService_DeleteAllUserSessions_DeletesRedisAndDB(t *testing.T) {
 	if err := auth.DeleteAllUserSessions(ctx, userID); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if redis.delCalls != 2 {
-		t.Fatalf("expected 2 redis deletions, got %d", redis.delCalls)
+	if redis.delCalls != 4 {
+		t.Fatalf("expected session and seen keys deleted for both sessions, got %d", redis.delCalls)
 	}
 	if !execCalled {
 		t.Fatal("expected database delete for user sessions")
@@ -467,18 +469,22 @@ func TestAuthService_DeleteSession_Success(t *testing.T) {
 	}
 }
 
-func TestAuthService_CreateSession_RedisSuccess(t *testing.T) {
-	ctx := context.Background()
+func TestAuthService_CreateSession_StoresRowAndCachesInRedis(t *testing.T) {
 	userID := uuid.New()
+	var insertArgs []any
 	db := &fakeDB{
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			t.Fatal("unexpected db fallback")
-			return fakeCommandTag{}, nil
+			if !strings.Contains(sql, "INSERT INTO sessions") {
+				t.Fatalf("unexpected exec: %q", sql)
+			}
+			insertArgs = args
+			return fakeCommandTag{rowsAffected: 1}, nil
 		},
 	}
 	redis := &fakeRedis{}
 
 	auth := NewAuthService(db, redis)
+	ctx := WithSessionClient(context.Background(), strings.Repeat("a", 600), "203.0.113.7")
 	token, err := auth.CreateSession(ctx, userID)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -486,8 +492,34 @@ func TestAuthService_CreateSession_RedisSuccess(t *testing.T) {
 	if token == "" {
 		t.Fatal("expected token")
 	}
-	if redis.setCalls != 1 {
-		t.Fatalf("expected redis set, got %d", redis.setCalls)
+	if redis.setCalls != 2 {
+		t.Fatalf("expected session and seen keys set in redis, got %d", redis.setCalls)
+	}
+	if len(insertArgs) != 6 || insertArgs[0] != userID || insertArgs[1] != auth.hashToken(token) {
+		t.Fatalf("expected the session row to be inserted, got %v", insertArgs)
+	}
+	if ua := insertArgs[4].(*string); ua == nil || len(*ua) != maxSessionUserAgentLength {
+		t.Fatalf("expected the user agent cut to %d characters, got %v", maxSessionUserAgentLength, ua)
+	}
+	if ip := insertArgs[5].(*string); ip == nil || *ip != "203.0.113.7" {
+		t.Fatalf("expected the client IP stored, got %v", ip)
+	}
+}
+
+func TestAuthService_CreateSession_DBError(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{}, errors.New("db down")
+		},
+	}
+	redis := &fakeRedis{}
+
+	auth := NewAuthService(db, redis)
+	if _, err := auth.CreateSession(context.Background(), uuid.New()); err == nil {
+		t.Fatal("expected error")
+	}
+	if redis.setCalls != 0 {
+		t.Fatalf("expected nothing cached for a session without a row, got %d", redis.setCalls)
 	}
 }
 
@@ -503,7 +535,7 @@ func TestAuthService_ValidateSession_DBHit(t *testing.T) {
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			call++
 			if call == 1 {
-				return rowFromValues(sessionID, userID, "hash", expires, now)
+				return rowFromValues(sessionID, userID, "hash", expires, now, (*time.Time)(nil))
 			}
 			return rowFromValues(
 				userID,
@@ -553,3 +585,110 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+func TestAuthService_ListAndRevokeSessions(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "sessions@example.com", Username: "sessions"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	redis := newMemoryRedis()
+	auth := NewAuthService(db, redis)
+
+	current, err := auth.CreateSession(WithSessionClient(ctx, "Firefox", "198.51.100.1"), user.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	laptop, err := auth.CreateSession(WithSessionClient(ctx, "Safari", "198.51.100.2"), user.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	phone, err := auth.CreateSession(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sessions, err := auth.ListSessions(ctx, user.ID, current)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("expected 3 sessions, got %d", len(sessions))
+	}
+	var currentSession, laptopSession *models.Session
+	for i := range sessions {
+		switch sessions[i].TokenHash {
+		case auth.hashToken(current):
+			currentSession = &sessions[i]
+		case auth.hashToken(laptop):
+			laptopSession = &sessions[i]
+		}
+	}
+	if currentSession == nil || !currentSession.Current || currentSession.UserAgent != "Firefox" || currentSession.IPAddress != "198.51.100.1" {
+		t.Fatalf("expected the current session flagged with its client details, got %+v", currentSession)
+	}
+	if laptopSession == nil || laptopSession.Current || laptopSession.LastSeenAt == nil {
+		t.Fatalf("expected the laptop session listed with last seen, got %+v", laptopSession)
+	}
+
+	// Someone else can't revoke the user's session.
+	if _, err := auth.RevokeSession(ctx, uuid.New(), laptopSession.ID, current); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound, got %v", err)
+	}
+	wasCurrent, err := auth.RevokeSession(ctx, user.ID, laptopSession.ID, current)
+	if err != nil || wasCurrent {
+		t.Fatalf("expected the laptop session revoked, got current=%v err=%v", wasCurrent, err)
+	}
+	if _, err := auth.ValidateSession(ctx, laptop); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected the revoked session to stop working at once, got %v", err)
+	}
+
+	revoked, err := auth.RevokeOtherSessions(ctx, user.ID, current)
+	if err != nil || revoked != 1 {
+		t.Fatalf("expected the phone session revoked, got %d err=%v", revoked, err)
+	}
+	if _, err := auth.ValidateSession(ctx, phone); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected the phone session to stop working, got %v", err)
+	}
+	if _, err := auth.ValidateSession(ctx, current); err != nil {
+		t.Fatalf("expected the current session kept, got %v", err)
+	}
+
+	wasCurrent, err = auth.RevokeSession(ctx, user.ID, currentSession.ID, current)
+	if err != nil || !wasCurrent {
+		t.Fatalf("expected revoking the current session to be reported, got current=%v err=%v", wasCurrent, err)
+	}
+}
+
+func TestAuthService_ValidateSession_CachedSessionWithoutRowGetsOne(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "legacy@example.com", Username: "legacy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	redis := newMemoryRedis()
+	auth := NewAuthService(db, redis)
+
+	// Sessions used to live only in Redis.
+	token, tokenHash, err := auth.GenerateSessionToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = redis.Set(ctx, sessionKeyPrefix+tokenHash, user.ID.String(), sessionDuration)
+
+	if _, err := auth.ValidateSession(ctx, token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sessions, err := auth.ListSessions(ctx, user.ID, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sessions) != 1 || !sessions[0].Current || sessions[0].LastSeenAt == nil {
+		t.Fatalf("expected the cached session to be listed, got %+v", sessions)
+	}
+	if _, ok := redis.values[sessionSeenKeyPrefix+tokenHash]; !ok {
+		t.Fatal("expected the touch to be throttled by the seen key")
+	}
+}
@@ -32,7 +32,7 @@ func (s *BlockService) Block(ctx context.Context, blockerID, blockedID uuid.UUID
 		return ErrCannotBlockSelf
 	}
 
-	tx, err := s.db.Begin(ctx)
+	tx, ctx, err := beginTx(ctx, s.db)
 	if err != nil {
 		return fmt.Errorf("begin block transaction: %w", err)
 	}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+)
+
+// Accent colors used by the stock emails when no primary color is configured.
+const (
+	authEmailAccent     = "#4F46E5"
+	reminderEmailAccent = "#0f6f62"
+)
+
+// emailBrand renders the deployment's branding into email chrome. The zero
+// value reproduces the stock Year of Bingo emails.
+type emailBrand config.BrandingConfig
+
+func (b emailBrand) name() string {
+	return config.BrandingConfig(b).DisplayName()
+}
+
+// accent returns the configured primary color, or fallback when unset.
+func (b emailBrand) accent(fallback string) string {
+	if b.PrimaryColor != "" {
+		return b.PrimaryColor
+	}
+	return fallback
+}
+
+// logoHTML is the optional logo shown above an email heading.
+func (b emailBrand) logoHTML() string {
+	if b.LogoURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("<p><img src=\"%s\" alt=\"%s\" style=\"max-height: 48px;\"></p>\n  ",
+		templateEscape(b.LogoURL), templateEscape(b.name()))
+}
+
+func (b emailBrand) footerHTML() string {
+	if config.BrandingConfig(b).IsDefaultName() {
+		return "Year of Bingo - yearofbingo.com"
+	}
+	return templateEscape(b.name())
+}
+
+func (b emailBrand) footerText() string {
+	if config.BrandingConfig(b).IsDefaultName() {
+		return "Year of Bingo\nyearofbingo.com"
+	}
+	return b.name()
+}
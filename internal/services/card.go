@@ -12,6 +12,8 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
+	"github.com/HammerMeetNail/yearofbingo/internal/bingo"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
@@ -22,8 +24,11 @@ var (
 	ErrCardTitleExists   = errors.New("you already have a card with this title for this year")
 	ErrCardFinalized     = errors.New("card is finalized and cannot be modified")
 	ErrCardNotFinalized  = errors.New("card must be finalized first")
+	ErrProofRequired     = errors.New("a note or proof URL is required to complete items on this card")
 	ErrCardFull          = errors.New("card is full")
 	ErrItemNotFound      = errors.New("item not found")
+	ErrItemCompleted     = errors.New("item is already completed")
+	ErrItemAmbiguous     = errors.New("more than one item matches")
 	ErrPositionOccupied  = errors.New("position is already occupied")
 	ErrInvalidPosition   = errors.New("invalid position")
 	ErrNotCardOwner      = errors.New("you do not own this card")
@@ -32,21 +37,180 @@ var (
 	ErrInvalidGridSize   = errors.New("invalid grid size")
 	ErrInvalidHeaderText = errors.New("invalid header text")
 	ErrNoSpaceForFree    = errors.New("no space available for free space")
+
+	ErrInvalidFreeSpaceText = errors.New("invalid free space text")
+	ErrInvalidCardPeriod    = errors.New("invalid card period")
+	ErrNotEnoughOpenSquares = errors.New("not enough open squares")
+	ErrCardsNotOwned        = errors.New("cards not found")
 )
 
+// CardsNotOwnedError lists the card IDs in a bulk request that don't exist or
+// belong to another user. It matches ErrCardsNotOwned with errors.Is.
+type CardsNotOwnedError struct {
+	CardIDs []uuid.UUID
+}
+
+func (e *CardsNotOwnedError) Error() string {
+	ids := make([]string, len(e.CardIDs))
+	for i, id := range e.CardIDs {
+		ids[i] = id.String()
+	}
+	return fmt.Sprintf("%s: %s", ErrCardsNotOwned, strings.Join(ids, ", "))
+}
+
+func (e *CardsNotOwnedError) Unwrap() error {
+	return ErrCardsNotOwned
+}
+
+// ItemAmbiguousError lists the items that matched a content lookup when more
+// than one did. It matches ErrItemAmbiguous with errors.Is.
+type ItemAmbiguousError struct {
+	Candidates []models.BingoItem
+}
+
+func (e *ItemAmbiguousError) Error() string {
+	return fmt.Sprintf("%s: %d candidates", ErrItemAmbiguous, len(e.Candidates))
+}
+
+func (e *ItemAmbiguousError) Unwrap() error {
+	return ErrItemAmbiguous
+}
+
 type CardService struct {
 	db                  DB
 	notificationService NotificationServiceInterface
+	suggestionUsage     SuggestionUsageRecorder
+	difficultyWeights   models.DifficultyWeights
+	shareCache          *SharedCardCache
+	shareAccessLog      *ShareAccessLog
+	webhooks            WebhookEnqueuer
+	activities          ActivityRecorder
+	proofStorage        StorageService
 }
 
 func NewCardService(db DB) *CardService {
-	return &CardService{db: db}
+	return &CardService{db: db, difficultyWeights: models.DefaultDifficultyWeights}
 }
 
 func (s *CardService) SetNotificationService(notificationService NotificationServiceInterface) {
 	s.notificationService = notificationService
 }
 
+// SetSuggestionUsageRecorder enables suggestion adoption counting for new goals.
+func (s *CardService) SetSuggestionUsageRecorder(recorder SuggestionUsageRecorder) {
+	s.suggestionUsage = recorder
+}
+
+// SetShareCache enables caching of public share responses. Write paths that
+// can change a finalized card invalidate it.
+func (s *CardService) SetShareCache(cache *SharedCardCache) {
+	s.shareCache = cache
+}
+
+// SetWebhookEnqueuer enables webhook events for finalized cards, completed
+// items and new bingos.
+func (s *CardService) SetWebhookEnqueuer(webhooks WebhookEnqueuer) {
+	s.webhooks = webhooks
+}
+
+// SetActivityRecorder publishes completions and bingos on friends-visible
+// cards to the owner's ActivityPub outbox.
+func (s *CardService) SetActivityRecorder(activities ActivityRecorder) {
+	s.activities = activities
+}
+
+// recordActivity is enqueueWebhook for the ActivityPub outbox.
+func (s *CardService) recordActivity(ctx context.Context, userID, cardID uuid.UUID, kind string, bingoCount int) {
+	if s.activities == nil {
+		return
+	}
+	if err := s.activities.RecordActivity(context.WithoutCancel(ctx), userID, cardID, kind, bingoCount); err != nil {
+		logging.Warn("Failed to record ActivityPub activity", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID.String(),
+			"card_id": cardID.String(),
+			"kind":    kind,
+		})
+	}
+}
+
+// enqueueWebhook queues event for the user's webhooks once its write has
+// committed. Like notifications, a failure never fails the write. The write
+// is done, so a client disconnecting now doesn't drop the event.
+func (s *CardService) enqueueWebhook(ctx context.Context, userID uuid.UUID, event string, data any) {
+	if s.webhooks == nil {
+		return
+	}
+	if err := s.webhooks.Enqueue(context.WithoutCancel(ctx), userID, event, data); err != nil {
+		logging.Warn("Failed to queue webhook event", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID.String(),
+			"event":   event,
+		})
+	}
+}
+
+// invalidateShareCache drops cached share responses for the cards.
+func (s *CardService) invalidateShareCache(ctx context.Context, cardIDs ...uuid.UUID) {
+	if s.shareCache == nil {
+		return
+	}
+	for _, cardID := range cardIDs {
+		s.shareCache.Invalidate(ctx, cardID)
+	}
+}
+
+// SetDifficultyWeights overrides the weights used for weighted progress.
+// Non-positive weights keep their defaults.
+func (s *CardService) SetDifficultyWeights(weights models.DifficultyWeights) {
+	if weights.Easy > 0 {
+		s.difficultyWeights.Easy = weights.Easy
+	}
+	if weights.Medium > 0 {
+		s.difficultyWeights.Medium = weights.Medium
+	}
+	if weights.Hard > 0 {
+		s.difficultyWeights.Hard = weights.Hard
+	}
+}
+
+// recordSuggestionUsage counts new goals that match curated suggestions.
+// Analytics must never block a card edit, so failures are only logged.
+func (s *CardService) recordSuggestionUsage(ctx context.Context, contents []string) {
+	if s.suggestionUsage == nil || len(contents) == 0 {
+		return
+	}
+	if err := s.suggestionUsage.RecordUsage(ctx, contents); err != nil {
+		logging.Warn("Failed to record suggestion usage", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// resolveCardPeriod fills in the default calendar-year period for missing
+// dates and validates the result. A start date without an end date gives a
+// rolling 12-month card.
+func resolveCardPeriod(year int, start, end *time.Time) (time.Time, time.Time, error) {
+	startDate, endDate := models.DefaultCardPeriod(year)
+	if start != nil {
+		startDate = truncateToDate(*start)
+		if end == nil {
+			endDate = startDate.AddDate(1, 0, -1)
+		}
+	}
+	if end != nil {
+		endDate = truncateToDate(*end)
+	}
+	if err := models.ValidateCardPeriod(startDate, endDate); err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidCardPeriod
+	}
+	return startDate, endDate, nil
+}
+
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 func (s *CardService) Create(ctx context.Context, params models.CreateCardParams) (*models.BingoCard, error) {
 	// Validate category if provided
 	if params.Category != nil && *params.Category != "" {
@@ -60,6 +224,14 @@ func (s *CardService) Create(ctx context.Context, params models.CreateCardParams
 		return nil, ErrTitleTooLong
 	}
 
+	if params.Year == 0 && params.StartDate != nil {
+		params.Year = params.StartDate.Year()
+	}
+	startDate, endDate, err := resolveCardPeriod(params.Year, params.StartDate, params.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
 	if params.GridSize == 0 {
 		params.GridSize = models.MaxGridSize
 	}
@@ -110,21 +282,22 @@ func (s *CardService) Create(ctx context.Context, params models.CreateCardParams
 	}
 
 	card := &models.BingoCard{}
-	err := s.db.QueryRow(ctx,
-		`INSERT INTO bingo_cards (user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		 RETURNING id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position, is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at`,
-		params.UserID, params.Year, params.Category, params.Title, params.GridSize, params.Header, params.HasFree, freePos,
+	err = s.db.QueryRow(ctx,
+		`INSERT INTO bingo_cards (user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position, start_date, end_date)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		 RETURNING id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position, is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof`,
+		params.UserID, params.Year, params.Category, params.Title, params.GridSize, params.Header, params.HasFree, freePos, startDate, endDate,
 	).Scan(
 		&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 		&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.FreeSpaceText, &card.StartDate, &card.EndDate, &card.RequireProof,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("creating card: %w", err)
 	}
 
 	card.Items = []models.BingoItem{}
+	card.SyncFreeSpace()
 	return card, nil
 }
 
@@ -132,13 +305,13 @@ func (s *CardService) GetByID(ctx context.Context, cardID uuid.UUID) (*models.Bi
 	card := &models.BingoCard{}
 	err := s.db.QueryRow(ctx,
 		`SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof
 		 FROM bingo_cards WHERE id = $1`,
 		cardID,
 	).Scan(
 		&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 		&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.FreeSpaceText, &card.StartDate, &card.EndDate, &card.RequireProof,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrCardNotFound
@@ -152,6 +325,7 @@ func (s *CardService) GetByID(ctx context.Context, cardID uuid.UUID) (*models.Bi
 		return nil, err
 	}
 	card.Items = items
+	card.SyncFreeSpace()
 
 	return card, nil
 }
@@ -160,13 +334,13 @@ func (s *CardService) GetByUserAndYear(ctx context.Context, userID uuid.UUID, ye
 	card := &models.BingoCard{}
 	err := s.db.QueryRow(ctx,
 		`SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof
 		 FROM bingo_cards WHERE user_id = $1 AND year = $2`,
 		userID, year,
 	).Scan(
 		&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 		&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.FreeSpaceText, &card.StartDate, &card.EndDate, &card.RequireProof,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrCardNotFound
@@ -180,6 +354,7 @@ func (s *CardService) GetByUserAndYear(ctx context.Context, userID uuid.UUID, ye
 		return nil, err
 	}
 	card.Items = items
+	card.SyncFreeSpace()
 
 	return card, nil
 }
@@ -187,7 +362,7 @@ func (s *CardService) GetByUserAndYear(ctx context.Context, userID uuid.UUID, ye
 func (s *CardService) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof
 		 FROM bingo_cards WHERE user_id = $1 ORDER BY year DESC, created_at DESC`,
 		userID,
 	)
@@ -202,7 +377,7 @@ func (s *CardService) ListByUser(ctx context.Context, userID uuid.UUID) ([]*mode
 		if err := rows.Scan(
 			&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 			&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-			&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+			&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.FreeSpaceText, &card.StartDate, &card.EndDate, &card.RequireProof,
 		); err != nil {
 			return nil, fmt.Errorf("scanning card: %w", err)
 		}
@@ -216,6 +391,7 @@ func (s *CardService) ListByUser(ctx context.Context, userID uuid.UUID) ([]*mode
 			return nil, err
 		}
 		card.Items = items
+		card.SyncFreeSpace()
 	}
 
 	return cards, nil
@@ -224,7 +400,7 @@ func (s *CardService) ListByUser(ctx context.Context, userID uuid.UUID) ([]*mode
 func (s *CardService) AddItem(ctx context.Context, userID uuid.UUID, params models.AddItemParams) (*models.BingoItem, error) {
 	if params.Position == nil {
 		// Choose a random available position atomically (important for small grids + concurrent adds).
-		tx, err := s.db.Begin(ctx)
+		tx, ctx, err := beginTx(ctx, s.db)
 		if err != nil {
 			return nil, fmt.Errorf("starting transaction: %w", err)
 		}
@@ -244,7 +420,7 @@ func (s *CardService) AddItem(ctx context.Context, userID uuid.UUID, params mode
 		if err != nil {
 			return nil, fmt.Errorf("locking card: %w", err)
 		}
-		if card.UserID != userID {
+		if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 			return nil, ErrNotCardOwner
 		}
 		if card.IsFinalized {
@@ -291,11 +467,11 @@ func (s *CardService) AddItem(ctx context.Context, userID uuid.UUID, params mode
 
 		item := &models.BingoItem{}
 		err = tx.QueryRow(ctx,
-			`INSERT INTO bingo_items (card_id, position, content)
-			 VALUES ($1, $2, $3)
-			 RETURNING id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at`,
-			params.CardID, position, params.Content,
-		).Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.CreatedAt)
+			`INSERT INTO bingo_items (card_id, position, content, is_private, difficulty)
+			 VALUES ($1, $2, $3, $4, $5)
+			 RETURNING id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at, is_private, difficulty`,
+			params.CardID, position, params.Content, params.IsPrivate, params.Difficulty,
+		).Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.CreatedAt, &item.IsPrivate, &item.Difficulty)
 		if err != nil {
 			var pgErr *pgconn.PgError
 			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -305,8 +481,13 @@ func (s *CardService) AddItem(ctx context.Context, userID uuid.UUID, params mode
 		}
 
 		if err := tx.Commit(ctx); err != nil {
+			if isItemPositionViolation(err) {
+				return nil, ErrInvalidPosition
+			}
 			return nil, fmt.Errorf("committing transaction: %w", err)
 		}
+		s.recordSuggestionUsage(ctx, []string{item.Content})
+		item.ContentTruncated = contentTruncated(item.Content, card.GridSize)
 		return item, nil
 	}
 
@@ -315,7 +496,7 @@ func (s *CardService) AddItem(ctx context.Context, userID uuid.UUID, params mode
 	if err != nil {
 		return nil, err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return nil, ErrNotCardOwner
 	}
 	if card.IsFinalized {
@@ -337,19 +518,24 @@ func (s *CardService) AddItem(ctx context.Context, userID uuid.UUID, params mode
 
 	item := &models.BingoItem{}
 	err = s.db.QueryRow(ctx,
-		`INSERT INTO bingo_items (card_id, position, content)
-		 VALUES ($1, $2, $3)
-		 RETURNING id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at`,
-		params.CardID, position, params.Content,
-	).Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.CreatedAt)
+		`INSERT INTO bingo_items (card_id, position, content, is_private, difficulty)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at, is_private, difficulty`,
+		params.CardID, position, params.Content, params.IsPrivate, params.Difficulty,
+	).Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.CreatedAt, &item.IsPrivate, &item.Difficulty)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			return nil, ErrPositionOccupied
 		}
+		if isItemPositionViolation(err) {
+			return nil, ErrInvalidPosition
+		}
 		return nil, fmt.Errorf("adding item: %w", err)
 	}
 
+	s.recordSuggestionUsage(ctx, []string{item.Content})
+	item.ContentTruncated = contentTruncated(item.Content, card.GridSize)
 	return item, nil
 }
 
@@ -359,10 +545,13 @@ func (s *CardService) UpdateItem(ctx context.Context, userID, cardID uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return nil, ErrNotCardOwner
 	}
-	if card.IsFinalized {
+	// Privacy and difficulty can still be changed after finalizing; the
+	// layout cannot.
+	tagsOnly := (params.IsPrivate != nil || params.Difficulty != nil) && params.Content == nil && params.Position == nil
+	if card.IsFinalized && !tagsOnly {
 		return nil, ErrCardFinalized
 	}
 
@@ -390,6 +579,32 @@ func (s *CardService) UpdateItem(ctx context.Context, userID, cardID uuid.UUID,
 		item.Content = *params.Content
 	}
 
+	if params.IsPrivate != nil {
+		_, err = s.db.Exec(ctx,
+			"UPDATE bingo_items SET is_private = $1 WHERE id = $2",
+			*params.IsPrivate, item.ID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("updating item privacy: %w", err)
+		}
+		item.IsPrivate = *params.IsPrivate
+	}
+
+	if params.Difficulty != nil {
+		var difficulty *string
+		if *params.Difficulty != "" {
+			difficulty = params.Difficulty
+		}
+		_, err = s.db.Exec(ctx,
+			"UPDATE bingo_items SET difficulty = $1 WHERE id = $2",
+			difficulty, item.ID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("updating item difficulty: %w", err)
+		}
+		item.Difficulty = difficulty
+	}
+
 	// Update position if provided
 	if params.Position != nil {
 		newPos := *params.Position
@@ -406,12 +621,17 @@ func (s *CardService) UpdateItem(ctx context.Context, userID, cardID uuid.UUID,
 			"UPDATE bingo_items SET position = $1 WHERE id = $2",
 			newPos, item.ID,
 		)
+		if isItemPositionViolation(err) {
+			return nil, ErrInvalidPosition
+		}
 		if err != nil {
 			return nil, fmt.Errorf("updating item position: %w", err)
 		}
 		item.Position = newPos
 	}
 
+	s.invalidateShareCache(ctx, cardID)
+	item.ContentTruncated = contentTruncated(item.Content, card.GridSize)
 	return item, nil
 }
 
@@ -421,7 +641,7 @@ func (s *CardService) SwapItems(ctx context.Context, userID, cardID uuid.UUID, p
 	if err != nil {
 		return err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return ErrNotCardOwner
 	}
 	if card.IsFinalized {
@@ -461,7 +681,7 @@ func (s *CardService) SwapItems(ctx context.Context, userID, cardID uuid.UUID, p
 	}
 
 	// Use a transaction to swap atomically
-	tx, err := s.db.Begin(ctx)
+	tx, ctx, err := beginTx(ctx, s.db)
 	if err != nil {
 		return fmt.Errorf("starting transaction: %w", err)
 	}
@@ -502,6 +722,9 @@ func (s *CardService) SwapItems(ctx context.Context, userID, cardID uuid.UUID, p
 	}
 
 	if err = tx.Commit(ctx); err != nil {
+		if isItemPositionViolation(err) {
+			return ErrInvalidPosition
+		}
 		return fmt.Errorf("committing transaction: %w", err)
 	}
 
@@ -556,7 +779,7 @@ func (s *CardService) moveFreeSpace(ctx context.Context, card *models.BingoCard,
 		return ErrNoSpaceForFree
 	}
 
-	tx, err := s.db.Begin(ctx)
+	tx, ctx, err := beginTx(ctx, s.db)
 	if err != nil {
 		return fmt.Errorf("starting transaction: %w", err)
 	}
@@ -576,6 +799,9 @@ func (s *CardService) moveFreeSpace(ctx context.Context, card *models.BingoCard,
 	}
 
 	if err := tx.Commit(ctx); err != nil {
+		if isItemPositionViolation(err) {
+			return ErrInvalidPosition
+		}
 		return fmt.Errorf("committing transaction: %w", err)
 	}
 	return nil
@@ -587,7 +813,7 @@ func (s *CardService) RemoveItem(ctx context.Context, userID, cardID uuid.UUID,
 	if err != nil {
 		return err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return ErrNotCardOwner
 	}
 	if card.IsFinalized {
@@ -614,7 +840,7 @@ func (s *CardService) Delete(ctx context.Context, userID, cardID uuid.UUID) erro
 	if err != nil {
 		return err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return ErrNotCardOwner
 	}
 
@@ -632,6 +858,10 @@ func (s *CardService) Delete(ctx context.Context, userID, cardID uuid.UUID) erro
 	if result.RowsAffected() == 0 {
 		return ErrCardNotFound
 	}
+	s.invalidateShareCache(ctx, cardID)
+	for _, item := range card.Items {
+		s.deleteStoredProof(ctx, card.UserID, item.ID, item.ProofURL)
+	}
 
 	return nil
 }
@@ -643,7 +873,7 @@ func (s *CardService) UpdateMeta(ctx context.Context, userID, cardID uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return nil, ErrNotCardOwner
 	}
 
@@ -683,79 +913,13 @@ func (s *CardService) UpdateMeta(ctx context.Context, userID, cardID uuid.UUID,
 		if err != nil {
 			return nil, fmt.Errorf("updating card meta: %w", err)
 		}
+		s.invalidateShareCache(ctx, cardID)
 	}
 
 	// Return updated card
 	return s.GetByID(ctx, cardID)
 }
 
-func (s *CardService) Shuffle(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
-	// Get and verify card ownership
-	card, err := s.GetByID(ctx, cardID)
-	if err != nil {
-		return nil, err
-	}
-	if card.UserID != userID {
-		return nil, ErrNotCardOwner
-	}
-	if card.IsFinalized {
-		return nil, ErrCardFinalized
-	}
-
-	if len(card.Items) == 0 {
-		return card, nil
-	}
-
-	// Get all available positions (excluding FREE if enabled)
-	availablePositions := make([]int, 0, card.TotalSquares())
-	for i := 0; i < card.TotalSquares(); i++ {
-		if !card.IsFreeSpacePosition(i) {
-			availablePositions = append(availablePositions, i)
-		}
-	}
-
-	// Shuffle positions
-	rand.Shuffle(len(availablePositions), func(i, j int) {
-		availablePositions[i], availablePositions[j] = availablePositions[j], availablePositions[i]
-	})
-
-	// Update items with new positions
-	tx, err := s.db.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("beginning transaction: %w", err)
-	}
-	defer func() { _ = tx.Rollback(ctx) }()
-
-	// First, set all positions to negative to avoid unique constraint violations
-	for i, item := range card.Items {
-		_, err = tx.Exec(ctx,
-			"UPDATE bingo_items SET position = $1 WHERE id = $2",
-			-(i + 1), item.ID,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("clearing position: %w", err)
-		}
-	}
-
-	// Then assign new positions
-	for i, item := range card.Items {
-		_, err = tx.Exec(ctx,
-			"UPDATE bingo_items SET position = $1 WHERE id = $2",
-			availablePositions[i], item.ID,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("assigning new position: %w", err)
-		}
-	}
-
-	if err = tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("committing transaction: %w", err)
-	}
-
-	// Reload card with updated positions
-	return s.GetByID(ctx, cardID)
-}
-
 // FinalizeParams contains optional parameters for finalizing a card
 type FinalizeParams struct {
 	VisibleToFriends *bool // Optional; if nil, keeps current value (default true for new cards)
@@ -767,7 +931,7 @@ func (s *CardService) Finalize(ctx context.Context, userID, cardID uuid.UUID, pa
 	if err != nil {
 		return nil, err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return nil, ErrNotCardOwner
 	}
 	if card.IsFinalized {
@@ -785,7 +949,11 @@ func (s *CardService) Finalize(ctx context.Context, userID, cardID uuid.UUID, pa
 		visibleToFriends = *params.VisibleToFriends
 	}
 
-	_, err = s.db.Exec(ctx,
+	var notify func(tx Tx) []uuid.UUID
+	if visibleToFriends {
+		notify = func(tx Tx) []uuid.UUID { return s.notifyFriendsNewCard(ctx, tx, userID, cardID) }
+	}
+	err = s.execNotifying(ctx, notify,
 		"UPDATE bingo_cards SET is_finalized = true, visible_to_friends = $2 WHERE id = $1",
 		cardID, visibleToFriends,
 	)
@@ -795,9 +963,12 @@ func (s *CardService) Finalize(ctx context.Context, userID, cardID uuid.UUID, pa
 
 	card.IsFinalized = true
 	card.VisibleToFriends = visibleToFriends
-	if card.VisibleToFriends {
-		s.notifyFriendsNewCard(ctx, userID, cardID)
-	}
+	s.enqueueWebhook(ctx, userID, models.WebhookEventCardFinalized, models.WebhookCardFinalized{
+		CardID:   cardID,
+		Year:     card.Year,
+		Title:    card.Title,
+		GridSize: card.GridSize,
+	})
 	return card, nil
 }
 
@@ -808,12 +979,15 @@ func (s *CardService) UpdateVisibility(ctx context.Context, userID, cardID uuid.
 	if err != nil {
 		return nil, err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return nil, ErrNotCardOwner
 	}
 
-	wasVisible := card.VisibleToFriends
-	_, err = s.db.Exec(ctx,
+	var notify func(tx Tx) []uuid.UUID
+	if card.IsFinalized && !card.VisibleToFriends && visibleToFriends {
+		notify = func(tx Tx) []uuid.UUID { return s.notifyFriendsNewCard(ctx, tx, userID, cardID) }
+	}
+	err = s.execNotifying(ctx, notify,
 		"UPDATE bingo_cards SET visible_to_friends = $2, updated_at = NOW() WHERE id = $1",
 		cardID, visibleToFriends,
 	)
@@ -822,63 +996,105 @@ func (s *CardService) UpdateVisibility(ctx context.Context, userID, cardID uuid.
 	}
 
 	card.VisibleToFriends = visibleToFriends
-	if card.IsFinalized && !wasVisible && visibleToFriends {
-		s.notifyFriendsNewCard(ctx, userID, cardID)
-	}
 	return card, nil
 }
 
-// BulkUpdateVisibility updates the visibility of multiple cards owned by the user
-// Returns the count of cards updated (cards not owned by user are silently skipped)
-func (s *CardService) BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error) {
-	if len(cardIDs) == 0 {
-		return 0, nil
+// BulkUpdateVisibility applies per-card visibility values in one transaction.
+// Every card must exist and belong to the user; otherwise nothing is changed
+// and a *CardsNotOwnedError lists the offending IDs.
+func (s *CardService) BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, changes []models.CardVisibilityChange) ([]models.CardVisibilityResult, error) {
+	if len(changes) == 0 {
+		return nil, nil
 	}
 
-	var notifyCardIDs []uuid.UUID
-	if visibleToFriends {
-		rows, err := s.db.Query(ctx,
-			`SELECT id FROM bingo_cards
-			 WHERE id = ANY($1) AND user_id = $2 AND is_finalized = true AND visible_to_friends = false`,
-			cardIDs, userID,
-		)
-		if err != nil {
-			return 0, fmt.Errorf("fetching visibility changes: %w", err)
-		}
-		for rows.Next() {
-			var id uuid.UUID
-			if err := rows.Scan(&id); err != nil {
-				rows.Close()
-				return 0, fmt.Errorf("scanning visibility changes: %w", err)
-			}
-			notifyCardIDs = append(notifyCardIDs, id)
-		}
-		rows.Close()
+	cardIDs := make([]uuid.UUID, len(changes))
+	for i, change := range changes {
+		cardIDs[i] = change.CardID
 	}
 
-	result, err := s.db.Exec(ctx,
-		`UPDATE bingo_cards SET visible_to_friends = $1, updated_at = NOW()
-		 WHERE id = ANY($2) AND user_id = $3`,
-		visibleToFriends, cardIDs, userID,
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	type cardState struct {
+		visible   bool
+		finalized bool
+	}
+	current := make(map[uuid.UUID]cardState, len(changes))
+	rows, err := tx.Query(ctx,
+		`SELECT id, visible_to_friends, is_finalized FROM bingo_cards
+		 WHERE id = ANY($1) AND user_id = $2
+		 FOR UPDATE`,
+		cardIDs, userID,
 	)
 	if err != nil {
-		return 0, fmt.Errorf("bulk updating visibility: %w", err)
+		return nil, fmt.Errorf("locking cards: %w", err)
+	}
+	for rows.Next() {
+		var (
+			id    uuid.UUID
+			state cardState
+		)
+		if err := rows.Scan(&id, &state.visible, &state.finalized); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning card: %w", err)
+		}
+		current[id] = state
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating cards: %w", err)
 	}
 
-	if visibleToFriends {
-		for _, cardID := range notifyCardIDs {
-			s.notifyFriendsNewCard(ctx, userID, cardID)
+	var missing []uuid.UUID
+	for _, id := range cardIDs {
+		if _, ok := current[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &CardsNotOwnedError{CardIDs: missing}
+	}
+
+	results := make([]models.CardVisibilityResult, 0, len(changes))
+	var emailIDs []uuid.UUID
+	for _, change := range changes {
+		state := current[change.CardID]
+		changed := state.visible != change.VisibleToFriends
+		if changed {
+			if _, err := tx.Exec(ctx,
+				"UPDATE bingo_cards SET visible_to_friends = $2, updated_at = NOW() WHERE id = $1",
+				change.CardID, change.VisibleToFriends,
+			); err != nil {
+				return nil, fmt.Errorf("updating visibility: %w", err)
+			}
+			if state.finalized && change.VisibleToFriends {
+				emailIDs = append(emailIDs, s.notifyFriendsNewCard(ctx, tx, userID, change.CardID)...)
+			}
 		}
+		results = append(results, models.CardVisibilityResult{
+			CardID:           change.CardID,
+			VisibleToFriends: change.VisibleToFriends,
+			Changed:          changed,
+		})
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
 	}
 
-	return int(result.RowsAffected()), nil
+	s.dispatchNotificationEmails(emailIDs)
+
+	return results, nil
 }
 
-// BulkDelete deletes multiple cards owned by the user
-// Returns the count of cards deleted (cards not owned by user are silently skipped)
-func (s *CardService) BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error) {
+// BulkDelete deletes multiple cards owned by the user and returns the IDs it
+// deleted. Cards the user doesn't own are silently skipped.
+func (s *CardService) BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) ([]uuid.UUID, error) {
 	if len(cardIDs) == 0 {
-		return 0, nil
+		return nil, nil
 	}
 
 	// First delete items for these cards (owned by user)
@@ -889,38 +1105,63 @@ func (s *CardService) BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs
 		cardIDs, userID,
 	)
 	if err != nil {
-		return 0, fmt.Errorf("bulk deleting card items: %w", err)
+		return nil, fmt.Errorf("bulk deleting card items: %w", err)
 	}
 
 	// Then delete the cards
-	result, err := s.db.Exec(ctx,
-		`DELETE FROM bingo_cards WHERE id = ANY($1) AND user_id = $2`,
+	rows, err := s.db.Query(ctx,
+		`DELETE FROM bingo_cards WHERE id = ANY($1) AND user_id = $2 RETURNING id`,
 		cardIDs, userID,
 	)
 	if err != nil {
-		return 0, fmt.Errorf("bulk deleting cards: %w", err)
+		return nil, fmt.Errorf("bulk deleting cards: %w", err)
+	}
+	deleted, err := scanCardIDs(rows)
+	if err != nil {
+		return nil, fmt.Errorf("bulk deleting cards: %w", err)
 	}
+	s.invalidateShareCache(ctx, deleted...)
 
-	return int(result.RowsAffected()), nil
+	return deleted, nil
 }
 
-// BulkUpdateArchive updates the archive status of multiple cards owned by the user
-// Returns the count of cards updated (cards not owned by user are silently skipped)
-func (s *CardService) BulkUpdateArchive(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) (int, error) {
+// BulkUpdateArchive updates the archive status of multiple cards owned by the
+// user and returns the IDs it updated. Cards the user doesn't own are silently
+// skipped.
+func (s *CardService) BulkUpdateArchive(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) ([]uuid.UUID, error) {
 	if len(cardIDs) == 0 {
-		return 0, nil
+		return nil, nil
 	}
 
-	result, err := s.db.Exec(ctx,
+	rows, err := s.db.Query(ctx,
 		`UPDATE bingo_cards SET is_archived = $1, updated_at = NOW()
-		 WHERE id = ANY($2) AND user_id = $3`,
+		 WHERE id = ANY($2) AND user_id = $3
+		 RETURNING id`,
 		isArchived, cardIDs, userID,
 	)
 	if err != nil {
-		return 0, fmt.Errorf("bulk updating archive status: %w", err)
+		return nil, fmt.Errorf("bulk updating archive status: %w", err)
+	}
+	updated, err := scanCardIDs(rows)
+	if err != nil {
+		return nil, fmt.Errorf("bulk updating archive status: %w", err)
 	}
 
-	return int(result.RowsAffected()), nil
+	return updated, nil
+}
+
+// scanCardIDs reads a single id column and closes rows.
+func scanCardIDs(rows Rows) ([]uuid.UUID, error) {
+	defer rows.Close()
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
 }
 
 func (s *CardService) CompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error) {
@@ -929,12 +1170,15 @@ func (s *CardService) CompleteItem(ctx context.Context, userID, cardID uuid.UUID
 	if err != nil {
 		return nil, err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return nil, ErrNotCardOwner
 	}
 	if !card.IsFinalized {
 		return nil, ErrCardNotFinalized
 	}
+	if card.RequireProof && !params.HasProof() {
+		return nil, ErrProofRequired
+	}
 
 	// Find the item
 	var item *models.BingoItem
@@ -949,23 +1193,99 @@ func (s *CardService) CompleteItem(ctx context.Context, userID, cardID uuid.UUID
 		return nil, ErrItemNotFound
 	}
 
-	now := time.Now()
-	_, err = s.db.Exec(ctx,
-		`UPDATE bingo_items
-		 SET is_completed = true, completed_at = $1, notes = $2, proof_url = $3
-		 WHERE id = $4`,
-		now, params.Notes, params.ProofURL, item.ID,
-	)
+	return s.markItemComplete(ctx, card, item, params)
+}
+
+// CompleteItemByContent completes the item whose content matches content,
+// for clients that don't track positions. Content is compared normalized
+// (case, surrounding and repeated whitespace ignored). Unless exact is set, a
+// goal containing content also matches when no goal equals it. Completing an
+// already completed item returns it unchanged, or ErrItemCompleted when the
+// request carries different notes or proof.
+func (s *CardService) CompleteItemByContent(ctx context.Context, userID, cardID uuid.UUID, content string, exact bool, params models.CompleteItemParams) (*models.BingoItem, error) {
+	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
-		return nil, fmt.Errorf("completing item: %w", err)
+		return nil, err
+	}
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
+		return nil, ErrNotCardOwner
+	}
+	if !card.IsFinalized {
+		return nil, ErrCardNotFinalized
 	}
 
-	item.IsCompleted = true
-	item.CompletedAt = &now
-	item.Notes = params.Notes
-	item.ProofURL = params.ProofURL
+	item, err := findItemByContent(card.Items, content, exact)
+	if err != nil {
+		return nil, err
+	}
+	if item.IsCompleted {
+		return completedItem(item, params)
+	}
+	if card.RequireProof && !params.HasProof() {
+		return nil, ErrProofRequired
+	}
 
-	if card.VisibleToFriends {
+	return s.markItemComplete(ctx, card, item, params)
+}
+
+// findItemByContent resolves content to a single item, returning
+// ErrItemNotFound or an *ItemAmbiguousError otherwise.
+func findItemByContent(items []models.BingoItem, content string, exact bool) (*models.BingoItem, error) {
+	want := normalizeItemContent(content)
+	if want == "" {
+		return nil, ErrItemNotFound
+	}
+
+	var equal, partial []models.BingoItem
+	for _, item := range items {
+		got := normalizeItemContent(item.Content)
+		if got == want {
+			equal = append(equal, item)
+		} else if !exact && strings.Contains(got, want) {
+			partial = append(partial, item)
+		}
+	}
+
+	matches := equal
+	if len(matches) == 0 {
+		matches = partial
+	}
+	switch len(matches) {
+	case 0:
+		return nil, ErrItemNotFound
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, &ItemAmbiguousError{Candidates: matches}
+	}
+}
+
+// markItemComplete writes the completion for item on card, notifying friends
+// in the same transaction when it makes a bingo on a friends-visible card.
+// Webhooks get the completion, and any new lines it finishes, after the
+// write commits. The write only applies to an incomplete item, so when the
+// item is already completed, or a concurrent request got there first, this
+// returns the item as stored and nothing is sent twice; see completedItem.
+// An uploaded proof photo is stored first and discarded again if the
+// completion isn't written.
+func (s *CardService) markItemComplete(ctx context.Context, card *models.BingoCard, item *models.BingoItem, params models.CompleteItemParams) (*models.BingoItem, error) {
+	userID, cardID, position := card.UserID, card.ID, item.Position
+	now := time.Now()
+	if item.IsCompleted {
+		return completedItem(item, params)
+	}
+
+	if len(params.ProofImage) > 0 {
+		proofURL, err := s.storeProofImage(ctx, userID, item.ID, params.ProofImage)
+		if err != nil {
+			return nil, err
+		}
+		params.ProofURL = &proofURL
+	}
+
+	var notify func(tx Tx) []uuid.UUID
+	var bingosBefore, bingos int
+	if card.VisibleToFriends || s.webhooks != nil {
 		updatedItems := make([]models.BingoItem, len(card.Items))
 		copy(updatedItems, card.Items)
 		for i := range updatedItems {
@@ -981,22 +1301,89 @@ func (s *CardService) CompleteItem(ctx context.Context, userID, cardID uuid.UUID
 		if card.HasFreePositionSet() {
 			freePos = card.FreeSpacePos
 		}
-		bingos := s.countBingos(updatedItems, card.GridSize, freePos)
-		if bingos > 0 {
-			s.notifyFriendsBingo(ctx, userID, cardID, bingos)
-		}
+		bingosBefore = s.countBingos(card.Items, card.GridSize, freePos)
+		bingos = s.countBingos(updatedItems, card.GridSize, freePos)
+	}
+	if card.VisibleToFriends && bingos > 0 {
+		notify = func(tx Tx) []uuid.UUID { return s.notifyFriendsBingo(ctx, tx, userID, cardID, bingos) }
 	}
 
-	return item, nil
-}
-
-func (s *CardService) UncompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error) {
-	// Get and verify card ownership
-	card, err := s.GetByID(ctx, cardID)
+	changed, err := s.claimNotifying(ctx, notify,
+		`UPDATE bingo_items
+		 SET is_completed = true, completed_at = $1, notes = $2, proof_url = $3
+		 WHERE id = $4 AND is_completed = false`,
+		now, params.Notes, params.ProofURL, item.ID,
+	)
+	if err != nil || !changed {
+		if len(params.ProofImage) > 0 {
+			s.deleteStoredProof(ctx, userID, item.ID, params.ProofURL)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("completing item: %w", err)
+	}
+	if !changed {
+		stored, err := s.reloadItemState(ctx, item)
+		if err != nil || !stored.IsCompleted {
+			return stored, err
+		}
+		return completedItem(stored, params)
+	}
+	s.invalidateShareCache(ctx, cardID)
+
+	item.IsCompleted = true
+	item.CompletedAt = &now
+	item.Notes = params.Notes
+	item.ProofURL = params.ProofURL
+
+	s.enqueueWebhook(ctx, userID, models.WebhookEventItemCompleted, models.WebhookItemCompleted{
+		CardID:      cardID,
+		ItemID:      item.ID,
+		Position:    position,
+		Content:     item.Content,
+		CompletedAt: now,
+	})
+	if bingos > bingosBefore {
+		s.enqueueWebhook(ctx, userID, models.WebhookEventBingo, models.WebhookBingo{
+			CardID:     cardID,
+			ItemID:     item.ID,
+			BingoCount: bingos,
+			NewLines:   bingos - bingosBefore,
+		})
+	}
+	// Friends-visible cards are the ones a public profile shows. A completion
+	// that makes a bingo is published once, as the bingo.
+	if card.VisibleToFriends {
+		kind := models.ActivityKindItemCompleted
+		if bingos > bingosBefore {
+			kind = models.ActivityKindBingo
+		}
+		s.recordActivity(ctx, userID, cardID, kind, bingos)
+	}
+	return item, nil
+}
+
+// completedItem answers a completion request for an item that is already
+// completed. Repeating the completion returns the item, but completing never
+// overwrites notes or proof, so a request that would change them, including
+// any proof photo, fails with ErrItemCompleted rather than being dropped.
+// UpdateItemNotes edits a completed item.
+func completedItem(item *models.BingoItem, params models.CompleteItemParams) (*models.BingoItem, error) {
+	if len(params.ProofImage) > 0 ||
+		derefString(params.Notes) != derefString(item.Notes) ||
+		derefString(params.ProofURL) != derefString(item.ProofURL) {
+		return nil, ErrItemCompleted
+	}
+	return item, nil
+}
+
+func (s *CardService) UncompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error) {
+	// Get and verify card ownership
+	card, err := s.GetByID(ctx, cardID)
 	if err != nil {
 		return nil, err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return nil, ErrNotCardOwner
 	}
 	if !card.IsFinalized {
@@ -1016,18 +1403,31 @@ func (s *CardService) UncompleteItem(ctx context.Context, userID, cardID uuid.UU
 		return nil, ErrItemNotFound
 	}
 
-	_, err = s.db.Exec(ctx,
+	// Like completion, only a completed item is written, so a repeated
+	// request returns the item as stored. An uploaded proof photo goes with
+	// the completion; a proof link is kept.
+	proofURL := item.ProofURL
+	if _, ok := storedProofKey(card.UserID, item.ID, proofURL); ok {
+		proofURL = nil
+	}
+	result, err := s.db.Exec(ctx,
 		`UPDATE bingo_items
-		 SET is_completed = false, completed_at = NULL
-		 WHERE id = $1`,
-		item.ID,
+		 SET is_completed = false, completed_at = NULL, proof_url = $2
+		 WHERE id = $1 AND is_completed = true`,
+		item.ID, proofURL,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("uncompleting item: %w", err)
 	}
+	if result.RowsAffected() == 0 {
+		return s.reloadItemState(ctx, item)
+	}
+	s.invalidateShareCache(ctx, cardID)
+	s.deleteStoredProof(ctx, card.UserID, item.ID, item.ProofURL)
 
 	item.IsCompleted = false
 	item.CompletedAt = nil
+	item.ProofURL = proofURL
 
 	return item, nil
 }
@@ -1038,7 +1438,7 @@ func (s *CardService) UpdateItemNotes(ctx context.Context, userID, cardID uuid.U
 	if err != nil {
 		return nil, err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return nil, ErrNotCardOwner
 	}
 
@@ -1062,6 +1462,9 @@ func (s *CardService) UpdateItemNotes(ctx context.Context, userID, cardID uuid.U
 	if err != nil {
 		return nil, fmt.Errorf("updating notes: %w", err)
 	}
+	if item.ProofURL != nil && (proofURL == nil || *proofURL != *item.ProofURL) {
+		s.deleteStoredProof(ctx, card.UserID, item.ID, item.ProofURL)
+	}
 
 	item.Notes = notes
 	item.ProofURL = proofURL
@@ -1071,7 +1474,7 @@ func (s *CardService) UpdateItemNotes(ctx context.Context, userID, cardID uuid.U
 
 func (s *CardService) getCardItems(ctx context.Context, cardID uuid.UUID) ([]models.BingoItem, error) {
 	rows, err := s.db.Query(ctx,
-		`SELECT id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at
+		`SELECT id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at, is_private, difficulty
 		 FROM bingo_items WHERE card_id = $1 ORDER BY position`,
 		cardID,
 	)
@@ -1083,7 +1486,7 @@ func (s *CardService) getCardItems(ctx context.Context, cardID uuid.UUID) ([]mod
 	var items []models.BingoItem
 	for rows.Next() {
 		var item models.BingoItem
-		if err := rows.Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.CreatedAt); err != nil {
+		if err := rows.Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.CreatedAt, &item.IsPrivate, &item.Difficulty); err != nil {
 			return nil, fmt.Errorf("scanning item: %w", err)
 		}
 		items = append(items, item)
@@ -1119,17 +1522,73 @@ func (s *CardService) findRandomPosition(card *models.BingoCard) (int, error) {
 	return available[rand.Intn(len(available))], nil
 }
 
-// GetArchive returns all finalized cards from past years (not current year)
+const (
+	// memoriesMaxYears is how many earlier years GetMemories looks back.
+	memoriesMaxYears = 10
+	// memoriesWindowDays is how far either side of today's date counts as "on this day".
+	memoriesWindowDays = 7
+	memoriesLimit      = 20
+)
+
+// GetMemories returns goals the user completed within a week of today's date
+// (in loc) in each of the last memoriesMaxYears years, newest year first,
+// across all of their cards including archived ones.
+func (s *CardService) GetMemories(ctx context.Context, userID uuid.UUID, now time.Time, loc *time.Location) ([]models.Memory, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	starts := make([]time.Time, memoriesMaxYears)
+	ends := make([]time.Time, memoriesMaxYears)
+	for i := range starts {
+		day := time.Date(local.Year()-(i+1), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		starts[i] = day.AddDate(0, 0, -memoriesWindowDays)
+		ends[i] = day.AddDate(0, 0, memoriesWindowDays+1)
+	}
+
+	// One range scan per year window; WITH ORDINALITY numbers the windows so
+	// years_ago is 1 for last year.
+	rows, err := s.db.Query(ctx,
+		`SELECT bi.id, c.id, c.title, c.year, c.is_archived, bi.content, bi.completed_at, w.years_ago
+		 FROM bingo_cards c
+		 CROSS JOIN unnest($2::timestamptz[], $3::timestamptz[]) WITH ORDINALITY AS w(start_at, end_at, years_ago)
+		 JOIN bingo_items bi ON bi.card_id = c.id
+		   AND bi.completed_at >= w.start_at AND bi.completed_at < w.end_at
+		 WHERE c.user_id = $1 AND bi.is_completed = true
+		 ORDER BY w.years_ago, bi.completed_at
+		 LIMIT $4`,
+		userID, starts, ends, memoriesLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting memories: %w", err)
+	}
+	defer rows.Close()
+
+	memories := []models.Memory{}
+	for rows.Next() {
+		var m models.Memory
+		if err := rows.Scan(&m.ItemID, &m.CardID, &m.CardTitle, &m.CardYear, &m.IsArchived, &m.Content, &m.CompletedAt, &m.YearsAgo); err != nil {
+			return nil, fmt.Errorf("scanning memory: %w", err)
+		}
+		memories = append(memories, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating memories: %w", err)
+	}
+	return memories, nil
+}
+
+// GetArchive returns all finalized cards whose period has ended
 func (s *CardService) GetArchive(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error) {
-	currentYear := time.Now().Year()
+	today := truncateToDate(time.Now())
 
 	rows, err := s.db.Query(ctx,
 		`SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		        is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof
 		 FROM bingo_cards
-		 WHERE user_id = $1 AND year < $2 AND is_finalized = true
+		 WHERE user_id = $1 AND end_date < $2 AND is_finalized = true
 		 ORDER BY year DESC, created_at DESC`,
-		userID, currentYear,
+		userID, today,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("listing archive cards: %w", err)
@@ -1142,7 +1601,7 @@ func (s *CardService) GetArchive(ctx context.Context, userID uuid.UUID) ([]*mode
 		if err := rows.Scan(
 			&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 			&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-			&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+			&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.FreeSpaceText, &card.StartDate, &card.EndDate, &card.RequireProof,
 		); err != nil {
 			return nil, fmt.Errorf("scanning card: %w", err)
 		}
@@ -1156,6 +1615,7 @@ func (s *CardService) GetArchive(ctx context.Context, userID uuid.UUID) ([]*mode
 			return nil, err
 		}
 		card.Items = items
+		card.SyncFreeSpace()
 	}
 
 	return cards, nil
@@ -1168,14 +1628,20 @@ func (s *CardService) GetStats(ctx context.Context, userID, cardID uuid.UUID) (*
 	if err != nil {
 		return nil, err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return nil, ErrNotCardOwner
 	}
 
+	now := time.Now()
+	startDate, endDate := card.Period()
 	stats := &models.CardStats{
-		CardID:     card.ID,
-		Year:       card.Year,
-		TotalItems: card.Capacity(),
+		CardID:        card.ID,
+		Year:          card.Year,
+		TotalItems:    card.Capacity(),
+		StartDate:     startDate,
+		EndDate:       endDate,
+		DaysRemaining: card.DaysRemaining(now),
+		IsOverdue:     card.IsOverdue(now),
 	}
 
 	// Count completed items and find first/last completion
@@ -1201,6 +1667,7 @@ func (s *CardService) GetStats(ctx context.Context, userID, cardID uuid.UUID) (*
 	if stats.TotalItems > 0 {
 		stats.CompletionRate = float64(stats.CompletedItems) / float64(stats.TotalItems) * 100
 	}
+	stats.WeightedCompletionRate = card.WeightedCompletionRate(s.difficultyWeights)
 
 	// Count bingos achieved
 	stats.BingosAchieved = s.countBingos(card.Items, card.GridSize, func() *int {
@@ -1213,79 +1680,113 @@ func (s *CardService) GetStats(ctx context.Context, userID, cardID uuid.UUID) (*
 	return stats, nil
 }
 
-// countBingos counts how many bingos (rows, columns, diagonals) are complete
-func (s *CardService) countBingos(items []models.BingoItem, gridSize int, freePos *int) int {
-	if !models.IsValidGridSize(gridSize) {
-		gridSize = models.MaxGridSize
-	}
-	total := gridSize * gridSize
-	grid := make([]bool, total)
-
-	if freePos != nil && *freePos >= 0 && *freePos < total {
-		grid[*freePos] = true
+// ListProgress summarizes every card the user owns for the dashboard, newest
+// first, in one query: each card is joined to its completed goals and the
+// bingo count comes from the same line logic as GetStats. Archived cards are
+// left out unless includeArchived is set.
+func (s *CardService) ListProgress(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]models.CardProgress, error) {
+	query := `SELECT c.id, c.year, c.title, c.grid_size, c.has_free_space, c.free_space_position,
+	                 c.is_finalized, c.is_archived, i.position, i.completed_at
+	          FROM bingo_cards c
+	          LEFT JOIN bingo_items i ON i.card_id = c.id AND i.is_completed = true
+	          WHERE c.user_id = $1`
+	if !includeArchived {
+		query += " AND c.is_archived = false"
+	}
+	query += " ORDER BY c.year DESC, c.created_at DESC, c.id"
+
+	rows, err := s.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing card progress: %w", err)
 	}
+	defer rows.Close()
 
-	// Mark completed items
-	for _, item := range items {
-		if item.IsCompleted {
-			grid[item.Position] = true
+	progress := []models.CardProgress{}
+	var cards []*models.BingoCard
+	for rows.Next() {
+		var card models.BingoCard
+		var position *int
+		var completedAt *time.Time
+		if err := rows.Scan(
+			&card.ID, &card.Year, &card.Title, &card.GridSize, &card.HasFreeSpace, &card.FreeSpacePos,
+			&card.IsFinalized, &card.IsArchived, &position, &completedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning card progress: %w", err)
 		}
-	}
-
-	bingos := 0
-
-	// Check rows
-	for row := 0; row < gridSize; row++ {
-		complete := true
-		for col := 0; col < gridSize; col++ {
-			if !grid[row*gridSize+col] {
-				complete = false
-				break
-			}
+		if len(cards) == 0 || cards[len(cards)-1].ID != card.ID {
+			cards = append(cards, &card)
 		}
-		if complete {
-			bingos++
+		current := cards[len(cards)-1]
+		if position != nil {
+			current.Items = append(current.Items, models.BingoItem{Position: *position, IsCompleted: true, CompletedAt: completedAt})
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating card progress: %w", err)
+	}
 
-	// Check columns
-	for col := 0; col < gridSize; col++ {
-		complete := true
-		for row := 0; row < gridSize; row++ {
-			if !grid[row*gridSize+col] {
-				complete = false
-				break
-			}
+	for _, card := range cards {
+		p := models.CardProgress{
+			CardID:         card.ID,
+			Year:           card.Year,
+			Title:          card.Title,
+			GridSize:       card.GridSize,
+			IsFinalized:    card.IsFinalized,
+			IsArchived:     card.IsArchived,
+			CompletedItems: len(card.Items),
+			TotalItems:     card.Capacity(),
 		}
-		if complete {
-			bingos++
+		var freePos *int
+		if card.HasFreeSpace {
+			freePos = card.FreeSpacePos
 		}
+		p.BingosAchieved = s.countBingos(card.Items, card.GridSize, freePos)
+		for _, item := range card.Items {
+			if item.CompletedAt != nil && (p.LastCompletion == nil || item.CompletedAt.After(*p.LastCompletion)) {
+				p.LastCompletion = item.CompletedAt
+			}
+		}
+		progress = append(progress, p)
 	}
+	return progress, nil
+}
 
-	// Check diagonals
-	complete := true
-	for i := 0; i < gridSize; i++ {
-		if !grid[i*gridSize+i] {
-			complete = false
-			break
-		}
+// MaxCardRecommendations caps how many next goals GetRecommendations returns.
+const MaxCardRecommendations = 10
+
+// GetRecommendations returns up to limit open goals that bring the card
+// closest to its next bingo, using the same ranking as reminder emails.
+func (s *CardService) GetRecommendations(ctx context.Context, userID, cardID uuid.UUID, limit int) ([]models.CardRecommendation, error) {
+	card, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
 	}
-	if complete {
-		bingos++
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
+		return nil, ErrNotCardOwner
+	}
+	if limit <= 0 || limit > MaxCardRecommendations {
+		limit = 3
 	}
 
-	complete = true
-	for i := 0; i < gridSize; i++ {
-		if !grid[i*gridSize+(gridSize-1-i)] {
-			complete = false
-			break
-		}
+	var freePos *int
+	if card.HasFreeSpace {
+		freePos = card.FreeSpacePos
 	}
-	if complete {
-		bingos++
+	recs := bingo.Recommend(card.Items, card.GridSize, freePos, limit)
+	result := make([]models.CardRecommendation, 0, len(recs))
+	for _, rec := range recs {
+		lines := make([]string, 0, len(rec.Lines))
+		for _, line := range rec.Lines {
+			lines = append(lines, line.Label())
+		}
+		result = append(result, models.CardRecommendation{Item: rec.Item, Lines: lines, Reason: rec.Reason()})
 	}
+	return result, nil
+}
 
-	return bingos
+// countBingos counts how many bingos (rows, columns, diagonals) are complete
+func (s *CardService) countBingos(items []models.BingoItem, gridSize int, freePos *int) int {
+	return bingo.CountBingos(items, gridSize, freePos)
 }
 
 // CheckForConflict checks if a card already exists for the given user, year, and optional title
@@ -1299,13 +1800,13 @@ func (s *CardService) CheckForConflict(ctx context.Context, userID uuid.UUID, ye
 	if title != nil && *title != "" {
 		// Check for card with this specific title
 		query = `SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		                is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		                is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof
 			FROM bingo_cards WHERE user_id = $1 AND year = $2 AND title = $3`
 		args = []interface{}{userID, year, *title}
 	} else {
 		// Check for any card with null title (default card)
 		query = `SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		                is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		                is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof
 			FROM bingo_cards WHERE user_id = $1 AND year = $2 AND title IS NULL`
 		args = []interface{}{userID, year}
 	}
@@ -1313,7 +1814,7 @@ func (s *CardService) CheckForConflict(ctx context.Context, userID uuid.UUID, ye
 	err := s.db.QueryRow(ctx, query, args...).Scan(
 		&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 		&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.FreeSpaceText, &card.StartDate, &card.EndDate, &card.RequireProof,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrCardNotFound
@@ -1328,36 +1829,86 @@ func (s *CardService) CheckForConflict(ctx context.Context, userID uuid.UUID, ye
 		return nil, err
 	}
 	card.Items = items
+	card.SyncFreeSpace()
 
 	return &card, nil
 }
 
 // Import imports an anonymous card, creating the card and all items in one transaction
 func (s *CardService) Import(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error) {
+	startDate, endDate, err := prepareImport(&params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start a transaction
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	card, err := insertImportedCard(ctx, tx, params, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var emailIDs []uuid.UUID
+	if card.IsFinalized && card.VisibleToFriends {
+		emailIDs = s.notifyFriendsNewCard(ctx, tx, card.UserID, card.ID)
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	s.dispatchNotificationEmails(emailIDs)
+
+	contents := make([]string, len(params.Items))
+	for i, itemParam := range params.Items {
+		contents[i] = itemParam.Content
+	}
+	s.recordSuggestionUsage(ctx, contents)
+
+	return card, nil
+}
+
+// prepareImport validates import parameters and fills in their defaults:
+// grid size, header text and, for a card with a FREE square but no position,
+// where the FREE square goes. It returns the card's period.
+func prepareImport(params *models.ImportCardParams) (time.Time, time.Time, error) {
 	// Validate category if provided
 	if params.Category != nil && *params.Category != "" {
 		if !models.IsValidCategory(*params.Category) {
-			return nil, ErrInvalidCategory
+			return time.Time{}, time.Time{}, ErrInvalidCategory
 		}
 	}
 
 	// Validate title length if provided
 	if params.Title != nil && len(*params.Title) > 100 {
-		return nil, ErrTitleTooLong
+		return time.Time{}, time.Time{}, ErrTitleTooLong
+	}
+
+	if params.Year == 0 && params.StartDate != nil {
+		params.Year = params.StartDate.Year()
+	}
+	startDate, endDate, err := resolveCardPeriod(params.Year, params.StartDate, params.EndDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
 	}
 
 	if params.GridSize == 0 {
 		params.GridSize = models.MaxGridSize
 	}
 	if !models.IsValidGridSize(params.GridSize) {
-		return nil, ErrInvalidGridSize
+		return time.Time{}, time.Time{}, ErrInvalidGridSize
 	}
 	if params.HeaderText == "" {
 		params.HeaderText = models.DefaultHeaderText(params.GridSize)
 	}
 	params.HeaderText = models.NormalizeHeaderText(params.HeaderText)
 	if err := models.ValidateHeaderText(params.HeaderText, params.GridSize); err != nil {
-		return nil, ErrInvalidHeaderText
+		return time.Time{}, time.Time{}, ErrInvalidHeaderText
 	}
 
 	if params.HasFreeSpace && params.FreeSpacePos == nil {
@@ -1377,7 +1928,7 @@ func (s *CardService) Import(ctx context.Context, params models.ImportCardParams
 				}
 			}
 			if len(empties) == 0 {
-				return nil, ErrNoSpaceForFree
+				return time.Time{}, time.Time{}, ErrNoSpaceForFree
 			}
 			pos := empties[rand.Intn(len(empties))]
 			params.FreeSpacePos = &pos
@@ -1397,28 +1948,26 @@ func (s *CardService) Import(ctx context.Context, params models.ImportCardParams
 	positions := make(map[int]bool)
 	for _, item := range params.Items {
 		if item.Position < 0 || item.Position >= totalSquares {
-			return nil, ErrInvalidPosition
+			return time.Time{}, time.Time{}, ErrInvalidPosition
 		}
 		if params.FreeSpacePos != nil && item.Position == *params.FreeSpacePos {
-			return nil, ErrInvalidPosition
+			return time.Time{}, time.Time{}, ErrInvalidPosition
 		}
 		if positions[item.Position] {
-			return nil, ErrPositionOccupied
+			return time.Time{}, time.Time{}, ErrPositionOccupied
 		}
 		positions[item.Position] = true
 	}
 
 	if params.Finalize && len(params.Items) != capacity {
-		return nil, fmt.Errorf("card needs %d items, has %d", capacity, len(params.Items))
+		return time.Time{}, time.Time{}, fmt.Errorf("card needs %d items, has %d", capacity, len(params.Items))
 	}
+	return startDate, endDate, nil
+}
 
-	// Start a transaction
-	tx, err := s.db.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("starting transaction: %w", err)
-	}
-	defer func() { _ = tx.Rollback(ctx) }()
-
+// insertImportedCard creates a card prepared by prepareImport and its items
+// in tx.
+func insertImportedCard(ctx context.Context, tx Tx, params models.ImportCardParams, startDate, endDate time.Time) (*models.BingoCard, error) {
 	// Determine visibility (default to true if not specified)
 	visibleToFriends := true
 	if params.VisibleToFriends != nil {
@@ -1427,16 +1976,16 @@ func (s *CardService) Import(ctx context.Context, params models.ImportCardParams
 
 	// Create the card
 	card := &models.BingoCard{}
-	err = tx.QueryRow(ctx,
-		`INSERT INTO bingo_cards (user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position, is_finalized, visible_to_friends)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	err := tx.QueryRow(ctx,
+		`INSERT INTO bingo_cards (user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position, is_finalized, visible_to_friends, start_date, end_date, free_space_text, require_proof)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		 RETURNING id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		           is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at`,
-		params.UserID, params.Year, params.Category, params.Title, params.GridSize, params.HeaderText, params.HasFreeSpace, params.FreeSpacePos, params.Finalize, visibleToFriends,
+		           is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof`,
+		params.UserID, params.Year, params.Category, params.Title, params.GridSize, params.HeaderText, params.HasFreeSpace, params.FreeSpacePos, params.Finalize, visibleToFriends, startDate, endDate, params.FreeSpaceText, params.RequireProof,
 	).Scan(
 		&card.ID, &card.UserID, &card.Year, &card.Category, &card.Title,
 		&card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos,
-		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt,
+		&card.IsActive, &card.IsFinalized, &card.VisibleToFriends, &card.IsArchived, &card.CreatedAt, &card.UpdatedAt, &card.FreeSpaceText, &card.StartDate, &card.EndDate, &card.RequireProof,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("creating card: %w", err)
@@ -1444,30 +1993,150 @@ func (s *CardService) Import(ctx context.Context, params models.ImportCardParams
 
 	// Insert all items
 	card.Items = make([]models.BingoItem, len(params.Items))
+	card.SyncFreeSpace()
 	for i, itemParam := range params.Items {
 		var item models.BingoItem
 		err = tx.QueryRow(ctx,
-			`INSERT INTO bingo_items (card_id, position, content)
-			 VALUES ($1, $2, $3)
-			 RETURNING id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at`,
-			card.ID, itemParam.Position, itemParam.Content,
-		).Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.CreatedAt)
+			`INSERT INTO bingo_items (card_id, position, content, difficulty, is_private, notes, proof_url, is_completed, completed_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			 RETURNING id, card_id, position, content, is_completed, completed_at, notes, proof_url, difficulty, is_private, created_at`,
+			card.ID, itemParam.Position, itemParam.Content, itemParam.Difficulty, itemParam.IsPrivate,
+			itemParam.Notes, itemParam.ProofURL, itemParam.IsCompleted, itemParam.CompletedAt,
+		).Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.Difficulty, &item.IsPrivate, &item.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("creating item: %w", err)
 		}
 		card.Items[i] = item
 	}
+	return card, nil
+}
+
+// MergeImport appends imported items into the open squares of an existing
+// draft card, in position order. Items whose content matches an existing goal
+// (or an earlier item in the payload) are skipped. If the remaining items don't
+// all fit, nothing is added. With DryRun the card is left unchanged and the
+// result lists the planned positions.
+func (s *CardService) MergeImport(ctx context.Context, params models.MergeImportParams) (*models.MergeImportResult, error) {
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	card := &models.BingoCard{}
+	err = tx.QueryRow(ctx,
+		`SELECT id, user_id, grid_size, header_text, has_free_space, free_space_position, is_finalized
+		 FROM bingo_cards
+		 WHERE id = $1
+		 FOR UPDATE`,
+		params.CardID,
+	).Scan(&card.ID, &card.UserID, &card.GridSize, &card.HeaderText, &card.HasFreeSpace, &card.FreeSpacePos, &card.IsFinalized)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("locking card: %w", err)
+	}
+	if !authz.CanEditCard(authz.For(params.UserID, card.UserID)) {
+		return nil, ErrNotCardOwner
+	}
+	if card.IsFinalized {
+		return nil, ErrCardFinalized
+	}
+
+	rows, err := tx.Query(ctx, "SELECT position, content FROM bingo_items WHERE card_id = $1", params.CardID)
+	if err != nil {
+		return nil, fmt.Errorf("getting current items: %w", err)
+	}
+	defer rows.Close()
+
+	occupied := make(map[int]bool)
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var (
+			pos     int
+			content string
+		)
+		if err := rows.Scan(&pos, &content); err != nil {
+			return nil, fmt.Errorf("scanning current item: %w", err)
+		}
+		occupied[pos] = true
+		seen[normalizeItemContent(content)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating current items: %w", err)
+	}
+
+	result := &models.MergeImportResult{
+		DryRun:     params.DryRun,
+		Added:      []models.BingoItem{},
+		Duplicates: []string{},
+	}
+	var toAdd []string
+	for _, item := range params.Items {
+		content := strings.TrimSpace(item.Content)
+		key := normalizeItemContent(content)
+		if seen[key] {
+			result.Duplicates = append(result.Duplicates, content)
+			continue
+		}
+		seen[key] = true
+		toAdd = append(toAdd, content)
+	}
+
+	open := make([]int, 0, card.Capacity())
+	for pos := 0; pos < card.TotalSquares(); pos++ {
+		if !card.IsFreeSpacePosition(pos) && !occupied[pos] {
+			open = append(open, pos)
+		}
+	}
+	if len(toAdd) > len(open) {
+		return nil, fmt.Errorf("%w: %d new items but only %d open (%d short)", ErrNotEnoughOpenSquares, len(toAdd), len(open), len(toAdd)-len(open))
+	}
+
+	if params.DryRun {
+		for i, content := range toAdd {
+			result.Added = append(result.Added, models.BingoItem{
+				CardID:   card.ID,
+				Position: open[i],
+				Content:  content,
+			})
+		}
+		return result, nil
+	}
+
+	for i, content := range toAdd {
+		var item models.BingoItem
+		err = tx.QueryRow(ctx,
+			`INSERT INTO bingo_items (card_id, position, content)
+			 VALUES ($1, $2, $3)
+			 RETURNING id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at, is_private, difficulty`,
+			card.ID, open[i], content,
+		).Scan(&item.ID, &item.CardID, &item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL, &item.CreatedAt, &item.IsPrivate, &item.Difficulty)
+		if err != nil {
+			return nil, fmt.Errorf("adding item: %w", err)
+		}
+		item.ContentTruncated = contentTruncated(item.Content, card.GridSize)
+		result.Added = append(result.Added, item)
+	}
 
-	// Commit the transaction
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("committing transaction: %w", err)
 	}
+	s.recordSuggestionUsage(ctx, toAdd)
 
-	if card.IsFinalized && card.VisibleToFriends {
-		s.notifyFriendsNewCard(ctx, card.UserID, card.ID)
+	merged, err := s.GetByID(ctx, card.ID)
+	if err != nil {
+		return nil, err
 	}
+	result.Card = merged
+	return result, nil
+}
 
-	return card, nil
+// normalizeItemContent is the key used to spot duplicate goals: trimmed,
+// case-insensitive, with internal whitespace collapsed.
+func normalizeItemContent(content string) string {
+	return strings.ToLower(strings.Join(strings.Fields(content), " "))
 }
 
 func (s *CardService) UpdateConfig(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardConfigParams) (*models.BingoCard, error) {
@@ -1475,11 +2144,21 @@ func (s *CardService) UpdateConfig(ctx context.Context, userID, cardID uuid.UUID
 	if err != nil {
 		return nil, err
 	}
-	if card.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
 		return nil, ErrNotCardOwner
 	}
 	if card.IsFinalized {
-		return nil, ErrCardFinalized
+		// require_proof doesn't change card content, so it stays editable.
+		if !params.OnlyRequireProof() {
+			return nil, ErrCardFinalized
+		}
+		if _, err := s.db.Exec(ctx,
+			"UPDATE bingo_cards SET require_proof = $1, updated_at = NOW() WHERE id = $2",
+			*params.RequireProof, card.ID,
+		); err != nil {
+			return nil, fmt.Errorf("updating require proof: %w", err)
+		}
+		return s.GetByID(ctx, card.ID)
 	}
 
 	headerText := (*string)(nil)
@@ -1491,10 +2170,23 @@ func (s *CardService) UpdateConfig(ctx context.Context, userID, cardID uuid.UUID
 		headerText = &normalized
 	}
 
+	freeSpaceText := card.FreeSpaceText
+	if params.FreeSpaceText != nil {
+		normalized := models.NormalizeFreeSpaceText(*params.FreeSpaceText)
+		if err := models.ValidateFreeSpaceText(normalized); err != nil {
+			return nil, ErrInvalidFreeSpaceText
+		}
+		if normalized == "" {
+			freeSpaceText = nil
+		} else {
+			freeSpaceText = &normalized
+		}
+	}
+
 	hasFree := card.HasFreeSpace
 	freePos := card.FreeSpacePos
 
-	tx, err := s.db.Begin(ctx)
+	tx, ctx, err := beginTx(ctx, s.db)
 	if err != nil {
 		return nil, fmt.Errorf("starting transaction: %w", err)
 	}
@@ -1559,15 +2251,20 @@ func (s *CardService) UpdateConfig(ctx context.Context, userID, cardID uuid.UUID
 		`UPDATE bingo_cards
 		 SET header_text = COALESCE($1, header_text),
 		     has_free_space = $2,
-		     free_space_position = $3
-		 WHERE id = $4`,
-		headerText, hasFree, freePos, card.ID,
+		     free_space_position = $3,
+		     free_space_text = $4,
+		     require_proof = COALESCE($5, require_proof)
+		 WHERE id = $6`,
+		headerText, hasFree, freePos, freeSpaceText, params.RequireProof, card.ID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("updating card config: %w", err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
+		if isItemPositionViolation(err) {
+			return nil, ErrInvalidPosition
+		}
 		return nil, fmt.Errorf("committing transaction: %w", err)
 	}
 
@@ -1618,7 +2315,7 @@ func (s *CardService) Clone(ctx context.Context, userID, sourceCardID uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
-	if source.UserID != userID {
+	if !authz.CanEditCard(authz.For(userID, source.UserID)) {
 		return nil, ErrNotCardOwner
 	}
 
@@ -1642,6 +2339,12 @@ func (s *CardService) Clone(ctx context.Context, userID, sourceCardID uuid.UUID,
 		year = *params.Year
 	}
 
+	// Keep the source's period shape (e.g. a Sep-Aug school year) and move it
+	// to the target year.
+	sourceStart, sourceEnd := source.Period()
+	startDate := sourceStart.AddDate(year-source.Year, 0, 0)
+	endDate := sourceEnd.AddDate(year-source.Year, 0, 0)
+
 	title := (*string)(nil)
 	if params.Title != nil {
 		trimmed := strings.TrimSpace(*params.Title)
@@ -1690,23 +2393,28 @@ func (s *CardService) Clone(ctx context.Context, userID, sourceCardID uuid.UUID,
 		availablePositions[i], availablePositions[j] = availablePositions[j], availablePositions[i]
 	})
 
-	tx, err := s.db.Begin(ctx)
+	tx, ctx, err := beginTx(ctx, s.db)
 	if err != nil {
 		return nil, fmt.Errorf("starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
 
+	var freeSpaceText *string
+	if hasFreeSpace {
+		freeSpaceText = source.FreeSpaceText
+	}
+
 	newCard := &models.BingoCard{}
 	err = tx.QueryRow(ctx,
-		`INSERT INTO bingo_cards (user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`INSERT INTO bingo_cards (user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position, free_space_text, start_date, end_date)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		 RETURNING id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		           is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at`,
-		userID, year, category, title, params.GridSize, params.HeaderText, hasFreeSpace, freePos,
+		           is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof`,
+		userID, year, category, title, params.GridSize, params.HeaderText, hasFreeSpace, freePos, freeSpaceText, startDate, endDate,
 	).Scan(
 		&newCard.ID, &newCard.UserID, &newCard.Year, &newCard.Category, &newCard.Title,
 		&newCard.GridSize, &newCard.HeaderText, &newCard.HasFreeSpace, &newCard.FreeSpacePos,
-		&newCard.IsActive, &newCard.IsFinalized, &newCard.VisibleToFriends, &newCard.IsArchived, &newCard.CreatedAt, &newCard.UpdatedAt,
+		&newCard.IsActive, &newCard.IsFinalized, &newCard.VisibleToFriends, &newCard.IsArchived, &newCard.CreatedAt, &newCard.UpdatedAt, &newCard.FreeSpaceText, &newCard.StartDate, &newCard.EndDate, &newCard.RequireProof,
 	)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -1721,9 +2429,9 @@ func (s *CardService) Clone(ctx context.Context, userID, sourceCardID uuid.UUID,
 	for i, it := range itemsToCopy {
 		pos := availablePositions[i]
 		_, err := tx.Exec(ctx,
-			`INSERT INTO bingo_items (card_id, position, content)
-			 VALUES ($1, $2, $3)`,
-			newCard.ID, pos, it.Content,
+			`INSERT INTO bingo_items (card_id, position, content, is_private, difficulty)
+			 VALUES ($1, $2, $3, $4, $5)`,
+			newCard.ID, pos, it.Content, it.IsPrivate, it.Difficulty,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("copying item: %w", err)
@@ -1742,24 +2450,31 @@ func (s *CardService) Clone(ctx context.Context, userID, sourceCardID uuid.UUID,
 	return &CloneResult{Card: created, TruncatedItemCount: truncated}, nil
 }
 
-func (s *CardService) notifyFriendsNewCard(ctx context.Context, userID, cardID uuid.UUID) {
+// notifyFriendsNewCard creates new-card notifications in tx, the transaction
+// of the card write that triggered them. The returned IDs need an email, sent
+// with dispatchNotificationEmails only after tx commits.
+func (s *CardService) notifyFriendsNewCard(ctx context.Context, tx Tx, userID, cardID uuid.UUID) []uuid.UUID {
 	if s.notificationService == nil {
-		return
+		return nil
 	}
-	if err := s.notificationService.NotifyFriendsNewCard(ctx, userID, cardID); err != nil {
+	emailIDs, err := s.notificationService.NotifyFriendsNewCard(ctx, tx, userID, cardID)
+	if err != nil {
 		logging.Error("Failed to notify friends about new card", map[string]interface{}{
 			"error":   err.Error(),
 			"user_id": userID.String(),
 			"card_id": cardID.String(),
 		})
 	}
+	return emailIDs
 }
 
-func (s *CardService) notifyFriendsBingo(ctx context.Context, userID, cardID uuid.UUID, bingoCount int) {
+// notifyFriendsBingo is notifyFriendsNewCard for bingos.
+func (s *CardService) notifyFriendsBingo(ctx context.Context, tx Tx, userID, cardID uuid.UUID, bingoCount int) []uuid.UUID {
 	if s.notificationService == nil {
-		return
+		return nil
 	}
-	if err := s.notificationService.NotifyFriendsBingo(ctx, userID, cardID, bingoCount); err != nil {
+	emailIDs, err := s.notificationService.NotifyFriendsBingo(ctx, tx, userID, cardID, bingoCount)
+	if err != nil {
 		logging.Error("Failed to notify friends about bingo", map[string]interface{}{
 			"error":       err.Error(),
 			"user_id":     userID.String(),
@@ -1767,4 +2482,87 @@ func (s *CardService) notifyFriendsBingo(ctx context.Context, userID, cardID uui
 			"bingo_count": bingoCount,
 		})
 	}
+	return emailIDs
+}
+
+// reloadItemState refreshes item's completion fields from the database, for
+// a write that found the item already in the state it asked for.
+func (s *CardService) reloadItemState(ctx context.Context, item *models.BingoItem) (*models.BingoItem, error) {
+	err := s.db.QueryRow(ctx,
+		"SELECT is_completed, completed_at, notes, proof_url FROM bingo_items WHERE id = $1",
+		item.ID,
+	).Scan(&item.IsCompleted, &item.CompletedAt, &item.Notes, &item.ProofURL)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting item: %w", err)
+	}
+	return item, nil
+}
+
+func (s *CardService) dispatchNotificationEmails(emailIDs []uuid.UUID) {
+	if s.notificationService == nil || len(emailIDs) == 0 {
+		return
+	}
+	s.notificationService.DispatchEmails(emailIDs)
+}
+
+// execNotifying runs a single-statement card write. When notify is set and
+// notifications are enabled, the write and the notifications notify creates
+// share one transaction, and their emails go out after it commits.
+func (s *CardService) execNotifying(ctx context.Context, notify func(tx Tx) []uuid.UUID, sql string, args ...any) error {
+	if notify == nil || s.notificationService == nil {
+		_, err := s.db.Exec(ctx, sql, args...)
+		return err
+	}
+
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+	emailIDs := notify(tx)
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	s.dispatchNotificationEmails(emailIDs)
+	return nil
+}
+
+// claimNotifying is execNotifying for a conditional write: notify only runs
+// when the write changed a row, so requests racing on the same row notify at
+// most once. It reports whether a row changed.
+func (s *CardService) claimNotifying(ctx context.Context, notify func(tx Tx) []uuid.UUID, sql string, args ...any) (bool, error) {
+	if notify == nil || s.notificationService == nil {
+		result, err := s.db.Exec(ctx, sql, args...)
+		if err != nil {
+			return false, err
+		}
+		return result.RowsAffected() > 0, nil
+	}
+
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	result, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+	emailIDs := notify(tx)
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("committing transaction: %w", err)
+	}
+	s.dispatchNotificationEmails(emailIDs)
+	return true, nil
 }
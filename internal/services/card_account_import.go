@@ -0,0 +1,328 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// ErrInvalidAccountExport is returned for an account export whose cards or
+// goals can't be read back.
+var ErrInvalidAccountExport = errors.New("invalid account export")
+
+// ReadAccountExportZip reads the cards and goals from an account export ZIP
+// (see AccountService.StreamExport) as draft import parameters. Only
+// cards.csv and items.csv are used.
+func ReadAccountExportZip(r io.ReaderAt, size int64, includeCompletions bool) ([]models.ImportCardParams, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a ZIP file", ErrInvalidAccountExport)
+	}
+	cards, err := archive.Open("cards.csv")
+	if err != nil {
+		return nil, fmt.Errorf("%w: cards.csv is missing", ErrInvalidAccountExport)
+	}
+	defer func() { _ = cards.Close() }()
+	items, err := archive.Open("items.csv")
+	if err != nil {
+		return nil, fmt.Errorf("%w: items.csv is missing", ErrInvalidAccountExport)
+	}
+	defer func() { _ = items.Close() }()
+	return ReadAccountExportCSV(cards, items, includeCompletions)
+}
+
+// ReadAccountExportCSV reads an account export's cards.csv and items.csv as
+// draft import parameters, one per card in file order. Each card keeps its
+// year, period, title, category, grid and FREE square settings; each goal
+// keeps its position, content, notes, difficulty and privacy. With
+// includeCompletions, completed goals stay completed with their completion
+// time and proof URL.
+func ReadAccountExportCSV(cards, items io.Reader, includeCompletions bool) ([]models.ImportCardParams, error) {
+	cardRows, err := readExportTable(cards, "cards.csv", "id", "year", "title", "category", "grid_size", "header_text",
+		"has_free_space", "free_space_position", "free_space_text", "start_date", "end_date", "require_proof")
+	if err != nil {
+		return nil, err
+	}
+	itemRows, err := readExportTable(items, "items.csv", "card_id", "position", "content", "is_completed",
+		"completed_at", "notes", "proof_url", "is_private", "difficulty")
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]models.ImportCardParams, 0, len(cardRows))
+	index := make(map[uuid.UUID]int, len(cardRows))
+	for i, row := range cardRows {
+		p := exportRowParser{row: row}
+		id := p.uuid("id")
+		card := models.ImportCardParams{
+			Year:          p.int("year"),
+			Title:         p.optionalString("title"),
+			Category:      p.optionalString("category"),
+			GridSize:      p.int("grid_size"),
+			HeaderText:    p.string("header_text"),
+			HasFreeSpace:  p.bool("has_free_space"),
+			FreeSpacePos:  p.optionalInt("free_space_position"),
+			FreeSpaceText: p.optionalString("free_space_text"),
+			StartDate:     p.date("start_date"),
+			EndDate:       p.date("end_date"),
+			RequireProof:  p.bool("require_proof"),
+		}
+		if p.err != nil {
+			return nil, fmt.Errorf("%w: cards.csv row %d: %v", ErrInvalidAccountExport, i+2, p.err)
+		}
+		if _, ok := index[id]; ok {
+			return nil, fmt.Errorf("%w: cards.csv row %d: duplicate card id", ErrInvalidAccountExport, i+2)
+		}
+		index[id] = len(params)
+		params = append(params, card)
+	}
+
+	for i, row := range itemRows {
+		p := exportRowParser{row: row}
+		cardID := p.uuid("card_id")
+		item := models.ImportItem{
+			Position:   p.int("position"),
+			Content:    strings.TrimSpace(p.string("content")),
+			Notes:      p.optionalString("notes"),
+			IsPrivate:  p.bool("is_private"),
+			Difficulty: p.optionalString("difficulty"),
+		}
+		if includeCompletions && p.bool("is_completed") {
+			item.IsCompleted = true
+			item.CompletedAt = p.optionalTime("completed_at")
+			item.ProofURL = p.optionalString("proof_url")
+		}
+		if p.err != nil {
+			return nil, fmt.Errorf("%w: items.csv row %d: %v", ErrInvalidAccountExport, i+2, p.err)
+		}
+		if item.Content == "" {
+			return nil, fmt.Errorf("%w: items.csv row %d: goal content is required", ErrInvalidAccountExport, i+2)
+		}
+		card, ok := index[cardID]
+		if !ok {
+			return nil, fmt.Errorf("%w: items.csv row %d: card %s is not in cards.csv", ErrInvalidAccountExport, i+2, cardID)
+		}
+		params[card].Items = append(params[card].Items, item)
+	}
+	return params, nil
+}
+
+// readExportTable reads a CSV file written by the account export into one
+// map per row, keyed by column name. The listed columns must be present.
+func readExportTable(r io.Reader, name string, required ...string) ([]map[string]string, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidAccountExport, name, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: %s is empty", ErrInvalidAccountExport, name)
+	}
+	header := records[0]
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[column] = i
+	}
+	for _, column := range required {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("%w: %s has no %s column", ErrInvalidAccountExport, name, column)
+		}
+	}
+
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for column, i := range columns {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// exportRowParser converts the columns of an export row back into values,
+// keeping the first error so a row can be parsed without checking each
+// column.
+type exportRowParser struct {
+	row map[string]string
+	err error
+}
+
+func (p *exportRowParser) fail(column string, err error) {
+	if p.err == nil {
+		p.err = fmt.Errorf("%s: %w", column, err)
+	}
+}
+
+// string returns a text column as it was before the export guarded it
+// against spreadsheet formulas (see sanitizeCSVValue).
+func (p *exportRowParser) string(column string) string {
+	return unsanitizeCSVValue(p.row[column])
+}
+
+func (p *exportRowParser) optionalString(column string) *string {
+	if p.row[column] == "" {
+		return nil
+	}
+	value := p.string(column)
+	return &value
+}
+
+func (p *exportRowParser) int(column string) int {
+	value, err := strconv.Atoi(p.row[column])
+	if err != nil {
+		p.fail(column, err)
+	}
+	return value
+}
+
+func (p *exportRowParser) optionalInt(column string) *int {
+	if p.row[column] == "" {
+		return nil
+	}
+	value := p.int(column)
+	return &value
+}
+
+func (p *exportRowParser) bool(column string) bool {
+	value, err := strconv.ParseBool(p.row[column])
+	if err != nil {
+		p.fail(column, err)
+	}
+	return value
+}
+
+func (p *exportRowParser) uuid(column string) uuid.UUID {
+	value, err := uuid.Parse(p.row[column])
+	if err != nil {
+		p.fail(column, err)
+	}
+	return value
+}
+
+func (p *exportRowParser) date(column string) *time.Time {
+	value, err := time.Parse("2006-01-02", p.row[column])
+	if err != nil {
+		p.fail(column, err)
+		return nil
+	}
+	return &value
+}
+
+func (p *exportRowParser) optionalTime(column string) *time.Time {
+	if p.row[column] == "" {
+		return nil
+	}
+	value, err := time.Parse(time.RFC3339, p.row[column])
+	if err != nil {
+		p.fail(column, err)
+		return nil
+	}
+	return &value
+}
+
+// unsanitizeCSVValue undoes sanitizeCSVValue. A value that really started
+// with a quote followed by a formula character can't be told apart and loses
+// its quote.
+func unsanitizeCSVValue(value string) string {
+	if !strings.HasPrefix(value, "'") {
+		return value
+	}
+	switch firstNonSpace(value[1:]) {
+	case '=', '+', '-', '@':
+		return strings.ReplaceAll(value[1:], "''", "'")
+	default:
+		return value
+	}
+}
+
+// ImportAccountExport recreates cards read from an account export (see
+// ReadAccountExportZip) as unfinalized drafts owned by userID, in one
+// transaction. A card whose title and year match one of the user's cards,
+// or an earlier card in the export, is left out and reported as a duplicate
+// instead.
+func (s *CardService) ImportAccountExport(ctx context.Context, userID uuid.UUID, cards []models.ImportCardParams) (*models.AccountExportImportResult, error) {
+	type plannedCard struct {
+		params     models.ImportCardParams
+		start, end time.Time
+	}
+
+	result := &models.AccountExportImportResult{
+		Cards:      []*models.BingoCard{},
+		Duplicates: []models.CardImportDuplicate{},
+	}
+	planned := make([]plannedCard, 0, len(cards))
+	seen := make(map[string]bool, len(cards))
+	for _, params := range cards {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		params.UserID = userID
+		params.Finalize = false
+		params.VisibleToFriends = nil
+
+		title := ""
+		if params.Title != nil {
+			title = *params.Title
+		}
+		key := strconv.Itoa(params.Year) + "\x00" + title
+		if seen[key] {
+			result.Duplicates = append(result.Duplicates, models.CardImportDuplicate{Title: params.Title, Year: params.Year})
+			continue
+		}
+		seen[key] = true
+
+		existing, err := s.CheckForConflict(ctx, userID, params.Year, params.Title)
+		if err == nil {
+			result.Duplicates = append(result.Duplicates, models.CardImportDuplicate{
+				Title:          params.Title,
+				Year:           params.Year,
+				ExistingCardID: &existing.ID,
+			})
+			continue
+		}
+		if !errors.Is(err, ErrCardNotFound) {
+			return nil, err
+		}
+
+		start, end, err := prepareImport(&params)
+		if err != nil {
+			return nil, fmt.Errorf("%w: card %q (%d): %v", ErrInvalidAccountExport, title, params.Year, err)
+		}
+		planned = append(planned, plannedCard{params: params, start: start, end: end})
+	}
+	if len(planned) == 0 {
+		return result, nil
+	}
+
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, card := range planned {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		created, err := insertImportedCard(ctx, tx, card.params, card.start, card.end)
+		if err != nil {
+			return nil, err
+		}
+		result.Cards = append(result.Cards, created)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return result, nil
+}
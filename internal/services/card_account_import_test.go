@@ -0,0 +1,321 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
+)
+
+// exportZipFor streams an account export containing the given cards.
+func exportZipFor(t *testing.T, user *testutil.TestUser, cards ...*testutil.TestCard) []byte {
+	t.Helper()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(user.ExportRow()...)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			var rows [][]any
+			switch {
+			case strings.Contains(sql, "FROM bingo_cards"):
+				for _, card := range cards {
+					rows = append(rows, card.Row())
+				}
+			case strings.Contains(sql, "FROM bingo_items"):
+				for _, card := range cards {
+					rows = append(rows, card.ItemRows()...)
+				}
+			}
+			return &fakeRows{rows: rows}, nil
+		},
+	}
+	var buf bytes.Buffer
+	if err := NewAccountService(db).StreamExport(context.Background(), user.ID, &buf); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadAccountExportZip_RoundTrip(t *testing.T) {
+	user := testutil.NewTestUser()
+	title := "=Formula-looking title"
+	card := testutil.NewTestCard(testutil.WithCardOwner(user.ID), testutil.WithGridSize(3), testutil.WithItems(3), testutil.WithCompletedItems(1))
+	card.Title = &title
+	note := "Ran it in the rain"
+	card.Items[0].Notes = &note
+
+	data := exportZipFor(t, user, card)
+
+	cards, err := ReadAccountExportZip(bytes.NewReader(data), int64(len(data)), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cards) != 1 {
+		t.Fatalf("expected 1 card, got %d", len(cards))
+	}
+	got := cards[0]
+	if got.Title == nil || *got.Title != title {
+		t.Fatalf("expected title %q, got %v", title, got.Title)
+	}
+	if got.Year != card.Year || got.GridSize != 3 || !got.HasFreeSpace {
+		t.Fatalf("expected year, grid and FREE square kept, got %+v", got)
+	}
+	if got.FreeSpacePos == nil || *got.FreeSpacePos != *card.FreeSpacePos {
+		t.Fatalf("expected free space position %d, got %v", *card.FreeSpacePos, got.FreeSpacePos)
+	}
+	if got.StartDate == nil || !got.StartDate.Equal(card.StartDate) {
+		t.Fatalf("expected start date %v, got %v", card.StartDate, got.StartDate)
+	}
+	if len(got.Items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(got.Items))
+	}
+	first := got.Items[0]
+	if first.Content != "Goal 1" || first.Position != card.Items[0].Position {
+		t.Fatalf("expected first goal kept, got %+v", first)
+	}
+	if first.Notes == nil || *first.Notes != note {
+		t.Fatalf("expected notes kept, got %v", first.Notes)
+	}
+	if first.IsCompleted || first.CompletedAt != nil {
+		t.Fatalf("expected completion dropped without include_completions, got %+v", first)
+	}
+
+	cards, err = ReadAccountExportZip(bytes.NewReader(data), int64(len(data)), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first = cards[0].Items[0]
+	if !first.IsCompleted || first.CompletedAt == nil || !first.CompletedAt.Equal(testutil.FixtureTime) {
+		t.Fatalf("expected completion kept, got %+v", first)
+	}
+	if cards[0].Items[1].IsCompleted {
+		t.Fatal("expected an open goal to stay open")
+	}
+}
+
+func TestReadAccountExportZip_Invalid(t *testing.T) {
+	tests := map[string][]byte{
+		"not a zip": []byte("hello"),
+		"no cards":  exportZipWithout(t, "cards.csv"),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadAccountExportZip(bytes.NewReader(data), int64(len(data)), false)
+			if !errors.Is(err, ErrInvalidAccountExport) {
+				t.Fatalf("expected ErrInvalidAccountExport, got %v", err)
+			}
+		})
+	}
+}
+
+func exportZipWithout(t *testing.T, name string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range []string{"cards.csv", "items.csv"} {
+		if file == name {
+			continue
+		}
+		if _, err := zw.Create(file); err != nil {
+			t.Fatalf("create %s: %v", file, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadAccountExportCSV_Errors(t *testing.T) {
+	cardID := uuid.New()
+	cardsCSV := "id,year,title,category,grid_size,header_text,has_free_space,free_space_position,free_space_text,start_date,end_date,require_proof\n" +
+		cardID.String() + ",2025,,,2,BI,false,,,2025-01-01,2025-12-31,false\n"
+	itemsHeader := "card_id,position,content,is_completed,completed_at,notes,proof_url,is_private,difficulty\n"
+
+	tests := []struct {
+		name  string
+		cards string
+		items string
+		want  string
+	}{
+		{name: "missing column", cards: "id,year\n", items: itemsHeader, want: "no title column"},
+		{name: "bad year", cards: strings.Replace(cardsCSV, ",2025,", ",soon,", 1), items: itemsHeader, want: "cards.csv row 2: year"},
+		{name: "unknown card", cards: cardsCSV, items: itemsHeader + uuid.NewString() + ",0,Run,false,,,,false,\n", want: "is not in cards.csv"},
+		{name: "empty goal", cards: cardsCSV, items: itemsHeader + cardID.String() + ",0,  ,false,,,,false,\n", want: "goal content is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadAccountExportCSV(strings.NewReader(tt.cards), strings.NewReader(tt.items), false)
+			if !errors.Is(err, ErrInvalidAccountExport) || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected ErrInvalidAccountExport mentioning %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestUnsanitizeCSVValue(t *testing.T) {
+	for _, value := range []string{"plain", "=SUM(A1)", " -1", "@mention", "+it's", "'quoted", ""} {
+		if got := unsanitizeCSVValue(sanitizeCSVValue(value)); got != value {
+			t.Errorf("round trip of %q: got %q", value, got)
+		}
+	}
+}
+
+func TestCardService_ImportAccountExport(t *testing.T) {
+	userID := uuid.New()
+	existingID := uuid.New()
+	taken, fresh := "Taken", "Fresh"
+	log := &testutil.SQLLog{}
+	var insertedCards int
+	var insertedItems [][]any
+	committed := false
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			log.Record(sql, args...)
+			if len(args) == 3 && args[2] == taken {
+				card := testutil.NewTestCard(testutil.WithCardOwner(userID))
+				card.ID = existingID
+				return rowFromValues(card.Row()...)
+			}
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{}, nil
+		},
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			return &fakeTx{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					log.Record(sql, args...)
+					if strings.Contains(sql, "INSERT INTO bingo_cards") {
+						insertedCards++
+						card := testutil.NewTestCard(testutil.WithCardOwner(userID), testutil.WithDraft())
+						card.Title = args[3].(*string)
+						return rowFromValues(card.Row()...)
+					}
+					insertedItems = append(insertedItems, args)
+					completedAt, _ := args[8].(*time.Time)
+					return rowFromValues(uuid.New(), uuid.New(), args[1], args[2], args[7], completedAt, args[5], args[6], args[3], args[4], time.Now())
+				},
+				CommitFunc: func(ctx context.Context) error {
+					committed = true
+					return nil
+				},
+			}, nil
+		},
+	}
+
+	completedAt := testutil.FixtureTime
+	note := "Notes survive"
+	svc := NewCardService(db)
+	result, err := svc.ImportAccountExport(context.Background(), userID, []models.ImportCardParams{
+		{Year: 2025, Title: &taken, GridSize: 2, HeaderText: "BI"},
+		{Year: 2025, Title: &fresh, GridSize: 2, HeaderText: "BI", Finalize: true, Items: []models.ImportItem{
+			{Position: 0, Content: "Run", Notes: &note, IsCompleted: true, CompletedAt: &completedAt},
+		}},
+		{Year: 2025, Title: &fresh, GridSize: 2, HeaderText: "BI"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !committed {
+		t.Fatal("expected transaction to commit")
+	}
+	if insertedCards != 1 || len(result.Cards) != 1 {
+		t.Fatalf("expected only the fresh card created, got %d inserts and %d cards", insertedCards, len(result.Cards))
+	}
+	insert := log.AssertCalled(t, "INSERT INTO bingo_cards")
+	if insert.Args[0] != userID || insert.Args[8] != false {
+		t.Fatalf("expected an unfinalized card for the importing user, got args %v", insert.Args)
+	}
+	if len(insertedItems) != 1 || insertedItems[0][5] != &note || insertedItems[0][7] != true {
+		t.Fatalf("expected the goal inserted with notes and completion, got %v", insertedItems)
+	}
+	if len(result.Duplicates) != 2 {
+		t.Fatalf("expected 2 duplicates, got %+v", result.Duplicates)
+	}
+	if dup := result.Duplicates[0]; dup.ExistingCardID == nil || *dup.ExistingCardID != existingID {
+		t.Fatalf("expected clash with existing card %s, got %+v", existingID, dup)
+	}
+	if dup := result.Duplicates[1]; dup.ExistingCardID != nil || *dup.Title != fresh {
+		t.Fatalf("expected clash within the import, got %+v", dup)
+	}
+}
+
+func TestCardService_ImportAccountExport_AllDuplicatesSkipsTransaction(t *testing.T) {
+	title := "Taken"
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(testutil.NewTestCard().Row()...)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{}, nil
+		},
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			t.Fatal("expected no transaction")
+			return nil, nil
+		},
+	}
+
+	result, err := NewCardService(db).ImportAccountExport(context.Background(), uuid.New(), []models.ImportCardParams{
+		{Year: 2025, Title: &title, GridSize: 2, HeaderText: "BI"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Cards) != 0 || len(result.Duplicates) != 1 {
+		t.Fatalf("expected one duplicate and no cards, got %+v", result)
+	}
+}
+
+func TestCardService_ImportAccountExport_StopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lookups := 0
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			lookups++
+			cancel()
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			t.Fatal("expected no transaction")
+			return nil, nil
+		},
+	}
+
+	first, second := "First", "Second"
+	_, err := NewCardService(db).ImportAccountExport(ctx, uuid.New(), []models.ImportCardParams{
+		{Year: 2025, Title: &first, GridSize: 2, HeaderText: "BI"},
+		{Year: 2025, Title: &second, GridSize: 2, HeaderText: "BI"},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if lookups != 1 {
+		t.Fatalf("expected the import to stop after one conflict check, got %d", lookups)
+	}
+}
+
+func TestCardService_ImportAccountExport_InvalidCard(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+
+	_, err := NewCardService(db).ImportAccountExport(context.Background(), uuid.New(), []models.ImportCardParams{
+		{Year: 2025, GridSize: 7},
+	})
+	if !errors.Is(err, ErrInvalidAccountExport) {
+		t.Fatalf("expected ErrInvalidAccountExport, got %v", err)
+	}
+}
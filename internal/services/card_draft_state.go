@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	cardDraftStateKeyPrefix = "carddraft:"
+	// CardDraftStateMaxBytes caps a saved card wizard state.
+	CardDraftStateMaxBytes = 32 * 1024
+	// CardDraftStateTTL is how long a saved wizard state lives after its
+	// last save.
+	CardDraftStateTTL = 7 * 24 * time.Hour
+)
+
+var (
+	ErrCardDraftStateNotFound = errors.New("no saved card draft state")
+	ErrCardDraftStateTooLarge = errors.New("card draft state is too large")
+	ErrCardDraftStateInvalid  = errors.New("card draft state must be JSON")
+)
+
+// CardDraftStateService keeps one in-progress card creation wizard state per
+// user, so the wizard survives a closed tab before the card exists. The state
+// is opaque to the server: it is only checked for size and JSON syntax.
+type CardDraftStateService struct {
+	redis RedisClient
+}
+
+func NewCardDraftStateService(redis RedisClient) *CardDraftStateService {
+	return &CardDraftStateService{redis: redis}
+}
+
+func cardDraftStateKey(userID uuid.UUID) string {
+	return cardDraftStateKeyPrefix + userID.String()
+}
+
+// Save replaces the user's saved state and restarts its TTL.
+func (s *CardDraftStateService) Save(ctx context.Context, userID uuid.UUID, state []byte) error {
+	if len(state) > CardDraftStateMaxBytes {
+		return ErrCardDraftStateTooLarge
+	}
+	if !json.Valid(state) {
+		return ErrCardDraftStateInvalid
+	}
+	if err := s.redis.Set(ctx, cardDraftStateKey(userID), string(state), CardDraftStateTTL); err != nil {
+		return fmt.Errorf("saving card draft state: %w", err)
+	}
+	return nil
+}
+
+// Get returns the user's saved state, or ErrCardDraftStateNotFound.
+func (s *CardDraftStateService) Get(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	value, err := s.redis.Get(ctx, cardDraftStateKey(userID))
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCardDraftStateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading card draft state: %w", err)
+	}
+	return []byte(value), nil
+}
+
+// Clear drops the user's saved state. Clearing a missing state is not an
+// error.
+func (s *CardDraftStateService) Clear(ctx context.Context, userID uuid.UUID) error {
+	if err := s.redis.Del(ctx, cardDraftStateKey(userID)); err != nil {
+		return fmt.Errorf("clearing card draft state: %w", err)
+	}
+	return nil
+}
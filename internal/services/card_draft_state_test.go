@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCardDraftStateService_SaveGetClear(t *testing.T) {
+	ctx := context.Background()
+	redis := newMemoryRedis()
+	svc := NewCardDraftStateService(redis)
+	userID := uuid.New()
+
+	if _, err := svc.Get(ctx, userID); !errors.Is(err, ErrCardDraftStateNotFound) {
+		t.Fatalf("expected ErrCardDraftStateNotFound, got %v", err)
+	}
+
+	state := []byte(`{"step":1}`)
+	if err := svc.Save(ctx, userID, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl := redis.ttls[cardDraftStateKey(userID)]; ttl != CardDraftStateTTL {
+		t.Fatalf("expected a %v TTL, got %v", CardDraftStateTTL, ttl)
+	}
+	got, err := svc.Get(ctx, userID)
+	if err != nil || string(got) != string(state) {
+		t.Fatalf("expected %s, got %s, %v", state, got, err)
+	}
+	if _, err := svc.Get(ctx, uuid.New()); !errors.Is(err, ErrCardDraftStateNotFound) {
+		t.Fatalf("expected states to be per user, got %v", err)
+	}
+
+	if err := svc.Clear(ctx, userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.Get(ctx, userID); !errors.Is(err, ErrCardDraftStateNotFound) {
+		t.Fatalf("expected ErrCardDraftStateNotFound after clear, got %v", err)
+	}
+	if err := svc.Clear(ctx, userID); err != nil {
+		t.Fatalf("expected clearing twice to succeed, got %v", err)
+	}
+}
+
+func TestCardDraftStateService_ValidatesSizeAndJSON(t *testing.T) {
+	ctx := context.Background()
+	redis := newMemoryRedis()
+	svc := NewCardDraftStateService(redis)
+	userID := uuid.New()
+
+	atLimit := []byte(`"` + strings.Repeat("a", CardDraftStateMaxBytes-2) + `"`)
+	if err := svc.Save(ctx, userID, atLimit); err != nil {
+		t.Fatalf("expected a state at the cap to save, got %v", err)
+	}
+	overLimit := []byte(`"` + strings.Repeat("a", CardDraftStateMaxBytes-1) + `"`)
+	if err := svc.Save(ctx, userID, overLimit); !errors.Is(err, ErrCardDraftStateTooLarge) {
+		t.Fatalf("expected ErrCardDraftStateTooLarge, got %v", err)
+	}
+	for _, invalid := range []string{"", "{", "not json", `{"a":1}{"b":2}`} {
+		if err := svc.Save(ctx, userID, []byte(invalid)); !errors.Is(err, ErrCardDraftStateInvalid) {
+			t.Fatalf("%q: expected ErrCardDraftStateInvalid, got %v", invalid, err)
+		}
+	}
+	if got, _ := svc.Get(ctx, userID); string(got) != string(atLimit) {
+		t.Fatal("expected rejected saves to keep the previous state")
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// Card export widths in pixels. Exports keep the card image's aspect ratio.
+const (
+	CardExportWidthDefault = 1080
+	CardExportWidthLarge   = 2048
+)
+
+// cardExportMemoryBudget bounds the bytes allocated to render one export,
+// including the PDF wrapper, at the largest grid and width.
+const cardExportMemoryBudget = 96 << 20
+
+// PDF page for printable exports: US Letter landscape, in points, with a
+// half-inch margin around the image.
+const (
+	pdfPageWidth  = 792
+	pdfPageHeight = 612
+	pdfPageMargin = 36
+)
+
+// IsValidCardExportWidth reports whether width is a supported export width.
+func IsValidCardExportWidth(width int) bool {
+	return width == CardExportWidthDefault || width == CardExportWidthLarge
+}
+
+// RenderCardExportPNG renders the card with the same layout as reminder and
+// share images, scaled to width pixels wide.
+func RenderCardExportPNG(card models.BingoCard, items []models.BingoItem, opts RenderOptions, width int) ([]byte, error) {
+	img, err := renderCardExportImage(card, items, opts, width)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderCardExportPDF renders the card image at width pixels wide on a single
+// printable PDF page.
+func RenderCardExportPDF(card models.BingoCard, items []models.BingoItem, opts RenderOptions, width int) ([]byte, error) {
+	img, err := renderCardExportImage(card, items, opts, width)
+	if err != nil {
+		return nil, err
+	}
+	return encodeImagePDF(img)
+}
+
+func renderCardExportImage(card models.BingoCard, items []models.BingoItem, opts RenderOptions, width int) (*image.RGBA, error) {
+	if !IsValidCardExportWidth(width) {
+		return nil, fmt.Errorf("unsupported export width %d", width)
+	}
+	base, err := renderCardImage(card, items, opts)
+	if err != nil {
+		return nil, err
+	}
+	height := base.Bounds().Dy() * width / base.Bounds().Dx()
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), base, base.Bounds(), xdraw.Src, nil)
+	return scaled, nil
+}
+
+// encodeImagePDF writes a minimal one-page PDF showing img centered on the
+// page. The pixels are stored as a Flate-compressed RGB image XObject, which
+// every PDF reader supports without extra dependencies.
+func encodeImagePDF(img *image.RGBA) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	var pixels bytes.Buffer
+	zw := zlib.NewWriter(&pixels)
+	row := make([]byte, width*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		src := img.Pix[img.PixOffset(bounds.Min.X, y):]
+		for x := 0; x < width; x++ {
+			copy(row[x*3:x*3+3], src[x*4:x*4+3])
+		}
+		if _, err := zw.Write(row); err != nil {
+			return nil, fmt.Errorf("compress pdf image: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compress pdf image: %w", err)
+	}
+
+	scale := math.Min(float64(pdfPageWidth-2*pdfPageMargin)/float64(width), float64(pdfPageHeight-2*pdfPageMargin)/float64(height))
+	drawWidth, drawHeight := float64(width)*scale, float64(height)*scale
+	content := fmt.Sprintf("q %.2f 0 0 %.2f %.2f %.2f cm /Im0 Do Q\n",
+		drawWidth, drawHeight, (pdfPageWidth-drawWidth)/2, (pdfPageHeight-drawHeight)/2)
+
+	var out bytes.Buffer
+	offsets := make([]int, 0, 5)
+	beginObject := func(n int) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n", n)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	beginObject(1)
+	out.WriteString("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	beginObject(2)
+	out.WriteString("<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n")
+	beginObject(3)
+	fmt.Fprintf(&out, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /XObject << /Im0 4 0 R >> >> /Contents 5 0 R >>\nendobj\n", pdfPageWidth, pdfPageHeight)
+	beginObject(4)
+	fmt.Fprintf(&out, "<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n", width, height, pixels.Len())
+	out.Write(pixels.Bytes())
+	out.WriteString("\nendstream\nendobj\n")
+	beginObject(5)
+	fmt.Fprintf(&out, "<< /Length %d >>\nstream\n%sendstream\nendobj\n", len(content), content)
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes(), nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image/png"
+	"io"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func exportTestCard(gridSize int) (models.BingoCard, []models.BingoItem) {
+	card := models.BingoCard{ID: uuid.New(), UserID: uuid.New(), Year: 2026, GridSize: gridSize}
+	items := make([]models.BingoItem, 0, gridSize*gridSize)
+	for i := 0; i < gridSize*gridSize; i++ {
+		items = append(items, models.BingoItem{
+			ID:          uuid.New(),
+			CardID:      card.ID,
+			Position:    i,
+			Content:     strings.Repeat(fmt.Sprintf("Goal %d ", i), models.MaxItemContentLength/8),
+			IsCompleted: i%2 == 0,
+		})
+	}
+	return card, items
+}
+
+func TestRenderCardExportPNG_ScalesToWidth(t *testing.T) {
+	card, items := exportTestCard(3)
+	for _, width := range []int{CardExportWidthDefault, CardExportWidthLarge} {
+		pngBytes, err := RenderCardExportPNG(card, items, RenderOptions{ShowCompletions: true}, width)
+		if err != nil {
+			t.Fatalf("width %d: unexpected error: %v", width, err)
+		}
+		img, err := png.Decode(bytes.NewReader(pngBytes))
+		if err != nil {
+			t.Fatalf("width %d: expected valid png: %v", width, err)
+		}
+		if got, want := img.Bounds().Dx(), width; got != want {
+			t.Fatalf("expected width %d, got %d", want, got)
+		}
+		if got, want := img.Bounds().Dy(), renderHeight*width/renderWidth; got != want {
+			t.Fatalf("expected height %d to keep the aspect ratio, got %d", want, got)
+		}
+	}
+
+	if _, err := RenderCardExportPNG(card, items, RenderOptions{}, 4096); err == nil {
+		t.Fatal("expected an error for an unsupported width")
+	}
+}
+
+func TestRenderCardExportPDF_SinglePageWithImage(t *testing.T) {
+	card, items := exportTestCard(2)
+	pdf, err := RenderCardExportPDF(card, items, RenderOptions{}, CardExportWidthDefault)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc := string(pdf)
+	if !strings.HasPrefix(doc, "%PDF-1.4\n") || !strings.HasSuffix(doc, "%%EOF\n") {
+		t.Fatal("expected a complete PDF document")
+	}
+	if strings.Count(doc, "/Type /Page ") != 1 {
+		t.Fatal("expected exactly one page")
+	}
+	height := renderHeight * CardExportWidthDefault / renderWidth
+	if !strings.Contains(doc, fmt.Sprintf("/Width %d /Height %d", CardExportWidthDefault, height)) {
+		t.Fatal("expected the image XObject at the export size")
+	}
+
+	// Every xref entry must point at its object, and startxref at the table.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(doc)
+	if startxref == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(startxref[1])
+	if !strings.HasPrefix(doc[xref:], "xref\n0 6\n") {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(doc[xref:], -1)
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 xref entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !strings.HasPrefix(doc[offset:], want) {
+			t.Fatalf("xref entry %d does not point at object %d", i, i+1)
+		}
+	}
+
+	// The image stream inflates to exactly one RGB triple per pixel.
+	start := strings.Index(doc, ">>\nstream\n") + len(">>\nstream\n")
+	end := strings.Index(doc[start:], "\nendstream") + start
+	zr, err := zlib.NewReader(bytes.NewReader(pdf[start:end]))
+	if err != nil {
+		t.Fatalf("expected a zlib image stream: %v", err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("unexpected error inflating image: %v", err)
+	}
+	if len(raw) != CardExportWidthDefault*height*3 {
+		t.Fatalf("expected %d image bytes, got %d", CardExportWidthDefault*height*3, len(raw))
+	}
+}
+
+func TestRenderCardExport_LargestGridStaysWithinMemoryBudget(t *testing.T) {
+	card, items := exportTestCard(models.MaxGridSize)
+	// Parse the font up front so the measurement covers only the export.
+	if _, err := RenderReminderPNG(card, items, RenderOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, render := range []struct {
+		name string
+		fn   func(models.BingoCard, []models.BingoItem, RenderOptions, int) ([]byte, error)
+	}{
+		{"png", RenderCardExportPNG},
+		{"pdf", RenderCardExportPDF},
+	} {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		if _, err := render.fn(card, items, RenderOptions{ShowCompletions: true}, CardExportWidthLarge); err != nil {
+			t.Fatalf("%s: unexpected error: %v", render.name, err)
+		}
+		runtime.ReadMemStats(&after)
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > cardExportMemoryBudget {
+			t.Fatalf("%s: rendering a %dx%d card at %dpx allocated %d bytes, budget is %d",
+				render.name, models.MaxGridSize, models.MaxGridSize, CardExportWidthLarge, allocated, cardExportMemoryBudget)
+		}
+	}
+}
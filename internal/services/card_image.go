@@ -21,8 +21,25 @@ import (
 // RenderOptions controls reminder image rendering behavior.
 type RenderOptions struct {
 	ShowCompletions bool
+	// HideProgress leaves out the completion and bingo counts under the
+	// title, for images that must not reveal progress at all.
+	HideProgress bool
+	// Theme is models.ImageThemeLight (the default when empty) or
+	// models.ImageThemeDark.
+	Theme string
 }
 
+// Card image layout. Cell geometry depends only on the grid size, so content
+// fit can be checked without rendering a full image.
+const (
+	renderWidth        = 1200
+	renderHeight       = 630
+	renderPadding      = 40
+	renderHeaderHeight = 80
+	renderCellPadding  = 10
+	renderBodyFontSize = 18
+)
+
 var (
 	fontOnce      sync.Once
 	parsedGoFont  *opentype.Font
@@ -31,15 +48,30 @@ var (
 
 // RenderReminderPNG renders a bingo card PNG for reminder emails.
 func RenderReminderPNG(card models.BingoCard, items []models.BingoItem, opts RenderOptions) ([]byte, error) {
-	const width = 1200
-	const height = 630
-	const padding = 40
-	const headerHeight = 80
+	img, err := renderCardImage(card, items, opts)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderCardImage draws the card image shared by reminder emails, share
+// previews and card exports.
+func renderCardImage(card models.BingoCard, items []models.BingoItem, opts RenderOptions) (*image.RGBA, error) {
+	const width = renderWidth
+	const height = renderHeight
+	const padding = renderPadding
+	const headerHeight = renderHeaderHeight
 	const borderWidth = 2
-	const cellPadding = 10
+	const cellPadding = renderCellPadding
 
+	palette := opts.palette()
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
-	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{0xFA, 0xF9, 0xF7, 0xFF}}, image.Point{}, draw.Src)
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: palette.background}, image.Point{}, draw.Src)
 
 	headerFace, err := newFontFace(32)
 	if err != nil {
@@ -47,7 +79,7 @@ func RenderReminderPNG(card models.BingoCard, items []models.BingoItem, opts Ren
 	}
 	defer func() { _ = headerFace.Close() }()
 
-	bodyFace, err := newFontFace(18)
+	bodyFace, err := newFontFace(renderBodyFontSize)
 	if err != nil {
 		return nil, err
 	}
@@ -63,17 +95,17 @@ func RenderReminderPNG(card models.BingoCard, items []models.BingoItem, opts Ren
 	stats := buildReminderStats(&card, items)
 	statsLine := fmt.Sprintf("%d/%d complete - %s", stats.Completed, stats.Total, pluralizeBingo(stats.Bingos))
 
-	drawText(img, headerFace, padding, 44, cardName, color.RGBA{0x2D, 0x2D, 0x2D, 0xFF})
-	drawText(img, statsFace, padding, 70, statsLine, color.RGBA{0x6B, 0x6B, 0x6B, 0xFF})
+	drawText(img, headerFace, padding, 44, cardName, palette.title)
+	if !opts.HideProgress {
+		drawText(img, statsFace, padding, 70, statsLine, palette.stats)
+	}
 
 	gridSize := card.GridSize
 	if !models.IsValidGridSize(gridSize) {
 		gridSize = models.MaxGridSize
 	}
 
-	gridAvailableWidth := width - padding*2
-	gridAvailableHeight := height - headerHeight - padding*2
-	cellSize := minInt(gridAvailableWidth/gridSize, gridAvailableHeight/gridSize)
+	cellSize := renderCellSize(gridSize)
 	gridWidth := cellSize * gridSize
 	gridLeft := (width - gridWidth) / 2
 	gridTop := headerHeight + padding
@@ -98,46 +130,344 @@ func RenderReminderPNG(card models.BingoCard, items []models.BingoItem, opts Ren
 				gridTop+(row+1)*cellSize,
 			)
 
-			bg := color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
-			textColor := color.RGBA{0x2D, 0x2D, 0x2D, 0xFF}
-			content := ""
-			completed := false
-
-			if pos == freePos {
-				content = "FREE"
-				completed = true
-				bg = color.RGBA{0xF1, 0xF0, 0xEB, 0xFF}
-			} else if item, ok := itemByPos[pos]; ok {
-				content = item.Content
-				completed = item.IsCompleted
-			}
-
-			if completed && opts.ShowCompletions {
-				bg = color.RGBA{0xD7, 0xF3, 0xE3, 0xFF}
-				textColor = color.RGBA{0x1B, 0x4D, 0x3E, 0xFF}
-			}
-
-			draw.Draw(img, rect, &image.Uniform{C: bg}, image.Point{}, draw.Src)
-			drawBorder(img, rect, borderWidth, color.RGBA{0x3A, 0x3A, 0x3A, 0xFF})
+			cell := newRenderCell(card, itemByPos, freePos, pos, opts)
+			draw.Draw(img, rect, &image.Uniform{C: cell.bg}, image.Point{}, draw.Src)
+			drawBorder(img, rect, borderWidth, palette.border)
+			drawDifficultyDots(img, rect, cell.difficulty, cell.textColor)
 
-			if strings.TrimSpace(content) == "" {
+			if strings.TrimSpace(cell.content) == "" {
 				continue
 			}
 
-			maxLines := 4
-			if gridSize >= 4 {
-				maxLines = 3
-			}
-
 			textRect := image.Rect(
 				rect.Min.X+cellPadding,
 				rect.Min.Y+cellPadding,
 				rect.Max.X-cellPadding,
 				rect.Max.Y-cellPadding,
 			)
-			lines := wrapText(bodyFace, content, textRect.Dx())
-			lines = clampLines(bodyFace, lines, maxLines, textRect.Dx())
-			drawWrappedText(img, bodyFace, textRect, lines, textColor)
+			lines, _ := fitCellText(bodyFace, cell.content, textRect.Dx(), cellMaxLines(gridSize))
+			drawWrappedText(img, bodyFace, textRect, lines, cell.textColor)
+		}
+	}
+
+	return img, nil
+}
+
+// renderPalette holds the card image colors shared by the PNG and SVG
+// renderers. Cell text must stay readable on its cell in both themes.
+type renderPalette struct {
+	background    color.RGBA
+	title         color.RGBA
+	stats         color.RGBA
+	border        color.RGBA
+	cell          color.RGBA
+	cellText      color.RGBA
+	freeCell      color.RGBA
+	completed     color.RGBA
+	completedText color.RGBA
+}
+
+var (
+	lightRenderPalette = renderPalette{
+		background:    color.RGBA{0xFA, 0xF9, 0xF7, 0xFF},
+		title:         color.RGBA{0x2D, 0x2D, 0x2D, 0xFF},
+		stats:         color.RGBA{0x6B, 0x6B, 0x6B, 0xFF},
+		border:        color.RGBA{0x3A, 0x3A, 0x3A, 0xFF},
+		cell:          color.RGBA{0xFF, 0xFF, 0xFF, 0xFF},
+		cellText:      color.RGBA{0x2D, 0x2D, 0x2D, 0xFF},
+		freeCell:      color.RGBA{0xF1, 0xF0, 0xEB, 0xFF},
+		completed:     color.RGBA{0xD7, 0xF3, 0xE3, 0xFF},
+		completedText: color.RGBA{0x1B, 0x4D, 0x3E, 0xFF},
+	}
+	darkRenderPalette = renderPalette{
+		background:    color.RGBA{0x1C, 0x1D, 0x21, 0xFF},
+		title:         color.RGBA{0xF2, 0xF1, 0xEE, 0xFF},
+		stats:         color.RGBA{0xA9, 0xA8, 0xA4, 0xFF},
+		border:        color.RGBA{0x55, 0x56, 0x5C, 0xFF},
+		cell:          color.RGBA{0x28, 0x29, 0x2E, 0xFF},
+		cellText:      color.RGBA{0xE8, 0xE7, 0xE3, 0xFF},
+		freeCell:      color.RGBA{0x34, 0x35, 0x3A, 0xFF},
+		completed:     color.RGBA{0x1E, 0x4D, 0x38, 0xFF},
+		completedText: color.RGBA{0xD7, 0xF3, 0xE3, 0xFF},
+	}
+)
+
+// palette returns the colors for the requested theme.
+func (opts RenderOptions) palette() renderPalette {
+	if opts.Theme == models.ImageThemeDark {
+		return darkRenderPalette
+	}
+	return lightRenderPalette
+}
+
+// renderCell is what a card image draws in one grid square.
+type renderCell struct {
+	content    string
+	difficulty *string
+	bg         color.RGBA
+	textColor  color.RGBA
+}
+
+func newRenderCell(card models.BingoCard, itemByPos map[int]models.BingoItem, freePos, pos int, opts RenderOptions) renderCell {
+	palette := opts.palette()
+	cell := renderCell{
+		bg:        palette.cell,
+		textColor: palette.cellText,
+	}
+	completed := false
+	if pos == freePos {
+		cell.content = models.FreeSpaceLabel(card.FreeSpaceText)
+		completed = true
+		cell.bg = palette.freeCell
+	} else if item, ok := itemByPos[pos]; ok {
+		cell.content = item.Content
+		completed = item.IsCompleted
+		cell.difficulty = item.Difficulty
+	}
+	if completed && opts.ShowCompletions {
+		cell.bg = palette.completed
+		cell.textColor = palette.completedText
+	}
+	return cell
+}
+
+// RenderReminderPlaceholderPNG renders the generic image served in place of
+// a card once a reminder image token has used up its views.
+func RenderReminderPlaceholderPNG(brandName string) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, renderWidth, renderHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{0xFA, 0xF9, 0xF7, 0xFF}}, image.Point{}, draw.Src)
+
+	headerFace, err := newFontFace(40)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = headerFace.Close() }()
+
+	bodyFace, err := newFontFace(22)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = bodyFace.Close() }()
+
+	drawCenteredText(img, headerFace, renderHeight/2-10, brandName, color.RGBA{0x2D, 0x2D, 0x2D, 0xFF})
+	drawCenteredText(img, bodyFace, renderHeight/2+34, "Open "+brandName+" to see your latest card", color.RGBA{0x6B, 0x6B, 0x6B, 0xFF})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// profileImageMaxCards is how many cards the profile OG image lists.
+const profileImageMaxCards = 4
+
+// RenderProfilePNG renders the OpenGraph image for a public profile: the
+// username and a progress bar for each of the most recent cards.
+func RenderProfilePNG(profile models.PublicProfile, brandName string) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, renderWidth, renderHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{0xFA, 0xF9, 0xF7, 0xFF}}, image.Point{}, draw.Src)
+
+	headerFace, err := newFontFace(40)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = headerFace.Close() }()
+
+	bodyFace, err := newFontFace(24)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = bodyFace.Close() }()
+
+	statsFace, err := newFontFace(18)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = statsFace.Close() }()
+
+	ink := color.RGBA{0x2D, 0x2D, 0x2D, 0xFF}
+	muted := color.RGBA{0x6B, 0x6B, 0x6B, 0xFF}
+	track := color.RGBA{0xE7, 0xE5, 0xE0, 0xFF}
+	fill := color.RGBA{0x22, 0xC5, 0x5E, 0xFF}
+
+	const padding = 60
+	drawText(img, headerFace, padding, 90, profile.Username+" on "+brandName, ink)
+
+	if len(profile.Cards) == 0 {
+		drawText(img, bodyFace, padding, 160, "No cards to show yet", muted)
+	}
+
+	barWidth := renderWidth - padding*2
+	for i, card := range profile.Cards {
+		if i == profileImageMaxCards {
+			break
+		}
+		top := 150 + i*115
+		name, _ := fitCellText(bodyFace, card.DisplayName(), barWidth, 1)
+		if len(name) > 0 {
+			drawText(img, bodyFace, padding, top+24, name[0], ink)
+		}
+		bar := image.Rect(padding, top+38, padding+barWidth, top+58)
+		draw.Draw(img, bar, &image.Uniform{C: track}, image.Point{}, draw.Src)
+		if done := barWidth * card.Percent() / 100; done > 0 {
+			draw.Draw(img, image.Rect(bar.Min.X, bar.Min.Y, bar.Min.X+done, bar.Max.Y), &image.Uniform{C: fill}, image.Point{}, draw.Src)
+		}
+		stats := fmt.Sprintf("%d/%d complete - %s", card.CompletedItems, card.TotalItems, pluralizeBingo(card.Bingos))
+		drawText(img, statsFace, padding, top+84, stats, muted)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Widget image layout: a mini-grid on the left and the progress numbers on
+// the right, sized for phone widgets.
+const (
+	widgetWidth    = 720
+	widgetHeight   = 240
+	widgetPadding  = 20
+	widgetGridSide = widgetHeight - widgetPadding*2
+)
+
+// RenderWidgetPNG renders the compact progress strip served to home- and
+// lock-screen widgets: completed/total, bingo count and a mini-grid of
+// completed squares. It draws no goal text.
+func RenderWidgetPNG(card models.BingoCard, items []models.BingoItem) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, widgetWidth, widgetHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{0xFA, 0xF9, 0xF7, 0xFF}}, image.Point{}, draw.Src)
+
+	nameFace, err := newFontFace(24)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = nameFace.Close() }()
+
+	countFace, err := newFontFace(64)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = countFace.Close() }()
+
+	statsFace, err := newFontFace(24)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = statsFace.Close() }()
+
+	ink := color.RGBA{0x2D, 0x2D, 0x2D, 0xFF}
+	muted := color.RGBA{0x6B, 0x6B, 0x6B, 0xFF}
+	empty := color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	done := color.RGBA{0x22, 0xC5, 0x5E, 0xFF}
+	free := color.RGBA{0xE7, 0xE5, 0xE0, 0xFF}
+
+	gridSize := card.GridSize
+	if !models.IsValidGridSize(gridSize) {
+		gridSize = models.MaxGridSize
+	}
+	completedAt := make(map[int]bool, len(items))
+	for _, item := range items {
+		if item.IsCompleted {
+			completedAt[item.Position] = true
+		}
+	}
+	freePos := -1
+	if card.HasFreeSpace && card.FreeSpacePos != nil {
+		freePos = *card.FreeSpacePos
+	}
+
+	const gap = 4
+	cell := (widgetGridSide - gap*(gridSize-1)) / gridSize
+	for row := 0; row < gridSize; row++ {
+		for col := 0; col < gridSize; col++ {
+			pos := row*gridSize + col
+			x := widgetPadding + col*(cell+gap)
+			y := widgetPadding + row*(cell+gap)
+			rect := image.Rect(x, y, x+cell, y+cell)
+			fill := empty
+			switch {
+			case pos == freePos:
+				fill = free
+			case completedAt[pos]:
+				fill = done
+			}
+			draw.Draw(img, rect, &image.Uniform{C: fill}, image.Point{}, draw.Src)
+			drawBorder(img, rect, 1, color.RGBA{0x3A, 0x3A, 0x3A, 0xFF})
+		}
+	}
+
+	textLeft := widgetPadding*2 + widgetGridSide
+	textWidth := widgetWidth - textLeft - widgetPadding
+	stats := buildReminderStats(&card, items)
+	if name, _ := fitCellText(nameFace, card.DisplayName(), textWidth, 1); len(name) > 0 {
+		drawText(img, nameFace, textLeft, 56, name[0], muted)
+	}
+	drawText(img, countFace, textLeft, 140, fmt.Sprintf("%d/%d", stats.Completed, stats.Total), ink)
+	drawText(img, statsFace, textLeft, 190, pluralizeBingo(stats.Bingos), muted)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderRecapPNG renders a year-end recap as a shareable image: completed
+// goals, bingos, the longest weekly streak, completion dates and the goals
+// still open.
+func RenderRecapPNG(recap models.CardRecap) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, renderWidth, renderHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{0xFA, 0xF9, 0xF7, 0xFF}}, image.Point{}, draw.Src)
+
+	headerFace, err := newFontFace(40)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = headerFace.Close() }()
+
+	countFace, err := newFontFace(72)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = countFace.Close() }()
+
+	bodyFace, err := newFontFace(24)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = bodyFace.Close() }()
+
+	ink := color.RGBA{0x2D, 0x2D, 0x2D, 0xFF}
+	muted := color.RGBA{0x6B, 0x6B, 0x6B, 0xFF}
+
+	const padding = 60
+	width := renderWidth - padding*2
+	if title, _ := fitCellText(headerFace, fmt.Sprintf("%s - %d recap", recap.DisplayName, recap.Year), width, 1); len(title) > 0 {
+		drawText(img, headerFace, padding, 90, title[0], ink)
+	}
+	drawText(img, countFace, padding, 190, fmt.Sprintf("%d/%d", recap.CompletedItems, recap.TotalItems), ink)
+	drawText(img, bodyFace, padding, 230, "goals completed", muted)
+
+	weeks := fmt.Sprintf("%d weeks", recap.LongestWeekStreak)
+	if recap.LongestWeekStreak == 1 {
+		weeks = "1 week"
+	}
+	drawText(img, bodyFace, padding, 290, pluralizeBingo(recap.BingosAchieved)+" - longest streak "+weeks, ink)
+	if recap.FirstCompletion != nil && recap.LastCompletion != nil {
+		dates := fmt.Sprintf("First completion %s, last %s",
+			recap.FirstCompletion.UTC().Format("Jan 2"), recap.LastCompletion.UTC().Format("Jan 2"))
+		drawText(img, bodyFace, padding, 330, dates, muted)
+	}
+
+	if len(recap.OldestOpenGoals) > 0 {
+		drawText(img, bodyFace, padding, 400, "Still to do:", ink)
+		for i, goal := range recap.OldestOpenGoals {
+			if line, _ := fitCellText(bodyFace, "- "+goal.Content, width, 1); len(line) > 0 {
+				drawText(img, bodyFace, padding, 440+i*40, line[0], muted)
+			}
 		}
 	}
 
@@ -148,26 +478,81 @@ func RenderReminderPNG(card models.BingoCard, items []models.BingoItem, opts Ren
 	return buf.Bytes(), nil
 }
 
-func newFontFace(size float64) (*opentype.Face, error) {
+func drawCenteredText(img draw.Image, face font.Face, y int, text string, clr color.Color) {
+	width := font.MeasureString(face, text).Ceil()
+	drawText(img, face, (img.Bounds().Dx()-width)/2, y, text, clr)
+}
+
+// ContentFitsCell reports whether content renders in a card image cell at
+// gridSize without being ellipsized.
+func ContentFitsCell(content string, gridSize int) (bool, error) {
+	if !models.IsValidGridSize(gridSize) {
+		gridSize = models.MaxGridSize
+	}
+	face, err := newFontFace(renderBodyFontSize)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = face.Close() }()
+
+	_, truncated := fitCellText(face, content, renderCellSize(gridSize)-2*renderCellPadding, cellMaxLines(gridSize))
+	return !truncated, nil
+}
+
+// contentTruncated reports whether content will be ellipsized in card images.
+// The check is advisory, so a font error reports no truncation.
+func contentTruncated(content string, gridSize int) bool {
+	fits, err := ContentFitsCell(content, gridSize)
+	return err == nil && !fits
+}
+
+func renderCellSize(gridSize int) int {
+	gridAvailableWidth := renderWidth - renderPadding*2
+	gridAvailableHeight := renderHeight - renderHeaderHeight - renderPadding*2
+	return minInt(gridAvailableWidth/gridSize, gridAvailableHeight/gridSize)
+}
+
+func cellMaxLines(gridSize int) int {
+	if gridSize >= 4 {
+		return 3
+	}
+	return 4
+}
+
+// fitCellText wraps text to maxWidth and clamps it to maxLines, ending the last
+// line with an ellipsis when content is cut. Words wider than a line are broken
+// at rune boundaries so nothing overflows the cell. The result depends only on
+// the inputs, so every renderer lays out a given cell identically.
+func fitCellText(face font.Face, text string, maxWidth, maxLines int) ([]string, bool) {
+	lines := wrapText(face, text, maxWidth)
+	if len(lines) <= maxLines {
+		return lines, false
+	}
+	return clampLines(face, lines, maxLines, maxWidth), true
+}
+
+// newFontFace returns the bundled Go font at size, backed by any fallback
+// fonts loaded with LoadFallbackFonts for runes the Go font cannot draw.
+func newFontFace(size float64) (font.Face, error) {
 	fontOnce.Do(func() {
 		parsedGoFont, parsedGoError = opentype.Parse(goregular.TTF)
 	})
 	if parsedGoError != nil {
 		return nil, fmt.Errorf("parse font: %w", parsedGoError)
 	}
-	face, err := opentype.NewFace(parsedGoFont, &opentype.FaceOptions{
+	opts := &opentype.FaceOptions{
 		Size:    size,
 		DPI:     72,
 		Hinting: font.HintingFull,
-	})
+	}
+	if fallbacks := loadedFallbackFonts(); len(fallbacks) > 0 {
+		return newFallbackFace(parsedGoFont, fallbacks, opts)
+	}
+	face, err := opentype.NewFace(parsedGoFont, opts)
 	if err != nil {
 		return nil, fmt.Errorf("load font face: %w", err)
 	}
-	otFace, ok := face.(*opentype.Face)
-	if !ok {
-		return nil, fmt.Errorf("load font face: unexpected type")
-	}
-	return otFace, nil
+	return face, nil
 }
 
 func drawText(img draw.Image, face font.Face, x, y int, text string, clr color.Color) {
@@ -177,7 +562,45 @@ func drawText(img draw.Image, face font.Face, x, y int, text string, clr color.C
 		Face: face,
 		Dot:  fixed.P(x, y),
 	}
-	d.DrawString(text)
+	d.DrawString(visualOrder(text))
+}
+
+// drawDifficultyDots marks a tagged cell with one dot for easy, two for medium
+// and three for hard goals in its top-right corner.
+func drawDifficultyDots(img draw.Image, cell image.Rectangle, difficulty *string, clr color.Color) {
+	count := difficultyDotCount(difficulty)
+	radius := maxInt(cell.Dx()/48, 2)
+	gap := radius
+	y := cell.Min.Y + radius*3
+	x := cell.Max.X - radius*3
+	for i := 0; i < count; i++ {
+		drawDot(img, x-i*(2*radius+gap), y, radius, clr)
+	}
+}
+
+func difficultyDotCount(difficulty *string) int {
+	if difficulty == nil {
+		return 0
+	}
+	switch *difficulty {
+	case models.DifficultyEasy:
+		return 1
+	case models.DifficultyMedium:
+		return 2
+	case models.DifficultyHard:
+		return 3
+	}
+	return 0
+}
+
+func drawDot(img draw.Image, cx, cy, r int, clr color.Color) {
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if dx*dx+dy*dy <= r*r {
+				img.Set(cx+dx, cy+dy, clr)
+			}
+		}
+	}
 }
 
 func drawBorder(img draw.Image, rect image.Rectangle, width int, clr color.Color) {
@@ -189,28 +612,49 @@ func drawBorder(img draw.Image, rect image.Rectangle, width int, clr color.Color
 }
 
 func wrapText(face font.Face, text string, maxWidth int) []string {
-	words := strings.Fields(text)
-	if len(words) == 0 {
-		return []string{}
-	}
-
 	d := &font.Drawer{Face: face}
 	lines := []string{}
-	current := words[0]
+	current := ""
 
-	for _, word := range words[1:] {
-		test := current + " " + word
-		if d.MeasureString(test).Ceil() <= maxWidth {
-			current = test
-			continue
+	for _, word := range strings.Fields(text) {
+		for _, part := range splitWord(d, word, maxWidth) {
+			if current == "" {
+				current = part
+				continue
+			}
+			test := current + " " + part
+			if d.MeasureString(test).Ceil() <= maxWidth {
+				current = test
+				continue
+			}
+			lines = append(lines, current)
+			current = part
 		}
+	}
+	if current != "" {
 		lines = append(lines, current)
-		current = word
 	}
-	lines = append(lines, current)
 	return lines
 }
 
+// splitWord breaks a word wider than maxWidth into rune-aligned pieces that
+// each fit. A single rune wider than maxWidth is kept as its own piece.
+func splitWord(d *font.Drawer, word string, maxWidth int) []string {
+	if d.MeasureString(word).Ceil() <= maxWidth {
+		return []string{word}
+	}
+	runes := []rune(word)
+	parts := []string{}
+	start := 0
+	for end := start + 1; end <= len(runes); end++ {
+		if end-start > 1 && d.MeasureString(string(runes[start:end])).Ceil() > maxWidth {
+			parts = append(parts, string(runes[start:end-1]))
+			start = end - 1
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
+
 func clampLines(face font.Face, lines []string, maxLines int, maxWidth int) []string {
 	if len(lines) <= maxLines {
 		return lines
@@ -245,6 +689,13 @@ func drawWrappedText(img draw.Image, face font.Face, rect image.Rectangle, lines
 	}
 }
 
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func minInt(a, b int) int {
 	if a < b {
 		return a
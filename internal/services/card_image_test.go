@@ -2,14 +2,18 @@ package services
 
 import (
 	"bytes"
+	"image"
 	"image/color"
 	"image/png"
+	"math"
 	"strings"
 	"testing"
+	"time"
 	"unicode/utf8"
 
 	"github.com/google/uuid"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 
 	"golang.org/x/image/font"
@@ -137,3 +141,336 @@ func TestRenderReminderPNG_InvalidGridSizeFallsBackToMax(t *testing.T) {
 		t.Fatalf("expected 1200x630 image, got %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
 	}
 }
+
+func TestRenderReminderPNG_UsesCustomFreeSpaceText(t *testing.T) {
+	free := 4
+	card := models.BingoCard{
+		ID:           uuid.New(),
+		UserID:       uuid.New(),
+		Year:         2025,
+		GridSize:     3,
+		HasFreeSpace: true,
+		FreeSpacePos: &free,
+	}
+
+	defaultOut, err := RenderReminderPNG(card, nil, RenderOptions{ShowCompletions: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := strings.Repeat("W", models.MaxFreeSpaceTextLength)
+	card.FreeSpaceText = &text
+	customOut, err := RenderReminderPNG(card, nil, RenderOptions{ShowCompletions: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Equal(defaultOut, customOut) {
+		t.Fatal("expected custom free space text to change the rendered image")
+	}
+}
+
+func TestFitCellText_PathologicalInputsStayInsideCell(t *testing.T) {
+	face, err := newFontFace(renderBodyFontSize)
+	if err != nil {
+		t.Fatalf("load font: %v", err)
+	}
+	defer func() { _ = face.Close() }()
+	d := &font.Drawer{Face: face}
+
+	inputs := map[string]string{
+		"no spaces":   strings.Repeat("supercalifragilistic", 25),
+		"emoji heavy": strings.Repeat("🎉🏃‍♀️📚✈️", 60),
+		"rtl":         strings.Repeat("هدف جديد للسنة القادمة ", 20),
+		"hebrew":      strings.Repeat("לקרוא שנים עשר ספרים ", 20),
+		"mixed":       "Read 12 books 📚 " + strings.Repeat("x", 200) + " קריאה",
+	}
+
+	for name, input := range inputs {
+		for gridSize := models.MinGridSize; gridSize <= models.MaxGridSize; gridSize++ {
+			maxWidth := renderCellSize(gridSize) - 2*renderCellPadding
+			maxLines := cellMaxLines(gridSize)
+
+			lines, truncated := fitCellText(face, input, maxWidth, maxLines)
+			if len(lines) == 0 || len(lines) > maxLines {
+				t.Fatalf("%s/%d: expected 1..%d lines, got %d", name, gridSize, maxLines, len(lines))
+			}
+			if !truncated {
+				t.Fatalf("%s/%d: expected oversized input to be truncated", name, gridSize)
+			}
+			if !strings.HasSuffix(lines[len(lines)-1], "...") {
+				t.Fatalf("%s/%d: expected ellipsis on last line, got %q", name, gridSize, lines[len(lines)-1])
+			}
+			for _, line := range lines {
+				if w := d.MeasureString(line).Ceil(); w > maxWidth {
+					t.Fatalf("%s/%d: line %q is %dpx wide, cell allows %dpx", name, gridSize, line, w, maxWidth)
+				}
+				if !utf8.ValidString(line) {
+					t.Fatalf("%s/%d: invalid UTF-8 in %q", name, gridSize, line)
+				}
+			}
+
+			again, _ := fitCellText(face, input, maxWidth, maxLines)
+			if strings.Join(again, "\n") != strings.Join(lines, "\n") {
+				t.Fatalf("%s/%d: expected deterministic layout", name, gridSize)
+			}
+		}
+	}
+}
+
+func TestContentFitsCell_DependsOnGridSize(t *testing.T) {
+	short := "Read 12 books"
+	for gridSize := models.MinGridSize; gridSize <= models.MaxGridSize; gridSize++ {
+		fits, err := ContentFitsCell(short, gridSize)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !fits {
+			t.Fatalf("expected %q to fit at grid size %d", short, gridSize)
+		}
+	}
+
+	medium := "Run a half marathon in under two hours and then celebrate with friends at the beach"
+	fitsSmallGrid, err := ContentFitsCell(medium, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fitsLargeGrid, err := ContentFitsCell(medium, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fitsSmallGrid || fitsLargeGrid {
+		t.Fatalf("expected content to fit a 2x2 cell but not a 5x5 cell, got %v/%v", fitsSmallGrid, fitsLargeGrid)
+	}
+
+	if fits, _ := ContentFitsCell(strings.Repeat("a", models.MaxItemContentLength), 2); fits {
+		t.Fatal("expected max-length content without spaces to be truncated")
+	}
+}
+
+func TestRenderReminderPNG_MaxLengthPathologicalContent(t *testing.T) {
+	card := models.BingoCard{ID: uuid.New(), UserID: uuid.New(), Year: 2025, GridSize: 5}
+	contents := []string{
+		strings.Repeat("W", models.MaxItemContentLength),
+		strings.Repeat("😀", models.MaxItemContentLength),
+		strings.Repeat("مرحبا ", models.MaxItemContentLength/6),
+	}
+	items := make([]models.BingoItem, 0, len(contents))
+	for i, content := range contents {
+		items = append(items, models.BingoItem{ID: uuid.New(), CardID: card.ID, Position: i, Content: content})
+	}
+
+	pngBytes, err := RenderReminderPNG(card, items, RenderOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(pngBytes)); err != nil {
+		t.Fatalf("expected valid png: %v", err)
+	}
+}
+
+func TestDrawDifficultyDots_OneDotPerLevel(t *testing.T) {
+	ink := color.RGBA{R: 0x2D, G: 0x2D, B: 0x2D, A: 0xFF}
+	cell := image.Rect(0, 0, 192, 192)
+	countInk := func(difficulty *string) int {
+		img := image.NewRGBA(cell)
+		drawDifficultyDots(img, cell, difficulty, ink)
+		n := 0
+		for y := cell.Min.Y; y < cell.Max.Y; y++ {
+			for x := cell.Min.X; x < cell.Max.X; x++ {
+				if img.RGBAAt(x, y) == ink {
+					n++
+				}
+			}
+		}
+		return n
+	}
+
+	easy, hard, unknown := models.DifficultyEasy, models.DifficultyHard, "extreme"
+	dot := countInk(&easy)
+	if dot == 0 {
+		t.Fatal("expected an easy dot")
+	}
+	if got := countInk(&hard); got != 3*dot {
+		t.Fatalf("expected three dots for hard, got %d pixels (one dot is %d)", got, dot)
+	}
+	if countInk(nil) != 0 || countInk(&unknown) != 0 {
+		t.Fatal("expected no dots for untagged items")
+	}
+}
+
+func TestRenderProfilePNG_RendersWithAndWithoutCards(t *testing.T) {
+	title := "A very long card title that should be clamped to fit the image width nicely"
+	profiles := []models.PublicProfile{
+		{Username: "alice"},
+		{Username: "bob", Cards: []models.PublicProfileCard{
+			{Year: 2026, Title: &title, GridSize: 5, CompletedItems: 12, TotalItems: 24, Bingos: 2},
+			{Year: 2025, GridSize: 3, CompletedItems: 8, TotalItems: 8, Bingos: 8},
+		}},
+	}
+	for _, profile := range profiles {
+		data, err := RenderProfilePNG(profile, config.DefaultBrandName)
+		if err != nil {
+			t.Fatalf("render %s: %v", profile.Username, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("decode %s: %v", profile.Username, err)
+		}
+		if img.Bounds().Dx() == 0 || img.Bounds().Dy() == 0 {
+			t.Fatalf("expected non-empty image for %s", profile.Username)
+		}
+	}
+}
+
+func TestRenderRecapPNG_RendersWithAndWithoutCompletions(t *testing.T) {
+	completed := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+	recaps := []models.CardRecap{
+		{Year: 2026, DisplayName: "2026 Bingo Card", TotalItems: 24, OldestOpenGoals: []models.RecapGoal{}},
+		{Year: 2026, DisplayName: strings.Repeat("Long title ", 20), CompletedItems: 20, TotalItems: 24, BingosAchieved: 5,
+			FirstCompletion: &completed, LastCompletion: &completed, LongestWeekStreak: 1,
+			OldestOpenGoals: []models.RecapGoal{{Content: "Learn to juggle"}, {Content: strings.Repeat("x", 200)}}},
+	}
+	for i, recap := range recaps {
+		data, err := RenderRecapPNG(recap)
+		if err != nil {
+			t.Fatalf("render %d: %v", i, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("decode %d: %v", i, err)
+		}
+		if img.Bounds().Dx() != renderWidth || img.Bounds().Dy() != renderHeight {
+			t.Fatalf("expected %dx%d image, got %v", renderWidth, renderHeight, img.Bounds())
+		}
+	}
+}
+
+func TestRenderWidgetPNG_MarksCompletedSquares(t *testing.T) {
+	freePos := 4
+	card := models.BingoCard{Year: 2026, GridSize: 3, HasFreeSpace: true, FreeSpacePos: &freePos}
+	items := []models.BingoItem{
+		{Position: 0, Content: "Done", IsCompleted: true},
+		{Position: 1, Content: "Open"},
+	}
+
+	data, err := RenderWidgetPNG(card, items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	if img.Bounds().Dx() != widgetWidth || img.Bounds().Dy() != widgetHeight {
+		t.Fatalf("expected %dx%d image, got %v", widgetWidth, widgetHeight, img.Bounds())
+	}
+
+	cell := (widgetGridSide - 4*2) / 3
+	sample := func(pos int) color.RGBA {
+		x := widgetPadding + (pos%3)*(cell+4) + cell/2
+		y := widgetPadding + (pos/3)*(cell+4) + cell/2
+		r, g, b, a := img.At(x, y).RGBA()
+		return color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(a >> 8)}
+	}
+	if got := sample(0); got != (color.RGBA{0x22, 0xC5, 0x5E, 0xFF}) {
+		t.Fatalf("expected completed square to be green, got %v", got)
+	}
+	if got := sample(1); got != (color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Fatalf("expected open square to be white, got %v", got)
+	}
+	if got := sample(freePos); got != (color.RGBA{0xE7, 0xE5, 0xE0, 0xFF}) {
+		t.Fatalf("expected free square to be grey, got %v", got)
+	}
+}
+
+// contrastRatio is the WCAG 2 contrast ratio between two opaque colors.
+func contrastRatio(a, b color.RGBA) float64 {
+	luminance := func(c color.RGBA) float64 {
+		channel := func(v uint8) float64 {
+			s := float64(v) / 255
+			if s <= 0.03928 {
+				return s / 12.92
+			}
+			return math.Pow((s+0.055)/1.055, 2.4)
+		}
+		return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+	}
+	la, lb := luminance(a), luminance(b)
+	return (math.Max(la, lb) + 0.05) / (math.Min(la, lb) + 0.05)
+}
+
+func TestRenderReminderPNG_ThemesKeepCellsReadable(t *testing.T) {
+	freePos := 4
+	card := models.BingoCard{ID: uuid.New(), UserID: uuid.New(), Year: 2026, GridSize: 3, HasFreeSpace: true, FreeSpacePos: &freePos}
+	items := []models.BingoItem{
+		{ID: uuid.New(), CardID: card.ID, Position: 0, Content: "Open goal"},
+		{ID: uuid.New(), CardID: card.ID, Position: 1, Content: "Done goal", IsCompleted: true},
+	}
+	cellSize := renderCellSize(3)
+	gridLeft := (renderWidth - cellSize*3) / 2
+	gridTop := renderHeaderHeight + renderPadding
+	cellRect := func(pos int) image.Rectangle {
+		x, y := gridLeft+(pos%3)*cellSize, gridTop+(pos/3)*cellSize
+		return image.Rect(x, y, x+cellSize, y+cellSize)
+	}
+	toRGBA := func(c color.Color) color.RGBA {
+		r, g, b, a := c.RGBA()
+		return color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(a >> 8)}
+	}
+
+	for theme, palette := range map[string]renderPalette{
+		"":                     lightRenderPalette,
+		models.ImageThemeLight: lightRenderPalette,
+		models.ImageThemeDark:  darkRenderPalette,
+	} {
+		data, err := RenderReminderPNG(card, items, RenderOptions{ShowCompletions: true, Theme: theme})
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", theme, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%q: decode png: %v", theme, err)
+		}
+		if got := toRGBA(img.At(5, 5)); got != palette.background {
+			t.Fatalf("%q: expected background %v, got %v", theme, palette.background, got)
+		}
+
+		cells := []struct {
+			name     string
+			pos      int
+			bg, text color.RGBA
+		}{
+			{"open", 0, palette.cell, palette.cellText},
+			{"completed", 1, palette.completed, palette.completedText},
+			{"free", 4, palette.completed, palette.completedText},
+		}
+		for _, cell := range cells {
+			rect := cellRect(cell.pos)
+			if got := toRGBA(img.At(rect.Min.X+3, rect.Min.Y+3)); got != cell.bg {
+				t.Fatalf("%q %s cell: expected background %v, got %v", theme, cell.name, cell.bg, got)
+			}
+			inked := 0
+			for y := rect.Min.Y; y < rect.Max.Y; y++ {
+				for x := rect.Min.X; x < rect.Max.X; x++ {
+					if toRGBA(img.At(x, y)) == cell.text {
+						inked++
+					}
+				}
+			}
+			if inked == 0 {
+				t.Fatalf("%q %s cell: expected text drawn in %v", theme, cell.name, cell.text)
+			}
+			if ratio := contrastRatio(cell.text, cell.bg); ratio < 4.5 {
+				t.Fatalf("%q %s cell: text contrast %.2f is below 4.5", theme, cell.name, ratio)
+			}
+		}
+		if palette.completed == palette.cell {
+			t.Fatalf("%q: expected completed cells shaded apart from open ones", theme)
+		}
+		for name, clr := range map[string]color.RGBA{"title": palette.title, "stats": palette.stats} {
+			if ratio := contrastRatio(clr, palette.background); ratio < 4.5 {
+				t.Fatalf("%q %s: contrast %.2f is below 4.5", theme, name, ratio)
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/unicode/bidi"
+)
+
+var (
+	fallbackFontsMu sync.RWMutex
+	fallbackFonts   []*opentype.Font
+)
+
+// LoadFallbackFonts parses every TrueType/OpenType font (.ttf, .otf, .ttc)
+// under dir and uses them, in path order, for glyphs the bundled font lacks.
+// Files that fail to parse are skipped and reported in the returned error;
+// fonts that did load stay in use. Color bitmap emoji fonts are not supported
+// by the rasterizer, so use a monochrome emoji font for emoji coverage.
+func LoadFallbackFonts(dir string) (int, error) {
+	var (
+		fonts []*opentype.Font
+		errs  []error
+	)
+	walkErr := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".ttf" && ext != ".otf" && ext != ".ttc" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("read font %s: %w", path, err))
+			return nil
+		}
+		parsed, err := parseFontFile(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parse font %s: %w", path, err))
+			return nil
+		}
+		fonts = append(fonts, parsed...)
+		return nil
+	})
+	if walkErr != nil {
+		return 0, fmt.Errorf("load fallback fonts: %w", walkErr)
+	}
+
+	setFallbackFonts(fonts)
+	return len(fonts), errors.Join(errs...)
+}
+
+func parseFontFile(data []byte) ([]*opentype.Font, error) {
+	collection, err := opentype.ParseCollection(data)
+	if err != nil {
+		return nil, err
+	}
+	fonts := make([]*opentype.Font, 0, collection.NumFonts())
+	for i := 0; i < collection.NumFonts(); i++ {
+		f, err := collection.Font(i)
+		if err != nil {
+			return nil, err
+		}
+		fonts = append(fonts, f)
+	}
+	return fonts, nil
+}
+
+func setFallbackFonts(fonts []*opentype.Font) {
+	fallbackFontsMu.Lock()
+	defer fallbackFontsMu.Unlock()
+	fallbackFonts = fonts
+}
+
+func loadedFallbackFonts() []*opentype.Font {
+	fallbackFontsMu.RLock()
+	defer fallbackFontsMu.RUnlock()
+	return fallbackFonts
+}
+
+// fallbackFace draws each rune with the first font that has a glyph for it.
+// Metrics come from the primary font so line layout does not shift when a
+// fallback is used. Like opentype.Face, it is not safe for concurrent use.
+type fallbackFace struct {
+	fonts []*opentype.Font
+	faces []font.Face
+	buf   sfnt.Buffer
+}
+
+func newFallbackFace(primary *opentype.Font, fallbacks []*opentype.Font, opts *opentype.FaceOptions) (font.Face, error) {
+	ff := &fallbackFace{}
+	for _, f := range append([]*opentype.Font{primary}, fallbacks...) {
+		face, err := opentype.NewFace(f, opts)
+		if err != nil {
+			_ = ff.Close()
+			return nil, fmt.Errorf("load font face: %w", err)
+		}
+		ff.fonts = append(ff.fonts, f)
+		ff.faces = append(ff.faces, face)
+	}
+	return ff, nil
+}
+
+func (f *fallbackFace) faceFor(r rune) font.Face {
+	for i, candidate := range f.fonts {
+		idx, err := candidate.GlyphIndex(&f.buf, r)
+		if err == nil && idx != 0 {
+			return f.faces[i]
+		}
+	}
+	return f.faces[0]
+}
+
+func (f *fallbackFace) Close() error {
+	var errs []error
+	for _, face := range f.faces {
+		errs = append(errs, face.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.faceFor(r).Glyph(dot, r)
+}
+
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphBounds(r)
+}
+
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphAdvance(r)
+}
+
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	face := f.faceFor(r0)
+	if face != f.faceFor(r1) {
+		return 0
+	}
+	return face.Kern(r0, r1)
+}
+
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
+
+// visualOrder reorders a single line of logically ordered text for
+// left-to-right drawing. Right-to-left runs are reversed (with mirrored
+// brackets) and, in a right-to-left paragraph, the runs themselves are laid
+// out from right to left. Lines without RTL characters are returned as is.
+// Arabic contextual shaping is not applied.
+func visualOrder(text string) (ordered string) {
+	if !containsRTL(text) {
+		return text
+	}
+	// The bidi package is incomplete upstream; never let it take down a render.
+	defer func() {
+		if recover() != nil {
+			ordered = text
+		}
+	}()
+
+	var opts []bidi.Option
+	rtlParagraph := baseDirectionRTL(text)
+	if rtlParagraph {
+		opts = append(opts, bidi.DefaultDirection(bidi.RightToLeft))
+	}
+	var p bidi.Paragraph
+	if _, err := p.SetString(text, opts...); err != nil {
+		return text
+	}
+	order, err := p.Order()
+	if err != nil {
+		return text
+	}
+
+	runs := make([]string, order.NumRuns())
+	for i := range runs {
+		run := order.Run(i)
+		if run.Direction() == bidi.RightToLeft {
+			runs[i] = bidi.ReverseString(run.String())
+		} else {
+			runs[i] = run.String()
+		}
+	}
+	if rtlParagraph {
+		for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+			runs[i], runs[j] = runs[j], runs[i]
+		}
+	}
+	return strings.Join(runs, "")
+}
+
+func containsRTL(text string) bool {
+	for _, r := range text {
+		if isStrongRTL(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// baseDirectionRTL reports whether the first strongly directional character
+// is right-to-left (Unicode bidi rules P2/P3).
+func baseDirectionRTL(text string) bool {
+	for _, r := range text {
+		if isStrongRTL(r) {
+			return true
+		}
+		if props, _ := bidi.LookupRune(r); props.Class() == bidi.L {
+			return false
+		}
+	}
+	return false
+}
+
+func isStrongRTL(r rune) bool {
+	props, _ := bidi.LookupRune(r)
+	class := props.Class()
+	return class == bidi.R || class == bidi.AL
+}
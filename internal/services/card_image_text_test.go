@@ -0,0 +1,148 @@
+package services
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestVisualOrder_MixedDirectionSnapshots(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "ltr unchanged", in: "Run a marathon", want: "Run a marathon"},
+		{name: "hebrew", in: "שלום", want: "םולש"},
+		{name: "hebrew inside ltr", in: "Learn עברית today", want: "Learn תירבע today"},
+		{name: "ltr inside hebrew", in: "שלום world", want: "world םולש"},
+		{name: "arabic with number", in: "سباق 5 كم", want: "مك 5 قابس"},
+		{name: "arabic inside ltr with brackets", in: "Read 12 كتب (books)", want: "Read 12 بتك (books)"},
+		{name: "mirrored brackets", in: "(שלום)", want: "(םולש)"},
+		{name: "leading emoji in rtl", in: "🎉 שלום", want: "םולש 🎉"},
+		{name: "trailing punctuation in rtl", in: "שלום, world!", want: "!world ,םולש"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := visualOrder(tt.in); got != tt.want {
+				t.Fatalf("visualOrder(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFallbackFonts_LoadsFontsAndReportsBadFiles(t *testing.T) {
+	t.Cleanup(func() { setFallbackFonts(nil) })
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"a.ttf":        goregular.TTF,
+		"nested/b.TTF": gomono.TTF,
+		"broken.otf":   []byte("not a font"),
+		"README.txt":   []byte("ignored"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := LoadFallbackFonts(dir)
+	if loaded != 2 {
+		t.Fatalf("expected 2 fonts loaded, got %d", loaded)
+	}
+	if err == nil || !strings.Contains(err.Error(), "broken.otf") {
+		t.Fatalf("expected error naming broken.otf, got %v", err)
+	}
+
+	face, err := newFontFace(renderBodyFontSize)
+	if err != nil {
+		t.Fatalf("newFontFace: %v", err)
+	}
+	defer func() { _ = face.Close() }()
+	if _, ok := face.(*fallbackFace); !ok {
+		t.Fatalf("expected fallback face once fonts are loaded, got %T", face)
+	}
+}
+
+func TestLoadFallbackFonts_MissingDir(t *testing.T) {
+	t.Cleanup(func() { setFallbackFonts(nil) })
+
+	loaded, err := LoadFallbackFonts(filepath.Join(t.TempDir(), "missing"))
+	if err == nil {
+		t.Fatal("expected error for missing dir")
+	}
+	if loaded != 0 || len(loadedFallbackFonts()) != 0 {
+		t.Fatalf("expected no fonts loaded, got %d", loaded)
+	}
+}
+
+func TestFallbackFace_UsesFallbackForMissingGlyphs(t *testing.T) {
+	const systemFont = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+	data, err := os.ReadFile(systemFont)
+	if err != nil {
+		t.Skipf("no font with Hebrew coverage available: %v", err)
+	}
+	primary, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fallback, err := opentype.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &opentype.FaceOptions{Size: renderBodyFontSize, DPI: 72}
+	face, err := newFallbackFace(primary, []*opentype.Font{fallback}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = face.Close() }()
+	ff := face.(*fallbackFace)
+
+	if ff.faceFor('a') != ff.faces[0] {
+		t.Fatal("expected primary font for Latin text")
+	}
+	if ff.faceFor('ש') != ff.faces[1] {
+		t.Fatal("expected fallback font for Hebrew text")
+	}
+	if ff.Metrics() != ff.faces[0].Metrics() {
+		t.Fatal("expected metrics from the primary font")
+	}
+	if ff.Kern('a', 'ש') != 0 {
+		t.Fatal("expected no kerning across fonts")
+	}
+}
+
+func TestRenderReminderPNG_MixedDirectionIsDeterministic(t *testing.T) {
+	title := "שנת 2025 Goals"
+	card := models.BingoCard{ID: uuid.New(), Year: 2025, Title: &title, GridSize: 3, IsFinalized: true}
+	items := []models.BingoItem{
+		{Position: 0, Content: "Learn עברית today"},
+		{Position: 1, Content: "سباق 5 كم"},
+		{Position: 2, Content: "🎉 שלום world"},
+	}
+
+	first, err := RenderReminderPNG(card, items, RenderOptions{ShowCompletions: true})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	second, err := RenderReminderPNG(card, items, RenderOptions{ShowCompletions: true})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatal("expected identical PNG output for identical mixed-direction input")
+	}
+}
@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -33,6 +35,10 @@ func TestCardService_Finalize_NotifiesFriendsWhenVisible(t *testing.T) {
 		false,
 		createdAt,
 		updatedAt,
+		(*string)(nil),
+		testCardStart,
+		testCardEnd,
+		false,
 	}
 
 	items := []models.BingoItem{
@@ -55,28 +61,48 @@ func TestCardService_Finalize_NotifiesFriendsWhenVisible(t *testing.T) {
 			if strings.Contains(sql, "FROM bingo_items") {
 				rows := make([][]any, 0, len(items))
 				for _, item := range items {
-					rows = append(rows, []any{item.ID, item.CardID, item.Position, item.Content, item.IsCompleted, item.CompletedAt, item.Notes, item.ProofURL, time.Now()})
+					rows = append(rows, []any{item.ID, item.CardID, item.Position, item.Content, item.IsCompleted, item.CompletedAt, item.Notes, item.ProofURL, time.Now(), item.IsPrivate, item.Difficulty})
 				}
 				return &fakeRows{rows: rows}, nil
 			}
 			return &fakeRows{}, nil
 		},
+	}
+	var finalized, committed bool
+	tx := &fakeTx{
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
 			if strings.Contains(sql, "UPDATE bingo_cards SET is_finalized") {
+				finalized = true
 				return fakeCommandTag{rowsAffected: 1}, nil
 			}
 			return fakeCommandTag{}, nil
 		},
+		CommitFunc: func(ctx context.Context) error {
+			committed = true
+			return nil
+		},
 	}
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
 
+	emailID := uuid.New()
+	var dispatched []uuid.UUID
 	svc := NewCardService(db)
 	svc.SetNotificationService(&stubNotificationService{
-		NotifyFriendsNewCardFunc: func(ctx context.Context, actorID, gotCardID uuid.UUID) error {
+		NotifyFriendsNewCardFunc: func(ctx context.Context, gotTx Tx, actorID, gotCardID uuid.UUID) ([]uuid.UUID, error) {
 			notified = true
+			if gotTx != tx || !finalized || committed {
+				t.Fatal("expected notifications inside the finalize transaction")
+			}
 			if actorID != userID || gotCardID != cardID {
 				t.Fatalf("unexpected notification args: %v %v", actorID, gotCardID)
 			}
-			return nil
+			return []uuid.UUID{emailID}, nil
+		},
+		DispatchEmailsFunc: func(ids []uuid.UUID) {
+			if !committed {
+				t.Fatal("expected emails only after commit")
+			}
+			dispatched = ids
 		},
 	})
 
@@ -87,6 +113,9 @@ func TestCardService_Finalize_NotifiesFriendsWhenVisible(t *testing.T) {
 	if !notified {
 		t.Fatal("expected notification")
 	}
+	if len(dispatched) != 1 || dispatched[0] != emailID {
+		t.Fatalf("expected email dispatch for %v, got %v", emailID, dispatched)
+	}
 }
 
 func TestCardService_CompleteItem_NotifiesBingo(t *testing.T) {
@@ -111,6 +140,10 @@ func TestCardService_CompleteItem_NotifiesBingo(t *testing.T) {
 		false,
 		createdAt,
 		updatedAt,
+		(*string)(nil),
+		testCardStart,
+		testCardEnd,
+		false,
 	}
 
 	items := []models.BingoItem{
@@ -133,31 +166,39 @@ func TestCardService_CompleteItem_NotifiesBingo(t *testing.T) {
 			if strings.Contains(sql, "FROM bingo_items") {
 				rows := make([][]any, 0, len(items))
 				for _, item := range items {
-					rows = append(rows, []any{item.ID, item.CardID, item.Position, item.Content, item.IsCompleted, item.CompletedAt, item.Notes, item.ProofURL, time.Now()})
+					rows = append(rows, []any{item.ID, item.CardID, item.Position, item.Content, item.IsCompleted, item.CompletedAt, item.Notes, item.ProofURL, time.Now(), item.IsPrivate, item.Difficulty})
 				}
 				return &fakeRows{rows: rows}, nil
 			}
 			return &fakeRows{}, nil
 		},
+	}
+	var completed bool
+	tx := &fakeTx{
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
 			if strings.Contains(sql, "UPDATE bingo_items") {
+				completed = true
 				return fakeCommandTag{rowsAffected: 1}, nil
 			}
 			return fakeCommandTag{}, nil
 		},
 	}
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
 
 	svc := NewCardService(db)
 	svc.SetNotificationService(&stubNotificationService{
-		NotifyFriendsBingoFunc: func(ctx context.Context, actorID, gotCardID uuid.UUID, bingoCount int) error {
+		NotifyFriendsBingoFunc: func(ctx context.Context, gotTx Tx, actorID, gotCardID uuid.UUID, bingoCount int) ([]uuid.UUID, error) {
 			notified = true
+			if gotTx != tx || !completed {
+				t.Fatal("expected notifications inside the completion transaction")
+			}
 			if actorID != userID || gotCardID != cardID {
 				t.Fatalf("unexpected notification args: %v %v", actorID, gotCardID)
 			}
 			if bingoCount == 0 {
 				t.Fatal("expected bingo count")
 			}
-			return nil
+			return nil, nil
 		},
 	})
 
@@ -169,3 +210,157 @@ func TestCardService_CompleteItem_NotifiesBingo(t *testing.T) {
 		t.Fatal("expected bingo notification")
 	}
 }
+
+func TestCardService_CompleteItem_ConcurrentRequestsNotifyOnce(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	now := time.Now()
+	items := [][]any{
+		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, now, false, nil},
+	}
+
+	// Both requests load the card before either writes, so both see B
+	// incomplete; only the first conditional update gets to change the row.
+	var mu sync.Mutex
+	var completedAt *time.Time
+	db := newCardDB(cardID, userID, 2, false, nil, true, items)
+	cardRow := db.QueryRowFunc
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if strings.Contains(sql, "FROM bingo_items") {
+			mu.Lock()
+			defer mu.Unlock()
+			return rowFromValues(completedAt != nil, completedAt, (*string)(nil), (*string)(nil))
+		}
+		return cardRow(ctx, sql, args...)
+	}
+	loaded := make(chan struct{}, 2)
+	release := make(chan struct{})
+	cardItems := db.QueryFunc
+	db.QueryFunc = func(ctx context.Context, sql string, args ...any) (Rows, error) {
+		rows, err := cardItems(ctx, sql, args...)
+		loaded <- struct{}{}
+		<-release
+		return rows, err
+	}
+	tx := &fakeTx{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "is_completed = false") {
+				t.Fatalf("expected a conditional update, got %q", sql)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if completedAt != nil {
+				return fakeCommandTag{}, nil
+			}
+			at := args[0].(time.Time)
+			completedAt = &at
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
+
+	var notifications int
+	svc := NewCardService(db)
+	svc.SetNotificationService(&stubNotificationService{
+		NotifyFriendsBingoFunc: func(ctx context.Context, tx Tx, actorID, cardID uuid.UUID, bingoCount int) ([]uuid.UUID, error) {
+			mu.Lock()
+			notifications++
+			mu.Unlock()
+			return nil, nil
+		},
+	})
+
+	var wg sync.WaitGroup
+	results := make([]*models.BingoItem, 2)
+	errs := make([]error, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = svc.CompleteItem(context.Background(), userID, cardID, 1, models.CompleteItemParams{})
+		}(i)
+	}
+	<-loaded
+	<-loaded
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+		if !results[i].IsCompleted || results[i].CompletedAt == nil || !results[i].CompletedAt.Equal(*completedAt) {
+			t.Fatalf("request %d: expected the stored completion, got %+v", i, results[i])
+		}
+	}
+	if notifications != 1 {
+		t.Fatalf("expected one bingo notification, got %d", notifications)
+	}
+}
+
+func TestCardService_CompleteItem_LostRaceWithNewProof(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	now := time.Now()
+	items := [][]any{
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, now, false, nil},
+	}
+	// The card loads with A open, but another request completes it first.
+	theirs := "https://example.com/theirs.jpg"
+	db := newCardDB(cardID, userID, 2, false, nil, true, items)
+	cardRow := db.QueryRowFunc
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if strings.Contains(sql, "FROM bingo_items") {
+			return rowFromValues(true, &now, (*string)(nil), &theirs)
+		}
+		return cardRow(ctx, sql, args...)
+	}
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		return fakeCommandTag{}, nil
+	}
+
+	svc := NewCardService(db)
+	mine := "https://example.com/mine.jpg"
+	if _, err := svc.CompleteItem(context.Background(), userID, cardID, 0, models.CompleteItemParams{ProofURL: &mine}); !errors.Is(err, ErrItemCompleted) {
+		t.Fatalf("expected ErrItemCompleted, got %v", err)
+	}
+	item, err := svc.CompleteItem(context.Background(), userID, cardID, 0, models.CompleteItemParams{ProofURL: &theirs})
+	if err != nil || !item.IsCompleted || item.ProofURL == nil || *item.ProofURL != theirs {
+		t.Fatalf("expected the stored completion, got %+v %v", item, err)
+	}
+}
+
+func TestCardService_UncompleteItem_AlreadyIncomplete(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	now := time.Now()
+	items := [][]any{
+		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, now, false, nil},
+	}
+	db := newCardDB(cardID, userID, 2, false, nil, true, items)
+	cardRow := db.QueryRowFunc
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if strings.Contains(sql, "FROM bingo_items") {
+			return rowFromValues(false, (*time.Time)(nil), (*string)(nil), (*string)(nil))
+		}
+		return cardRow(ctx, sql, args...)
+	}
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if !strings.Contains(sql, "is_completed = true") {
+			t.Fatalf("expected a conditional update, got %q", sql)
+		}
+		return fakeCommandTag{}, nil
+	}
+
+	svc := NewCardService(db)
+	item, err := svc.UncompleteItem(context.Background(), userID, cardID, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.IsCompleted || item.CompletedAt != nil {
+		t.Fatalf("expected the stored incomplete item, got %+v", item)
+	}
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Item positions are grid squares: a goal's position is its square on the
+// card, 0 to TotalSquares()-1 row by row, unique per card and never the FREE
+// square (see models.BingoCard.IsValidItemPosition). They are not a dense
+// index, so removing a goal leaves a gap rather than shifting the goals
+// after it. Reactions and goal reminders reference item IDs, so moving a
+// goal never detaches them.
+//
+// Every write that places a goal checks the square up front. The deferred
+// bingo_items_position_valid constraint triggers check again at commit, which
+// lets swaps and shuffles park goals on temporary negative positions.
+
+// itemPositionConstraint is the constraint name the position triggers raise.
+const itemPositionConstraint = "bingo_items_position_valid"
+
+// isItemPositionViolation reports whether err is the database rejecting a
+// write that would leave a goal off the grid or on the FREE square.
+func isItemPositionViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23514" && pgErr.ConstraintName == itemPositionConstraint
+}
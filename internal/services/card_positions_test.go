@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var errItemPositionViolation = &pgconn.PgError{Code: "23514", ConstraintName: itemPositionConstraint}
+
+func TestIsItemPositionViolation(t *testing.T) {
+	if !isItemPositionViolation(errItemPositionViolation) {
+		t.Fatal("expected the position constraint to be recognized")
+	}
+	if isItemPositionViolation(&pgconn.PgError{Code: "23514", ConstraintName: "bingo_cards_valid_grid_size"}) {
+		t.Fatal("expected other check constraints to be ignored")
+	}
+	if isItemPositionViolation(errors.New("boom")) {
+		t.Fatal("expected plain errors to be ignored")
+	}
+}
+
+func TestCardService_AddItem_PositionConstraint(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 3, false, nil, false, [][]any{})
+	cardRow := db.QueryRowFunc
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if strings.Contains(sql, "INSERT INTO bingo_items") {
+			return fakeRow{scanFunc: func(dest ...any) error { return errItemPositionViolation }}
+		}
+		return cardRow(ctx, sql, args...)
+	}
+
+	pos := 4
+	_, err := NewCardService(db).AddItem(context.Background(), userID, models.AddItemParams{CardID: cardID, Position: &pos, Content: "Goal"})
+	if !errors.Is(err, ErrInvalidPosition) {
+		t.Fatalf("expected ErrInvalidPosition, got %v", err)
+	}
+}
+
+func TestCardService_RemoveItem_LeavesOtherPositions(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 2, false, nil, false, [][]any{
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 3, "C", false, nil, nil, nil, time.Now(), false, nil},
+	})
+	var execs []string
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		execs = append(execs, sql)
+		return fakeCommandTag{rowsAffected: 1}, nil
+	}
+
+	if err := NewCardService(db).RemoveItem(context.Background(), userID, cardID, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(execs) != 1 || !strings.HasPrefix(execs[0], "DELETE FROM bingo_items") {
+		t.Fatalf("expected a single delete and no renumbering, got %v", execs)
+	}
+}
+
+func TestCardService_SwapItems_PositionConstraint(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 2, false, nil, false, [][]any{
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+	})
+	db.BeginFunc = func(ctx context.Context) (Tx, error) {
+		return &fakeTx{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+				return fakeCommandTag{rowsAffected: 1}, nil
+			},
+			CommitFunc: func(ctx context.Context) error { return errItemPositionViolation },
+		}, nil
+	}
+
+	err := NewCardService(db).SwapItems(context.Background(), userID, cardID, 0, 1)
+	if !errors.Is(err, ErrInvalidPosition) {
+		t.Fatalf("expected ErrInvalidPosition, got %v", err)
+	}
+}
+
+func TestCardService_SwapItems_KeepsSquaresDistinct(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	rows, items := shuffleCardItems(cardID, 0, 1, 3)
+	db := newCardDB(cardID, userID, 2, false, nil, false, rows)
+	var rec shuffleTx
+	db.BeginFunc = func(ctx context.Context) (Tx, error) {
+		return rec.tx(nil), nil
+	}
+
+	if err := NewCardService(db).SwapItems(context.Background(), userID, cardID, 0, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.positions[items[0].ID] != 3 || rec.positions[items[2].ID] != 0 {
+		t.Fatalf("expected goals at 0 and 3 to trade squares, got %v", rec.positions)
+	}
+	if _, moved := rec.positions[items[1].ID]; moved {
+		t.Fatalf("expected the goal at 1 to stay put, got %v", rec.positions)
+	}
+}
+
+func TestCardService_Shuffle_RepairsDriftedPositions(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	freePos := 4
+	// One goal sits on the FREE square and one is off the 3x3 grid.
+	rows, items := shuffleCardItems(cardID, 0, 1, 4, 9)
+	db := newCardDB(cardID, userID, 3, true, &freePos, false, rows)
+	var rec shuffleTx
+	db.BeginFunc = func(ctx context.Context) (Tx, error) {
+		return rec.tx(nil), nil
+	}
+
+	seed := int64(7)
+	if _, err := NewCardService(db).Shuffle(context.Background(), userID, cardID, &seed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	card := &models.BingoCard{GridSize: 3, HasFreeSpace: true, FreeSpacePos: &freePos}
+	used := map[int]bool{}
+	for _, item := range items {
+		pos, ok := rec.positions[item.ID]
+		if !ok || !card.IsValidItemPosition(pos) || used[pos] {
+			t.Fatalf("expected every goal on its own valid square, got %v", rec.positions)
+		}
+		used[pos] = true
+	}
+}
+
+func TestCardService_Shuffle_TooManyGoals(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	rows, _ := shuffleCardItems(cardID, 0, 1, 2, 3, 4)
+	db := newCardDB(cardID, userID, 2, false, nil, false, rows)
+	db.BeginFunc = func(ctx context.Context) (Tx, error) {
+		t.Fatal("expected no writes")
+		return nil, nil
+	}
+
+	seed := int64(1)
+	if _, err := NewCardService(db).Shuffle(context.Background(), userID, cardID, &seed); !errors.Is(err, ErrInvalidPosition) {
+		t.Fatalf("expected ErrInvalidPosition, got %v", err)
+	}
+}
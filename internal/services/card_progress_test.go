@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestCardService_ListProgress(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "owner@example.com", Username: "owner"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+
+	cards := NewCardService(db)
+	newCard := func(year int) *models.BingoCard {
+		t.Helper()
+		card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: year, GridSize: 2, Header: "BI"})
+		if err != nil {
+			t.Fatalf("unexpected error creating card: %v", err)
+		}
+		for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+			if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+				t.Fatalf("unexpected error adding %q: %v", content, err)
+			}
+		}
+		if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+			t.Fatalf("unexpected error finalizing: %v", err)
+		}
+		return card
+	}
+
+	current := newCard(2026)
+	for _, pos := range []int{0, 1, 3} {
+		if _, err := cards.CompleteItem(ctx, user.ID, current.ID, pos, models.CompleteItemParams{}); err != nil {
+			t.Fatalf("unexpected error completing %d: %v", pos, err)
+		}
+	}
+	archived := newCard(2025)
+	if _, err := cards.BulkUpdateArchive(ctx, user.ID, []uuid.UUID{archived.ID}, true); err != nil {
+		t.Fatalf("unexpected error archiving: %v", err)
+	}
+
+	progress, err := cards.ListProgress(ctx, user.ID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(progress) != 1 {
+		t.Fatalf("expected only the active card, got %+v", progress)
+	}
+	got := progress[0]
+	// Row 1, column 2 and the diagonal are complete on the 2x2 grid.
+	if got.CardID != current.ID || got.CompletedItems != 3 || got.TotalItems != 4 || got.BingosAchieved != 3 || got.LastCompletion == nil {
+		t.Fatalf("unexpected progress %+v", got)
+	}
+
+	progress, err = cards.ListProgress(ctx, user.ID, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(progress) != 2 || progress[1].CardID != archived.ID || !progress[1].IsArchived {
+		t.Fatalf("expected the archived card last, got %+v", progress)
+	}
+	if progress[1].CompletedItems != 0 || progress[1].BingosAchieved != 0 || progress[1].LastCompletion != nil {
+		t.Fatalf("expected no progress on the archived card, got %+v", progress[1])
+	}
+}
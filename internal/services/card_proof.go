@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrProofImageTooLarge   = fmt.Errorf("proof image must be %dMB or less", models.MaxProofImageBytes>>20)
+	ErrProofImageType       = errors.New("proof image must be a JPEG, PNG or WebP")
+	ErrProofUploadsDisabled = errors.New("proof uploads are not configured")
+)
+
+// ProofPathPrefix is where stored proof photos are served. A proof URL under
+// it points at an upload rather than an external link.
+const ProofPathPrefix = "/proofs/"
+
+// proofImageExtensions maps the accepted proof photo types, as sniffed from
+// the upload, to the extension they are stored with.
+var proofImageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// ProofImageContentType returns the content type to serve a stored proof
+// photo with, from its key's extension.
+func ProofImageContentType(key string) string {
+	for contentType, ext := range proofImageExtensions {
+		if strings.HasSuffix(key, ext) {
+			return contentType
+		}
+	}
+	return "application/octet-stream"
+}
+
+// SetProofStorage enables proof photo uploads on completion.
+func (s *CardService) SetProofStorage(storage StorageService) {
+	s.proofStorage = storage
+}
+
+// validateProofImage checks an upload's size and sniffed type, returning the
+// extension to store it with.
+func validateProofImage(data []byte) (string, error) {
+	if len(data) > models.MaxProofImageBytes {
+		return "", ErrProofImageTooLarge
+	}
+	ext, ok := proofImageExtensions[http.DetectContentType(data)]
+	if !ok {
+		return "", ErrProofImageType
+	}
+	return ext, nil
+}
+
+// storeProofImage saves a proof photo for item and returns its URL. Keys are
+// "<user>/<item>-<random>.<ext>", so an account's photos share a prefix and a
+// URL can be checked against the item it belongs to.
+func (s *CardService) storeProofImage(ctx context.Context, userID, itemID uuid.UUID, data []byte) (string, error) {
+	if s.proofStorage == nil {
+		return "", ErrProofUploadsDisabled
+	}
+	ext, err := validateProofImage(data)
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate proof key: %w", err)
+	}
+	key := fmt.Sprintf("%s/%s-%s%s", userID, itemID, hex.EncodeToString(buf), ext)
+	if err := s.proofStorage.Put(ctx, key, data); err != nil {
+		return "", fmt.Errorf("storing proof image: %w", err)
+	}
+	return ProofPathPrefix + key, nil
+}
+
+// storedProofKey returns the storage key behind proofURL when it is a photo
+// uploaded for this user's item. Proof URLs can be set freely, so anything
+// else, including another item's upload, is left alone.
+func storedProofKey(userID, itemID uuid.UUID, proofURL *string) (string, bool) {
+	if proofURL == nil {
+		return "", false
+	}
+	key, ok := strings.CutPrefix(*proofURL, ProofPathPrefix)
+	if !ok || !strings.HasPrefix(key, fmt.Sprintf("%s/%s-", userID, itemID)) || !validStorageKey(key) {
+		return "", false
+	}
+	return key, true
+}
+
+// deleteStoredProof removes item's uploaded proof photo, if proofURL is one.
+// The row no longer points at it, so a failure only leaves a stray file.
+func (s *CardService) deleteStoredProof(ctx context.Context, userID, itemID uuid.UUID, proofURL *string) {
+	key, ok := storedProofKey(userID, itemID, proofURL)
+	if !ok || s.proofStorage == nil {
+		return
+	}
+	if err := s.proofStorage.Delete(context.WithoutCancel(ctx), key); err != nil {
+		logging.Warn("Failed to delete proof image", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID.String(),
+			"item_id": itemID.String(),
+		})
+	}
+}
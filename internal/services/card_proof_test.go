@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var testProofPNG = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+func TestCardService_ProofImageLifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "owner@example.com", Username: "owner"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+
+	storage := NewDiskStorage(t.TempDir())
+	cards := NewCardService(db)
+	cards.SetProofStorage(storage)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: 2026, GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+
+	oversized := append(append([]byte{}, testProofPNG...), make([]byte, models.MaxProofImageBytes)...)
+	for _, tc := range []struct {
+		image []byte
+		want  error
+	}{
+		{oversized, ErrProofImageTooLarge},
+		{[]byte("GIF89a not allowed"), ErrProofImageType},
+	} {
+		if _, err := cards.CompleteItem(ctx, user.ID, card.ID, 0, models.CompleteItemParams{ProofImage: tc.image}); !errors.Is(err, tc.want) {
+			t.Fatalf("expected %v, got %v", tc.want, err)
+		}
+	}
+
+	item, err := cards.CompleteItem(ctx, user.ID, card.ID, 0, models.CompleteItemParams{ProofImage: testProofPNG})
+	if err != nil {
+		t.Fatalf("unexpected error completing with a photo: %v", err)
+	}
+	if item.ProofURL == nil || !strings.HasPrefix(*item.ProofURL, ProofPathPrefix+user.ID.String()+"/") {
+		t.Fatalf("expected a stored proof URL, got %+v", item.ProofURL)
+	}
+	key := strings.TrimPrefix(*item.ProofURL, ProofPathPrefix)
+	if data, err := storage.Get(ctx, key); err != nil || len(data) != len(testProofPNG) {
+		t.Fatalf("expected the photo to be stored, got %d bytes, %v", len(data), err)
+	}
+	share, err := cards.CreateOrRotateShare(ctx, user.ID, card.ID, nil, nil, models.ShareOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error sharing: %v", err)
+	}
+	shared, err := cards.GetSharedCardByToken(ctx, share.Token)
+	if err != nil || shared.Items[0].ProofURL == nil || *shared.Items[0].ProofURL != *item.ProofURL {
+		t.Fatalf("expected the proof URL on the shared card, got %+v %v", shared, err)
+	}
+
+	// Uncompleting revokes an uploaded photo but keeps a proof link.
+	item, err = cards.UncompleteItem(ctx, user.ID, card.ID, 0)
+	if err != nil {
+		t.Fatalf("unexpected error uncompleting: %v", err)
+	}
+	if item.ProofURL != nil {
+		t.Fatalf("expected the photo to be unlinked, got %q", *item.ProofURL)
+	}
+	if _, err := storage.Get(ctx, key); !errors.Is(err, ErrStorageObjectNotFound) {
+		t.Fatalf("expected the photo to be deleted, got %v", err)
+	}
+	link := "https://example.com/finish.jpg"
+	if _, err := cards.CompleteItem(ctx, user.ID, card.ID, 1, models.CompleteItemParams{ProofURL: &link}); err != nil {
+		t.Fatalf("unexpected error completing with a link: %v", err)
+	}
+	if item, err = cards.UncompleteItem(ctx, user.ID, card.ID, 1); err != nil || item.ProofURL == nil || *item.ProofURL != link {
+		t.Fatalf("expected the proof link to be kept, got %+v %v", item, err)
+	}
+
+	// A proof URL naming another user's upload is never deleted.
+	other := ProofPathPrefix + uuid.NewString() + "/" + uuid.NewString() + "-abc.png"
+	otherKey := strings.TrimPrefix(other, ProofPathPrefix)
+	if err := storage.Put(ctx, otherKey, testProofPNG); err != nil {
+		t.Fatalf("unexpected error storing: %v", err)
+	}
+	if _, err := cards.CompleteItem(ctx, user.ID, card.ID, 2, models.CompleteItemParams{ProofURL: &other}); err != nil {
+		t.Fatalf("unexpected error completing: %v", err)
+	}
+	if _, err := cards.UncompleteItem(ctx, user.ID, card.ID, 2); err != nil {
+		t.Fatalf("unexpected error uncompleting: %v", err)
+	}
+	if _, err := storage.Get(ctx, otherKey); err != nil {
+		t.Fatalf("expected another user's photo to be kept, got %v", err)
+	}
+
+	// Deleting the account removes its photos.
+	item, err = cards.CompleteItem(ctx, user.ID, card.ID, 3, models.CompleteItemParams{ProofImage: testProofPNG})
+	if err != nil {
+		t.Fatalf("unexpected error completing with a photo: %v", err)
+	}
+	accounts := NewAccountService(db)
+	accounts.SetProofStorage(storage)
+	if err := accounts.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("unexpected error deleting account: %v", err)
+	}
+	if _, err := storage.Get(ctx, strings.TrimPrefix(*item.ProofURL, ProofPathPrefix)); !errors.Is(err, ErrStorageObjectNotFound) {
+		t.Fatalf("expected the account's photos to be deleted, got %v", err)
+	}
+	var remaining int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM bingo_items WHERE card_id = $1 AND proof_url LIKE $2", card.ID, ProofPathPrefix+user.ID.String()+"/%").Scan(&remaining); err != nil {
+		t.Fatalf("unexpected error counting proofs: %v", err)
+	}
+	if remaining != 0 {
+		t.Fatalf("expected stored proof URLs to be cleared, got %d", remaining)
+	}
+}
+
+func TestCardService_CompleteItem_AlreadyCompleted(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "owner@example.com", Username: "owner"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	root := t.TempDir()
+	cards := NewCardService(db)
+	cards.SetProofStorage(NewDiskStorage(root))
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: 2026, GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+	link := "https://example.com/finish.jpg"
+	done, err := cards.CompleteItem(ctx, user.ID, card.ID, 0, models.CompleteItemParams{ProofURL: &link})
+	if err != nil {
+		t.Fatalf("unexpected error completing: %v", err)
+	}
+
+	// Repeating the same completion is a no-op.
+	again, err := cards.CompleteItem(ctx, user.ID, card.ID, 0, models.CompleteItemParams{ProofURL: &link})
+	if err != nil || !again.IsCompleted || again.ProofURL == nil || *again.ProofURL != link {
+		t.Fatalf("expected the stored completion, got %+v %v", again, err)
+	}
+
+	// New proof is refused rather than dropped, and no photo is left behind.
+	other := "https://example.com/other.jpg"
+	note := "Ran 5k"
+	for name, params := range map[string]models.CompleteItemParams{
+		"proof url": {ProofURL: &other},
+		"notes":     {Notes: &note, ProofURL: &link},
+		"photo":     {ProofImage: testProofPNG},
+	} {
+		if _, err := cards.CompleteItem(ctx, user.ID, card.ID, 0, params); !errors.Is(err, ErrItemCompleted) {
+			t.Fatalf("%s: expected ErrItemCompleted, got %v", name, err)
+		}
+	}
+	if _, err := cards.CompleteItemByContent(ctx, user.ID, card.ID, done.Content, true, models.CompleteItemParams{ProofURL: &other}); !errors.Is(err, ErrItemCompleted) {
+		t.Fatalf("by content: expected ErrItemCompleted, got %v", err)
+	}
+	var stored *string
+	if err := db.QueryRow(ctx, "SELECT proof_url FROM bingo_items WHERE id = $1", done.ID).Scan(&stored); err != nil || stored == nil || *stored != link {
+		t.Fatalf("expected the proof link kept, got %v %v", stored, err)
+	}
+	if entries, err := os.ReadDir(filepath.Join(root, user.ID.String())); err == nil && len(entries) > 0 {
+		t.Fatalf("expected no stored photos, got %d", len(entries))
+	}
+}
+
+func TestDiskStorage_RejectsKeysOutsideRoot(t *testing.T) {
+	storage := NewDiskStorage(t.TempDir())
+	for _, key := range []string{"", "../escape.png", "a/../../escape.png", "/abs.png", "a//b.png", ".hidden/x.png"} {
+		if err := storage.Put(context.Background(), key, testProofPNG); !errors.Is(err, ErrInvalidStorageKey) {
+			t.Fatalf("%q: expected ErrInvalidStorageKey, got %v", key, err)
+		}
+	}
+}
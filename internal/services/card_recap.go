@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// recapOpenGoals is how many never-completed goals a recap lists.
+const recapOpenGoals = 3
+
+// BuildRecap summarizes the owner's card for the end of its year.
+func (s *CardService) BuildRecap(ctx context.Context, userID, cardID uuid.UUID) (*models.CardRecap, error) {
+	card, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
+		return nil, ErrNotCardOwner
+	}
+	return s.recapFor(card), nil
+}
+
+// BuildSharedRecap summarizes the card behind a share token, with the same
+// access rules as the share page. Private goals show as placeholders. A
+// recap is all about progress, so shares that hide completions have none.
+func (s *CardService) BuildSharedRecap(ctx context.Context, token string) (*models.CardRecap, error) {
+	shared, err := s.GetSharedCardByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if shared.CompletionsHidden {
+		return nil, ErrShareNotFound
+	}
+	card, err := s.GetByID(ctx, shared.Card.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range card.Items {
+		card.Items[i] = item.Redacted()
+	}
+	return s.recapFor(card), nil
+}
+
+func (s *CardService) recapFor(card *models.BingoCard) *models.CardRecap {
+	recap := &models.CardRecap{
+		CardID:          card.ID,
+		Year:            card.Year,
+		DisplayName:     card.DisplayName(),
+		TotalItems:      card.Capacity(),
+		OldestOpenGoals: []models.RecapGoal{},
+	}
+
+	var open []models.BingoItem
+	var completions []time.Time
+	for _, item := range card.Items {
+		if !item.IsCompleted {
+			open = append(open, item)
+			continue
+		}
+		recap.CompletedItems++
+		if item.CompletedAt == nil {
+			continue
+		}
+		completions = append(completions, *item.CompletedAt)
+		if recap.FirstCompletion == nil || item.CompletedAt.Before(*recap.FirstCompletion) {
+			recap.FirstCompletion = item.CompletedAt
+		}
+		if recap.LastCompletion == nil || item.CompletedAt.After(*recap.LastCompletion) {
+			recap.LastCompletion = item.CompletedAt
+		}
+	}
+
+	var freePos *int
+	if card.HasFreeSpace {
+		freePos = card.FreeSpacePos
+	}
+	recap.BingosAchieved = s.countBingos(card.Items, card.GridSize, freePos)
+	recap.LongestWeekStreak = longestWeekStreak(completions)
+
+	sort.SliceStable(open, func(i, j int) bool {
+		if !open[i].CreatedAt.Equal(open[j].CreatedAt) {
+			return open[i].CreatedAt.Before(open[j].CreatedAt)
+		}
+		return open[i].Position < open[j].Position
+	})
+	for _, item := range open[:min(len(open), recapOpenGoals)] {
+		recap.OldestOpenGoals = append(recap.OldestOpenGoals, models.RecapGoal{
+			Position:  item.Position,
+			Content:   item.Content,
+			CreatedAt: item.CreatedAt,
+		})
+	}
+	return recap
+}
+
+// longestWeekStreak counts the longest run of consecutive UTC weeks, starting
+// Monday, with at least one completion.
+func longestWeekStreak(completions []time.Time) int {
+	weeks := make(map[time.Time]bool, len(completions))
+	for _, t := range completions {
+		weeks[weekStart(t)] = true
+	}
+	starts := make([]time.Time, 0, len(weeks))
+	for week := range weeks {
+		starts = append(starts, week)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	longest, run := 0, 0
+	for i, week := range starts {
+		if i > 0 && starts[i-1].AddDate(0, 0, 7).Equal(week) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+	return longest
+}
+
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestCardService_BuildRecap(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "owner@example.com", Username: "owner"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+
+	cards := NewCardService(db)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: 2026, GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	created := time.Date(2025, time.December, 20, 0, 0, 0, 0, time.UTC)
+	for pos, goal := range []struct {
+		content string
+		private bool
+		age     int
+	}{
+		{"Run", false, 0},
+		{"Read", false, 0},
+		{"Cook", true, 2},
+		{"Hike", false, 3},
+	} {
+		item, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: goal.content, Position: &pos, IsPrivate: goal.private})
+		if err != nil {
+			t.Fatalf("unexpected error adding %q: %v", goal.content, err)
+		}
+		if _, err := db.Exec(ctx, "UPDATE bingo_items SET created_at = $1 WHERE id = $2", created.AddDate(0, 0, -goal.age), item.ID); err != nil {
+			t.Fatalf("unexpected error backdating %q: %v", goal.content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+
+	// Monday of one week and Wednesday of the next make a two-week streak.
+	first := time.Date(2026, time.January, 5, 9, 0, 0, 0, time.UTC)
+	last := time.Date(2026, time.January, 14, 18, 0, 0, 0, time.UTC)
+	for pos, at := range map[int]time.Time{0: first, 1: last} {
+		item, err := cards.CompleteItem(ctx, user.ID, card.ID, pos, models.CompleteItemParams{})
+		if err != nil {
+			t.Fatalf("unexpected error completing %d: %v", pos, err)
+		}
+		if _, err := db.Exec(ctx, "UPDATE bingo_items SET completed_at = $1 WHERE id = $2", at, item.ID); err != nil {
+			t.Fatalf("unexpected error backdating completion: %v", err)
+		}
+	}
+
+	recap, err := cards.BuildRecap(ctx, user.ID, card.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recap.CompletedItems != 2 || recap.TotalItems != 4 || recap.BingosAchieved != 1 || recap.LongestWeekStreak != 2 {
+		t.Fatalf("unexpected recap %+v", recap)
+	}
+	if !recap.FirstCompletion.Equal(first) || !recap.LastCompletion.Equal(last) {
+		t.Fatalf("unexpected completion dates %v %v", recap.FirstCompletion, recap.LastCompletion)
+	}
+	if len(recap.OldestOpenGoals) != 2 || recap.OldestOpenGoals[0].Content != "Hike" || recap.OldestOpenGoals[1].Content != "Cook" {
+		t.Fatalf("expected open goals oldest first, got %+v", recap.OldestOpenGoals)
+	}
+
+	if _, err := cards.BuildRecap(ctx, uuid.New(), card.ID); !errors.Is(err, ErrNotCardOwner) {
+		t.Fatalf("expected ErrNotCardOwner, got %v", err)
+	}
+
+	share, err := cards.CreateOrRotateShare(ctx, user.ID, card.ID, nil, nil, models.ShareOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error sharing: %v", err)
+	}
+	shared, err := cards.BuildSharedRecap(ctx, share.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shared.CompletedItems != 2 || shared.OldestOpenGoals[1].Content != models.PrivateItemPlaceholder {
+		t.Fatalf("expected the private goal hidden on the shared recap, got %+v", shared)
+	}
+	if _, err := cards.BuildSharedRecap(ctx, "missing"); !errors.Is(err, ErrShareNotFound) {
+		t.Fatalf("expected ErrShareNotFound, got %v", err)
+	}
+}
+
+func TestLongestWeekStreak(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 12, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name        string
+		completions []time.Time
+		want        int
+	}{
+		{"none", nil, 0},
+		{"same week", []time.Time{day(time.March, 2), day(time.March, 8)}, 1},
+		{"sunday then monday", []time.Time{day(time.March, 8), day(time.March, 9)}, 2},
+		{"gap resets", []time.Time{day(time.March, 2), day(time.March, 9), day(time.March, 23), day(time.March, 30), day(time.April, 6)}, 3},
+		{"across new year", []time.Time{time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC), day(time.January, 5)}, 2},
+	}
+	for _, tt := range tests {
+		if got := longestWeekStreak(tt.completions); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
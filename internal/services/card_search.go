@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+const (
+	// searchSnippetLength is how many characters of a long goal or title are
+	// shown around the first match.
+	searchSnippetLength = 120
+	// searchSnippetLead is how much text is kept before the first match.
+	searchSnippetLead = 40
+)
+
+// Search finds the user's cards, archived ones included, whose title or goal
+// text contains params.Query, most recent cards first. Private goals are
+// included since only the owner can search them. Limit and Offset count
+// cards: the page of matching cards is chosen first and then all of their
+// hits are read, so a card's hits never split across pages.
+func (s *CardService) Search(ctx context.Context, params models.CardSearchParams) (*models.CardSearchResult, error) {
+	rows, err := s.db.Query(ctx,
+		`WITH page AS (
+		   SELECT c.id, c.title, c.year, c.is_archived, c.created_at
+		   FROM bingo_cards c
+		   WHERE c.user_id = $1
+		     AND (c.title ILIKE $2 OR EXISTS (SELECT 1 FROM bingo_items m WHERE m.card_id = c.id AND m.content ILIKE $2))
+		   ORDER BY c.year DESC, c.created_at DESC, c.id
+		   LIMIT $3 OFFSET $4
+		 )
+		 SELECT p.id AS card_id, p.title, p.year, p.is_archived, p.created_at, NULL::uuid AS item_id, -1 AS position, COALESCE(p.title, '') AS text
+		 FROM page p
+		 WHERE p.title ILIKE $2
+		 UNION ALL
+		 SELECT p.id, p.title, p.year, p.is_archived, p.created_at, i.id, i.position, i.content
+		 FROM page p
+		 JOIN bingo_items i ON i.card_id = p.id
+		 WHERE i.content ILIKE $2
+		 ORDER BY year DESC, created_at DESC, card_id, position`,
+		params.UserID, likeContains(params.Query), params.Limit+1, params.Offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("searching cards: %w", err)
+	}
+	defer rows.Close()
+
+	type hit struct {
+		match    models.CardSearchMatch
+		itemID   *uuid.UUID
+		position int
+		text     string
+	}
+	var hits []hit
+	for rows.Next() {
+		var h hit
+		var createdAt time.Time
+		if err := rows.Scan(&h.match.CardID, &h.match.Title, &h.match.Year, &h.match.IsArchived, &createdAt, &h.itemID, &h.position, &h.text); err != nil {
+			return nil, fmt.Errorf("scanning card search hit: %w", err)
+		}
+		hits = append(hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating card search hits: %w", err)
+	}
+
+	// One card past the page was read to tell whether more follow.
+	result := &models.CardSearchResult{Items: []models.CardSearchMatch{}}
+	for _, h := range hits {
+		last := len(result.Items) - 1
+		if last < 0 || result.Items[last].CardID != h.match.CardID {
+			if last+1 == params.Limit {
+				result.HasMore = true
+				break
+			}
+			h.match.Items = []models.CardSearchItem{}
+			result.Items = append(result.Items, h.match)
+			last++
+		}
+		snippet := searchSnippet(h.text, params.Query)
+		if h.itemID == nil {
+			result.Items[last].TitleSnippet = snippet
+			continue
+		}
+		result.Items[last].Items = append(result.Items[last].Items, models.CardSearchItem{
+			ItemID:   *h.itemID,
+			Position: h.position,
+			Snippet:  snippet,
+		})
+	}
+	return result, nil
+}
+
+// searchSnippet splits text into runs with every case-insensitive occurrence
+// of query marked. Long text is cut to a window around the first occurrence.
+func searchSnippet(text, query string) []models.SnippetPart {
+	runes := []rune(text)
+	lower := lowerRunes(runes)
+	needle := lowerRunes([]rune(query))
+
+	var matches [][2]int
+	if len(needle) > 0 {
+		for i := 0; i+len(needle) <= len(lower); {
+			if string(lower[i:i+len(needle)]) == string(needle) {
+				matches = append(matches, [2]int{i, i + len(needle)})
+				i += len(needle)
+				continue
+			}
+			i++
+		}
+	}
+
+	start, end := 0, len(runes)
+	if len(runes) > searchSnippetLength {
+		if len(matches) > 0 {
+			start = max(0, matches[0][0]-searchSnippetLead)
+		}
+		end = min(len(runes), start+searchSnippetLength)
+		start = max(0, end-searchSnippetLength)
+	}
+
+	var parts []models.SnippetPart
+	appendPart := func(s string, match bool) {
+		if s == "" {
+			return
+		}
+		if n := len(parts); n > 0 && parts[n-1].Match == match {
+			parts[n-1].Text += s
+			return
+		}
+		parts = append(parts, models.SnippetPart{Text: s, Match: match})
+	}
+	if start > 0 {
+		appendPart("…", false)
+	}
+	pos := start
+	for _, m := range matches {
+		if m[1] <= start || m[0] >= end {
+			continue
+		}
+		from, to := max(m[0], start), min(m[1], end)
+		appendPart(string(runes[pos:from]), false)
+		appendPart(string(runes[from:to]), true)
+		pos = to
+	}
+	appendPart(string(runes[pos:end]), false)
+	if end < len(runes) {
+		appendPart("…", false)
+	}
+	return parts
+}
+
+func lowerRunes(runes []rune) []rune {
+	out := make([]rune, len(runes))
+	for i, r := range runes {
+		out[i] = unicode.ToLower(r)
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestCardService_Search_GroupsHitsByCard(t *testing.T) {
+	userID := uuid.New()
+	recent, older := uuid.New(), uuid.New()
+	title := "Juggling year"
+	juggle, unicycle, knit := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "c.user_id = $1") {
+				t.Fatalf("expected search scoped to the owner, got %s", sql)
+			}
+			gotArgs = args
+			return &fakeRows{rows: [][]any{
+				{recent, &title, 2026, false, now, nil, -1, title},
+				{recent, &title, 2026, false, now, &juggle, 3, "Learn to juggle"},
+				{older, (*string)(nil), 2023, true, now, &unicycle, 0, "Juggle on a unicycle"},
+				{older, (*string)(nil), 2023, true, now, &knit, 5, "Knit while juggling"},
+			}}, nil
+		},
+	}
+
+	result, err := NewCardService(db).Search(context.Background(), models.CardSearchParams{UserID: userID, Query: "jugg_", Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotArgs[0] != userID || gotArgs[1] != `%jugg\_%` || gotArgs[2] != 3 || gotArgs[3] != 0 {
+		t.Fatalf("unexpected query args %v", gotArgs)
+	}
+	if result.HasMore || len(result.Items) != 2 {
+		t.Fatalf("expected two cards and no more results, got %+v", result)
+	}
+
+	first := result.Items[0]
+	if first.CardID != recent || first.TitleSnippet == nil || len(first.Items) != 1 || first.Items[0].Position != 3 {
+		t.Fatalf("expected title and goal hit on the recent card, got %+v", first)
+	}
+	second := result.Items[1]
+	if second.CardID != older || !second.IsArchived || second.TitleSnippet != nil || len(second.Items) != 2 || second.Items[1].ItemID != knit {
+		t.Fatalf("expected both hits of the archived card, got %+v", second)
+	}
+
+	// The page holds whole cards; the extra card only reports more.
+	result, err = NewCardService(db).Search(context.Background(), models.CardSearchParams{UserID: userID, Query: "jugg", Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.HasMore || len(result.Items) != 1 || result.Items[0].CardID != recent {
+		t.Fatalf("expected only the recent card and more results, got %+v", result)
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	got := searchSnippet("Juggle and juggle again", "JUGGLE")
+	want := []models.SnippetPart{
+		{Text: "Juggle", Match: true},
+		{Text: " and "},
+		{Text: "juggle", Match: true},
+		{Text: " again"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	long := strings.Repeat("a", 200) + "juggle" + strings.Repeat("b", 200)
+	parts := searchSnippet(long, "juggle")
+	if len(parts) != 3 || parts[1].Text != "juggle" || !parts[1].Match {
+		t.Fatalf("expected a window around the match, got %+v", parts)
+	}
+	if !strings.HasPrefix(parts[0].Text, "…") || !strings.HasSuffix(parts[2].Text, "…") {
+		t.Fatalf("expected elided ends, got %+v", parts)
+	}
+	if n := len([]rune(parts[0].Text + parts[1].Text + parts[2].Text)); n != searchSnippetLength+2 {
+		t.Fatalf("expected %d characters plus ellipses, got %d", searchSnippetLength, n)
+	}
+
+	if got := searchSnippet("Straße", "zz"); len(got) != 1 || got[0].Match {
+		t.Fatalf("expected plain text when nothing matches, got %+v", got)
+	}
+}
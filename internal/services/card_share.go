@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
@@ -18,18 +19,26 @@ import (
 const (
 	ShareExpiryMinDays = 1
 	ShareExpiryMaxDays = 3650
+
+	// ShareGraceMaxDays caps how long a rotated-out token keeps resolving.
+	ShareGraceMaxDays = 30
 )
 
 var (
 	ErrShareNotFound = errors.New("share not found")
 )
 
-func (s *CardService) CreateOrRotateShare(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error) {
+// CreateOrRotateShare enables sharing or replaces the card's share token. A
+// non-nil graceUntil keeps the replaced token resolving, marked superseded,
+// until then, or until the old token's own expiry if that is sooner. An
+// already-expired token gets no grace, and only the latest replaced token is
+// kept. Options left unset keep the share's current settings.
+func (s *CardService) CreateOrRotateShare(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error) {
 	cardOwnerID, finalized, err := s.loadCardOwner(ctx, cardID)
 	if err != nil {
 		return nil, err
 	}
-	if cardOwnerID != userID {
+	if !authz.CanEditCard(authz.For(userID, cardOwnerID)) {
 		return nil, ErrNotCardOwner
 	}
 	if !finalized {
@@ -40,29 +49,49 @@ func (s *CardService) CreateOrRotateShare(ctx context.Context, userID, cardID uu
 	if err != nil {
 		return nil, err
 	}
+	oldTokens := s.cachedShareTokens(ctx, cardID)
 
 	share := &models.CardShare{}
 	err = s.db.QueryRow(ctx, `
-		INSERT INTO bingo_card_shares (card_id, token, expires_at)
-		VALUES ($1, $2, $3)
+		INSERT INTO bingo_card_shares (card_id, token, expires_at, show_completions, show_notes)
+		VALUES ($1, $2, $3, COALESCE($5, true), COALESCE($6, false))
 		ON CONFLICT (card_id)
 		DO UPDATE SET token = EXCLUDED.token,
 		              expires_at = EXCLUDED.expires_at,
+		              show_completions = COALESCE($5, bingo_card_shares.show_completions),
+		              show_notes = COALESCE($6, bingo_card_shares.show_notes),
 		              created_at = NOW(),
 		              last_accessed_at = NULL,
-		              access_count = 0
-		RETURNING card_id, token, created_at, expires_at, last_accessed_at, access_count
-	`, cardID, token, expiresAt).Scan(
+		              access_count = 0,
+		              previous_token = CASE
+		                  WHEN $4::timestamptz IS NOT NULL
+		                   AND (bingo_card_shares.expires_at IS NULL OR bingo_card_shares.expires_at > NOW())
+		                  THEN bingo_card_shares.token
+		              END,
+		              previous_token_expires_at = CASE
+		                  WHEN $4::timestamptz IS NOT NULL
+		                   AND (bingo_card_shares.expires_at IS NULL OR bingo_card_shares.expires_at > NOW())
+		                  THEN LEAST($4::timestamptz, bingo_card_shares.expires_at)
+		              END
+		RETURNING card_id, token, created_at, expires_at, last_accessed_at, access_count, previous_token_expires_at,
+		          show_completions, show_notes
+	`, cardID, token, expiresAt, graceUntil, options.ShowCompletions, options.ShowNotes).Scan(
 		&share.CardID,
 		&share.Token,
 		&share.CreatedAt,
 		&share.ExpiresAt,
 		&share.LastAccessedAt,
 		&share.AccessCount,
+		&share.SupersededUntil,
+		&share.ShowCompletions,
+		&share.ShowNotes,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("upserting card share: %w", err)
 	}
+	s.invalidateShareCache(ctx, cardID)
+	s.purgeShareTokens(ctx, oldTokens)
+	clearEndedGrace(share)
 
 	return share, nil
 }
@@ -72,13 +101,14 @@ func (s *CardService) GetShareStatus(ctx context.Context, userID, cardID uuid.UU
 	if err != nil {
 		return nil, err
 	}
-	if cardOwnerID != userID {
+	if !authz.CanEditCard(authz.For(userID, cardOwnerID)) {
 		return nil, ErrNotCardOwner
 	}
 
 	share := &models.CardShare{}
 	err = s.db.QueryRow(ctx, `
-		SELECT card_id, token, created_at, expires_at, last_accessed_at, access_count
+		SELECT card_id, token, created_at, expires_at, last_accessed_at, access_count, previous_token_expires_at,
+		       show_completions, show_notes
 		FROM bingo_card_shares
 		WHERE card_id = $1
 	`, cardID).Scan(
@@ -88,6 +118,9 @@ func (s *CardService) GetShareStatus(ctx context.Context, userID, cardID uuid.UU
 		&share.ExpiresAt,
 		&share.LastAccessedAt,
 		&share.AccessCount,
+		&share.SupersededUntil,
+		&share.ShowCompletions,
+		&share.ShowNotes,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrShareNotFound
@@ -95,37 +128,97 @@ func (s *CardService) GetShareStatus(ctx context.Context, userID, cardID uuid.UU
 	if err != nil {
 		return nil, fmt.Errorf("loading card share: %w", err)
 	}
+	clearEndedGrace(share)
 
 	return share, nil
 }
 
+// clearEndedGrace hides a superseded token whose grace period is over.
+func clearEndedGrace(share *models.CardShare) {
+	if share.SupersededUntil != nil && !share.SupersededUntil.After(time.Now()) {
+		share.SupersededUntil = nil
+	}
+}
+
+// cachedShareTokens returns the card's current and superseded share tokens
+// so their cache entries can be purged once the share changes. It reads
+// nothing without a share cache.
+func (s *CardService) cachedShareTokens(ctx context.Context, cardID uuid.UUID) []string {
+	if s.shareCache == nil {
+		return nil
+	}
+	var token string
+	var previous *string
+	err := s.db.QueryRow(ctx,
+		"SELECT token, previous_token FROM bingo_card_shares WHERE card_id = $1",
+		cardID,
+	).Scan(&token, &previous)
+	if err != nil {
+		return nil
+	}
+	if previous != nil {
+		return []string{token, *previous}
+	}
+	return []string{token}
+}
+
+// purgeShareTokens drops every cached response and OG image for tokens.
+func (s *CardService) purgeShareTokens(ctx context.Context, tokens []string) {
+	if s.shareCache == nil || len(tokens) == 0 {
+		return
+	}
+	s.shareCache.Purge(ctx, tokens...)
+}
+
+// RevokeShare disables sharing. The current and any superseded token stop
+// resolving immediately.
 func (s *CardService) RevokeShare(ctx context.Context, userID, cardID uuid.UUID) error {
 	cardOwnerID, _, err := s.loadCardOwner(ctx, cardID)
 	if err != nil {
 		return err
 	}
-	if cardOwnerID != userID {
+	if !authz.CanEditCard(authz.For(userID, cardOwnerID)) {
 		return ErrNotCardOwner
 	}
 
+	oldTokens := s.cachedShareTokens(ctx, cardID)
 	if _, err := s.db.Exec(ctx, "DELETE FROM bingo_card_shares WHERE card_id = $1", cardID); err != nil {
 		return fmt.Errorf("revoking card share: %w", err)
 	}
+	s.invalidateShareCache(ctx, cardID)
+	s.purgeShareTokens(ctx, oldTokens)
 
 	return nil
 }
 
+// GetSharedCardByToken resolves a share token to its public card. With a
+// share cache set, recent responses are served from Redis; expired and
+// revoked tokens are never cached.
 func (s *CardService) GetSharedCardByToken(ctx context.Context, token string) (*models.SharedCard, error) {
+	if s.shareCache != nil {
+		if entry, ok := s.shareCache.getCard(ctx, token); ok {
+			if entry.Touch {
+				s.recordShareAccess(ctx, token)
+			}
+			return entry.Card, nil
+		}
+	}
+
 	card := models.PublicBingoCard{}
 	var expiresAt *time.Time
+	var ownerMinimized bool
+	var current bool
+	var graceUntil *time.Time
+	var showCompletions, showNotes bool
 
 	err := s.db.QueryRow(ctx, `
 		SELECT c.id, c.year, c.category, c.title, c.grid_size, c.header_text, c.has_free_space,
-		       c.free_space_position, c.is_finalized, s.expires_at
+		       c.free_space_position, c.is_finalized, s.expires_at, c.free_space_text, u.data_minimization,
+		       s.token = $1, s.previous_token_expires_at, c.updated_at, s.show_completions, s.show_notes
 		FROM bingo_card_shares s
 		JOIN bingo_cards c ON c.id = s.card_id
 		JOIN users u ON u.id = c.user_id AND u.deleted_at IS NULL
-		WHERE s.token = $1
+		WHERE s.token = $1 OR s.previous_token = $1
 	`, token).Scan(
 		&card.ID,
 		&card.Year,
@@ -137,6 +230,13 @@ func (s *CardService) GetSharedCardByToken(ctx context.Context, token string) (*
 		&card.FreeSpacePos,
 		&card.IsFinalized,
 		&expiresAt,
+		&card.FreeSpaceText,
+		&ownerMinimized,
+		&current,
+		&graceUntil,
+		&card.UpdatedAt,
+		&showCompletions,
+		&showNotes,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrShareNotFound
@@ -147,12 +247,25 @@ func (s *CardService) GetSharedCardByToken(ctx context.Context, token string) (*
 	if !card.IsFinalized {
 		return nil, ErrShareNotFound
 	}
-	if expiresAt != nil && expiresAt.Before(time.Now()) {
+	if current && expiresAt != nil && expiresAt.Before(time.Now()) {
 		return nil, ErrShareNotFound
 	}
+	if !current && (graceUntil == nil || !graceUntil.After(time.Now())) {
+		return nil, ErrShareNotFound
+	}
+
+	// Read the cache version before the items so a completion committed in
+	// between leaves this response under an outdated version.
+	var cacheVersion string
+	cacheable := s.shareCache != nil
+	if cacheable {
+		if cacheVersion, err = s.shareCache.version(ctx, card.ID); err != nil {
+			cacheable = false
+		}
+	}
 
 	rows, err := s.db.Query(ctx, `
-		SELECT position, content, is_completed
+		SELECT position, content, is_completed, is_private, proof_url, notes
 		FROM bingo_items
 		WHERE card_id = $1
 		ORDER BY position
@@ -165,20 +278,65 @@ func (s *CardService) GetSharedCardByToken(ctx context.Context, token string) (*
 	items := make([]models.PublicBingoItem, 0)
 	for rows.Next() {
 		var item models.PublicBingoItem
-		if err := rows.Scan(&item.Position, &item.Content, &item.IsCompleted); err != nil {
+		if err := rows.Scan(&item.Position, &item.Content, &item.IsCompleted, &item.IsPrivate, &item.ProofURL, &item.Notes); err != nil {
 			return nil, fmt.Errorf("scanning shared item: %w", err)
 		}
+		if item.IsPrivate {
+			item.Content = models.PrivateItemPlaceholder
+			item.ProofURL = nil
+			item.Notes = nil
+		}
+		// A proof is only ever attached to a completed goal, so it goes too.
+		if !showCompletions {
+			item.IsCompleted = false
+			item.ProofURL = nil
+		}
+		if !showNotes {
+			item.Notes = nil
+		}
 		items = append(items, item)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterating shared items: %w", err)
 	}
 
+	// Owners with data minimization on get no share access analytics. Access
+	// stats describe the current token, so superseded visits aren't counted.
+	touch := !ownerMinimized && current
+	if touch {
+		s.recordShareAccess(ctx, token)
+	}
+
+	shared := &models.SharedCard{
+		Card:              card,
+		Items:             items,
+		FreeSpace:         models.NewFreeSpaceItem(card.HasFreeSpace, card.FreeSpacePos, card.FreeSpaceText),
+		Superseded:        !current,
+		CompletionsHidden: !showCompletions,
+	}
+	if cacheable {
+		validUntil := expiresAt
+		if !current {
+			validUntil = graceUntil
+		}
+		s.shareCache.putCard(ctx, token, sharedCardCacheEntry{
+			CardID:  card.ID,
+			Version: cacheVersion,
+			Touch:   touch,
+			Card:    shared,
+		}, validUntil)
+	}
+
+	return shared, nil
+}
+
+// recordShareAccess updates share access analytics and queues the visit for
+// the access log. A failure never blocks the share from rendering.
+func (s *CardService) recordShareAccess(ctx context.Context, token string) {
+	s.logShareVisit(ctx, token)
 	if err := s.touchShareToken(ctx, token); err != nil {
 		logging.Warn("Failed to record share access", map[string]interface{}{"error": err.Error()})
 	}
-
-	return &models.SharedCard{Card: card, Items: items}, nil
 }
 
 func (s *CardService) loadCardOwner(ctx context.Context, cardID uuid.UUID) (uuid.UUID, bool, error) {
@@ -10,6 +10,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
 func TestCardService_CreateOrRotateShare_NotOwner(t *testing.T) {
@@ -29,7 +31,7 @@ func TestCardService_CreateOrRotateShare_NotOwner(t *testing.T) {
 	}
 
 	svc := NewCardService(db)
-	_, err := svc.CreateOrRotateShare(context.Background(), userID, cardID, nil)
+	_, err := svc.CreateOrRotateShare(context.Background(), userID, cardID, nil, nil, models.ShareOptions{})
 	if !errors.Is(err, ErrNotCardOwner) {
 		t.Fatalf("expected ErrNotCardOwner, got %v", err)
 	}
@@ -51,7 +53,7 @@ func TestCardService_CreateOrRotateShare_NotFinalized(t *testing.T) {
 	}
 
 	svc := NewCardService(db)
-	_, err := svc.CreateOrRotateShare(context.Background(), userID, cardID, nil)
+	_, err := svc.CreateOrRotateShare(context.Background(), userID, cardID, nil, nil, models.ShareOptions{})
 	if !errors.Is(err, ErrCardNotFinalized) {
 		t.Fatalf("expected ErrCardNotFinalized, got %v", err)
 	}
@@ -81,12 +83,12 @@ func TestCardService_CreateOrRotateShare_Success_NoExpiry(t *testing.T) {
 			if args[2] != nil {
 				gotExpiresAt, _ = args[2].(*time.Time)
 			}
-			return rowFromValues(cardID, gotToken, createdAt, (*time.Time)(nil), (*time.Time)(nil), 0)
+			return rowFromValues(cardID, gotToken, createdAt, (*time.Time)(nil), (*time.Time)(nil), 0, (*time.Time)(nil), true, false)
 		},
 	}
 
 	svc := NewCardService(db)
-	share, err := svc.CreateOrRotateShare(context.Background(), userID, cardID, nil)
+	share, err := svc.CreateOrRotateShare(context.Background(), userID, cardID, nil, nil, models.ShareOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -126,12 +128,12 @@ func TestCardService_CreateOrRotateShare_Success_WithExpiry(t *testing.T) {
 			if args[2] != nil {
 				gotExpiresAt, _ = args[2].(*time.Time)
 			}
-			return rowFromValues(cardID, args[1], time.Now(), &expiresAt, (*time.Time)(nil), 0)
+			return rowFromValues(cardID, args[1], time.Now(), &expiresAt, (*time.Time)(nil), 0, (*time.Time)(nil), true, false)
 		},
 	}
 
 	svc := NewCardService(db)
-	share, err := svc.CreateOrRotateShare(context.Background(), userID, cardID, &expiresAt)
+	share, err := svc.CreateOrRotateShare(context.Background(), userID, cardID, &expiresAt, nil, models.ShareOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -208,15 +210,17 @@ func TestCardService_GetSharedCardByToken_Success(t *testing.T) {
 			if !strings.Contains(sql, "FROM bingo_card_shares") {
 				t.Fatalf("unexpected query for share lookup: %s", sql)
 			}
-			return rowFromValues(cardID, year, (*string)(nil), (*string)(nil), gridSize, header, hasFree, &freePos, true, expiresAt)
+			return rowFromValues(cardID, year, (*string)(nil), (*string)(nil), gridSize, header, hasFree, &freePos, true, expiresAt, (*string)(nil), false, true, (*time.Time)(nil), time.Now(), true, false)
 		},
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
 			if !strings.Contains(sql, "FROM bingo_items") {
 				t.Fatalf("unexpected query for items: %s", sql)
 			}
+			proof := "/proofs/photo.jpg"
+			notes := "Went well"
 			return &fakeRows{rows: [][]any{
-				{0, "Goal A", false},
-				{1, "Goal B", true},
+				{0, "Goal A", false, false, &proof, &notes},
+				{1, "Goal B", true, true, &proof, &notes},
 			}}, nil
 		},
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -247,11 +251,48 @@ func TestCardService_GetSharedCardByToken_Success(t *testing.T) {
 	if shared.Items[1].IsCompleted != true {
 		t.Fatal("expected completion state to be true for item 2")
 	}
+	if shared.Items[0].Content != "Goal A" {
+		t.Fatalf("expected public content, got %q", shared.Items[0].Content)
+	}
+	if shared.Items[1].Content != models.PrivateItemPlaceholder {
+		t.Fatalf("expected private item placeholder, got %q", shared.Items[1].Content)
+	}
+	if shared.Items[0].ProofURL == nil || shared.Items[1].ProofURL != nil {
+		t.Fatalf("expected proof only on the public item, got %+v", shared.Items)
+	}
+	if shared.Items[0].Notes != nil || shared.Items[1].Notes != nil {
+		t.Fatalf("expected notes hidden by default, got %+v", shared.Items)
+	}
+	if shared.CompletionsHidden {
+		t.Fatal("expected completions shown by default")
+	}
+	if shared.FreeSpace == nil || shared.FreeSpace.Position != freePos || shared.FreeSpace.Content != models.DefaultFreeSpaceText {
+		t.Fatalf("expected default free space pseudo-item at %d, got %+v", freePos, shared.FreeSpace)
+	}
 	if !touchCalled {
 		t.Fatal("expected share access to be recorded")
 	}
 }
 
+func TestCardService_GetSharedCardByToken_DataMinimizedSkipsAccessRecord(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(uuid.New(), 2025, (*string)(nil), (*string)(nil), 5, "BINGO", true, (*int)(nil), true, (*time.Time)(nil), (*string)(nil), true, true, (*time.Time)(nil), time.Now(), true, false)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE bingo_card_shares") {
+				t.Fatal("expected no share access record for a data-minimized owner")
+			}
+			return fakeCommandTag{}, nil
+		},
+	}
+
+	svc := NewCardService(db)
+	if _, err := svc.GetSharedCardByToken(context.Background(), "deadbeef"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestCardService_GetSharedCardByToken_Expired(t *testing.T) {
 	cardID := uuid.New()
 	token := "deadbeef"
@@ -259,7 +300,7 @@ func TestCardService_GetSharedCardByToken_Expired(t *testing.T) {
 
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			return rowFromValues(cardID, 2025, (*string)(nil), (*string)(nil), 5, "BINGO", true, (*int)(nil), true, &expired)
+			return rowFromValues(cardID, 2025, (*string)(nil), (*string)(nil), 5, "BINGO", true, (*int)(nil), true, &expired, (*string)(nil), false, true, (*time.Time)(nil), time.Now(), true, false)
 		},
 	}
 
@@ -269,3 +310,209 @@ func TestCardService_GetSharedCardByToken_Expired(t *testing.T) {
 		t.Fatalf("expected ErrShareNotFound, got %v", err)
 	}
 }
+
+func TestCardService_CreateOrRotateShare_PassesGraceDeadline(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	graceUntil := time.Now().Add(7 * 24 * time.Hour)
+	var gotGrace *time.Time
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM bingo_cards") {
+				return rowFromValues(userID, true)
+			}
+			if !strings.Contains(sql, "previous_token = CASE") || !strings.Contains(sql, "LEAST($4::timestamptz, bingo_card_shares.expires_at)") {
+				t.Fatalf("expected rotation to keep the old token capped at its own expiry, got %s", sql)
+			}
+			gotGrace, _ = args[3].(*time.Time)
+			return rowFromValues(cardID, args[1], time.Now(), (*time.Time)(nil), (*time.Time)(nil), 0, &graceUntil, true, false)
+		},
+	}
+
+	svc := NewCardService(db)
+	share, err := svc.CreateOrRotateShare(context.Background(), userID, cardID, nil, &graceUntil, models.ShareOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotGrace == nil || !gotGrace.Equal(graceUntil) {
+		t.Fatalf("expected grace deadline arg %v, got %v", graceUntil, gotGrace)
+	}
+	if share.SupersededUntil == nil || !share.SupersededUntil.Equal(graceUntil) {
+		t.Fatalf("expected superseded_until %v, got %v", graceUntil, share.SupersededUntil)
+	}
+}
+
+func TestCardService_GetShareStatus_HidesEndedGrace(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	ended := time.Now().Add(-time.Minute)
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM bingo_cards") {
+				return rowFromValues(userID, true)
+			}
+			return rowFromValues(cardID, "token", time.Now(), (*time.Time)(nil), (*time.Time)(nil), 0, &ended, true, false)
+		},
+	}
+
+	svc := NewCardService(db)
+	share, err := svc.GetShareStatus(context.Background(), userID, cardID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if share.SupersededUntil != nil {
+		t.Fatalf("expected ended grace period to be hidden, got %v", share.SupersededUntil)
+	}
+}
+
+// supersededShareDB resolves a rotated-out token whose grace ends at graceUntil.
+func supersededShareDB(t *testing.T, graceUntil *time.Time, touched *bool) *fakeDB {
+	t.Helper()
+	return &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "s.previous_token = $1") {
+				t.Fatalf("expected lookup to match superseded tokens, got %s", sql)
+			}
+			return rowFromValues(uuid.New(), 2025, (*string)(nil), (*string)(nil), 5, "BINGO", true, (*int)(nil), true, (*time.Time)(nil), (*string)(nil), false, false, graceUntil, time.Now(), true, false)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{{0, "Goal A", true, false, (*string)(nil), (*string)(nil)}}}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			*touched = true
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+}
+
+func TestCardService_GetSharedCardByToken_SupersededDuringGrace(t *testing.T) {
+	graceUntil := time.Now().Add(3 * 24 * time.Hour)
+	var touched bool
+
+	svc := NewCardService(supersededShareDB(t, &graceUntil, &touched))
+	shared, err := svc.GetSharedCardByToken(context.Background(), "oldtoken")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !shared.Superseded {
+		t.Fatal("expected superseded share to be marked")
+	}
+	if len(shared.Items) != 1 {
+		t.Fatalf("expected items to load, got %d", len(shared.Items))
+	}
+	if touched {
+		t.Fatal("expected superseded visits not to count as access to the current link")
+	}
+}
+
+func TestCardService_GetSharedCardByToken_SupersededAfterGrace(t *testing.T) {
+	ended := time.Now().Add(-time.Minute)
+	var touched bool
+
+	svc := NewCardService(supersededShareDB(t, &ended, &touched))
+	if _, err := svc.GetSharedCardByToken(context.Background(), "oldtoken"); !errors.Is(err, ErrShareNotFound) {
+		t.Fatalf("expected ErrShareNotFound after grace, got %v", err)
+	}
+
+	// A superseded match without a grace deadline never resolves.
+	svc = NewCardService(supersededShareDB(t, nil, &touched))
+	if _, err := svc.GetSharedCardByToken(context.Background(), "oldtoken"); !errors.Is(err, ErrShareNotFound) {
+		t.Fatalf("expected ErrShareNotFound without grace, got %v", err)
+	}
+}
+
+func TestCardService_RevokeShare_DeletesSupersededToken(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	var deleted string
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(userID, true)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			deleted = sql
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	svc := NewCardService(db)
+	if err := svc.RevokeShare(context.Background(), userID, cardID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Both tokens live on the one share row, so deleting it kills the grace too.
+	if !strings.Contains(deleted, "DELETE FROM bingo_card_shares WHERE card_id = $1") {
+		t.Fatalf("expected the share row to be deleted, got %q", deleted)
+	}
+}
+
+func TestCardService_ShareOptions_HideCompletionsShowNotes(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "owner@example.com", Username: "owner"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	cards := NewCardService(db)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: 2026, GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+	notes, link := "Felt great", "https://example.com/run.jpg"
+	if _, err := cards.CompleteItem(ctx, user.ID, card.ID, 0, models.CompleteItemParams{Notes: &notes, ProofURL: &link}); err != nil {
+		t.Fatalf("unexpected error completing: %v", err)
+	}
+
+	share, err := cards.CreateOrRotateShare(ctx, user.ID, card.ID, nil, nil, models.ShareOptions{ShowCompletions: boolPtr(false), ShowNotes: boolPtr(true)})
+	if err != nil {
+		t.Fatalf("unexpected error sharing: %v", err)
+	}
+	if share.ShowCompletions || !share.ShowNotes {
+		t.Fatalf("expected completions hidden and notes shown, got %+v", share)
+	}
+	shared, err := cards.GetSharedCardByToken(ctx, share.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	item := shared.Items[0]
+	if !shared.CompletionsHidden || item.IsCompleted || item.ProofURL != nil {
+		t.Fatalf("expected completion and proof stripped, got %+v", shared)
+	}
+	if item.Notes == nil || *item.Notes != notes {
+		t.Fatalf("expected shared notes, got %+v", item.Notes)
+	}
+	if _, err := cards.BuildSharedRecap(ctx, share.Token); !errors.Is(err, ErrShareNotFound) {
+		t.Fatalf("expected no recap for a share hiding completions, got %v", err)
+	}
+
+	// Rotating without options keeps the share's settings.
+	share, err = cards.CreateOrRotateShare(ctx, user.ID, card.ID, nil, nil, models.ShareOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error rotating: %v", err)
+	}
+	if share.ShowCompletions || !share.ShowNotes {
+		t.Fatalf("expected rotation to keep the options, got %+v", share)
+	}
+
+	share, err = cards.CreateOrRotateShare(ctx, user.ID, card.ID, nil, nil, models.ShareOptions{ShowCompletions: boolPtr(true)})
+	if err != nil {
+		t.Fatalf("unexpected error rotating: %v", err)
+	}
+	shared, err = cards.GetSharedCardByToken(ctx, share.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shared.CompletionsHidden || !shared.Items[0].IsCompleted || shared.Items[0].ProofURL == nil || shared.Items[0].Notes == nil {
+		t.Fatalf("expected completions, proof and notes shown, got %+v", shared.Items[0])
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
+	"github.com/HammerMeetNail/yearofbingo/internal/bingo"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// MaxShuffleHistory is how many pre-shuffle layouts a draft keeps for undo.
+const MaxShuffleHistory = 5
+
+var (
+	ErrInvalidShuffleSeed = errors.New("invalid shuffle seed")
+	ErrNoShuffleToUndo    = errors.New("no shuffle to undo")
+	ErrShuffleUndoStale   = errors.New("card changed since the last shuffle")
+)
+
+type ShuffleResult struct {
+	Card *models.BingoCard
+	Seed int64
+}
+
+// Shuffle lays a draft's goals out again with a seeded shuffle, keeping the
+// free space where it is. A nil seed picks a random one; the seed used is
+// returned so the layout can be reproduced. The previous layout is saved for
+// UndoShuffle.
+func (s *CardService) Shuffle(ctx context.Context, userID, cardID uuid.UUID, seed *int64) (*ShuffleResult, error) {
+	if seed != nil && (*seed < 0 || *seed > bingo.MaxShuffleSeed) {
+		return nil, ErrInvalidShuffleSeed
+	}
+	var shuffleSeed int64
+	if seed != nil {
+		shuffleSeed = *seed
+	} else {
+		shuffleSeed = rand.Int63n(bingo.MaxShuffleSeed + 1)
+	}
+
+	card, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
+		return nil, ErrNotCardOwner
+	}
+	if card.IsFinalized {
+		return nil, ErrCardFinalized
+	}
+
+	if len(card.Items) == 0 {
+		return &ShuffleResult{Card: card, Seed: shuffleSeed}, nil
+	}
+
+	var freePos *int
+	if card.HasFreePositionSet() {
+		freePos = card.FreeSpacePos
+	}
+	layout := bingo.ShuffleLayout(card.Items, card.GridSize, freePos, shuffleSeed)
+	if !layoutFitsCard(card, layout) {
+		// Only possible for a card holding more goals than it has squares.
+		return nil, ErrInvalidPosition
+	}
+
+	previous := make(map[uuid.UUID]int, len(card.Items))
+	for _, item := range card.Items {
+		previous[item.ID] = item.Position
+	}
+	previousJSON, err := json.Marshal(previous)
+	if err != nil {
+		return nil, fmt.Errorf("encoding shuffle history: %w", err)
+	}
+
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := applyItemLayout(ctx, tx, card.Items, layout); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx,
+		"INSERT INTO card_shuffle_history (card_id, layout, seed) VALUES ($1, $2, $3)",
+		cardID, previousJSON, shuffleSeed,
+	); err != nil {
+		return nil, fmt.Errorf("saving shuffle history: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM card_shuffle_history
+		 WHERE card_id = $1 AND id NOT IN (
+		     SELECT id FROM card_shuffle_history WHERE card_id = $1 ORDER BY created_at DESC LIMIT $2
+		 )`,
+		cardID, MaxShuffleHistory,
+	); err != nil {
+		return nil, fmt.Errorf("trimming shuffle history: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		if isItemPositionViolation(err) {
+			return nil, ErrInvalidPosition
+		}
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	card, err = s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	return &ShuffleResult{Card: card, Seed: shuffleSeed}, nil
+}
+
+// UndoShuffle puts a draft back to its layout before the latest shuffle. If
+// goals were added, removed or moved since, the saved layouts no longer fit
+// and the history is dropped.
+func (s *CardService) UndoShuffle(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error) {
+	card, err := s.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanEditCard(authz.For(userID, card.UserID)) {
+		return nil, ErrNotCardOwner
+	}
+	if card.IsFinalized {
+		return nil, ErrCardFinalized
+	}
+
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var historyID uuid.UUID
+	var layoutJSON []byte
+	err = tx.QueryRow(ctx,
+		`SELECT id, layout FROM card_shuffle_history
+		 WHERE card_id = $1
+		 ORDER BY created_at DESC
+		 LIMIT 1
+		 FOR UPDATE`,
+		cardID,
+	).Scan(&historyID, &layoutJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoShuffleToUndo
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading shuffle history: %w", err)
+	}
+
+	var layout map[uuid.UUID]int
+	if err := json.Unmarshal(layoutJSON, &layout); err != nil || !layoutFitsCard(card, layout) {
+		if _, err := tx.Exec(ctx, "DELETE FROM card_shuffle_history WHERE card_id = $1", cardID); err != nil {
+			return nil, fmt.Errorf("clearing shuffle history: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("committing transaction: %w", err)
+		}
+		return nil, ErrShuffleUndoStale
+	}
+
+	if err := applyItemLayout(ctx, tx, card.Items, layout); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM card_shuffle_history WHERE id = $1", historyID); err != nil {
+		return nil, fmt.Errorf("removing shuffle history: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		if isItemPositionViolation(err) {
+			return nil, ErrInvalidPosition
+		}
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return s.GetByID(ctx, cardID)
+}
+
+// applyItemLayout moves every item to its position in layout.
+func applyItemLayout(ctx context.Context, tx Tx, items []models.BingoItem, layout map[uuid.UUID]int) error {
+	// First, set all positions to negative to avoid unique constraint violations
+	for i, item := range items {
+		if _, err := tx.Exec(ctx,
+			"UPDATE bingo_items SET position = $1 WHERE id = $2",
+			-(i + 1), item.ID,
+		); err != nil {
+			return fmt.Errorf("clearing position: %w", err)
+		}
+	}
+
+	// Then assign new positions
+	for _, item := range items {
+		if _, err := tx.Exec(ctx,
+			"UPDATE bingo_items SET position = $1 WHERE id = $2",
+			layout[item.ID], item.ID,
+		); err != nil {
+			return fmt.Errorf("assigning new position: %w", err)
+		}
+	}
+	return nil
+}
+
+// layoutFitsCard reports whether layout places exactly the card's items on
+// distinct squares that are on the grid and not the free space.
+func layoutFitsCard(card *models.BingoCard, layout map[uuid.UUID]int) bool {
+	if len(layout) != len(card.Items) {
+		return false
+	}
+	used := make(map[int]bool, len(layout))
+	for _, item := range card.Items {
+		pos, ok := layout[item.ID]
+		if !ok || !card.IsValidItemPosition(pos) || used[pos] {
+			return false
+		}
+		used[pos] = true
+	}
+	return true
+}
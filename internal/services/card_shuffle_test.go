@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/bingo"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// shuffleTx records the final position given to each item and the SQL of any
+// other statement.
+type shuffleTx struct {
+	positions map[uuid.UUID]int
+	other     []string
+	args      [][]any
+}
+
+func (r *shuffleTx) tx(queryRow func(sql string) Row) *fakeTx {
+	r.positions = map[uuid.UUID]int{}
+	return &fakeTx{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE bingo_items SET position") {
+				r.positions[args[1].(uuid.UUID)] = args[0].(int)
+				return fakeCommandTag{rowsAffected: 1}, nil
+			}
+			r.other = append(r.other, sql)
+			r.args = append(r.args, args)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return queryRow(sql)
+		},
+	}
+}
+
+func shuffleCardItems(cardID uuid.UUID, positions ...int) ([][]any, []models.BingoItem) {
+	rows := make([][]any, len(positions))
+	items := make([]models.BingoItem, len(positions))
+	for i, pos := range positions {
+		id := uuid.New()
+		rows[i] = []any{id, cardID, pos, "Goal", false, nil, nil, nil, time.Now(), false, nil}
+		items[i] = models.BingoItem{ID: id, Position: pos}
+	}
+	return rows, items
+}
+
+func TestCardService_Shuffle_SeededAndSavesHistory(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	freePos := 4
+	rows, items := shuffleCardItems(cardID, 0, 1, 2, 3, 5, 6, 7, 8)
+	db := newCardDB(cardID, userID, 3, true, &freePos, false, rows)
+	var rec shuffleTx
+	db.BeginFunc = func(ctx context.Context) (Tx, error) {
+		return rec.tx(nil), nil
+	}
+
+	seed := int64(2026)
+	result, err := NewCardService(db).Shuffle(context.Background(), userID, cardID, &seed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Seed != seed {
+		t.Fatalf("expected seed %d, got %d", seed, result.Seed)
+	}
+
+	want := bingo.ShuffleLayout(items, 3, &freePos, seed)
+	for id, pos := range want {
+		if rec.positions[id] != pos {
+			t.Fatalf("expected item %s at %d, got %d", id, pos, rec.positions[id])
+		}
+		if pos == freePos {
+			t.Fatal("expected the free space to stay empty")
+		}
+	}
+
+	if len(rec.other) != 2 || !strings.Contains(rec.other[0], "INSERT INTO card_shuffle_history") || !strings.Contains(rec.other[1], "DELETE FROM card_shuffle_history") {
+		t.Fatalf("expected history insert and trim, got %v", rec.other)
+	}
+	var saved map[uuid.UUID]int
+	if err := json.Unmarshal(rec.args[0][1].([]byte), &saved); err != nil {
+		t.Fatalf("invalid history layout: %v", err)
+	}
+	for _, item := range items {
+		if saved[item.ID] != item.Position {
+			t.Fatalf("expected history to keep item %s at %d, got %d", item.ID, item.Position, saved[item.ID])
+		}
+	}
+	if rec.args[1][1] != MaxShuffleHistory {
+		t.Fatalf("expected history trimmed to %d, got %v", MaxShuffleHistory, rec.args[1][1])
+	}
+}
+
+func TestCardService_Shuffle_PicksSeedWhenOmitted(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 3, false, nil, false, [][]any{})
+
+	result, err := NewCardService(db).Shuffle(context.Background(), userID, cardID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Seed < 0 || result.Seed > bingo.MaxShuffleSeed {
+		t.Fatalf("expected a seed in range, got %d", result.Seed)
+	}
+}
+
+func TestCardService_Shuffle_InvalidSeed(t *testing.T) {
+	svc := NewCardService(&fakeDB{})
+	for _, seed := range []int64{-1, bingo.MaxShuffleSeed + 1} {
+		if _, err := svc.Shuffle(context.Background(), uuid.New(), uuid.New(), &seed); !errors.Is(err, ErrInvalidShuffleSeed) {
+			t.Fatalf("seed %d: expected ErrInvalidShuffleSeed, got %v", seed, err)
+		}
+	}
+}
+
+func TestCardService_UndoShuffle(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	freePos := 4
+	rows, items := shuffleCardItems(cardID, 0, 1, 2)
+	historyID := uuid.New()
+
+	historyRow := func(layout map[uuid.UUID]int) func(sql string) Row {
+		return func(sql string) Row {
+			if !strings.Contains(sql, "FROM card_shuffle_history") {
+				t.Fatalf("unexpected query: %s", sql)
+			}
+			if layout == nil {
+				return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+			}
+			raw, _ := json.Marshal(layout)
+			return rowFromValues(historyID, raw)
+		}
+	}
+
+	t.Run("restores the saved layout", func(t *testing.T) {
+		db := newCardDB(cardID, userID, 3, true, &freePos, false, rows)
+		layout := map[uuid.UUID]int{items[0].ID: 8, items[1].ID: 7, items[2].ID: 0}
+		var rec shuffleTx
+		db.BeginFunc = func(ctx context.Context) (Tx, error) { return rec.tx(historyRow(layout)), nil }
+
+		if _, err := NewCardService(db).UndoShuffle(context.Background(), userID, cardID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for id, pos := range layout {
+			if rec.positions[id] != pos {
+				t.Fatalf("expected item %s back at %d, got %d", id, pos, rec.positions[id])
+			}
+		}
+		if len(rec.other) != 1 || !strings.Contains(rec.other[0], "DELETE FROM card_shuffle_history WHERE id") {
+			t.Fatalf("expected the used history entry to be removed, got %v", rec.other)
+		}
+	})
+
+	t.Run("nothing to undo", func(t *testing.T) {
+		db := newCardDB(cardID, userID, 3, true, &freePos, false, rows)
+		var rec shuffleTx
+		db.BeginFunc = func(ctx context.Context) (Tx, error) { return rec.tx(historyRow(nil)), nil }
+
+		if _, err := NewCardService(db).UndoShuffle(context.Background(), userID, cardID); !errors.Is(err, ErrNoShuffleToUndo) {
+			t.Fatalf("expected ErrNoShuffleToUndo, got %v", err)
+		}
+	})
+
+	t.Run("stale layout clears history", func(t *testing.T) {
+		db := newCardDB(cardID, userID, 3, true, &freePos, false, rows)
+		// A goal added since the shuffle is missing, and one sits on the free space.
+		layout := map[uuid.UUID]int{items[0].ID: 4, items[1].ID: 7}
+		var rec shuffleTx
+		db.BeginFunc = func(ctx context.Context) (Tx, error) { return rec.tx(historyRow(layout)), nil }
+
+		if _, err := NewCardService(db).UndoShuffle(context.Background(), userID, cardID); !errors.Is(err, ErrShuffleUndoStale) {
+			t.Fatalf("expected ErrShuffleUndoStale, got %v", err)
+		}
+		if len(rec.positions) != 0 {
+			t.Fatalf("expected no positions to change, got %v", rec.positions)
+		}
+		if len(rec.other) != 1 || !strings.Contains(rec.other[0], "DELETE FROM card_shuffle_history WHERE card_id") {
+			t.Fatalf("expected history to be cleared, got %v", rec.other)
+		}
+	})
+
+	t.Run("finalized", func(t *testing.T) {
+		db := newCardDB(cardID, userID, 3, true, &freePos, true, rows)
+		if _, err := NewCardService(db).UndoShuffle(context.Background(), userID, cardID); !errors.Is(err, ErrCardFinalized) {
+			t.Fatalf("expected ErrCardFinalized, got %v", err)
+		}
+	})
+}
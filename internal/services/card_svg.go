@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"golang.org/x/image/font"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// svgFontFamily prefers the Go font the PNG renderer draws with, then
+// falls back to common sans-serif fonts of similar width.
+const svgFontFamily = "Go, 'Helvetica Neue', Arial, sans-serif"
+
+// RenderCardSVG renders the card as SVG with the same layout as
+// RenderReminderPNG. Cell text is wrapped and truncated with the PNG
+// renderer's font metrics, and each line is stretched to its measured width,
+// so the two images break and ellipsize goals identically whatever font the
+// viewer substitutes.
+func RenderCardSVG(card models.BingoCard, items []models.BingoItem, opts RenderOptions) ([]byte, error) {
+	bodyFace, err := newFontFace(renderBodyFontSize)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = bodyFace.Close() }()
+
+	palette := opts.palette()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="%s">`,
+		renderWidth, renderHeight, renderWidth, renderHeight, svgFontFamily)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, renderWidth, renderHeight, svgColor(palette.background))
+
+	stats := buildReminderStats(&card, items)
+	svgText(&buf, renderPadding, 44, 32, palette.title, 0, card.DisplayName())
+	if !opts.HideProgress {
+		statsLine := fmt.Sprintf("%d/%d complete - %s", stats.Completed, stats.Total, pluralizeBingo(stats.Bingos))
+		svgText(&buf, renderPadding, 70, 16, palette.stats, 0, statsLine)
+	}
+
+	gridSize := card.GridSize
+	if !models.IsValidGridSize(gridSize) {
+		gridSize = models.MaxGridSize
+	}
+	cellSize := renderCellSize(gridSize)
+	gridLeft := (renderWidth - cellSize*gridSize) / 2
+	gridTop := renderHeaderHeight + renderPadding
+
+	itemByPos := map[int]models.BingoItem{}
+	for _, item := range items {
+		itemByPos[item.Position] = item
+	}
+	freePos := -1
+	if card.HasFreeSpace && card.FreeSpacePos != nil {
+		freePos = *card.FreeSpacePos
+	}
+
+	for row := 0; row < gridSize; row++ {
+		for col := 0; col < gridSize; col++ {
+			pos := row*gridSize + col
+			rect := image.Rect(gridLeft+col*cellSize, gridTop+row*cellSize, gridLeft+(col+1)*cellSize, gridTop+(row+1)*cellSize)
+
+			cell := newRenderCell(card, itemByPos, freePos, pos, opts)
+			// The PNG border is drawn inside the cell, so the stroke is inset
+			// by half its width.
+			fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="%s" stroke-width="2"/>`,
+				rect.Min.X+1, rect.Min.Y+1, rect.Dx()-2, rect.Dy()-2, svgColor(cell.bg), svgColor(palette.border))
+			svgDifficultyDots(&buf, rect, cell.difficulty, cell.textColor)
+
+			if strings.TrimSpace(cell.content) == "" {
+				continue
+			}
+			textRect := image.Rect(
+				rect.Min.X+renderCellPadding,
+				rect.Min.Y+renderCellPadding,
+				rect.Max.X-renderCellPadding,
+				rect.Max.Y-renderCellPadding,
+			)
+			lines, _ := fitCellText(bodyFace, cell.content, textRect.Dx(), cellMaxLines(gridSize))
+			svgWrappedText(&buf, bodyFace, textRect, lines, cell.textColor)
+		}
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+// svgWrappedText places lines where drawWrappedText would: vertically
+// centered as a block, each line centered horizontally.
+func svgWrappedText(buf *bytes.Buffer, face font.Face, rect image.Rectangle, lines []string, clr color.RGBA) {
+	if len(lines) == 0 {
+		return
+	}
+	metrics := face.Metrics()
+	lineHeight := metrics.Height.Ceil()
+	startY := rect.Min.Y + (rect.Dy()-lineHeight*len(lines))/2 + metrics.Ascent.Ceil()
+	for i, line := range lines {
+		width := font.MeasureString(face, line).Ceil()
+		x := rect.Min.X + (rect.Dx()-width)/2
+		svgText(buf, x, startY+i*lineHeight, renderBodyFontSize, clr, width, line)
+	}
+}
+
+// svgText writes one text element with its baseline at (x, y). A positive
+// textLength stretches or squeezes the glyphs to exactly that width.
+func svgText(buf *bytes.Buffer, x, y, size int, clr color.RGBA, textLength int, text string) {
+	fmt.Fprintf(buf, `<text x="%d" y="%d" font-size="%d" fill="%s"`, x, y, size, svgColor(clr))
+	if textLength > 0 {
+		fmt.Fprintf(buf, ` textLength="%d" lengthAdjust="spacingAndGlyphs"`, textLength)
+	}
+	buf.WriteString(`>`)
+	_ = xml.EscapeText(buf, []byte(text))
+	buf.WriteString(`</text>`)
+}
+
+// svgDifficultyDots mirrors drawDifficultyDots.
+func svgDifficultyDots(buf *bytes.Buffer, cell image.Rectangle, difficulty *string, clr color.RGBA) {
+	count := difficultyDotCount(difficulty)
+	radius := maxInt(cell.Dx()/48, 2)
+	y := cell.Min.Y + radius*3
+	x := cell.Max.X - radius*3
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(buf, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`, x-i*3*radius, y, radius, svgColor(clr))
+	}
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
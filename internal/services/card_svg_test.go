@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type parsedSVG struct {
+	texts []string
+	fills []string
+}
+
+// parseCardSVG checks the SVG is well-formed XML and collects its text
+// contents and rect fills in document order.
+func parseCardSVG(t *testing.T, svg []byte) parsedSVG {
+	t.Helper()
+	var parsed parsedSVG
+	decoder := xml.NewDecoder(bytes.NewReader(svg))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return parsed
+		}
+		if err != nil {
+			t.Fatalf("expected well-formed SVG: %v", err)
+		}
+		switch el := token.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "text":
+				inText = true
+				parsed.texts = append(parsed.texts, "")
+			case "rect":
+				for _, attr := range el.Attr {
+					if attr.Name.Local == "fill" {
+						parsed.fills = append(parsed.fills, attr.Value)
+					}
+				}
+			}
+		case xml.CharData:
+			if inText {
+				parsed.texts[len(parsed.texts)-1] += string(el)
+			}
+		case xml.EndElement:
+			if el.Name.Local == "text" {
+				inText = false
+			}
+		}
+	}
+}
+
+func TestRenderCardSVG_MirrorsPNGTextFitting(t *testing.T) {
+	card := models.BingoCard{ID: uuid.New(), UserID: uuid.New(), Year: 2026, GridSize: 5}
+	long := strings.Repeat("Practice the piano every single evening ", 6)
+	unbroken := strings.Repeat("W", models.MaxItemContentLength)
+	items := []models.BingoItem{
+		{ID: uuid.New(), CardID: card.ID, Position: 0, Content: long},
+		{ID: uuid.New(), CardID: card.ID, Position: 1, Content: unbroken},
+	}
+
+	svg, err := RenderCardSVG(card, items, RenderOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed := parseCardSVG(t, svg)
+
+	face, err := newFontFace(renderBodyFontSize)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = face.Close() }()
+	textWidth := renderCellSize(5) - 2*renderCellPadding
+	var want []string
+	for _, item := range items {
+		lines, truncated := fitCellText(face, item.Content, textWidth, cellMaxLines(5))
+		if !truncated {
+			t.Fatalf("expected %q to be truncated in a 5x5 cell", item.Content)
+		}
+		want = append(want, lines...)
+	}
+	// The title and stats line come first, then the cell lines.
+	if got := parsed.texts[2:]; !slices.Equal(got, want) {
+		t.Fatalf("expected the PNG renderer's lines %q, got %q", want, got)
+	}
+	if !strings.HasSuffix(parsed.texts[len(parsed.texts)-1], "...") {
+		t.Fatal("expected the last line to end with an ellipsis")
+	}
+}
+
+func TestRenderCardSVG_CompletionsFreeSpaceAndEscaping(t *testing.T) {
+	freePos := 4
+	freeText := "Me & <you>"
+	card := models.BingoCard{ID: uuid.New(), UserID: uuid.New(), Year: 2026, GridSize: 3, HasFreeSpace: true, FreeSpacePos: &freePos, FreeSpaceText: &freeText}
+	items := []models.BingoItem{
+		{ID: uuid.New(), CardID: card.ID, Position: 0, Content: `<i>"x"&</i>`, IsCompleted: true},
+	}
+
+	completedFill := svgColor(newRenderCell(card, map[int]models.BingoItem{0: items[0]}, freePos, 0, RenderOptions{ShowCompletions: true}).bg)
+	for _, show := range []bool{true, false} {
+		svg, err := RenderCardSVG(card, items, RenderOptions{ShowCompletions: show, HideProgress: !show})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if bytes.Contains(svg, []byte("<i>")) {
+			t.Fatal("expected goal text to be escaped")
+		}
+		parsed := parseCardSVG(t, svg)
+		if !slices.Contains(parsed.texts, `<i>"x"&</i>`) || !slices.Contains(parsed.texts, freeText) {
+			t.Fatalf("expected goal and free space text to round-trip, got %q", parsed.texts)
+		}
+		// The first fill is the background, then one per cell.
+		if got := parsed.fills[1] == completedFill; got != show {
+			t.Fatalf("show_completions=%t: expected completed shading %t, got fill %s", show, show, parsed.fills[1])
+		}
+		hasStats := slices.ContainsFunc(parsed.texts, func(text string) bool { return strings.Contains(text, "complete - ") })
+		if hasStats != show {
+			t.Fatalf("expected the progress line only when shown, got %q", parsed.texts)
+		}
+	}
+}
+
+func TestRenderCardSVG_DarkTheme(t *testing.T) {
+	card := models.BingoCard{ID: uuid.New(), UserID: uuid.New(), Year: 2026, GridSize: 3}
+	items := []models.BingoItem{{ID: uuid.New(), CardID: card.ID, Position: 0, Content: "Done", IsCompleted: true}}
+
+	svg, err := RenderCardSVG(card, items, RenderOptions{ShowCompletions: true, Theme: models.ImageThemeDark})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed := parseCardSVG(t, svg)
+	want := []string{svgColor(darkRenderPalette.background), svgColor(darkRenderPalette.completed), svgColor(darkRenderPalette.cell)}
+	if !slices.Equal(parsed.fills[:3], want) {
+		t.Fatalf("expected dark fills %q, got %q", want, parsed.fills[:3])
+	}
+	if !bytes.Contains(svg, []byte(`fill="`+svgColor(darkRenderPalette.completedText)+`"`)) {
+		t.Fatal("expected completed text in the dark palette")
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
+var testCardStart, testCardEnd = models.DefaultCardPeriod(2024)
+
 func cardRowValues(cardID, userID uuid.UUID, gridSize int, hasFree bool, freePos *int, finalized bool) []any {
 	now := time.Now()
 	return []any{
@@ -32,6 +34,10 @@ func cardRowValues(cardID, userID uuid.UUID, gridSize int, hasFree bool, freePos
 		false,
 		now,
 		now,
+		(*string)(nil),
+		testCardStart,
+		testCardEnd,
+		false,
 	}
 }
 
@@ -232,6 +238,64 @@ func TestCardService_Create_YearConflictWithoutTitle(t *testing.T) {
 	}
 }
 
+func TestCardService_Create_InvalidPeriod(t *testing.T) {
+	svc := &CardService{}
+	start := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(2, 0, 0)
+	_, err := svc.Create(context.Background(), models.CreateCardParams{
+		UserID:    uuid.New(),
+		StartDate: &start,
+		EndDate:   &end,
+		GridSize:  5,
+		Header:    "BINGO",
+		HasFree:   true,
+	})
+	if !errors.Is(err, ErrInvalidCardPeriod) {
+		t.Fatalf("expected ErrInvalidCardPeriod, got %v", err)
+	}
+}
+
+func TestCardService_Create_RollingPeriodFromStartDate(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	start := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	var insertArgs []any
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "SELECT EXISTS") {
+				return rowFromValues(false)
+			}
+			if strings.Contains(sql, "INSERT INTO bingo_cards") {
+				insertArgs = args
+				return rowFromValues(cardRowValues(cardID, userID, 5, true, nil, false)...)
+			}
+			return fakeRow{scanFunc: func(dest ...any) error {
+				return errors.New("unexpected query")
+			}}
+		},
+	}
+
+	svc := NewCardService(db)
+	if _, err := svc.Create(context.Background(), models.CreateCardParams{
+		UserID:    userID,
+		StartDate: &start,
+		GridSize:  5,
+		Header:    "BINGO",
+		HasFree:   true,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if insertArgs[1] != 2025 {
+		t.Fatalf("expected year derived from start date, got %v", insertArgs[1])
+	}
+	if insertArgs[8] != start {
+		t.Fatalf("expected start date %v, got %v", start, insertArgs[8])
+	}
+	if want := time.Date(2026, time.August, 31, 0, 0, 0, 0, time.UTC); insertArgs[9] != want {
+		t.Fatalf("expected end date %v, got %v", want, insertArgs[9])
+	}
+}
+
 func TestCardService_Create_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
@@ -340,7 +404,7 @@ func TestCardService_AddItem_PositionOccupied(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	db := newCardDB(cardID, userID, 2, false, nil, false, [][]any{
-		{uuid.New(), cardID, 0, "Item", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "Item", false, nil, nil, nil, time.Now(), false, nil},
 	})
 
 	svc := NewCardService(db)
@@ -398,12 +462,12 @@ func TestCardService_Shuffle_EmptyItems(t *testing.T) {
 	}
 
 	svc := NewCardService(db)
-	card, err := svc.Shuffle(context.Background(), userID, cardID)
+	result, err := svc.Shuffle(context.Background(), userID, cardID, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if card.ID != cardID {
-		t.Fatalf("expected card ID %v, got %v", cardID, card.ID)
+	if result.Card.ID != cardID {
+		t.Fatalf("expected card ID %v, got %v", cardID, result.Card.ID)
 	}
 }
 
@@ -413,7 +477,7 @@ func TestCardService_Shuffle_NotOwner(t *testing.T) {
 	db := newCardDB(cardID, uuid.New(), 5, true, nil, false, [][]any{})
 
 	svc := NewCardService(db)
-	_, err := svc.Shuffle(context.Background(), userID, cardID)
+	_, err := svc.Shuffle(context.Background(), userID, cardID, nil)
 	if !errors.Is(err, ErrNotCardOwner) {
 		t.Fatalf("expected ErrNotCardOwner, got %v", err)
 	}
@@ -433,19 +497,19 @@ func TestCardService_UpdateVisibility_NotOwner(t *testing.T) {
 
 func TestCardService_BulkUpdateVisibility_Empty(t *testing.T) {
 	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			t.Fatal("unexpected exec for empty cardIDs")
-			return fakeCommandTag{}, nil
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			t.Fatal("unexpected transaction for empty changes")
+			return nil, nil
 		},
 	}
 
 	svc := NewCardService(db)
-	updated, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), nil, true)
+	results, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if updated != 0 {
-		t.Fatalf("expected 0 updated, got %d", updated)
+	if len(results) != 0 {
+		t.Fatalf("expected no results, got %v", results)
 	}
 }
 
@@ -497,6 +561,88 @@ func TestCardService_UpdateConfig_Finalized(t *testing.T) {
 	}
 }
 
+func TestCardService_UpdateConfig_FinalizedAllowsRequireProof(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 5, true, nil, true, [][]any{})
+	var updateArgs []any
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if !strings.Contains(sql, "SET require_proof") {
+			t.Fatalf("unexpected exec: %s", sql)
+		}
+		updateArgs = args
+		return fakeCommandTag{rowsAffected: 1}, nil
+	}
+
+	requireProof := true
+	svc := NewCardService(db)
+	if _, err := svc.UpdateConfig(context.Background(), userID, cardID, models.UpdateCardConfigParams{
+		RequireProof: &requireProof,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updateArgs) != 2 || updateArgs[0] != true || updateArgs[1] != cardID {
+		t.Fatalf("unexpected update args: %v", updateArgs)
+	}
+
+	// Anything beyond require_proof still needs a draft card.
+	header := "BINGO"
+	_, err := svc.UpdateConfig(context.Background(), userID, cardID, models.UpdateCardConfigParams{
+		RequireProof: &requireProof,
+		HeaderText:   &header,
+	})
+	if !errors.Is(err, ErrCardFinalized) {
+		t.Fatalf("expected ErrCardFinalized, got %v", err)
+	}
+}
+
+func TestCardService_CompleteItem_RequireProof(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	itemID := uuid.New()
+	now := time.Now()
+	items := [][]any{
+		{itemID, cardID, 0, "Run a marathon", false, (*time.Time)(nil), (*string)(nil), (*string)(nil), now, false, nil},
+	}
+	row := cardRowValues(cardID, userID, 5, false, nil, true)
+	row[18] = true
+
+	var completed bool
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(row...)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: items}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			completed = true
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	svc := NewCardService(db)
+
+	blank := "   "
+	for _, params := range []models.CompleteItemParams{{}, {Notes: &blank}, {ProofURL: &blank}} {
+		_, err := svc.CompleteItem(context.Background(), userID, cardID, 0, params)
+		if !errors.Is(err, ErrProofRequired) {
+			t.Fatalf("expected ErrProofRequired for %+v, got %v", params, err)
+		}
+	}
+	if completed {
+		t.Fatal("expected no completion without proof")
+	}
+
+	proof := "https://example.com/finish-line.jpg"
+	item, err := svc.CompleteItem(context.Background(), userID, cardID, 0, models.CompleteItemParams{ProofURL: &proof})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !completed || !item.IsCompleted {
+		t.Fatal("expected completion with proof")
+	}
+}
+
 func TestCardService_UpdateConfig_InvalidHeader(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
@@ -549,16 +695,16 @@ func TestCardService_BulkDelete_Empty(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if deleted != 0 {
-		t.Fatalf("expected 0 deleted, got %d", deleted)
+	if len(deleted) != 0 {
+		t.Fatalf("expected nothing deleted, got %v", deleted)
 	}
 }
 
 func TestCardService_BulkUpdateArchive_Empty(t *testing.T) {
 	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			t.Fatal("unexpected exec for empty cardIDs")
-			return fakeCommandTag{}, nil
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			t.Fatal("unexpected query for empty cardIDs")
+			return nil, nil
 		},
 	}
 
@@ -567,32 +713,33 @@ func TestCardService_BulkUpdateArchive_Empty(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if updated != 0 {
-		t.Fatalf("expected 0 updated, got %d", updated)
+	if len(updated) != 0 {
+		t.Fatalf("expected nothing updated, got %v", updated)
 	}
 }
 
-func TestCardService_BulkUpdateArchive_Count(t *testing.T) {
+func TestCardService_BulkUpdateArchive_ReturnsUpdatedIDs(t *testing.T) {
+	owned := uuid.New()
 	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			return fakeCommandTag{rowsAffected: 4}, nil
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{{owned}}}, nil
 		},
 	}
 
 	svc := NewCardService(db)
-	updated, err := svc.BulkUpdateArchive(context.Background(), uuid.New(), []uuid.UUID{uuid.New()}, true)
+	updated, err := svc.BulkUpdateArchive(context.Background(), uuid.New(), []uuid.UUID{owned, uuid.New()}, true)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if updated != 4 {
-		t.Fatalf("expected 4 updated, got %d", updated)
+	if len(updated) != 1 || updated[0] != owned {
+		t.Fatalf("expected only the owned card updated, got %v", updated)
 	}
 }
 
 func TestCardService_BulkUpdateArchive_Error(t *testing.T) {
 	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			return fakeCommandTag{}, errors.New("boom")
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return nil, errors.New("boom")
 		},
 	}
 
@@ -775,6 +922,8 @@ func TestCardService_AddItem_Random_CommitError(t *testing.T) {
 						nil,
 						nil,
 						time.Now(),
+						false,
+						nil,
 					)
 				},
 				QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
@@ -794,14 +943,56 @@ func TestCardService_AddItem_Random_CommitError(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+func TestCardService_AddItem_Random_FlagsTruncatedContent(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	long := strings.Repeat("marathon ", 40)
+
+	for _, tt := range []struct {
+		content string
+		want    bool
+	}{
+		{content: "Read 12 books", want: false},
+		{content: long, want: true},
+	} {
+		db := &fakeDB{
+			BeginFunc: func(ctx context.Context) (Tx, error) {
+				return &fakeTx{
+					QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+						if strings.Contains(sql, "FOR UPDATE") {
+							return rowFromValues(lockCardRowValues(cardID, userID, 5, false, nil, false)...)
+						}
+						return rowFromValues(uuid.New(), cardID, 0, tt.content, false, nil, nil, nil, time.Now(), false, nil)
+					},
+					QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+						return &fakeRows{rows: [][]any{}}, nil
+					},
+					CommitFunc: func(ctx context.Context) error { return nil },
+				}, nil
+			},
+		}
+
+		item, err := NewCardService(db).AddItem(context.Background(), userID, models.AddItemParams{
+			CardID:  cardID,
+			Content: tt.content,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item.ContentTruncated != tt.want {
+			t.Fatalf("content %q: expected ContentTruncated=%v, got %v", tt.content, tt.want, item.ContentTruncated)
+		}
+	}
+}
+
 func TestCardService_UpdateConfig_NoSpaceForFree(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -924,7 +1115,7 @@ func TestCardService_Clone_BeginError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -942,7 +1133,7 @@ func TestCardService_Clone_CreateError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -966,7 +1157,7 @@ func TestCardService_Clone_CopyItemError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -991,7 +1182,7 @@ func TestCardService_Clone_CommitError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1070,7 +1261,7 @@ func TestCardService_GetByUserAndYear_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 
@@ -1176,11 +1367,11 @@ func TestCardService_ListByUser_Success(t *testing.T) {
 	cardID2 := uuid.New()
 	items := map[uuid.UUID][][]any{
 		cardID: {
-			{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+			{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 		},
 		cardID2: {
-			{uuid.New(), cardID2, 1, "B", false, nil, nil, nil, time.Now()},
-			{uuid.New(), cardID2, 2, "C", false, nil, nil, nil, time.Now()},
+			{uuid.New(), cardID2, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+			{uuid.New(), cardID2, 2, "C", false, nil, nil, nil, time.Now(), false, nil},
 		},
 	}
 
@@ -1513,59 +1704,138 @@ func TestCardService_UpdateVisibility_DBError(t *testing.T) {
 	}
 }
 
-func TestCardService_BulkUpdateVisibility_Count(t *testing.T) {
-	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			return fakeCommandTag{rowsAffected: 3}, nil
+// newBulkVisibilityDB returns a DB whose transaction reports owned as the
+// user's cards (id, visible_to_friends, is_finalized) and records updates.
+func newBulkVisibilityDB(owned [][]any, updated *[]uuid.UUID, committed *bool, execErr error) *fakeDB {
+	return &fakeDB{
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			return &fakeTx{
+				QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+					return &fakeRows{rows: owned}, nil
+				},
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					if execErr != nil {
+						return fakeCommandTag{}, execErr
+					}
+					*updated = append(*updated, args[0].(uuid.UUID))
+					return fakeCommandTag{rowsAffected: 1}, nil
+				},
+				CommitFunc: func(ctx context.Context) error {
+					*committed = true
+					return nil
+				},
+			}, nil
 		},
 	}
+}
+
+func TestCardService_BulkUpdateVisibility_PerCardValues(t *testing.T) {
+	hideID := uuid.New()
+	showID := uuid.New()
+	unchangedID := uuid.New()
+	var updated []uuid.UUID
+	committed := false
+	db := newBulkVisibilityDB([][]any{
+		{hideID, true, true},
+		{showID, false, false},
+		{unchangedID, true, false},
+	}, &updated, &committed, nil)
 
 	svc := NewCardService(db)
-	count, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), []uuid.UUID{uuid.New(), uuid.New()}, true)
+	results, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), []models.CardVisibilityChange{
+		{CardID: hideID, VisibleToFriends: false},
+		{CardID: showID, VisibleToFriends: true},
+		{CardID: unchangedID, VisibleToFriends: true},
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if count != 3 {
-		t.Fatalf("expected 3 updated, got %d", count)
+	if !committed {
+		t.Fatal("expected transaction to commit")
+	}
+	if len(updated) != 2 || updated[0] != hideID || updated[1] != showID {
+		t.Fatalf("expected updates for changed cards only, got %v", updated)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if !results[0].Changed || results[0].VisibleToFriends || !results[1].Changed || results[2].Changed {
+		t.Fatalf("unexpected results: %+v", results)
 	}
 }
 
-func TestCardService_BulkUpdateVisibility_Error(t *testing.T) {
-	db := &fakeDB{
-		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			return fakeCommandTag{}, errors.New("boom")
-		},
+func TestCardService_BulkUpdateVisibility_ForeignCardFailsWholeRequest(t *testing.T) {
+	ownedID := uuid.New()
+	foreignID := uuid.New()
+	var updated []uuid.UUID
+	committed := false
+	db := newBulkVisibilityDB([][]any{
+		{ownedID, false, false},
+	}, &updated, &committed, nil)
+
+	svc := NewCardService(db)
+	_, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), []models.CardVisibilityChange{
+		{CardID: ownedID, VisibleToFriends: true},
+		{CardID: foreignID, VisibleToFriends: true},
+	})
+	if !errors.Is(err, ErrCardsNotOwned) {
+		t.Fatalf("expected ErrCardsNotOwned, got %v", err)
+	}
+	var notOwned *CardsNotOwnedError
+	if !errors.As(err, &notOwned) || len(notOwned.CardIDs) != 1 || notOwned.CardIDs[0] != foreignID {
+		t.Fatalf("expected foreign card ID in error, got %v", err)
+	}
+	if len(updated) != 0 || committed {
+		t.Fatalf("expected no writes, got updates %v committed %v", updated, committed)
 	}
+}
+
+func TestCardService_BulkUpdateVisibility_Error(t *testing.T) {
+	cardID := uuid.New()
+	var updated []uuid.UUID
+	committed := false
+	db := newBulkVisibilityDB([][]any{
+		{cardID, false, false},
+	}, &updated, &committed, errors.New("boom"))
 
 	svc := NewCardService(db)
-	_, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), []uuid.UUID{uuid.New()}, true)
+	_, err := svc.BulkUpdateVisibility(context.Background(), uuid.New(), []models.CardVisibilityChange{
+		{CardID: cardID, VisibleToFriends: true},
+	})
 	if err == nil {
 		t.Fatal("expected error")
 	}
+	if committed {
+		t.Fatal("expected no commit after failed update")
+	}
 }
 
-func TestCardService_BulkDelete_Count(t *testing.T) {
-	call := 0
+func TestCardService_BulkDelete_ReturnsDeletedIDs(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	execs := 0
 	db := &fakeDB{
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			call++
-			if call == 1 {
-				return fakeCommandTag{rowsAffected: 5}, nil
+			execs++
+			return fakeCommandTag{rowsAffected: 5}, nil
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "DELETE FROM bingo_cards") {
+				t.Fatalf("unexpected query %s", sql)
 			}
-			return fakeCommandTag{rowsAffected: 2}, nil
+			return &fakeRows{rows: [][]any{{first}, {second}}}, nil
 		},
 	}
 
 	svc := NewCardService(db)
-	count, err := svc.BulkDelete(context.Background(), uuid.New(), []uuid.UUID{uuid.New()})
+	deleted, err := svc.BulkDelete(context.Background(), uuid.New(), []uuid.UUID{first, second, uuid.New()})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if count != 2 {
-		t.Fatalf("expected 2 deleted, got %d", count)
+	if len(deleted) != 2 || deleted[0] != first || deleted[1] != second {
+		t.Fatalf("expected the two owned cards deleted, got %v", deleted)
 	}
-	if call != 2 {
-		t.Fatalf("expected 2 exec calls, got %d", call)
+	if execs != 1 {
+		t.Fatalf("expected 1 exec call, got %d", execs)
 	}
 }
 
@@ -1584,14 +1854,12 @@ func TestCardService_BulkDelete_ItemsError(t *testing.T) {
 }
 
 func TestCardService_BulkDelete_CardsError(t *testing.T) {
-	call := 0
 	db := &fakeDB{
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
-			call++
-			if call == 1 {
-				return fakeCommandTag{rowsAffected: 1}, nil
-			}
-			return fakeCommandTag{}, errors.New("cards error")
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return nil, errors.New("cards error")
 		},
 	}
 
@@ -1749,8 +2017,8 @@ func TestCardService_SwapItems_BothItems(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1772,8 +2040,8 @@ func TestCardService_SwapItems_MoveTempError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1795,8 +2063,8 @@ func TestCardService_SwapItems_BeginError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1814,7 +2082,7 @@ func TestCardService_SwapItems_SingleItem(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1836,7 +2104,7 @@ func TestCardService_SwapItems_SecondItemOnly(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 1, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1858,8 +2126,8 @@ func TestCardService_SwapItems_CommitError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1896,6 +2164,8 @@ func TestCardService_AddItem_Explicit_Success(t *testing.T) {
 			nil,
 			nil,
 			time.Now(),
+			false,
+			nil,
 		)
 	}
 
@@ -1914,6 +2184,76 @@ func TestCardService_AddItem_Explicit_Success(t *testing.T) {
 	}
 }
 
+type fakeSuggestionUsageRecorder struct {
+	contents [][]string
+	err      error
+}
+
+func (f *fakeSuggestionUsageRecorder) RecordUsage(ctx context.Context, contents []string) error {
+	f.contents = append(f.contents, contents)
+	return f.err
+}
+
+func TestCardService_AddItem_RecordsSuggestionUsage(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 2, false, nil, false, [][]any{})
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if strings.Contains(sql, "FROM bingo_cards") {
+			return rowFromValues(cardRowValues(cardID, userID, 2, false, nil, false)...)
+		}
+		return rowFromValues(uuid.New(), cardID, 1, "Run a 5K", false, nil, nil, nil, time.Now(), false, nil)
+	}
+
+	// A failing recorder must not fail the add.
+	recorder := &fakeSuggestionUsageRecorder{err: errors.New("boom")}
+	svc := NewCardService(db)
+	svc.SetSuggestionUsageRecorder(recorder)
+	pos := 1
+	if _, err := svc.AddItem(context.Background(), userID, models.AddItemParams{
+		CardID:   cardID,
+		Position: &pos,
+		Content:  "Run a 5K",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorder.contents) != 1 || len(recorder.contents[0]) != 1 || recorder.contents[0][0] != "Run a 5K" {
+		t.Fatalf("expected usage recorded for new goal, got %v", recorder.contents)
+	}
+}
+
+func TestCardService_AddItem_PassesPrivacyFlag(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	db := newCardDB(cardID, userID, 2, false, nil, false, [][]any{})
+	var insertArgs []any
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if strings.Contains(sql, "FROM bingo_cards") {
+			return rowFromValues(cardRowValues(cardID, userID, 2, false, nil, false)...)
+		}
+		insertArgs = args
+		return rowFromValues(uuid.New(), cardID, 1, "Therapy", false, nil, nil, nil, time.Now(), true, nil)
+	}
+
+	svc := NewCardService(db)
+	pos := 1
+	item, err := svc.AddItem(context.Background(), userID, models.AddItemParams{
+		CardID:    cardID,
+		Position:  &pos,
+		Content:   "Therapy",
+		IsPrivate: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !item.IsPrivate {
+		t.Fatal("expected item to be private")
+	}
+	if len(insertArgs) != 5 || insertArgs[3] != true {
+		t.Fatalf("expected is_private insert arg, got %v", insertArgs)
+	}
+}
+
 func TestCardService_AddItem_Explicit_InsertConflict(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
@@ -1979,8 +2319,8 @@ func TestCardService_Shuffle_WithItems(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -1993,7 +2333,7 @@ func TestCardService_Shuffle_WithItems(t *testing.T) {
 	}
 
 	svc := NewCardService(db)
-	if _, err := svc.Shuffle(context.Background(), userID, cardID); err != nil {
+	if _, err := svc.Shuffle(context.Background(), userID, cardID, nil); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
@@ -2002,8 +2342,8 @@ func TestCardService_Shuffle_ClearError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2015,7 +2355,7 @@ func TestCardService_Shuffle_ClearError(t *testing.T) {
 	}
 
 	svc := NewCardService(db)
-	_, err := svc.Shuffle(context.Background(), userID, cardID)
+	_, err := svc.Shuffle(context.Background(), userID, cardID, nil)
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -2025,8 +2365,8 @@ func TestCardService_Shuffle_AssignError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	call := 0
@@ -2043,7 +2383,7 @@ func TestCardService_Shuffle_AssignError(t *testing.T) {
 	}
 
 	svc := NewCardService(db)
-	_, err := svc.Shuffle(context.Background(), userID, cardID)
+	_, err := svc.Shuffle(context.Background(), userID, cardID, nil)
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -2053,7 +2393,7 @@ func TestCardService_Shuffle_CommitError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2066,7 +2406,7 @@ func TestCardService_Shuffle_CommitError(t *testing.T) {
 	}
 
 	svc := NewCardService(db)
-	_, err := svc.Shuffle(context.Background(), userID, cardID)
+	_, err := svc.Shuffle(context.Background(), userID, cardID, nil)
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -2076,7 +2416,7 @@ func TestCardService_Shuffle_BeginError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2084,7 +2424,7 @@ func TestCardService_Shuffle_BeginError(t *testing.T) {
 	}
 
 	svc := NewCardService(db)
-	_, err := svc.Shuffle(context.Background(), userID, cardID)
+	_, err := svc.Shuffle(context.Background(), userID, cardID, nil)
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -2094,7 +2434,7 @@ func TestCardService_Finalize_MissingItems(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 
@@ -2121,10 +2461,10 @@ func TestCardService_Finalize_UpdateError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -2203,7 +2543,7 @@ func TestCardService_UpdateConfig_EnableFree_OddGrid(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 3, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2228,7 +2568,7 @@ func TestCardService_UpdateConfig_RelocateError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 4, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 4, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 3, false, nil, false, items)
 	db.BeginFunc = func(ctx context.Context) (Tx, error) {
@@ -2318,7 +2658,7 @@ func TestCardService_CheckForConflict_Found(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 
@@ -2432,7 +2772,7 @@ func TestCardService_GetArchive_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := &fakeDB{
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
@@ -2540,6 +2880,10 @@ func TestCardService_Import_Success(t *testing.T) {
 							false,
 							now,
 							now,
+							(*string)(nil),
+							testCardStart,
+							testCardEnd,
+							false,
 						)
 					}
 					return rowFromValues(
@@ -2551,6 +2895,8 @@ func TestCardService_Import_Success(t *testing.T) {
 						nil,
 						nil,
 						nil,
+						nil,
+						false,
 						now,
 					)
 				},
@@ -2654,6 +3000,10 @@ func TestCardService_Import_CreateItemError(t *testing.T) {
 							false,
 							now,
 							now,
+							(*string)(nil),
+							testCardStart,
+							testCardEnd,
+							false,
 						)
 					}
 					return fakeRow{scanFunc: func(dest ...any) error {
@@ -2705,6 +3055,10 @@ func TestCardService_Import_CommitError(t *testing.T) {
 							false,
 							now,
 							now,
+							(*string)(nil),
+							testCardStart,
+							testCardEnd,
+							false,
 						)
 					}
 					return rowFromValues(
@@ -2716,6 +3070,8 @@ func TestCardService_Import_CommitError(t *testing.T) {
 						nil,
 						nil,
 						nil,
+						nil,
+						false,
 						now,
 					)
 				},
@@ -2745,10 +3101,10 @@ func TestCardService_GetStats_AllCompleted(t *testing.T) {
 	cardID := uuid.New()
 	now := time.Now()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", true, &now, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", true, &now, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", true, &now, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", true, &now, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 2, "C", true, &now, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 3, "D", true, &now, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, true, items)
 
@@ -2769,6 +3125,12 @@ func TestCardService_GetStats_AllCompleted(t *testing.T) {
 	if stats.FirstCompletion == nil || stats.LastCompletion == nil {
 		t.Fatal("expected completion timestamps")
 	}
+	if stats.StartDate != testCardStart || stats.EndDate != testCardEnd {
+		t.Fatalf("expected card period in stats, got %v - %v", stats.StartDate, stats.EndDate)
+	}
+	if !stats.IsOverdue || stats.DaysRemaining != 0 {
+		t.Fatalf("expected 2024 card to be overdue, got overdue=%v days=%d", stats.IsOverdue, stats.DaysRemaining)
+	}
 }
 
 func TestCardService_GetStats_NotOwner(t *testing.T) {
@@ -2783,6 +3145,50 @@ func TestCardService_GetStats_NotOwner(t *testing.T) {
 	}
 }
 
+func TestCardService_GetRecommendations_ReturnsLineReasons(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	freePos := 4
+	now := time.Now()
+	items := [][]any{
+		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 1, "B", true, &now, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 5, "E", false, nil, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 6, "F", false, nil, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 7, "G", false, nil, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 8, "H", true, &now, nil, nil, now, false, nil},
+	}
+	db := newCardDB(cardID, userID, 3, true, &freePos, true, items)
+
+	svc := NewCardService(db)
+	recs, err := svc.GetRecommendations(context.Background(), userID, cardID, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 recommendations, got %+v", recs)
+	}
+	if recs[0].Item.Position != 2 || recs[0].Reason != "completes row 1" || len(recs[0].Lines) != 1 || recs[0].Lines[0] != "row 1" {
+		t.Fatalf("unexpected first recommendation: %+v", recs[0])
+	}
+	if recs[1].Item.Position != 7 || recs[1].Reason != "completes column 2" {
+		t.Fatalf("unexpected second recommendation: %+v", recs[1])
+	}
+}
+
+func TestCardService_GetRecommendations_NotOwner(t *testing.T) {
+	cardID := uuid.New()
+	db := newCardDB(cardID, uuid.New(), 2, false, nil, true, [][]any{})
+
+	svc := NewCardService(db)
+	_, err := svc.GetRecommendations(context.Background(), uuid.New(), cardID, 3)
+	if !errors.Is(err, ErrNotCardOwner) {
+		t.Fatalf("expected ErrNotCardOwner, got %v", err)
+	}
+}
+
 func TestCardService_Import_InvalidPosition(t *testing.T) {
 	svc := &CardService{}
 	_, err := svc.Import(context.Background(), models.ImportCardParams{
@@ -2844,6 +3250,8 @@ func TestCardService_AddItem_Random_Success(t *testing.T) {
 						nil,
 						nil,
 						time.Now(),
+						false,
+						nil,
 					)
 				},
 				QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
@@ -2867,10 +3275,10 @@ func TestCardService_Finalize_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -2891,10 +3299,10 @@ func TestCardService_Finalize_VisibleOverride(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	now := time.Now()
 	db := &fakeDB{
@@ -2915,6 +3323,10 @@ func TestCardService_Finalize_VisibleOverride(t *testing.T) {
 				false,
 				now,
 				now,
+				(*string)(nil),
+				testCardStart,
+				testCardEnd,
+				false,
 			)
 		},
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
@@ -2974,7 +3386,7 @@ func TestCardService_UpdateItem_ContentSuccess(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -2994,11 +3406,123 @@ func TestCardService_UpdateItem_ContentSuccess(t *testing.T) {
 	}
 }
 
+func TestCardService_UpdateItem_PrivacyAllowedWhenFinalized(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	items := [][]any{
+		{uuid.New(), cardID, 0, "Therapy", false, nil, nil, nil, time.Now(), false, nil},
+	}
+	db := newCardDB(cardID, userID, 2, false, nil, true, items)
+	var execArgs []any
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if !strings.Contains(sql, "is_private") {
+			t.Fatalf("unexpected exec: %q", sql)
+		}
+		execArgs = args
+		return fakeCommandTag{rowsAffected: 1}, nil
+	}
+
+	svc := NewCardService(db)
+	private := true
+	item, err := svc.UpdateItem(context.Background(), userID, cardID, 0, models.UpdateItemParams{
+		IsPrivate: &private,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !item.IsPrivate || item.Content != "Therapy" {
+		t.Fatalf("expected private item with original content, got %+v", item)
+	}
+	if len(execArgs) != 2 || execArgs[0] != true {
+		t.Fatalf("unexpected exec args: %v", execArgs)
+	}
+
+	content := "New"
+	_, err = svc.UpdateItem(context.Background(), userID, cardID, 0, models.UpdateItemParams{
+		Content:   &content,
+		IsPrivate: &private,
+	})
+	if !errors.Is(err, ErrCardFinalized) {
+		t.Fatalf("expected ErrCardFinalized for content change, got %v", err)
+	}
+}
+
+func TestCardService_UpdateItem_DifficultySetAndClearedWhenFinalized(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	items := [][]any{
+		{uuid.New(), cardID, 0, "Marathon", false, nil, nil, nil, time.Now(), false, nil},
+	}
+	db := newCardDB(cardID, userID, 2, false, nil, true, items)
+	var execArgs [][]any
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if !strings.Contains(sql, "SET difficulty") {
+			t.Fatalf("unexpected exec: %q", sql)
+		}
+		execArgs = append(execArgs, args)
+		return fakeCommandTag{rowsAffected: 1}, nil
+	}
+
+	svc := NewCardService(db)
+	hard := models.DifficultyHard
+	item, err := svc.UpdateItem(context.Background(), userID, cardID, 0, models.UpdateItemParams{Difficulty: &hard})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Difficulty == nil || *item.Difficulty != models.DifficultyHard {
+		t.Fatalf("expected hard difficulty, got %v", item.Difficulty)
+	}
+
+	clear := ""
+	item, err = svc.UpdateItem(context.Background(), userID, cardID, 0, models.UpdateItemParams{Difficulty: &clear})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Difficulty != nil {
+		t.Fatalf("expected difficulty cleared, got %v", *item.Difficulty)
+	}
+	if len(execArgs) != 2 || execArgs[1][0] != (*string)(nil) {
+		t.Fatalf("expected NULL on clear, got %v", execArgs)
+	}
+}
+
+func TestCardService_GetStats_WeightsByDifficulty(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	now := time.Now()
+	hard, easy := models.DifficultyHard, models.DifficultyEasy
+	items := [][]any{
+		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, now, false, &hard},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, now, false, &easy},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, now, false, &easy},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, now, false, &easy},
+	}
+	db := newCardDB(cardID, userID, 2, false, nil, true, items)
+
+	svc := NewCardService(db)
+	stats, err := svc.GetStats(context.Background(), userID, cardID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.CompletionRate != 25 || stats.WeightedCompletionRate != 50 {
+		t.Fatalf("expected 25%% plain and 50%% weighted, got %v / %v", stats.CompletionRate, stats.WeightedCompletionRate)
+	}
+
+	svc.SetDifficultyWeights(models.DifficultyWeights{Hard: 1, Easy: -1})
+	stats, err = svc.GetStats(context.Background(), userID, cardID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.WeightedCompletionRate != 25 {
+		t.Fatalf("expected custom weights to apply, got %v", stats.WeightedCompletionRate)
+	}
+}
+
 func TestCardService_UpdateItem_ContentError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3019,7 +3543,7 @@ func TestCardService_UpdateItem_PositionError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "Old", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3040,8 +3564,8 @@ func TestCardService_UpdateItem_PositionOccupied(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 
@@ -3059,7 +3583,7 @@ func TestCardService_UpdateItem_PositionInvalid(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 
@@ -3077,7 +3601,7 @@ func TestCardService_UpdateItem_PositionSuccess(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3101,10 +3625,10 @@ func TestCardService_CompleteItem_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, true, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3121,15 +3645,79 @@ func TestCardService_CompleteItem_Success(t *testing.T) {
 	}
 }
 
+func TestCardService_CompleteItemByContent(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	now := time.Now()
+	items := [][]any{
+		{uuid.New(), cardID, 0, "Go for a run", false, nil, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 1, "Go for a run  with Sam", false, nil, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 2, "Read a book", true, &now, nil, nil, now, false, nil},
+		{uuid.New(), cardID, 3, "Read a poem", false, nil, nil, nil, now, false, nil},
+	}
+
+	cases := []struct {
+		name       string
+		content    string
+		exact      bool
+		wantPos    int
+		wantErr    error
+		candidates int
+		wantWrite  bool
+	}{
+		{name: "equal match beats containing match", content: "  GO FOR A RUN ", wantPos: 0, wantWrite: true},
+		{name: "partial match", content: "with sam", wantPos: 1, wantWrite: true},
+		{name: "exact rejects partial", content: "with sam", exact: true, wantErr: ErrItemNotFound},
+		{name: "ambiguous partial", content: "read a", wantErr: ErrItemAmbiguous, candidates: 2},
+		{name: "no match", content: "swim", wantErr: ErrItemNotFound},
+		{name: "already complete is idempotent", content: "read a book", wantPos: 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newCardDB(cardID, userID, 2, false, nil, true, items)
+			wrote := false
+			db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+				wrote = true
+				return fakeCommandTag{rowsAffected: 1}, nil
+			}
+
+			svc := NewCardService(db)
+			item, err := svc.CompleteItemByContent(context.Background(), userID, cardID, tc.content, tc.exact, models.CompleteItemParams{})
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected %v, got %v", tc.wantErr, err)
+				}
+				var ambiguous *ItemAmbiguousError
+				if tc.candidates > 0 && (!errors.As(err, &ambiguous) || len(ambiguous.Candidates) != tc.candidates) {
+					t.Fatalf("expected %d candidates, got %v", tc.candidates, err)
+				}
+				if wrote {
+					t.Fatal("expected no write")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if item.Position != tc.wantPos || !item.IsCompleted {
+				t.Fatalf("expected completed item at %d, got %+v", tc.wantPos, item)
+			}
+			if wrote != tc.wantWrite {
+				t.Fatalf("expected write=%v, got %v", tc.wantWrite, wrote)
+			}
+		})
+	}
+}
+
 func TestCardService_UncompleteItem_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	now := time.Now()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, time.Now()},
-		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", true, &now, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 2, "C", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 3, "D", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, true, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3150,7 +3738,7 @@ func TestCardService_UpdateItemNotes_Success(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3172,7 +3760,7 @@ func TestCardService_UpdateItemNotes_DBError(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
 	items := [][]any{
-		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, false, nil, false, items)
 	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3191,8 +3779,8 @@ func TestCardService_SwapItems_FreeSpaceMoves(t *testing.T) {
 	cardID := uuid.New()
 	free := 0
 	items := [][]any{
-		{uuid.New(), cardID, 1, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), cardID, 2, "B", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 1, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 2, "B", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 2, true, &free, false, items)
 	var movedFree bool
@@ -3229,7 +3817,7 @@ func TestCardService_UpdateConfig_CenterOccupiedRelocates(t *testing.T) {
 	cardID := uuid.New()
 	free := (*int)(nil)
 	items := [][]any{
-		{uuid.New(), cardID, 4, "Center", false, nil, nil, nil, time.Now()},
+		{uuid.New(), cardID, 4, "Center", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	db := newCardDB(cardID, userID, 3, false, free, false, items)
 	var relocated bool
@@ -3288,6 +3876,58 @@ func TestCardService_UpdateConfig_DisableFree(t *testing.T) {
 	}
 }
 
+func TestCardService_UpdateConfig_FreeSpaceText(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	free := 4
+	db := newCardDB(cardID, userID, 3, true, &free, false, [][]any{})
+	var gotText any
+	db.BeginFunc = func(ctx context.Context) (Tx, error) {
+		return &fakeTx{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+				gotText = args[3]
+				return fakeCommandTag{rowsAffected: 1}, nil
+			},
+			CommitFunc: func(ctx context.Context) error { return nil },
+		}, nil
+	}
+
+	svc := NewCardService(db)
+	text := "  FREE - you survived 2024 "
+	card, err := svc.UpdateConfig(context.Background(), userID, cardID, models.UpdateCardConfigParams{
+		FreeSpaceText: &text,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ptr, ok := gotText.(*string); !ok || ptr == nil || *ptr != "FREE - you survived 2024" {
+		t.Fatalf("expected trimmed free space text, got %#v", gotText)
+	}
+	if card.FreeSpace == nil || card.FreeSpace.Position != free || !card.FreeSpace.IsCompleted {
+		t.Fatalf("expected free space pseudo-item at %d, got %+v", free, card.FreeSpace)
+	}
+	if len(card.Items) != 0 {
+		t.Fatalf("free space must not be added to items, got %d", len(card.Items))
+	}
+
+	empty := " "
+	if _, err := svc.UpdateConfig(context.Background(), userID, cardID, models.UpdateCardConfigParams{
+		FreeSpaceText: &empty,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ptr, ok := gotText.(*string); !ok || ptr != nil {
+		t.Fatalf("expected empty text to clear the label, got %#v", gotText)
+	}
+
+	tooLong := strings.Repeat("x", models.MaxFreeSpaceTextLength+1)
+	if _, err := svc.UpdateConfig(context.Background(), userID, cardID, models.UpdateCardConfigParams{
+		FreeSpaceText: &tooLong,
+	}); !errors.Is(err, ErrInvalidFreeSpaceText) {
+		t.Fatalf("expected ErrInvalidFreeSpaceText, got %v", err)
+	}
+}
+
 func TestCardService_Delete_NotFoundAfterDelete(t *testing.T) {
 	userID := uuid.New()
 	cardID := uuid.New()
@@ -3366,16 +4006,16 @@ func TestCardService_Clone_TruncatesItems(t *testing.T) {
 	free := 4
 	fallbackTitle := "2024 Bingo Card (Copy)"
 	sourceItems := [][]any{
-		{uuid.New(), sourceCardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), sourceCardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), sourceCardID, 2, "C", false, nil, nil, nil, time.Now()},
-		{uuid.New(), sourceCardID, 3, "D", false, nil, nil, nil, time.Now()},
-		{uuid.New(), sourceCardID, 5, "E", false, nil, nil, nil, time.Now()},
+		{uuid.New(), sourceCardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), sourceCardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), sourceCardID, 2, "C", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), sourceCardID, 3, "D", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), sourceCardID, 5, "E", false, nil, nil, nil, time.Now(), false, nil},
 	}
 	newItems := [][]any{
-		{uuid.New(), newCardID, 0, "A", false, nil, nil, nil, time.Now()},
-		{uuid.New(), newCardID, 1, "B", false, nil, nil, nil, time.Now()},
-		{uuid.New(), newCardID, 2, "C", false, nil, nil, nil, time.Now()},
+		{uuid.New(), newCardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), newCardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), newCardID, 2, "C", false, nil, nil, nil, time.Now(), false, nil},
 	}
 
 	db := &fakeDB{
@@ -3423,6 +4063,10 @@ func TestCardService_Clone_TruncatesItems(t *testing.T) {
 						false,
 						time.Now(),
 						time.Now(),
+						(*string)(nil),
+						testCardStart,
+						testCardEnd,
+						false,
 					)
 				},
 				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -3738,3 +4382,227 @@ func TestValidItemPosition_Default5x5WithFree(t *testing.T) {
 		}
 	}
 }
+
+// newMergeImportDB serves MergeImport's transaction: the locked card, its
+// current (position, content) rows, and inserts recorded into inserted.
+func newMergeImportDB(cardID, userID uuid.UUID, gridSize int, hasFree bool, freePos *int, finalized bool, current [][]any, inserted *[]int, committed *bool) *fakeDB {
+	db := newCardDB(cardID, userID, gridSize, hasFree, freePos, finalized, nil)
+	db.BeginFunc = func(ctx context.Context) (Tx, error) {
+		return &fakeTx{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				if strings.Contains(sql, "FOR UPDATE") {
+					return rowFromValues(lockCardRowValues(cardID, userID, gridSize, hasFree, freePos, finalized)...)
+				}
+				if strings.Contains(sql, "INSERT INTO bingo_items") {
+					pos := args[1].(int)
+					*inserted = append(*inserted, pos)
+					return rowFromValues(uuid.New(), cardID, pos, args[2], false, nil, nil, nil, time.Now(), false, nil)
+				}
+				return fakeRow{scanFunc: func(dest ...any) error {
+					return errors.New("unexpected query")
+				}}
+			},
+			QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+				return &fakeRows{rows: current}, nil
+			},
+			CommitFunc: func(ctx context.Context) error {
+				*committed = true
+				return nil
+			},
+		}, nil
+	}
+	return db
+}
+
+func TestCardService_MergeImport_FillsOpenSquaresAndSkipsDuplicates(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	var inserted []int
+	committed := false
+	db := newMergeImportDB(cardID, userID, 2, false, nil, false, [][]any{
+		{0, "Run a 5K"},
+		{2, "Read 12 books"},
+	}, &inserted, &committed)
+
+	svc := NewCardService(db)
+	result, err := svc.MergeImport(context.Background(), models.MergeImportParams{
+		UserID: userID,
+		CardID: cardID,
+		Items: []models.ImportItem{
+			{Content: "  run a  5k "},
+			{Content: "Learn to juggle"},
+			{Content: "learn to juggle"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !committed {
+		t.Fatal("expected transaction to commit")
+	}
+	if len(inserted) != 1 || inserted[0] != 1 {
+		t.Fatalf("expected one insert at position 1, got %v", inserted)
+	}
+	if len(result.Added) != 1 || result.Added[0].Content != "Learn to juggle" {
+		t.Fatalf("unexpected added items: %+v", result.Added)
+	}
+	if len(result.Duplicates) != 2 {
+		t.Fatalf("expected 2 duplicates, got %v", result.Duplicates)
+	}
+	if result.Card == nil {
+		t.Fatal("expected merged card in result")
+	}
+}
+
+func TestCardService_MergeImport_CapacityShortfallIsAtomic(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	freePos := 3
+	var inserted []int
+	committed := false
+	db := newMergeImportDB(cardID, userID, 2, true, &freePos, false, [][]any{
+		{0, "Existing"},
+	}, &inserted, &committed)
+
+	svc := NewCardService(db)
+	_, err := svc.MergeImport(context.Background(), models.MergeImportParams{
+		UserID: userID,
+		CardID: cardID,
+		Items: []models.ImportItem{
+			{Content: "A"},
+			{Content: "B"},
+			{Content: "C"},
+		},
+	})
+	if !errors.Is(err, ErrNotEnoughOpenSquares) {
+		t.Fatalf("expected ErrNotEnoughOpenSquares, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "3 new items but only 2 open (1 short)") {
+		t.Fatalf("expected shortfall in error, got %q", err.Error())
+	}
+	if len(inserted) != 0 || committed {
+		t.Fatalf("expected no writes, got inserts %v committed %v", inserted, committed)
+	}
+}
+
+func TestCardService_MergeImport_RejectsFinalizedCard(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	var inserted []int
+	committed := false
+	db := newMergeImportDB(cardID, userID, 2, false, nil, true, nil, &inserted, &committed)
+
+	svc := NewCardService(db)
+	_, err := svc.MergeImport(context.Background(), models.MergeImportParams{
+		UserID: userID,
+		CardID: cardID,
+		Items:  []models.ImportItem{{Content: "A"}},
+	})
+	if !errors.Is(err, ErrCardFinalized) {
+		t.Fatalf("expected ErrCardFinalized, got %v", err)
+	}
+}
+
+func TestCardService_MergeImport_NotOwner(t *testing.T) {
+	cardID := uuid.New()
+	var inserted []int
+	committed := false
+	db := newMergeImportDB(cardID, uuid.New(), 2, false, nil, false, nil, &inserted, &committed)
+
+	svc := NewCardService(db)
+	_, err := svc.MergeImport(context.Background(), models.MergeImportParams{
+		UserID: uuid.New(),
+		CardID: cardID,
+		Items:  []models.ImportItem{{Content: "A"}},
+	})
+	if !errors.Is(err, ErrNotCardOwner) {
+		t.Fatalf("expected ErrNotCardOwner, got %v", err)
+	}
+}
+
+func TestCardService_MergeImport_DryRunDoesNotWrite(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	var inserted []int
+	committed := false
+	db := newMergeImportDB(cardID, userID, 2, false, nil, false, [][]any{
+		{1, "Existing"},
+	}, &inserted, &committed)
+
+	svc := NewCardService(db)
+	result, err := svc.MergeImport(context.Background(), models.MergeImportParams{
+		UserID: userID,
+		CardID: cardID,
+		Items:  []models.ImportItem{{Content: "A"}, {Content: "B"}},
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inserted) != 0 || committed {
+		t.Fatalf("expected no writes on dry run, got inserts %v committed %v", inserted, committed)
+	}
+	if !result.DryRun || len(result.Added) != 2 || result.Added[0].Position != 0 || result.Added[1].Position != 2 {
+		t.Fatalf("unexpected dry run result: %+v", result)
+	}
+	if result.Card != nil {
+		t.Fatal("expected no card on dry run")
+	}
+}
+
+func TestCardService_GetMemories_QueriesYearWindows(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	title := "2025 goals"
+	completedAt := time.Date(2025, time.March, 12, 18, 0, 0, 0, time.UTC)
+	loc := time.FixedZone("UTC-5", -5*3600)
+	// 02:00 UTC on the 11th is still the 10th in loc.
+	now := time.Date(2026, time.March, 11, 2, 0, 0, 0, time.UTC)
+
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "WITH ORDINALITY") || !strings.Contains(sql, "c.user_id = $1") {
+				t.Fatalf("unexpected memories sql: %q", sql)
+			}
+			gotArgs = args
+			return &fakeRows{rows: [][]any{
+				{uuid.New(), cardID, &title, 2025, true, "Run a 5K", completedAt, int64(1)},
+			}}, nil
+		},
+	}
+
+	svc := NewCardService(db)
+	memories, err := svc.GetMemories(context.Background(), userID, now, loc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	starts := gotArgs[1].([]time.Time)
+	ends := gotArgs[2].([]time.Time)
+	if len(starts) != memoriesMaxYears || len(ends) != memoriesMaxYears {
+		t.Fatalf("expected %d windows, got %d/%d", memoriesMaxYears, len(starts), len(ends))
+	}
+	if want := time.Date(2025, time.March, 3, 0, 0, 0, 0, loc); !starts[0].Equal(want) {
+		t.Fatalf("expected last year's window to start %v, got %v", want, starts[0])
+	}
+	if want := time.Date(2025, time.March, 18, 0, 0, 0, 0, loc); !ends[0].Equal(want) {
+		t.Fatalf("expected last year's window to end %v, got %v", want, ends[0])
+	}
+	if want := time.Date(2024, time.March, 3, 0, 0, 0, 0, loc); !starts[1].Equal(want) {
+		t.Fatalf("expected two years ago window to start %v, got %v", want, starts[1])
+	}
+	if len(memories) != 1 || memories[0].YearsAgo != 1 || memories[0].Content != "Run a 5K" || !memories[0].IsArchived {
+		t.Fatalf("unexpected memories: %+v", memories)
+	}
+}
+
+func TestCardService_GetMemories_EmptyHistory(t *testing.T) {
+	svc := NewCardService(&fakeDB{})
+	memories, err := svc.GetMemories(context.Background(), uuid.New(), time.Now(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if memories == nil || len(memories) != 0 {
+		t.Fatalf("expected empty non-nil slice, got %#v", memories)
+	}
+}
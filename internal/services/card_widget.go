@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// MaxWidgetTokensPerCard caps live widget tokens on one card, roughly one per
+// device.
+const MaxWidgetTokensPerCard = 10
+
+var (
+	ErrWidgetTokenNotFound = errors.New("widget token not found")
+	ErrWidgetTokenLimit    = errors.New("too many widget tokens for this card")
+)
+
+// CreateWidgetToken mints a widget image token for the owner's card. Tokens
+// don't expire; they last until revoked or the card is deleted.
+func (s *CardService) CreateWidgetToken(ctx context.Context, userID, cardID uuid.UUID) (*models.CardWidgetToken, error) {
+	cardOwnerID, _, err := s.loadCardOwner(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanEditCard(authz.For(userID, cardOwnerID)) {
+		return nil, ErrNotCardOwner
+	}
+
+	var count int
+	if err := s.db.QueryRow(ctx,
+		"SELECT COUNT(*) FROM card_widget_tokens WHERE card_id = $1",
+		cardID,
+	).Scan(&count); err != nil {
+		return nil, fmt.Errorf("counting widget tokens: %w", err)
+	}
+	if count >= MaxWidgetTokensPerCard {
+		return nil, ErrWidgetTokenLimit
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, err
+	}
+
+	widget := &models.CardWidgetToken{}
+	err = s.db.QueryRow(ctx, `
+		INSERT INTO card_widget_tokens (card_id, token)
+		VALUES ($1, $2)
+		RETURNING id, card_id, token, created_at, last_accessed_at, access_count
+	`, cardID, token).Scan(
+		&widget.ID,
+		&widget.CardID,
+		&widget.Token,
+		&widget.CreatedAt,
+		&widget.LastAccessedAt,
+		&widget.AccessCount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating widget token: %w", err)
+	}
+	return widget, nil
+}
+
+// ListWidgetTokens returns the owner's widget tokens for a card, newest first.
+func (s *CardService) ListWidgetTokens(ctx context.Context, userID, cardID uuid.UUID) ([]models.CardWidgetToken, error) {
+	cardOwnerID, _, err := s.loadCardOwner(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanEditCard(authz.For(userID, cardOwnerID)) {
+		return nil, ErrNotCardOwner
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT id, card_id, token, created_at, last_accessed_at, access_count
+		FROM card_widget_tokens
+		WHERE card_id = $1
+		ORDER BY created_at DESC
+	`, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("listing widget tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]models.CardWidgetToken, 0)
+	for rows.Next() {
+		var widget models.CardWidgetToken
+		if err := rows.Scan(
+			&widget.ID,
+			&widget.CardID,
+			&widget.Token,
+			&widget.CreatedAt,
+			&widget.LastAccessedAt,
+			&widget.AccessCount,
+		); err != nil {
+			return nil, fmt.Errorf("scanning widget token: %w", err)
+		}
+		tokens = append(tokens, widget)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating widget tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeWidgetToken deletes one widget token from the owner's card.
+func (s *CardService) RevokeWidgetToken(ctx context.Context, userID, cardID, tokenID uuid.UUID) error {
+	cardOwnerID, _, err := s.loadCardOwner(ctx, cardID)
+	if err != nil {
+		return err
+	}
+	if !authz.CanEditCard(authz.For(userID, cardOwnerID)) {
+		return ErrNotCardOwner
+	}
+
+	result, err := s.db.Exec(ctx,
+		"DELETE FROM card_widget_tokens WHERE id = $1 AND card_id = $2",
+		tokenID, cardID,
+	)
+	if err != nil {
+		return fmt.Errorf("revoking widget token: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWidgetTokenNotFound
+	}
+	return nil
+}
+
+// RenderWidgetByToken renders the progress strip for the card a widget token
+// points at. Goal text is never drawn, so private goals need no redaction.
+func (s *CardService) RenderWidgetByToken(ctx context.Context, token string) ([]byte, error) {
+	var cardID uuid.UUID
+	var ownerMinimized bool
+	err := s.db.QueryRow(ctx, `
+		SELECT w.card_id, u.data_minimization
+		FROM card_widget_tokens w
+		JOIN bingo_cards c ON c.id = w.card_id
+		JOIN users u ON u.id = c.user_id AND u.deleted_at IS NULL
+		WHERE w.token = $1
+	`, token).Scan(&cardID, &ownerMinimized)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrWidgetTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading widget token: %w", err)
+	}
+
+	card, err := s.GetByID(ctx, cardID)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, ErrWidgetTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pngBytes, err := RenderWidgetPNG(*card, card.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like shares, no access analytics for owners with data minimization on.
+	if !ownerMinimized {
+		if _, err := s.db.Exec(ctx,
+			`UPDATE card_widget_tokens
+			 SET last_accessed_at = NOW(), access_count = access_count + 1
+			 WHERE token = $1`,
+			token,
+		); err != nil {
+			logging.Warn("Failed to record widget access", map[string]interface{}{"error": err.Error()})
+		}
+	}
+
+	return pngBytes, nil
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestCardService_CreateWidgetToken(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+
+	t.Run("not owner", func(t *testing.T) {
+		db := &fakeDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				if !strings.Contains(sql, "FROM bingo_cards") {
+					t.Fatalf("unexpected query after ownership check: %s", sql)
+				}
+				return rowFromValues(uuid.New(), true)
+			},
+		}
+		_, err := NewCardService(db).CreateWidgetToken(context.Background(), userID, cardID)
+		if !errors.Is(err, ErrNotCardOwner) {
+			t.Fatalf("expected ErrNotCardOwner, got %v", err)
+		}
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		db := &fakeDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				switch {
+				case strings.Contains(sql, "FROM bingo_cards"):
+					return rowFromValues(userID, true)
+				case strings.Contains(sql, "COUNT(*) FROM card_widget_tokens"):
+					return rowFromValues(MaxWidgetTokensPerCard)
+				}
+				t.Fatalf("unexpected query: %s", sql)
+				return nil
+			},
+		}
+		_, err := NewCardService(db).CreateWidgetToken(context.Background(), userID, cardID)
+		if !errors.Is(err, ErrWidgetTokenLimit) {
+			t.Fatalf("expected ErrWidgetTokenLimit, got %v", err)
+		}
+	})
+
+	t.Run("works on draft cards", func(t *testing.T) {
+		var gotToken string
+		db := &fakeDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				switch {
+				case strings.Contains(sql, "FROM bingo_cards"):
+					return rowFromValues(userID, false)
+				case strings.Contains(sql, "COUNT(*)"):
+					return rowFromValues(0)
+				case strings.Contains(sql, "INSERT INTO card_widget_tokens"):
+					gotToken = args[1].(string)
+					return rowFromValues(uuid.New(), cardID, gotToken, time.Now(), (*time.Time)(nil), 0)
+				}
+				t.Fatalf("unexpected query: %s", sql)
+				return nil
+			},
+		}
+		widget, err := NewCardService(db).CreateWidgetToken(context.Background(), userID, cardID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(gotToken) != 64 || widget.Token != gotToken {
+			t.Fatalf("expected a 64-char token, got %q", widget.Token)
+		}
+	})
+}
+
+func TestCardService_RevokeWidgetToken_ScopedToCard(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	tokenID := uuid.New()
+	var gotArgs []any
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(userID, true)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			gotArgs = args
+			return fakeCommandTag{rowsAffected: 0}, nil
+		},
+	}
+
+	err := NewCardService(db).RevokeWidgetToken(context.Background(), userID, cardID, tokenID)
+	if !errors.Is(err, ErrWidgetTokenNotFound) {
+		t.Fatalf("expected ErrWidgetTokenNotFound, got %v", err)
+	}
+	if len(gotArgs) != 2 || gotArgs[0] != tokenID || gotArgs[1] != cardID {
+		t.Fatalf("expected delete scoped to token and card, got %v", gotArgs)
+	}
+}
+
+func TestCardService_RenderWidgetByToken(t *testing.T) {
+	userID := uuid.New()
+	cardID := uuid.New()
+	now := time.Now()
+	items := [][]any{
+		{uuid.New(), cardID, 0, "Private goal text", true, &now, nil, nil, now, true, nil},
+	}
+
+	t.Run("unknown token", func(t *testing.T) {
+		db := &fakeDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+			},
+		}
+		_, err := NewCardService(db).RenderWidgetByToken(context.Background(), "missing")
+		if !errors.Is(err, ErrWidgetTokenNotFound) {
+			t.Fatalf("expected ErrWidgetTokenNotFound, got %v", err)
+		}
+	})
+
+	for _, minimized := range []bool{false, true} {
+		db := newCardDB(cardID, userID, 3, false, nil, true, items)
+		cardRow := db.QueryRowFunc
+		db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM card_widget_tokens") {
+				return rowFromValues(cardID, minimized)
+			}
+			return cardRow(ctx, sql, args...)
+		}
+		touched := false
+		db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE card_widget_tokens") {
+				touched = true
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		}
+
+		data, err := NewCardService(db).RenderWidgetByToken(context.Background(), "token")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+			t.Fatalf("decode png: %v", err)
+		}
+		if touched == minimized {
+			t.Fatalf("expected access recorded=%v for data_minimization=%v", !minimized, minimized)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+)
+
+// cleanupLimits bounds a retention cleanup run so a large backlog of expired
+// rows can't hold locks or run for minutes. Rows left over when the budget
+// runs out are deleted by the next run.
+type cleanupLimits struct {
+	batchSize int
+	budget    time.Duration
+}
+
+var defaultCleanupLimits = cleanupLimits{batchSize: 5000, budget: 2 * time.Minute}
+
+// cleanupProgressEvery is how many batches pass between progress logs.
+const cleanupProgressEvery = 10
+
+// batchDelete deletes the rows of table matching where, limits.batchSize at
+// a time, until none are left or the deadline passes. It returns the number
+// of rows deleted. Stopping at the deadline is not an error.
+func batchDelete(ctx context.Context, db DBConn, table, where string, limits cleanupLimits, deadline time.Time) (int, error) {
+	query := fmt.Sprintf(
+		`DELETE FROM %[1]s WHERE ctid = ANY(ARRAY(SELECT ctid FROM %[1]s WHERE %[2]s LIMIT $1))`,
+		table, where,
+	)
+
+	deleted, batches := 0, 0
+	for {
+		tag, err := db.Exec(ctx, query, limits.batchSize)
+		if err != nil {
+			return deleted, fmt.Errorf("cleanup %s: %w", table, err)
+		}
+		batches++
+		deleted += int(tag.RowsAffected())
+		if tag.RowsAffected() < int64(limits.batchSize) {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			logging.Warn("Cleanup stopped at its time budget", map[string]interface{}{
+				"table": table, "deleted": deleted, "batches": batches,
+			})
+			return deleted, nil
+		}
+		if batches%cleanupProgressEvery == 0 {
+			logging.Info("Cleanup in progress", map[string]interface{}{
+				"table": table, "deleted": deleted, "batches": batches,
+			})
+		}
+	}
+
+	if batches > 1 {
+		logging.Info("Cleanup finished", map[string]interface{}{
+			"table": table, "deleted": deleted, "batches": batches,
+		})
+	}
+	return deleted, nil
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// batchCountingDB answers cleanup DELETEs from a per-table backlog of rows,
+// removing at most the batch limit per call, and counts the calls.
+type batchCountingDB struct {
+	fakeDB
+	backlog map[string]int
+	batches map[string]int
+	failOn  string
+}
+
+func newBatchCountingDB(backlog map[string]int) *batchCountingDB {
+	db := &batchCountingDB{backlog: backlog, batches: map[string]int{}}
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		table := strings.Fields(sql)[2]
+		db.batches[table]++
+		if table == db.failOn {
+			return nil, errors.New("db down")
+		}
+		deleted := min(db.backlog[table], args[0].(int))
+		db.backlog[table] -= deleted
+		return fakeCommandTag{rowsAffected: int64(deleted)}, nil
+	}
+	return db
+}
+
+func TestNotificationService_CleanupOld_DeletesInBatches(t *testing.T) {
+	db := newBatchCountingDB(map[string]int{"notifications": 25})
+	svc := NewNotificationService(db, nil, "http://example.com")
+	svc.cleanup = cleanupLimits{batchSize: 10, budget: time.Minute}
+
+	deleted, err := svc.CleanupOld(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 25 {
+		t.Fatalf("expected 25 deleted, got %d", deleted)
+	}
+	if db.batches["notifications"] != 3 {
+		t.Fatalf("expected 3 batches, got %d", db.batches["notifications"])
+	}
+}
+
+func TestNotificationService_CleanupOld_ExactMultipleNeedsEmptyBatch(t *testing.T) {
+	db := newBatchCountingDB(map[string]int{"notifications": 20})
+	svc := NewNotificationService(db, nil, "http://example.com")
+	svc.cleanup = cleanupLimits{batchSize: 10, budget: time.Minute}
+
+	if _, err := svc.CleanupOld(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.batches["notifications"] != 3 || db.backlog["notifications"] != 0 {
+		t.Fatalf("expected 3 batches and nothing left, got %d batches and %d left", db.batches["notifications"], db.backlog["notifications"])
+	}
+}
+
+func TestNotificationService_CleanupOld_StopsAtTimeBudget(t *testing.T) {
+	db := newBatchCountingDB(map[string]int{"notifications": 100})
+	svc := NewNotificationService(db, nil, "http://example.com")
+	svc.cleanup = cleanupLimits{batchSize: 10, budget: 0}
+
+	deleted, err := svc.CleanupOld(context.Background())
+	if err != nil {
+		t.Fatalf("expected the budget to end the run without error, got %v", err)
+	}
+	if deleted != 10 || db.batches["notifications"] != 1 {
+		t.Fatalf("expected one batch of 10, got %d deleted in %d batches", deleted, db.batches["notifications"])
+	}
+}
+
+func TestNotificationService_CleanupOld_Error(t *testing.T) {
+	db := newBatchCountingDB(map[string]int{"notifications": 100})
+	db.failOn = "notifications"
+	svc := NewNotificationService(db, nil, "http://example.com")
+
+	if _, err := svc.CleanupOld(context.Background()); err == nil || !strings.Contains(err.Error(), "cleanup notifications") {
+		t.Fatalf("expected cleanup error, got %v", err)
+	}
+}
+
+func TestReminderService_CleanupOld_DeletesEachTableInBatches(t *testing.T) {
+	db := newBatchCountingDB(map[string]int{
+		"reminder_image_tokens":       12,
+		"reminder_unsubscribe_tokens": 3,
+		"reminder_email_log":          30,
+	})
+	svc := NewReminderService(db, nil, "http://example.com")
+	svc.cleanup = cleanupLimits{batchSize: 10, budget: time.Minute}
+
+	deleted, err := svc.CleanupOld(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 45 {
+		t.Fatalf("expected 45 deleted, got %d", deleted)
+	}
+	want := map[string]int{"reminder_image_tokens": 2, "reminder_unsubscribe_tokens": 1, "reminder_email_log": 4}
+	for table, batches := range want {
+		if db.batches[table] != batches {
+			t.Fatalf("expected %d batches for %s, got %d", batches, table, db.batches[table])
+		}
+	}
+}
+
+func TestReminderService_CleanupOld_SharesTimeBudget(t *testing.T) {
+	db := newBatchCountingDB(map[string]int{
+		"reminder_image_tokens":       50,
+		"reminder_unsubscribe_tokens": 50,
+		"reminder_email_log":          50,
+	})
+	svc := NewReminderService(db, nil, "http://example.com")
+	svc.cleanup = cleanupLimits{batchSize: 10, budget: 0}
+
+	deleted, err := svc.CleanupOld(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 10 || len(db.batches) != 1 {
+		t.Fatalf("expected a single batch before the budget ran out, got %d deleted, batches %v", deleted, db.batches)
+	}
+}
+
+func TestReminderService_CleanupOld_ErrorStopsRun(t *testing.T) {
+	db := newBatchCountingDB(map[string]int{"reminder_image_tokens": 5})
+	db.failOn = "reminder_unsubscribe_tokens"
+	svc := NewReminderService(db, nil, "http://example.com")
+
+	deleted, err := svc.CleanupOld(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "cleanup reminder_unsubscribe_tokens") {
+		t.Fatalf("expected cleanup error, got %v", err)
+	}
+	if deleted != 5 || db.batches["reminder_email_log"] != 0 {
+		t.Fatalf("expected the run to stop after the failing table, got %d deleted, batches %v", deleted, db.batches)
+	}
+}
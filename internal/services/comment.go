@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrCommentEmpty    = errors.New("comment is empty")
+	ErrCommentTooLong  = fmt.Errorf("comment must be %d characters or less", models.MaxItemCommentLength)
+)
+
+// CommentService stores short comments on goals. Friends who can see a goal
+// may comment on it, as they may react to it, and the owner may reply.
+type CommentService struct {
+	db                  DBConn
+	friendService       FriendChecker
+	notificationService NotificationServiceInterface
+}
+
+func NewCommentService(db DBConn, friendService FriendChecker) *CommentService {
+	return &CommentService{
+		db:            db,
+		friendService: friendService,
+	}
+}
+
+func (s *CommentService) SetNotificationService(notificationService NotificationServiceInterface) {
+	s.notificationService = notificationService
+}
+
+// commentTarget is the goal a comment is about.
+type commentTarget struct {
+	cardID  uuid.UUID
+	ownerID uuid.UUID
+}
+
+// AddComment saves a comment and notifies the card owner when the commenter
+// is a friend. A failed notification is logged and doesn't fail the comment.
+func (s *CommentService) AddComment(ctx context.Context, userID, itemID uuid.UUID, content string) (*models.ItemComment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, ErrCommentEmpty
+	}
+	if utf8.RuneCountInString(content) > models.MaxItemCommentLength {
+		return nil, ErrCommentTooLong
+	}
+
+	target, err := s.loadTarget(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.ItemComment{ItemID: itemID, UserID: userID, Content: content}
+	err = s.db.QueryRow(ctx,
+		`INSERT INTO bingo_item_comments (item_id, user_id, content)
+		 VALUES ($1, $2, $3)
+		 RETURNING id, created_at`,
+		itemID, userID, content,
+	).Scan(&comment.ID, &comment.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("adding comment: %w", err)
+	}
+	if err := s.db.QueryRow(ctx, "SELECT username FROM users WHERE id = $1", userID).Scan(&comment.Username); err != nil {
+		return nil, fmt.Errorf("getting comment author: %w", err)
+	}
+
+	if target.ownerID != userID && s.notificationService != nil {
+		if err := s.notificationService.NotifyItemComment(ctx, target.ownerID, userID, target.cardID); err != nil {
+			logging.Error("Failed to send comment notification", map[string]interface{}{
+				"error":        err.Error(),
+				"user_id":      userID.String(),
+				"recipient_id": target.ownerID.String(),
+				"comment_id":   comment.ID.String(),
+				"notification": string(models.NotificationTypeItemComment),
+			})
+		}
+	}
+
+	return comment, nil
+}
+
+// ListComments returns a goal's comments, oldest first, to anyone who could
+// comment on it.
+func (s *CommentService) ListComments(ctx context.Context, userID, itemID uuid.UUID) ([]models.ItemComment, error) {
+	if _, err := s.loadTarget(ctx, userID, itemID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT c.id, c.item_id, c.user_id, u.username, c.content, c.created_at
+		 FROM bingo_item_comments c
+		 JOIN users u ON u.id = c.user_id AND u.deleted_at IS NULL
+		 WHERE c.item_id = $1
+		 ORDER BY c.created_at, c.id`,
+		itemID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []models.ItemComment{}
+	for rows.Next() {
+		var c models.ItemComment
+		if err := rows.Scan(&c.ID, &c.ItemID, &c.UserID, &c.Username, &c.Content, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating comments: %w", err)
+	}
+	return comments, nil
+}
+
+// DeleteComment removes a comment. Its author and the owner of the card it is
+// on may delete it; anyone else gets ErrCommentNotFound.
+func (s *CommentService) DeleteComment(ctx context.Context, userID, commentID uuid.UUID) error {
+	result, err := s.db.Exec(ctx,
+		`DELETE FROM bingo_item_comments
+		 WHERE id = $1
+		   AND (user_id = $2 OR item_id IN (
+		     SELECT bi.id FROM bingo_items bi
+		     JOIN bingo_cards bc ON bc.id = bi.card_id
+		     WHERE bc.user_id = $2
+		   ))`,
+		commentID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting comment: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// loadTarget applies the reaction access rules: the owner may always see their
+// goal; anyone else must be an unblocked friend who can see the card, and
+// private goals are off limits.
+func (s *CommentService) loadTarget(ctx context.Context, userID, itemID uuid.UUID) (commentTarget, error) {
+	var target commentTarget
+	var isPrivate bool
+	var vis authz.CardVisibility
+	err := s.db.QueryRow(ctx,
+		`SELECT bc.id, bc.user_id, bi.is_private, bc.is_finalized, bc.visible_to_friends
+		 FROM bingo_items bi
+		 JOIN bingo_cards bc ON bi.card_id = bc.id
+		 WHERE bi.id = $1`,
+		itemID,
+	).Scan(&target.cardID, &target.ownerID, &isPrivate, &vis.Finalized, &vis.VisibleToFriends)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return target, ErrItemNotFound
+	}
+	if err != nil {
+		return target, fmt.Errorf("getting item info: %w", err)
+	}
+	if target.ownerID == userID {
+		return target, nil
+	}
+
+	if isPrivate {
+		return target, ErrItemPrivate
+	}
+	rel, err := s.friendService.Relation(ctx, userID, target.ownerID)
+	if err != nil {
+		return target, err
+	}
+	if !rel.IsFriend() {
+		return target, ErrNotFriend
+	}
+	if !authz.CanViewItem(rel, vis, isPrivate) {
+		return target, ErrItemNotFound
+	}
+	return target, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestCommentService_AccessAndNotifications(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	users := NewUserService(db)
+	newUser := func(name string) uuid.UUID {
+		user, err := users.Create(ctx, models.CreateUserParams{Email: name + "@example.com", Username: name})
+		if err != nil {
+			t.Fatalf("unexpected error creating user: %v", err)
+		}
+		return user.ID
+	}
+	owner, friend, stranger := newUser("owner"), newUser("friend"), newUser("stranger")
+
+	friends := NewFriendService(db)
+	friendship, _, err := friends.SendRequest(ctx, owner, friend)
+	if err != nil {
+		t.Fatalf("unexpected error sending request: %v", err)
+	}
+	if _, err := friends.AcceptRequest(ctx, friend, friendship.ID); err != nil {
+		t.Fatalf("unexpected error accepting request: %v", err)
+	}
+
+	cards := NewCardService(db)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: owner, Year: 2026, GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	var items []uuid.UUID
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		item, err := cards.AddItem(ctx, owner, models.AddItemParams{CardID: card.ID, Content: content})
+		if err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+		items = append(items, item.ID)
+	}
+	if _, err := cards.Finalize(ctx, owner, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE bingo_items SET is_private = true WHERE id = $1", items[1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	notifications := NewNotificationService(db, nil, "http://example.com")
+	svc := NewCommentService(db, friends)
+	svc.SetNotificationService(notifications)
+
+	comment, err := svc.AddComment(ctx, friend, items[0], "  so proud of you!  ")
+	if err != nil {
+		t.Fatalf("unexpected error commenting: %v", err)
+	}
+	if comment.Content != "so proud of you!" || comment.Username != "friend" {
+		t.Fatalf("unexpected comment %+v", comment)
+	}
+	reply, err := svc.AddComment(ctx, owner, items[0], "thanks!")
+	if err != nil {
+		t.Fatalf("unexpected error replying: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		userID  uuid.UUID
+		itemID  uuid.UUID
+		content string
+		want    error
+	}{
+		{"blank", friend, items[0], "   ", ErrCommentEmpty},
+		{"too long", friend, items[0], strings.Repeat("é", models.MaxItemCommentLength+1), ErrCommentTooLong},
+		{"stranger", stranger, items[0], "hi", ErrNotFriend},
+		{"private goal", friend, items[1], "hi", ErrItemPrivate},
+		{"missing goal", friend, uuid.New(), "hi", ErrItemNotFound},
+	} {
+		if _, err := svc.AddComment(ctx, tc.userID, tc.itemID, tc.content); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+	if _, err := svc.AddComment(ctx, friend, items[0], strings.Repeat("é", models.MaxItemCommentLength)); err != nil {
+		t.Fatalf("expected a comment at the limit to be saved, got %v", err)
+	}
+
+	// Comments made within the same millisecond would tie, so space them out.
+	for i, id := range []uuid.UUID{comment.ID, reply.ID} {
+		if _, err := db.Exec(ctx, "UPDATE bingo_item_comments SET created_at = $1 WHERE id = $2", time.Now().Add(time.Duration(i-2)*time.Minute), id); err != nil {
+			t.Fatalf("unexpected error backdating comment: %v", err)
+		}
+	}
+
+	listed, _, err := notifications.List(ctx, owner, NotificationListParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error listing notifications: %v", err)
+	}
+	if len(listed) != 2 || listed[0].Type != models.NotificationTypeItemComment ||
+		listed[0].ActorUserID == nil || *listed[0].ActorUserID != friend || listed[0].CardID == nil || *listed[0].CardID != card.ID {
+		t.Fatalf("expected a comment notification per friend comment, got %+v", listed)
+	}
+
+	comments, err := svc.ListComments(ctx, friend, items[0])
+	if err != nil || len(comments) != 3 || comments[0].ID != comment.ID || comments[1].ID != reply.ID {
+		t.Fatalf("expected comments oldest first, got %+v %v", comments, err)
+	}
+	if _, err := svc.ListComments(ctx, stranger, items[0]); !errors.Is(err, ErrNotFriend) {
+		t.Fatalf("expected ErrNotFriend listing as a stranger, got %v", err)
+	}
+
+	// The author and the card owner may delete a comment; nobody else can.
+	if err := svc.DeleteComment(ctx, stranger, comment.ID); !errors.Is(err, ErrCommentNotFound) {
+		t.Fatalf("expected ErrCommentNotFound for a stranger, got %v", err)
+	}
+	if err := svc.DeleteComment(ctx, friend, reply.ID); !errors.Is(err, ErrCommentNotFound) {
+		t.Fatalf("expected ErrCommentNotFound deleting the owner's reply, got %v", err)
+	}
+	if err := svc.DeleteComment(ctx, owner, comment.ID); err != nil {
+		t.Fatalf("unexpected error deleting as the owner: %v", err)
+	}
+	if err := svc.DeleteComment(ctx, owner, reply.ID); err != nil {
+		t.Fatalf("unexpected error deleting as the author: %v", err)
+	}
+
+	if err := NewBlockService(db).Block(ctx, owner, friend); err != nil {
+		t.Fatalf("unexpected error blocking: %v", err)
+	}
+	if _, err := svc.AddComment(ctx, friend, items[0], "hi"); !errors.Is(err, ErrNotFriend) {
+		t.Fatalf("expected ErrNotFriend after a block, got %v", err)
+	}
+}
@@ -46,6 +46,27 @@ type DB interface {
 	Begin(ctx context.Context) (Tx, error)
 }
 
+// txBinder is implemented by transactions that route pool calls made with a
+// bound context into themselves (see SQLiteAdapter).
+type txBinder interface {
+	bind(ctx context.Context) context.Context
+}
+
+// beginTx starts a transaction and returns the context to use until it
+// ends. Pool calls made with that context, e.g. by another service, run
+// inside the transaction on SQLite, where a separate write would wait on the
+// transaction's own lock; on Postgres the context is returned unchanged.
+func beginTx(ctx context.Context, db DB) (Tx, context.Context, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, ctx, err
+	}
+	if b, ok := tx.(txBinder); ok {
+		ctx = b.bind(ctx)
+	}
+	return tx, ctx, nil
+}
+
 type pgxPoolLike interface {
 	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
@@ -0,0 +1,579 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// sqliteTimeLayout is how times are stored in SQLite mode: UTC with fixed
+// microsecond precision, so comparing them as text orders them correctly.
+const sqliteTimeLayout = "2006-01-02 15:04:05.000000"
+
+// sqliteNow returns a SQLite expression for the current time in
+// sqliteTimeLayout, shifted by date modifiers such as "-90 days".
+func sqliteNow(modifiers ...string) string {
+	args := []string{"'%Y-%m-%d %H:%M:%f'", "'now'"}
+	for _, modifier := range modifiers {
+		args = append(args, "'"+modifier+"'")
+	}
+	return "(strftime(" + strings.Join(args, ", ") + ") || '000')"
+}
+
+// sqliteToday returns a SQLite expression for today's date in sqliteTimeLayout.
+func sqliteToday() string {
+	return "(strftime('%Y-%m-%d', 'now') || ' 00:00:00.000000')"
+}
+
+// SQLiteAdapter wraps a database/sql SQLite handle to satisfy DB. Services
+// keep writing Postgres SQL; the adapter rewrites the few constructs SQLite
+// lacks and maps results and errors back to what pgx would return.
+//
+// SQLite allows one writer at a time, so a pool write made while the same
+// flow holds a transaction would wait on itself. beginTx therefore binds the
+// transaction to a derived context, and calls made with that context run
+// inside it. Other callers, even ones sharing the original context, use the
+// pool.
+type SQLiteAdapter struct {
+	db *sql.DB
+}
+
+// NewSQLiteAdapter builds a DB adapter around an open SQLite database.
+func NewSQLiteAdapter(db *sql.DB) *SQLiteAdapter {
+	return &SQLiteAdapter{db: db}
+}
+
+// sqlConn is the query surface shared by *sql.DB and *sql.Tx.
+type sqlConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type sqliteTxKey struct{}
+
+// boundTx returns the open transaction of this adapter that ctx is bound
+// to, if any.
+func (a *SQLiteAdapter) boundTx(ctx context.Context) *sqliteTx {
+	t, ok := ctx.Value(sqliteTxKey{}).(*sqliteTx)
+	if !ok || t.adapter != a || t.done.Load() {
+		return nil
+	}
+	return t
+}
+
+// conn returns the open transaction ctx is bound to, or the pool.
+func (a *SQLiteAdapter) conn(ctx context.Context) sqlConn {
+	if t := a.boundTx(ctx); t != nil {
+		return t.tx
+	}
+	return a.db
+}
+
+func (a *SQLiteAdapter) Exec(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+	return sqliteExec(ctx, a.conn(ctx), sql, args)
+}
+
+func (a *SQLiteAdapter) Query(ctx context.Context, sql string, args ...any) (Rows, error) {
+	return sqliteQuery(ctx, a.conn(ctx), sql, args)
+}
+
+func (a *SQLiteAdapter) QueryRow(ctx context.Context, sql string, args ...any) Row {
+	return sqliteRow{row: a.conn(ctx).QueryRowContext(ctx, rewriteForSQLite(sql), sqliteArgs(args)...)}
+}
+
+// Begin starts a transaction, or joins the one ctx is bound to.
+func (a *SQLiteAdapter) Begin(ctx context.Context) (Tx, error) {
+	if outer := a.boundTx(ctx); outer != nil {
+		return &sqliteTx{tx: outer.tx, adapter: a, nested: true}, nil
+	}
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, sqliteError(err)
+	}
+	return &sqliteTx{tx: tx, adapter: a}, nil
+}
+
+// sqliteTx is a transaction; a nested one shares its outer transaction and
+// leaves committing or rolling back to it.
+type sqliteTx struct {
+	tx      *sql.Tx
+	adapter *SQLiteAdapter
+	nested  bool
+	done    atomic.Bool
+}
+
+// bind returns ctx bound to the transaction. A nested transaction's context
+// is already bound to its outer one.
+func (t *sqliteTx) bind(ctx context.Context) context.Context {
+	if t.nested {
+		return ctx
+	}
+	return context.WithValue(ctx, sqliteTxKey{}, t)
+}
+
+func (t *sqliteTx) Exec(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+	return sqliteExec(ctx, t.tx, sql, args)
+}
+
+func (t *sqliteTx) Query(ctx context.Context, sql string, args ...any) (Rows, error) {
+	return sqliteQuery(ctx, t.tx, sql, args)
+}
+
+func (t *sqliteTx) QueryRow(ctx context.Context, sql string, args ...any) Row {
+	return sqliteRow{row: t.tx.QueryRowContext(ctx, rewriteForSQLite(sql), sqliteArgs(args)...)}
+}
+
+func (t *sqliteTx) Commit(ctx context.Context) error {
+	if t.nested {
+		return nil
+	}
+	t.done.Store(true)
+	return sqliteError(t.tx.Commit())
+}
+
+func (t *sqliteTx) Rollback(ctx context.Context) error {
+	if t.nested {
+		return nil
+	}
+	t.done.Store(true)
+	return sqliteError(t.tx.Rollback())
+}
+
+func sqliteExec(ctx context.Context, conn sqlConn, query string, args []any) (CommandTag, error) {
+	result, err := conn.ExecContext(ctx, rewriteForSQLite(query), sqliteArgs(args)...)
+	if err != nil {
+		return sqliteCommandTag{}, sqliteError(err)
+	}
+	return sqliteCommandTag{result: result}, nil
+}
+
+func sqliteQuery(ctx context.Context, conn sqlConn, query string, args []any) (Rows, error) {
+	rows, err := conn.QueryContext(ctx, rewriteForSQLite(query), sqliteArgs(args)...)
+	if err != nil {
+		return nil, sqliteError(err)
+	}
+	return &sqliteRows{rows: rows}, nil
+}
+
+type sqliteRow struct {
+	row *sql.Row
+}
+
+func (r sqliteRow) Scan(dest ...any) error {
+	return sqliteError(r.row.Scan(sqliteDests(dest)...))
+}
+
+type sqliteRows struct {
+	rows *sql.Rows
+}
+
+func (r *sqliteRows) Close() {
+	_ = r.rows.Close()
+}
+
+func (r *sqliteRows) Err() error {
+	return sqliteError(r.rows.Err())
+}
+
+func (r *sqliteRows) Next() bool {
+	return r.rows.Next()
+}
+
+func (r *sqliteRows) Scan(dest ...any) error {
+	return sqliteError(r.rows.Scan(sqliteDests(dest)...))
+}
+
+type sqliteCommandTag struct {
+	result sql.Result
+}
+
+func (c sqliteCommandTag) RowsAffected() int64 {
+	if c.result == nil {
+		return 0
+	}
+	n, err := c.result.RowsAffected()
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// SQLite extended result codes for constraint failures and the Postgres
+// SQLSTATEs services check for instead.
+var sqliteConstraintCodes = map[int]string{
+	275:  "23514", // SQLITE_CONSTRAINT_CHECK
+	787:  "23503", // SQLITE_CONSTRAINT_FOREIGNKEY
+	1299: "23502", // SQLITE_CONSTRAINT_NOTNULL
+	1555: "23505", // SQLITE_CONSTRAINT_PRIMARYKEY
+	2067: "23505", // SQLITE_CONSTRAINT_UNIQUE
+}
+
+// sqliteError maps database/sql and driver errors onto the pgx errors
+// services already handle.
+func sqliteError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return pgx.ErrNoRows
+	}
+	if errors.Is(err, sql.ErrTxDone) {
+		return pgx.ErrTxClosed
+	}
+	var coded interface{ Code() int }
+	if errors.As(err, &coded) {
+		if code, ok := sqliteConstraintCodes[coded.Code()]; ok {
+			return &pgconn.PgError{Code: code, Message: err.Error()}
+		}
+	}
+	return err
+}
+
+// sqliteArgs converts query arguments to the forms the rewritten queries
+// expect: times as sqliteTimeLayout text and slices as JSON arrays for
+// json_each.
+func sqliteArgs(args []any) []any {
+	out := make([]any, len(args))
+	for i, arg := range args {
+		out[i] = sqliteArg(arg)
+	}
+	return out
+}
+
+func sqliteArg(arg any) any {
+	switch v := arg.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		return v.UTC().Format(sqliteTimeLayout)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC().Format(sqliteTimeLayout)
+	case []byte:
+		return v
+	case json.RawMessage:
+		if v == nil {
+			return nil
+		}
+		return string(v)
+	case driver.Valuer:
+		return arg
+	}
+
+	value := reflect.ValueOf(arg)
+	if value.Kind() != reflect.Slice || value.Type().Elem().Kind() == reflect.Uint8 {
+		return arg
+	}
+	if value.IsNil() {
+		return nil
+	}
+	elems := make([]any, value.Len())
+	for i := range elems {
+		elem := sqliteArg(value.Index(i).Interface())
+		if valuer, ok := elem.(driver.Valuer); ok {
+			converted, err := valuer.Value()
+			if err == nil {
+				elem = converted
+			}
+		}
+		elems[i] = elem
+	}
+	encoded, err := json.Marshal(elems)
+	if err != nil {
+		return arg
+	}
+	return string(encoded)
+}
+
+// sqliteDests wraps scan destinations that database/sql can't fill from
+// SQLite values by itself.
+func sqliteDests(dest []any) []any {
+	out := make([]any, len(dest))
+	for i, d := range dest {
+		switch v := d.(type) {
+		case *time.Time:
+			out[i] = &sqliteTimeDest{dst: v}
+		case **time.Time:
+			out[i] = &sqliteNullTimeDest{dst: v}
+		case *json.RawMessage:
+			out[i] = &sqliteRawDest{dst: v}
+		default:
+			out[i] = d
+		}
+	}
+	return out
+}
+
+var sqliteTimeLayouts = []string{
+	sqliteTimeLayout,
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func parseSQLiteTime(src any) (time.Time, error) {
+	switch v := src.(type) {
+	case time.Time:
+		return v.UTC(), nil
+	case string:
+		for _, layout := range sqliteTimeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t.UTC(), nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("parsing sqlite time %q", v)
+	case []byte:
+		return parseSQLiteTime(string(v))
+	}
+	return time.Time{}, fmt.Errorf("cannot scan %T into time.Time", src)
+}
+
+type sqliteTimeDest struct {
+	dst *time.Time
+}
+
+func (d *sqliteTimeDest) Scan(src any) error {
+	if src == nil {
+		return errors.New("cannot scan NULL into *time.Time")
+	}
+	t, err := parseSQLiteTime(src)
+	if err != nil {
+		return err
+	}
+	*d.dst = t
+	return nil
+}
+
+type sqliteNullTimeDest struct {
+	dst **time.Time
+}
+
+func (d *sqliteNullTimeDest) Scan(src any) error {
+	if src == nil {
+		*d.dst = nil
+		return nil
+	}
+	t, err := parseSQLiteTime(src)
+	if err != nil {
+		return err
+	}
+	*d.dst = &t
+	return nil
+}
+
+type sqliteRawDest struct {
+	dst *json.RawMessage
+}
+
+func (d *sqliteRawDest) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d.dst = nil
+	case string:
+		*d.dst = json.RawMessage(v)
+	case []byte:
+		*d.dst = append(json.RawMessage(nil), v...)
+	default:
+		return fmt.Errorf("cannot scan %T into json.RawMessage", src)
+	}
+	return nil
+}
+
+var (
+	sqliteCastPattern      = regexp.MustCompile(`::[a-zA-Z_]+(\[\])?`)
+	sqliteIntervalPattern  = regexp.MustCompile(`(?i)NOW\(\)\s*([-+])\s*INTERVAL\s*'(\d+)\s+([a-z]+)'`)
+	sqliteNowPattern       = regexp.MustCompile(`(?i)\bNOW\(\)`)
+	sqliteTodayPattern     = regexp.MustCompile(`\bCURRENT_DATE\b`)
+	sqliteAnyPattern       = regexp.MustCompile(`=\s*ANY\(\s*(\$\d+)\s*\)`)
+	sqliteILikePattern     = regexp.MustCompile(`\bILIKE\s+(\$\d+)`)
+	sqliteForUpdatePattern = regexp.MustCompile(`\s+FOR UPDATE(\s+OF\s+\w+(\s*,\s*\w+)*)?(\s+SKIP LOCKED|\s+NOWAIT)?`)
+	sqliteParamPattern     = regexp.MustCompile(`\$(\d+)`)
+	sqliteCtidPattern      = regexp.MustCompile(`\bctid\b`)
+	sqliteUnnestAlias      = regexp.MustCompile(`^\s*(WITH ORDINALITY\s+)?AS\s+(\w+)\(([^)]*)\)`)
+)
+
+var sqliteRewriteCache sync.Map
+
+// rewriteForSQLite translates the Postgres-only SQL used by services:
+// casts, NOW() and interval arithmetic, ANY and unnest over array
+// parameters (passed as JSON), ILIKE, LEAST/GREATEST, ctid batches and row
+// locks. SQLite mode runs a single process and serializes writers, so row
+// locks are dropped rather than emulated.
+func rewriteForSQLite(query string) string {
+	if cached, ok := sqliteRewriteCache.Load(query); ok {
+		return cached.(string)
+	}
+
+	out := sqliteCastPattern.ReplaceAllString(query, "")
+	out = sqliteIntervalPattern.ReplaceAllStringFunc(out, func(match string) string {
+		parts := sqliteIntervalPattern.FindStringSubmatch(match)
+		return sqliteNow(parts[1] + parts[2] + " " + strings.ToLower(parts[3]))
+	})
+	out = sqliteNowPattern.ReplaceAllString(out, sqliteNow())
+	out = sqliteTodayPattern.ReplaceAllString(out, sqliteToday())
+	out = sqliteCtidPattern.ReplaceAllString(out, "rowid")
+	out = rewriteSQLiteCalls(out, "= ANY(ARRAY", func(args []string, rest string) (string, string) {
+		// "= ANY(ARRAY(SELECT ...))": args hold the subquery and rest
+		// still starts with ANY's closing parenthesis.
+		rest = strings.TrimLeft(rest, " ")
+		if len(args) != 1 || !strings.HasPrefix(rest, ")") {
+			return "", ""
+		}
+		return "IN (" + strings.TrimSpace(args[0]) + ")", rest[1:]
+	})
+	out = sqliteAnyPattern.ReplaceAllString(out, "IN (SELECT value FROM json_each($1))")
+	out = rewriteSQLiteCalls(out, "unnest", rewriteSQLiteUnnest)
+	out = rewriteSQLiteCalls(out, "LEAST", func(args []string, rest string) (string, string) {
+		return "COALESCE(MIN(" + strings.Join(args, ",") + ")," + strings.Join(args, ",") + ")", rest
+	})
+	out = rewriteSQLiteCalls(out, "GREATEST", func(args []string, rest string) (string, string) {
+		return "COALESCE(MAX(" + strings.Join(args, ",") + ")," + strings.Join(args, ",") + ")", rest
+	})
+	out = sqliteILikePattern.ReplaceAllString(out, `LIKE $1 ESCAPE '\'`)
+	out = sqliteForUpdatePattern.ReplaceAllString(out, "")
+	out = sqliteParamPattern.ReplaceAllString(out, "?$1")
+
+	sqliteRewriteCache.Store(query, out)
+	return out
+}
+
+// rewriteSQLiteUnnest turns "unnest($1, $2) [WITH ORDINALITY] AS w(a, b[, n])"
+// into a json_each subquery zipping the JSON array parameters by index.
+func rewriteSQLiteUnnest(args []string, rest string) (string, string) {
+	alias := sqliteUnnestAlias.FindStringSubmatch(rest)
+	if alias == nil {
+		return "", ""
+	}
+	ordinality := alias[1] != ""
+	columns := strings.Split(alias[3], ",")
+	if len(columns) != len(args)+boolToInt(ordinality) {
+		return "", ""
+	}
+
+	selects := make([]string, 0, len(columns))
+	joins := make([]string, 0, len(args))
+	for i, arg := range args {
+		selects = append(selects, fmt.Sprintf("u%d.value AS %s", i, strings.TrimSpace(columns[i])))
+		if i == 0 {
+			joins = append(joins, fmt.Sprintf("json_each(%s) AS u0", strings.TrimSpace(arg)))
+		} else {
+			joins = append(joins, fmt.Sprintf("JOIN json_each(%s) AS u%d ON u%d.key = u0.key", strings.TrimSpace(arg), i, i))
+		}
+	}
+	if ordinality {
+		selects = append(selects, "u0.key + 1 AS "+strings.TrimSpace(columns[len(columns)-1]))
+	}
+	sub := fmt.Sprintf("(SELECT %s FROM %s) AS %s", strings.Join(selects, ", "), strings.Join(joins, " "), alias[2])
+	return sub, rest[len(alias[0]):]
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// rewriteSQLiteCalls replaces each call of name( ... ) in query with the
+// output of fn, which gets the call's top-level arguments and the text after
+// its closing parenthesis and returns the replacement and the remaining text.
+// An empty replacement leaves the call as written.
+func rewriteSQLiteCalls(query, name string, fn func(args []string, rest string) (string, string)) string {
+	var b strings.Builder
+	for {
+		idx := indexSQLiteCall(query, name)
+		if idx < 0 {
+			b.WriteString(query)
+			return b.String()
+		}
+		open := idx + len(name)
+		for open < len(query) && query[open] == ' ' {
+			open++
+		}
+		args, end := splitSQLiteArgs(query, open)
+		if end < 0 {
+			b.WriteString(query)
+			return b.String()
+		}
+		replacement, rest := fn(args, query[end+1:])
+		if replacement == "" {
+			b.WriteString(query[:end+1])
+			query = query[end+1:]
+			continue
+		}
+		b.WriteString(query[:idx])
+		b.WriteString(replacement)
+		query = rest
+	}
+}
+
+// indexSQLiteCall finds name followed by "(" as a whole word.
+func indexSQLiteCall(query, name string) int {
+	offset := 0
+	for {
+		idx := strings.Index(query[offset:], name)
+		if idx < 0 {
+			return -1
+		}
+		idx += offset
+		after := strings.TrimLeft(query[idx+len(name):], " ")
+		wordStart := idx == 0 || !isSQLiteIdentChar(query[idx-1]) || !isSQLiteIdentChar(name[0])
+		if wordStart && strings.HasPrefix(after, "(") {
+			return idx
+		}
+		offset = idx + len(name)
+	}
+}
+
+func isSQLiteIdentChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// splitSQLiteArgs splits the parenthesized list starting at query[open] on
+// top-level commas, skipping quoted text, and returns the index of the
+// closing parenthesis (-1 if unbalanced).
+func splitSQLiteArgs(query string, open int) ([]string, int) {
+	depth := 0
+	start := open + 1
+	var args []string
+	inQuote := false
+	for i := open; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case inQuote:
+			if c == '\'' {
+				inQuote = false
+			}
+		case c == '\'':
+			inQuote = true
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				args = append(args, query[start:i])
+				return args, i
+			}
+		case c == ',' && depth == 1:
+			args = append(args, query[start:i])
+			start = i + 1
+		}
+	}
+	return nil, -1
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/database"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestRewriteForSQLite(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "casts and placeholders",
+			in:   "SELECT COUNT(*)::int FROM t WHERE id = $1::uuid AND n = $2",
+			want: "SELECT COUNT(*) FROM t WHERE id = ?1 AND n = ?2",
+		},
+		{
+			name: "interval arithmetic",
+			in:   "DELETE FROM t WHERE created_at < NOW() - INTERVAL '90 days'",
+			want: "DELETE FROM t WHERE created_at < " + sqliteNow("-90 days"),
+		},
+		{
+			name: "array parameter",
+			in:   "UPDATE t SET x = 1 WHERE id = ANY($1)",
+			want: "UPDATE t SET x = 1 WHERE id IN (SELECT value FROM json_each(?1))",
+		},
+		{
+			name: "ctid batch",
+			in:   "DELETE FROM t WHERE ctid = ANY(ARRAY(SELECT ctid FROM t WHERE x < $1 LIMIT 10))",
+			want: "DELETE FROM t WHERE rowid IN (SELECT rowid FROM t WHERE x < ?1 LIMIT 10)",
+		},
+		{
+			name: "unnest with ordinality",
+			in:   "SELECT w.a, w.n FROM unnest($1, $2) WITH ORDINALITY AS w(a, b, n)",
+			want: "SELECT w.a, w.n FROM (SELECT u0.value AS a, u1.value AS b, u0.key + 1 AS n FROM json_each(?1) AS u0 JOIN json_each(?2) AS u1 ON u1.key = u0.key) AS w",
+		},
+		{
+			name: "least and ilike",
+			in:   "SELECT LEAST(a, $1) FROM t WHERE title ILIKE $2",
+			want: `SELECT COALESCE(MIN(a, ?1),a, ?1) FROM t WHERE title LIKE ?2 ESCAPE '\'`,
+		},
+		{
+			name: "row locks",
+			in:   "SELECT id FROM t WHERE x FOR UPDATE OF t SKIP LOCKED",
+			want: "SELECT id FROM t WHERE x",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rewriteForSQLite(tc.in); got != tc.want {
+				t.Fatalf("rewrite mismatch:\n got: %s\nwant: %s", got, tc.want)
+			}
+		})
+	}
+}
+
+// newSQLiteTestDB migrates a fresh SQLite file and wraps it in the adapter.
+func newSQLiteTestDB(t *testing.T) *SQLiteAdapter {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bingo.db")
+	db, err := database.NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(db.Close)
+	migrator, err := database.NewMigrator(database.SQLiteMigrationURL(path), "../../migrations/sqlite")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = migrator.Close() }()
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("unexpected migration error: %v", err)
+	}
+	return NewSQLiteAdapter(db.DB)
+}
+
+func TestParseSQLiteTime_ReturnsUTC(t *testing.T) {
+	for _, input := range []any{"2026-03-09 09:30:00-05:00", "2026-03-09T14:30:00Z", time.Date(2026, time.March, 9, 9, 30, 0, 0, time.FixedZone("EST", -5*60*60))} {
+		got, err := parseSQLiteTime(input)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", input, err)
+		}
+		if got.Location() != time.UTC || got.Format(time.RFC3339) != "2026-03-09T14:30:00Z" {
+			t.Fatalf("%v: expected 2026-03-09T14:30:00Z, got %v", input, got)
+		}
+	}
+}
+
+func TestSQLiteAdapter_CardLifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	users := NewUserService(db)
+	cards := NewCardService(db)
+
+	user, err := users.Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "solo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	if _, err := users.Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "other"}); !errors.Is(err, ErrEmailAlreadyExists) {
+		t.Fatalf("expected ErrEmailAlreadyExists, got %v", err)
+	}
+
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: 2026, GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	if card.StartDate.Format("2006-01-02") != "2026-01-01" {
+		t.Fatalf("expected default start date, got %v", card.StartDate)
+	}
+	if _, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: 2026, GridSize: 2, Header: "BI"}); !errors.Is(err, ErrCardAlreadyExists) {
+		t.Fatalf("expected ErrCardAlreadyExists, got %v", err)
+	}
+
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+	item, err := cards.CompleteItem(ctx, user.ID, card.ID, 0, models.CompleteItemParams{})
+	if err != nil {
+		t.Fatalf("unexpected error completing: %v", err)
+	}
+	if !item.IsCompleted || item.CompletedAt == nil || time.Since(*item.CompletedAt) > time.Minute {
+		t.Fatalf("expected a fresh completion, got %+v", item)
+	}
+
+	loaded, err := cards.GetByID(ctx, card.ID)
+	if err != nil {
+		t.Fatalf("unexpected error loading card: %v", err)
+	}
+	if !loaded.IsFinalized || len(loaded.Items) != 4 {
+		t.Fatalf("unexpected card: %+v", loaded)
+	}
+
+	if ids, err := cards.BulkUpdateArchive(ctx, user.ID, []uuid.UUID{card.ID}, true); err != nil || len(ids) != 1 || ids[0] != card.ID {
+		t.Fatalf("expected one archived card, got %v %v", ids, err)
+	}
+
+	share, err := cards.CreateOrRotateShare(ctx, user.ID, card.ID, nil, nil, models.ShareOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error sharing: %v", err)
+	}
+	shared, err := cards.GetSharedCardByToken(ctx, share.Token)
+	if err != nil {
+		t.Fatalf("unexpected error loading share: %v", err)
+	}
+	if shared.Card.ID != card.ID || len(shared.Items) != 4 {
+		t.Fatalf("unexpected shared card: %+v", shared)
+	}
+
+	if _, err := cards.GetByID(ctx, uuid.New()); !errors.Is(err, ErrCardNotFound) {
+		t.Fatalf("expected ErrCardNotFound, got %v", err)
+	}
+
+	if ids, err := cards.BulkDelete(ctx, user.ID, []uuid.UUID{card.ID, uuid.New()}); err != nil || len(ids) != 1 || ids[0] != card.ID {
+		t.Fatalf("expected only the owned card deleted, got %v %v", ids, err)
+	}
+}
+
+func TestSQLiteAdapter_CardSearchPagesByCard(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "owner@example.com", Username: "owner"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	cards := NewCardService(db)
+	goals := map[int][]string{
+		2025: {"Juggle three balls", "Juggle four balls", "Juggle clubs", "Read"},
+		2026: {"Juggle fire", "Cook", "Hike", "Swim"},
+	}
+	for _, year := range []int{2025, 2026} {
+		card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: year, GridSize: 2, Header: "BI"})
+		if err != nil {
+			t.Fatalf("unexpected error creating card: %v", err)
+		}
+		for _, content := range goals[year] {
+			if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+				t.Fatalf("unexpected error adding %q: %v", content, err)
+			}
+		}
+	}
+
+	var years []int
+	var hits []int
+	params := models.CardSearchParams{UserID: user.ID, Query: "juggle", Limit: 1}
+	for page := 0; page < 3; page++ {
+		result, err := cards.Search(ctx, params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, match := range result.Items {
+			years = append(years, match.Year)
+			hits = append(hits, len(match.Items))
+		}
+		if !result.HasMore {
+			break
+		}
+		params.Offset += params.Limit
+	}
+	if !reflect.DeepEqual(years, []int{2026, 2025}) || !reflect.DeepEqual(hits, []int{1, 3}) {
+		t.Fatalf("expected one whole card per page, got years %v with %v hits", years, hits)
+	}
+}
+
+func TestSQLiteAdapter_TxRollback(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO users (email, username) VALUES ($1, $2)", "tx@example.com", "tx"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Rollback(ctx); !errors.Is(err, pgx.ErrTxClosed) {
+		t.Fatalf("expected pgx.ErrTxClosed on second rollback, got %v", err)
+	}
+
+	var id uuid.UUID
+	err = db.QueryRow(ctx, "SELECT id FROM users WHERE email = $1", "tx@example.com").Scan(&id)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected pgx.ErrNoRows after rollback, got %v", err)
+	}
+}
+
+func countScratch(t *testing.T, db DBConn, ctx context.Context, id uuid.UUID) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM selftest_scratch WHERE id = $1", id).Scan(&n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return n
+}
+
+func TestSQLiteAdapter_BoundContextJoinsTx(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+
+	tx, txCtx, err := beginTx(ctx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A pool write with the bound context runs inside the transaction instead
+	// of waiting on its lock, and so does a nested Begin.
+	joined := uuid.New()
+	if _, err := db.Exec(txCtx, "INSERT INTO selftest_scratch (id) VALUES ($1)", joined); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nested, nestedCtx, err := beginTx(txCtx, db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nestedCtx != txCtx {
+		t.Fatal("expected the nested transaction to keep the bound context")
+	}
+	if err := nested.Commit(nestedCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Rollback(txCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if countScratch(t, db, ctx, joined) != 0 {
+		t.Fatal("expected the joined write rolled back with the transaction")
+	}
+
+	// Once the transaction ends the bound context uses the pool again.
+	after := uuid.New()
+	if _, err := db.Exec(txCtx, "INSERT INTO selftest_scratch (id) VALUES ($1)", after); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if countScratch(t, db, ctx, after) != 1 {
+		t.Fatal("expected the write after the transaction to persist")
+	}
+}
+
+func TestSQLiteAdapter_SharedContextDoesNotJoinTx(t *testing.T) {
+	// Background jobs share one context; a goroutine must not be routed into
+	// another's transaction just because the context is the same.
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+
+	inTx, outside := uuid.New(), uuid.New()
+	began := make(chan struct{})
+	release := make(chan struct{})
+	txDone := make(chan error, 1)
+	go func() {
+		tx, txCtx, err := beginTx(ctx, db)
+		if err != nil {
+			close(began)
+			txDone <- err
+			return
+		}
+		if _, err := tx.Exec(txCtx, "INSERT INTO selftest_scratch (id) VALUES ($1)", inTx); err != nil {
+			close(began)
+			txDone <- err
+			return
+		}
+		close(began)
+		<-release
+		txDone <- tx.Rollback(txCtx)
+	}()
+	<-began
+
+	writeDone := make(chan error, 1)
+	go func() {
+		writeDone <- func() error {
+			_, err := db.Exec(ctx, "INSERT INTO selftest_scratch (id) VALUES ($1)", outside)
+			return err
+		}()
+	}()
+	// The pool write has to wait for the open transaction's lock; finishing
+	// first would mean it ran inside that transaction.
+	select {
+	case err := <-writeDone:
+		t.Fatalf("expected the write to wait for the other transaction, finished with %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	close(release)
+
+	if err := <-txDone; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := <-writeDone; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if countScratch(t, db, ctx, inTx) != 0 {
+		t.Fatal("expected the transaction's write rolled back")
+	}
+	if countScratch(t, db, ctx, outside) != 1 {
+		t.Fatal("expected the other goroutine's write kept despite the rollback")
+	}
+}
+
+func TestSQLiteAdapter_ReminderRun(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "solo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE users SET email_verified = true WHERE id = $1", user.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cards := NewCardService(db)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: time.Now().Year(), GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+
+	sent := 0
+	reminders := NewReminderService(db, stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			sent++
+			return nil
+		},
+	}, "https://example.com")
+	enabled := true
+	if _, err := reminders.UpdateSettings(ctx, user.ID, models.ReminderSettingsPatch{EmailEnabled: &enabled}); err != nil {
+		t.Fatalf("unexpected error enabling reminders: %v", err)
+	}
+	noImage := false
+	if _, err := reminders.UpsertCardCheckin(ctx, user.ID, card.ID, models.CardCheckinScheduleInput{
+		Frequency:    "monthly",
+		Schedule:     models.CardCheckinSchedulePayload{DayOfMonth: 1, Time: "09:00"},
+		IncludeImage: &noImage,
+	}); err != nil {
+		t.Fatalf("unexpected error scheduling check-in: %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE card_checkin_reminders SET next_send_at = $1", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	n, err := reminders.RunDue(ctx, time.Now(), 10)
+	if err != nil || n != 1 || sent != 1 {
+		t.Fatalf("expected one reminder sent, got %d (%d emails) %v", n, sent, err)
+	}
+	history, err := reminders.GetEmailHistory(ctx, user.ID, ReminderHistoryParams{})
+	if err != nil {
+		t.Fatalf("unexpected error loading history: %v", err)
+	}
+	if len(history.Items) != 1 || history.Items[0].CardID == nil || *history.Items[0].CardID != card.ID {
+		t.Fatalf("unexpected history: %+v", history.Items)
+	}
+	if _, err := reminders.CleanupOld(ctx); err != nil {
+		t.Fatalf("unexpected cleanup error: %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// Names reported for each doctor check.
+const (
+	DoctorItemPositions         = "item_positions"
+	DoctorDuplicateItems        = "duplicate_item_positions"
+	DoctorFreeSpacePosition     = "free_space_position"
+	DoctorReminderCards         = "reminder_cards"
+	DoctorOrphanedReactions     = "orphaned_reactions"
+	DoctorOrphanedNotifications = "orphaned_notifications"
+	DoctorDeletedUserSessions   = "deleted_user_sessions"
+)
+
+// Enabled reminders whose card isn't finalized or is archived; goal
+// reminders also when their item isn't on that card. The runner skips them,
+// so disabling them is safe.
+const (
+	doctorBadCheckinReminders = `enabled = true
+		   AND NOT EXISTS (
+		     SELECT 1 FROM bingo_cards c
+		      WHERE c.id = card_checkin_reminders.card_id
+		        AND c.is_finalized = true AND c.is_archived = false
+		   )`
+	doctorBadGoalReminders = `enabled = true
+		   AND NOT EXISTS (
+		     SELECT 1 FROM bingo_cards c
+		       JOIN bingo_items i ON i.card_id = c.id
+		      WHERE c.id = goal_reminders.card_id AND i.id = goal_reminders.item_id
+		        AND c.is_finalized = true AND c.is_archived = false
+		   )`
+)
+
+// Reactions whose goal or user row is gone, which the foreign keys should
+// have cascaded away.
+const doctorOrphanedReactions = `item_id IS NULL OR user_id IS NULL
+		    OR NOT EXISTS (SELECT 1 FROM bingo_items i WHERE i.id = reactions.item_id)
+		    OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = reactions.user_id)`
+
+// DoctorService validates data invariants the code assumes but the schema
+// doesn't fully enforce, e.g. after a bad migration or a manual fix. Checks
+// only read; with fix set, the ones with a safe repair apply it.
+type DoctorService struct {
+	db DBConn
+}
+
+func NewDoctorService(db DBConn) *DoctorService {
+	return &DoctorService{db: db}
+}
+
+type doctorCheck struct {
+	name  string
+	count func(ctx context.Context) (int64, error)
+	// fix repairs the rows count finds and returns how many it changed. Nil
+	// when there is no safe automatic repair.
+	fix func(ctx context.Context) (int64, error)
+}
+
+func (s *DoctorService) checks() []doctorCheck {
+	return []doctorCheck{
+		{DoctorItemPositions, s.countItemsOffGrid, nil},
+		{DoctorDuplicateItems, s.countDuplicateItemPositions, nil},
+		{DoctorFreeSpacePosition, s.countStrayFreeSpacePositions, nil},
+		{DoctorReminderCards, s.countBadReminders, s.disableBadReminders},
+		{DoctorOrphanedReactions, s.countOrphanedReactions, s.deleteOrphanedReactions},
+		{DoctorOrphanedNotifications, s.countOrphanedNotifications, nil},
+		{DoctorDeletedUserSessions, s.countDeletedUserSessions, nil},
+	}
+}
+
+// Run executes every check in order. It never stops early, so one report
+// shows everything that is wrong. With fix set, checks that found rows and
+// have a safe repair apply it and count again; they report fixed only when
+// nothing is left.
+func (s *DoctorService) Run(ctx context.Context, fix bool) models.DoctorReport {
+	report := models.DoctorReport{OK: true}
+	for _, c := range s.checks() {
+		result := s.runCheck(ctx, c, fix)
+		if result.Status == models.DoctorFailed {
+			report.OK = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+func (s *DoctorService) runCheck(ctx context.Context, c doctorCheck, fix bool) models.DoctorCheck {
+	result := models.DoctorCheck{Name: c.name, Status: models.DoctorFailed, Fixable: c.fix != nil}
+	rows, err := c.count(ctx)
+	if err != nil {
+		result.Error = "count: " + err.Error()
+		return result
+	}
+	result.Rows = rows
+	if rows == 0 {
+		result.Status = models.DoctorOK
+		return result
+	}
+	if !fix || c.fix == nil {
+		return result
+	}
+
+	fixed, err := c.fix(ctx)
+	result.Fixed = fixed
+	if err != nil {
+		result.Error = "fix: " + err.Error()
+		return result
+	}
+	remaining, err := c.count(ctx)
+	if err != nil {
+		result.Error = "recount: " + err.Error()
+		return result
+	}
+	if remaining > 0 {
+		result.Error = fmt.Sprintf("%d rows left after fix", remaining)
+		return result
+	}
+	result.Status = models.DoctorFixed
+	return result
+}
+
+func (s *DoctorService) count(ctx context.Context, sql string) (int64, error) {
+	var n int64
+	if err := s.db.QueryRow(ctx, sql).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// countItemsOffGrid counts goals outside their card's grid or on its FREE
+// square.
+func (s *DoctorService) countItemsOffGrid(ctx context.Context) (int64, error) {
+	return s.count(ctx, `
+		SELECT COUNT(*)
+		  FROM bingo_items i
+		  JOIN bingo_cards c ON c.id = i.card_id
+		 WHERE i.position < 0
+		    OR i.position >= c.grid_size * c.grid_size
+		    OR (c.has_free_space = true AND i.position = c.free_space_position)`)
+}
+
+// countDuplicateItemPositions counts goals sharing a square with another goal
+// on the same card.
+func (s *DoctorService) countDuplicateItemPositions(ctx context.Context) (int64, error) {
+	return s.count(ctx, `
+		SELECT COUNT(*)
+		  FROM bingo_items i
+		 WHERE EXISTS (
+		   SELECT 1 FROM bingo_items d
+		    WHERE d.card_id = i.card_id AND d.position = i.position AND d.id <> i.id
+		 )`)
+}
+
+// countStrayFreeSpacePositions counts cards without a FREE square that still
+// store its position.
+func (s *DoctorService) countStrayFreeSpacePositions(ctx context.Context) (int64, error) {
+	return s.count(ctx, `
+		SELECT COUNT(*) FROM bingo_cards
+		 WHERE has_free_space = false AND free_space_position IS NOT NULL`)
+}
+
+func (s *DoctorService) countBadReminders(ctx context.Context) (int64, error) {
+	return s.count(ctx, `
+		SELECT (SELECT COUNT(*) FROM card_checkin_reminders WHERE `+doctorBadCheckinReminders+`)
+		     + (SELECT COUNT(*) FROM goal_reminders WHERE `+doctorBadGoalReminders+`)`)
+}
+
+func (s *DoctorService) disableBadReminders(ctx context.Context) (int64, error) {
+	var disabled int64
+	for _, table := range []struct{ name, where string }{
+		{"card_checkin_reminders", doctorBadCheckinReminders},
+		{"goal_reminders", doctorBadGoalReminders},
+	} {
+		result, err := s.db.Exec(ctx,
+			"UPDATE "+table.name+" SET enabled = false, next_send_at = NULL, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE "+table.where,
+		)
+		if err != nil {
+			return disabled, fmt.Errorf("disabling %s: %w", table.name, err)
+		}
+		disabled += result.RowsAffected()
+	}
+	return disabled, nil
+}
+
+func (s *DoctorService) countOrphanedReactions(ctx context.Context) (int64, error) {
+	return s.count(ctx, "SELECT COUNT(*) FROM reactions WHERE "+doctorOrphanedReactions)
+}
+
+func (s *DoctorService) deleteOrphanedReactions(ctx context.Context) (int64, error) {
+	result, err := s.db.Exec(ctx, "DELETE FROM reactions WHERE "+doctorOrphanedReactions)
+	if err != nil {
+		return 0, fmt.Errorf("deleting reactions: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// countOrphanedNotifications counts notifications whose recipient is gone or
+// that point at a user, friendship, card or goal that no longer exists.
+// Deleting a referenced row normally nulls the reference instead.
+func (s *DoctorService) countOrphanedNotifications(ctx context.Context) (int64, error) {
+	return s.count(ctx, `
+		SELECT COUNT(*)
+		  FROM notifications n
+		 WHERE NOT EXISTS (SELECT 1 FROM users u WHERE u.id = n.user_id)
+		    OR (n.actor_user_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = n.actor_user_id))
+		    OR (n.friendship_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM friendships f WHERE f.id = n.friendship_id))
+		    OR (n.card_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM bingo_cards c WHERE c.id = n.card_id))
+		    OR (n.item_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM bingo_items i WHERE i.id = n.item_id))`)
+}
+
+// countDeletedUserSessions counts database sessions of soft-deleted users,
+// which account deletion removes.
+func (s *DoctorService) countDeletedUserSessions(ctx context.Context) (int64, error) {
+	return s.count(ctx, `
+		SELECT COUNT(*)
+		  FROM sessions s
+		  JOIN users u ON u.id = s.user_id
+		 WHERE u.deleted_at IS NOT NULL`)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// doctorCountDB answers every doctor count with counts[n] for the n-th
+// query whose SQL contains key, or 0 once they run out.
+type doctorCountDB struct {
+	fakeDB
+	counts  map[string][]int64
+	queries []string
+	execs   []string
+}
+
+func newDoctorCountDB(counts map[string][]int64) *doctorCountDB {
+	db := &doctorCountDB{counts: counts}
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		db.queries = append(db.queries, sql)
+		for key, values := range db.counts {
+			if strings.Contains(sql, key) {
+				if len(values) == 0 {
+					return rowFromValues(int64(0))
+				}
+				db.counts[key] = values[1:]
+				return rowFromValues(values[0])
+			}
+		}
+		return rowFromValues(int64(0))
+	}
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		db.execs = append(db.execs, sql)
+		return fakeCommandTag{rowsAffected: 2}, nil
+	}
+	return db
+}
+
+func TestDoctorService_Checks(t *testing.T) {
+	tests := []struct {
+		name  string
+		count func(s *DoctorService) func(ctx context.Context) (int64, error)
+		want  []string
+	}{
+		{DoctorItemPositions, func(s *DoctorService) func(ctx context.Context) (int64, error) { return s.countItemsOffGrid }, []string{"c.grid_size * c.grid_size", "i.position = c.free_space_position"}},
+		{DoctorDuplicateItems, func(s *DoctorService) func(ctx context.Context) (int64, error) { return s.countDuplicateItemPositions }, []string{"d.position = i.position AND d.id <> i.id"}},
+		{DoctorFreeSpacePosition, func(s *DoctorService) func(ctx context.Context) (int64, error) { return s.countStrayFreeSpacePositions }, []string{"has_free_space = false AND free_space_position IS NOT NULL"}},
+		{DoctorReminderCards, func(s *DoctorService) func(ctx context.Context) (int64, error) { return s.countBadReminders }, []string{"FROM card_checkin_reminders", "FROM goal_reminders", "c.is_finalized = true AND c.is_archived = false"}},
+		{DoctorOrphanedReactions, func(s *DoctorService) func(ctx context.Context) (int64, error) { return s.countOrphanedReactions }, []string{"FROM reactions", "i.id = reactions.item_id", "u.id = reactions.user_id"}},
+		{DoctorOrphanedNotifications, func(s *DoctorService) func(ctx context.Context) (int64, error) { return s.countOrphanedNotifications }, []string{"FROM notifications n", "f.id = n.friendship_id", "i.id = n.item_id"}},
+		{DoctorDeletedUserSessions, func(s *DoctorService) func(ctx context.Context) (int64, error) { return s.countDeletedUserSessions }, []string{"FROM sessions s", "u.deleted_at IS NOT NULL"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSQL string
+			svc := NewDoctorService(&fakeDB{QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				gotSQL = sql
+				return rowFromValues(int64(3))
+			}})
+			n, err := tt.count(svc)(context.Background())
+			if err != nil || n != 3 {
+				t.Fatalf("expected 3, got %d %v", n, err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(gotSQL, want) {
+					t.Fatalf("expected SQL to contain %q, got %q", want, gotSQL)
+				}
+			}
+
+			svc = NewDoctorService(&fakeDB{QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				return fakeRow{scanFunc: func(dest ...any) error { return errors.New("relation does not exist") }}
+			}})
+			if _, err := tt.count(svc)(context.Background()); err == nil {
+				t.Fatal("expected the query error")
+			}
+		})
+	}
+	if got := len(NewDoctorService(&fakeDB{}).checks()); got != len(tests) {
+		t.Fatalf("expected every check covered, got %d checks and %d tests", got, len(tests))
+	}
+}
+
+func TestDoctorService_Run_ReportsWithoutFixing(t *testing.T) {
+	db := newDoctorCountDB(map[string][]int64{
+		"has_free_space = false": {4},
+		"FROM reactions":         {2},
+	})
+	report := NewDoctorService(db).Run(context.Background(), false)
+
+	if report.OK || len(report.Checks) != 7 {
+		t.Fatalf("expected a failing report with seven checks, got %+v", report)
+	}
+	byName := map[string]models.DoctorCheck{}
+	for _, check := range report.Checks {
+		byName[check.Name] = check
+	}
+	if c := byName[DoctorFreeSpacePosition]; c.Status != models.DoctorFailed || c.Rows != 4 || c.Fixable {
+		t.Fatalf("unexpected free space check %+v", c)
+	}
+	if c := byName[DoctorOrphanedReactions]; c.Status != models.DoctorFailed || c.Rows != 2 || !c.Fixable || c.Fixed != 0 {
+		t.Fatalf("unexpected reactions check %+v", c)
+	}
+	if c := byName[DoctorItemPositions]; c.Status != models.DoctorOK || c.Rows != 0 {
+		t.Fatalf("unexpected item positions check %+v", c)
+	}
+	if len(db.execs) != 0 {
+		t.Fatalf("expected no writes without fix, got %v", db.execs)
+	}
+}
+
+func TestDoctorService_Run_Fix(t *testing.T) {
+	db := newDoctorCountDB(map[string][]int64{
+		"FROM goal_reminders": {3, 0},
+		"FROM reactions":      {2, 1},
+		"FROM sessions s":     {5},
+	})
+	report := NewDoctorService(db).Run(context.Background(), true)
+
+	byName := map[string]models.DoctorCheck{}
+	for _, check := range report.Checks {
+		byName[check.Name] = check
+	}
+	if c := byName[DoctorReminderCards]; c.Status != models.DoctorFixed || c.Rows != 3 || c.Fixed != 4 {
+		t.Fatalf("expected reminders fixed, got %+v", c)
+	}
+	if c := byName[DoctorOrphanedReactions]; c.Status != models.DoctorFailed || c.Fixed != 2 || c.Error != "1 rows left after fix" {
+		t.Fatalf("expected rows left after the reactions fix, got %+v", c)
+	}
+	if c := byName[DoctorDeletedUserSessions]; c.Status != models.DoctorFailed || c.Fixed != 0 {
+		t.Fatalf("expected sessions reported only, got %+v", c)
+	}
+	if report.OK {
+		t.Fatal("expected the report to fail while rows are left")
+	}
+	if len(db.execs) != 3 ||
+		!strings.HasPrefix(db.execs[0], "UPDATE card_checkin_reminders SET enabled = false") ||
+		!strings.HasPrefix(db.execs[1], "UPDATE goal_reminders SET enabled = false") ||
+		!strings.HasPrefix(db.execs[2], "DELETE FROM reactions") {
+		t.Fatalf("unexpected writes %v", db.execs)
+	}
+}
+
+func TestDoctorService_Run_FixError(t *testing.T) {
+	db := newDoctorCountDB(map[string][]int64{"FROM reactions": {2}})
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		return nil, errors.New("permission denied")
+	}
+	report := NewDoctorService(db).Run(context.Background(), true)
+	for _, c := range report.Checks {
+		if c.Name == DoctorOrphanedReactions && (c.Status != models.DoctorFailed || !strings.HasPrefix(c.Error, "fix: deleting reactions")) {
+			t.Fatalf("expected the fix error, got %+v", c)
+		}
+	}
+}
+
+func TestDoctorService_SQLite(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	if _, err := newTestSeedService(db).Seed(ctx, SeedOptions{Users: 2, CardsPerUser: 1, WithFriends: true, WithReminders: true, Seed: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doctor := NewDoctorService(db)
+
+	report := doctor.Run(ctx, false)
+	if !report.OK {
+		t.Fatalf("expected seeded data to pass, got %+v", report)
+	}
+
+	var cardID uuid.UUID
+	if err := db.QueryRow(ctx, "SELECT card_id FROM card_checkin_reminders LIMIT 1").Scan(&cardID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE bingo_cards SET is_archived = true WHERE id = $1", cardID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report := doctor.Run(ctx, false); report.OK {
+		t.Fatal("expected reminders on an archived card to fail")
+	}
+
+	report = doctor.Run(ctx, true)
+	if !report.OK {
+		t.Fatalf("expected the fix to repair it, got %+v", report)
+	}
+	for _, c := range report.Checks {
+		if c.Name == DoctorReminderCards && (c.Status != models.DoctorFixed || c.Rows == 0 || c.Fixed != c.Rows) {
+			t.Fatalf("unexpected reminder check %+v", c)
+		}
+	}
+	var enabled int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM card_checkin_reminders WHERE card_id = $1 AND enabled = true", cardID).Scan(&enabled); err != nil || enabled != 0 {
+		t.Fatalf("expected the reminder disabled, got %d %v", enabled, err)
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// draftNudgeAge is how long a card has to sit as a draft before its owner is
+// nudged to finalize it.
+const draftNudgeAge = 14 * 24 * time.Hour
+
+// NudgeForgottenDrafts sends a one-time notification for up to limit drafts
+// that are at least two weeks old and whose owner has no finalized card for
+// the same year, and returns how many were nudged. The card's nudged_at is
+// set in the same transaction as the notification insert, so each draft is
+// nudged at most once even with several servers running the job. Drafts for
+// past years are left alone.
+func (s *NotificationService) NudgeForgottenDrafts(ctx context.Context, now time.Time, limit int) (int, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT c.id
+		 FROM bingo_cards c
+		 JOIN users u ON u.id = c.user_id AND u.deleted_at IS NULL
+		 WHERE c.is_finalized = false AND c.is_archived = false AND c.nudged_at IS NULL
+		   AND c.created_at <= $1
+		   AND c.year >= $2
+		   AND NOT EXISTS (
+		     SELECT 1 FROM bingo_cards f
+		     WHERE f.user_id = c.user_id AND f.year = c.year AND f.is_finalized = true
+		   )
+		 ORDER BY c.created_at
+		 LIMIT $3`,
+		now.Add(-draftNudgeAge), now.Year(), limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("load forgotten drafts: %w", err)
+	}
+	var cardIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan forgotten draft: %w", err)
+		}
+		cardIDs = append(cardIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate forgotten drafts: %w", err)
+	}
+
+	nudged := 0
+	for _, cardID := range cardIDs {
+		ok, err := s.nudgeDraft(ctx, cardID, now)
+		if err != nil {
+			logging.Warn("Draft nudge failed", map[string]interface{}{
+				"card_id": cardID.String(),
+				"error":   err.Error(),
+			})
+			continue
+		}
+		if ok {
+			nudged++
+		}
+	}
+	return nudged, nil
+}
+
+// nudgeDraft claims one draft by setting nudged_at and inserts its
+// notification in the same transaction. A draft that was already claimed or
+// finalized in the meantime is skipped. A user with both channels switched
+// off gets no notification, but the draft still counts as nudged.
+func (s *NotificationService) nudgeDraft(ctx context.Context, cardID uuid.UUID, now time.Time) (bool, error) {
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	claimed, err := tx.Exec(ctx,
+		"UPDATE bingo_cards SET nudged_at = $2 WHERE id = $1 AND nudged_at IS NULL AND is_finalized = false",
+		cardID, now,
+	)
+	if err != nil {
+		return false, fmt.Errorf("claiming draft: %w", err)
+	}
+	if claimed.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	inAppEnabled := "COALESCE(ns.in_app_enabled, true)"
+	emailEnabled := "(COALESCE(ns.email_enabled, false) AND " + emailNotPausedSQL + " AND u.email_verified)"
+	rows, err := tx.Query(ctx,
+		fmt.Sprintf(
+			`INSERT INTO notifications (user_id, type, card_id, in_app_delivered, email_delivered)
+			 SELECT c.user_id, $2, c.id, %s, %s
+			 FROM bingo_cards c
+			 JOIN users u ON u.id = c.user_id AND u.deleted_at IS NULL
+			 LEFT JOIN notification_settings ns ON ns.user_id = u.id
+			 WHERE c.id = $1 AND (%s OR %s)
+			 ON CONFLICT DO NOTHING
+			 RETURNING id, user_id, email_delivered`,
+			inAppEnabled, emailEnabled, inAppEnabled, emailEnabled,
+		),
+		cardID, string(models.NotificationTypeDraftNudge),
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert draft nudge: %w", err)
+	}
+	inserted := collectInserted(rows)
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("insert draft nudge: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("committing transaction: %w", err)
+	}
+	s.DispatchEmails(inserted.emailIDs)
+	return true, nil
+}
+
+// buildDraftNudgeEmail links straight to the draft so it can be finalized.
+func buildDraftNudgeEmail(baseURL string, cardID uuid.UUID, cardTitle *string, cardYear *int, preferencesURL string, brandCfg config.BrandingConfig) (string, string, string) {
+	cardName := isolateBidi(cardDisplayName(cardTitle, cardYear))
+	cardURL := fmt.Sprintf("%s/card/%s", baseURL, cardID)
+	settingsURL := baseURL + "/profile"
+	safeCardURL := templateEscape(cardURL)
+	safeSettingsURL := templateEscape(settingsURL)
+	preferencesHTML, preferencesText := emailPreferencesLines(preferencesURL)
+
+	brand := emailBrand(brandCfg)
+	subject := "Your bingo card is still a draft"
+	message := fmt.Sprintf("%s is still a draft, so completed goals and bingos aren't being tracked yet. Finalize it to get started.", cardName)
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
+  %s<h1 style="color: #333; font-size: 24px;">%s</h1>
+
+  <p style="font-size: 16px;">%s</p>
+
+  <p>
+    <a href="%s" style="display: inline-block; background: %s; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">
+      Finalize your card
+    </a>
+  </p>
+
+  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
+  <p style="color: #666; font-size: 14px;">Manage notification settings: <a href="%s">%s</a></p>
+  %s<p style="color: #999; font-size: 12px;">%s</p>
+</body>
+</html>`,
+		brand.logoHTML(),
+		templateEscape(brand.name()),
+		templateEscape(message),
+		safeCardURL,
+		brand.accent(reminderEmailAccent),
+		safeSettingsURL,
+		safeSettingsURL,
+		preferencesHTML,
+		brand.footerHTML(),
+	)
+
+	text := fmt.Sprintf(`%s
+
+Finalize your card: %s
+
+Manage notification settings: %s
+%s
+--
+%s`, message, cardURL, settingsURL, preferencesText, brand.footerText())
+
+	return subject, html, text
+}
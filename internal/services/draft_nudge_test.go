@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestNotificationService_NudgeForgottenDrafts(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	now := time.Now()
+	users := NewUserService(db)
+	cards := NewCardService(db)
+
+	newUser := func(name string) uuid.UUID {
+		user, err := users.Create(ctx, models.CreateUserParams{Email: name + "@example.com", Username: name})
+		if err != nil {
+			t.Fatalf("unexpected error creating user: %v", err)
+		}
+		return user.ID
+	}
+	newCard := func(userID uuid.UUID, title string, age time.Duration, finalized bool) uuid.UUID {
+		card, err := cards.Create(ctx, models.CreateCardParams{UserID: userID, Year: now.Year(), Title: &title, GridSize: 2, Header: "BI"})
+		if err != nil {
+			t.Fatalf("unexpected error creating card: %v", err)
+		}
+		if _, err := db.Exec(ctx,
+			"UPDATE bingo_cards SET created_at = $1, is_finalized = $2 WHERE id = $3",
+			now.Add(-age), finalized, card.ID,
+		); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return card.ID
+	}
+
+	forgetful := newUser("forgetful")
+	if _, err := db.Exec(ctx, "UPDATE users SET email_verified = true WHERE id = $1", forgetful); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	forgotten := newCard(forgetful, "Forgotten", 20*24*time.Hour, false)
+	newCard(forgetful, "Fresh", 24*time.Hour, false)
+
+	// A spare draft doesn't need a nudge when another card for the year is live.
+	busy := newUser("busy")
+	newCard(busy, "Spare", 20*24*time.Hour, false)
+	newCard(busy, "Live", 20*24*time.Hour, true)
+
+	var mu sync.Mutex
+	var sent []string
+	email := stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, toEmail+" "+text)
+			return nil
+		},
+	}
+	svc := NewNotificationService(db, email, "http://example.com")
+	svc.SetAsync(func(fn func()) { fn() })
+	if _, err := svc.UpdateSettings(ctx, forgetful, models.NotificationSettingsPatch{EmailEnabled: boolPtr(true)}); err != nil {
+		t.Fatalf("unexpected error updating settings: %v", err)
+	}
+
+	n, err := svc.NudgeForgottenDrafts(ctx, now, 10)
+	if err != nil || n != 1 {
+		t.Fatalf("expected one nudge, got %d %v", n, err)
+	}
+	if len(sent) != 1 || !strings.HasPrefix(sent[0], "forgetful@example.com ") ||
+		!strings.Contains(sent[0], "Finalize your card: http://example.com/card/"+forgotten.String()) {
+		t.Fatalf("expected a nudge email linking the draft, got %q", sent)
+	}
+
+	listed, _, err := svc.List(ctx, forgetful, NotificationListParams{Limit: 10})
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("expected one notification, got %d %v", len(listed), err)
+	}
+	if got := listed[0]; got.Type != models.NotificationTypeDraftNudge || got.CardID == nil || *got.CardID != forgotten || !got.EmailDelivered {
+		t.Fatalf("unexpected notification %+v", got)
+	}
+	if listed, _, err := svc.List(ctx, busy, NotificationListParams{Limit: 10}); err != nil || len(listed) != 0 {
+		t.Fatalf("expected no notification for busy, got %d %v", len(listed), err)
+	}
+
+	if n, err := svc.NudgeForgottenDrafts(ctx, now.Add(time.Hour), 10); err != nil || n != 0 {
+		t.Fatalf("expected each draft to be nudged once, got %d %v", n, err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("expected no second email, got %q", sent)
+	}
+}
+
+func TestBuildDraftNudgeEmail(t *testing.T) {
+	cardID := uuid.New()
+	title := "<Goals>"
+	subject, html, text := buildDraftNudgeEmail("http://example.com", cardID, &title, nil, "", config.BrandingConfig{})
+	if !strings.Contains(subject, "draft") {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+	if strings.Contains(html, "<Goals>") || !strings.Contains(html, "&lt;Goals&gt;") {
+		t.Fatalf("expected the title to be escaped, got %q", html)
+	}
+	if !strings.Contains(text, "http://example.com/card/"+cardID.String()) {
+		t.Fatalf("expected a link to the card, got %q", text)
+	}
+}
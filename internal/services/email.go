@@ -6,17 +6,23 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"html/template"
+	"mime/multipart"
+	"net/mail"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/resend/resend-go/v2"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
 // Token expiration durations
@@ -28,12 +34,19 @@ const (
 
 // Email represents an email to be sent
 type Email struct {
+	// From is the sender as an RFC 5322 address; providers send from the
+	// stock Year of Bingo address when it is empty.
+	From    string
 	To      string
 	Subject string
 	HTML    string
 	Text    string
 }
 
+// defaultEmailFromAddress is the sender address when EMAIL_FROM_ADDRESS is
+// unset.
+const defaultEmailFromAddress = "noreply@yearofbingo.com"
+
 // EmailProvider is the interface for sending emails
 type EmailProvider interface {
 	Send(ctx context.Context, email *Email) error
@@ -42,10 +55,12 @@ type EmailProvider interface {
 // EmailService handles all email-related operations
 type EmailService struct {
 	provider    EmailProvider
+	limiter     *emailSendLimiter
 	db          DBConn
 	fromAddress string
 	fromName    string
 	baseURL     string
+	branding    config.BrandingConfig
 }
 
 // NewEmailService creates a new email service based on configuration
@@ -61,9 +76,18 @@ func NewEmailService(cfg *config.EmailConfig, db DBConn) *EmailService {
 		provider = NewConsoleProvider()
 	}
 
+	// Every send goes through one limiter so the provider's request rate
+	// holds across reminders, notifications and account email.
+	var limiter *emailSendLimiter
+	if cfg.SendRate > 0 {
+		limiter = newEmailSendLimiter(cfg.SendRate, cfg.SendMaxWait)
+		provider = &rateLimitedProvider{next: provider, limiter: limiter}
+	}
+
 	trimmedBaseURL := strings.TrimRight(cfg.BaseURL, "/")
 	return &EmailService{
 		provider:    provider,
+		limiter:     limiter,
 		db:          db,
 		fromAddress: cfg.FromAddress,
 		fromName:    cfg.FromName,
@@ -71,6 +95,32 @@ func NewEmailService(cfg *config.EmailConfig, db DBConn) *EmailService {
 	}
 }
 
+// SetBranding applies the deployment's branding to account emails and routes
+// support requests to the configured support address.
+func (s *EmailService) SetBranding(branding config.BrandingConfig) {
+	s.branding = branding
+}
+
+// send delivers email from the configured address under the deployment's
+// brand name, the same name the email bodies use.
+func (s *EmailService) send(ctx context.Context, email *Email) error {
+	address := s.fromAddress
+	if address == "" {
+		address = defaultEmailFromAddress
+	}
+	email.From = (&mail.Address{Name: emailBrand(s.branding).name(), Address: address}).String()
+	return s.provider.Send(ctx, email)
+}
+
+// SendLimiterStats reports the send rate limiter's state; ok is false when
+// sends are not rate limited.
+func (s *EmailService) SendLimiterStats() (stats models.EmailSendLimiterStats, ok bool) {
+	if s.limiter == nil {
+		return models.EmailSendLimiterStats{}, false
+	}
+	return s.limiter.stats(), true
+}
+
 // GenerateToken creates a secure random token and returns both the token and its hash
 func GenerateToken() (token string, hash string, err error) {
 	bytes := make([]byte, 32)
@@ -108,9 +158,9 @@ func (s *EmailService) SendVerificationEmail(ctx context.Context, userID uuid.UU
 
 	html, text := s.renderVerificationEmail(verifyURL)
 
-	return s.provider.Send(ctx, &Email{
+	return s.send(ctx, &Email{
 		To:      email,
-		Subject: "Verify your Year of Bingo account",
+		Subject: sanitizeSubject(fmt.Sprintf("Verify your %s account", emailBrand(s.branding).name())),
 		HTML:    html,
 		Text:    text,
 	})
@@ -173,9 +223,9 @@ func (s *EmailService) SendMagicLinkEmail(ctx context.Context, email string) err
 
 	html, text := s.renderMagicLinkEmail(loginURL)
 
-	return s.provider.Send(ctx, &Email{
+	return s.send(ctx, &Email{
 		To:      email,
-		Subject: "Your Year of Bingo login link",
+		Subject: sanitizeSubject(fmt.Sprintf("Your %s login link", emailBrand(s.branding).name())),
 		HTML:    html,
 		Text:    text,
 	})
@@ -236,9 +286,9 @@ func (s *EmailService) SendPasswordResetEmail(ctx context.Context, userID uuid.U
 
 	html, text := s.renderPasswordResetEmail(resetURL)
 
-	return s.provider.Send(ctx, &Email{
+	return s.send(ctx, &Email{
 		To:      email,
-		Subject: "Reset your Year of Bingo password",
+		Subject: sanitizeSubject(fmt.Sprintf("Reset your %s password", emailBrand(s.branding).name())),
 		HTML:    html,
 		Text:    text,
 	})
@@ -280,9 +330,13 @@ func (s *EmailService) MarkPasswordResetUsed(ctx context.Context, token string)
 	return err
 }
 
-// SendNotificationEmail sends a pre-rendered notification email.
+// SendNotificationEmail sends a pre-rendered notification email. Recipients
+// who chose plaintext email get the text part alone.
 func (s *EmailService) SendNotificationEmail(ctx context.Context, toEmail, subject, html, text string) error {
-	return s.provider.Send(ctx, &Email{
+	if text != "" && s.prefersTextEmail(ctx, toEmail) {
+		html = ""
+	}
+	return s.send(ctx, &Email{
 		To:      toEmail,
 		Subject: subject,
 		HTML:    html,
@@ -290,9 +344,35 @@ func (s *EmailService) SendNotificationEmail(ctx context.Context, toEmail, subje
 	})
 }
 
+// prefersTextEmail reports whether the account at toEmail chose plaintext
+// email. Addresses without an account (share subscribers) and lookup failures
+// get both parts.
+func (s *EmailService) prefersTextEmail(ctx context.Context, toEmail string) bool {
+	if s.db == nil {
+		return false
+	}
+	var format string
+	err := s.db.QueryRow(ctx,
+		`SELECT ns.email_format
+		 FROM users u
+		 JOIN notification_settings ns ON ns.user_id = u.id
+		 WHERE u.email = $1 AND u.deleted_at IS NULL`,
+		toEmail,
+	).Scan(&format)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logging.Warn("Failed to load email format; sending HTML", map[string]interface{}{"error": err.Error()})
+		}
+		return false
+	}
+	return format == models.EmailFormatText
+}
+
 // Email templates
 
 func (s *EmailService) renderVerificationEmail(verifyURL string) (html, text string) {
+	brand := emailBrand(s.branding)
+
 	html = fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head>
@@ -300,12 +380,12 @@ func (s *EmailService) renderVerificationEmail(verifyURL string) (html, text str
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
 </head>
 <body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
-  <h1 style="color: #333; font-size: 24px;">Welcome to Year of Bingo!</h1>
+  %s<h1 style="color: #333; font-size: 24px;">Welcome to %s!</h1>
 
   <p>Please verify your email address by clicking the button below:</p>
 
   <a href="%s"
-     style="display: inline-block; background: #4F46E5; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin: 20px 0;">
+     style="display: inline-block; background: %s; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin: 20px 0;">
     Verify Email Address
   </a>
 
@@ -318,11 +398,11 @@ func (s *EmailService) renderVerificationEmail(verifyURL string) (html, text str
   </p>
 
   <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
-  <p style="color: #999; font-size: 12px;">Year of Bingo - yearofbingo.com</p>
+  <p style="color: #999; font-size: 12px;">%s</p>
 </body>
-</html>`, verifyURL, verifyURL)
+</html>`, brand.logoHTML(), templateEscape(brand.name()), verifyURL, brand.accent(authEmailAccent), verifyURL, brand.footerHTML())
 
-	text = fmt.Sprintf(`Welcome to Year of Bingo!
+	text = fmt.Sprintf(`Welcome to %s!
 
 Please verify your email address by visiting:
 %s
@@ -332,13 +412,14 @@ This link expires in 24 hours.
 If you didn't create an account, you can ignore this email.
 
 --
-Year of Bingo
-yearofbingo.com`, verifyURL)
+%s`, brand.name(), verifyURL, brand.footerText())
 
 	return html, text
 }
 
 func (s *EmailService) renderMagicLinkEmail(loginURL string) (html, text string) {
+	brand := emailBrand(s.branding)
+
 	html = fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head>
@@ -346,12 +427,12 @@ func (s *EmailService) renderMagicLinkEmail(loginURL string) (html, text string)
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
 </head>
 <body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
-  <h1 style="color: #333; font-size: 24px;">Sign in to Year of Bingo</h1>
+  %s<h1 style="color: #333; font-size: 24px;">Sign in to %s</h1>
 
   <p>Click the button below to sign in to your account:</p>
 
   <a href="%s"
-     style="display: inline-block; background: #4F46E5; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin: 20px 0;">
+     style="display: inline-block; background: %s; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin: 20px 0;">
     Sign In
   </a>
 
@@ -368,11 +449,11 @@ func (s *EmailService) renderMagicLinkEmail(loginURL string) (html, text string)
   </p>
 
   <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
-  <p style="color: #999; font-size: 12px;">Year of Bingo - yearofbingo.com</p>
+  <p style="color: #999; font-size: 12px;">%s</p>
 </body>
-</html>`, loginURL, loginURL)
+</html>`, brand.logoHTML(), templateEscape(brand.name()), loginURL, brand.accent(authEmailAccent), loginURL, brand.footerHTML())
 
-	text = fmt.Sprintf(`Sign in to Year of Bingo
+	text = fmt.Sprintf(`Sign in to %s
 
 Click the link below to sign in:
 %s
@@ -382,13 +463,14 @@ This link expires in 15 minutes and can only be used once.
 If you didn't request this link, you can safely ignore this email.
 
 --
-Year of Bingo
-yearofbingo.com`, loginURL)
+%s`, brand.name(), loginURL, brand.footerText())
 
 	return html, text
 }
 
 func (s *EmailService) renderPasswordResetEmail(resetURL string) (html, text string) {
+	brand := emailBrand(s.branding)
+
 	html = fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head>
@@ -396,12 +478,12 @@ func (s *EmailService) renderPasswordResetEmail(resetURL string) (html, text str
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
 </head>
 <body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
-  <h1 style="color: #333; font-size: 24px;">Reset Your Password</h1>
+  %s<h1 style="color: #333; font-size: 24px;">Reset Your Password</h1>
 
   <p>We received a request to reset your password. Click the button below to choose a new password:</p>
 
   <a href="%s"
-     style="display: inline-block; background: #4F46E5; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin: 20px 0;">
+     style="display: inline-block; background: %s; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin: 20px 0;">
     Reset Password
   </a>
 
@@ -418,9 +500,9 @@ func (s *EmailService) renderPasswordResetEmail(resetURL string) (html, text str
   </p>
 
   <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
-  <p style="color: #999; font-size: 12px;">Year of Bingo - yearofbingo.com</p>
+  <p style="color: #999; font-size: 12px;">%s</p>
 </body>
-</html>`, resetURL, resetURL)
+</html>`, brand.logoHTML(), resetURL, brand.accent(authEmailAccent), resetURL, brand.footerHTML())
 
 	text = fmt.Sprintf(`Reset Your Password
 
@@ -434,8 +516,7 @@ This link expires in 1 hour and can only be used once.
 If you didn't request a password reset, you can safely ignore this email.
 
 --
-Year of Bingo
-yearofbingo.com`, resetURL)
+%s`, resetURL, brand.footerText())
 
 	return html, text
 }
@@ -453,7 +534,7 @@ func NewResendProvider(apiKey string) *ResendProvider {
 
 func (p *ResendProvider) Send(ctx context.Context, email *Email) error {
 	params := &resend.SendEmailRequest{
-		From:    "Year of Bingo <noreply@yearofbingo.com>",
+		From:    emailFrom(email),
 		To:      []string{email.To},
 		Subject: email.Subject,
 		Html:    email.HTML,
@@ -482,23 +563,56 @@ func NewSMTPProvider(host string, port int) *SMTPProvider {
 func (p *SMTPProvider) Send(ctx context.Context, email *Email) error {
 	addr := fmt.Sprintf("%s:%d", p.host, p.port)
 
-	// Build email message
+	sender, err := mail.ParseAddress(emailFrom(email))
+	if err != nil {
+		return fmt.Errorf("parsing sender address: %w", err)
+	}
+	err = smtp.SendMail(addr, nil, sender.Address, []string{email.To}, buildSMTPMessage(email))
+	if err != nil {
+		return fmt.Errorf("sending email via SMTP: %w", err)
+	}
+
+	logging.Info("Email sent via SMTP", map[string]interface{}{"to": email.To, "subject": email.Subject})
+	return nil
+}
+
+// buildSMTPMessage renders email as a MIME message: multipart/alternative
+// when it has both parts, otherwise a single text or HTML part.
+func buildSMTPMessage(email *Email) []byte {
 	var buf bytes.Buffer
-	buf.WriteString("From: Year of Bingo <noreply@yearofbingo.com>\r\n")
+	buf.WriteString(fmt.Sprintf("From: %s\r\n", emailFrom(email)))
 	buf.WriteString(fmt.Sprintf("To: %s\r\n", email.To))
 	buf.WriteString(fmt.Sprintf("Subject: %s\r\n", email.Subject))
 	buf.WriteString("MIME-Version: 1.0\r\n")
-	buf.WriteString("Content-Type: text/html; charset=utf-8\r\n")
-	buf.WriteString("\r\n")
-	buf.WriteString(email.HTML)
 
-	err := smtp.SendMail(addr, nil, "noreply@yearofbingo.com", []string{email.To}, buf.Bytes())
-	if err != nil {
-		return fmt.Errorf("sending email via SMTP: %w", err)
+	switch {
+	case email.HTML == "":
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		buf.WriteString(email.Text)
+	case email.Text == "":
+		buf.WriteString("Content-Type: text/html; charset=utf-8\r\n\r\n")
+		buf.WriteString(email.HTML)
+	default:
+		mw := multipart.NewWriter(&buf)
+		buf.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary()))
+		for _, part := range []struct{ contentType, body string }{
+			{"text/plain; charset=utf-8", email.Text},
+			{"text/html; charset=utf-8", email.HTML},
+		} {
+			w, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+			_, _ = w.Write([]byte(part.body))
+		}
+		_ = mw.Close()
 	}
+	return buf.Bytes()
+}
 
-	logging.Info("Email sent via SMTP", map[string]interface{}{"to": email.To, "subject": email.Subject})
-	return nil
+// emailFrom returns the sender header for email.
+func emailFrom(email *Email) string {
+	if email.From == "" {
+		return (&mail.Address{Name: config.DefaultBrandName, Address: defaultEmailFromAddress}).String()
+	}
+	return email.From
 }
 
 // ConsoleProvider logs emails to console (for development)
@@ -523,20 +637,30 @@ func (p *ConsoleProvider) Send(ctx context.Context, email *Email) error {
 func (s *EmailService) SendSupportEmail(ctx context.Context, fromEmail, category, message string, userID string) error {
 	html, text := s.renderSupportEmail(fromEmail, category, message, userID)
 
-	return s.provider.Send(ctx, &Email{
-		To:      "support@yearofbingo.com",
+	return s.send(ctx, &Email{
+		To:      s.supportAddress(),
 		Subject: fmt.Sprintf("[Support] %s", category),
 		HTML:    html,
 		Text:    text,
 	})
 }
 
+// supportAddress is where support requests are delivered.
+func (s *EmailService) supportAddress() string {
+	if s.branding.SupportEmail != "" {
+		return s.branding.SupportEmail
+	}
+	return "support@yearofbingo.com"
+}
+
 func (s *EmailService) renderSupportEmail(fromEmail, category, message, userID string) (html, text string) {
 	userInfo := "Not logged in"
 	if userID != "" {
 		userInfo = userID
 	}
 
+	brand := emailBrand(s.branding)
+
 	html = fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head>
@@ -565,9 +689,9 @@ func (s *EmailService) renderSupportEmail(fromEmail, category, message, userID s
   <div style="background: #f5f5f5; padding: 15px; border-radius: 6px; white-space: pre-wrap;">%s</div>
 
   <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
-  <p style="color: #999; font-size: 12px;">Year of Bingo Support System</p>
+  <p style="color: #999; font-size: 12px;">%s Support System</p>
 </body>
-</html>`, fromEmail, category, userInfo, template.HTMLEscapeString(message))
+</html>`, fromEmail, category, userInfo, template.HTMLEscapeString(message), templateEscape(brand.name()))
 
 	text = fmt.Sprintf(`Support Request
 ===============
@@ -581,7 +705,59 @@ Message:
 %s
 
 --
-Year of Bingo Support System`, fromEmail, category, userInfo, message)
+%s Support System`, fromEmail, category, userInfo, message, brand.name())
+
+	return html, text
+}
+
+// SendDataExportEmail lets a user know their account data was exported, so an
+// export they didn't start doesn't go unnoticed.
+func (s *EmailService) SendDataExportEmail(ctx context.Context, email string, exportedAt time.Time) error {
+	html, text := s.renderDataExportEmail(exportedAt)
+
+	return s.send(ctx, &Email{
+		To:      email,
+		Subject: sanitizeSubject(fmt.Sprintf("Your %s data was exported", emailBrand(s.branding).name())),
+		HTML:    html,
+		Text:    text,
+	})
+}
+
+func (s *EmailService) renderDataExportEmail(exportedAt time.Time) (html, text string) {
+	when := exportedAt.UTC().Format("January 2, 2006 at 15:04 UTC")
+	supportURL := s.baseURL + "/support"
+
+	brand := emailBrand(s.branding)
+
+	html = fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
+  %s<h1 style="color: #333; font-size: 24px;">Your Data Was Exported</h1>
+
+  <p>A copy of your %s account data was downloaded on %s.</p>
+
+  <p style="color: #666; font-size: 14px;">
+    If this was you, there's nothing else to do. If you didn't request this export, change your password and <a href="%s">contact support</a>.
+  </p>
+
+  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
+  <p style="color: #999; font-size: 12px;">%s</p>
+</body>
+</html>`, brand.logoHTML(), templateEscape(brand.name()), when, supportURL, brand.footerHTML())
+
+	text = fmt.Sprintf(`Your Data Was Exported
+
+A copy of your %s account data was downloaded on %s.
+
+If this was you, there's nothing else to do. If you didn't request this export, change your password and contact support:
+%s
+
+--
+%s`, brand.name(), when, supportURL, brand.footerText())
 
 	return html, text
 }
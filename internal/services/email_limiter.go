@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// ErrEmailRateLimited is returned by a send that could not get a slot from
+// the email send limiter in time. Nothing was handed to the provider.
+var ErrEmailRateLimited = errors.New("email send rate limit reached")
+
+type emailNoWaitKey struct{}
+
+// WithoutEmailWait marks ctx so a send fails with ErrEmailRateLimited right
+// away when the send limiter is saturated instead of waiting for a slot.
+// Callers that can queue the email for later use it.
+func WithoutEmailWait(ctx context.Context) context.Context {
+	return context.WithValue(ctx, emailNoWaitKey{}, true)
+}
+
+func emailWaitAllowed(ctx context.Context) bool {
+	noWait, _ := ctx.Value(emailNoWaitKey{}).(bool)
+	return !noWait
+}
+
+// emailSendLimiter is a token bucket shared by every email the server sends,
+// so a reminder batch and a notification burst together stay under the
+// provider's request rate. Each send takes a token; when none is left the
+// caller waits for its turn, up to maxWait.
+type emailSendLimiter struct {
+	rate    float64 // tokens per second
+	burst   float64
+	maxWait time.Duration
+	now     func() time.Time
+	sleep   func(ctx context.Context, d time.Duration) error
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	waiting int
+	limited int64
+}
+
+func newEmailSendLimiter(rate float64, maxWait time.Duration) *emailSendLimiter {
+	burst := math.Max(1, math.Floor(rate))
+	return &emailSendLimiter{
+		rate:    rate,
+		burst:   burst,
+		maxWait: maxWait,
+		now:     time.Now,
+		sleep:   sleepContext,
+		tokens:  burst,
+	}
+}
+
+// wait takes a token, sleeping until it is due. It fails with
+// ErrEmailRateLimited without taking one when the wait would be longer than
+// maxWait or ctx allows, or when ctx was marked with WithoutEmailWait.
+func (l *emailSendLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.refill(l.now())
+	l.tokens--
+	if l.tokens >= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	deadline, hasDeadline := ctx.Deadline()
+	if !emailWaitAllowed(ctx) || delay > l.maxWait || (hasDeadline && time.Until(deadline) < delay) {
+		l.tokens++
+		l.limited++
+		l.mu.Unlock()
+		return ErrEmailRateLimited
+	}
+	l.waiting++
+	l.mu.Unlock()
+
+	err := l.sleep(ctx, delay)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waiting--
+	if err != nil {
+		// The send is abandoned, so its reserved token goes back.
+		l.tokens = math.Min(l.burst, l.tokens+1)
+		return err
+	}
+	return nil
+}
+
+// refill adds the tokens earned since the last call. Callers hold l.mu.
+func (l *emailSendLimiter) refill(now time.Time) {
+	if !now.After(l.last) {
+		return
+	}
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+}
+
+func (l *emailSendLimiter) stats() models.EmailSendLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(l.now())
+	available := math.Max(0, l.tokens)
+	return models.EmailSendLimiterStats{
+		RatePerSecond: l.rate,
+		Burst:         int(l.burst),
+		Available:     math.Round(available*100) / 100,
+		Saturation:    math.Round((1-available/l.burst)*100) / 100,
+		Waiting:       l.waiting,
+		RateLimited:   l.limited,
+	}
+}
+
+// rateLimitedProvider makes every send wait for the shared limiter first.
+type rateLimitedProvider struct {
+	next    EmailProvider
+	limiter *emailSendLimiter
+}
+
+func (p *rateLimitedProvider) Send(ctx context.Context, email *Email) error {
+	if err := p.limiter.wait(ctx); err != nil {
+		return err
+	}
+	return p.next.Send(ctx, email)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// fakeLimiterClock drives an emailSendLimiter: sleeping advances the clock
+// instead of blocking.
+type fakeLimiterClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClockLimiter(rate float64, maxWait time.Duration) (*emailSendLimiter, *fakeLimiterClock) {
+	clock := &fakeLimiterClock{now: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)}
+	limiter := newEmailSendLimiter(rate, maxWait)
+	limiter.now = func() time.Time { return clock.now }
+	limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		clock.sleeps = append(clock.sleeps, d)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		clock.now = clock.now.Add(d)
+		return nil
+	}
+	return limiter, clock
+}
+
+func TestEmailSendLimiter_PacesSendsAtTheRate(t *testing.T) {
+	limiter, clock := newFakeClockLimiter(2, time.Second)
+	start := clock.now
+
+	var sentAt []time.Duration
+	for i := 0; i < 6; i++ {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatalf("send %d: unexpected error: %v", i+1, err)
+		}
+		sentAt = append(sentAt, clock.now.Sub(start))
+	}
+
+	// The burst of two goes out at once, then one every half second.
+	want := []time.Duration{0, 0, 500 * time.Millisecond, time.Second, 1500 * time.Millisecond, 2 * time.Second}
+	for i := range want {
+		if sentAt[i] != want[i] {
+			t.Fatalf("expected sends at %v, got %v", want, sentAt)
+		}
+	}
+
+	// An idle second refills the bucket to its burst and no further.
+	clock.now = clock.now.Add(10 * time.Second)
+	if stats := limiter.stats(); stats.Available != 2 || stats.Saturation != 0 {
+		t.Fatalf("expected a full bucket after idling, got %+v", stats)
+	}
+}
+
+func TestEmailSendLimiter_RefusesInsteadOfWaitingTooLong(t *testing.T) {
+	limiter, clock := newFakeClockLimiter(1, 500*time.Millisecond)
+	ctx := context.Background()
+	if err := limiter.wait(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The next token is a second away, past maxWait.
+	if err := limiter.wait(ctx); !errors.Is(err, ErrEmailRateLimited) {
+		t.Fatalf("expected ErrEmailRateLimited, got %v", err)
+	}
+	// Callers with an outbox never wait, even briefly.
+	clock.now = clock.now.Add(700 * time.Millisecond)
+	if err := limiter.wait(WithoutEmailWait(ctx)); !errors.Is(err, ErrEmailRateLimited) {
+		t.Fatalf("expected ErrEmailRateLimited without waiting, got %v", err)
+	}
+	if len(clock.sleeps) != 0 {
+		t.Fatalf("expected refused sends not to sleep, got %v", clock.sleeps)
+	}
+
+	stats := limiter.stats()
+	want := models.EmailSendLimiterStats{RatePerSecond: 1, Burst: 1, Available: 0.7, Saturation: 0.3, RateLimited: 2}
+	if stats != want {
+		t.Fatalf("expected refusals to keep their tokens, got %+v", stats)
+	}
+
+	// Within maxWait the caller waits for its token.
+	if err := limiter.wait(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clock.sleeps) != 1 || clock.sleeps[0] != 300*time.Millisecond {
+		t.Fatalf("expected one 300ms wait, got %v", clock.sleeps)
+	}
+}
+
+func TestEmailSendLimiter_CanceledWaitReturnsItsToken(t *testing.T) {
+	limiter, clock := newFakeClockLimiter(1, time.Second)
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clock.now = clock.now.Add(100 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if stats := limiter.stats(); stats.Available != 0.1 || stats.Waiting != 0 {
+		t.Fatalf("expected the reserved token back and no waiters, got %+v", stats)
+	}
+}
+
+func TestRateLimitedProvider_SkipsTheProviderWhenRefused(t *testing.T) {
+	limiter, _ := newFakeClockLimiter(1, 0)
+	sends := 0
+	provider := &rateLimitedProvider{
+		next: providerFunc(func(ctx context.Context, email *Email) error {
+			sends++
+			return nil
+		}),
+		limiter: limiter,
+	}
+	email := &Email{To: "to@example.com", Subject: "Hi"}
+	if err := provider.Send(context.Background(), email); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := provider.Send(context.Background(), email); !errors.Is(err, ErrEmailRateLimited) {
+		t.Fatalf("expected ErrEmailRateLimited, got %v", err)
+	}
+	if sends != 1 {
+		t.Fatalf("expected one provider call, got %d", sends)
+	}
+}
+
+type providerFunc func(ctx context.Context, email *Email) error
+
+func (f providerFunc) Send(ctx context.Context, email *Email) error {
+	return f(ctx, email)
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// emailPreferencesTokenTTL is how long an emailed preferences link works.
+// Unlike unsubscribe links it can be used any number of times until then.
+const emailPreferencesTokenTTL = 30 * 24 * time.Hour
+
+var ErrEmailPreferencesExpired = errors.New("email preferences link expired")
+
+// createEmailPreferencesURL mints a preferences page link for an outgoing
+// email. The link is a convenience next to unsubscribe, so a failure only
+// drops it from the email.
+func createEmailPreferencesURL(ctx context.Context, db DBConn, baseURL string, userID uuid.UUID, now time.Time) string {
+	token, err := randomToken(24)
+	if err == nil {
+		_, err = db.Exec(ctx,
+			"INSERT INTO reminder_unsubscribe_tokens (token, user_id, expires_at, scope) VALUES ($1, $2, $3, $4)",
+			token, userID, now.Add(emailPreferencesTokenTTL), unsubscribeScopeEmailPreferences,
+		)
+	}
+	if err != nil {
+		logging.Warn("Failed to create email preferences token", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+		return ""
+	}
+	return fmt.Sprintf("%s/r/preferences?token=%s", baseURL, token)
+}
+
+// EmailPreferencesByToken loads the email toggles of the user a preferences
+// link was issued to.
+func (s *ReminderService) EmailPreferencesByToken(ctx context.Context, token string) (*models.EmailPreferences, error) {
+	userID, err := s.loadEmailPreferencesToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadEmailPreferences(ctx, userID)
+}
+
+// UpdateEmailPreferencesByToken saves the page's toggles. As in the signed-in
+// settings, turning an email on needs a verified address; turning one off
+// never does.
+func (s *ReminderService) UpdateEmailPreferencesByToken(ctx context.Context, token string, prefs models.EmailPreferences) (*models.EmailPreferences, error) {
+	userID, err := s.loadEmailPreferencesToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.loadEmailPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if enablesEmailPreference(*current, prefs) {
+		verified, err := s.isEmailVerified(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !verified {
+			return nil, ErrEmailNotVerified
+		}
+	}
+
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("begin email preferences update: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE reminder_settings SET email_enabled = $1, updated_at = NOW() WHERE user_id = $2",
+		prefs.ReminderEmails, userID,
+	); err != nil {
+		return nil, fmt.Errorf("update reminder settings: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE notification_settings
+		 SET email_enabled = $1, email_friend_request_received = $2, email_friend_request_accepted = $3,
+		     email_friend_bingo = $4, email_friend_new_card = $5, email_friends_digest = $6, updated_at = NOW()
+		 WHERE user_id = $7`,
+		prefs.NotificationEmails, prefs.FriendRequestReceived, prefs.FriendRequestAccepted,
+		prefs.FriendBingo, prefs.FriendNewCard, prefs.FriendsDigest, userID,
+	); err != nil {
+		return nil, fmt.Errorf("update notification settings: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit email preferences update: %w", err)
+	}
+
+	return &prefs, nil
+}
+
+// loadEmailPreferencesToken resolves a preferences token to its user. Tokens
+// of the other unsubscribe scopes are rejected like unknown ones.
+func (s *ReminderService) loadEmailPreferencesToken(ctx context.Context, token string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	var expiresAt time.Time
+	err := s.db.QueryRow(ctx,
+		`SELECT t.user_id, t.expires_at
+		 FROM reminder_unsubscribe_tokens t
+		 JOIN users u ON u.id = t.user_id AND u.deleted_at IS NULL
+		 WHERE t.token = $1 AND t.scope = $2`,
+		token, unsubscribeScopeEmailPreferences,
+	).Scan(&userID, &expiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrReminderNotFound
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("load email preferences token: %w", err)
+	}
+	if expiresAt.Before(s.now()) {
+		return uuid.Nil, ErrEmailPreferencesExpired
+	}
+	return userID, nil
+}
+
+func (s *ReminderService) loadEmailPreferences(ctx context.Context, userID uuid.UUID) (*models.EmailPreferences, error) {
+	if err := s.ensureSettingsRow(ctx, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.db.Exec(ctx,
+		"INSERT INTO notification_settings (user_id) VALUES ($1) ON CONFLICT DO NOTHING",
+		userID,
+	); err != nil {
+		return nil, fmt.Errorf("ensure notification settings: %w", err)
+	}
+
+	prefs := &models.EmailPreferences{}
+	if err := s.db.QueryRow(ctx,
+		`SELECT rs.email_enabled, ns.email_enabled, ns.email_friend_request_received, ns.email_friend_request_accepted,
+		        ns.email_friend_bingo, ns.email_friend_new_card, ns.email_friends_digest
+		 FROM reminder_settings rs
+		 JOIN notification_settings ns ON ns.user_id = rs.user_id
+		 WHERE rs.user_id = $1`,
+		userID,
+	).Scan(
+		&prefs.ReminderEmails,
+		&prefs.NotificationEmails,
+		&prefs.FriendRequestReceived,
+		&prefs.FriendRequestAccepted,
+		&prefs.FriendBingo,
+		&prefs.FriendNewCard,
+		&prefs.FriendsDigest,
+	); err != nil {
+		return nil, fmt.Errorf("load email preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// enablesEmailPreference reports whether next turns on any email that is off
+// in current.
+func enablesEmailPreference(current, next models.EmailPreferences) bool {
+	return (next.ReminderEmails && !current.ReminderEmails) ||
+		(next.NotificationEmails && !current.NotificationEmails) ||
+		(next.FriendRequestReceived && !current.FriendRequestReceived) ||
+		(next.FriendRequestAccepted && !current.FriendRequestAccepted) ||
+		(next.FriendBingo && !current.FriendBingo) ||
+		(next.FriendNewCard && !current.FriendNewCard) ||
+		(next.FriendsDigest && !current.FriendsDigest)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func emailPreferencesDB(t *testing.T, expiresAt time.Time, current models.EmailPreferences, verified bool, updates *[]string) *fakeDB {
+	t.Helper()
+	userID := uuid.New()
+	return &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM reminder_unsubscribe_tokens"):
+				if args[1] != unsubscribeScopeEmailPreferences {
+					t.Fatalf("expected scope filter, got %v", args[1])
+				}
+				return rowFromValues(userID, expiresAt)
+			case strings.Contains(sql, "JOIN notification_settings"):
+				return rowFromValues(current.ReminderEmails, current.NotificationEmails, current.FriendRequestReceived,
+					current.FriendRequestAccepted, current.FriendBingo, current.FriendNewCard, current.FriendsDigest)
+			case strings.Contains(sql, "SELECT email_verified"):
+				return rowFromValues(verified)
+			default:
+				t.Fatalf("unexpected query: %s", sql)
+				return nil
+			}
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			return &fakeTx{
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					*updates = append(*updates, sql)
+					return fakeCommandTag{rowsAffected: 1}, nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestReminderService_EmailPreferencesByToken(t *testing.T) {
+	var updates []string
+	current := models.EmailPreferences{ReminderEmails: true, FriendsDigest: true}
+	svc := NewReminderService(emailPreferencesDB(t, time.Now().Add(time.Hour), current, true, &updates), nil, "http://example.com")
+
+	prefs, err := svc.EmailPreferencesByToken(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *prefs != current {
+		t.Fatalf("expected %+v, got %+v", current, *prefs)
+	}
+}
+
+func TestReminderService_EmailPreferencesByToken_Expired(t *testing.T) {
+	var updates []string
+	svc := NewReminderService(emailPreferencesDB(t, time.Now().Add(-time.Minute), models.EmailPreferences{}, true, &updates), nil, "http://example.com")
+
+	if _, err := svc.EmailPreferencesByToken(context.Background(), "tok"); !errors.Is(err, ErrEmailPreferencesExpired) {
+		t.Fatalf("expected ErrEmailPreferencesExpired, got %v", err)
+	}
+	if _, err := svc.UpdateEmailPreferencesByToken(context.Background(), "tok", models.EmailPreferences{}); !errors.Is(err, ErrEmailPreferencesExpired) {
+		t.Fatalf("expected ErrEmailPreferencesExpired, got %v", err)
+	}
+	if len(updates) != 0 {
+		t.Fatalf("expected no updates, got %d", len(updates))
+	}
+}
+
+func TestReminderService_EmailPreferencesByToken_UnknownToken(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	svc := NewReminderService(db, nil, "http://example.com")
+
+	if _, err := svc.EmailPreferencesByToken(context.Background(), "tok"); !errors.Is(err, ErrReminderNotFound) {
+		t.Fatalf("expected ErrReminderNotFound, got %v", err)
+	}
+}
+
+func TestReminderService_UpdateEmailPreferencesByToken(t *testing.T) {
+	t.Run("turning off needs no verified email", func(t *testing.T) {
+		var updates []string
+		current := models.EmailPreferences{ReminderEmails: true, NotificationEmails: true, FriendBingo: true}
+		svc := NewReminderService(emailPreferencesDB(t, time.Now().Add(time.Hour), current, false, &updates), nil, "http://example.com")
+
+		next := models.EmailPreferences{NotificationEmails: true}
+		saved, err := svc.UpdateEmailPreferencesByToken(context.Background(), "tok", next)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *saved != next {
+			t.Fatalf("expected %+v, got %+v", next, *saved)
+		}
+		if len(updates) != 2 {
+			t.Fatalf("expected reminder and notification updates, got %d", len(updates))
+		}
+	})
+
+	t.Run("turning on needs a verified email", func(t *testing.T) {
+		var updates []string
+		svc := NewReminderService(emailPreferencesDB(t, time.Now().Add(time.Hour), models.EmailPreferences{}, false, &updates), nil, "http://example.com")
+
+		_, err := svc.UpdateEmailPreferencesByToken(context.Background(), "tok", models.EmailPreferences{FriendsDigest: true})
+		if !errors.Is(err, ErrEmailNotVerified) {
+			t.Fatalf("expected ErrEmailNotVerified, got %v", err)
+		}
+		if len(updates) != 0 {
+			t.Fatalf("expected no updates, got %d", len(updates))
+		}
+	})
+}
+
+func TestReminderService_UnsubscribeByToken_RejectsPreferencesToken(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(uuid.New(), time.Now().Add(time.Hour), (*time.Time)(nil), unsubscribeScopeEmailPreferences)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatalf("unexpected exec: %s", sql)
+			return nil, nil
+		},
+	}
+	svc := NewReminderService(db, nil, "http://example.com")
+
+	if _, err := svc.UnsubscribeByToken(context.Background(), "tok"); !errors.Is(err, ErrReminderNotFound) {
+		t.Fatalf("expected ErrReminderNotFound, got %v", err)
+	}
+}
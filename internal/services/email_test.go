@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/config"
 )
@@ -524,6 +525,40 @@ func TestEmailService_SendSupportEmail_Success(t *testing.T) {
 	}
 }
 
+func TestEmailService_SendSupportEmail_UsesBrandingSupportAddress(t *testing.T) {
+	provider := &fakeEmailProvider{}
+	service := &EmailService{provider: provider}
+	service.SetBranding(config.BrandingConfig{Name: "Team Goals", SupportEmail: "help@example.com"})
+
+	if err := service.SendSupportEmail(context.Background(), "from@example.com", "Bug", "Help", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.sent[0].To != "help@example.com" {
+		t.Fatalf("unexpected recipient: %s", provider.sent[0].To)
+	}
+	if !strings.Contains(provider.sent[0].Text, "Team Goals Support System") {
+		t.Fatalf("expected branded footer, got %q", provider.sent[0].Text)
+	}
+}
+
+func TestEmailService_SendsFromBrandName(t *testing.T) {
+	provider := &fakeEmailProvider{}
+	service := &EmailService{provider: provider, fromAddress: "goals@example.com"}
+	if err := service.SendSupportEmail(context.Background(), "from@example.com", "Bug", "Help", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.SetBranding(config.BrandingConfig{Name: "Team Goals"})
+	if err := service.SendSupportEmail(context.Background(), "from@example.com", "Bug", "Help", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := provider.sent[0].From; got != `"Year of Bingo" <goals@example.com>` {
+		t.Fatalf("expected the stock name by default, got %q", got)
+	}
+	if got := provider.sent[1].From; got != `"Team Goals" <goals@example.com>` {
+		t.Fatalf("expected the brand name, got %q", got)
+	}
+}
+
 func TestNewEmailService_Providers(t *testing.T) {
 	db := &fakeDB{}
 	cfg := &config.EmailConfig{Provider: "resend"}
@@ -690,3 +725,77 @@ func TestEmailRenderingFunctions(t *testing.T) {
 		}
 	})
 }
+
+func TestEmailService_SendDataExportEmail(t *testing.T) {
+	provider := &fakeEmailProvider{}
+	service := &EmailService{
+		provider: provider,
+		baseURL:  "https://example.com",
+	}
+
+	exportedAt := time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC)
+	if err := service.SendDataExportEmail(context.Background(), "to@example.com", exportedAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(provider.sent) != 1 {
+		t.Fatalf("expected 1 email sent, got %d", len(provider.sent))
+	}
+	sent := provider.sent[0]
+	if sent.To != "to@example.com" || !strings.Contains(sent.Subject, "exported") {
+		t.Fatalf("unexpected email: %+v", sent)
+	}
+	if !strings.Contains(sent.Text, "March 4, 2026 at 15:30 UTC") || !strings.Contains(sent.Text, "https://example.com/support") {
+		t.Fatalf("expected export time and support link in body, got %q", sent.Text)
+	}
+}
+
+func TestEmailService_SendNotificationEmail_EmailFormat(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		row      Row
+		wantHTML bool
+	}{
+		{"text preference", rowFromValues("text"), false},
+		{"html preference", rowFromValues("html"), true},
+		{"no account", fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}, true},
+		{"lookup error", fakeRow{scanFunc: func(dest ...any) error { return errors.New("boom") }}, true},
+	} {
+		provider := &fakeEmailProvider{}
+		db := &fakeDB{QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if args[0] != "to@example.com" {
+				t.Fatalf("%s: expected lookup by recipient, got %v", tc.name, args)
+			}
+			return tc.row
+		}}
+		service := &EmailService{provider: provider, db: db}
+		if err := service.SendNotificationEmail(context.Background(), "to@example.com", "Subject", "<p>Hi</p>", "Hi"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		sent := provider.sent[0]
+		if (sent.HTML != "") != tc.wantHTML || sent.Text != "Hi" {
+			t.Fatalf("%s: expected html=%v with text, got %+v", tc.name, tc.wantHTML, sent)
+		}
+	}
+}
+
+func TestBuildSMTPMessage(t *testing.T) {
+	both := string(buildSMTPMessage(&Email{To: "to@example.com", Subject: "Hi", HTML: "<p>Hello</p>", Text: "Hello"}))
+	if !strings.Contains(both, "Content-Type: multipart/alternative; boundary=") ||
+		!strings.Contains(both, "Content-Type: text/plain; charset=utf-8\r\n\r\nHello") ||
+		!strings.Contains(both, "Content-Type: text/html; charset=utf-8\r\n\r\n<p>Hello</p>") {
+		t.Fatalf("expected text and HTML alternatives, got %q", both)
+	}
+
+	textOnly := string(buildSMTPMessage(&Email{To: "to@example.com", Subject: "Hi", Text: "Hello"}))
+	if !strings.Contains(textOnly, "Content-Type: text/plain; charset=utf-8\r\n\r\nHello") || strings.Contains(textOnly, "multipart") {
+		t.Fatalf("expected a single text part, got %q", textOnly)
+	}
+	if !strings.HasPrefix(textOnly, "From: \"Year of Bingo\" <noreply@yearofbingo.com>\r\n") {
+		t.Fatalf("expected the stock sender without From, got %q", textOnly)
+	}
+
+	branded := string(buildSMTPMessage(&Email{From: `"Team Goals" <goals@example.com>`, To: "to@example.com", Subject: "Hi", Text: "Hello"}))
+	if !strings.HasPrefix(branded, "From: \"Team Goals\" <goals@example.com>\r\n") {
+		t.Fatalf("expected the email's sender, got %q", branded)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
@@ -80,14 +81,18 @@ func (s *FriendService) SearchUsers(ctx context.Context, currentUserID uuid.UUID
 	return results, nil
 }
 
-func (s *FriendService) SendRequest(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
+// SendRequest creates a pending request from userID to friendID. It is
+// idempotent: when a pending or accepted friendship already exists in either
+// direction (including one created by an invite link), that friendship is
+// returned with created set to false and nothing is written.
+func (s *FriendService) SendRequest(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, bool, error) {
 	if userID == friendID {
-		return nil, ErrCannotFriendSelf
+		return nil, false, ErrCannotFriendSelf
 	}
 
-	tx, err := s.db.Begin(ctx)
+	tx, ctx, err := beginTx(ctx, s.db)
 	if err != nil {
-		return nil, fmt.Errorf("begin friend request transaction: %w", err)
+		return nil, false, fmt.Errorf("begin friend request transaction: %w", err)
 	}
 
 	committed := false
@@ -98,7 +103,7 @@ func (s *FriendService) SendRequest(ctx context.Context, userID, friendID uuid.U
 	}()
 
 	if err := lockUserPairForUpdate(ctx, tx, userID, friendID); err != nil {
-		return nil, fmt.Errorf("lock users: %w", err)
+		return nil, false, fmt.Errorf("lock users: %w", err)
 	}
 
 	var isBlocked bool
@@ -111,26 +116,18 @@ func (s *FriendService) SendRequest(ctx context.Context, userID, friendID uuid.U
 		userID, friendID,
 	).Scan(&isBlocked)
 	if err != nil {
-		return nil, fmt.Errorf("checking block status: %w", err)
+		return nil, false, fmt.Errorf("checking block status: %w", err)
 	}
 	if isBlocked {
-		return nil, ErrUserBlocked
+		return nil, false, ErrUserBlocked
 	}
 
-	var exists bool
-	err = tx.QueryRow(ctx,
-		`SELECT EXISTS(
-			SELECT 1 FROM friendships
-			WHERE (user_id = $1 AND friend_id = $2)
-			   OR (user_id = $2 AND friend_id = $1)
-		)`,
-		userID, friendID,
-	).Scan(&exists)
+	existing, err := findFriendshipBetween(ctx, tx, userID, friendID)
 	if err != nil {
-		return nil, fmt.Errorf("checking friendship existence: %w", err)
+		return nil, false, fmt.Errorf("checking friendship existence: %w", err)
 	}
-	if exists {
-		return nil, ErrFriendshipExists
+	if existing != nil {
+		return existing, false, nil
 	}
 
 	friendship := &models.Friendship{}
@@ -141,11 +138,11 @@ func (s *FriendService) SendRequest(ctx context.Context, userID, friendID uuid.U
 		userID, friendID,
 	).Scan(&friendship.ID, &friendship.UserID, &friendship.FriendID, &friendship.Status, &friendship.CreatedAt)
 	if err != nil {
-		return nil, fmt.Errorf("creating friendship: %w", err)
+		return nil, false, fmt.Errorf("creating friendship: %w", err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("commit friend request: %w", err)
+		return nil, false, fmt.Errorf("commit friend request: %w", err)
 	}
 	committed = true
 
@@ -161,6 +158,28 @@ func (s *FriendService) SendRequest(ctx context.Context, userID, friendID uuid.U
 		}
 	}
 
+	return friendship, true, nil
+}
+
+// findFriendshipBetween returns the friendship between two users in either
+// direction, preferring an accepted row, or nil when there is none.
+func findFriendshipBetween(ctx context.Context, q DBConn, userA, userB uuid.UUID) (*models.Friendship, error) {
+	friendship := &models.Friendship{}
+	err := q.QueryRow(ctx,
+		`SELECT id, user_id, friend_id, status, created_at
+		 FROM friendships
+		 WHERE (user_id = $1 AND friend_id = $2)
+		    OR (user_id = $2 AND friend_id = $1)
+		 ORDER BY (status = 'accepted') DESC, created_at
+		 LIMIT 1`,
+		userA, userB,
+	).Scan(&friendship.ID, &friendship.UserID, &friendship.FriendID, &friendship.Status, &friendship.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
 	return friendship, nil
 }
 
@@ -391,6 +410,32 @@ func (s *FriendService) IsFriend(ctx context.Context, userID, otherUserID uuid.U
 	return isFriend, nil
 }
 
+// Relation loads how viewerID stands toward ownerID: whether they are
+// accepted friends and whether either has blocked the other.
+func (s *FriendService) Relation(ctx context.Context, viewerID, ownerID uuid.UUID) (authz.Relation, error) {
+	rel := authz.Relation{ViewerID: viewerID, OwnerID: ownerID}
+	err := s.db.QueryRow(ctx,
+		`SELECT
+			EXISTS(
+				SELECT 1 FROM friendships f
+				JOIN users u1 ON f.user_id = u1.id AND u1.deleted_at IS NULL
+				JOIN users u2 ON f.friend_id = u2.id AND u2.deleted_at IS NULL
+				WHERE ((f.user_id = $1 AND f.friend_id = $2) OR (f.user_id = $2 AND f.friend_id = $1))
+				  AND f.status = 'accepted'
+			),
+			EXISTS(
+				SELECT 1 FROM user_blocks
+				WHERE (blocker_id = $1 AND blocked_id = $2)
+				   OR (blocker_id = $2 AND blocked_id = $1)
+			)`,
+		viewerID, ownerID,
+	).Scan(&rel.Friends, &rel.Blocked)
+	if err != nil {
+		return authz.Relation{}, fmt.Errorf("loading relation: %w", err)
+	}
+	return rel, nil
+}
+
 func (s *FriendService) GetFriendUserID(ctx context.Context, currentUserID, friendshipID uuid.UUID) (uuid.UUID, error) {
 	friendship, err := s.getByID(ctx, friendshipID)
 	if err != nil {
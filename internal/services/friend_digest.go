@@ -0,0 +1,413 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/bingo"
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// friendsDigestWindow is both the activity window a digest covers and the
+// minimum gap between two digests to the same user.
+const friendsDigestWindow = 7 * 24 * time.Hour
+
+// Scopes of reminder_unsubscribe_tokens.
+const (
+	unsubscribeScopeReminders        = "reminders"
+	unsubscribeScopeFriendsDigest    = "friends_digest"
+	unsubscribeScopeEmailPreferences = "email_preferences"
+)
+
+// FriendDigestService sends the opt-in weekly "friends activity" email.
+type FriendDigestService struct {
+	db           DB
+	emailService EmailServiceInterface
+	baseURL      string
+	branding     config.BrandingConfig
+}
+
+func NewFriendDigestService(db DB, emailService EmailServiceInterface, baseURL string) *FriendDigestService {
+	return &FriendDigestService{
+		db:           db,
+		emailService: emailService,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// SetBranding applies the deployment's branding to digest emails.
+func (s *FriendDigestService) SetBranding(branding config.BrandingConfig) {
+	s.branding = branding
+}
+
+// friendActivity sums one friend's visible progress over the digest window.
+type friendActivity struct {
+	Username  string
+	Completed int
+	NewBingos int
+	NewCards  int
+}
+
+type friendsDigestRecipient struct {
+	UserID   uuid.UUID
+	Email    string
+	DailyCap int
+}
+
+// RunDue sends digests to up to limit opted-in users whose last digest is at
+// least a week old and returns how many were sent. Users already at their
+// daily email cap are retried on a later run; users whose friends had no
+// activity are marked done without an email.
+func (s *FriendDigestService) RunDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	if s.emailService == nil {
+		return 0, nil
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT u.id, u.email, COALESCE(rs.daily_email_cap, 3)
+		 FROM notification_settings ns
+		 JOIN users u ON u.id = ns.user_id AND u.deleted_at IS NULL AND u.email_verified = true
+		 LEFT JOIN reminder_settings rs ON rs.user_id = u.id
+		 WHERE ns.email_enabled = true
+		   AND ns.email_friends_digest = true
+		   AND (ns.friends_digest_sent_at IS NULL OR ns.friends_digest_sent_at <= $1)
+		   AND `+emailNotPausedSQL+`
+		 ORDER BY ns.friends_digest_sent_at NULLS FIRST
+		 LIMIT $2`,
+		now.Add(-friendsDigestWindow), limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("load friends digest recipients: %w", err)
+	}
+	var recipients []friendsDigestRecipient
+	for rows.Next() {
+		var r friendsDigestRecipient
+		if err := rows.Scan(&r.UserID, &r.Email, &r.DailyCap); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan friends digest recipient: %w", err)
+		}
+		recipients = append(recipients, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate friends digest recipients: %w", err)
+	}
+
+	sent := 0
+	for _, recipient := range recipients {
+		ok, err := s.sendDigest(ctx, recipient, now)
+		if err != nil {
+			logging.Warn("Friends digest failed", map[string]interface{}{
+				"user_id": recipient.UserID.String(),
+				"error":   err.Error(),
+			})
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (s *FriendDigestService) sendDigest(ctx context.Context, recipient friendsDigestRecipient, now time.Time) (bool, error) {
+	sentOn := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	cap := recipient.DailyCap
+	if cap <= 0 {
+		cap = 3
+	}
+	var sentToday int
+	if err := s.db.QueryRow(ctx,
+		"SELECT COUNT(*) FROM reminder_email_log WHERE user_id = $1 AND status = 'sent' AND sent_on = $2 AND source_type <> 'deliverability_check'",
+		recipient.UserID, sentOn,
+	).Scan(&sentToday); err != nil {
+		return false, fmt.Errorf("check daily email cap: %w", err)
+	}
+	if sentToday >= cap {
+		return false, nil
+	}
+
+	activity, err := s.loadActivity(ctx, recipient.UserID, now.Add(-friendsDigestWindow))
+	if err != nil {
+		return false, err
+	}
+	if len(activity) == 0 {
+		return false, s.markSent(ctx, recipient.UserID, now)
+	}
+
+	unsubscribeURL, err := s.createUnsubscribeURL(ctx, recipient.UserID, now)
+	if err != nil {
+		return false, err
+	}
+	preferencesURL := createEmailPreferencesURL(ctx, s.db, s.baseURL, recipient.UserID, now)
+	subject, html, text := buildFriendsDigestEmail(activity, s.baseURL, unsubscribeURL, preferencesURL, s.branding)
+
+	status := "sent"
+	sendErr := s.emailService.SendNotificationEmail(ctx, recipient.Email, subject, html, text)
+	if sendErr != nil {
+		status = "failed"
+	}
+	if _, err := s.db.Exec(ctx,
+		"INSERT INTO reminder_email_log (user_id, source_type, source_id, status, sent_at, sent_on) VALUES ($1, 'friends_digest', $1, $2, $3, $4)",
+		recipient.UserID, status, now, sentOn,
+	); err != nil {
+		return false, fmt.Errorf("log friends digest: %w", err)
+	}
+	// A failed send still counts as this week's digest so a bad address isn't retried every run.
+	if err := s.markSent(ctx, recipient.UserID, now); err != nil {
+		return false, err
+	}
+	if sendErr != nil {
+		return false, fmt.Errorf("send friends digest: %w", sendErr)
+	}
+	return true, nil
+}
+
+// loadActivity reads every visible, finalized card of the user's unblocked
+// friends that was created or had a goal completed since the window start, in
+// one query, and sums completions, new bingos and new cards per friend.
+func (s *FriendDigestService) loadActivity(ctx context.Context, userID uuid.UUID, since time.Time) ([]friendActivity, error) {
+	rows, err := s.db.Query(ctx,
+		`WITH friends AS (
+		   SELECT CASE WHEN user_id = $1 THEN friend_id ELSE user_id END AS friend_id
+		   FROM friendships
+		   WHERE status = 'accepted' AND (user_id = $1 OR friend_id = $1)
+		 )
+		 SELECT c.user_id, u.username, c.id, c.grid_size,
+		        CASE WHEN c.has_free_space THEN c.free_space_position END,
+		        c.created_at, i.position, COALESCE(i.is_completed, false), i.completed_at
+		 FROM friends fr
+		 JOIN bingo_cards c ON c.user_id = fr.friend_id
+		 JOIN users u ON u.id = c.user_id AND u.deleted_at IS NULL
+		 LEFT JOIN bingo_items i ON i.card_id = c.id
+		 WHERE c.is_finalized = true AND c.visible_to_friends = true AND c.is_archived = false
+		   AND NOT EXISTS (
+		     SELECT 1 FROM user_blocks
+		     WHERE (blocker_id = $1 AND blocked_id = c.user_id)
+		        OR (blocker_id = c.user_id AND blocked_id = $1)
+		   )
+		   AND (c.created_at >= $2 OR EXISTS (
+		     SELECT 1 FROM bingo_items x WHERE x.card_id = c.id AND x.completed_at >= $2
+		   ))
+		 ORDER BY u.username, c.id, i.position`,
+		userID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("load friends activity: %w", err)
+	}
+	defer rows.Close()
+
+	type cardState struct {
+		friendID  uuid.UUID
+		gridSize  int
+		freePos   *int
+		isNew     bool
+		completed int
+		before    []models.BingoItem
+		current   []models.BingoItem
+	}
+	var (
+		order     []uuid.UUID
+		usernames = make(map[uuid.UUID]string)
+		cards     = make(map[uuid.UUID]*cardState)
+		cardOrder []uuid.UUID
+	)
+	for rows.Next() {
+		var (
+			friendID    uuid.UUID
+			username    string
+			cardID      uuid.UUID
+			gridSize    int
+			freePos     *int
+			createdAt   time.Time
+			position    *int
+			completed   bool
+			completedAt *time.Time
+		)
+		if err := rows.Scan(&friendID, &username, &cardID, &gridSize, &freePos, &createdAt, &position, &completed, &completedAt); err != nil {
+			return nil, fmt.Errorf("scan friends activity: %w", err)
+		}
+		if _, ok := usernames[friendID]; !ok {
+			usernames[friendID] = username
+			order = append(order, friendID)
+		}
+		card, ok := cards[cardID]
+		if !ok {
+			card = &cardState{friendID: friendID, gridSize: gridSize, freePos: freePos, isNew: !createdAt.Before(since)}
+			cards[cardID] = card
+			cardOrder = append(cardOrder, cardID)
+		}
+		if position == nil || !completed {
+			continue
+		}
+		card.current = append(card.current, models.BingoItem{Position: *position, IsCompleted: true})
+		if completedAt != nil && !completedAt.Before(since) {
+			card.completed++
+		} else {
+			card.before = append(card.before, models.BingoItem{Position: *position, IsCompleted: true})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate friends activity: %w", err)
+	}
+
+	totals := make(map[uuid.UUID]*friendActivity, len(order))
+	for _, id := range order {
+		totals[id] = &friendActivity{Username: usernames[id]}
+	}
+	for _, cardID := range cardOrder {
+		card := cards[cardID]
+		total := totals[card.friendID]
+		total.Completed += card.completed
+		if card.isNew {
+			total.NewCards++
+		}
+		if gained := bingo.CountBingos(card.current, card.gridSize, card.freePos) - bingo.CountBingos(card.before, card.gridSize, card.freePos); gained > 0 {
+			total.NewBingos += gained
+		}
+	}
+
+	activity := make([]friendActivity, 0, len(order))
+	for _, id := range order {
+		total := totals[id]
+		if total.Completed == 0 && total.NewBingos == 0 && total.NewCards == 0 {
+			continue
+		}
+		activity = append(activity, *total)
+	}
+	return activity, nil
+}
+
+func (s *FriendDigestService) markSent(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	if _, err := s.db.Exec(ctx,
+		"UPDATE notification_settings SET friends_digest_sent_at = $2 WHERE user_id = $1",
+		userID, now,
+	); err != nil {
+		return fmt.Errorf("mark friends digest sent: %w", err)
+	}
+	return nil
+}
+
+func (s *FriendDigestService) createUnsubscribeURL(ctx context.Context, userID uuid.UUID, now time.Time) (string, error) {
+	token, err := randomToken(24)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.db.Exec(ctx,
+		"INSERT INTO reminder_unsubscribe_tokens (token, user_id, expires_at, scope) VALUES ($1, $2, $3, $4)",
+		token, userID, now.Add(30*24*time.Hour), unsubscribeScopeFriendsDigest,
+	); err != nil {
+		return "", fmt.Errorf("create unsubscribe token: %w", err)
+	}
+	return fmt.Sprintf("%s/r/unsubscribe?token=%s&list=%s", s.baseURL, token, unsubscribeScopeFriendsDigest), nil
+}
+
+func buildFriendsDigestEmail(activity []friendActivity, baseURL, unsubscribeURL, preferencesURL string, brandCfg config.BrandingConfig) (string, string, string) {
+	friendsURL := baseURL + "/friends"
+	manageURL := baseURL + "/profile"
+	safeFriendsURL := templateEscape(friendsURL)
+	safeManageURL := templateEscape(manageURL)
+	safeUnsubscribe := templateEscape(unsubscribeURL)
+	preferencesHTML, preferencesText := emailPreferencesLines(preferencesURL)
+
+	brand := emailBrand(brandCfg)
+	subject := sanitizeSubject("Your friends' week on " + brand.name())
+
+	htmlItems := make([]string, 0, len(activity))
+	textItems := make([]string, 0, len(activity))
+	for _, a := range activity {
+		summary := friendActivitySummary(a)
+		htmlItems = append(htmlItems, fmt.Sprintf("<li><strong>%s</strong> %s</li>", templateEscape(a.Username), templateEscape(summary)))
+		textItems = append(textItems, fmt.Sprintf("- %s %s", isolateBidi(a.Username), summary))
+	}
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 640px; margin: 0 auto; padding: 24px;">
+  %s<h1 style="color: #333; font-size: 24px;">%s</h1>
+  <p style="font-size: 18px;">Here's what your friends got up to this week:</p>
+  <ul style="padding-left: 20px;">%s</ul>
+  <p>
+    <a href="%s" style="display: inline-block; background: %s; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">See their cards</a>
+  </p>
+  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
+  <p style="color: #666; font-size: 14px;">Manage email settings: <a href="%s">%s</a></p>
+  %s<p style="color: #666; font-size: 14px;">Unsubscribe from this digest: <a href="%s">%s</a></p>
+  <p style="color: #999; font-size: 12px;">%s</p>
+</body>
+</html>`,
+		brand.logoHTML(),
+		templateEscape(brand.name()),
+		strings.Join(htmlItems, ""),
+		safeFriendsURL,
+		brand.accent(reminderEmailAccent),
+		safeManageURL,
+		safeManageURL,
+		preferencesHTML,
+		safeUnsubscribe,
+		safeUnsubscribe,
+		brand.footerHTML(),
+	)
+
+	text := fmt.Sprintf(`Here's what your friends got up to this week:
+
+%s
+
+See their cards: %s
+
+Manage email settings: %s
+%sUnsubscribe from this digest: %s
+
+--
+%s`,
+		strings.Join(textItems, "\n"),
+		friendsURL,
+		manageURL,
+		preferencesText,
+		unsubscribeURL,
+		brand.footerText(),
+	)
+
+	return subject, html, text
+}
+
+// friendActivitySummary describes one friend's week, e.g.
+// "completed 3 goals, got 1 new bingo and started a new card".
+func friendActivitySummary(a friendActivity) string {
+	var parts []string
+	if a.Completed > 0 {
+		if a.Completed == 1 {
+			parts = append(parts, "completed 1 goal")
+		} else {
+			parts = append(parts, fmt.Sprintf("completed %d goals", a.Completed))
+		}
+	}
+	if a.NewBingos > 0 {
+		if a.NewBingos == 1 {
+			parts = append(parts, "got 1 new bingo")
+		} else {
+			parts = append(parts, fmt.Sprintf("got %d new bingos", a.NewBingos))
+		}
+	}
+	if a.NewCards > 0 {
+		if a.NewCards == 1 {
+			parts = append(parts, "started a new card")
+		} else {
+			parts = append(parts, fmt.Sprintf("started %d new cards", a.NewCards))
+		}
+	}
+	if len(parts) <= 1 {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
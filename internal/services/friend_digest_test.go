@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestFriendDigestService_LoadActivity_SumsPerFriend(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-friendsDigestWindow)
+	recent := now.Add(-24 * time.Hour)
+	old := now.Add(-30 * 24 * time.Hour)
+
+	alice := uuid.New()
+	bob := uuid.New()
+	aliceCard := uuid.New()
+	bobCard := uuid.New()
+	freePos := intPtr(12)
+
+	// Alice's 5x5 card: row 0 positions 0-3 were completed before the window,
+	// position 4 this week, completing the row.
+	var rows [][]any
+	for pos := 0; pos < 4; pos++ {
+		completedAt := old
+		rows = append(rows, []any{alice, "alice", aliceCard, 5, freePos, old, intPtr(pos), true, &completedAt})
+	}
+	rows = append(rows,
+		[]any{alice, "alice", aliceCard, 5, freePos, old, intPtr(4), true, &recent},
+		[]any{alice, "alice", aliceCard, 5, freePos, old, intPtr(5), false, (*time.Time)(nil)},
+		// Bob started a card this week and hasn't completed anything.
+		[]any{bob, "bob", bobCard, 5, freePos, recent, intPtr(0), false, (*time.Time)(nil)},
+	)
+
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "user_blocks") {
+				t.Fatalf("expected blocked friends to be excluded, got %q", sql)
+			}
+			if !strings.Contains(sql, "visible_to_friends = true") {
+				t.Fatalf("expected only visible cards, got %q", sql)
+			}
+			gotArgs = args
+			return &fakeRows{rows: rows}, nil
+		},
+	}
+
+	svc := NewFriendDigestService(db, nil, "https://example.com")
+	activity, err := svc.loadActivity(context.Background(), uuid.New(), since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gotArgs) != 2 || gotArgs[1] != since {
+		t.Fatalf("expected window start arg, got %v", gotArgs)
+	}
+	if len(activity) != 2 {
+		t.Fatalf("expected 2 friends, got %+v", activity)
+	}
+	if got := activity[0]; got.Username != "alice" || got.Completed != 1 || got.NewBingos != 1 || got.NewCards != 0 {
+		t.Fatalf("unexpected alice activity: %+v", got)
+	}
+	if got := activity[1]; got.Username != "bob" || got.Completed != 0 || got.NewBingos != 0 || got.NewCards != 1 {
+		t.Fatalf("unexpected bob activity: %+v", got)
+	}
+}
+
+func TestFriendDigestService_RunDue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	friendID := uuid.New()
+	cardID := uuid.New()
+	recent := now.Add(-time.Hour)
+
+	newDB := func(sentToday int, activity [][]any, execs *[]string) *fakeDB {
+		return &fakeDB{
+			QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+				if strings.Contains(sql, "FROM notification_settings ns") {
+					return &fakeRows{rows: [][]any{{userID, "user@example.com", 3}}}, nil
+				}
+				return &fakeRows{rows: activity}, nil
+			},
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				return rowFromValues(sentToday)
+			},
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+				*execs = append(*execs, sql)
+				return fakeCommandTag{rowsAffected: 1}, nil
+			},
+		}
+	}
+	withActivity := [][]any{{friendID, "alice", cardID, 3, (*int)(nil), now.Add(-60 * 24 * time.Hour), intPtr(0), true, &recent}}
+
+	t.Run("sends digest with scoped unsubscribe link", func(t *testing.T) {
+		var execs []string
+		var text string
+		email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, body string) error {
+			text = body
+			return nil
+		}}
+		svc := NewFriendDigestService(newDB(0, withActivity, &execs), email, "https://example.com/")
+		sent, err := svc.RunDue(context.Background(), now, 50)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sent != 1 {
+			t.Fatalf("expected 1 sent, got %d", sent)
+		}
+		if !strings.Contains(text, "alice completed 1 goal") {
+			t.Fatalf("expected friend summary, got %q", text)
+		}
+		if !strings.Contains(text, "https://example.com/r/unsubscribe?token=") || !strings.Contains(text, "list=friends_digest") {
+			t.Fatalf("expected digest unsubscribe link, got %q", text)
+		}
+		joined := strings.Join(execs, "\n")
+		for _, want := range []string{"reminder_unsubscribe_tokens", "'friends_digest'", "friends_digest_sent_at"} {
+			if !strings.Contains(joined, want) {
+				t.Fatalf("expected exec containing %q, got %v", want, execs)
+			}
+		}
+	})
+
+	t.Run("no activity marks sent without email", func(t *testing.T) {
+		var execs []string
+		email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, body string) error {
+			t.Fatal("expected no email")
+			return nil
+		}}
+		svc := NewFriendDigestService(newDB(0, nil, &execs), email, "https://example.com")
+		sent, err := svc.RunDue(context.Background(), now, 50)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sent != 0 {
+			t.Fatalf("expected 0 sent, got %d", sent)
+		}
+		if len(execs) != 1 || !strings.Contains(execs[0], "friends_digest_sent_at") {
+			t.Fatalf("expected only mark-sent exec, got %v", execs)
+		}
+	})
+
+	t.Run("daily cap reached skips without marking", func(t *testing.T) {
+		var execs []string
+		email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, body string) error {
+			t.Fatal("expected no email")
+			return nil
+		}}
+		svc := NewFriendDigestService(newDB(3, withActivity, &execs), email, "https://example.com")
+		sent, err := svc.RunDue(context.Background(), now, 50)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sent != 0 || len(execs) != 0 {
+			t.Fatalf("expected skip, got sent=%d execs=%v", sent, execs)
+		}
+	})
+
+	t.Run("failed send is logged and marked", func(t *testing.T) {
+		var execs []string
+		email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, body string) error {
+			return errors.New("boom")
+		}}
+		svc := NewFriendDigestService(newDB(0, withActivity, &execs), email, "https://example.com")
+		sent, err := svc.RunDue(context.Background(), now, 50)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sent != 0 {
+			t.Fatalf("expected 0 sent, got %d", sent)
+		}
+		if !strings.Contains(strings.Join(execs, "\n"), "friends_digest_sent_at") {
+			t.Fatalf("expected digest marked sent after failure, got %v", execs)
+		}
+	})
+}
+
+func TestFriendActivitySummary(t *testing.T) {
+	tests := []struct {
+		in   friendActivity
+		want string
+	}{
+		{friendActivity{Completed: 1}, "completed 1 goal"},
+		{friendActivity{Completed: 3, NewBingos: 1}, "completed 3 goals and got 1 new bingo"},
+		{friendActivity{Completed: 2, NewBingos: 2, NewCards: 1}, "completed 2 goals, got 2 new bingos and started a new card"},
+		{friendActivity{NewCards: 2}, "started 2 new cards"},
+	}
+	for _, tt := range tests {
+		if got := friendActivitySummary(tt.in); got != tt.want {
+			t.Errorf("friendActivitySummary(%+v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBuildFriendsDigestEmail_EscapesUsernames(t *testing.T) {
+	_, html, _ := buildFriendsDigestEmail([]friendActivity{{Username: "<b>eve</b>", Completed: 1}}, "https://example.com", "https://example.com/r/unsubscribe?token=t&list=friends_digest", "", config.BrandingConfig{})
+	if strings.Contains(html, "<b>eve</b>") {
+		t.Fatalf("expected username to be escaped, got %q", html)
+	}
+	if !strings.Contains(html, "https://example.com/friends") {
+		t.Fatalf("expected friends link, got %q", html)
+	}
+}
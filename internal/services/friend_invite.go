@@ -143,10 +143,14 @@ func (s *FriendInviteService) RevokeInvite(ctx context.Context, inviterID, invit
 	return nil
 }
 
+// AcceptInvite makes the recipient and the inviter friends and consumes the
+// invite. A pending request between them in either direction is accepted in
+// place. If they are already friends the call succeeds without changes and
+// the invite stays unused.
 func (s *FriendInviteService) AcceptInvite(ctx context.Context, recipientID uuid.UUID, token string) (*models.UserSearchResult, error) {
 	tokenHash := hashInviteToken(token)
 
-	tx, err := s.db.Begin(ctx)
+	tx, ctx, err := beginTx(ctx, s.db)
 	if err != nil {
 		return nil, fmt.Errorf("begin invite accept transaction: %w", err)
 	}
@@ -203,31 +207,37 @@ func (s *FriendInviteService) AcceptInvite(ctx context.Context, recipientID uuid
 		return nil, ErrUserBlocked
 	}
 
-	var exists bool
-	err = tx.QueryRow(ctx,
-		`SELECT EXISTS(
-			SELECT 1 FROM friendships
-			WHERE (user_id = $1 AND friend_id = $2)
-			   OR (user_id = $2 AND friend_id = $1)
-		)`,
-		inviterID, recipientID,
-	).Scan(&exists)
+	inviter := &models.UserSearchResult{ID: inviterID, Username: inviterUsername}
+	existing, err := findFriendshipBetween(ctx, tx, inviterID, recipientID)
 	if err != nil {
 		return nil, fmt.Errorf("check friendship: %w", err)
 	}
-	if exists {
-		return nil, ErrFriendshipExists
+	if existing != nil && existing.Status == models.FriendshipStatusAccepted {
+		// Already friends: leave the invite unused so it still works for someone else.
+		return inviter, nil
 	}
 
 	var friendshipID uuid.UUID
-	err = tx.QueryRow(ctx,
-		`INSERT INTO friendships (user_id, friend_id, status)
-		 VALUES ($1, $2, 'accepted')
-		 RETURNING id`,
-		inviterID, recipientID,
-	).Scan(&friendshipID)
-	if err != nil {
-		return nil, fmt.Errorf("insert friendship: %w", err)
+	if existing != nil {
+		// The invite confirms a pending request from either side.
+		friendshipID = existing.ID
+		_, err = tx.Exec(ctx,
+			"UPDATE friendships SET status = 'accepted' WHERE id = $1",
+			friendshipID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("accept pending friendship: %w", err)
+		}
+	} else {
+		err = tx.QueryRow(ctx,
+			`INSERT INTO friendships (user_id, friend_id, status)
+			 VALUES ($1, $2, 'accepted')
+			 RETURNING id`,
+			inviterID, recipientID,
+		).Scan(&friendshipID)
+		if err != nil {
+			return nil, fmt.Errorf("insert friendship: %w", err)
+		}
 	}
 
 	_, err = tx.Exec(ctx,
@@ -256,7 +266,7 @@ func (s *FriendInviteService) AcceptInvite(ctx context.Context, recipientID uuid
 		}
 	}
 
-	return &models.UserSearchResult{ID: inviterID, Username: inviterUsername}, nil
+	return inviter, nil
 }
 
 func generateInviteToken() (string, error) {
@@ -224,7 +224,7 @@ func TestFriendInviteService_AcceptInvite_Success(t *testing.T) {
 				return rowFromValues(false)
 			}
 			if strings.Contains(sql, "FROM friendships") {
-				return rowFromValues(false)
+				return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
 			}
 			if strings.Contains(sql, "INSERT INTO friendships") {
 				return rowFromValues(friendshipID)
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// friendGraph is an in-memory stand-in for the friendships and friend_invites
+// tables, shared by FriendService and FriendInviteService so their SQL paths
+// can be exercised against each other.
+type friendGraph struct {
+	t           *testing.T
+	friendships []models.Friendship
+	inviteID    uuid.UUID
+	inviterID   uuid.UUID
+	inviteHash  string
+	acceptedBy  *uuid.UUID
+}
+
+func (g *friendGraph) db() *fakeDB {
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM friend_invites"):
+				if args[0] != g.inviteHash || g.acceptedBy != nil {
+					return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+				}
+				return rowFromValues(g.inviteID, g.inviterID, "inviter")
+			case strings.Contains(sql, "FROM users") && strings.Contains(sql, "FOR UPDATE"):
+				return rowFromValues(args[0])
+			case strings.Contains(sql, "FROM user_blocks"):
+				return rowFromValues(false)
+			case strings.Contains(sql, "FROM friendships") && strings.Contains(sql, "LIMIT 1"):
+				if f := g.between(args[0].(uuid.UUID), args[1].(uuid.UUID)); f != nil {
+					return rowFromValues(f.ID, f.UserID, f.FriendID, f.Status, f.CreatedAt)
+				}
+				return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+			case strings.Contains(sql, "INSERT INTO friendships") && strings.Contains(sql, "'pending'"):
+				f := g.insert(args[0].(uuid.UUID), args[1].(uuid.UUID), models.FriendshipStatusPending)
+				return rowFromValues(f.ID, f.UserID, f.FriendID, f.Status, f.CreatedAt)
+			case strings.Contains(sql, "INSERT INTO friendships") && strings.Contains(sql, "'accepted'"):
+				f := g.insert(args[0].(uuid.UUID), args[1].(uuid.UUID), models.FriendshipStatusAccepted)
+				return rowFromValues(f.ID)
+			}
+			g.t.Fatalf("unexpected sql: %q", sql)
+			return rowFromValues()
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			switch {
+			case strings.Contains(sql, "UPDATE friendships SET status = 'accepted'"):
+				for i := range g.friendships {
+					if g.friendships[i].ID == args[0] {
+						g.friendships[i].Status = models.FriendshipStatusAccepted
+					}
+				}
+			case strings.Contains(sql, "UPDATE friend_invites"):
+				recipientID := args[0].(uuid.UUID)
+				g.acceptedBy = &recipientID
+			default:
+				g.t.Fatalf("unexpected exec: %q", sql)
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	return &fakeDB{
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			return tx, nil
+		},
+	}
+}
+
+func (g *friendGraph) between(a, b uuid.UUID) *models.Friendship {
+	var found *models.Friendship
+	for i := range g.friendships {
+		f := &g.friendships[i]
+		if (f.UserID == a && f.FriendID == b) || (f.UserID == b && f.FriendID == a) {
+			if found == nil || f.Status == models.FriendshipStatusAccepted {
+				found = f
+			}
+		}
+	}
+	return found
+}
+
+func (g *friendGraph) insert(userID, friendID uuid.UUID, status models.FriendshipStatus) models.Friendship {
+	f := models.Friendship{ID: uuid.New(), UserID: userID, FriendID: friendID, Status: status, CreatedAt: time.Now()}
+	g.friendships = append(g.friendships, f)
+	return f
+}
+
+func TestFriendProvenance_AllOrderingsConvergeToOneFriendship(t *testing.T) {
+	type step struct {
+		name string
+		run  func(t *testing.T, friends *FriendService, invites *FriendInviteService, inviter, invitee uuid.UUID)
+	}
+	const token = "invite-token"
+	inviterRequests := step{"inviter requests", func(t *testing.T, friends *FriendService, _ *FriendInviteService, inviter, invitee uuid.UUID) {
+		if _, _, err := friends.SendRequest(context.Background(), inviter, invitee); err != nil {
+			t.Fatalf("inviter request: %v", err)
+		}
+	}}
+	inviteeRequests := step{"invitee requests", func(t *testing.T, friends *FriendService, _ *FriendInviteService, inviter, invitee uuid.UUID) {
+		if _, _, err := friends.SendRequest(context.Background(), invitee, inviter); err != nil {
+			t.Fatalf("invitee request: %v", err)
+		}
+	}}
+	inviteeAccepts := step{"invitee accepts invite", func(t *testing.T, _ *FriendService, invites *FriendInviteService, inviter, invitee uuid.UUID) {
+		result, err := invites.AcceptInvite(context.Background(), invitee, token)
+		if err != nil {
+			t.Fatalf("accept invite: %v", err)
+		}
+		if result.ID != inviter {
+			t.Fatalf("expected inviter %v, got %v", inviter, result.ID)
+		}
+	}}
+
+	orderings := [][]step{
+		{inviterRequests, inviteeRequests, inviteeAccepts},
+		{inviterRequests, inviteeAccepts, inviteeRequests},
+		{inviteeRequests, inviterRequests, inviteeAccepts},
+		{inviteeRequests, inviteeAccepts, inviterRequests},
+		{inviteeAccepts, inviterRequests, inviteeRequests},
+		{inviteeAccepts, inviteeRequests, inviterRequests},
+	}
+
+	for _, ordering := range orderings {
+		names := make([]string, len(ordering))
+		for i, s := range ordering {
+			names[i] = s.name
+		}
+		t.Run(strings.Join(names, ", then "), func(t *testing.T) {
+			inviter := uuid.New()
+			invitee := uuid.New()
+			graph := &friendGraph{t: t, inviteID: uuid.New(), inviterID: inviter, inviteHash: hashInviteToken(token)}
+			db := graph.db()
+
+			var requestNotifications, acceptNotifications int
+			notifier := &stubNotificationService{
+				NotifyFriendRequestReceivedFunc: func(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error {
+					requestNotifications++
+					return nil
+				},
+				NotifyFriendRequestAcceptedFunc: func(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error {
+					acceptNotifications++
+					return nil
+				},
+			}
+			friends := NewFriendService(db)
+			friends.SetNotificationService(notifier)
+			invites := NewFriendInviteService(db)
+			invites.SetNotificationService(notifier)
+
+			for _, s := range ordering {
+				s.run(t, friends, invites, inviter, invitee)
+			}
+
+			if len(graph.friendships) != 1 {
+				t.Fatalf("expected exactly one friendship, got %d: %+v", len(graph.friendships), graph.friendships)
+			}
+			if graph.friendships[0].Status != models.FriendshipStatusAccepted {
+				t.Fatalf("expected accepted friendship, got %s", graph.friendships[0].Status)
+			}
+			if graph.acceptedBy == nil || *graph.acceptedBy != invitee {
+				t.Fatalf("expected invite consumed by invitee, got %v", graph.acceptedBy)
+			}
+			if acceptNotifications != 1 {
+				t.Fatalf("expected one acceptance notification, got %d", acceptNotifications)
+			}
+			if requestNotifications > 1 {
+				t.Fatalf("expected at most one request notification, got %d", requestNotifications)
+			}
+
+			// Repeating any step afterwards is a no-op.
+			friendship, created, err := friends.SendRequest(context.Background(), invitee, inviter)
+			if err != nil || created || friendship.Status != models.FriendshipStatusAccepted {
+				t.Fatalf("expected existing accepted friendship, got %+v created=%v err=%v", friendship, created, err)
+			}
+			if len(graph.friendships) != 1 {
+				t.Fatalf("expected no new friendship rows, got %d", len(graph.friendships))
+			}
+		})
+	}
+}
+
+func TestFriendInviteService_AcceptInvite_AlreadyFriendsLeavesInviteUnused(t *testing.T) {
+	inviter := uuid.New()
+	invitee := uuid.New()
+	graph := &friendGraph{t: t, inviteID: uuid.New(), inviterID: inviter, inviteHash: hashInviteToken("token")}
+	graph.insert(invitee, inviter, models.FriendshipStatusAccepted)
+
+	svc := NewFriendInviteService(graph.db())
+	svc.SetNotificationService(&stubNotificationService{
+		NotifyFriendRequestAcceptedFunc: func(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error {
+			t.Fatal("expected no notification for an existing friendship")
+			return nil
+		},
+	})
+
+	result, err := svc.AcceptInvite(context.Background(), invitee, "token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != inviter || result.Username != "inviter" {
+		t.Fatalf("unexpected inviter: %+v", result)
+	}
+	if graph.acceptedBy != nil {
+		t.Fatal("expected invite to stay unused")
+	}
+	if len(graph.friendships) != 1 {
+		t.Fatalf("expected no new friendship rows, got %d", len(graph.friendships))
+	}
+}
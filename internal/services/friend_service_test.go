@@ -85,7 +85,7 @@ func TestFriendService_SearchUsers_ScanError(t *testing.T) {
 func TestFriendService_SendRequest_Self(t *testing.T) {
 	svc := &FriendService{}
 	userID := uuid.New()
-	_, err := svc.SendRequest(context.Background(), userID, userID)
+	_, _, err := svc.SendRequest(context.Background(), userID, userID)
 	if !errors.Is(err, ErrCannotFriendSelf) {
 		t.Fatalf("expected ErrCannotFriendSelf, got %v", err)
 	}
@@ -94,6 +94,7 @@ func TestFriendService_SendRequest_Self(t *testing.T) {
 func TestFriendService_SendRequest_AlreadyExists(t *testing.T) {
 	userID := uuid.New()
 	friendID := uuid.New()
+	existingID := uuid.New()
 	var rolledBack bool
 	tx := &fakeTx{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
@@ -103,8 +104,8 @@ func TestFriendService_SendRequest_AlreadyExists(t *testing.T) {
 			if strings.Contains(sql, "FROM user_blocks") {
 				return rowFromValues(false)
 			}
-			if strings.Contains(sql, "SELECT EXISTS") && strings.Contains(sql, "FROM friendships") {
-				return rowFromValues(true)
+			if strings.Contains(sql, "FROM friendships") && strings.Contains(sql, "LIMIT 1") {
+				return rowFromValues(friendshipRowValues(existingID, friendID, userID, models.FriendshipStatusAccepted)...)
 			}
 			t.Fatalf("unexpected sql: %q", sql)
 			return rowFromValues()
@@ -121,9 +122,15 @@ func TestFriendService_SendRequest_AlreadyExists(t *testing.T) {
 	}
 
 	svc := NewFriendService(db)
-	_, err := svc.SendRequest(context.Background(), userID, friendID)
-	if !errors.Is(err, ErrFriendshipExists) {
-		t.Fatalf("expected ErrFriendshipExists, got %v", err)
+	friendship, created, err := svc.SendRequest(context.Background(), userID, friendID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created {
+		t.Fatal("expected existing friendship, not a new request")
+	}
+	if friendship.ID != existingID || friendship.Status != models.FriendshipStatusAccepted {
+		t.Fatalf("unexpected friendship: %+v", friendship)
 	}
 	if !rolledBack {
 		t.Fatal("expected rollback")
@@ -157,7 +164,7 @@ func TestFriendService_SendRequest_Blocked(t *testing.T) {
 	}
 
 	svc := NewFriendService(db)
-	_, err := svc.SendRequest(context.Background(), userID, friendID)
+	_, _, err := svc.SendRequest(context.Background(), userID, friendID)
 	if !errors.Is(err, ErrUserBlocked) {
 		t.Fatalf("expected ErrUserBlocked, got %v", err)
 	}
@@ -196,7 +203,7 @@ func TestFriendService_SendRequest_ExistenceError(t *testing.T) {
 	}
 
 	svc := NewFriendService(db)
-	_, err := svc.SendRequest(context.Background(), uuid.New(), uuid.New())
+	_, _, err := svc.SendRequest(context.Background(), uuid.New(), uuid.New())
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -219,8 +226,8 @@ func TestFriendService_SendRequest_Success(t *testing.T) {
 			if strings.Contains(sql, "FROM user_blocks") {
 				return rowFromValues(false)
 			}
-			if strings.Contains(sql, "SELECT EXISTS") && strings.Contains(sql, "FROM friendships") {
-				return rowFromValues(false)
+			if strings.Contains(sql, "FROM friendships") && strings.Contains(sql, "LIMIT 1") {
+				return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
 			}
 			if strings.Contains(sql, "INSERT INTO friendships") {
 				if len(args) != 2 || args[0] != userID || args[1] != friendID {
@@ -252,10 +259,13 @@ func TestFriendService_SendRequest_Success(t *testing.T) {
 			return nil
 		},
 	})
-	friendship, err := svc.SendRequest(context.Background(), userID, friendID)
+	friendship, created, err := svc.SendRequest(context.Background(), userID, friendID)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if !created {
+		t.Fatal("expected a new request")
+	}
 	if friendship.ID != friendshipID {
 		t.Fatalf("expected friendship %v, got %v", friendshipID, friendship.ID)
 	}
@@ -279,8 +289,8 @@ func TestFriendService_SendRequest_InsertError(t *testing.T) {
 			if strings.Contains(sql, "FROM user_blocks") {
 				return rowFromValues(false)
 			}
-			if strings.Contains(sql, "FROM friendships") && strings.Contains(sql, "SELECT EXISTS") {
-				return rowFromValues(false)
+			if strings.Contains(sql, "FROM friendships") && strings.Contains(sql, "LIMIT 1") {
+				return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
 			}
 			if strings.Contains(sql, "INSERT INTO friendships") {
 				return fakeRow{scanFunc: func(dest ...any) error {
@@ -302,7 +312,7 @@ func TestFriendService_SendRequest_InsertError(t *testing.T) {
 	}
 
 	svc := NewFriendService(db)
-	_, err := svc.SendRequest(context.Background(), userID, friendID)
+	_, _, err := svc.SendRequest(context.Background(), userID, friendID)
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -324,8 +334,8 @@ func TestFriendService_SendRequest_CommitError(t *testing.T) {
 			if strings.Contains(sql, "FROM user_blocks") {
 				return rowFromValues(false)
 			}
-			if strings.Contains(sql, "FROM friendships") && strings.Contains(sql, "SELECT EXISTS") {
-				return rowFromValues(false)
+			if strings.Contains(sql, "FROM friendships") && strings.Contains(sql, "LIMIT 1") {
+				return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
 			}
 			if strings.Contains(sql, "INSERT INTO friendships") {
 				return rowFromValues(friendshipRowValues(uuid.New(), userID, friendID, models.FriendshipStatusPending)...)
@@ -348,7 +358,7 @@ func TestFriendService_SendRequest_CommitError(t *testing.T) {
 	}
 
 	svc := NewFriendService(db)
-	_, err := svc.SendRequest(context.Background(), userID, friendID)
+	_, _, err := svc.SendRequest(context.Background(), userID, friendID)
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -695,6 +705,30 @@ func TestFriendService_IsFriend_Error(t *testing.T) {
 	}
 }
 
+func TestFriendService_Relation_BlockedFormerFriend(t *testing.T) {
+	viewerID := uuid.New()
+	ownerID := uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "user_blocks") {
+				t.Fatalf("expected block state in the query: %s", sql)
+			}
+			return rowFromValues(true, true)
+		},
+	}
+
+	rel, err := NewFriendService(db).Relation(context.Background(), viewerID, ownerID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rel.ViewerID != viewerID || rel.OwnerID != ownerID || !rel.Friends || !rel.Blocked {
+		t.Fatalf("unexpected relation: %+v", rel)
+	}
+	if rel.IsFriend() {
+		t.Fatal("expected a block to override the friendship")
+	}
+}
+
 func TestFriendService_GetFriendUserID_NotParticipant(t *testing.T) {
 	friendshipID := uuid.New()
 	userID := uuid.New()
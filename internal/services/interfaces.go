@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
@@ -17,6 +19,7 @@ type UserServiceInterface interface {
 	UpdatePassword(ctx context.Context, userID uuid.UUID, newPasswordHash string) error
 	MarkEmailVerified(ctx context.Context, userID uuid.UUID) error
 	UpdateSearchable(ctx context.Context, userID uuid.UUID, searchable bool) error
+	SuggestUsername(ctx context.Context, email string) (string, error)
 }
 
 // AuthServiceInterface defines the contract for authentication operations.
@@ -28,12 +31,18 @@ type AuthServiceInterface interface {
 	ValidateSession(ctx context.Context, token string) (*models.User, error)
 	DeleteSession(ctx context.Context, token string) error
 	DeleteAllUserSessions(ctx context.Context, userID uuid.UUID) error
+	ListSessions(ctx context.Context, userID uuid.UUID, currentToken string) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID, currentToken string) (bool, error)
+	RevokeOtherSessions(ctx context.Context, userID uuid.UUID, currentToken string) (int, error)
 }
 
 // ProviderAuthServiceInterface defines the contract for OAuth/OIDC provider auth flows.
 type ProviderAuthServiceInterface interface {
 	LinkOrFindUserFromProvider(ctx context.Context, claims IdentityClaims) (*ProviderLinkResult, error)
 	CreateUserFromProviderPending(ctx context.Context, pending PendingProviderUser, username string, searchable bool) (*models.User, error)
+	ListIdentities(ctx context.Context, userID uuid.UUID) ([]models.LinkedIdentity, error)
+	LinkProviderToUser(ctx context.Context, userID uuid.UUID, claims IdentityClaims) error
+	UnlinkProvider(ctx context.Context, userID uuid.UUID, provider Provider) error
 }
 
 // CardServiceInterface defines the contract for bingo card operations used by handlers.
@@ -48,24 +57,39 @@ type CardServiceInterface interface {
 	Clone(ctx context.Context, userID, cardID uuid.UUID, params CloneParams) (*CloneResult, error)
 	UpdateItem(ctx context.Context, userID, cardID uuid.UUID, position int, params models.UpdateItemParams) (*models.BingoItem, error)
 	RemoveItem(ctx context.Context, userID, cardID uuid.UUID, position int) error
-	Shuffle(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error)
+	Shuffle(ctx context.Context, userID, cardID uuid.UUID, seed *int64) (*ShuffleResult, error)
+	UndoShuffle(ctx context.Context, userID, cardID uuid.UUID) (*models.BingoCard, error)
 	SwapItems(ctx context.Context, userID, cardID uuid.UUID, pos1, pos2 int) error
 	Finalize(ctx context.Context, userID, cardID uuid.UUID, params *FinalizeParams) (*models.BingoCard, error)
 	CompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int, params models.CompleteItemParams) (*models.BingoItem, error)
+	CompleteItemByContent(ctx context.Context, userID, cardID uuid.UUID, content string, exact bool, params models.CompleteItemParams) (*models.BingoItem, error)
 	UncompleteItem(ctx context.Context, userID, cardID uuid.UUID, position int) (*models.BingoItem, error)
 	UpdateItemNotes(ctx context.Context, userID, cardID uuid.UUID, position int, notes, proofURL *string) (*models.BingoItem, error)
 	GetArchive(ctx context.Context, userID uuid.UUID) ([]*models.BingoCard, error)
+	Search(ctx context.Context, params models.CardSearchParams) (*models.CardSearchResult, error)
+	GetMemories(ctx context.Context, userID uuid.UUID, now time.Time, loc *time.Location) ([]models.Memory, error)
 	GetStats(ctx context.Context, userID, cardID uuid.UUID) (*models.CardStats, error)
+	ListProgress(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]models.CardProgress, error)
+	BuildRecap(ctx context.Context, userID, cardID uuid.UUID) (*models.CardRecap, error)
+	BuildSharedRecap(ctx context.Context, token string) (*models.CardRecap, error)
+	GetRecommendations(ctx context.Context, userID, cardID uuid.UUID, limit int) ([]models.CardRecommendation, error)
 	UpdateMeta(ctx context.Context, userID, cardID uuid.UUID, params models.UpdateCardMetaParams) (*models.BingoCard, error)
 	UpdateVisibility(ctx context.Context, userID, cardID uuid.UUID, visibleToFriends bool) (*models.BingoCard, error)
-	BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, visibleToFriends bool) (int, error)
-	BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) (int, error)
-	BulkUpdateArchive(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) (int, error)
+	BulkUpdateVisibility(ctx context.Context, userID uuid.UUID, changes []models.CardVisibilityChange) ([]models.CardVisibilityResult, error)
+	BulkDelete(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID) ([]uuid.UUID, error)
+	BulkUpdateArchive(ctx context.Context, userID uuid.UUID, cardIDs []uuid.UUID, isArchived bool) ([]uuid.UUID, error)
 	Import(ctx context.Context, params models.ImportCardParams) (*models.BingoCard, error)
-	CreateOrRotateShare(ctx context.Context, userID, cardID uuid.UUID, expiresAt *time.Time) (*models.CardShare, error)
+	MergeImport(ctx context.Context, params models.MergeImportParams) (*models.MergeImportResult, error)
+	ImportAccountExport(ctx context.Context, userID uuid.UUID, cards []models.ImportCardParams) (*models.AccountExportImportResult, error)
+	CreateOrRotateShare(ctx context.Context, userID, cardID uuid.UUID, expiresAt, graceUntil *time.Time, options models.ShareOptions) (*models.CardShare, error)
 	GetShareStatus(ctx context.Context, userID, cardID uuid.UUID) (*models.CardShare, error)
 	RevokeShare(ctx context.Context, userID, cardID uuid.UUID) error
 	GetSharedCardByToken(ctx context.Context, token string) (*models.SharedCard, error)
+	GetShareAnalytics(ctx context.Context, userID, cardID uuid.UUID) (*models.ShareAnalytics, error)
+	CreateWidgetToken(ctx context.Context, userID, cardID uuid.UUID) (*models.CardWidgetToken, error)
+	ListWidgetTokens(ctx context.Context, userID, cardID uuid.UUID) ([]models.CardWidgetToken, error)
+	RevokeWidgetToken(ctx context.Context, userID, cardID, tokenID uuid.UUID) error
+	RenderWidgetByToken(ctx context.Context, token string) ([]byte, error)
 }
 
 // SuggestionServiceInterface defines the contract for suggestion operations.
@@ -76,10 +100,36 @@ type SuggestionServiceInterface interface {
 	GetGroupedByCategory(ctx context.Context) ([]SuggestionsByCategory, error)
 }
 
+// SuggestionAnalyticsServiceInterface exposes suggestion adoption stats to admins.
+type SuggestionAnalyticsServiceInterface interface {
+	Analytics(ctx context.Context, since time.Time, windowDays, limit int) (*models.SuggestionAnalytics, error)
+}
+
+// MemoryFinder looks up goals completed around today's date in earlier years.
+type MemoryFinder interface {
+	GetMemories(ctx context.Context, userID uuid.UUID, now time.Time, loc *time.Location) ([]models.Memory, error)
+}
+
+// SuggestionUsageRecorder counts new goals that match curated suggestions.
+type SuggestionUsageRecorder interface {
+	RecordUsage(ctx context.Context, contents []string) error
+}
+
+// WebhookEnqueuer queues card events for delivery to a user's webhooks.
+type WebhookEnqueuer interface {
+	Enqueue(ctx context.Context, userID uuid.UUID, event string, data any) error
+}
+
+// ActivityRecorder records card events for the ActivityPub outbox of the
+// owner's public profile. kind is a models.ActivityKind* constant.
+type ActivityRecorder interface {
+	RecordActivity(ctx context.Context, userID, cardID uuid.UUID, kind string, bingoCount int) error
+}
+
 // FriendServiceInterface defines the contract for friendship operations.
 type FriendServiceInterface interface {
 	SearchUsers(ctx context.Context, currentUserID uuid.UUID, query string) ([]models.UserSearchResult, error)
-	SendRequest(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error)
+	SendRequest(ctx context.Context, userID, friendID uuid.UUID) (friendship *models.Friendship, created bool, err error)
 	AcceptRequest(ctx context.Context, userID, friendshipID uuid.UUID) (*models.Friendship, error)
 	RejectRequest(ctx context.Context, userID, friendshipID uuid.UUID) error
 	RemoveFriend(ctx context.Context, userID, friendshipID uuid.UUID) error
@@ -89,11 +139,12 @@ type FriendServiceInterface interface {
 	ListSentRequests(ctx context.Context, userID uuid.UUID) ([]models.FriendWithUser, error)
 	IsFriend(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error)
 	GetFriendUserID(ctx context.Context, currentUserID, friendshipID uuid.UUID) (uuid.UUID, error)
+	Relation(ctx context.Context, viewerID, ownerID uuid.UUID) (authz.Relation, error)
 }
 
 // FriendChecker is a lightweight interface for friendship checks used by the reaction service.
 type FriendChecker interface {
-	IsFriend(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error)
+	Relation(ctx context.Context, viewerID, ownerID uuid.UUID) (authz.Relation, error)
 }
 
 // BlockServiceInterface defines the contract for blocking operations.
@@ -104,6 +155,13 @@ type BlockServiceInterface interface {
 	ListBlocked(ctx context.Context, blockerID uuid.UUID) ([]models.BlockedUser, error)
 }
 
+// ProfileServiceInterface defines the contract for public profile pages.
+type ProfileServiceInterface interface {
+	GetSettings(ctx context.Context, userID uuid.UUID) (*models.ProfileSettings, error)
+	UpdateSettings(ctx context.Context, userID uuid.UUID, visibility *string, indexable *bool) (*models.ProfileSettings, error)
+	GetPublicProfile(ctx context.Context, username string) (*models.PublicProfile, error)
+}
+
 // FriendInviteServiceInterface defines the contract for friend invite operations.
 type FriendInviteServiceInterface interface {
 	CreateInvite(ctx context.Context, inviterID uuid.UUID, expiresInDays int) (*models.FriendInvite, string, error)
@@ -116,17 +174,27 @@ type FriendInviteServiceInterface interface {
 type ReactionServiceInterface interface {
 	AddReaction(ctx context.Context, userID, itemID uuid.UUID, emoji string) (*models.Reaction, error)
 	RemoveReaction(ctx context.Context, userID, itemID uuid.UUID) error
+	CanViewReactions(ctx context.Context, userID, itemID uuid.UUID) error
 	GetReactionsForItem(ctx context.Context, itemID uuid.UUID) ([]models.ReactionWithUser, error)
 	GetReactionSummaryForItem(ctx context.Context, itemID uuid.UUID) ([]models.ReactionSummary, error)
 	GetReactionsForCard(ctx context.Context, cardID uuid.UUID) (map[uuid.UUID][]models.ReactionWithUser, error)
 	GetUserReactionForItem(ctx context.Context, userID, itemID uuid.UUID) (*models.Reaction, error)
 }
 
+// CommentServiceInterface defines the contract for goal comment operations.
+type CommentServiceInterface interface {
+	AddComment(ctx context.Context, userID, itemID uuid.UUID, content string) (*models.ItemComment, error)
+	ListComments(ctx context.Context, userID, itemID uuid.UUID) ([]models.ItemComment, error)
+	DeleteComment(ctx context.Context, userID, commentID uuid.UUID) error
+}
+
 // NotificationServiceInterface defines the contract for notification operations.
 type NotificationServiceInterface interface {
 	GetSettings(ctx context.Context, userID uuid.UUID) (*models.NotificationSettings, error)
 	UpdateSettings(ctx context.Context, userID uuid.UUID, patch models.NotificationSettingsPatch) (*models.NotificationSettings, error)
-	List(ctx context.Context, userID uuid.UUID, params NotificationListParams) ([]models.Notification, error)
+	CopySettings(ctx context.Context, userID uuid.UUID, from, to models.NotificationChannel) (*models.NotificationSettings, error)
+	SetEmailPause(ctx context.Context, userID uuid.UUID, until *time.Time) (*models.NotificationSettings, error)
+	List(ctx context.Context, userID uuid.UUID, params NotificationListParams) ([]models.Notification, bool, error)
 	MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error
 	MarkAllRead(ctx context.Context, userID uuid.UUID) error
 	Delete(ctx context.Context, userID, notificationID uuid.UUID) error
@@ -134,8 +202,12 @@ type NotificationServiceInterface interface {
 	UnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
 	NotifyFriendRequestReceived(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error
 	NotifyFriendRequestAccepted(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error
-	NotifyFriendsNewCard(ctx context.Context, actorID, cardID uuid.UUID) error
-	NotifyFriendsBingo(ctx context.Context, actorID, cardID uuid.UUID, bingoCount int) error
+	NotifyItemComment(ctx context.Context, recipientID, actorID, cardID uuid.UUID) error
+	NotifyReminderCheckin(ctx context.Context, tx Tx, userID, cardID uuid.UUID, sentAt time.Time) error
+	NotifyReminderGoal(ctx context.Context, tx Tx, userID, cardID, itemID uuid.UUID, sentAt time.Time) error
+	NotifyFriendsNewCard(ctx context.Context, tx Tx, actorID, cardID uuid.UUID) ([]uuid.UUID, error)
+	NotifyFriendsBingo(ctx context.Context, tx Tx, actorID, cardID uuid.UUID, bingoCount int) ([]uuid.UUID, error)
+	DispatchEmails(notificationIDs []uuid.UUID)
 }
 
 // ReminderServiceInterface defines the contract for reminder operations.
@@ -147,10 +219,85 @@ type ReminderServiceInterface interface {
 	DeleteCardCheckin(ctx context.Context, userID, cardID uuid.UUID) error
 	ListGoalReminders(ctx context.Context, userID uuid.UUID, cardID *uuid.UUID) ([]models.GoalReminderSummary, error)
 	UpsertGoalReminder(ctx context.Context, userID uuid.UUID, input models.GoalReminderInput) (*models.GoalReminder, error)
+	BulkUpsertGoalReminders(ctx context.Context, userID uuid.UUID, input models.GoalReminderBulkInput) (*models.GoalReminderBulkResult, error)
 	DeleteGoalReminder(ctx context.Context, userID uuid.UUID, reminderID uuid.UUID) error
+	ValidateSchedule(ctx context.Context, userID uuid.UUID, input models.ReminderScheduleValidationInput) (*models.ReminderScheduleValidation, error)
 	SendTestEmail(ctx context.Context, userID, cardID uuid.UUID) error
-	RenderImageByToken(ctx context.Context, token string) ([]byte, error)
+	RenderImageByToken(ctx context.Context, token, theme string) ([]byte, error)
+	FollowReminderLink(ctx context.Context, token string) (string, error)
+	GetEmailHistory(ctx context.Context, userID uuid.UUID, params ReminderHistoryParams) (*models.ReminderEmailHistory, error)
 	UnsubscribeByToken(ctx context.Context, token string) (bool, error)
+	SnoozeGoalReminderByToken(ctx context.Context, token string, days int) (bool, error)
+	EmailPreferencesByToken(ctx context.Context, token string) (*models.EmailPreferences, error)
+	UpdateEmailPreferencesByToken(ctx context.Context, token string, prefs models.EmailPreferences) (*models.EmailPreferences, error)
+	ResendReminder(ctx context.Context, reminderID uuid.UUID, bypassCap bool) (*models.ReminderResendResult, error)
+	RunNow(ctx context.Context, asOf *time.Time, limit int) (*models.ReminderRunResult, error)
+	GetUserReminderReport(ctx context.Context, userID uuid.UUID) (*models.UserReminderReport, error)
+	RevokeImageTokens(ctx context.Context, userID uuid.UUID) (int64, error)
+	RunDeliverabilityCheck(ctx context.Context, userID uuid.UUID) (*models.DeliverabilityCheck, error)
+	GetDeliverabilityCheck(ctx context.Context, userID uuid.UUID) (*models.DeliverabilityCheck, error)
+	GetCalendarFeed(ctx context.Context, userID uuid.UUID) (*models.ReminderCalendarFeed, error)
+	CreateCalendarFeed(ctx context.Context, userID uuid.UUID) (*models.ReminderCalendarFeed, error)
+	RevokeCalendarFeed(ctx context.Context, userID uuid.UUID) error
+	RenderCalendarFeed(ctx context.Context, token string) ([]byte, error)
+}
+
+// ShareSubscriptionServiceInterface defines the contract for email watchers
+// of shared cards.
+type ShareSubscriptionServiceInterface interface {
+	Subscribe(ctx context.Context, shareToken, email string) error
+	Confirm(ctx context.Context, confirmToken string) error
+	Unsubscribe(ctx context.Context, unsubscribeToken string) error
+	ListSubscribers(ctx context.Context, userID, cardID uuid.UUID) ([]models.ShareSubscriber, error)
+	RemoveSubscriber(ctx context.Context, userID, cardID, subscriberID uuid.UUID) error
+}
+
+// AdminAuditServiceInterface defines the contract for recording admin actions.
+type AdminAuditServiceInterface interface {
+	Record(ctx context.Context, entry models.AdminAuditEntry) error
+}
+
+// AdminSearchServiceInterface defines the contract for admin content search.
+type AdminSearchServiceInterface interface {
+	Search(ctx context.Context, params models.AdminSearchParams) (*models.AdminSearchResult, error)
+}
+
+// AIQuotaAdminServiceInterface lets admins lift a user's AI quota.
+type AIQuotaAdminServiceInterface interface {
+	SetUnlimited(ctx context.Context, userID uuid.UUID, unlimited bool) (*models.AIQuota, error)
+}
+
+// JobRegistryInterface exposes background job status to handlers.
+type JobRegistryInterface interface {
+	Statuses() []models.JobStatus
+}
+
+// CardDraftStateServiceInterface stores the card creation wizard's autosave.
+type CardDraftStateServiceInterface interface {
+	Save(ctx context.Context, userID uuid.UUID, state []byte) error
+	Get(ctx context.Context, userID uuid.UUID) ([]byte, error)
+	Clear(ctx context.Context, userID uuid.UUID) error
+}
+
+// EmailSendLimiterStatsInterface exposes the email send limiter to handlers.
+type EmailSendLimiterStatsInterface interface {
+	SendLimiterStats() (models.EmailSendLimiterStats, bool)
+}
+
+// CacheStatsInterface exposes cache hit and miss counters to handlers.
+type CacheStatsInterface interface {
+	Stats() map[string]models.CacheStats
+}
+
+// SharedCardImageCacheInterface caches rendered share OG images.
+type SharedCardImageCacheInterface interface {
+	GetImage(ctx context.Context, token, theme string, shared *models.SharedCard) ([]byte, bool)
+	PutImage(ctx context.Context, token, theme string, shared *models.SharedCard, png []byte)
+}
+
+// UsageServiceInterface reports per-user API usage to handlers.
+type UsageServiceInterface interface {
+	Get(ctx context.Context, user *models.User) (*models.UsageReport, error)
 }
 
 // EmailServiceInterface defines the contract for email operations.
@@ -164,6 +311,7 @@ type EmailServiceInterface interface {
 	MarkPasswordResetUsed(ctx context.Context, token string) error
 	SendNotificationEmail(ctx context.Context, toEmail, subject, html, text string) error
 	SendSupportEmail(ctx context.Context, fromEmail, category, message string, userID string) error
+	SendDataExportEmail(ctx context.Context, email string, exportedAt time.Time) error
 }
 
 // ApiTokenServiceInterface defines the contract for API token operations used by handlers.
@@ -174,8 +322,19 @@ type ApiTokenServiceInterface interface {
 	DeleteAll(ctx context.Context, userID uuid.UUID) error
 }
 
-// AccountServiceInterface defines the contract for account export/delete operations.
+// WebhookServiceInterface defines the contract for managing webhooks.
+type WebhookServiceInterface interface {
+	List(ctx context.Context, userID uuid.UUID) ([]models.Webhook, error)
+	Create(ctx context.Context, userID uuid.UUID, rawURL string) (*models.Webhook, error)
+	Delete(ctx context.Context, userID, webhookID uuid.UUID) error
+}
+
+// AccountServiceInterface defines the contract for account export, delete and preference operations.
 type AccountServiceInterface interface {
-	BuildExportZip(ctx context.Context, userID uuid.UUID) ([]byte, error)
+	StreamExport(ctx context.Context, userID uuid.UUID, w io.Writer) error
+	BuildExportJSON(ctx context.Context, userID uuid.UUID) ([]byte, error)
+	RecordEvent(ctx context.Context, event models.AccountEvent) error
 	Delete(ctx context.Context, userID uuid.UUID) error
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, prefs models.UserPreferences) (*models.UserPreferences, error)
 }
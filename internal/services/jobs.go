@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// JobRegistry tracks the background loops started by the server so operators
+// can see whether each one is alive and when it last ran. It is safe for
+// concurrent use.
+type JobRegistry struct {
+	mu   sync.RWMutex
+	jobs map[string]*models.JobStatus
+	now  func() time.Time
+}
+
+func NewJobRegistry() *JobRegistry {
+	return &JobRegistry{
+		jobs: make(map[string]*models.JobStatus),
+		now:  time.Now,
+	}
+}
+
+// Register adds a job that runs every interval. The first tick is expected one
+// interval from now. Registering an existing name resets its status.
+func (r *JobRegistry) Register(name string, interval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := &models.JobStatus{
+		Name:            name,
+		IntervalSeconds: int64(interval / time.Second),
+	}
+	if interval > 0 {
+		next := r.now().Add(interval)
+		status.NextRunAt = &next
+	}
+	r.jobs[name] = status
+}
+
+// Run executes fn as one run of the named job and records its start time,
+// duration, error, and the number of items it reports processing. The error
+// from fn is returned unchanged so callers keep their own logging. Unregistered
+// names are registered without an interval.
+func (r *JobRegistry) Run(ctx context.Context, name string, fn func(ctx context.Context) (int, error)) error {
+	started := r.now()
+	r.mu.Lock()
+	status, ok := r.jobs[name]
+	if !ok {
+		status = &models.JobStatus{Name: name}
+		r.jobs[name] = status
+	}
+	status.Running = true
+	r.mu.Unlock()
+
+	processed, err := fn(ctx)
+	finished := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status.Running = false
+	status.Runs++
+	status.LastRunAt = &started
+	status.LastDurationMS = finished.Sub(started).Milliseconds()
+	status.ItemsProcessed = processed
+	status.LastRunFailed = err != nil
+	status.LastError = nil
+	if err != nil {
+		msg := err.Error()
+		status.LastError = &msg
+	}
+	if status.IntervalSeconds > 0 {
+		next := started.Add(time.Duration(status.IntervalSeconds) * time.Second)
+		status.NextRunAt = &next
+	}
+	return err
+}
+
+// Statuses returns a copy of every job's status, sorted by name.
+func (r *JobRegistry) Statuses() []models.JobStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	statuses := make([]models.JobStatus, 0, len(r.jobs))
+	for _, status := range r.jobs {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJobRegistry_RegisterSetsNextTick(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	registry := NewJobRegistry()
+	registry.now = func() time.Time { return now }
+
+	registry.Register("reminder_runner", time.Minute)
+	registry.Register("adhoc", 0)
+
+	statuses := registry.Statuses()
+	if len(statuses) != 2 || statuses[0].Name != "adhoc" || statuses[1].Name != "reminder_runner" {
+		t.Fatalf("expected statuses sorted by name, got %+v", statuses)
+	}
+	if statuses[0].NextRunAt != nil {
+		t.Fatalf("expected no next tick without an interval, got %v", statuses[0].NextRunAt)
+	}
+	runner := statuses[1]
+	if runner.IntervalSeconds != 60 || runner.NextRunAt == nil || !runner.NextRunAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected runner status: %+v", runner)
+	}
+	if runner.LastRunAt != nil || runner.Runs != 0 {
+		t.Fatalf("expected no runs yet, got %+v", runner)
+	}
+}
+
+func TestJobRegistry_RunRecordsSuccessAndFailure(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	registry := NewJobRegistry()
+	registry.now = func() time.Time { return now }
+	registry.Register("reminder_runner", time.Minute)
+
+	err := registry.Run(context.Background(), "reminder_runner", func(ctx context.Context) (int, error) {
+		if status := registry.Statuses()[0]; !status.Running {
+			t.Fatal("expected job to be marked running during fn")
+		}
+		now = now.Add(1500 * time.Millisecond)
+		return 7, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status := registry.Statuses()[0]
+	if status.Running || status.Runs != 1 || status.ItemsProcessed != 7 || status.LastDurationMS != 1500 {
+		t.Fatalf("unexpected status after success: %+v", status)
+	}
+	started := now.Add(-1500 * time.Millisecond)
+	if status.LastRunAt == nil || !status.LastRunAt.Equal(started) {
+		t.Fatalf("expected last_run_at %v, got %v", started, status.LastRunAt)
+	}
+	if status.NextRunAt == nil || !status.NextRunAt.Equal(started.Add(time.Minute)) {
+		t.Fatalf("expected next tick one interval after start, got %v", status.NextRunAt)
+	}
+	if status.LastRunFailed || status.LastError != nil {
+		t.Fatalf("expected no error recorded, got %+v", status)
+	}
+
+	boom := errors.New("boom")
+	if err := registry.Run(context.Background(), "reminder_runner", func(ctx context.Context) (int, error) {
+		return 0, boom
+	}); !errors.Is(err, boom) {
+		t.Fatalf("expected fn error returned, got %v", err)
+	}
+	status = registry.Statuses()[0]
+	if status.Runs != 2 || !status.LastRunFailed || status.LastError == nil || *status.LastError != "boom" {
+		t.Fatalf("unexpected status after failure: %+v", status)
+	}
+
+	if err := registry.Run(context.Background(), "reminder_runner", func(ctx context.Context) (int, error) {
+		return 1, nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status := registry.Statuses()[0]; status.LastRunFailed || status.LastError != nil {
+		t.Fatalf("expected error cleared after a successful run, got %+v", status)
+	}
+}
+
+func TestJobRegistry_RunRegistersUnknownJob(t *testing.T) {
+	registry := NewJobRegistry()
+	if err := registry.Run(context.Background(), "startup_cleanup", func(ctx context.Context) (int, error) {
+		return 0, nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statuses := registry.Statuses()
+	if len(statuses) != 1 || statuses[0].Name != "startup_cleanup" || statuses[0].Runs != 1 || statuses[0].NextRunAt != nil {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+}
@@ -4,45 +4,72 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
 )
 
 var (
 	ErrNotificationNotFound = errors.New("notification not found")
 	ErrEmailNotVerified     = errors.New("email not verified")
+	ErrInvalidEmailPause    = errors.New("invalid email pause")
+	ErrInvalidEmailFormat   = errors.New("invalid email format")
+
+	ErrInvalidNotificationChannel  = errors.New("invalid notification channel")
+	ErrNotificationChannelDisabled = errors.New("notification channel disabled")
 )
 
-var notificationSettingsColumns = map[string]struct{}{
-	"in_app_enabled":                 {},
-	"in_app_friend_request_received": {},
-	"in_app_friend_request_accepted": {},
-	"in_app_friend_bingo":            {},
-	"in_app_friend_new_card":         {},
-	"email_enabled":                  {},
-	"email_friend_request_received":  {},
-	"email_friend_request_accepted":  {},
-	"email_friend_bingo":             {},
-	"email_friend_new_card":          {},
-}
+// maxEmailPause bounds how far ahead a user can pause all email.
+const maxEmailPause = 366 * 24 * time.Hour
+
+// emailNotPausedSQL is true when a notification_settings row (aliased ns) has
+// no active email pause. A LEFT JOIN miss counts as not paused.
+const emailNotPausedSQL = "(ns.email_paused_until IS NULL OR ns.email_paused_until <= NOW())"
+
+// notificationSettingsColumns are the notification_settings columns that may
+// be named in generated SQL: each channel's switch and per-type flags, plus
+// the email-only and in-app-only settings.
+var notificationSettingsColumns = func() map[string]struct{} {
+	columns := map[string]struct{}{
+		"email_friends_digest":    {},
+		"email_format":            {},
+		"in_app_reminder_checkin": {},
+		"in_app_reminder_goal":    {},
+	}
+	for _, channel := range models.NotificationChannels {
+		columns[notificationChannelColumn(channel)] = struct{}{}
+		for _, nType := range models.NotificationTypes {
+			columns[notificationTypeColumn(channel, nType)] = struct{}{}
+		}
+	}
+	return columns
+}()
 
+// NotificationListParams pages through a user's notifications, newest first.
+// After is the position of the previous page's last notification.
 type NotificationListParams struct {
 	Limit      int
-	Before     *time.Time
+	After      *pagination.Key
 	UnreadOnly bool
 }
 
+var notificationListOrder = pagination.NewOrder("n.created_at", "n.id")
+
 type NotificationService struct {
 	db           DB
 	emailService EmailServiceInterface
 	baseURL      string
 	async        func(fn func())
 	asyncCtx     context.Context
+	branding     config.BrandingConfig
+	cleanup      cleanupLimits
 }
 
 func NewNotificationService(db DB, emailService EmailServiceInterface, baseURL string) *NotificationService {
@@ -55,6 +82,7 @@ func NewNotificationService(db DB, emailService EmailServiceInterface, baseURL s
 			go fn()
 		},
 		asyncCtx: context.Background(),
+		cleanup:  defaultCleanupLimits,
 	}
 }
 
@@ -62,6 +90,11 @@ func (s *NotificationService) SetAsync(fn func(fn func())) {
 	s.async = fn
 }
 
+// SetBranding applies the deployment's branding to notification emails.
+func (s *NotificationService) SetBranding(branding config.BrandingConfig) {
+	s.branding = branding
+}
+
 func (s *NotificationService) SetAsyncContext(ctx context.Context) {
 	if ctx == nil {
 		s.asyncCtx = context.Background()
@@ -78,6 +111,9 @@ func (s *NotificationService) GetSettings(ctx context.Context, userID uuid.UUID)
 }
 
 func (s *NotificationService) UpdateSettings(ctx context.Context, userID uuid.UUID, patch models.NotificationSettingsPatch) (*models.NotificationSettings, error) {
+	if patch.EmailFormat != nil && !models.IsValidEmailFormat(*patch.EmailFormat) {
+		return nil, ErrInvalidEmailFormat
+	}
 	if enablesEmail(patch) {
 		verified, err := s.isEmailVerified(ctx, userID)
 		if err != nil {
@@ -97,8 +133,8 @@ func (s *NotificationService) UpdateSettings(ctx context.Context, userID uuid.UU
 	idx := 1
 	invalidColumn := ""
 
-	addBool := func(column string, value *bool) {
-		if value == nil || invalidColumn != "" {
+	addValue := func(column string, value any) {
+		if invalidColumn != "" {
 			return
 		}
 		if !isNotificationSettingsColumnAllowed(column) {
@@ -106,20 +142,31 @@ func (s *NotificationService) UpdateSettings(ctx context.Context, userID uuid.UU
 			return
 		}
 		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, idx))
-		args = append(args, *value)
+		args = append(args, value)
 		idx++
 	}
+	addBool := func(column string, value *bool) {
+		if value != nil {
+			addValue(column, *value)
+		}
+	}
 
 	addBool("in_app_enabled", patch.InAppEnabled)
 	addBool("in_app_friend_request_received", patch.InAppFriendRequestReceived)
 	addBool("in_app_friend_request_accepted", patch.InAppFriendRequestAccepted)
 	addBool("in_app_friend_bingo", patch.InAppFriendBingo)
 	addBool("in_app_friend_new_card", patch.InAppFriendNewCard)
+	addBool("in_app_reminder_checkin", patch.InAppReminderCheckin)
+	addBool("in_app_reminder_goal", patch.InAppReminderGoal)
 	addBool("email_enabled", patch.EmailEnabled)
 	addBool("email_friend_request_received", patch.EmailFriendRequestReceived)
 	addBool("email_friend_request_accepted", patch.EmailFriendRequestAccepted)
 	addBool("email_friend_bingo", patch.EmailFriendBingo)
 	addBool("email_friend_new_card", patch.EmailFriendNewCard)
+	addBool("email_friends_digest", patch.EmailFriendsDigest)
+	if patch.EmailFormat != nil {
+		addValue("email_format", *patch.EmailFormat)
+	}
 
 	if invalidColumn != "" {
 		return nil, fmt.Errorf("invalid notification settings column: %s", invalidColumn)
@@ -144,20 +191,89 @@ func (s *NotificationService) UpdateSettings(ctx context.Context, userID uuid.UU
 	return s.loadSettings(ctx, userID)
 }
 
-func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, params NotificationListParams) ([]models.Notification, error) {
+// CopySettings copies the per-type flags of one channel onto another in a
+// single update. The target channel must already be switched on; its switch
+// and channel-only settings such as the digest are left as they are.
+func (s *NotificationService) CopySettings(ctx context.Context, userID uuid.UUID, from, to models.NotificationChannel) (*models.NotificationSettings, error) {
+	if !models.IsValidNotificationChannel(from) || !models.IsValidNotificationChannel(to) {
+		return nil, ErrInvalidNotificationChannel
+	}
+
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.ChannelEnabled(to) {
+		return nil, ErrNotificationChannelDisabled
+	}
+	if from == to {
+		return settings, nil
+	}
+
+	setClauses := make([]string, 0, len(models.NotificationTypes)+1)
+	for _, nType := range models.NotificationTypes {
+		fromCol := notificationTypeColumn(from, nType)
+		toCol := notificationTypeColumn(to, nType)
+		if !isNotificationSettingsColumnAllowed(fromCol) || !isNotificationSettingsColumnAllowed(toCol) {
+			return nil, fmt.Errorf("invalid notification settings column: %s", toCol)
+		}
+		setClauses = append(setClauses, fmt.Sprintf("%s = %s", toCol, fromCol))
+	}
+	setClauses = append(setClauses, "updated_at = NOW()")
+
+	if _, err := s.db.Exec(ctx,
+		fmt.Sprintf("UPDATE notification_settings SET %s WHERE user_id = $1", strings.Join(setClauses, ", ")),
+		userID,
+	); err != nil {
+		return nil, fmt.Errorf("copying notification settings: %w", err)
+	}
+
+	return s.loadSettings(ctx, userID)
+}
+
+// SetEmailPause pauses notification and reminder emails until the given time,
+// or clears the pause when until is nil. Notification emails raised during a
+// pause are dropped (the in-app notification is kept); reminders are deferred.
+func (s *NotificationService) SetEmailPause(ctx context.Context, userID uuid.UUID, until *time.Time) (*models.NotificationSettings, error) {
+	if until != nil {
+		remaining := time.Until(*until)
+		if remaining <= 0 || remaining > maxEmailPause {
+			return nil, ErrInvalidEmailPause
+		}
+	}
+
+	if err := s.ensureSettingsRow(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec(ctx,
+		"UPDATE notification_settings SET email_paused_until = $1, updated_at = NOW() WHERE user_id = $2",
+		until,
+		userID,
+	); err != nil {
+		return nil, fmt.Errorf("updating email pause: %w", err)
+	}
+
+	return s.loadSettings(ctx, userID)
+}
+
+// List returns a page of the user's in-app notifications and whether older
+// ones follow.
+func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, params NotificationListParams) ([]models.Notification, bool, error) {
 	limit := params.Limit
-	if limit <= 0 || limit > 100 {
-		limit = 50
+	if limit <= 0 || limit > pagination.MaxLimit {
+		limit = pagination.DefaultLimit
 	}
 
 	conditions := []string{"n.user_id = $1", "n.in_app_delivered = true"}
 	args := []any{userID}
 	idx := 2
 
-	if params.Before != nil {
-		conditions = append(conditions, fmt.Sprintf("n.created_at < $%d", idx))
-		args = append(args, *params.Before)
-		idx++
+	if params.After != nil {
+		condition, keyArgs := notificationListOrder.After(*params.After, idx)
+		conditions = append(conditions, condition)
+		args = append(args, keyArgs...)
+		idx += len(keyArgs)
 	}
 
 	if params.UnreadOnly {
@@ -166,22 +282,24 @@ func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, params
 
 	query := fmt.Sprintf(
 		`SELECT n.id, n.user_id, n.type, n.actor_user_id, au.username,
-		        n.friendship_id, n.card_id, c.title, c.year, n.bingo_count,
+		        n.friendship_id, n.card_id, c.title, c.year, n.bingo_count, n.item_id, bi.content,
 		        n.in_app_delivered, n.email_delivered, n.email_sent_at, n.read_at, n.created_at
 		 FROM notifications n
 		 LEFT JOIN users au ON n.actor_user_id = au.id AND au.deleted_at IS NULL
 		 LEFT JOIN bingo_cards c ON n.card_id = c.id
+		 LEFT JOIN bingo_items bi ON n.item_id = bi.id
 		 WHERE %s
-		 ORDER BY n.created_at DESC
+		 ORDER BY %s
 		 LIMIT $%d`,
 		strings.Join(conditions, " AND "),
+		notificationListOrder.OrderBy(),
 		idx,
 	)
-	args = append(args, limit)
+	args = append(args, limit+1)
 
 	rows, err := s.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("listing notifications: %w", err)
+		return nil, false, fmt.Errorf("listing notifications: %w", err)
 	}
 	defer rows.Close()
 
@@ -200,13 +318,15 @@ func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, params
 			&n.CardTitle,
 			&n.CardYear,
 			&n.BingoCount,
+			&n.ItemID,
+			&n.ItemContent,
 			&n.InAppDelivered,
 			&n.EmailDelivered,
 			&n.EmailSentAt,
 			&n.ReadAt,
 			&n.CreatedAt,
 		); err != nil {
-			return nil, fmt.Errorf("scanning notification: %w", err)
+			return nil, false, fmt.Errorf("scanning notification: %w", err)
 		}
 		n.Type = models.NotificationType(nType)
 		notifications = append(notifications, n)
@@ -215,7 +335,8 @@ func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, params
 	if notifications == nil {
 		notifications = []models.Notification{}
 	}
-	return notifications, nil
+	notifications, hasMore := pagination.Trim(notifications, limit)
+	return notifications, hasMore, nil
 }
 
 func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
@@ -288,23 +409,67 @@ func (s *NotificationService) NotifyFriendRequestAccepted(ctx context.Context, r
 	return s.notifySingle(ctx, recipientID, actorID, friendshipID, nil, nil, models.NotificationTypeFriendRequestAccepted)
 }
 
-func (s *NotificationService) NotifyFriendsNewCard(ctx context.Context, actorID, cardID uuid.UUID) error {
-	return s.notifyFriends(ctx, actorID, cardID, nil, models.NotificationTypeFriendNewCard)
+// NotifyItemComment tells a card owner that actorID commented on one of their
+// goals. It is in-app only, follows the owner's in-app switch, and is skipped
+// when either side has blocked the other.
+func (s *NotificationService) NotifyItemComment(ctx context.Context, recipientID, actorID, cardID uuid.UUID) error {
+	if _, err := s.db.Exec(ctx,
+		`INSERT INTO notifications (user_id, type, actor_user_id, card_id, in_app_delivered, email_delivered)
+		 SELECT u.id, $2, $3, $4, true, false
+		 FROM users u
+		 LEFT JOIN notification_settings ns ON ns.user_id = u.id
+		 WHERE u.id = $1
+		   AND u.deleted_at IS NULL
+		   AND COALESCE(ns.in_app_enabled, true)
+		   AND NOT EXISTS (
+		     SELECT 1 FROM user_blocks
+		     WHERE (blocker_id = $1 AND blocked_id = $3)
+		        OR (blocker_id = $3 AND blocked_id = $1)
+		   )`,
+		recipientID, string(models.NotificationTypeItemComment), actorID, cardID,
+	); err != nil {
+		return fmt.Errorf("insert comment notification: %w", err)
+	}
+	return nil
+}
+
+// NotifyReminderCheckin puts the check-in for cardID that came due at sentAt
+// in the owner's bell. It runs inside tx, the reminder run's transaction, and
+// is called whether or not the email went out, so a failing mailbox still
+// leaves one channel. See notifyReminder for deduplication and failures.
+func (s *NotificationService) NotifyReminderCheckin(ctx context.Context, tx Tx, userID, cardID uuid.UUID, sentAt time.Time) error {
+	return s.notifyReminder(ctx, tx, userID, cardID, nil, sentAt, models.NotificationTypeReminderCheckin,
+		"(SELECT last_sent_at FROM card_checkin_reminders WHERE user_id = $1 AND card_id = $3)")
+}
+
+// NotifyReminderGoal is NotifyReminderCheckin for a goal reminder on itemID.
+func (s *NotificationService) NotifyReminderGoal(ctx context.Context, tx Tx, userID, cardID, itemID uuid.UUID, sentAt time.Time) error {
+	return s.notifyReminder(ctx, tx, userID, cardID, &itemID, sentAt, models.NotificationTypeReminderGoal,
+		"(SELECT last_sent_at FROM goal_reminders WHERE user_id = $1 AND item_id = $4)")
+}
+
+// NotifyFriendsNewCard creates new-card notifications for the actor's friends
+// inside tx, the transaction of the card write that triggered them. It returns
+// the notifications that need an email; pass them to DispatchEmails once tx
+// has committed. See notifyFriendsInTx for failure handling.
+func (s *NotificationService) NotifyFriendsNewCard(ctx context.Context, tx Tx, actorID, cardID uuid.UUID) ([]uuid.UUID, error) {
+	return s.notifyFriendsInTx(ctx, tx, actorID, cardID, nil, models.NotificationTypeFriendNewCard)
 }
 
-func (s *NotificationService) NotifyFriendsBingo(ctx context.Context, actorID, cardID uuid.UUID, bingoCount int) error {
+// NotifyFriendsBingo is NotifyFriendsNewCard for bingos. A zero count is a
+// no-op.
+func (s *NotificationService) NotifyFriendsBingo(ctx context.Context, tx Tx, actorID, cardID uuid.UUID, bingoCount int) ([]uuid.UUID, error) {
 	if bingoCount <= 0 {
-		return nil
+		return nil, nil
 	}
-	return s.notifyFriends(ctx, actorID, cardID, &bingoCount, models.NotificationTypeFriendBingo)
+	return s.notifyFriendsInTx(ctx, tx, actorID, cardID, &bingoCount, models.NotificationTypeFriendBingo)
 }
 
-func (s *NotificationService) CleanupOld(ctx context.Context) error {
-	_, err := s.db.Exec(ctx, "DELETE FROM notifications WHERE created_at < NOW() - INTERVAL '1 year'")
-	if err != nil {
-		return fmt.Errorf("cleanup notifications: %w", err)
-	}
-	return nil
+// CleanupOld deletes notifications older than a year in batches and returns
+// how many it deleted. A run stops early once its time budget is spent.
+func (s *NotificationService) CleanupOld(ctx context.Context) (int, error) {
+	deadline := time.Now().Add(s.cleanup.budget)
+	return batchDelete(ctx, s.db, "notifications", "created_at < NOW() - INTERVAL '1 year'", s.cleanup, deadline)
 }
 
 func (s *NotificationService) notifySingle(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID, cardID *uuid.UUID, bingoCount *int, nType models.NotificationType) error {
@@ -317,7 +482,7 @@ func (s *NotificationService) notifySingle(ctx context.Context, recipientID, act
 	}
 
 	inAppEnabled := "COALESCE(ns.in_app_enabled, true)"
-	emailEnabled := "COALESCE(ns.email_enabled, false)"
+	emailEnabled := "(COALESCE(ns.email_enabled, false) AND " + emailNotPausedSQL + ")"
 	inAppSetting := fmt.Sprintf("COALESCE(ns.%s, true)", inAppCol)
 	emailSetting := fmt.Sprintf("COALESCE(ns.%s, false)", emailCol)
 
@@ -356,23 +521,111 @@ func (s *NotificationService) notifySingle(ctx context.Context, recipientID, act
 
 	inserted := collectInserted(rows)
 	if len(inserted.emailIDs) > 0 {
-		s.dispatchEmails(inserted.emailIDs)
+		s.DispatchEmails(inserted.emailIDs)
 	}
 
 	return nil
 }
 
-func (s *NotificationService) notifyFriends(ctx context.Context, actorID, cardID uuid.UUID, bingoCount *int, nType models.NotificationType) error {
+// notifyReminder inserts one in-app reminder notification stamped sentAt,
+// subject to the in-app switch and the type's setting. A failed email is
+// retried every few minutes without advancing the reminder's last_sent_at,
+// so a notification for the same target created after lastSentSQL means
+// this occurrence is already in the bell and none is added. A successful
+// send sets last_sent_at to sentAt, which the next occurrence then compares
+// against. The insert runs under a savepoint so a failure here never aborts
+// the reminder run.
+func (s *NotificationService) notifyReminder(ctx context.Context, tx Tx, userID, cardID uuid.UUID, itemID *uuid.UUID, sentAt time.Time, nType models.NotificationType, lastSentSQL string) error {
+	settingCol := notificationTypeColumn(models.NotificationChannelInApp, nType)
+	if !isNotificationSettingsColumnAllowed(settingCol) {
+		return fmt.Errorf("invalid notification settings column: %s", settingCol)
+	}
+	target := "n.card_id = $3"
+	if itemID != nil {
+		target = "n.item_id = $4"
+	}
+
+	if _, err := tx.Exec(ctx, "SAVEPOINT reminder_notification"); err != nil {
+		return fmt.Errorf("creating reminder notification savepoint: %w", err)
+	}
+	_, err := tx.Exec(ctx, fmt.Sprintf(
+		`INSERT INTO notifications (user_id, type, card_id, item_id, in_app_delivered, email_delivered, created_at)
+		 SELECT u.id, $2, $3, $4, true, false, $5
+		 FROM users u
+		 LEFT JOIN notification_settings ns ON ns.user_id = u.id
+		 WHERE u.id = $1
+		   AND u.deleted_at IS NULL
+		   AND COALESCE(ns.in_app_enabled, true)
+		   AND COALESCE(ns.%s, true)
+		   AND NOT EXISTS (
+		     SELECT 1 FROM notifications n
+		     WHERE n.user_id = $1 AND n.type = $2 AND %s
+		       AND (%s IS NULL OR n.created_at > %s)
+		   )`,
+		settingCol, target, lastSentSQL, lastSentSQL,
+	), userID, string(nType), cardID, itemID, sentAt)
+	if err != nil {
+		if _, rbErr := tx.Exec(ctx, "ROLLBACK TO SAVEPOINT reminder_notification"); rbErr != nil {
+			return fmt.Errorf("rolling back reminder notification savepoint: %w", rbErr)
+		}
+		return fmt.Errorf("insert reminder notification: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "RELEASE SAVEPOINT reminder_notification"); err != nil {
+		return fmt.Errorf("releasing reminder notification savepoint: %w", err)
+	}
+	return nil
+}
+
+// notifyFriendsInTx runs the friend notification insert under a savepoint in
+// tx. If the insert fails it is rolled back to the savepoint and queued in
+// pending_notifications for ReconcilePending instead, so the triggering card
+// write still commits. The returned error is then informational only.
+func (s *NotificationService) notifyFriendsInTx(ctx context.Context, tx Tx, actorID, cardID uuid.UUID, bingoCount *int, nType models.NotificationType) ([]uuid.UUID, error) {
+	if _, err := tx.Exec(ctx, "SAVEPOINT friend_notifications"); err != nil {
+		return nil, fmt.Errorf("creating notification savepoint: %w", err)
+	}
+
+	emailIDs, insertErr := s.insertFriendNotifications(ctx, tx, actorID, cardID, bingoCount, nType)
+	if insertErr != nil {
+		if _, err := tx.Exec(ctx, "ROLLBACK TO SAVEPOINT friend_notifications"); err != nil {
+			return nil, fmt.Errorf("rolling back notification savepoint: %w", err)
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO pending_notifications (type, actor_user_id, card_id, bingo_count, attempts, last_error)
+			 VALUES ($1, $2, $3, $4, 1, $5)`,
+			string(nType), actorID, cardID, bingoCount, insertErr.Error(),
+		); err != nil {
+			if _, rbErr := tx.Exec(ctx, "ROLLBACK TO SAVEPOINT friend_notifications"); rbErr != nil {
+				return nil, fmt.Errorf("rolling back notification savepoint: %w", rbErr)
+			}
+			return nil, fmt.Errorf("queueing notifications after %v: %w", insertErr, err)
+		}
+		emailIDs = nil
+	}
+
+	if _, err := tx.Exec(ctx, "RELEASE SAVEPOINT friend_notifications"); err != nil {
+		return nil, fmt.Errorf("releasing notification savepoint: %w", err)
+	}
+	if insertErr != nil {
+		return nil, fmt.Errorf("notifications queued for retry: %w", insertErr)
+	}
+	return emailIDs, nil
+}
+
+// insertFriendNotifications fans a notification out to the actor's accepted,
+// unblocked friends according to their settings, and returns the IDs that
+// need an email.
+func (s *NotificationService) insertFriendNotifications(ctx context.Context, conn DBConn, actorID, cardID uuid.UUID, bingoCount *int, nType models.NotificationType) ([]uuid.UUID, error) {
 	inAppCol, emailCol, err := notificationScenarioColumns(nType)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !isNotificationSettingsColumnAllowed(inAppCol) || !isNotificationSettingsColumnAllowed(emailCol) {
-		return fmt.Errorf("invalid notification settings column")
+		return nil, fmt.Errorf("invalid notification settings column")
 	}
 
 	inAppEnabled := "COALESCE(ns.in_app_enabled, true)"
-	emailEnabled := "COALESCE(ns.email_enabled, false)"
+	emailEnabled := "(COALESCE(ns.email_enabled, false) AND " + emailNotPausedSQL + ")"
 	inAppSetting := fmt.Sprintf("COALESCE(ns.%s, true)", inAppCol)
 	emailSetting := fmt.Sprintf("COALESCE(ns.%s, false)", emailCol)
 
@@ -408,21 +661,130 @@ func (s *NotificationService) notifyFriends(ctx context.Context, actorID, cardID
 		emailSetting,
 	)
 
-	rows, err := s.db.Query(ctx, query, actorID, string(nType), cardID, bingoCount)
+	rows, err := conn.Query(ctx, query, actorID, string(nType), cardID, bingoCount)
 	if err != nil {
-		return fmt.Errorf("insert notifications: %w", err)
+		return nil, fmt.Errorf("insert notifications: %w", err)
 	}
-	defer rows.Close()
-
 	inserted := collectInserted(rows)
-	if len(inserted.emailIDs) > 0 {
-		s.dispatchEmails(inserted.emailIDs)
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("insert notifications: %w", err)
 	}
+	return inserted.emailIDs, nil
+}
 
-	return nil
+// ReconcilePending retries friend notifications queued by notifyFriendsInTx,
+// oldest first, each in its own transaction. A retry that fails again stays
+// queued until maxPendingNotificationAttempts, then is dropped. Returns how
+// many were delivered.
+func (s *NotificationService) ReconcilePending(ctx context.Context) (int, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT id, type, actor_user_id, card_id, bingo_count
+		 FROM pending_notifications
+		 ORDER BY created_at
+		 LIMIT $1`,
+		pendingNotificationBatch,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("loading pending notifications: %w", err)
+	}
+	var pending []pendingNotification
+	for rows.Next() {
+		var p pendingNotification
+		var nType string
+		if err := rows.Scan(&p.id, &nType, &p.actorID, &p.cardID, &p.bingoCount); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning pending notification: %w", err)
+		}
+		p.nType = models.NotificationType(nType)
+		pending = append(pending, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterating pending notifications: %w", err)
+	}
+
+	delivered := 0
+	for _, p := range pending {
+		ok, err := s.retryPending(ctx, p)
+		if err != nil {
+			return delivered, err
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// pendingNotificationBatch caps how many queued notifications one
+// ReconcilePending pass retries.
+const pendingNotificationBatch = 100
+
+// maxPendingNotificationAttempts is how many failed inserts a queued
+// notification gets, counting the original one, before it is dropped.
+const maxPendingNotificationAttempts = 5
+
+type pendingNotification struct {
+	id         uuid.UUID
+	nType      models.NotificationType
+	actorID    uuid.UUID
+	cardID     uuid.UUID
+	bingoCount *int
 }
 
-func (s *NotificationService) dispatchEmails(notificationIDs []uuid.UUID) {
+// retryPending claims one queued notification by deleting it and inserts the
+// notifications in the same transaction. Another server that already claimed
+// it makes this a no-op. Only database errors outside the insert itself are
+// returned.
+func (s *NotificationService) retryPending(ctx context.Context, p pendingNotification) (bool, error) {
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	claimed, err := tx.Exec(ctx, "DELETE FROM pending_notifications WHERE id = $1", p.id)
+	if err != nil {
+		return false, fmt.Errorf("claiming pending notification: %w", err)
+	}
+	if claimed.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	emailIDs, insertErr := s.insertFriendNotifications(ctx, tx, p.actorID, p.cardID, p.bingoCount, p.nType)
+	if insertErr != nil {
+		_ = tx.Rollback(ctx)
+		fields := map[string]interface{}{"error": insertErr.Error(), "pending_id": p.id.String()}
+		result, err := s.db.Exec(ctx,
+			`UPDATE pending_notifications SET attempts = attempts + 1, last_error = $2
+			 WHERE id = $1 AND attempts + 1 < $3`,
+			p.id, insertErr.Error(), maxPendingNotificationAttempts,
+		)
+		if err != nil {
+			return false, fmt.Errorf("recording pending notification failure: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			if _, err := s.db.Exec(ctx, "DELETE FROM pending_notifications WHERE id = $1", p.id); err != nil {
+				return false, fmt.Errorf("dropping pending notification: %w", err)
+			}
+			logging.Error("Dropped friend notification after repeated failures", fields)
+			return false, nil
+		}
+		logging.Warn("Retrying friend notification failed", fields)
+		return false, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("committing transaction: %w", err)
+	}
+	s.DispatchEmails(emailIDs)
+	return true, nil
+}
+
+// DispatchEmails sends the emails for the given notifications in the
+// background. Call it only after the notifications are committed.
+func (s *NotificationService) DispatchEmails(notificationIDs []uuid.UUID) {
 	if s.emailService == nil || len(notificationIDs) == 0 {
 		return
 	}
@@ -438,13 +800,58 @@ func (s *NotificationService) dispatchEmails(notificationIDs []uuid.UUID) {
 		}
 		ctx, cancel := context.WithTimeout(baseCtx, 10*time.Second)
 		defer cancel()
-		s.sendNotificationEmails(ctx, notificationIDs)
+		s.sendNotificationEmails(ctx, notificationIDs, false)
 	})
 }
 
-func (s *NotificationService) sendNotificationEmails(ctx context.Context, notificationIDs []uuid.UUID) {
+// notificationOutboxBatch caps how many deferred notification emails one
+// outbox run sends.
+const notificationOutboxBatch = 100
+
+// SendDeferredEmails sends the notification emails that were deferred
+// because the email send limiter was saturated, oldest first. Unlike the
+// first attempt it waits for the limiter. An email that is refused again
+// stays deferred; one that fails for any other reason is dropped, as a first
+// attempt would be. Returns how many were sent.
+func (s *NotificationService) SendDeferredEmails(ctx context.Context) (int, error) {
+	if s.emailService == nil {
+		return 0, nil
+	}
 	rows, err := s.db.Query(ctx,
-		`SELECT n.id, n.type, u.email, u.username, au.username, n.friendship_id, c.title, c.year, n.bingo_count
+		`SELECT id FROM notifications
+		 WHERE email_deferred_at IS NOT NULL AND email_sent_at IS NULL
+		 ORDER BY email_deferred_at
+		 LIMIT $1`,
+		notificationOutboxBatch,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("loading deferred notification emails: %w", err)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning deferred notification email: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterating deferred notification emails: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return s.sendNotificationEmails(ctx, ids, true), nil
+}
+
+// sendNotificationEmails sends the emails for the given notifications and
+// returns how many went out. First attempts never wait for the email send
+// limiter: when it is saturated the email is deferred to the outbox instead.
+func (s *NotificationService) sendNotificationEmails(ctx context.Context, notificationIDs []uuid.UUID, fromOutbox bool) int {
+	rows, err := s.db.Query(ctx,
+		`SELECT n.id, n.user_id, n.type, u.email, u.username, au.username, n.friendship_id, n.card_id, c.title, c.year, n.bingo_count
 		 FROM notifications n
 		 JOIN users u ON n.user_id = u.id AND u.deleted_at IS NULL
 		 LEFT JOIN users au ON n.actor_user_id = au.id AND au.deleted_at IS NULL
@@ -454,26 +861,36 @@ func (s *NotificationService) sendNotificationEmails(ctx context.Context, notifi
 	)
 	if err != nil {
 		logging.Error("Failed to load notification emails", map[string]interface{}{"error": err.Error()})
-		return
+		return 0
 	}
 	defer rows.Close()
 
+	sendCtx := ctx
+	if !fromOutbox {
+		sendCtx = WithoutEmailWait(ctx)
+	}
+	sent := 0
+
 	for rows.Next() {
 		var id uuid.UUID
+		var recipientID uuid.UUID
 		var nType string
 		var recipientEmail string
 		var actorName *string
 		var friendshipID *uuid.UUID
+		var cardID *uuid.UUID
 		var cardTitle *string
 		var cardYear *int
 		var bingoCount *int
 		if err := rows.Scan(
 			&id,
+			&recipientID,
 			&nType,
 			&recipientEmail,
 			new(string),
 			&actorName,
 			&friendshipID,
+			&cardID,
 			&cardTitle,
 			&cardYear,
 			&bingoCount,
@@ -482,23 +899,50 @@ func (s *NotificationService) sendNotificationEmails(ctx context.Context, notifi
 			continue
 		}
 
-		subject, html, text := s.buildNotificationEmail(models.NotificationType(nType), actorName, cardTitle, cardYear, bingoCount)
-		if err := s.emailService.SendNotificationEmail(ctx, recipientEmail, subject, html, text); err != nil {
-			logging.Error("Failed to send notification email", map[string]interface{}{"error": err.Error(), "notification_id": id.String()})
+		preferencesURL := createEmailPreferencesURL(ctx, s.db, s.baseURL, recipientID, time.Now())
+		var subject, html, text string
+		if models.NotificationType(nType) == models.NotificationTypeDraftNudge && cardID != nil {
+			subject, html, text = buildDraftNudgeEmail(s.baseURL, *cardID, cardTitle, cardYear, preferencesURL, s.branding)
+		} else {
+			subject, html, text = s.buildNotificationEmail(models.NotificationType(nType), actorName, friendshipID, cardTitle, cardYear, bingoCount, preferencesURL)
+		}
+		if err := s.emailService.SendNotificationEmail(sendCtx, recipientEmail, subject, html, text); err != nil {
+			s.deferOrDropNotificationEmail(ctx, id, err, fromOutbox)
 			continue
 		}
-		if _, err := s.db.Exec(ctx, "UPDATE notifications SET email_sent_at = NOW() WHERE id = $1", id); err != nil {
+		sent++
+		if _, err := s.db.Exec(ctx, "UPDATE notifications SET email_sent_at = NOW(), email_deferred_at = NULL WHERE id = $1", id); err != nil {
 			logging.Error("Failed to mark notification email sent", map[string]interface{}{"error": err.Error(), "notification_id": id.String()})
 		}
 	}
+	return sent
 }
 
-func (s *NotificationService) buildNotificationEmail(nType models.NotificationType, actorName *string, cardTitle *string, cardYear *int, bingoCount *int) (string, string, string) {
+// deferOrDropNotificationEmail queues a notification email the send limiter
+// refused for the outbox, and takes one that failed otherwise off it.
+func (s *NotificationService) deferOrDropNotificationEmail(ctx context.Context, id uuid.UUID, sendErr error, fromOutbox bool) {
+	fields := map[string]interface{}{"error": sendErr.Error(), "notification_id": id.String()}
+	query := "UPDATE notifications SET email_deferred_at = NOW() WHERE id = $1"
+	if errors.Is(sendErr, ErrEmailRateLimited) {
+		logging.Info("Deferred notification email to the outbox", fields)
+	} else {
+		logging.Error("Failed to send notification email", fields)
+		if !fromOutbox {
+			return
+		}
+		query = "UPDATE notifications SET email_deferred_at = NULL WHERE id = $1"
+	}
+	if _, err := s.db.Exec(ctx, query, id); err != nil {
+		logging.Error("Failed to update deferred notification email", map[string]interface{}{"error": err.Error(), "notification_id": id.String()})
+	}
+}
+
+func (s *NotificationService) buildNotificationEmail(nType models.NotificationType, actorName *string, friendshipID *uuid.UUID, cardTitle *string, cardYear *int, bingoCount *int, preferencesURL string) (string, string, string) {
 	actor := "A friend"
 	if actorName != nil && *actorName != "" {
-		actor = *actorName
+		actor = isolateBidi(*actorName)
 	}
-	cardName := cardDisplayName(cardTitle, cardYear)
+	cardName := isolateBidi(cardDisplayName(cardTitle, cardYear))
 
 	var subject string
 	var message string
@@ -526,9 +970,18 @@ func (s *NotificationService) buildNotificationEmail(nType models.NotificationTy
 
 	viewURL := fmt.Sprintf("%s/notifications", s.baseURL)
 	friendsURL := fmt.Sprintf("%s/friends", s.baseURL)
+	// Card events link to the friend's card, as the in-app notification does.
+	cardHTML, cardText := "", ""
+	if friendshipID != nil && (nType == models.NotificationTypeFriendBingo || nType == models.NotificationTypeFriendNewCard) {
+		cardURL := fmt.Sprintf("%s/friend-card/%s", s.baseURL, *friendshipID)
+		cardHTML = fmt.Sprintf("<p style=\"color: #666; font-size: 14px;\">See the card: <a href=\"%s\">%s</a></p>\n  ", templateEscape(cardURL), templateEscape(cardURL))
+		cardText = fmt.Sprintf("See the card: %s\n", cardURL)
+	}
 	settingsURL := fmt.Sprintf("%s/profile", s.baseURL)
 	friendsLabel := "Friends page"
 	settingsLabel := "Manage notification settings"
+	preferencesHTML, preferencesText := emailPreferencesLines(preferencesURL)
+	brand := emailBrand(s.branding)
 
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html>
@@ -537,42 +990,47 @@ func (s *NotificationService) buildNotificationEmail(nType models.NotificationTy
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
 </head>
 <body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 600px; margin: 0 auto; padding: 20px;">
-  <h1 style="color: #333; font-size: 24px;">Year of Bingo</h1>
+  %s<h1 style="color: #333; font-size: 24px;">%s</h1>
 
   <p style="font-size: 16px;">%s</p>
 
   <p>
-    <a href="%s" style="display: inline-block; background: #4F46E5; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">
+    <a href="%s" style="display: inline-block; background: %s; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">
       View Notifications
     </a>
   </p>
 
-  <p style="color: #666; font-size: 14px;">
+  %s<p style="color: #666; font-size: 14px;">
     Friends page: <a href="%s">%s</a>
   </p>
 
   <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
   <p style="color: #666; font-size: 14px;">Manage notification settings: <a href="%s">%s</a></p>
-  <p style="color: #999; font-size: 12px;">Year of Bingo - yearofbingo.com</p>
+  %s<p style="color: #999; font-size: 12px;">%s</p>
 </body>
 </html>`,
+		brand.logoHTML(),
+		templateEscape(brand.name()),
 		templateEscape(message),
 		viewURL,
+		brand.accent(authEmailAccent),
+		cardHTML,
 		friendsURL,
 		friendsLabel,
 		settingsURL,
 		settingsLabel,
+		preferencesHTML,
+		brand.footerHTML(),
 	)
 
 	text := fmt.Sprintf(`%s
 
 View notifications: %s
-Friends page: %s
+%sFriends page: %s
 Manage notification settings: %s
-
+%s
 --
-Year of Bingo
-yearofbingo.com`, message, viewURL, friendsURL, settingsURL)
+%s`, message, viewURL, cardText, friendsURL, settingsURL, preferencesText, brand.footerText())
 
 	return subject, html, text
 }
@@ -592,8 +1050,10 @@ func (s *NotificationService) loadSettings(ctx context.Context, userID uuid.UUID
 	settings := &models.NotificationSettings{}
 	err := s.db.QueryRow(ctx,
 		`SELECT user_id, in_app_enabled, in_app_friend_request_received, in_app_friend_request_accepted,
-		        in_app_friend_bingo, in_app_friend_new_card, email_enabled, email_friend_request_received,
-		        email_friend_request_accepted, email_friend_bingo, email_friend_new_card, created_at, updated_at
+		        in_app_friend_bingo, in_app_friend_new_card, in_app_reminder_checkin, in_app_reminder_goal,
+		        email_enabled, email_friend_request_received,
+		        email_friend_request_accepted, email_friend_bingo, email_friend_new_card, email_friends_digest,
+		        email_format, created_at, updated_at, CASE WHEN email_paused_until > NOW() THEN email_paused_until END
 		 FROM notification_settings WHERE user_id = $1`,
 		userID,
 	).Scan(
@@ -603,13 +1063,18 @@ func (s *NotificationService) loadSettings(ctx context.Context, userID uuid.UUID
 		&settings.InAppFriendRequestAccepted,
 		&settings.InAppFriendBingo,
 		&settings.InAppFriendNewCard,
+		&settings.InAppReminderCheckin,
+		&settings.InAppReminderGoal,
 		&settings.EmailEnabled,
 		&settings.EmailFriendRequestReceived,
 		&settings.EmailFriendRequestAccepted,
 		&settings.EmailFriendBingo,
 		&settings.EmailFriendNewCard,
+		&settings.EmailFriendsDigest,
+		&settings.EmailFormat,
 		&settings.CreatedAt,
 		&settings.UpdatedAt,
+		&settings.EmailPausedUntil,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("load notification settings: %w", err)
@@ -647,18 +1112,22 @@ func collectInserted(rows Rows) insertedNotifications {
 }
 
 func notificationScenarioColumns(nType models.NotificationType) (string, string, error) {
-	switch nType {
-	case models.NotificationTypeFriendRequestReceived:
-		return "in_app_friend_request_received", "email_friend_request_received", nil
-	case models.NotificationTypeFriendRequestAccepted:
-		return "in_app_friend_request_accepted", "email_friend_request_accepted", nil
-	case models.NotificationTypeFriendBingo:
-		return "in_app_friend_bingo", "email_friend_bingo", nil
-	case models.NotificationTypeFriendNewCard:
-		return "in_app_friend_new_card", "email_friend_new_card", nil
-	default:
+	if !slices.Contains(models.NotificationTypes, nType) {
 		return "", "", fmt.Errorf("unsupported notification type: %s", nType)
 	}
+	return notificationTypeColumn(models.NotificationChannelInApp, nType),
+		notificationTypeColumn(models.NotificationChannelEmail, nType), nil
+}
+
+// notificationChannelColumn is the column holding a channel's global switch.
+func notificationChannelColumn(channel models.NotificationChannel) string {
+	return string(channel) + "_enabled"
+}
+
+// notificationTypeColumn is the column holding the flag for one notification
+// type on a channel, e.g. email_friend_bingo.
+func notificationTypeColumn(channel models.NotificationChannel, nType models.NotificationType) string {
+	return string(channel) + "_" + string(nType)
 }
 
 func cardDisplayName(title *string, year *int) string {
@@ -676,7 +1145,8 @@ func enablesEmail(patch models.NotificationSettingsPatch) bool {
 		(patch.EmailFriendRequestReceived != nil && *patch.EmailFriendRequestReceived) ||
 		(patch.EmailFriendRequestAccepted != nil && *patch.EmailFriendRequestAccepted) ||
 		(patch.EmailFriendBingo != nil && *patch.EmailFriendBingo) ||
-		(patch.EmailFriendNewCard != nil && *patch.EmailFriendNewCard)
+		(patch.EmailFriendNewCard != nil && *patch.EmailFriendNewCard) ||
+		(patch.EmailFriendsDigest != nil && *patch.EmailFriendsDigest)
 }
 
 func templateEscape(value string) string {
@@ -21,7 +21,7 @@ func TestNotificationService_MarkReadAndUnreadCountAndCleanupOld(t *testing.T) {
 			switch {
 			case strings.Contains(sql, "UPDATE notifications SET read_at"):
 				return fakeCommandTag{rowsAffected: 0}, nil
-			case strings.Contains(sql, "DELETE FROM notifications WHERE created_at"):
+			case strings.Contains(sql, "DELETE FROM notifications"):
 				return fakeCommandTag{rowsAffected: 1}, nil
 			default:
 				t.Fatalf("unexpected exec sql: %q", sql)
@@ -48,8 +48,8 @@ func TestNotificationService_MarkReadAndUnreadCountAndCleanupOld(t *testing.T) {
 	if count != 7 {
 		t.Fatalf("expected count 7, got %d", count)
 	}
-	if err := svc.CleanupOld(context.Background()); err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	if deleted, err := svc.CleanupOld(context.Background()); err != nil || deleted != 1 {
+		t.Fatalf("expected 1 deleted, got %d, %v", deleted, err)
 	}
 }
 
@@ -77,8 +77,10 @@ func TestNotificationService_UpdateSettings_EmailEnabledRequiresVerifiedEmail(t
 					userID,
 					true, true, true, true, true,
 					true, true, true, true, true,
+					false,
 					time.Now().Add(-time.Hour),
 					time.Now(),
+					nil,
 				)
 			}
 			t.Fatalf("unexpected query sql: %q", sql)
@@ -118,10 +120,13 @@ func TestNotificationService_UpdateSettings_UpdatesWhenVerified(t *testing.T) {
 			if strings.Contains(sql, "FROM notification_settings") {
 				return rowFromValues(
 					userID,
-					true, true, true, true, true,
+					true, true, true, true, true, true, true,
 					true, true, true, friendBingo, true,
+					false,
+					"html",
 					time.Now().Add(-time.Hour),
 					time.Now(),
+					nil,
 				)
 			}
 			t.Fatalf("unexpected query sql: %q", sql)
@@ -150,8 +155,14 @@ func TestNotificationService_NotifyFriendsBingo_NoOpWhenCountZero(t *testing.T)
 		},
 	}
 	svc := NewNotificationService(db, nil, "http://example.com")
-	if err := svc.NotifyFriendsBingo(context.Background(), uuid.New(), uuid.New(), 0); err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	ids, err := svc.NotifyFriendsBingo(context.Background(), &fakeTx{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatal("expected no tx calls when bingoCount<=0")
+			return nil, nil
+		},
+	}, uuid.New(), uuid.New(), 0)
+	if err != nil || ids != nil {
+		t.Fatalf("expected no-op, got %v %v", ids, err)
 	}
 }
 
@@ -171,10 +182,13 @@ func TestNotificationService_DispatchEmails_SendsAndMarksSent(t *testing.T) {
 				t.Fatalf("unexpected query sql: %q", sql)
 			}
 			return &fakeRows{rows: [][]any{
-				{notificationID, string(models.NotificationTypeFriendBingo), "to@test.com", "recipient", &actor, nil, &cardTitle, &cardYear, &bingoCount},
+				{notificationID, uuid.New(), string(models.NotificationTypeFriendBingo), "to@test.com", "recipient", &actor, nil, nil, &cardTitle, &cardYear, &bingoCount},
 			}}, nil
 		},
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "INSERT INTO reminder_unsubscribe_tokens") {
+				return fakeCommandTag{rowsAffected: 1}, nil
+			}
 			if !strings.Contains(sql, "UPDATE notifications SET email_sent_at") {
 				t.Fatalf("unexpected exec sql: %q", sql)
 			}
@@ -193,6 +207,9 @@ func TestNotificationService_DispatchEmails_SendsAndMarksSent(t *testing.T) {
 			if !strings.Contains(text, "View notifications:") {
 				t.Fatalf("expected text to include links, got %q", text)
 			}
+			if !strings.Contains(text, "Email preferences: http://example.com/r/preferences?token=") {
+				t.Fatalf("expected text to link the preferences page, got %q", text)
+			}
 			return nil
 		},
 	}
@@ -201,7 +218,7 @@ func TestNotificationService_DispatchEmails_SendsAndMarksSent(t *testing.T) {
 	svc.asyncCtx = nil
 	svc.SetAsync(func(fn func()) { fn() })
 
-	svc.dispatchEmails([]uuid.UUID{notificationID})
+	svc.DispatchEmails([]uuid.UUID{notificationID})
 	if sentTo != "to@test.com" {
 		t.Fatalf("expected to@test.com, got %q", sentTo)
 	}
@@ -213,6 +230,95 @@ func TestNotificationService_DispatchEmails_SendsAndMarksSent(t *testing.T) {
 	}
 }
 
+func TestNotificationService_DispatchEmails_DefersWhenRateLimited(t *testing.T) {
+	notificationID := uuid.New()
+	var execs []string
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{
+				{notificationID, uuid.New(), string(models.NotificationTypeFriendNewCard), "to@test.com", "recipient", nil, nil, nil, nil, nil, nil},
+			}}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "INSERT INTO reminder_unsubscribe_tokens") {
+				execs = append(execs, sql)
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	emailSvc := stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			if emailWaitAllowed(ctx) {
+				t.Fatal("expected a first attempt not to wait for the limiter")
+			}
+			return ErrEmailRateLimited
+		},
+	}
+
+	svc := NewNotificationService(db, emailSvc, "http://example.com")
+	svc.SetAsync(func(fn func()) { fn() })
+	svc.DispatchEmails([]uuid.UUID{notificationID})
+
+	if len(execs) != 1 || !strings.Contains(execs[0], "SET email_deferred_at = NOW()") {
+		t.Fatalf("expected the email to be deferred, got %q", execs)
+	}
+}
+
+func TestNotificationService_SendDeferredEmails(t *testing.T) {
+	sentID, failedID, limitedID := uuid.New(), uuid.New(), uuid.New()
+	updates := map[uuid.UUID]string{}
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if strings.Contains(sql, "WHERE email_deferred_at IS NOT NULL") {
+				return &fakeRows{rows: [][]any{{sentID}, {failedID}, {limitedID}}}, nil
+			}
+			if ids, ok := args[0].([]uuid.UUID); !ok || len(ids) != 3 {
+				t.Fatalf("expected the deferred ids, got %v", args)
+			}
+			return &fakeRows{rows: [][]any{
+				{sentID, uuid.New(), string(models.NotificationTypeFriendNewCard), "sent@test.com", "a", nil, nil, nil, nil, nil, nil},
+				{failedID, uuid.New(), string(models.NotificationTypeFriendNewCard), "failed@test.com", "b", nil, nil, nil, nil, nil, nil},
+				{limitedID, uuid.New(), string(models.NotificationTypeFriendNewCard), "limited@test.com", "c", nil, nil, nil, nil, nil, nil},
+			}}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if id, ok := args[0].(uuid.UUID); ok && strings.Contains(sql, "UPDATE notifications") {
+				updates[id] = sql
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	emailSvc := stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			if !emailWaitAllowed(ctx) {
+				t.Fatal("expected outbox sends to wait for the limiter")
+			}
+			switch toEmail {
+			case "failed@test.com":
+				return errors.New("provider down")
+			case "limited@test.com":
+				return ErrEmailRateLimited
+			}
+			return nil
+		},
+	}
+
+	svc := NewNotificationService(db, emailSvc, "http://example.com")
+	sent, err := svc.SendDeferredEmails(context.Background())
+	if err != nil || sent != 1 {
+		t.Fatalf("expected one email sent, got %d %v", sent, err)
+	}
+	if !strings.Contains(updates[sentID], "email_sent_at = NOW(), email_deferred_at = NULL") {
+		t.Fatalf("expected the sent email to leave the outbox, got %q", updates[sentID])
+	}
+	if !strings.Contains(updates[failedID], "SET email_deferred_at = NULL") {
+		t.Fatalf("expected the failed email to be dropped, got %q", updates[failedID])
+	}
+	if !strings.Contains(updates[limitedID], "SET email_deferred_at = NOW()") {
+		t.Fatalf("expected the refused email to stay deferred, got %q", updates[limitedID])
+	}
+}
+
 func TestNotificationService_BuildNotificationEmail_CoversScenarios(t *testing.T) {
 	svc := NewNotificationService(&fakeDB{}, stubEmailService{}, "http://example.com")
 
@@ -221,18 +327,191 @@ func TestNotificationService_BuildNotificationEmail_CoversScenarios(t *testing.T
 	year := 2025
 	bingos := 3
 
-	subject, html, text := svc.buildNotificationEmail(models.NotificationTypeFriendRequestAccepted, &actor, &title, &year, nil)
+	subject, html, text := svc.buildNotificationEmail(models.NotificationTypeFriendRequestAccepted, &actor, nil, &title, &year, nil, "")
 	if !strings.Contains(subject, "accepted") || !strings.Contains(html, "accepted") || !strings.Contains(text, "accepted") {
 		t.Fatalf("expected accepted notification text, got subject=%q", subject)
 	}
 
-	subject, _, _ = svc.buildNotificationEmail(models.NotificationTypeFriendBingo, &actor, &title, &year, &bingos)
+	subject, _, _ = svc.buildNotificationEmail(models.NotificationTypeFriendBingo, &actor, nil, &title, &year, &bingos, "")
 	if !strings.Contains(subject, "bingo") {
 		t.Fatalf("expected bingo subject, got %q", subject)
 	}
 
-	subject, _, _ = svc.buildNotificationEmail(models.NotificationTypeFriendNewCard, nil, nil, nil, nil)
+	subject, _, _ = svc.buildNotificationEmail(models.NotificationTypeFriendNewCard, nil, nil, nil, nil, nil, "")
 	if !strings.Contains(subject, "new") {
 		t.Fatalf("expected new-card subject, got %q", subject)
 	}
 }
+
+func TestNotificationService_NotifyFriendsNewCard_QueuesOnInsertFailure(t *testing.T) {
+	actorID := uuid.New()
+	cardID := uuid.New()
+	var execs []string
+	var queuedArgs []any
+	tx := &fakeTx{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return nil, errors.New("insert failed")
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			execs = append(execs, sql)
+			if strings.Contains(sql, "INSERT INTO pending_notifications") {
+				queuedArgs = args
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	svc := NewNotificationService(&fakeDB{}, nil, "http://example.com")
+	ids, err := svc.NotifyFriendsNewCard(context.Background(), tx, actorID, cardID)
+	if err == nil || !strings.Contains(err.Error(), "queued for retry") {
+		t.Fatalf("expected queued error, got %v", err)
+	}
+	if ids != nil {
+		t.Fatalf("expected no email ids, got %v", ids)
+	}
+	if len(execs) != 4 ||
+		!strings.Contains(execs[0], "SAVEPOINT friend_notifications") ||
+		!strings.Contains(execs[1], "ROLLBACK TO SAVEPOINT") ||
+		!strings.Contains(execs[2], "INSERT INTO pending_notifications") ||
+		!strings.Contains(execs[3], "RELEASE SAVEPOINT") {
+		t.Fatalf("unexpected exec sequence: %v", execs)
+	}
+	if queuedArgs[0] != string(models.NotificationTypeFriendNewCard) || queuedArgs[1] != actorID || queuedArgs[2] != cardID {
+		t.Fatalf("unexpected queued args: %v", queuedArgs)
+	}
+}
+
+func TestNotificationService_ReconcilePending(t *testing.T) {
+	pendingID := uuid.New()
+	pendingRows := func() Rows {
+		return &fakeRows{rows: [][]any{{pendingID, string(models.NotificationTypeFriendNewCard), uuid.New(), uuid.New(), (*int)(nil)}}}
+	}
+
+	t.Run("delivers and removes", func(t *testing.T) {
+		committed := false
+		tx := &fakeTx{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+				if !strings.Contains(sql, "DELETE FROM pending_notifications") || args[0] != pendingID {
+					t.Fatalf("unexpected tx exec: %q", sql)
+				}
+				return fakeCommandTag{rowsAffected: 1}, nil
+			},
+			QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+				return &fakeRows{rows: [][]any{{uuid.New(), uuid.New(), true}}}, nil
+			},
+			CommitFunc: func(ctx context.Context) error {
+				committed = true
+				return nil
+			},
+		}
+		db := &fakeDB{
+			QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+				return pendingRows(), nil
+			},
+			BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil },
+		}
+
+		svc := NewNotificationService(db, nil, "http://example.com")
+		delivered, err := svc.ReconcilePending(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if delivered != 1 || !committed {
+			t.Fatalf("expected one committed delivery, got %d committed=%v", delivered, committed)
+		}
+	})
+
+	t.Run("failure bumps attempts then drops", func(t *testing.T) {
+		for _, tc := range []struct {
+			name        string
+			bumped      int64
+			wantDropped bool
+		}{
+			{"bumped", 1, false},
+			{"exhausted", 0, true},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				tx := &fakeTx{
+					ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+						return fakeCommandTag{rowsAffected: 1}, nil
+					},
+					QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+						return nil, errors.New("still failing")
+					},
+					CommitFunc: func(ctx context.Context) error {
+						t.Fatal("failed retry should not commit")
+						return nil
+					},
+				}
+				dropped := false
+				db := &fakeDB{
+					QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+						return pendingRows(), nil
+					},
+					BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil },
+					ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+						if strings.Contains(sql, "UPDATE pending_notifications SET attempts = attempts + 1") {
+							if args[2] != maxPendingNotificationAttempts {
+								t.Fatalf("expected attempts cap arg, got %v", args)
+							}
+							return fakeCommandTag{rowsAffected: tc.bumped}, nil
+						}
+						if strings.Contains(sql, "DELETE FROM pending_notifications") {
+							dropped = true
+							return fakeCommandTag{rowsAffected: 1}, nil
+						}
+						t.Fatalf("unexpected exec: %q", sql)
+						return nil, nil
+					},
+				}
+
+				svc := NewNotificationService(db, nil, "http://example.com")
+				delivered, err := svc.ReconcilePending(context.Background())
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if delivered != 0 || dropped != tc.wantDropped {
+					t.Fatalf("expected delivered=0 dropped=%v, got %d %v", tc.wantDropped, delivered, dropped)
+				}
+			})
+		}
+	})
+}
+
+func TestNotificationService_UpdateSettings_EmailFormat(t *testing.T) {
+	userID := uuid.New()
+	var updateSQL string
+	var updateArgs []any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE notification_settings SET") {
+				updateSQL, updateArgs = sql, args
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(userID, true, true, true, true, true, true, true, true, true, true, true, true, false, "text", time.Now(), time.Now(), nil)
+		},
+	}
+	svc := NewNotificationService(db, nil, "http://example.com")
+
+	bad := "rtf"
+	if _, err := svc.UpdateSettings(context.Background(), userID, models.NotificationSettingsPatch{EmailFormat: &bad}); !errors.Is(err, ErrInvalidEmailFormat) {
+		t.Fatalf("expected ErrInvalidEmailFormat, got %v", err)
+	}
+	if updateSQL != "" {
+		t.Fatalf("expected no update for an invalid format, got %q", updateSQL)
+	}
+
+	text := models.EmailFormatText
+	settings, err := svc.UpdateSettings(context.Background(), userID, models.NotificationSettingsPatch{EmailFormat: &text})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(updateSQL, "email_format = $1") || updateArgs[0] != "text" {
+		t.Fatalf("expected email_format update, got %q %v", updateSQL, updateArgs)
+	}
+	if settings.EmailFormat != models.EmailFormatText {
+		t.Fatalf("expected text format in settings, got %q", settings.EmailFormat)
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -11,8 +12,12 @@ import (
 type stubNotificationService struct {
 	NotifyFriendRequestReceivedFunc func(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error
 	NotifyFriendRequestAcceptedFunc func(ctx context.Context, recipientID, actorID, friendshipID uuid.UUID) error
-	NotifyFriendsNewCardFunc        func(ctx context.Context, actorID, cardID uuid.UUID) error
-	NotifyFriendsBingoFunc          func(ctx context.Context, actorID, cardID uuid.UUID, bingoCount int) error
+	NotifyItemCommentFunc           func(ctx context.Context, recipientID, actorID, cardID uuid.UUID) error
+	NotifyReminderCheckinFunc       func(ctx context.Context, tx Tx, userID, cardID uuid.UUID, sentAt time.Time) error
+	NotifyReminderGoalFunc          func(ctx context.Context, tx Tx, userID, cardID, itemID uuid.UUID, sentAt time.Time) error
+	NotifyFriendsNewCardFunc        func(ctx context.Context, tx Tx, actorID, cardID uuid.UUID) ([]uuid.UUID, error)
+	NotifyFriendsBingoFunc          func(ctx context.Context, tx Tx, actorID, cardID uuid.UUID, bingoCount int) ([]uuid.UUID, error)
+	DispatchEmailsFunc              func(notificationIDs []uuid.UUID)
 }
 
 func (s *stubNotificationService) GetSettings(ctx context.Context, userID uuid.UUID) (*models.NotificationSettings, error) {
@@ -23,8 +28,16 @@ func (s *stubNotificationService) UpdateSettings(ctx context.Context, userID uui
 	return &models.NotificationSettings{}, nil
 }
 
-func (s *stubNotificationService) List(ctx context.Context, userID uuid.UUID, params NotificationListParams) ([]models.Notification, error) {
-	return []models.Notification{}, nil
+func (s *stubNotificationService) CopySettings(ctx context.Context, userID uuid.UUID, from, to models.NotificationChannel) (*models.NotificationSettings, error) {
+	return &models.NotificationSettings{}, nil
+}
+
+func (s *stubNotificationService) SetEmailPause(ctx context.Context, userID uuid.UUID, until *time.Time) (*models.NotificationSettings, error) {
+	return &models.NotificationSettings{EmailPausedUntil: until}, nil
+}
+
+func (s *stubNotificationService) List(ctx context.Context, userID uuid.UUID, params NotificationListParams) ([]models.Notification, bool, error) {
+	return []models.Notification{}, false, nil
 }
 
 func (s *stubNotificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
@@ -61,16 +74,43 @@ func (s *stubNotificationService) NotifyFriendRequestAccepted(ctx context.Contex
 	return nil
 }
 
-func (s *stubNotificationService) NotifyFriendsNewCard(ctx context.Context, actorID, cardID uuid.UUID) error {
-	if s.NotifyFriendsNewCardFunc != nil {
-		return s.NotifyFriendsNewCardFunc(ctx, actorID, cardID)
+func (s *stubNotificationService) NotifyItemComment(ctx context.Context, recipientID, actorID, cardID uuid.UUID) error {
+	if s.NotifyItemCommentFunc != nil {
+		return s.NotifyItemCommentFunc(ctx, recipientID, actorID, cardID)
 	}
 	return nil
 }
 
-func (s *stubNotificationService) NotifyFriendsBingo(ctx context.Context, actorID, cardID uuid.UUID, bingoCount int) error {
-	if s.NotifyFriendsBingoFunc != nil {
-		return s.NotifyFriendsBingoFunc(ctx, actorID, cardID, bingoCount)
+func (s *stubNotificationService) NotifyReminderCheckin(ctx context.Context, tx Tx, userID, cardID uuid.UUID, sentAt time.Time) error {
+	if s.NotifyReminderCheckinFunc != nil {
+		return s.NotifyReminderCheckinFunc(ctx, tx, userID, cardID, sentAt)
+	}
+	return nil
+}
+
+func (s *stubNotificationService) NotifyReminderGoal(ctx context.Context, tx Tx, userID, cardID, itemID uuid.UUID, sentAt time.Time) error {
+	if s.NotifyReminderGoalFunc != nil {
+		return s.NotifyReminderGoalFunc(ctx, tx, userID, cardID, itemID, sentAt)
 	}
 	return nil
 }
+
+func (s *stubNotificationService) NotifyFriendsNewCard(ctx context.Context, tx Tx, actorID, cardID uuid.UUID) ([]uuid.UUID, error) {
+	if s.NotifyFriendsNewCardFunc != nil {
+		return s.NotifyFriendsNewCardFunc(ctx, tx, actorID, cardID)
+	}
+	return nil, nil
+}
+
+func (s *stubNotificationService) NotifyFriendsBingo(ctx context.Context, tx Tx, actorID, cardID uuid.UUID, bingoCount int) ([]uuid.UUID, error) {
+	if s.NotifyFriendsBingoFunc != nil {
+		return s.NotifyFriendsBingoFunc(ctx, tx, actorID, cardID, bingoCount)
+	}
+	return nil, nil
+}
+
+func (s *stubNotificationService) DispatchEmails(notificationIDs []uuid.UUID) {
+	if s.DispatchEmailsFunc != nil {
+		s.DispatchEmailsFunc(notificationIDs)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
 )
 
 func TestNotificationService_GetSettings_CreatesRow(t *testing.T) {
@@ -33,13 +34,18 @@ func TestNotificationService_GetSettings_CreatesRow(t *testing.T) {
 				true,
 				true,
 				true,
+				true,
+				true,
 				false,
 				false,
 				false,
 				false,
 				false,
+				false,
+				"html",
 				time.Now(),
 				time.Now(),
+				nil,
 			)
 		},
 	}
@@ -82,8 +88,10 @@ func TestNotificationService_UpdateSettings_EmailRequiresVerification(t *testing
 				false,
 				false,
 				false,
+				false,
 				time.Now(),
 				time.Now(),
+				nil,
 			)
 		},
 		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
@@ -118,7 +126,7 @@ func TestNotificationService_List_UnreadOnlyFilters(t *testing.T) {
 	}
 
 	svc := NewNotificationService(db, nil, "http://example.com")
-	_, err := svc.List(context.Background(), userID, NotificationListParams{
+	_, _, err := svc.List(context.Background(), userID, NotificationListParams{
 		Limit:      10,
 		UnreadOnly: true,
 	})
@@ -202,15 +210,15 @@ func TestNotificationService_NotifyFriendsNewCard_UsesSettingsAndBlocks(t *testi
 	actorID := uuid.New()
 	cardID := uuid.New()
 	var gotSQL string
-	db := &fakeDB{
+	tx := &fakeTx{
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
 			gotSQL = sql
 			return &fakeRows{rows: [][]any{}}, nil
 		},
 	}
 
-	svc := NewNotificationService(db, nil, "http://example.com")
-	if err := svc.NotifyFriendsNewCard(context.Background(), actorID, cardID); err != nil {
+	svc := NewNotificationService(&fakeDB{}, nil, "http://example.com")
+	if _, err := svc.NotifyFriendsNewCard(context.Background(), tx, actorID, cardID); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if !strings.Contains(gotSQL, "FROM friendships") {
@@ -255,11 +263,257 @@ func TestNotificationService_NotifyFriendRequestReceived_UsesScenarioToggles(t *
 	if !strings.Contains(gotSQL, "COALESCE(ns.in_app_enabled, true)") {
 		t.Fatalf("expected default in-app settings for missing rows, got %q", gotSQL)
 	}
+	if !strings.Contains(gotSQL, "ns.email_paused_until") {
+		t.Fatalf("expected email pause gating, got %q", gotSQL)
+	}
 	if !strings.Contains(gotSQL, "ON CONFLICT DO NOTHING") {
 		t.Fatalf("expected ON CONFLICT DO NOTHING, got %q", gotSQL)
 	}
 }
 
+func TestNotificationService_SetEmailPause(t *testing.T) {
+	userID := uuid.New()
+	until := time.Now().Add(7 * 24 * time.Hour)
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "SET email_paused_until") {
+				gotArgs = args
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(
+				userID,
+				true, true, true, true, true, true, true,
+				true, true, true, true, true,
+				false,
+				"html",
+				time.Now(),
+				time.Now(),
+				&until,
+			)
+		},
+	}
+
+	svc := NewNotificationService(db, nil, "http://example.com")
+	settings, err := svc.SetEmailPause(context.Background(), userID, &until)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gotArgs) != 2 || gotArgs[1] != userID {
+		t.Fatalf("expected pause update for %v, got %v", userID, gotArgs)
+	}
+	if got, ok := gotArgs[0].(*time.Time); !ok || got == nil || !got.Equal(until) {
+		t.Fatalf("expected pause until %v, got %v", until, gotArgs[0])
+	}
+	if settings.EmailPausedUntil == nil || !settings.EmailPausedUntil.Equal(until) {
+		t.Fatalf("expected settings to report pause, got %v", settings.EmailPausedUntil)
+	}
+
+	gotArgs = nil
+	if _, err := svc.SetEmailPause(context.Background(), userID, nil); err != nil {
+		t.Fatalf("unexpected error clearing pause: %v", err)
+	}
+	if got, _ := gotArgs[0].(*time.Time); got != nil {
+		t.Fatalf("expected pause to be cleared, got %v", got)
+	}
+}
+
+func TestNotificationService_SetEmailPause_RejectsInvalidTimes(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatalf("unexpected exec: %q", sql)
+			return nil, nil
+		},
+	}
+	svc := NewNotificationService(db, nil, "http://example.com")
+
+	past := time.Now().Add(-time.Hour)
+	tooFar := time.Now().Add(2 * 366 * 24 * time.Hour)
+	for _, until := range []time.Time{past, tooFar} {
+		if _, err := svc.SetEmailPause(context.Background(), uuid.New(), &until); !errors.Is(err, ErrInvalidEmailPause) {
+			t.Fatalf("expected ErrInvalidEmailPause for %v, got %v", until, err)
+		}
+	}
+}
+
 func boolPtr(v bool) *bool {
 	return &v
 }
+
+func TestNotificationService_CopySettings(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "solo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	svc := NewNotificationService(db, nil, "http://example.com")
+
+	for _, pair := range [][2]models.NotificationChannel{{"in_app", "push"}, {"sms", "email"}, {"", ""}} {
+		if _, err := svc.CopySettings(ctx, user.ID, pair[0], pair[1]); !errors.Is(err, ErrInvalidNotificationChannel) {
+			t.Fatalf("expected ErrInvalidNotificationChannel for %v, got %v", pair, err)
+		}
+	}
+	if _, err := svc.CopySettings(ctx, user.ID, models.NotificationChannelInApp, models.NotificationChannelEmail); !errors.Is(err, ErrNotificationChannelDisabled) {
+		t.Fatalf("expected ErrNotificationChannelDisabled, got %v", err)
+	}
+
+	if _, err := db.Exec(ctx, "UPDATE users SET email_verified = true WHERE id = $1", user.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.UpdateSettings(ctx, user.ID, models.NotificationSettingsPatch{
+		InAppFriendBingo:   boolPtr(false),
+		EmailEnabled:       boolPtr(true),
+		EmailFriendsDigest: boolPtr(true),
+	}); err != nil {
+		t.Fatalf("unexpected error updating settings: %v", err)
+	}
+
+	settings, err := svc.CopySettings(ctx, user.ID, models.NotificationChannelInApp, models.NotificationChannelEmail)
+	if err != nil {
+		t.Fatalf("unexpected error copying: %v", err)
+	}
+	for _, nType := range models.NotificationTypes {
+		want := nType != models.NotificationTypeFriendBingo
+		if got := settings.TypeEnabled(models.NotificationChannelEmail, nType); got != want {
+			t.Fatalf("expected email %s = %v, got %v", nType, want, got)
+		}
+		if got := settings.TypeEnabled(models.NotificationChannelInApp, nType); got != want {
+			t.Fatalf("expected in-app %s to be unchanged, got %v", nType, got)
+		}
+	}
+	if !settings.EmailEnabled || !settings.EmailFriendsDigest {
+		t.Fatalf("expected the email switch and digest to be left alone, got %+v", settings)
+	}
+}
+
+func TestNotificationService_NotifyReminder_OncePerOccurrence(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "solo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	cards := NewCardService(db)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: 2026, GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	item, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: "Run a 10k"})
+	if err != nil {
+		t.Fatalf("unexpected error adding item: %v", err)
+	}
+	if _, err := db.Exec(ctx,
+		"INSERT INTO goal_reminders (user_id, card_id, item_id, kind, schedule) VALUES ($1, $2, $3, 'recurring', '{}')",
+		user.ID, card.ID, item.ID,
+	); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := NewNotificationService(db, nil, "http://example.com")
+
+	notify := func(sentAt time.Time) {
+		t.Helper()
+		tx, err := db.Begin(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := svc.NotifyReminderGoal(ctx, tx, user.ID, card.ID, item.ID, sentAt); err != nil {
+			t.Fatalf("unexpected error notifying: %v", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	count := func() int {
+		t.Helper()
+		n, err := svc.UnreadCount(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return n
+	}
+
+	first := time.Now().Add(-2 * time.Hour)
+	// A failed email is retried without advancing last_sent_at.
+	notify(first)
+	notify(first.Add(15 * time.Minute))
+	if got := count(); got != 1 {
+		t.Fatalf("expected one notification for a retried occurrence, got %d", got)
+	}
+	list, _, err := svc.List(ctx, user.ID, NotificationListParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list[0].Type != models.NotificationTypeReminderGoal || list[0].ItemContent == nil || *list[0].ItemContent != "Run a 10k" || list[0].CardYear == nil {
+		t.Fatalf("expected the goal and card on the notification, got %+v", list[0])
+	}
+
+	// The send finally succeeds; the next occurrence gets its own.
+	sentAt := first.Add(30 * time.Minute)
+	if _, err := db.Exec(ctx, "UPDATE goal_reminders SET last_sent_at = $1 WHERE item_id = $2", sentAt, item.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notify(sentAt.Add(time.Hour))
+	if got := count(); got != 2 {
+		t.Fatalf("expected a notification for the next occurrence, got %d", got)
+	}
+
+	if _, err := svc.UpdateSettings(ctx, user.ID, models.NotificationSettingsPatch{InAppReminderGoal: boolPtr(false)}); err != nil {
+		t.Fatalf("unexpected error updating settings: %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE goal_reminders SET last_sent_at = $1 WHERE item_id = $2", time.Now(), item.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notify(time.Now().Add(time.Minute))
+	if got := count(); got != 2 {
+		t.Fatalf("expected the setting to suppress goal reminder notifications, got %d", got)
+	}
+}
+
+func TestNotificationService_List_PagesThroughTiesWithoutGapsOrRepeats(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "pages@example.com", Username: "pages"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Three of the five share a timestamp, so a page boundary falls inside
+	// the tie.
+	base := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	for _, createdAt := range []time.Time{base, base, base, base.Add(-time.Minute), base.Add(time.Minute)} {
+		if _, err := db.Exec(ctx, "INSERT INTO notifications (user_id, type, created_at) VALUES ($1, 'friend_new_card', $2)", user.ID, createdAt); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	svc := NewNotificationService(db, nil, "http://example.com")
+	seen := map[uuid.UUID]bool{}
+	var after *pagination.Key
+	var previous time.Time
+	for page := 0; ; page++ {
+		list, hasMore, err := svc.List(ctx, user.ID, NotificationListParams{Limit: 2, After: after})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, n := range list {
+			if seen[n.ID] {
+				t.Fatalf("notification %s repeated on page %d", n.ID, page)
+			}
+			if !previous.IsZero() && n.CreatedAt.After(previous) {
+				t.Fatalf("expected newest first, got %v after %v", n.CreatedAt, previous)
+			}
+			seen[n.ID], previous = true, n.CreatedAt
+		}
+		if !hasMore {
+			break
+		}
+		last := list[len(list)-1]
+		after = &pagination.Key{At: last.CreatedAt, ID: last.ID}
+	}
+	if len(seen) != 5 {
+		t.Fatalf("expected all 5 notifications across pages, got %d", len(seen))
+	}
+}
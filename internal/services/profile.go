@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/bingo"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// MaxPublicProfileCards caps how many cards a public profile lists.
+const MaxPublicProfileCards = 24
+
+var (
+	ErrProfileNotFound          = errors.New("profile not found")
+	ErrInvalidProfileVisibility = errors.New("invalid profile visibility")
+)
+
+type ProfileService struct {
+	db      DB
+	baseURL string
+}
+
+func NewProfileService(db DB, baseURL string) *ProfileService {
+	return &ProfileService{db: db, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (s *ProfileService) GetSettings(ctx context.Context, userID uuid.UUID) (*models.ProfileSettings, error) {
+	var username string
+	settings := &models.ProfileSettings{}
+	err := s.db.QueryRow(ctx,
+		`SELECT username, profile_visibility, profile_indexable
+		 FROM users
+		 WHERE id = $1 AND deleted_at IS NULL`,
+		userID,
+	).Scan(&username, &settings.Visibility, &settings.Indexable)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading profile settings: %w", err)
+	}
+	s.decorate(settings, username)
+	return settings, nil
+}
+
+// UpdateSettings changes the public profile settings; nil fields are left as
+// they are.
+func (s *ProfileService) UpdateSettings(ctx context.Context, userID uuid.UUID, visibility *string, indexable *bool) (*models.ProfileSettings, error) {
+	if visibility != nil && *visibility != models.ProfileVisibilityOff && *visibility != models.ProfileVisibilityPublic {
+		return nil, ErrInvalidProfileVisibility
+	}
+
+	var username string
+	settings := &models.ProfileSettings{}
+	err := s.db.QueryRow(ctx,
+		`UPDATE users
+		 SET profile_visibility = COALESCE($2, profile_visibility),
+		     profile_indexable = COALESCE($3, profile_indexable),
+		     updated_at = NOW()
+		 WHERE id = $1 AND deleted_at IS NULL
+		 RETURNING username, profile_visibility, profile_indexable`,
+		userID, visibility, indexable,
+	).Scan(&username, &settings.Visibility, &settings.Indexable)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("updating profile settings: %w", err)
+	}
+	s.decorate(settings, username)
+	return settings, nil
+}
+
+func (s *ProfileService) decorate(settings *models.ProfileSettings, username string) {
+	settings.URL = s.baseURL + "/u/" + username
+	settings.Notice = models.ProfilePublicNotice
+}
+
+// GetPublicProfile loads the public profile for username. Users who have not
+// opted in, and deleted users, are reported as ErrProfileNotFound.
+func (s *ProfileService) GetPublicProfile(ctx context.Context, username string) (*models.PublicProfile, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, ErrProfileNotFound
+	}
+
+	profile := &models.PublicProfile{}
+	err := s.db.QueryRow(ctx,
+		`SELECT id, username, profile_indexable
+		 FROM users
+		 WHERE LOWER(username) = LOWER($1)
+		   AND deleted_at IS NULL
+		   AND profile_visibility = 'public'`,
+		username,
+	).Scan(&profile.UserID, &profile.Username, &profile.Indexable)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading public profile: %w", err)
+	}
+
+	// Only finalized cards the owner already shows to friends are listed.
+	rows, err := s.db.Query(ctx,
+		`SELECT c.id, c.year, c.title, c.category, c.grid_size, c.has_free_space, c.free_space_position,
+		        c.is_archived, bi.position, bi.is_completed
+		 FROM (
+		     SELECT id, year, title, category, grid_size, has_free_space, free_space_position, is_archived, created_at
+		     FROM bingo_cards
+		     WHERE user_id = $1 AND is_finalized = true AND visible_to_friends = true
+		     ORDER BY year DESC, created_at DESC
+		     LIMIT $2
+		 ) c
+		 LEFT JOIN bingo_items bi ON bi.card_id = c.id
+		 ORDER BY c.year DESC, c.created_at DESC, bi.position`,
+		profile.UserID, MaxPublicProfileCards,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("loading public profile cards: %w", err)
+	}
+	defer rows.Close()
+
+	profile.Cards = []models.PublicProfileCard{}
+	var (
+		currentID uuid.UUID
+		current   *models.BingoCard
+		archived  bool
+	)
+	flush := func() {
+		if current == nil {
+			return
+		}
+		completed := 0
+		for _, item := range current.Items {
+			if item.IsCompleted {
+				completed++
+			}
+		}
+		profile.Cards = append(profile.Cards, models.PublicProfileCard{
+			Year:           current.Year,
+			Title:          current.Title,
+			Category:       current.Category,
+			GridSize:       current.GridSize,
+			IsArchived:     archived,
+			CompletedItems: completed,
+			TotalItems:     current.Capacity(),
+			Bingos:         bingo.CountBingos(current.Items, current.GridSize, current.FreeSpacePos),
+		})
+	}
+	for rows.Next() {
+		var (
+			card        models.BingoCard
+			isArchived  bool
+			position    *int
+			isCompleted *bool
+		)
+		if err := rows.Scan(
+			&card.ID, &card.Year, &card.Title, &card.Category, &card.GridSize, &card.HasFreeSpace, &card.FreeSpacePos,
+			&isArchived, &position, &isCompleted,
+		); err != nil {
+			return nil, fmt.Errorf("scanning public profile card: %w", err)
+		}
+		if current == nil || card.ID != currentID {
+			flush()
+			currentID = card.ID
+			current = &card
+			archived = isArchived
+		}
+		if position != nil {
+			current.Items = append(current.Items, models.BingoItem{
+				Position:    *position,
+				IsCompleted: isCompleted != nil && *isCompleted,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating public profile cards: %w", err)
+	}
+	flush()
+
+	return profile, nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestProfileService_GetSettings(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues("alice", models.ProfileVisibilityPublic, false)
+		},
+	}
+	svc := NewProfileService(db, "https://example.com/")
+
+	settings, err := svc.GetSettings(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Visibility != models.ProfileVisibilityPublic || settings.Indexable {
+		t.Fatalf("unexpected settings: %+v", settings)
+	}
+	if settings.URL != "https://example.com/u/alice" {
+		t.Fatalf("unexpected url: %q", settings.URL)
+	}
+	if settings.Notice != models.ProfilePublicNotice {
+		t.Fatalf("expected public notice, got %q", settings.Notice)
+	}
+}
+
+func TestProfileService_GetSettings_UserNotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	_, err := NewProfileService(db, "").GetSettings(context.Background(), uuid.New())
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestProfileService_UpdateSettings(t *testing.T) {
+	t.Run("rejects unknown visibility", func(t *testing.T) {
+		visibility := "friends"
+		_, err := NewProfileService(&fakeDB{}, "").UpdateSettings(context.Background(), uuid.New(), &visibility, nil)
+		if !errors.Is(err, ErrInvalidProfileVisibility) {
+			t.Fatalf("expected ErrInvalidProfileVisibility, got %v", err)
+		}
+	})
+
+	t.Run("passes optional fields", func(t *testing.T) {
+		var gotArgs []any
+		db := &fakeDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				gotArgs = args
+				return rowFromValues("alice", models.ProfileVisibilityOff, true)
+			},
+		}
+		indexable := true
+		settings, err := NewProfileService(db, "").UpdateSettings(context.Background(), uuid.New(), nil, &indexable)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !settings.Indexable {
+			t.Fatalf("expected indexable settings, got %+v", settings)
+		}
+		if len(gotArgs) != 3 || gotArgs[1].(*string) != nil || gotArgs[2].(*bool) != &indexable {
+			t.Fatalf("unexpected args: %v", gotArgs)
+		}
+	})
+}
+
+func TestProfileService_GetPublicProfile_NotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "profile_visibility = 'public'") || !strings.Contains(sql, "deleted_at IS NULL") {
+				t.Fatalf("expected opt-in and deleted filters, got %s", sql)
+			}
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	svc := NewProfileService(db, "")
+
+	if _, err := svc.GetPublicProfile(context.Background(), "alice"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound, got %v", err)
+	}
+	if _, err := svc.GetPublicProfile(context.Background(), "  "); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound for blank username, got %v", err)
+	}
+}
+
+func TestProfileService_GetPublicProfile_AggregatesCards(t *testing.T) {
+	userID := uuid.New()
+	fullCard := uuid.New()
+	emptyCard := uuid.New()
+	title := "Big year"
+	freePos := 4
+
+	var rows [][]any
+	// 3x3 card with a free centre and the top row complete.
+	for pos := 0; pos < 9; pos++ {
+		if pos == freePos {
+			continue
+		}
+		rows = append(rows, []any{fullCard, 2026, &title, nil, 3, true, &freePos, false, intPtr(pos), boolPtr(pos < 3)})
+	}
+	rows = append(rows, []any{emptyCard, 2025, nil, nil, 5, false, nil, true, nil, nil})
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(userID, "alice", true)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "is_finalized = true") || !strings.Contains(sql, "visible_to_friends = true") {
+				t.Fatalf("expected finalized, friend-visible filter, got %s", sql)
+			}
+			if args[0] != userID || args[1] != MaxPublicProfileCards {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return &fakeRows{rows: rows}, nil
+		},
+	}
+
+	profile, err := NewProfileService(db, "").GetPublicProfile(context.Background(), "Alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.Username != "alice" || !profile.Indexable {
+		t.Fatalf("unexpected profile: %+v", profile)
+	}
+	if len(profile.Cards) != 2 {
+		t.Fatalf("expected 2 cards, got %d", len(profile.Cards))
+	}
+
+	first := profile.Cards[0]
+	if first.CompletedItems != 3 || first.TotalItems != 8 || first.Bingos != 1 {
+		t.Fatalf("unexpected first card: %+v", first)
+	}
+	if first.Title == nil || *first.Title != title {
+		t.Fatalf("expected title, got %v", first.Title)
+	}
+
+	second := profile.Cards[1]
+	if second.CompletedItems != 0 || second.TotalItems != 25 || second.Bingos != 0 || !second.IsArchived {
+		t.Fatalf("unexpected second card: %+v", second)
+	}
+}
+
+func TestProfileService_GetPublicProfile_NoCards(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(uuid.New(), "alice", false)
+		},
+	}
+	profile, err := NewProfileService(db, "").GetPublicProfile(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.Cards == nil || len(profile.Cards) != 0 {
+		t.Fatalf("expected empty card list, got %v", profile.Cards)
+	}
+}
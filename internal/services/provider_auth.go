@@ -19,6 +19,9 @@ var (
 	ErrProviderIdentityExists  = errors.New("provider identity already linked")
 	ErrInvalidProviderPending  = errors.New("invalid provider pending record")
 	ErrInvalidUsername         = errors.New("invalid username")
+	ErrProviderAlreadyLinked   = errors.New("provider already linked to this account")
+	ErrProviderNotLinked       = errors.New("provider not linked")
+	ErrLastLoginMethod         = errors.New("cannot remove the last login method")
 )
 
 type PendingProviderUser struct {
@@ -40,6 +43,13 @@ func NewProviderAuthService(db DB) *ProviderAuthService {
 	return &ProviderAuthService{db: db}
 }
 
+// LinkOrFindUserFromProvider resolves a provider login to an account.
+// Identities are keyed by (provider, subject), so a linked account is found
+// even after its owner changes their email or the provider reports a
+// different address; the account's own email is never rewritten from claims.
+// Only when the subject is unlinked does it fall back to matching the
+// provider's email, and only if the provider has verified it. An address no
+// account holds any longer yields a pending signup rather than a link.
 func (s *ProviderAuthService) LinkOrFindUserFromProvider(ctx context.Context, claims IdentityClaims) (*ProviderLinkResult, error) {
 	provider := strings.TrimSpace(string(claims.Provider))
 	subject := strings.TrimSpace(claims.Subject)
@@ -105,7 +115,7 @@ func (s *ProviderAuthService) CreateUserFromProviderPending(ctx context.Context,
 		return nil, ErrInvalidUsername
 	}
 
-	tx, err := s.db.Begin(ctx)
+	tx, ctx, err := beginTx(ctx, s.db)
 	if err != nil {
 		return nil, fmt.Errorf("starting transaction: %w", err)
 	}
@@ -156,8 +166,165 @@ func (s *ProviderAuthService) CreateUserFromProviderPending(ctx context.Context,
 	return user, nil
 }
 
+// ListIdentities returns the providers linked to a user, oldest first.
+func (s *ProviderAuthService) ListIdentities(ctx context.Context, userID uuid.UUID) ([]models.LinkedIdentity, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT provider, email_at_link_time, linked_at
+		 FROM user_identities
+		 WHERE user_id = $1
+		 ORDER BY linked_at, provider`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := []models.LinkedIdentity{}
+	for rows.Next() {
+		var identity models.LinkedIdentity
+		if err := rows.Scan(&identity.Provider, &identity.Email, &identity.LinkedAt); err != nil {
+			return nil, fmt.Errorf("scanning identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing identities: %w", err)
+	}
+	return identities, nil
+}
+
+// LinkProviderToUser attaches a provider identity to a signed-in user. Unlike
+// LinkOrFindUserFromProvider the provider's email doesn't have to match or
+// be verified, because the user proved control of both accounts, and it
+// doesn't verify the account's own email. Relinking the same identity is a
+// no-op; an identity held by another account, or a second identity from a
+// provider the user already linked, is refused.
+func (s *ProviderAuthService) LinkProviderToUser(ctx context.Context, userID uuid.UUID, claims IdentityClaims) error {
+	subject := strings.TrimSpace(claims.Subject)
+	if strings.TrimSpace(string(claims.Provider)) == "" || subject == "" {
+		return ErrInvalidProviderClaims
+	}
+
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	// Linking and unlinking lock the user so the last-method check in
+	// UnlinkProvider sees every identity.
+	if _, err := s.lockUser(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	existing, err := s.getUserByProviderSubject(ctx, claims.Provider, subject, tx)
+	if err == nil {
+		if existing.ID == userID {
+			return nil
+		}
+		return ErrProviderIdentityExists
+	}
+	if !errors.Is(err, ErrUserNotFound) {
+		return err
+	}
+
+	var linked bool
+	if err := tx.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM user_identities WHERE user_id = $1 AND provider = $2)",
+		userID, claims.Provider,
+	).Scan(&linked); err != nil {
+		return fmt.Errorf("checking linked providers: %w", err)
+	}
+	if linked {
+		return ErrProviderAlreadyLinked
+	}
+
+	var email *string
+	if normalized := normalizeEmail(claims.Email); normalized != "" {
+		email = &normalized
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO user_identities (user_id, provider, subject, email_at_link_time)
+		 VALUES ($1, $2, $3, $4)`,
+		userID, claims.Provider, subject, email,
+	); err != nil {
+		if isUniqueViolation(err) {
+			return ErrProviderIdentityExists
+		}
+		return fmt.Errorf("inserting user identity: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// UnlinkProvider removes a provider from a user. It refuses to remove the
+// only way left to sign in: the last identity of an account without a
+// password.
+func (s *ProviderAuthService) UnlinkProvider(ctx context.Context, userID uuid.UUID, provider Provider) error {
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op after commit
+
+	hasPassword, err := s.lockUser(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	var total, linked int
+	if err := tx.QueryRow(ctx,
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE provider = $2)
+		 FROM user_identities WHERE user_id = $1`,
+		userID, provider,
+	).Scan(&total, &linked); err != nil {
+		return fmt.Errorf("counting identities: %w", err)
+	}
+	if linked == 0 {
+		return ErrProviderNotLinked
+	}
+	if !hasPassword && total == linked {
+		return ErrLastLoginMethod
+	}
+
+	if _, err := tx.Exec(ctx,
+		"DELETE FROM user_identities WHERE user_id = $1 AND provider = $2",
+		userID, provider,
+	); err != nil {
+		return fmt.Errorf("deleting user identity: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// lockUser locks a live user's row for the rest of tx and reports whether
+// the account has a password.
+func (s *ProviderAuthService) lockUser(ctx context.Context, tx Tx, userID uuid.UUID) (bool, error) {
+	var hasPassword bool
+	err := tx.QueryRow(ctx,
+		`SELECT COALESCE(password_hash, '') <> ''
+		 FROM users WHERE id = $1 AND deleted_at IS NULL
+		 FOR UPDATE`,
+		userID,
+	).Scan(&hasPassword)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrUserNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("locking user: %w", err)
+	}
+	return hasPassword, nil
+}
+
 func (s *ProviderAuthService) linkIdentity(ctx context.Context, userID uuid.UUID, provider Provider, subject, email string) error {
-	tx, err := s.db.Begin(ctx)
+	tx, ctx, err := beginTx(ctx, s.db)
 	if err != nil {
 		return fmt.Errorf("starting transaction: %w", err)
 	}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
 func TestProviderAuth_LinkOrFind_InvalidClaims(t *testing.T) {
@@ -264,3 +266,191 @@ func TestProviderAuth_CreateUserFromProviderPending_Success(t *testing.T) {
 		t.Fatalf("expected user id %v, got %v", userID, user.ID)
 	}
 }
+
+func TestProviderAuth_LinkOrFind_SubjectSurvivesEmailChange(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	var queries []string
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			queries = append(queries, sql)
+			return rowFromValues(userID, "new@example.com", stringPtr("hash"), "tester", true, nil, 0, true, now, now)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatalf("expected no writes, got %s", sql)
+			return nil, nil
+		},
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			t.Fatal("expected no identity link")
+			return nil, nil
+		},
+	}
+	service := NewProviderAuthService(db)
+
+	// The Google account is still bound to the address the user moved away
+	// from, and an unverified claim must not matter once the subject matches.
+	for _, verified := range []bool{true, false} {
+		queries = nil
+		result, err := service.LinkOrFindUserFromProvider(context.Background(), IdentityClaims{
+			Provider:      ProviderGoogle,
+			Subject:       "sub",
+			Email:         "old@example.com",
+			EmailVerified: verified,
+		})
+		if err != nil {
+			t.Fatalf("verified=%v: unexpected error: %v", verified, err)
+		}
+		if result.User == nil || result.User.ID != userID || result.User.Email != "new@example.com" {
+			t.Fatalf("verified=%v: expected user with changed email, got %#v", verified, result.User)
+		}
+		if len(queries) != 1 || !strings.Contains(queries[0], "FROM user_identities") {
+			t.Fatalf("verified=%v: expected a single subject lookup, got %v", verified, queries)
+		}
+	}
+}
+
+func TestProviderAuth_LinkOrFind_OldEmailDoesNotLink(t *testing.T) {
+	var emailLookup string
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM users") && !strings.Contains(sql, "user_identities") {
+				emailLookup = args[0].(string)
+			}
+			// Neither the unlinked subject nor the abandoned address matches.
+			return fakeRow{scanFunc: func(dest ...any) error {
+				return pgx.ErrNoRows
+			}}
+		},
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			t.Fatal("expected no identity link")
+			return nil, nil
+		},
+	}
+	service := NewProviderAuthService(db)
+
+	result, err := service.LinkOrFindUserFromProvider(context.Background(), IdentityClaims{
+		Provider:      ProviderGoogle,
+		Subject:       "other-sub",
+		Email:         "Old@Example.com",
+		EmailVerified: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if emailLookup != "old@example.com" {
+		t.Fatalf("expected fallback lookup by normalized email, got %q", emailLookup)
+	}
+	if result.User != nil || result.Pending == nil || result.Pending.Email != "old@example.com" {
+		t.Fatalf("expected pending signup for the abandoned address, got %#v", result)
+	}
+}
+
+func TestProviderAuth_LinkProviderToUser(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	users := NewUserService(db)
+	service := NewProviderAuthService(db)
+
+	alice, err := users.Create(ctx, models.CreateUserParams{Email: "alice@example.com", Username: "alice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bob, err := users.Create(ctx, models.CreateUserParams{Email: "bob@example.com", Username: "bob"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The provider email doesn't need to match or be verified.
+	claims := IdentityClaims{Provider: ProviderGoogle, Subject: "alice-sub", Email: " Alice.Personal@Gmail.com "}
+	if err := service.LinkProviderToUser(ctx, alice.ID, claims); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.LinkProviderToUser(ctx, alice.ID, claims); err != nil {
+		t.Fatalf("expected relinking to be a no-op, got %v", err)
+	}
+	if err := service.LinkProviderToUser(ctx, bob.ID, claims); !errors.Is(err, ErrProviderIdentityExists) {
+		t.Fatalf("expected ErrProviderIdentityExists, got %v", err)
+	}
+	if err := service.LinkProviderToUser(ctx, alice.ID, IdentityClaims{Provider: ProviderGoogle, Subject: "other-sub"}); !errors.Is(err, ErrProviderAlreadyLinked) {
+		t.Fatalf("expected ErrProviderAlreadyLinked, got %v", err)
+	}
+	if err := service.LinkProviderToUser(ctx, uuid.New(), IdentityClaims{Provider: ProviderGoogle, Subject: "ghost"}); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if err := service.LinkProviderToUser(ctx, alice.ID, IdentityClaims{Provider: ProviderGoogle}); !errors.Is(err, ErrInvalidProviderClaims) {
+		t.Fatalf("expected ErrInvalidProviderClaims, got %v", err)
+	}
+
+	identities, err := service.ListIdentities(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(identities) != 1 || identities[0].Provider != "google" || identities[0].Email == nil || *identities[0].Email != "alice.personal@gmail.com" {
+		t.Fatalf("unexpected identities %+v", identities)
+	}
+	if identities, err := service.ListIdentities(ctx, bob.ID); err != nil || identities == nil || len(identities) != 0 {
+		t.Fatalf("expected an empty list, got %v %v", identities, err)
+	}
+
+	// Linking doesn't verify the account email.
+	user, err := users.GetByID(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.EmailVerified {
+		t.Fatal("expected the account email to stay unverified")
+	}
+}
+
+func TestProviderAuth_UnlinkProvider(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	users := NewUserService(db)
+	service := NewProviderAuthService(db)
+
+	hash := "hash"
+	withPassword, err := users.Create(ctx, models.CreateUserParams{Email: "pw@example.com", Username: "pw", PasswordHash: &hash})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	oauthOnly, err := users.Create(ctx, models.CreateUserParams{Email: "oauth@example.com", Username: "oauth"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, link := range []struct {
+		userID  uuid.UUID
+		subject string
+	}{{withPassword.ID, "pw-sub"}, {oauthOnly.ID, "oauth-sub"}} {
+		if err := service.LinkProviderToUser(ctx, link.userID, IdentityClaims{Provider: ProviderGoogle, Subject: link.subject}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := service.UnlinkProvider(ctx, oauthOnly.ID, ProviderGoogle); !errors.Is(err, ErrLastLoginMethod) {
+		t.Fatalf("expected ErrLastLoginMethod, got %v", err)
+	}
+	if identities, _ := service.ListIdentities(ctx, oauthOnly.ID); len(identities) != 1 {
+		t.Fatalf("expected the identity to remain, got %+v", identities)
+	}
+
+	// A second provider can go while another remains.
+	if _, err := db.Exec(ctx,
+		"INSERT INTO user_identities (user_id, provider, subject) VALUES ($1, $2, $3)",
+		oauthOnly.ID, "github", "gh-sub",
+	); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.UnlinkProvider(ctx, oauthOnly.ID, ProviderGoogle); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := service.UnlinkProvider(ctx, withPassword.ID, ProviderGoogle); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.UnlinkProvider(ctx, withPassword.ID, ProviderGoogle); !errors.Is(err, ErrProviderNotLinked) {
+		t.Fatalf("expected ErrProviderNotLinked, got %v", err)
+	}
+	if err := service.UnlinkProvider(ctx, uuid.New(), ProviderGoogle); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
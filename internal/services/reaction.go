@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
@@ -16,6 +18,7 @@ var (
 	ErrInvalidEmoji     = errors.New("invalid emoji")
 	ErrCannotReactToOwn = errors.New("cannot react to your own items")
 	ErrItemNotCompleted = errors.New("can only react to completed items")
+	ErrItemPrivate      = errors.New("cannot react to private items")
 )
 
 type ReactionService struct {
@@ -38,14 +41,15 @@ func (s *ReactionService) AddReaction(ctx context.Context, userID, itemID uuid.U
 
 	// Get the item and its card to check ownership and completion
 	var cardUserID uuid.UUID
-	var isCompleted bool
+	var isCompleted, isPrivate bool
+	var vis authz.CardVisibility
 	err := s.db.QueryRow(ctx,
-		`SELECT bc.user_id, bi.is_completed
+		`SELECT bc.user_id, bi.is_completed, bi.is_private, bc.is_finalized, bc.visible_to_friends
 		 FROM bingo_items bi
 		 JOIN bingo_cards bc ON bi.card_id = bc.id
 		 WHERE bi.id = $1`,
 		itemID,
-	).Scan(&cardUserID, &isCompleted)
+	).Scan(&cardUserID, &isCompleted, &isPrivate, &vis.Finalized, &vis.VisibleToFriends)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrItemNotFound
 	}
@@ -63,25 +67,46 @@ func (s *ReactionService) AddReaction(ctx context.Context, userID, itemID uuid.U
 		return nil, ErrItemNotCompleted
 	}
 
-	// Check if users are friends
-	isFriend, err := s.friendService.IsFriend(ctx, userID, cardUserID)
+	// Private items are placeholders to friends, so there is nothing to react to
+	if isPrivate {
+		return nil, ErrItemPrivate
+	}
+
+	// Only friends who can see the goal may react, and a block ends that even
+	// if the friendship row is still around.
+	rel, err := s.friendService.Relation(ctx, userID, cardUserID)
 	if err != nil {
 		return nil, err
 	}
-	if !isFriend {
+	if !rel.IsFriend() {
 		return nil, ErrNotFriend
 	}
+	if !authz.CanViewItem(rel, vis, isPrivate) {
+		return nil, ErrItemNotFound
+	}
 
-	// Upsert the reaction (insert or update if exists)
+	// Upsert the reaction. Re-adding the same emoji is a no-op that returns the
+	// existing reaction, so scripted repeats don't rewrite the row.
 	reaction := &models.Reaction{}
 	err = s.db.QueryRow(ctx,
 		`INSERT INTO reactions (item_id, user_id, emoji)
 		 VALUES ($1, $2, $3)
 		 ON CONFLICT (item_id, user_id)
-		 DO UPDATE SET emoji = $3
+		 DO UPDATE SET emoji = EXCLUDED.emoji
+		 WHERE reactions.emoji <> EXCLUDED.emoji
 		 RETURNING id, item_id, user_id, emoji, created_at`,
 		itemID, userID, emoji,
 	).Scan(&reaction.ID, &reaction.ItemID, &reaction.UserID, &reaction.Emoji, &reaction.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		existing, err := s.GetUserReactionForItem(ctx, userID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, ErrReactionNotFound
+		}
+		return existing, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("adding reaction: %w", err)
 	}
@@ -103,6 +128,46 @@ func (s *ReactionService) RemoveReaction(ctx context.Context, userID, itemID uui
 	return nil
 }
 
+// CanViewReactions reports whether userID may see who reacted to an item: the
+// owner always may; anyone else must be an unblocked friend who can see the
+// goal, and private goals are off limits.
+func (s *ReactionService) CanViewReactions(ctx context.Context, userID, itemID uuid.UUID) error {
+	var cardUserID uuid.UUID
+	var isPrivate bool
+	var vis authz.CardVisibility
+	err := s.db.QueryRow(ctx,
+		`SELECT bc.user_id, bi.is_private, bc.is_finalized, bc.visible_to_friends
+		 FROM bingo_items bi
+		 JOIN bingo_cards bc ON bi.card_id = bc.id
+		 WHERE bi.id = $1`,
+		itemID,
+	).Scan(&cardUserID, &isPrivate, &vis.Finalized, &vis.VisibleToFriends)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrItemNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("getting item info: %w", err)
+	}
+	if authz.For(userID, cardUserID).IsOwner() {
+		return nil
+	}
+
+	if isPrivate {
+		return ErrItemPrivate
+	}
+	rel, err := s.friendService.Relation(ctx, userID, cardUserID)
+	if err != nil {
+		return err
+	}
+	if !rel.IsFriend() {
+		return ErrNotFriend
+	}
+	if !authz.CanViewItem(rel, vis, isPrivate) {
+		return ErrItemNotFound
+	}
+	return nil
+}
+
 func (s *ReactionService) GetReactionsForItem(ctx context.Context, itemID uuid.UUID) ([]models.ReactionWithUser, error) {
 	rows, err := s.db.Query(ctx,
 		`SELECT r.id, r.item_id, r.user_id, r.emoji, r.created_at, u.username
@@ -153,6 +218,7 @@ func (s *ReactionService) GetReactionSummaryForItem(ctx context.Context, itemID
 		if err := rows.Scan(&summary.Emoji, &summary.Count); err != nil {
 			return nil, fmt.Errorf("scanning summary: %w", err)
 		}
+		summary.DisplayCount = reactionDisplayCount(summary.Count)
 		summaries = append(summaries, summary)
 	}
 
@@ -207,6 +273,37 @@ func (s *ReactionService) GetUserReactionForItem(ctx context.Context, userID, it
 	return reaction, nil
 }
 
+// CleanupOrphaned removes reactions left by users who are no longer friends
+// with the item's owner, e.g. after an unfriend or a block.
+func (s *ReactionService) CleanupOrphaned(ctx context.Context) (int, error) {
+	result, err := s.db.Exec(ctx,
+		`DELETE FROM reactions
+		 WHERE id IN (
+		   SELECT r.id
+		     FROM reactions r
+		     JOIN bingo_items bi ON bi.id = r.item_id
+		     JOIN bingo_cards bc ON bc.id = bi.card_id
+		    WHERE NOT EXISTS (
+		      SELECT 1 FROM friendships f
+		      WHERE f.status = 'accepted'
+		        AND ((f.user_id = r.user_id AND f.friend_id = bc.user_id)
+		          OR (f.user_id = bc.user_id AND f.friend_id = r.user_id))
+		    )
+		 )`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("cleaning up orphaned reactions: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
+func reactionDisplayCount(count int) string {
+	if count > models.ReactionDisplayMax {
+		return strconv.Itoa(models.ReactionDisplayMax) + "+"
+	}
+	return strconv.Itoa(count)
+}
+
 func isValidEmoji(emoji string) bool {
 	for _, e := range models.AllowedEmojis {
 		if e == emoji {
@@ -9,17 +9,23 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
 )
 
 type fakeFriendChecker struct {
 	isFriend bool
+	blocked  bool
 	err      error
 	calls    int
 }
 
-func (f *fakeFriendChecker) IsFriend(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error) {
+func (f *fakeFriendChecker) Relation(ctx context.Context, viewerID, ownerID uuid.UUID) (authz.Relation, error) {
 	f.calls++
-	return f.isFriend, f.err
+	if f.err != nil {
+		return authz.Relation{}, f.err
+	}
+	return authz.Relation{ViewerID: viewerID, OwnerID: ownerID, Friends: f.isFriend, Blocked: f.blocked}, nil
 }
 
 func TestReactionService_AddReaction_InvalidEmoji(t *testing.T) {
@@ -79,7 +85,7 @@ func TestReactionService_AddReaction_CannotReactToOwnItem(t *testing.T) {
 	userID := uuid.New()
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			return rowFromValues(userID, true)
+			return rowFromValues(userID, true, false, true, true)
 		},
 	}
 	friend := &fakeFriendChecker{}
@@ -98,7 +104,7 @@ func TestReactionService_AddReaction_ItemNotCompleted(t *testing.T) {
 	userID := uuid.New()
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			return rowFromValues(uuid.New(), false)
+			return rowFromValues(uuid.New(), false, false, true, true)
 		},
 	}
 	friend := &fakeFriendChecker{}
@@ -113,10 +119,28 @@ func TestReactionService_AddReaction_ItemNotCompleted(t *testing.T) {
 	}
 }
 
+func TestReactionService_AddReaction_ItemPrivate(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(uuid.New(), true, true, true, true)
+		},
+	}
+	friend := &fakeFriendChecker{}
+
+	service := NewReactionService(db, friend)
+	_, err := service.AddReaction(context.Background(), uuid.New(), uuid.New(), "🎉")
+	if !errors.Is(err, ErrItemPrivate) {
+		t.Fatalf("expected ErrItemPrivate, got %v", err)
+	}
+	if friend.calls != 0 {
+		t.Fatalf("expected no friend checks, got %d", friend.calls)
+	}
+}
+
 func TestReactionService_AddReaction_NotFriend(t *testing.T) {
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			return rowFromValues(uuid.New(), true)
+			return rowFromValues(uuid.New(), true, false, true, true)
 		},
 	}
 	friend := &fakeFriendChecker{isFriend: false}
@@ -131,10 +155,46 @@ func TestReactionService_AddReaction_NotFriend(t *testing.T) {
 	}
 }
 
+func TestReactionService_AddReaction_BlockedFormerFriend(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "INSERT INTO reactions") {
+				t.Fatal("expected no reaction to be written")
+			}
+			return rowFromValues(uuid.New(), true, false, true, true)
+		},
+	}
+	friend := &fakeFriendChecker{isFriend: true, blocked: true}
+
+	service := NewReactionService(db, friend)
+	_, err := service.AddReaction(context.Background(), uuid.New(), uuid.New(), "🎉")
+	if !errors.Is(err, ErrNotFriend) {
+		t.Fatalf("expected ErrNotFriend, got %v", err)
+	}
+}
+
+func TestReactionService_AddReaction_CardHiddenFromFriends(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "INSERT INTO reactions") {
+				t.Fatal("expected no reaction to be written")
+			}
+			return rowFromValues(uuid.New(), true, false, true, false)
+		},
+	}
+	friend := &fakeFriendChecker{isFriend: true}
+
+	service := NewReactionService(db, friend)
+	_, err := service.AddReaction(context.Background(), uuid.New(), uuid.New(), "🎉")
+	if !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("expected ErrItemNotFound, got %v", err)
+	}
+}
+
 func TestReactionService_AddReaction_FriendCheckError(t *testing.T) {
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			return rowFromValues(uuid.New(), true)
+			return rowFromValues(uuid.New(), true, false, true, true)
 		},
 	}
 	friend := &fakeFriendChecker{err: errors.New("friend error")}
@@ -152,7 +212,7 @@ func TestReactionService_AddReaction_InsertError(t *testing.T) {
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			if strings.Contains(sql, "FROM bingo_items") {
-				return rowFromValues(uuid.New(), true)
+				return rowFromValues(uuid.New(), true, false, true, true)
 			}
 			return fakeRow{scanFunc: func(dest ...any) error {
 				return errors.New("insert error")
@@ -174,7 +234,7 @@ func TestReactionService_AddReaction_Success(t *testing.T) {
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			if strings.Contains(sql, "FROM bingo_items") {
-				return rowFromValues(uuid.New(), true)
+				return rowFromValues(uuid.New(), true, false, true, true)
 			}
 			return rowFromValues(uuid.New(), itemID, userID, "🎉", time.Now())
 		},
@@ -286,6 +346,37 @@ func TestReactionService_GetUserReactionForItem_Success(t *testing.T) {
 	}
 }
 
+func TestReactionService_CanViewReactions(t *testing.T) {
+	ownerID := uuid.New()
+	for _, tc := range []struct {
+		name     string
+		viewerID uuid.UUID
+		private  bool
+		visible  bool
+		friend   *fakeFriendChecker
+		want     error
+	}{
+		{"owner", ownerID, true, false, &fakeFriendChecker{}, nil},
+		{"friend", uuid.New(), false, true, &fakeFriendChecker{isFriend: true}, nil},
+		{"blocked former friend", uuid.New(), false, true, &fakeFriendChecker{isFriend: true, blocked: true}, ErrNotFriend},
+		{"stranger", uuid.New(), false, true, &fakeFriendChecker{}, ErrNotFriend},
+		{"private goal", uuid.New(), true, true, &fakeFriendChecker{isFriend: true}, ErrItemPrivate},
+		{"hidden card", uuid.New(), false, false, &fakeFriendChecker{isFriend: true}, ErrItemNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					return rowFromValues(ownerID, tc.private, true, tc.visible)
+				},
+			}
+			service := NewReactionService(db, tc.friend)
+			if err := service.CanViewReactions(context.Background(), tc.viewerID, uuid.New()); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
 func TestReactionService_GetReactionsForItem_Empty(t *testing.T) {
 	db := &fakeDB{
 		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
@@ -481,3 +572,85 @@ func TestReactionService_GetReactionsForCard_ScanError(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestReactionService_AddReaction_SameEmojiIsNoop(t *testing.T) {
+	userID := uuid.New()
+	itemID := uuid.New()
+	existingID := uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM bingo_items"):
+				return rowFromValues(uuid.New(), true, false, true, true)
+			case strings.Contains(sql, "INSERT INTO reactions"):
+				if !strings.Contains(sql, "WHERE reactions.emoji <> EXCLUDED.emoji") {
+					t.Fatalf("expected upsert to skip unchanged emoji, got %q", sql)
+				}
+				return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+			default:
+				return rowFromValues(existingID, itemID, userID, "🎉", time.Now())
+			}
+		},
+	}
+
+	service := NewReactionService(db, &fakeFriendChecker{isFriend: true})
+	reaction, err := service.AddReaction(context.Background(), userID, itemID, "🎉")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reaction.ID != existingID {
+		t.Fatalf("expected existing reaction %v, got %v", existingID, reaction.ID)
+	}
+}
+
+func TestReactionService_GetReactionSummaryForItem_CapsDisplayCount(t *testing.T) {
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{
+				{"🎉", int64(150)},
+				{"👏", int64(99)},
+			}}, nil
+		},
+	}
+
+	service := NewReactionService(db, &fakeFriendChecker{})
+	summaries, err := service.GetReactionSummaryForItem(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summaries[0].Count != 150 || summaries[0].DisplayCount != "99+" {
+		t.Fatalf("expected 150 shown as 99+, got %+v", summaries[0])
+	}
+	if summaries[1].DisplayCount != "99" {
+		t.Fatalf("expected 99 shown as 99, got %+v", summaries[1])
+	}
+}
+
+func TestReactionService_CleanupOrphaned(t *testing.T) {
+	var gotSQL string
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			gotSQL = sql
+			return fakeCommandTag{rowsAffected: 4}, nil
+		},
+	}
+
+	service := NewReactionService(db, &fakeFriendChecker{})
+	removed, err := service.CleanupOrphaned(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if removed != 4 {
+		t.Fatalf("expected 4 removed, got %d", removed)
+	}
+	if !strings.Contains(gotSQL, "DELETE FROM reactions") || !strings.Contains(gotSQL, "f.status = 'accepted'") {
+		t.Fatalf("unexpected cleanup SQL: %q", gotSQL)
+	}
+
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		return nil, errors.New("boom")
+	}
+	if _, err := service.CleanupOrphaned(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+}
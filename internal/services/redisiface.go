@@ -40,3 +40,11 @@ func (r *RedisAdapter) Expire(ctx context.Context, key string, expiration time.D
 func (r *RedisAdapter) Del(ctx context.Context, keys ...string) error {
 	return r.client.Del(ctx, keys...).Err()
 }
+
+func (r *RedisAdapter) HIncrBy(ctx context.Context, key, field string, incr int64) error {
+	return r.client.HIncrBy(ctx, key, field, incr).Err()
+}
+
+func (r *RedisAdapter) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return r.client.HGetAll(ctx, key).Result()
+}
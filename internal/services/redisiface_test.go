@@ -32,4 +32,10 @@ func TestRedisAdapter_MethodsReturnErrorsWhenUnavailable(t *testing.T) {
 	if err := adapter.Del(ctx, "k"); err == nil {
 		t.Fatal("expected Del to return error when redis unavailable")
 	}
+	if err := adapter.HIncrBy(ctx, "k", "f", 1); err == nil {
+		t.Fatal("expected HIncrBy to return error when redis unavailable")
+	}
+	if _, err := adapter.HGetAll(ctx, "k"); err == nil {
+		t.Fatal("expected HGetAll to return error when redis unavailable")
+	}
 }
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	mathrand "math/rand/v2"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -14,6 +16,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
+	"github.com/HammerMeetNail/yearofbingo/internal/bingo"
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/logging"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
@@ -24,17 +29,46 @@ var (
 	ErrCardNotEligible   = errors.New("card not eligible for reminders")
 	ErrGoalCompleted     = errors.New("goal already completed")
 	ErrRemindersDisabled = errors.New("reminders disabled")
+
+	ErrInvalidImageTokenMode = errors.New("invalid image token mode")
+	ErrInvalidImageTheme     = errors.New("invalid image theme")
+	ErrInvalidTimezone       = errors.New("invalid timezone")
+	ErrInvalidDigestTime     = errors.New("invalid digest time")
+	ErrInvalidSnoozeDays     = errors.New("invalid snooze length")
+
+	// errSendAtInPast is the ErrInvalidSchedule of a one-time reminder whose
+	// send time has already passed.
+	errSendAtInPast = fmt.Errorf("%w: send time is not in the future", ErrInvalidSchedule)
 )
 
 type monthlySchedule struct {
-	DayOfMonth int    `json:"day_of_month"`
-	Time       string `json:"time"`
+	DayOfMonth          int    `json:"day_of_month"`
+	Time                string `json:"time"`
+	JitterWindowMinutes int    `json:"jitter_window_minutes,omitempty"`
+	JitterOffsetMinutes int    `json:"jitter_offset_minutes,omitempty"`
+}
+
+// maxReminderJitterMinutes caps the send-time jitter window on either side of
+// the scheduled time.
+const maxReminderJitterMinutes = 60
+
+// jitterOffset is the stored per-reminder shift applied to the scheduled time.
+func (m monthlySchedule) jitterOffset() time.Duration {
+	return time.Duration(m.JitterOffsetMinutes) * time.Minute
 }
 
 type oneTimeSchedule struct {
 	SendAt string `json:"send_at"`
 }
 
+type recurringSchedule struct {
+	EveryDays int    `json:"every_days"`
+	Time      string `json:"time"`
+}
+
+// maxRecurringEveryDays caps the interval of recurring goal reminders.
+const maxRecurringEveryDays = 365
+
 type checkinJob struct {
 	ID                     uuid.UUID
 	UserID                 uuid.UUID
@@ -43,17 +77,29 @@ type checkinJob struct {
 	Schedule               []byte
 	IncludeImage           bool
 	IncludeRecommendations bool
+	IncludeMemories        bool
 	NextSendAt             time.Time
+	EmailPausedUntil       *time.Time
+	ImageTokenMode         string
+	ImageTheme             string
+	Timezone               string
+	// RecentRecommendations is the stored recent_recommendations history.
+	RecentRecommendations []byte
+	// ProgressSnapshot is the stored progress_snapshot; nil before the
+	// first send.
+	ProgressSnapshot []byte
 }
 
 type goalReminderJob struct {
-	ID         uuid.UUID
-	UserID     uuid.UUID
-	CardID     uuid.UUID
-	ItemID     uuid.UUID
-	Kind       string
-	Schedule   []byte
-	NextSendAt time.Time
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	CardID           uuid.UUID
+	ItemID           uuid.UUID
+	Kind             string
+	Schedule         []byte
+	NextSendAt       time.Time
+	EmailPausedUntil *time.Time
+	Timezone         string
 }
 
 type reminderEmailStatus string
@@ -61,22 +107,213 @@ type reminderEmailStatus string
 const (
 	reminderEmailSent   reminderEmailStatus = "sent"
 	reminderEmailFailed reminderEmailStatus = "failed"
+	// reminderEmailManual marks a support-initiated resend; it never counts
+	// toward the daily cap.
+	reminderEmailManual reminderEmailStatus = "manual"
 )
 
+// reuseImageTokenTTL is the rolling expiry of image tokens in reuse mode.
+const reuseImageTokenTTL = 14 * 24 * time.Hour
+
+// reminderClaimLease is how long a claimed reminder is hidden from other
+// runners while its email is sent. A runner that dies mid-send leaves the
+// reminder to be sent again once the lease runs out, and the daily caps
+// count live claims as sends.
+const reminderClaimLease = 10 * time.Minute
+
 type ReminderService struct {
 	db           DB
 	emailService EmailServiceInterface
 	baseURL      string
 	now          func() time.Time
+	randIntn     func(n int) int
+
+	// runnerID is written to claimed_by on the reminders this process sends.
+	runnerID string
+
+	// Policy for per-email image tokens; see SetImageTokenPolicy.
+	perEmailTokenTTL       time.Duration
+	perEmailTokenMaxAccess int
+
+	memoryFinder MemoryFinder
+
+	// notificationService, when set, mirrors each send in the bell.
+	notificationService NotificationServiceInterface
+
+	// difficultyPacing breaks recommendation ties by item difficulty for the
+	// time of year; see SetDifficultyPacing.
+	difficultyPacing bool
+
+	// cleanup bounds each CleanupOld run.
+	cleanup cleanupLimits
+
+	branding config.BrandingConfig
+}
+
+// SetMemoryFinder enables the "on this day" line in check-in emails.
+func (s *ReminderService) SetMemoryFinder(finder MemoryFinder) {
+	s.memoryFinder = finder
+}
+
+// SetNotificationService enables in-app notifications for reminder sends.
+func (s *ReminderService) SetNotificationService(notificationService NotificationServiceInterface) {
+	s.notificationService = notificationService
+}
+
+// SetDifficultyPacing makes check-in recommendations prefer easier goals early
+// in the year and harder ones later.
+func (s *ReminderService) SetDifficultyPacing(enabled bool) {
+	s.difficultyPacing = enabled
+}
+
+// SetBranding applies the deployment's branding to reminder emails.
+func (s *ReminderService) SetBranding(branding config.BrandingConfig) {
+	s.branding = branding
+}
+
+// pickReminderRecommendations chooses the goals suggested in check-in emails.
+// Goals in recent were suggested by the last few emails and are rotated out
+// unless they are the only way to the next bingo.
+func (s *ReminderService) pickReminderRecommendations(card *models.BingoCard, items []models.BingoItem, recent map[uuid.UUID]bool) []models.BingoItem {
+	if s.difficultyPacing {
+		return bingo.PickPacedRotatedRecommendations(items, card.GridSize, card.FreeSpacePos, 3, s.now().Month(), recent)
+	}
+	return bingo.PickRotatedRecommendations(items, card.GridSize, card.FreeSpacePos, 3, recent)
+}
+
+// recentRecommendationSends is how many check-in emails a suggested goal is
+// deprioritized for.
+const recentRecommendationSends = 2
+
+// recentRecommendationIDs flattens a recent_recommendations history. An
+// unreadable history is ignored rather than blocking the send.
+func recentRecommendationIDs(raw []byte) map[uuid.UUID]bool {
+	var sends [][]uuid.UUID
+	if len(raw) == 0 || json.Unmarshal(raw, &sends) != nil {
+		return nil
+	}
+	ids := map[uuid.UUID]bool{}
+	for _, send := range sends {
+		for _, id := range send {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+// appendRecentRecommendations records the goals suggested by a send at the
+// front of the history, keeping the last recentRecommendationSends sends.
+func appendRecentRecommendations(raw []byte, picked []models.BingoItem) ([]byte, error) {
+	var sends [][]uuid.UUID
+	if len(raw) > 0 && json.Unmarshal(raw, &sends) != nil {
+		sends = nil
+	}
+	ids := make([]uuid.UUID, 0, len(picked))
+	for _, item := range picked {
+		ids = append(ids, item.ID)
+	}
+	sends = append([][]uuid.UUID{ids}, sends...)
+	if len(sends) > recentRecommendationSends {
+		sends = sends[:recentRecommendationSends]
+	}
+	return json.Marshal(sends)
+}
+
+// checkinSnapshot is a card's progress as of the last sent check-in.
+type checkinSnapshot struct {
+	Completed int         `json:"completed"`
+	ItemIDs   []uuid.UUID `json:"item_ids"`
+	Bingos    int         `json:"bingos"`
+}
+
+func newCheckinSnapshot(card *models.BingoCard, items []models.BingoItem) checkinSnapshot {
+	stats := buildReminderStats(card, items)
+	snapshot := checkinSnapshot{Completed: stats.Completed, ItemIDs: []uuid.UUID{}, Bingos: stats.Bingos}
+	for _, item := range items {
+		if item.IsCompleted {
+			snapshot.ItemIDs = append(snapshot.ItemIDs, item.ID)
+		}
+	}
+	return snapshot
+}
+
+// checkinDelta is what changed on a card since the last check-in.
+type checkinDelta struct {
+	// Completed are the goals completed since, in board order.
+	Completed []models.BingoItem
+	// Uncompleted counts goals that were complete last time and aren't now.
+	Uncompleted int
+	// NewBingos is the change in bingo count; negative after uncompleting.
+	NewBingos int
+}
+
+// checkinProgressDelta compares the card with the stored snapshot. It
+// returns nil when there is no readable snapshot, as on the first send.
+func checkinProgressDelta(raw []byte, items []models.BingoItem, stats reminderStats) *checkinDelta {
+	var previous checkinSnapshot
+	if len(raw) == 0 || json.Unmarshal(raw, &previous) != nil {
+		return nil
+	}
+	wasCompleted := make(map[uuid.UUID]bool, len(previous.ItemIDs))
+	for _, id := range previous.ItemIDs {
+		wasCompleted[id] = true
+	}
+	delta := &checkinDelta{NewBingos: stats.Bingos - previous.Bingos}
+	stillCompleted := 0
+	for _, item := range items {
+		switch {
+		case item.IsCompleted && wasCompleted[item.ID]:
+			stillCompleted++
+		case item.IsCompleted:
+			delta.Completed = append(delta.Completed, item)
+		}
+	}
+	sort.Slice(delta.Completed, func(i, j int) bool { return delta.Completed[i].Position < delta.Completed[j].Position })
+	delta.Uncompleted = len(previous.ItemIDs) - stillCompleted
+	return delta
 }
 
 func NewReminderService(db DB, emailService EmailServiceInterface, baseURL string) *ReminderService {
 	trimmed := strings.TrimRight(baseURL, "/")
 	return &ReminderService{
-		db:           db,
-		emailService: emailService,
-		baseURL:      trimmed,
-		now:          time.Now,
+		db:                     db,
+		emailService:           emailService,
+		baseURL:                trimmed,
+		now:                    time.Now,
+		randIntn:               mathrand.IntN,
+		runnerID:               newReminderRunnerID(),
+		perEmailTokenTTL:       7 * 24 * time.Hour,
+		perEmailTokenMaxAccess: 50,
+		cleanup:                defaultCleanupLimits,
+	}
+}
+
+// newReminderRunnerID names this process in claimed_by: the host name, so a
+// stuck claim can be traced to its replica, and a random suffix, so two
+// processes on one host differ.
+func newReminderRunnerID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "reminder-runner"
+	}
+	if len(host) > 80 {
+		host = host[:80]
+	}
+	suffix, err := randomToken(4)
+	if err != nil {
+		return host
+	}
+	return host + "-" + suffix
+}
+
+// SetImageTokenPolicy configures the TTL and view cap of image tokens minted
+// for users in per-email mode. A maxAccess of 0 leaves views unlimited.
+func (s *ReminderService) SetImageTokenPolicy(ttl time.Duration, maxAccess int) {
+	if ttl > 0 {
+		s.perEmailTokenTTL = ttl
+	}
+	if maxAccess >= 0 {
+		s.perEmailTokenMaxAccess = maxAccess
 	}
 }
 
@@ -98,30 +335,126 @@ func (s *ReminderService) UpdateSettings(ctx context.Context, userID uuid.UUID,
 		}
 	}
 
+	if patch.ImageTokenMode != nil &&
+		*patch.ImageTokenMode != models.ReminderImageTokenReuse &&
+		*patch.ImageTokenMode != models.ReminderImageTokenPerEmail {
+		return nil, ErrInvalidImageTokenMode
+	}
+	if patch.ImageTheme != nil && !models.IsValidImageTheme(*patch.ImageTheme) {
+		return nil, ErrInvalidImageTheme
+	}
+
+	var timezone string
+	if patch.Timezone != nil {
+		loc, err := parseReminderTimezone(*patch.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		timezone = loc.String()
+	}
+	var digestTime string
+	if patch.DigestTime != nil {
+		parsed, err := time.Parse("15:04", strings.TrimSpace(*patch.DigestTime))
+		if err != nil {
+			return nil, ErrInvalidDigestTime
+		}
+		digestTime = parsed.Format("15:04")
+	}
+
 	if err := s.ensureSettingsRow(ctx, userID); err != nil {
 		return nil, err
 	}
 
-	if patch.EmailEnabled == nil {
-		return s.loadSettings(ctx, userID)
+	if patch.EmailEnabled != nil {
+		if _, err := s.db.Exec(ctx,
+			"UPDATE reminder_settings SET email_enabled = $1, updated_at = NOW() WHERE user_id = $2",
+			*patch.EmailEnabled,
+			userID,
+		); err != nil {
+			return nil, fmt.Errorf("update reminder settings: %w", err)
+		}
 	}
 
-	if _, err := s.db.Exec(ctx,
-		"UPDATE reminder_settings SET email_enabled = $1, updated_at = NOW() WHERE user_id = $2",
-		*patch.EmailEnabled,
-		userID,
-	); err != nil {
-		return nil, fmt.Errorf("update reminder settings: %w", err)
+	if patch.ImageTokenMode != nil {
+		if _, err := s.db.Exec(ctx,
+			"UPDATE reminder_settings SET image_token_mode = $1, updated_at = NOW() WHERE user_id = $2",
+			*patch.ImageTokenMode,
+			userID,
+		); err != nil {
+			return nil, fmt.Errorf("update reminder settings: %w", err)
+		}
 	}
 
-	return s.loadSettings(ctx, userID)
+	if patch.ImageTheme != nil {
+		if _, err := s.db.Exec(ctx,
+			"UPDATE reminder_settings SET image_theme = $1, updated_at = NOW() WHERE user_id = $2",
+			*patch.ImageTheme,
+			userID,
+		); err != nil {
+			return nil, fmt.Errorf("update reminder settings: %w", err)
+		}
+	}
+
+	// Changing the zone leaves scheduled next_send_at values alone; they move
+	// to the new zone the next time each reminder is saved or sent.
+	if patch.Timezone != nil {
+		if _, err := s.db.Exec(ctx,
+			"UPDATE reminder_settings SET timezone = $1, updated_at = NOW() WHERE user_id = $2",
+			timezone,
+			userID,
+		); err != nil {
+			return nil, fmt.Errorf("update reminder settings: %w", err)
+		}
+	}
+
+	if patch.DigestEnabled != nil {
+		if _, err := s.db.Exec(ctx,
+			"UPDATE reminder_settings SET digest_enabled = $1, updated_at = NOW() WHERE user_id = $2",
+			*patch.DigestEnabled,
+			userID,
+		); err != nil {
+			return nil, fmt.Errorf("update reminder settings: %w", err)
+		}
+	}
+
+	if patch.DigestTime != nil {
+		if _, err := s.db.Exec(ctx,
+			"UPDATE reminder_settings SET digest_time = $1, updated_at = NOW() WHERE user_id = $2",
+			digestTime,
+			userID,
+		); err != nil {
+			return nil, fmt.Errorf("update reminder settings: %w", err)
+		}
+	}
+
+	settings, err := s.loadSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Unlike reminders, the digest moves to a new time or zone right away.
+	if patch.DigestEnabled != nil || patch.DigestTime != nil || patch.Timezone != nil {
+		if err := s.scheduleDigest(ctx, settings); err != nil {
+			return nil, err
+		}
+	}
+	return settings, nil
+}
+
+// RevokeImageTokens deletes every reminder image token the user owns, so
+// images in previously sent emails stop loading.
+func (s *ReminderService) RevokeImageTokens(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := s.db.Exec(ctx, "DELETE FROM reminder_image_tokens WHERE user_id = $1", userID)
+	if err != nil {
+		return 0, fmt.Errorf("revoke reminder image tokens: %w", err)
+	}
+	return result.RowsAffected(), nil
 }
 
 func (s *ReminderService) ListCardCheckins(ctx context.Context, userID uuid.UUID) ([]models.CardCheckinSummary, error) {
 	rows, err := s.db.Query(ctx, `
 		SELECT c.id, c.title, c.year, c.is_finalized, c.is_archived, c.has_free_space, c.grid_size,
 		       r.id, r.user_id, r.card_id, r.enabled, r.frequency, r.schedule, r.include_image,
-		       r.include_recommendations, r.next_send_at, r.last_sent_at, r.created_at, r.updated_at
+		       r.include_recommendations, r.include_memories, r.next_send_at, r.last_sent_at, r.created_at, r.updated_at
 		  FROM bingo_cards c
 		  LEFT JOIN card_checkin_reminders r
 		    ON r.card_id = c.id AND r.user_id = $1
@@ -147,6 +480,7 @@ func (s *ReminderService) ListCardCheckins(ctx context.Context, userID uuid.UUID
 		var schedule []byte
 		var includeImage *bool
 		var includeRecommendations *bool
+		var includeMemories *bool
 		var nextSendAt *time.Time
 		var lastSentAt *time.Time
 		var checkinCreatedAt *time.Time
@@ -168,6 +502,7 @@ func (s *ReminderService) ListCardCheckins(ctx context.Context, userID uuid.UUID
 			&schedule,
 			&includeImage,
 			&includeRecommendations,
+			&includeMemories,
 			&nextSendAt,
 			&lastSentAt,
 			&checkinCreatedAt,
@@ -186,6 +521,7 @@ func (s *ReminderService) ListCardCheckins(ctx context.Context, userID uuid.UUID
 				Schedule:               schedule,
 				IncludeImage:           derefBool(includeImage),
 				IncludeRecommendations: derefBool(includeRecommendations),
+				IncludeMemories:        derefBool(includeMemories),
 				NextSendAt:             nextSendAt,
 				LastSentAt:             lastSentAt,
 				CreatedAt:              derefTime(checkinCreatedAt),
@@ -204,12 +540,9 @@ func (s *ReminderService) ListCardCheckins(ctx context.Context, userID uuid.UUID
 }
 
 func (s *ReminderService) UpsertCardCheckin(ctx context.Context, userID, cardID uuid.UUID, input models.CardCheckinScheduleInput) (*models.CardCheckinReminder, error) {
-	frequency := strings.TrimSpace(input.Frequency)
-	if frequency == "" {
-		frequency = "monthly"
-	}
-	if frequency != "monthly" {
-		return nil, ErrInvalidSchedule
+	frequency, err := normalizeCheckinFrequency(input.Frequency)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := s.ensureCardEligible(ctx, userID, cardID); err != nil {
@@ -219,24 +552,27 @@ func (s *ReminderService) UpsertCardCheckin(ctx context.Context, userID, cardID
 		return nil, err
 	}
 
-	schedule, err := parseMonthlySchedule(input.Schedule)
+	loc, err := s.loadLocation(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-
-	nextSendAt, err := nextMonthlySend(s.now(), schedule)
+	schedule, nextSendAt, err := s.checkinSchedule(input.Schedule, s.now().In(loc), true)
 	if err != nil {
 		return nil, err
 	}
 
 	includeImage := true
 	includeRecommendations := true
+	includeMemories := true
 	if input.IncludeImage != nil {
 		includeImage = *input.IncludeImage
 	}
 	if input.IncludeRecommendations != nil {
 		includeRecommendations = *input.IncludeRecommendations
 	}
+	if input.IncludeMemories != nil {
+		includeMemories = *input.IncludeMemories
+	}
 
 	scheduleJSON, err := json.Marshal(schedule)
 	if err != nil {
@@ -246,19 +582,20 @@ func (s *ReminderService) UpsertCardCheckin(ctx context.Context, userID, cardID
 	reminder := &models.CardCheckinReminder{}
 	err = s.db.QueryRow(ctx, `
 		INSERT INTO card_checkin_reminders
-			(user_id, card_id, frequency, schedule, include_image, include_recommendations, next_send_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+			(user_id, card_id, frequency, schedule, include_image, include_recommendations, include_memories, next_send_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (user_id, card_id)
 		DO UPDATE SET frequency = EXCLUDED.frequency,
 		              schedule = EXCLUDED.schedule,
 		              include_image = EXCLUDED.include_image,
 		              include_recommendations = EXCLUDED.include_recommendations,
+		              include_memories = EXCLUDED.include_memories,
 		              enabled = true,
 		              next_send_at = EXCLUDED.next_send_at,
 		              updated_at = NOW()
 		RETURNING id, user_id, card_id, enabled, frequency, schedule, include_image,
-		          include_recommendations, next_send_at, last_sent_at, created_at, updated_at`,
-		userID, cardID, frequency, scheduleJSON, includeImage, includeRecommendations, nextSendAt,
+		          include_recommendations, include_memories, next_send_at, last_sent_at, created_at, updated_at`,
+		userID, cardID, frequency, scheduleJSON, includeImage, includeRecommendations, includeMemories, nextSendAt,
 	).Scan(
 		&reminder.ID,
 		&reminder.UserID,
@@ -268,6 +605,7 @@ func (s *ReminderService) UpsertCardCheckin(ctx context.Context, userID, cardID
 		&reminder.Schedule,
 		&reminder.IncludeImage,
 		&reminder.IncludeRecommendations,
+		&reminder.IncludeMemories,
 		&reminder.NextSendAt,
 		&reminder.LastSentAt,
 		&reminder.CreatedAt,
@@ -348,12 +686,9 @@ func (s *ReminderService) UpsertGoalReminder(ctx context.Context, userID uuid.UU
 	if input.ItemID == uuid.Nil {
 		return nil, ErrInvalidSchedule
 	}
-	kind := strings.TrimSpace(input.Kind)
-	if kind == "" {
-		kind = "one_time"
-	}
-	if kind != "one_time" {
-		return nil, ErrInvalidSchedule
+	kind, err := normalizeGoalReminderKind(input.Kind)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := s.ensureSettingsRow(ctx, userID); err != nil {
@@ -371,26 +706,13 @@ func (s *ReminderService) UpsertGoalReminder(ctx context.Context, userID uuid.UU
 		return nil, ErrGoalCompleted
 	}
 
-	sendAt, err := parseOneTimeSchedule(input.Schedule, s.now())
+	scheduleJSON, sendAt, err := s.goalReminderSchedule(ctx, userID, kind, input.Schedule)
 	if err != nil {
 		return nil, err
 	}
 
-	scheduleJSON, err := json.Marshal(oneTimeSchedule{SendAt: sendAt.UTC().Format(time.RFC3339)})
-	if err != nil {
-		return nil, fmt.Errorf("encode schedule: %w", err)
-	}
-
 	reminder := &models.GoalReminder{}
-	if err := s.db.QueryRow(ctx, `
-		INSERT INTO goal_reminders (user_id, card_id, item_id, kind, schedule, next_send_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (user_id, item_id)
-		DO UPDATE SET kind = EXCLUDED.kind,
-		              schedule = EXCLUDED.schedule,
-		              enabled = true,
-		              next_send_at = EXCLUDED.next_send_at,
-		              updated_at = NOW()
+	if err := s.db.QueryRow(ctx, upsertGoalReminderSQL+`
 		RETURNING id, user_id, card_id, item_id, enabled, kind, schedule, next_send_at,
 		          last_sent_at, created_at, updated_at`,
 		userID, cardID, input.ItemID, kind, scheduleJSON, sendAt.UTC(),
@@ -413,6 +735,196 @@ func (s *ReminderService) UpsertGoalReminder(ctx context.Context, userID uuid.UU
 	return reminder, nil
 }
 
+// upsertGoalReminderSQL creates a goal reminder or replaces the schedule of
+// the goal's existing one, re-enabling it.
+const upsertGoalReminderSQL = `
+		INSERT INTO goal_reminders (user_id, card_id, item_id, kind, schedule, next_send_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, item_id)
+		DO UPDATE SET kind = EXCLUDED.kind,
+		              schedule = EXCLUDED.schedule,
+		              enabled = true,
+		              next_send_at = EXCLUDED.next_send_at,
+		              updated_at = NOW()`
+
+// BulkUpsertGoalReminders sets the same reminder on every open goal of a
+// finalized, unarchived card in one transaction. Goals that already have a
+// reminder keep it unless input.Overwrite is set.
+func (s *ReminderService) BulkUpsertGoalReminders(ctx context.Context, userID uuid.UUID, input models.GoalReminderBulkInput) (*models.GoalReminderBulkResult, error) {
+	if input.CardID == uuid.Nil {
+		return nil, ErrCardNotFound
+	}
+	kind, err := normalizeGoalReminderKind(input.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureSettingsRow(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureCardEligible(ctx, userID, input.CardID); err != nil {
+		return nil, err
+	}
+	scheduleJSON, sendAt, err := s.goalReminderSchedule(ctx, userID, kind, input.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("begin bulk goal reminders: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		SELECT i.id, gr.id
+		  FROM bingo_items i
+		  JOIN bingo_cards c ON c.id = i.card_id
+		  LEFT JOIN goal_reminders gr ON gr.item_id = i.id AND gr.user_id = c.user_id
+		 WHERE c.id = $1 AND c.user_id = $2
+		   AND NOT i.is_completed
+		   AND (c.free_space_position IS NULL OR i.position <> c.free_space_position)
+		 ORDER BY i.position
+		   FOR UPDATE OF i`,
+		input.CardID,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list open goals: %w", err)
+	}
+	type openGoal struct {
+		itemID     uuid.UUID
+		reminderID *uuid.UUID
+	}
+	var goals []openGoal
+	for rows.Next() {
+		var goal openGoal
+		if err := rows.Scan(&goal.itemID, &goal.reminderID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan open goal: %w", err)
+		}
+		goals = append(goals, goal)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list open goals: %w", err)
+	}
+
+	result := &models.GoalReminderBulkResult{
+		Created:        []uuid.UUID{},
+		Updated:        []uuid.UUID{},
+		SkippedItemIDs: []uuid.UUID{},
+	}
+	for _, goal := range goals {
+		if goal.reminderID != nil && !input.Overwrite {
+			result.SkippedItemIDs = append(result.SkippedItemIDs, goal.itemID)
+			continue
+		}
+		var reminderID uuid.UUID
+		if err := tx.QueryRow(ctx, upsertGoalReminderSQL+" RETURNING id",
+			userID, input.CardID, goal.itemID, kind, scheduleJSON, sendAt.UTC(),
+		).Scan(&reminderID); err != nil {
+			return nil, fmt.Errorf("upsert goal reminder: %w", err)
+		}
+		if goal.reminderID != nil {
+			result.Updated = append(result.Updated, reminderID)
+		} else {
+			result.Created = append(result.Created, reminderID)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit bulk goal reminders: %w", err)
+	}
+	return result, nil
+}
+
+// normalizeCheckinFrequency defaults an empty frequency to monthly, the only
+// one supported.
+func normalizeCheckinFrequency(frequency string) (string, error) {
+	frequency = strings.TrimSpace(frequency)
+	if frequency == "" {
+		return "monthly", nil
+	}
+	if frequency != "monthly" {
+		return "", ErrInvalidSchedule
+	}
+	return frequency, nil
+}
+
+// checkinSchedule validates a check-in schedule and returns it as stored,
+// with the first send after now, in now's zone. With pickJitter set, a
+// schedule with a jitter window gets its random offset; previews leave it
+// at zero since the offset is only chosen on save.
+func (s *ReminderService) checkinSchedule(input models.CardCheckinSchedulePayload, now time.Time, pickJitter bool) (monthlySchedule, time.Time, error) {
+	schedule, err := parseMonthlySchedule(input)
+	if err != nil {
+		return monthlySchedule{}, time.Time{}, err
+	}
+	// The offset is chosen once per upsert and stored, so the send time stays
+	// stable month to month while users who all pick 09:00 are spread out.
+	if window := schedule.JitterWindowMinutes; pickJitter && window > 0 {
+		schedule.JitterOffsetMinutes = s.randIntn(2*window+1) - window
+	}
+	nextSendAt, err := nextMonthlySend(now, schedule)
+	if err != nil {
+		return monthlySchedule{}, time.Time{}, err
+	}
+	return schedule, nextSendAt, nil
+}
+
+// normalizeGoalReminderKind defaults an empty kind to one_time and rejects
+// unknown kinds.
+func normalizeGoalReminderKind(kind string) (string, error) {
+	kind = strings.TrimSpace(kind)
+	if kind == "" {
+		return models.GoalReminderKindOneTime, nil
+	}
+	if kind != models.GoalReminderKindOneTime && kind != models.GoalReminderKindRecurring {
+		return "", ErrInvalidSchedule
+	}
+	return kind, nil
+}
+
+// goalReminderSchedule validates a goal reminder schedule in the user's
+// reminder time zone and returns it as stored, with the first send time.
+func (s *ReminderService) goalReminderSchedule(ctx context.Context, userID uuid.UUID, kind string, input models.GoalReminderScheduleInput) ([]byte, time.Time, error) {
+	loc, err := s.loadLocation(ctx, userID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return s.goalReminderScheduleIn(loc, kind, input)
+}
+
+// goalReminderScheduleIn is goalReminderSchedule for a known time zone.
+func (s *ReminderService) goalReminderScheduleIn(loc *time.Location, kind string, input models.GoalReminderScheduleInput) ([]byte, time.Time, error) {
+	if kind == models.GoalReminderKindRecurring {
+		schedule, err := parseRecurringSchedule(input)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		sendAt, err := firstRecurringSend(s.now().In(loc), schedule)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		scheduleJSON, err := json.Marshal(schedule)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("encode schedule: %w", err)
+		}
+		return scheduleJSON, sendAt, nil
+	}
+
+	sendAt, err := parseOneTimeSchedule(input, s.now(), loc)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	scheduleJSON, err := json.Marshal(oneTimeSchedule{SendAt: sendAt.UTC().Format(time.RFC3339)})
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("encode schedule: %w", err)
+	}
+	return scheduleJSON, sendAt, nil
+}
+
 func (s *ReminderService) DeleteGoalReminder(ctx context.Context, userID, reminderID uuid.UUID) error {
 	result, err := s.db.Exec(ctx,
 		"DELETE FROM goal_reminders WHERE id = $1 AND user_id = $2",
@@ -459,11 +971,12 @@ func (s *ReminderService) SendTestEmail(ctx context.Context, userID, cardID uuid
 	}
 
 	imageURL := ""
-	if token, err := s.createImageToken(ctx, userID, cardID, true); err == nil {
-		imageURL = fmt.Sprintf("%s/r/img/%s.png", s.baseURL, token)
+	if token, err := s.createImageToken(ctx, userID, cardID, true, settings.ImageTokenMode); err == nil {
+		imageURL = reminderImageURL(s.baseURL, token, settings.ImageTheme)
 	}
 
-	recommendations := pickReminderRecommendations(items, card.GridSize, card.FreeSpacePos, 3)
+	recommendations := s.pickReminderRecommendations(card, items, nil)
+	memory := s.checkinMemory(ctx, userID)
 	stats := buildReminderStats(card, items)
 	unsubscribeURL, err := s.createUnsubscribeURL(ctx, userID)
 	if err != nil {
@@ -474,10 +987,14 @@ func (s *ReminderService) SendTestEmail(ctx context.Context, userID, cardID uuid
 		Card:            card,
 		Stats:           stats,
 		Recommendations: recommendations,
+		Memory:          memory,
 		BaseURL:         s.baseURL,
 		ImageURL:        imageURL,
 		UnsubscribeURL:  unsubscribeURL,
+		PreferencesURL:  createEmailPreferencesURL(ctx, s.db, s.baseURL, userID, s.now()),
 		IsTest:          true,
+		Now:             s.now(),
+		Brand:           s.branding,
 	})
 
 	if s.emailService == nil {
@@ -487,40 +1004,93 @@ func (s *ReminderService) SendTestEmail(ctx context.Context, userID, cardID uuid
 }
 
 func (s *ReminderService) RunDue(ctx context.Context, now time.Time, limit int) (int, error) {
-	if limit <= 0 {
-		limit = 50
-	}
+	result, err := s.runDue(ctx, now, limit)
+	return result.Sent, err
+}
 
-	sent := 0
-	checkinSent, err := s.runDueCheckins(ctx, now, limit)
-	if err != nil {
-		return sent, err
-	}
-	sent += checkinSent
+// reminderOutcome is what processing one due reminder or digest did with it.
+type reminderOutcome int
 
-	goalSent, err := s.runDueGoals(ctx, now, limit)
-	if err != nil {
-		return sent, err
-	}
-	sent += goalSent
+const (
+	// reminderSkipped means nothing went out and the reminder was not
+	// rescheduled: it was no longer eligible, or processing failed.
+	reminderSkipped reminderOutcome = iota
+	reminderSent
+	// reminderDeferred means the send was held back (daily cap, paused
+	// email, failed delivery) and rescheduled for later.
+	reminderDeferred
+)
 
-	return sent, nil
+func countReminderOutcome(result *models.ReminderRunResult, outcome reminderOutcome) {
+	switch outcome {
+	case reminderSent:
+		result.Sent++
+	case reminderDeferred:
+		result.Deferred++
+	default:
+		result.Skipped++
+	}
 }
 
-func (s *ReminderService) CleanupOld(ctx context.Context) error {
-	if _, err := s.db.Exec(ctx, "DELETE FROM reminder_image_tokens WHERE expires_at < NOW()"); err != nil {
-		return fmt.Errorf("cleanup reminder image tokens: %w", err)
+// runDue sends due digests, then due check-ins and goal reminders, each up to
+// limit, and counts what happened to them.
+func (s *ReminderService) runDue(ctx context.Context, now time.Time, limit int) (models.ReminderRunResult, error) {
+	if limit <= 0 {
+		limit = 50
 	}
-	if _, err := s.db.Exec(ctx, "DELETE FROM reminder_unsubscribe_tokens WHERE expires_at < NOW()"); err != nil {
-		return fmt.Errorf("cleanup reminder unsubscribe tokens: %w", err)
+
+	var result models.ReminderRunResult
+	for _, run := range []func(context.Context, time.Time, int, *models.ReminderRunResult) error{
+		s.runDueDigests,
+		s.runDueCheckins,
+		s.runDueGoals,
+	} {
+		if err := run(ctx, now, limit, &result); err != nil {
+			return result, err
+		}
 	}
-	if _, err := s.db.Exec(ctx, "DELETE FROM reminder_email_log WHERE sent_at < NOW() - INTERVAL '90 days'"); err != nil {
-		return fmt.Errorf("cleanup reminder email log: %w", err)
+	return result, nil
+}
+
+// CleanupOld deletes expired reminder tokens and email log entries older
+// than 90 days in batches, and returns how many rows it deleted. The tables
+// share one time budget; whatever is left when it runs out waits for the
+// next run.
+func (s *ReminderService) CleanupOld(ctx context.Context) (int, error) {
+	deadline := time.Now().Add(s.cleanup.budget)
+	total := 0
+	for _, target := range []struct{ table, where string }{
+		{"reminder_image_tokens", "expires_at < NOW()"},
+		{"reminder_unsubscribe_tokens", "expires_at < NOW()"},
+		{"reminder_link_tokens", "expires_at < NOW()"},
+		{"reminder_snooze_tokens", "expires_at < NOW()"},
+		{"reminder_email_log", "sent_at < NOW() - INTERVAL '90 days'"},
+	} {
+		deleted, err := batchDelete(ctx, s.db, target.table, target.where, s.cleanup, deadline)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
 	}
-	return nil
+	return total, nil
 }
 
-func (s *ReminderService) RenderImageByToken(ctx context.Context, token string) ([]byte, error) {
+// reminderImageURL links an email to its card image in the user's theme.
+// Light is the image's default, so only dark is spelled out.
+func reminderImageURL(baseURL, token, theme string) string {
+	imageURL := fmt.Sprintf("%s/r/img/%s.png", baseURL, token)
+	if theme == models.ImageThemeDark {
+		imageURL += "?theme=" + models.ImageThemeDark
+	}
+	return imageURL
+}
+
+// RenderImageByToken renders the card image for a reminder image token in
+// the given theme (light when empty).
+func (s *ReminderService) RenderImageByToken(ctx context.Context, token, theme string) ([]byte, error) {
 	imageToken, err := s.loadImageToken(ctx, token)
 	if err != nil {
 		return nil, err
@@ -528,6 +1098,9 @@ func (s *ReminderService) RenderImageByToken(ctx context.Context, token string)
 	if imageToken.ExpiresAt.Before(s.now()) {
 		return nil, ErrReminderNotFound
 	}
+	if imageToken.MaxAccessCount != nil && imageToken.AccessCount >= *imageToken.MaxAccessCount {
+		return RenderReminderPlaceholderPNG(s.branding.DisplayName())
+	}
 
 	card, items, err := s.loadCardWithItems(ctx, imageToken.UserID, imageToken.CardID)
 	if err != nil {
@@ -537,8 +1110,16 @@ func (s *ReminderService) RenderImageByToken(ctx context.Context, token string)
 		return nil, err
 	}
 
+	// Completion images are the ones people forward and post, so treat them
+	// like a share and hide private goals.
+	if imageToken.ShowCompletions {
+		card.RedactPrivateItems()
+		items = card.Items
+	}
+
 	pngBytes, err := RenderReminderPNG(*card, items, RenderOptions{
 		ShowCompletions: imageToken.ShowCompletions,
+		Theme:           theme,
 	})
 	if err != nil {
 		return nil, err
@@ -551,20 +1132,27 @@ func (s *ReminderService) RenderImageByToken(ctx context.Context, token string)
 	return pngBytes, nil
 }
 
+// UnsubscribeByToken turns off the emails the token was issued for: reminder
+// emails, or the weekly friends digest. It reports whether they were already off.
 func (s *ReminderService) UnsubscribeByToken(ctx context.Context, token string) (bool, error) {
 	var userID uuid.UUID
 	var expiresAt time.Time
 	var usedAt *time.Time
+	var scope string
 	err := s.db.QueryRow(ctx,
-		"SELECT user_id, expires_at, used_at FROM reminder_unsubscribe_tokens WHERE token = $1",
+		"SELECT user_id, expires_at, used_at, scope FROM reminder_unsubscribe_tokens WHERE token = $1",
 		token,
-	).Scan(&userID, &expiresAt, &usedAt)
+	).Scan(&userID, &expiresAt, &usedAt, &scope)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return false, ErrReminderNotFound
 	}
 	if err != nil {
 		return false, fmt.Errorf("load unsubscribe token: %w", err)
 	}
+	// Preferences links share the table but never unsubscribe on their own.
+	if scope == unsubscribeScopeEmailPreferences {
+		return false, ErrReminderNotFound
+	}
 	if usedAt != nil {
 		return true, nil
 	}
@@ -572,6 +1160,27 @@ func (s *ReminderService) UnsubscribeByToken(ctx context.Context, token string)
 		return false, ErrReminderNotFound
 	}
 
+	var wasEnabled bool
+	if scope == unsubscribeScopeFriendsDigest {
+		wasEnabled, err = s.disableFriendsDigest(ctx, userID)
+	} else {
+		wasEnabled, err = s.disableReminderEmails(ctx, userID)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := s.db.Exec(ctx,
+		"UPDATE reminder_unsubscribe_tokens SET used_at = NOW() WHERE token = $1",
+		token,
+	); err != nil {
+		return false, fmt.Errorf("mark unsubscribe token used: %w", err)
+	}
+
+	return !wasEnabled, nil
+}
+
+func (s *ReminderService) disableReminderEmails(ctx context.Context, userID uuid.UUID) (bool, error) {
 	if err := s.ensureSettingsRow(ctx, userID); err != nil {
 		return false, err
 	}
@@ -590,44 +1199,43 @@ func (s *ReminderService) UnsubscribeByToken(ctx context.Context, token string)
 	); err != nil {
 		return false, fmt.Errorf("disable reminder settings: %w", err)
 	}
-	if _, err := s.db.Exec(ctx,
-		"UPDATE reminder_unsubscribe_tokens SET used_at = NOW() WHERE token = $1",
-		token,
-	); err != nil {
-		return false, fmt.Errorf("mark unsubscribe token used: %w", err)
-	}
-
-	return !wasEnabled, nil
+	return wasEnabled, nil
 }
 
-func (s *ReminderService) runDueCheckins(ctx context.Context, now time.Time, limit int) (int, error) {
-	tx, err := s.db.Begin(ctx)
+func (s *ReminderService) disableFriendsDigest(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var wasEnabled bool
+	err := s.db.QueryRow(ctx,
+		"SELECT email_friends_digest FROM notification_settings WHERE user_id = $1",
+		userID,
+	).Scan(&wasEnabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
 	if err != nil {
-		return 0, fmt.Errorf("begin checkin reminder tx: %w", err)
+		return false, fmt.Errorf("load friends digest enabled: %w", err)
 	}
-	defer func() { _ = tx.Rollback(ctx) }()
 
-	rows, err := tx.Query(ctx, `
+	if _, err := s.db.Exec(ctx,
+		"UPDATE notification_settings SET email_friends_digest = false, updated_at = NOW() WHERE user_id = $1",
+		userID,
+	); err != nil {
+		return false, fmt.Errorf("disable friends digest: %w", err)
+	}
+	return wasEnabled, nil
+}
+
+// checkinJobSelect reads checkinJob rows; callers add the WHERE clause.
+const checkinJobSelect = `
 		SELECT r.id, r.user_id, r.card_id, r.frequency, r.schedule, r.include_image,
-		       r.include_recommendations, r.next_send_at
+		       r.include_recommendations, r.include_memories, r.next_send_at, ns.email_paused_until, s.image_token_mode,
+		       s.image_theme, r.recent_recommendations, s.timezone, r.progress_snapshot
 		  FROM card_checkin_reminders r
 		  JOIN reminder_settings s ON s.user_id = r.user_id
 		  JOIN users u ON u.id = r.user_id AND u.deleted_at IS NULL
-		 WHERE r.enabled = true
-		   AND r.next_send_at <= $1
-		   AND s.email_enabled = true
-		   AND u.email_verified = true
-		 ORDER BY r.next_send_at ASC
-		 LIMIT $2
-		 FOR UPDATE OF r SKIP LOCKED`,
-		now,
-		limit,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("query due card checkins: %w", err)
-	}
-	defer rows.Close()
+		  LEFT JOIN notification_settings ns ON ns.user_id = r.user_id`
 
+func scanCheckinJobs(rows Rows) ([]checkinJob, error) {
+	defer rows.Close()
 	var jobs []checkinJob
 	for rows.Next() {
 		var job checkinJob
@@ -639,216 +1247,572 @@ func (s *ReminderService) runDueCheckins(ctx context.Context, now time.Time, lim
 			&job.Schedule,
 			&job.IncludeImage,
 			&job.IncludeRecommendations,
+			&job.IncludeMemories,
 			&job.NextSendAt,
+			&job.EmailPausedUntil,
+			&job.ImageTokenMode,
+			&job.ImageTheme,
+			&job.RecentRecommendations,
+			&job.Timezone,
+			&job.ProgressSnapshot,
 		); err != nil {
-			return 0, fmt.Errorf("scan checkin job: %w", err)
+			return nil, fmt.Errorf("scan checkin job: %w", err)
 		}
 		jobs = append(jobs, job)
 	}
+	return jobs, nil
+}
 
-	sent := 0
-	for _, job := range jobs {
-		ok, err := s.processCheckin(ctx, tx, job, now)
-		if err != nil {
-			logging.Error("Failed to process card checkin", map[string]interface{}{"error": err.Error()})
-			continue
-		}
-		if ok {
-			sent++
-		}
-	}
+// goalReminderJobSelect reads goalReminderJob rows; callers add the WHERE
+// clause.
+const goalReminderJobSelect = `
+		SELECT gr.id, gr.user_id, gr.card_id, gr.item_id, gr.kind, gr.schedule, gr.next_send_at,
+		       ns.email_paused_until, s.timezone
+		  FROM goal_reminders gr
+		  JOIN reminder_settings s ON s.user_id = gr.user_id
+		  JOIN users u ON u.id = gr.user_id AND u.deleted_at IS NULL
+		  LEFT JOIN notification_settings ns ON ns.user_id = gr.user_id`
 
-	if err := tx.Commit(ctx); err != nil {
-		return sent, fmt.Errorf("commit checkin reminder tx: %w", err)
+func scanGoalReminderJobs(rows Rows) ([]goalReminderJob, error) {
+	defer rows.Close()
+	var jobs []goalReminderJob
+	for rows.Next() {
+		var job goalReminderJob
+		if err := rows.Scan(
+			&job.ID,
+			&job.UserID,
+			&job.CardID,
+			&job.ItemID,
+			&job.Kind,
+			&job.Schedule,
+			&job.NextSendAt,
+			&job.EmailPausedUntil,
+			&job.Timezone,
+		); err != nil {
+			return nil, fmt.Errorf("scan goal reminder job: %w", err)
+		}
+		jobs = append(jobs, job)
 	}
-	return sent, nil
+	return jobs, nil
 }
 
-func (s *ReminderService) runDueGoals(ctx context.Context, now time.Time, limit int) (int, error) {
-	tx, err := s.db.Begin(ctx)
+// runDueCheckins sends due check-ins one at a time. Each is claimed in a
+// short transaction, its email is sent with no transaction open, and the
+// outcome is recorded in a second short transaction, so replicas running side
+// by side never send the same check-in twice and a slow provider holds no
+// locks.
+func (s *ReminderService) runDueCheckins(ctx context.Context, now time.Time, limit int, result *models.ReminderRunResult) error {
+	rows, err := s.db.Query(ctx, checkinJobSelect+`
+		 WHERE r.enabled = true
+		   AND r.next_send_at <= $1
+		   AND (r.claimed_at IS NULL OR r.claimed_at <= $3)
+		   AND s.email_enabled = true
+		   AND s.digest_enabled = false
+		   AND u.email_verified = true
+		 ORDER BY r.next_send_at ASC
+		 LIMIT $2`,
+		now,
+		limit,
+		s.now().Add(-reminderClaimLease),
+	)
 	if err != nil {
-		return 0, fmt.Errorf("begin goal reminder tx: %w", err)
+		return fmt.Errorf("query due card checkins: %w", err)
+	}
+	jobs, err := scanCheckinJobs(rows)
+	if err != nil {
+		return err
 	}
-	defer func() { _ = tx.Rollback(ctx) }()
 
-	rows, err := tx.Query(ctx, `
-		SELECT gr.id, gr.user_id, gr.card_id, gr.item_id, gr.kind, gr.schedule, gr.next_send_at
-		  FROM goal_reminders gr
-		  JOIN reminder_settings s ON s.user_id = gr.user_id
-		  JOIN users u ON u.id = gr.user_id AND u.deleted_at IS NULL
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		outcome, err := s.processCheckin(ctx, job, now)
+		if err != nil {
+			logging.Error("Failed to process card checkin", map[string]interface{}{"error": err.Error()})
+			outcome = reminderSkipped
+		}
+		countReminderOutcome(result, outcome)
+	}
+	return nil
+}
+
+// runDueGoals sends due goal reminders the same way runDueCheckins sends
+// check-ins.
+func (s *ReminderService) runDueGoals(ctx context.Context, now time.Time, limit int, result *models.ReminderRunResult) error {
+	rows, err := s.db.Query(ctx, goalReminderJobSelect+`
 		 WHERE gr.enabled = true
 		   AND gr.next_send_at <= $1
+		   AND (gr.claimed_at IS NULL OR gr.claimed_at <= $3)
 		   AND s.email_enabled = true
+		   AND s.digest_enabled = false
 		   AND u.email_verified = true
 		 ORDER BY gr.next_send_at ASC
-		 LIMIT $2
-		 FOR UPDATE OF gr SKIP LOCKED`,
+		 LIMIT $2`,
 		now,
 		limit,
+		s.now().Add(-reminderClaimLease),
 	)
 	if err != nil {
-		return 0, fmt.Errorf("query due goal reminders: %w", err)
+		return fmt.Errorf("query due goal reminders: %w", err)
+	}
+	jobs, err := scanGoalReminderJobs(rows)
+	if err != nil {
+		return err
 	}
-	defer rows.Close()
 
-	var jobs []goalReminderJob
-	for rows.Next() {
-		var job goalReminderJob
-		if err := rows.Scan(
-			&job.ID,
-			&job.UserID,
-			&job.CardID,
-			&job.ItemID,
-			&job.Kind,
-			&job.Schedule,
-			&job.NextSendAt,
-		); err != nil {
-			return 0, fmt.Errorf("scan goal reminder job: %w", err)
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		outcome, err := s.processGoalReminder(ctx, job, now)
+		if err != nil {
+			logging.Error("Failed to process goal reminder", map[string]interface{}{"error": err.Error()})
+			outcome = reminderSkipped
+		}
+		countReminderOutcome(result, outcome)
+	}
+	return nil
+}
+
+// reminderSend is the result of handing one reminder email to the provider.
+type reminderSend struct {
+	sent bool
+	// rateLimited means the email limiter refused the send, so it never
+	// reached the provider: it is retried like a failure, but the retry is
+	// what gets logged and notified.
+	rateLimited bool
+}
+
+func (s *ReminderService) sendReminderEmail(ctx context.Context, to, subject, html, text string) reminderSend {
+	if s.emailService == nil {
+		return reminderSend{}
+	}
+	if err := s.emailService.SendNotificationEmail(ctx, to, subject, html, text); err != nil {
+		return reminderSend{rateLimited: errors.Is(err, ErrEmailRateLimited)}
+	}
+	return reminderSend{sent: true}
+}
+
+func (r reminderSend) status() reminderEmailStatus {
+	if r.sent {
+		return reminderEmailSent
+	}
+	return reminderEmailFailed
+}
+
+func (r reminderSend) outcome() reminderOutcome {
+	if r.sent {
+		return reminderSent
+	}
+	return reminderDeferred
+}
+
+func (s *ReminderService) processCheckin(ctx context.Context, job checkinJob, now time.Time) (reminderOutcome, error) {
+	// The daily cap, the email log day and the next send are all counted in
+	// the user's zone.
+	now = now.In(reminderLocation(job.Timezone))
+	card, items, outcome, err := s.claimCheckin(ctx, job, now)
+	if err != nil || card == nil {
+		return outcome, err
+	}
+
+	userEmail, err := s.loadUserEmail(ctx, job.UserID)
+	if err != nil {
+		s.releaseReminderClaim(ctx, "card_checkin_reminders", job.ID)
+		return reminderSkipped, err
+	}
+
+	recommendations := s.checkinRecommendations(job, card, items)
+	link := s.createReminderLink(ctx, job.UserID, "card_checkin", job.ID, checkinLinkPath(job.CardID))
+	subject, html, text, err := s.composeCheckinEmail(ctx, job, card, items, recommendations, link.url)
+	if err != nil {
+		s.discardReminderLink(ctx, link)
+		s.releaseReminderClaim(ctx, "card_checkin_reminders", job.ID)
+		return reminderSkipped, err
+	}
+
+	send := s.sendReminderEmail(ctx, userEmail, subject, html, text)
+	if !send.sent {
+		s.discardReminderLink(ctx, link)
+	}
+	if err := s.finishCheckin(ctx, job, card, items, recommendations, send, now); err != nil {
+		// The send already happened either way; the claim lapses after the
+		// lease and the reminder goes out again.
+		return send.outcome(), err
+	}
+	return send.outcome(), nil
+}
+
+// claimCheckin claims a due check-in for this runner in a short transaction
+// and re-checks it. It returns a nil card with the outcome when there is
+// nothing to send: another runner got to it first, or it was disabled or
+// deferred, which releases the claim.
+func (s *ReminderService) claimCheckin(ctx context.Context, job checkinJob, now time.Time) (*models.BingoCard, []models.BingoItem, reminderOutcome, error) {
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, nil, reminderSkipped, fmt.Errorf("begin checkin claim tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	card, items, outcome, err := s.claimCheckinTx(ctx, tx, job, now)
+	if err != nil {
+		return nil, nil, reminderSkipped, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, reminderSkipped, fmt.Errorf("commit checkin claim tx: %w", err)
+	}
+	return card, items, outcome, nil
+}
+
+func (s *ReminderService) claimCheckinTx(ctx context.Context, tx Tx, job checkinJob, now time.Time) (*models.BingoCard, []models.BingoItem, reminderOutcome, error) {
+	// Claim before anything else, so only the claim holder disables,
+	// defers or sends the reminder.
+	claimed, err := s.claimReminder(ctx, tx, "card_checkin_reminders", job.ID, now)
+	if err != nil || !claimed {
+		return nil, nil, reminderSkipped, err
+	}
+
+	card, items, err := s.loadCardWithItemsTx(ctx, tx, job.UserID, job.CardID)
+	if err != nil {
+		if errors.Is(err, ErrCardNotFound) {
+			outcome, err := s.disableCheckin(ctx, tx, job.ID)
+			return nil, nil, outcome, err
+		}
+		return nil, nil, reminderSkipped, err
+	}
+	if !card.IsFinalized || card.IsArchived {
+		outcome, err := s.disableCheckin(ctx, tx, job.ID)
+		return nil, nil, outcome, err
+	}
+
+	if emailPaused(job.EmailPausedUntil, now) {
+		if err := s.deferCheckinUntilPauseEnds(ctx, tx, job.ID, *job.EmailPausedUntil); err != nil {
+			return nil, nil, reminderSkipped, err
+		}
+		return nil, nil, reminderDeferred, nil
+	}
+
+	// The settings lock serializes claims for one user until this claim
+	// commits, so the cap check sees every check-in another runner has
+	// claimed but not yet sent.
+	if err := s.lockReminderSettings(ctx, tx, job.UserID); err != nil {
+		return nil, nil, reminderSkipped, err
+	}
+
+	capReached, err := s.cardCheckinCapReached(ctx, job.UserID, job.ID, now)
+	if err != nil {
+		return nil, nil, reminderSkipped, err
+	}
+	if capReached {
+		if err := s.deferCheckinAfterCapReached(ctx, tx, job, now); err != nil {
+			return nil, nil, reminderSkipped, err
+		}
+		return nil, nil, reminderDeferred, nil
+	}
+	return card, items, reminderSent, nil
+}
+
+// finishCheckin records a check-in send in a short transaction: the in-app
+// notification, the next send or the retry, the email log row, and the
+// release of the claim.
+func (s *ReminderService) finishCheckin(ctx context.Context, job checkinJob, card *models.BingoCard, items []models.BingoItem, recommendations []models.BingoItem, send reminderSend, now time.Time) error {
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("begin checkin finish tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if s.notificationService != nil && !send.rateLimited {
+		if err := s.notificationService.NotifyReminderCheckin(ctx, tx, job.UserID, job.CardID, now); err != nil {
+			logging.Error("Failed to create check-in notification", map[string]interface{}{"error": err.Error()})
+		}
+	}
+
+	if send.sent {
+		nextSendAt, err := s.nextCheckinSendAt(now, job)
+		if err != nil {
+			return err
+		}
+		recent, err := appendRecentRecommendations(job.RecentRecommendations, recommendations)
+		if err != nil {
+			return fmt.Errorf("encode recent recommendations: %w", err)
+		}
+		snapshot, err := json.Marshal(newCheckinSnapshot(card, items))
+		if err != nil {
+			return fmt.Errorf("encode progress snapshot: %w", err)
+		}
+		if err := s.updateCheckinAfterSend(ctx, tx, job.ID, now, nextSendAt, recent, snapshot); err != nil {
+			return err
+		}
+	} else {
+		if err := s.deferCheckinAfterFailure(ctx, tx, job.ID, now); err != nil {
+			return err
+		}
+	}
+
+	if !send.rateLimited {
+		if err := s.logReminderEmail(ctx, tx, job.UserID, "card_checkin", job.ID, send.status(), now); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit checkin finish tx: %w", err)
+	}
+	return nil
+}
+
+func (s *ReminderService) processGoalReminder(ctx context.Context, job goalReminderJob, now time.Time) (reminderOutcome, error) {
+	now = now.In(reminderLocation(job.Timezone))
+	ctxData, outcome, err := s.claimGoalReminder(ctx, job, now)
+	if err != nil || ctxData == nil {
+		return outcome, err
+	}
+
+	link := s.createReminderLink(ctx, job.UserID, "goal_reminder", job.ID, goalLinkPath(ctxData.CardID, job.ItemID))
+	subject, html, text, err := s.composeGoalReminderEmail(ctx, job, ctxData, link.url)
+	if err != nil {
+		s.discardReminderLink(ctx, link)
+		s.releaseReminderClaim(ctx, "goal_reminders", job.ID)
+		return reminderSkipped, err
+	}
+
+	send := s.sendReminderEmail(ctx, ctxData.UserEmail, subject, html, text)
+	if !send.sent {
+		s.discardReminderLink(ctx, link)
+	}
+	if err := s.finishGoalReminder(ctx, job, ctxData, send, now); err != nil {
+		return send.outcome(), err
+	}
+	return send.outcome(), nil
+}
+
+// claimGoalReminder claims and re-checks a due goal reminder, like
+// claimCheckin.
+func (s *ReminderService) claimGoalReminder(ctx context.Context, job goalReminderJob, now time.Time) (*goalReminderContext, reminderOutcome, error) {
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, reminderSkipped, fmt.Errorf("begin goal reminder claim tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ctxData, outcome, err := s.claimGoalReminderTx(ctx, tx, job, now)
+	if err != nil {
+		return nil, reminderSkipped, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, reminderSkipped, fmt.Errorf("commit goal reminder claim tx: %w", err)
+	}
+	return ctxData, outcome, nil
+}
+
+func (s *ReminderService) claimGoalReminderTx(ctx context.Context, tx Tx, job goalReminderJob, now time.Time) (*goalReminderContext, reminderOutcome, error) {
+	claimed, err := s.claimReminder(ctx, tx, "goal_reminders", job.ID, now)
+	if err != nil || !claimed {
+		return nil, reminderSkipped, err
+	}
+
+	ctxData, err := s.loadGoalReminderContext(ctx, job.UserID, job.ItemID)
+	if err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			outcome, err := s.disableGoalReminder(ctx, tx, job.ID)
+			return nil, outcome, err
+		}
+		return nil, reminderSkipped, err
+	}
+	if !ctxData.CardFinalized || ctxData.CardArchived || ctxData.ItemCompleted {
+		outcome, err := s.disableGoalReminder(ctx, tx, job.ID)
+		return nil, outcome, err
+	}
+
+	if emailPaused(job.EmailPausedUntil, now) {
+		if err := s.deferGoalUntilPauseEnds(ctx, tx, job.ID, *job.EmailPausedUntil); err != nil {
+			return nil, reminderSkipped, err
+		}
+		return nil, reminderDeferred, nil
+	}
+
+	if err := s.lockReminderSettings(ctx, tx, job.UserID); err != nil {
+		return nil, reminderSkipped, err
+	}
+
+	capReached, err := s.goalReminderCapReached(ctx, job.UserID, job.ID, now, ctxData.DailyCap)
+	if err != nil {
+		return nil, reminderSkipped, err
+	}
+	if capReached {
+		if err := s.deferGoalAfterCapReached(ctx, tx, job, now); err != nil {
+			return nil, reminderSkipped, err
+		}
+		return nil, reminderDeferred, nil
+	}
+	return ctxData, reminderSent, nil
+}
+
+// finishGoalReminder records a goal reminder send in a short transaction,
+// like finishCheckin.
+func (s *ReminderService) finishGoalReminder(ctx context.Context, job goalReminderJob, ctxData *goalReminderContext, send reminderSend, now time.Time) error {
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("begin goal reminder finish tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if s.notificationService != nil && !send.rateLimited {
+		if err := s.notificationService.NotifyReminderGoal(ctx, tx, job.UserID, ctxData.CardID, job.ItemID, now); err != nil {
+			logging.Error("Failed to create goal reminder notification", map[string]interface{}{"error": err.Error()})
+		}
+	}
+
+	if send.sent && job.Kind == models.GoalReminderKindRecurring {
+		// Recurring reminders keep going until the goal is completed or the
+		// card is archived, which the claim checks turn into a disable.
+		nextSendAt, err := s.nextGoalSendAt(now, job)
+		if err != nil {
+			return err
+		}
+		if err := s.rescheduleGoalReminder(ctx, tx, job.ID, now, nextSendAt); err != nil {
+			return err
+		}
+	} else if send.sent {
+		if err := s.markGoalReminderSent(ctx, tx, job.ID, now); err != nil {
+			return err
+		}
+	} else {
+		if err := s.deferGoalAfterFailure(ctx, tx, job.ID, now); err != nil {
+			return err
 		}
-		jobs = append(jobs, job)
 	}
 
-	sent := 0
-	for _, job := range jobs {
-		ok, err := s.processGoalReminder(ctx, tx, job, now)
-		if err != nil {
-			logging.Error("Failed to process goal reminder", map[string]interface{}{"error": err.Error()})
-			continue
-		}
-		if ok {
-			sent++
+	if !send.rateLimited {
+		if err := s.logReminderEmail(ctx, tx, job.UserID, "goal_reminder", job.ID, send.status(), now); err != nil {
+			return err
 		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return sent, fmt.Errorf("commit goal reminder tx: %w", err)
+		return fmt.Errorf("commit goal reminder finish tx: %w", err)
 	}
-	return sent, nil
+	return nil
 }
 
-func (s *ReminderService) processCheckin(ctx context.Context, tx Tx, job checkinJob, now time.Time) (bool, error) {
-	card, items, err := s.loadCardWithItemsTx(ctx, tx, job.UserID, job.CardID)
+// claimReminder marks a due reminder as being sent by this runner. It
+// returns false when the reminder is no longer due or another runner holds a
+// live claim on it, which the UPDATE settles atomically. table is
+// card_checkin_reminders or goal_reminders.
+func (s *ReminderService) claimReminder(ctx context.Context, tx Tx, table string, reminderID uuid.UUID, now time.Time) (bool, error) {
+	claimedAt := s.now()
+	tag, err := tx.Exec(ctx,
+		"UPDATE "+table+` SET claimed_at = $2, claimed_by = $3
+		 WHERE id = $1
+		   AND enabled = true
+		   AND next_send_at <= $4
+		   AND (claimed_at IS NULL OR claimed_at <= $5)`,
+		reminderID,
+		claimedAt,
+		s.runnerID,
+		now,
+		claimedAt.Add(-reminderClaimLease),
+	)
 	if err != nil {
-		if errors.Is(err, ErrCardNotFound) {
-			return s.disableCheckin(ctx, tx, job.ID)
-		}
-		return false, err
-	}
-	if !card.IsFinalized || card.IsArchived {
-		return s.disableCheckin(ctx, tx, job.ID)
+		return false, fmt.Errorf("claim %s: %w", table, err)
 	}
+	return tag.RowsAffected() > 0, nil
+}
 
-	if err := s.lockReminderSettings(ctx, tx, job.UserID); err != nil {
-		return false, err
+// releaseReminderClaim drops this runner's claim on a reminder it gave up on
+// before sending, so the next run picks it up again.
+func (s *ReminderService) releaseReminderClaim(ctx context.Context, table string, reminderID uuid.UUID) {
+	if _, err := s.db.Exec(ctx,
+		"UPDATE "+table+" SET claimed_at = NULL, claimed_by = NULL WHERE id = $1 AND claimed_by = $2",
+		reminderID,
+		s.runnerID,
+	); err != nil {
+		logging.Warn("Failed to release reminder claim", map[string]interface{}{"table": table, "error": err.Error()})
 	}
+}
 
-	capReached, err := s.cardCheckinCapReached(ctx, job.UserID, now)
-	if err != nil {
-		return false, err
-	}
-	if capReached {
-		if err := s.deferCheckinAfterCapReached(ctx, tx, job, now); err != nil {
-			return false, err
-		}
-		return false, nil
+// checkinRecommendations picks the goals a check-in email suggests, or none
+// when the reminder has recommendations turned off.
+func (s *ReminderService) checkinRecommendations(job checkinJob, card *models.BingoCard, items []models.BingoItem) []models.BingoItem {
+	if !job.IncludeRecommendations {
+		return nil
 	}
+	return s.pickReminderRecommendations(card, items, recentRecommendationIDs(job.RecentRecommendations))
+}
 
+// composeCheckinEmail renders a scheduled card check-in email, minting the
+// image, unsubscribe and preferences tokens it links to. A non-empty linkURL
+// replaces the card link.
+func (s *ReminderService) composeCheckinEmail(ctx context.Context, job checkinJob, card *models.BingoCard, items []models.BingoItem, recommendations []models.BingoItem, linkURL string) (string, string, string, error) {
 	stats := buildReminderStats(card, items)
-	var recommendations []models.BingoItem
-	if job.IncludeRecommendations {
-		recommendations = pickReminderRecommendations(items, card.GridSize, card.FreeSpacePos, 3)
+	var memory *models.Memory
+	if job.IncludeMemories {
+		memory = s.checkinMemory(ctx, job.UserID)
 	}
 
 	imageURL := ""
 	if job.IncludeImage {
-		if token, err := s.createImageToken(ctx, job.UserID, job.CardID, true); err == nil {
-			imageURL = fmt.Sprintf("%s/r/img/%s.png", s.baseURL, token)
+		if token, err := s.createImageToken(ctx, job.UserID, job.CardID, true, job.ImageTokenMode); err == nil {
+			imageURL = reminderImageURL(s.baseURL, token, job.ImageTheme)
 		}
 	}
 
-	userEmail, err := s.loadUserEmail(ctx, job.UserID)
-	if err != nil {
-		return false, err
-	}
-
 	unsubscribeURL, err := s.createUnsubscribeURL(ctx, job.UserID)
 	if err != nil {
-		return false, err
+		return "", "", "", err
 	}
 	subject, html, text := buildCheckinEmail(checkinEmailParams{
 		Card:            card,
 		Stats:           stats,
+		Delta:           checkinProgressDelta(job.ProgressSnapshot, items, stats),
 		Recommendations: recommendations,
+		Memory:          memory,
 		BaseURL:         s.baseURL,
 		ImageURL:        imageURL,
+		LinkURL:         linkURL,
 		UnsubscribeURL:  unsubscribeURL,
+		PreferencesURL:  createEmailPreferencesURL(ctx, s.db, s.baseURL, job.UserID, s.now()),
 		IsTest:          false,
+		Now:             s.now(),
+		Brand:           s.branding,
 	})
-
-	sent := false
-	status := reminderEmailSent
-	if s.emailService == nil {
-		status = reminderEmailFailed
-	} else if err := s.emailService.SendNotificationEmail(ctx, userEmail, subject, html, text); err != nil {
-		status = reminderEmailFailed
-	} else {
-		sent = true
-	}
-
-	if sent {
-		nextSendAt, err := s.nextCheckinSendAt(now, job)
-		if err != nil {
-			return sent, err
-		}
-		if err := s.updateCheckinAfterSend(ctx, tx, job.ID, now, nextSendAt); err != nil {
-			return sent, err
-		}
-	} else {
-		if err := s.deferCheckinAfterFailure(ctx, tx, job.ID, now); err != nil {
-			return sent, err
-		}
-	}
-
-	if err := s.logReminderEmail(ctx, tx, job.UserID, "card_checkin", job.ID, status, now); err != nil {
-		return sent, err
-	}
-
-	return sent, nil
+	return subject, html, text, nil
 }
 
-func (s *ReminderService) processGoalReminder(ctx context.Context, tx Tx, job goalReminderJob, now time.Time) (bool, error) {
-	ctxData, err := s.loadGoalReminderContext(ctx, job.UserID, job.ItemID)
-	if err != nil {
-		if errors.Is(err, ErrItemNotFound) {
-			return s.disableGoalReminder(ctx, tx, job.ID)
-		}
-		return false, err
-	}
-	if !ctxData.CardFinalized || ctxData.CardArchived {
-		return s.disableGoalReminder(ctx, tx, job.ID)
-	}
-	if ctxData.ItemCompleted {
-		return s.disableGoalReminder(ctx, tx, job.ID)
-	}
-
-	if err := s.lockReminderSettings(ctx, tx, job.UserID); err != nil {
-		return false, err
+// checkinMemory picks the most recent goal the user completed around today's
+// date in an earlier year. Reminders have no per-user timezone, so "today" is
+// server time, like the schedule. Lookup failures only drop the line.
+func (s *ReminderService) checkinMemory(ctx context.Context, userID uuid.UUID) *models.Memory {
+	if s.memoryFinder == nil {
+		return nil
 	}
-
-	capReached, err := s.goalReminderCapReached(ctx, job.UserID, now, ctxData.DailyCap)
+	memories, err := s.memoryFinder.GetMemories(ctx, userID, s.now(), time.UTC)
 	if err != nil {
-		return false, err
+		logging.Warn("Failed to load check-in memories", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+		return nil
 	}
-	if capReached {
-		if err := s.deferGoalAfterCapReached(ctx, tx, job, now); err != nil {
-			return false, err
-		}
-		return false, nil
+	if len(memories) == 0 {
+		return nil
 	}
+	return &memories[0]
+}
 
+// composeGoalReminderEmail renders a goal reminder email with fresh snooze,
+// unsubscribe and preferences links. A non-empty linkURL replaces the goal
+// link.
+func (s *ReminderService) composeGoalReminderEmail(ctx context.Context, job goalReminderJob, ctxData *goalReminderContext, linkURL string) (string, string, string, error) {
 	unsubscribeURL, err := s.createUnsubscribeURL(ctx, job.UserID)
 	if err != nil {
-		return false, err
+		return "", "", "", err
+	}
+	snoozeURL, err := s.createSnoozeURL(ctx, job.UserID, job.ID)
+	if err != nil {
+		return "", "", "", err
 	}
 	subject, html, text := buildGoalReminderEmail(goalReminderEmailParams{
 		CardID:         ctxData.CardID,
@@ -857,34 +1821,13 @@ func (s *ReminderService) processGoalReminder(ctx context.Context, tx Tx, job go
 		CardYear:       ctxData.CardYear,
 		GoalText:       ctxData.ItemContent,
 		BaseURL:        s.baseURL,
+		LinkURL:        linkURL,
+		SnoozeURL:      snoozeURL,
 		UnsubscribeURL: unsubscribeURL,
+		PreferencesURL: createEmailPreferencesURL(ctx, s.db, s.baseURL, job.UserID, s.now()),
+		Brand:          s.branding,
 	})
-
-	sent := false
-	status := reminderEmailSent
-	if s.emailService == nil {
-		status = reminderEmailFailed
-	} else if err := s.emailService.SendNotificationEmail(ctx, ctxData.UserEmail, subject, html, text); err != nil {
-		status = reminderEmailFailed
-	} else {
-		sent = true
-	}
-
-	if sent {
-		if err := s.markGoalReminderSent(ctx, tx, job.ID, now); err != nil {
-			return sent, err
-		}
-	} else {
-		if err := s.deferGoalAfterFailure(ctx, tx, job.ID, now); err != nil {
-			return sent, err
-		}
-	}
-
-	if err := s.logReminderEmail(ctx, tx, job.UserID, "goal_reminder", job.ID, status, now); err != nil {
-		return sent, err
-	}
-
-	return sent, nil
+	return subject, html, text, nil
 }
 
 func (s *ReminderService) nextCheckinSendAt(now time.Time, job checkinJob) (time.Time, error) {
@@ -892,14 +1835,18 @@ func (s *ReminderService) nextCheckinSendAt(now time.Time, job checkinJob) (time
 	if err := json.Unmarshal(job.Schedule, &schedule); err != nil {
 		return time.Time{}, ErrInvalidSchedule
 	}
-	return nextMonthlySend(now, schedule)
+	return nextMonthlySend(now.In(reminderLocation(job.Timezone)), schedule)
 }
 
-func (s *ReminderService) updateCheckinAfterSend(ctx context.Context, tx Tx, reminderID uuid.UUID, sentAt, nextSendAt time.Time) error {
+// updateCheckinAfterSend schedules the next check-in and records what this
+// one suggested and the progress it reported, in the send's transaction.
+func (s *ReminderService) updateCheckinAfterSend(ctx context.Context, tx Tx, reminderID uuid.UUID, sentAt, nextSendAt time.Time, recent, snapshot []byte) error {
 	_, err := tx.Exec(ctx,
-		"UPDATE card_checkin_reminders SET last_sent_at = $1, next_send_at = $2, updated_at = NOW() WHERE id = $3",
+		"UPDATE card_checkin_reminders SET last_sent_at = $1, next_send_at = $2, recent_recommendations = $3, progress_snapshot = $4, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $5",
 		sentAt,
 		nextSendAt,
+		recent,
+		snapshot,
 		reminderID,
 	)
 	if err != nil {
@@ -914,7 +1861,7 @@ func (s *ReminderService) deferCheckinAfterFailure(ctx context.Context, tx Tx, r
 	}
 	next := now.Add(15 * time.Minute)
 	_, err := tx.Exec(ctx,
-		"UPDATE card_checkin_reminders SET next_send_at = $1, updated_at = NOW() WHERE id = $2",
+		"UPDATE card_checkin_reminders SET next_send_at = $1, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $2",
 		next,
 		reminderID,
 	)
@@ -934,10 +1881,14 @@ func (s *ReminderService) deferCheckinAfterCapReached(ctx context.Context, tx Tx
 		return ErrInvalidSchedule
 	}
 
-	loc := now.Location()
-	nextDay := time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), 0, 0, loc).AddDate(0, 0, 1)
+	loc := reminderLocation(job.Timezone)
+	now = now.In(loc)
+	nextDay := time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), 0, 0, loc).AddDate(0, 0, 1).Add(schedule.jitterOffset())
+	for !nextDay.After(now) {
+		nextDay = nextDay.AddDate(0, 0, 1)
+	}
 	_, err = tx.Exec(ctx,
-		"UPDATE card_checkin_reminders SET next_send_at = $1, updated_at = NOW() WHERE id = $2",
+		"UPDATE card_checkin_reminders SET next_send_at = $1, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $2",
 		nextDay,
 		job.ID,
 	)
@@ -947,13 +1898,30 @@ func (s *ReminderService) deferCheckinAfterCapReached(ctx context.Context, tx Tx
 	return nil
 }
 
+// deferCheckinUntilPauseEnds pushes a due check-in to the end of the user's
+// email pause so it is sent once the pause expires rather than skipped.
+func (s *ReminderService) deferCheckinUntilPauseEnds(ctx context.Context, tx Tx, reminderID uuid.UUID, until time.Time) error {
+	if tx == nil {
+		return fmt.Errorf("defer checkin until pause ends: missing transaction")
+	}
+	_, err := tx.Exec(ctx,
+		"UPDATE card_checkin_reminders SET next_send_at = $1, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $2",
+		until,
+		reminderID,
+	)
+	if err != nil {
+		return fmt.Errorf("defer card checkin until pause ends: %w", err)
+	}
+	return nil
+}
+
 func (s *ReminderService) deferGoalAfterFailure(ctx context.Context, tx Tx, reminderID uuid.UUID, now time.Time) error {
 	if tx == nil {
 		return fmt.Errorf("defer goal reminder after failure: missing transaction")
 	}
 	next := now.Add(15 * time.Minute)
 	_, err := tx.Exec(ctx,
-		"UPDATE goal_reminders SET next_send_at = $1, updated_at = NOW() WHERE id = $2",
+		"UPDATE goal_reminders SET next_send_at = $1, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $2",
 		next,
 		reminderID,
 	)
@@ -968,11 +1936,12 @@ func (s *ReminderService) deferGoalAfterCapReached(ctx context.Context, tx Tx, j
 		return fmt.Errorf("defer goal reminder after cap: missing transaction")
 	}
 
-	base := job.NextSendAt
-	loc := base.Location()
-	nextDay := time.Date(now.In(loc).Year(), now.In(loc).Month(), now.In(loc).Day(), base.Hour(), base.Minute(), 0, 0, loc).AddDate(0, 0, 1)
+	loc := reminderLocation(job.Timezone)
+	base := job.NextSendAt.In(loc)
+	today := now.In(loc)
+	nextDay := time.Date(today.Year(), today.Month(), today.Day(), base.Hour(), base.Minute(), 0, 0, loc).AddDate(0, 0, 1)
 	_, err := tx.Exec(ctx,
-		"UPDATE goal_reminders SET next_send_at = $1, updated_at = NOW() WHERE id = $2",
+		"UPDATE goal_reminders SET next_send_at = $1, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $2",
 		nextDay,
 		job.ID,
 	)
@@ -982,12 +1951,33 @@ func (s *ReminderService) deferGoalAfterCapReached(ctx context.Context, tx Tx, j
 	return nil
 }
 
+// deferGoalUntilPauseEnds pushes a due goal reminder to the end of the user's
+// email pause.
+func (s *ReminderService) deferGoalUntilPauseEnds(ctx context.Context, tx Tx, reminderID uuid.UUID, until time.Time) error {
+	if tx == nil {
+		return fmt.Errorf("defer goal reminder until pause ends: missing transaction")
+	}
+	_, err := tx.Exec(ctx,
+		"UPDATE goal_reminders SET next_send_at = $1, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $2",
+		until,
+		reminderID,
+	)
+	if err != nil {
+		return fmt.Errorf("defer goal reminder until pause ends: %w", err)
+	}
+	return nil
+}
+
+func emailPaused(until *time.Time, now time.Time) bool {
+	return until != nil && until.After(now)
+}
+
 func (s *ReminderService) markGoalReminderSent(ctx context.Context, tx Tx, reminderID uuid.UUID, sentAt time.Time) error {
 	if tx == nil {
 		return fmt.Errorf("mark goal reminder sent: missing transaction")
 	}
 	_, err := tx.Exec(ctx,
-		"UPDATE goal_reminders SET last_sent_at = $1, enabled = false, next_send_at = NULL, updated_at = NOW() WHERE id = $2",
+		"UPDATE goal_reminders SET last_sent_at = $1, enabled = false, next_send_at = NULL, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $2",
 		sentAt,
 		reminderID,
 	)
@@ -997,6 +1987,32 @@ func (s *ReminderService) markGoalReminderSent(ctx context.Context, tx Tx, remin
 	return nil
 }
 
+// nextGoalSendAt is the send after one made at now: EveryDays calendar days
+// later, at the scheduled time in the user's zone.
+func (s *ReminderService) nextGoalSendAt(now time.Time, job goalReminderJob) (time.Time, error) {
+	var schedule recurringSchedule
+	if err := json.Unmarshal(job.Schedule, &schedule); err != nil {
+		return time.Time{}, ErrInvalidSchedule
+	}
+	return nextRecurringSend(now.In(reminderLocation(job.Timezone)), schedule)
+}
+
+func (s *ReminderService) rescheduleGoalReminder(ctx context.Context, tx Tx, reminderID uuid.UUID, sentAt, nextSendAt time.Time) error {
+	if tx == nil {
+		return fmt.Errorf("reschedule goal reminder: missing transaction")
+	}
+	_, err := tx.Exec(ctx,
+		"UPDATE goal_reminders SET last_sent_at = $1, next_send_at = $2, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $3",
+		sentAt,
+		nextSendAt,
+		reminderID,
+	)
+	if err != nil {
+		return fmt.Errorf("reschedule goal reminder: %w", err)
+	}
+	return nil
+}
+
 func (s *ReminderService) logReminderEmail(ctx context.Context, tx Tx, userID uuid.UUID, sourceType string, sourceID uuid.UUID, status reminderEmailStatus, sentAt time.Time) error {
 	sentOn := time.Date(sentAt.Year(), sentAt.Month(), sentAt.Day(), 0, 0, 0, 0, sentAt.Location())
 	if tx != nil {
@@ -1019,11 +2035,11 @@ type reminderEmailLogDB interface {
 }
 
 func (s *ReminderService) upsertReminderEmailLog(ctx context.Context, db reminderEmailLogDB, userID uuid.UUID, sourceType string, sourceID uuid.UUID, status reminderEmailStatus, sentAt, sentOn time.Time) error {
-	if sourceType == "card_checkin" {
+	if sourceType == "card_checkin" && status != reminderEmailManual {
 		_, err := db.Exec(ctx, `
 			INSERT INTO reminder_email_log (user_id, source_type, source_id, status, sent_at, sent_on)
 			VALUES ($1, $2, $3, $4, $5, $6)
-			ON CONFLICT (source_type, source_id, sent_on) WHERE source_type = 'card_checkin'
+			ON CONFLICT (source_type, source_id, sent_on) WHERE source_type = 'card_checkin' AND status <> 'manual'
 			DO UPDATE SET status = EXCLUDED.status, sent_at = EXCLUDED.sent_at`,
 			userID,
 			sourceType,
@@ -1046,54 +2062,65 @@ func (s *ReminderService) upsertReminderEmailLog(ctx context.Context, db reminde
 	return err
 }
 
-func (s *ReminderService) disableCheckin(ctx context.Context, tx Tx, reminderID uuid.UUID) (bool, error) {
+func (s *ReminderService) disableCheckin(ctx context.Context, tx Tx, reminderID uuid.UUID) (reminderOutcome, error) {
 	_, err := tx.Exec(ctx,
-		"UPDATE card_checkin_reminders SET enabled = false, next_send_at = NULL, updated_at = NOW() WHERE id = $1",
+		"UPDATE card_checkin_reminders SET enabled = false, next_send_at = NULL, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $1",
 		reminderID,
 	)
 	if err != nil {
-		return false, fmt.Errorf("disable card checkin: %w", err)
+		return reminderSkipped, fmt.Errorf("disable card checkin: %w", err)
 	}
-	return false, nil
+	return reminderSkipped, nil
 }
 
-func (s *ReminderService) disableGoalReminder(ctx context.Context, tx Tx, reminderID uuid.UUID) (bool, error) {
+func (s *ReminderService) disableGoalReminder(ctx context.Context, tx Tx, reminderID uuid.UUID) (reminderOutcome, error) {
 	if tx == nil {
-		return false, fmt.Errorf("disable goal reminder: missing transaction")
+		return reminderSkipped, fmt.Errorf("disable goal reminder: missing transaction")
 	}
 	_, err := tx.Exec(ctx,
-		"UPDATE goal_reminders SET enabled = false, next_send_at = NULL, updated_at = NOW() WHERE id = $1",
+		"UPDATE goal_reminders SET enabled = false, next_send_at = NULL, claimed_at = NULL, claimed_by = NULL, updated_at = NOW() WHERE id = $1",
 		reminderID,
 	)
 	if err != nil {
-		return false, fmt.Errorf("disable goal reminder: %w", err)
+		return reminderSkipped, fmt.Errorf("disable goal reminder: %w", err)
 	}
-	return false, nil
+	return reminderSkipped, nil
 }
 
-func (s *ReminderService) cardCheckinCapReached(ctx context.Context, userID uuid.UUID, now time.Time) (bool, error) {
+// cardCheckinCapReached reports whether the user has had a check-in today,
+// counting check-ins other than reminderID that a runner has claimed and is
+// sending.
+func (s *ReminderService) cardCheckinCapReached(ctx context.Context, userID, reminderID uuid.UUID, now time.Time) (bool, error) {
 	sentOn := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	var count int
 	if err := s.db.QueryRow(ctx,
-		"SELECT COUNT(*) FROM reminder_email_log WHERE user_id = $1 AND source_type = 'card_checkin' AND status = 'sent' AND sent_on = $2",
+		`SELECT (SELECT COUNT(*) FROM reminder_email_log WHERE user_id = $1 AND source_type = 'card_checkin' AND status = 'sent' AND sent_on = $2)
+		      + (SELECT COUNT(*) FROM card_checkin_reminders WHERE user_id = $1 AND id <> $3 AND claimed_at > $4)`,
 		userID,
 		sentOn,
+		reminderID,
+		s.now().Add(-reminderClaimLease),
 	).Scan(&count); err != nil {
 		return false, fmt.Errorf("check card checkin cap: %w", err)
 	}
 	return count >= 1, nil
 }
 
-func (s *ReminderService) goalReminderCapReached(ctx context.Context, userID uuid.UUID, now time.Time, cap int) (bool, error) {
+// goalReminderCapReached reports whether the user has had cap goal reminders
+// or digests today, counted like cardCheckinCapReached.
+func (s *ReminderService) goalReminderCapReached(ctx context.Context, userID, reminderID uuid.UUID, now time.Time, cap int) (bool, error) {
 	if cap <= 0 {
 		cap = 3
 	}
 	sentOn := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	var count int
 	if err := s.db.QueryRow(ctx,
-		"SELECT COUNT(*) FROM reminder_email_log WHERE user_id = $1 AND source_type = 'goal_reminder' AND status = 'sent' AND sent_on = $2",
+		`SELECT (SELECT COUNT(*) FROM reminder_email_log WHERE user_id = $1 AND source_type IN ('goal_reminder', 'digest') AND status = 'sent' AND sent_on = $2)
+		      + (SELECT COUNT(*) FROM goal_reminders WHERE user_id = $1 AND id <> $3 AND claimed_at > $4)`,
 		userID,
 		sentOn,
+		reminderID,
+		s.now().Add(-reminderClaimLease),
 	).Scan(&count); err != nil {
 		return false, fmt.Errorf("check goal reminder cap: %w", err)
 	}
@@ -1114,20 +2141,47 @@ func (s *ReminderService) ensureSettingsRow(ctx context.Context, userID uuid.UUI
 func (s *ReminderService) loadSettings(ctx context.Context, userID uuid.UUID) (*models.ReminderSettings, error) {
 	settings := &models.ReminderSettings{}
 	if err := s.db.QueryRow(ctx,
-		"SELECT user_id, email_enabled, daily_email_cap, created_at, updated_at FROM reminder_settings WHERE user_id = $1",
+		`SELECT rs.user_id, rs.email_enabled, rs.daily_email_cap, rs.image_token_mode, rs.image_theme, rs.timezone,
+		        rs.digest_enabled, rs.digest_time, rs.digest_next_at, rs.created_at, rs.updated_at,
+		        (SELECT ns.email_paused_until FROM notification_settings ns
+		          WHERE ns.user_id = rs.user_id AND ns.email_paused_until > NOW())
+		   FROM reminder_settings rs WHERE rs.user_id = $1`,
 		userID,
 	).Scan(
 		&settings.UserID,
 		&settings.EmailEnabled,
 		&settings.DailyEmailCap,
+		&settings.ImageTokenMode,
+		&settings.ImageTheme,
+		&settings.Timezone,
+		&settings.DigestEnabled,
+		&settings.DigestTime,
+		&settings.DigestNextAt,
 		&settings.CreatedAt,
 		&settings.UpdatedAt,
+		&settings.EmailPausedUntil,
 	); err != nil {
 		return nil, fmt.Errorf("load reminder settings: %w", err)
 	}
 	return settings, nil
 }
 
+// loadLocation returns the zone the user's reminder times are read in.
+func (s *ReminderService) loadLocation(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+	var timezone string
+	err := s.db.QueryRow(ctx,
+		"SELECT timezone FROM reminder_settings WHERE user_id = $1",
+		userID,
+	).Scan(&timezone)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.UTC, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load reminder timezone: %w", err)
+	}
+	return reminderLocation(timezone), nil
+}
+
 func (s *ReminderService) isEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
 	var verified bool
 	if err := s.db.QueryRow(ctx, "SELECT email_verified FROM users WHERE id = $1 AND deleted_at IS NULL", userID).Scan(&verified); err != nil {
@@ -1136,19 +2190,25 @@ func (s *ReminderService) isEmailVerified(ctx context.Context, userID uuid.UUID)
 	return verified, nil
 }
 
+// ensureCardEligible checks that userID may schedule reminders for the card:
+// it must be theirs, finalized and not archived. Someone else's card reads as
+// not found.
 func (s *ReminderService) ensureCardEligible(ctx context.Context, userID, cardID uuid.UUID) error {
+	var ownerID uuid.UUID
 	var finalized bool
 	var archived bool
 	if err := s.db.QueryRow(ctx,
-		"SELECT is_finalized, is_archived FROM bingo_cards WHERE id = $1 AND user_id = $2",
+		"SELECT user_id, is_finalized, is_archived FROM bingo_cards WHERE id = $1",
 		cardID,
-		userID,
-	).Scan(&finalized, &archived); err != nil {
+	).Scan(&ownerID, &finalized, &archived); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrCardNotFound
 		}
 		return fmt.Errorf("load card state: %w", err)
 	}
+	if !authz.CanEditCard(authz.For(userID, ownerID)) {
+		return ErrCardNotFound
+	}
 	if !finalized || archived {
 		return ErrCardNotEligible
 	}
@@ -1180,7 +2240,7 @@ func (s *ReminderService) loadCardWithItems(ctx context.Context, userID, cardID
 	card := &models.BingoCard{}
 	if err := s.db.QueryRow(ctx, `
 		SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		       is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		       is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof
 		  FROM bingo_cards WHERE id = $1 AND user_id = $2`,
 		cardID,
 		userID,
@@ -1200,6 +2260,7 @@ func (s *ReminderService) loadCardWithItems(ctx context.Context, userID, cardID
 		&card.IsArchived,
 		&card.CreatedAt,
 		&card.UpdatedAt,
+		&card.FreeSpaceText, &card.StartDate, &card.EndDate, &card.RequireProof,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil, ErrCardNotFound
@@ -1219,7 +2280,7 @@ func (s *ReminderService) loadCardWithItemsTx(ctx context.Context, tx Tx, userID
 	card := &models.BingoCard{}
 	if err := tx.QueryRow(ctx, `
 		SELECT id, user_id, year, category, title, grid_size, header_text, has_free_space, free_space_position,
-		       is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at
+		       is_active, is_finalized, visible_to_friends, is_archived, created_at, updated_at, free_space_text, start_date, end_date, require_proof
 		  FROM bingo_cards WHERE id = $1 AND user_id = $2`,
 		cardID,
 		userID,
@@ -1239,6 +2300,7 @@ func (s *ReminderService) loadCardWithItemsTx(ctx context.Context, tx Tx, userID
 		&card.IsArchived,
 		&card.CreatedAt,
 		&card.UpdatedAt,
+		&card.FreeSpaceText, &card.StartDate, &card.EndDate, &card.RequireProof,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil, ErrCardNotFound
@@ -1256,7 +2318,7 @@ func (s *ReminderService) loadCardWithItemsTx(ctx context.Context, tx Tx, userID
 
 func (s *ReminderService) loadItemsForCard(ctx context.Context, cardID uuid.UUID) ([]models.BingoItem, error) {
 	rows, err := s.db.Query(ctx,
-		"SELECT id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at FROM bingo_items WHERE card_id = $1 ORDER BY position",
+		"SELECT id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at, is_private, difficulty FROM bingo_items WHERE card_id = $1 ORDER BY position",
 		cardID,
 	)
 	if err != nil {
@@ -1277,6 +2339,8 @@ func (s *ReminderService) loadItemsForCard(ctx context.Context, cardID uuid.UUID
 			&item.Notes,
 			&item.ProofURL,
 			&item.CreatedAt,
+			&item.IsPrivate,
+			&item.Difficulty,
 		); err != nil {
 			return nil, fmt.Errorf("scan card item: %w", err)
 		}
@@ -1290,7 +2354,7 @@ func (s *ReminderService) loadItemsForCard(ctx context.Context, cardID uuid.UUID
 
 func (s *ReminderService) loadItemsForCardTx(ctx context.Context, tx Tx, cardID uuid.UUID) ([]models.BingoItem, error) {
 	rows, err := tx.Query(ctx,
-		"SELECT id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at FROM bingo_items WHERE card_id = $1 ORDER BY position",
+		"SELECT id, card_id, position, content, is_completed, completed_at, notes, proof_url, created_at, is_private, difficulty FROM bingo_items WHERE card_id = $1 ORDER BY position",
 		cardID,
 	)
 	if err != nil {
@@ -1311,6 +2375,8 @@ func (s *ReminderService) loadItemsForCardTx(ctx context.Context, tx Tx, cardID
 			&item.Notes,
 			&item.ProofURL,
 			&item.CreatedAt,
+			&item.IsPrivate,
+			&item.Difficulty,
 		); err != nil {
 			return nil, fmt.Errorf("scan card item: %w", err)
 		}
@@ -1389,7 +2455,19 @@ func (s *ReminderService) lockReminderSettings(ctx context.Context, tx Tx, userI
 	return nil
 }
 
-func (s *ReminderService) createImageToken(ctx context.Context, userID, cardID uuid.UUID, showCompletions bool) (string, error) {
+// createImageToken returns the token for a card image link. In reuse mode
+// an unexpired token is reused and its expiry pushed out again; in per-email
+// mode every call mints a fresh token with a shorter TTL and a view cap.
+func (s *ReminderService) createImageToken(ctx context.Context, userID, cardID uuid.UUID, showCompletions bool, mode string) (string, error) {
+	if mode == models.ReminderImageTokenPerEmail {
+		var maxAccess *int
+		if s.perEmailTokenMaxAccess > 0 {
+			limit := s.perEmailTokenMaxAccess
+			maxAccess = &limit
+		}
+		return s.insertImageToken(ctx, userID, cardID, showCompletions, s.now().Add(s.perEmailTokenTTL), maxAccess)
+	}
+
 	var existing string
 	if err := s.db.QueryRow(ctx, `
 		SELECT token
@@ -1397,6 +2475,7 @@ func (s *ReminderService) createImageToken(ctx context.Context, userID, cardID u
 		 WHERE user_id = $1
 		   AND card_id = $2
 		   AND show_completions = $3
+		   AND max_access_count IS NULL
 		   AND expires_at > NOW()
 		 ORDER BY expires_at DESC
 		 LIMIT 1`,
@@ -1406,7 +2485,7 @@ func (s *ReminderService) createImageToken(ctx context.Context, userID, cardID u
 	).Scan(&existing); err == nil && existing != "" {
 		if _, err := s.db.Exec(ctx,
 			"UPDATE reminder_image_tokens SET expires_at = $1 WHERE token = $2",
-			s.now().Add(14*24*time.Hour),
+			s.now().Add(reuseImageTokenTTL),
 			existing,
 		); err != nil {
 			return "", fmt.Errorf("refresh reminder image token: %w", err)
@@ -1416,18 +2495,23 @@ func (s *ReminderService) createImageToken(ctx context.Context, userID, cardID u
 		return "", fmt.Errorf("load reminder image token: %w", err)
 	}
 
+	return s.insertImageToken(ctx, userID, cardID, showCompletions, s.now().Add(reuseImageTokenTTL), nil)
+}
+
+func (s *ReminderService) insertImageToken(ctx context.Context, userID, cardID uuid.UUID, showCompletions bool, expiresAt time.Time, maxAccess *int) (string, error) {
 	token, err := randomToken(24)
 	if err != nil {
 		return "", err
 	}
 	_, err = s.db.Exec(ctx, `
-		INSERT INTO reminder_image_tokens (token, user_id, card_id, show_completions, expires_at)
-		VALUES ($1, $2, $3, $4, $5)`,
+		INSERT INTO reminder_image_tokens (token, user_id, card_id, show_completions, expires_at, max_access_count)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
 		token,
 		userID,
 		cardID,
 		showCompletions,
-		s.now().Add(14*24*time.Hour),
+		expiresAt,
+		maxAccess,
 	)
 	if err != nil {
 		return "", fmt.Errorf("create reminder image token: %w", err)
@@ -1438,7 +2522,8 @@ func (s *ReminderService) createImageToken(ctx context.Context, userID, cardID u
 func (s *ReminderService) loadImageToken(ctx context.Context, token string) (*models.ReminderImageToken, error) {
 	imageToken := &models.ReminderImageToken{}
 	if err := s.db.QueryRow(ctx, `
-		SELECT token, user_id, card_id, show_completions, expires_at, created_at, last_accessed_at, access_count
+		SELECT token, user_id, card_id, show_completions, expires_at, created_at, last_accessed_at, access_count,
+		       max_access_count
 		  FROM reminder_image_tokens WHERE token = $1`,
 		token,
 	).Scan(
@@ -1450,6 +2535,7 @@ func (s *ReminderService) loadImageToken(ctx context.Context, token string) (*mo
 		&imageToken.CreatedAt,
 		&imageToken.LastAccessedAt,
 		&imageToken.AccessCount,
+		&imageToken.MaxAccessCount,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrReminderNotFound
@@ -1509,7 +2595,10 @@ func parseMonthlySchedule(input models.CardCheckinSchedulePayload) (monthlySched
 	if _, err := time.Parse("15:04", input.Time); err != nil {
 		return monthlySchedule{}, ErrInvalidSchedule
 	}
-	return monthlySchedule{DayOfMonth: day, Time: input.Time}, nil
+	if input.JitterWindowMinutes < 0 || input.JitterWindowMinutes > maxReminderJitterMinutes {
+		return monthlySchedule{}, ErrInvalidSchedule
+	}
+	return monthlySchedule{DayOfMonth: day, Time: input.Time, JitterWindowMinutes: input.JitterWindowMinutes}, nil
 }
 
 func nextMonthlySend(after time.Time, schedule monthlySchedule) (time.Time, error) {
@@ -1527,264 +2616,111 @@ func nextMonthlySend(after time.Time, schedule monthlySchedule) (time.Time, erro
 
 	year, month, _ := after.Date()
 	loc := after.Location()
-	candidate := time.Date(year, month, day, parsed.Hour(), parsed.Minute(), 0, 0, loc)
+	offset := schedule.jitterOffset()
+	candidate := time.Date(year, month, day, parsed.Hour(), parsed.Minute(), 0, 0, loc).Add(offset)
 	if !candidate.After(after) {
 		nextMonth := time.Date(year, month, 1, parsed.Hour(), parsed.Minute(), 0, 0, loc).AddDate(0, 1, 0)
-		candidate = time.Date(nextMonth.Year(), nextMonth.Month(), day, parsed.Hour(), parsed.Minute(), 0, 0, loc)
+		candidate = time.Date(nextMonth.Year(), nextMonth.Month(), day, parsed.Hour(), parsed.Minute(), 0, 0, loc).Add(offset)
 	}
 	return candidate, nil
 }
 
-func parseOneTimeSchedule(input models.GoalReminderScheduleInput, now time.Time) (time.Time, error) {
+// parseOneTimeSchedule accepts an RFC 3339 time, or a wall-clock
+// "2006-01-02T15:04" read in loc.
+func parseOneTimeSchedule(input models.GoalReminderScheduleInput, now time.Time, loc *time.Location) (time.Time, error) {
 	if strings.TrimSpace(input.SendAt) == "" {
 		return time.Time{}, ErrInvalidSchedule
 	}
 	if parsed, err := time.Parse(time.RFC3339, input.SendAt); err == nil {
 		if !parsed.After(now) {
-			return time.Time{}, ErrInvalidSchedule
+			return time.Time{}, errSendAtInPast
 		}
-		return parsed, nil
+		return parsed.UTC(), nil
 	}
-	localParsed, err := time.ParseInLocation("2006-01-02T15:04", input.SendAt, time.Local)
+	localParsed, err := time.ParseInLocation("2006-01-02T15:04", input.SendAt, loc)
 	if err != nil {
 		return time.Time{}, ErrInvalidSchedule
 	}
 	if !localParsed.After(now) {
-		return time.Time{}, ErrInvalidSchedule
-	}
-	return localParsed, nil
-}
-
-func buildReminderStats(card *models.BingoCard, items []models.BingoItem) reminderStats {
-	capacity := card.Capacity()
-	completed := 0
-	for _, item := range items {
-		if item.IsCompleted {
-			completed++
-		}
+		return time.Time{}, errSendAtInPast
 	}
-	bingos := countReminderBingos(items, card.GridSize, card.FreeSpacePos)
-	return reminderStats{Completed: completed, Total: capacity, Bingos: bingos}
+	return localParsed.UTC(), nil
 }
 
-func countReminderBingos(items []models.BingoItem, gridSize int, freePos *int) int {
-	if !models.IsValidGridSize(gridSize) {
-		gridSize = models.MaxGridSize
-	}
-	total := gridSize * gridSize
-	grid := make([]bool, total)
-
-	if freePos != nil && *freePos >= 0 && *freePos < total {
-		grid[*freePos] = true
-	}
-
-	for _, item := range items {
-		if item.IsCompleted && item.Position >= 0 && item.Position < total {
-			grid[item.Position] = true
-		}
-	}
-
-	bingos := 0
-	for row := 0; row < gridSize; row++ {
-		complete := true
-		for col := 0; col < gridSize; col++ {
-			if !grid[row*gridSize+col] {
-				complete = false
-				break
-			}
-		}
-		if complete {
-			bingos++
-		}
-	}
-	for col := 0; col < gridSize; col++ {
-		complete := true
-		for row := 0; row < gridSize; row++ {
-			if !grid[row*gridSize+col] {
-				complete = false
-				break
-			}
-		}
-		if complete {
-			bingos++
-		}
-	}
-
-	complete := true
-	for i := 0; i < gridSize; i++ {
-		if !grid[i*gridSize+i] {
-			complete = false
-			break
-		}
-	}
-	if complete {
-		bingos++
-	}
-
-	complete = true
-	for i := 0; i < gridSize; i++ {
-		if !grid[i*gridSize+(gridSize-1-i)] {
-			complete = false
-			break
-		}
+func parseRecurringSchedule(input models.GoalReminderScheduleInput) (recurringSchedule, error) {
+	if input.EveryDays < 1 || input.EveryDays > maxRecurringEveryDays {
+		return recurringSchedule{}, ErrInvalidSchedule
 	}
-	if complete {
-		bingos++
+	if _, err := time.Parse("15:04", input.Time); err != nil {
+		return recurringSchedule{}, ErrInvalidSchedule
 	}
-
-	return bingos
+	return recurringSchedule{EveryDays: input.EveryDays, Time: input.Time}, nil
 }
 
-func pickReminderRecommendations(items []models.BingoItem, gridSize int, freePos *int, limit int) []models.BingoItem {
-	if limit <= 0 {
-		return []models.BingoItem{}
-	}
-	if !models.IsValidGridSize(gridSize) {
-		gridSize = models.MaxGridSize
-	}
-	itemByPos := make(map[int]models.BingoItem, len(items))
-	for _, item := range items {
-		itemByPos[item.Position] = item
-	}
-
-	free := -1
-	if freePos != nil {
-		free = *freePos
-	}
-
-	lines := buildLines(gridSize)
-	minMissing := gridSize + 1
-	lineMissing := make([][]int, 0, len(lines))
-
-	for _, line := range lines {
-		missing := make([]int, 0, len(line))
-		for _, pos := range line {
-			if pos == free {
-				continue
-			}
-			item, ok := itemByPos[pos]
-			if !ok {
-				missing = append(missing, pos)
-				continue
-			}
-			if !item.IsCompleted {
-				missing = append(missing, pos)
-			}
-		}
-		if len(missing) == 0 {
-			lineMissing = append(lineMissing, nil)
-			continue
-		}
-		if len(missing) < minMissing {
-			minMissing = len(missing)
-		}
-		lineMissing = append(lineMissing, missing)
+// firstRecurringSend is the first time-of-day occurrence after now, today or
+// tomorrow, in now's zone.
+func firstRecurringSend(now time.Time, schedule recurringSchedule) (time.Time, error) {
+	parsed, err := time.Parse("15:04", schedule.Time)
+	if err != nil {
+		return time.Time{}, ErrInvalidSchedule
 	}
-
-	scores := map[int]int{}
-	if minMissing <= gridSize {
-		for _, missing := range lineMissing {
-			if len(missing) == 0 || len(missing) != minMissing {
-				continue
-			}
-			for _, pos := range missing {
-				scores[pos]++
-			}
-		}
+	candidate := time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), 0, 0, now.Location())
+	if !candidate.After(now) {
+		candidate = candidate.AddDate(0, 0, 1)
 	}
+	return candidate, nil
+}
 
-	type scoredItem struct {
-		Item  models.BingoItem
-		Score int
-	}
-	var scored []scoredItem
-	for pos, score := range scores {
-		item, ok := itemByPos[pos]
-		if !ok {
-			continue
-		}
-		if item.IsCompleted || pos == free {
-			continue
-		}
-		scored = append(scored, scoredItem{Item: item, Score: score})
+// nextRecurringSend counts EveryDays from the day of the last send, so a
+// send delayed by the daily cap or a pause shifts the rhythm rather than
+// firing twice in a row.
+func nextRecurringSend(sentAt time.Time, schedule recurringSchedule) (time.Time, error) {
+	if schedule.EveryDays < 1 {
+		return time.Time{}, ErrInvalidSchedule
 	}
-
-	if len(scored) == 0 {
-		return fallbackRecommendations(items, free, limit)
+	parsed, err := time.Parse("15:04", schedule.Time)
+	if err != nil {
+		return time.Time{}, ErrInvalidSchedule
 	}
+	day := time.Date(sentAt.Year(), sentAt.Month(), sentAt.Day(), parsed.Hour(), parsed.Minute(), 0, 0, sentAt.Location())
+	return day.AddDate(0, 0, schedule.EveryDays), nil
+}
 
-	sort.SliceStable(scored, func(i, j int) bool {
-		if scored[i].Score != scored[j].Score {
-			return scored[i].Score > scored[j].Score
-		}
-		return scored[i].Item.Position < scored[j].Item.Position
-	})
-
-	if len(scored) > limit {
-		scored = scored[:limit]
+// parseReminderTimezone validates a user-supplied IANA zone name. "Local" is
+// refused because it means the server's zone, which is what users are
+// opting out of.
+func parseReminderTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" || len(name) > 64 {
+		return nil, ErrInvalidTimezone
 	}
-
-	result := make([]models.BingoItem, 0, len(scored))
-	for _, entry := range scored {
-		result = append(result, entry.Item)
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
 	}
-	return result
+	return loc, nil
 }
 
-func fallbackRecommendations(items []models.BingoItem, freePos int, limit int) []models.BingoItem {
-	candidates := make([]models.BingoItem, 0, len(items))
-	for _, item := range items {
-		if item.IsCompleted || item.Position == freePos {
-			continue
-		}
-		candidates = append(candidates, item)
-	}
-
-	sort.SliceStable(candidates, func(i, j int) bool {
-		if !candidates[i].CreatedAt.IsZero() && !candidates[j].CreatedAt.IsZero() {
-			if !candidates[i].CreatedAt.Equal(candidates[j].CreatedAt) {
-				return candidates[i].CreatedAt.Before(candidates[j].CreatedAt)
-			}
-		}
-		return candidates[i].Position < candidates[j].Position
-	})
-
-	if len(candidates) > limit {
-		candidates = candidates[:limit]
+// reminderLocation resolves a stored zone name, falling back to UTC for
+// empty or no-longer-known names so one bad row can't stall the queue.
+func reminderLocation(name string) *time.Location {
+	loc, err := parseReminderTimezone(name)
+	if err != nil {
+		return time.UTC
 	}
-	return candidates
+	return loc
 }
 
-func buildLines(gridSize int) [][]int {
-	lines := make([][]int, 0, gridSize*2+2)
-	for row := 0; row < gridSize; row++ {
-		line := make([]int, 0, gridSize)
-		for col := 0; col < gridSize; col++ {
-			line = append(line, row*gridSize+col)
-		}
-		lines = append(lines, line)
-	}
-	for col := 0; col < gridSize; col++ {
-		line := make([]int, 0, gridSize)
-		for row := 0; row < gridSize; row++ {
-			line = append(line, row*gridSize+col)
+func buildReminderStats(card *models.BingoCard, items []models.BingoItem) reminderStats {
+	capacity := card.Capacity()
+	completed := 0
+	for _, item := range items {
+		if item.IsCompleted {
+			completed++
 		}
-		lines = append(lines, line)
-	}
-
-	line := make([]int, 0, gridSize)
-	for i := 0; i < gridSize; i++ {
-		line = append(line, i*gridSize+i)
 	}
-	lines = append(lines, line)
-
-	line = make([]int, 0, gridSize)
-	for i := 0; i < gridSize; i++ {
-		line = append(line, i*gridSize+(gridSize-1-i))
-	}
-	lines = append(lines, line)
-
-	return lines
+	bingos := bingo.CountBingos(items, card.GridSize, card.FreeSpacePos)
+	return reminderStats{Completed: completed, Total: capacity, Bingos: bingos}
 }
 
 func derefString(value *string) string {
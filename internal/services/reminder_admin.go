@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var (
+	ErrReminderCapReached = errors.New("reminder daily cap reached")
+	ErrReminderSendFailed = errors.New("reminder email send failed")
+	ErrRunAsOfInPast      = errors.New("reminder run as_of is in the past")
+)
+
+// reminderReportLogLimit bounds the email log rows returned in a support report.
+const reminderReportLogLimit = 500
+
+// RunNow runs the reminder sender immediately instead of waiting for the
+// poll interval, as if the clock read asOf (now when nil). asOf may not be in
+// the past: schedules are advanced from it, so an earlier clock could leave
+// them behind the real one and resend on the next tick.
+func (s *ReminderService) RunNow(ctx context.Context, asOf *time.Time, limit int) (*models.ReminderRunResult, error) {
+	now := s.now()
+	if asOf != nil {
+		if asOf.Before(now) {
+			return nil, ErrRunAsOfInPast
+		}
+		now = *asOf
+	}
+	result, err := s.runDue(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	result.AsOf = now
+	return &result, nil
+}
+
+// ResendReminder immediately re-sends a card check-in or goal reminder on behalf
+// of support. The reminder's schedule is left untouched and the send is logged
+// with a "manual" status, which never counts toward the user's daily cap. The
+// cap is still enforced unless bypassCap is set.
+func (s *ReminderService) ResendReminder(ctx context.Context, reminderID uuid.UUID, bypassCap bool) (*models.ReminderResendResult, error) {
+	now := s.now()
+
+	var (
+		sourceType string
+		checkin    checkinJob
+		goal       goalReminderJob
+	)
+	err := s.db.QueryRow(ctx,
+		"SELECT id, user_id, card_id, frequency, schedule, include_image, include_recommendations, include_memories, recent_recommendations, progress_snapshot FROM card_checkin_reminders WHERE id = $1",
+		reminderID,
+	).Scan(
+		&checkin.ID,
+		&checkin.UserID,
+		&checkin.CardID,
+		&checkin.Frequency,
+		&checkin.Schedule,
+		&checkin.IncludeImage,
+		&checkin.IncludeRecommendations,
+		&checkin.IncludeMemories,
+		&checkin.RecentRecommendations,
+		&checkin.ProgressSnapshot,
+	)
+	switch {
+	case err == nil:
+		sourceType = "card_checkin"
+	case errors.Is(err, pgx.ErrNoRows):
+		err = s.db.QueryRow(ctx,
+			"SELECT id, user_id, card_id, item_id, kind, schedule FROM goal_reminders WHERE id = $1",
+			reminderID,
+		).Scan(&goal.ID, &goal.UserID, &goal.CardID, &goal.ItemID, &goal.Kind, &goal.Schedule)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReminderNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("load goal reminder: %w", err)
+		}
+		sourceType = "goal_reminder"
+	default:
+		return nil, fmt.Errorf("load card checkin: %w", err)
+	}
+
+	userID := checkin.UserID
+	if sourceType == "goal_reminder" {
+		userID = goal.UserID
+	}
+
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.EmailEnabled {
+		return nil, ErrRemindersDisabled
+	}
+	checkin.ImageTokenMode = settings.ImageTokenMode
+	verified, err := s.isEmailVerified(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !verified {
+		return nil, ErrEmailNotVerified
+	}
+
+	var userEmail, subject, html, text string
+	var link reminderLink
+	switch sourceType {
+	case "card_checkin":
+		card, items, err := s.loadCardWithItems(ctx, userID, checkin.CardID)
+		if err != nil {
+			return nil, err
+		}
+		if !card.IsFinalized || card.IsArchived {
+			return nil, ErrCardNotEligible
+		}
+		if !bypassCap {
+			capReached, err := s.cardCheckinCapReached(ctx, userID, uuid.Nil, now)
+			if err != nil {
+				return nil, err
+			}
+			if capReached {
+				return nil, ErrReminderCapReached
+			}
+		}
+		if userEmail, err = s.loadUserEmail(ctx, userID); err != nil {
+			return nil, err
+		}
+		link = s.createReminderLink(ctx, userID, sourceType, reminderID, checkinLinkPath(checkin.CardID))
+		if subject, html, text, err = s.composeCheckinEmail(ctx, checkin, card, items, s.checkinRecommendations(checkin, card, items), link.url); err != nil {
+			s.discardReminderLink(ctx, link)
+			return nil, err
+		}
+	case "goal_reminder":
+		ctxData, err := s.loadGoalReminderContext(ctx, userID, goal.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if !ctxData.CardFinalized || ctxData.CardArchived {
+			return nil, ErrCardNotEligible
+		}
+		if ctxData.ItemCompleted {
+			return nil, ErrGoalCompleted
+		}
+		if !bypassCap {
+			capReached, err := s.goalReminderCapReached(ctx, userID, uuid.Nil, now, ctxData.DailyCap)
+			if err != nil {
+				return nil, err
+			}
+			if capReached {
+				return nil, ErrReminderCapReached
+			}
+		}
+		userEmail = ctxData.UserEmail
+		link = s.createReminderLink(ctx, userID, sourceType, reminderID, goalLinkPath(ctxData.CardID, goal.ItemID))
+		if subject, html, text, err = s.composeGoalReminderEmail(ctx, goal, ctxData, link.url); err != nil {
+			s.discardReminderLink(ctx, link)
+			return nil, err
+		}
+	}
+
+	if s.emailService == nil {
+		s.discardReminderLink(ctx, link)
+		return nil, fmt.Errorf("email service not configured")
+	}
+	if err := s.emailService.SendNotificationEmail(ctx, userEmail, subject, html, text); err != nil {
+		s.discardReminderLink(ctx, link)
+		return nil, fmt.Errorf("%w: %v", ErrReminderSendFailed, err)
+	}
+
+	if err := s.logReminderEmail(ctx, nil, userID, sourceType, reminderID, reminderEmailManual, now); err != nil {
+		return nil, err
+	}
+
+	return &models.ReminderResendResult{
+		ReminderID: reminderID,
+		UserID:     userID,
+		SourceType: sourceType,
+		Status:     string(reminderEmailManual),
+		SentAt:     now,
+	}, nil
+}
+
+// GetUserReminderReport returns a user's reminder settings, every check-in and
+// goal reminder row (including disabled ones), the last 30 days of the
+// reminder email log and the click-through of their card links.
+func (s *ReminderService) GetUserReminderReport(ctx context.Context, userID uuid.UUID) (*models.UserReminderReport, error) {
+	var exists bool
+	if err := s.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", userID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check user exists: %w", err)
+	}
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.UserReminderReport{
+		UserID:   userID,
+		Settings: settings,
+		Checkins: []models.CardCheckinReminder{},
+		Goals:    []models.GoalReminder{},
+	}
+
+	checkinRows, err := s.db.Query(ctx, `
+		SELECT id, user_id, card_id, enabled, frequency, schedule, include_image, include_recommendations,
+		       include_memories, next_send_at, last_sent_at, created_at, updated_at
+		  FROM card_checkin_reminders
+		 WHERE user_id = $1
+		 ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list card checkins: %w", err)
+	}
+	defer checkinRows.Close()
+	for checkinRows.Next() {
+		var checkin models.CardCheckinReminder
+		if err := checkinRows.Scan(
+			&checkin.ID,
+			&checkin.UserID,
+			&checkin.CardID,
+			&checkin.Enabled,
+			&checkin.Frequency,
+			&checkin.Schedule,
+			&checkin.IncludeImage,
+			&checkin.IncludeRecommendations,
+			&checkin.IncludeMemories,
+			&checkin.NextSendAt,
+			&checkin.LastSentAt,
+			&checkin.CreatedAt,
+			&checkin.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan card checkin: %w", err)
+		}
+		report.Checkins = append(report.Checkins, checkin)
+	}
+	if err := checkinRows.Err(); err != nil {
+		return nil, fmt.Errorf("list card checkins: %w", err)
+	}
+
+	goalRows, err := s.db.Query(ctx, `
+		SELECT id, user_id, card_id, item_id, enabled, kind, schedule, next_send_at, last_sent_at,
+		       created_at, updated_at
+		  FROM goal_reminders
+		 WHERE user_id = $1
+		 ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list goal reminders: %w", err)
+	}
+	defer goalRows.Close()
+	for goalRows.Next() {
+		var goal models.GoalReminder
+		if err := goalRows.Scan(
+			&goal.ID,
+			&goal.UserID,
+			&goal.CardID,
+			&goal.ItemID,
+			&goal.Enabled,
+			&goal.Kind,
+			&goal.Schedule,
+			&goal.NextSendAt,
+			&goal.LastSentAt,
+			&goal.CreatedAt,
+			&goal.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan goal reminder: %w", err)
+		}
+		report.Goals = append(report.Goals, goal)
+	}
+	if err := goalRows.Err(); err != nil {
+		return nil, fmt.Errorf("list goal reminders: %w", err)
+	}
+
+	if report.EmailLog, err = s.recentEmailLog(ctx, userID); err != nil {
+		return nil, err
+	}
+	if report.ClickThrough, err = s.reminderClickThrough(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// recentEmailLog returns up to reminderReportLogLimit reminder sends from the
+// last 30 days, newest first.
+func (s *ReminderService) recentEmailLog(ctx context.Context, userID uuid.UUID) ([]models.ReminderEmailLogEntry, error) {
+	logRows, err := s.db.Query(ctx, `
+		SELECT id, source_type, source_id, status, sent_at
+		  FROM reminder_email_log
+		 WHERE user_id = $1
+		   AND sent_at >= $2
+		 ORDER BY sent_at DESC
+		 LIMIT $3`,
+		userID,
+		s.now().AddDate(0, 0, -30),
+		reminderReportLogLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list reminder email log: %w", err)
+	}
+	defer logRows.Close()
+	entries := []models.ReminderEmailLogEntry{}
+	for logRows.Next() {
+		var entry models.ReminderEmailLogEntry
+		if err := logRows.Scan(&entry.ID, &entry.SourceType, &entry.SourceID, &entry.Status, &entry.SentAt); err != nil {
+			return nil, fmt.Errorf("scan reminder email log: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := logRows.Err(); err != nil {
+		return nil, fmt.Errorf("list reminder email log: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func goalResendDB(t *testing.T, reminderID, userID, cardID, itemID uuid.UUID, sentToday int, logged *[]any) *fakeDB {
+	t.Helper()
+	now := time.Now()
+	return &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "INSERT INTO reminder_email_log") {
+				if strings.Contains(sql, "ON CONFLICT") {
+					t.Fatalf("manual resend must not upsert over the scheduled log row: %q", sql)
+				}
+				*logged = args
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM reminder_email_log"):
+				return rowFromValues(sentToday)
+			case strings.Contains(sql, "FROM card_checkin_reminders"):
+				return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+			case strings.Contains(sql, "FROM goal_reminders"):
+				return rowFromValues(reminderID, userID, cardID, itemID, "one_time", []byte(`{}`))
+			case strings.Contains(sql, "FROM reminder_settings"):
+				return rowFromValues(userID, true, 3, "reuse", "light", "UTC", false, "08:00", (*time.Time)(nil), now, now, nil)
+			case strings.Contains(sql, "SELECT email_verified"):
+				return rowFromValues(true)
+			case strings.Contains(sql, "FROM bingo_items"):
+				return rowFromValues(cardID, nil, 2025, true, false, "Run a 10k", false, "user@test.com", 3)
+			case strings.Contains(sql, "FROM users u"):
+				return rowFromValues(false, "reuse")
+			}
+			t.Fatalf("unexpected query sql: %q", sql)
+			return nil
+		},
+	}
+}
+
+func TestReminderService_ResendReminder_GoalLogsManual(t *testing.T) {
+	reminderID, userID, cardID, itemID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	var logged []any
+	db := goalResendDB(t, reminderID, userID, cardID, itemID, 0, &logged)
+
+	var sentTo, sentText string
+	svc := NewReminderService(db, stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			sentTo, sentText = toEmail, text
+			return nil
+		},
+	}, "http://example.com")
+
+	result, err := svc.ResendReminder(context.Background(), reminderID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sentTo != "user@test.com" {
+		t.Fatalf("expected email to user@test.com, got %q", sentTo)
+	}
+	if !strings.Contains(sentText, "http://example.com/r/go/") {
+		t.Fatalf("expected tracked goal link, got %q", sentText)
+	}
+	if result.SourceType != "goal_reminder" || result.Status != "manual" || result.UserID != userID {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	if len(logged) < 4 || logged[3] != reminderEmailManual {
+		t.Fatalf("expected manual email log entry, got %#v", logged)
+	}
+}
+
+func TestReminderService_ResendReminder_RespectsCapUnlessBypassed(t *testing.T) {
+	reminderID, userID, cardID, itemID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	var logged []any
+	db := goalResendDB(t, reminderID, userID, cardID, itemID, 3, &logged)
+
+	sends := 0
+	svc := NewReminderService(db, stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			sends++
+			return nil
+		},
+	}, "http://example.com")
+
+	if _, err := svc.ResendReminder(context.Background(), reminderID, false); !errors.Is(err, ErrReminderCapReached) {
+		t.Fatalf("expected ErrReminderCapReached, got %v", err)
+	}
+	if sends != 0 {
+		t.Fatalf("expected no sends while capped, got %d", sends)
+	}
+
+	if _, err := svc.ResendReminder(context.Background(), reminderID, true); err != nil {
+		t.Fatalf("unexpected error with bypass: %v", err)
+	}
+	if sends != 1 {
+		t.Fatalf("expected 1 send with bypass, got %d", sends)
+	}
+}
+
+func TestReminderService_ResendReminder_SendFailure(t *testing.T) {
+	reminderID, userID, cardID, itemID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	var logged []any
+	db := goalResendDB(t, reminderID, userID, cardID, itemID, 0, &logged)
+	discarded := false
+	exec := db.ExecFunc
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if strings.Contains(sql, "DELETE FROM reminder_link_tokens") {
+			discarded = true
+		}
+		return exec(ctx, sql, args...)
+	}
+
+	svc := NewReminderService(db, stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			return errors.New("provider down")
+		},
+	}, "http://example.com")
+
+	if _, err := svc.ResendReminder(context.Background(), reminderID, true); !errors.Is(err, ErrReminderSendFailed) {
+		t.Fatalf("expected ErrReminderSendFailed, got %v", err)
+	}
+	if logged != nil {
+		t.Fatalf("expected failed manual send not to be logged, got %#v", logged)
+	}
+	if !discarded {
+		t.Fatal("expected the unsent email's link token to be discarded")
+	}
+}
+
+func TestReminderService_ResendReminder_NotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+
+	svc := NewReminderService(db, stubEmailService{}, "http://example.com")
+	if _, err := svc.ResendReminder(context.Background(), uuid.New(), false); !errors.Is(err, ErrReminderNotFound) {
+		t.Fatalf("expected ErrReminderNotFound, got %v", err)
+	}
+}
+
+func TestReminderService_ResendReminder_RemindersDisabled(t *testing.T) {
+	reminderID, userID := uuid.New(), uuid.New()
+	now := time.Now()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM card_checkin_reminders"):
+				return rowFromValues(reminderID, userID, uuid.New(), "monthly", []byte(`{"day_of_month":1,"time":"09:00"}`), false, false, true, []byte(`[]`), []byte(nil))
+			case strings.Contains(sql, "FROM reminder_settings"):
+				return rowFromValues(userID, false, 3, "reuse", "light", "UTC", false, "08:00", (*time.Time)(nil), now, now, nil)
+			}
+			t.Fatalf("unexpected query sql: %q", sql)
+			return nil
+		},
+	}
+
+	svc := NewReminderService(db, stubEmailService{}, "http://example.com")
+	if _, err := svc.ResendReminder(context.Background(), reminderID, true); !errors.Is(err, ErrRemindersDisabled) {
+		t.Fatalf("expected ErrRemindersDisabled, got %v", err)
+	}
+}
+
+func TestReminderService_GetUserReminderReport(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+
+	var logSince time.Time
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "SELECT EXISTS"):
+				return rowFromValues(true)
+			case strings.Contains(sql, "FROM reminder_settings"):
+				return rowFromValues(userID, true, 3, "reuse", "light", "UTC", false, "08:00", (*time.Time)(nil), now, now, nil)
+			case strings.Contains(sql, "FROM reminder_link_tokens"):
+				return rowFromValues(4, 1)
+			}
+			t.Fatalf("unexpected query sql: %q", sql)
+			return nil
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			switch {
+			case strings.Contains(sql, "FROM card_checkin_reminders"):
+				return &fakeRows{rows: [][]any{{uuid.New(), userID, uuid.New(), false, "monthly", []byte(`{}`), true, true, true, nil, &now, now, now}}}, nil
+			case strings.Contains(sql, "FROM goal_reminders"):
+				return &fakeRows{rows: [][]any{}}, nil
+			case strings.Contains(sql, "FROM reminder_email_log"):
+				logSince = args[1].(time.Time)
+				return &fakeRows{rows: [][]any{{uuid.New(), "card_checkin", uuid.New(), "sent", now}}}, nil
+			}
+			t.Fatalf("unexpected query sql: %q", sql)
+			return nil, nil
+		},
+	}
+
+	svc := NewReminderService(db, nil, "http://example.com")
+	report, err := svc.GetUserReminderReport(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Checkins) != 1 || report.Checkins[0].Enabled {
+		t.Fatalf("expected disabled checkin to be included, got %#v", report.Checkins)
+	}
+	if report.Goals == nil || len(report.Goals) != 0 {
+		t.Fatalf("expected empty goals slice, got %#v", report.Goals)
+	}
+	if len(report.EmailLog) != 1 {
+		t.Fatalf("expected 1 email log entry, got %d", len(report.EmailLog))
+	}
+	if since := now.Sub(logSince); since < 29*24*time.Hour || since > 31*24*time.Hour {
+		t.Fatalf("expected 30-day log window, got %s", since)
+	}
+	if ct := report.ClickThrough; ct == nil || ct.Tracked != 4 || ct.Clicked != 1 || ct.Rate != 0.25 {
+		t.Fatalf("unexpected click-through: %#v", report.ClickThrough)
+	}
+}
+
+func TestReminderService_GetUserReminderReport_UserNotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(false)
+		},
+	}
+
+	svc := NewReminderService(db, nil, "http://example.com")
+	if _, err := svc.GetUserReminderReport(context.Background(), uuid.New()); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestReminderService_RunNow_AsOfOverride(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "solo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE users SET email_verified = true WHERE id = $1", user.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cards := NewCardService(db)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: time.Now().Year(), GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	var items []*models.BingoItem
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		item, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content})
+		if err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+		items = append(items, item)
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+
+	sent := 0
+	reminders := NewReminderService(db, stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			sent++
+			return nil
+		},
+	}, "https://example.com")
+	enabled := true
+	if _, err := reminders.UpdateSettings(ctx, user.ID, models.ReminderSettingsPatch{EmailEnabled: &enabled}); err != nil {
+		t.Fatalf("unexpected error enabling reminders: %v", err)
+	}
+	var reminderIDs []uuid.UUID
+	for _, item := range items[:2] {
+		reminder, err := reminders.UpsertGoalReminder(ctx, user.ID, models.GoalReminderInput{
+			ItemID:   item.ID,
+			Kind:     models.GoalReminderKindRecurring,
+			Schedule: models.GoalReminderScheduleInput{EveryDays: 7, Time: "09:00"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error scheduling goal reminder: %v", err)
+		}
+		reminderIDs = append(reminderIDs, reminder.ID)
+	}
+	// Both reminders are due in two days; the second goal is already done.
+	scheduled := time.Now().Add(48 * time.Hour)
+	if _, err := db.Exec(ctx, "UPDATE goal_reminders SET next_send_at = $1", scheduled); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE bingo_items SET is_completed = true WHERE id = $1", items[1].ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	if _, err := reminders.RunNow(ctx, &past, 0); !errors.Is(err, ErrRunAsOfInPast) {
+		t.Fatalf("expected ErrRunAsOfInPast, got %v", err)
+	}
+
+	result, err := reminders.RunNow(ctx, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result != (models.ReminderRunResult{AsOf: result.AsOf}) || sent != 0 {
+		t.Fatalf("expected nothing due yet, got %+v (%d emails)", result, sent)
+	}
+
+	asOf := time.Now().Add(72 * time.Hour)
+	result, err = reminders.RunNow(ctx, &asOf, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Sent != 1 || result.Skipped != 1 || result.Deferred != 0 || sent != 1 || !result.AsOf.Equal(asOf) {
+		t.Fatalf("expected one send and one skip as of %v, got %+v (%d emails)", asOf, result, sent)
+	}
+
+	var nextSendAt time.Time
+	if err := db.QueryRow(ctx, "SELECT next_send_at FROM goal_reminders WHERE id = $1", reminderIDs[0]).Scan(&nextSendAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !nextSendAt.After(asOf) {
+		t.Fatalf("expected the next send after %v, got %v", asOf, nextSendAt)
+	}
+	var stillEnabled bool
+	if err := db.QueryRow(ctx, "SELECT enabled FROM goal_reminders WHERE id = $1", reminderIDs[1]).Scan(&stillEnabled); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stillEnabled {
+		t.Fatal("expected the completed goal's reminder to be disabled")
+	}
+}
+
+func TestReminderService_RunDue_RateLimitedSendIsRetriedUnlogged(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "solo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE users SET email_verified = true WHERE id = $1", user.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cards := NewCardService(db)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: time.Now().Year(), GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+
+	reminders := NewReminderService(db, stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			return ErrEmailRateLimited
+		},
+	}, "https://example.com")
+	enabled := true
+	if _, err := reminders.UpdateSettings(ctx, user.ID, models.ReminderSettingsPatch{EmailEnabled: &enabled}); err != nil {
+		t.Fatalf("unexpected error enabling reminders: %v", err)
+	}
+	noImage := false
+	if _, err := reminders.UpsertCardCheckin(ctx, user.ID, card.ID, models.CardCheckinScheduleInput{
+		Frequency:    "monthly",
+		Schedule:     models.CardCheckinSchedulePayload{DayOfMonth: 1, Time: "09:00"},
+		IncludeImage: &noImage,
+	}); err != nil {
+		t.Fatalf("unexpected error scheduling check-in: %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE card_checkin_reminders SET next_send_at = $1", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	result, err := reminders.RunNow(ctx, nil, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Sent != 0 || result.Deferred != 1 {
+		t.Fatalf("expected the refused send to be deferred, got %+v", result)
+	}
+
+	var logged int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM reminder_email_log").Scan(&logged); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if logged != 0 {
+		t.Fatalf("expected no email log rows for a refused send, got %d", logged)
+	}
+	var nextSendAt time.Time
+	if err := db.QueryRow(ctx, "SELECT next_send_at FROM card_checkin_reminders WHERE card_id = $1", card.ID).Scan(&nextSendAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !nextSendAt.After(now) || nextSendAt.After(now.Add(24*time.Hour)) {
+		t.Fatalf("expected a retry soon after %v, got %v", now, nextSendAt)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
 )
 
 type stubEmailService struct {
@@ -45,10 +46,13 @@ func (s stubEmailService) SendSupportEmail(ctx context.Context, fromEmail, categ
 	return nil
 }
 
+func (s stubEmailService) SendDataExportEmail(ctx context.Context, email string, exportedAt time.Time) error {
+	return nil
+}
+
 func TestReminderService_GetSettings_InsertsThenLoads(t *testing.T) {
-	userID := uuid.New()
-	createdAt := time.Now().Add(-time.Hour)
-	updatedAt := time.Now()
+	fixture := testutil.NewTestReminderSettings(uuid.New())
+	userID := fixture.UserID
 
 	execCalls := 0
 	db := &fakeDB{
@@ -66,7 +70,7 @@ func TestReminderService_GetSettings_InsertsThenLoads(t *testing.T) {
 			if !strings.Contains(sql, "FROM reminder_settings") {
 				t.Fatalf("unexpected query sql: %q", sql)
 			}
-			return rowFromValues(userID, true, 3, createdAt, updatedAt)
+			return rowFromValues(fixture.Row()...)
 		},
 	}
 
@@ -105,10 +109,9 @@ func TestReminderService_UpdateSettings_EnableRequiresVerifiedEmail(t *testing.T
 }
 
 func TestReminderService_UpdateSettings_UpdatesAndReturnsSettings(t *testing.T) {
-	userID := uuid.New()
+	fixture := testutil.NewTestReminderSettings(uuid.New())
+	userID := fixture.UserID
 	enabled := true
-	createdAt := time.Now().Add(-time.Hour)
-	updatedAt := time.Now()
 
 	var updated bool
 	db := &fakeDB{
@@ -131,7 +134,7 @@ func TestReminderService_UpdateSettings_UpdatesAndReturnsSettings(t *testing.T)
 				return rowFromValues(true)
 			}
 			if strings.Contains(sql, "FROM reminder_settings") {
-				return rowFromValues(userID, true, 3, createdAt, updatedAt)
+				return rowFromValues(fixture.Row()...)
 			}
 			t.Fatalf("unexpected query sql: %q", sql)
 			return rowFromValues(false)
@@ -165,6 +168,7 @@ func TestReminderService_ListCardCheckins_MapsOptionalReminder(t *testing.T) {
 	frequency := "monthly"
 	includeImage := true
 	includeRecommendations := false
+	includeMemories := true
 	schedule := []byte(`{"day_of_month":28,"time":"09:00"}`)
 
 	db := &fakeDB{
@@ -177,9 +181,9 @@ func TestReminderService_ListCardCheckins_MapsOptionalReminder(t *testing.T) {
 			}
 			return &fakeRows{rows: [][]any{
 				// Card with no reminder (NULL reminder fields)
-				{cardID1, &title, 2025, true, false, false, 3, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{cardID1, &title, 2025, true, false, false, 3, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
 				// Card with a reminder
-				{cardID2, &title, 2024, true, false, true, 5, &checkinID, &userID, &cardID2, &enabled, &frequency, schedule, &includeImage, &includeRecommendations, &next, &lastSent, &createdAt, &updatedAt},
+				{cardID2, &title, 2024, true, false, true, 5, &checkinID, &userID, &cardID2, &enabled, &frequency, schedule, &includeImage, &includeRecommendations, &includeMemories, &next, &lastSent, &createdAt, &updatedAt},
 			}}, nil
 		},
 	}
@@ -207,11 +211,15 @@ func TestReminderService_ListCardCheckins_MapsOptionalReminder(t *testing.T) {
 }
 
 func TestReminderService_UpsertCardCheckin_DefaultsAndClampsSchedule(t *testing.T) {
-	userID := uuid.New()
-	cardID := uuid.New()
-	checkinID := uuid.New()
+	card := testutil.NewTestCard()
 	fixedNow := time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC)
 	expectedNext := time.Date(2026, time.January, 28, 9, 0, 0, 0, time.UTC)
+	fixture := testutil.NewTestReminder(card,
+		testutil.WithSchedule(`{"day_of_month":28,"time":"09:00"}`),
+		testutil.WithNextSendAt(expectedNext),
+	)
+	fixture.IncludeRecommendations = false
+	userID, cardID, checkinID := card.UserID, card.ID, fixture.ID
 
 	var insertArgs []any
 	db := &fakeDB{
@@ -223,25 +231,15 @@ func TestReminderService_UpsertCardCheckin_DefaultsAndClampsSchedule(t *testing.
 			return fakeCommandTag{}, nil
 		},
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
-			if strings.Contains(sql, "SELECT is_finalized, is_archived") {
-				return rowFromValues(true, false)
+			if strings.Contains(sql, "SELECT user_id, is_finalized, is_archived") {
+				return rowFromValues(userID, true, false)
+			}
+			if strings.Contains(sql, "SELECT timezone") {
+				return rowFromValues("UTC")
 			}
 			if strings.Contains(sql, "INSERT INTO card_checkin_reminders") {
 				insertArgs = args
-				return rowFromValues(
-					checkinID,
-					userID,
-					cardID,
-					true,
-					"monthly",
-					[]byte(`{"day_of_month":28,"time":"09:00"}`),
-					true,
-					false,
-					&expectedNext,
-					nil,
-					fixedNow.Add(-time.Hour),
-					fixedNow,
-				)
+				return rowFromValues(fixture.Row()...)
 			}
 			t.Fatalf("unexpected query sql: %q", sql)
 			return rowFromValues(false)
@@ -269,8 +267,8 @@ func TestReminderService_UpsertCardCheckin_DefaultsAndClampsSchedule(t *testing.
 	if checkin.NextSendAt == nil || !checkin.NextSendAt.Equal(expectedNext) {
 		t.Fatalf("expected next send %v, got %v", expectedNext, checkin.NextSendAt)
 	}
-	if len(insertArgs) != 7 {
-		t.Fatalf("expected 7 insert args, got %#v", insertArgs)
+	if len(insertArgs) != 8 {
+		t.Fatalf("expected 8 insert args, got %#v", insertArgs)
 	}
 	scheduleJSON, ok := insertArgs[3].([]byte)
 	if !ok {
@@ -283,11 +281,65 @@ func TestReminderService_UpsertCardCheckin_DefaultsAndClampsSchedule(t *testing.
 	if parsed.DayOfMonth != 28 || parsed.Time != "09:00" {
 		t.Fatalf("unexpected parsed schedule: %#v", parsed)
 	}
-	if insertArgs[4] != true || insertArgs[5] != false {
-		t.Fatalf("unexpected include args: %#v", insertArgs[4:6])
+	if insertArgs[4] != true || insertArgs[5] != false || insertArgs[6] != true {
+		t.Fatalf("unexpected include args: %#v", insertArgs[4:7])
 	}
-	if gotNext, ok := insertArgs[6].(time.Time); !ok || !gotNext.Equal(expectedNext) {
-		t.Fatalf("expected next send arg %v, got %#v", expectedNext, insertArgs[6])
+	if gotNext, ok := insertArgs[7].(time.Time); !ok || !gotNext.Equal(expectedNext) {
+		t.Fatalf("expected next send arg %v, got %#v", expectedNext, insertArgs[7])
+	}
+}
+
+func TestReminderService_UpsertCardCheckin_StoresJitterOffset(t *testing.T) {
+	card := testutil.NewTestCard()
+	userID, cardID := card.UserID, card.ID
+	fixedNow := time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC)
+	expectedNext := time.Date(2026, time.January, 15, 8, 48, 0, 0, time.UTC)
+
+	var insertArgs []any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "SELECT user_id, is_finalized, is_archived") {
+				return rowFromValues(userID, true, false)
+			}
+			if strings.Contains(sql, "SELECT timezone") {
+				return rowFromValues("UTC")
+			}
+			insertArgs = args
+			fixture := testutil.NewTestReminder(card, testutil.WithNextSendAt(expectedNext))
+			fixture.Schedule = args[3].([]byte)
+			return rowFromValues(fixture.Row()...)
+		},
+	}
+
+	svc := NewReminderService(db, nil, "http://example.com")
+	svc.now = func() time.Time { return fixedNow }
+	var gotN int
+	svc.randIntn = func(n int) int {
+		gotN = n
+		return 18 // 18 - 30 = -12 minutes
+	}
+
+	_, err := svc.UpsertCardCheckin(context.Background(), userID, cardID, models.CardCheckinScheduleInput{
+		Schedule: models.CardCheckinSchedulePayload{DayOfMonth: 15, Time: "09:00", JitterWindowMinutes: 30},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotN != 61 {
+		t.Fatalf("expected offset drawn from 61 values, got %d", gotN)
+	}
+	var parsed monthlySchedule
+	if err := json.Unmarshal(insertArgs[3].([]byte), &parsed); err != nil {
+		t.Fatalf("unmarshal schedule json: %v", err)
+	}
+	if parsed.JitterWindowMinutes != 30 || parsed.JitterOffsetMinutes != -12 {
+		t.Fatalf("unexpected stored jitter: %#v", parsed)
+	}
+	if gotNext, ok := insertArgs[7].(time.Time); !ok || !gotNext.Equal(expectedNext) {
+		t.Fatalf("expected next send arg %v, got %#v", expectedNext, insertArgs[7])
 	}
 }
 
@@ -341,12 +393,11 @@ func TestReminderService_ListGoalReminders_OptionalCardFilter(t *testing.T) {
 }
 
 func TestReminderService_UpsertGoalReminder_SuccessAndSerializesSchedule(t *testing.T) {
-	userID := uuid.New()
-	cardID := uuid.New()
-	itemID := uuid.New()
-	reminderID := uuid.New()
+	card := testutil.NewTestCard(testutil.WithItems(1))
 	fixedNow := time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC)
 	sendAt := fixedNow.Add(2 * time.Hour)
+	fixture := testutil.NewTestGoalReminder(card, card.Items[0], sendAt)
+	userID, cardID, itemID, reminderID := card.UserID, card.ID, fixture.ItemID, fixture.ID
 
 	var insertArgs []any
 	db := &fakeDB{
@@ -361,9 +412,12 @@ func TestReminderService_UpsertGoalReminder_SuccessAndSerializesSchedule(t *test
 			if strings.Contains(sql, "FROM bingo_items i") {
 				return rowFromValues(cardID, false, true, false)
 			}
+			if strings.Contains(sql, "SELECT timezone") {
+				return rowFromValues("UTC")
+			}
 			if strings.Contains(sql, "INSERT INTO goal_reminders") {
 				insertArgs = args
-				return rowFromValues(reminderID, userID, cardID, itemID, true, "one_time", []byte(`{"send_at":"`+sendAt.UTC().Format(time.RFC3339)+`"}`), &sendAt, nil, fixedNow, fixedNow)
+				return rowFromValues(fixture.Row()...)
 			}
 			t.Fatalf("unexpected query sql: %q", sql)
 			return rowFromValues(false)
@@ -410,20 +464,20 @@ func TestReminderService_CleanupOld_ExecutesDeletes(t *testing.T) {
 		},
 	}
 	svc := NewReminderService(db, nil, "http://example.com")
-	if err := svc.CleanupOld(context.Background()); err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	if deleted, err := svc.CleanupOld(context.Background()); err != nil || deleted != 5 {
+		t.Fatalf("expected 5 deleted, got %d, %v", deleted, err)
 	}
-	if len(queries) != 3 {
-		t.Fatalf("expected 3 cleanup queries, got %d", len(queries))
+	if len(queries) != 5 {
+		t.Fatalf("expected 5 cleanup queries, got %d", len(queries))
 	}
 }
 
 func TestReminderService_SendTestEmail_SendsNotificationEmail(t *testing.T) {
-	userID := uuid.New()
-	cardID := uuid.New()
 	title := "Card"
-	createdAt := time.Now().Add(-48 * time.Hour)
-	updatedAt := time.Now().Add(-time.Hour)
+	card := testutil.NewTestCard(testutil.WithGridSize(3), testutil.WithoutFreeSpace(), testutil.WithItems(2), testutil.WithCompletedItems(1))
+	card.Title = &title
+	settings := testutil.NewTestReminderSettings(card.UserID)
+	userID, cardID := card.UserID, card.ID
 	nextSend := time.Now().Add(24 * time.Hour)
 	existingToken := "tok123"
 
@@ -448,37 +502,16 @@ func TestReminderService_SendTestEmail_SendsNotificationEmail(t *testing.T) {
 			if !strings.Contains(sql, "FROM bingo_items") {
 				t.Fatalf("unexpected items query sql: %q", sql)
 			}
-			itemID := uuid.New()
-			return &fakeRows{rows: [][]any{
-				{itemID, cardID, 0, "Do thing", false, nil, nil, nil, createdAt},
-				{uuid.New(), cardID, 1, "Done thing", true, &createdAt, nil, nil, createdAt},
-			}}, nil
+			return &fakeRows{rows: card.ItemRows()}, nil
 		},
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			switch {
 			case strings.Contains(sql, "FROM reminder_settings"):
-				return rowFromValues(userID, true, 3, createdAt, updatedAt)
+				return rowFromValues(settings.Row()...)
 			case strings.Contains(sql, "SELECT email_verified"):
 				return rowFromValues(true)
 			case strings.Contains(sql, "FROM bingo_cards WHERE id"):
-				freePos := 4
-				return rowFromValues(
-					cardID,
-					userID,
-					2025,
-					nil,
-					&title,
-					3,
-					"BING",
-					false,
-					&freePos,
-					true,
-					true,
-					false,
-					false,
-					createdAt,
-					updatedAt,
-				)
+				return rowFromValues(card.Row()...)
 			case strings.Contains(sql, "SELECT email FROM users"):
 				return rowFromValues("user@test.com")
 			case strings.Contains(sql, "FROM reminder_image_tokens"):
@@ -529,16 +562,26 @@ func TestParseMonthlySchedule_Validates(t *testing.T) {
 	if out.DayOfMonth != 28 {
 		t.Fatalf("expected clamped day 28, got %d", out.DayOfMonth)
 	}
+
+	for _, window := range []int{-1, 61} {
+		if _, err := parseMonthlySchedule(models.CardCheckinSchedulePayload{DayOfMonth: 1, Time: "09:00", JitterWindowMinutes: window}); !errors.Is(err, ErrInvalidSchedule) {
+			t.Fatalf("expected ErrInvalidSchedule for jitter window %d, got %v", window, err)
+		}
+	}
+	out, err = parseMonthlySchedule(models.CardCheckinSchedulePayload{DayOfMonth: 1, Time: "09:00", JitterWindowMinutes: 30})
+	if err != nil || out.JitterWindowMinutes != 30 || out.JitterOffsetMinutes != 0 {
+		t.Fatalf("expected jitter window carried through, got %#v err=%v", out, err)
+	}
 }
 
 func TestParseOneTimeSchedule_RequiresFutureTime(t *testing.T) {
 	now := time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC)
-	_, err := parseOneTimeSchedule(models.GoalReminderScheduleInput{SendAt: now.Add(-time.Minute).Format(time.RFC3339)}, now)
+	_, err := parseOneTimeSchedule(models.GoalReminderScheduleInput{SendAt: now.Add(-time.Minute).Format(time.RFC3339)}, now, time.UTC)
 	if !errors.Is(err, ErrInvalidSchedule) {
 		t.Fatalf("expected ErrInvalidSchedule, got %v", err)
 	}
 
-	out, err := parseOneTimeSchedule(models.GoalReminderScheduleInput{SendAt: now.Add(time.Hour).Format(time.RFC3339)}, now)
+	out, err := parseOneTimeSchedule(models.GoalReminderScheduleInput{SendAt: now.Add(time.Hour).Format(time.RFC3339)}, now, time.UTC)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -563,3 +606,20 @@ func TestReminderService_EnsureCardEligible_NotFound(t *testing.T) {
 		t.Fatalf("expected ErrCardNotFound, got %v", err)
 	}
 }
+
+func TestReminderService_EnsureCardEligible_OtherUsersCard(t *testing.T) {
+	cardID := uuid.New()
+	ownerID := uuid.New()
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(ownerID, true, false)
+		},
+	}
+	svc := NewReminderService(db, nil, "http://example.com")
+	if err := svc.ensureCardEligible(context.Background(), uuid.New(), cardID); !errors.Is(err, ErrCardNotFound) {
+		t.Fatalf("expected ErrCardNotFound for another user's card, got %v", err)
+	}
+	if err := svc.ensureCardEligible(context.Background(), ownerID, cardID); err != nil {
+		t.Fatalf("expected the owner's card to be eligible, got %v", err)
+	}
+}
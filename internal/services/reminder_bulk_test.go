@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
+)
+
+// newBulkGoalReminderDB serves BulkUpsertGoalReminders for card, whose open
+// goals are listed with the reminder IDs in existing. Upserts are recorded
+// in log; committed reports whether the transaction committed.
+func newBulkGoalReminderDB(card *testutil.TestCard, existing map[uuid.UUID]uuid.UUID, log *testutil.SQLLog, committed *bool) *fakeDB {
+	return &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "SELECT user_id, is_finalized, is_archived"):
+				return rowFromValues(card.UserID, card.IsFinalized, card.IsArchived)
+			case strings.Contains(sql, "SELECT timezone"):
+				return rowFromValues("UTC")
+			}
+			return fakeRow{scanFunc: func(dest ...any) error { return errors.New("unexpected query") }}
+		},
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			return &fakeTx{
+				QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+					log.Record(sql, args...)
+					var rows [][]any
+					for _, item := range card.Items {
+						if item.IsCompleted {
+							continue
+						}
+						var reminderID *uuid.UUID
+						if id, ok := existing[item.ID]; ok {
+							reminderID = &id
+						}
+						rows = append(rows, []any{item.ID, reminderID})
+					}
+					return &fakeRows{rows: rows}, nil
+				},
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					log.Record(sql, args...)
+					if id, ok := existing[args[2].(uuid.UUID)]; ok {
+						return rowFromValues(id)
+					}
+					return rowFromValues(uuid.New())
+				},
+				CommitFunc: func(ctx context.Context) error {
+					*committed = true
+					return nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestReminderService_BulkUpsertGoalReminders_SkipsExistingReminders(t *testing.T) {
+	card := testutil.NewTestCard(testutil.WithGridSize(3), testutil.WithItems(4), testutil.WithCompletedItems(1))
+	existingID := uuid.New()
+	existing := map[uuid.UUID]uuid.UUID{card.Items[1].ID: existingID}
+	log := &testutil.SQLLog{}
+	committed := false
+	svc := NewReminderService(newBulkGoalReminderDB(card, existing, log, &committed), nil, "http://example.com")
+	svc.now = func() time.Time { return time.Date(2026, time.January, 10, 10, 0, 0, 0, time.UTC) }
+
+	result, err := svc.BulkUpsertGoalReminders(context.Background(), card.UserID, models.GoalReminderBulkInput{
+		CardID:   card.ID,
+		Kind:     models.GoalReminderKindRecurring,
+		Schedule: models.GoalReminderScheduleInput{EveryDays: 7, Time: "09:00"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !committed {
+		t.Fatal("expected transaction to commit")
+	}
+	if len(result.Created) != 2 || len(result.Updated) != 0 {
+		t.Fatalf("expected 2 created and none updated, got %+v", result)
+	}
+	if len(result.SkippedItemIDs) != 1 || result.SkippedItemIDs[0] != card.Items[1].ID {
+		t.Fatalf("expected the goal with a reminder skipped, got %v", result.SkippedItemIDs)
+	}
+	upserts := log.Matching("INSERT INTO goal_reminders")
+	if len(upserts) != 2 {
+		t.Fatalf("expected 2 upserts, got %d", len(upserts))
+	}
+	for _, call := range upserts {
+		if call.Args[1] != card.ID || call.Args[3] != models.GoalReminderKindRecurring {
+			t.Fatalf("unexpected upsert args %v", call.Args)
+		}
+		if want := time.Date(2026, time.January, 11, 9, 0, 0, 0, time.UTC); !call.Args[5].(time.Time).Equal(want) {
+			t.Fatalf("expected first send %v, got %v", want, call.Args[5])
+		}
+	}
+	list := log.AssertCalled(t, "FROM bingo_items i")
+	if !strings.Contains(list.SQL, "NOT i.is_completed") || !strings.Contains(list.SQL, "free_space_position") {
+		t.Fatalf("expected completed and FREE squares excluded, got %q", list.SQL)
+	}
+}
+
+func TestReminderService_BulkUpsertGoalReminders_Overwrite(t *testing.T) {
+	card := testutil.NewTestCard(testutil.WithItems(2))
+	existingID := uuid.New()
+	existing := map[uuid.UUID]uuid.UUID{card.Items[0].ID: existingID}
+	log := &testutil.SQLLog{}
+	committed := false
+	svc := NewReminderService(newBulkGoalReminderDB(card, existing, log, &committed), nil, "http://example.com")
+
+	result, err := svc.BulkUpsertGoalReminders(context.Background(), card.UserID, models.GoalReminderBulkInput{
+		CardID:    card.ID,
+		Schedule:  models.GoalReminderScheduleInput{SendAt: "2030-01-02T15:04:05Z"},
+		Overwrite: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != existingID || len(result.Created) != 1 || len(result.SkippedItemIDs) != 0 {
+		t.Fatalf("expected one updated and one created, got %+v", result)
+	}
+	if upsert := log.AssertCalled(t, "INSERT INTO goal_reminders"); upsert.Args[3] != models.GoalReminderKindOneTime {
+		t.Fatalf("expected kind to default to one_time, got %v", upsert.Args[3])
+	}
+}
+
+func TestReminderService_BulkUpsertGoalReminders_Eligibility(t *testing.T) {
+	tests := []struct {
+		name string
+		card *testutil.TestCard
+		want error
+	}{
+		{"draft", testutil.NewTestCard(testutil.WithDraft(), testutil.WithItems(1)), ErrCardNotEligible},
+		{"archived", testutil.NewTestCard(testutil.WithArchived(), testutil.WithItems(1)), ErrCardNotEligible},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &testutil.SQLLog{}
+			committed := false
+			svc := NewReminderService(newBulkGoalReminderDB(tt.card, nil, log, &committed), nil, "http://example.com")
+
+			_, err := svc.BulkUpsertGoalReminders(context.Background(), tt.card.UserID, models.GoalReminderBulkInput{
+				CardID:   tt.card.ID,
+				Schedule: models.GoalReminderScheduleInput{SendAt: "2030-01-02T15:04:05Z"},
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			log.AssertNotCalled(t, "INSERT INTO goal_reminders")
+		})
+	}
+}
+
+func TestReminderService_BulkUpsertGoalReminders_CardNotFound(t *testing.T) {
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	svc := NewReminderService(db, nil, "http://example.com")
+
+	_, err := svc.BulkUpsertGoalReminders(context.Background(), uuid.New(), models.GoalReminderBulkInput{CardID: uuid.New()})
+	if !errors.Is(err, ErrCardNotFound) {
+		t.Fatalf("expected ErrCardNotFound, got %v", err)
+	}
+}
+
+func TestReminderService_BulkUpsertGoalReminders_InvalidSchedule(t *testing.T) {
+	card := testutil.NewTestCard(testutil.WithItems(1))
+	log := &testutil.SQLLog{}
+	committed := false
+	svc := NewReminderService(newBulkGoalReminderDB(card, nil, log, &committed), nil, "http://example.com")
+
+	for _, input := range []models.GoalReminderBulkInput{
+		{CardID: card.ID, Kind: "hourly"},
+		{CardID: card.ID, Kind: models.GoalReminderKindRecurring, Schedule: models.GoalReminderScheduleInput{EveryDays: 0, Time: "09:00"}},
+	} {
+		if _, err := svc.BulkUpsertGoalReminders(context.Background(), card.UserID, input); !errors.Is(err, ErrInvalidSchedule) {
+			t.Fatalf("%+v: expected ErrInvalidSchedule, got %v", input, err)
+		}
+	}
+	if committed {
+		t.Fatal("expected no transaction")
+	}
+}
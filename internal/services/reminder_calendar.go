@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// reminderCalendarEventLength is how long each feed event lasts; reminders
+// are instants, but calendar apps hide zero-length events.
+const reminderCalendarEventLength = 15 * time.Minute
+
+// reminderCalendarEvent is one VEVENT in the feed.
+type reminderCalendarEvent struct {
+	uid         string
+	start       time.Time
+	summary     string
+	description string
+	url         string
+	// rrule repeats the event; empty for a single occurrence.
+	rrule string
+}
+
+// GetCalendarFeed reports whether the user has a calendar feed. The feed URL
+// itself can't be shown again.
+func (s *ReminderService) GetCalendarFeed(ctx context.Context, userID uuid.UUID) (*models.ReminderCalendarFeed, error) {
+	var createdAt time.Time
+	err := s.db.QueryRow(ctx,
+		"SELECT created_at FROM reminder_calendar_feeds WHERE user_id = $1",
+		userID,
+	).Scan(&createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &models.ReminderCalendarFeed{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load calendar feed: %w", err)
+	}
+	return &models.ReminderCalendarFeed{Active: true, CreatedAt: &createdAt}, nil
+}
+
+// CreateCalendarFeed issues a new feed token, replacing any previous one, and
+// returns the feed URL.
+func (s *ReminderService) CreateCalendarFeed(ctx context.Context, userID uuid.UUID) (*models.ReminderCalendarFeed, error) {
+	token, err := randomToken(24)
+	if err != nil {
+		return nil, err
+	}
+	var createdAt time.Time
+	if err := s.db.QueryRow(ctx, `
+		INSERT INTO reminder_calendar_feeds (user_id, token_hash, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		  SET token_hash = EXCLUDED.token_hash,
+		      created_at = EXCLUDED.created_at
+		RETURNING created_at`,
+		userID,
+		hashCalendarFeedToken(token),
+	).Scan(&createdAt); err != nil {
+		return nil, fmt.Errorf("create calendar feed: %w", err)
+	}
+	return &models.ReminderCalendarFeed{
+		Active:    true,
+		URL:       s.baseURL + "/api/reminders/calendar.ics?token=" + token,
+		CreatedAt: &createdAt,
+	}, nil
+}
+
+// RevokeCalendarFeed deletes the user's feed token, so calendar apps
+// subscribed to it stop getting updates.
+func (s *ReminderService) RevokeCalendarFeed(ctx context.Context, userID uuid.UUID) error {
+	if _, err := s.db.Exec(ctx, "DELETE FROM reminder_calendar_feeds WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("revoke calendar feed: %w", err)
+	}
+	return nil
+}
+
+// RenderCalendarFeed builds the iCalendar feed for a feed token: every
+// check-in over the next 12 months and every pending goal reminder, from the
+// current schedules. Event UIDs only depend on the reminder (and, for
+// check-ins, the month), so refetching updates events in place.
+func (s *ReminderService) RenderCalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, ErrCalendarFeedNotFound
+	}
+	var userID uuid.UUID
+	err := s.db.QueryRow(ctx, `
+		SELECT f.user_id
+		  FROM reminder_calendar_feeds f
+		  JOIN users u ON u.id = f.user_id AND u.deleted_at IS NULL
+		 WHERE f.token_hash = $1`,
+		hashCalendarFeedToken(token),
+	).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load calendar feed: %w", err)
+	}
+
+	loc, err := s.loadLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	until := now.In(loc).AddDate(1, 0, 0)
+
+	checkins, err := s.calendarCheckinEvents(ctx, userID, loc, until)
+	if err != nil {
+		return nil, err
+	}
+	goals, err := s.calendarGoalEvents(ctx, userID, until)
+	if err != nil {
+		return nil, err
+	}
+	events := append(checkins, goals...)
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].start.Equal(events[j].start) {
+			return events[i].start.Before(events[j].start)
+		}
+		return events[i].uid < events[j].uid
+	})
+	return buildReminderCalendar(s.branding.DisplayName(), now, events), nil
+}
+
+// calendarCheckinEvents expands each enabled check-in from its next send to
+// until, one event per month.
+func (s *ReminderService) calendarCheckinEvents(ctx context.Context, userID uuid.UUID, loc *time.Location, until time.Time) ([]reminderCalendarEvent, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT r.id, r.card_id, r.schedule, r.next_send_at, c.title, c.year
+		  FROM card_checkin_reminders r
+		  JOIN bingo_cards c ON c.id = r.card_id
+		 WHERE r.user_id = $1
+		   AND r.enabled = true
+		   AND r.next_send_at IS NOT NULL
+		   AND c.is_finalized = true
+		   AND c.is_archived = false`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list calendar check-ins: %w", err)
+	}
+	defer rows.Close()
+
+	var events []reminderCalendarEvent
+	for rows.Next() {
+		var reminderID uuid.UUID
+		var card models.BingoCard
+		var scheduleJSON []byte
+		var nextSendAt time.Time
+		if err := rows.Scan(&reminderID, &card.ID, &scheduleJSON, &nextSendAt, &card.Title, &card.Year); err != nil {
+			return nil, fmt.Errorf("scan calendar check-in: %w", err)
+		}
+		var schedule monthlySchedule
+		if err := json.Unmarshal(scheduleJSON, &schedule); err != nil {
+			continue
+		}
+		name := card.DisplayName()
+		for sendAt := nextSendAt.In(loc); sendAt.Before(until); {
+			events = append(events, reminderCalendarEvent{
+				uid:         fmt.Sprintf("checkin-%s-%s@%s", reminderID, sendAt.Format("200601"), s.calendarHost()),
+				start:       sendAt,
+				summary:     "Check in: " + name,
+				description: "Time to update your progress on " + name + ".",
+				url:         fmt.Sprintf("%s/card/%s", s.baseURL, card.ID),
+			})
+			if sendAt, err = nextMonthlySend(sendAt, schedule); err != nil {
+				break
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list calendar check-ins: %w", err)
+	}
+	return events, nil
+}
+
+// calendarGoalEvents lists each pending goal reminder once; recurring ones
+// repeat with an RRULE up to until.
+func (s *ReminderService) calendarGoalEvents(ctx context.Context, userID uuid.UUID, until time.Time) ([]reminderCalendarEvent, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT gr.id, gr.card_id, gr.kind, gr.schedule, gr.next_send_at, c.title, c.year, i.content
+		  FROM goal_reminders gr
+		  JOIN bingo_items i ON i.id = gr.item_id
+		  JOIN bingo_cards c ON c.id = gr.card_id
+		 WHERE gr.user_id = $1
+		   AND gr.enabled = true
+		   AND gr.next_send_at IS NOT NULL
+		   AND i.is_completed = false
+		   AND c.is_archived = false`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list calendar goal reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var events []reminderCalendarEvent
+	for rows.Next() {
+		var reminderID uuid.UUID
+		var card models.BingoCard
+		var kind, content string
+		var scheduleJSON []byte
+		var nextSendAt time.Time
+		if err := rows.Scan(&reminderID, &card.ID, &kind, &scheduleJSON, &nextSendAt, &card.Title, &card.Year, &content); err != nil {
+			return nil, fmt.Errorf("scan calendar goal reminder: %w", err)
+		}
+		if !nextSendAt.Before(until) {
+			continue
+		}
+		event := reminderCalendarEvent{
+			uid:         fmt.Sprintf("goal-%s@%s", reminderID, s.calendarHost()),
+			start:       nextSendAt,
+			summary:     "Goal reminder: " + content,
+			description: "From " + card.DisplayName() + ".",
+			url:         fmt.Sprintf("%s/card/%s", s.baseURL, card.ID),
+		}
+		if kind == models.GoalReminderKindRecurring {
+			var schedule recurringSchedule
+			if err := json.Unmarshal(scheduleJSON, &schedule); err != nil || schedule.EveryDays < 1 {
+				continue
+			}
+			event.rrule = fmt.Sprintf("FREQ=DAILY;INTERVAL=%d;UNTIL=%s", schedule.EveryDays, icsTime(until))
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list calendar goal reminders: %w", err)
+	}
+	return events, nil
+}
+
+// calendarHost scopes event UIDs to this deployment.
+func (s *ReminderService) calendarHost() string {
+	if u, err := url.Parse(s.baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "yearofbingo"
+}
+
+func hashCalendarFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// buildReminderCalendar writes an RFC 5545 calendar with CRLF line endings
+// and lines folded at 75 octets.
+func buildReminderCalendar(brandName string, now time.Time, events []reminderCalendarEvent) []byte {
+	var b strings.Builder
+	line := func(name, value string) {
+		writeICSLine(&b, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//"+icsText(brandName)+"//Reminders//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", icsText(brandName+" reminders"))
+	for _, event := range events {
+		line("BEGIN", "VEVENT")
+		line("UID", event.uid)
+		line("DTSTAMP", icsTime(now))
+		line("DTSTART", icsTime(event.start))
+		line("DTEND", icsTime(event.start.Add(reminderCalendarEventLength)))
+		if event.rrule != "" {
+			line("RRULE", event.rrule)
+		}
+		line("SUMMARY", icsText(event.summary))
+		line("DESCRIPTION", icsText(event.description))
+		line("URL", event.url)
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return []byte(b.String())
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsText escapes a TEXT value: backslashes, semicolons and commas are
+// escaped, line breaks become \n, and other control characters are dropped.
+func icsText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '\\' || r == ';' || r == ',':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\r':
+			b.WriteString(`\n`)
+		case r < 0x20 || r == 0x7F:
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// writeICSLine folds content into lines of at most 75 octets, never splitting
+// a UTF-8 sequence; continuation lines start with a space.
+func writeICSLine(b *strings.Builder, content string) {
+	const maxOctets = 75
+	limit := maxOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		limit = maxOctets - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
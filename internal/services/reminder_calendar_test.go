@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func calendarFeedDB(t *testing.T, token string, userID uuid.UUID, checkins, goals [][]any) *fakeDB {
+	t.Helper()
+	return &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM reminder_calendar_feeds"):
+				if args[0] != hashCalendarFeedToken(token) {
+					return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+				}
+				return rowFromValues(userID)
+			case strings.Contains(sql, "SELECT timezone FROM reminder_settings"):
+				return rowFromValues("America/New_York")
+			default:
+				t.Fatalf("unexpected query sql: %q", sql)
+				return nil
+			}
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			switch {
+			case strings.Contains(sql, "FROM card_checkin_reminders"):
+				return &fakeRows{rows: checkins}, nil
+			case strings.Contains(sql, "FROM goal_reminders"):
+				return &fakeRows{rows: goals}, nil
+			default:
+				t.Fatalf("unexpected query sql: %q", sql)
+				return nil, nil
+			}
+		},
+	}
+}
+
+// unfoldICS joins folded lines back together.
+func unfoldICS(ics string) []string {
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(ics, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func TestReminderService_RenderCalendarFeed(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	now := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+	userID, cardID := uuid.New(), uuid.New()
+	checkinID, oneTimeID, recurringID := uuid.New(), uuid.New(), uuid.New()
+	title := `Run, swim; bike\`
+	checkins := [][]any{{
+		checkinID, cardID, []byte(`{"day_of_month":15,"time":"09:00"}`),
+		time.Date(2026, time.January, 15, 9, 0, 0, 0, newYork), &title, 2026,
+	}}
+	goals := [][]any{
+		{oneTimeID, cardID, models.GoalReminderKindOneTime, []byte(`{"send_at":"2026-02-01T15:00:00Z"}`),
+			time.Date(2026, time.February, 1, 15, 0, 0, 0, time.UTC), &title, 2026, "Read a book\nabout the sea"},
+		{recurringID, cardID, models.GoalReminderKindRecurring, []byte(`{"every_days":3,"time":"07:30"}`),
+			time.Date(2026, time.January, 11, 12, 30, 0, 0, time.UTC), &title, 2026, "Stretch"},
+	}
+	svc := NewReminderService(calendarFeedDB(t, "tok", userID, checkins, goals), nil, "https://example.com")
+	svc.now = func() time.Time { return now }
+
+	ics, err := svc.RenderCalendarFeed(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw := string(ics)
+	for _, line := range strings.Split(strings.TrimSuffix(raw, "\r\n"), "\r\n") {
+		if len(line) > 75 || strings.Contains(line, "\n") {
+			t.Fatalf("expected CRLF lines of at most 75 octets, got %q", line)
+		}
+	}
+	lines := unfoldICS(raw)
+	if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+		t.Fatalf("expected a VCALENDAR, got %q", lines)
+	}
+
+	checkinStarts := []string{}
+	for i, line := range lines {
+		if strings.HasPrefix(line, "UID:checkin-") {
+			checkinStarts = append(checkinStarts, strings.TrimPrefix(lines[i+2], "DTSTART:"))
+		}
+	}
+	// Twelve monthly check-ins, at 09:00 New York time on either side of DST.
+	if len(checkinStarts) != 12 || checkinStarts[0] != "20260115T140000Z" || checkinStarts[6] != "20260715T130000Z" || checkinStarts[11] != "20261215T140000Z" {
+		t.Fatalf("expected check-ins on the 15th through December, got %q", checkinStarts)
+	}
+	for _, want := range []string{
+		"UID:checkin-" + checkinID.String() + "-202601@example.com",
+		`SUMMARY:Check in: Run\, swim\; bike\\`,
+		"URL:https://example.com/card/" + cardID.String(),
+		"UID:goal-" + oneTimeID.String() + "@example.com",
+		`SUMMARY:Goal reminder: Read a book\nabout the sea`,
+		"UID:goal-" + recurringID.String() + "@example.com",
+		"RRULE:FREQ=DAILY;INTERVAL=3;UNTIL=20270110T120000Z",
+	} {
+		if !strings.Contains(strings.Join(lines, "\n"), want) {
+			t.Fatalf("expected feed to contain %q, got:\n%s", want, strings.Join(lines, "\n"))
+		}
+	}
+	if got := strings.Count(raw, "RRULE:"); got != 1 {
+		t.Fatalf("expected only the recurring goal reminder to repeat, got %d RRULEs", got)
+	}
+
+	// A later fetch keeps the same UIDs, so calendar apps update rather than
+	// duplicate events.
+	uids := regexp.MustCompile(`UID:[^\r]+`)
+	svc.now = func() time.Time { return now.Add(time.Hour) }
+	again, err := svc.RenderCalendarFeed(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first, second := uids.FindAllString(raw, -1), uids.FindAllString(string(again), -1); strings.Join(first, ",") != strings.Join(second, ",") {
+		t.Fatalf("expected stable UIDs, got %q then %q", first, second)
+	}
+
+	for _, token := range []string{"", "other"} {
+		if _, err := svc.RenderCalendarFeed(context.Background(), token); !errors.Is(err, ErrCalendarFeedNotFound) {
+			t.Fatalf("token %q: expected ErrCalendarFeedNotFound, got %v", token, err)
+		}
+	}
+}
+
+func TestReminderService_CreateCalendarFeed_StoresOnlyTheHash(t *testing.T) {
+	userID := uuid.New()
+	createdAt := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	var storedHash any
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "INSERT INTO reminder_calendar_feeds") || !strings.Contains(sql, "ON CONFLICT (user_id) DO UPDATE") {
+				t.Fatalf("unexpected query sql: %q", sql)
+			}
+			storedHash = args[1]
+			return rowFromValues(createdAt)
+		},
+	}
+	svc := NewReminderService(db, nil, "https://example.com")
+
+	feed, err := svc.CreateCalendarFeed(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	token, ok := strings.CutPrefix(feed.URL, "https://example.com/api/reminders/calendar.ics?token=")
+	if !ok || len(token) != 48 {
+		t.Fatalf("expected a feed URL with a token, got %q", feed.URL)
+	}
+	if storedHash != hashCalendarFeedToken(token) {
+		t.Fatalf("expected the token's hash stored, got %v", storedHash)
+	}
+	if !feed.Active || feed.CreatedAt == nil || !feed.CreatedAt.Equal(createdAt) {
+		t.Fatalf("unexpected feed: %+v", feed)
+	}
+}
+
+func TestICSTextAndFolding(t *testing.T) {
+	if got, want := icsText("a\\b;c,d\r\ne\x07f"), `a\\b\;c\,d\nef`; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	var b strings.Builder
+	long := "SUMMARY:" + strings.Repeat("é", 100)
+	writeICSLine(&b, long)
+	folded := strings.TrimSuffix(b.String(), "\r\n")
+	for _, line := range strings.Split(folded, "\r\n") {
+		if len(line) > 75 || !utf8.ValidString(strings.TrimPrefix(line, " ")) {
+			t.Fatalf("expected valid lines of at most 75 octets, got %q", line)
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != long {
+		t.Fatal("expected folding to round-trip")
+	}
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
+)
+
+// claimedGoalReminder is one due goal reminder behind a fake DB that applies
+// the runner's claim and schedule UPDATEs the way the database would, so
+// several runners sharing it race like replicas on one database.
+type claimedGoalReminder struct {
+	mu         sync.Mutex
+	card       *testutil.TestCard
+	id         uuid.UUID
+	enabled    bool
+	nextSendAt time.Time
+	claimedBy  string
+	claimedAt  time.Time
+	logged     int
+
+	// selected, when set, is called after each due-reminder query with the
+	// lock released.
+	selected func()
+}
+
+func newClaimedGoalReminder(nextSendAt time.Time) *claimedGoalReminder {
+	return &claimedGoalReminder{
+		card:       testutil.NewTestCard(testutil.WithItems(1)),
+		id:         uuid.New(),
+		enabled:    true,
+		nextSendAt: nextSendAt,
+	}
+}
+
+func (r *claimedGoalReminder) job() goalReminderJob {
+	return goalReminderJob{
+		ID:         r.id,
+		UserID:     r.card.UserID,
+		CardID:     r.card.ID,
+		ItemID:     r.card.Items[0].ID,
+		Kind:       models.GoalReminderKindOneTime,
+		NextSendAt: r.nextSendAt,
+		Timezone:   "UTC",
+	}
+}
+
+func (r *claimedGoalReminder) db() *fakeDB {
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(r.card.UserID)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			switch {
+			case strings.Contains(sql, "SET claimed_at = $2"):
+				now, cutoff := args[3].(time.Time), args[4].(time.Time)
+				if !r.enabled || r.nextSendAt.After(now) || (r.claimedBy != "" && r.claimedAt.After(cutoff)) {
+					return fakeCommandTag{}, nil
+				}
+				r.claimedAt, r.claimedBy = args[1].(time.Time), args[2].(string)
+			case strings.Contains(sql, "UPDATE goal_reminders SET last_sent_at"):
+				r.enabled, r.nextSendAt, r.claimedBy = false, time.Time{}, ""
+			case strings.Contains(sql, "UPDATE goal_reminders SET next_send_at"):
+				r.nextSendAt, r.claimedBy = args[0].(time.Time), ""
+			case strings.Contains(sql, "INSERT INTO reminder_email_log"):
+				r.logged++
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	return &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "FROM goal_reminders gr") {
+				return &fakeRows{}, nil
+			}
+			r.mu.Lock()
+			now, cutoff := args[0].(time.Time), args[2].(time.Time)
+			var rows [][]any
+			if r.enabled && !r.nextSendAt.After(now) && (r.claimedBy == "" || !r.claimedAt.After(cutoff)) {
+				job := r.job()
+				rows = append(rows, []any{job.ID, job.UserID, job.CardID, job.ItemID, job.Kind, job.Schedule, job.NextSendAt, job.EmailPausedUntil, job.Timezone})
+			}
+			selected := r.selected
+			r.mu.Unlock()
+			if selected != nil {
+				selected()
+			}
+			return &fakeRows{rows: rows}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM bingo_items") {
+				return rowFromValues(testutil.GoalReminderContextRow(r.card, r.card.Items[0], "user@test.com", 3)...)
+			}
+			return rowFromValues(0)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		BeginFunc: func(ctx context.Context) (Tx, error) { return tx, nil },
+	}
+}
+
+func TestReminderService_RunDue_ConcurrentRunnersSendOnce(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	reminder := newClaimedGoalReminder(now.Add(-time.Minute))
+
+	// Both runners see the reminder as due before either claims it.
+	var bothSelected sync.WaitGroup
+	bothSelected.Add(2)
+	reminder.selected = func() {
+		bothSelected.Done()
+		bothSelected.Wait()
+	}
+	db := reminder.db()
+
+	// The winner's email is slow: it is still sending while the other
+	// runner finishes its pass.
+	release := make(chan struct{})
+	var sendsMu sync.Mutex
+	sends := 0
+	email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+		sendsMu.Lock()
+		sends++
+		sendsMu.Unlock()
+		select {
+		case <-release:
+		case <-time.After(2 * time.Second):
+		}
+		return nil
+	}}
+
+	results := make(chan models.ReminderRunResult, 2)
+	for i := 0; i < 2; i++ {
+		svc := NewReminderService(db, email, "http://example.com")
+		go func() {
+			result, err := svc.runDue(context.Background(), now, 10)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			results <- result
+		}()
+	}
+
+	first := <-results
+	if first.Sent != 0 || first.Deferred != 0 {
+		t.Fatalf("expected the losing runner to send nothing while the winner sends, got %+v", first)
+	}
+	close(release)
+	second := <-results
+	if second.Sent != 1 {
+		t.Fatalf("expected the winning runner to send once, got %+v", second)
+	}
+
+	if sends != 1 || reminder.logged != 1 {
+		t.Fatalf("expected one send and one log row, got %d sends and %d rows", sends, reminder.logged)
+	}
+	if reminder.enabled || reminder.claimedBy != "" {
+		t.Fatalf("expected the one-time reminder finished and its claim released, got enabled=%v claimed_by=%q", reminder.enabled, reminder.claimedBy)
+	}
+
+	// A runner still holding the reminder from an earlier read can't
+	// claim it once it has been sent.
+	svc := NewReminderService(db, email, "http://example.com")
+	outcome, err := svc.processGoalReminder(context.Background(), reminder.job(), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outcome != reminderSkipped || sends != 1 {
+		t.Fatalf("expected a stale job to be skipped, got outcome %v and %d sends", outcome, sends)
+	}
+}
+
+func TestReminderService_RunDue_AbandonedClaimIsRetriedAfterLease(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	reminder := newClaimedGoalReminder(now.Add(-time.Minute))
+	db := reminder.db()
+
+	sends := 0
+	svc := NewReminderService(db, stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+		sends++
+		return nil
+	}}, "http://example.com")
+	svc.now = func() time.Time { return now }
+
+	// A runner that died mid-send keeps the reminder until its lease runs
+	// out.
+	reminder.claimedBy, reminder.claimedAt = "crashed-runner", now.Add(-reminderClaimLease/2)
+	result, err := svc.runDue(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Sent != 0 || sends != 0 {
+		t.Fatalf("expected a live claim to be left alone, got %+v and %d sends", result, sends)
+	}
+
+	reminder.claimedAt = now.Add(-reminderClaimLease - time.Second)
+	result, err = svc.runDue(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Sent != 1 || sends != 1 {
+		t.Fatalf("expected an expired claim to be taken over and sent, got %+v and %d sends", result, sends)
+	}
+}
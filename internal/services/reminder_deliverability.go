@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var ErrDeliverabilityCheckLimited = errors.New("deliverability check rate limited")
+
+const (
+	// deliverabilityCheckInterval is how often a user may send a probe email.
+	deliverabilityCheckInterval = time.Hour
+
+	deliverabilitySourceType = "deliverability_check"
+)
+
+// RunDeliverabilityCheck sends a minimal probe email to the user's verified
+// address and records whether the provider accepted it, at most once per
+// deliverabilityCheckInterval. It returns the refreshed checklist.
+func (s *ReminderService) RunDeliverabilityCheck(ctx context.Context, userID uuid.UUID) (*models.DeliverabilityCheck, error) {
+	now := s.now()
+
+	verified, err := s.isEmailVerified(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !verified {
+		return nil, ErrEmailNotVerified
+	}
+	if err := s.ensureSettingsRow(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("begin deliverability check tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Serializes concurrent checks for the user so the limit holds.
+	if err := s.lockReminderSettings(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	last, err := s.lastDeliverabilityProbe(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil && now.Before(last.SentAt.Add(deliverabilityCheckInterval)) {
+		return nil, ErrDeliverabilityCheckLimited
+	}
+
+	userEmail, err := s.loadUserEmail(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, html, text := buildDeliverabilityProbeEmail(s.baseURL, s.branding)
+	status := reminderEmailSent
+	if s.emailService == nil {
+		status = reminderEmailFailed
+	} else if err := s.emailService.SendNotificationEmail(ctx, userEmail, subject, html, text); err != nil {
+		status = reminderEmailFailed
+		logging.Warn("Deliverability probe rejected by email provider", map[string]interface{}{
+			"user_id": userID.String(),
+			"error":   err.Error(),
+		})
+	}
+
+	if err := s.logReminderEmail(ctx, tx, userID, deliverabilitySourceType, userID, status, now); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit deliverability check tx: %w", err)
+	}
+
+	return s.GetDeliverabilityCheck(ctx, userID)
+}
+
+// GetDeliverabilityCheck reports the latest probe and a checklist of the
+// things that commonly stop reminder emails from arriving.
+func (s *ReminderService) GetDeliverabilityCheck(ctx context.Context, userID uuid.UUID) (*models.DeliverabilityCheck, error) {
+	now := s.now()
+
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	verified, err := s.isEmailVerified(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var activeReminders int
+	if err := s.db.QueryRow(ctx,
+		`SELECT (SELECT COUNT(*) FROM card_checkin_reminders WHERE user_id = $1 AND enabled = true)
+		      + (SELECT COUNT(*) FROM goal_reminders WHERE user_id = $1 AND enabled = true)`,
+		userID,
+	).Scan(&activeReminders); err != nil {
+		return nil, fmt.Errorf("count active reminders: %w", err)
+	}
+
+	var lastSend *models.ReminderEmailLogEntry
+	var entry models.ReminderEmailLogEntry
+	err = s.db.QueryRow(ctx,
+		`SELECT id, source_type, source_id, status, sent_at
+		   FROM reminder_email_log
+		  WHERE user_id = $1 AND source_type IN ('card_checkin', 'goal_reminder', 'digest')
+		  ORDER BY sent_at DESC
+		  LIMIT 1`,
+		userID,
+	).Scan(&entry.ID, &entry.SourceType, &entry.SourceID, &entry.Status, &entry.SentAt)
+	switch {
+	case err == nil:
+		lastSend = &entry
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("load last reminder send: %w", err)
+	}
+
+	probe, err := s.lastDeliverabilityProbe(ctx, s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &models.DeliverabilityCheck{
+		Probe:     probe,
+		Checklist: buildDeliverabilityChecklist(verified, settings, activeReminders, lastSend, now),
+	}
+	if probe != nil {
+		if next := probe.SentAt.Add(deliverabilityCheckInterval); now.Before(next) {
+			check.NextCheckAt = &next
+		}
+	}
+	return check, nil
+}
+
+func (s *ReminderService) lastDeliverabilityProbe(ctx context.Context, db DBConn, userID uuid.UUID) (*models.DeliverabilityProbe, error) {
+	var probe models.DeliverabilityProbe
+	err := db.QueryRow(ctx,
+		`SELECT status, sent_at
+		   FROM reminder_email_log
+		  WHERE user_id = $1 AND source_type = 'deliverability_check'
+		  ORDER BY sent_at DESC
+		  LIMIT 1`,
+		userID,
+	).Scan(&probe.Status, &probe.SentAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load last deliverability probe: %w", err)
+	}
+	return &probe, nil
+}
+
+func buildDeliverabilityChecklist(verified bool, settings *models.ReminderSettings, activeReminders int, lastSend *models.ReminderEmailLogEntry, now time.Time) []models.DeliverabilityCheckItem {
+	items := make([]models.DeliverabilityCheckItem, 0, 6)
+
+	verifiedItem := models.DeliverabilityCheckItem{Key: "email_verified", Label: "Email address verified", Status: models.DeliverabilityOK}
+	if !verified {
+		verifiedItem.Status = models.DeliverabilityFailed
+		verifiedItem.Detail = "Reminders are only sent to verified addresses. Verify your email from your profile."
+	}
+	items = append(items, verifiedItem)
+
+	enabledItem := models.DeliverabilityCheckItem{Key: "reminders_enabled", Label: "Reminder emails turned on", Status: models.DeliverabilityOK}
+	if !settings.EmailEnabled {
+		enabledItem.Status = models.DeliverabilityFailed
+		enabledItem.Detail = "Turn on reminder emails in your reminder settings."
+	}
+	items = append(items, enabledItem)
+
+	activeItem := models.DeliverabilityCheckItem{Key: "active_reminders", Label: "Reminders scheduled", Status: models.DeliverabilityOK}
+	if activeReminders == 0 {
+		activeItem.Status = models.DeliverabilityWarning
+		activeItem.Detail = "You have no active card check-ins or goal reminders."
+	}
+	items = append(items, activeItem)
+
+	pauseItem := models.DeliverabilityCheckItem{Key: "email_pause", Label: "Emails not paused", Status: models.DeliverabilityOK}
+	if settings.EmailPausedUntil != nil && settings.EmailPausedUntil.After(now) {
+		pauseItem.Status = models.DeliverabilityWarning
+		pauseItem.Detail = fmt.Sprintf("All emails are paused until %s.", settings.EmailPausedUntil.UTC().Format(time.RFC3339))
+	}
+	items = append(items, pauseItem)
+
+	sendItem := models.DeliverabilityCheckItem{Key: "last_send", Label: "Last reminder email", Status: models.DeliverabilityUnknown, Detail: "No reminder emails have been sent yet."}
+	if lastSend != nil {
+		switch lastSend.Status {
+		case string(reminderEmailFailed):
+			sendItem.Status = models.DeliverabilityFailed
+			sendItem.Detail = fmt.Sprintf("The email provider rejected the last reminder on %s.", lastSend.SentAt.UTC().Format(time.RFC3339))
+		default:
+			sendItem.Status = models.DeliverabilityOK
+			sendItem.Detail = fmt.Sprintf("Last reminder accepted by the email provider on %s.", lastSend.SentAt.UTC().Format(time.RFC3339))
+		}
+	}
+	items = append(items, sendItem)
+
+	// Bounces are not reported back to the app, so this can only point at
+	// the probe and the spam folder.
+	items = append(items, models.DeliverabilityCheckItem{
+		Key:    "bounce",
+		Label:  "Bounces",
+		Status: models.DeliverabilityUnknown,
+		Detail: "Bounce reports aren't tracked. If a probe was accepted but never arrived, check your spam folder.",
+	})
+
+	return items
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// deliverabilityDB fakes the queries behind the deliverability check. A nil
+// lastProbe means no probe has been sent yet.
+func deliverabilityDB(t *testing.T, userID uuid.UUID, verified bool, lastProbe *time.Time, logged *[]string) *fakeDB {
+	t.Helper()
+	probeRow := func() Row {
+		if lastProbe == nil {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		}
+		return rowFromValues("sent", *lastProbe)
+	}
+	now := time.Now()
+	return &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "SELECT email_verified"):
+				return rowFromValues(verified)
+			case strings.Contains(sql, "SELECT email FROM users"):
+				return rowFromValues("user@test.com")
+			case strings.Contains(sql, "FROM reminder_settings"):
+				return rowFromValues(userID, true, 3, "reuse", "light", "UTC", false, "08:00", (*time.Time)(nil), now, now, (*time.Time)(nil))
+			case strings.Contains(sql, "FROM goal_reminders"):
+				return rowFromValues(2)
+			case strings.Contains(sql, "source_type = 'deliverability_check'"):
+				return probeRow()
+			case strings.Contains(sql, "source_type IN ('card_checkin', 'goal_reminder', 'digest')"):
+				return rowFromValues(uuid.New(), "card_checkin", uuid.New(), "failed", now.Add(-24*time.Hour))
+			}
+			t.Fatalf("unexpected query: %q", sql)
+			return nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		BeginFunc: func(ctx context.Context) (Tx, error) {
+			return &fakeTx{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if strings.Contains(sql, "FOR UPDATE") {
+						return rowFromValues(userID)
+					}
+					return probeRow()
+				},
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					if strings.Contains(sql, "INSERT INTO reminder_email_log") {
+						*logged = append(*logged, args[1].(string)+":"+string(args[3].(reminderEmailStatus)))
+						sentAt := args[4].(time.Time)
+						lastProbe = &sentAt
+					}
+					return fakeCommandTag{rowsAffected: 1}, nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestReminderService_RunDeliverabilityCheck_RecordsProviderOutcome(t *testing.T) {
+	userID := uuid.New()
+	var logged []string
+	db := deliverabilityDB(t, userID, true, nil, &logged)
+
+	sends := 0
+	svc := NewReminderService(db, stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			sends++
+			if toEmail != "user@test.com" || !strings.Contains(text, "reminder emails can reach you") {
+				t.Fatalf("unexpected probe email to %q: %q", toEmail, text)
+			}
+			return errors.New("provider rejected")
+		},
+	}, "https://example.com")
+
+	check, err := svc.RunDeliverabilityCheck(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sends != 1 || len(logged) != 1 || logged[0] != "deliverability_check:failed" {
+		t.Fatalf("expected one rejected probe to be logged, sends=%d logged=%v", sends, logged)
+	}
+	if check.Probe == nil || check.NextCheckAt == nil {
+		t.Fatalf("expected probe result and next check time, got %+v", check)
+	}
+
+	// A second run inside the hour is refused without sending.
+	if _, err := svc.RunDeliverabilityCheck(context.Background(), userID); !errors.Is(err, ErrDeliverabilityCheckLimited) {
+		t.Fatalf("expected ErrDeliverabilityCheckLimited, got %v", err)
+	}
+	if sends != 1 {
+		t.Fatalf("expected no second probe, got %d sends", sends)
+	}
+}
+
+func TestReminderService_RunDeliverabilityCheck_AllowsAfterAnHour(t *testing.T) {
+	userID := uuid.New()
+	lastProbe := time.Now().Add(-61 * time.Minute)
+	var logged []string
+	svc := NewReminderService(deliverabilityDB(t, userID, true, &lastProbe, &logged), stubEmailService{}, "https://example.com")
+
+	if _, err := svc.RunDeliverabilityCheck(context.Background(), userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logged) != 1 || logged[0] != "deliverability_check:sent" {
+		t.Fatalf("expected an accepted probe to be logged, got %v", logged)
+	}
+}
+
+func TestReminderService_RunDeliverabilityCheck_RequiresVerifiedEmail(t *testing.T) {
+	userID := uuid.New()
+	var logged []string
+	svc := NewReminderService(deliverabilityDB(t, userID, false, nil, &logged), stubEmailService{}, "https://example.com")
+
+	if _, err := svc.RunDeliverabilityCheck(context.Background(), userID); !errors.Is(err, ErrEmailNotVerified) {
+		t.Fatalf("expected ErrEmailNotVerified, got %v", err)
+	}
+	if len(logged) != 0 {
+		t.Fatalf("expected nothing logged, got %v", logged)
+	}
+}
+
+func TestReminderService_GetDeliverabilityCheck_Checklist(t *testing.T) {
+	userID := uuid.New()
+	var logged []string
+	svc := NewReminderService(deliverabilityDB(t, userID, false, nil, &logged), nil, "https://example.com")
+
+	check, err := svc.GetDeliverabilityCheck(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if check.Probe != nil || check.NextCheckAt != nil {
+		t.Fatalf("expected no probe yet, got %+v", check)
+	}
+
+	want := map[string]string{
+		"email_verified":    models.DeliverabilityFailed,
+		"reminders_enabled": models.DeliverabilityOK,
+		"active_reminders":  models.DeliverabilityOK,
+		"email_pause":       models.DeliverabilityOK,
+		"last_send":         models.DeliverabilityFailed,
+		"bounce":            models.DeliverabilityUnknown,
+	}
+	if len(check.Checklist) != len(want) {
+		t.Fatalf("expected %d checklist items, got %+v", len(want), check.Checklist)
+	}
+	for _, item := range check.Checklist {
+		if want[item.Key] != item.Status {
+			t.Errorf("%s: expected %s, got %s", item.Key, want[item.Key], item.Status)
+		}
+	}
+}
+
+func TestBuildDeliverabilityChecklist_PausedAndIdle(t *testing.T) {
+	now := time.Now()
+	paused := now.Add(48 * time.Hour)
+	items := buildDeliverabilityChecklist(true, &models.ReminderSettings{EmailPausedUntil: &paused}, 0, nil, now)
+
+	got := map[string]string{}
+	for _, item := range items {
+		got[item.Key] = item.Status
+	}
+	if got["reminders_enabled"] != models.DeliverabilityFailed ||
+		got["active_reminders"] != models.DeliverabilityWarning ||
+		got["email_pause"] != models.DeliverabilityWarning ||
+		got["last_send"] != models.DeliverabilityUnknown {
+		t.Fatalf("unexpected checklist %+v", items)
+	}
+}
@@ -0,0 +1,561 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// reminderDigestRecipient is a user whose daily reminder digest is due.
+type reminderDigestRecipient struct {
+	UserID     uuid.UUID
+	Email      string
+	DailyCap   int
+	DigestTime string
+	Timezone   string
+}
+
+// digestSource is one reminder a digest email covered, as recorded in the
+// email log's included_sources.
+type digestSource struct {
+	SourceType string    `json:"source_type"`
+	SourceID   uuid.UUID `json:"source_id"`
+}
+
+type digestCheckinSection struct {
+	Card  *models.BingoCard
+	Stats reminderStats
+	// Delta is the progress since the last check-in; nil on the first one.
+	Delta           *checkinDelta
+	Recommendations []models.BingoItem
+}
+
+type digestGoalSection struct {
+	CardID    uuid.UUID
+	ItemID    uuid.UUID
+	CardTitle *string
+	CardYear  int
+	GoalText  string
+}
+
+type digestEmailParams struct {
+	Checkins       []digestCheckinSection
+	Goals          []digestGoalSection
+	BaseURL        string
+	UnsubscribeURL string
+	PreferencesURL string
+	Now            time.Time
+	Brand          config.BrandingConfig
+}
+
+// digestCheckin pairs a due check-in with what its section shows.
+type digestCheckin struct {
+	job             checkinJob
+	card            *models.BingoCard
+	items           []models.BingoItem
+	recommendations []models.BingoItem
+}
+
+// digestGoal pairs a due goal reminder with the goal it is about.
+type digestGoal struct {
+	job     goalReminderJob
+	ctxData *goalReminderContext
+}
+
+// nextDigestAt is the first digest time strictly after now, read in loc.
+func nextDigestAt(now time.Time, digestTime string, loc *time.Location) time.Time {
+	parsed, err := time.Parse("15:04", digestTime)
+	if err != nil {
+		parsed, _ = time.Parse("15:04", models.DefaultReminderDigestTime)
+	}
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), parsed.Hour(), parsed.Minute(), 0, 0, loc)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// scheduleDigest stores when the user's next digest goes out, or clears it
+// when the digest is off, and mirrors the result onto settings.
+func (s *ReminderService) scheduleDigest(ctx context.Context, settings *models.ReminderSettings) error {
+	var next *time.Time
+	if settings.DigestEnabled {
+		at := nextDigestAt(s.now(), settings.DigestTime, reminderLocation(settings.Timezone))
+		next = &at
+	}
+	if _, err := s.db.Exec(ctx,
+		"UPDATE reminder_settings SET digest_next_at = $1 WHERE user_id = $2",
+		next,
+		settings.UserID,
+	); err != nil {
+		return fmt.Errorf("schedule reminder digest: %w", err)
+	}
+	settings.DigestNextAt = next
+	return nil
+}
+
+// runDueDigests sends the daily digest to up to limit users whose digest time
+// has passed. Digest users' check-ins and goal reminders are skipped by the
+// per-reminder runs and wait here until their digest goes out.
+func (s *ReminderService) runDueDigests(ctx context.Context, now time.Time, limit int, result *models.ReminderRunResult) error {
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("begin reminder digest tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		SELECT s.user_id, u.email, s.daily_email_cap, s.digest_time, s.timezone
+		  FROM reminder_settings s
+		  JOIN users u ON u.id = s.user_id AND u.deleted_at IS NULL
+		  LEFT JOIN notification_settings ns ON ns.user_id = s.user_id
+		 WHERE s.digest_enabled = true
+		   AND s.email_enabled = true
+		   AND s.digest_next_at <= $1
+		   AND u.email_verified = true
+		   AND `+emailNotPausedSQL+`
+		 ORDER BY s.digest_next_at ASC
+		 LIMIT $2
+		 FOR UPDATE OF s SKIP LOCKED`,
+		now,
+		limit,
+	)
+	if err != nil {
+		return fmt.Errorf("query due reminder digests: %w", err)
+	}
+	var recipients []reminderDigestRecipient
+	for rows.Next() {
+		var r reminderDigestRecipient
+		if err := rows.Scan(&r.UserID, &r.Email, &r.DailyCap, &r.DigestTime, &r.Timezone); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan reminder digest recipient: %w", err)
+		}
+		recipients = append(recipients, r)
+	}
+	rows.Close()
+
+	for _, recipient := range recipients {
+		outcome, err := s.processDigest(ctx, tx, recipient, now)
+		if err != nil {
+			logging.Error("Failed to process reminder digest", map[string]interface{}{
+				"user_id": recipient.UserID.String(),
+				"error":   err.Error(),
+			})
+			outcome = reminderSkipped
+		}
+		countReminderOutcome(result, outcome)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit reminder digest tx: %w", err)
+	}
+	return nil
+}
+
+func (s *ReminderService) processDigest(ctx context.Context, tx Tx, recipient reminderDigestRecipient, now time.Time) (reminderOutcome, error) {
+	loc := reminderLocation(recipient.Timezone)
+	now = now.In(loc)
+	nextDigest := nextDigestAt(now, recipient.DigestTime, loc)
+
+	checkins, err := s.loadDigestCheckins(ctx, tx, recipient.UserID, now)
+	if err != nil {
+		return reminderSkipped, err
+	}
+	goals, err := s.loadDigestGoals(ctx, tx, recipient.UserID, now)
+	if err != nil {
+		return reminderSkipped, err
+	}
+	if len(checkins) == 0 && len(goals) == 0 {
+		return reminderSkipped, s.setDigestNextAt(ctx, tx, recipient.UserID, nextDigest)
+	}
+
+	capReached, err := s.digestCapReached(ctx, recipient.UserID, now, recipient.DailyCap)
+	if err != nil {
+		return reminderSkipped, err
+	}
+	if capReached {
+		// The reminders stay due and go into tomorrow's digest.
+		return reminderDeferred, s.setDigestNextAt(ctx, tx, recipient.UserID, nextDigest)
+	}
+
+	unsubscribeURL, err := s.createUnsubscribeURL(ctx, recipient.UserID)
+	if err != nil {
+		return reminderSkipped, err
+	}
+	params := digestEmailParams{
+		BaseURL:        s.baseURL,
+		UnsubscribeURL: unsubscribeURL,
+		PreferencesURL: createEmailPreferencesURL(ctx, s.db, s.baseURL, recipient.UserID, s.now()),
+		Now:            s.now(),
+		Brand:          s.branding,
+	}
+	var sources []digestSource
+	for _, checkin := range checkins {
+		stats := buildReminderStats(checkin.card, checkin.items)
+		params.Checkins = append(params.Checkins, digestCheckinSection{
+			Card:            checkin.card,
+			Stats:           stats,
+			Delta:           checkinProgressDelta(checkin.job.ProgressSnapshot, checkin.items, stats),
+			Recommendations: checkin.recommendations,
+		})
+		sources = append(sources, digestSource{SourceType: "card_checkin", SourceID: checkin.job.ID})
+	}
+	for _, goal := range goals {
+		params.Goals = append(params.Goals, digestGoalSection{
+			CardID:    goal.ctxData.CardID,
+			ItemID:    goal.job.ItemID,
+			CardTitle: goal.ctxData.CardTitle,
+			CardYear:  goal.ctxData.CardYear,
+			GoalText:  goal.ctxData.ItemContent,
+		})
+		sources = append(sources, digestSource{SourceType: "goal_reminder", SourceID: goal.job.ID})
+	}
+	subject, html, text := buildDigestEmail(params)
+
+	sent := false
+	rateLimited := false
+	status := reminderEmailSent
+	if s.emailService == nil {
+		status = reminderEmailFailed
+	} else if err := s.emailService.SendNotificationEmail(ctx, recipient.Email, subject, html, text); err != nil {
+		status = reminderEmailFailed
+		rateLimited = errors.Is(err, ErrEmailRateLimited)
+	} else {
+		sent = true
+	}
+	// As with single reminders, a rate-limited digest is logged and
+	// notified when the retry goes out.
+	if !rateLimited {
+		s.notifyDigestReminders(ctx, tx, checkins, goals, now)
+	}
+	outcome := reminderSent
+	if !sent {
+		outcome = reminderDeferred
+	}
+
+	if sent {
+		if err := s.advanceDigestReminders(ctx, tx, checkins, goals, now); err != nil {
+			return outcome, err
+		}
+	} else {
+		for _, checkin := range checkins {
+			if err := s.deferCheckinAfterFailure(ctx, tx, checkin.job.ID, now); err != nil {
+				return outcome, err
+			}
+		}
+		for _, goal := range goals {
+			if err := s.deferGoalAfterFailure(ctx, tx, goal.job.ID, now); err != nil {
+				return outcome, err
+			}
+		}
+		// Retry with the reminders rather than waiting for tomorrow.
+		nextDigest = now.Add(15 * time.Minute)
+	}
+
+	if !rateLimited {
+		if err := s.logDigestEmail(ctx, tx, recipient.UserID, sources, status, now); err != nil {
+			return outcome, err
+		}
+	}
+	if err := s.setDigestNextAt(ctx, tx, recipient.UserID, nextDigest); err != nil {
+		return outcome, err
+	}
+	return outcome, nil
+}
+
+// loadDigestCheckins returns the user's due check-ins, disabling those whose
+// card can no longer be checked in on as the per-reminder run would.
+func (s *ReminderService) loadDigestCheckins(ctx context.Context, tx Tx, userID uuid.UUID, now time.Time) ([]digestCheckin, error) {
+	rows, err := tx.Query(ctx, checkinJobSelect+`
+		 WHERE r.user_id = $1
+		   AND r.enabled = true
+		   AND r.next_send_at <= $2
+		 ORDER BY r.next_send_at ASC
+		 FOR UPDATE OF r SKIP LOCKED`,
+		userID,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query digest card checkins: %w", err)
+	}
+	jobs, err := scanCheckinJobs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkins []digestCheckin
+	for _, job := range jobs {
+		card, items, err := s.loadCardWithItemsTx(ctx, tx, job.UserID, job.CardID)
+		if err != nil && !errors.Is(err, ErrCardNotFound) {
+			return nil, err
+		}
+		if err != nil || !card.IsFinalized || card.IsArchived {
+			if _, err := s.disableCheckin(ctx, tx, job.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		checkins = append(checkins, digestCheckin{
+			job:             job,
+			card:            card,
+			items:           items,
+			recommendations: s.checkinRecommendations(job, card, items),
+		})
+	}
+	return checkins, nil
+}
+
+// loadDigestGoals returns the user's due goal reminders, disabling those for
+// completed goals or cards that are archived or no longer finalized.
+func (s *ReminderService) loadDigestGoals(ctx context.Context, tx Tx, userID uuid.UUID, now time.Time) ([]digestGoal, error) {
+	rows, err := tx.Query(ctx, goalReminderJobSelect+`
+		 WHERE gr.user_id = $1
+		   AND gr.enabled = true
+		   AND gr.next_send_at <= $2
+		 ORDER BY gr.next_send_at ASC
+		 FOR UPDATE OF gr SKIP LOCKED`,
+		userID,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query digest goal reminders: %w", err)
+	}
+	jobs, err := scanGoalReminderJobs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var goals []digestGoal
+	for _, job := range jobs {
+		ctxData, err := s.loadGoalReminderContext(ctx, job.UserID, job.ItemID)
+		if err != nil && !errors.Is(err, ErrItemNotFound) {
+			return nil, err
+		}
+		if err != nil || !ctxData.CardFinalized || ctxData.CardArchived || ctxData.ItemCompleted {
+			if _, err := s.disableGoalReminder(ctx, tx, job.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		goals = append(goals, digestGoal{job: job, ctxData: ctxData})
+	}
+	return goals, nil
+}
+
+// advanceDigestReminders moves every reminder a sent digest covered on to its
+// next occurrence, exactly as its own email would have.
+func (s *ReminderService) advanceDigestReminders(ctx context.Context, tx Tx, checkins []digestCheckin, goals []digestGoal, now time.Time) error {
+	for _, checkin := range checkins {
+		nextSendAt, err := s.nextCheckinSendAt(now, checkin.job)
+		if err != nil {
+			return err
+		}
+		recent, err := appendRecentRecommendations(checkin.job.RecentRecommendations, checkin.recommendations)
+		if err != nil {
+			return fmt.Errorf("encode recent recommendations: %w", err)
+		}
+		snapshot, err := json.Marshal(newCheckinSnapshot(checkin.card, checkin.items))
+		if err != nil {
+			return fmt.Errorf("encode progress snapshot: %w", err)
+		}
+		if err := s.updateCheckinAfterSend(ctx, tx, checkin.job.ID, now, nextSendAt, recent, snapshot); err != nil {
+			return err
+		}
+	}
+	for _, goal := range goals {
+		if goal.job.Kind != models.GoalReminderKindRecurring {
+			if err := s.markGoalReminderSent(ctx, tx, goal.job.ID, now); err != nil {
+				return err
+			}
+			continue
+		}
+		nextSendAt, err := s.nextGoalSendAt(now, goal.job)
+		if err != nil {
+			return err
+		}
+		if err := s.rescheduleGoalReminder(ctx, tx, goal.job.ID, now, nextSendAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyDigestReminders adds the in-app notification each covered reminder
+// would have created on its own. Failures are only logged.
+func (s *ReminderService) notifyDigestReminders(ctx context.Context, tx Tx, checkins []digestCheckin, goals []digestGoal, now time.Time) {
+	if s.notificationService == nil {
+		return
+	}
+	for _, checkin := range checkins {
+		if err := s.notificationService.NotifyReminderCheckin(ctx, tx, checkin.job.UserID, checkin.job.CardID, now); err != nil {
+			logging.Error("Failed to create check-in notification", map[string]interface{}{"error": err.Error()})
+		}
+	}
+	for _, goal := range goals {
+		if err := s.notificationService.NotifyReminderGoal(ctx, tx, goal.job.UserID, goal.ctxData.CardID, goal.job.ItemID, now); err != nil {
+			logging.Error("Failed to create goal reminder notification", map[string]interface{}{"error": err.Error()})
+		}
+	}
+}
+
+// digestCapReached reports whether the user has used up today's reminder
+// emails. A digest counts as a single send however many reminders it holds.
+func (s *ReminderService) digestCapReached(ctx context.Context, userID uuid.UUID, now time.Time, cap int) (bool, error) {
+	if cap <= 0 {
+		cap = 3
+	}
+	sentOn := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var count int
+	if err := s.db.QueryRow(ctx,
+		"SELECT COUNT(*) FROM reminder_email_log WHERE user_id = $1 AND status = 'sent' AND sent_on = $2 AND source_type <> 'deliverability_check'",
+		userID,
+		sentOn,
+	).Scan(&count); err != nil {
+		return false, fmt.Errorf("check reminder digest cap: %w", err)
+	}
+	return count >= cap, nil
+}
+
+func (s *ReminderService) setDigestNextAt(ctx context.Context, tx Tx, userID uuid.UUID, next time.Time) error {
+	if _, err := tx.Exec(ctx,
+		"UPDATE reminder_settings SET digest_next_at = $1 WHERE user_id = $2",
+		next,
+		userID,
+	); err != nil {
+		return fmt.Errorf("schedule reminder digest: %w", err)
+	}
+	return nil
+}
+
+// logDigestEmail records one 'digest' row for the whole email, keyed by the
+// user, listing the reminders it covered.
+func (s *ReminderService) logDigestEmail(ctx context.Context, tx Tx, userID uuid.UUID, sources []digestSource, status reminderEmailStatus, sentAt time.Time) error {
+	included, err := json.Marshal(sources)
+	if err != nil {
+		return fmt.Errorf("encode digest sources: %w", err)
+	}
+	sentOn := time.Date(sentAt.Year(), sentAt.Month(), sentAt.Day(), 0, 0, 0, 0, sentAt.Location())
+	if _, err := tx.Exec(ctx,
+		"INSERT INTO reminder_email_log (user_id, source_type, source_id, status, sent_at, sent_on, included_sources) VALUES ($1, 'digest', $1, $2, $3, $4, $5)",
+		userID,
+		status,
+		sentAt,
+		sentOn,
+		included,
+	); err != nil {
+		return fmt.Errorf("log reminder digest: %w", err)
+	}
+	return nil
+}
+
+// buildDigestEmail renders the daily digest: a section per check-in card with
+// its progress and suggestions, then a section per goal reminder.
+func buildDigestEmail(params digestEmailParams) (string, string, string) {
+	now := params.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	brand := emailBrand(params.Brand)
+	manageURL := fmt.Sprintf("%s/profile", params.BaseURL)
+	safeManageURL := templateEscape(manageURL)
+	unsubscribe := params.UnsubscribeURL
+	safeUnsubscribe := templateEscape(unsubscribe)
+	preferencesHTML, preferencesText := emailPreferencesLines(params.PreferencesURL)
+
+	count := len(params.Checkins) + len(params.Goals)
+	summary := "1 reminder for today"
+	if count != 1 {
+		summary = fmt.Sprintf("%d reminders for today", count)
+	}
+	subject := sanitizeSubject(fmt.Sprintf("Your %s digest: %s", brand.name(), summary))
+
+	var sectionsHTML, sectionsText strings.Builder
+	for _, section := range params.Checkins {
+		cardName := section.Card.DisplayName()
+		cardURL := fmt.Sprintf("%s/card/%s", params.BaseURL, section.Card.ID)
+		progress := fmt.Sprintf("%d/%d complete - %s - %s", section.Stats.Completed, section.Stats.Total, pluralizeBingo(section.Stats.Bingos), cardTimeLeft(section.Card, now))
+
+		fmt.Fprintf(&sectionsHTML, "<h2 style=\"color: #333; font-size: 20px; margin-top: 28px;\">%s</h2>\n  <p style=\"color: #666; margin-top: 0;\">%s</p>\n  ",
+			templateEscape(cardName), templateEscape(progress))
+		fmt.Fprintf(&sectionsText, "%s\n%s\n", isolateBidi(cardName), progress)
+		if section.Delta != nil {
+			line := checkinDeltaLine(section.Delta)
+			fmt.Fprintf(&sectionsHTML, "<p style=\"color: #444;\">%s</p>\n  ", templateEscape(line))
+			sectionsText.WriteString(line + "\n")
+		}
+		if len(section.Recommendations) > 0 {
+			items := make([]string, 0, len(section.Recommendations))
+			textItems := make([]string, 0, len(section.Recommendations))
+			for _, item := range section.Recommendations {
+				items = append(items, fmt.Sprintf("<li>%s</li>", templateEscape(item.Content)))
+				textItems = append(textItems, fmt.Sprintf("- %s", isolateBidi(item.Content)))
+			}
+			fmt.Fprintf(&sectionsHTML, "<p style=\"margin-bottom: 4px;\">Suggested next goals:</p><ul style=\"padding-left: 20px; margin-top: 0;\">%s</ul>\n  ", strings.Join(items, ""))
+			fmt.Fprintf(&sectionsText, "Suggested next goals:\n%s\n", strings.Join(textItems, "\n"))
+		}
+		fmt.Fprintf(&sectionsHTML, "<p><a href=\"%s\">Open my card</a></p>\n  ", templateEscape(cardURL))
+		fmt.Fprintf(&sectionsText, "Open my card: %s\n\n", cardURL)
+	}
+	for _, section := range params.Goals {
+		cardName := cardDisplayName(section.CardTitle, &section.CardYear)
+		goalURL := fmt.Sprintf("%s/card/%s?item=%s", params.BaseURL, section.CardID, section.ItemID)
+
+		fmt.Fprintf(&sectionsHTML, "<h2 style=\"color: #333; font-size: 20px; margin-top: 28px;\">Reminder: %s</h2>\n  <p style=\"color: #666; margin-top: 0;\">Card: %s</p>\n  <p><a href=\"%s\">Open this goal</a></p>\n  ",
+			templateEscape(section.GoalText), templateEscape(cardName), templateEscape(goalURL))
+		fmt.Fprintf(&sectionsText, "Reminder: %s\nCard: %s\nOpen this goal: %s\n\n", isolateBidi(section.GoalText), isolateBidi(cardName), goalURL)
+	}
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 640px; margin: 0 auto; padding: 24px;">
+  %s<h1 style="color: #333; font-size: 24px;">%s</h1>
+  <p style="font-size: 16px;">%s</p>
+  %s<hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
+  <p style="color: #666; font-size: 14px;">Manage reminders: <a href="%s">%s</a></p>
+  %s<p style="color: #666; font-size: 14px;">Unsubscribe: <a href="%s">%s</a></p>
+  <p style="color: #999; font-size: 12px;">%s</p>
+</body>
+</html>`,
+		brand.logoHTML(),
+		templateEscape(brand.name()),
+		templateEscape(summary),
+		sectionsHTML.String(),
+		safeManageURL,
+		safeManageURL,
+		preferencesHTML,
+		safeUnsubscribe,
+		safeUnsubscribe,
+		brand.footerHTML(),
+	)
+
+	text := fmt.Sprintf(`%s
+
+%sManage reminders: %s
+%sUnsubscribe: %s
+
+--
+%s`,
+		summary,
+		sectionsText.String(),
+		manageURL,
+		preferencesText,
+		unsubscribe,
+		brand.footerText(),
+	)
+
+	return subject, html, text
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestNextDigestAt(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name       string
+		now        time.Time
+		digestTime string
+		loc        *time.Location
+		want       time.Time
+	}{
+		{"later today", time.Date(2026, 3, 10, 6, 0, 0, 0, time.UTC), "08:00", time.UTC, time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)},
+		{"exactly now rolls over", time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC), "08:00", time.UTC, time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)},
+		{"user's zone", time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC), "09:30", ny, time.Date(2026, 3, 11, 9, 30, 0, 0, ny)},
+		{"invalid falls back to default", time.Date(2026, 3, 10, 6, 0, 0, 0, time.UTC), "nope", time.UTC, time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := nextDigestAt(tt.now, tt.digestTime, tt.loc); !got.Equal(tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestBuildDigestEmail_SectionsAndEscaping(t *testing.T) {
+	title := "<b>Card</b>"
+	card := &models.BingoCard{ID: uuid.New(), Year: 2026, Title: &title}
+	subject, html, text := buildDigestEmail(digestEmailParams{
+		Checkins: []digestCheckinSection{{
+			Card:            card,
+			Stats:           reminderStats{Completed: 2, Total: 24},
+			Recommendations: []models.BingoItem{{Content: "Read & <write>"}},
+		}},
+		Goals: []digestGoalSection{{
+			CardID:   card.ID,
+			ItemID:   uuid.New(),
+			CardYear: 2026,
+			GoalText: `<script>alert("x")</script>`,
+		}},
+		BaseURL:        "https://example.com",
+		UnsubscribeURL: "https://example.com/r/unsubscribe?token=abc",
+		Now:            time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC),
+	})
+
+	if !strings.Contains(subject, "2 reminders for today") {
+		t.Fatalf("expected the reminder count in the subject, got %q", subject)
+	}
+	for _, raw := range []string{"<b>Card</b>", "<write>", "<script>"} {
+		if strings.Contains(html, raw) {
+			t.Fatalf("expected %q to be escaped in the HTML body", raw)
+		}
+	}
+	for _, want := range []string{"2/24 complete", "Suggested next goals", "Open my card", "Open this goal", "/r/unsubscribe?token=abc"} {
+		if !strings.Contains(html, want) || !strings.Contains(text, want) {
+			t.Fatalf("expected %q in both bodies", want)
+		}
+	}
+	if !strings.Contains(text, `Reminder: <script>alert("x")</script>`) {
+		t.Fatalf("expected the plain goal text in the text body, got %q", text)
+	}
+}
+
+func TestReminderService_RunDue_Digest(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "solo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	if _, err := db.Exec(ctx, "UPDATE users SET email_verified = true WHERE id = $1", user.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cards := NewCardService(db)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: time.Now().Year(), GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	var goal *models.BingoItem
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		item, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content})
+		if err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+		if goal == nil {
+			goal = item
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+
+	var emails []string
+	reminders := NewReminderService(db, stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			emails = append(emails, text)
+			return nil
+		},
+	}, "https://example.com")
+	badTime := "25:00"
+	if _, err := reminders.UpdateSettings(ctx, user.ID, models.ReminderSettingsPatch{DigestTime: &badTime}); !errors.Is(err, ErrInvalidDigestTime) {
+		t.Fatalf("expected ErrInvalidDigestTime, got %v", err)
+	}
+	enabled := true
+	digestTime := "07:30"
+	settings, err := reminders.UpdateSettings(ctx, user.ID, models.ReminderSettingsPatch{EmailEnabled: &enabled, DigestEnabled: &enabled, DigestTime: &digestTime})
+	if err != nil {
+		t.Fatalf("unexpected error enabling the digest: %v", err)
+	}
+	if !settings.DigestEnabled || settings.DigestTime != "07:30" || settings.DigestNextAt == nil || !settings.DigestNextAt.After(time.Now()) {
+		t.Fatalf("expected a scheduled 07:30 digest, got %+v", settings)
+	}
+
+	noImage := false
+	checkin, err := reminders.UpsertCardCheckin(ctx, user.ID, card.ID, models.CardCheckinScheduleInput{
+		Frequency:    "monthly",
+		Schedule:     models.CardCheckinSchedulePayload{DayOfMonth: 1, Time: "09:00"},
+		IncludeImage: &noImage,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error scheduling check-in: %v", err)
+	}
+	goalReminder, err := reminders.UpsertGoalReminder(ctx, user.ID, models.GoalReminderInput{
+		ItemID:   goal.ID,
+		Kind:     models.GoalReminderKindRecurring,
+		Schedule: models.GoalReminderScheduleInput{EveryDays: 7, Time: "09:00"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error scheduling goal reminder: %v", err)
+	}
+	past := time.Now().Add(-time.Hour)
+	for _, query := range []string{
+		"UPDATE card_checkin_reminders SET next_send_at = $1",
+		"UPDATE goal_reminders SET next_send_at = $1",
+	} {
+		if _, err := db.Exec(ctx, query, past); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Due reminders wait for the digest time instead of going out alone.
+	if n, err := reminders.RunDue(ctx, time.Now(), 10); err != nil || n != 0 || len(emails) != 0 {
+		t.Fatalf("expected nothing before the digest time, got %d (%d emails) %v", n, len(emails), err)
+	}
+
+	if _, err := db.Exec(ctx, "UPDATE reminder_settings SET digest_next_at = $1", past); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n, err := reminders.RunDue(ctx, time.Now(), 10)
+	if err != nil || n != 1 || len(emails) != 1 {
+		t.Fatalf("expected one digest email, got %d (%d emails) %v", n, len(emails), err)
+	}
+	if !strings.Contains(emails[0], "Reminder: Run") || !strings.Contains(emails[0], "Open my card") {
+		t.Fatalf("expected check-in and goal sections, got %q", emails[0])
+	}
+
+	var sourceType string
+	var sourceID uuid.UUID
+	var included []byte
+	if err := db.QueryRow(ctx,
+		"SELECT source_type, source_id, included_sources FROM reminder_email_log WHERE user_id = $1",
+		user.ID,
+	).Scan(&sourceType, &sourceID, &included); err != nil {
+		t.Fatalf("expected a single email log row: %v", err)
+	}
+	var sources []digestSource
+	if err := json.Unmarshal(included, &sources); err != nil {
+		t.Fatalf("unexpected included_sources %q: %v", included, err)
+	}
+	want := []digestSource{{"card_checkin", checkin.ID}, {"goal_reminder", goalReminder.ID}}
+	if sourceType != "digest" || sourceID != user.ID || len(sources) != 2 || sources[0] != want[0] || sources[1] != want[1] {
+		t.Fatalf("unexpected digest log entry %s %s %+v", sourceType, sourceID, sources)
+	}
+
+	var checkinNext, goalNext, digestNext time.Time
+	if err := db.QueryRow(ctx, "SELECT next_send_at FROM card_checkin_reminders WHERE id = $1", checkin.ID).Scan(&checkinNext); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := db.QueryRow(ctx, "SELECT next_send_at FROM goal_reminders WHERE id = $1", goalReminder.ID).Scan(&goalNext); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := db.QueryRow(ctx, "SELECT digest_next_at FROM reminder_settings WHERE user_id = $1", user.ID).Scan(&digestNext); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !checkinNext.After(time.Now()) || !goalNext.After(time.Now()) || !digestNext.After(time.Now()) {
+		t.Fatalf("expected the reminders and digest to move on, got %v %v %v", checkinNext, goalNext, digestNext)
+	}
+
+	// With the reminders sent there is nothing left for another digest.
+	if n, err := reminders.RunDue(ctx, time.Now(), 10); err != nil || n != 0 || len(emails) != 1 {
+		t.Fatalf("expected no second email, got %d (%d emails) %v", n, len(emails), err)
+	}
+}
@@ -3,9 +3,11 @@ package services
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
@@ -16,38 +18,61 @@ type reminderStats struct {
 }
 
 type checkinEmailParams struct {
-	Card            *models.BingoCard
-	Stats           reminderStats
+	Card  *models.BingoCard
+	Stats reminderStats
+	// Delta is the progress since the last check-in; nil on the first one.
+	Delta           *checkinDelta
 	Recommendations []models.BingoItem
+	Memory          *models.Memory
 	BaseURL         string
 	ImageURL        string
-	UnsubscribeURL  string
-	IsTest          bool
+	// LinkURL replaces the card link, e.g. with a click-tracking redirect.
+	LinkURL        string
+	UnsubscribeURL string
+	PreferencesURL string
+	IsTest         bool
+	Now            time.Time
+	Brand          config.BrandingConfig
 }
 
 type goalReminderEmailParams struct {
-	CardID         uuid.UUID
-	ItemID         uuid.UUID
-	CardTitle      *string
-	CardYear       int
-	GoalText       string
-	BaseURL        string
+	CardID    uuid.UUID
+	ItemID    uuid.UUID
+	CardTitle *string
+	CardYear  int
+	GoalText  string
+	BaseURL   string
+	// LinkURL replaces the goal link, e.g. with a click-tracking redirect.
+	LinkURL string
+	// SnoozeURL links to the snooze page; empty leaves the link out.
+	SnoozeURL      string
 	UnsubscribeURL string
+	PreferencesURL string
+	Brand          config.BrandingConfig
 }
 
 func buildCheckinEmail(params checkinEmailParams) (string, string, string) {
 	cardName := params.Card.DisplayName()
-	progress := fmt.Sprintf("%d/%d complete - %s", params.Stats.Completed, params.Stats.Total, pluralizeBingo(params.Stats.Bingos))
+	now := params.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	progress := fmt.Sprintf("%d/%d complete - %s - %s", params.Stats.Completed, params.Stats.Total, pluralizeBingo(params.Stats.Bingos), cardTimeLeft(params.Card, now))
 	manageURL := fmt.Sprintf("%s/profile", params.BaseURL)
 	cardURL := fmt.Sprintf("%s/card/%s", params.BaseURL, params.Card.ID)
+	if params.LinkURL != "" {
+		cardURL = params.LinkURL
+	}
 	unsubscribe := params.UnsubscribeURL
 	safeManageURL := templateEscape(manageURL)
 	safeCardURL := templateEscape(cardURL)
 	safeUnsubscribe := templateEscape(unsubscribe)
+	preferencesHTML, preferencesText := emailPreferencesLines(params.PreferencesURL)
 
-	subject := "Your Year of Bingo check-in"
+	brand := emailBrand(params.Brand)
+	subject := sanitizeSubject(fmt.Sprintf("Your %s check-in", brand.name()))
 	if params.IsTest {
-		subject = "Your Year of Bingo check-in (test)"
+		subject += " (test)"
 	}
 
 	recommendationHTML := ""
@@ -61,11 +86,38 @@ func buildCheckinEmail(params checkinEmailParams) (string, string, string) {
 
 		textItems := make([]string, 0, len(params.Recommendations))
 		for _, item := range params.Recommendations {
-			textItems = append(textItems, fmt.Sprintf("- %s", item.Content))
+			textItems = append(textItems, fmt.Sprintf("- %s", isolateBidi(item.Content)))
 		}
 		recommendationText = fmt.Sprintf("Suggested next goals:\n%s\n\n", strings.Join(textItems, "\n"))
 	}
 
+	memoryHTML := ""
+	memoryText := ""
+	if params.Memory != nil {
+		line := memoryLine(params.Memory)
+		memoryHTML = fmt.Sprintf("<p style=\"color: #444;\">%s</p>", templateEscape(line))
+		memoryText = isolateBidi(line) + "\n\n"
+	}
+
+	deltaHTML := ""
+	deltaText := ""
+	if params.Delta != nil {
+		line := checkinDeltaLine(params.Delta)
+		deltaHTML = fmt.Sprintf("<p style=\"color: #444; margin-bottom: 4px;\">%s</p>", templateEscape(line))
+		deltaText = line + "\n"
+		if len(params.Delta.Completed) > 0 {
+			items := make([]string, 0, len(params.Delta.Completed))
+			textItems := make([]string, 0, len(params.Delta.Completed))
+			for _, item := range params.Delta.Completed {
+				items = append(items, fmt.Sprintf("<li>%s</li>", templateEscape(item.Content)))
+				textItems = append(textItems, fmt.Sprintf("- %s", isolateBidi(item.Content)))
+			}
+			deltaHTML += fmt.Sprintf("<ul style=\"padding-left: 20px; margin-top: 0;\">%s</ul>", strings.Join(items, ""))
+			deltaText += strings.Join(textItems, "\n") + "\n"
+		}
+		deltaText += "\n"
+	}
+
 	imageBlock := ""
 	if params.ImageURL != "" {
 		safeImageURL := templateEscape(params.ImageURL)
@@ -82,64 +134,132 @@ func buildCheckinEmail(params checkinEmailParams) (string, string, string) {
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
 </head>
 <body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 640px; margin: 0 auto; padding: 24px;">
-  <h1 style="color: #333; font-size: 24px;">Year of Bingo</h1>
+  %s<h1 style="color: #333; font-size: 24px;">%s</h1>
   <p style="font-size: 18px; margin-bottom: 4px;"><strong>%s</strong></p>
   <p style="color: #666; margin-top: 0;">%s</p>
   %s
+  %s
+  %s
   <p>
-    <a href="%s" style="display: inline-block; background: #0f6f62; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">Open my card</a>
+    <a href="%s" style="display: inline-block; background: %s; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">Open my card</a>
   </p>
   %s
   <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
   <p style="color: #666; font-size: 14px;">Manage reminders: <a href="%s">%s</a></p>
-  <p style="color: #666; font-size: 14px;">Unsubscribe: <a href="%s">%s</a></p>
-  <p style="color: #999; font-size: 12px;">Year of Bingo - yearofbingo.com</p>
+  %s<p style="color: #666; font-size: 14px;">Unsubscribe: <a href="%s">%s</a></p>
+  <p style="color: #999; font-size: 12px;">%s</p>
 </body>
 </html>`,
+		brand.logoHTML(),
+		templateEscape(brand.name()),
 		templateEscape(cardName),
 		templateEscape(progress),
+		deltaHTML,
+		memoryHTML,
 		imageBlock,
 		safeCardURL,
+		brand.accent(reminderEmailAccent),
 		recommendationHTML,
 		safeManageURL,
 		safeManageURL,
+		preferencesHTML,
 		safeUnsubscribe,
 		safeUnsubscribe,
+		brand.footerHTML(),
 	)
 
 	text := fmt.Sprintf(`%s
 %s
 
-Open my card: %s
+%s%sOpen my card: %s
 
 %sManage reminders: %s
-Unsubscribe: %s
+%sUnsubscribe: %s
 
 --
-Year of Bingo
-yearofbingo.com`,
-		cardName,
+%s`,
+		isolateBidi(cardName),
 		progress,
+		deltaText,
+		memoryText,
 		cardURL,
 		recommendationText,
 		manageURL,
+		preferencesText,
 		unsubscribe,
+		brand.footerText(),
 	)
 
 	return subject, html, text
 }
 
+// checkinDeltaLine summarizes progress since the last check-in, e.g.
+// "Since last month: +3 completed, 1 new bingo".
+func checkinDeltaLine(delta *checkinDelta) string {
+	var parts []string
+	if n := len(delta.Completed); n > 0 {
+		parts = append(parts, fmt.Sprintf("+%d completed", n))
+	}
+	if delta.Uncompleted > 0 {
+		parts = append(parts, fmt.Sprintf("%d marked not done", delta.Uncompleted))
+	}
+	switch {
+	case delta.NewBingos == 1:
+		parts = append(parts, "1 new bingo")
+	case delta.NewBingos > 1:
+		parts = append(parts, fmt.Sprintf("%d new bingos", delta.NewBingos))
+	}
+	if len(parts) == 0 {
+		return "Since last month: no new completions"
+	}
+	return "Since last month: " + strings.Join(parts, ", ")
+}
+
+// memoryLine is the check-in email's "on this day" line, e.g.
+// "Last year you completed: Run a 5K".
+func memoryLine(memory *models.Memory) string {
+	if memory.YearsAgo <= 1 {
+		return "Last year you completed: " + memory.Content
+	}
+	return fmt.Sprintf("%d years ago you completed: %s", memory.YearsAgo, memory.Content)
+}
+
+// cardTimeLeft describes how much of the card's period remains, e.g.
+// "4 months left on this card".
+func cardTimeLeft(card *models.BingoCard, now time.Time) string {
+	if card.IsOverdue(now) {
+		return "this card's period has ended"
+	}
+	if months := card.MonthsRemaining(now); months > 0 {
+		if months == 1 {
+			return "1 month left on this card"
+		}
+		return fmt.Sprintf("%d months left on this card", months)
+	}
+	days := card.DaysRemaining(now)
+	if days == 1 {
+		return "1 day left on this card"
+	}
+	return fmt.Sprintf("%d days left on this card", days)
+}
+
 func buildGoalReminderEmail(params goalReminderEmailParams) (string, string, string) {
 	cardName := cardDisplayName(params.CardTitle, &params.CardYear)
 	goalText := templateEscape(params.GoalText)
 	manageURL := fmt.Sprintf("%s/profile", params.BaseURL)
 	goalURL := fmt.Sprintf("%s/card/%s?item=%s", params.BaseURL, params.CardID, params.ItemID)
+	if params.LinkURL != "" {
+		goalURL = params.LinkURL
+	}
 	unsubscribe := params.UnsubscribeURL
 	safeManageURL := templateEscape(manageURL)
 	safeGoalURL := templateEscape(goalURL)
 	safeUnsubscribe := templateEscape(unsubscribe)
+	preferencesHTML, preferencesText := emailPreferencesLines(params.PreferencesURL)
+	snoozeHTML, snoozeText := snoozeLines(params.SnoozeURL)
 
 	subject := sanitizeSubject(fmt.Sprintf("Reminder: %s", params.GoalText))
+	brand := emailBrand(params.Brand)
 
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html>
@@ -148,48 +268,78 @@ func buildGoalReminderEmail(params goalReminderEmailParams) (string, string, str
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
 </head>
 <body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 640px; margin: 0 auto; padding: 24px;">
-  <h1 style="color: #333; font-size: 24px;">Year of Bingo</h1>
+  %s<h1 style="color: #333; font-size: 24px;">%s</h1>
   <p style="font-size: 16px;">%s</p>
   <p style="color: #666;">Card: %s</p>
   <p>
-    <a href="%s" style="display: inline-block; background: #0f6f62; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">Open this goal</a>
+    <a href="%s" style="display: inline-block; background: %s; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">Open this goal</a>
   </p>
-  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
+  %s<hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
   <p style="color: #666; font-size: 14px;">Manage reminders: <a href="%s">%s</a></p>
-  <p style="color: #666; font-size: 14px;">Unsubscribe: <a href="%s">%s</a></p>
-  <p style="color: #999; font-size: 12px;">Year of Bingo - yearofbingo.com</p>
+  %s<p style="color: #666; font-size: 14px;">Unsubscribe: <a href="%s">%s</a></p>
+  <p style="color: #999; font-size: 12px;">%s</p>
 </body>
 </html>`,
+		brand.logoHTML(),
+		templateEscape(brand.name()),
 		goalText,
 		templateEscape(cardName),
 		safeGoalURL,
+		brand.accent(reminderEmailAccent),
+		snoozeHTML,
 		safeManageURL,
 		safeManageURL,
+		preferencesHTML,
 		safeUnsubscribe,
 		safeUnsubscribe,
+		brand.footerHTML(),
 	)
 
 	text := fmt.Sprintf(`%s
 Card: %s
 
 Open this goal: %s
-
+%s
 Manage reminders: %s
-Unsubscribe: %s
+%sUnsubscribe: %s
 
 --
-Year of Bingo
-yearofbingo.com`,
-		params.GoalText,
-		cardName,
+%s`,
+		isolateBidi(params.GoalText),
+		isolateBidi(cardName),
 		goalURL,
+		snoozeText,
 		manageURL,
+		preferencesText,
 		unsubscribe,
+		brand.footerText(),
 	)
 
 	return subject, html, text
 }
 
+// snoozeLines renders the goal reminder's snooze link, or nothing when no
+// link was minted.
+func snoozeLines(snoozeURL string) (string, string) {
+	if snoozeURL == "" {
+		return "", ""
+	}
+	safeURL := templateEscape(snoozeURL)
+	html := fmt.Sprintf("<p style=\"color: #666; font-size: 14px;\">Not now? <a href=\"%s\">Snooze this reminder</a></p>\n  ", safeURL)
+	return html, fmt.Sprintf("Not now? Snooze this reminder: %s\n", snoozeURL)
+}
+
+// emailPreferencesLines renders the footer link to the tokenized email
+// preferences page, or nothing when no link was minted.
+func emailPreferencesLines(preferencesURL string) (string, string) {
+	if preferencesURL == "" {
+		return "", ""
+	}
+	safeURL := templateEscape(preferencesURL)
+	html := fmt.Sprintf("<p style=\"color: #666; font-size: 14px;\">Email preferences: <a href=\"%s\">%s</a></p>\n  ", safeURL, safeURL)
+	return html, fmt.Sprintf("Email preferences: %s\n", preferencesURL)
+}
+
 func pluralizeBingo(count int) string {
 	if count == 1 {
 		return "1 bingo"
@@ -207,8 +357,64 @@ func sanitizeSubject(subject string) string {
 		return r
 	}, cleaned)
 	cleaned = strings.TrimSpace(cleaned)
-	if len(cleaned) > 120 {
-		cleaned = cleaned[:117] + "..."
+	if runes := []rune(cleaned); len(runes) > 120 {
+		cleaned = string(runes[:117]) + "..."
 	}
 	return cleaned
 }
+
+// isolateBidi wraps user text containing right-to-left characters in Unicode
+// first-strong isolate marks so it cannot reorder the surrounding labels and
+// punctuation of a plaintext email line.
+func isolateBidi(text string) string {
+	if !containsRTL(text) {
+		return text
+	}
+	return "\u2068" + text + "\u2069"
+}
+
+// buildDeliverabilityProbeEmail renders the minimal email sent by a
+// self-serve deliverability check.
+func buildDeliverabilityProbeEmail(baseURL string, brandCfg config.BrandingConfig) (string, string, string) {
+	manageURL := fmt.Sprintf("%s/profile", baseURL)
+	safeManageURL := templateEscape(manageURL)
+	brand := emailBrand(brandCfg)
+
+	subject := sanitizeSubject(brand.name() + " email check")
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 640px; margin: 0 auto; padding: 24px;">
+  %s<h1 style="color: #333; font-size: 24px;">%s</h1>
+  <p style="font-size: 16px;">This is the test email you asked for. If you can read it, reminder emails can reach you.</p>
+  <p style="color: #666;">If it landed in spam, mark it as not spam so future reminders arrive in your inbox.</p>
+  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
+  <p style="color: #666; font-size: 14px;">Manage reminders: <a href="%s">%s</a></p>
+  <p style="color: #999; font-size: 12px;">%s</p>
+</body>
+</html>`,
+		brand.logoHTML(),
+		templateEscape(brand.name()),
+		safeManageURL,
+		safeManageURL,
+		brand.footerHTML(),
+	)
+
+	text := fmt.Sprintf(`This is the test email you asked for. If you can read it, reminder emails can reach you.
+
+If it landed in spam, mark it as not spam so future reminders arrive in your inbox.
+
+Manage reminders: %s
+
+--
+%s`,
+		manageURL,
+		brand.footerText(),
+	)
+
+	return subject, html, text
+}
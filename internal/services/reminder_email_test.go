@@ -0,0 +1,383 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestBuildGoalReminderEmail_IsolatesRTLTextInPlaintext(t *testing.T) {
+	title := "שנת היעדים"
+	_, _, text := buildGoalReminderEmail(goalReminderEmailParams{
+		CardID:    uuid.New(),
+		ItemID:    uuid.New(),
+		CardTitle: &title,
+		CardYear:  2025,
+		GoalText:  "לרוץ 5 ק\"מ",
+		BaseURL:   "https://example.com",
+	})
+
+	if !strings.HasPrefix(text, "\u2068לרוץ 5 ק\"מ\u2069\n") {
+		t.Fatalf("expected isolated goal text on first line, got %q", strings.SplitN(text, "\n", 2)[0])
+	}
+	if !strings.Contains(text, "Card: \u2068שנת היעדים\u2069\n") {
+		t.Fatalf("expected isolated card name, got %q", text)
+	}
+}
+
+func TestBuildGoalReminderEmail_LeavesLTRTextUntouched(t *testing.T) {
+	_, _, text := buildGoalReminderEmail(goalReminderEmailParams{
+		CardID:   uuid.New(),
+		ItemID:   uuid.New(),
+		CardYear: 2025,
+		GoalText: "Run a marathon",
+		BaseURL:  "https://example.com",
+	})
+	if strings.ContainsAny(text, "\u2068\u2069") {
+		t.Fatalf("expected no isolate marks for LTR text, got %q", text)
+	}
+}
+
+func TestBuildGoalReminderEmail_LinksPreferencesPage(t *testing.T) {
+	params := goalReminderEmailParams{
+		CardID:         uuid.New(),
+		ItemID:         uuid.New(),
+		CardYear:       2025,
+		GoalText:       "Run a marathon",
+		BaseURL:        "https://example.com",
+		UnsubscribeURL: "https://example.com/r/unsubscribe?token=u",
+		PreferencesURL: "https://example.com/r/preferences?token=p&x=1",
+	}
+	_, html, text := buildGoalReminderEmail(params)
+	if !strings.Contains(html, `Email preferences: <a href="https://example.com/r/preferences?token=p&amp;x=1">`) {
+		t.Fatalf("expected escaped preferences link in html, got %q", html)
+	}
+	if !strings.Contains(text, "Email preferences: https://example.com/r/preferences?token=p&x=1\nUnsubscribe:") {
+		t.Fatalf("expected preferences line before unsubscribe, got %q", text)
+	}
+
+	params.PreferencesURL = ""
+	_, html, text = buildGoalReminderEmail(params)
+	if strings.Contains(html, "Email preferences") || strings.Contains(text, "Email preferences") {
+		t.Fatal("expected no preferences line without a link")
+	}
+}
+
+func TestBuildGoalReminderEmail_LinksSnoozePage(t *testing.T) {
+	params := goalReminderEmailParams{
+		CardID:    uuid.New(),
+		ItemID:    uuid.New(),
+		CardYear:  2025,
+		GoalText:  "Run a marathon",
+		BaseURL:   "https://example.com",
+		SnoozeURL: "https://example.com/r/snooze?token=s&x=1",
+	}
+	_, html, text := buildGoalReminderEmail(params)
+	if !strings.Contains(html, `<a href="https://example.com/r/snooze?token=s&amp;x=1">Snooze this reminder</a>`) {
+		t.Fatalf("expected escaped snooze link in html, got %q", html)
+	}
+	if !strings.Contains(text, "Not now? Snooze this reminder: https://example.com/r/snooze?token=s&x=1\n") {
+		t.Fatalf("expected snooze line in text, got %q", text)
+	}
+
+	params.SnoozeURL = ""
+	_, html, text = buildGoalReminderEmail(params)
+	if strings.Contains(html, "Snooze") || strings.Contains(text, "Snooze") {
+		t.Fatal("expected no snooze line without a link")
+	}
+}
+
+func TestSanitizeSubject_TruncatesOnRuneBoundaries(t *testing.T) {
+	subject := sanitizeSubject("Reminder: " + strings.Repeat("שלום ", 40))
+	if !utf8.ValidString(subject) {
+		t.Fatalf("expected valid UTF-8 subject, got %q", subject)
+	}
+	if got := utf8.RuneCountInString(subject); got != 120 {
+		t.Fatalf("expected 120 characters, got %d", got)
+	}
+	if !strings.HasSuffix(subject, "...") {
+		t.Fatalf("expected ellipsis, got %q", subject)
+	}
+}
+
+func TestBuildCheckinEmail_ShowsTimeLeftOnCard(t *testing.T) {
+	card := &models.BingoCard{
+		ID:        uuid.New(),
+		Year:      2025,
+		StartDate: time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, time.August, 31, 0, 0, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{time.Date(2026, time.May, 1, 9, 0, 0, 0, time.UTC), "4 months left on this card"},
+		{time.Date(2026, time.August, 30, 9, 0, 0, 0, time.UTC), "2 days left on this card"},
+		{time.Date(2026, time.September, 2, 9, 0, 0, 0, time.UTC), "this card's period has ended"},
+	}
+	for _, tt := range tests {
+		_, html, text := buildCheckinEmail(checkinEmailParams{
+			Card:    card,
+			Stats:   reminderStats{Completed: 3, Total: 24},
+			BaseURL: "https://example.com",
+			Now:     tt.now,
+		})
+		if !strings.Contains(text, tt.want) {
+			t.Fatalf("expected text to contain %q, got %q", tt.want, text)
+		}
+		if !strings.Contains(html, templateEscape(tt.want)) {
+			t.Fatalf("expected html to contain %q", tt.want)
+		}
+	}
+}
+
+func TestBuildCheckinEmail_IncludesMemory(t *testing.T) {
+	card := &models.BingoCard{ID: uuid.New(), Year: 2026}
+	tests := []struct {
+		memory *models.Memory
+		want   string
+	}{
+		{&models.Memory{Content: "Run a <5K>", YearsAgo: 1}, "Last year you completed: Run a <5K>"},
+		{&models.Memory{Content: "Learn to juggle", YearsAgo: 3}, "3 years ago you completed: Learn to juggle"},
+	}
+	for _, tt := range tests {
+		_, html, text := buildCheckinEmail(checkinEmailParams{
+			Card:    card,
+			Memory:  tt.memory,
+			BaseURL: "https://example.com",
+		})
+		if !strings.Contains(text, tt.want) {
+			t.Fatalf("expected text to contain %q, got %q", tt.want, text)
+		}
+		if !strings.Contains(html, templateEscape(tt.want)) {
+			t.Fatalf("expected escaped memory in html, got %q", html)
+		}
+	}
+
+	_, _, text := buildCheckinEmail(checkinEmailParams{Card: card, BaseURL: "https://example.com"})
+	if strings.Contains(text, "you completed") {
+		t.Fatalf("expected no memory line, got %q", text)
+	}
+}
+
+func TestBuildCheckinEmail_ProgressSinceLastCheckin(t *testing.T) {
+	card := &models.BingoCard{ID: uuid.New(), Year: 2026, GridSize: 2}
+	items := []models.BingoItem{
+		{ID: uuid.New(), Position: 0, Content: "Run a <5K>"},
+		{ID: uuid.New(), Position: 1, Content: "Read 12 books"},
+		{ID: uuid.New(), Position: 2, Content: "Learn to juggle"},
+		{ID: uuid.New(), Position: 3, Content: "Bake bread"},
+	}
+	complete := func(positions ...int) []models.BingoItem {
+		out := append([]models.BingoItem(nil), items...)
+		for _, pos := range positions {
+			out[pos].IsCompleted = true
+		}
+		return out
+	}
+	snapshot := func(positions ...int) []byte {
+		raw, err := json.Marshal(newCheckinSnapshot(card, complete(positions...)))
+		if err != nil {
+			t.Fatalf("encode snapshot: %v", err)
+		}
+		return raw
+	}
+
+	tests := []struct {
+		name     string
+		snapshot []byte
+		now      []models.BingoItem
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "first send shows totals only",
+			snapshot: nil,
+			now:      complete(0, 1),
+			want:     []string{"2/4 complete"},
+			notWant:  []string{"Since last month"},
+		},
+		{
+			name:     "no change",
+			snapshot: snapshot(0),
+			now:      complete(0),
+			want:     []string{"Since last month: no new completions"},
+			notWant:  []string{"- Run a <5K>"},
+		},
+		{
+			name:     "new completions and bingo",
+			snapshot: snapshot(),
+			now:      complete(0, 3),
+			want:     []string{"Since last month: +2 completed, 1 new bingo", "- Run a <5K>\n- Bake bread"},
+		},
+		{
+			name:     "regression",
+			snapshot: snapshot(0, 1),
+			now:      complete(1),
+			want:     []string{"1/4 complete - 0 bingos", "Since last month: 1 marked not done\n"},
+			notWant:  []string{"completed,", "new bingo", "- Read 12 books"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := buildReminderStats(card, tt.now)
+			_, html, text := buildCheckinEmail(checkinEmailParams{
+				Card:    card,
+				Stats:   stats,
+				Delta:   checkinProgressDelta(tt.snapshot, tt.now, stats),
+				BaseURL: "https://example.com",
+			})
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Fatalf("expected text to contain %q, got %q", want, text)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(text, notWant) {
+					t.Fatalf("expected text not to contain %q, got %q", notWant, text)
+				}
+			}
+			if strings.Contains(html, "Run a <5K>") {
+				t.Fatalf("expected goals to be escaped in html, got %q", html)
+			}
+		})
+	}
+}
+
+func TestCheckinProgressDelta_IgnoresBadSnapshot(t *testing.T) {
+	if got := checkinProgressDelta([]byte(`not json`), nil, reminderStats{}); got != nil {
+		t.Fatalf("expected no delta for an unreadable snapshot, got %+v", got)
+	}
+}
+
+func TestBuildReminderEmails_LinkURLReplacesCardLink(t *testing.T) {
+	card := &models.BingoCard{ID: uuid.New(), Year: 2026}
+	tracked := "https://example.com/r/go/abc123"
+
+	_, html, text := buildCheckinEmail(checkinEmailParams{Card: card, BaseURL: "https://example.com", LinkURL: tracked})
+	if !strings.Contains(html, tracked) || !strings.Contains(text, tracked) {
+		t.Fatalf("expected tracked check-in link, got %q", text)
+	}
+	if strings.Contains(text, "/card/"+card.ID.String()) {
+		t.Fatalf("expected direct card link to be replaced, got %q", text)
+	}
+
+	_, html, text = buildGoalReminderEmail(goalReminderEmailParams{CardID: card.ID, ItemID: uuid.New(), CardYear: 2026, GoalText: "Run", BaseURL: "https://example.com", LinkURL: tracked})
+	if !strings.Contains(html, tracked) || !strings.Contains(text, tracked) {
+		t.Fatalf("expected tracked goal link, got %q", text)
+	}
+}
+
+func TestBuildCheckinEmail_Branding(t *testing.T) {
+	card := &models.BingoCard{ID: uuid.New(), Year: 2025, GridSize: 5}
+
+	subject, html, text := buildCheckinEmail(checkinEmailParams{Card: card, BaseURL: "https://example.com"})
+	if subject != "Your Year of Bingo check-in" {
+		t.Fatalf("unexpected default subject %q", subject)
+	}
+	if !strings.Contains(html, "background: #0f6f62;") || !strings.Contains(html, "Year of Bingo - yearofbingo.com") || strings.Contains(html, "<img") {
+		t.Fatalf("expected stock chrome, got %q", html)
+	}
+	if !strings.HasSuffix(text, "--\nYear of Bingo\nyearofbingo.com") {
+		t.Fatalf("expected stock text footer, got %q", text)
+	}
+
+	subject, html, text = buildCheckinEmail(checkinEmailParams{
+		Card:    card,
+		BaseURL: "https://example.com",
+		IsTest:  true,
+		Brand: config.BrandingConfig{
+			Name:         "Acme <Goals>",
+			LogoURL:      "https://cdn.example.com/logo.png?a=1&b=2",
+			PrimaryColor: "#123abc",
+		},
+	})
+	if subject != "Your Acme <Goals> check-in (test)" {
+		t.Fatalf("unexpected branded subject %q", subject)
+	}
+	for _, want := range []string{
+		`<img src="https://cdn.example.com/logo.png?a=1&amp;b=2" alt="Acme &lt;Goals&gt;"`,
+		`>Acme &lt;Goals&gt;</h1>`,
+		"background: #123abc;",
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected %q in html, got %q", want, html)
+		}
+	}
+	if strings.Contains(html, "yearofbingo.com") || !strings.HasSuffix(text, "--\nAcme <Goals>") {
+		t.Fatalf("expected branded footer, got %q", text)
+	}
+}
+
+// Plaintext-only recipients get the text part alone, so it has to carry every
+// link the HTML part does.
+func TestEmailBuilders_TextPartStandsAlone(t *testing.T) {
+	base := "https://example.com"
+	unsubscribe := base + "/r/unsubscribe?token=unsub"
+	preferences := base + "/r/preferences?token=prefs"
+	card := &models.BingoCard{ID: uuid.New(), Year: 2026, GridSize: 3, StartDate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)}
+	cardID, itemID, friendshipID := uuid.New(), uuid.New(), uuid.New()
+	actor, title, year := "bob", "Fitness", 2026
+	notifications := NewNotificationService(&fakeDB{}, stubEmailService{}, base)
+
+	type built struct{ html, text string }
+	build := func(_, html, text string) built { return built{html, text} }
+
+	cases := map[string]struct {
+		email built
+		links []string
+	}{
+		"check-in": {
+			build(buildCheckinEmail(checkinEmailParams{Card: card, BaseURL: base, UnsubscribeURL: unsubscribe, PreferencesURL: preferences, Now: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)})),
+			[]string{base + "/card/" + card.ID.String(), base + "/profile", preferences, unsubscribe},
+		},
+		"goal reminder": {
+			build(buildGoalReminderEmail(goalReminderEmailParams{CardID: cardID, ItemID: itemID, CardYear: 2026, GoalText: "Run", BaseURL: base, UnsubscribeURL: unsubscribe, PreferencesURL: preferences})),
+			[]string{base + "/card/" + cardID.String() + "?item=" + itemID.String(), base + "/profile", preferences, unsubscribe},
+		},
+		"deliverability probe": {
+			build(buildDeliverabilityProbeEmail(base, config.BrandingConfig{})),
+			[]string{base + "/profile"},
+		},
+		"friend bingo": {
+			build(notifications.buildNotificationEmail(models.NotificationTypeFriendBingo, &actor, &friendshipID, &title, &year, nil, preferences)),
+			[]string{base + "/friend-card/" + friendshipID.String(), base + "/notifications", base + "/profile", preferences},
+		},
+		"friend new card": {
+			build(notifications.buildNotificationEmail(models.NotificationTypeFriendNewCard, &actor, &friendshipID, &title, &year, nil, preferences)),
+			[]string{base + "/friend-card/" + friendshipID.String(), base + "/profile", preferences},
+		},
+		"friend request": {
+			build(notifications.buildNotificationEmail(models.NotificationTypeFriendRequestReceived, &actor, &friendshipID, nil, nil, nil, preferences)),
+			[]string{base + "/friends", base + "/profile", preferences},
+		},
+		"friends digest": {
+			build(buildFriendsDigestEmail([]friendActivity{{Username: "bob", Completed: 2}}, base, unsubscribe, preferences, config.BrandingConfig{})),
+			[]string{base + "/friends", base + "/profile", preferences, unsubscribe},
+		},
+		"share progress": {
+			build(buildShareProgressEmail(&shareWatchCard{Name: "Fitness", GridSize: 3, Total: 9, Completed: 1}, []string{"Run"}, 0, base+"/s/share", base+"/r/watch/unsubscribe?token=w", config.BrandingConfig{})),
+			[]string{base + "/s/share", base + "/r/watch/unsubscribe?token=w"},
+		},
+	}
+	for name, tc := range cases {
+		for _, link := range tc.links {
+			if !strings.Contains(tc.email.text, link) {
+				t.Errorf("%s: expected text part to contain %s, got %q", name, link, tc.email.text)
+			}
+			if !strings.Contains(tc.email.html, templateEscape(link)) {
+				t.Errorf("%s: expected HTML part to contain %s", name, link)
+			}
+		}
+		if strings.Contains(tc.email.text, "<a ") || strings.Contains(tc.email.text, "</") {
+			t.Errorf("%s: expected no markup in the text part, got %q", name, tc.email.text)
+		}
+	}
+}
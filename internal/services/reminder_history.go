@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/pagination"
+)
+
+// reminderHistoryRetention matches how long CleanupOld keeps reminder_email_log
+// rows.
+const reminderHistoryRetention = 90 * 24 * time.Hour
+
+var reminderHistoryOrder = pagination.NewOrder("l.sent_at", "l.id")
+
+// ReminderHistoryParams pages through a user's reminder email history, newest
+// first. After is the position of the previous page's last entry.
+type ReminderHistoryParams struct {
+	Limit int
+	After *pagination.Key
+}
+
+// GetEmailHistory returns a page of the user's reminder sends and the
+// click-through of their tracked links.
+func (s *ReminderService) GetEmailHistory(ctx context.Context, userID uuid.UUID, params ReminderHistoryParams) (*models.ReminderEmailHistory, error) {
+	entries, hasMore, err := s.ListEmailHistory(ctx, userID, params)
+	if err != nil {
+		return nil, err
+	}
+	clickThrough, err := s.reminderClickThrough(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	history := &models.ReminderEmailHistory{Items: entries, ClickThrough: clickThrough}
+	history.HasMore = hasMore
+	return history, nil
+}
+
+// ListEmailHistory returns the user's reminder email log from the retention
+// window with the card and goal each email was about, and whether older
+// entries follow.
+func (s *ReminderService) ListEmailHistory(ctx context.Context, userID uuid.UUID, params ReminderHistoryParams) ([]models.ReminderHistoryEntry, bool, error) {
+	limit := params.Limit
+	if limit <= 0 || limit > pagination.MaxLimit {
+		limit = pagination.DefaultLimit
+	}
+
+	conditions := []string{"l.user_id = $1", "l.sent_at >= $2"}
+	args := []any{userID, s.now().Add(-reminderHistoryRetention)}
+	idx := 3
+	if params.After != nil {
+		condition, keyArgs := reminderHistoryOrder.After(*params.After, idx)
+		conditions = append(conditions, condition)
+		args = append(args, keyArgs...)
+		idx += len(keyArgs)
+	}
+
+	query := fmt.Sprintf(
+		`SELECT l.id, l.source_type, c.id, c.title, c.year, i.content, l.status, l.sent_at
+		 FROM reminder_email_log l
+		 LEFT JOIN card_checkin_reminders cr ON l.source_type = 'card_checkin' AND cr.id = l.source_id
+		 LEFT JOIN goal_reminders gr ON l.source_type = 'goal_reminder' AND gr.id = l.source_id
+		 LEFT JOIN bingo_items i ON i.id = gr.item_id
+		 LEFT JOIN bingo_cards c ON c.id = COALESCE(cr.card_id, gr.card_id)
+		 WHERE %s
+		 ORDER BY %s
+		 LIMIT $%d`,
+		strings.Join(conditions, " AND "),
+		reminderHistoryOrder.OrderBy(),
+		idx,
+	)
+	args = append(args, limit+1)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("list reminder history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.ReminderHistoryEntry{}
+	for rows.Next() {
+		var entry models.ReminderHistoryEntry
+		if err := rows.Scan(&entry.ID, &entry.SourceType, &entry.CardID, &entry.CardTitle, &entry.CardYear, &entry.GoalText, &entry.Status, &entry.SentAt); err != nil {
+			return nil, false, fmt.Errorf("scan reminder history: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("list reminder history: %w", err)
+	}
+	entries, hasMore := pagination.Trim(entries, limit)
+	return entries, hasMore, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestReminderService_GetEmailHistory(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+	sentAt := now.Add(-24 * time.Hour)
+	cardID := uuid.New()
+	title := "Travel"
+	year := 2026
+	goal := "Visit Lisbon"
+
+	var querySQL string
+	var queryArgs []any
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "FROM reminder_link_tokens") {
+				t.Fatalf("unexpected query: %s", sql)
+			}
+			return rowFromValues(0, 0)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			querySQL, queryArgs = sql, args
+			return &fakeRows{rows: [][]any{
+				{uuid.New(), "goal_reminder", &cardID, &title, &year, &goal, "sent", sentAt.Add(-time.Hour)},
+				{uuid.New(), "friends_digest", (*uuid.UUID)(nil), (*string)(nil), (*int)(nil), (*string)(nil), "failed", sentAt.Add(-2 * time.Hour)},
+			}}, nil
+		},
+	}
+	svc := NewReminderService(db, nil, "https://example.com")
+	svc.now = func() time.Time { return now }
+
+	history, err := svc.GetEmailHistory(context.Background(), userID, ReminderHistoryParams{Limit: 500})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(querySQL, "provider_message_id") {
+		t.Fatalf("expected provider message IDs to stay private, got %q", querySQL)
+	}
+	if !strings.Contains(querySQL, "LIMIT $3") {
+		t.Fatalf("expected no cursor condition on the first page, got %q", querySQL)
+	}
+	if since := queryArgs[1].(time.Time); !since.Equal(now.AddDate(0, 0, -90)) {
+		t.Fatalf("expected 90-day window, got %v", since)
+	}
+	// One row past the page tells whether more follow.
+	if queryArgs[2] != 51 {
+		t.Fatalf("expected oversized limit to fall back to 50, got %v", queryArgs[2])
+	}
+	if len(history.Items) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(history.Items))
+	}
+	if entry := history.Items[0]; entry.CardTitle == nil || *entry.CardTitle != title || entry.GoalText == nil || *entry.GoalText != goal {
+		t.Fatalf("expected resolved card and goal, got %+v", entry)
+	}
+	if entry := history.Items[1]; entry.CardID != nil || entry.Status != "failed" {
+		t.Fatalf("unexpected digest entry: %+v", entry)
+	}
+	if history.ClickThrough == nil || history.ClickThrough.Tracked != 0 || history.ClickThrough.Rate != 0 {
+		t.Fatalf("expected zero click-through without tracked links, got %+v", history.ClickThrough)
+	}
+}
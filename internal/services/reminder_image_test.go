@@ -9,12 +9,18 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
 )
 
 func TestReminderService_RenderImageByToken_SuccessAndExpiry(t *testing.T) {
 	now := time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC)
-	userID := uuid.New()
-	cardID := uuid.New()
+	title := "Card"
+	card := testutil.NewTestCard(testutil.WithGridSize(2), testutil.WithoutFreeSpace())
+	card.Title = &title
+	userID, cardID := card.UserID, card.ID
 	token := "tok"
 
 	db := &fakeDB{
@@ -36,26 +42,9 @@ func TestReminderService_RenderImageByToken_SuccessAndExpiry(t *testing.T) {
 				expiresAt := now.Add(time.Hour)
 				createdAt := now.Add(-time.Hour)
 				var lastAccessedAt *time.Time
-				return rowFromValues(token, userID, cardID, true, expiresAt, createdAt, lastAccessedAt, 0)
+				return rowFromValues(token, userID, cardID, true, expiresAt, createdAt, lastAccessedAt, 0, (*int)(nil))
 			case strings.Contains(sql, "FROM bingo_cards WHERE id"):
-				title := "Card"
-				return rowFromValues(
-					cardID,
-					userID,
-					2025,
-					nil,
-					&title,
-					2,
-					"BI",
-					false,
-					nil,
-					true,
-					true,
-					false,
-					false,
-					now.Add(-2*time.Hour),
-					now.Add(-time.Hour),
-				)
+				return rowFromValues(card.Row()...)
 			default:
 				return fakeRow{scanFunc: func(dest ...any) error { return errors.New("unexpected query") }}
 			}
@@ -65,7 +54,7 @@ func TestReminderService_RenderImageByToken_SuccessAndExpiry(t *testing.T) {
 	svc := NewReminderService(db, nil, "http://example.com")
 	svc.now = func() time.Time { return now }
 
-	pngBytes, err := svc.RenderImageByToken(context.Background(), token)
+	pngBytes, err := svc.RenderImageByToken(context.Background(), token, "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -78,11 +67,11 @@ func TestReminderService_RenderImageByToken_SuccessAndExpiry(t *testing.T) {
 		if strings.Contains(sql, "FROM reminder_image_tokens") {
 			expiresAt := now.Add(-time.Minute)
 			createdAt := now.Add(-time.Hour)
-			return rowFromValues(token, userID, cardID, false, expiresAt, createdAt, (*time.Time)(nil), 0)
+			return rowFromValues(token, userID, cardID, false, expiresAt, createdAt, (*time.Time)(nil), 0, (*int)(nil))
 		}
 		return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
 	}
-	_, err = svc.RenderImageByToken(context.Background(), token)
+	_, err = svc.RenderImageByToken(context.Background(), token, "")
 	if !errors.Is(err, ErrReminderNotFound) {
 		t.Fatalf("expected ErrReminderNotFound, got %v", err)
 	}
@@ -106,7 +95,7 @@ func TestReminderService_CreateImageToken_CreatesWhenMissing(t *testing.T) {
 	}
 
 	svc := NewReminderService(db, nil, "http://example.com")
-	token, err := svc.createImageToken(context.Background(), userID, cardID, true)
+	token, err := svc.createImageToken(context.Background(), userID, cardID, true, models.ReminderImageTokenReuse)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -126,7 +115,7 @@ func TestReminderService_UnsubscribeByToken_AlreadyUsed(t *testing.T) {
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			if strings.Contains(sql, "FROM reminder_unsubscribe_tokens") {
-				return rowFromValues(userID, now.Add(time.Hour), &usedAt)
+				return rowFromValues(userID, now.Add(time.Hour), &usedAt, "reminders")
 			}
 			return rowFromValues(false)
 		},
@@ -143,3 +132,227 @@ func TestReminderService_UnsubscribeByToken_AlreadyUsed(t *testing.T) {
 		t.Fatal("expected alreadyDisabled=true for used token")
 	}
 }
+
+func TestReminderService_CreateImageToken_PerEmailMintsCappedToken(t *testing.T) {
+	now := time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	cardID := uuid.New()
+
+	var inserts [][]any
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			t.Fatalf("per-email mode should not look up existing tokens: %q", sql)
+			return nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if !strings.Contains(sql, "INSERT INTO reminder_image_tokens") {
+				t.Fatalf("unexpected exec sql: %q", sql)
+			}
+			inserts = append(inserts, args)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	svc := NewReminderService(db, nil, "http://example.com")
+	svc.now = func() time.Time { return now }
+	svc.SetImageTokenPolicy(3*24*time.Hour, 5)
+
+	first, err := svc.createImageToken(context.Background(), userID, cardID, true, models.ReminderImageTokenPerEmail)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := svc.createImageToken(context.Background(), userID, cardID, true, models.ReminderImageTokenPerEmail)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first == second || len(inserts) != 2 {
+		t.Fatalf("expected a fresh token per email, got %q and %q (%d inserts)", first, second, len(inserts))
+	}
+	if expiresAt, _ := inserts[0][4].(time.Time); !expiresAt.Equal(now.Add(3 * 24 * time.Hour)) {
+		t.Fatalf("expected per-email TTL, got %v", inserts[0][4])
+	}
+	if maxAccess, _ := inserts[0][5].(*int); maxAccess == nil || *maxAccess != 5 {
+		t.Fatalf("expected access cap 5, got %#v", inserts[0][5])
+	}
+}
+
+func TestReminderService_CreateImageToken_ReuseSkipsCappedTokens(t *testing.T) {
+	now := time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC)
+	var refreshedTo time.Time
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if !strings.Contains(sql, "max_access_count IS NULL") {
+				t.Fatalf("expected reuse lookup to ignore per-email tokens: %q", sql)
+			}
+			return rowFromValues("existing")
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE reminder_image_tokens SET expires_at") {
+				refreshedTo, _ = args[0].(time.Time)
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	svc := NewReminderService(db, nil, "http://example.com")
+	svc.now = func() time.Time { return now }
+
+	token, err := svc.createImageToken(context.Background(), uuid.New(), uuid.New(), true, models.ReminderImageTokenReuse)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "existing" || !refreshedTo.Equal(now.Add(14*24*time.Hour)) {
+		t.Fatalf("expected existing token refreshed to 14 days, got %q %v", token, refreshedTo)
+	}
+}
+
+func TestReminderService_RenderImageByToken_AccessCapServesPlaceholder(t *testing.T) {
+	now := time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC)
+	maxAccess := 3
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM reminder_image_tokens") {
+				return rowFromValues("tok", uuid.New(), uuid.New(), true, now.Add(time.Hour), now.Add(-time.Hour), &now, 3, &maxAccess)
+			}
+			t.Fatalf("expected no card lookup once the cap is reached: %q", sql)
+			return nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatalf("expected no access recorded once the cap is reached: %q", sql)
+			return fakeCommandTag{}, nil
+		},
+	}
+
+	svc := NewReminderService(db, nil, "http://example.com")
+	svc.now = func() time.Time { return now }
+
+	pngBytes, err := svc.RenderImageByToken(context.Background(), "tok", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	placeholder, err := RenderReminderPlaceholderPNG(config.DefaultBrandName)
+	if err != nil {
+		t.Fatalf("render placeholder: %v", err)
+	}
+	if string(pngBytes) != string(placeholder) {
+		t.Fatal("expected the placeholder image")
+	}
+}
+
+func TestReminderService_RevokeImageTokens(t *testing.T) {
+	userID := uuid.New()
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if sql != "DELETE FROM reminder_image_tokens WHERE user_id = $1" || args[0] != userID {
+				t.Fatalf("unexpected exec: %q %v", sql, args)
+			}
+			return fakeCommandTag{rowsAffected: 4}, nil
+		},
+	}
+
+	svc := NewReminderService(db, nil, "http://example.com")
+	revoked, err := svc.RevokeImageTokens(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if revoked != 4 {
+		t.Fatalf("expected 4 revoked tokens, got %d", revoked)
+	}
+}
+
+func TestReminderService_UpdateSettings_ImageTokenMode(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	var updatedMode any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "SET image_token_mode") {
+				updatedMode = args[0]
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(userID, false, 3, models.ReminderImageTokenPerEmail, "light", "UTC", false, "08:00", (*time.Time)(nil), now, now, nil)
+		},
+	}
+	svc := NewReminderService(db, nil, "http://example.com")
+
+	invalid := "forever"
+	if _, err := svc.UpdateSettings(context.Background(), userID, models.ReminderSettingsPatch{ImageTokenMode: &invalid}); !errors.Is(err, ErrInvalidImageTokenMode) {
+		t.Fatalf("expected ErrInvalidImageTokenMode, got %v", err)
+	}
+
+	mode := models.ReminderImageTokenPerEmail
+	settings, err := svc.UpdateSettings(context.Background(), userID, models.ReminderSettingsPatch{ImageTokenMode: &mode})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updatedMode != mode || settings.ImageTokenMode != mode {
+		t.Fatalf("expected mode %q stored and returned, got %v / %q", mode, updatedMode, settings.ImageTokenMode)
+	}
+}
+
+func TestReminderService_UpdateSettings_ImageTheme(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	var updatedTheme any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "SET image_theme") {
+				updatedTheme = args[0]
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(userID, false, 3, models.ReminderImageTokenReuse, models.ImageThemeDark, "UTC", false, "08:00", (*time.Time)(nil), now, now, nil)
+		},
+	}
+	svc := NewReminderService(db, nil, "http://example.com")
+
+	invalid := "sepia"
+	if _, err := svc.UpdateSettings(context.Background(), userID, models.ReminderSettingsPatch{ImageTheme: &invalid}); !errors.Is(err, ErrInvalidImageTheme) {
+		t.Fatalf("expected ErrInvalidImageTheme, got %v", err)
+	}
+	if updatedTheme != nil {
+		t.Fatalf("expected nothing stored for an invalid theme, got %v", updatedTheme)
+	}
+
+	theme := models.ImageThemeDark
+	settings, err := svc.UpdateSettings(context.Background(), userID, models.ReminderSettingsPatch{ImageTheme: &theme})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updatedTheme != theme || settings.ImageTheme != theme {
+		t.Fatalf("expected theme %q stored and returned, got %v / %q", theme, updatedTheme, settings.ImageTheme)
+	}
+}
+
+func TestReminderService_ComposeCheckinEmail_LinksImageInUserTheme(t *testing.T) {
+	card := testutil.NewTestCard(testutil.WithGridSize(3), testutil.WithoutFreeSpace(), testutil.WithItems(2))
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM reminder_image_tokens") {
+				return rowFromValues("tok123")
+			}
+			return rowFromValues(false)
+		},
+	}
+	svc := NewReminderService(db, nil, "http://example.com")
+
+	for theme, want := range map[string]string{
+		models.ImageThemeLight: `src="http://example.com/r/img/tok123.png"`,
+		models.ImageThemeDark:  `src="http://example.com/r/img/tok123.png?theme=dark"`,
+	} {
+		job := checkinJob{UserID: card.UserID, CardID: card.ID, IncludeImage: true, ImageTheme: theme}
+		_, html, _, err := svc.composeCheckinEmail(context.Background(), job, &card.BingoCard, card.Items, nil, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(html, want) {
+			t.Fatalf("%s: expected the image linked as %s, got %q", theme, want, html)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// reminderLink is the tracked "Open my card" link of one reminder email. An
+// empty url means the email links straight to the card.
+type reminderLink struct {
+	token string
+	url   string
+}
+
+func checkinLinkPath(cardID uuid.UUID) string {
+	return fmt.Sprintf("/card/%s", cardID)
+}
+
+func goalLinkPath(cardID, itemID uuid.UUID) string {
+	return fmt.Sprintf("/card/%s?item=%s", cardID, itemID)
+}
+
+// createReminderLink mints a click-through token for one reminder email. It
+// expires with the email's image token, and nothing is minted for users with
+// data minimization on. Tracking never blocks a send, so failures only fall
+// back to the untracked link.
+func (s *ReminderService) createReminderLink(ctx context.Context, userID uuid.UUID, sourceType string, sourceID uuid.UUID, targetPath string) reminderLink {
+	var minimized bool
+	var mode string
+	err := s.db.QueryRow(ctx, `
+		SELECT u.data_minimization, COALESCE(rs.image_token_mode, $2)
+		  FROM users u
+		  LEFT JOIN reminder_settings rs ON rs.user_id = u.id
+		 WHERE u.id = $1`,
+		userID, models.ReminderImageTokenReuse,
+	).Scan(&minimized, &mode)
+	if err != nil {
+		logging.Warn("Failed to load reminder link tracking settings", map[string]interface{}{"user_id": userID.String(), "error": err.Error()})
+		return reminderLink{}
+	}
+	if minimized {
+		return reminderLink{}
+	}
+
+	ttl := reuseImageTokenTTL
+	if mode == models.ReminderImageTokenPerEmail {
+		ttl = s.perEmailTokenTTL
+	}
+	token, err := randomToken(24)
+	if err != nil {
+		return reminderLink{}
+	}
+	if _, err := s.db.Exec(ctx, `
+		INSERT INTO reminder_link_tokens (token, user_id, source_type, source_id, target_path, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		token, userID, sourceType, sourceID, targetPath, s.now().Add(ttl),
+	); err != nil {
+		logging.Warn("Failed to create reminder link token", map[string]interface{}{"user_id": userID.String(), "error": err.Error()})
+		return reminderLink{}
+	}
+	return reminderLink{token: token, url: fmt.Sprintf("%s/r/go/%s", s.baseURL, token)}
+}
+
+// discardReminderLink drops the token of an email that was never sent so it
+// doesn't count toward click-through.
+func (s *ReminderService) discardReminderLink(ctx context.Context, link reminderLink) {
+	if link.token == "" {
+		return
+	}
+	if _, err := s.db.Exec(ctx, "DELETE FROM reminder_link_tokens WHERE token = $1", link.token); err != nil {
+		logging.Warn("Failed to discard reminder link token", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// FollowReminderLink records the first click on a reminder email link and
+// returns the URL it points to. Expired links still resolve until cleanup
+// but aren't counted, and neither are clicks by users who have since turned
+// data minimization on.
+func (s *ReminderService) FollowReminderLink(ctx context.Context, token string) (string, error) {
+	var target string
+	var count bool
+	err := s.db.QueryRow(ctx, `
+		SELECT l.target_path, l.expires_at > NOW() AND NOT u.data_minimization
+		  FROM reminder_link_tokens l
+		  JOIN users u ON u.id = l.user_id
+		 WHERE l.token = $1`,
+		token,
+	).Scan(&target, &count)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrReminderNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("load reminder link: %w", err)
+	}
+
+	if count {
+		if _, err := s.db.Exec(ctx,
+			"UPDATE reminder_link_tokens SET clicked_at = NOW() WHERE token = $1 AND clicked_at IS NULL",
+			token,
+		); err != nil {
+			logging.Warn("Failed to record reminder link click", map[string]interface{}{"error": err.Error()})
+		}
+	}
+	return s.baseURL + target, nil
+}
+
+// reminderClickThrough counts the user's link tokens, which cleanup removes
+// once they expire, and how many of them were clicked.
+func (s *ReminderService) reminderClickThrough(ctx context.Context, userID uuid.UUID) (*models.ReminderClickThrough, error) {
+	clickThrough := &models.ReminderClickThrough{}
+	if err := s.db.QueryRow(ctx,
+		"SELECT COUNT(*), COUNT(clicked_at) FROM reminder_link_tokens WHERE user_id = $1",
+		userID,
+	).Scan(&clickThrough.Tracked, &clickThrough.Clicked); err != nil {
+		return nil, fmt.Errorf("count reminder link clicks: %w", err)
+	}
+	if clickThrough.Tracked > 0 {
+		clickThrough.Rate = float64(clickThrough.Clicked) / float64(clickThrough.Tracked)
+	}
+	return clickThrough, nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
+)
+
+func TestReminderService_CreateReminderLink(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name      string
+		minimized bool
+		mode      string
+		wantTTL   time.Duration
+	}{
+		{name: "reuse mode", mode: models.ReminderImageTokenReuse, wantTTL: reuseImageTokenTTL},
+		{name: "per-email mode", mode: models.ReminderImageTokenPerEmail, wantTTL: 48 * time.Hour},
+		{name: "data minimization", minimized: true, mode: models.ReminderImageTokenReuse},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var sqlLog testutil.SQLLog
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					return rowFromValues(tc.minimized, tc.mode)
+				},
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					sqlLog.Record(sql, args...)
+					return fakeCommandTag{rowsAffected: 1}, nil
+				},
+			}
+			svc := NewReminderService(db, nil, "https://example.com/")
+			svc.now = func() time.Time { return now }
+			svc.SetImageTokenPolicy(48*time.Hour, 0)
+
+			cardID := uuid.New()
+			link := svc.createReminderLink(context.Background(), uuid.New(), "card_checkin", uuid.New(), checkinLinkPath(cardID))
+			if tc.minimized {
+				if link.url != "" {
+					t.Fatalf("expected untracked link, got %q", link.url)
+				}
+				sqlLog.AssertNotCalled(t, "INSERT INTO reminder_link_tokens")
+				return
+			}
+			if link.url != "https://example.com/r/go/"+link.token || link.token == "" {
+				t.Fatalf("unexpected link: %+v", link)
+			}
+			call := sqlLog.AssertCalled(t, "INSERT INTO reminder_link_tokens")
+			if call.Args[4] != "/card/"+cardID.String() {
+				t.Fatalf("expected card target path, got %v", call.Args[4])
+			}
+			if expiresAt := call.Args[5].(time.Time); !expiresAt.Equal(now.Add(tc.wantTTL)) {
+				t.Fatalf("expected expiry %v, got %v", now.Add(tc.wantTTL), expiresAt)
+			}
+		})
+	}
+}
+
+func TestReminderService_CreateReminderLink_LookupFailureFallsBack(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return errors.New("db down") }}
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatalf("unexpected exec: %s", sql)
+			return nil, nil
+		},
+	}
+	svc := NewReminderService(db, nil, "https://example.com")
+	if link := svc.createReminderLink(context.Background(), uuid.New(), "goal_reminder", uuid.New(), "/card/x"); link != (reminderLink{}) {
+		t.Fatalf("expected untracked link, got %+v", link)
+	}
+}
+
+func TestReminderService_FollowReminderLink(t *testing.T) {
+	cases := []struct {
+		name       string
+		countable  bool
+		wantRecord bool
+	}{
+		{name: "records first click", countable: true, wantRecord: true},
+		{name: "expired or minimized is not counted", countable: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var sqlLog testutil.SQLLog
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					return rowFromValues("/card/abc?item=def", tc.countable)
+				},
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					sqlLog.Record(sql, args...)
+					return fakeCommandTag{rowsAffected: 1}, nil
+				},
+			}
+			svc := NewReminderService(db, nil, "https://example.com")
+
+			target, err := svc.FollowReminderLink(context.Background(), "tok")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if target != "https://example.com/card/abc?item=def" {
+				t.Fatalf("unexpected target %q", target)
+			}
+			if tc.wantRecord {
+				call := sqlLog.AssertCalled(t, "SET clicked_at = NOW()")
+				if !strings.Contains(call.SQL, "clicked_at IS NULL") {
+					t.Fatalf("expected only the first click to be stored, got %q", call.SQL)
+				}
+			} else {
+				sqlLog.AssertNotCalled(t, "SET clicked_at")
+			}
+		})
+	}
+}
+
+func TestReminderService_FollowReminderLink_NotFound(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	svc := NewReminderService(db, nil, "https://example.com")
+	if _, err := svc.FollowReminderLink(context.Background(), "missing"); !errors.Is(err, ErrReminderNotFound) {
+		t.Fatalf("expected ErrReminderNotFound, got %v", err)
+	}
+}
@@ -55,7 +55,7 @@ func TestReminderService_UpdateCheckinAfterSend_WrapsTxErrors(t *testing.T) {
 	}
 
 	svc := NewReminderService(&fakeDB{}, nil, "http://example.com")
-	err := svc.updateCheckinAfterSend(context.Background(), tx, reminderID, sentAt, next)
+	err := svc.updateCheckinAfterSend(context.Background(), tx, reminderID, sentAt, next, []byte(`[]`), []byte(`{}`))
 	if err == nil || !strings.Contains(err.Error(), "update card checkin") {
 		t.Fatalf("expected wrapped error, got %v", err)
 	}
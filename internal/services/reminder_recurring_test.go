@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
+)
+
+func TestParseRecurringSchedule(t *testing.T) {
+	for _, input := range []models.GoalReminderScheduleInput{
+		{EveryDays: 0, Time: "09:00"},
+		{EveryDays: maxRecurringEveryDays + 1, Time: "09:00"},
+		{EveryDays: 7},
+		{EveryDays: 7, Time: "9am"},
+	} {
+		if _, err := parseRecurringSchedule(input); !errors.Is(err, ErrInvalidSchedule) {
+			t.Fatalf("%+v: expected ErrInvalidSchedule, got %v", input, err)
+		}
+	}
+	got, err := parseRecurringSchedule(models.GoalReminderScheduleInput{EveryDays: 7, Time: "09:00", SendAt: "ignored"})
+	if err != nil || got != (recurringSchedule{EveryDays: 7, Time: "09:00"}) {
+		t.Fatalf("unexpected schedule %+v, %v", got, err)
+	}
+}
+
+func TestRecurringSend_FirstAndNext(t *testing.T) {
+	schedule := recurringSchedule{EveryDays: 3, Time: "09:00"}
+
+	first, _ := firstRecurringSend(time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC), schedule)
+	if want := time.Date(2026, time.January, 10, 9, 0, 0, 0, time.UTC); !first.Equal(want) {
+		t.Fatalf("expected later today %v, got %v", want, first)
+	}
+	first, _ = firstRecurringSend(time.Date(2026, time.January, 10, 9, 0, 0, 0, time.UTC), schedule)
+	if want := time.Date(2026, time.January, 11, 9, 0, 0, 0, time.UTC); !first.Equal(want) {
+		t.Fatalf("expected tomorrow %v, got %v", want, first)
+	}
+
+	// A send pushed to 11:00 by the daily cap still lands on 09:00 next time.
+	next, _ := nextRecurringSend(time.Date(2026, time.January, 11, 11, 0, 0, 0, time.UTC), schedule)
+	if want := time.Date(2026, time.January, 14, 9, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+}
+
+func TestReminderService_UpsertGoalReminder_Recurring(t *testing.T) {
+	card := testutil.NewTestCard(testutil.WithItems(1))
+	userID, cardID, itemID := card.UserID, card.ID, card.Items[0].ID
+	fixedNow := time.Date(2026, time.January, 10, 10, 0, 0, 0, time.UTC)
+
+	var insertArgs []any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM bingo_items i"):
+				return rowFromValues(cardID, false, true, false)
+			case strings.Contains(sql, "SELECT timezone"):
+				return rowFromValues("UTC")
+			}
+			insertArgs = args
+			fixture := testutil.NewTestGoalReminder(card, card.Items[0], args[5].(time.Time))
+			fixture.Kind = args[3].(string)
+			fixture.Schedule = args[4].([]byte)
+			return rowFromValues(fixture.Row()...)
+		},
+	}
+	svc := NewReminderService(db, nil, "http://example.com")
+	svc.now = func() time.Time { return fixedNow }
+
+	reminder, err := svc.UpsertGoalReminder(context.Background(), userID, models.GoalReminderInput{
+		ItemID:   itemID,
+		Kind:     models.GoalReminderKindRecurring,
+		Schedule: models.GoalReminderScheduleInput{EveryDays: 7, Time: "09:00"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reminder.Kind != models.GoalReminderKindRecurring {
+		t.Fatalf("expected recurring kind, got %q", reminder.Kind)
+	}
+	var stored recurringSchedule
+	if err := json.Unmarshal(insertArgs[4].([]byte), &stored); err != nil || stored != (recurringSchedule{EveryDays: 7, Time: "09:00"}) {
+		t.Fatalf("unexpected stored schedule %s (%v)", insertArgs[4], err)
+	}
+	if want := time.Date(2026, time.January, 11, 9, 0, 0, 0, time.UTC); !reminder.NextSendAt.Equal(want) {
+		t.Fatalf("expected first send %v, got %v", want, reminder.NextSendAt)
+	}
+
+	if _, err := svc.UpsertGoalReminder(context.Background(), userID, models.GoalReminderInput{
+		ItemID: itemID,
+		Kind:   "hourly",
+	}); !errors.Is(err, ErrInvalidSchedule) {
+		t.Fatalf("expected ErrInvalidSchedule for unknown kind, got %v", err)
+	}
+}
+
+func TestReminderService_ProcessGoalReminder_Recurring(t *testing.T) {
+	now := time.Date(2026, time.January, 11, 9, 1, 0, 0, time.UTC)
+	schedule := []byte(`{"every_days":7,"time":"09:00"}`)
+
+	run := func(t *testing.T, completed bool, sentToday int) (reminderOutcome, []string, []any) {
+		t.Helper()
+		opts := []testutil.CardOption{testutil.WithItems(1)}
+		if completed {
+			opts = append(opts, testutil.WithCompletedItems(1))
+		}
+		card := testutil.NewTestCard(opts...)
+		db := &fakeDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				switch {
+				case strings.Contains(sql, "FROM bingo_items"):
+					return rowFromValues(testutil.GoalReminderContextRow(card, card.Items[0], "user@test.com", 2)...)
+				case strings.Contains(sql, "FROM reminder_email_log"):
+					return rowFromValues(sentToday)
+				}
+				return rowFromValues(0)
+			},
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+				return fakeCommandTag{rowsAffected: 1}, nil
+			},
+		}
+		var updates []string
+		var lastArgs []any
+		tx := &fakeTx{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				return rowFromValues(uuid.New())
+			},
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+				if strings.HasPrefix(sql, "UPDATE goal_reminders") && !strings.Contains(sql, "SET claimed_at") {
+					updates = append(updates, sql)
+					lastArgs = args
+				}
+				return fakeCommandTag{rowsAffected: 1}, nil
+			},
+		}
+		email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, body string) error {
+			return nil
+		}}
+		db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
+		svc := NewReminderService(db, email, "http://example.com")
+		outcome, err := svc.processGoalReminder(context.Background(), goalReminderJob{
+			ID:         uuid.New(),
+			UserID:     card.UserID,
+			ItemID:     card.Items[0].ID,
+			Kind:       models.GoalReminderKindRecurring,
+			Schedule:   schedule,
+			NextSendAt: now.Add(-time.Minute),
+		}, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return outcome, updates, lastArgs
+	}
+
+	t.Run("sends and schedules the next one", func(t *testing.T) {
+		outcome, updates, args := run(t, false, 0)
+		if outcome != reminderSent || len(updates) != 1 || strings.Contains(updates[0], "enabled = false") {
+			t.Fatalf("expected a send that keeps the reminder enabled, got %v", updates)
+		}
+		if want := time.Date(2026, time.January, 18, 9, 0, 0, 0, time.UTC); !args[1].(time.Time).Equal(want) {
+			t.Fatalf("expected next send %v, got %v", want, args[1])
+		}
+	})
+
+	t.Run("completed goal disables", func(t *testing.T) {
+		outcome, updates, _ := run(t, true, 0)
+		if outcome != reminderSkipped || len(updates) != 1 || !strings.Contains(updates[0], "enabled = false") {
+			t.Fatalf("expected the reminder disabled without a send, got %v", updates)
+		}
+	})
+
+	t.Run("daily cap defers", func(t *testing.T) {
+		outcome, updates, args := run(t, false, 2)
+		if outcome != reminderDeferred || len(updates) != 1 || strings.Contains(updates[0], "enabled = false") {
+			t.Fatalf("expected a deferral without a send, got %v", updates)
+		}
+		if want := time.Date(2026, time.January, 12, 9, 0, 0, 0, time.UTC); !args[0].(time.Time).Equal(want) {
+			t.Fatalf("expected deferral to %v, got %v", want, args[0])
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// ValidateSchedule previews a check-in or goal reminder schedule without
+// saving anything. It runs the same parsing and next-send code as saving the
+// reminder, in the user's reminder time zone, so the preview matches what a
+// save would store. The next send is returned in UTC like every other API
+// timestamp; Timezone says which zone the schedule was read in. A check-in's jitter offset is only picked on save, so a
+// schedule with a jitter window sends up to that many minutes either side of
+// the previewed time. Invalid schedules are reported in the result; the error
+// is only for failures loading the time zone.
+func (s *ReminderService) ValidateSchedule(ctx context.Context, userID uuid.UUID, input models.ReminderScheduleValidationInput) (*models.ReminderScheduleValidation, error) {
+	loc, err := s.loadLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.ReminderScheduleValidation{Timezone: loc.String()}
+
+	var scheduleJSON []byte
+	var nextSendAt time.Time
+	switch input.Type {
+	case models.ReminderScheduleTypeCheckin:
+		scheduleJSON, nextSendAt, err = s.previewCheckinSchedule(input, loc)
+	case models.ReminderScheduleTypeGoal:
+		scheduleJSON, nextSendAt, err = s.previewGoalSchedule(input, loc)
+	default:
+		result.ErrorCode = models.ReminderScheduleErrorInvalidType
+		return result, nil
+	}
+	switch {
+	case errors.Is(err, errSendAtInPast):
+		result.ErrorCode = models.ReminderScheduleErrorSendAtInPast
+		return result, nil
+	case errors.Is(err, ErrInvalidSchedule):
+		result.ErrorCode = models.ReminderScheduleErrorInvalidSchedule
+		return result, nil
+	case err != nil:
+		return nil, err
+	}
+
+	nextSendAt = nextSendAt.UTC()
+	result.Valid = true
+	result.NormalizedSchedule = scheduleJSON
+	result.NextSendAt = &nextSendAt
+	return result, nil
+}
+
+func (s *ReminderService) previewCheckinSchedule(input models.ReminderScheduleValidationInput, loc *time.Location) ([]byte, time.Time, error) {
+	if _, err := normalizeCheckinFrequency(input.Frequency); err != nil {
+		return nil, time.Time{}, err
+	}
+	var payload models.CardCheckinSchedulePayload
+	if err := json.Unmarshal(input.Schedule, &payload); err != nil {
+		return nil, time.Time{}, ErrInvalidSchedule
+	}
+	schedule, nextSendAt, err := s.checkinSchedule(payload, s.now().In(loc), false)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	scheduleJSON, err := json.Marshal(schedule)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("encode schedule: %w", err)
+	}
+	return scheduleJSON, nextSendAt, nil
+}
+
+func (s *ReminderService) previewGoalSchedule(input models.ReminderScheduleValidationInput, loc *time.Location) ([]byte, time.Time, error) {
+	kind, err := normalizeGoalReminderKind(input.Kind)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var payload models.GoalReminderScheduleInput
+	if err := json.Unmarshal(input.Schedule, &payload); err != nil {
+		return nil, time.Time{}, ErrInvalidSchedule
+	}
+	return s.goalReminderScheduleIn(loc, kind, payload)
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestReminderService_ValidateSchedule(t *testing.T) {
+	newYork := loadNewYork(t)
+	fixedNow := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name       string
+		input      models.ReminderScheduleValidationInput
+		wantCode   string
+		wantNext   time.Time
+		wantStored string
+	}{
+		{
+			name:       "checkin",
+			input:      models.ReminderScheduleValidationInput{Type: "checkin", Schedule: json.RawMessage(`{"day_of_month":31,"time":"09:00","jitter_window_minutes":15}`)},
+			wantNext:   time.Date(2026, time.March, 28, 9, 0, 0, 0, newYork),
+			wantStored: `{"day_of_month":28,"time":"09:00","jitter_window_minutes":15}`,
+		},
+		{
+			name:     "checkin with unknown frequency",
+			input:    models.ReminderScheduleValidationInput{Type: "checkin", Frequency: "weekly", Schedule: json.RawMessage(`{"day_of_month":1,"time":"09:00"}`)},
+			wantCode: models.ReminderScheduleErrorInvalidSchedule,
+		},
+		{
+			name:     "checkin with bad time",
+			input:    models.ReminderScheduleValidationInput{Type: "checkin", Schedule: json.RawMessage(`{"day_of_month":1,"time":"9am"}`)},
+			wantCode: models.ReminderScheduleErrorInvalidSchedule,
+		},
+		{
+			name:       "one-time goal in local time",
+			input:      models.ReminderScheduleValidationInput{Type: "goal", Schedule: json.RawMessage(`{"send_at":"2026-03-02T08:30"}`)},
+			wantNext:   time.Date(2026, time.March, 2, 8, 30, 0, 0, newYork),
+			wantStored: `{"send_at":"2026-03-02T13:30:00Z"}`,
+		},
+		{
+			name:     "one-time goal in the past",
+			input:    models.ReminderScheduleValidationInput{Type: "goal", Kind: "one_time", Schedule: json.RawMessage(`{"send_at":"2026-02-01T08:30:00Z"}`)},
+			wantCode: models.ReminderScheduleErrorSendAtInPast,
+		},
+		{
+			name:       "recurring goal",
+			input:      models.ReminderScheduleValidationInput{Type: "goal", Kind: "recurring", Schedule: json.RawMessage(`{"every_days":3,"time":"06:00"}`)},
+			wantNext:   time.Date(2026, time.March, 2, 6, 0, 0, 0, newYork),
+			wantStored: `{"every_days":3,"time":"06:00"}`,
+		},
+		{
+			name:     "recurring goal without interval",
+			input:    models.ReminderScheduleValidationInput{Type: "goal", Kind: "recurring", Schedule: json.RawMessage(`{"time":"06:00"}`)},
+			wantCode: models.ReminderScheduleErrorInvalidSchedule,
+		},
+		{
+			name:     "missing schedule",
+			input:    models.ReminderScheduleValidationInput{Type: "goal"},
+			wantCode: models.ReminderScheduleErrorInvalidSchedule,
+		},
+		{
+			name:     "unknown type",
+			input:    models.ReminderScheduleValidationInput{Type: "digest"},
+			wantCode: models.ReminderScheduleErrorInvalidType,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &fakeDB{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if !strings.Contains(sql, "SELECT timezone") {
+						t.Fatalf("unexpected query: %s", sql)
+					}
+					return rowFromValues("America/New_York")
+				},
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					t.Fatalf("unexpected write: %s", sql)
+					return nil, nil
+				},
+			}
+			svc := NewReminderService(db, nil, "http://example.com")
+			svc.now = func() time.Time { return fixedNow }
+
+			result, err := svc.ValidateSchedule(context.Background(), uuid.New(), tc.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Timezone != "America/New_York" {
+				t.Fatalf("expected the user's time zone, got %q", result.Timezone)
+			}
+			if tc.wantCode != "" {
+				if result.Valid || result.ErrorCode != tc.wantCode || result.NextSendAt != nil {
+					t.Fatalf("expected error code %q, got %+v", tc.wantCode, result)
+				}
+				return
+			}
+			if !result.Valid || result.ErrorCode != "" {
+				t.Fatalf("expected a valid schedule, got %+v", result)
+			}
+			if !result.NextSendAt.Equal(tc.wantNext) || result.NextSendAt.Location() != time.UTC {
+				t.Fatalf("expected next send %v, got %v", tc.wantNext, result.NextSendAt)
+			}
+			if string(result.NormalizedSchedule) != tc.wantStored {
+				t.Fatalf("expected schedule %s, got %s", tc.wantStored, result.NormalizedSchedule)
+			}
+		})
+	}
+}
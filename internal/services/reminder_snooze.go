@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// snoozeTokenTTL matches the lifetime of unsubscribe tokens. Tokens are also
+// deleted with their reminder.
+const snoozeTokenTTL = 30 * 24 * time.Hour
+
+// createSnoozeURL mints the single-use "Snooze" link of one goal reminder
+// email.
+func (s *ReminderService) createSnoozeURL(ctx context.Context, userID, reminderID uuid.UUID) (string, error) {
+	token, err := randomToken(24)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.db.Exec(ctx,
+		"INSERT INTO reminder_snooze_tokens (token, user_id, reminder_id, expires_at) VALUES ($1, $2, $3, $4)",
+		token,
+		userID,
+		reminderID,
+		s.now().Add(snoozeTokenTTL),
+	)
+	if err != nil {
+		return "", fmt.Errorf("create snooze token: %w", err)
+	}
+	return fmt.Sprintf("%s/r/snooze?token=%s", s.baseURL, token), nil
+}
+
+// SnoozeGoalReminderByToken moves the token's goal reminder to days from now,
+// turning it back on if it was disabled after sending. It reports whether the
+// token was already used, in which case nothing changes, so a second click
+// doesn't push the reminder out again.
+func (s *ReminderService) SnoozeGoalReminderByToken(ctx context.Context, token string, days int) (bool, error) {
+	if !slices.Contains(models.GoalReminderSnoozeDays, days) {
+		return false, ErrInvalidSnoozeDays
+	}
+
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return false, fmt.Errorf("begin snooze tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var reminderID uuid.UUID
+	var expiresAt time.Time
+	var usedAt *time.Time
+	var completed bool
+	err = tx.QueryRow(ctx, `
+		SELECT t.reminder_id, t.expires_at, t.used_at, i.is_completed
+		  FROM reminder_snooze_tokens t
+		  JOIN goal_reminders gr ON gr.id = t.reminder_id
+		  JOIN bingo_items i ON i.id = gr.item_id
+		 WHERE t.token = $1
+		   FOR UPDATE OF t`,
+		token,
+	).Scan(&reminderID, &expiresAt, &usedAt, &completed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrReminderNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("load snooze token: %w", err)
+	}
+	if usedAt != nil {
+		return true, nil
+	}
+	if expiresAt.Before(s.now()) {
+		return false, ErrReminderNotFound
+	}
+	if completed {
+		return false, ErrGoalCompleted
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE goal_reminders SET enabled = true, next_send_at = $1, updated_at = NOW() WHERE id = $2",
+		s.now().Add(time.Duration(days)*24*time.Hour),
+		reminderID,
+	); err != nil {
+		return false, fmt.Errorf("snooze goal reminder: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		"UPDATE reminder_snooze_tokens SET used_at = NOW() WHERE token = $1",
+		token,
+	); err != nil {
+		return false, fmt.Errorf("mark snooze token used: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit snooze: %w", err)
+	}
+	return false, nil
+}
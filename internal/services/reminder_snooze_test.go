@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
+)
+
+func TestReminderService_CreateSnoozeURL(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	var sqlLog testutil.SQLLog
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			sqlLog.Record(sql, args...)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	svc := NewReminderService(db, nil, "https://example.com")
+	svc.now = func() time.Time { return now }
+
+	reminderID := uuid.New()
+	url, err := svc.createSnoozeURL(context.Background(), uuid.New(), reminderID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(url, "https://example.com/r/snooze?token=") {
+		t.Fatalf("unexpected url %q", url)
+	}
+	call := sqlLog.AssertCalled(t, "INSERT INTO reminder_snooze_tokens")
+	if call.Args[2] != reminderID {
+		t.Fatalf("expected reminder id %v, got %v", reminderID, call.Args[2])
+	}
+	if expiresAt := call.Args[3].(time.Time); !expiresAt.Equal(now.Add(snoozeTokenTTL)) {
+		t.Fatalf("expected expiry %v, got %v", now.Add(snoozeTokenTTL), expiresAt)
+	}
+}
+
+func TestReminderService_SnoozeGoalReminderByToken(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	usedAt := now.Add(-time.Minute)
+	reminderID := uuid.New()
+	cases := []struct {
+		name        string
+		days        int
+		row         Row
+		wantErr     error
+		wantAlready bool
+		wantSnooze  bool
+	}{
+		{
+			name:       "snoozes and re-enables",
+			days:       7,
+			row:        rowFromValues(reminderID, now.Add(time.Hour), (*time.Time)(nil), false),
+			wantSnooze: true,
+		},
+		{
+			name:        "second click leaves the reminder alone",
+			days:        30,
+			row:         rowFromValues(reminderID, now.Add(time.Hour), &usedAt, false),
+			wantAlready: true,
+		},
+		{
+			name:    "expired token",
+			days:    1,
+			row:     rowFromValues(reminderID, now.Add(-time.Hour), (*time.Time)(nil), false),
+			wantErr: ErrReminderNotFound,
+		},
+		{
+			name:    "deleted reminder",
+			days:    1,
+			row:     fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }},
+			wantErr: ErrReminderNotFound,
+		},
+		{
+			name:    "completed goal",
+			days:    1,
+			row:     rowFromValues(reminderID, now.Add(time.Hour), (*time.Time)(nil), true),
+			wantErr: ErrGoalCompleted,
+		},
+		{
+			name:    "unsupported length",
+			days:    3,
+			wantErr: ErrInvalidSnoozeDays,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var sqlLog testutil.SQLLog
+			committed := false
+			tx := &fakeTx{
+				QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+					if !strings.Contains(sql, "FOR UPDATE OF t") {
+						t.Fatalf("expected the token row to be locked, got %s", sql)
+					}
+					return tc.row
+				},
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+					sqlLog.Record(sql, args...)
+					return fakeCommandTag{rowsAffected: 1}, nil
+				},
+				CommitFunc: func(ctx context.Context) error {
+					committed = true
+					return nil
+				},
+			}
+			db := &fakeDB{
+				BeginFunc: func(ctx context.Context) (Tx, error) {
+					if tc.row == nil {
+						t.Fatal("unexpected transaction")
+					}
+					return tx, nil
+				},
+			}
+			svc := NewReminderService(db, nil, "https://example.com")
+			svc.now = func() time.Time { return now }
+
+			already, err := svc.SnoozeGoalReminderByToken(context.Background(), "tok", tc.days)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if already != tc.wantAlready {
+				t.Fatalf("expected already snoozed %v, got %v", tc.wantAlready, already)
+			}
+			if !tc.wantSnooze {
+				sqlLog.AssertNotCalled(t, "UPDATE goal_reminders")
+				sqlLog.AssertNotCalled(t, "UPDATE reminder_snooze_tokens")
+				return
+			}
+			call := sqlLog.AssertCalled(t, "UPDATE goal_reminders SET enabled = true")
+			if want := now.Add(time.Duration(tc.days) * 24 * time.Hour); !call.Args[0].(time.Time).Equal(want) {
+				t.Fatalf("expected next send %v, got %v", want, call.Args[0])
+			}
+			if call.Args[1] != reminderID {
+				t.Fatalf("expected reminder %v, got %v", reminderID, call.Args[1])
+			}
+			sqlLog.AssertCalled(t, "UPDATE reminder_snooze_tokens SET used_at = NOW()")
+			if !committed {
+				t.Fatal("expected commit")
+			}
+		})
+	}
+}
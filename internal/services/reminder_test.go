@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
 )
 
 func TestNextMonthlySend_ClampsDay(t *testing.T) {
@@ -35,14 +38,46 @@ func TestNextMonthlySend_ClampsDay(t *testing.T) {
 	}
 }
 
-func TestReminderService_RunDue_UsesSkipLocked(t *testing.T) {
-	var queries []string
+func TestNextMonthlySend_AppliesJitterOffset(t *testing.T) {
+	after := time.Date(2025, time.January, 10, 8, 0, 0, 0, time.UTC)
+	next, err := nextMonthlySend(after, monthlySchedule{DayOfMonth: 10, Time: "09:00", JitterOffsetMinutes: -25})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := time.Date(2025, time.January, 10, 8, 35, 0, 0, time.UTC)
+	if !next.Equal(expected) {
+		t.Fatalf("expected %v, got %v", expected, next)
+	}
+
+	// A negative offset that lands before the after-time rolls to next month.
+	after = time.Date(2025, time.January, 10, 8, 40, 0, 0, time.UTC)
+	next, err = nextMonthlySend(after, monthlySchedule{DayOfMonth: 10, Time: "09:00", JitterOffsetMinutes: -25})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = time.Date(2025, time.February, 10, 8, 35, 0, 0, time.UTC)
+	if !next.Equal(expected) {
+		t.Fatalf("expected %v, got %v", expected, next)
+	}
+
+	next, err = nextMonthlySend(after, monthlySchedule{DayOfMonth: 10, Time: "09:00", JitterOffsetMinutes: 30})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = time.Date(2025, time.January, 10, 9, 30, 0, 0, time.UTC)
+	if !next.Equal(expected) {
+		t.Fatalf("expected %v, got %v", expected, next)
+	}
+}
+
+func TestReminderService_RunDue_LocksDigestsAndSkipsClaimedReminders(t *testing.T) {
+	var txQueries, dueQueries []string
 	beginCalls := 0
 	fakeBegin := func(ctx context.Context) (Tx, error) {
 		beginCalls++
 		return &fakeTx{
 			QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
-				queries = append(queries, sql)
+				txQueries = append(txQueries, sql)
 				return &fakeRows{rows: [][]any{}}, nil
 			},
 			CommitFunc:   func(ctx context.Context) error { return nil },
@@ -50,18 +85,21 @@ func TestReminderService_RunDue_UsesSkipLocked(t *testing.T) {
 		}, nil
 	}
 
-	db := &fakeDB{BeginFunc: fakeBegin}
+	db := &fakeDB{
+		BeginFunc: fakeBegin,
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			dueQueries = append(dueQueries, sql)
+			return &fakeRows{rows: [][]any{}}, nil
+		},
+	}
 	svc := NewReminderService(db, nil, "http://example.com")
 	if _, err := svc.RunDue(context.Background(), time.Now(), 5); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if beginCalls == 0 {
-		t.Fatal("expected transaction begin")
+	if beginCalls == 0 || len(txQueries) == 0 {
+		t.Fatal("expected the due digest query in a transaction")
 	}
-	if len(queries) == 0 {
-		t.Fatal("expected due reminder queries")
-	}
-	for _, query := range queries {
+	for _, query := range txQueries {
 		if !strings.Contains(query, "SKIP LOCKED") {
 			t.Fatalf("expected SKIP LOCKED in query, got %q", query)
 		}
@@ -69,29 +107,29 @@ func TestReminderService_RunDue_UsesSkipLocked(t *testing.T) {
 			t.Fatalf("expected FOR UPDATE OF <alias> to avoid locking join tables, got %q", query)
 		}
 	}
+	// Check-ins and goal reminders are claimed one at a time instead, so
+	// the due queries only skip reminders another runner holds.
+	if len(dueQueries) != 2 {
+		t.Fatalf("expected due check-in and goal reminder queries, got %d", len(dueQueries))
+	}
+	for _, query := range dueQueries {
+		if !strings.Contains(query, "claimed_at IS NULL OR") {
+			t.Fatalf("expected live claims to be skipped, got %q", query)
+		}
+	}
 }
 
 func TestReminderService_ProcessGoalReminder_DisablesCompleted(t *testing.T) {
-	userID := uuid.New()
-	cardID := uuid.New()
-	itemID := uuid.New()
+	card := testutil.NewTestCard(testutil.WithItems(1), testutil.WithCompletedItems(1))
+	item := card.Items[0]
+	userID, cardID, itemID := card.UserID, card.ID, item.ID
 	reminderID := uuid.New()
 
 	var disabled bool
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			if strings.Contains(sql, "FROM bingo_items") {
-				return rowFromValues(
-					cardID,
-					nil,
-					2025,
-					true,
-					false,
-					"Finish project",
-					true,
-					"user@test.com",
-					3,
-				)
+				return rowFromValues(testutil.GoalReminderContextRow(card, item, "user@test.com", 3)...)
 			}
 			return rowFromValues(0)
 		},
@@ -105,8 +143,9 @@ func TestReminderService_ProcessGoalReminder_DisablesCompleted(t *testing.T) {
 		},
 	}
 
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
 	svc := NewReminderService(db, nil, "http://example.com")
-	sent, err := svc.processGoalReminder(context.Background(), tx, goalReminderJob{
+	outcome, err := svc.processGoalReminder(context.Background(), goalReminderJob{
 		ID:     reminderID,
 		UserID: userID,
 		CardID: cardID,
@@ -116,7 +155,7 @@ func TestReminderService_ProcessGoalReminder_DisablesCompleted(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if sent {
+	if outcome == reminderSent {
 		t.Fatal("expected no email to be sent")
 	}
 	if !disabled {
@@ -138,7 +177,7 @@ func TestReminderService_GoalReminderCapReached(t *testing.T) {
 	}
 
 	svc := NewReminderService(db, nil, "http://example.com")
-	reached, err := svc.goalReminderCapReached(context.Background(), userID, now, 3)
+	reached, err := svc.goalReminderCapReached(context.Background(), userID, uuid.Nil, now, 3)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -147,40 +186,6 @@ func TestReminderService_GoalReminderCapReached(t *testing.T) {
 	}
 }
 
-func TestPickReminderRecommendations_ExcludesCompletedAndFree(t *testing.T) {
-	freePos := 4
-	items := []models.BingoItem{
-		{Position: 0, Content: "A", IsCompleted: true},
-		{Position: 1, Content: "B", IsCompleted: true},
-		{Position: 2, Content: "C"},
-		{Position: 3, Content: "D", IsCompleted: true},
-		{Position: 5, Content: "E", IsCompleted: true},
-		{Position: 6, Content: "F"},
-		{Position: 7, Content: "G", IsCompleted: true},
-		{Position: 8, Content: "H"},
-	}
-
-	recs := pickReminderRecommendations(items, 3, &freePos, 3)
-	if len(recs) < 3 {
-		t.Fatalf("expected at least 3 recommendations, got %d", len(recs))
-	}
-	for _, rec := range recs {
-		if rec.IsCompleted {
-			t.Fatalf("expected incomplete recommendation, got %+v", rec)
-		}
-		if rec.Position == freePos {
-			t.Fatalf("expected free position excluded, got %d", rec.Position)
-		}
-	}
-
-	expected := []int{2, 6, 8}
-	for i, pos := range expected {
-		if recs[i].Position != pos {
-			t.Fatalf("expected recommendation %d to be position %d, got %d", i, pos, recs[i].Position)
-		}
-	}
-}
-
 func TestReminderService_RenderImageByToken_MissingCardReturnsNotFound(t *testing.T) {
 	token := "abc123"
 	userID := uuid.New()
@@ -199,6 +204,7 @@ func TestReminderService_RenderImageByToken_MissingCardReturnsNotFound(t *testin
 					time.Now(),
 					(*time.Time)(nil),
 					0,
+					(*int)(nil),
 				)
 			}
 			if strings.Contains(sql, "FROM bingo_cards") {
@@ -209,7 +215,7 @@ func TestReminderService_RenderImageByToken_MissingCardReturnsNotFound(t *testin
 	}
 
 	svc := NewReminderService(db, nil, "http://example.com")
-	_, err := svc.RenderImageByToken(context.Background(), token)
+	_, err := svc.RenderImageByToken(context.Background(), token, "")
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -227,7 +233,7 @@ func TestReminderService_UnsubscribeByToken_ReturnsAlreadyDisabled(t *testing.T)
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			switch {
 			case strings.Contains(sql, "FROM reminder_unsubscribe_tokens"):
-				return rowFromValues(userID, expiresAt, (*time.Time)(nil))
+				return rowFromValues(userID, expiresAt, (*time.Time)(nil), "reminders")
 			case strings.Contains(sql, "SELECT email_enabled FROM reminder_settings"):
 				return rowFromValues(false)
 			default:
@@ -253,6 +259,46 @@ func TestReminderService_UnsubscribeByToken_ReturnsAlreadyDisabled(t *testing.T)
 	}
 }
 
+func TestReminderService_UnsubscribeByToken_FriendsDigestScope(t *testing.T) {
+	userID := uuid.New()
+	expiresAt := time.Now().Add(2 * time.Hour)
+
+	var execCalls []string
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "FROM reminder_unsubscribe_tokens"):
+				return rowFromValues(userID, expiresAt, (*time.Time)(nil), unsubscribeScopeFriendsDigest)
+			case strings.Contains(sql, "SELECT email_friends_digest FROM notification_settings"):
+				return rowFromValues(true)
+			default:
+				t.Fatalf("unexpected query: %s", sql)
+				return nil
+			}
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			execCalls = append(execCalls, sql)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	svc := NewReminderService(db, nil, "http://example.com")
+	alreadyDisabled, err := svc.UnsubscribeByToken(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if alreadyDisabled {
+		t.Fatal("expected alreadyDisabled=false")
+	}
+	joined := strings.Join(execCalls, "\n")
+	if !strings.Contains(joined, "email_friends_digest = false") {
+		t.Fatalf("expected digest to be disabled, got %v", execCalls)
+	}
+	if strings.Contains(joined, "reminder_settings") {
+		t.Fatalf("expected reminder settings untouched, got %v", execCalls)
+	}
+}
+
 func TestReminderService_CapQueriesCountOnlySent(t *testing.T) {
 	t.Run("checkin", func(t *testing.T) {
 		var got string
@@ -263,7 +309,7 @@ func TestReminderService_CapQueriesCountOnlySent(t *testing.T) {
 			},
 		}
 		svc := NewReminderService(db, nil, "http://example.com")
-		_, _ = svc.cardCheckinCapReached(context.Background(), uuid.New(), time.Now())
+		_, _ = svc.cardCheckinCapReached(context.Background(), uuid.New(), uuid.Nil, time.Now())
 		if !strings.Contains(got, "status = 'sent'") {
 			t.Fatalf("expected status filter in query, got %q", got)
 		}
@@ -278,7 +324,7 @@ func TestReminderService_CapQueriesCountOnlySent(t *testing.T) {
 			},
 		}
 		svc := NewReminderService(db, nil, "http://example.com")
-		_, _ = svc.goalReminderCapReached(context.Background(), uuid.New(), time.Now(), 3)
+		_, _ = svc.goalReminderCapReached(context.Background(), uuid.New(), uuid.Nil, time.Now(), 3)
 		if !strings.Contains(got, "status = 'sent'") {
 			t.Fatalf("expected status filter in query, got %q", got)
 		}
@@ -287,8 +333,8 @@ func TestReminderService_CapQueriesCountOnlySent(t *testing.T) {
 
 func TestReminderService_ProcessCheckin_CapReachedDefersToNextDay(t *testing.T) {
 	now := time.Date(2025, time.January, 2, 10, 0, 0, 0, time.UTC)
-	userID := uuid.New()
-	cardID := uuid.New()
+	card := testutil.NewTestCard()
+	userID, cardID := card.UserID, card.ID
 	reminderID := uuid.New()
 
 	db := &fakeDB{
@@ -305,23 +351,7 @@ func TestReminderService_ProcessCheckin_CapReachedDefersToNextDay(t *testing.T)
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			switch {
 			case strings.Contains(sql, "FROM bingo_cards"):
-				return rowFromValues(
-					cardID,
-					userID,
-					2025,
-					nil,
-					nil,
-					5,
-					"BINGO",
-					true,
-					nil,
-					true,
-					true,
-					true,
-					false,
-					now,
-					now,
-				)
+				return rowFromValues(card.Row()...)
 			case strings.Contains(sql, "FROM reminder_settings"):
 				return rowFromValues(userID)
 			default:
@@ -342,8 +372,9 @@ func TestReminderService_ProcessCheckin_CapReachedDefersToNextDay(t *testing.T)
 		},
 	}
 
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
 	svc := NewReminderService(db, nil, "http://example.com")
-	sent, err := svc.processCheckin(context.Background(), tx, checkinJob{
+	outcome, err := svc.processCheckin(context.Background(), checkinJob{
 		ID:         reminderID,
 		UserID:     userID,
 		CardID:     cardID,
@@ -354,7 +385,7 @@ func TestReminderService_ProcessCheckin_CapReachedDefersToNextDay(t *testing.T)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if sent {
+	if outcome == reminderSent {
 		t.Fatal("expected no email to be sent")
 	}
 
@@ -367,27 +398,46 @@ func TestReminderService_ProcessCheckin_CapReachedDefersToNextDay(t *testing.T)
 	}
 }
 
+func TestReminderService_DeferCheckinAfterCapReached_UsesJitteredTime(t *testing.T) {
+	now := time.Date(2025, time.January, 2, 10, 0, 0, 0, time.UTC)
+	reminderID := uuid.New()
+
+	var updatedTo time.Time
+	tx := &fakeTx{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE card_checkin_reminders SET next_send_at") {
+				updatedTo, _ = args[0].(time.Time)
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	svc := NewReminderService(&fakeDB{}, nil, "http://example.com")
+	err := svc.deferCheckinAfterCapReached(context.Background(), tx, checkinJob{
+		ID:       reminderID,
+		Schedule: []byte(`{"day_of_month":1,"time":"09:00","jitter_window_minutes":30,"jitter_offset_minutes":17}`),
+	}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := time.Date(2025, time.January, 3, 9, 17, 0, 0, time.UTC)
+	if !updatedTo.Equal(expected) {
+		t.Fatalf("expected deferred time %v, got %v", expected, updatedTo)
+	}
+}
+
 func TestReminderService_ProcessGoalReminder_CapReachedDefersToNextDay(t *testing.T) {
 	now := time.Date(2025, time.January, 2, 10, 0, 0, 0, time.UTC)
-	userID := uuid.New()
-	cardID := uuid.New()
-	itemID := uuid.New()
+	card := testutil.NewTestCard(testutil.WithItems(1))
+	item := card.Items[0]
+	userID, cardID, itemID := card.UserID, card.ID, item.ID
 	reminderID := uuid.New()
 
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			if strings.Contains(sql, "FROM bingo_items") {
-				return rowFromValues(
-					cardID,
-					nil,
-					2025,
-					true,
-					false,
-					"Finish project",
-					false,
-					"user@test.com",
-					3,
-				)
+				return rowFromValues(testutil.GoalReminderContextRow(card, item, "user@test.com", 3)...)
 			}
 			if strings.Contains(sql, "FROM reminder_email_log") {
 				return rowFromValues(3)
@@ -412,9 +462,10 @@ func TestReminderService_ProcessGoalReminder_CapReachedDefersToNextDay(t *testin
 		},
 	}
 
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
 	svc := NewReminderService(db, nil, "http://example.com")
 	base := time.Date(2025, time.January, 2, 9, 30, 0, 0, time.UTC)
-	sent, err := svc.processGoalReminder(context.Background(), tx, goalReminderJob{
+	outcome, err := svc.processGoalReminder(context.Background(), goalReminderJob{
 		ID:         reminderID,
 		UserID:     userID,
 		CardID:     cardID,
@@ -425,7 +476,7 @@ func TestReminderService_ProcessGoalReminder_CapReachedDefersToNextDay(t *testin
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if sent {
+	if outcome == reminderSent {
 		t.Fatal("expected no email to be sent")
 	}
 	expected := time.Date(2025, time.January, 3, 9, 30, 0, 0, time.UTC)
@@ -439,8 +490,8 @@ func TestReminderService_ProcessGoalReminder_CapReachedDefersToNextDay(t *testin
 
 func TestReminderService_ProcessCheckin_FailureDefers15MinutesAndDoesNotAdvanceSchedule(t *testing.T) {
 	now := time.Date(2025, time.January, 2, 10, 0, 0, 0, time.UTC)
-	userID := uuid.New()
-	cardID := uuid.New()
+	card := testutil.NewTestCard()
+	userID, cardID := card.UserID, card.ID
 	reminderID := uuid.New()
 
 	db := &fakeDB{
@@ -466,23 +517,7 @@ func TestReminderService_ProcessCheckin_FailureDefers15MinutesAndDoesNotAdvanceS
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			switch {
 			case strings.Contains(sql, "FROM bingo_cards"):
-				return rowFromValues(
-					cardID,
-					userID,
-					2025,
-					nil,
-					nil,
-					5,
-					"BINGO",
-					true,
-					nil,
-					true,
-					true,
-					true,
-					false,
-					now,
-					now,
-				)
+				return rowFromValues(card.Row()...)
 			case strings.Contains(sql, "FROM reminder_settings"):
 				return rowFromValues(userID)
 			default:
@@ -506,8 +541,19 @@ func TestReminderService_ProcessCheckin_FailureDefers15MinutesAndDoesNotAdvanceS
 		},
 	}
 
+	var notifiedCard uuid.UUID
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
 	svc := NewReminderService(db, nil, "http://example.com")
-	sent, err := svc.processCheckin(context.Background(), tx, checkinJob{
+	svc.SetNotificationService(&stubNotificationService{
+		NotifyReminderCheckinFunc: func(ctx context.Context, notifyTx Tx, notifyUser, notifyCard uuid.UUID, sentAt time.Time) error {
+			if notifyTx != tx || notifyUser != userID || !sentAt.Equal(now) {
+				t.Fatalf("unexpected notification call: %v %v", notifyUser, sentAt)
+			}
+			notifiedCard = notifyCard
+			return nil
+		},
+	})
+	outcome, err := svc.processCheckin(context.Background(), checkinJob{
 		ID:                     reminderID,
 		UserID:                 userID,
 		CardID:                 cardID,
@@ -520,9 +566,12 @@ func TestReminderService_ProcessCheckin_FailureDefers15MinutesAndDoesNotAdvanceS
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if sent {
+	if outcome == reminderSent {
 		t.Fatal("expected no email to be sent")
 	}
+	if notifiedCard != cardID {
+		t.Fatal("expected an in-app notification despite the failed email")
+	}
 	if sawAdvance {
 		t.Fatal("expected schedule not to advance on failure")
 	}
@@ -540,25 +589,15 @@ func TestReminderService_ProcessCheckin_FailureDefers15MinutesAndDoesNotAdvanceS
 
 func TestReminderService_ProcessGoalReminder_FailureDefers15MinutesAndDoesNotDisable(t *testing.T) {
 	now := time.Date(2025, time.January, 2, 10, 0, 0, 0, time.UTC)
-	userID := uuid.New()
-	cardID := uuid.New()
-	itemID := uuid.New()
+	card := testutil.NewTestCard(testutil.WithItems(1))
+	item := card.Items[0]
+	userID, cardID, itemID := card.UserID, card.ID, item.ID
 	reminderID := uuid.New()
 
 	db := &fakeDB{
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
 			if strings.Contains(sql, "FROM bingo_items") {
-				return rowFromValues(
-					cardID,
-					nil,
-					2025,
-					true,
-					false,
-					"Finish project",
-					false,
-					"user@test.com",
-					3,
-				)
+				return rowFromValues(testutil.GoalReminderContextRow(card, item, "user@test.com", 3)...)
 			}
 			if strings.Contains(sql, "FROM reminder_email_log") {
 				return rowFromValues(0)
@@ -595,8 +634,20 @@ func TestReminderService_ProcessGoalReminder_FailureDefers15MinutesAndDoesNotDis
 		},
 	}
 
+	var notifiedItem uuid.UUID
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
 	svc := NewReminderService(db, nil, "http://example.com")
-	sent, err := svc.processGoalReminder(context.Background(), tx, goalReminderJob{
+	svc.SetNotificationService(&stubNotificationService{
+		NotifyReminderGoalFunc: func(ctx context.Context, notifyTx Tx, notifyUser, notifyCard, notifyItem uuid.UUID, sentAt time.Time) error {
+			if notifyCard != cardID {
+				t.Fatalf("expected card %s, got %s", cardID, notifyCard)
+			}
+			notifiedItem = notifyItem
+			// A failed notification must not stop the reminder from deferring.
+			return errors.New("boom")
+		},
+	})
+	outcome, err := svc.processGoalReminder(context.Background(), goalReminderJob{
 		ID:         reminderID,
 		UserID:     userID,
 		CardID:     cardID,
@@ -607,9 +658,12 @@ func TestReminderService_ProcessGoalReminder_FailureDefers15MinutesAndDoesNotDis
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if sent {
+	if outcome == reminderSent {
 		t.Fatal("expected no email to be sent")
 	}
+	if notifiedItem != itemID {
+		t.Fatal("expected an in-app notification despite the failed email")
+	}
 	if sawDisable {
 		t.Fatal("expected goal reminder not to be disabled on failure")
 	}
@@ -664,3 +718,295 @@ func TestReminderService_LogReminderEmail_GoalsDoNotUseUpsert(t *testing.T) {
 		t.Fatal("expected no ON CONFLICT for goal reminder logs")
 	}
 }
+
+func TestReminderService_ProcessCheckin_EmailPauseDefersUntilPauseEnds(t *testing.T) {
+	now := time.Date(2025, time.January, 2, 10, 0, 0, 0, time.UTC)
+	pausedUntil := time.Date(2025, time.January, 9, 0, 0, 0, 0, time.UTC)
+	card := testutil.NewTestCard()
+	userID, cardID := card.UserID, card.ID
+	reminderID := uuid.New()
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "SELECT email FROM users") {
+				return rowFromValues("user@test.com")
+			}
+			return rowFromValues(0)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	var deferredTo time.Time
+	var sawAdvance, sawLog bool
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM bingo_cards") {
+				return rowFromValues(card.Row()...)
+			}
+			return rowFromValues(userID)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			return &fakeRows{rows: [][]any{}}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			switch {
+			case strings.Contains(sql, "UPDATE card_checkin_reminders SET last_sent_at"):
+				sawAdvance = true
+			case strings.Contains(sql, "UPDATE card_checkin_reminders SET next_send_at"):
+				deferredTo, _ = args[0].(time.Time)
+			case strings.Contains(sql, "INSERT INTO reminder_email_log"):
+				sawLog = true
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	sends := 0
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
+	svc := NewReminderService(db, stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			sends++
+			return nil
+		},
+	}, "http://example.com")
+	job := checkinJob{
+		ID:               reminderID,
+		UserID:           userID,
+		CardID:           cardID,
+		Frequency:        "monthly",
+		Schedule:         []byte(`{"day_of_month":1,"time":"09:00"}`),
+		NextSendAt:       now.Add(-1 * time.Minute),
+		EmailPausedUntil: &pausedUntil,
+	}
+
+	outcome, err := svc.processCheckin(context.Background(), job, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outcome != reminderDeferred || sends != 0 {
+		t.Fatal("expected the paused checkin to be deferred without an email")
+	}
+	if sawAdvance || sawLog {
+		t.Fatal("expected a paused checkin to be neither advanced nor logged")
+	}
+	if !deferredTo.Equal(pausedUntil) {
+		t.Fatalf("expected checkin deferred to %v, got %v", pausedUntil, deferredTo)
+	}
+
+	// Once the pause expires the deferred reminder is picked up and sent.
+	job.NextSendAt = deferredTo
+	outcome, err = svc.processCheckin(context.Background(), job, pausedUntil.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error after pause: %v", err)
+	}
+	if outcome != reminderSent || sends != 1 {
+		t.Fatalf("expected deferred checkin to send after pause, outcome=%v sends=%d", outcome, sends)
+	}
+	if !sawAdvance {
+		t.Fatal("expected schedule to advance after the deferred send")
+	}
+}
+
+func TestReminderService_ProcessGoalReminder_EmailPauseDefersUntilPauseEnds(t *testing.T) {
+	now := time.Date(2025, time.January, 2, 10, 0, 0, 0, time.UTC)
+	pausedUntil := time.Date(2025, time.January, 5, 12, 0, 0, 0, time.UTC)
+	card := testutil.NewTestCard(testutil.WithItems(1))
+	item := card.Items[0]
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM bingo_items") {
+				return rowFromValues(testutil.GoalReminderContextRow(card, item, "user@test.com", 3)...)
+			}
+			return rowFromValues(0)
+		},
+	}
+
+	var deferredTo time.Time
+	var disabled bool
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(card.UserID)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE goal_reminders SET next_send_at") {
+				deferredTo, _ = args[0].(time.Time)
+			}
+			if strings.Contains(sql, "enabled = false") {
+				disabled = true
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
+	svc := NewReminderService(db, stubEmailService{
+		SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+			t.Fatal("expected no email while paused")
+			return nil
+		},
+	}, "http://example.com")
+	outcome, err := svc.processGoalReminder(context.Background(), goalReminderJob{
+		ID:               uuid.New(),
+		UserID:           card.UserID,
+		CardID:           card.ID,
+		ItemID:           item.ID,
+		Kind:             "one_time",
+		NextSendAt:       now.Add(-time.Minute),
+		EmailPausedUntil: &pausedUntil,
+	}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outcome == reminderSent {
+		t.Fatal("expected no email to be sent")
+	}
+	if disabled {
+		t.Fatal("expected one-time goal reminder to stay enabled during a pause")
+	}
+	if !deferredTo.Equal(pausedUntil) {
+		t.Fatalf("expected goal reminder deferred to %v, got %v", pausedUntil, deferredTo)
+	}
+}
+
+func TestPickReminderRecommendations_DifficultyPacing(t *testing.T) {
+	easy, hard := "easy", "hard"
+	card := &models.BingoCard{GridSize: 3}
+	items := []models.BingoItem{
+		{Position: 3, Difficulty: &hard},
+		{Position: 4, IsCompleted: true},
+		{Position: 5, Difficulty: &easy},
+	}
+
+	svc := NewReminderService(&fakeDB{}, nil, "https://example.com")
+	svc.now = func() time.Time { return time.Date(2026, time.January, 15, 9, 0, 0, 0, time.UTC) }
+	if got := svc.pickReminderRecommendations(card, items, nil); got[0].Position != 3 {
+		t.Fatalf("expected position order without pacing, got %+v", got)
+	}
+
+	svc.SetDifficultyPacing(true)
+	if got := svc.pickReminderRecommendations(card, items, nil); got[0].Position != 5 {
+		t.Fatalf("expected easy goal first in January, got %+v", got)
+	}
+	svc.now = func() time.Time { return time.Date(2026, time.November, 15, 9, 0, 0, 0, time.UTC) }
+	if got := svc.pickReminderRecommendations(card, items, nil); got[0].Position != 3 {
+		t.Fatalf("expected hard goal first in November, got %+v", got)
+	}
+}
+
+func TestReminderService_ProcessCheckin_RotatesRecommendationsAcrossSends(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	// Open 3x3 card without a FREE space: every square is on a line, ranked
+	// centre, corners, then edges.
+	card := testutil.NewTestCard(testutil.WithGridSize(3), testutil.WithoutFreeSpace(), testutil.WithItems(9))
+	userID, cardID := card.UserID, card.ID
+	itemIDs := make([]uuid.UUID, 9)
+	positionByID := map[uuid.UUID]int{}
+	for _, item := range card.Items {
+		itemIDs[item.Position] = item.ID
+		positionByID[item.ID] = item.Position
+	}
+
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "SELECT email FROM users") {
+				return rowFromValues("user@test.com")
+			}
+			return rowFromValues(0)
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	var recorded []byte
+	tx := &fakeTx{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			if strings.Contains(sql, "FROM bingo_cards") {
+				return rowFromValues(card.Row()...)
+			}
+			return rowFromValues(userID)
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if strings.Contains(sql, "FROM bingo_items") {
+				return &fakeRows{rows: card.ItemRows()}, nil
+			}
+			return &fakeRows{rows: [][]any{}}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "UPDATE card_checkin_reminders SET last_sent_at") {
+				if !strings.Contains(sql, "recent_recommendations = $3") {
+					t.Fatalf("expected recent recommendations to be recorded, got %q", sql)
+				}
+				recorded, _ = args[2].([]byte)
+				var snapshot checkinSnapshot
+				if raw, _ := args[3].([]byte); json.Unmarshal(raw, &snapshot) != nil || snapshot.ItemIDs == nil {
+					t.Fatalf("expected a progress snapshot to be recorded, got %q", args[3])
+				}
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	db.BeginFunc = func(ctx context.Context) (Tx, error) { return tx, nil }
+	svc := NewReminderService(db, stubEmailService{}, "http://example.com")
+	job := checkinJob{
+		ID:                     uuid.New(),
+		UserID:                 userID,
+		CardID:                 cardID,
+		Frequency:              "monthly",
+		Schedule:               []byte(`{"day_of_month":1,"time":"09:00"}`),
+		IncludeRecommendations: true,
+		NextSendAt:             now,
+	}
+
+	want := [][]int{{4, 0, 2}, {6, 8, 1}, {3, 5, 7}}
+	for i, positions := range want {
+		recorded = nil
+		outcome, err := svc.processCheckin(context.Background(), job, now.AddDate(0, i, 0))
+		if err != nil || outcome != reminderSent {
+			t.Fatalf("send %d: expected email to be sent, outcome=%v err=%v", i+1, outcome, err)
+		}
+
+		var history [][]uuid.UUID
+		if err := json.Unmarshal(recorded, &history); err != nil {
+			t.Fatalf("send %d: decode history: %v", i+1, err)
+		}
+		if len(history) != min(i+1, recentRecommendationSends) {
+			t.Fatalf("send %d: expected %d sends of history, got %d", i+1, min(i+1, recentRecommendationSends), len(history))
+		}
+		got := make([]int, 0, len(history[0]))
+		for _, id := range history[0] {
+			got = append(got, positionByID[id])
+		}
+		if fmt.Sprint(got) != fmt.Sprint(positions) {
+			t.Fatalf("send %d: expected positions %v, got %v", i+1, positions, got)
+		}
+		job.RecentRecommendations = recorded
+	}
+
+	// The first send has aged out of the two-send window, so its goals return.
+	got := svc.checkinRecommendations(job, &models.BingoCard{GridSize: 3}, []models.BingoItem{
+		{ID: itemIDs[4], Position: 4},
+		{ID: itemIDs[6], Position: 6},
+		{ID: itemIDs[3], Position: 3},
+	})
+	if len(got) == 0 || got[0].Position != 4 {
+		t.Fatalf("expected goals from the oldest send to rotate back in, got %+v", got)
+	}
+}
+
+func TestRecentRecommendationIDs_IgnoresBadHistory(t *testing.T) {
+	if got := recentRecommendationIDs([]byte(`not json`)); got != nil {
+		t.Fatalf("expected nil for unreadable history, got %v", got)
+	}
+	raw, err := appendRecentRecommendations([]byte(`not json`), []models.BingoItem{{ID: uuid.New()}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := recentRecommendationIDs(raw); len(got) != 1 {
+		t.Fatalf("expected a fresh one-send history, got %v", got)
+	}
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+	"github.com/HammerMeetNail/yearofbingo/internal/testutil"
+)
+
+func loadNewYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tz database unavailable: %v", err)
+	}
+	return loc
+}
+
+// In 2026 US daylight time runs from March 8 to November 1, so 09:00 in New
+// York is 14:00 UTC in winter and 13:00 UTC in summer.
+func TestReminderService_NextCheckinSendAt_FollowsDST(t *testing.T) {
+	loadNewYork(t)
+	svc := NewReminderService(&fakeDB{}, nil, "http://example.com")
+	job := checkinJob{Schedule: []byte(`{"day_of_month":10,"time":"09:00"}`), Timezone: "America/New_York"}
+
+	cases := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"into daylight time", time.Date(2026, time.February, 10, 14, 5, 0, 0, time.UTC), time.Date(2026, time.March, 10, 13, 0, 0, 0, time.UTC)},
+		{"out of daylight time", time.Date(2026, time.October, 10, 13, 5, 0, 0, time.UTC), time.Date(2026, time.November, 10, 14, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		next, err := svc.nextCheckinSendAt(tc.now, job)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if !next.Equal(tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, next.UTC())
+		}
+	}
+}
+
+func TestReminderService_NextCheckinSendAt_UnknownZoneFallsBackToUTC(t *testing.T) {
+	svc := NewReminderService(&fakeDB{}, nil, "http://example.com")
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	for _, zone := range []string{"", "Not/AZone"} {
+		next, err := svc.nextCheckinSendAt(now, checkinJob{Schedule: []byte(`{"day_of_month":10,"time":"09:00"}`), Timezone: zone})
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", zone, err)
+		}
+		if want := time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC); !next.Equal(want) {
+			t.Fatalf("%q: expected %v, got %v", zone, want, next)
+		}
+	}
+}
+
+func TestReminderService_UpsertCardCheckin_UsesUserTimezone(t *testing.T) {
+	loadNewYork(t)
+	card := testutil.NewTestCard()
+	userID, cardID := card.UserID, card.ID
+	fixedNow := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	var insertArgs []any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "SELECT user_id, is_finalized, is_archived"):
+				return rowFromValues(userID, true, false)
+			case strings.Contains(sql, "SELECT timezone"):
+				return rowFromValues("America/New_York")
+			}
+			insertArgs = args
+			fixture := testutil.NewTestReminder(card, testutil.WithNextSendAt(args[7].(time.Time)))
+			fixture.Schedule = args[3].([]byte)
+			return rowFromValues(fixture.Row()...)
+		},
+	}
+	svc := NewReminderService(db, nil, "http://example.com")
+	svc.now = func() time.Time { return fixedNow }
+
+	if _, err := svc.UpsertCardCheckin(context.Background(), userID, cardID, models.CardCheckinScheduleInput{
+		Schedule: models.CardCheckinSchedulePayload{DayOfMonth: 15, Time: "09:00"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := time.Date(2026, time.March, 15, 13, 0, 0, 0, time.UTC)
+	if got, ok := insertArgs[7].(time.Time); !ok || !got.Equal(want) {
+		t.Fatalf("expected next send %v, got %#v", want, insertArgs[7])
+	}
+}
+
+func TestReminderService_DeferCheckinAfterCap_AcrossDST(t *testing.T) {
+	loadNewYork(t)
+	var updatedTo time.Time
+	tx := &fakeTx{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			updatedTo, _ = args[0].(time.Time)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	svc := NewReminderService(&fakeDB{}, nil, "http://example.com")
+
+	// Saturday March 7 at 09:00 EST; the retry lands on Sunday 09:00 EDT.
+	now := time.Date(2026, time.March, 7, 14, 0, 0, 0, time.UTC)
+	err := svc.deferCheckinAfterCapReached(context.Background(), tx, checkinJob{
+		ID:       uuid.New(),
+		Schedule: []byte(`{"day_of_month":7,"time":"09:00"}`),
+		Timezone: "America/New_York",
+	}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2026, time.March, 8, 13, 0, 0, 0, time.UTC); !updatedTo.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, updatedTo.UTC())
+	}
+}
+
+func TestReminderService_DeferGoalAfterCap_AcrossDST(t *testing.T) {
+	loadNewYork(t)
+	var updatedTo time.Time
+	tx := &fakeTx{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			updatedTo, _ = args[0].(time.Time)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+	svc := NewReminderService(&fakeDB{}, nil, "http://example.com")
+
+	// Due Sunday November 1 at 09:00 EST, read back from the database in UTC.
+	due := time.Date(2026, time.November, 1, 14, 0, 0, 0, time.UTC)
+	err := svc.deferGoalAfterCapReached(context.Background(), tx, goalReminderJob{
+		ID:         uuid.New(),
+		NextSendAt: due,
+		Timezone:   "America/New_York",
+	}, due.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2026, time.November, 2, 14, 0, 0, 0, time.UTC); !updatedTo.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, updatedTo.UTC())
+	}
+
+	// The same wall-clock time in summer is an hour earlier in UTC.
+	due = time.Date(2026, time.July, 1, 13, 0, 0, 0, time.UTC)
+	if err := svc.deferGoalAfterCapReached(context.Background(), tx, goalReminderJob{
+		ID:         uuid.New(),
+		NextSendAt: due,
+		Timezone:   "America/New_York",
+	}, due.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2026, time.July, 2, 13, 0, 0, 0, time.UTC); !updatedTo.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, updatedTo.UTC())
+	}
+}
+
+func TestParseOneTimeSchedule_WallClockInZone(t *testing.T) {
+	loc := loadNewYork(t)
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]time.Time{
+		"2026-03-09T09:00": time.Date(2026, time.March, 9, 13, 0, 0, 0, time.UTC),
+		"2026-11-02T09:00": time.Date(2026, time.November, 2, 14, 0, 0, 0, time.UTC),
+		// An explicit offset is taken as given.
+		"2026-03-09T09:00:00Z":      time.Date(2026, time.March, 9, 9, 0, 0, 0, time.UTC),
+		"2026-03-09T09:00:00+02:00": time.Date(2026, time.March, 9, 7, 0, 0, 0, time.UTC),
+	}
+	for input, want := range cases {
+		got, err := parseOneTimeSchedule(models.GoalReminderScheduleInput{SendAt: input}, now, loc)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", input, err)
+		}
+		if !got.Equal(want) || got.Location() != time.UTC {
+			t.Fatalf("%s: expected %v, got %v", input, want, got)
+		}
+	}
+}
+
+func TestReminderService_UpdateSettings_Timezone(t *testing.T) {
+	loadNewYork(t)
+	fixture := testutil.NewTestReminderSettings(uuid.New())
+	fixture.Timezone = "America/New_York"
+	userID := fixture.UserID
+	var stored any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			if strings.Contains(sql, "SET timezone") {
+				stored = args[0]
+			}
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(fixture.Row()...)
+		},
+	}
+	svc := NewReminderService(db, nil, "http://example.com")
+
+	for _, invalid := range []string{"", "Local", "Mars/Olympus"} {
+		zone := invalid
+		if _, err := svc.UpdateSettings(context.Background(), userID, models.ReminderSettingsPatch{Timezone: &zone}); !errors.Is(err, ErrInvalidTimezone) {
+			t.Fatalf("%q: expected ErrInvalidTimezone, got %v", invalid, err)
+		}
+	}
+	if stored != nil {
+		t.Fatalf("expected nothing stored for invalid zones, got %v", stored)
+	}
+
+	zone := " America/New_York "
+	settings, err := svc.UpdateSettings(context.Background(), userID, models.ReminderSettingsPatch{Timezone: &zone})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored != "America/New_York" || settings.Timezone != "America/New_York" {
+		t.Fatalf("expected trimmed zone stored and returned, got %v / %q", stored, settings.Timezone)
+	}
+}
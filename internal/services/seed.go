@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// SeedEmailDomain tags every account the seed command creates, so Clean can
+// find them again. .local never resolves, so nothing sent to them is
+// delivered.
+const SeedEmailDomain = "seed.local"
+
+// SeedPassword is the password of every seeded account.
+const SeedPassword = "Password1"
+
+const (
+	maxSeedCardsPerUser = 5
+	// seedMinGridSize skips 2x2 cards, which make poor screenshots.
+	seedMinGridSize = 3
+	// seedCheckinTime and seedGoalReminderTime are when seeded reminders go
+	// out, in the user's reminder time zone.
+	seedCheckinTime      = "09:00"
+	seedGoalReminderTime = "18:00"
+)
+
+var (
+	ErrSeedDatabaseNotEmpty = errors.New("database already has users")
+	ErrSeedAlreadyPresent   = errors.New("seed data already present")
+	ErrSeedInvalidOptions   = errors.New("invalid seed options")
+)
+
+var seedUsernames = []string{
+	"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi", "ivan", "judy",
+	"kim", "leo", "mallory", "nina", "oscar", "peggy", "quinn", "rupert", "sybil", "trent",
+}
+
+var seedGoals = []string{
+	"Run a 10k", "Read 12 books", "Learn to knit", "Visit a new country", "Plant a vegetable garden",
+	"Bake sourdough bread", "Call grandma every month", "Swim in a lake", "Write a song", "Try rock climbing",
+	"Cook a three-course dinner", "Take a pottery class", "Volunteer for a weekend", "Go camping", "Learn 50 words in Italian",
+	"Watch a sunrise", "Do a digital detox day", "Finish a jigsaw puzzle", "See a live concert", "Host a game night",
+	"Ride a bike across town", "Start a journal", "Fix something instead of replacing it", "Try a new cuisine", "Visit a museum",
+	"Meditate for 30 days", "Learn a card trick", "Walk 10,000 steps a day for a week", "Paint a picture", "Go to a comedy show",
+	"Donate blood", "Learn to juggle", "Make homemade pasta", "Hike a mountain", "Send five thank-you notes",
+	"Take a dance class", "Build a birdhouse", "Go stargazing", "Cook with a friend", "Run a photo walk",
+}
+
+// SeedOptions configures Seed.
+type SeedOptions struct {
+	Users         int
+	CardsPerUser  int
+	WithFriends   bool
+	WithReminders bool
+	// Force seeds a database that already has users of its own.
+	Force bool
+	// Seed fixes the goals, grids and completions picked, so the same
+	// options give the same data.
+	Seed int64
+}
+
+// SeedService fills a development or demo database with verified users,
+// finalized cards, friendships, notifications and reminder schedules. It goes
+// through the same services the API uses, so the data obeys the same rules,
+// and only backdates completions so progress is spread over the year.
+type SeedService struct {
+	db        DB
+	users     *UserService
+	auth      *AuthService
+	cards     *CardService
+	friends   *FriendService
+	reminders *ReminderService
+	now       func() time.Time
+}
+
+func NewSeedService(db DB, baseURL string) *SeedService {
+	// Seeded users keep the default of no notification emails, and saving a
+	// reminder schedule sends nothing, so no email service is needed.
+	notifications := NewNotificationService(db, nil, baseURL)
+	cards := NewCardService(db)
+	cards.SetNotificationService(notifications)
+	friends := NewFriendService(db)
+	friends.SetNotificationService(notifications)
+	reminders := NewReminderService(db, nil, baseURL)
+	reminders.SetNotificationService(notifications)
+	return &SeedService{
+		db:        db,
+		users:     NewUserService(db),
+		auth:      NewAuthService(db, nil),
+		cards:     cards,
+		friends:   friends,
+		reminders: reminders,
+		now:       time.Now,
+	}
+}
+
+// Seed creates the data described by opts. It refuses a database with users
+// unless opts.Force is set, and always refuses one that was already seeded;
+// run Clean first.
+func (s *SeedService) Seed(ctx context.Context, opts SeedOptions) (*models.SeedReport, error) {
+	if opts.Users < 1 || opts.Users > len(seedUsernames) {
+		return nil, fmt.Errorf("%w: users must be between 1 and %d", ErrSeedInvalidOptions, len(seedUsernames))
+	}
+	if opts.CardsPerUser < 0 || opts.CardsPerUser > maxSeedCardsPerUser {
+		return nil, fmt.Errorf("%w: cards per user must be between 0 and %d", ErrSeedInvalidOptions, maxSeedCardsPerUser)
+	}
+	if opts.WithReminders && opts.CardsPerUser == 0 {
+		return nil, fmt.Errorf("%w: reminders need at least one card per user", ErrSeedInvalidOptions)
+	}
+
+	var users, seeded int
+	if err := s.db.QueryRow(ctx,
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE email LIKE $1) FROM users`,
+		"%@"+SeedEmailDomain,
+	).Scan(&users, &seeded); err != nil {
+		return nil, fmt.Errorf("counting users: %w", err)
+	}
+	if seeded > 0 {
+		return nil, ErrSeedAlreadyPresent
+	}
+	if users > 0 && !opts.Force {
+		return nil, ErrSeedDatabaseNotEmpty
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	now := s.now()
+	report := &models.SeedReport{Password: SeedPassword}
+
+	created, err := s.seedUsers(ctx, opts.Users, report)
+	if err != nil {
+		return nil, err
+	}
+	// Friendships come before cards so finalizing and bingos notify friends.
+	if opts.WithFriends {
+		if err := s.seedFriendships(ctx, created, report); err != nil {
+			return nil, err
+		}
+	}
+	for i, user := range created {
+		var current *models.BingoCard
+		for c := 0; c < opts.CardsPerUser; c++ {
+			card, err := s.seedCard(ctx, rng, user.ID, now.Year()-c, now, report)
+			if err != nil {
+				return nil, fmt.Errorf("seeding cards for %s: %w", user.Username, err)
+			}
+			if c == 0 {
+				current = card
+			}
+		}
+		if opts.WithReminders {
+			if err := s.seedReminders(ctx, i, user.ID, current, report); err != nil {
+				return nil, fmt.Errorf("seeding reminders for %s: %w", user.Username, err)
+			}
+		}
+	}
+
+	if err := s.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM notifications n JOIN users u ON u.id = n.user_id WHERE u.email LIKE $1`,
+		"%@"+SeedEmailDomain,
+	).Scan(&report.Notifications); err != nil {
+		return nil, fmt.Errorf("counting notifications: %w", err)
+	}
+	return report, nil
+}
+
+// Clean deletes every seeded account. Their cards, friendships,
+// notifications and reminders go with them. Returns how many accounts were
+// deleted.
+func (s *SeedService) Clean(ctx context.Context) (int64, error) {
+	result, err := s.db.Exec(ctx, `DELETE FROM users WHERE email LIKE $1`, "%@"+SeedEmailDomain)
+	if err != nil {
+		return 0, fmt.Errorf("deleting seed users: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+func (s *SeedService) seedUsers(ctx context.Context, count int, report *models.SeedReport) ([]*models.User, error) {
+	hash, err := s.auth.HashPassword(SeedPassword)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*models.User, 0, count)
+	for _, username := range seedUsernames[:count] {
+		user, err := s.users.Create(ctx, models.CreateUserParams{
+			Email:        username + "@" + SeedEmailDomain,
+			PasswordHash: &hash,
+			Username:     username,
+			Searchable:   true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating %s: %w", username, err)
+		}
+		if err := s.users.MarkEmailVerified(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+		report.Users = append(report.Users, models.SeedUser{Username: user.Username, Email: user.Email})
+	}
+	return users, nil
+}
+
+// seedFriendships makes each user friends with the next one, and leaves a
+// request from the last user to the first pending.
+func (s *SeedService) seedFriendships(ctx context.Context, users []*models.User, report *models.SeedReport) error {
+	for i := 0; i+1 < len(users); i++ {
+		friendship, _, err := s.friends.SendRequest(ctx, users[i].ID, users[i+1].ID)
+		if err != nil {
+			return fmt.Errorf("sending friend request: %w", err)
+		}
+		if _, err := s.friends.AcceptRequest(ctx, users[i+1].ID, friendship.ID); err != nil {
+			return fmt.Errorf("accepting friend request: %w", err)
+		}
+		report.Friendships++
+	}
+	if len(users) > 2 {
+		if _, _, err := s.friends.SendRequest(ctx, users[len(users)-1].ID, users[0].ID); err != nil {
+			return fmt.Errorf("sending friend request: %w", err)
+		}
+	}
+	return nil
+}
+
+// seedCard creates and finalizes a card for year, then completes some of its
+// goals with completion times spread from the start of the year to now (or
+// the year's end). The current year's card is as far along as the year is.
+func (s *SeedService) seedCard(ctx context.Context, rng *rand.Rand, userID uuid.UUID, year int, now time.Time, report *models.SeedReport) (*models.BingoCard, error) {
+	gridSize := seedMinGridSize + rng.Intn(models.MaxGridSize-seedMinGridSize+1)
+	category := models.ValidCategories[rng.Intn(len(models.ValidCategories))]
+	card, err := s.cards.Create(ctx, models.CreateCardParams{
+		UserID:   userID,
+		Year:     year,
+		Category: &category,
+		GridSize: gridSize,
+		HasFree:  gridSize%2 == 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	goals := rng.Perm(len(seedGoals))
+	var positions []int
+	for pos := 0; pos < card.TotalSquares(); pos++ {
+		if card.IsFreeSpacePosition(pos) {
+			continue
+		}
+		position := pos
+		if _, err := s.cards.AddItem(ctx, userID, models.AddItemParams{
+			CardID:   card.ID,
+			Content:  seedGoals[goals[len(positions)]],
+			Position: &position,
+		}); err != nil {
+			return nil, err
+		}
+		positions = append(positions, pos)
+	}
+	if _, err := s.cards.Finalize(ctx, userID, card.ID, nil); err != nil {
+		return nil, err
+	}
+	report.Cards++
+
+	start, yearEnd := card.Period()
+	end := yearEnd
+	if end.After(now) {
+		end = now
+	}
+	if !end.After(start) {
+		return card, nil
+	}
+	share := 0.3 + 0.5*rng.Float64()
+	if year == now.Year() {
+		share *= float64(end.Sub(start)) / float64(yearEnd.Sub(start))
+	}
+	completed := int(share * float64(len(positions)))
+	order := rng.Perm(len(positions))[:completed]
+	offsets := make([]time.Duration, completed)
+	for i := range offsets {
+		offsets[i] = time.Duration(rng.Int63n(int64(end.Sub(start))))
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	for i, idx := range order {
+		item, err := s.cards.CompleteItem(ctx, userID, card.ID, positions[idx], models.CompleteItemParams{})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.db.Exec(ctx,
+			`UPDATE bingo_items SET completed_at = $1 WHERE id = $2`,
+			start.Add(offsets[i]).UTC(), item.ID,
+		); err != nil {
+			return nil, fmt.Errorf("backdating completion: %w", err)
+		}
+		report.Completed++
+	}
+	return card, nil
+}
+
+// seedReminders adds a monthly check-in for the user's current card, on a
+// day that differs between users, and a weekly reminder for its first open
+// goal.
+func (s *SeedService) seedReminders(ctx context.Context, index int, userID uuid.UUID, card *models.BingoCard, report *models.SeedReport) error {
+	if _, err := s.reminders.UpsertCardCheckin(ctx, userID, card.ID, models.CardCheckinScheduleInput{
+		Frequency: "monthly",
+		Schedule:  models.CardCheckinSchedulePayload{DayOfMonth: index%28 + 1, Time: seedCheckinTime},
+	}); err != nil {
+		return err
+	}
+	report.Reminders++
+
+	var itemID uuid.UUID
+	err := s.db.QueryRow(ctx,
+		`SELECT id FROM bingo_items WHERE card_id = $1 AND is_completed = false ORDER BY position LIMIT 1`,
+		card.ID,
+	).Scan(&itemID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("finding an open goal: %w", err)
+	}
+	if _, err := s.reminders.UpsertGoalReminder(ctx, userID, models.GoalReminderInput{
+		ItemID:   itemID,
+		Kind:     models.GoalReminderKindRecurring,
+		Schedule: models.GoalReminderScheduleInput{EveryDays: 7, Time: seedGoalReminderTime},
+	}); err != nil {
+		return err
+	}
+	report.Reminders++
+	return nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+var seedTestNow = time.Date(2026, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestSeedService(db DB) *SeedService {
+	seed := NewSeedService(db, "https://bingo.example")
+	seed.now = func() time.Time { return seedTestNow }
+	return seed
+}
+
+// seededItems lists every seeded goal as username/year/position/content and
+// whether it is completed, in a stable order.
+func seededItems(t *testing.T, db DB) []string {
+	t.Helper()
+	rows, err := db.Query(context.Background(),
+		`SELECT u.username, c.year, i.position, i.content, i.is_completed
+		 FROM bingo_items i
+		 JOIN bingo_cards c ON c.id = i.card_id
+		 JOIN users u ON u.id = c.user_id
+		 ORDER BY u.username, c.year, i.position`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var username, content string
+		var year, position int
+		var completed bool
+		if err := rows.Scan(&username, &year, &position, &content, &completed); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		items = append(items, fmt.Sprintf("%s/%d/%d/%s/%t", username, year, position, content, completed))
+	}
+	return items
+}
+
+func TestSeedService_Seed(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	seed := newTestSeedService(db)
+
+	report, err := seed.Seed(ctx, SeedOptions{Users: 3, CardsPerUser: 2, WithFriends: true, WithReminders: true, Seed: 7})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Users) != 3 || report.Users[0].Email != "alice@seed.local" || report.Password != SeedPassword {
+		t.Fatalf("unexpected users %+v", report)
+	}
+	if report.Cards != 6 || report.Friendships != 2 || report.Reminders != 6 || report.Completed == 0 || report.Notifications == 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	// Seeded users can sign in and are verified.
+	user, err := NewUserService(db).GetByEmail(ctx, "bob@seed.local")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !user.EmailVerified || !NewAuthService(db, nil).VerifyPassword(user.PasswordHash, SeedPassword) {
+		t.Fatalf("expected a verified user with the seed password, got %+v", user)
+	}
+
+	// Completions are spread over each card's year and never in the future.
+	rows, err := db.Query(ctx,
+		`SELECT c.year, i.completed_at FROM bingo_items i JOIN bingo_cards c ON c.id = i.card_id WHERE i.is_completed = true`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rows.Close()
+	months := map[time.Month]bool{}
+	for rows.Next() {
+		var year int
+		var completedAt time.Time
+		if err := rows.Scan(&year, &completedAt); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if completedAt.Year() != year || completedAt.After(seedTestNow) {
+			t.Fatalf("completion at %s is outside %d or in the future", completedAt, year)
+		}
+		months[completedAt.Month()] = true
+	}
+	if len(months) < 4 {
+		t.Fatalf("expected completions across the year, got months %v", months)
+	}
+
+	var pending int
+	if err := db.QueryRow(ctx, `SELECT COUNT(*) FROM friendships WHERE status = 'pending'`).Scan(&pending); err != nil || pending != 1 {
+		t.Fatalf("expected one pending request, got %d %v", pending, err)
+	}
+}
+
+func TestSeedService_SameSeedSameData(t *testing.T) {
+	ctx := context.Background()
+	opts := SeedOptions{Users: 2, CardsPerUser: 2, Seed: 42}
+
+	first := newSQLiteTestDB(t)
+	if _, err := newTestSeedService(first).Seed(ctx, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second := newSQLiteTestDB(t)
+	if _, err := newTestSeedService(second).Seed(ctx, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a, b := seededItems(t, first), seededItems(t, second)
+	if len(a) == 0 || strings.Join(a, "\n") != strings.Join(b, "\n") {
+		t.Fatalf("expected identical data for the same seed:\n%v\n%v", a, b)
+	}
+
+	third := newSQLiteTestDB(t)
+	opts.Seed = 43
+	if _, err := newTestSeedService(third).Seed(ctx, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(a, "\n") == strings.Join(seededItems(t, third), "\n") {
+		t.Fatal("expected a different seed to pick different data")
+	}
+}
+
+func TestSeedService_RefusesAndCleans(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	seed := newTestSeedService(db)
+	if _, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "real@example.com", Username: "real"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := seed.Seed(ctx, SeedOptions{Users: 2, CardsPerUser: 1}); !errors.Is(err, ErrSeedDatabaseNotEmpty) {
+		t.Fatalf("expected ErrSeedDatabaseNotEmpty, got %v", err)
+	}
+	if _, err := seed.Seed(ctx, SeedOptions{Users: 2, CardsPerUser: 1, WithFriends: true, Force: true}); err != nil {
+		t.Fatalf("unexpected error with force: %v", err)
+	}
+	if _, err := seed.Seed(ctx, SeedOptions{Users: 2, CardsPerUser: 1, Force: true}); !errors.Is(err, ErrSeedAlreadyPresent) {
+		t.Fatalf("expected ErrSeedAlreadyPresent, got %v", err)
+	}
+	for _, opts := range []SeedOptions{{Users: 0}, {Users: len(seedUsernames) + 1}, {Users: 1, CardsPerUser: -1}, {Users: 1, WithReminders: true}} {
+		if _, err := seed.Seed(ctx, opts); !errors.Is(err, ErrSeedInvalidOptions) {
+			t.Errorf("%+v: expected ErrSeedInvalidOptions, got %v", opts, err)
+		}
+	}
+
+	deleted, err := seed.Clean(ctx)
+	if err != nil || deleted != 2 {
+		t.Fatalf("expected two seeded users deleted, got %d %v", deleted, err)
+	}
+	var users, cards, friendships int
+	if err := db.QueryRow(ctx,
+		`SELECT (SELECT COUNT(*) FROM users), (SELECT COUNT(*) FROM bingo_cards), (SELECT COUNT(*) FROM friendships)`,
+	).Scan(&users, &cards, &friendships); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if users != 1 || cards != 0 || friendships != 0 {
+		t.Fatalf("expected only the real user to remain, got %d users, %d cards, %d friendships", users, cards, friendships)
+	}
+
+	// Once cleaned the database can be seeded again.
+	if _, err := seed.Seed(ctx, SeedOptions{Users: 1, CardsPerUser: 1, Force: true}); err != nil {
+		t.Fatalf("unexpected error reseeding: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// Names reported for each self-test check.
+const (
+	SelfTestPostgres = "postgres"
+	SelfTestRedis    = "redis"
+	SelfTestEmail    = "email"
+	SelfTestAI       = "ai"
+)
+
+// selfTestTimeout bounds each check so one hung dependency still lets the
+// others report.
+const selfTestTimeout = 15 * time.Second
+
+// AIPinger checks that the AI provider accepts the configured credentials
+// without generating anything.
+type AIPinger interface {
+	Ping(ctx context.Context) error
+}
+
+// SelfTestService checks connectivity to every external dependency the
+// server relies on. Its only side effects are a scratch row in
+// selftest_scratch, a short-lived Redis key (both removed again) and one
+// email to the operator address.
+type SelfTestService struct {
+	db      DBConn
+	redis   RedisClient
+	email   EmailServiceInterface
+	emailTo string
+	ai      AIPinger
+	now     func() time.Time
+}
+
+func NewSelfTestService(db DBConn, redis RedisClient) *SelfTestService {
+	return &SelfTestService{db: db, redis: redis, now: time.Now}
+}
+
+// SetEmail enables the email check, which sends one message to the given
+// operator address. Without it the check is skipped.
+func (s *SelfTestService) SetEmail(email EmailServiceInterface, to string) {
+	s.email = email
+	s.emailTo = to
+}
+
+// SetAIPinger enables the AI check. Leave it unset when no provider is
+// configured and the check is skipped.
+func (s *SelfTestService) SetAIPinger(ai AIPinger) {
+	s.ai = ai
+}
+
+// Run executes every check in order and reports each one's latency and
+// error. It never stops early, so one report shows everything that is broken.
+func (s *SelfTestService) Run(ctx context.Context) models.SelfTestReport {
+	report := models.SelfTestReport{OK: true}
+	checks := []struct {
+		name string
+		skip bool
+		fn   func(ctx context.Context) error
+	}{
+		{SelfTestPostgres, false, s.checkPostgres},
+		{SelfTestRedis, false, s.checkRedis},
+		{SelfTestEmail, s.email == nil || s.emailTo == "", s.checkEmail},
+		{SelfTestAI, s.ai == nil, s.checkAI},
+	}
+	for _, c := range checks {
+		result := models.SelfTestCheck{Name: c.name, Status: models.SelfTestSkipped}
+		if !c.skip {
+			result = s.runCheck(ctx, c.name, c.fn)
+		}
+		if result.Status == models.SelfTestFailed {
+			report.OK = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+func (s *SelfTestService) runCheck(ctx context.Context, name string, fn func(ctx context.Context) error) models.SelfTestCheck {
+	checkCtx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+	defer cancel()
+	started := s.now()
+	err := fn(checkCtx)
+	result := models.SelfTestCheck{
+		Name:      name,
+		Status:    models.SelfTestOK,
+		LatencyMS: s.now().Sub(started).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = models.SelfTestFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// checkPostgres writes a scratch row, reads it back and deletes it.
+func (s *SelfTestService) checkPostgres(ctx context.Context) error {
+	if s.db == nil {
+		return errors.New("no database connection")
+	}
+	id := uuid.New()
+	if _, err := s.db.Exec(ctx, "INSERT INTO selftest_scratch (id) VALUES ($1)", id); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+	var readBack uuid.UUID
+	readErr := s.db.QueryRow(ctx, "SELECT id FROM selftest_scratch WHERE id = $1", id).Scan(&readBack)
+	if _, err := s.db.Exec(ctx, "DELETE FROM selftest_scratch WHERE id = $1", id); err != nil && readErr == nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	if readErr != nil {
+		return fmt.Errorf("select: %w", readErr)
+	}
+	if readBack != id {
+		return errors.New("select returned a different row")
+	}
+	return nil
+}
+
+// checkRedis sets a key with a TTL, reads it back, shortens the TTL and
+// deletes it.
+func (s *SelfTestService) checkRedis(ctx context.Context) error {
+	if s.redis == nil {
+		return errors.New("no redis connection")
+	}
+	key := "selftest:" + uuid.NewString()
+	value := s.now().UTC().Format(time.RFC3339Nano)
+	if err := s.redis.Set(ctx, key, value, time.Minute); err != nil {
+		return fmt.Errorf("set: %w", err)
+	}
+	defer func() { _ = s.redis.Del(context.WithoutCancel(ctx), key) }()
+	got, err := s.redis.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("get: %w", err)
+	}
+	if got != value {
+		return errors.New("get returned a different value")
+	}
+	if err := s.redis.Expire(ctx, key, 5*time.Second); err != nil {
+		return fmt.Errorf("expire: %w", err)
+	}
+	return nil
+}
+
+func (s *SelfTestService) checkEmail(ctx context.Context) error {
+	sentAt := s.now().UTC().Format(time.RFC1123)
+	text := fmt.Sprintf("This is a self-test email sent at %s to confirm outgoing email works. No action is needed.", sentAt)
+	html := "<p>" + text + "</p>"
+	return s.email.SendNotificationEmail(ctx, s.emailTo, "Self-test email", html, text)
+}
+
+func (s *SelfTestService) checkAI(ctx context.Context) error {
+	return s.ai.Ping(ctx)
+}
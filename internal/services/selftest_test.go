@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type aiPingerFunc func(ctx context.Context) error
+
+func (f aiPingerFunc) Ping(ctx context.Context) error { return f(ctx) }
+
+func TestSelfTestService_Run_AllPass(t *testing.T) {
+	fixedNow := time.Date(2026, time.January, 10, 9, 0, 0, 0, time.UTC)
+	var execs []string
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			execs = append(execs, sql)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(args[0])
+		},
+	}
+	redis := &fakeRedis{getValue: fixedNow.Format(time.RFC3339Nano)}
+	var sentTo []string
+	email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, text string) error {
+		sentTo = append(sentTo, toEmail)
+		return nil
+	}}
+
+	svc := NewSelfTestService(db, redis)
+	svc.now = func() time.Time { return fixedNow }
+	svc.SetEmail(email, "ops@example.com")
+	svc.SetAIPinger(aiPingerFunc(func(ctx context.Context) error { return nil }))
+
+	report := svc.Run(context.Background())
+	if !report.OK || len(report.Checks) != 4 {
+		t.Fatalf("expected four passing checks, got %+v", report)
+	}
+	for _, check := range report.Checks {
+		if check.Status != models.SelfTestOK {
+			t.Fatalf("expected %s ok, got %+v", check.Name, check)
+		}
+	}
+	if len(execs) != 2 || !strings.HasPrefix(execs[0], "INSERT INTO selftest_scratch") || !strings.HasPrefix(execs[1], "DELETE FROM selftest_scratch") {
+		t.Fatalf("expected the scratch row inserted and deleted, got %v", execs)
+	}
+	if redis.setCalls != 1 || redis.expireCalls != 1 || redis.delCalls != 1 {
+		t.Fatalf("expected set/expire/del once each, got %+v", redis)
+	}
+	if len(sentTo) != 1 || sentTo[0] != "ops@example.com" {
+		t.Fatalf("expected one email to the operator, got %v", sentTo)
+	}
+}
+
+func TestSelfTestService_Run_ReportsEveryFailure(t *testing.T) {
+	var execs []string
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			execs = append(execs, sql)
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return fakeRow{scanFunc: func(dest ...any) error { return errors.New("permission denied") }}
+		},
+	}
+	redis := &fakeRedis{getValue: "stale"}
+
+	svc := NewSelfTestService(db, redis)
+	svc.SetAIPinger(aiPingerFunc(func(ctx context.Context) error { return errors.New("status 403") }))
+
+	report := svc.Run(context.Background())
+	if report.OK {
+		t.Fatal("expected the report to fail")
+	}
+	want := map[string]string{
+		SelfTestPostgres: models.SelfTestFailed,
+		SelfTestRedis:    models.SelfTestFailed,
+		SelfTestEmail:    models.SelfTestSkipped,
+		SelfTestAI:       models.SelfTestFailed,
+	}
+	for _, check := range report.Checks {
+		if check.Status != want[check.Name] {
+			t.Fatalf("%s: expected %s, got %+v", check.Name, want[check.Name], check)
+		}
+		if check.Status == models.SelfTestFailed && check.Error == "" {
+			t.Fatalf("%s: expected an error message", check.Name)
+		}
+	}
+	// The scratch row and key are cleaned up even when the read fails.
+	if len(execs) != 2 || redis.delCalls != 1 {
+		t.Fatalf("expected cleanup after failed reads, got execs %v, dels %d", execs, redis.delCalls)
+	}
+}
+
+func TestSelfTestService_Run_NoConnections(t *testing.T) {
+	report := NewSelfTestService(nil, nil).Run(context.Background())
+	if report.OK {
+		t.Fatal("expected missing connections to fail")
+	}
+	if report.Checks[0].Status != models.SelfTestFailed || report.Checks[1].Status != models.SelfTestFailed {
+		t.Fatalf("expected postgres and redis failed, got %+v", report.Checks)
+	}
+	if report.Checks[2].Status != models.SelfTestSkipped || report.Checks[3].Status != models.SelfTestSkipped {
+		t.Fatalf("expected email and ai skipped, got %+v", report.Checks)
+	}
+}
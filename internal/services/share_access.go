@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+const (
+	// ShareAccessFlushInterval is how often buffered share visits are written.
+	ShareAccessFlushInterval = 10 * time.Second
+	// ShareAccessMaxRows caps the visits kept per share; the oldest go first.
+	ShareAccessMaxRows = 5000
+	// ShareAnalyticsDays is how many days the analytics endpoint covers.
+	ShareAnalyticsDays = 30
+
+	// ShareSourceTagMaxLen caps a share link's source tag, in characters.
+	ShareSourceTagMaxLen = 32
+
+	shareAccessBufferSize      = 1000
+	shareAccessReferrerMaxLen  = 100
+	shareAnalyticsTopReferrers = 10
+	shareAnalyticsTopSources   = 20
+)
+
+// User agent classes stored with each share visit.
+const (
+	ShareAgentDesktop = "desktop"
+	ShareAgentMobile  = "mobile"
+	ShareAgentBot     = "bot"
+	ShareAgentUnknown = "unknown"
+)
+
+// shareBotMarkers are lowercase user agent fragments of crawlers and link
+// preview fetchers.
+var shareBotMarkers = []string{"bot", "crawl", "spider", "preview", "facebookexternalhit", "whatsapp", "embedly", "curl", "wget", "python-requests", "go-http-client"}
+
+type shareVisitContextKey struct{}
+
+type shareVisit struct {
+	referrer  string
+	userAgent string
+	source    string
+}
+
+// WithShareVisit marks ctx as a visitor opening a share link, so
+// GetSharedCardByToken logs the visit for the owner's analytics. source is
+// the link's optional ?src= tag. Loads without it, such as OG images and
+// print pages, only update the access counter.
+func WithShareVisit(ctx context.Context, referrer, userAgent, source string) context.Context {
+	return context.WithValue(ctx, shareVisitContextKey{}, shareVisit{referrer: referrer, userAgent: userAgent, source: source})
+}
+
+type shareAccess struct {
+	token      string
+	accessedAt time.Time
+	referrer   string
+	agentClass string
+	source     string
+}
+
+// ShareAccessLog buffers share visits in memory and writes them in batches,
+// so logging adds no database round trip to the public share page. Visits
+// arriving while the buffer is full are dropped. It is safe for concurrent
+// use.
+type ShareAccessLog struct {
+	db      DB
+	mu      sync.Mutex
+	pending []shareAccess
+	maxRows int
+	now     func() time.Time
+}
+
+func NewShareAccessLog(db DB) *ShareAccessLog {
+	return &ShareAccessLog{db: db, maxRows: ShareAccessMaxRows, now: time.Now}
+}
+
+// Record queues a visit to the share with token.
+func (l *ShareAccessLog) Record(token, referrer, userAgent, source string) {
+	access := shareAccess{
+		token:      token,
+		accessedAt: l.now().UTC(),
+		referrer:   shareReferrerHost(referrer),
+		agentClass: shareAgentClass(userAgent),
+		source:     shareSourceTag(source),
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.pending) >= shareAccessBufferSize {
+		return
+	}
+	l.pending = append(l.pending, access)
+}
+
+// Flush writes the queued visits in one transaction, then trims each share
+// it touched back to the row cap. Visits to tokens that no longer resolve are
+// skipped. It returns the number of visits written.
+func (l *ShareAccessLog) Flush(ctx context.Context) (int, error) {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	tx, ctx, err := beginTx(ctx, l.db)
+	if err != nil {
+		return 0, fmt.Errorf("begin share access flush: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	written := 0
+	touched := make(map[uuid.UUID]bool)
+	for _, access := range batch {
+		var cardID uuid.UUID
+		err := tx.QueryRow(ctx,
+			`INSERT INTO card_share_accesses (card_id, accessed_at, referrer_host, user_agent_class, source_tag)
+			 SELECT card_id, $2, $3, $4, $5 FROM bingo_card_shares WHERE token = $1
+			 RETURNING card_id`,
+			access.token, access.accessedAt, access.referrer, access.agentClass, access.source,
+		).Scan(&cardID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("insert share access: %w", err)
+		}
+		written++
+		touched[cardID] = true
+	}
+
+	for cardID := range touched {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM card_share_accesses
+			 WHERE card_id = $1
+			   AND accessed_at < (
+			     SELECT accessed_at FROM card_share_accesses
+			     WHERE card_id = $1
+			     ORDER BY accessed_at DESC
+			     LIMIT 1 OFFSET $2
+			   )`,
+			cardID, l.maxRows-1,
+		); err != nil {
+			return 0, fmt.Errorf("trim share accesses: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit share accesses: %w", err)
+	}
+	committed = true
+	return written, nil
+}
+
+// shareReferrerHost reduces a Referer header to its host, without "www.",
+// so no path or query string from another site is stored. Unparseable
+// referrers count as direct visits.
+func shareReferrerHost(referrer string) string {
+	parsed, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	if len(host) > shareAccessReferrerMaxLen {
+		host = host[:shareAccessReferrerMaxLen]
+	}
+	return host
+}
+
+// shareSourceTag cleans a share link's ?src= tag: control and other
+// invisible characters are dropped, whitespace is collapsed, letters are
+// lowercased so "Family" and "family" group together, and the result is cut
+// to ShareSourceTagMaxLen characters.
+func shareSourceTag(tag string) string {
+	tag = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		if !unicode.IsPrint(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, tag)
+	tag = strings.Join(strings.Fields(tag), " ")
+	if runes := []rune(tag); len(runes) > ShareSourceTagMaxLen {
+		tag = strings.TrimSpace(string(runes[:ShareSourceTagMaxLen]))
+	}
+	return tag
+}
+
+// shareAgentClass sorts a User-Agent into desktop, mobile, bot or unknown.
+func shareAgentClass(userAgent string) string {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return ShareAgentUnknown
+	}
+	for _, marker := range shareBotMarkers {
+		if strings.Contains(ua, marker) {
+			return ShareAgentBot
+		}
+	}
+	if strings.Contains(ua, "mobi") || strings.Contains(ua, "android") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad") {
+		return ShareAgentMobile
+	}
+	return ShareAgentDesktop
+}
+
+// SetShareAccessLog enables per-visit share analytics.
+func (s *CardService) SetShareAccessLog(log *ShareAccessLog) {
+	s.shareAccessLog = log
+}
+
+// logShareVisit queues the visit carried by ctx, if any.
+func (s *CardService) logShareVisit(ctx context.Context, token string) {
+	if s.shareAccessLog == nil {
+		return
+	}
+	if visit, ok := ctx.Value(shareVisitContextKey{}).(shareVisit); ok {
+		s.shareAccessLog.Record(token, visit.referrer, visit.userAgent, visit.source)
+	}
+}
+
+// GetShareAnalytics returns the owner's visit counts for the card's share
+// link: one entry per UTC day for the last ShareAnalyticsDays days, oldest
+// first, plus the top referrers, source tags and user agent classes over that
+// window.
+func (s *CardService) GetShareAnalytics(ctx context.Context, userID, cardID uuid.UUID) (*models.ShareAnalytics, error) {
+	if _, err := s.GetShareStatus(ctx, userID, cardID); err != nil {
+		return nil, err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(ShareAnalyticsDays - 1))
+	rows, err := s.db.Query(ctx,
+		`SELECT accessed_at, referrer_host, user_agent_class, source_tag
+		 FROM card_share_accesses
+		 WHERE card_id = $1 AND accessed_at >= $2`,
+		cardID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("loading share accesses: %w", err)
+	}
+	defer rows.Close()
+
+	perDay := make(map[string]int, ShareAnalyticsDays)
+	referrers := make(map[string]int)
+	sources := make(map[string]int)
+	analytics := &models.ShareAnalytics{
+		Days:       make([]models.ShareAnalyticsDay, 0, ShareAnalyticsDays),
+		Referrers:  []models.ShareReferrerCount{},
+		Sources:    []models.ShareSourceCount{},
+		UserAgents: map[string]int{ShareAgentDesktop: 0, ShareAgentMobile: 0, ShareAgentBot: 0, ShareAgentUnknown: 0},
+	}
+	for rows.Next() {
+		var accessedAt time.Time
+		var referrer, agentClass, source string
+		if err := rows.Scan(&accessedAt, &referrer, &agentClass, &source); err != nil {
+			return nil, fmt.Errorf("scanning share access: %w", err)
+		}
+		perDay[accessedAt.UTC().Format("2006-01-02")]++
+		referrers[referrer]++
+		sources[source]++
+		analytics.UserAgents[agentClass]++
+		analytics.Total++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating share accesses: %w", err)
+	}
+
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		analytics.Days = append(analytics.Days, models.ShareAnalyticsDay{Date: date, Count: perDay[date]})
+	}
+	for host, count := range referrers {
+		analytics.Referrers = append(analytics.Referrers, models.ShareReferrerCount{Host: host, Count: count})
+	}
+	sort.Slice(analytics.Referrers, func(i, j int) bool {
+		a, b := analytics.Referrers[i], analytics.Referrers[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Host < b.Host
+	})
+	if len(analytics.Referrers) > shareAnalyticsTopReferrers {
+		analytics.Referrers = analytics.Referrers[:shareAnalyticsTopReferrers]
+	}
+	for tag, count := range sources {
+		analytics.Sources = append(analytics.Sources, models.ShareSourceCount{Tag: tag, Count: count})
+	}
+	sort.Slice(analytics.Sources, func(i, j int) bool {
+		a, b := analytics.Sources[i], analytics.Sources[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Tag < b.Tag
+	})
+	if len(analytics.Sources) > shareAnalyticsTopSources {
+		analytics.Sources = analytics.Sources[:shareAnalyticsTopSources]
+	}
+	return analytics, nil
+}
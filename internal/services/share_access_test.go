@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// newSharedTestCard creates a user with a finalized, shared 2x2 card.
+func newSharedTestCard(t *testing.T, db DB) (*CardService, *models.User, *models.BingoCard, *models.CardShare) {
+	t.Helper()
+	ctx := context.Background()
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "owner@example.com", Username: "owner"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	cards := NewCardService(db)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: 2026, GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+	share, err := cards.CreateOrRotateShare(ctx, user.ID, card.ID, nil, nil, models.ShareOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error sharing: %v", err)
+	}
+	return cards, user, card, share
+}
+
+func TestShareAccessLog_FlushAndAnalytics(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	cards, user, card, share := newSharedTestCard(t, db)
+	accessLog := NewShareAccessLog(db)
+	cards.SetShareAccessLog(accessLog)
+
+	visits := []struct{ referrer, userAgent, source string }{
+		{"https://www.twitter.com/some/post?id=1", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148", "Family Chat"},
+		{"https://twitter.com/other", "Mozilla/5.0 (Windows NT 10.0; Win64; x64)", " family   chat "},
+		{"", "Slackbot-LinkExpanding 1.0", ""},
+	}
+	for _, visit := range visits {
+		if _, err := cards.GetSharedCardByToken(WithShareVisit(ctx, visit.referrer, visit.userAgent, visit.source), share.Token); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// Loads that aren't visits, such as the OG image, aren't logged.
+	if _, err := cards.GetSharedCardByToken(ctx, share.Token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := cards.GetShareAnalytics(ctx, user.ID, card.ID); err != nil {
+		t.Fatalf("unexpected error before flush: %v", err)
+	}
+	written, err := accessLog.Flush(ctx)
+	if err != nil || written != len(visits) {
+		t.Fatalf("expected %d visits written, got %d, %v", len(visits), written, err)
+	}
+
+	analytics, err := cards.GetShareAnalytics(ctx, user.ID, card.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analytics.Total != 3 || len(analytics.Days) != ShareAnalyticsDays {
+		t.Fatalf("expected 3 visits over %d days, got %+v", ShareAnalyticsDays, analytics)
+	}
+	today := analytics.Days[len(analytics.Days)-1]
+	if today.Date != time.Now().UTC().Format("2006-01-02") || today.Count != 3 {
+		t.Fatalf("expected today's 3 visits last, got %+v", today)
+	}
+	if len(analytics.Referrers) != 2 || analytics.Referrers[0] != (models.ShareReferrerCount{Host: "twitter.com", Count: 2}) {
+		t.Fatalf("expected twitter.com first with 2 visits, got %+v", analytics.Referrers)
+	}
+	wantSources := []models.ShareSourceCount{{Tag: "family chat", Count: 2}, {Tag: "", Count: 1}}
+	if len(analytics.Sources) != 2 || analytics.Sources[0] != wantSources[0] || analytics.Sources[1] != wantSources[1] {
+		t.Fatalf("expected visits grouped by source tag %+v, got %+v", wantSources, analytics.Sources)
+	}
+	if analytics.UserAgents[ShareAgentMobile] != 1 || analytics.UserAgents[ShareAgentDesktop] != 1 || analytics.UserAgents[ShareAgentBot] != 1 {
+		t.Fatalf("unexpected user agent classes: %+v", analytics.UserAgents)
+	}
+
+	other, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "other@example.com", Username: "other"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+	if _, err := cards.GetShareAnalytics(ctx, other.ID, card.ID); !errors.Is(err, ErrNotCardOwner) {
+		t.Fatalf("expected ErrNotCardOwner for another user, got %v", err)
+	}
+	if err := cards.RevokeShare(ctx, user.ID, card.ID); err != nil {
+		t.Fatalf("unexpected error revoking: %v", err)
+	}
+	if _, err := cards.GetShareAnalytics(ctx, user.ID, card.ID); !errors.Is(err, ErrShareNotFound) {
+		t.Fatalf("expected ErrShareNotFound once revoked, got %v", err)
+	}
+}
+
+func TestShareAccessLog_CapsRowsPerShare(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	cards, user, card, share := newSharedTestCard(t, db)
+	accessLog := NewShareAccessLog(db)
+	accessLog.maxRows = 3
+	start := time.Now().Add(-time.Hour)
+	tick := 0
+	accessLog.now = func() time.Time {
+		tick++
+		return start.Add(time.Duration(tick) * time.Minute)
+	}
+
+	for i := 0; i < 5; i++ {
+		accessLog.Record(share.Token, "", "Mozilla/5.0", "")
+	}
+	accessLog.Record("gone", "", "Mozilla/5.0", "")
+	written, err := accessLog.Flush(ctx)
+	if err != nil || written != 5 {
+		t.Fatalf("expected 5 visits written and the unknown token skipped, got %d, %v", written, err)
+	}
+
+	analytics, err := cards.GetShareAnalytics(ctx, user.ID, card.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analytics.Total != 3 {
+		t.Fatalf("expected the newest 3 visits kept, got %d", analytics.Total)
+	}
+	var oldest time.Time
+	if err := db.QueryRow(ctx, "SELECT MIN(accessed_at) FROM card_share_accesses").Scan(&oldest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if oldest.Sub(start.Add(3*time.Minute)).Abs() > time.Second {
+		t.Fatalf("expected the oldest visits trimmed, oldest kept %v", oldest)
+	}
+}
+
+func TestShareAccessLog_SkipsMinimizedOwners(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	cards, user, _, share := newSharedTestCard(t, db)
+	accessLog := NewShareAccessLog(db)
+	cards.SetShareAccessLog(accessLog)
+	if _, err := NewAccountService(db).UpdatePreferences(ctx, user.ID, models.UserPreferences{DataMinimization: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := cards.GetSharedCardByToken(WithShareVisit(ctx, "", "Mozilla/5.0", ""), share.Token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if written, err := accessLog.Flush(ctx); err != nil || written != 0 {
+		t.Fatalf("expected no visits logged for a minimized owner, got %d, %v", written, err)
+	}
+}
+
+func TestShareAccessLog_DropsWhenBufferFull(t *testing.T) {
+	accessLog := NewShareAccessLog(&fakeDB{})
+	for i := 0; i < shareAccessBufferSize+5; i++ {
+		accessLog.Record("token", "", "", "")
+	}
+	if len(accessLog.pending) != shareAccessBufferSize {
+		t.Fatalf("expected the buffer capped at %d, got %d", shareAccessBufferSize, len(accessLog.pending))
+	}
+}
+
+func TestShareReferrerHost(t *testing.T) {
+	cases := map[string]string{
+		"":                                 "",
+		"not a url":                        "",
+		"https://www.Example.com/a?b=c#d":  "example.com",
+		"http://news.ycombinator.com:8080": "news.ycombinator.com",
+	}
+	for input, want := range cases {
+		if got := shareReferrerHost(input); got != want {
+			t.Fatalf("%q: expected %q, got %q", input, want, got)
+		}
+	}
+}
+
+func TestShareSourceTag(t *testing.T) {
+	cases := map[string]string{
+		"":                        "",
+		"  Work\tSlack  ":         "work slack",
+		"climbing\x00\u200bgroup": "climbinggroup",
+		strings.Repeat("ab", 20):  strings.Repeat("ab", 16),
+		strings.Repeat("é", 40):   strings.Repeat("é", ShareSourceTagMaxLen),
+		"<script>":                "<script>",
+	}
+	for input, want := range cases {
+		if got := shareSourceTag(input); got != want {
+			t.Fatalf("%q: expected %q, got %q", input, want, got)
+		}
+	}
+}
+
+func TestShareAgentClass(t *testing.T) {
+	cases := map[string]string{
+		"": ShareAgentUnknown,
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) AppleWebKit/605.1.15":         ShareAgentDesktop,
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Mobile Safari": ShareAgentMobile,
+		"facebookexternalhit/1.1": ShareAgentBot,
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)": ShareAgentBot,
+	}
+	for input, want := range cases {
+		if got := shareAgentClass(input); got != want {
+			t.Fatalf("%q: expected %q, got %q", input, want, got)
+		}
+	}
+}
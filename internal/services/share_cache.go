@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// DefaultSharedCardCacheTTL is how long a public share response stays cached.
+const DefaultSharedCardCacheTTL = 45 * time.Second
+
+// SharedImageCacheTTL is how long a rendered OG image stays cached. It matches
+// the max-age the image is served with; entries are still checked against
+// the current card data, so edits never serve a stale image.
+const SharedImageCacheTTL = 15 * time.Minute
+
+const (
+	sharedCardCachePrefix   = "sharecache:card:"
+	sharedImageCachePrefix  = "sharecache:og:"
+	sharedCardVersionPrefix = "sharecache:version:"
+)
+
+// SharedCardCache keeps public share responses in Redis for a short TTL, and
+// their rendered OG images for SharedImageCacheTTL, so hot share links don't
+// reload the card on every view. Owner edits bump a per-card version instead
+// of deleting keys, so a card can be invalidated without knowing its share
+// tokens; rotating or revoking a share also purges the old tokens' keys.
+// Redis failures only turn into misses.
+type SharedCardCache struct {
+	redis RedisClient
+	ttl   time.Duration
+
+	cardHits    atomic.Int64
+	cardMisses  atomic.Int64
+	imageHits   atomic.Int64
+	imageMisses atomic.Int64
+}
+
+// NewSharedCardCache builds a cache with the given TTL, or
+// DefaultSharedCardCacheTTL if it isn't positive.
+func NewSharedCardCache(redis RedisClient, ttl time.Duration) *SharedCardCache {
+	if ttl <= 0 {
+		ttl = DefaultSharedCardCacheTTL
+	}
+	return &SharedCardCache{redis: redis, ttl: ttl}
+}
+
+type sharedCardCacheEntry struct {
+	CardID  uuid.UUID          `json:"card_id"`
+	Version string             `json:"version"`
+	Touch   bool               `json:"touch"`
+	Card    *models.SharedCard `json:"card"`
+}
+
+type sharedImageCacheEntry struct {
+	Digest string `json:"digest"`
+	PNG    []byte `json:"png"`
+}
+
+// Stats returns hit and miss counts since startup, keyed by cache name.
+func (c *SharedCardCache) Stats() map[string]models.CacheStats {
+	return map[string]models.CacheStats{
+		"shared_card":       {Hits: c.cardHits.Load(), Misses: c.cardMisses.Load()},
+		"shared_card_image": {Hits: c.imageHits.Load(), Misses: c.imageMisses.Load()},
+	}
+}
+
+// Invalidate makes every cached response for the card miss. The version key
+// outlives any entry written under the previous version.
+func (c *SharedCardCache) Invalidate(ctx context.Context, cardID uuid.UUID) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		logging.Warn("Failed to invalidate shared card cache", map[string]interface{}{"card_id": cardID.String(), "error": err.Error()})
+		return
+	}
+	if err := c.redis.Set(ctx, sharedCardVersionPrefix+cardID.String(), hex.EncodeToString(buf), 2*c.ttl); err != nil {
+		logging.Warn("Failed to invalidate shared card cache", map[string]interface{}{"card_id": cardID.String(), "error": err.Error()})
+	}
+}
+
+// Purge deletes the cached responses and OG images for tokens, for share
+// links that were rotated out or revoked.
+func (c *SharedCardCache) Purge(ctx context.Context, tokens ...string) {
+	keys := make([]string, 0, 3*len(tokens))
+	for _, token := range tokens {
+		keys = append(keys,
+			sharedCardCachePrefix+token,
+			sharedImageCacheKey(token, models.ImageThemeLight),
+			sharedImageCacheKey(token, models.ImageThemeDark),
+		)
+	}
+	if err := c.redis.Del(ctx, keys...); err != nil {
+		logging.Warn("Failed to purge shared card cache", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// version returns the card's current cache version. A missing key is the
+// empty version.
+func (c *SharedCardCache) version(ctx context.Context, cardID uuid.UUID) (string, error) {
+	value, err := c.redis.Get(ctx, sharedCardVersionPrefix+cardID.String())
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return value, err
+}
+
+func (c *SharedCardCache) getCard(ctx context.Context, token string) (*sharedCardCacheEntry, bool) {
+	value, err := c.redis.Get(ctx, sharedCardCachePrefix+token)
+	if err != nil {
+		c.cardMisses.Add(1)
+		return nil, false
+	}
+	var entry sharedCardCacheEntry
+	if err := json.Unmarshal([]byte(value), &entry); err != nil || entry.Card == nil {
+		c.cardMisses.Add(1)
+		return nil, false
+	}
+	version, err := c.version(ctx, entry.CardID)
+	if err != nil || version != entry.Version {
+		c.cardMisses.Add(1)
+		return nil, false
+	}
+	c.cardHits.Add(1)
+	return &entry, true
+}
+
+// putCard stores entry until the cache TTL or validUntil, whichever is
+// sooner, so expiring shares stop resolving on time.
+func (c *SharedCardCache) putCard(ctx context.Context, token string, entry sharedCardCacheEntry, validUntil *time.Time) {
+	ttl := c.ttl
+	if validUntil != nil {
+		if remaining := time.Until(*validUntil); remaining < ttl {
+			ttl = remaining
+		}
+	}
+	if ttl <= 0 {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := c.redis.Set(ctx, sharedCardCachePrefix+token, string(data), ttl); err != nil {
+		logging.Warn("Failed to cache shared card", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// GetImage returns the cached OG image for the token and theme if it was
+// rendered from exactly this card data.
+func (c *SharedCardCache) GetImage(ctx context.Context, token, theme string, shared *models.SharedCard) ([]byte, bool) {
+	value, err := c.redis.Get(ctx, sharedImageCacheKey(token, theme))
+	if err != nil {
+		c.imageMisses.Add(1)
+		return nil, false
+	}
+	var entry sharedImageCacheEntry
+	if err := json.Unmarshal([]byte(value), &entry); err != nil || entry.Digest != sharedCardDigest(shared) {
+		c.imageMisses.Add(1)
+		return nil, false
+	}
+	c.imageHits.Add(1)
+	return entry.PNG, true
+}
+
+// PutImage caches an OG image rendered from shared in theme.
+func (c *SharedCardCache) PutImage(ctx context.Context, token, theme string, shared *models.SharedCard, png []byte) {
+	data, err := json.Marshal(sharedImageCacheEntry{Digest: sharedCardDigest(shared), PNG: png})
+	if err != nil {
+		return
+	}
+	if err := c.redis.Set(ctx, sharedImageCacheKey(token, theme), string(data), max(c.ttl, SharedImageCacheTTL)); err != nil {
+		logging.Warn("Failed to cache shared card image", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// sharedImageCacheKey keeps light images under the bare token, as before
+// themes existed.
+func sharedImageCacheKey(token, theme string) string {
+	if theme == models.ImageThemeDark {
+		return sharedImageCachePrefix + token + ":" + theme
+	}
+	return sharedImageCachePrefix + token
+}
+
+func sharedCardDigest(shared *models.SharedCard) string {
+	data, err := json.Marshal(shared)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// memoryRedis is an in-memory RedisClient that ignores expiry but records
+// the TTL each key was last set with.
+type memoryRedis struct {
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newMemoryRedis() *memoryRedis {
+	return &memoryRedis{values: map[string]string{}, ttls: map[string]time.Duration{}}
+}
+
+func (m *memoryRedis) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	m.values[key] = value.(string)
+	m.ttls[key] = expiration
+	return nil
+}
+
+func (m *memoryRedis) Get(ctx context.Context, key string) (string, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (m *memoryRedis) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	m.ttls[key] = expiration
+	return nil
+}
+
+func (m *memoryRedis) Del(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(m.values, key)
+	}
+	return nil
+}
+
+// sharedCardDB serves one finalized card through both the owner paths and
+// the share token lookup, counting share lookups.
+type sharedCardDB struct {
+	*fakeDB
+	shareLoads int
+}
+
+func newSharedCardDB(cardID, userID uuid.UUID, expiresAt *time.Time, items [][]any) *sharedCardDB {
+	db := &sharedCardDB{fakeDB: newCardDB(cardID, userID, 2, false, nil, true, items)}
+	cardRow := db.QueryRowFunc
+	db.QueryRowFunc = func(ctx context.Context, sql string, args ...any) Row {
+		if strings.Contains(sql, "FROM bingo_card_shares") {
+			db.shareLoads++
+			return rowFromValues(cardID, 2025, (*string)(nil), (*string)(nil), 2, "BI", false, (*int)(nil), true, expiresAt, (*string)(nil), false, true, (*time.Time)(nil), time.Now(), true, false)
+		}
+		return cardRow(ctx, sql, args...)
+	}
+	db.QueryFunc = func(ctx context.Context, sql string, args ...any) (Rows, error) {
+		if strings.Contains(sql, "SELECT position, content, is_completed, is_private") {
+			rows := make([][]any, 0, len(items))
+			for _, item := range items {
+				rows = append(rows, []any{item[2], item[3], item[4], item[9], item[7], (*string)(nil)})
+			}
+			return &fakeRows{rows: rows}, nil
+		}
+		return &fakeRows{rows: items}, nil
+	}
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if strings.Contains(sql, "SET is_completed = true") {
+			items[0][4] = true
+		}
+		return fakeCommandTag{rowsAffected: 1}, nil
+	}
+	return db
+}
+
+func TestCardService_GetSharedCardByToken_CacheHit(t *testing.T) {
+	cardID, userID := uuid.New(), uuid.New()
+	items := [][]any{{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil}}
+	db := newSharedCardDB(cardID, userID, nil, items)
+	touches := 0
+	exec := db.ExecFunc
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		if strings.Contains(sql, "UPDATE bingo_card_shares") {
+			touches++
+		}
+		return exec(ctx, sql, args...)
+	}
+	cache := NewSharedCardCache(newMemoryRedis(), 0)
+	svc := NewCardService(db)
+	svc.SetShareCache(cache)
+
+	for i := 0; i < 2; i++ {
+		shared, err := svc.GetSharedCardByToken(context.Background(), "deadbeef")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shared.Card.ID != cardID || len(shared.Items) != 1 || shared.Items[0].Content != "A" {
+			t.Fatalf("unexpected shared card: %+v", shared)
+		}
+	}
+	if db.shareLoads != 1 {
+		t.Fatalf("expected one share lookup, got %d", db.shareLoads)
+	}
+	if touches != 2 {
+		t.Fatalf("expected access recorded on every view, got %d", touches)
+	}
+	stats := cache.Stats()["shared_card"]
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("expected 1 hit and 1 miss, got %+v", stats)
+	}
+}
+
+func TestCardService_GetSharedCardByToken_CompletionInvalidatesCache(t *testing.T) {
+	cardID, userID := uuid.New(), uuid.New()
+	items := [][]any{
+		{uuid.New(), cardID, 0, "A", false, nil, nil, nil, time.Now(), false, nil},
+		{uuid.New(), cardID, 1, "B", false, nil, nil, nil, time.Now(), false, nil},
+	}
+	db := newSharedCardDB(cardID, userID, nil, items)
+	svc := NewCardService(db)
+	svc.SetShareCache(NewSharedCardCache(newMemoryRedis(), 0))
+	ctx := context.Background()
+
+	if _, err := svc.GetSharedCardByToken(ctx, "deadbeef"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.CompleteItem(ctx, userID, cardID, 0, models.CompleteItemParams{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shared, err := svc.GetSharedCardByToken(ctx, "deadbeef")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.shareLoads != 2 {
+		t.Fatalf("expected completion to force a reload, got %d share lookups", db.shareLoads)
+	}
+	if !shared.Items[0].IsCompleted {
+		t.Fatalf("expected completed item after invalidation, got %+v", shared.Items[0])
+	}
+
+	items[0][4] = false
+	if _, err := svc.UncompleteItem(ctx, userID, cardID, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.GetSharedCardByToken(ctx, "deadbeef"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.shareLoads != 3 {
+		t.Fatalf("expected uncompletion to force a reload, got %d share lookups", db.shareLoads)
+	}
+}
+
+func TestCardService_GetSharedCardByToken_CacheTTLCappedByExpiry(t *testing.T) {
+	cardID := uuid.New()
+	expiresAt := time.Now().Add(10 * time.Second)
+	store := newMemoryRedis()
+	svc := NewCardService(newSharedCardDB(cardID, uuid.New(), &expiresAt, nil).fakeDB)
+	svc.SetShareCache(NewSharedCardCache(store, time.Minute))
+
+	if _, err := svc.GetSharedCardByToken(context.Background(), "deadbeef"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ttl := store.ttls[sharedCardCachePrefix+"deadbeef"]
+	if ttl <= 0 || ttl > 10*time.Second {
+		t.Fatalf("expected TTL capped at the share expiry, got %v", ttl)
+	}
+}
+
+func TestCardService_GetSharedCardByToken_ExpiredNotCached(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	store := newMemoryRedis()
+	svc := NewCardService(newSharedCardDB(uuid.New(), uuid.New(), &expired, nil).fakeDB)
+	svc.SetShareCache(NewSharedCardCache(store, 0))
+
+	if _, err := svc.GetSharedCardByToken(context.Background(), "deadbeef"); !errors.Is(err, ErrShareNotFound) {
+		t.Fatalf("expected ErrShareNotFound, got %v", err)
+	}
+	if _, ok := store.values[sharedCardCachePrefix+"deadbeef"]; ok {
+		t.Fatal("expected expired share not to be cached")
+	}
+}
+
+func TestSharedCardCache_ImageRequiresMatchingCard(t *testing.T) {
+	ctx := context.Background()
+	cache := NewSharedCardCache(newMemoryRedis(), 0)
+	shared := &models.SharedCard{Items: []models.PublicBingoItem{{Position: 0, Content: "A"}}}
+
+	if _, ok := cache.GetImage(ctx, "deadbeef", models.ImageThemeLight, shared); ok {
+		t.Fatal("expected miss before any image is cached")
+	}
+	cache.PutImage(ctx, "deadbeef", models.ImageThemeLight, shared, []byte("png"))
+	if png, ok := cache.GetImage(ctx, "deadbeef", models.ImageThemeLight, shared); !ok || string(png) != "png" {
+		t.Fatalf("expected cached image, got %q %v", png, ok)
+	}
+
+	shared.Items[0].IsCompleted = true
+	if _, ok := cache.GetImage(ctx, "deadbeef", models.ImageThemeLight, shared); ok {
+		t.Fatal("expected miss once the card data changed")
+	}
+	stats := cache.Stats()["shared_card_image"]
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Fatalf("expected 1 hit and 2 misses, got %+v", stats)
+	}
+}
+
+// shareTokensDB serves a card owned by userID whose share row holds the
+// current and previous tokens.
+func shareTokensDB(userID uuid.UUID, current, previous string) *fakeDB {
+	return &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			switch {
+			case strings.Contains(sql, "SELECT token, previous_token FROM bingo_card_shares"):
+				return rowFromValues(current, &previous)
+			case strings.Contains(sql, "INSERT INTO bingo_card_shares"):
+				return rowFromValues(args[0], args[1], time.Now(), (*time.Time)(nil), (*time.Time)(nil), 0, (*time.Time)(nil), true, false)
+			default:
+				return rowFromValues(userID, true)
+			}
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+}
+
+func TestCardService_RevokeShare_PurgesCachedImages(t *testing.T) {
+	ctx := context.Background()
+	userID, cardID := uuid.New(), uuid.New()
+	store := newMemoryRedis()
+	cache := NewSharedCardCache(store, 0)
+	shared := &models.SharedCard{Items: []models.PublicBingoItem{{Position: 0, Content: "A"}}}
+	cache.PutImage(ctx, "current", models.ImageThemeLight, shared, []byte("png"))
+	cache.PutImage(ctx, "previous", models.ImageThemeLight, shared, []byte("png"))
+	cache.PutImage(ctx, "other", models.ImageThemeLight, shared, []byte("png"))
+	cache.PutImage(ctx, "current", models.ImageThemeDark, shared, []byte("png"))
+
+	svc := NewCardService(shareTokensDB(userID, "current", "previous"))
+	svc.SetShareCache(cache)
+	if err := svc.RevokeShare(ctx, userID, cardID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, token := range []string{"current", "previous"} {
+		for _, theme := range []string{models.ImageThemeLight, models.ImageThemeDark} {
+			if _, ok := cache.GetImage(ctx, token, theme, shared); ok {
+				t.Fatalf("expected %s image for %q to be purged", theme, token)
+			}
+		}
+	}
+	if _, ok := cache.GetImage(ctx, "other", models.ImageThemeLight, shared); !ok {
+		t.Fatal("expected other shares' images to stay cached")
+	}
+}
+
+func TestCardService_CreateOrRotateShare_PurgesOldTokenImage(t *testing.T) {
+	ctx := context.Background()
+	userID, cardID := uuid.New(), uuid.New()
+	store := newMemoryRedis()
+	cache := NewSharedCardCache(store, 0)
+	shared := &models.SharedCard{Items: []models.PublicBingoItem{{Position: 0, Content: "A"}}}
+	cache.PutImage(ctx, "current", models.ImageThemeLight, shared, []byte("png"))
+
+	svc := NewCardService(shareTokensDB(userID, "current", "previous"))
+	svc.SetShareCache(cache)
+	share, err := svc.CreateOrRotateShare(ctx, userID, cardID, nil, nil, models.ShareOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if share.Token == "current" {
+		t.Fatal("expected a new token")
+	}
+	if _, ok := store.values[sharedImageCachePrefix+"current"]; ok {
+		t.Fatal("expected the rotated-out token's image to be purged")
+	}
+}
+
+func TestSharedCardCache_ImageOutlivesCardTTL(t *testing.T) {
+	store := newMemoryRedis()
+	cache := NewSharedCardCache(store, 0)
+	cache.PutImage(context.Background(), "deadbeef", models.ImageThemeLight, &models.SharedCard{}, []byte("png"))
+
+	if ttl := store.ttls[sharedImageCachePrefix+"deadbeef"]; ttl != SharedImageCacheTTL {
+		t.Fatalf("expected image TTL %v, got %v", SharedImageCacheTTL, ttl)
+	}
+}
@@ -0,0 +1,548 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/authz"
+	"github.com/HammerMeetNail/yearofbingo/internal/bingo"
+	"github.com/HammerMeetNail/yearofbingo/internal/config"
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+const (
+	// MaxShareSubscribers caps watchers of one shared card, pending or
+	// confirmed.
+	MaxShareSubscribers = 25
+
+	// shareUpdateWindow is both the span a progress email covers and the
+	// minimum gap between two emails to the same watcher.
+	shareUpdateWindow = 7 * 24 * time.Hour
+
+	// shareConfirmTTL is how long a confirmation link works. Pending rows
+	// older than this are deleted by the weekly job.
+	shareConfirmTTL = 7 * 24 * time.Hour
+
+	// shareConfirmResendGap stops repeat sign-ups from mailing the same
+	// address over and over.
+	shareConfirmResendGap = 10 * time.Minute
+)
+
+var (
+	ErrShareSubscriberLimit      = errors.New("too many subscribers for this share")
+	ErrShareSubscriptionNotFound = errors.New("share subscription not found")
+)
+
+// ShareSubscriptionService lets people without an account follow a shared
+// card by email: a double-opt-in sign-up from the share page, then a weekly
+// progress email for as long as the share stays active.
+type ShareSubscriptionService struct {
+	db           DB
+	emailService EmailServiceInterface
+	baseURL      string
+	branding     config.BrandingConfig
+}
+
+func NewShareSubscriptionService(db DB, emailService EmailServiceInterface, baseURL string) *ShareSubscriptionService {
+	return &ShareSubscriptionService{
+		db:           db,
+		emailService: emailService,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// SetBranding applies the deployment's branding to watcher emails.
+func (s *ShareSubscriptionService) SetBranding(branding config.BrandingConfig) {
+	s.branding = branding
+}
+
+// Subscribe starts a subscription to the card behind shareToken and emails a
+// confirmation link. Only the share's current, unexpired token is accepted,
+// and only when the share shows completions.
+// Addresses that are already confirmed, or were sent a confirmation in the
+// last few minutes, get no new email, so callers can't tell them apart from
+// a fresh sign-up.
+func (s *ShareSubscriptionService) Subscribe(ctx context.Context, shareToken, email string) error {
+	if s.emailService == nil {
+		return errors.New("email service not configured")
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	now := time.Now()
+
+	var cardID uuid.UUID
+	var title *string
+	var year int
+	err := s.db.QueryRow(ctx, `
+		SELECT s.card_id, c.title, c.year
+		FROM bingo_card_shares s
+		JOIN bingo_cards c ON c.id = s.card_id AND c.is_finalized = true
+		JOIN users u ON u.id = c.user_id AND u.deleted_at IS NULL
+		WHERE s.token = $1 AND (s.expires_at IS NULL OR s.expires_at > $2)
+		  AND s.show_completions = true
+	`, shareToken, now).Scan(&cardID, &title, &year)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrShareNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("loading share for subscription: %w", err)
+	}
+
+	var confirmedAt *time.Time
+	var createdAt time.Time
+	err = s.db.QueryRow(ctx,
+		"SELECT confirmed_at, created_at FROM share_subscriptions WHERE card_id = $1 AND email = $2",
+		cardID, email,
+	).Scan(&confirmedAt, &createdAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		var count int
+		if err := s.db.QueryRow(ctx,
+			"SELECT COUNT(*) FROM share_subscriptions WHERE card_id = $1",
+			cardID,
+		).Scan(&count); err != nil {
+			return fmt.Errorf("counting share subscriptions: %w", err)
+		}
+		if count >= MaxShareSubscribers {
+			return ErrShareSubscriberLimit
+		}
+	case err != nil:
+		return fmt.Errorf("loading share subscription: %w", err)
+	case confirmedAt != nil:
+		return nil
+	case now.Sub(createdAt) < shareConfirmResendGap:
+		return nil
+	}
+
+	confirmToken, err := generateShareToken()
+	if err != nil {
+		return err
+	}
+	unsubscribeToken, err := generateShareToken()
+	if err != nil {
+		return err
+	}
+	// A pending row gets a fresh confirmation token; a row confirmed in the
+	// meantime is left alone.
+	result, err := s.db.Exec(ctx, `
+		INSERT INTO share_subscriptions (card_id, email, confirm_token, unsubscribe_token, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (card_id, email) DO UPDATE
+		SET confirm_token = EXCLUDED.confirm_token, created_at = EXCLUDED.created_at
+		WHERE share_subscriptions.confirmed_at IS NULL
+	`, cardID, email, confirmToken, unsubscribeToken, now)
+	if err != nil {
+		return fmt.Errorf("saving share subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil
+	}
+
+	confirmURL := s.baseURL + "/r/watch/confirm?token=" + confirmToken
+	subject, html, text := buildShareConfirmEmail(cardDisplayName(title, &year), confirmURL, s.branding)
+	if err := s.emailService.SendNotificationEmail(ctx, email, subject, html, text); err != nil {
+		return fmt.Errorf("sending share subscription confirmation: %w", err)
+	}
+	return nil
+}
+
+// Confirm activates the pending subscription that owns confirmToken. Weekly
+// emails start a week after confirming.
+func (s *ShareSubscriptionService) Confirm(ctx context.Context, confirmToken string) error {
+	now := time.Now()
+	result, err := s.db.Exec(ctx, `
+		UPDATE share_subscriptions
+		SET confirmed_at = $2, confirm_token = NULL
+		WHERE confirm_token = $1 AND confirmed_at IS NULL AND created_at > $3
+	`, confirmToken, now, now.Add(-shareConfirmTTL))
+	if err != nil {
+		return fmt.Errorf("confirming share subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrShareSubscriptionNotFound
+	}
+	return nil
+}
+
+// Unsubscribe deletes the subscription that owns unsubscribeToken.
+func (s *ShareSubscriptionService) Unsubscribe(ctx context.Context, unsubscribeToken string) error {
+	result, err := s.db.Exec(ctx,
+		"DELETE FROM share_subscriptions WHERE unsubscribe_token = $1",
+		unsubscribeToken,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting share subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrShareSubscriptionNotFound
+	}
+	return nil
+}
+
+// ListSubscribers returns the watchers of the owner's shared card, oldest
+// first. Pending sign-ups are included and marked unconfirmed.
+func (s *ShareSubscriptionService) ListSubscribers(ctx context.Context, userID, cardID uuid.UUID) ([]models.ShareSubscriber, error) {
+	if err := s.checkOwner(ctx, userID, cardID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT id, email, confirmed_at IS NOT NULL, created_at, last_sent_at
+		FROM share_subscriptions
+		WHERE card_id = $1
+		ORDER BY created_at
+	`, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("listing share subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	subscribers := make([]models.ShareSubscriber, 0)
+	for rows.Next() {
+		var sub models.ShareSubscriber
+		if err := rows.Scan(&sub.ID, &sub.Email, &sub.Confirmed, &sub.CreatedAt, &sub.LastSentAt); err != nil {
+			return nil, fmt.Errorf("scanning share subscriber: %w", err)
+		}
+		subscribers = append(subscribers, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating share subscribers: %w", err)
+	}
+	return subscribers, nil
+}
+
+// RemoveSubscriber deletes one watcher from the owner's shared card.
+func (s *ShareSubscriptionService) RemoveSubscriber(ctx context.Context, userID, cardID, subscriberID uuid.UUID) error {
+	if err := s.checkOwner(ctx, userID, cardID); err != nil {
+		return err
+	}
+
+	result, err := s.db.Exec(ctx,
+		"DELETE FROM share_subscriptions WHERE id = $1 AND card_id = $2",
+		subscriberID, cardID,
+	)
+	if err != nil {
+		return fmt.Errorf("removing share subscriber: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrShareSubscriptionNotFound
+	}
+	return nil
+}
+
+func (s *ShareSubscriptionService) checkOwner(ctx context.Context, userID, cardID uuid.UUID) error {
+	var ownerID uuid.UUID
+	err := s.db.QueryRow(ctx, "SELECT user_id FROM bingo_cards WHERE id = $1", cardID).Scan(&ownerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCardNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("loading card owner: %w", err)
+	}
+	if !authz.CanEditCard(authz.For(userID, ownerID)) {
+		return ErrNotCardOwner
+	}
+	return nil
+}
+
+// shareWatchCard is the progress of one watched card, loaded once per run
+// however many watchers it has.
+type shareWatchCard struct {
+	Name      string
+	GridSize  int
+	FreePos   *int
+	Total     int
+	Completed int
+	Items     []models.BingoItem
+}
+
+type shareWatcher struct {
+	ID               uuid.UUID
+	CardID           uuid.UUID
+	Email            string
+	UnsubscribeToken string
+	ShareToken       string
+	Since            time.Time
+}
+
+// RunDue sends the weekly progress email to up to limit confirmed watchers
+// whose last email (or confirmation) is at least a week old, and returns how
+// many were sent. Watchers of expired shares, or of shares that now hide
+// completions, are skipped until the owner changes the share. Weeks with no completions are marked done
+// without an email. Expired pending sign-ups are cleared on each run.
+func (s *ShareSubscriptionService) RunDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	if s.emailService == nil {
+		return 0, nil
+	}
+
+	if _, err := s.db.Exec(ctx,
+		"DELETE FROM share_subscriptions WHERE confirmed_at IS NULL AND created_at <= $1",
+		now.Add(-shareConfirmTTL),
+	); err != nil {
+		return 0, fmt.Errorf("clear expired share subscriptions: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT sub.id, sub.card_id, sub.email, sub.unsubscribe_token, s.token,
+		        COALESCE(sub.last_sent_at, sub.confirmed_at)
+		 FROM share_subscriptions sub
+		 JOIN bingo_card_shares s ON s.card_id = sub.card_id
+		 JOIN bingo_cards c ON c.id = sub.card_id AND c.is_finalized = true
+		 JOIN users u ON u.id = c.user_id AND u.deleted_at IS NULL
+		 WHERE sub.confirmed_at IS NOT NULL
+		   AND (s.expires_at IS NULL OR s.expires_at > $1)
+		   AND s.show_completions = true
+		   AND COALESCE(sub.last_sent_at, sub.confirmed_at) <= $2
+		 ORDER BY sub.last_sent_at NULLS FIRST
+		 LIMIT $3`,
+		now, now.Add(-shareUpdateWindow), limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("load share watchers: %w", err)
+	}
+	var watchers []shareWatcher
+	for rows.Next() {
+		var w shareWatcher
+		if err := rows.Scan(&w.ID, &w.CardID, &w.Email, &w.UnsubscribeToken, &w.ShareToken, &w.Since); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan share watcher: %w", err)
+		}
+		watchers = append(watchers, w)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate share watchers: %w", err)
+	}
+
+	cards := make(map[uuid.UUID]*shareWatchCard)
+	sent := 0
+	for _, watcher := range watchers {
+		card, ok := cards[watcher.CardID]
+		if !ok {
+			card, err = s.loadWatchCard(ctx, watcher.CardID)
+			if err != nil {
+				logging.Warn("Share progress email failed", map[string]interface{}{
+					"card_id": watcher.CardID.String(),
+					"error":   err.Error(),
+				})
+				continue
+			}
+			cards[watcher.CardID] = card
+		}
+		ok, err := s.sendUpdate(ctx, watcher, card, now)
+		if err != nil {
+			logging.Warn("Share progress email failed", map[string]interface{}{
+				"subscription_id": watcher.ID.String(),
+				"error":           err.Error(),
+			})
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (s *ShareSubscriptionService) loadWatchCard(ctx context.Context, cardID uuid.UUID) (*shareWatchCard, error) {
+	var title *string
+	var year int
+	card := &shareWatchCard{}
+	if err := s.db.QueryRow(ctx,
+		`SELECT title, year, grid_size, CASE WHEN has_free_space THEN free_space_position END
+		 FROM bingo_cards WHERE id = $1`,
+		cardID,
+	).Scan(&title, &year, &card.GridSize, &card.FreePos); err != nil {
+		return nil, fmt.Errorf("load watched card: %w", err)
+	}
+	card.Name = cardDisplayName(title, &year)
+
+	rows, err := s.db.Query(ctx,
+		`SELECT position, content, is_completed, completed_at, is_private
+		 FROM bingo_items WHERE card_id = $1 ORDER BY position`,
+		cardID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("load watched items: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item models.BingoItem
+		if err := rows.Scan(&item.Position, &item.Content, &item.IsCompleted, &item.CompletedAt, &item.IsPrivate); err != nil {
+			return nil, fmt.Errorf("scan watched item: %w", err)
+		}
+		if item.IsPrivate {
+			item.Content = models.PrivateItemPlaceholder
+		}
+		card.Total++
+		if item.IsCompleted {
+			card.Completed++
+		}
+		card.Items = append(card.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate watched items: %w", err)
+	}
+	return card, nil
+}
+
+func (s *ShareSubscriptionService) sendUpdate(ctx context.Context, watcher shareWatcher, card *shareWatchCard, now time.Time) (bool, error) {
+	var recent []string
+	var completed []models.BingoItem
+	for _, item := range card.Items {
+		if !item.IsCompleted {
+			continue
+		}
+		completed = append(completed, item)
+		if item.CompletedAt != nil && !item.CompletedAt.Before(watcher.Since) {
+			recent = append(recent, item.Content)
+		}
+	}
+	if len(recent) == 0 {
+		return false, s.markSent(ctx, watcher.ID, now)
+	}
+
+	shareURL := s.baseURL + "/s/" + watcher.ShareToken
+	unsubscribeURL := s.baseURL + "/r/watch/unsubscribe?token=" + watcher.UnsubscribeToken
+	bingos := bingo.CountBingos(completed, card.GridSize, card.FreePos)
+	subject, html, text := buildShareProgressEmail(card, recent, bingos, shareURL, unsubscribeURL, s.branding)
+
+	sendErr := s.emailService.SendNotificationEmail(ctx, watcher.Email, subject, html, text)
+	// A failed send still counts as this week's email so a bad address isn't retried every run.
+	if err := s.markSent(ctx, watcher.ID, now); err != nil {
+		return false, err
+	}
+	if sendErr != nil {
+		return false, fmt.Errorf("send share progress email: %w", sendErr)
+	}
+	return true, nil
+}
+
+func (s *ShareSubscriptionService) markSent(ctx context.Context, subscriptionID uuid.UUID, now time.Time) error {
+	if _, err := s.db.Exec(ctx,
+		"UPDATE share_subscriptions SET last_sent_at = $2 WHERE id = $1",
+		subscriptionID, now,
+	); err != nil {
+		return fmt.Errorf("mark share progress email sent: %w", err)
+	}
+	return nil
+}
+
+func buildShareConfirmEmail(cardName, confirmURL string, brandCfg config.BrandingConfig) (string, string, string) {
+	brand := emailBrand(brandCfg)
+	subject := sanitizeSubject("Confirm weekly updates for " + cardName)
+	safeConfirm := templateEscape(confirmURL)
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 640px; margin: 0 auto; padding: 24px;">
+  %s<h1 style="color: #333; font-size: 24px;">%s</h1>
+  <p style="font-size: 18px;">Someone asked for a weekly progress email about <strong>%s</strong>. Confirm to start receiving it:</p>
+  <p>
+    <a href="%s" style="display: inline-block; background: %s; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">Confirm updates</a>
+  </p>
+  <p style="color: #666; font-size: 14px;">If this wasn't you, ignore this email and nothing more will be sent. The link works for 7 days.</p>
+  <p style="color: #999; font-size: 12px;">%s</p>
+</body>
+</html>`,
+		brand.logoHTML(),
+		templateEscape(brand.name()),
+		templateEscape(cardName),
+		safeConfirm,
+		brand.accent(reminderEmailAccent),
+		brand.footerHTML(),
+	)
+
+	text := fmt.Sprintf(`Someone asked for a weekly progress email about %s. Confirm to start receiving it:
+
+%s
+
+If this wasn't you, ignore this email and nothing more will be sent. The link works for 7 days.
+
+--
+%s`,
+		isolateBidi(cardName),
+		confirmURL,
+		brand.footerText(),
+	)
+
+	return subject, html, text
+}
+
+func buildShareProgressEmail(card *shareWatchCard, recent []string, bingos int, shareURL, unsubscribeURL string, brandCfg config.BrandingConfig) (string, string, string) {
+	brand := emailBrand(brandCfg)
+	subject := sanitizeSubject(card.Name + ": this week's progress")
+	safeShare := templateEscape(shareURL)
+	safeUnsubscribe := templateEscape(unsubscribeURL)
+	summary := fmt.Sprintf("%d of %d goals done, %s.", card.Completed, card.Total, pluralizeBingo(bingos))
+
+	htmlItems := make([]string, 0, len(recent))
+	textItems := make([]string, 0, len(recent))
+	for _, content := range recent {
+		htmlItems = append(htmlItems, "<li>"+templateEscape(content)+"</li>")
+		textItems = append(textItems, "- "+isolateBidi(content))
+	}
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 640px; margin: 0 auto; padding: 24px;">
+  %s<h1 style="color: #333; font-size: 24px;">%s</h1>
+  <p style="font-size: 18px;">Completed this week:</p>
+  <ul style="padding-left: 20px;">%s</ul>
+  <p>%s</p>
+  <p>
+    <a href="%s" style="display: inline-block; background: %s; color: white; padding: 10px 18px; text-decoration: none; border-radius: 6px; margin: 12px 0;">View the card</a>
+  </p>
+  <hr style="border: none; border-top: 1px solid #eee; margin: 30px 0;">
+  <p style="color: #666; font-size: 14px;">You get this because you asked to follow this card. Unsubscribe: <a href="%s">%s</a></p>
+  <p style="color: #999; font-size: 12px;">%s</p>
+</body>
+</html>`,
+		brand.logoHTML(),
+		templateEscape(card.Name),
+		strings.Join(htmlItems, ""),
+		templateEscape(summary),
+		safeShare,
+		brand.accent(reminderEmailAccent),
+		safeUnsubscribe,
+		safeUnsubscribe,
+		brand.footerHTML(),
+	)
+
+	text := fmt.Sprintf(`%s
+
+Completed this week:
+%s
+
+%s
+
+View the card: %s
+
+You get this because you asked to follow this card. Unsubscribe: %s
+
+--
+%s`,
+		isolateBidi(card.Name),
+		strings.Join(textItems, "\n"),
+		summary,
+		shareURL,
+		unsubscribeURL,
+		brand.footerText(),
+	)
+
+	return subject, html, text
+}
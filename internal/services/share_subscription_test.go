@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestShareSubscriptionService_Subscribe(t *testing.T) {
+	cardID := uuid.New()
+	title := "Reading year"
+
+	newDB := func(existing Row, count int, execs *[][]any) *fakeDB {
+		return &fakeDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				switch {
+				case strings.Contains(sql, "FROM bingo_card_shares s"):
+					return rowFromValues(cardID, &title, 2026)
+				case strings.Contains(sql, "SELECT confirmed_at, created_at"):
+					return existing
+				case strings.Contains(sql, "COUNT(*)"):
+					return rowFromValues(count)
+				}
+				t.Fatalf("unexpected query: %s", sql)
+				return nil
+			},
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+				*execs = append(*execs, args)
+				return fakeCommandTag{rowsAffected: 1}, nil
+			},
+		}
+	}
+	noRow := &fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+
+	t.Run("new address gets a confirmation link", func(t *testing.T) {
+		var execs [][]any
+		var to, text string
+		email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, body string) error {
+			to, text = toEmail, body
+			return nil
+		}}
+		svc := NewShareSubscriptionService(newDB(noRow, 0, &execs), email, "https://example.com/")
+		if err := svc.Subscribe(context.Background(), "sharetoken", " Fan@Example.com"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(execs) != 1 || execs[0][1] != "fan@example.com" {
+			t.Fatalf("expected one normalized insert, got %v", execs)
+		}
+		confirmToken := execs[0][2].(string)
+		if to != "fan@example.com" || !strings.Contains(text, "https://example.com/r/watch/confirm?token="+confirmToken) {
+			t.Fatalf("expected confirmation link to %s, got %q", to, text)
+		}
+		if !strings.Contains(text, title) {
+			t.Fatalf("expected card name in email, got %q", text)
+		}
+	})
+
+	t.Run("full share", func(t *testing.T) {
+		var execs [][]any
+		svc := NewShareSubscriptionService(newDB(noRow, MaxShareSubscribers, &execs), stubEmailService{}, "https://example.com")
+		if err := svc.Subscribe(context.Background(), "sharetoken", "fan@example.com"); !errors.Is(err, ErrShareSubscriberLimit) {
+			t.Fatalf("expected ErrShareSubscriberLimit, got %v", err)
+		}
+	})
+
+	t.Run("confirmed or recently mailed address is left alone", func(t *testing.T) {
+		confirmed := time.Now().Add(-time.Hour)
+		for name, existing := range map[string]Row{
+			"confirmed": rowFromValues(&confirmed, confirmed),
+			"recent":    rowFromValues((*time.Time)(nil), time.Now().Add(-time.Minute)),
+		} {
+			var execs [][]any
+			email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, body string) error {
+				t.Fatalf("%s: expected no email", name)
+				return nil
+			}}
+			svc := NewShareSubscriptionService(newDB(existing, 0, &execs), email, "https://example.com")
+			if err := svc.Subscribe(context.Background(), "sharetoken", "fan@example.com"); err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if len(execs) != 0 {
+				t.Fatalf("%s: expected no writes, got %v", name, execs)
+			}
+		}
+	})
+
+	t.Run("inactive share", func(t *testing.T) {
+		db := &fakeDB{QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row { return noRow }}
+		svc := NewShareSubscriptionService(db, stubEmailService{}, "https://example.com")
+		if err := svc.Subscribe(context.Background(), "sharetoken", "fan@example.com"); !errors.Is(err, ErrShareNotFound) {
+			t.Fatalf("expected ErrShareNotFound, got %v", err)
+		}
+	})
+}
+
+func TestShareSubscriptionService_RemoveSubscriber_NotOwner(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(uuid.New())
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			t.Fatal("expected no delete")
+			return nil, nil
+		},
+	}
+	svc := NewShareSubscriptionService(db, nil, "https://example.com")
+	if err := svc.RemoveSubscriber(context.Background(), uuid.New(), uuid.New(), uuid.New()); !errors.Is(err, ErrNotCardOwner) {
+		t.Fatalf("expected ErrNotCardOwner, got %v", err)
+	}
+}
+
+func TestShareSubscriptionService_RunDue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	lastSent := now.Add(-8 * 24 * time.Hour)
+	recent := now.Add(-24 * time.Hour)
+	old := now.Add(-30 * 24 * time.Hour)
+	cardID := uuid.New()
+	title := "Fitness"
+
+	newDB := func(items [][]any, marked *int) *fakeDB {
+		return &fakeDB{
+			QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+				if strings.Contains(sql, "FROM share_subscriptions sub") {
+					return &fakeRows{rows: [][]any{{uuid.New(), cardID, "fan@example.com", "unsubtoken", "sharetoken", lastSent}}}, nil
+				}
+				return &fakeRows{rows: items}, nil
+			},
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+				return rowFromValues(&title, 2026, 3, (*int)(nil))
+			},
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+				if strings.Contains(sql, "SET last_sent_at") {
+					*marked++
+				}
+				return fakeCommandTag{rowsAffected: 1}, nil
+			},
+		}
+	}
+
+	t.Run("sends new completions with private goals hidden", func(t *testing.T) {
+		items := [][]any{
+			{0, "Run a 5k", true, &old, false},
+			{1, "Swim a mile", true, &recent, false},
+			{2, "See a therapist", true, &recent, true},
+			{3, "Climb a wall", false, (*time.Time)(nil), false},
+		}
+		var marked int
+		var text string
+		email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, body string) error {
+			text = body
+			return nil
+		}}
+		svc := NewShareSubscriptionService(newDB(items, &marked), email, "https://example.com")
+		sent, err := svc.RunDue(context.Background(), now, 50)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sent != 1 || marked != 1 {
+			t.Fatalf("expected 1 sent and marked, got %d sent, %d marked", sent, marked)
+		}
+		if !strings.Contains(text, "Swim a mile") || strings.Contains(text, "Run a 5k") {
+			t.Fatalf("expected only this week's completions, got %q", text)
+		}
+		if strings.Contains(text, "therapist") || !strings.Contains(text, "Private goal") {
+			t.Fatalf("expected private goal hidden, got %q", text)
+		}
+		if !strings.Contains(text, "3 of 4 goals done, 1 bingo.") {
+			t.Fatalf("expected progress summary, got %q", text)
+		}
+		if !strings.Contains(text, "https://example.com/s/sharetoken") || !strings.Contains(text, "https://example.com/r/watch/unsubscribe?token=unsubtoken") {
+			t.Fatalf("expected share and unsubscribe links, got %q", text)
+		}
+	})
+
+	t.Run("quiet week is marked without an email", func(t *testing.T) {
+		items := [][]any{{0, "Run a 5k", true, &old, false}}
+		var marked int
+		email := stubEmailService{SendNotificationEmailFunc: func(ctx context.Context, toEmail, subject, html, body string) error {
+			t.Fatal("expected no email")
+			return nil
+		}}
+		svc := NewShareSubscriptionService(newDB(items, &marked), email, "https://example.com")
+		sent, err := svc.RunDue(context.Background(), now, 50)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sent != 0 || marked != 1 {
+			t.Fatalf("expected quiet week marked, got %d sent, %d marked", sent, marked)
+		}
+	})
+}
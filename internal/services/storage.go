@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrStorageObjectNotFound = errors.New("stored object not found")
+	ErrInvalidStorageKey     = errors.New("invalid storage key")
+)
+
+// StorageService keeps uploaded files such as proof photos. Keys are
+// slash-separated paths; DeletePrefix removes everything under a key prefix
+// so all of a user's files can be dropped at once. DiskStorage is the
+// built-in backend; an S3-style bucket fits the same interface.
+type StorageService interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// storageKeySegment is one path segment of a storage key. Leading dots are
+// rejected, so keys can't climb out of the storage root.
+var storageKeySegment = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func validStorageKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if !storageKeySegment.MatchString(segment) {
+			return false
+		}
+	}
+	return true
+}
+
+// DiskStorage stores files under a local directory.
+type DiskStorage struct {
+	dir string
+}
+
+func NewDiskStorage(dir string) *DiskStorage {
+	return &DiskStorage{dir: dir}
+}
+
+func (s *DiskStorage) path(key string) (string, error) {
+	if !validStorageKey(key) {
+		return "", ErrInvalidStorageKey
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+func (s *DiskStorage) Put(ctx context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("creating storage directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o640); err != nil {
+		return fmt.Errorf("writing stored object: %w", err)
+	}
+	return nil
+}
+
+func (s *DiskStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrStorageObjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading stored object: %w", err)
+	}
+	return data, nil
+}
+
+// Delete removes a file. Deleting a missing file is not an error.
+func (s *DiskStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("deleting stored object: %w", err)
+	}
+	return nil
+}
+
+// DeletePrefix removes the directory holding every key under prefix.
+func (s *DiskStorage) DeletePrefix(ctx context.Context, prefix string) error {
+	path, err := s.path(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("deleting stored objects: %w", err)
+	}
+	return nil
+}
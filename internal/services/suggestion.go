@@ -3,19 +3,51 @@ package services
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/HammerMeetNail/yearofbingo/internal/models"
 )
 
 type SuggestionService struct {
-	db DBConn
+	db    DBConn
+	cache *SuggestionCache
 }
 
 func NewSuggestionService(db DBConn) *SuggestionService {
 	return &SuggestionService{db: db}
 }
 
+// SetCache serves the suggestion reads from cache. The pool only changes
+// through migrations today; anything that edits it must call InvalidateCache.
+func (s *SuggestionService) SetCache(cache *SuggestionCache) {
+	s.cache = cache
+}
+
+// InvalidateCache drops cached suggestions after the pool changes.
+func (s *SuggestionService) InvalidateCache(ctx context.Context) {
+	if s.cache != nil {
+		s.cache.Invalidate(ctx)
+	}
+}
+
 func (s *SuggestionService) GetAll(ctx context.Context) ([]*models.Suggestion, error) {
+	if s.cache == nil {
+		return s.loadAll(ctx)
+	}
+	var suggestions []*models.Suggestion
+	if s.cache.get(ctx, suggestionCacheAllKey, &suggestions) {
+		return suggestions, nil
+	}
+	suggestions, err := s.loadAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.put(ctx, suggestionCacheAllKey, suggestions)
+	return suggestions, nil
+}
+
+func (s *SuggestionService) loadAll(ctx context.Context) ([]*models.Suggestion, error) {
 	rows, err := s.db.Query(ctx,
 		`SELECT id, category, content, is_active
 		 FROM suggestions
@@ -40,6 +72,22 @@ func (s *SuggestionService) GetAll(ctx context.Context) ([]*models.Suggestion, e
 }
 
 func (s *SuggestionService) GetByCategory(ctx context.Context, category string) ([]*models.Suggestion, error) {
+	if s.cache != nil {
+		// The cached pool is ordered by category, then content, so filtering
+		// it matches the query below.
+		all, err := s.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var suggestions []*models.Suggestion
+		for _, suggestion := range all {
+			if suggestion.Category == category {
+				suggestions = append(suggestions, suggestion)
+			}
+		}
+		return suggestions, nil
+	}
+
 	rows, err := s.db.Query(ctx,
 		`SELECT id, category, content, is_active
 		 FROM suggestions
@@ -65,6 +113,22 @@ func (s *SuggestionService) GetByCategory(ctx context.Context, category string)
 }
 
 func (s *SuggestionService) GetCategories(ctx context.Context) ([]string, error) {
+	if s.cache == nil {
+		return s.loadCategories(ctx)
+	}
+	var categories []string
+	if s.cache.get(ctx, suggestionCacheCategoriesKey, &categories) {
+		return categories, nil
+	}
+	categories, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.put(ctx, suggestionCacheCategoriesKey, categories)
+	return categories, nil
+}
+
+func (s *SuggestionService) loadCategories(ctx context.Context) ([]string, error) {
 	rows, err := s.db.Query(ctx,
 		`SELECT DISTINCT category FROM suggestions WHERE is_active = true ORDER BY category`,
 	)
@@ -113,3 +177,110 @@ func (s *SuggestionService) GetGroupedByCategory(ctx context.Context) ([]Suggest
 
 	return result, nil
 }
+
+// suggestionAnalyticsMinSample keeps a handful of goals from dominating the
+// completion rankings.
+const suggestionAnalyticsMinSample = 5
+
+// RecordUsage bumps today's adoption counter for every suggestion whose
+// normalized content matches one of the new goals. Matching and counting
+// happen in one statement; nothing about the user or card is stored.
+func (s *SuggestionService) RecordUsage(ctx context.Context, contents []string) error {
+	if len(contents) == 0 {
+		return nil
+	}
+	if _, err := s.db.Exec(ctx,
+		`INSERT INTO suggestion_usage_daily (suggestion_id, day, added_count)
+		 SELECT s.id, CURRENT_DATE, COUNT(*)
+		 FROM unnest($1::text[]) AS c(content)
+		 JOIN suggestions s ON s.content_hash = suggestion_content_hash(c.content)
+		 GROUP BY s.id
+		 ON CONFLICT (suggestion_id, day)
+		 DO UPDATE SET added_count = suggestion_usage_daily.added_count + EXCLUDED.added_count`,
+		contents,
+	); err != nil {
+		return fmt.Errorf("recording suggestion usage: %w", err)
+	}
+	return nil
+}
+
+// Analytics ranks active suggestions by how often they were added since the
+// window start and by the completion rate of goals that match them.
+func (s *SuggestionService) Analytics(ctx context.Context, since time.Time, windowDays, limit int) (*models.SuggestionAnalytics, error) {
+	rows, err := s.db.Query(ctx,
+		`WITH usage AS (
+		   SELECT suggestion_id, SUM(added_count)::int AS added
+		   FROM suggestion_usage_daily
+		   WHERE day >= $1::date
+		   GROUP BY suggestion_id
+		 ), matched AS (
+		   SELECT s.id AS suggestion_id,
+		          COUNT(*)::int AS total,
+		          COUNT(*) FILTER (WHERE bi.is_completed)::int AS completed
+		   FROM suggestions s
+		   JOIN bingo_items bi ON suggestion_content_hash(bi.content) = s.content_hash
+		   WHERE s.is_active = true AND bi.created_at >= $1
+		   GROUP BY s.id
+		 )
+		 SELECT s.id, s.category, s.content,
+		        COALESCE(u.added, 0), COALESCE(m.total, 0), COALESCE(m.completed, 0)
+		 FROM suggestions s
+		 LEFT JOIN usage u ON u.suggestion_id = s.id
+		 LEFT JOIN matched m ON m.suggestion_id = s.id
+		 WHERE s.is_active = true
+		 ORDER BY s.category, s.content`,
+		since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting suggestion analytics: %w", err)
+	}
+	defer rows.Close()
+
+	var all []models.SuggestionStats
+	for rows.Next() {
+		var st models.SuggestionStats
+		if err := rows.Scan(&st.SuggestionID, &st.Category, &st.Content, &st.AddedCount, &st.MatchedItems, &st.CompletedItems); err != nil {
+			return nil, fmt.Errorf("scanning suggestion analytics: %w", err)
+		}
+		if st.MatchedItems > 0 {
+			st.CompletionRate = float64(st.CompletedItems) / float64(st.MatchedItems)
+		}
+		all = append(all, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating suggestion analytics: %w", err)
+	}
+
+	var sampled []models.SuggestionStats
+	for _, st := range all {
+		if st.MatchedItems >= suggestionAnalyticsMinSample {
+			sampled = append(sampled, st)
+		}
+	}
+
+	byAdoption := func(a, b models.SuggestionStats) bool { return a.AddedCount > b.AddedCount }
+	byCompletion := func(a, b models.SuggestionStats) bool { return a.CompletionRate > b.CompletionRate }
+
+	return &models.SuggestionAnalytics{
+		WindowDays:        windowDays,
+		MinSample:         suggestionAnalyticsMinSample,
+		MostAdopted:       rankSuggestions(all, byAdoption, limit),
+		LeastAdopted:      rankSuggestions(all, func(a, b models.SuggestionStats) bool { return byAdoption(b, a) }, limit),
+		HighestCompletion: rankSuggestions(sampled, byCompletion, limit),
+		LowestCompletion:  rankSuggestions(sampled, func(a, b models.SuggestionStats) bool { return byCompletion(b, a) }, limit),
+	}, nil
+}
+
+// rankSuggestions returns the first limit stats ordered by less. The input is
+// already sorted by category and content, which breaks ties.
+func rankSuggestions(stats []models.SuggestionStats, less func(a, b models.SuggestionStats) bool, limit int) []models.SuggestionStats {
+	ranked := append([]models.SuggestionStats(nil), stats...)
+	sort.SliceStable(ranked, func(i, j int) bool { return less(ranked[i], ranked[j]) })
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	if ranked == nil {
+		ranked = []models.SuggestionStats{}
+	}
+	return ranked
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// SuggestionCacheTTL is how long the active suggestion pool stays cached.
+const SuggestionCacheTTL = 10 * time.Minute
+
+const (
+	suggestionCacheAllKey        = "suggestions:all"
+	suggestionCacheCategoriesKey = "suggestions:categories"
+)
+
+// SuggestionCache keeps the active suggestions and their categories for
+// SuggestionCacheTTL, in Redis when one is given and in process otherwise.
+// Every suggestion endpoint is answered from these two entries, so
+// Invalidate only has fixed keys to drop. Redis failures only turn into
+// misses.
+type SuggestionCache struct {
+	redis RedisClient
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu    sync.Mutex
+	local map[string]suggestionCacheLocalEntry
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type suggestionCacheLocalEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewSuggestionCache builds a cache backed by redis, or by an in-process map
+// when redis is nil.
+func NewSuggestionCache(redis RedisClient) *SuggestionCache {
+	return &SuggestionCache{
+		redis: redis,
+		ttl:   SuggestionCacheTTL,
+		now:   time.Now,
+		local: make(map[string]suggestionCacheLocalEntry),
+	}
+}
+
+// Stats returns hit and miss counts since startup, keyed by cache name.
+func (c *SuggestionCache) Stats() map[string]models.CacheStats {
+	return map[string]models.CacheStats{
+		"suggestions": {Hits: c.hits.Load(), Misses: c.misses.Load()},
+	}
+}
+
+// Invalidate drops the cached pool. Call it after anything changes the
+// suggestions table.
+func (c *SuggestionCache) Invalidate(ctx context.Context) {
+	if c.redis == nil {
+		c.mu.Lock()
+		clear(c.local)
+		c.mu.Unlock()
+		return
+	}
+	if err := c.redis.Del(ctx, suggestionCacheAllKey, suggestionCacheCategoriesKey); err != nil {
+		logging.Warn("Failed to invalidate suggestion cache", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// get decodes the cached value for key into dest.
+func (c *SuggestionCache) get(ctx context.Context, key string, dest any) bool {
+	var data []byte
+	if c.redis == nil {
+		c.mu.Lock()
+		entry, ok := c.local[key]
+		c.mu.Unlock()
+		if ok && c.now().Before(entry.expiresAt) {
+			data = entry.data
+		}
+	} else if value, err := c.redis.Get(ctx, key); err == nil {
+		data = []byte(value)
+	}
+	if data == nil || json.Unmarshal(data, dest) != nil {
+		c.misses.Add(1)
+		return false
+	}
+	c.hits.Add(1)
+	return true
+}
+
+func (c *SuggestionCache) put(ctx context.Context, key string, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if c.redis == nil {
+		c.mu.Lock()
+		c.local[key] = suggestionCacheLocalEntry{data: data, expiresAt: c.now().Add(c.ttl)}
+		c.mu.Unlock()
+		return
+	}
+	if err := c.redis.Set(ctx, key, string(data), c.ttl); err != nil {
+		logging.Warn("Failed to cache suggestions", map[string]interface{}{"error": err.Error()})
+	}
+}
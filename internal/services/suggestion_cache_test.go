@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// suggestionPoolDB serves a fixed suggestion pool and counts queries.
+func suggestionPoolDB(queries *int) *fakeDB {
+	return &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			*queries++
+			if strings.Contains(sql, "DISTINCT category") {
+				return &fakeRows{rows: [][]any{{"Health & Fitness"}, {"Travel & Adventure"}}}, nil
+			}
+			return &fakeRows{rows: [][]any{
+				{uuid.New(), "Health & Fitness", "Run a 5k", true},
+				{uuid.New(), "Health & Fitness", "Walk 10k steps", true},
+				{uuid.New(), "Travel & Adventure", "Visit a new city", true},
+			}}, nil
+		},
+	}
+}
+
+func TestSuggestionService_CachedReadsSkipDB(t *testing.T) {
+	for name, redis := range map[string]RedisClient{"redis": newMemoryRedis(), "in-process": nil} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			queries := 0
+			svc := NewSuggestionService(suggestionPoolDB(&queries))
+			cache := NewSuggestionCache(redis)
+			svc.SetCache(cache)
+
+			for i := 0; i < 3; i++ {
+				all, err := svc.GetAll(ctx)
+				if err != nil || len(all) != 3 {
+					t.Fatalf("expected 3 suggestions, got %d, %v", len(all), err)
+				}
+				categories, err := svc.GetCategories(ctx)
+				if err != nil || len(categories) != 2 {
+					t.Fatalf("expected 2 categories, got %v, %v", categories, err)
+				}
+			}
+			if queries != 2 {
+				t.Fatalf("expected one query per cached entry, got %d", queries)
+			}
+
+			// Category and grouped reads are answered from the cached pool.
+			health, err := svc.GetByCategory(ctx, "Health & Fitness")
+			if err != nil || len(health) != 2 || health[0].Content != "Run a 5k" {
+				t.Fatalf("expected the 2 health suggestions in order, got %+v, %v", health, err)
+			}
+			grouped, err := svc.GetGroupedByCategory(ctx)
+			if err != nil || len(grouped) != 2 {
+				t.Fatalf("expected 2 groups, got %+v, %v", grouped, err)
+			}
+			if queries != 2 {
+				t.Fatalf("expected no further queries, got %d", queries)
+			}
+
+			svc.InvalidateCache(ctx)
+			if _, err := svc.GetAll(ctx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if queries != 3 {
+				t.Fatalf("expected a query after invalidation, got %d", queries)
+			}
+			if stats := cache.Stats()["suggestions"]; stats.Hits == 0 || stats.Misses != 3 {
+				t.Fatalf("unexpected cache stats: %+v", stats)
+			}
+		})
+	}
+}
+
+func TestSuggestionCache_InProcessEntriesExpire(t *testing.T) {
+	ctx := context.Background()
+	queries := 0
+	svc := NewSuggestionService(suggestionPoolDB(&queries))
+	cache := NewSuggestionCache(nil)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	svc.SetCache(cache)
+
+	if _, err := svc.GetAll(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now = now.Add(SuggestionCacheTTL - time.Second)
+	if _, err := svc.GetAll(ctx); err != nil || queries != 1 {
+		t.Fatalf("expected a hit inside the TTL, got %d queries, %v", queries, err)
+	}
+	now = now.Add(time.Second)
+	if _, err := svc.GetAll(ctx); err != nil || queries != 2 {
+		t.Fatalf("expected a reload once the TTL passed, got %d queries, %v", queries, err)
+	}
+}
+
+func TestSuggestionService_CacheDoesNotStoreErrors(t *testing.T) {
+	ctx := context.Background()
+	fail := true
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if fail {
+				return nil, errors.New("db down")
+			}
+			return &fakeRows{rows: [][]any{{uuid.New(), "Health & Fitness", "Run a 5k", true}}}, nil
+		},
+	}
+	redis := newMemoryRedis()
+	svc := NewSuggestionService(db)
+	svc.SetCache(NewSuggestionCache(redis))
+
+	if _, err := svc.GetAll(ctx); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, ok := redis.values[suggestionCacheAllKey]; ok {
+		t.Fatal("expected a failed load not to be cached")
+	}
+	fail = false
+	if all, err := svc.GetAll(ctx); err != nil || len(all) != 1 {
+		t.Fatalf("expected the pool once the database recovers, got %v, %v", all, err)
+	}
+	if redis.ttls[suggestionCacheAllKey] != SuggestionCacheTTL {
+		t.Fatalf("expected entries cached for %v, got %v", SuggestionCacheTTL, redis.ttls[suggestionCacheAllKey])
+	}
+}
+
+func BenchmarkSuggestionService_CachedGetAll(b *testing.B) {
+	ctx := context.Background()
+	queries := 0
+	svc := NewSuggestionService(suggestionPoolDB(&queries))
+	svc.SetCache(NewSuggestionCache(nil))
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.GetAll(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if queries != 1 {
+		b.Fatalf("expected a single query, got %d", queries)
+	}
+}
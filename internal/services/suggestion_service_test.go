@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -211,3 +212,88 @@ func TestSuggestionService_GetGroupedByCategory_Error(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestSuggestionService_RecordUsage(t *testing.T) {
+	var gotSQL string
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+			gotSQL = sql
+			gotArgs = args
+			return fakeCommandTag{rowsAffected: 1}, nil
+		},
+	}
+
+	svc := NewSuggestionService(db)
+	if err := svc.RecordUsage(context.Background(), []string{"Run a 5K", "Read 12 books"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(gotSQL, "suggestion_content_hash(c.content)") || !strings.Contains(gotSQL, "ON CONFLICT (suggestion_id, day)") {
+		t.Fatalf("unexpected usage sql: %q", gotSQL)
+	}
+	if strings.Contains(gotSQL, "user_id") {
+		t.Fatalf("usage must not store user IDs: %q", gotSQL)
+	}
+	if contents, ok := gotArgs[0].([]string); !ok || len(contents) != 2 {
+		t.Fatalf("unexpected usage args: %v", gotArgs)
+	}
+
+	db.ExecFunc = func(ctx context.Context, sql string, args ...any) (CommandTag, error) {
+		t.Fatal("expected no query for empty contents")
+		return nil, nil
+	}
+	if err := svc.RecordUsage(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSuggestionService_Analytics_Ranks(t *testing.T) {
+	popular := uuid.New()
+	ignored := uuid.New()
+	hard := uuid.New()
+	db := &fakeDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (Rows, error) {
+			if !strings.Contains(sql, "suggestion_usage_daily") || !strings.Contains(sql, "bi.is_completed") {
+				t.Fatalf("unexpected analytics sql: %q", sql)
+			}
+			return &fakeRows{rows: [][]any{
+				{hard, "Finance", "Save $10k", 3, 10, 1},
+				{popular, "Health", "Run a 5K", 40, 20, 15},
+				{ignored, "Travel", "Visit Antarctica", 0, 2, 2},
+			}}, nil
+		},
+	}
+
+	svc := NewSuggestionService(db)
+	got, err := svc.Analytics(context.Background(), time.Now().AddDate(0, 0, -90), 90, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.WindowDays != 90 || got.MinSample != suggestionAnalyticsMinSample {
+		t.Fatalf("unexpected window: %+v", got)
+	}
+	if len(got.MostAdopted) != 2 || got.MostAdopted[0].SuggestionID != popular {
+		t.Fatalf("unexpected most adopted: %+v", got.MostAdopted)
+	}
+	if got.LeastAdopted[0].SuggestionID != ignored {
+		t.Fatalf("unexpected least adopted: %+v", got.LeastAdopted)
+	}
+	// Visit Antarctica has too few matching goals to be ranked by completion.
+	if len(got.HighestCompletion) != 2 || got.HighestCompletion[0].SuggestionID != popular {
+		t.Fatalf("unexpected highest completion: %+v", got.HighestCompletion)
+	}
+	if got.LowestCompletion[0].SuggestionID != hard || got.LowestCompletion[0].CompletionRate != 0.1 {
+		t.Fatalf("unexpected lowest completion: %+v", got.LowestCompletion)
+	}
+}
+
+func TestSuggestionService_Analytics_EmptyLists(t *testing.T) {
+	svc := NewSuggestionService(&fakeDB{})
+	got, err := svc.Analytics(context.Background(), time.Now(), 30, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.MostAdopted == nil || got.HighestCompletion == nil {
+		t.Fatalf("expected empty lists, got %+v", got)
+	}
+}
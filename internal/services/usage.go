@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+const (
+	usageKeyPrefix = "usage:"
+	// UsageRetention is how long a day's request counts are kept after its
+	// last request.
+	UsageRetention = 7 * 24 * time.Hour
+)
+
+// UsageStore is the subset of Redis the usage counters need.
+type UsageStore interface {
+	HIncrBy(ctx context.Context, key, field string, incr int64) error
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+}
+
+// UsageLimitSpec describes a per-user rate limiter whose counter lives at
+// KeyPrefix + user ID, so usage reports can read how much of it is spent.
+// Requests made with an API token count against that token's own counter,
+// which the report doesn't include.
+type UsageLimitSpec struct {
+	Name      string
+	Limit     int64
+	Window    time.Duration
+	KeyPrefix string
+}
+
+// UsageService counts each user's API requests per UTC day in a Redis hash
+// keyed by route group, and reports them next to the user's rate limits.
+type UsageService struct {
+	store             UsageStore
+	limits            []UsageLimitSpec
+	aiFreeGenerations int
+	now               func() time.Time
+}
+
+func NewUsageService(store UsageStore) *UsageService {
+	return &UsageService{store: store, now: time.Now}
+}
+
+// SetLimits sets the rate limits included in usage reports, in order.
+func (s *UsageService) SetLimits(limits ...UsageLimitSpec) {
+	s.limits = limits
+}
+
+// SetAIFreeGenerations sets the number of AI generations unverified users may
+// run, so reports can include how many they have left.
+func (s *UsageService) SetAIFreeGenerations(n int) {
+	s.aiFreeGenerations = n
+}
+
+func usageKey(userID uuid.UUID, day time.Time) string {
+	return usageKeyPrefix + userID.String() + ":" + day.UTC().Format("2006-01-02")
+}
+
+// Record counts one request from the user to the route group.
+func (s *UsageService) Record(ctx context.Context, userID uuid.UUID, group string) error {
+	key := usageKey(userID, s.now())
+	if err := s.store.HIncrBy(ctx, key, group, 1); err != nil {
+		return fmt.Errorf("increment usage: %w", err)
+	}
+	if err := s.store.Expire(ctx, key, UsageRetention); err != nil {
+		return fmt.Errorf("expire usage: %w", err)
+	}
+	return nil
+}
+
+// Get reports the user's request counts for today and how much of each rate
+// limit they have used.
+func (s *UsageService) Get(ctx context.Context, user *models.User) (*models.UsageReport, error) {
+	now := s.now()
+	counts, err := s.store.HGetAll(ctx, usageKey(user.ID, now))
+	if err != nil {
+		return nil, fmt.Errorf("load usage: %w", err)
+	}
+
+	report := &models.UsageReport{
+		Date:     now.UTC().Format("2006-01-02"),
+		Requests: make(map[string]int64, len(counts)),
+		Limits:   make([]models.UsageLimit, 0, len(s.limits)),
+	}
+	for group, value := range counts {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		report.Requests[group] = count
+		report.TotalRequests += count
+	}
+
+	for _, spec := range s.limits {
+		used, err := s.limitUsed(ctx, spec.KeyPrefix+user.ID.String())
+		if err != nil {
+			return nil, fmt.Errorf("load %s limit: %w", spec.Name, err)
+		}
+		report.Limits = append(report.Limits, models.UsageLimit{
+			Name:          spec.Name,
+			Limit:         spec.Limit,
+			WindowSeconds: int64(spec.Window / time.Second),
+			Used:          used,
+			Remaining:     max(spec.Limit-used, 0),
+		})
+	}
+
+	if !user.EmailVerified {
+		remaining := max(s.aiFreeGenerations-user.AIFreeGenerationsUsed, 0)
+		report.AIFreeGenerationsRemaining = &remaining
+	}
+	return report, nil
+}
+
+// limitUsed reads a rate limiter counter. A missing key means the window
+// expired, so nothing is used.
+func (s *UsageService) limitUsed(ctx context.Context, key string) (int64, error) {
+	value, err := s.store.Get(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	used, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, nil
+	}
+	return used, nil
+}
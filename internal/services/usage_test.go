@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+type fakeUsageStore struct {
+	hashes  map[string]map[string]int64
+	values  map[string]string
+	expires map[string]time.Duration
+	err     error
+}
+
+func newFakeUsageStore() *fakeUsageStore {
+	return &fakeUsageStore{
+		hashes:  map[string]map[string]int64{},
+		values:  map[string]string{},
+		expires: map[string]time.Duration{},
+	}
+}
+
+func (f *fakeUsageStore) HIncrBy(ctx context.Context, key, field string, incr int64) error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.hashes[key] == nil {
+		f.hashes[key] = map[string]int64{}
+	}
+	f.hashes[key][field] += incr
+	return nil
+}
+
+func (f *fakeUsageStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := map[string]string{}
+	for field, count := range f.hashes[key] {
+		out[field] = strconv.FormatInt(count, 10)
+	}
+	return out, nil
+}
+
+func (f *fakeUsageStore) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	f.expires[key] = expiration
+	return nil
+}
+
+func (f *fakeUsageStore) Get(ctx context.Context, key string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	value, ok := f.values[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func TestUsageService_RecordAndGet(t *testing.T) {
+	store := newFakeUsageStore()
+	svc := NewUsageService(store)
+	svc.now = func() time.Time { return time.Date(2026, time.March, 4, 23, 30, 0, 0, time.UTC) }
+	svc.SetAIFreeGenerations(5)
+	svc.SetLimits(
+		UsageLimitSpec{Name: "ai", Limit: 10, Window: time.Hour, KeyPrefix: "ratelimit:ai:"},
+		UsageLimitSpec{Name: "reactions", Limit: 60, Window: time.Minute, KeyPrefix: "ratelimit:reactions:"},
+	)
+	user := &models.User{ID: uuid.New(), AIFreeGenerationsUsed: 2}
+	store.values["ratelimit:ai:"+user.ID.String()] = "12"
+
+	for _, group := range []string{"cards", "cards", "ai"} {
+		if err := svc.Record(context.Background(), user.ID, group); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+	key := "usage:" + user.ID.String() + ":2026-03-04"
+	if store.expires[key] != UsageRetention {
+		t.Fatalf("expected %s retention on %s, got %v", UsageRetention, key, store.expires)
+	}
+
+	report, err := svc.Get(context.Background(), user)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if report.Date != "2026-03-04" || report.Requests["cards"] != 2 || report.Requests["ai"] != 1 || report.TotalRequests != 3 {
+		t.Fatalf("unexpected counts: %+v", report)
+	}
+	if len(report.Limits) != 2 {
+		t.Fatalf("expected two limits, got %+v", report.Limits)
+	}
+	if ai := report.Limits[0]; ai.Used != 12 || ai.Remaining != 0 || ai.WindowSeconds != 3600 {
+		t.Fatalf("expected the ai limit spent, got %+v", ai)
+	}
+	if reactions := report.Limits[1]; reactions.Used != 0 || reactions.Remaining != 60 {
+		t.Fatalf("expected an unused reactions limit, got %+v", reactions)
+	}
+	if report.AIFreeGenerationsRemaining == nil || *report.AIFreeGenerationsRemaining != 3 {
+		t.Fatalf("expected 3 free generations left, got %v", report.AIFreeGenerationsRemaining)
+	}
+
+	user.EmailVerified = true
+	report, err = svc.Get(context.Background(), user)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if report.AIFreeGenerationsRemaining != nil {
+		t.Fatal("expected no free generation count for verified users")
+	}
+}
+
+func TestUsageService_StoreErrors(t *testing.T) {
+	store := newFakeUsageStore()
+	store.err = errors.New("redis down")
+	svc := NewUsageService(store)
+
+	if err := svc.Record(context.Background(), uuid.New(), "cards"); err == nil {
+		t.Fatal("expected record error")
+	}
+	if _, err := svc.Get(context.Background(), &models.User{ID: uuid.New()}); err == nil {
+		t.Fatal("expected get error")
+	}
+}
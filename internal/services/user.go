@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -15,8 +17,13 @@ var (
 	ErrUserNotFound          = errors.New("user not found")
 	ErrEmailAlreadyExists    = errors.New("email already exists")
 	ErrUsernameAlreadyExists = errors.New("username already taken")
+	ErrNoUsernameSuggestion  = errors.New("no available username suggestion")
 )
 
+// maxSuggestionSuffix bounds the numbered candidates (jane2, jane3, ...) tried
+// before falling back to random suffixes.
+const maxSuggestionSuffix = 20
+
 type UserService struct {
 	db DBConn
 }
@@ -138,3 +145,53 @@ func (s *UserService) UpdateSearchable(ctx context.Context, userID uuid.UUID, se
 
 	return nil
 }
+
+// SuggestUsername derives an available username from the local part of an
+// email, e.g. "jane.doe+news@example.com" -> "jane.doe", then "jane.doe2", ...
+func (s *UserService) SuggestUsername(ctx context.Context, email string) (string, error) {
+	base := usernameBaseFromEmail(email)
+	candidates := []string{base}
+	for i := 2; i <= maxSuggestionSuffix; i++ {
+		candidates = append(candidates, fmt.Sprintf("%s%d", base, i))
+	}
+	for i := 0; i < 5; i++ {
+		candidates = append(candidates, fmt.Sprintf("%s%d", base, 1000+rand.Intn(9000)))
+	}
+
+	for _, candidate := range candidates {
+		var exists bool
+		err := s.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(username) = LOWER($1) AND deleted_at IS NULL)", candidate).Scan(&exists)
+		if err != nil {
+			return "", fmt.Errorf("checking username existence: %w", err)
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+	return "", ErrNoUsernameSuggestion
+}
+
+func usernameBaseFromEmail(email string) string {
+	local := strings.ToLower(strings.TrimSpace(email))
+	if at := strings.Index(local, "@"); at >= 0 {
+		local = local[:at]
+	}
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+
+	var b strings.Builder
+	for _, r := range local {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	base := strings.Trim(b.String(), ".-_")
+	if len(base) > 90 {
+		base = base[:90]
+	}
+	if len(base) < 2 {
+		base = "user" + base
+	}
+	return base
+}
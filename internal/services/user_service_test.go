@@ -455,3 +455,52 @@ func TestUserService_UpdateSearchable_Success(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestUserService_SuggestUsername_SkipsTakenNames(t *testing.T) {
+	taken := map[string]bool{"jane.doe": true, "jane.doe2": true}
+	var checked []string
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			name := args[0].(string)
+			checked = append(checked, name)
+			return rowFromValues(taken[name])
+		},
+	}
+
+	service := NewUserService(db)
+	got, err := service.SuggestUsername(context.Background(), "Jane.Doe+news@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "jane.doe3" {
+		t.Fatalf("expected jane.doe3, got %q (checked %v)", got, checked)
+	}
+}
+
+func TestUserService_SuggestUsername_AllTaken(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(true)
+		},
+	}
+
+	service := NewUserService(db)
+	if _, err := service.SuggestUsername(context.Background(), "jane@example.com"); !errors.Is(err, ErrNoUsernameSuggestion) {
+		t.Fatalf("expected ErrNoUsernameSuggestion, got %v", err)
+	}
+}
+
+func TestUsernameBaseFromEmail(t *testing.T) {
+	tests := map[string]string{
+		"jane@example.com":         "jane",
+		"Jane.Doe+tag@example.com": "jane.doe",
+		"j@example.com":            "userj",
+		"_.@example.com":           "user",
+		"名前@example.com":           "user",
+	}
+	for input, want := range tests {
+		if got := usernameBaseFromEmail(input); got != want {
+			t.Fatalf("usernameBaseFromEmail(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
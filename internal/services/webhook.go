@@ -0,0 +1,484 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/logging"
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+const (
+	// MaxWebhooksPerUser caps the webhooks of one account.
+	MaxWebhooksPerUser = 5
+
+	// webhookMaxAttempts is how many times a delivery is tried before it is
+	// marked failed.
+	webhookMaxAttempts = 5
+
+	// webhookClaimLease is how long a claimed delivery is hidden from other
+	// runner passes while it is being sent. A runner that dies mid-send
+	// leaves the delivery to be retried once the lease runs out.
+	webhookClaimLease = 5 * time.Minute
+
+	webhookRequestTimeout = 10 * time.Second
+	webhookRecentLimit    = 10
+	webhookMaxURLLength   = 2048
+	webhookMaxErrorLength = 500
+)
+
+// webhookBackoff is the wait before each retry; the nth failed attempt waits
+// webhookBackoff[n-1].
+var webhookBackoff = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour}
+
+var (
+	ErrWebhookNotFound   = errors.New("webhook not found")
+	ErrWebhookLimit      = errors.New("too many webhooks")
+	ErrInvalidWebhookURL = errors.New("webhook URL must be an https URL")
+
+	errWebhookDestination = errors.New("webhook destination is not a public address")
+)
+
+// WebhookService stores a user's webhooks and delivers card events to them.
+// Events are queued by Enqueue and sent by DeliverDue, which the webhook
+// runner calls on a timer, so a slow endpoint never holds up a card write.
+type WebhookService struct {
+	db      DB
+	client  *http.Client
+	now     func() time.Time
+	cleanup cleanupLimits
+}
+
+func NewWebhookService(db DB) *WebhookService {
+	return &WebhookService{
+		db:      db,
+		client:  newWebhookClient(),
+		now:     time.Now,
+		cleanup: defaultCleanupLimits,
+	}
+}
+
+// newWebhookClient returns a client that only connects to public addresses
+// and doesn't follow redirects, so a webhook can't be pointed at the
+// server's own network.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return errWebhookDestination
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout:   webhookRequestTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// NewPublicHTTPClient returns the client webhooks use, for other requests to
+// URLs that users or remote servers supply.
+func NewPublicHTTPClient() *http.Client {
+	return newWebhookClient()
+}
+
+// nonPublicPrefixes are special-purpose ranges that net.IP's checks miss:
+// carrier-grade NAT, IETF protocol assignments, benchmarking, the reserved
+// 240/4 block and NAT64, which maps onto any IPv4 address.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateWebhookURL accepts absolute https URLs without credentials.
+func validateWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > webhookMaxURLLength {
+		return "", ErrInvalidWebhookURL
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return "", ErrInvalidWebhookURL
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !isPublicIP(ip) {
+		return "", ErrInvalidWebhookURL
+	}
+	return u.String(), nil
+}
+
+// List returns the user's webhooks, oldest first, each with its most recent
+// deliveries. Secrets are not included.
+func (s *WebhookService) List(ctx context.Context, userID uuid.UUID) ([]models.Webhook, error) {
+	rows, err := s.db.Query(ctx,
+		"SELECT id, user_id, url, created_at FROM webhooks WHERE user_id = $1 ORDER BY created_at",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []models.Webhook
+	index := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var hook models.Webhook
+		if err := rows.Scan(&hook.ID, &hook.UserID, &hook.URL, &hook.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning webhook: %w", err)
+		}
+		hook.RecentDeliveries = []models.WebhookDelivery{}
+		index[hook.ID] = len(webhooks)
+		webhooks = append(webhooks, hook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return webhooks, nil
+	}
+
+	rows, err = s.db.Query(ctx, `
+		SELECT d.webhook_id, d.id, d.event, d.status, d.attempts, d.response_status,
+		       d.last_error, d.next_attempt_at, d.delivered_at, d.created_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE w.user_id = $1
+		ORDER BY d.created_at DESC, d.id
+		LIMIT $2`,
+		userID, webhookRecentLimit*len(webhooks),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var webhookID uuid.UUID
+		var delivery models.WebhookDelivery
+		var nextAttemptAt time.Time
+		if err := rows.Scan(
+			&webhookID,
+			&delivery.ID,
+			&delivery.Event,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.ResponseStatus,
+			&delivery.LastError,
+			&nextAttemptAt,
+			&delivery.DeliveredAt,
+			&delivery.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning webhook delivery: %w", err)
+		}
+		if delivery.Status == models.WebhookDeliveryPending {
+			delivery.NextAttemptAt = &nextAttemptAt
+		}
+		i, ok := index[webhookID]
+		if !ok || len(webhooks[i].RecentDeliveries) >= webhookRecentLimit {
+			continue
+		}
+		webhooks[i].RecentDeliveries = append(webhooks[i].RecentDeliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing webhook deliveries: %w", err)
+	}
+	return webhooks, nil
+}
+
+// Create adds a webhook for rawURL with a new signing secret. The returned
+// webhook is the only place the secret is shown.
+func (s *WebhookService) Create(ctx context.Context, userID uuid.UUID, rawURL string) (*models.Webhook, error) {
+	hookURL, err := validateWebhookURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM webhooks WHERE user_id = $1", userID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("counting webhooks: %w", err)
+	}
+	if count >= MaxWebhooksPerUser {
+		return nil, ErrWebhookLimit
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hook := &models.Webhook{UserID: userID, URL: hookURL, Secret: secret, RecentDeliveries: []models.WebhookDelivery{}}
+	err = s.db.QueryRow(ctx,
+		"INSERT INTO webhooks (user_id, url, secret) VALUES ($1, $2, $3) RETURNING id, created_at",
+		userID, hookURL, secret,
+	).Scan(&hook.ID, &hook.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("creating webhook: %w", err)
+	}
+	return hook, nil
+}
+
+// Delete removes one of the user's webhooks along with its deliveries.
+func (s *WebhookService) Delete(ctx context.Context, userID, webhookID uuid.UUID) error {
+	result, err := s.db.Exec(ctx, "DELETE FROM webhooks WHERE id = $1 AND user_id = $2", webhookID, userID)
+	if err != nil {
+		return fmt.Errorf("deleting webhook: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// webhookPayload is the JSON body of every delivery.
+type webhookPayload struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// Enqueue queues event for each of the user's webhooks. Users without
+// webhooks cost one insert that matches nothing.
+func (s *WebhookService) Enqueue(ctx context.Context, userID uuid.UUID, event string, data any) error {
+	now := s.now()
+	payload, err := json.Marshal(webhookPayload{Event: event, OccurredAt: now.UTC(), Data: data})
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+	_, err = s.db.Exec(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, next_attempt_at, created_at)
+		SELECT id, $2, $3, $4, $4 FROM webhooks WHERE user_id = $1`,
+		userID, event, string(payload), now,
+	)
+	if err != nil {
+		return fmt.Errorf("queueing webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+type webhookJob struct {
+	ID       uuid.UUID
+	Event    string
+	Payload  string
+	Attempts int
+	URL      string
+	Secret   string
+}
+
+// DeliverDue sends up to limit pending deliveries whose next attempt is due
+// and returns how many were delivered. Deliveries are claimed in a short
+// transaction and sent after it commits, so concurrent runners don't send
+// the same delivery and no lock is held during the HTTP requests.
+func (s *WebhookService) DeliverDue(ctx context.Context, limit int) (int, error) {
+	jobs, err := s.claimDue(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		status, sendErr := s.send(ctx, job)
+		if err := s.recordAttempt(ctx, job, status, sendErr); err != nil {
+			logging.Error("Failed to record webhook delivery", map[string]interface{}{
+				"error":       err.Error(),
+				"delivery_id": job.ID.String(),
+			})
+			continue
+		}
+		if sendErr == nil {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+func (s *WebhookService) claimDue(ctx context.Context, limit int) ([]webhookJob, error) {
+	now := s.now()
+	tx, ctx, err := beginTx(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("begin webhook claim tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		SELECT d.id, d.event, d.payload, d.attempts, w.url, w.secret
+		  FROM webhook_deliveries d
+		  JOIN webhooks w ON w.id = d.webhook_id
+		  JOIN users u ON u.id = w.user_id AND u.deleted_at IS NULL
+		 WHERE d.status = 'pending'
+		   AND d.next_attempt_at <= $1
+		 ORDER BY d.next_attempt_at ASC
+		 LIMIT $2
+		 FOR UPDATE OF d SKIP LOCKED`,
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []webhookJob
+	var ids []uuid.UUID
+	for rows.Next() {
+		var job webhookJob
+		if err := rows.Scan(&job.ID, &job.Event, &job.Payload, &job.Attempts, &job.URL, &job.Secret); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		jobs = append(jobs, job)
+		ids = append(ids, job.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query due webhook deliveries: %w", err)
+	}
+	rows.Close()
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE webhook_deliveries SET next_attempt_at = $2 WHERE id = ANY($1)",
+		ids, now.Add(webhookClaimLease),
+	); err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit webhook claim tx: %w", err)
+	}
+	return jobs, nil
+}
+
+// signWebhook returns the signature header value for a delivery body sent at
+// timestamp: the hex HMAC-SHA256 of "<timestamp>.<body>" under the webhook's
+// secret. Including the timestamp lets receivers reject replays.
+func signWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// send posts one delivery. It returns the response status, if any, and an
+// error unless the endpoint answered 2xx.
+func (s *WebhookService) send(ctx context.Context, job webhookJob) (int, error) {
+	body := []byte(job.Payload)
+	timestamp := s.now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "YearOfBingo-Webhooks/1.0")
+	req.Header.Set("X-Bingo-Event", job.Event)
+	req.Header.Set("X-Bingo-Delivery", job.ID.String())
+	req.Header.Set("X-Bingo-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Bingo-Signature", signWebhook(job.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// recordAttempt stores the outcome of one send: delivered, retried after
+// the next backoff step, or failed once attempts run out.
+func (s *WebhookService) recordAttempt(ctx context.Context, job webhookJob, status int, sendErr error) error {
+	now := s.now()
+	attempts := job.Attempts + 1
+	var responseStatus *int
+	if status != 0 {
+		responseStatus = &status
+	}
+
+	if sendErr == nil {
+		_, err := s.db.Exec(ctx, `
+			UPDATE webhook_deliveries
+			SET status = 'delivered', attempts = $2, response_status = $3, last_error = NULL, delivered_at = $4
+			WHERE id = $1`,
+			job.ID, attempts, responseStatus, now,
+		)
+		return err
+	}
+
+	lastError := sendErr.Error()
+	if len(lastError) > webhookMaxErrorLength {
+		lastError = lastError[:webhookMaxErrorLength]
+	}
+	newStatus := models.WebhookDeliveryFailed
+	nextAttemptAt := now
+	if attempts < webhookMaxAttempts {
+		newStatus = models.WebhookDeliveryPending
+		nextAttemptAt = now.Add(webhookBackoff[min(attempts, len(webhookBackoff))-1])
+	}
+	_, err := s.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_status = $4, last_error = $5, next_attempt_at = $6
+		WHERE id = $1`,
+		job.ID, newStatus, attempts, responseStatus, lastError, nextAttemptAt,
+	)
+	return err
+}
+
+// CleanupOld deletes deliveries older than the retention window, whatever
+// their status.
+func (s *WebhookService) CleanupOld(ctx context.Context) (int, error) {
+	deadline := time.Now().Add(s.cleanup.budget)
+	return batchDelete(ctx, s.db, "webhook_deliveries", "created_at < NOW() - INTERVAL '30 days'", s.cleanup, deadline)
+}
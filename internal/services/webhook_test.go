@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	cases := map[string]bool{
+		"https://hooks.example.com/bingo":  true,
+		" https://hooks.example.com/x?a=1": true,
+		"http://hooks.example.com/bingo":   false,
+		"https://user:pw@example.com/":     false,
+		"https:///nohost":                  false,
+		"https://127.0.0.1/hook":           false,
+		"https://10.0.0.8/hook":            false,
+		"https://[::1]/hook":               false,
+		"https://169.254.169.254/latest":   false,
+		"https://100.64.0.1/hook":          false,
+		"https://100.127.255.254/hook":     false,
+		"https://192.0.0.8/hook":           false,
+		"https://198.18.0.1/hook":          false,
+		"https://198.19.255.1/hook":        false,
+		"https://240.0.0.1/hook":           false,
+		"https://255.255.255.255/hook":     false,
+		"https://[64:ff9b::a00:1]/hook":    false,
+		"https://[::ffff:100.64.0.1]/hook": false,
+		"https://100.128.0.1/hook":         true,
+		"https://198.20.0.1/hook":          true,
+		"https://[2606:4700::1111]/hook":   true,
+		"ftp://example.com/":               false,
+		"":                                 false,
+	}
+	for raw, ok := range cases {
+		_, err := validateWebhookURL(raw)
+		if ok && err != nil {
+			t.Errorf("%q: unexpected error %v", raw, err)
+		}
+		if !ok && !errors.Is(err, ErrInvalidWebhookURL) {
+			t.Errorf("%q: expected ErrInvalidWebhookURL, got %v", raw, err)
+		}
+	}
+}
+
+func TestWebhookService_CreateLimit(t *testing.T) {
+	db := &fakeDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) Row {
+			return rowFromValues(MaxWebhooksPerUser)
+		},
+	}
+	_, err := NewWebhookService(db).Create(context.Background(), uuid.New(), "https://hooks.example.com/bingo")
+	if !errors.Is(err, ErrWebhookLimit) {
+		t.Fatalf("expected ErrWebhookLimit, got %v", err)
+	}
+}
+
+func TestWebhookClient_RefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	_, err := newWebhookClient().Get(srv.URL)
+	if !errors.Is(err, errWebhookDestination) {
+		t.Fatalf("expected errWebhookDestination, got %v", err)
+	}
+}
+
+type receivedWebhook struct {
+	event     string
+	signature string
+	timestamp string
+	body      []byte
+}
+
+func TestWebhookService_DeliversCardEvents(t *testing.T) {
+	ctx := context.Background()
+	db := newSQLiteTestDB(t)
+	user, err := NewUserService(db).Create(ctx, models.CreateUserParams{Email: "solo@example.com", Username: "solo"})
+	if err != nil {
+		t.Fatalf("unexpected error creating user: %v", err)
+	}
+
+	var mu sync.Mutex
+	var received []receivedWebhook
+	status := http.StatusOK
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, receivedWebhook{
+			event:     r.Header.Get("X-Bingo-Event"),
+			signature: r.Header.Get("X-Bingo-Signature"),
+			timestamp: r.Header.Get("X-Bingo-Timestamp"),
+			body:      body,
+		})
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	hooks := NewWebhookService(db)
+	hooks.client = srv.Client()
+	hook, err := hooks.Create(ctx, user.ID, "https://hooks.example.com/bingo")
+	if err != nil {
+		t.Fatalf("unexpected error creating webhook: %v", err)
+	}
+	if len(hook.Secret) != 64 {
+		t.Fatalf("expected a 64 character secret, got %q", hook.Secret)
+	}
+	// The test server listens on loopback, which Create refuses.
+	if _, err := db.Exec(ctx, "UPDATE webhooks SET url = $1 WHERE id = $2", srv.URL, hook.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cards := NewCardService(db)
+	cards.SetWebhookEnqueuer(hooks)
+	card, err := cards.Create(ctx, models.CreateCardParams{UserID: user.ID, Year: 2026, GridSize: 2, Header: "BI"})
+	if err != nil {
+		t.Fatalf("unexpected error creating card: %v", err)
+	}
+	for _, content := range []string{"Run", "Read", "Cook", "Hike"} {
+		if _, err := cards.AddItem(ctx, user.ID, models.AddItemParams{CardID: card.ID, Content: content}); err != nil {
+			t.Fatalf("unexpected error adding %q: %v", content, err)
+		}
+	}
+	if _, err := cards.Finalize(ctx, user.ID, card.ID, nil); err != nil {
+		t.Fatalf("unexpected error finalizing: %v", err)
+	}
+	for _, position := range []int{0, 1} {
+		if _, err := cards.CompleteItem(ctx, user.ID, card.ID, position, models.CompleteItemParams{}); err != nil {
+			t.Fatalf("unexpected error completing %d: %v", position, err)
+		}
+	}
+
+	n, err := hooks.DeliverDue(ctx, 10)
+	if err != nil || n != 4 {
+		t.Fatalf("expected four deliveries, got %d %v", n, err)
+	}
+	events := map[string]int{}
+	for _, got := range received {
+		events[got.event]++
+		ts, err := strconv.ParseInt(got.timestamp, 10, 64)
+		if err != nil {
+			t.Fatalf("bad timestamp %q", got.timestamp)
+		}
+		if want := signWebhook(hook.Secret, ts, got.body); got.signature != want {
+			t.Fatalf("bad signature for %s: got %q want %q", got.event, got.signature, want)
+		}
+		var payload struct {
+			Event string         `json:"event"`
+			Data  map[string]any `json:"data"`
+		}
+		if err := json.Unmarshal(got.body, &payload); err != nil || payload.Event != got.event || payload.Data["card_id"] != card.ID.String() {
+			t.Fatalf("unexpected payload %s (%v)", got.body, err)
+		}
+	}
+	want := map[string]int{
+		models.WebhookEventCardFinalized: 1,
+		models.WebhookEventItemCompleted: 2,
+		models.WebhookEventBingo:         1,
+	}
+	for event, count := range want {
+		if events[event] != count {
+			t.Fatalf("expected %d %s deliveries, got %v", count, event, events)
+		}
+	}
+	if n, err := hooks.DeliverDue(ctx, 10); err != nil || n != 0 {
+		t.Fatalf("expected nothing left to deliver, got %d %v", n, err)
+	}
+
+	// A failing endpoint is retried with backoff, then given up on.
+	mu.Lock()
+	status = http.StatusBadGateway
+	mu.Unlock()
+	if _, err := cards.CompleteItem(ctx, user.ID, card.ID, 2, models.CompleteItemParams{}); err != nil {
+		t.Fatalf("unexpected error completing: %v", err)
+	}
+	if n, err := hooks.DeliverDue(ctx, 10); err != nil || n != 0 {
+		t.Fatalf("expected the delivery to fail, got %d %v", n, err)
+	}
+	listed, err := hooks.List(ctx, user.ID)
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("expected one webhook, got %d %v", len(listed), err)
+	}
+	latest := listed[0].RecentDeliveries[0]
+	if latest.Status != models.WebhookDeliveryPending || latest.Attempts != 1 ||
+		latest.ResponseStatus == nil || *latest.ResponseStatus != http.StatusBadGateway ||
+		latest.NextAttemptAt == nil || time.Until(*latest.NextAttemptAt) < 30*time.Second {
+		t.Fatalf("expected a delivery waiting to retry, got %+v", latest)
+	}
+	if listed[0].Secret != "" {
+		t.Fatal("expected List to leave out the secret")
+	}
+
+	if _, err := db.Exec(ctx,
+		"UPDATE webhook_deliveries SET attempts = $1, next_attempt_at = $2 WHERE id = $3",
+		webhookMaxAttempts-1, time.Now().Add(-time.Minute), latest.ID,
+	); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := hooks.DeliverDue(ctx, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	listed, err = hooks.List(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := false
+	for _, got := range listed[0].RecentDeliveries {
+		if got.ID != latest.ID {
+			continue
+		}
+		found = true
+		if got.Status != models.WebhookDeliveryFailed || got.LastError == nil || got.NextAttemptAt != nil {
+			t.Fatalf("expected a failed delivery, got %+v", got)
+		}
+	}
+	if !found {
+		t.Fatalf("delivery %s missing from %+v", latest.ID, listed[0].RecentDeliveries)
+	}
+
+	if _, err := hooks.CleanupOld(ctx); err != nil {
+		t.Fatalf("unexpected cleanup error: %v", err)
+	}
+	if err := hooks.Delete(ctx, user.ID, hook.ID); err != nil {
+		t.Fatalf("unexpected error deleting: %v", err)
+	}
+	if err := hooks.Delete(ctx, user.ID, hook.ID); !errors.Is(err, ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
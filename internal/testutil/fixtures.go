@@ -0,0 +1,333 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/HammerMeetNail/yearofbingo/internal/models"
+)
+
+// FixtureTime is the created/updated time every fixture starts with, so rows
+// and expectations built from the same fixture always agree.
+var FixtureTime = time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// Each fixture below holds a model plus whatever extra columns the queries
+// that load it select. Its Row methods return the values in the order those
+// queries scan them, so a test feeds the same fixture to a fake database and
+// compares the service's result against it. When a SELECT column list
+// changes, the matching Row method is the only place to update.
+
+// TestUser is a user fixture with the account columns models.User omits.
+type TestUser struct {
+	models.User
+	ProfileVisibility string
+	ProfileIndexable  bool
+	DataMinimization  bool
+	DeletedAt         *time.Time
+}
+
+// UserOption customizes a TestUser.
+type UserOption func(*TestUser)
+
+// WithEmail sets the user's email address.
+func WithEmail(email string) UserOption {
+	return func(u *TestUser) { u.Email = email }
+}
+
+// WithDataMinimization turns on the user's data minimization preference.
+func WithDataMinimization() UserOption {
+	return func(u *TestUser) { u.DataMinimization = true }
+}
+
+// NewTestUser returns a verified, searchable user with a public profile.
+func NewTestUser(opts ...UserOption) *TestUser {
+	verifiedAt := FixtureTime
+	u := &TestUser{
+		User: models.User{
+			ID:                    uuid.New(),
+			Email:                 "test@example.com",
+			Username:              "testuser",
+			EmailVerified:         true,
+			EmailVerifiedAt:       &verifiedAt,
+			AIFreeGenerationsUsed: 2,
+			Searchable:            true,
+			CreatedAt:             FixtureTime,
+			UpdatedAt:             FixtureTime,
+		},
+		ProfileVisibility: models.ProfileVisibilityPublic,
+		ProfileIndexable:  true,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// ExportRow returns the user as AccountService's data export selects it.
+func (u *TestUser) ExportRow() []any {
+	return []any{
+		u.ID, u.Email, u.Username, u.EmailVerified, u.EmailVerifiedAt, u.AIFreeGenerationsUsed,
+		u.Searchable, u.ProfileVisibility, u.ProfileIndexable, u.DataMinimization,
+		u.CreatedAt, u.UpdatedAt, u.DeletedAt,
+	}
+}
+
+// TestCard is a card fixture. Its goals are in Items.
+type TestCard struct {
+	models.BingoCard
+	itemCount      int
+	completedCount int
+}
+
+// CardOption customizes a TestCard.
+type CardOption func(*TestCard)
+
+// WithCardOwner sets the card's owner.
+func WithCardOwner(userID uuid.UUID) CardOption {
+	return func(c *TestCard) { c.UserID = userID }
+}
+
+// WithGridSize sets the grid size and the matching slice of "BINGO" as the
+// header. The FREE square, if any, moves to the new center.
+func WithGridSize(size int) CardOption {
+	return func(c *TestCard) {
+		c.GridSize = size
+		c.HeaderText = "BINGO"[:min(size, 5)]
+		if c.HasFreeSpace {
+			c.FreeSpacePos = centerOf(size)
+		}
+	}
+}
+
+// WithoutFreeSpace removes the FREE square.
+func WithoutFreeSpace() CardOption {
+	return func(c *TestCard) {
+		c.HasFreeSpace = false
+		c.FreeSpacePos = nil
+	}
+}
+
+// WithDraft leaves the card unfinalized.
+func WithDraft() CardOption {
+	return func(c *TestCard) { c.IsFinalized = false }
+}
+
+// WithArchived archives the card.
+func WithArchived() CardOption {
+	return func(c *TestCard) { c.IsArchived = true }
+}
+
+// WithItems fills the first n squares, skipping the FREE square, with goals
+// named "Goal 1", "Goal 2" and so on.
+func WithItems(n int) CardOption {
+	return func(c *TestCard) { c.itemCount = n }
+}
+
+// WithCompletedItems marks the first n goals completed.
+func WithCompletedItems(n int) CardOption {
+	return func(c *TestCard) { c.completedCount = n }
+}
+
+// NewTestCard returns a finalized, active 5x5 card with a centered FREE
+// square and no goals unless WithItems is given.
+func NewTestCard(opts ...CardOption) *TestCard {
+	start, end := models.DefaultCardPeriod(2025)
+	c := &TestCard{BingoCard: models.BingoCard{
+		ID:               uuid.New(),
+		UserID:           uuid.New(),
+		Year:             2025,
+		GridSize:         5,
+		HeaderText:       "BINGO",
+		HasFreeSpace:     true,
+		FreeSpacePos:     centerOf(5),
+		IsActive:         true,
+		IsFinalized:      true,
+		VisibleToFriends: true,
+		StartDate:        start,
+		EndDate:          end,
+		CreatedAt:        FixtureTime,
+		UpdatedAt:        FixtureTime,
+	}}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.Items = make([]models.BingoItem, 0, c.itemCount)
+	for pos := 0; len(c.Items) < c.itemCount && pos < c.GridSize*c.GridSize; pos++ {
+		if c.FreeSpacePos != nil && *c.FreeSpacePos == pos {
+			continue
+		}
+		item := models.BingoItem{
+			ID:        uuid.New(),
+			CardID:    c.ID,
+			Position:  pos,
+			Content:   fmt.Sprintf("Goal %d", len(c.Items)+1),
+			CreatedAt: FixtureTime,
+		}
+		if len(c.Items) < c.completedCount {
+			completedAt := FixtureTime
+			item.IsCompleted = true
+			item.CompletedAt = &completedAt
+		}
+		c.Items = append(c.Items, item)
+	}
+	return c
+}
+
+func centerOf(size int) *int {
+	pos := size * size / 2
+	return &pos
+}
+
+// Row returns the card as the services select it from bingo_cards.
+func (c *TestCard) Row() []any {
+	return []any{
+		c.ID, c.UserID, c.Year, c.Category, c.Title, c.GridSize, c.HeaderText, c.HasFreeSpace, c.FreeSpacePos,
+		c.IsActive, c.IsFinalized, c.VisibleToFriends, c.IsArchived, c.CreatedAt, c.UpdatedAt,
+		c.FreeSpaceText, c.StartDate, c.EndDate, c.RequireProof,
+	}
+}
+
+// ItemRows returns the card's goals as the services select them from
+// bingo_items, in position order.
+func (c *TestCard) ItemRows() [][]any {
+	rows := make([][]any, 0, len(c.Items))
+	for _, item := range c.Items {
+		rows = append(rows, ItemRow(item))
+	}
+	return rows
+}
+
+// ItemRow returns one goal as the services select it from bingo_items.
+func ItemRow(item models.BingoItem) []any {
+	return []any{
+		item.ID, item.CardID, item.Position, item.Content, item.IsCompleted, item.CompletedAt,
+		item.Notes, item.ProofURL, item.CreatedAt, item.IsPrivate, item.Difficulty,
+	}
+}
+
+// TestReminderSettings is a reminder_settings fixture.
+type TestReminderSettings struct {
+	models.ReminderSettings
+}
+
+// NewTestReminderSettings returns enabled settings with the default cap,
+// reused image tokens, light images and UTC times.
+func NewTestReminderSettings(userID uuid.UUID) *TestReminderSettings {
+	return &TestReminderSettings{models.ReminderSettings{
+		UserID:         userID,
+		EmailEnabled:   true,
+		DailyEmailCap:  3,
+		ImageTokenMode: "reuse",
+		ImageTheme:     models.ImageThemeLight,
+		Timezone:       models.DefaultReminderTimezone,
+		DigestTime:     models.DefaultReminderDigestTime,
+		CreatedAt:      FixtureTime,
+		UpdatedAt:      FixtureTime,
+	}}
+}
+
+// Row returns the settings as ReminderService loads them, with the
+// notification pause last.
+func (s *TestReminderSettings) Row() []any {
+	return []any{
+		s.UserID, s.EmailEnabled, s.DailyEmailCap, s.ImageTokenMode, s.ImageTheme, s.Timezone,
+		s.DigestEnabled, s.DigestTime, s.DigestNextAt, s.CreatedAt, s.UpdatedAt, s.EmailPausedUntil,
+	}
+}
+
+// TestReminder is a card check-in reminder fixture.
+type TestReminder struct {
+	models.CardCheckinReminder
+}
+
+// ReminderOption customizes a TestReminder.
+type ReminderOption func(*TestReminder)
+
+// WithSchedule sets the check-in schedule JSON.
+func WithSchedule(schedule string) ReminderOption {
+	return func(r *TestReminder) { r.Schedule = json.RawMessage(schedule) }
+}
+
+// WithNextSendAt sets when the reminder is next due.
+func WithNextSendAt(at time.Time) ReminderOption {
+	return func(r *TestReminder) { r.NextSendAt = &at }
+}
+
+// NewTestReminder returns an enabled monthly check-in for the card, due on
+// the 1st at 09:00 with every email section included.
+func NewTestReminder(card *TestCard, opts ...ReminderOption) *TestReminder {
+	r := &TestReminder{models.CardCheckinReminder{
+		ID:                     uuid.New(),
+		UserID:                 card.UserID,
+		CardID:                 card.ID,
+		Enabled:                true,
+		Frequency:              "monthly",
+		Schedule:               json.RawMessage(`{"day_of_month":1,"time":"09:00"}`),
+		IncludeImage:           true,
+		IncludeRecommendations: true,
+		IncludeMemories:        true,
+		CreatedAt:              FixtureTime,
+		UpdatedAt:              FixtureTime,
+	}}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Row returns the reminder as ReminderService reads it back from
+// card_checkin_reminders.
+func (r *TestReminder) Row() []any {
+	return []any{
+		r.ID, r.UserID, r.CardID, r.Enabled, r.Frequency, []byte(r.Schedule), r.IncludeImage,
+		r.IncludeRecommendations, r.IncludeMemories, r.NextSendAt, r.LastSentAt, r.CreatedAt, r.UpdatedAt,
+	}
+}
+
+// TestGoalReminder is a goal reminder fixture.
+type TestGoalReminder struct {
+	models.GoalReminder
+}
+
+// NewTestGoalReminder returns an enabled one-time reminder for the goal,
+// due at sendAt.
+func NewTestGoalReminder(card *TestCard, item models.BingoItem, sendAt time.Time) *TestGoalReminder {
+	schedule, _ := json.Marshal(map[string]string{"send_at": sendAt.UTC().Format(time.RFC3339)})
+	return &TestGoalReminder{models.GoalReminder{
+		ID:         uuid.New(),
+		UserID:     card.UserID,
+		CardID:     card.ID,
+		ItemID:     item.ID,
+		Enabled:    true,
+		Kind:       models.GoalReminderKindOneTime,
+		Schedule:   schedule,
+		NextSendAt: &sendAt,
+		CreatedAt:  FixtureTime,
+		UpdatedAt:  FixtureTime,
+	}}
+}
+
+// Row returns the reminder as ReminderService reads it back from
+// goal_reminders.
+func (r *TestGoalReminder) Row() []any {
+	return []any{
+		r.ID, r.UserID, r.CardID, r.ItemID, r.Enabled, r.Kind, []byte(r.Schedule),
+		r.NextSendAt, r.LastSentAt, r.CreatedAt, r.UpdatedAt,
+	}
+}
+
+// GoalReminderContextRow returns what ReminderService loads about a goal
+// before sending its reminder: the card, the goal, the owner's email and
+// their daily cap.
+func GoalReminderContextRow(card *TestCard, item models.BingoItem, email string, dailyCap int) []any {
+	return []any{
+		card.ID, card.Title, card.Year, card.IsFinalized, card.IsArchived,
+		item.Content, item.IsCompleted,
+		email,
+		dailyCap,
+	}
+}
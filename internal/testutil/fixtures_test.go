@@ -0,0 +1,90 @@
+package testutil
+
+import (
+	"testing"
+)
+
+func TestNewTestCard_ItemsSkipFreeSpace(t *testing.T) {
+	card := NewTestCard(WithItems(24), WithCompletedItems(2))
+	if len(card.Items) != 24 {
+		t.Fatalf("expected 24 items, got %d", len(card.Items))
+	}
+	for _, item := range card.Items {
+		if item.Position == 12 {
+			t.Fatal("expected the FREE square to be skipped")
+		}
+		if item.CardID != card.ID {
+			t.Fatalf("expected item on card %s, got %s", card.ID, item.CardID)
+		}
+	}
+	if !card.Items[1].IsCompleted || card.Items[2].IsCompleted {
+		t.Fatal("expected only the first two items completed")
+	}
+	if card.Items[12].Position != 13 || card.Items[12].Content != "Goal 13" {
+		t.Fatalf("expected Goal 13 after the FREE square, got %+v", card.Items[12])
+	}
+}
+
+func TestNewTestCard_GridOptions(t *testing.T) {
+	card := NewTestCard(WithGridSize(3))
+	if card.HeaderText != "BIN" || card.FreeSpacePos == nil || *card.FreeSpacePos != 4 {
+		t.Fatalf("expected BIN header with FREE at 4, got %q %v", card.HeaderText, card.FreeSpacePos)
+	}
+
+	card = NewTestCard(WithGridSize(2), WithoutFreeSpace(), WithItems(10))
+	if card.HasFreeSpace || card.FreeSpacePos != nil {
+		t.Fatal("expected no FREE square")
+	}
+	if len(card.Items) != 4 {
+		t.Fatalf("expected items capped at the grid, got %d", len(card.Items))
+	}
+}
+
+func TestFixtureRowLengths(t *testing.T) {
+	user := NewTestUser()
+	card := NewTestCard(WithCardOwner(user.ID), WithItems(1))
+	reminder := NewTestReminder(card, WithSchedule(`{"day_of_month":15,"time":"18:00"}`), WithNextSendAt(FixtureTime))
+	goal := NewTestGoalReminder(card, card.Items[0], FixtureTime)
+
+	cases := []struct {
+		name string
+		row  []any
+		want int
+	}{
+		{"user export", user.ExportRow(), 13},
+		{"card", card.Row(), 19},
+		{"item", card.ItemRows()[0], 11},
+		{"settings", NewTestReminderSettings(user.ID).Row(), 12},
+		{"reminder", reminder.Row(), 13},
+		{"goal reminder", goal.Row(), 11},
+		{"goal reminder context", GoalReminderContextRow(card, card.Items[0], user.Email, 3), 9},
+	}
+	for _, tc := range cases {
+		if len(tc.row) != tc.want {
+			t.Fatalf("%s: expected %d columns, got %d", tc.name, tc.want, len(tc.row))
+		}
+	}
+	if reminder.UserID != user.ID || goal.ItemID != card.Items[0].ID {
+		t.Fatal("expected reminders tied to the card owner and goal")
+	}
+}
+
+func TestSQLLog(t *testing.T) {
+	var log SQLLog
+	log.Record("UPDATE users SET deleted_at = NOW() WHERE id = $1", "user-1")
+	log.Record("DELETE FROM sessions WHERE user_id = $1", "user-1")
+	log.Record("DELETE FROM magic_link_tokens WHERE user_id = $1", "user-1")
+
+	if got := log.Matching("DELETE FROM"); len(got) != 2 {
+		t.Fatalf("expected two deletes, got %d", len(got))
+	}
+	call := log.AssertCalled(t, "UPDATE users")
+	if len(call.Args) != 1 || call.Args[0] != "user-1" {
+		t.Fatalf("expected recorded args, got %v", call.Args)
+	}
+	log.AssertNotCalled(t, "INSERT")
+	log.AssertOrder(t, "UPDATE users", "DELETE FROM magic_link_tokens")
+	if len(log.Calls()) != 3 {
+		t.Fatalf("expected three calls, got %d", len(log.Calls()))
+	}
+}
@@ -0,0 +1,95 @@
+package testutil
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// SQLCall is one statement a fake database received.
+type SQLCall struct {
+	SQL  string
+	Args []any
+}
+
+// SQLLog records the statements a fake database receives so tests can assert
+// on the calls a service made instead of setting flags inside each fake. Call
+// Record from the fake's Exec, Query and QueryRow funcs. It is safe for
+// concurrent use.
+type SQLLog struct {
+	mu    sync.Mutex
+	calls []SQLCall
+}
+
+// Record appends a statement to the log.
+func (l *SQLLog) Record(sql string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, SQLCall{SQL: sql, Args: args})
+}
+
+// Calls returns every recorded statement in order.
+func (l *SQLLog) Calls() []SQLCall {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]SQLCall(nil), l.calls...)
+}
+
+// Matching returns the recorded statements containing substr, in order.
+func (l *SQLLog) Matching(substr string) []SQLCall {
+	var matched []SQLCall
+	for _, call := range l.Calls() {
+		if strings.Contains(call.SQL, substr) {
+			matched = append(matched, call)
+		}
+	}
+	return matched
+}
+
+// AssertCalled fails the test unless a statement containing substr was
+// recorded, and returns the last such statement so its args can be checked.
+func (l *SQLLog) AssertCalled(t *testing.T, substr string) SQLCall {
+	t.Helper()
+	matched := l.Matching(substr)
+	if len(matched) == 0 {
+		t.Fatalf("expected a statement containing %q, got %s", substr, l.summary())
+		return SQLCall{}
+	}
+	return matched[len(matched)-1]
+}
+
+// AssertNotCalled fails the test if a statement containing substr was
+// recorded.
+func (l *SQLLog) AssertNotCalled(t *testing.T, substr string) {
+	t.Helper()
+	if matched := l.Matching(substr); len(matched) > 0 {
+		t.Fatalf("expected no statement containing %q, got %q", substr, matched[0].SQL)
+	}
+}
+
+// AssertOrder fails the test unless statements containing each substring
+// were recorded in the given order. Other statements may come in between.
+func (l *SQLLog) AssertOrder(t *testing.T, substrs ...string) {
+	t.Helper()
+	next := 0
+	for _, call := range l.Calls() {
+		if next < len(substrs) && strings.Contains(call.SQL, substrs[next]) {
+			next++
+		}
+	}
+	if next < len(substrs) {
+		t.Fatalf("expected a statement containing %q after %q, got %s", substrs[next], substrs[:next], l.summary())
+	}
+}
+
+func (l *SQLLog) summary() string {
+	calls := l.Calls()
+	if len(calls) == 0 {
+		return "no statements"
+	}
+	lines := make([]string, 0, len(calls))
+	for _, call := range calls {
+		lines = append(lines, strings.Join(strings.Fields(call.SQL), " "))
+	}
+	return "\n  " + strings.Join(lines, "\n  ")
+}
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_reminder_email_log_checkin_day;
+DELETE FROM reminder_email_log WHERE status = 'manual';
+CREATE UNIQUE INDEX idx_reminder_email_log_checkin_day ON reminder_email_log(source_type, source_id, sent_on)
+    WHERE source_type = 'card_checkin';
+
+ALTER TABLE reminder_email_log DROP CONSTRAINT reminder_email_log_status_check;
+ALTER TABLE reminder_email_log ADD CONSTRAINT reminder_email_log_status_check
+    CHECK (status IN ('sent', 'failed'));
+
+DROP TABLE IF EXISTS admin_audit_log;
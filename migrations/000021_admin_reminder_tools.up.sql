@@ -0,0 +1,21 @@
+CREATE TABLE admin_audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    admin_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    target_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    target_id UUID,
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_admin_audit_log_created ON admin_audit_log(created_at DESC);
+CREATE INDEX idx_admin_audit_log_target_user ON admin_audit_log(target_user_id, created_at DESC);
+
+ALTER TABLE reminder_email_log DROP CONSTRAINT reminder_email_log_status_check;
+ALTER TABLE reminder_email_log ADD CONSTRAINT reminder_email_log_status_check
+    CHECK (status IN ('sent', 'failed', 'manual'));
+
+-- Manual resends are logged alongside the scheduled send for the same day.
+DROP INDEX idx_reminder_email_log_checkin_day;
+CREATE UNIQUE INDEX idx_reminder_email_log_checkin_day ON reminder_email_log(source_type, source_id, sent_on)
+    WHERE source_type = 'card_checkin' AND status <> 'manual';
@@ -0,0 +1,2 @@
+ALTER TABLE notification_settings
+    DROP COLUMN IF EXISTS email_paused_until;
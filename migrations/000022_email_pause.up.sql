@@ -0,0 +1,3 @@
+-- Global "pause all email until" shared by notification and reminder emails.
+ALTER TABLE notification_settings
+    ADD COLUMN email_paused_until TIMESTAMPTZ;
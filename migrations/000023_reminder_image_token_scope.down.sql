@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_reminder_image_tokens_user;
+ALTER TABLE reminder_image_tokens
+    DROP COLUMN IF EXISTS max_access_count;
+ALTER TABLE reminder_settings
+    DROP COLUMN IF EXISTS image_token_mode;
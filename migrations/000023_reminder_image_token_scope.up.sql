@@ -0,0 +1,12 @@
+-- Per-user choice between reusing one long-lived image token per card
+-- ('reuse', the original behavior) and minting a short-lived token per email.
+ALTER TABLE reminder_settings
+    ADD COLUMN image_token_mode TEXT NOT NULL DEFAULT 'reuse'
+        CHECK (image_token_mode IN ('reuse', 'per_email'));
+
+-- NULL means unlimited; once access_count reaches the cap the image endpoint
+-- serves a placeholder instead of the card.
+ALTER TABLE reminder_image_tokens
+    ADD COLUMN max_access_count INT;
+
+CREATE INDEX idx_reminder_image_tokens_user ON reminder_image_tokens(user_id);
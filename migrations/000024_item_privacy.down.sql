@@ -0,0 +1,2 @@
+ALTER TABLE bingo_items
+    DROP COLUMN IF EXISTS is_private;
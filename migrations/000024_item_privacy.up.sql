@@ -0,0 +1,4 @@
+-- Private items stay fully visible to the owner but are shown as a generic
+-- placeholder to friends, on public shares and in shared images.
+ALTER TABLE bingo_items
+    ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+ALTER TABLE bingo_cards
+    DROP COLUMN IF EXISTS free_space_text;
@@ -0,0 +1,4 @@
+-- Optional custom label for the FREE square. NULL keeps the default "FREE".
+ALTER TABLE bingo_cards
+    ADD COLUMN free_space_text TEXT
+        CHECK (free_space_text IS NULL OR char_length(free_space_text) <= 40);
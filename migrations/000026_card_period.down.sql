@@ -0,0 +1,4 @@
+ALTER TABLE bingo_cards
+    DROP CONSTRAINT IF EXISTS bingo_cards_period_check,
+    DROP COLUMN IF EXISTS end_date,
+    DROP COLUMN IF EXISTS start_date;
@@ -0,0 +1,15 @@
+-- Card period: lets a card run on a fiscal, school or birthday "year".
+-- Existing cards default to Jan 1 - Dec 31 of their year.
+ALTER TABLE bingo_cards
+    ADD COLUMN start_date DATE,
+    ADD COLUMN end_date DATE;
+
+UPDATE bingo_cards
+SET start_date = make_date(year, 1, 1),
+    end_date = make_date(year, 12, 31);
+
+ALTER TABLE bingo_cards
+    ALTER COLUMN start_date SET NOT NULL,
+    ALTER COLUMN end_date SET NOT NULL,
+    ADD CONSTRAINT bingo_cards_period_check
+        CHECK (end_date > start_date AND end_date <= start_date + INTERVAL '18 months');
@@ -0,0 +1,10 @@
+-- Security-relevant actions a user takes on their own account (e.g. data exports).
+CREATE TABLE account_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_account_events_user ON account_events(user_id, created_at DESC);
@@ -0,0 +1,11 @@
+DELETE FROM reminder_unsubscribe_tokens WHERE scope <> 'reminders';
+ALTER TABLE reminder_unsubscribe_tokens DROP COLUMN IF EXISTS scope;
+
+DELETE FROM reminder_email_log WHERE source_type = 'friends_digest';
+ALTER TABLE reminder_email_log DROP CONSTRAINT reminder_email_log_source_type_check;
+ALTER TABLE reminder_email_log ADD CONSTRAINT reminder_email_log_source_type_check
+    CHECK (source_type IN ('card_checkin', 'goal_reminder'));
+
+ALTER TABLE notification_settings
+    DROP COLUMN IF EXISTS friends_digest_sent_at,
+    DROP COLUMN IF EXISTS email_friends_digest;
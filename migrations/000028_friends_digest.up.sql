@@ -0,0 +1,14 @@
+-- Opt-in weekly email summarizing friends' activity.
+ALTER TABLE notification_settings
+    ADD COLUMN email_friends_digest BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN friends_digest_sent_at TIMESTAMPTZ;
+
+-- Digests are logged with reminder emails so they count toward the daily email cap.
+ALTER TABLE reminder_email_log DROP CONSTRAINT reminder_email_log_source_type_check;
+ALTER TABLE reminder_email_log ADD CONSTRAINT reminder_email_log_source_type_check
+    CHECK (source_type IN ('card_checkin', 'goal_reminder', 'friends_digest'));
+
+-- Unsubscribe tokens record which emails they turn off.
+ALTER TABLE reminder_unsubscribe_tokens
+    ADD COLUMN scope TEXT NOT NULL DEFAULT 'reminders'
+    CHECK (scope IN ('reminders', 'friends_digest'));
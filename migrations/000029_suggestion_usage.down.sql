@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS suggestion_usage_daily;
+DROP INDEX IF EXISTS idx_bingo_items_content_hash;
+DROP INDEX IF EXISTS idx_suggestions_content_hash;
+ALTER TABLE suggestions DROP COLUMN IF EXISTS content_hash;
+DROP FUNCTION IF EXISTS suggestion_content_hash(TEXT);
@@ -0,0 +1,21 @@
+-- Normalized content hash shared by suggestions and goals: trimmed, lowercased,
+-- internal whitespace collapsed (matches normalizeItemContent in the card service).
+CREATE FUNCTION suggestion_content_hash(content TEXT) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+    AS $$ SELECT md5(lower(regexp_replace(btrim(content), '\s+', ' ', 'g'))) $$;
+
+ALTER TABLE suggestions
+    ADD COLUMN content_hash TEXT GENERATED ALWAYS AS (suggestion_content_hash(content)) STORED;
+
+CREATE INDEX idx_suggestions_content_hash ON suggestions(content_hash);
+
+-- Lets suggestion analytics find matching goals without scanning bingo_items.
+CREATE INDEX idx_bingo_items_content_hash ON bingo_items(suggestion_content_hash(content));
+
+-- Daily adoption counters. No user or card IDs are stored.
+CREATE TABLE suggestion_usage_daily (
+    suggestion_id UUID NOT NULL REFERENCES suggestions(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    added_count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (suggestion_id, day)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bingo_items_card_completed_at;
+ALTER TABLE card_checkin_reminders DROP COLUMN IF EXISTS include_memories;
@@ -0,0 +1,6 @@
+ALTER TABLE card_checkin_reminders
+    ADD COLUMN include_memories BOOLEAN NOT NULL DEFAULT true;
+
+-- Serves "on this day" lookups over a user's completed goals.
+CREATE INDEX idx_bingo_items_card_completed_at ON bingo_items(card_id, completed_at)
+    WHERE completed_at IS NOT NULL;
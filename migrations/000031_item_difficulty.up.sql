@@ -0,0 +1,5 @@
+-- Optional effort tag used for weighted progress and reminder pacing.
+-- Untagged items are weighted like medium ones.
+ALTER TABLE bingo_items
+    ADD COLUMN difficulty TEXT
+    CHECK (difficulty IS NULL OR difficulty IN ('easy', 'medium', 'hard'));
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS profile_indexable,
+    DROP COLUMN IF EXISTS profile_visibility;
@@ -0,0 +1,7 @@
+-- Opt-in public profile at /u/{username}. Public means public: blocks and
+-- friendships do not apply, and search engines are told not to index the
+-- page unless profile_indexable is set.
+ALTER TABLE users
+    ADD COLUMN profile_visibility TEXT NOT NULL DEFAULT 'off'
+        CHECK (profile_visibility IN ('off', 'public')),
+    ADD COLUMN profile_indexable BOOLEAN NOT NULL DEFAULT false;
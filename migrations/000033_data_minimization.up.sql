@@ -0,0 +1,4 @@
+-- Opt-out of analytics-style records: AI generation logs and share link
+-- access counters are not written for users with data_minimization set.
+ALTER TABLE users
+    ADD COLUMN data_minimization BOOLEAN NOT NULL DEFAULT false;
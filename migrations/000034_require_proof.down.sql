@@ -0,0 +1,2 @@
+ALTER TABLE bingo_cards
+    DROP COLUMN IF EXISTS require_proof;
@@ -0,0 +1,3 @@
+-- Accountability mode: completing an item on the card needs a note or proof URL.
+ALTER TABLE bingo_cards
+    ADD COLUMN require_proof BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+ALTER TABLE card_checkin_reminders
+    DROP COLUMN IF EXISTS recent_recommendations;
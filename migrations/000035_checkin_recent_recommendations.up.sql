@@ -0,0 +1,4 @@
+-- Goal IDs suggested in the last two check-in emails, newest send first, so
+-- the picker can rotate goals the user keeps ignoring.
+ALTER TABLE card_checkin_reminders
+    ADD COLUMN recent_recommendations JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,4 @@
+DELETE FROM reminder_email_log WHERE source_type = 'deliverability_check';
+ALTER TABLE reminder_email_log DROP CONSTRAINT reminder_email_log_source_type_check;
+ALTER TABLE reminder_email_log ADD CONSTRAINT reminder_email_log_source_type_check
+    CHECK (source_type IN ('card_checkin', 'goal_reminder', 'friends_digest'));
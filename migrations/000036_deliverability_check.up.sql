@@ -0,0 +1,5 @@
+-- Self-serve deliverability probes are logged with reminder emails so users
+-- can see whether the provider accepted them.
+ALTER TABLE reminder_email_log DROP CONSTRAINT reminder_email_log_source_type_check;
+ALTER TABLE reminder_email_log ADD CONSTRAINT reminder_email_log_source_type_check
+    CHECK (source_type IN ('card_checkin', 'goal_reminder', 'friends_digest', 'deliverability_check'));
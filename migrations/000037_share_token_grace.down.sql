@@ -0,0 +1,3 @@
+ALTER TABLE bingo_card_shares
+    DROP COLUMN IF EXISTS previous_token_expires_at,
+    DROP COLUMN IF EXISTS previous_token;
@@ -0,0 +1,5 @@
+-- The token replaced by the last rotation, kept resolvable until
+-- previous_token_expires_at so printed QR codes and posted links keep working.
+ALTER TABLE bingo_card_shares
+    ADD COLUMN previous_token VARCHAR(64) UNIQUE,
+    ADD COLUMN previous_token_expires_at TIMESTAMPTZ;
@@ -0,0 +1,15 @@
+-- Friend notifications whose insert failed inside the card write that raised
+-- them. The card write still commits; the notification_reconcile job retries
+-- these until they succeed or run out of attempts.
+CREATE TABLE pending_notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    type TEXT NOT NULL CHECK (type IN ('friend_bingo', 'friend_new_card')),
+    actor_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    card_id UUID NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    bingo_count INT,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pending_notifications_created ON pending_notifications(created_at);
@@ -0,0 +1,13 @@
+-- Long-lived, revocable tokens for the /w/{token}.png progress image used by
+-- lock-screen widgets. Kept apart from bingo_card_shares so a widget link
+-- never opens the public share page.
+CREATE TABLE card_widget_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token VARCHAR(64) NOT NULL UNIQUE,
+    card_id UUID NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_accessed_at TIMESTAMPTZ,
+    access_count INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_card_widget_tokens_card ON card_widget_tokens(card_id);
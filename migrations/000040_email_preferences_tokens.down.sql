@@ -0,0 +1,4 @@
+DELETE FROM reminder_unsubscribe_tokens WHERE scope = 'email_preferences';
+ALTER TABLE reminder_unsubscribe_tokens DROP CONSTRAINT reminder_unsubscribe_tokens_scope_check;
+ALTER TABLE reminder_unsubscribe_tokens ADD CONSTRAINT reminder_unsubscribe_tokens_scope_check
+    CHECK (scope IN ('reminders', 'friends_digest'));
@@ -0,0 +1,5 @@
+-- Email preferences links reuse the unsubscribe token table. Unlike the
+-- other scopes they are never marked used and work until they expire.
+ALTER TABLE reminder_unsubscribe_tokens DROP CONSTRAINT reminder_unsubscribe_tokens_scope_check;
+ALTER TABLE reminder_unsubscribe_tokens ADD CONSTRAINT reminder_unsubscribe_tokens_scope_check
+    CHECK (scope IN ('reminders', 'friends_digest', 'email_preferences'));
@@ -0,0 +1,11 @@
+-- The layout a draft card had before each shuffle, for undo. Only
+-- the latest few per card are kept.
+CREATE TABLE card_shuffle_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    card_id UUID NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    layout JSONB NOT NULL,
+    seed BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_card_shuffle_history_card ON card_shuffle_history(card_id, created_at DESC);
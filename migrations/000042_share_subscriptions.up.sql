@@ -0,0 +1,17 @@
+-- Email watchers of a shared card. Rows hang off the share itself, so
+-- revoking the share (deleting its row) drops every subscription. A row is
+-- pending until its confirm_token is used; only confirmed rows get the
+-- weekly progress email.
+CREATE TABLE share_subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    card_id UUID NOT NULL REFERENCES bingo_card_shares(card_id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    confirm_token VARCHAR(64) UNIQUE,
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    confirmed_at TIMESTAMPTZ,
+    last_sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (card_id, email)
+);
+
+CREATE INDEX idx_share_subscriptions_due ON share_subscriptions(last_sent_at) WHERE confirmed_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Repaired positions are not restored; they were invalid.
+DROP TABLE IF EXISTS item_position_repairs;
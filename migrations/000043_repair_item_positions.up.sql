@@ -0,0 +1,58 @@
+-- One-time repair of goals that drifted off their card's grid or onto the
+-- FREE square (e.g. cards from before free_space_position was stored).
+-- Each such goal moves to the lowest empty valid square of its card. Every
+-- move is recorded in item_position_repairs and raised as a NOTICE; a goal
+-- on a card with no empty square left is recorded with a NULL new_position
+-- and left where it is for manual follow-up.
+CREATE TABLE item_position_repairs (
+    id BIGSERIAL PRIMARY KEY,
+    card_id UUID NOT NULL,
+    item_id UUID NOT NULL,
+    old_position INTEGER NOT NULL,
+    new_position INTEGER,
+    repaired_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+DO $$
+DECLARE
+    card RECORD;
+    item RECORD;
+    slot INTEGER;
+BEGIN
+    FOR card IN
+        SELECT DISTINCT c.id, c.grid_size,
+               CASE WHEN c.has_free_space THEN c.free_space_position END AS free_pos
+        FROM bingo_cards c
+        JOIN bingo_items i ON i.card_id = c.id
+        WHERE i.position < 0
+           OR i.position >= c.grid_size * c.grid_size
+           OR (c.has_free_space AND i.position = c.free_space_position)
+    LOOP
+        FOR item IN
+            SELECT i.id, i.position
+            FROM bingo_items i
+            WHERE i.card_id = card.id
+              AND (i.position < 0
+                   OR i.position >= card.grid_size * card.grid_size
+                   OR i.position = card.free_pos)
+            ORDER BY i.position, i.id
+        LOOP
+            SELECT s INTO slot
+            FROM generate_series(0, card.grid_size * card.grid_size - 1) AS s
+            WHERE s IS DISTINCT FROM card.free_pos
+              AND NOT EXISTS (
+                  SELECT 1 FROM bingo_items x WHERE x.card_id = card.id AND x.position = s
+              )
+            ORDER BY s
+            LIMIT 1;
+
+            IF slot IS NOT NULL THEN
+                UPDATE bingo_items SET position = slot WHERE id = item.id;
+            END IF;
+            INSERT INTO item_position_repairs (card_id, item_id, old_position, new_position)
+            VALUES (card.id, item.id, item.position, slot);
+            RAISE NOTICE 'item position repair: card %, item %, position % -> %',
+                card.id, item.id, item.position, COALESCE(slot::text, 'unchanged (card full)');
+        END LOOP;
+    END LOOP;
+END $$;
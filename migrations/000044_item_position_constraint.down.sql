@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS bingo_cards_item_positions_valid ON bingo_cards;
+DROP TRIGGER IF EXISTS bingo_items_position_valid ON bingo_items;
+DROP FUNCTION IF EXISTS enforce_bingo_card_item_positions();
+DROP FUNCTION IF EXISTS enforce_bingo_item_position();
+DROP FUNCTION IF EXISTS bingo_item_position_valid(INTEGER, SMALLINT, BOOLEAN, INTEGER);
@@ -0,0 +1,56 @@
+-- Item position invariant: every goal is on its own square of its card's
+-- grid (0 .. grid_size^2 - 1) and never on the FREE square. UNIQUE(card_id,
+-- position) already covers "its own square"; these constraint triggers cover
+-- the rest. They are deferred to commit so swaps and shuffles can park goals
+-- on temporary negative positions inside a transaction.
+CREATE FUNCTION bingo_item_position_valid(pos INTEGER, grid_size SMALLINT, has_free_space BOOLEAN, free_space_position INTEGER)
+RETURNS BOOLEAN AS $$
+    SELECT pos >= 0
+       AND pos < grid_size * grid_size
+       AND NOT (has_free_space AND pos = free_space_position);
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION enforce_bingo_item_position() RETURNS TRIGGER AS $$
+BEGIN
+    -- Check the row as it is at commit; it may have moved again or been deleted.
+    IF EXISTS (
+        SELECT 1
+        FROM bingo_items i
+        JOIN bingo_cards c ON c.id = i.card_id
+        WHERE i.id = NEW.id
+          AND NOT bingo_item_position_valid(i.position, c.grid_size, c.has_free_space, c.free_space_position)
+    ) THEN
+        RAISE EXCEPTION 'bingo item % is not on a valid square of card %', NEW.id, NEW.card_id
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'bingo_items_position_valid';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION enforce_bingo_card_item_positions() RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1
+        FROM bingo_items i
+        JOIN bingo_cards c ON c.id = i.card_id
+        WHERE c.id = NEW.id
+          AND NOT bingo_item_position_valid(i.position, c.grid_size, c.has_free_space, c.free_space_position)
+    ) THEN
+        RAISE EXCEPTION 'card % has a goal off the grid or on the FREE square', NEW.id
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'bingo_items_position_valid';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER bingo_items_position_valid
+    AFTER INSERT OR UPDATE OF position, card_id ON bingo_items
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+    EXECUTE FUNCTION enforce_bingo_item_position();
+
+CREATE CONSTRAINT TRIGGER bingo_cards_item_positions_valid
+    AFTER UPDATE OF grid_size, has_free_space, free_space_position ON bingo_cards
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+    EXECUTE FUNCTION enforce_bingo_card_item_positions();
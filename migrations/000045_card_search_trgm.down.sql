@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bingo_items_content_trgm;
+DROP INDEX IF EXISTS idx_bingo_cards_title_trgm;
@@ -0,0 +1,6 @@
+-- Trigram indexes back the owner's substring search over card titles and
+-- goal text (GET /api/cards/search), which uses ILIKE.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_bingo_cards_title_trgm ON bingo_cards USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_bingo_items_content_trgm ON bingo_items USING GIN (content gin_trgm_ops);
@@ -0,0 +1,3 @@
+ALTER TABLE notification_settings
+    ADD COLUMN email_format VARCHAR(10) NOT NULL DEFAULT 'html'
+    CONSTRAINT notification_settings_email_format_check CHECK (email_format IN ('html', 'text'));
@@ -0,0 +1 @@
+ALTER TABLE reminder_settings DROP COLUMN IF EXISTS timezone;
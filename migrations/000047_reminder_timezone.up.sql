@@ -0,0 +1,3 @@
+-- IANA zone that reminder times are interpreted in. Existing rows get UTC.
+ALTER TABLE reminder_settings
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
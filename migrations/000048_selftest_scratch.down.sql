@@ -0,0 +1 @@
+DROP TABLE IF EXISTS selftest_scratch;
@@ -0,0 +1,6 @@
+-- Rows written and deleted by `server selftest` to prove the database
+-- accepts writes. Nothing else reads this table.
+CREATE TABLE selftest_scratch (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
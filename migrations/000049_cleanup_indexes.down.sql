@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reminder_email_log_sent;
+DROP INDEX IF EXISTS idx_notifications_created;
@@ -0,0 +1,4 @@
+-- Retention cleanup deletes old notifications and reminder email log rows in
+-- batches; these let each batch find its rows without scanning the table.
+CREATE INDEX idx_notifications_created ON notifications(created_at);
+CREATE INDEX idx_reminder_email_log_sent ON reminder_email_log(sent_at);
@@ -0,0 +1,17 @@
+-- One token per reminder email for its "Open my card" link. A click only sets
+-- clicked_at; no IP address or user agent is kept. Tokens expire with the
+-- email's image token lifetime.
+CREATE TABLE reminder_link_tokens (
+    token VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_type TEXT NOT NULL,
+    source_id UUID NOT NULL,
+    target_path TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    clicked_at TIMESTAMPTZ,
+    CHECK (source_type IN ('card_checkin', 'goal_reminder'))
+);
+
+CREATE INDEX idx_reminder_link_tokens_expires ON reminder_link_tokens(expires_at);
+CREATE INDEX idx_reminder_link_tokens_user ON reminder_link_tokens(user_id);
@@ -0,0 +1,14 @@
+-- One single-use token per goal reminder email for its "Snooze" link. Tokens
+-- are deleted with their reminder, and otherwise expire like unsubscribe
+-- tokens.
+CREATE TABLE reminder_snooze_tokens (
+    token VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reminder_id UUID NOT NULL REFERENCES goal_reminders(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_reminder_snooze_tokens_expires ON reminder_snooze_tokens(expires_at);
+CREATE INDEX idx_reminder_snooze_tokens_reminder ON reminder_snooze_tokens(reminder_id);
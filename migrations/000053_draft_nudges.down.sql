@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_notifications_draft_nudge;
+DELETE FROM notifications WHERE type = 'draft_nudge';
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card'));
+
+DROP INDEX IF EXISTS idx_bingo_cards_unnudged_drafts;
+ALTER TABLE bingo_cards DROP COLUMN IF EXISTS nudged_at;
@@ -0,0 +1,12 @@
+-- One-time nudge for drafts that were never finalized.
+ALTER TABLE bingo_cards ADD COLUMN nudged_at TIMESTAMPTZ;
+
+CREATE INDEX idx_bingo_cards_unnudged_drafts ON bingo_cards(created_at)
+    WHERE is_finalized = false AND nudged_at IS NULL;
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card', 'draft_nudge'));
+
+CREATE UNIQUE INDEX idx_notifications_draft_nudge ON notifications(user_id, card_id)
+    WHERE type = 'draft_nudge';
@@ -0,0 +1,6 @@
+DELETE FROM notifications WHERE type = 'item_comment';
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card', 'draft_nudge'));
+
+DROP TABLE IF EXISTS bingo_item_comments;
@@ -0,0 +1,2 @@
+ALTER TABLE bingo_card_shares DROP COLUMN IF EXISTS show_notes;
+ALTER TABLE bingo_card_shares DROP COLUMN IF EXISTS show_completions;
@@ -0,0 +1 @@
+ALTER TABLE card_checkin_reminders DROP COLUMN IF EXISTS progress_snapshot;
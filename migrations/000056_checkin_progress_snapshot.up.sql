@@ -0,0 +1,3 @@
+-- Progress as of the last sent check-in ({"completed", "item_ids", "bingos"}),
+-- so the next email can say what changed. NULL until the first send.
+ALTER TABLE card_checkin_reminders ADD COLUMN progress_snapshot JSONB;
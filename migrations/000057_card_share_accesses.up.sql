@@ -0,0 +1,13 @@
+-- One row per logged visit to a card's share link, for the owner's analytics.
+-- Only the referrer's host and a coarse user agent class are kept. Rows hang
+-- off the share, so revoking it drops them; each share keeps at most its
+-- newest few thousand visits.
+CREATE TABLE card_share_accesses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    card_id UUID NOT NULL REFERENCES bingo_card_shares(card_id) ON DELETE CASCADE,
+    accessed_at TIMESTAMPTZ NOT NULL,
+    referrer_host VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent_class VARCHAR(16) NOT NULL CHECK (user_agent_class IN ('desktop', 'mobile', 'bot', 'unknown'))
+);
+
+CREATE INDEX idx_card_share_accesses_card ON card_share_accesses(card_id, accessed_at);
@@ -0,0 +1,3 @@
+-- Owner-chosen source tag from the share link's ?src= parameter, so visits
+-- can be told apart by the audience the link was posted to.
+ALTER TABLE card_share_accesses ADD COLUMN source_tag VARCHAR(32) NOT NULL DEFAULT '';
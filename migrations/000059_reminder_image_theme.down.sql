@@ -0,0 +1,2 @@
+ALTER TABLE reminder_settings
+    DROP COLUMN IF EXISTS image_theme;
@@ -0,0 +1,4 @@
+-- Color theme of the card image in check-in emails, for dark-mode mail clients.
+ALTER TABLE reminder_settings
+    ADD COLUMN image_theme TEXT NOT NULL DEFAULT 'light'
+        CHECK (image_theme IN ('light', 'dark'));
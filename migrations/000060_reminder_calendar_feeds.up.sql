@@ -0,0 +1,7 @@
+-- One revocable calendar feed per user. Only the token's SHA-256 is kept; the
+-- feed URL is shown once when it is created.
+CREATE TABLE reminder_calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,9 @@
+DELETE FROM notifications WHERE type IN ('reminder_checkin', 'reminder_goal');
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card', 'draft_nudge', 'item_comment'));
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS item_id;
+
+ALTER TABLE notification_settings DROP COLUMN IF EXISTS in_app_reminder_goal;
+ALTER TABLE notification_settings DROP COLUMN IF EXISTS in_app_reminder_checkin;
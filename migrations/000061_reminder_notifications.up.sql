@@ -0,0 +1,9 @@
+-- In-app notifications raised when a reminder goes out.
+ALTER TABLE notification_settings ADD COLUMN in_app_reminder_checkin BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE notification_settings ADD COLUMN in_app_reminder_goal BOOLEAN NOT NULL DEFAULT true;
+
+ALTER TABLE notifications ADD COLUMN item_id UUID REFERENCES bingo_items(id) ON DELETE SET NULL;
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card', 'draft_nudge', 'item_comment', 'reminder_checkin', 'reminder_goal'));
@@ -0,0 +1,10 @@
+DELETE FROM reminder_email_log WHERE source_type = 'digest';
+ALTER TABLE reminder_email_log DROP CONSTRAINT reminder_email_log_source_type_check;
+ALTER TABLE reminder_email_log ADD CONSTRAINT reminder_email_log_source_type_check
+    CHECK (source_type IN ('card_checkin', 'goal_reminder', 'friends_digest', 'deliverability_check'));
+ALTER TABLE reminder_email_log DROP COLUMN IF EXISTS included_sources;
+
+DROP INDEX IF EXISTS idx_reminder_settings_digest_due;
+ALTER TABLE reminder_settings DROP COLUMN IF EXISTS digest_next_at;
+ALTER TABLE reminder_settings DROP COLUMN IF EXISTS digest_time;
+ALTER TABLE reminder_settings DROP COLUMN IF EXISTS digest_enabled;
@@ -0,0 +1,13 @@
+-- Optional daily digest that folds a user's due reminders into one email.
+ALTER TABLE reminder_settings ADD COLUMN digest_enabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE reminder_settings ADD COLUMN digest_time VARCHAR(5) NOT NULL DEFAULT '08:00';
+ALTER TABLE reminder_settings ADD COLUMN digest_next_at TIMESTAMPTZ;
+
+CREATE INDEX idx_reminder_settings_digest_due ON reminder_settings(digest_next_at) WHERE digest_enabled = true;
+
+-- The check-ins and goal reminders a digest email covered.
+ALTER TABLE reminder_email_log ADD COLUMN included_sources JSONB;
+
+ALTER TABLE reminder_email_log DROP CONSTRAINT reminder_email_log_source_type_check;
+ALTER TABLE reminder_email_log ADD CONSTRAINT reminder_email_log_source_type_check
+    CHECK (source_type IN ('card_checkin', 'goal_reminder', 'friends_digest', 'deliverability_check', 'digest'));
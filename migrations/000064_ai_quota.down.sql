@@ -0,0 +1,4 @@
+ALTER TABLE ai_generation_logs DROP COLUMN IF EXISTS tier;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS ai_quota_period_start,
+    DROP COLUMN IF EXISTS ai_unlimited;
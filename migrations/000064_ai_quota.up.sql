@@ -0,0 +1,9 @@
+-- Verified users get a monthly allowance of AI generations, counted in
+-- ai_free_generations_used for the month starting at ai_quota_period_start.
+-- ai_unlimited is the per-user admin override.
+ALTER TABLE users
+    ADD COLUMN ai_unlimited BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN ai_quota_period_start TIMESTAMPTZ;
+
+-- The quota tier a generation was admitted (or refused) under.
+ALTER TABLE ai_generation_logs ADD COLUMN tier VARCHAR(20);
@@ -0,0 +1,7 @@
+ALTER TABLE goal_reminders
+    DROP COLUMN IF EXISTS claimed_by,
+    DROP COLUMN IF EXISTS claimed_at;
+
+ALTER TABLE card_checkin_reminders
+    DROP COLUMN IF EXISTS claimed_by,
+    DROP COLUMN IF EXISTS claimed_at;
@@ -0,0 +1,10 @@
+-- A reminder runner claims each due reminder before sending its email, so
+-- replicas running side by side never send the same one. claimed_by names
+-- the runner; a claim older than the lease is treated as abandoned.
+ALTER TABLE card_checkin_reminders
+    ADD COLUMN claimed_at TIMESTAMPTZ,
+    ADD COLUMN claimed_by VARCHAR(100);
+
+ALTER TABLE goal_reminders
+    ADD COLUMN claimed_at TIMESTAMPTZ,
+    ADD COLUMN claimed_by VARCHAR(100);
@@ -0,0 +1,6 @@
+-- Every session now gets a row (Redis only caches it), so users can list
+-- and revoke their sessions. The client details are captured at login.
+ALTER TABLE sessions
+    ADD COLUMN last_seen_at TIMESTAMPTZ,
+    ADD COLUMN user_agent VARCHAR(512),
+    ADD COLUMN ip_address VARCHAR(45);
@@ -0,0 +1,34 @@
+DROP TABLE IF EXISTS selftest_scratch;
+DROP TABLE IF EXISTS admin_audit_log;
+DROP TABLE IF EXISTS account_events;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS item_position_repairs;
+DROP TABLE IF EXISTS card_shuffle_history;
+DROP TABLE IF EXISTS card_widget_tokens;
+DROP TABLE IF EXISTS share_subscriptions;
+DROP TABLE IF EXISTS bingo_card_shares;
+DROP TABLE IF EXISTS reminder_link_tokens;
+DROP TABLE IF EXISTS reminder_unsubscribe_tokens;
+DROP TABLE IF EXISTS reminder_email_log;
+DROP TABLE IF EXISTS reminder_image_tokens;
+DROP TABLE IF EXISTS goal_reminders;
+DROP TABLE IF EXISTS card_checkin_reminders;
+DROP TABLE IF EXISTS reminder_settings;
+DROP TABLE IF EXISTS pending_notifications;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_settings;
+DROP TABLE IF EXISTS friend_invites;
+DROP TABLE IF EXISTS user_blocks;
+DROP TABLE IF EXISTS ai_generation_logs;
+DROP TABLE IF EXISTS api_tokens;
+DROP TABLE IF EXISTS password_reset_tokens;
+DROP TABLE IF EXISTS magic_link_tokens;
+DROP TABLE IF EXISTS email_verification_tokens;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS suggestion_usage_daily;
+DROP TABLE IF EXISTS suggestions;
+DROP TABLE IF EXISTS reactions;
+DROP TABLE IF EXISTS friendships;
+DROP TABLE IF EXISTS bingo_items;
+DROP TABLE IF EXISTS bingo_cards;
+DROP TABLE IF EXISTS users;
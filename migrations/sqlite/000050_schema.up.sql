@@ -0,0 +1,641 @@
+-- SQLite schema for single-user installs (DB_DRIVER=sqlite), equivalent to
+-- the PostgreSQL migrations in migrations/ up to the same version. Each new
+-- PostgreSQL migration needs a matching file here with the same version;
+-- TestSQLiteSchemaMatchesPostgres checks the two stay in step.
+--
+-- Differences from PostgreSQL:
+--   * UUIDs, JSON and timestamps are TEXT; timestamps are UTC in
+--     'YYYY-MM-DD HH:MM:SS.ffffff' so they sort as text.
+--   * suggestions.content_hash is a plain column; suggestion analytics and
+--     admin search are off in SQLite mode.
+--   * The deferred goal position triggers (000044) and trigram indexes
+--     (000045) have no SQLite equivalent; the card service validates
+--     positions itself.
+
+CREATE TABLE users (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    email VARCHAR(255) UNIQUE NOT NULL,
+    password_hash VARCHAR(255),
+    username VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    email_verified BOOLEAN DEFAULT false,
+    email_verified_at TIMESTAMP,
+    searchable BOOLEAN NOT NULL DEFAULT false,
+    ai_free_generations_used INT NOT NULL DEFAULT 0,
+    deleted_at TIMESTAMP,
+    profile_visibility TEXT NOT NULL DEFAULT 'off'
+        CHECK (profile_visibility IN ('off', 'public')),
+    profile_indexable BOOLEAN NOT NULL DEFAULT false,
+    data_minimization BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE INDEX idx_users_email ON users(email);
+CREATE UNIQUE INDEX users_username_unique ON users (LOWER(username));
+
+CREATE TABLE bingo_cards (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    year INTEGER NOT NULL,
+    is_active BOOLEAN DEFAULT true,
+    is_finalized BOOLEAN DEFAULT false,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    category VARCHAR(50),
+    title VARCHAR(100),
+    visible_to_friends BOOLEAN NOT NULL DEFAULT true,
+    is_archived BOOLEAN NOT NULL DEFAULT false,
+    grid_size SMALLINT NOT NULL DEFAULT 5,
+    header_text VARCHAR(5) NOT NULL DEFAULT 'BINGO',
+    has_free_space BOOLEAN NOT NULL DEFAULT true,
+    free_space_position INTEGER,
+    free_space_text TEXT
+        CHECK (free_space_text IS NULL OR length(free_space_text) <= 40),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    require_proof BOOLEAN NOT NULL DEFAULT false,
+    CONSTRAINT bingo_cards_valid_grid_size
+        CHECK (grid_size IN (2,3,4,5)),
+    CONSTRAINT bingo_cards_header_len
+        CHECK (length(header_text) >= 1 AND length(header_text) <= grid_size),
+    CONSTRAINT bingo_cards_free_pos_required_when_enabled
+        CHECK (
+          (has_free_space = false AND free_space_position IS NULL) OR
+          (has_free_space = true AND free_space_position IS NOT NULL)
+        ),
+    CONSTRAINT bingo_cards_free_pos_in_range
+        CHECK (
+          free_space_position IS NULL OR
+          (free_space_position >= 0 AND free_space_position < (grid_size * grid_size))
+        ),
+    CONSTRAINT bingo_cards_period_check
+        CHECK (end_date > start_date AND end_date <= strftime('%Y-%m-%d %H:%M:%f', start_date, '+18 months') || '000')
+);
+
+CREATE INDEX idx_bingo_cards_user_id ON bingo_cards(user_id);
+CREATE INDEX idx_bingo_cards_year ON bingo_cards(year);
+CREATE INDEX idx_bingo_cards_visibility ON bingo_cards(user_id, is_finalized, visible_to_friends);
+CREATE INDEX idx_bingo_cards_is_archived ON bingo_cards(is_archived);
+CREATE UNIQUE INDEX idx_bingo_cards_user_year_title
+    ON bingo_cards(user_id, year, title)
+    WHERE title IS NOT NULL;
+CREATE UNIQUE INDEX idx_bingo_cards_user_year_null_title
+    ON bingo_cards(user_id, year)
+    WHERE title IS NULL;
+
+CREATE TRIGGER update_bingo_cards_updated_at
+    AFTER UPDATE ON bingo_cards
+    FOR EACH ROW
+    WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE bingo_cards SET updated_at = (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000') WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER update_users_updated_at
+    AFTER UPDATE ON users
+    FOR EACH ROW
+    WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE users SET updated_at = (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000') WHERE id = NEW.id;
+END;
+
+CREATE TABLE bingo_items (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    card_id TEXT REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    is_completed BOOLEAN DEFAULT false,
+    completed_at TIMESTAMP,
+    notes TEXT,
+    proof_url TEXT,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    is_private BOOLEAN NOT NULL DEFAULT false,
+    difficulty TEXT
+        CHECK (difficulty IS NULL OR difficulty IN ('easy', 'medium', 'hard')),
+    UNIQUE(card_id, position)
+);
+
+CREATE INDEX idx_bingo_items_card_id ON bingo_items(card_id);
+CREATE INDEX idx_bingo_items_card_completed_at ON bingo_items(card_id, completed_at)
+    WHERE completed_at IS NOT NULL;
+
+CREATE TABLE friendships (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    friend_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected')),
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    UNIQUE(user_id, friend_id)
+);
+
+CREATE INDEX idx_friendships_user_id ON friendships(user_id);
+CREATE INDEX idx_friendships_friend_id ON friendships(friend_id);
+CREATE INDEX idx_friendships_status ON friendships(status);
+
+CREATE TABLE reactions (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    item_id TEXT REFERENCES bingo_items(id) ON DELETE CASCADE,
+    user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    emoji VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    UNIQUE(item_id, user_id)
+);
+
+CREATE INDEX idx_reactions_item_id ON reactions(item_id);
+
+CREATE TABLE suggestions (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    category VARCHAR(50) NOT NULL,
+    content TEXT NOT NULL,
+    is_active BOOLEAN DEFAULT true,
+    content_hash TEXT
+);
+
+CREATE INDEX idx_suggestions_category ON suggestions(category);
+CREATE INDEX idx_suggestions_is_active ON suggestions(is_active);
+
+CREATE TABLE suggestion_usage_daily (
+    suggestion_id TEXT NOT NULL REFERENCES suggestions(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    added_count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (suggestion_id, day)
+);
+
+CREATE TABLE sessions (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_token_hash ON sessions(token_hash);
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
+
+CREATE TABLE email_verification_tokens (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_email_verification_token_hash ON email_verification_tokens(token_hash);
+CREATE INDEX idx_email_verification_user_id ON email_verification_tokens(user_id);
+
+CREATE TABLE magic_link_tokens (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_magic_link_token_hash ON magic_link_tokens(token_hash);
+CREATE INDEX idx_magic_link_email ON magic_link_tokens(email);
+
+CREATE TABLE password_reset_tokens (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_password_reset_token_hash ON password_reset_tokens(token_hash);
+CREATE INDEX idx_password_reset_user_id ON password_reset_tokens(user_id);
+
+CREATE TABLE api_tokens (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    token_prefix VARCHAR(8) NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    CONSTRAINT valid_scope CHECK (scope IN ('read', 'write', 'read_write'))
+);
+
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+CREATE INDEX idx_api_tokens_token_hash ON api_tokens(token_hash);
+CREATE INDEX idx_api_tokens_prefix ON api_tokens(token_prefix);
+
+CREATE TABLE ai_generation_logs (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    model VARCHAR(50) NOT NULL,
+    tokens_input INT NOT NULL,
+    tokens_output INT NOT NULL,
+    duration_ms INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_ai_logs_user_date ON ai_generation_logs(user_id, created_at);
+
+CREATE TABLE user_blocks (
+    blocker_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    PRIMARY KEY (blocker_id, blocked_id)
+);
+
+CREATE INDEX idx_user_blocks_blocked_id ON user_blocks(blocked_id);
+
+CREATE TABLE friend_invites (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    inviter_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invite_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    accepted_by_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_friend_invites_inviter ON friend_invites(inviter_user_id);
+CREATE INDEX idx_friend_invites_expires ON friend_invites(expires_at);
+
+CREATE TABLE notification_settings (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    in_app_enabled BOOLEAN NOT NULL DEFAULT true,
+    in_app_friend_request_received BOOLEAN NOT NULL DEFAULT true,
+    in_app_friend_request_accepted BOOLEAN NOT NULL DEFAULT true,
+    in_app_friend_bingo BOOLEAN NOT NULL DEFAULT true,
+    in_app_friend_new_card BOOLEAN NOT NULL DEFAULT true,
+    email_enabled BOOLEAN NOT NULL DEFAULT false,
+    email_friend_request_received BOOLEAN NOT NULL DEFAULT false,
+    email_friend_request_accepted BOOLEAN NOT NULL DEFAULT false,
+    email_friend_bingo BOOLEAN NOT NULL DEFAULT false,
+    email_friend_new_card BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    email_paused_until TIMESTAMP,
+    email_friends_digest BOOLEAN NOT NULL DEFAULT false,
+    friends_digest_sent_at TIMESTAMP,
+    email_format VARCHAR(10) NOT NULL DEFAULT 'html'
+        CONSTRAINT notification_settings_email_format_check CHECK (email_format IN ('html', 'text'))
+);
+
+CREATE TABLE notifications (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    actor_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    friendship_id TEXT REFERENCES friendships(id) ON DELETE SET NULL,
+    card_id TEXT REFERENCES bingo_cards(id) ON DELETE SET NULL,
+    bingo_count INT,
+    in_app_delivered BOOLEAN NOT NULL DEFAULT true,
+    email_delivered BOOLEAN NOT NULL DEFAULT false,
+    email_sent_at TIMESTAMP,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card'))
+);
+
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+CREATE INDEX idx_notifications_type ON notifications(type);
+CREATE INDEX idx_notifications_created ON notifications(created_at);
+CREATE UNIQUE INDEX idx_notifications_friend_request_received ON notifications(user_id, friendship_id)
+    WHERE type = 'friend_request_received';
+CREATE UNIQUE INDEX idx_notifications_friend_request_accepted ON notifications(user_id, friendship_id)
+    WHERE type = 'friend_request_accepted';
+CREATE UNIQUE INDEX idx_notifications_friend_bingo ON notifications(user_id, card_id)
+    WHERE type = 'friend_bingo';
+CREATE UNIQUE INDEX idx_notifications_friend_new_card ON notifications(user_id, card_id)
+    WHERE type = 'friend_new_card';
+
+CREATE TABLE pending_notifications (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    type TEXT NOT NULL CHECK (type IN ('friend_bingo', 'friend_new_card')),
+    actor_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    card_id TEXT NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    bingo_count INT,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_pending_notifications_created ON pending_notifications(created_at);
+
+CREATE TABLE reminder_settings (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    email_enabled BOOLEAN NOT NULL DEFAULT false,
+    daily_email_cap INT NOT NULL DEFAULT 3,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    image_token_mode TEXT NOT NULL DEFAULT 'reuse'
+        CHECK (image_token_mode IN ('reuse', 'per_email')),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC'
+);
+
+CREATE TABLE card_checkin_reminders (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    card_id TEXT NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    frequency TEXT NOT NULL,
+    schedule TEXT NOT NULL DEFAULT '{}',
+    include_image BOOLEAN NOT NULL DEFAULT true,
+    include_recommendations BOOLEAN NOT NULL DEFAULT true,
+    next_send_at TIMESTAMP,
+    last_sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    include_memories BOOLEAN NOT NULL DEFAULT true,
+    recent_recommendations TEXT NOT NULL DEFAULT '[]',
+    UNIQUE (user_id, card_id)
+);
+
+CREATE INDEX idx_card_checkin_due ON card_checkin_reminders(next_send_at) WHERE enabled = true;
+
+CREATE TABLE goal_reminders (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    card_id TEXT NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    item_id TEXT NOT NULL REFERENCES bingo_items(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    kind TEXT NOT NULL,
+    schedule TEXT NOT NULL DEFAULT '{}',
+    next_send_at TIMESTAMP,
+    last_sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    UNIQUE (user_id, item_id)
+);
+
+CREATE INDEX idx_goal_reminders_due ON goal_reminders(next_send_at) WHERE enabled = true;
+
+CREATE TABLE reminder_image_tokens (
+    token VARCHAR(64) PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    card_id TEXT NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    show_completions BOOLEAN NOT NULL DEFAULT true,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    last_accessed_at TIMESTAMP,
+    access_count INT NOT NULL DEFAULT 0,
+    max_access_count INT
+);
+
+CREATE INDEX idx_reminder_image_tokens_expires ON reminder_image_tokens(expires_at);
+CREATE INDEX idx_reminder_image_tokens_user ON reminder_image_tokens(user_id);
+
+CREATE TABLE reminder_email_log (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_type TEXT NOT NULL,
+    source_id TEXT NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    sent_on DATE NOT NULL DEFAULT (strftime('%Y-%m-%d', 'now') || ' 00:00:00.000000'),
+    provider_message_id TEXT,
+    status TEXT NOT NULL,
+    CONSTRAINT reminder_email_log_source_type_check
+        CHECK (source_type IN ('card_checkin', 'goal_reminder', 'friends_digest', 'deliverability_check')),
+    CONSTRAINT reminder_email_log_status_check
+        CHECK (status IN ('sent', 'failed', 'manual'))
+);
+
+CREATE INDEX idx_reminder_email_log_user_sent ON reminder_email_log(user_id, sent_at DESC);
+CREATE INDEX idx_reminder_email_log_sent ON reminder_email_log(sent_at);
+CREATE UNIQUE INDEX idx_reminder_email_log_checkin_day ON reminder_email_log(source_type, source_id, sent_on)
+    WHERE source_type = 'card_checkin' AND status <> 'manual';
+
+CREATE TABLE reminder_unsubscribe_tokens (
+    token VARCHAR(64) PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    used_at TIMESTAMP,
+    scope TEXT NOT NULL DEFAULT 'reminders'
+        CONSTRAINT reminder_unsubscribe_tokens_scope_check
+        CHECK (scope IN ('reminders', 'friends_digest', 'email_preferences'))
+);
+
+CREATE INDEX idx_reminder_unsubscribe_tokens_expires ON reminder_unsubscribe_tokens(expires_at);
+
+CREATE TABLE reminder_link_tokens (
+    token VARCHAR(64) PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_type TEXT NOT NULL,
+    source_id TEXT NOT NULL,
+    target_path TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    clicked_at TIMESTAMP,
+    CHECK (source_type IN ('card_checkin', 'goal_reminder'))
+);
+
+CREATE INDEX idx_reminder_link_tokens_expires ON reminder_link_tokens(expires_at);
+CREATE INDEX idx_reminder_link_tokens_user ON reminder_link_tokens(user_id);
+
+CREATE TABLE bingo_card_shares (
+    card_id TEXT PRIMARY KEY REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    expires_at TIMESTAMP,
+    last_accessed_at TIMESTAMP,
+    access_count INT NOT NULL DEFAULT 0,
+    previous_token VARCHAR(64) UNIQUE,
+    previous_token_expires_at TIMESTAMP
+);
+
+CREATE INDEX idx_bingo_card_shares_expires ON bingo_card_shares(expires_at) WHERE expires_at IS NOT NULL;
+
+CREATE TABLE share_subscriptions (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    card_id TEXT NOT NULL REFERENCES bingo_card_shares(card_id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    confirm_token VARCHAR(64) UNIQUE,
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    confirmed_at TIMESTAMP,
+    last_sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    UNIQUE (card_id, email)
+);
+
+CREATE INDEX idx_share_subscriptions_due ON share_subscriptions(last_sent_at) WHERE confirmed_at IS NOT NULL;
+
+CREATE TABLE card_widget_tokens (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    token VARCHAR(64) NOT NULL UNIQUE,
+    card_id TEXT NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    last_accessed_at TIMESTAMP,
+    access_count INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_card_widget_tokens_card ON card_widget_tokens(card_id);
+
+CREATE TABLE card_shuffle_history (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    card_id TEXT NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    layout TEXT NOT NULL,
+    seed BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_card_shuffle_history_card ON card_shuffle_history(card_id, created_at DESC);
+
+CREATE TABLE item_position_repairs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    card_id TEXT NOT NULL,
+    item_id TEXT NOT NULL,
+    old_position INTEGER NOT NULL,
+    new_position INTEGER,
+    repaired_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE TABLE user_identities (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject TEXT NOT NULL,
+    email_at_link_time VARCHAR(255),
+    linked_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    CONSTRAINT user_identities_provider_subject_unique UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+CREATE INDEX idx_user_identities_provider ON user_identities(provider);
+
+CREATE TABLE account_events (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    details TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_account_events_user ON account_events(user_id, created_at DESC);
+
+CREATE TABLE admin_audit_log (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    admin_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    target_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    target_id TEXT,
+    details TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_admin_audit_log_created ON admin_audit_log(created_at DESC);
+CREATE INDEX idx_admin_audit_log_target_user ON admin_audit_log(target_user_id, created_at DESC);
+
+CREATE TABLE selftest_scratch (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+-- Seed curated suggestions by category
+
+-- Health & Fitness
+INSERT INTO suggestions (category, content) VALUES
+('Health & Fitness', 'Run a 5K race'),
+('Health & Fitness', 'Complete 100 pushups in one session'),
+('Health & Fitness', 'Try a new sport or physical activity'),
+('Health & Fitness', 'Maintain a consistent sleep schedule for 30 days'),
+('Health & Fitness', 'Drink 8 glasses of water daily for a month'),
+('Health & Fitness', 'Take a yoga or meditation class'),
+('Health & Fitness', 'Go for a hike in nature'),
+('Health & Fitness', 'Cook healthy meals at home for a week'),
+('Health & Fitness', 'Complete a fitness challenge'),
+('Health & Fitness', 'Get a physical checkup');
+
+-- Career & Learning
+INSERT INTO suggestions (category, content) VALUES
+('Career & Learning', 'Read 12 books this year'),
+('Career & Learning', 'Learn a new programming language'),
+('Career & Learning', 'Complete an online course or certification'),
+('Career & Learning', 'Attend a professional conference or workshop'),
+('Career & Learning', 'Update your resume or portfolio'),
+('Career & Learning', 'Learn to use a new tool or software'),
+('Career & Learning', 'Give a presentation or public talk'),
+('Career & Learning', 'Mentor someone or get a mentor'),
+('Career & Learning', 'Start a side project'),
+('Career & Learning', 'Network with 5 new professionals');
+
+-- Relationships
+INSERT INTO suggestions (category, content) VALUES
+('Relationships', 'Reconnect with an old friend'),
+('Relationships', 'Plan a special outing with family'),
+('Relationships', 'Write a heartfelt letter to someone you appreciate'),
+('Relationships', 'Host a dinner party or gathering'),
+('Relationships', 'Call a family member weekly for a month'),
+('Relationships', 'Join a club or group to meet new people'),
+('Relationships', 'Volunteer in your community'),
+('Relationships', 'Plan a surprise for someone special'),
+('Relationships', 'Have a digital-free day with loved ones'),
+('Relationships', 'Learn about a friend''s hobby or interest');
+
+-- Hobbies & Creativity
+INSERT INTO suggestions (category, content) VALUES
+('Hobbies & Creativity', 'Learn to play a musical instrument'),
+('Hobbies & Creativity', 'Start a creative journal or sketchbook'),
+('Hobbies & Creativity', 'Try a new craft or DIY project'),
+('Hobbies & Creativity', 'Take up photography'),
+('Hobbies & Creativity', 'Write a short story or poem'),
+('Hobbies & Creativity', 'Learn to cook a cuisine from another culture'),
+('Hobbies & Creativity', 'Start a garden or grow plants'),
+('Hobbies & Creativity', 'Build something with your hands'),
+('Hobbies & Creativity', 'Try painting or drawing'),
+('Hobbies & Creativity', 'Learn a new dance style');
+
+-- Finance
+INSERT INTO suggestions (category, content) VALUES
+('Finance', 'Create and stick to a monthly budget'),
+('Finance', 'Build an emergency fund'),
+('Finance', 'Pay off a debt'),
+('Finance', 'Start investing or increase contributions'),
+('Finance', 'Track all expenses for a month'),
+('Finance', 'Learn about personal finance'),
+('Finance', 'Negotiate a bill or subscription'),
+('Finance', 'Set up automatic savings'),
+('Finance', 'Review and optimize subscriptions'),
+('Finance', 'Create a financial goal and achieve it');
+
+-- Travel & Adventure
+INSERT INTO suggestions (category, content) VALUES
+('Travel & Adventure', 'Visit a new city or country'),
+('Travel & Adventure', 'Take a road trip'),
+('Travel & Adventure', 'Try an adventure activity (skydiving, bungee, etc.)'),
+('Travel & Adventure', 'Explore a local attraction you''ve never visited'),
+('Travel & Adventure', 'Go camping'),
+('Travel & Adventure', 'Plan a weekend getaway'),
+('Travel & Adventure', 'Visit a national park'),
+('Travel & Adventure', 'Take a spontaneous day trip'),
+('Travel & Adventure', 'Try local food in a new place'),
+('Travel & Adventure', 'Document your travels with photos or a journal');
+
+-- Personal Growth
+INSERT INTO suggestions (category, content) VALUES
+('Personal Growth', 'Practice gratitude daily for 30 days'),
+('Personal Growth', 'Break a bad habit'),
+('Personal Growth', 'Establish a morning routine'),
+('Personal Growth', 'Learn to say no when needed'),
+('Personal Growth', 'Spend time in self-reflection weekly'),
+('Personal Growth', 'Face a fear'),
+('Personal Growth', 'Practice mindfulness or meditation regularly'),
+('Personal Growth', 'Set boundaries in a relationship'),
+('Personal Growth', 'Forgive someone or let go of a grudge'),
+('Personal Growth', 'Try something completely outside your comfort zone');
+
+-- Home & Organization
+INSERT INTO suggestions (category, content) VALUES
+('Home & Organization', 'Declutter one room completely'),
+('Home & Organization', 'Organize your digital files'),
+('Home & Organization', 'Complete a home improvement project'),
+('Home & Organization', 'Create a cleaning schedule and maintain it'),
+('Home & Organization', 'Donate items you no longer need'),
+('Home & Organization', 'Set up a productive workspace'),
+('Home & Organization', 'Organize your closet'),
+('Home & Organization', 'Fix something that''s been broken'),
+('Home & Organization', 'Create a system for managing mail and paperwork'),
+('Home & Organization', 'Deep clean your living space');
@@ -0,0 +1,11 @@
+CREATE TABLE reminder_snooze_tokens (
+    token VARCHAR(64) PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reminder_id TEXT NOT NULL REFERENCES goal_reminders(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    used_at TIMESTAMP
+);
+
+CREATE INDEX idx_reminder_snooze_tokens_expires ON reminder_snooze_tokens(expires_at);
+CREATE INDEX idx_reminder_snooze_tokens_reminder ON reminder_snooze_tokens(reminder_id);
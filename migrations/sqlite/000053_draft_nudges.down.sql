@@ -0,0 +1,35 @@
+CREATE TABLE notifications_new (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    actor_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    friendship_id TEXT REFERENCES friendships(id) ON DELETE SET NULL,
+    card_id TEXT REFERENCES bingo_cards(id) ON DELETE SET NULL,
+    bingo_count INT,
+    in_app_delivered BOOLEAN NOT NULL DEFAULT true,
+    email_delivered BOOLEAN NOT NULL DEFAULT false,
+    email_sent_at TIMESTAMP,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    CHECK (type IN ('friend_request_received', 'friend_request_accepted', 'friend_bingo', 'friend_new_card'))
+);
+
+INSERT INTO notifications_new SELECT * FROM notifications WHERE type <> 'draft_nudge';
+DROP TABLE notifications;
+ALTER TABLE notifications_new RENAME TO notifications;
+
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+CREATE INDEX idx_notifications_type ON notifications(type);
+CREATE INDEX idx_notifications_created ON notifications(created_at);
+CREATE UNIQUE INDEX idx_notifications_friend_request_received ON notifications(user_id, friendship_id)
+    WHERE type = 'friend_request_received';
+CREATE UNIQUE INDEX idx_notifications_friend_request_accepted ON notifications(user_id, friendship_id)
+    WHERE type = 'friend_request_accepted';
+CREATE UNIQUE INDEX idx_notifications_friend_bingo ON notifications(user_id, card_id)
+    WHERE type = 'friend_bingo';
+CREATE UNIQUE INDEX idx_notifications_friend_new_card ON notifications(user_id, card_id)
+    WHERE type = 'friend_new_card';
+
+DROP INDEX IF EXISTS idx_bingo_cards_unnudged_drafts;
+ALTER TABLE bingo_cards DROP COLUMN nudged_at;
@@ -0,0 +1,3 @@
+-- What a public share link reveals. Defaults keep existing links unchanged.
+ALTER TABLE bingo_card_shares ADD COLUMN show_completions BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE bingo_card_shares ADD COLUMN show_notes BOOLEAN NOT NULL DEFAULT false;
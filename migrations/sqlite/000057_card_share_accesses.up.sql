@@ -0,0 +1,13 @@
+-- One row per logged visit to a card's share link, for the owner's analytics.
+-- Only the referrer's host and a coarse user agent class are kept. Rows hang
+-- off the share, so revoking it drops them; each share keeps at most its
+-- newest few thousand visits.
+CREATE TABLE card_share_accesses (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    card_id TEXT NOT NULL REFERENCES bingo_card_shares(card_id) ON DELETE CASCADE,
+    accessed_at TIMESTAMP NOT NULL,
+    referrer_host TEXT NOT NULL DEFAULT '',
+    user_agent_class TEXT NOT NULL CHECK (user_agent_class IN ('desktop', 'mobile', 'bot', 'unknown'))
+);
+
+CREATE INDEX idx_card_share_accesses_card ON card_share_accesses(card_id, accessed_at);
@@ -0,0 +1 @@
+ALTER TABLE card_share_accesses DROP COLUMN source_tag;
@@ -0,0 +1 @@
+ALTER TABLE reminder_settings DROP COLUMN image_theme;
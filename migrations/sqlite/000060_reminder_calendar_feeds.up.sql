@@ -0,0 +1,7 @@
+-- One revocable calendar feed per user. Only the token's SHA-256 is kept; the
+-- feed URL is shown once when it is created.
+CREATE TABLE reminder_calendar_feeds (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
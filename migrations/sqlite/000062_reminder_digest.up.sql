@@ -0,0 +1,33 @@
+-- Optional daily digest that folds a user's due reminders into one email.
+ALTER TABLE reminder_settings ADD COLUMN digest_enabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE reminder_settings ADD COLUMN digest_time VARCHAR(5) NOT NULL DEFAULT '08:00';
+ALTER TABLE reminder_settings ADD COLUMN digest_next_at TIMESTAMP;
+
+CREATE INDEX idx_reminder_settings_digest_due ON reminder_settings(digest_next_at) WHERE digest_enabled = true;
+
+-- SQLite can't alter a CHECK constraint, so the table is rebuilt.
+CREATE TABLE reminder_email_log_new (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_type TEXT NOT NULL,
+    source_id TEXT NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    sent_on DATE NOT NULL DEFAULT (strftime('%Y-%m-%d', 'now') || ' 00:00:00.000000'),
+    provider_message_id TEXT,
+    status TEXT NOT NULL,
+    included_sources TEXT,
+    CONSTRAINT reminder_email_log_source_type_check
+        CHECK (source_type IN ('card_checkin', 'goal_reminder', 'friends_digest', 'deliverability_check', 'digest')),
+    CONSTRAINT reminder_email_log_status_check
+        CHECK (status IN ('sent', 'failed', 'manual'))
+);
+
+INSERT INTO reminder_email_log_new (id, user_id, source_type, source_id, sent_at, sent_on, provider_message_id, status)
+SELECT id, user_id, source_type, source_id, sent_at, sent_on, provider_message_id, status FROM reminder_email_log;
+DROP TABLE reminder_email_log;
+ALTER TABLE reminder_email_log_new RENAME TO reminder_email_log;
+
+CREATE INDEX idx_reminder_email_log_user_sent ON reminder_email_log(user_id, sent_at DESC);
+CREATE INDEX idx_reminder_email_log_sent ON reminder_email_log(sent_at);
+CREATE UNIQUE INDEX idx_reminder_email_log_checkin_day ON reminder_email_log(source_type, source_id, sent_on)
+    WHERE source_type = 'card_checkin' AND status <> 'manual';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notifications_email_deferred;
+ALTER TABLE notifications DROP COLUMN email_deferred_at;
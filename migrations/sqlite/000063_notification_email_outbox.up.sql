@@ -0,0 +1,6 @@
+-- Notification emails refused by the email send limiter wait here until the
+-- outbox job sends them.
+ALTER TABLE notifications ADD COLUMN email_deferred_at TIMESTAMP;
+
+CREATE INDEX idx_notifications_email_deferred ON notifications(email_deferred_at)
+    WHERE email_deferred_at IS NOT NULL AND email_sent_at IS NULL;
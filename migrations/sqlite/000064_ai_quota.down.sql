@@ -0,0 +1,3 @@
+ALTER TABLE ai_generation_logs DROP COLUMN tier;
+ALTER TABLE users DROP COLUMN ai_quota_period_start;
+ALTER TABLE users DROP COLUMN ai_unlimited;
@@ -0,0 +1,4 @@
+ALTER TABLE goal_reminders DROP COLUMN claimed_by;
+ALTER TABLE goal_reminders DROP COLUMN claimed_at;
+ALTER TABLE card_checkin_reminders DROP COLUMN claimed_by;
+ALTER TABLE card_checkin_reminders DROP COLUMN claimed_at;
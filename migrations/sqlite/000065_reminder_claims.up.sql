@@ -0,0 +1,8 @@
+-- A reminder runner claims each due reminder before sending its email, so
+-- replicas running side by side never send the same one. claimed_by names
+-- the runner; a claim older than the lease is treated as abandoned.
+ALTER TABLE card_checkin_reminders ADD COLUMN claimed_at TIMESTAMP;
+ALTER TABLE card_checkin_reminders ADD COLUMN claimed_by VARCHAR(100);
+
+ALTER TABLE goal_reminders ADD COLUMN claimed_at TIMESTAMP;
+ALTER TABLE goal_reminders ADD COLUMN claimed_by VARCHAR(100);
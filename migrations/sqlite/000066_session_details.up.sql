@@ -0,0 +1,5 @@
+-- Every session now gets a row (Redis only caches it), so users can list
+-- and revoke their sessions. The client details are captured at login.
+ALTER TABLE sessions ADD COLUMN last_seen_at TIMESTAMP;
+ALTER TABLE sessions ADD COLUMN user_agent VARCHAR(512);
+ALTER TABLE sessions ADD COLUMN ip_address VARCHAR(45);
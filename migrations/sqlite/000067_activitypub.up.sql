@@ -0,0 +1,32 @@
+-- Experimental ActivityPub support (ACTIVITYPUB_ENABLED). One RSA key signs
+-- every actor's requests, so it is stored once for the whole instance.
+CREATE TABLE activitypub_keys (
+    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    private_key_pem TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+-- Remote actors following a public profile, from accepted Follow activities.
+CREATE TABLE activitypub_followers (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_uri TEXT NOT NULL,
+    inbox_url TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    UNIQUE (user_id, actor_uri)
+);
+
+-- Outbox entries, recorded from the same completions and bingos that notify
+-- friends. The progress is a snapshot so each post reads as it was sent.
+CREATE TABLE activitypub_activities (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    card_id TEXT NOT NULL REFERENCES bingo_cards(id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL CHECK (kind IN ('item_completed', 'bingo')),
+    completed_items INT NOT NULL,
+    total_items INT NOT NULL,
+    bingo_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX idx_activitypub_activities_user ON activitypub_activities(user_id, created_at DESC, id DESC);
@@ -56,6 +56,7 @@ test('AI wizard continues after free limit for verified users', async ({ page, r
   const token = extractTokenFromEmail(message, 'verify-email');
 
   await page.goto(`/verify-email?token=${token}`);
+  await page.getByRole('button', { name: 'Verify email' }).click();
   await expect(page.getByRole('heading', { name: 'Email Verified!' })).toBeVisible();
 
   await page.goto('/create');
@@ -29,6 +29,7 @@ test('magic link login signs in with emailed token', async ({ page, request }, t
   const token = extractTokenFromEmail(message, 'magic-link');
 
   await page.goto(`/magic-link?token=${token}`);
+  await page.getByRole('button', { name: 'Sign in', exact: true }).click();
   await expect(page.getByRole('heading', { name: 'My Bingo Cards' })).toBeVisible();
 });
 
@@ -85,6 +86,7 @@ test('email verification banner clears after verifying', async ({ page, request
   const token = extractTokenFromEmail(message, 'verify-email');
 
   await page.goto(`/verify-email?token=${token}`);
+  await page.getByRole('button', { name: 'Verify email' }).click();
   await expect(page.getByRole('heading', { name: 'Email Verified!' })).toBeVisible();
 
   await page.goto('/dashboard');
@@ -115,6 +117,7 @@ test('verification link does not break authenticated actions in an already-open
   const mailPage = await page.context().newPage();
   await mailPage.goto('http://mailpit:8025');
   await mailPage.goto(`/verify-email?token=${token}`);
+  await mailPage.getByRole('button', { name: 'Verify email' }).click();
   await expect(mailPage.getByRole('heading', { name: 'Email Verified!' })).toBeVisible();
   await mailPage.close();
 
@@ -7,6 +7,7 @@ const {
   waitForEmail,
   extractTokenFromEmail,
   setOIDCNextUser,
+  startGoogleLogin,
 } = require('./helpers');
 
 test.describe.serial('google auth', () => {
@@ -93,4 +94,28 @@ test.describe.serial('google auth', () => {
 
     await loginWithCredentials(page, user.email, 'NewPass1');
   });
+
+  test('google login shows an error when the token exchange fails', async ({ page }, testInfo) => {
+    const user = buildUser(testInfo, 'googletokenerr');
+    await startGoogleLogin(page, {
+      email: user.email,
+      sub: `sub-${user.email}`,
+      tokenError: true,
+    });
+
+    await expect(page).toHaveURL(/\/login\?error=oauth_exchange/);
+    await expect(page.getByText('Google sign-in failed. Please try again.')).toBeVisible();
+  });
+
+  test('google login rejects an expired id_token', async ({ page }, testInfo) => {
+    const user = buildUser(testInfo, 'googleexpired');
+    await startGoogleLogin(page, {
+      email: user.email,
+      sub: `sub-${user.email}`,
+      expiredIdToken: true,
+    });
+
+    await expect(page).toHaveURL(/\/login\?error=oauth_exchange/);
+    await expect(page.getByText('Google sign-in failed. Please try again.')).toBeVisible();
+  });
 });
@@ -200,7 +200,14 @@ async function clearMailpit(request) {
   }
 }
 
-async function setOIDCNextUser(request, { email, emailVerified = true, sub } = {}) {
+async function setOIDCNextUser(request, {
+  email,
+  emailVerified = true,
+  sub,
+  state,
+  tokenError = false,
+  expiredIdToken = false,
+} = {}) {
   const payload = {
     email,
     email_verified: emailVerified,
@@ -208,6 +215,15 @@ async function setOIDCNextUser(request, { email, emailVerified = true, sub } = {
   if (sub) {
     payload.sub = sub;
   }
+  if (state) {
+    payload.state = state;
+  }
+  if (tokenError) {
+    payload.token_error = true;
+  }
+  if (expiredIdToken) {
+    payload.expired_id_token = true;
+  }
 
   const response = await request.post(`${OIDC_BASE_URL}/test/next-user`, { data: payload });
   if (!response.ok()) {
@@ -215,6 +231,26 @@ async function setOIDCNextUser(request, { email, emailVerified = true, sub } = {
   }
 }
 
+async function resetOIDC(request) {
+  const response = await request.post(`${OIDC_BASE_URL}/test/reset`);
+  if (!response.ok()) {
+    throw new Error(`Failed to reset OIDC server: ${response.status()}`);
+  }
+}
+
+// Starts Google sign-in and binds the OIDC user to this flow's state, so the
+// login is safe to run alongside other OIDC tests.
+async function startGoogleLogin(page, user = {}) {
+  const response = await page.request.get('/api/auth/google/start', { maxRedirects: 0 });
+  const location = response.headers().location;
+  if (!location) {
+    throw new Error(`Google start did not redirect: ${response.status()}`);
+  }
+  const state = new URL(location).searchParams.get('state');
+  await setOIDCNextUser(page.request, { ...user, state });
+  await page.goto(location);
+}
+
 function getMessageId(message) {
   return message.ID || message.id || message.Id || null;
 }
@@ -362,6 +398,8 @@ module.exports = {
   sendFriendRequest,
   clearMailpit,
   setOIDCNextUser,
+  resetOIDC,
+  startGoogleLogin,
   waitForEmail,
   expectNoEmail,
   extractTokenFromEmail,
@@ -28,6 +28,7 @@ test('email notifications respect opt-in', async ({ browser, request }, testInfo
   });
   const token = extractTokenFromEmail(verifyMessage, 'verify-email');
   await pageB.goto(`/verify-email?token=${token}`);
+  await pageB.getByRole('button', { name: 'Verify email' }).click();
   await expect(pageB.getByRole('heading', { name: 'Email Verified!' })).toBeVisible();
 
   const contextA = await browser.newContext();
@@ -16,6 +16,7 @@ async function verifyEmail(page, request, user) {
   });
   const token = extractTokenFromEmail(verifyMessage, 'verify-email');
   await page.goto(`/verify-email?token=${token}`);
+  await page.getByRole('button', { name: 'Verify email' }).click();
   await expect(page.getByRole('heading', { name: 'Email Verified!' })).toBeVisible();
 }
 
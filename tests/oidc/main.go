@@ -24,12 +24,33 @@ const (
 	defaultClientID     = "oidc-test"
 	defaultClientSecret = "oidc-secret"
 	defaultRedirectURI  = "http://app:8080/api/auth/google/callback"
+
+	// Bounds that keep a misbehaving test run from growing server state
+	// without limit.
+	maxPendingUsers = 100
+	pendingUserTTL  = 5 * time.Minute
+	authCodeTTL     = 5 * time.Minute
+	maxBodyBytes    = 16 << 10
 )
 
 type nextUser struct {
 	Email         string `json:"email"`
 	EmailVerified bool   `json:"email_verified"`
 	Sub           string `json:"sub"`
+
+	// State binds the user to the /authorize call carrying the same state,
+	// so parallel tests can't consume each other's users. Without it the
+	// user is matched by login_hint email, then in FIFO order.
+	State string `json:"state,omitempty"`
+
+	// Error injection for exercising the app's callback failure paths.
+	TokenError     bool `json:"token_error,omitempty"`
+	ExpiredIDToken bool `json:"expired_id_token,omitempty"`
+}
+
+type pendingUser struct {
+	User     nextUser
+	QueuedAt time.Time
 }
 
 type authCodeData struct {
@@ -49,7 +70,7 @@ type server struct {
 	keyID        string
 
 	mu        sync.Mutex
-	nextUser  *nextUser
+	pending   []pendingUser
 	authCodes map[string]authCodeData
 }
 
@@ -65,6 +86,7 @@ func main() {
 	http.HandleFunc("/token", srv.handleToken)
 	http.HandleFunc("/keys", srv.handleKeys)
 	http.HandleFunc("/test/next-user", srv.handleNextUser)
+	http.HandleFunc("/test/reset", srv.handleReset)
 
 	addr := ":5555"
 	server := &http.Server{
@@ -144,7 +166,7 @@ func (s *server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user := s.consumeNextUser()
+	user := s.consumeNextUser(state, query.Get("login_hint"))
 	if user.Email == "" {
 		http.Error(w, "missing test user", http.StatusBadRequest)
 		return
@@ -160,6 +182,7 @@ func (s *server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.mu.Lock()
+	s.pruneAuthCodesLocked(time.Now())
 	s.authCodes[code] = authCodeData{
 		User:        user,
 		Nonce:       nonce,
@@ -212,10 +235,14 @@ func (s *server) handleToken(w http.ResponseWriter, r *http.Request) {
 		delete(s.authCodes, code)
 	}
 	s.mu.Unlock()
-	if !ok {
+	if !ok || time.Since(data.IssuedAt) > authCodeTTL {
 		http.Error(w, "invalid code", http.StatusBadRequest)
 		return
 	}
+	if data.User.TokenError {
+		http.Error(w, "injected token error", http.StatusInternalServerError)
+		return
+	}
 
 	expectedClientID := data.ClientID
 	if expectedClientID == "" {
@@ -278,6 +305,7 @@ func (s *server) handleNextUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var user nextUser
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
 	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
 		http.Error(w, "invalid body", http.StatusBadRequest)
 		return
@@ -290,9 +318,39 @@ func (s *server) handleNextUser(w http.ResponseWriter, r *http.Request) {
 	if user.Sub != "" {
 		user.Sub = strings.TrimSpace(user.Sub)
 	}
+	user.State = strings.TrimSpace(user.State)
 
 	s.mu.Lock()
-	s.nextUser = &user
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.prunePendingLocked(now)
+	if user.State != "" {
+		// Re-registering a state replaces the earlier user for it.
+		for i, p := range s.pending {
+			if p.User.State == user.State {
+				s.pending = append(s.pending[:i], s.pending[i+1:]...)
+				break
+			}
+		}
+	}
+	if len(s.pending) >= maxPendingUsers {
+		http.Error(w, "too many pending users", http.StatusTooManyRequests)
+		return
+	}
+	s.pending = append(s.pending, pendingUser{User: user, QueuedAt: now})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) handleReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	s.pending = nil
+	s.authCodes = map[string]authCodeData{}
 	s.mu.Unlock()
 
 	w.WriteHeader(http.StatusNoContent)
@@ -300,12 +358,18 @@ func (s *server) handleNextUser(w http.ResponseWriter, r *http.Request) {
 
 func (s *server) issueIDToken(data authCodeData) (string, error) {
 	now := time.Now()
+	issuedAt := now
+	expiresAt := now.Add(10 * time.Minute)
+	if data.User.ExpiredIDToken {
+		issuedAt = now.Add(-20 * time.Minute)
+		expiresAt = now.Add(-10 * time.Minute)
+	}
 	claims := map[string]any{
 		"iss":            s.issuer,
 		"sub":            data.User.Sub,
 		"aud":            data.ClientID,
-		"exp":            now.Add(10 * time.Minute).Unix(),
-		"iat":            now.Unix(),
+		"exp":            expiresAt.Unix(),
+		"iat":            issuedAt.Unix(),
 		"email":          data.User.Email,
 		"email_verified": data.User.EmailVerified,
 		"nonce":          data.Nonce,
@@ -320,17 +384,65 @@ func (s *server) issueIDToken(data authCodeData) (string, error) {
 	return signJWT(header, claims, s.privateKey)
 }
 
-func (s *server) consumeNextUser() nextUser {
+// consumeNextUser removes and returns the pending user for an /authorize
+// call: the one registered for its state, else the oldest unkeyed user
+// whose email matches loginHint, else the oldest unkeyed user.
+func (s *server) consumeNextUser(state, loginHint string) nextUser {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if s.nextUser == nil {
+	s.prunePendingLocked(time.Now())
+
+	loginHint = strings.TrimSpace(strings.ToLower(loginHint))
+	match := -1
+	for i, p := range s.pending {
+		if state != "" && p.User.State == state {
+			match = i
+			break
+		}
+	}
+	if match < 0 && loginHint != "" {
+		for i, p := range s.pending {
+			if p.User.State == "" && p.User.Email == loginHint {
+				match = i
+				break
+			}
+		}
+	}
+	if match < 0 {
+		for i, p := range s.pending {
+			if p.User.State == "" {
+				match = i
+				break
+			}
+		}
+	}
+	if match < 0 {
 		return nextUser{}
 	}
-	user := *s.nextUser
-	s.nextUser = nil
+
+	user := s.pending[match].User
+	s.pending = append(s.pending[:match], s.pending[match+1:]...)
 	return user
 }
 
+func (s *server) prunePendingLocked(now time.Time) {
+	kept := s.pending[:0]
+	for _, p := range s.pending {
+		if now.Sub(p.QueuedAt) <= pendingUserTTL {
+			kept = append(kept, p)
+		}
+	}
+	s.pending = kept
+}
+
+func (s *server) pruneAuthCodesLocked(now time.Time) {
+	for code, data := range s.authCodes {
+		if now.Sub(data.IssuedAt) > authCodeTTL {
+			delete(s.authCodes, code)
+		}
+	}
+}
+
 func signJWT(header, claims map[string]any, key *rsa.PrivateKey) (string, error) {
 	headerJSON, err := json.Marshal(header)
 	if err != nil {
@@ -72,6 +72,22 @@ const AIWizard = {
     `);
   },
 
+  // Shown when a verified user's monthly AI allowance is spent; the server's
+  // message says when it resets.
+  showQuotaExhaustedModal(message) {
+    App.openModal('AI Limit Reached', `
+      <div class="finalize-confirm-modal">
+        <p style="margin-bottom: 1rem;">${App.escapeHtml(message || "You've used all of this month's free AI generations.")}</p>
+        <p class="text-muted" style="margin-bottom: 1.5rem;">
+          You can keep adding goals by hand in the meantime.
+        </p>
+        <div style="display: flex; gap: 1rem; justify-content: flex-end; flex-wrap: wrap;">
+          <button class="btn btn-primary" data-action="close-modal">OK</button>
+        </div>
+      </div>
+    `);
+  },
+
   mapWizardCategoryToCardCategory(category) {
     const map = {
       hobbies: 'hobbies',
@@ -323,6 +339,12 @@ const AIWizard = {
           return;
         }
       }
+      if (error?.data?.code === 'quota_exhausted') {
+        this.showQuotaExhaustedModal(error.message);
+        this.state.step = 'input';
+        this.render();
+        return;
+      }
       App.toast(error.message, 'error');
       this.state.step = 'input';
       this.render();
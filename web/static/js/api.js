@@ -29,7 +29,9 @@ const API = {
   },
 
   async request(method, path, body = null, options = {}) {
-    const headers = {
+    // FormData bodies are sent as multipart; the browser sets the boundary.
+    const isForm = body instanceof FormData;
+    const headers = isForm ? {} : {
       'Content-Type': 'application/json',
     };
 
@@ -44,7 +46,7 @@ const API = {
     };
 
     if (body && method !== 'GET') {
-      fetchOptions.body = JSON.stringify(body);
+      fetchOptions.body = isForm ? body : JSON.stringify(body);
     }
 
     // Add timeout support
@@ -227,7 +229,7 @@ const API = {
     },
 
     async verifyMagicLink(token) {
-      return API.request('GET', `/api/auth/magic-link/verify?token=${encodeURIComponent(token)}`);
+      return API.request('POST', '/api/auth/magic-link/verify', { token });
     },
 
     async forgotPassword(email) {
@@ -238,6 +240,10 @@ const API = {
       return API.request('POST', '/api/auth/reset-password', { token, password });
     },
 
+    async providerPending(provider) {
+      return API.request('GET', `/api/auth/${encodeURIComponent(provider)}/pending`);
+    },
+
     async providerComplete(provider, username, searchable) {
       return API.request('POST', `/api/auth/${encodeURIComponent(provider)}/complete`, {
         username,
@@ -248,6 +254,39 @@ const API = {
     async updateSearchable(searchable) {
       return API.request('PUT', '/api/auth/searchable', { searchable });
     },
+
+    async listSessions() {
+      return API.request('GET', '/api/auth/sessions');
+    },
+
+    async revokeSession(id) {
+      return API.request('DELETE', `/api/auth/sessions/${id}`);
+    },
+
+    async revokeOtherSessions() {
+      return API.request('DELETE', '/api/auth/sessions');
+    },
+
+    async listProviders() {
+      return API.request('GET', '/api/auth/providers');
+    },
+
+    async linkProvider(provider) {
+      const response = await API.request('POST', `/api/auth/${encodeURIComponent(provider)}/link`);
+      window.location.href = response.redirect_url;
+    },
+
+    async unlinkProvider(provider) {
+      return API.request('DELETE', `/api/auth/providers/${encodeURIComponent(provider)}`);
+    },
+
+    async getPreferences() {
+      return API.request('GET', '/api/auth/preferences');
+    },
+
+    async updatePreferences(preferences) {
+      return API.request('PUT', '/api/auth/preferences', preferences);
+    },
   },
 
   account: {
@@ -273,6 +312,8 @@ const API = {
       if (options && typeof options.gridSize === 'number') body.grid_size = options.gridSize;
       if (options && typeof options.headerText === 'string') body.header_text = options.headerText;
       if (options && typeof options.hasFreeSpace === 'boolean') body.has_free_space = options.hasFreeSpace;
+      if (options && options.startDate) body.start_date = options.startDate;
+      if (options && options.endDate) body.end_date = options.endDate;
       return API.request('POST', '/api/cards', body, { allowConflictResponse: true });
     },
 
@@ -299,11 +340,17 @@ const API = {
       return API.request('GET', '/api/cards/categories');
     },
 
-    async addItem(cardId, content, position = null) {
+    async addItem(cardId, content, position = null, isPrivate = false, difficulty = '') {
       const body = { content };
       if (position !== null) {
         body.position = position;
       }
+      if (isPrivate) {
+        body.is_private = true;
+      }
+      if (difficulty) {
+        body.difficulty = difficulty;
+      }
       return API.request('POST', `/api/cards/${cardId}/items`, body);
     },
 
@@ -315,8 +362,12 @@ const API = {
       return API.request('DELETE', `/api/cards/${cardId}/items/${position}`);
     },
 
-    async shuffle(cardId) {
-      return API.request('POST', `/api/cards/${cardId}/shuffle`);
+    async shuffle(cardId, seed = null) {
+      return API.request('POST', `/api/cards/${cardId}/shuffle`, seed === null ? null : { seed });
+    },
+
+    async undoShuffle(cardId) {
+      return API.request('POST', `/api/cards/${cardId}/shuffle/undo`);
     },
 
     async swap(cardId, position1, position2) {
@@ -332,19 +383,41 @@ const API = {
       return API.request('GET', `/api/cards/${cardId}/share`);
     },
 
-    async shareEnable(cardId, expiresInDays = null) {
-      const body = typeof expiresInDays === 'number' ? { expires_in_days: expiresInDays } : null;
-      return API.request('POST', `/api/cards/${cardId}/share`, body);
+    async shareEnable(cardId, expiresInDays = null, graceDays = null, options = {}) {
+      const body = {};
+      if (typeof expiresInDays === 'number') body.expires_in_days = expiresInDays;
+      if (typeof graceDays === 'number') body.grace_days = graceDays;
+      if (typeof options.show_completions === 'boolean') body.show_completions = options.show_completions;
+      if (typeof options.show_notes === 'boolean') body.show_notes = options.show_notes;
+      return API.request('POST', `/api/cards/${cardId}/share`, Object.keys(body).length ? body : null);
     },
 
     async shareDisable(cardId) {
       return API.request('DELETE', `/api/cards/${cardId}/share`);
     },
 
-    async updateConfig(cardId, headerText = null, hasFreeSpace = null) {
+    async shareSubscriberRemove(cardId, subscriberId) {
+      return API.request('DELETE', `/api/cards/${cardId}/share/subscribers/${subscriberId}`);
+    },
+
+    async widgetTokens(cardId) {
+      return API.request('GET', `/api/cards/${cardId}/widget-tokens`);
+    },
+
+    async widgetTokenCreate(cardId) {
+      return API.request('POST', `/api/cards/${cardId}/widget-token`);
+    },
+
+    async widgetTokenRevoke(cardId, tokenId) {
+      return API.request('DELETE', `/api/cards/${cardId}/widget-tokens/${tokenId}`);
+    },
+
+    async updateConfig(cardId, headerText = null, hasFreeSpace = null, freeSpaceText = null, requireProof = null) {
       const body = {};
       if (headerText !== null) body.header_text = headerText;
       if (hasFreeSpace !== null) body.has_free_space = hasFreeSpace;
+      if (freeSpaceText !== null) body.free_space_text = freeSpaceText;
+      if (requireProof !== null) body.require_proof = requireProof;
       return API.request('PUT', `/api/cards/${cardId}/config`, body);
     },
 
@@ -378,7 +451,14 @@ const API = {
       });
     },
 
-    async completeItem(cardId, position, notes = null, proofUrl = null) {
+    async completeItem(cardId, position, notes = null, proofUrl = null, proofImage = null) {
+      if (proofImage) {
+        const form = new FormData();
+        if (notes) form.append('notes', notes);
+        if (proofUrl) form.append('proof_url', proofUrl);
+        form.append('proof', proofImage);
+        return API.request('PUT', `/api/cards/${cardId}/items/${position}/complete`, form, { timeout: 60000 });
+      }
       const body = {};
       if (notes) body.notes = notes;
       if (proofUrl) body.proof_url = proofUrl;
@@ -400,10 +480,33 @@ const API = {
       return API.request('GET', '/api/cards/archive');
     },
 
+    async search(query, cursor = '') {
+      const params = new URLSearchParams({ q: query });
+      if (cursor) params.set('cursor', cursor);
+      return API.request('GET', `/api/cards/search?${params.toString()}`);
+    },
+
+    async getMemories() {
+      const tz = Intl.DateTimeFormat().resolvedOptions().timeZone || '';
+      return API.request('GET', `/api/memories${tz ? `?tz=${encodeURIComponent(tz)}` : ''}`);
+    },
+
     async getStats(cardId) {
       return API.request('GET', `/api/cards/${cardId}/stats`);
     },
 
+    async getRecap(cardId) {
+      return API.request('GET', `/api/cards/${cardId}/recap`);
+    },
+
+    async getAllStats(includeArchived = false) {
+      return API.request('GET', `/api/cards/stats${includeArchived ? '?include_archived=true' : ''}`);
+    },
+
+    async getRecommendations(cardId, limit = 3) {
+      return API.request('GET', `/api/cards/${cardId}/recommendations?limit=${limit}`);
+    },
+
     async getExportable() {
       return API.request('GET', '/api/cards/export');
     },
@@ -411,6 +514,14 @@ const API = {
     async import(cardData) {
       return API.request('POST', '/api/cards/import', cardData, { allowConflictResponse: true });
     },
+
+    async exportFile(cardId) {
+      return API.requestBlob('GET', `/api/cards/${cardId}/export.json`);
+    },
+
+    async importFile(doc) {
+      return API.request('POST', '/api/cards/import-json', doc);
+    },
   },
 
   // Suggestion endpoints
@@ -501,11 +612,11 @@ const API = {
 
   // Notification endpoints
   notifications: {
-    async list({ unread = false, limit = 50, before = null } = {}) {
+    async list({ unread = false, limit = 50, cursor = null } = {}) {
       const params = new URLSearchParams();
       if (unread) params.set('unread', '1');
       if (limit) params.set('limit', String(limit));
-      if (before) params.set('before', before);
+      if (cursor) params.set('cursor', cursor);
       const query = params.toString();
       const path = query ? `/api/notifications?${query}` : '/api/notifications';
       return API.request('GET', path);
@@ -538,6 +649,10 @@ const API = {
     async updateSettings(patch) {
       return API.request('PUT', '/api/notifications/settings', patch);
     },
+
+    async copySettings(from, to) {
+      return API.request('POST', '/api/notifications/settings/copy', { from, to });
+    },
   },
 
   // Reminder endpoints
@@ -575,9 +690,39 @@ const API = {
       return API.request('DELETE', `/api/reminders/goals/${id}`);
     },
 
+    // Previews a schedule without saving it: { type: 'checkin' | 'goal', frequency?, kind?, schedule }.
+    async validateSchedule(payload) {
+      return API.request('POST', '/api/reminders/validate-schedule', payload);
+    },
+
     async sendTestEmail(cardId) {
       return API.request('POST', '/api/reminders/test', { card_id: cardId });
     },
+
+    async revokeImageTokens() {
+      return API.request('DELETE', '/api/reminders/image-tokens');
+    },
+
+    async getDeliverability() {
+      return API.request('GET', '/api/reminders/deliverability-check');
+    },
+
+    async runDeliverabilityCheck() {
+      return API.request('POST', '/api/reminders/deliverability-check');
+    },
+
+    async getCalendarFeed() {
+      return API.request('GET', '/api/reminders/calendar-feed');
+    },
+
+    // Issues a new feed URL; the previous one stops working.
+    async createCalendarFeed() {
+      return API.request('POST', '/api/reminders/calendar-feed');
+    },
+
+    async revokeCalendarFeed() {
+      return API.request('DELETE', '/api/reminders/calendar-feed');
+    },
   },
 
   // Reaction endpoints
@@ -599,6 +744,21 @@ const API = {
     },
   },
 
+  // Comment endpoints
+  comments: {
+    async list(itemId) {
+      return API.request('GET', `/api/items/${itemId}/comments`);
+    },
+
+    async add(itemId, content) {
+      return API.request('POST', `/api/items/${itemId}/comments`, { content });
+    },
+
+    async remove(commentId) {
+      return API.request('DELETE', `/api/comments/${commentId}`);
+    },
+  },
+
   // Token endpoints
   tokens: {
     async list() {
@@ -634,6 +794,21 @@ const API = {
     async get(token) {
       return API.request('GET', `/api/share/${encodeURIComponent(token)}`);
     },
+
+    async subscribe(token, email) {
+      return API.request('POST', `/api/share/${encodeURIComponent(token)}/subscribe`, { email });
+    },
+  },
+
+  // Public profile endpoints
+  profile: {
+    async getSettings() {
+      return API.request('GET', '/api/profile/settings');
+    },
+
+    async updateSettings(patch) {
+      return API.request('PUT', '/api/profile/settings', patch);
+    },
   },
 
   // AI endpoints
@@ -657,6 +832,9 @@ const API = {
         avoid,
       }, { timeout: 100000 });
     },
+    async quota() {
+      return API.request('GET', '/api/ai/quota');
+    },
   },
 };
 
@@ -28,6 +28,7 @@ const App = {
 
   async init() {
     this.googleOAuthEnabled = document.body?.dataset?.googleOauthEnabled === 'true';
+    this.brandName = document.body?.dataset?.brandName || 'Year of Bingo';
     await API.init();
     await this.checkAuth();
     this.setupActionDelegation();
@@ -146,6 +147,18 @@ const App = {
       case 'send-reminder-test':
         this.sendReminderTest();
         break;
+      case 'revoke-reminder-image-tokens':
+        this.revokeReminderImageTokens();
+        break;
+      case 'create-calendar-feed':
+        this.createCalendarFeed();
+        break;
+      case 'revoke-calendar-feed':
+        this.revokeCalendarFeed();
+        break;
+      case 'run-deliverability-check':
+        this.runDeliverabilityCheck();
+        break;
       case 'set-goal-reminder':
         this.setGoalReminder(target);
         break;
@@ -177,6 +190,12 @@ const App = {
       case 'resend-verification':
         this.resendVerification();
         break;
+      case 'confirm-verify-email':
+        this.confirmVerifyEmail(target.dataset.token);
+        break;
+      case 'confirm-magic-link':
+        this.confirmMagicLink(target.dataset.token);
+        break;
       case 'resend-verification-and-route':
         this.resendVerification();
         this.navigate(`/check-email?type=verification&email=${encodeURIComponent(this.user?.email || '')}`, { skipWarning: true });
@@ -205,6 +224,12 @@ const App = {
       case 'export-cards':
         this.exportSelectedCards();
         break;
+      case 'export-card-file':
+        this.exportSelectedCardFile();
+        break;
+      case 'import-card-file':
+        document.getElementById('import-card-file-input')?.click();
+        break;
       case 'delete-card':
         if (target.dataset.cardId) this.deleteCard(target.dataset.cardId);
         break;
@@ -224,12 +249,21 @@ const App = {
         if (cardId) this.toggleCardVisibility(cardId, visible);
         break;
       }
+      case 'toggle-require-proof': {
+        const cardId = target.dataset.cardId;
+        const requireProof = target.dataset.requireProof === 'true';
+        if (cardId) this.toggleRequireProof(cardId, requireProof);
+        break;
+      }
       case 'confirm-clear-card-items':
         this.confirmClearCardItems();
         break;
       case 'shuffle-card':
         this.shuffleCard();
         break;
+      case 'undo-shuffle-card':
+        this.undoShuffleCard();
+        break;
       case 'show-clone-card-modal':
         this.showCloneCardModal();
         break;
@@ -242,9 +276,27 @@ const App = {
       case 'disable-share':
         this.disableShare();
         break;
+      case 'rotate-share':
+        this.rotateShare();
+        break;
       case 'copy-share-link':
         this.copyShareLink();
         break;
+      case 'create-widget-link':
+        this.createWidgetLink();
+        break;
+      case 'copy-widget-link':
+        this.copyToClipboard(`${window.location.origin}${target.dataset.url}`);
+        break;
+      case 'revoke-widget-link':
+        this.revokeWidgetLink(target.dataset.tokenId);
+        break;
+      case 'remove-share-subscriber':
+        this.removeShareSubscriber(target.dataset.subscriberId);
+        break;
+      case 'more-card-search':
+        this.loadCardSearchPage();
+        break;
       case 'finalize-card':
         this.finalizeCard();
         break;
@@ -360,6 +412,18 @@ const App = {
       case 'revoke-all-tokens':
         this.revokeAllTokens();
         break;
+      case 'link-provider':
+        if (target.dataset.provider) this.linkProvider(target.dataset.provider);
+        break;
+      case 'unlink-provider':
+        if (target.dataset.provider) this.unlinkProvider(target.dataset.provider);
+        break;
+      case 'revoke-session':
+        if (target.dataset.sessionId) this.revokeSession(target.dataset.sessionId);
+        break;
+      case 'revoke-other-sessions':
+        this.revokeOtherSessions();
+        break;
       case 'copy-new-token': {
         const tokenEl = document.getElementById('new-token');
         if (tokenEl?.textContent) this.copyToClipboard(tokenEl.textContent);
@@ -399,6 +463,12 @@ const App = {
       case 'clone-card':
         this.handleCloneCard(event);
         break;
+      case 'subscribe-shared-card':
+        this.subscribeToSharedCard(event, form);
+        break;
+      case 'search-cards':
+        this.searchCards(event, form);
+        break;
       case 'finalize-register':
         this.handleFinalizeRegister(event);
         break;
@@ -433,6 +503,9 @@ const App = {
       case 'dashboard-selection':
         this.updateDashboardSelection();
         break;
+      case 'import-card-file':
+        this.importCardFile(target);
+        break;
       case 'friend-card-select':
         this.switchFriendCard(target.value);
         break;
@@ -442,9 +515,27 @@ const App = {
       case 'notification-scenario-toggle':
         this.handleNotificationScenarioToggle(target);
         break;
+      case 'notification-email-format':
+        this.handleNotificationEmailFormat(target);
+        break;
       case 'reminder-master-toggle':
         this.handleReminderMasterToggle(target);
         break;
+      case 'reminder-image-token-mode':
+        this.handleReminderImageTokenMode(target);
+        break;
+      case 'reminder-image-theme':
+        this.handleReminderImageTheme(target);
+        break;
+      case 'reminder-timezone':
+        this.handleReminderTimezone(target);
+        break;
+      case 'reminder-digest-toggle':
+        this.handleReminderDigest({ digest_enabled: target.checked }, target);
+        break;
+      case 'reminder-digest-time':
+        this.handleReminderDigest({ digest_time: target.value }, target);
+        break;
       case 'reminder-card-select':
         this.handleReminderCardSelect(target);
         break;
@@ -830,7 +921,7 @@ const App = {
 
     try {
       const response = await API.notifications.list({ limit: 50 });
-      const notifications = response?.notifications || [];
+      const notifications = response?.items || [];
       const counts = this.renderNotificationList(listEl, notifications);
       this.updateNotificationMarkAllButton(markAllBtn, counts.unreadCount);
       this.updateNotificationDeleteAllButton(deleteAllBtn, counts.totalCount);
@@ -972,12 +1063,26 @@ const App = {
       }
       case 'friend_new_card':
         return `${actor} created a new card: ${cardName}.`;
+      case 'item_comment':
+        return `${actor} commented on a goal on ${cardName}.`;
+      case 'draft_nudge':
+        return `${cardName} is still a draft. Finalize it to start tracking your goals.`;
+      case 'reminder_checkin':
+        return `Monthly check-in for ${cardName}.`;
+      case 'reminder_goal':
+        return notification.item_content
+          ? `Reminder: "${notification.item_content}" on ${cardName}.`
+          : `Goal reminder for ${cardName}.`;
       default:
         return 'You have a new notification.';
     }
   },
 
   getNotificationLink(notification) {
+    const ownCardTypes = ['draft_nudge', 'item_comment', 'reminder_checkin', 'reminder_goal'];
+    if (ownCardTypes.includes(notification.type) && notification.card_id) {
+      return `/card/${notification.card_id}`;
+    }
     if (notification.type === 'friend_bingo' || notification.type === 'friend_new_card') {
       if (notification.friendship_id) {
         return `/friend-card/${notification.friendship_id}`;
@@ -1090,6 +1195,65 @@ const App = {
     }
   },
 
+  async loadPublicProfileSettings() {
+    const container = document.getElementById('public-profile-settings');
+    if (!container) return;
+
+    try {
+      const response = await API.profile.getSettings();
+      this.renderPublicProfileSettings(container, response.settings);
+    } catch (error) {
+      container.innerHTML = `<p class="text-muted">${this.escapeHtml(error.message)}</p>`;
+    }
+  },
+
+  renderPublicProfileSettings(container, settings) {
+    if (!settings) {
+      container.innerHTML = '<p class="text-muted">Unable to load public profile settings.</p>';
+      return;
+    }
+
+    const isPublic = settings.profile_visibility === 'public';
+    container.innerHTML = `
+      <label class="checkbox-label">
+        <input type="checkbox" id="public-profile-toggle" ${isPublic ? 'checked' : ''}>
+        <span>Publish a public profile page</span>
+      </label>
+      <small class="text-muted">${this.escapeHtml(settings.notice || '')}</small>
+      ${isPublic ? `
+        <div class="public-profile-link">
+          <a href="${this.escapeHtml(settings.profile_url)}" target="_blank" rel="noopener">${this.escapeHtml(settings.profile_url)}</a>
+        </div>
+        <label class="checkbox-label">
+          <input type="checkbox" id="public-profile-indexable" ${settings.profile_indexable ? 'checked' : ''}>
+          <span>Allow search engines to index my profile</span>
+        </label>
+      ` : ''}
+    `;
+
+    const update = async (patch, input) => {
+      try {
+        const response = await API.profile.updateSettings(patch);
+        this.renderPublicProfileSettings(container, response.settings);
+        this.toast('Public profile updated', 'success');
+      } catch (error) {
+        input.checked = !input.checked;
+        this.toast(error.message, 'error');
+      }
+    };
+
+    const toggle = container.querySelector('#public-profile-toggle');
+    toggle.addEventListener('change', () => {
+      update({ profile_visibility: toggle.checked ? 'public' : 'off' }, toggle);
+    });
+    const indexable = container.querySelector('#public-profile-indexable');
+    if (indexable) {
+      indexable.addEventListener('change', () => {
+        update({ profile_indexable: indexable.checked }, indexable);
+      });
+    }
+  },
+
   async loadNotificationSettings() {
     const container = document.getElementById('notification-settings');
     if (!container) return;
@@ -1138,6 +1302,14 @@ const App = {
               <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="in_app_friend_new_card" ${settings.in_app_friend_new_card ? 'checked' : ''}>
               <span>Friend creates a new card</span>
             </label>
+            <label class="checkbox-label">
+              <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="in_app_reminder_checkin" ${settings.in_app_reminder_checkin ? 'checked' : ''}>
+              <span>Card check-in sent</span>
+            </label>
+            <label class="checkbox-label">
+              <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="in_app_reminder_goal" ${settings.in_app_reminder_goal ? 'checked' : ''}>
+              <span>Goal reminder sent</span>
+            </label>
           </div>
         </div>
         <div class="notification-channel">
@@ -1163,7 +1335,17 @@ const App = {
               <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="email_friend_new_card" ${settings.email_friend_new_card ? 'checked' : ''}>
               <span>Friend creates a new card</span>
             </label>
+            <label class="checkbox-label">
+              <input type="checkbox" data-change-action="notification-scenario-toggle" data-setting="email_friends_digest" ${settings.email_friends_digest ? 'checked' : ''}>
+              <span>Weekly friends activity digest</span>
+            </label>
           </div>
+          <label class="form-label" for="notify-email-format" style="margin-top: 0.75rem;">Email format</label>
+          <select id="notify-email-format" class="form-input form-input--sm" data-change-action="notification-email-format">
+            <option value="html" ${settings.email_format !== 'text' ? 'selected' : ''}>Formatted (HTML)</option>
+            <option value="text" ${settings.email_format === 'text' ? 'selected' : ''}>Plain text only</option>
+          </select>
+          <small class="text-muted">Applies to reminder and notification emails.</small>
         </div>
       </div>
     `;
@@ -1225,6 +1407,16 @@ const App = {
     await this.saveNotificationSettings(patch, target, !enabled);
   },
 
+  async handleNotificationEmailFormat(target) {
+    if (!this.notificationSettings) return;
+    const previous = this.notificationSettings.email_format || 'html';
+    try {
+      await this.saveNotificationSettings({ email_format: target.value });
+    } finally {
+      target.value = this.notificationSettings.email_format || previous;
+    }
+  },
+
   async saveNotificationSettings(patch, target, revertValue) {
     try {
       const response = await API.notifications.updateSettings(patch);
@@ -1246,12 +1438,16 @@ const App = {
 
     container.innerHTML = '<div class="text-center"><div class="spinner spinner--small"></div></div>';
     try {
-      const [settingsResponse, cardsResponse, goalsResponse] = await Promise.all([
+      const [settingsResponse, cardsResponse, goalsResponse, deliverabilityResponse, calendarResponse] = await Promise.all([
         API.reminders.getSettings(),
         API.reminders.listCards(),
         API.reminders.listGoals(),
+        API.reminders.getDeliverability().catch(() => null),
+        API.reminders.getCalendarFeed().catch(() => null),
       ]);
       this.reminderSettings = settingsResponse.settings;
+      this.reminderDeliverability = deliverabilityResponse?.check || null;
+      this.reminderCalendarFeed = calendarResponse?.feed || null;
       this.reminderCards = cardsResponse.cards || [];
       this.goalReminders = goalsResponse.reminders || [];
       this.goalRemindersByItem = this.mapGoalReminders(this.goalReminders);
@@ -1277,6 +1473,7 @@ const App = {
     const schedule = this.getReminderSchedule(selectedCard?.checkin);
     const includeImage = selectedCard?.checkin?.include_image !== false;
     const includeRecommendations = selectedCard?.checkin?.include_recommendations !== false;
+    const includeMemories = selectedCard?.checkin?.include_memories !== false;
     const nextSend = selectedCard?.checkin?.next_send_at
       ? this.formatReminderTimestamp(selectedCard.checkin.next_send_at)
       : 'Not scheduled';
@@ -1303,6 +1500,22 @@ const App = {
           <span>Email reminders</span>
         </label>
         <small class="text-muted">${emailNote}</small>
+        <div class="form-group">
+          <label class="form-label" for="reminder-timezone">Time zone</label>
+          <select id="reminder-timezone" class="form-input" data-change-action="reminder-timezone">
+            ${this.reminderTimezoneOptions(settings.timezone)}
+          </select>
+          <small class="text-muted">Check-in and goal reminder times are in this zone.</small>
+        </div>
+        <label class="checkbox-label">
+          <input type="checkbox" id="reminder-digest-enabled" data-change-action="reminder-digest-toggle" ${settings.digest_enabled ? 'checked' : ''}>
+          <span>Combine reminders into one daily email</span>
+        </label>
+        <div class="form-group">
+          <label class="form-label" for="reminder-digest-time">Daily email time</label>
+          <input type="time" id="reminder-digest-time" class="form-input" data-change-action="reminder-digest-time" value="${this.escapeHtml(settings.digest_time || '08:00')}">
+          <small class="text-muted">Check-ins and goal reminders that come due wait for this email.</small>
+        </div>
       </div>
 
       <div class="reminder-section">
@@ -1329,6 +1542,11 @@ const App = {
               </div>
             </div>
             <p class="text-muted">Times use the server clock.</p>
+            <label class="checkbox-label">
+              <input type="checkbox" id="reminder-jitter" ${schedule.jitterWindow > 0 ? 'checked' : ''} ${disableControls ? 'disabled' : ''}>
+              <span>Spread send time (up to 30 minutes either side)</span>
+            </label>
+            ${schedule.jitterOffset !== 0 ? `<p class="text-muted">Effective send time: ${this.escapeHtml(this.getReminderEffectiveTime(schedule))}</p>` : ''}
 
             <label class="checkbox-label">
               <input type="checkbox" id="reminder-include-image" ${includeImage ? 'checked' : ''} ${disableControls ? 'disabled' : ''}>
@@ -1338,6 +1556,10 @@ const App = {
               <input type="checkbox" id="reminder-include-recommendations" ${includeRecommendations ? 'checked' : ''} ${disableControls ? 'disabled' : ''}>
               <span>Include suggested goals</span>
             </label>
+            <label class="checkbox-label">
+              <input type="checkbox" id="reminder-include-memories" ${includeMemories ? 'checked' : ''} ${disableControls ? 'disabled' : ''}>
+              <span>Include a goal you completed this time last year</span>
+            </label>
 
             <p class="text-muted">Next send: ${this.escapeHtml(nextSend)}</p>
 
@@ -1357,9 +1579,88 @@ const App = {
           ${this.renderGoalReminderList(goalReminders)}
         </div>
       </div>
+
+      <div class="reminder-section">
+        <h4>Card image links</h4>
+        <label class="checkbox-label">
+          <input type="checkbox" id="reminder-image-token-mode" data-change-action="reminder-image-token-mode" ${settings.image_token_mode === 'per_email' ? 'checked' : ''}>
+          <span>Use a separate, expiring image link in each email</span>
+        </label>
+        <small class="text-muted">Forwarded emails stop showing your latest card once the link expires.</small>
+        <label class="checkbox-label">
+          <input type="checkbox" id="reminder-image-theme" data-change-action="reminder-image-theme" ${settings.image_theme === 'dark' ? 'checked' : ''}>
+          <span>Use a dark card image</span>
+        </label>
+        <small class="text-muted">Looks better in mail apps set to dark mode.</small>
+        <div class="reminder-actions">
+          <button class="btn btn-ghost btn-sm" data-action="revoke-reminder-image-tokens">Revoke all image links</button>
+        </div>
+      </div>
+
+      <div class="reminder-section">
+        <h4>Calendar feed</h4>
+        <div id="reminder-calendar-feed">
+          ${this.renderCalendarFeed(this.reminderCalendarFeed)}
+        </div>
+      </div>
+
+      <div class="reminder-section">
+        <h4>Email delivery</h4>
+        <div id="reminder-deliverability">
+          ${this.renderDeliverabilityCheck(this.reminderDeliverability)}
+        </div>
+      </div>
+    `;
+  },
+
+  renderDeliverabilityCheck(check) {
+    if (!check) {
+      return '<p class="text-muted">Unable to load the delivery checklist.</p>';
+    }
+
+    const icons = { ok: '✅', warning: '⚠️', failed: '❌', unknown: '❔' };
+    const items = (check.checklist || []).map((item) => `
+      <li class="deliverability-item deliverability-item--${this.escapeHtml(item.status)}">
+        <span class="deliverability-icon" aria-hidden="true">${icons[item.status] || '❔'}</span>
+        <div>
+          <p class="deliverability-label">${this.escapeHtml(item.label)}</p>
+          ${item.detail ? `<p class="reminder-goal-meta">${this.escapeHtml(item.detail)}</p>` : ''}
+        </div>
+      </li>
+    `).join('');
+
+    let probe = '<p class="text-muted">Send a test message to check that emails can reach you.</p>';
+    if (check.probe) {
+      const when = this.escapeHtml(this.formatReminderTimestamp(check.probe.sent_at));
+      probe = check.probe.status === 'sent'
+        ? `<p class="text-muted">Last check ${when}: accepted by our email provider. If it never arrived, check your spam folder.</p>`
+        : `<p class="text-muted">Last check ${when}: rejected by our email provider. Contact support if this keeps happening.</p>`;
+    }
+    const limited = check.next_check_at && new Date(check.next_check_at) > new Date();
+
+    return `
+      <ul class="deliverability-list">${items}</ul>
+      ${probe}
+      <div class="reminder-actions">
+        <button class="btn btn-secondary btn-sm" data-action="run-deliverability-check" ${limited || !this.user?.email_verified ? 'disabled' : ''}>Send check email</button>
+      </div>
+      ${limited ? `<small class="text-muted">You can run another check after ${this.escapeHtml(this.formatReminderTimestamp(check.next_check_at))}.</small>` : ''}
     `;
   },
 
+  async runDeliverabilityCheck() {
+    try {
+      const response = await API.reminders.runDeliverabilityCheck();
+      this.reminderDeliverability = response.check;
+      const container = document.getElementById('reminder-deliverability');
+      if (container) container.innerHTML = this.renderDeliverabilityCheck(this.reminderDeliverability);
+      const accepted = response.check?.probe?.status === 'sent';
+      this.toast(accepted ? 'Check email sent' : 'Our email provider rejected the check email', accepted ? 'success' : 'error');
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
   renderGoalReminderList(goalReminders) {
     if (!goalReminders || goalReminders.length === 0) {
       return '<p class="text-muted">No goal reminders set yet.</p>';
@@ -1367,7 +1668,11 @@ const App = {
 
     return goalReminders.map((reminder) => {
       const cardName = this.escapeHtml(reminder.card_title || `${reminder.card_year} Bingo Card`);
-      const nextSend = reminder.next_send_at ? this.formatReminderTimestamp(reminder.next_send_at) : 'Not scheduled';
+      let nextSend = reminder.next_send_at ? this.formatReminderTimestamp(reminder.next_send_at) : 'Not scheduled';
+      if (reminder.kind === 'recurring' && reminder.schedule?.every_days) {
+        const days = reminder.schedule.every_days;
+        nextSend += days === 7 ? ' (weekly)' : ` (every ${days} day${days === 1 ? '' : 's'})`;
+      }
       const goalText = this.escapeHtml(reminder.item_text);
       return `
         <div class="reminder-goal-item">
@@ -1406,6 +1711,69 @@ const App = {
     }
   },
 
+  async handleReminderImageTokenMode(target) {
+    const mode = target.checked ? 'per_email' : 'reuse';
+    try {
+      const response = await API.reminders.updateSettings({ image_token_mode: mode });
+      this.reminderSettings = response.settings;
+      this.toast('Reminder settings updated', 'success');
+    } catch (error) {
+      target.checked = !target.checked;
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async handleReminderImageTheme(target) {
+    const theme = target.checked ? 'dark' : 'light';
+    try {
+      const response = await API.reminders.updateSettings({ image_theme: theme });
+      this.reminderSettings = response.settings;
+      this.toast('Reminder settings updated', 'success');
+    } catch (error) {
+      target.checked = !target.checked;
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async handleReminderDigest(patch, target) {
+    const previous = this.reminderSettings || {};
+    try {
+      const response = await API.reminders.updateSettings(patch);
+      this.reminderSettings = response.settings;
+      this.toast('Reminder settings updated', 'success');
+    } catch (error) {
+      if (target.type === 'checkbox') {
+        target.checked = !target.checked;
+      } else {
+        target.value = previous.digest_time || '08:00';
+      }
+      this.toast(error.message, 'error');
+    }
+  },
+
+  reminderTimezoneOptions(current) {
+    const selected = current || 'UTC';
+    const browserZone = Intl.DateTimeFormat().resolvedOptions().timeZone;
+    const zones = typeof Intl.supportedValuesOf === 'function' ? Intl.supportedValuesOf('timeZone') : [];
+    const all = [...new Set([selected, browserZone, 'UTC', ...zones].filter(Boolean))].sort();
+    return all.map((zone) => {
+      const label = zone === browserZone && zone !== selected ? `${zone} (this device)` : zone;
+      return `<option value="${this.escapeHtml(zone)}" ${zone === selected ? 'selected' : ''}>${this.escapeHtml(label)}</option>`;
+    }).join('');
+  },
+
+  async handleReminderTimezone(target) {
+    const previous = this.reminderSettings?.timezone || 'UTC';
+    try {
+      const response = await API.reminders.updateSettings({ timezone: target.value });
+      this.reminderSettings = response.settings;
+      this.toast('Reminder time zone updated. Re-save schedules to move their next send.', 'success');
+    } catch (error) {
+      target.value = previous;
+      this.toast(error.message, 'error');
+    }
+  },
+
   handleReminderCardSelect(target) {
     this.reminderSelectedCardId = target.value;
     const container = document.getElementById('reminder-settings');
@@ -1426,7 +1794,7 @@ const App = {
 
   getReminderSchedule(checkin) {
     if (!checkin || !checkin.schedule) {
-      return { day: 1, time: '09:00' };
+      return { day: 1, time: '09:00', jitterWindow: 0, jitterOffset: 0 };
     }
     let schedule = checkin.schedule;
     if (typeof schedule === 'string') {
@@ -1439,24 +1807,36 @@ const App = {
     return {
       day: Number(schedule.day_of_month) || 1,
       time: schedule.time || '09:00',
+      jitterWindow: Number(schedule.jitter_window_minutes) || 0,
+      jitterOffset: Number(schedule.jitter_offset_minutes) || 0,
     };
   },
 
+  getReminderEffectiveTime(schedule) {
+    const [hours, minutes] = schedule.time.split(':').map(Number);
+    const total = ((hours * 60 + minutes + schedule.jitterOffset) % 1440 + 1440) % 1440;
+    const pad = (value) => String(value).padStart(2, '0');
+    return `${pad(Math.floor(total / 60))}:${pad(total % 60)}`;
+  },
+
   async saveCardCheckin() {
     const selected = this.getSelectedReminderCard(this.reminderCards);
     if (!selected) return;
 
     const day = Number.parseInt(document.getElementById('reminder-day')?.value || '1', 10);
     const time = document.getElementById('reminder-time')?.value || '09:00';
+    const jitterWindow = document.getElementById('reminder-jitter')?.checked ? 30 : 0;
     const includeImage = document.getElementById('reminder-include-image')?.checked !== false;
     const includeRecommendations = document.getElementById('reminder-include-recommendations')?.checked !== false;
+    const includeMemories = document.getElementById('reminder-include-memories')?.checked !== false;
 
     try {
       await API.reminders.upsertCardCheckin(selected.card_id, {
         frequency: 'monthly',
-        schedule: { day_of_month: day, time },
+        schedule: { day_of_month: day, time, jitter_window_minutes: jitterWindow },
         include_image: includeImage,
         include_recommendations: includeRecommendations,
+        include_memories: includeMemories,
       });
       this.toast('Check-in schedule saved', 'success');
       await this.loadReminderSettings();
@@ -1470,15 +1850,18 @@ const App = {
 
     const day = Number.parseInt(document.getElementById('reminder-day')?.value || '1', 10);
     const time = document.getElementById('reminder-time')?.value || '09:00';
+    const jitterWindow = document.getElementById('reminder-jitter')?.checked ? 30 : 0;
     const includeImage = document.getElementById('reminder-include-image')?.checked !== false;
     const includeRecommendations = document.getElementById('reminder-include-recommendations')?.checked !== false;
+    const includeMemories = document.getElementById('reminder-include-memories')?.checked !== false;
 
     try {
       await Promise.all(this.reminderCards.map(card => API.reminders.upsertCardCheckin(card.card_id, {
         frequency: 'monthly',
-        schedule: { day_of_month: day, time },
+        schedule: { day_of_month: day, time, jitter_window_minutes: jitterWindow },
         include_image: includeImage,
         include_recommendations: includeRecommendations,
+        include_memories: includeMemories,
       })));
       this.toast('Schedules applied to all cards', 'success');
       await this.loadReminderSettings();
@@ -1512,6 +1895,73 @@ const App = {
     }
   },
 
+  renderCalendarFeed(feed) {
+    if (!feed) {
+      return '<p class="text-muted">Unable to load the calendar feed.</p>';
+    }
+    if (!feed.active) {
+      return `
+        <small class="text-muted">Subscribe to your check-ins and goal reminders from any calendar app.</small>
+        <div class="reminder-actions">
+          <button class="btn btn-secondary btn-sm" data-action="create-calendar-feed">Create feed link</button>
+        </div>
+      `;
+    }
+    const url = feed.url
+      ? `
+        <input type="text" class="form-input" id="reminder-calendar-feed-url" value="${this.escapeHtml(feed.url)}" readonly>
+        <small class="text-muted">Copy this link into your calendar app now; it won't be shown again.</small>
+      `
+      : `<small class="text-muted">Feed link created ${this.escapeHtml(new Date(feed.created_at).toLocaleDateString())}.</small>`;
+    return `
+      ${url}
+      <div class="reminder-actions">
+        <button class="btn btn-ghost btn-sm" data-action="create-calendar-feed">Replace link</button>
+        <button class="btn btn-ghost btn-sm" data-action="revoke-calendar-feed">Revoke</button>
+      </div>
+    `;
+  },
+
+  updateCalendarFeed(feed) {
+    this.reminderCalendarFeed = feed;
+    const container = document.getElementById('reminder-calendar-feed');
+    if (container) {
+      container.innerHTML = this.renderCalendarFeed(feed);
+    }
+  },
+
+  async createCalendarFeed() {
+    if (this.reminderCalendarFeed?.active && !confirm('Replace your calendar feed link? Calendars using the old link stop updating.')) return;
+    try {
+      const response = await API.reminders.createCalendarFeed();
+      this.updateCalendarFeed(response.feed);
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async revokeCalendarFeed() {
+    if (!confirm('Revoke your calendar feed link? Calendars using it stop updating.')) return;
+    try {
+      await API.reminders.revokeCalendarFeed();
+      this.updateCalendarFeed({ active: false });
+      this.toast('Calendar feed revoked', 'success');
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async revokeReminderImageTokens() {
+    if (!confirm('Revoke card image links in all past reminder emails?')) return;
+    try {
+      const response = await API.reminders.revokeImageTokens();
+      const count = response.revoked || 0;
+      this.toast(`Revoked ${count} image link${count === 1 ? '' : 's'}`, 'success');
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
   async loadGoalReminders(cardId = null) {
     try {
       const response = await API.reminders.listGoals(cardId);
@@ -1545,6 +1995,14 @@ const App = {
 
     let sendAt = '';
     const preset = target.dataset.preset;
+    if (preset === 'weekly') {
+      await this.saveGoalReminder(itemId, {
+        item_id: itemId,
+        kind: 'recurring',
+        schedule: { every_days: 7, time: '09:00' },
+      });
+      return;
+    }
     if (preset === 'custom') {
       const input = document.getElementById('reminder-custom-datetime');
       if (!input || !input.value) {
@@ -1561,12 +2019,16 @@ const App = {
       return;
     }
 
+    await this.saveGoalReminder(itemId, {
+      item_id: itemId,
+      kind: 'one_time',
+      schedule: { send_at: sendAt },
+    });
+  },
+
+  async saveGoalReminder(itemId, payload) {
     try {
-      await API.reminders.upsertGoalReminder({
-        item_id: itemId,
-        kind: 'one_time',
-        schedule: { send_at: sendAt },
-      });
+      await API.reminders.upsertGoalReminder(payload);
       await this.loadGoalReminders(this.currentCard?.id || null);
       const item = this.currentCard.items?.find(i => i.id === itemId);
       if (item) {
@@ -2019,7 +2481,7 @@ const App = {
         this.requireAuth(() => this.renderArchiveCard(container, params[0]));
         break;
       case 'profile':
-        this.requireAuth(() => this.renderProfile(container));
+        this.requireAuth(() => this.renderProfile(container, queryParams));
         break;
       case 'about':
         this.renderAbout(container);
@@ -2307,6 +2769,28 @@ const App = {
     });
   },
 
+  async loadGooglePending() {
+    try {
+      const pending = await API.auth.providerPending('google');
+      const emailEl = document.getElementById('google-complete-email');
+      if (emailEl && pending.email) {
+        emailEl.textContent = `Signing up as ${pending.email}`;
+        emailEl.classList.remove('hidden');
+      }
+      const usernameEl = document.getElementById('google-username');
+      if (usernameEl && pending.suggested_username) {
+        usernameEl.placeholder = pending.suggested_username;
+        document.getElementById('google-username-help')?.classList.remove('hidden');
+      }
+    } catch (error) {
+      const errorEl = document.getElementById('google-complete-error');
+      if (errorEl) {
+        errorEl.textContent = error.message;
+        errorEl.classList.remove('hidden');
+      }
+    }
+  },
+
   renderGoogleComplete(container) {
     if (this.user) {
       this.navigate('/dashboard', { replace: true, skipWarning: true });
@@ -2319,11 +2803,13 @@ const App = {
           <div class="auth-header">
             <h2 class="auth-title">Complete Your Signup</h2>
             <p class="text-muted">Pick a username to finish creating your account</p>
+            <p class="text-muted hidden" id="google-complete-email"></p>
           </div>
           <form id="google-complete-form">
             <div class="form-group">
               <label class="form-label" for="google-username">Username</label>
-              <input type="text" id="google-username" class="form-input" required minlength="2" maxlength="100">
+              <input type="text" id="google-username" class="form-input" minlength="2" maxlength="100">
+              <small class="text-muted hidden" id="google-username-help">Leave blank to use the suggestion.</small>
             </div>
             <div class="form-group">
               <label class="checkbox-label">
@@ -2341,6 +2827,8 @@ const App = {
       </div>
     `;
 
+    this.loadGooglePending();
+
     document.getElementById('google-complete-form').addEventListener('submit', async (e) => {
       e.preventDefault();
       const username = document.getElementById('google-username').value;
@@ -2412,7 +2900,24 @@ const App = {
     });
   },
 
-  async handleMagicLinkVerify(container, token) {
+  // Opening the emailed link only shows this page; the token is used when the
+  // user confirms, so mail scanners that prefetch links can't consume it.
+  handleMagicLinkVerify(container, token) {
+    container.innerHTML = `
+      <div class="auth-page">
+        <div class="card auth-card text-center">
+          <h2>Sign in to ${this.escapeHtml(this.brandName)}</h2>
+          <p class="text-muted">Confirm to finish signing in on this device.</p>
+          <button class="btn btn-primary btn-lg" style="width: 100%; margin-top: 1rem;" data-action="confirm-magic-link" data-token="${this.escapeHtml(token)}">
+            Sign in
+          </button>
+        </div>
+      </div>
+    `;
+  },
+
+  async confirmMagicLink(token) {
+    const container = document.getElementById('main-container');
     container.innerHTML = `
       <div class="auth-page">
         <div class="card auth-card text-center">
@@ -2547,7 +3052,9 @@ const App = {
   },
 
   // Email Verification
-  async handleVerifyEmail(container, token) {
+  // Like magic links, verification waits for the user to confirm so that a
+  // prefetch of the emailed link doesn't use up the token.
+  handleVerifyEmail(container, token) {
     if (!token) {
       container.innerHTML = `
         <div class="auth-page">
@@ -2561,6 +3068,21 @@ const App = {
       return;
     }
 
+    container.innerHTML = `
+      <div class="auth-page">
+        <div class="card auth-card text-center">
+          <h2>Verify your email</h2>
+          <p class="text-muted">Confirm that this email address belongs to you.</p>
+          <button class="btn btn-primary btn-lg" style="width: 100%; margin-top: 1rem;" data-action="confirm-verify-email" data-token="${this.escapeHtml(token)}">
+            Verify email
+          </button>
+        </div>
+      </div>
+    `;
+  },
+
+  async confirmVerifyEmail(token) {
+    const container = document.getElementById('main-container');
     container.innerHTML = `
       <div class="auth-page">
         <div class="card auth-card text-center">
@@ -2694,6 +3216,12 @@ const App = {
         <div class="dashboard-header">
           <h2>My Bingo Cards</h2>
         </div>
+        <form class="dashboard-search" data-action="search-cards" style="display: flex; gap: 0.5rem; margin-bottom: 1rem;">
+          <input type="search" name="q" class="form-input form-input--sm" maxlength="100" placeholder="Search goals and card titles" aria-label="Search my cards">
+          <button type="submit" class="btn btn-secondary btn-sm">Search</button>
+        </form>
+        <div id="card-search-results"></div>
+        <div id="dashboard-memories"></div>
         <div id="cards-list">
           <div class="text-center"><div class="spinner" style="margin: 2rem auto;"></div></div>
         </div>
@@ -2708,6 +3236,116 @@ const App = {
     } catch (error) {
       this.toast(error.message, 'error');
     }
+
+    this.loadDashboardMemories();
+  },
+
+  async searchCards(event, form) {
+    event.preventDefault();
+    const query = (form.querySelector('input[name="q"]')?.value || '').trim();
+    const el = document.getElementById('card-search-results');
+    if (!el) return;
+    if (!query) {
+      this.cardSearch = null;
+      el.innerHTML = '';
+      return;
+    }
+    if (query.length < 2) {
+      this.toast('Search for at least 2 characters', 'error');
+      return;
+    }
+    this.cardSearch = { query, cursor: '', cards: [], hasMore: false };
+    await this.loadCardSearchPage();
+  },
+
+  async loadCardSearchPage() {
+    const search = this.cardSearch;
+    if (!search) return;
+    try {
+      const response = await API.cards.search(search.query, search.cursor);
+      if (this.cardSearch !== search) return;
+      // Pages hold whole cards, so each card arrives with all of its hits.
+      (response.items || []).forEach((match) => {
+        search.cards.push({ ...match, items: match.items || [] });
+      });
+      search.cursor = response.next_cursor || '';
+      search.hasMore = !!response.next_cursor;
+      this.renderCardSearchResults();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  renderCardSearchResults() {
+    const el = document.getElementById('card-search-results');
+    const search = this.cardSearch;
+    if (!el || !search) return;
+
+    const snippet = (parts) => (parts || []).map(part => (
+      part.match ? `<mark>${this.escapeHtml(part.text)}</mark>` : this.escapeHtml(part.text)
+    )).join('');
+
+    if (search.cards.length === 0) {
+      el.innerHTML = `<div class="card text-muted" style="margin-bottom: 1rem;">No goals or cards match "${this.escapeHtml(search.query)}".</div>`;
+      return;
+    }
+
+    el.innerHTML = `
+      <div class="card" style="margin-bottom: 1rem;">
+        <ul style="list-style: none; padding: 0; margin: 0;">
+          ${search.cards.map((match) => {
+            const link = match.is_archived ? `/archive-card/${match.card_id}` : `/card/${match.card_id}`;
+            const name = match.title_snippet ? snippet(match.title_snippet) : this.escapeHtml(match.title || `${match.year} Bingo Card`);
+            return `
+              <li style="margin-bottom: 0.75rem;">
+                <a href="${link}"><strong>${name}</strong></a>
+                <span class="text-muted">${match.year}${match.is_archived ? ' · Archived' : ''}</span>
+                ${match.items.length ? `
+                  <ul style="margin: 0.25rem 0 0 1rem; padding: 0;">
+                    ${match.items.map(item => `<li><span class="text-muted">Square ${item.position + 1}:</span> ${snippet(item.snippet)}</li>`).join('')}
+                  </ul>
+                ` : ''}
+              </li>
+            `;
+          }).join('')}
+        </ul>
+        ${search.hasMore ? '<button class="btn btn-ghost btn-sm" data-action="more-card-search">More results</button>' : ''}
+      </div>
+    `;
+  },
+
+  async loadDashboardMemories() {
+    const el = document.getElementById('dashboard-memories');
+    if (!el) return;
+
+    let memories = [];
+    try {
+      const response = await API.cards.getMemories();
+      memories = response.memories || [];
+    } catch (error) {
+      // Memories are a nice-to-have; the dashboard works without them.
+      return;
+    }
+    if (memories.length === 0) return;
+
+    el.innerHTML = `
+      <div class="card dashboard-memories">
+        <h3>On this day</h3>
+        <ul class="dashboard-memories-list">
+          ${memories.map((memory) => {
+            const when = memory.years_ago === 1 ? 'Last year' : `${memory.years_ago} years ago`;
+            const cardName = memory.card_title || `${memory.card_year} Bingo Card`;
+            return `
+              <li>
+                <span class="text-muted">${this.escapeHtml(when)}:</span>
+                ${this.escapeHtml(memory.content)}
+                <a href="${memory.is_archived ? `/archive-card/${memory.card_id}` : `/card/${memory.card_id}`}" class="text-muted">${this.escapeHtml(cardName)}</a>
+              </li>
+            `;
+          }).join('')}
+        </ul>
+      </div>
+    `;
   },
 
   renderDashboardCards() {
@@ -2774,6 +3412,13 @@ const App = {
               <button class="dropdown-item ${hasSelection ? '' : 'dropdown-item--disabled'}" role="menuitem" data-action="export-cards" ${hasSelection ? '' : 'title="Select cards first"'}>
                 <i class="fas fa-download"></i> Export Cards
               </button>
+              <button class="dropdown-item ${this.selectedCards.length === 1 ? '' : 'dropdown-item--disabled'}" role="menuitem" data-action="export-card-file" ${this.selectedCards.length === 1 ? '' : 'title="Select one card"'}>
+                <i class="fas fa-file-export"></i> Download Card File
+              </button>
+              <button class="dropdown-item" role="menuitem" data-action="import-card-file">
+                <i class="fas fa-file-import"></i> Import Card File
+              </button>
+              <input type="file" id="import-card-file-input" accept="application/json,.json" hidden data-change-action="import-card-file">
             </div>
           </div>
           <button class="btn btn-primary" data-action="show-create-card-modal">+ Card</button>
@@ -2917,7 +3562,7 @@ const App = {
 
     try {
       const response = await API.cards.bulkUpdateVisibility(this.selectedCards, visibleToFriends);
-      const count = response.updated_count || this.selectedCards.length;
+      const count = response.succeeded?.length ?? this.selectedCards.length;
       this.toast(`${count} card${count !== 1 ? 's' : ''} updated`, 'success');
       // Refresh the dashboard
       this.selectedCards = [];
@@ -2937,7 +3582,7 @@ const App = {
 
     try {
       const response = await API.cards.bulkUpdateArchive(this.selectedCards, isArchived);
-      const count = response.updated_count || this.selectedCards.length;
+      const count = response.succeeded?.length ?? this.selectedCards.length;
       const action = isArchived ? 'archived' : 'unarchived';
       this.toast(`${count} card${count !== 1 ? 's' : ''} ${action}`, 'success');
       // Refresh the dashboard
@@ -2963,7 +3608,7 @@ const App = {
 
     try {
       const response = await API.cards.bulkDelete(this.selectedCards);
-      const deletedCount = response.deleted_count || count;
+      const deletedCount = response.succeeded?.length ?? count;
       this.toast(`${deletedCount} card${deletedCount !== 1 ? 's' : ''} deleted`, 'success');
       // Refresh the dashboard
       this.selectedCards = [];
@@ -3011,9 +3656,54 @@ const App = {
       const timestamp = new Date().toISOString().slice(0, 10);
       this.downloadBlob(blob, `yearofbingo_export_${timestamp}.zip`);
 
-      this.toast(`Exported ${cardsToExport.length} card${cardsToExport.length > 1 ? 's' : ''}`, 'success');
+      this.toast(`Exported ${cardsToExport.length} card${cardsToExport.length > 1 ? 's' : ''}`, 'success');
+    } catch (error) {
+      this.toast('Error generating export: ' + error.message, 'error');
+    }
+  },
+
+  // exportSelectedCardFile downloads the selected card as a portable card
+  // file: settings and goals only, no progress.
+  async exportSelectedCardFile() {
+    document.querySelectorAll('.dropdown-menu--visible').forEach(menu => {
+      menu.classList.remove('dropdown-menu--visible');
+    });
+
+    if (this.selectedCards.length !== 1) {
+      this.toast('Select one card', 'warning');
+      return;
+    }
+
+    try {
+      const blob = await API.cards.exportFile(this.selectedCards[0]);
+      const timestamp = new Date().toISOString().slice(0, 10);
+      this.downloadBlob(blob, `yearofbingo_card_${timestamp}.json`);
+      this.toast('Card file downloaded', 'success');
+    } catch (error) {
+      this.toast(error.message || 'Unable to download card file', 'error');
+    }
+  },
+
+  async importCardFile(input) {
+    const file = input.files && input.files[0];
+    input.value = '';
+    if (!file) return;
+
+    let doc;
+    try {
+      doc = JSON.parse(await file.text());
+    } catch (error) {
+      this.toast('That file is not a card file', 'error');
+      return;
+    }
+
+    try {
+      const response = await API.cards.importFile(doc);
+      this.currentCard = response.card;
+      this.navigate(`/card/${response.card.id}`);
+      this.toast(response.message || 'Card imported', 'success');
     } catch (error) {
-      this.toast('Error generating export: ' + error.message, 'error');
+      this.toast(error.message || 'Unable to import card file', 'error');
     }
   },
 
@@ -3491,6 +4181,14 @@ const App = {
             </select>
           </div>
 
+          <div class="form-group">
+            <label for="card-start-date">
+              Start date <span class="text-muted" style="font-weight: normal;">(optional)</span>
+            </label>
+            <input type="date" id="card-start-date" class="form-input">
+            <small class="text-muted">For a school, fiscal or birthday year. The card runs 12 months from this date; leave blank for Jan 1 - Dec 31.</small>
+          </div>
+
           <div style="display: flex; gap: 0.5rem; margin-top: 1rem;">
             <a href="/dashboard" class="btn btn-ghost btn-lg" style="flex: 1; text-align: center;">Cancel</a>
             <button type="submit" class="btn btn-primary btn-lg" style="flex: 1;">Create Card</button>
@@ -3503,12 +4201,15 @@ const App = {
   async handleCreateCard(event) {
     event.preventDefault();
 
-    const year = parseInt(document.getElementById('card-year').value, 10);
+    const startDate = document.getElementById('card-start-date')?.value || '';
+    const year = startDate
+      ? parseInt(startDate.slice(0, 4), 10)
+      : parseInt(document.getElementById('card-year').value, 10);
     const title = document.getElementById('card-title').value.trim() || null;
     const category = document.getElementById('card-category').value || null;
 
     try {
-      const response = await API.cards.create(year, title, category);
+      const response = await API.cards.create(year, title, category, startDate ? { startDate } : {});
       this.currentCard = response.card;
       this.navigate(`/card/${response.card.id}`);
       const cardName = title || `${year} Bingo Card`;
@@ -3653,6 +4354,19 @@ const App = {
               <input type="checkbox" id="card-free-toggle" ${this.getHasFreeSpace(this.currentCard) ? 'checked' : ''}>
               <span>Include FREE space</span>
             </label>
+            ${!isAnon && this.getHasFreeSpace(this.currentCard) ? `
+              <div class="form-group" style="margin-top: 0.75rem; margin-bottom: 0;">
+                <label class="form-label" for="card-free-text-input">FREE space text</label>
+                <input type="text" id="card-free-text-input" class="form-input" maxlength="40" placeholder="FREE">
+                <small class="text-muted">Optional, up to 40 characters.</small>
+              </div>
+            ` : ''}
+            ${!isAnon ? `
+              <label style="display: flex; align-items: center; gap: 0.5rem; cursor: pointer; user-select: none; margin-top: 0.75rem;">
+                <input type="checkbox" id="card-require-proof-toggle" ${this.currentCard.require_proof ? 'checked' : ''}>
+                <span>Require a note or proof link to complete goals</span>
+              </label>
+            ` : ''}
           </div>
 
           <div class="action-bar action-bar--side editor-actions">
@@ -3662,6 +4376,11 @@ const App = {
             <button class="btn btn-secondary" id="shuffle-btn" data-action="shuffle-card" ${itemCount === 0 ? 'disabled' : ''}>
               🔀 Shuffle
             </button>
+            ${!isAnon ? `
+              <button class="btn btn-secondary" id="undo-shuffle-btn" data-action="undo-shuffle-card" hidden>
+                ↩️ Undo shuffle
+              </button>
+            ` : ''}
             ${!isAnon ? `
               <button class="btn btn-secondary" data-action="show-clone-card-modal">
                 📄 Clone
@@ -3715,6 +4434,8 @@ const App = {
 
     const headerInput = document.getElementById('card-header-input');
     if (headerInput) headerInput.value = this.getHeaderText(this.currentCard);
+    const freeTextInput = document.getElementById('card-free-text-input');
+    if (freeTextInput) freeTextInput.value = this.currentCard.free_space_text || '';
 
     this.setupEditorEvents();
   },
@@ -3907,10 +4628,14 @@ const App = {
     if (showActions) {
       const visibilityIcon = this.currentCard.visible_to_friends ? 'eye' : 'eye-slash';
       const visibilityLabel = this.currentCard.visible_to_friends ? 'Visible to friends' : 'Private';
+      const requireProofLabel = this.currentCard.require_proof
+        ? 'Proof required to complete goals (click to turn off)'
+        : 'Require a note or proof link to complete goals';
       actionsHtml = `
         <button class="btn btn-ghost btn-sm" data-action="edit-card-meta" title="Edit card name">✏️</button>
         <button class="btn btn-ghost btn-sm" data-action="show-clone-card-modal" title="Clone card">📄</button>
         <button class="btn btn-ghost btn-sm" data-action="open-share-modal" title="Share card">🔗</button>
+        <button class="btn btn-ghost btn-sm ${this.currentCard.require_proof ? 'require-proof-btn--on' : ''}" data-action="toggle-require-proof" data-card-id="${this.currentCard.id}" data-require-proof="${!this.currentCard.require_proof}" title="${requireProofLabel}" aria-label="${requireProofLabel}">🧾</button>
         <button class="visibility-toggle-btn ${this.currentCard.visible_to_friends ? 'visibility-toggle-btn--visible' : 'visibility-toggle-btn--private'}" data-action="toggle-card-visibility" data-card-id="${this.currentCard.id}" data-visible="${!this.currentCard.visible_to_friends}" title="${visibilityLabel}" aria-label="${visibilityLabel}">
           <i class="fas fa-${visibilityIcon}"></i>
           <span>${visibilityLabel}</span>
@@ -3939,12 +4664,14 @@ const App = {
           </div>
         </div>
 
+        ${options.hideProgress ? '' : `
         <div class="finalized-card-progress">
           <div class="progress-bar">
             <div class="progress-fill" style="width: ${progress}%"></div>
           </div>
           <p class="progress-text">${completedCount}/${capacity} completed</p>
         </div>
+        `}
       </div>
     `;
 
@@ -3976,7 +4703,29 @@ const App = {
       const items = response.items || [];
       this.currentCard = response.card || {};
       this.currentCard.items = items;
-      this.renderFinalizedCard(container, { readOnly: true, shared: true });
+      this.sharedCompletionsHidden = !!response.completions_hidden;
+      this.renderFinalizedCard(container, { readOnly: true, shared: true, hideProgress: this.sharedCompletionsHidden });
+      container.insertAdjacentHTML('beforeend', `
+        <p class="text-center mt-md"><a href="/s/${encodeURIComponent(token)}/print" target="_blank" rel="noopener">Print this card</a></p>
+      `);
+      if (response.superseded) {
+        container.insertAdjacentHTML('afterbegin', `
+          <div class="share-superseded-banner" role="status">
+            This link has been replaced by the card's owner and will stop working soon. Ask them for the new link.
+          </div>
+        `);
+      } else if (!this.sharedCompletionsHidden) {
+        container.insertAdjacentHTML('beforeend', `
+          <div class="card mt-lg">
+            <h3>Follow this card</h3>
+            <p class="text-muted">Get a weekly email when goals are completed. You'll confirm by email first, and can unsubscribe from any email.</p>
+            <form class="search-input-group" data-action="subscribe-shared-card" data-token="${this.escapeHtml(token)}">
+              <input type="email" name="email" class="form-input" placeholder="you@example.com" required maxlength="254" aria-label="Email address">
+              <button type="submit" class="btn btn-secondary">Follow</button>
+            </form>
+          </div>
+        `);
+      }
     } catch (error) {
       container.innerHTML = `
         <div class="card text-center" style="padding: 3rem;">
@@ -4047,7 +4796,7 @@ const App = {
         const draggable = !finalized ? 'draggable="true"' : '';
         cells.push(`
           <div class="bingo-cell bingo-cell--free" data-position="${i}" ${draggable}>
-            <span class="bingo-cell-content">FREE</span>
+            <span class="bingo-cell-content">${this.escapeHtml(this.currentCard.free_space_text || 'FREE')}</span>
           </div>
         `);
       } else {
@@ -4057,12 +4806,14 @@ const App = {
           const shortText = this.truncateText(item.content, 50);
           const itemIdAttr = item.id ? `data-item-id="${item.id}"` : '';
           cells.push(`
-            <div class="bingo-cell ${isCompleted ? 'bingo-cell--completed' : ''}"
+            <div class="bingo-cell ${isCompleted ? 'bingo-cell--completed' : ''} ${item.is_private ? 'bingo-cell--private' : ''}"
                  data-position="${i}"
                  ${itemIdAttr}
                  ${!finalized ? 'draggable="true"' : ''}
                  >
               <span class="bingo-cell-content">${this.escapeHtml(shortText)}</span>
+              ${this.difficultyDot(item.difficulty)}
+              ${finalized ? this.verifiedMark(item) : ''}
             </div>
           `);
         } else {
@@ -4076,6 +4827,47 @@ const App = {
     return headerRow + cells.join('');
   },
 
+  // Uploaded proof photos are served from /proofs/; anything else is a link.
+  isUploadedProof(proofUrl) {
+    return typeof proofUrl === 'string' && proofUrl.startsWith('/proofs/');
+  },
+
+  renderItemProof(item) {
+    const proofUrl = (item?.proof_url || '').trim();
+    if (!proofUrl) return '';
+    if (this.isUploadedProof(proofUrl)) {
+      return `<p class="item-detail-proof"><img src="${this.escapeHtml(proofUrl)}" alt="Proof photo" loading="lazy" style="max-width: 100%; border-radius: 8px;"></p>`;
+    }
+    if (!/^https?:\/\//i.test(proofUrl)) return '';
+    return `<p class="item-detail-proof"><a href="${this.escapeHtml(proofUrl)}" target="_blank" rel="noopener noreferrer">View proof</a></p>`;
+  },
+
+  // A completion counts as verified when the card requires proof and the
+  // item carries a note or proof link. Redacted private items never qualify.
+  isVerifiedItem(item) {
+    if (!this.currentCard?.require_proof || !item?.is_completed || item.is_private) return false;
+    return !!((item.notes || '').trim() || (item.proof_url || '').trim());
+  },
+
+  verifiedMark(item) {
+    if (!this.isVerifiedItem(item)) return '';
+    return '<span class="bingo-cell-verified" title="Completed with proof" aria-label="Completed with proof">✓</span>';
+  },
+
+  difficultyLabels: { easy: 'Easy', medium: 'Medium', hard: 'Hard' },
+
+  difficultyDot(difficulty) {
+    const label = this.difficultyLabels[difficulty];
+    if (!label) return '';
+    return `<span class="bingo-cell-difficulty bingo-cell-difficulty--${difficulty}" title="${label} goal" aria-label="${label} goal"></span>`;
+  },
+
+  setCellDifficulty(cell, difficulty) {
+    cell.querySelector('.bingo-cell-difficulty')?.remove();
+    const dot = this.difficultyDot(difficulty);
+    if (dot) cell.insertAdjacentHTML('beforeend', dot);
+  },
+
   truncateText(text, maxLength) {
     if (text.length <= maxLength) return text;
     // Find a good break point (space) near maxLength
@@ -4158,6 +4950,18 @@ const App = {
         await this.updateDraftConfig({ hasFreeSpace: freeToggle.checked });
       });
     }
+    const freeTextInput = document.getElementById('card-free-text-input');
+    if (freeTextInput) {
+      freeTextInput.addEventListener('change', async () => {
+        await this.updateDraftConfig({ freeSpaceText: freeTextInput.value });
+      });
+    }
+    const requireProofToggle = document.getElementById('card-require-proof-toggle');
+    if (requireProofToggle) {
+      requireProofToggle.addEventListener('change', async () => {
+        await this.updateDraftConfig({ requireProof: requireProofToggle.checked });
+      });
+    }
 
     // Drag and drop
     this.setupDragAndDrop();
@@ -4182,7 +4986,7 @@ const App = {
       const isCompleted = cell.classList.contains('bingo-cell--completed');
 
       if (readOnly) {
-        this.showSharedItemModal(content, isCompleted);
+        this.showSharedItemModal(content, isCompleted, item?.notes);
         return;
       }
 
@@ -4191,13 +4995,22 @@ const App = {
     });
   },
 
-  showSharedItemModal(content, isCompleted) {
+  // Shares that hide progress report every goal as open, so no status is
+  // shown for them. Notes are only sent when the owner shares them.
+  showSharedItemModal(content, isCompleted, notes) {
     const statusText = isCompleted ? 'Completed' : 'Not completed yet';
     const statusClass = isCompleted ? 'badge badge-success' : 'badge badge-warning';
+    const statusLine = this.sharedCompletionsHidden
+      ? ''
+      : `<p style="margin-top: 1rem;"><span class="${statusClass}">${statusText}</span></p>`;
+    const notesLine = (notes || '').trim()
+      ? `<p class="text-muted" style="margin-top: 1rem; white-space: pre-wrap;">${this.escapeHtml(notes)}</p>`
+      : '';
     this.openModal(isCompleted ? 'Completed Goal' : 'Goal', `
       <div class="item-detail">
         <p class="item-detail-content">${this.escapeHtml(content)}</p>
-        <p style="margin-top: 1rem;"><span class="${statusClass}">${statusText}</span></p>
+        ${statusLine}
+        ${notesLine}
       </div>
       <div style="margin-top: 1.5rem;">
         <button type="button" class="btn btn-secondary" style="width: 100%;" data-action="close-modal">
@@ -4230,12 +5043,13 @@ const App = {
           <button type="button" class="btn btn-secondary btn-sm" data-action="set-goal-reminder" data-item-id="${item.id}" data-preset="tomorrow" ${disableAttr}>Tomorrow morning</button>
           <button type="button" class="btn btn-secondary btn-sm" data-action="set-goal-reminder" data-item-id="${item.id}" data-preset="week" ${disableAttr}>Next week</button>
           <button type="button" class="btn btn-secondary btn-sm" data-action="set-goal-reminder" data-item-id="${item.id}" data-preset="month" ${disableAttr}>Next month</button>
+          <button type="button" class="btn btn-secondary btn-sm" data-action="set-goal-reminder" data-item-id="${item.id}" data-preset="weekly" ${disableAttr}>Every week until done</button>
         </div>
         <div class="reminder-custom">
           <input type="datetime-local" id="reminder-custom-datetime" class="form-input" ${disableAttr}>
           <button type="button" class="btn btn-secondary btn-sm" data-action="set-goal-reminder" data-item-id="${item.id}" data-preset="custom" ${disableAttr}>Set custom reminder</button>
         </div>
-        <p class="text-muted">Reminder times use the server clock.</p>
+        <p class="text-muted">Reminder times use the time zone in your reminder settings.</p>
         ${existing ? `
           <button type="button" class="btn btn-ghost btn-sm" data-action="delete-goal-reminder" data-reminder-id="${existing.id}" ${disableAttr}>Stop reminders for this goal</button>
         ` : ''}
@@ -4248,12 +5062,14 @@ const App = {
     const notes = item?.notes || '';
 
     const reminderControls = this.renderGoalReminderControls(item);
+    const requireProof = !!this.currentCard.require_proof;
 
     if (isCompleted) {
       this.openModal('Goal Completed!', `
         <div class="item-detail">
           <p class="item-detail-content">${this.escapeHtml(content)}</p>
           ${notes ? `<p class="item-detail-notes"><strong>Notes:</strong> ${this.escapeHtml(notes)}</p>` : ''}
+          ${this.renderItemProof(item)}
         </div>
         ${reminderControls}
         <div style="display: flex; gap: 1rem; margin-top: 1.5rem;">
@@ -4273,9 +5089,21 @@ const App = {
         ${reminderControls}
         <form id="complete-form">
           <div class="form-group" style="margin-top: 1rem;">
-            <label class="form-label">Notes (optional)</label>
+            <label class="form-label">${requireProof ? 'Notes' : 'Notes (optional)'}</label>
             <textarea id="complete-notes" class="form-input" rows="3" placeholder="How did you accomplish this?"></textarea>
           </div>
+          ${requireProof ? `
+            <div class="form-group">
+              <label class="form-label" for="complete-proof-url">Proof link</label>
+              <input type="url" id="complete-proof-url" class="form-input" maxlength="2048" placeholder="https://...">
+              <small class="text-muted">This card requires a note, a proof link or a photo to complete a goal.</small>
+            </div>
+          ` : ''}
+          <div class="form-group">
+            <label class="form-label" for="complete-proof-image">Proof photo (optional)</label>
+            <input type="file" id="complete-proof-image" class="form-input" accept="image/jpeg,image/png,image/webp">
+            <small class="text-muted">JPEG, PNG or WebP, up to 5MB.</small>
+          </div>
           <div style="display: flex; gap: 1rem;">
             <button type="button" class="btn btn-secondary" style="flex: 1;" data-action="close-modal">
               Cancel
@@ -4290,7 +5118,17 @@ const App = {
       document.getElementById('complete-form').addEventListener('submit', async (e) => {
         e.preventDefault();
         const notes = document.getElementById('complete-notes').value;
-        await this.completeItem(position, notes);
+        const proofUrl = document.getElementById('complete-proof-url')?.value.trim() || '';
+        const proofImage = document.getElementById('complete-proof-image')?.files?.[0] || null;
+        if (proofImage && proofImage.size > 5 * 1024 * 1024) {
+          this.toast('Proof photo must be 5MB or less', 'error');
+          return;
+        }
+        if (requireProof && !notes.trim() && !proofUrl && !proofImage) {
+          this.toast('Add a note, proof link or photo to complete this goal', 'error');
+          return;
+        }
+        await this.completeItem(position, notes, proofUrl, proofImage);
       });
     }
   },
@@ -4310,17 +5148,20 @@ const App = {
       document.querySelector('.progress-fill').style.width = `${progress}%`;
       document.querySelector('.progress-text').textContent = `${completedCount}/${capacity} completed`;
 
-      // Update local state
+      // Update local state; an uploaded proof photo is removed with the completion.
       const item = this.currentCard.items?.find(i => i.position === position);
-      if (item) item.is_completed = false;
+      if (item) {
+        item.is_completed = false;
+        if (this.isUploadedProof(item.proof_url)) item.proof_url = '';
+      }
     } catch (error) {
       this.toast(error.message, 'error');
     }
   },
 
-  async completeItem(position, notes) {
+  async completeItem(position, notes, proofUrl = '', proofImage = null) {
     try {
-      await API.cards.completeItem(this.currentCard.id, position, notes);
+      const response = await API.cards.completeItem(this.currentCard.id, position, notes, proofUrl, proofImage);
       const cell = document.querySelector(`[data-position="${position}"]`);
       cell.classList.add('bingo-cell--completed', 'bingo-cell--completing');
       setTimeout(() => cell.classList.remove('bingo-cell--completing'), 400);
@@ -4333,6 +5174,7 @@ const App = {
       if (item) {
         item.is_completed = true;
         item.notes = notes || '';
+        item.proof_url = response?.item?.proof_url || proofUrl || '';
       }
 
       // Update progress
@@ -4573,6 +5415,8 @@ const App = {
     const position = parseInt(cell.dataset.position, 10);
     const item = this.currentCard.items?.find(i => i.position === position);
     const content = item?.content || '';
+    const isPrivate = !!item?.is_private;
+    const difficulty = item?.difficulty || '';
     const isEmpty = cell.classList.contains('bingo-cell--empty');
     const modalTitle = isEmpty ? 'Add Goal' : 'Edit Goal';
     const aiButtonLabel = isEmpty ? '🧙 Suggest with AI' : '🧙 Refine with AI';
@@ -4600,6 +5444,25 @@ const App = {
           <label class="form-label" for="edit-item-content-${position}">Goal</label>
           <textarea id="edit-item-content-${position}" class="form-input" rows="4" maxlength="500" autofocus>${this.escapeHtml(content)}</textarea>
         </div>
+        ${this.isAnonymousMode ? '' : `
+        <div class="form-group">
+          <label class="visibility-toggle">
+            <input type="checkbox" id="edit-item-private-${position}" ${isPrivate ? 'checked' : ''}>
+            <span>Private goal</span>
+          </label>
+          <p class="text-muted" style="font-size: 0.85rem; margin-top: 0.25rem;">Friends and share links see "Private goal" instead of the text. It still counts toward bingos.</p>
+        </div>
+        <div class="form-group">
+          <label class="form-label" for="edit-item-difficulty-${position}">Difficulty</label>
+          <select id="edit-item-difficulty-${position}" class="form-input">
+            <option value="" ${difficulty === '' ? 'selected' : ''}>Not set</option>
+            ${Object.entries(this.difficultyLabels).map(([value, label]) => `
+              <option value="${value}" ${difficulty === value ? 'selected' : ''}>${label}</option>
+            `).join('')}
+          </select>
+          <p class="text-muted" style="font-size: 0.85rem; margin-top: 0.25rem;">Harder goals count for more in weighted progress.</p>
+        </div>
+        `}
         ${aiSection}
         <div style="display: flex; gap: 1rem; margin-top: 1.5rem;">
           <button type="button" class="btn btn-secondary" style="flex: 1;" data-action="close-modal">
@@ -4704,6 +5567,10 @@ const App = {
           return;
         }
       }
+      if (error?.data?.code === 'quota_exhausted') {
+        AIWizard.showQuotaExhaustedModal(error.message);
+        return;
+      }
       this.toast(error.message, 'error');
     } finally {
       const fallbackLabel = mode === 'new' ? '🧙 Suggest with AI' : '🧙 Refine with AI';
@@ -4728,7 +5595,7 @@ const App = {
     this.usedSuggestions.add(newKey);
   },
 
-  async addItemAtPosition(position, content) {
+  async addItemAtPosition(position, content, isPrivate = false, difficulty = '') {
     const items = this.currentCard?.items || [];
     if (items.some(i => i.position === position)) {
       this.toast('That cell already has a goal', 'error');
@@ -4757,7 +5624,7 @@ const App = {
           is_completed: false,
         };
       } else {
-        const response = await API.cards.addItem(this.currentCard.id, content, position);
+        const response = await API.cards.addItem(this.currentCard.id, content, position, isPrivate, difficulty);
         newItem = response.item;
       }
 
@@ -4770,6 +5637,7 @@ const App = {
         const shortText = this.truncateText(content, 50);
         cell.classList.remove('bingo-cell--empty');
         cell.classList.add('bingo-cell--appearing');
+        cell.classList.toggle('bingo-cell--private', !!newItem.is_private);
         cell.dataset.itemId = this.isAnonymousMode ? `anon-${position}` : newItem.id;
         cell.title = content;
         cell.draggable = true;
@@ -4778,6 +5646,7 @@ const App = {
         contentEl.className = 'bingo-cell-content';
         contentEl.textContent = shortText;
         cell.appendChild(contentEl);
+        this.setCellDifficulty(cell, newItem.difficulty);
       }
 
       const itemCount = this.currentCard.items.length;
@@ -4826,6 +5695,10 @@ const App = {
       this.toast('Goal must be 500 characters or less', 'error');
       return;
     }
+    const privateInput = document.getElementById(`edit-item-private-${position}`);
+    const newIsPrivate = privateInput ? privateInput.checked : false;
+    const difficultyInput = document.getElementById(`edit-item-difficulty-${position}`);
+    const newDifficulty = difficultyInput ? difficultyInput.value : '';
 
     if (this._itemEditInFlightPositions.has(position)) return;
     this._itemEditInFlightPositions.add(position);
@@ -4839,12 +5712,14 @@ const App = {
     const item = this.currentCard.items?.find(i => i.position === position);
     try {
       if (!item) {
-        await this.addItemAtPosition(position, newContent);
+        await this.addItemAtPosition(position, newContent, newIsPrivate, newDifficulty);
         return;
       }
       const oldContent = item.content || '';
+      const privacyChanged = newIsPrivate !== !!item.is_private;
+      const difficultyChanged = !this.isAnonymousMode && newDifficulty !== (item.difficulty || '');
 
-      if (newContent === oldContent) {
+      if (newContent === oldContent && !privacyChanged && !difficultyChanged) {
         this.closeModal();
         return;
       }
@@ -4854,11 +5729,17 @@ const App = {
         if (!ok) throw new Error('Failed to update goal');
         item.content = newContent;
       } else {
-        const response = await API.cards.updateItem(this.currentCard.id, position, { content: newContent });
+        const updates = {};
+        if (newContent !== oldContent) updates.content = newContent;
+        if (privacyChanged) updates.is_private = newIsPrivate;
+        if (difficultyChanged) updates.difficulty = newDifficulty;
+        const response = await API.cards.updateItem(this.currentCard.id, position, updates);
         if (response?.item) {
           Object.assign(item, response.item);
         } else {
           item.content = newContent;
+          item.is_private = newIsPrivate;
+          item.difficulty = newDifficulty || undefined;
         }
       }
 
@@ -4867,10 +5748,12 @@ const App = {
       const cell = document.querySelector(`.bingo-cell[data-position="${position}"]`);
       if (cell) {
         cell.title = item.content;
+        cell.classList.toggle('bingo-cell--private', !!item.is_private);
         const contentEl = cell.querySelector('.bingo-cell-content');
         if (contentEl) {
           contentEl.textContent = this.truncateText(item.content, 50);
         }
+        this.setCellDifficulty(cell, item.difficulty);
       }
 
       this.refreshSuggestionsList();
@@ -5189,6 +6072,8 @@ const App = {
         // Shuffle on server
         const response = await API.cards.shuffle(this.currentCard.id);
         this.currentCard = response.card;
+        const undoBtn = document.getElementById('undo-shuffle-btn');
+        if (undoBtn) undoBtn.hidden = false;
       }
 
       // Wait for animation then update
@@ -5203,7 +6088,21 @@ const App = {
     }
   },
 
-  async updateDraftConfig({ headerText = null, hasFreeSpace = null } = {}) {
+  async undoShuffleCard() {
+    const undoBtn = document.getElementById('undo-shuffle-btn');
+    try {
+      const response = await API.cards.undoShuffle(this.currentCard.id);
+      this.currentCard = response.card;
+      document.getElementById('bingo-grid').innerHTML = this.renderGrid();
+      this.setupDragAndDrop();
+      this.toast('Shuffle undone', 'success');
+    } catch (error) {
+      if (undoBtn) undoBtn.hidden = true;
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async updateDraftConfig({ headerText = null, hasFreeSpace = null, freeSpaceText = null, requireProof = null } = {}) {
     if (!this.currentCard || this.currentCard.is_finalized) return;
 
     const normalizedHeader = headerText !== null ? headerText.trim() : null;
@@ -5228,7 +6127,9 @@ const App = {
         const response = await API.cards.updateConfig(
           this.currentCard.id,
           normalizedHeader,
-          typeof hasFreeSpace === 'boolean' ? hasFreeSpace : null
+          typeof hasFreeSpace === 'boolean' ? hasFreeSpace : null,
+          freeSpaceText !== null ? freeSpaceText.trim() : null,
+          typeof requireProof === 'boolean' ? requireProof : null
         );
         this.currentCard = response.card;
       }
@@ -5384,9 +6285,94 @@ const App = {
       <div id="share-modal-content">
         <div class="text-center"><div class="spinner" style="margin: 1rem auto;"></div></div>
       </div>
+      <div id="widget-links-content" style="margin-top: 1.5rem;"></div>
     `);
 
-    await this.refreshShareModal();
+    await Promise.all([this.refreshShareModal(), this.refreshWidgetLinks()]);
+  },
+
+  // Widget links are image URLs for home- and lock-screen widgets. They are
+  // separate from the share link: they only show progress, never the goals.
+  async refreshWidgetLinks() {
+    const content = document.getElementById('widget-links-content');
+    if (!content) return;
+
+    try {
+      const response = await API.cards.widgetTokens(this.currentCard.id);
+      const tokens = response?.tokens || [];
+      const rows = tokens.map((token) => {
+        const lastUsed = token.last_accessed_at
+          ? `last used ${this.escapeHtml(new Date(token.last_accessed_at).toLocaleDateString())}`
+          : 'never used';
+        return `
+          <li style="display: flex; gap: 0.5rem; align-items: center; justify-content: space-between; margin-bottom: 0.5rem;">
+            <span class="text-muted">Created ${this.escapeHtml(new Date(token.created_at).toLocaleDateString())}, ${lastUsed}</span>
+            <span style="display: flex; gap: 0.5rem;">
+              <button class="btn btn-secondary btn-sm" data-action="copy-widget-link" data-url="${this.escapeHtml(token.url)}">Copy</button>
+              <button class="btn btn-ghost btn-sm" data-action="revoke-widget-link" data-token-id="${this.escapeHtml(token.id)}">Revoke</button>
+            </span>
+          </li>
+        `;
+      }).join('');
+      content.innerHTML = `
+        <label class="form-label">Widget image links</label>
+        <p class="text-muted">A progress image (completed goals, bingos and a mini-grid) for widget apps. It refreshes every 15 minutes and never shows goal text.</p>
+        ${rows ? `<ul style="list-style: none; padding: 0;">${rows}</ul>` : ''}
+        <button class="btn btn-secondary btn-sm" data-action="create-widget-link">New Widget Link</button>
+      `;
+    } catch (error) {
+      content.innerHTML = '<p class="text-muted" id="widget-links-error"></p>';
+      const errorEl = document.getElementById('widget-links-error');
+      if (errorEl) errorEl.textContent = error.message;
+    }
+  },
+
+  async createWidgetLink() {
+    try {
+      const token = await API.cards.widgetTokenCreate(this.currentCard.id);
+      await this.refreshWidgetLinks();
+      this.copyToClipboard(`${window.location.origin}${token.url}`);
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async revokeWidgetLink(tokenId) {
+    if (!tokenId || !confirm('Revoke this widget link? Widgets using it will stop updating.')) return;
+    try {
+      await API.cards.widgetTokenRevoke(this.currentCard.id, tokenId);
+      await this.refreshWidgetLinks();
+      this.toast('Widget link revoked', 'success');
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async subscribeToSharedCard(event, form) {
+    event.preventDefault();
+    const input = form.querySelector('input[name="email"]');
+    const button = form.querySelector('button[type="submit"]');
+    const email = (input?.value || '').trim();
+    if (!email) return;
+    if (button) button.disabled = true;
+    try {
+      await API.share.subscribe(form.dataset.token, email);
+      form.outerHTML = '<p class="text-muted">Check your inbox for a link to confirm.</p>';
+    } catch (error) {
+      this.toast(error.message, 'error');
+      if (button) button.disabled = false;
+    }
+  },
+
+  async removeShareSubscriber(subscriberId) {
+    if (!subscriberId || !confirm('Remove this subscriber? They will stop getting progress emails.')) return;
+    try {
+      await API.cards.shareSubscriberRemove(this.currentCard.id, subscriberId);
+      await this.refreshShareModal();
+      this.toast('Subscriber removed', 'success');
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
   },
 
   async refreshShareModal() {
@@ -5487,15 +6473,63 @@ const App = {
           <input type="number" id="share-expiry-custom" class="form-input" min="1" max="3650" placeholder="Enter days">
         </div>
       </div>
+      <div class="form-group">
+        <label><input type="checkbox" id="share-show-completions" checked> Show which goals are completed</label>
+        <label><input type="checkbox" id="share-show-notes"> Show goal notes</label>
+      </div>
     `;
     const expirationNote = isEnabled
       ? '<p class="text-muted" style="margin-top: 0.5rem;">Disable sharing to change the expiration.</p>'
       : '';
 
+    const hidden = [];
+    if (status?.show_completions === false) hidden.push('which goals are completed');
+    if (status?.show_notes !== true) hidden.push('goal notes');
+    const optionsNote = isEnabled && hidden.length
+      ? `<p class="text-muted">This link hides ${hidden.join(' and ')}.</p>`
+      : '';
+
+    const supersededUntil = status?.superseded_until ? new Date(status.superseded_until) : null;
+    const supersededNote = isEnabled && supersededUntil
+      ? `<p class="text-muted">Your previous link keeps working until ${this.escapeHtml(supersededUntil.toLocaleDateString())}, with a notice that it was replaced.</p>`
+      : '';
+
+    const rotateControls = isEnabled && !expired ? `
+      <div class="form-group">
+        <label class="form-label" for="share-grace-select">Get a new link</label>
+        <select id="share-grace-select" class="form-input">
+          <option value="0">Old link stops working now</option>
+          <option value="7">Old link works for 7 more days</option>
+          <option value="30">Old link works for 30 more days</option>
+        </select>
+        <button class="btn btn-secondary mt-sm" data-action="rotate-share">New Link</button>
+      </div>
+    ` : '';
+
+    const subscribers = isEnabled && Array.isArray(status?.subscribers) ? status.subscribers : [];
+    const subscriberSection = subscribers.length ? `
+      <div class="form-group">
+        <label class="form-label">Following by email</label>
+        <ul style="list-style: none; padding: 0;">
+          ${subscribers.map(sub => `
+            <li style="display: flex; gap: 0.5rem; align-items: center; justify-content: space-between; margin-bottom: 0.5rem;">
+              <span>${this.escapeHtml(sub.email)}${sub.confirmed ? '' : ' <span class="text-muted">(unconfirmed)</span>'}</span>
+              <button class="btn btn-ghost btn-sm" data-action="remove-share-subscriber" data-subscriber-id="${this.escapeHtml(sub.id)}">Remove</button>
+            </li>
+          `).join('')}
+        </ul>
+        <p class="text-muted">Disabling sharing removes everyone.</p>
+      </div>
+    ` : '';
+
     return `
       ${statusLine}
       ${expirationNote}
       ${linkSection}
+      ${optionsNote}
+      ${supersededNote}
+      ${rotateControls}
+      ${subscriberSection}
       ${expirationControls}
       <div style="display: flex; gap: 0.5rem; flex-wrap: wrap; justify-content: flex-end;">
         ${disableAction}
@@ -5534,8 +6568,12 @@ const App = {
       this.toast('Enter a valid expiration in days', 'error');
       return;
     }
+    const options = {
+      show_completions: document.getElementById('share-show-completions')?.checked !== false,
+      show_notes: !!document.getElementById('share-show-notes')?.checked,
+    };
     try {
-      await API.cards.shareEnable(this.currentCard.id, days);
+      await API.cards.shareEnable(this.currentCard.id, days, null, options);
       await this.refreshShareModal();
       this.toast('Share link created', 'success');
     } catch (error) {
@@ -5543,8 +6581,27 @@ const App = {
     }
   },
 
+  // rotateShare replaces the share link, keeping the current expiry date.
+  // The old link can stay valid for a grace period so printed QR codes and
+  // posted links don't break straight away.
+  async rotateShare() {
+    const graceDays = parseInt(document.getElementById('share-grace-select')?.value || '0', 10);
+    const expiresAt = this.currentShareStatus?.expires_at ? new Date(this.currentShareStatus.expires_at) : null;
+    let expiresInDays = 0;
+    if (expiresAt) {
+      expiresInDays = Math.min(3650, Math.max(1, Math.ceil((expiresAt - new Date()) / (24 * 60 * 60 * 1000))));
+    }
+    try {
+      await API.cards.shareEnable(this.currentCard.id, expiresInDays, Number.isFinite(graceDays) ? graceDays : 0);
+      await this.refreshShareModal();
+      this.toast('New share link created', 'success');
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
   async disableShare() {
-    if (!confirm('Disable sharing? The current link will stop working.')) return;
+    if (!confirm('Disable sharing? The current link and any previous link will stop working, and anyone following the card by email is removed.')) return;
     try {
       await API.cards.shareDisable(this.currentCard.id);
       await this.refreshShareModal();
@@ -5572,6 +6629,17 @@ const App = {
     }
   },
 
+  async toggleRequireProof(cardId, requireProof) {
+    try {
+      const response = await API.cards.updateConfig(cardId, null, null, null, requireProof);
+      this.currentCard = response.card;
+      this.toast(requireProof ? 'Completions now require a note or proof link' : 'Proof is no longer required', 'success');
+      this.route();
+    } catch (error) {
+      this.toast(error.message || 'Failed to update proof setting', 'error');
+    }
+  },
+
   async finalizeCard() {
     // For anonymous users, show the auth modal instead of finalizing directly
     if (this.isAnonymousMode) {
@@ -6421,8 +7489,10 @@ const App = {
 
   async sendFriendRequest(friendId) {
     try {
-      await API.friends.sendRequest(friendId);
-      this.toast('Friend request sent!', 'success');
+      const response = await API.friends.sendRequest(friendId);
+      // Existing friendships come back as 200 with a message describing them.
+      const existingMessage = response?.message !== 'Friend request sent' ? response?.message : '';
+      this.toast(existingMessage || 'Friend request sent!', 'success');
       document.getElementById('friend-search').value = '';
       document.getElementById('search-results').innerHTML = '';
       await this.loadFriends();
@@ -6718,11 +7788,13 @@ const App = {
   async showFriendItemModal(itemId, content, isCompleted) {
     const item = this.currentCard.items?.find(i => i.id === itemId);
     const notes = item?.notes || '';
+    const isPrivate = !!item?.is_private;
+    const canReact = isCompleted && !isPrivate;
 
     let reactionsHtml = '';
     let userReaction = null;
 
-    if (isCompleted) {
+    if (canReact) {
       try {
         const response = await API.reactions.get(itemId);
         const reactions = response.reactions || [];
@@ -6733,7 +7805,7 @@ const App = {
         if (summary.length > 0) {
           reactionsHtml = `
             <div class="reactions-summary">
-              ${summary.map(s => `<span class="reaction-badge">${s.emoji} ${s.count}</span>`).join('')}
+              ${summary.map(s => `<span class="reaction-badge">${s.emoji} ${s.display_count || s.count}</span>`).join('')}
             </div>
           `;
         }
@@ -6742,7 +7814,7 @@ const App = {
       }
     }
 
-    const emojiPickerHtml = isCompleted ? `
+    const emojiPickerHtml = canReact ? `
       <div class="reaction-picker">
         <p>React to this achievement:</p>
         <div class="emoji-buttons">
@@ -6759,8 +7831,11 @@ const App = {
       <div class="item-detail">
         <p class="item-detail-content">${this.escapeHtml(content)}</p>
         ${notes && isCompleted ? `<p class="item-detail-notes"><strong>Notes:</strong> ${this.escapeHtml(notes)}</p>` : ''}
+        ${this.isVerifiedItem(item) ? '<p class="item-detail-verified">✓ Completed with proof</p>' : ''}
+        ${isCompleted ? this.renderItemProof(item) : ''}
         ${reactionsHtml}
         ${emojiPickerHtml}
+        ${isPrivate ? '<p class="text-muted" style="margin-top: 1rem;">This goal is private.</p>' : ''}
         ${!isCompleted ? '<p class="text-muted" style="margin-top: 1rem;">This goal hasn\'t been completed yet.</p>' : ''}
       </div>
       <div style="margin-top: 1.5rem;">
@@ -6801,7 +7876,7 @@ const App = {
   },
 
   // Profile page
-  renderProfile(container) {
+  renderProfile(container, queryParams = new URLSearchParams()) {
     const verifiedBadge = this.user.email_verified
       ? '<span class="badge badge-success">Verified</span>'
       : '<span class="badge badge-warning">Not verified</span>';
@@ -6852,6 +7927,14 @@ const App = {
                 <span>Allow others to find me by username</span>
               </label>
               <small class="text-muted">When disabled, you won't appear in friend search results</small>
+              <label class="checkbox-label">
+                <input type="checkbox" id="data-minimization-toggle" disabled>
+                <span>Minimize data kept about me</span>
+              </label>
+              <small class="text-muted">Stops AI generation logs and share link view counts. Turning this on also deletes the ones already stored.</small>
+            </div>
+            <div id="public-profile-settings" class="public-profile-settings">
+              <div class="text-center"><div class="spinner spinner--small"></div></div>
             </div>
           </div>
 
@@ -6890,6 +7973,22 @@ const App = {
             </form>
           </div>
 
+          <div class="card profile-section">
+            <h3>Sign-in Methods</h3>
+            <p class="text-muted">Accounts you can use to sign in besides your email and password.</p>
+            <div id="providers-list" class="tokens-list">
+              <div class="text-center"><div class="spinner spinner--small"></div></div>
+            </div>
+          </div>
+
+          <div class="card profile-section">
+            <h3>Signed-in Devices</h3>
+            <p class="text-muted">Browsers and devices where you are signed in. Signing one out takes effect on its next request.</p>
+            <div id="sessions-list" class="tokens-list">
+              <div class="text-center"><div class="spinner spinner--small"></div></div>
+            </div>
+          </div>
+
           <div class="card profile-section">
             <h3>API Tokens</h3>
             <div class="profile-tokens">
@@ -6931,9 +8030,13 @@ const App = {
     `;
 
     this.setupProfileEvents();
+    this.loadPublicProfileSettings();
     this.loadNotificationSettings();
     this.loadReminderSettings();
+    this.loadProviders();
+    this.loadSessions();
     this.loadApiTokens();
+    this.showProviderLinkResult(queryParams);
   },
 
   setupProfileEvents() {
@@ -6953,6 +8056,23 @@ const App = {
       }
     });
 
+    const minimizationToggle = document.getElementById('data-minimization-toggle');
+    API.auth.getPreferences()
+      .then((response) => {
+        minimizationToggle.checked = !!response.preferences?.data_minimization;
+        minimizationToggle.disabled = false;
+      })
+      .catch((error) => this.toast(error.message, 'error'));
+    minimizationToggle.addEventListener('change', async (e) => {
+      try {
+        await API.auth.updatePreferences({ data_minimization: e.target.checked });
+        this.toast(e.target.checked ? 'Data minimization enabled' : 'Data minimization disabled', 'success');
+      } catch (error) {
+        e.target.checked = !e.target.checked; // Revert on error
+        this.toast(error.message, 'error');
+      }
+    });
+
     form.addEventListener('submit', async (e) => {
       e.preventDefault();
       errorEl.classList.add('hidden');
@@ -7054,6 +8174,10 @@ const App = {
             <div class="stat-value">${stats.completion_rate.toFixed(0)}%</div>
             <div class="stat-label">Completion Rate</div>
           </div>
+          <div class="stat-card">
+            <div class="stat-value">${(stats.weighted_completion_rate ?? stats.completion_rate).toFixed(0)}%</div>
+            <div class="stat-label">Effort Completed</div>
+          </div>
           <div class="stat-card">
             <div class="stat-value">${stats.bingos_achieved}</div>
             <div class="stat-label">Bingos</div>
@@ -7444,6 +8568,167 @@ const App = {
     }
   },
 
+  providerName(provider) {
+    const names = { google: 'Google' };
+    return names[provider] || provider;
+  },
+
+  // The link callback returns to /profile with ?linked= or ?link_error=.
+  showProviderLinkResult(queryParams) {
+    const linked = queryParams.get('linked');
+    const linkError = queryParams.get('link_error');
+    if (!linked && !linkError) return;
+
+    if (linked) {
+      this.toast(`${this.providerName(linked)} account connected`, 'success');
+    } else {
+      const errorMessages = {
+        'oauth_linked_elsewhere': 'That account is already connected to a different user.',
+        'oauth_already_linked': 'You already have an account from that provider connected. Disconnect it first.',
+      };
+      this.toast(errorMessages[linkError] || 'Connecting the account failed. Please try again.', 'error');
+    }
+    history.replaceState({}, '', '/profile');
+  },
+
+  async loadProviders() {
+    const listEl = document.getElementById('providers-list');
+    if (!listEl) return;
+
+    try {
+      const response = await API.auth.listProviders();
+      const identities = response.identities || [];
+      const linked = new Set(identities.map(identity => identity.provider));
+      const unlinked = (response.available || []).filter(provider => !linked.has(provider));
+
+      if (identities.length === 0 && unlinked.length === 0) {
+        listEl.innerHTML = '<p class="text-muted">No sign-in providers are available.</p>';
+        return;
+      }
+
+      listEl.innerHTML = identities.map(identity => `
+        <div class="token-item" style="padding: 0.75rem; border: 1px solid var(--border-color); border-radius: 0.5rem; margin-top: 0.5rem; display: flex; justify-content: space-between; align-items: center;">
+          <div class="token-info">
+            <div style="font-weight: 500;">
+              ${this.escapeHtml(this.providerName(identity.provider))}
+              <span class="badge badge-success">Connected</span>
+            </div>
+            <div class="token-meta text-muted" style="font-size: 0.85rem;">
+              ${identity.email ? `<span>${this.escapeHtml(identity.email)}</span><span>•</span>` : ''}
+              <span>Connected ${new Date(identity.linked_at).toLocaleDateString()}</span>
+            </div>
+          </div>
+          <button class="btn btn-ghost btn-sm" style="color: var(--color-danger);" data-action="unlink-provider" data-provider="${this.escapeHtml(identity.provider)}">Disconnect</button>
+        </div>
+      `).join('') + unlinked.map(provider => `
+        <div class="token-item" style="padding: 0.75rem; border: 1px solid var(--border-color); border-radius: 0.5rem; margin-top: 0.5rem; display: flex; justify-content: space-between; align-items: center;">
+          <div class="token-info">
+            <div style="font-weight: 500;">${this.escapeHtml(this.providerName(provider))}</div>
+          </div>
+          <button class="btn btn-secondary btn-sm" data-action="link-provider" data-provider="${this.escapeHtml(provider)}">Connect</button>
+        </div>
+      `).join('');
+
+      if (!response.has_password && identities.length === 1) {
+        listEl.innerHTML += `
+          <p class="text-muted" style="margin-top: 0.75rem; font-size: 0.85rem;">
+            This is your only way to sign in. Set a password with "Forgot password?" on the login page before disconnecting it.
+          </p>
+        `;
+      }
+    } catch (error) {
+      listEl.innerHTML = '<p class="text-muted text-danger" id="providers-error"></p>';
+      const errorEl = document.getElementById('providers-error');
+      if (errorEl) errorEl.textContent = `Failed to load sign-in methods: ${error.message}`;
+    }
+  },
+
+  async linkProvider(provider) {
+    try {
+      await API.auth.linkProvider(provider);
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async unlinkProvider(provider) {
+    if (!confirm(`Disconnect ${this.providerName(provider)}? You won't be able to sign in with it anymore.`)) return;
+    try {
+      await API.auth.unlinkProvider(provider);
+      this.toast(`${this.providerName(provider)} disconnected`, 'success');
+      this.loadProviders();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async loadSessions() {
+    const listEl = document.getElementById('sessions-list');
+    if (!listEl) return;
+
+    try {
+      const response = await API.auth.listSessions();
+      const sessions = response.sessions || [];
+
+      listEl.innerHTML = sessions.map(session => `
+        <div class="token-item" style="padding: 0.75rem; border: 1px solid var(--border-color); border-radius: 0.5rem; margin-top: 0.5rem; display: flex; justify-content: space-between; align-items: center;">
+          <div class="token-info">
+            <div style="font-weight: 500;">
+              ${this.escapeHtml(session.user_agent || 'Unknown device')}
+              ${session.current ? '<span class="badge badge-success">This device</span>' : ''}
+            </div>
+            <div class="token-meta text-muted" style="font-size: 0.85rem;">
+              <span>${this.escapeHtml(session.ip_address || 'Unknown IP')}</span>
+              <span>•</span>
+              <span>Signed in ${new Date(session.created_at).toLocaleDateString()}</span>
+              <span>•</span>
+              <span>Last active ${new Date(session.last_seen_at || session.created_at).toLocaleString()}</span>
+            </div>
+          </div>
+          ${session.current ? '' : `
+            <button class="btn btn-ghost btn-sm" style="color: var(--color-danger);" data-action="revoke-session" data-session-id="${session.id}" title="Sign Out Device">
+              <i class="fas fa-right-from-bracket"></i>
+            </button>
+          `}
+        </div>
+      `).join('');
+
+      if (sessions.some(session => !session.current)) {
+        listEl.innerHTML += `
+          <div style="margin-top: 1rem; text-align: right;">
+            <button class="btn btn-ghost btn-sm" style="color: var(--color-danger);" data-action="revoke-other-sessions">Sign Out All Other Devices</button>
+          </div>
+        `;
+      }
+    } catch (error) {
+      listEl.innerHTML = '<p class="text-muted text-danger" id="sessions-error"></p>';
+      const errorEl = document.getElementById('sessions-error');
+      if (errorEl) errorEl.textContent = `Failed to load sessions: ${error.message}`;
+    }
+  },
+
+  async revokeSession(id) {
+    if (!confirm('Sign out this device?')) return;
+    try {
+      await API.auth.revokeSession(id);
+      this.toast('Device signed out', 'success');
+      this.loadSessions();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
+  async revokeOtherSessions() {
+    if (!confirm('Sign out every other device?')) return;
+    try {
+      const response = await API.auth.revokeOtherSessions();
+      this.toast(`Signed out ${response.revoked} other ${response.revoked === 1 ? 'device' : 'devices'}`, 'success');
+      this.loadSessions();
+    } catch (error) {
+      this.toast(error.message, 'error');
+    }
+  },
+
   // Utilities
   toast(message, type = 'success') {
     const container = document.getElementById('toast-container');
@@ -7636,6 +8921,8 @@ const App = {
               <p>
                 On the Dashboard, select the cards you want to export using the checkboxes, then click
                 Actions &rarr; "Export Cards". You'll download a ZIP file containing CSV files for each selected card.
+                To share a card as a template, select one card and choose "Download Card File". Anyone can load it
+                with Actions &rarr; "Import Card File" to get a draft with the same settings and goals, without your progress.
               </p>
             </div>
 